@@ -18,6 +18,16 @@ func (c APIClient) Dump(w io.Writer) error {
 	return grpcutil.ScrubGRPC(grpcutil.WriteFromStreamingBytesClient(goroClient, w))
 }
 
+// DumpArchive writes a gzipped tar archive of the dumps of pachd and its
+// workers, including the workers' driver state, to w.
+func (c APIClient) DumpArchive(w io.Writer) error {
+	goroClient, err := c.DebugClient.Dump(c.Ctx(), &debug.DumpRequest{Archive: true})
+	if err != nil {
+		return grpcutil.ScrubGRPC(err)
+	}
+	return grpcutil.ScrubGRPC(grpcutil.WriteFromStreamingBytesClient(goroClient, w))
+}
+
 // Profile writes a pprof profile for pachd to w.
 func (c APIClient) Profile(profile string, duration time.Duration, w io.Writer) error {
 	var d *types.Duration
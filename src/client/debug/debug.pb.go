@@ -31,7 +31,11 @@ type DumpRequest struct {
 	// Recursed is true if this request is a recursive call from another request.
 	// Callers should leave it unset, it's used to prevent infinite loops of
 	// recursive calls.
-	Recursed             bool     `protobuf:"varint,1,opt,name=recursed,proto3" json:"recursed,omitempty"`
+	Recursed bool `protobuf:"varint,1,opt,name=recursed,proto3" json:"recursed,omitempty"`
+	// archive, if set, returns a gzipped tar archive in which the dump of pachd
+	// and of each worker (including the worker's driver state) are separate
+	// files, rather than plain text.
+	Archive              bool     `protobuf:"varint,2,opt,name=archive,proto3" json:"archive,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -77,6 +81,13 @@ func (m *DumpRequest) GetRecursed() bool {
 	return false
 }
 
+func (m *DumpRequest) GetArchive() bool {
+	if m != nil {
+		return m.Archive
+	}
+	return false
+}
+
 type ProfileRequest struct {
 	Profile              string          `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
 	Duration             *types.Duration `protobuf:"bytes,2,opt,name=duration,proto3" json:"duration,omitempty"`
@@ -180,27 +191,27 @@ func init() {
 func init() { proto.RegisterFile("client/debug/debug.proto", fileDescriptor_6d15a320d0127c22) }
 
 var fileDescriptor_6d15a320d0127c22 = []byte{
-	// 307 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x91, 0x4f, 0x4b, 0xc3, 0x30,
-	0x18, 0xc6, 0x17, 0x71, 0x7f, 0xcc, 0x50, 0x21, 0x28, 0xd4, 0x09, 0x65, 0xf4, 0x34, 0x2f, 0x89,
-	0x4c, 0x3c, 0x79, 0x90, 0x8d, 0x7e, 0x00, 0xe9, 0xc1, 0x83, 0xb7, 0xb4, 0x7d, 0xd7, 0x15, 0xba,
-	0x26, 0xa6, 0x09, 0xd2, 0x6f, 0xe2, 0x47, 0xda, 0xd1, 0x8f, 0x20, 0xf5, 0x8b, 0xc8, 0x9a, 0x76,
-	0x74, 0xec, 0xb0, 0x4b, 0xe9, 0xf3, 0xbe, 0x4f, 0x9e, 0xf7, 0xf7, 0x26, 0xd8, 0x89, 0xb2, 0x14,
-	0x72, 0xcd, 0x62, 0x08, 0x4d, 0x62, 0xbf, 0x54, 0x2a, 0xa1, 0x05, 0xe9, 0xd7, 0x62, 0xe2, 0x26,
-	0x42, 0x24, 0x19, 0xb0, 0xba, 0x18, 0x9a, 0x15, 0xfb, 0x52, 0x5c, 0x4a, 0x50, 0x85, 0xb5, 0x1d,
-	0xf7, 0x63, 0xa3, 0xb8, 0x4e, 0x45, 0x6e, 0xfb, 0xde, 0x03, 0x1e, 0xfb, 0x66, 0x23, 0x03, 0xf8,
-	0x34, 0x50, 0x68, 0x32, 0xc1, 0x23, 0x05, 0x91, 0x51, 0x05, 0xc4, 0x0e, 0x9a, 0xa2, 0xd9, 0x28,
-	0xd8, 0x6b, 0x8f, 0xe3, 0xab, 0x37, 0x25, 0x56, 0x69, 0x06, 0xad, 0xdb, 0xc1, 0x43, 0x69, 0x2b,
-	0xb5, 0xf9, 0x22, 0x68, 0x25, 0x79, 0xc6, 0xa3, 0x76, 0x90, 0x73, 0x36, 0x45, 0xb3, 0xf1, 0xfc,
-	0x8e, 0x5a, 0x12, 0xda, 0x92, 0x50, 0xbf, 0x31, 0x04, 0x7b, 0xab, 0x77, 0x8d, 0x2f, 0x97, 0x69,
-	0xce, 0x55, 0xd9, 0x4c, 0x98, 0x6f, 0x11, 0xee, 0xfb, 0xbb, 0x45, 0xc9, 0x0b, 0x3e, 0xdf, 0x81,
-	0x12, 0x42, 0xed, 0x2d, 0x74, 0xa8, 0x27, 0xf7, 0x47, 0xd9, 0xcb, 0x52, 0x43, 0xf1, 0xce, 0x33,
-	0x03, 0x5e, 0xef, 0x11, 0x91, 0x05, 0x1e, 0x36, 0xe8, 0xe4, 0xb6, 0x39, 0x7f, 0xb8, 0xca, 0xe9,
-	0x88, 0x57, 0x3c, 0xb0, 0x68, 0xe4, 0xa6, 0x49, 0x38, 0x20, 0x3d, 0x19, 0xb0, 0x5c, 0x6c, 0x2b,
-	0x17, 0xfd, 0x54, 0x2e, 0xfa, 0xad, 0x5c, 0xf4, 0xfd, 0xe7, 0xf6, 0x3e, 0x58, 0x92, 0xea, 0xb5,
-	0x09, 0x69, 0x24, 0x36, 0x4c, 0xf2, 0x68, 0x5d, 0xc6, 0xa0, 0xba, 0x7f, 0x85, 0x8a, 0x58, 0xf7,
-	0xfd, 0xc3, 0x41, 0x9d, 0xfd, 0xf4, 0x1f, 0x00, 0x00, 0xff, 0xff, 0x93, 0x2d, 0xd0, 0x8a, 0x16,
-	0x02, 0x00, 0x00,
+	// 316 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x92, 0x4f, 0x4e, 0xf3, 0x30,
+	0x10, 0xc5, 0xeb, 0x4f, 0x5f, 0xff, 0x30, 0x15, 0x20, 0x59, 0x20, 0x85, 0x22, 0x45, 0x28, 0x2b,
+	0x56, 0x09, 0x2a, 0x62, 0xc5, 0x02, 0xb5, 0xf4, 0x00, 0x28, 0x0b, 0x16, 0xec, 0x1c, 0x67, 0x9a,
+	0x5a, 0x4a, 0x6b, 0xe3, 0xd8, 0xa0, 0xde, 0x84, 0x23, 0x75, 0xc9, 0x11, 0x50, 0xb9, 0x08, 0xaa,
+	0x9d, 0x54, 0xad, 0xba, 0xe8, 0x26, 0xca, 0x9b, 0x19, 0xff, 0xfc, 0xde, 0xc8, 0x10, 0xf0, 0x52,
+	0xe0, 0xc2, 0x24, 0x39, 0x66, 0xb6, 0xf0, 0xdf, 0x58, 0x69, 0x69, 0x24, 0x6d, 0x3b, 0x31, 0x08,
+	0x0b, 0x29, 0x8b, 0x12, 0x13, 0x57, 0xcc, 0xec, 0x34, 0xf9, 0xd4, 0x4c, 0x29, 0xd4, 0x95, 0x1f,
+	0x3b, 0xec, 0xe7, 0x56, 0x33, 0x23, 0xe4, 0xc2, 0xf7, 0xa3, 0x67, 0xe8, 0x4f, 0xec, 0x5c, 0xa5,
+	0xf8, 0x6e, 0xb1, 0x32, 0x74, 0x00, 0x3d, 0x8d, 0xdc, 0xea, 0x0a, 0xf3, 0x80, 0xdc, 0x90, 0xdb,
+	0x5e, 0xba, 0xd5, 0x34, 0x80, 0x2e, 0xd3, 0x7c, 0x26, 0x3e, 0x30, 0xf8, 0xe7, 0x5a, 0x8d, 0x8c,
+	0x18, 0x9c, 0xbd, 0x68, 0x39, 0x15, 0x25, 0x36, 0x9c, 0x00, 0xba, 0xca, 0x57, 0x1c, 0xe6, 0x24,
+	0x6d, 0x24, 0x7d, 0x80, 0x5e, 0x63, 0xc1, 0x61, 0xfa, 0xc3, 0xab, 0xd8, 0x7b, 0x8c, 0x1b, 0x8f,
+	0xf1, 0xa4, 0x1e, 0x48, 0xb7, 0xa3, 0xd1, 0x39, 0x9c, 0x8e, 0xc5, 0x82, 0xe9, 0x65, 0x7d, 0xc3,
+	0x70, 0x45, 0xa0, 0x3d, 0xd9, 0xac, 0x80, 0x3e, 0xc2, 0xff, 0x4d, 0x04, 0x4a, 0x63, 0xbf, 0x9f,
+	0x9d, 0x3c, 0x83, 0xeb, 0x03, 0xf6, 0x78, 0x69, 0xb0, 0x7a, 0x65, 0xa5, 0xc5, 0xa8, 0x75, 0x47,
+	0xe8, 0x08, 0xba, 0xb5, 0x75, 0x7a, 0x59, 0x9f, 0xdf, 0x8f, 0x72, 0x1c, 0xf1, 0x04, 0x1d, 0x6f,
+	0x8d, 0x5e, 0xd4, 0x84, 0x3d, 0xa7, 0x47, 0x01, 0xe3, 0xd1, 0x6a, 0x1d, 0x92, 0xef, 0x75, 0x48,
+	0x7e, 0xd6, 0x21, 0xf9, 0xfa, 0x0d, 0x5b, 0x6f, 0x49, 0x21, 0xcc, 0xcc, 0x66, 0x31, 0x97, 0xf3,
+	0x44, 0x31, 0x3e, 0x5b, 0xe6, 0xa8, 0x77, 0xff, 0x2a, 0xcd, 0x93, 0xdd, 0x97, 0x91, 0x75, 0x1c,
+	0xfb, 0xfe, 0x6f, 0x00, 0xb7, 0x57, 0x9c, 0xa4, 0x30, 0x02, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -460,6 +471,16 @@ func (m *DumpRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.Archive {
+		i--
+		if m.Archive {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x10
+	}
 	if m.Recursed {
 		i--
 		if m.Recursed {
@@ -566,6 +587,9 @@ func (m *DumpRequest) Size() (n int) {
 	if m.Recursed {
 		n += 2
 	}
+	if m.Archive {
+		n += 2
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -659,6 +683,26 @@ func (m *DumpRequest) Unmarshal(dAtA []byte) error {
 				}
 			}
 			m.Recursed = bool(v != 0)
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Archive", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDebug
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Archive = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipDebug(dAtA[iNdEx:])
@@ -310,6 +310,15 @@ func (c APIClient) ListCommitF(repoName string, to string, from string, number u
 	if to != "" {
 		req.To = NewCommit(repoName, to)
 	}
+	return c.ListCommitFilterF(req, f)
+}
+
+// ListCommitFilterF is like ListCommitF, but takes a whole request, so that
+// the commits can also be filtered by time, branch and provenance on the
+// server (see pfs.ListCommitRequest). Results can be paged through by
+// setting `req.Number`, and setting `req.StartAfter` to the ID of the last
+// commit of the previous page.
+func (c APIClient) ListCommitFilterF(req *pfs.ListCommitRequest, f func(*pfs.CommitInfo) error) error {
 	stream, err := c.PfsAPIClient.ListCommitStream(c.Ctx(), req)
 	if err != nil {
 		return grpcutil.ScrubGRPC(err)
@@ -353,6 +362,25 @@ func (c APIClient) CreateBranch(repoName string, branch string, commit string, p
 	return grpcutil.ScrubGRPC(err)
 }
 
+// ProtectBranch sets the protection of a branch, keeping its head and
+// provenance. An empty protection removes the branch's protection.
+func (c APIClient) ProtectBranch(repoName string, branch string, protection *pfs.BranchProtection) error {
+	branchInfo, err := c.InspectBranch(repoName, branch)
+	if err != nil {
+		return err
+	}
+	_, err = c.PfsAPIClient.CreateBranch(
+		c.Ctx(),
+		&pfs.CreateBranchRequest{
+			Branch:     NewBranch(repoName, branch),
+			Head:       NewCommit(repoName, branch),
+			Provenance: branchInfo.DirectProvenance,
+			Protection: protection,
+		},
+	)
+	return grpcutil.ScrubGRPC(err)
+}
+
 // InspectBranch returns information on a specific PFS branch
 func (c APIClient) InspectBranch(repoName string, branch string) (*pfs.BranchInfo, error) {
 	branchInfo, err := c.PfsAPIClient.InspectBranch(
@@ -409,6 +437,23 @@ func (c APIClient) DeleteCommit(repoName string, commitID string) error {
 	return grpcutil.ScrubGRPC(err)
 }
 
+// ExpireCommits deletes the commits in a repo that are older than the repo's
+// commit TTL, and returns them. If dryRun is set, the commits that would be
+// deleted are returned, but not deleted.
+func (c APIClient) ExpireCommits(repoName string, dryRun bool) ([]*pfs.CommitInfo, error) {
+	commitInfos, err := c.PfsAPIClient.ExpireCommits(
+		c.Ctx(),
+		&pfs.ExpireCommitsRequest{
+			Repo:   NewRepo(repoName),
+			DryRun: dryRun,
+		},
+	)
+	if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	return commitInfos.CommitInfo, nil
+}
+
 // FlushCommit returns an iterator that returns commits that have the
 // specified `commits` as provenance.  Note that the iterator can block if
 // jobs have not successfully completed. This in effect waits for all of the
@@ -524,7 +569,6 @@ func (c *commitInfoIterator) Close() {
 // SubscribeCommit is like ListCommit but it keeps listening for commits as
 // they come in.
 func (c APIClient) SubscribeCommit(repo, branch string, prov *pfs.CommitProvenance, from string, state pfs.CommitState) (CommitInfoIterator, error) {
-	ctx, cancel := context.WithCancel(c.Ctx())
 	req := &pfs.SubscribeCommitRequest{
 		Repo:   NewRepo(repo),
 		Branch: branch,
@@ -534,6 +578,27 @@ func (c APIClient) SubscribeCommit(repo, branch string, prov *pfs.CommitProvenan
 	if from != "" {
 		req.From = NewCommit(repo, from)
 	}
+	return c.subscribeCommit(req)
+}
+
+// SubscribeValidCommit is like SubscribeCommit, but it only returns commits
+// once they're finished, and only if they and their provenance aren't empty
+// (see pfs.SubscribeCommitRequest.ValidOnly).
+func (c APIClient) SubscribeValidCommit(repo, branch string, prov *pfs.CommitProvenance, from string) (CommitInfoIterator, error) {
+	req := &pfs.SubscribeCommitRequest{
+		Repo:      NewRepo(repo),
+		Branch:    branch,
+		Prov:      prov,
+		ValidOnly: true,
+	}
+	if from != "" {
+		req.From = NewCommit(repo, from)
+	}
+	return c.subscribeCommit(req)
+}
+
+func (c APIClient) subscribeCommit(req *pfs.SubscribeCommitRequest) (CommitInfoIterator, error) {
+	ctx, cancel := context.WithCancel(c.Ctx())
 	stream, err := c.PfsAPIClient.SubscribeCommit(ctx, req)
 	if err != nil {
 		cancel()
@@ -569,6 +634,32 @@ func (c APIClient) SubscribeCommitF(repo, branch string, prov *pfs.CommitProvena
 	}
 }
 
+// SubscribeFileChangesF calls 'f' with the files created, updated and deleted
+// by each commit as it's finished on 'branch'. If 'from' is set, only commits
+// after it are returned.
+func (c APIClient) SubscribeFileChangesF(repo, branch, from string, f func(*pfs.FileChangeEvent) error) error {
+	req := &pfs.SubscribeFileChangesRequest{
+		Repo:   NewRepo(repo),
+		Branch: branch,
+	}
+	if from != "" {
+		req.From = NewCommit(repo, from)
+	}
+	stream, err := c.PfsAPIClient.SubscribeFileChanges(c.Ctx(), req)
+	if err != nil {
+		return grpcutil.ScrubGRPC(err)
+	}
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			return grpcutil.ScrubGRPC(err)
+		}
+		if err := f(event); err != nil {
+			return grpcutil.ScrubGRPC(err)
+		}
+	}
+}
+
 // PutObjectAsync puts a value into the object store asynchronously.
 func (c APIClient) PutObjectAsync(tags []*pfs.Tag) (*PutObjectWriteCloserAsync, error) {
 	w, err := c.newPutObjectWriteCloserAsync(tags)
@@ -580,8 +671,18 @@ func (c APIClient) PutObjectAsync(tags []*pfs.Tag) (*PutObjectWriteCloserAsync,
 
 // PutObject puts a value into the object store and tags it with 0 or more tags.
 func (c APIClient) PutObject(_r io.Reader, tags ...string) (object *pfs.Object, _ int64, retErr error) {
+	return c.putObject(_r, pfs.StorageClass_STANDARD, tags...)
+}
+
+// PutObjectWithClass is the same as PutObject except that the object is
+// written to a block in 'class', if object storage supports storage classes.
+func (c APIClient) PutObjectWithClass(_r io.Reader, class pfs.StorageClass) (object *pfs.Object, _ int64, retErr error) {
+	return c.putObject(_r, class)
+}
+
+func (c APIClient) putObject(_r io.Reader, class pfs.StorageClass, tags ...string) (object *pfs.Object, _ int64, retErr error) {
 	r := grpcutil.ReaderWrapper{_r}
-	w, err := c.newPutObjectWriteCloser(tags...)
+	w, err := c.newPutObjectWriteCloser(class, tags...)
 	if err != nil {
 		return nil, 0, grpcutil.ScrubGRPC(err)
 	}
@@ -607,8 +708,15 @@ func (c APIClient) PutObject(_r io.Reader, tags ...string) (object *pfs.Object,
 // into several smaller objects.  This is primarily useful if you'd like to
 // be able to resume upload.
 func (c APIClient) PutObjectSplit(_r io.Reader) (objects []*pfs.Object, _ int64, retErr error) {
+	return c.PutObjectSplitWithClass(_r, pfs.StorageClass_STANDARD)
+}
+
+// PutObjectSplitWithClass is the same as PutObjectSplit except that the
+// objects are written to blocks in 'class', if object storage supports
+// storage classes.
+func (c APIClient) PutObjectSplitWithClass(_r io.Reader, class pfs.StorageClass) (objects []*pfs.Object, _ int64, retErr error) {
 	r := grpcutil.ReaderWrapper{_r}
-	w, err := c.newPutObjectSplitWriteCloser()
+	w, err := c.newPutObjectSplitWriteCloser(class)
 	if err != nil {
 		return nil, 0, grpcutil.ScrubGRPC(err)
 	}
@@ -943,7 +1051,7 @@ func (c APIClient) NewPutFileClient() (PutFileClient, error) {
 	return &putFileClient{c: pfc}, nil
 }
 
-func (c APIClient) newOneoffPutFileClient() (PutFileClient, error) {
+func (c APIClient) newOneoffPutFileClient() (*putFileClient, error) {
 	pfc, err := c.PfsAPIClient.PutFile(c.Ctx())
 	if err != nil {
 		return nil, grpcutil.ScrubGRPC(err)
@@ -1093,6 +1201,28 @@ func (c APIClient) PutFileOverwrite(repoName string, commitID string, path strin
 	return pfc.PutFileOverwrite(repoName, commitID, path, reader, overwriteIndex)
 }
 
+// PutFileOverwriteChecksums is like PutFileOverwrite, but it also computes
+// the file's checksums in 'algorithms' as it's written, so that
+// InspectFileChecksums doesn't have to read the file to compute them.
+func (c APIClient) PutFileOverwriteChecksums(repoName string, commitID string, path string, reader io.Reader, overwriteIndex int64, algorithms ...pfs.ChecksumAlgorithm) (_ int, retErr error) {
+	pfc, err := c.newOneoffPutFileClient()
+	if err != nil {
+		return 0, err
+	}
+	writer, err := pfc.newPutFileWriteCloser(repoName, commitID, path, pfs.Delimiter_NONE, 0, 0, 0, &pfs.OverwriteIndex{Index: overwriteIndex})
+	if err != nil {
+		return 0, grpcutil.ScrubGRPC(err)
+	}
+	writer.request.Checksums = algorithms
+	defer func() {
+		if err := writer.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+	written, err := io.Copy(writer, reader)
+	return int(written), grpcutil.ScrubGRPC(err)
+}
+
 //PutFileSplit writes a file to PFS from a reader
 // delimiter is used to tell PFS how to break the input into blocks
 func (c APIClient) PutFileSplit(repoName string, commitID string, path string, delimiter pfs.Delimiter, targetFileDatums int64, targetFileBytes int64, headerRecords int64, overwrite bool, reader io.Reader) (_ int, retErr error) {
@@ -1149,6 +1279,34 @@ func (c APIClient) GetFile(repoName string, commitID string, path string, offset
 	return nil
 }
 
+// GetFileRecords gets the records of a file at a specific Commit, split by
+// delimiter, and writes them to writer. offset specifies a number of records
+// that should be skipped in the beginning of the file, and size limits the
+// number of records that are returned (if it's 0 then all of them are).
+// Only whole records are returned.
+func (c APIClient) GetFileRecords(repoName string, commitID string, path string, delimiter pfs.Delimiter, offset int64, size int64, writer io.Writer) error {
+	if c.limiter != nil {
+		c.limiter.Acquire()
+		defer c.limiter.Release()
+	}
+	apiGetFileClient, err := c.PfsAPIClient.GetFile(
+		c.Ctx(),
+		&pfs.GetFileRequest{
+			File:          NewFile(repoName, commitID, path),
+			Delimiter:     delimiter,
+			OffsetRecords: offset,
+			SizeRecords:   size,
+		},
+	)
+	if err != nil {
+		return grpcutil.ScrubGRPC(err)
+	}
+	if err := grpcutil.WriteFromStreamingBytesClient(apiGetFileClient, writer); err != nil {
+		return grpcutil.ScrubGRPC(err)
+	}
+	return nil
+}
+
 // GetFileReader returns a reader for the contents of a file at a specific Commit.
 // offset specifies a number of bytes that should be skipped in the beginning of the file.
 // size limits the total amount of data returned, note you will get fewer bytes
@@ -1199,11 +1357,19 @@ func (c APIClient) InspectFile(repoName string, commitID string, path string) (*
 	return c.inspectFile(repoName, commitID, path)
 }
 
-func (c APIClient) inspectFile(repoName string, commitID string, path string) (*pfs.FileInfo, error) {
+// InspectFileChecksums is like InspectFile, but the returned FileInfo also
+// has the file's checksums in 'algorithms' (see
+// pfs.InspectFileRequest.Checksums).
+func (c APIClient) InspectFileChecksums(repoName string, commitID string, path string, algorithms ...pfs.ChecksumAlgorithm) (*pfs.FileInfo, error) {
+	return c.inspectFile(repoName, commitID, path, algorithms...)
+}
+
+func (c APIClient) inspectFile(repoName string, commitID string, path string, algorithms ...pfs.ChecksumAlgorithm) (*pfs.FileInfo, error) {
 	fileInfo, err := c.PfsAPIClient.InspectFile(
 		c.Ctx(),
 		&pfs.InspectFileRequest{
-			File: NewFile(repoName, commitID, path),
+			File:      NewFile(repoName, commitID, path),
+			Checksums: algorithms,
 		},
 	)
 	if err != nil {
@@ -1238,13 +1404,19 @@ func (c APIClient) ListFileHistory(repoName string, commitID string, path string
 
 // ListFileF returns info about all files in a Commit under path, calling f with each FileInfo.
 func (c APIClient) ListFileF(repoName string, commitID string, path string, history int64, f func(fi *pfs.FileInfo) error) error {
-	fs, err := c.PfsAPIClient.ListFileStream(
-		c.Ctx(),
-		&pfs.ListFileRequest{
-			File:    NewFile(repoName, commitID, path),
-			History: history,
-		},
-	)
+	return c.ListFilePageF(&pfs.ListFileRequest{
+		File:    NewFile(repoName, commitID, path),
+		History: history,
+	}, f)
+}
+
+// ListFilePageF is like ListFileF, but takes a whole request, so that a page
+// of the files under a directory can be selected on the server (see
+// pfs.ListFileRequest). Large directories can be paged through by setting
+// `req.Limit`, and setting `req.StartAfter` to the path of the last file of
+// the previous page.
+func (c APIClient) ListFilePageF(req *pfs.ListFileRequest, f func(fi *pfs.FileInfo) error) error {
+	fs, err := c.PfsAPIClient.ListFileStream(c.Ctx(), req)
 	if err != nil {
 		return grpcutil.ScrubGRPC(err)
 	}
@@ -1264,6 +1436,23 @@ func (c APIClient) ListFileF(repoName string, commitID string, path string, hist
 	}
 }
 
+// ListFileSummary returns the number and total size of the regular files that
+// ListFile would return for path in a Commit, without returning the files
+// themselves.
+func (c APIClient) ListFileSummary(repoName string, commitID string, path string) (int64, uint64, error) {
+	fileInfos, err := c.PfsAPIClient.ListFile(
+		c.Ctx(),
+		&pfs.ListFileRequest{
+			File:    NewFile(repoName, commitID, path),
+			Summary: true,
+		},
+	)
+	if err != nil {
+		return 0, 0, grpcutil.ScrubGRPC(err)
+	}
+	return fileInfos.Count, fileInfos.SizeBytes, nil
+}
+
 // GlobFile returns files that match a given glob pattern in a given commit.
 // The pattern is documented here:
 // https://golang.org/pkg/path/filepath/#Match
@@ -1295,13 +1484,24 @@ func (c APIClient) GlobFile(repoName string, commitID string, pattern string) ([
 // calling f with each FileInfo. The pattern is documented here:
 // https://golang.org/pkg/path/filepath/#Match
 func (c APIClient) GlobFileF(repoName string, commitID string, pattern string, f func(fi *pfs.FileInfo) error) error {
-	fs, err := c.PfsAPIClient.GlobFileStream(
-		c.Ctx(),
-		&pfs.GlobFileRequest{
-			Commit:  NewCommit(repoName, commitID),
-			Pattern: pattern,
-		},
-	)
+	return c.globFileF(&pfs.GlobFileRequest{
+		Commit:  NewCommit(repoName, commitID),
+		Pattern: pattern,
+	}, f)
+}
+
+// GlobFileSizeOnlyF is like GlobFileF, except that the FileInfos passed to
+// 'f' only contain each file's path, type, size, hash and committed time.
+func (c APIClient) GlobFileSizeOnlyF(repoName string, commitID string, pattern string, f func(fi *pfs.FileInfo) error) error {
+	return c.globFileF(&pfs.GlobFileRequest{
+		Commit:   NewCommit(repoName, commitID),
+		Pattern:  pattern,
+		SizeOnly: true,
+	}, f)
+}
+
+func (c APIClient) globFileF(request *pfs.GlobFileRequest, f func(fi *pfs.FileInfo) error) error {
+	fs, err := c.PfsAPIClient.GlobFileStream(c.Ctx(), request)
 	if err != nil {
 		return grpcutil.ScrubGRPC(err)
 	}
@@ -1321,6 +1521,24 @@ func (c APIClient) GlobFileF(repoName string, commitID string, pattern string, f
 	}
 }
 
+// GlobFileSummary returns the number and total size of the regular files that
+// match a given glob pattern in a given commit, without returning the files
+// themselves.
+func (c APIClient) GlobFileSummary(repoName string, commitID string, pattern string) (int64, uint64, error) {
+	fileInfos, err := c.PfsAPIClient.GlobFile(
+		c.Ctx(),
+		&pfs.GlobFileRequest{
+			Commit:  NewCommit(repoName, commitID),
+			Pattern: pattern,
+			Summary: true,
+		},
+	)
+	if err != nil {
+		return 0, 0, grpcutil.ScrubGRPC(err)
+	}
+	return fileInfos.Count, fileInfos.SizeBytes, nil
+}
+
 // DiffFile returns the difference between 2 paths, old path may be omitted in
 // which case the parent of the new path will be used. DiffFile return 2 values
 // (unless it returns an error) the first value is files present under new
@@ -1346,6 +1564,25 @@ func (c APIClient) DiffFile(newRepoName, newCommitID, newPath, oldRepoName,
 	return resp.NewFiles, resp.OldFiles, nil
 }
 
+// SearchFiles returns the files in a finished commit that the repo's index
+// extractors have indexed with all of 'attributes' (an empty value matches
+// any value of the attribute). If 'pattern' is set, only files matching the
+// glob pattern are returned.
+func (c APIClient) SearchFiles(repoName string, commitID string, pattern string, attributes map[string]string) ([]*pfs.IndexedFile, error) {
+	resp, err := c.PfsAPIClient.SearchFiles(
+		c.Ctx(),
+		&pfs.SearchFilesRequest{
+			Commit:     NewCommit(repoName, commitID),
+			Pattern:    pattern,
+			Attributes: attributes,
+		},
+	)
+	if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	return resp.Files, nil
+}
+
 // WalkFn is the type of the function called for each file in Walk.
 // Returning a non-nil error from WalkFn will result in Walk aborting and
 // returning said error.
@@ -1355,9 +1592,14 @@ type WalkFn func(*pfs.FileInfo) error
 // file found under path in lexicographical order. This includes both regular
 // files and directories.
 func (c APIClient) Walk(repoName string, commitID string, path string, f WalkFn) error {
-	fs, err := c.PfsAPIClient.WalkFile(
-		c.Ctx(),
-		&pfs.WalkFileRequest{File: NewFile(repoName, commitID, path)})
+	return c.WalkFileF(&pfs.WalkFileRequest{File: NewFile(repoName, commitID, path)}, f)
+}
+
+// WalkFileF walks the files below request.File, like Walk, but only calls 'f'
+// with those that match the request's max_depth and file_type filters (see
+// pfs.WalkFileRequest).
+func (c APIClient) WalkFileF(request *pfs.WalkFileRequest, f WalkFn) error {
+	fs, err := c.PfsAPIClient.WalkFile(c.Ctx(), request)
 	if err != nil {
 		return grpcutil.ScrubGRPC(err)
 	}
@@ -1517,7 +1759,7 @@ type putObjectWriteCloser struct {
 	object  *pfs.Object
 }
 
-func (c APIClient) newPutObjectWriteCloser(tags ...string) (*putObjectWriteCloser, error) {
+func (c APIClient) newPutObjectWriteCloser(class pfs.StorageClass, tags ...string) (*putObjectWriteCloser, error) {
 	client, err := c.ObjectAPIClient.PutObject(c.Ctx())
 	if err != nil {
 		return nil, grpcutil.ScrubGRPC(err)
@@ -1528,7 +1770,8 @@ func (c APIClient) newPutObjectWriteCloser(tags ...string) (*putObjectWriteClose
 	}
 	return &putObjectWriteCloser{
 		request: &pfs.PutObjectRequest{
-			Tags: _tags,
+			Tags:         _tags,
+			StorageClass: class,
 		},
 		client: client,
 	}, nil
@@ -1540,6 +1783,7 @@ func (w *putObjectWriteCloser) Write(p []byte) (int, error) {
 		return 0, grpcutil.ScrubGRPC(err)
 	}
 	w.request.Tags = nil
+	w.request.StorageClass = pfs.StorageClass_STANDARD
 	return len(p), nil
 }
 
@@ -1654,13 +1898,13 @@ type putObjectSplitWriteCloser struct {
 	objects []*pfs.Object
 }
 
-func (c APIClient) newPutObjectSplitWriteCloser() (*putObjectSplitWriteCloser, error) {
+func (c APIClient) newPutObjectSplitWriteCloser(class pfs.StorageClass) (*putObjectSplitWriteCloser, error) {
 	client, err := c.ObjectAPIClient.PutObjectSplit(c.Ctx())
 	if err != nil {
 		return nil, grpcutil.ScrubGRPC(err)
 	}
 	return &putObjectSplitWriteCloser{
-		request: &pfs.PutObjectRequest{},
+		request: &pfs.PutObjectRequest{StorageClass: class},
 		client:  client,
 	}, nil
 }
@@ -1670,6 +1914,7 @@ func (w *putObjectSplitWriteCloser) Write(p []byte) (int, error) {
 	if err := w.client.Send(w.request); err != nil {
 		return 0, grpcutil.ScrubGRPC(err)
 	}
+	w.request.StorageClass = pfs.StorageClass_STANDARD
 	return len(p), nil
 }
 
@@ -0,0 +1,283 @@
+package pfs
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/grpcstatus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The resource types and reasons in the status of PFS errors (see
+// grpcstatus.New), which identify them across GRPC boundaries
+const (
+	repoResource   = "pfs.repo"
+	branchResource = "pfs.branch"
+	commitResource = "pfs.commit"
+	fileResource   = "pfs.file"
+
+	reasonNotFound       = "not found"
+	reasonExists         = "exists"
+	reasonNoHead         = "no head"
+	reasonFinished       = "finished"
+	reasonDeleted        = "deleted"
+	reasonParentNotFound = "parent not found"
+	reasonNotFinished    = "not finished"
+)
+
+// ErrFileNotFound represents a file-not-found error.
+type ErrFileNotFound struct {
+	File *File
+}
+
+// ErrRepoNotFound represents a repo-not-found error.
+type ErrRepoNotFound struct {
+	Repo *Repo
+}
+
+// ErrRepoExists represents a repo-exists error.
+type ErrRepoExists struct {
+	Repo *Repo
+}
+
+// ErrCommitNotFound represents a commit-not-found error.
+type ErrCommitNotFound struct {
+	Commit *Commit
+}
+
+// ErrBranchNoHead represents an error encountered because a branch has no
+// head (e.g. inspectCommit(master) when 'master' has no commits)
+type ErrBranchNoHead struct {
+	Branch *Branch
+}
+
+// ErrCommitExists represents an error where the commit already exists.
+type ErrCommitExists struct {
+	Commit *Commit
+}
+
+// ErrCommitFinished represents an error where the commit has been finished
+// (e.g from PutFile or DeleteFile)
+type ErrCommitFinished struct {
+	Commit *Commit
+}
+
+// ErrCommitDeleted represents an error where the commit has been deleted (e.g.
+// from InspectCommit)
+type ErrCommitDeleted struct {
+	Commit *Commit
+}
+
+// ErrParentCommitNotFound represents a parent-commit-not-found error.
+type ErrParentCommitNotFound struct {
+	Commit *Commit
+}
+
+// ErrOutputCommitNotFinished represents an error where the commit has not
+// been finished
+type ErrOutputCommitNotFinished struct {
+	Commit *Commit
+}
+
+func (e ErrFileNotFound) Error() string {
+	return fmt.Sprintf("file %v not found in repo %v at commit %v", e.File.Path, e.File.Commit.Repo.Name, e.File.Commit.ID)
+}
+
+// GRPCStatus returns the status that 'e' is sent with
+func (e ErrFileNotFound) GRPCStatus() *status.Status {
+	return grpcstatus.New(codes.NotFound, fileResource, e.File.Commit.FullID()+":"+e.File.Path, reasonNotFound, e.Error())
+}
+
+func (e ErrRepoNotFound) Error() string {
+	return fmt.Sprintf("repo %v not found", e.Repo.Name)
+}
+
+// GRPCStatus returns the status that 'e' is sent with
+func (e ErrRepoNotFound) GRPCStatus() *status.Status {
+	return grpcstatus.New(codes.NotFound, repoResource, e.Repo.Name, reasonNotFound, e.Error())
+}
+
+func (e ErrRepoExists) Error() string {
+	return fmt.Sprintf("repo %v already exists", e.Repo.Name)
+}
+
+// GRPCStatus returns the status that 'e' is sent with
+func (e ErrRepoExists) GRPCStatus() *status.Status {
+	return grpcstatus.New(codes.AlreadyExists, repoResource, e.Repo.Name, reasonExists, e.Error())
+}
+
+func (e ErrCommitNotFound) Error() string {
+	return fmt.Sprintf("commit %v not found in repo %v", e.Commit.ID, e.Commit.Repo.Name)
+}
+
+// GRPCStatus returns the status that 'e' is sent with
+func (e ErrCommitNotFound) GRPCStatus() *status.Status {
+	return grpcstatus.New(codes.NotFound, commitResource, e.Commit.FullID(), reasonNotFound, e.Error())
+}
+
+func (e ErrBranchNoHead) Error() string {
+	// the dashboard is matching on this message in stats. Please open an issue on the dash before changing this
+	return fmt.Sprintf("the branch \"%s\" has no head (create one with 'start commit')", e.Branch.Name)
+}
+
+// GRPCStatus returns the status that 'e' is sent with
+func (e ErrBranchNoHead) GRPCStatus() *status.Status {
+	name := e.Branch.Name
+	if e.Branch.Repo != nil {
+		name = e.Branch.Repo.Name + "@" + name
+	}
+	return grpcstatus.New(codes.FailedPrecondition, branchResource, name, reasonNoHead, e.Error())
+}
+
+func (e ErrCommitExists) Error() string {
+	return fmt.Sprintf("commit %v already exists in repo %v", e.Commit.ID, e.Commit.Repo.Name)
+}
+
+// GRPCStatus returns the status that 'e' is sent with
+func (e ErrCommitExists) GRPCStatus() *status.Status {
+	return grpcstatus.New(codes.AlreadyExists, commitResource, e.Commit.FullID(), reasonExists, e.Error())
+}
+
+func (e ErrCommitFinished) Error() string {
+	return fmt.Sprintf("commit %v in repo %v has already finished", e.Commit.ID, e.Commit.Repo.Name)
+}
+
+// GRPCStatus returns the status that 'e' is sent with
+func (e ErrCommitFinished) GRPCStatus() *status.Status {
+	return grpcstatus.New(codes.FailedPrecondition, commitResource, e.Commit.FullID(), reasonFinished, e.Error())
+}
+
+func (e ErrCommitDeleted) Error() string {
+	return fmt.Sprintf("commit %v/%v was deleted", e.Commit.Repo.Name, e.Commit.ID)
+}
+
+// GRPCStatus returns the status that 'e' is sent with
+func (e ErrCommitDeleted) GRPCStatus() *status.Status {
+	return grpcstatus.New(codes.NotFound, commitResource, e.Commit.FullID(), reasonDeleted, e.Error())
+}
+
+func (e ErrParentCommitNotFound) Error() string {
+	return fmt.Sprintf("parent commit %v not found in repo %v", e.Commit.ID, e.Commit.Repo.Name)
+}
+
+// GRPCStatus returns the status that 'e' is sent with
+func (e ErrParentCommitNotFound) GRPCStatus() *status.Status {
+	return grpcstatus.New(codes.NotFound, commitResource, e.Commit.FullID(), reasonParentNotFound, e.Error())
+}
+
+func (e ErrOutputCommitNotFinished) Error() string {
+	return fmt.Sprintf("output commit %v not finished", e.Commit.ID)
+}
+
+// GRPCStatus returns the status that 'e' is sent with
+func (e ErrOutputCommitNotFinished) GRPCStatus() *status.Status {
+	return grpcstatus.New(codes.FailedPrecondition, commitResource, e.Commit.FullID(), reasonNotFinished, e.Error())
+}
+
+// The messages of PFS errors. They're only matched if an error has lost its
+// status, e.g. because it was wrapped in another error, or it came from a
+// pachd that doesn't send statuses.
+var (
+	commitNotFoundRe          = regexp.MustCompile("commit [^ ]+ not found in repo [^ ]+")
+	commitDeletedRe           = regexp.MustCompile("commit [^ ]+/[^ ]+ was deleted")
+	commitFinishedRe          = regexp.MustCompile("commit [^ ]+ in repo [^ ]+ has already finished")
+	repoNotFoundRe            = regexp.MustCompile(`repos/ ?[a-zA-Z0-9.\-_]{1,255} not found`)
+	branchNotFoundRe          = regexp.MustCompile(`branches/[a-zA-Z0-9.\-_]{1,255}/ [^ ]+ not found`)
+	fileNotFoundRe            = regexp.MustCompile(`file .+ not found`)
+	hasNoHeadRe               = regexp.MustCompile(`the branch .+ has no head \(create one with 'start commit'\)`)
+	outputCommitNotFinishedRe = regexp.MustCompile("output commit .+ not finished")
+)
+
+// IsCommitNotFoundErr returns true if 'err' is an ErrCommitNotFound (or an
+// ErrParentCommitNotFound)
+func IsCommitNotFoundErr(err error) bool {
+	if err == nil {
+		return false
+	}
+	if grpcstatus.Is(err, commitResource, reasonNotFound) ||
+		grpcstatus.Is(err, commitResource, reasonParentNotFound) {
+		return true
+	}
+	return commitNotFoundRe.MatchString(status.Convert(err).Message())
+}
+
+// IsCommitDeletedErr returns true if 'err' is an ErrCommitDeleted
+func IsCommitDeletedErr(err error) bool {
+	if err == nil {
+		return false
+	}
+	if grpcstatus.Is(err, commitResource, reasonDeleted) {
+		return true
+	}
+	return commitDeletedRe.MatchString(status.Convert(err).Message())
+}
+
+// IsCommitFinishedErr returns true if 'err' is an ErrCommitFinished
+func IsCommitFinishedErr(err error) bool {
+	if err == nil {
+		return false
+	}
+	if grpcstatus.Is(err, commitResource, reasonFinished) {
+		return true
+	}
+	return commitFinishedRe.MatchString(status.Convert(err).Message())
+}
+
+// IsRepoNotFoundErr returns true if 'err' is an ErrRepoNotFound, or an error
+// about a repo not being found in etcd
+func IsRepoNotFoundErr(err error) bool {
+	if err == nil {
+		return false
+	}
+	if grpcstatus.Is(err, repoResource, reasonNotFound) {
+		return true
+	}
+	return repoNotFoundRe.MatchString(err.Error())
+}
+
+// IsBranchNotFoundErr returns true if 'err' is an error about a branch not
+// being found in etcd. These errors have no status, so they're matched by
+// message.
+func IsBranchNotFoundErr(err error) bool {
+	if err == nil {
+		return false
+	}
+	return branchNotFoundRe.MatchString(err.Error())
+}
+
+// IsFileNotFoundErr returns true if 'err' is an ErrFileNotFound
+func IsFileNotFoundErr(err error) bool {
+	if err == nil {
+		return false
+	}
+	if grpcstatus.Is(err, fileResource, reasonNotFound) {
+		return true
+	}
+	return fileNotFoundRe.MatchString(err.Error())
+}
+
+// IsBranchNoHeadErr returns true if 'err' is an ErrBranchNoHead, i.e. it's
+// due to an operation that cannot be performed on a headless branch
+func IsBranchNoHeadErr(err error) bool {
+	if err == nil {
+		return false
+	}
+	if grpcstatus.Is(err, branchResource, reasonNoHead) {
+		return true
+	}
+	return hasNoHeadRe.MatchString(err.Error())
+}
+
+// IsOutputCommitNotFinishedErr returns true if 'err' is an
+// ErrOutputCommitNotFinished, i.e. it's due to an operation that cannot be
+// performed on an unfinished output commit
+func IsOutputCommitNotFinishedErr(err error) bool {
+	if err == nil {
+		return false
+	}
+	if grpcstatus.Is(err, commitResource, reasonNotFinished) {
+		return true
+	}
+	return outputCommitNotFinishedRe.MatchString(err.Error())
+}
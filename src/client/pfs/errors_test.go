@@ -0,0 +1,49 @@
+package pfs_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/grpcstatus"
+	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// sent simulates sending 'err' from a GRPC server to a client, which scrubs it
+func sent(err error) error {
+	return grpcutil.ScrubGRPC(status.ErrorProto(status.Convert(err).Proto()))
+}
+
+func TestErrorStatus(t *testing.T) {
+	commit := &pfs.Commit{Repo: &pfs.Repo{Name: "foo"}, ID: "bar"}
+	err := sent(pfs.ErrCommitNotFound{Commit: commit})
+	require.Equal(t, "commit bar not found in repo foo", err.Error())
+	require.Equal(t, codes.NotFound, status.Code(err))
+	require.True(t, pfs.IsCommitNotFoundErr(err))
+	require.False(t, pfs.IsCommitDeletedErr(err))
+	require.False(t, pfs.IsFileNotFoundErr(err))
+
+	err = sent(pfs.ErrBranchNoHead{Branch: &pfs.Branch{Repo: &pfs.Repo{Name: "foo"}, Name: "master"}})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	require.True(t, pfs.IsBranchNoHeadErr(err))
+	require.False(t, pfs.IsCommitNotFoundErr(err))
+
+	err = sent(pfs.ErrFileNotFound{File: &pfs.File{Commit: commit, Path: "a"}})
+	require.True(t, pfs.IsFileNotFoundErr(err))
+	require.False(t, pfs.IsRepoNotFoundErr(err))
+
+	// Errors are recognized by their status rather than their message
+	err = sent(grpcstatus.New(codes.NotFound, "pfs.file", "foo/bar:a", "not found", "no such file").Err())
+	require.True(t, pfs.IsFileNotFoundErr(err))
+	require.False(t, pfs.IsFileNotFoundErr(sent(status.Error(codes.NotFound, "no such file"))))
+
+	// Errors that lost their status, e.g. by being wrapped, are still
+	// recognized by their message
+	require.True(t, pfs.IsCommitNotFoundErr(fmt.Errorf("error inspecting commit: %v", sent(pfs.ErrCommitNotFound{Commit: commit}))))
+	require.True(t, pfs.IsCommitFinishedErr(errors.New("commit bar in repo foo has already finished")))
+	require.False(t, pfs.IsCommitFinishedErr(nil))
+}
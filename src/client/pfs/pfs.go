@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"hash"
+	"strings"
 )
 
 var (
@@ -39,3 +40,21 @@ func GetBlock(hash hash.Hash) *Block {
 		Hash: base64.URLEncoding.EncodeToString(hash.Sum(nil)),
 	}
 }
+
+// ParseChecksumAlgorithms parses a comma-separated list of checksum
+// algorithms (e.g. "sha256,md5"). Case is ignored.
+func ParseChecksumAlgorithms(s string) ([]ChecksumAlgorithm, error) {
+	var result []ChecksumAlgorithm
+	for _, name := range strings.Split(s, ",") {
+		name = strings.ToUpper(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		algorithm, ok := ChecksumAlgorithm_value[name]
+		if !ok || ChecksumAlgorithm(algorithm) == ChecksumAlgorithm_CHECKSUM_NONE {
+			return nil, fmt.Errorf("invalid checksum algorithm %q", name)
+		}
+		result = append(result, ChecksumAlgorithm(algorithm))
+	}
+	return result, nil
+}
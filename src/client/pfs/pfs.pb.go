@@ -29,6 +29,40 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+// StorageClass is a hint of how often the data in a block is read. Object
+// storage backends that support storage tiers map it to one of theirs, and
+// the others ignore it.
+type StorageClass int32
+
+const (
+	StorageClass_STANDARD StorageClass = 0
+	// INFREQUENT_ACCESS is STANDARD_IA in S3 and the Cool tier in Azure
+	StorageClass_INFREQUENT_ACCESS StorageClass = 1
+	// ARCHIVE is GLACIER in S3 and the Archive tier in Azure. Archived blocks
+	// are restored when they're read, which can take hours.
+	StorageClass_ARCHIVE StorageClass = 2
+)
+
+var StorageClass_name = map[int32]string{
+	0: "STANDARD",
+	1: "INFREQUENT_ACCESS",
+	2: "ARCHIVE",
+}
+
+var StorageClass_value = map[string]int32{
+	"STANDARD":          0,
+	"INFREQUENT_ACCESS": 1,
+	"ARCHIVE":           2,
+}
+
+func (x StorageClass) String() string {
+	return proto.EnumName(StorageClass_name, int32(x))
+}
+
+func (StorageClass) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_b48f014707f6595c, []int{0}
+}
+
 // These are the different places where a commit may be originated from
 type OriginKind int32
 
@@ -55,7 +89,7 @@ func (x OriginKind) String() string {
 }
 
 func (OriginKind) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{0}
+	return fileDescriptor_b48f014707f6595c, []int{1}
 }
 
 type FileType int32
@@ -83,7 +117,37 @@ func (x FileType) String() string {
 }
 
 func (FileType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{1}
+	return fileDescriptor_b48f014707f6595c, []int{2}
+}
+
+// ChecksumAlgorithm is a standard digest of a file's content, which PFS can
+// compute in addition to its own hash (FileInfo.hash)
+type ChecksumAlgorithm int32
+
+const (
+	ChecksumAlgorithm_CHECKSUM_NONE ChecksumAlgorithm = 0
+	ChecksumAlgorithm_SHA256        ChecksumAlgorithm = 1
+	ChecksumAlgorithm_MD5           ChecksumAlgorithm = 2
+)
+
+var ChecksumAlgorithm_name = map[int32]string{
+	0: "CHECKSUM_NONE",
+	1: "SHA256",
+	2: "MD5",
+}
+
+var ChecksumAlgorithm_value = map[string]int32{
+	"CHECKSUM_NONE": 0,
+	"SHA256":        1,
+	"MD5":           2,
+}
+
+func (x ChecksumAlgorithm) String() string {
+	return proto.EnumName(ChecksumAlgorithm_name, int32(x))
+}
+
+func (ChecksumAlgorithm) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_b48f014707f6595c, []int{3}
 }
 
 // CommitState describes the states a commit can be in.
@@ -113,7 +177,35 @@ func (x CommitState) String() string {
 }
 
 func (CommitState) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{2}
+	return fileDescriptor_b48f014707f6595c, []int{4}
+}
+
+type FileChangeType int32
+
+const (
+	FileChangeType_FILE_CREATED FileChangeType = 0
+	FileChangeType_FILE_UPDATED FileChangeType = 1
+	FileChangeType_FILE_DELETED FileChangeType = 2
+)
+
+var FileChangeType_name = map[int32]string{
+	0: "FILE_CREATED",
+	1: "FILE_UPDATED",
+	2: "FILE_DELETED",
+}
+
+var FileChangeType_value = map[string]int32{
+	"FILE_CREATED": 0,
+	"FILE_UPDATED": 1,
+	"FILE_DELETED": 2,
+}
+
+func (x FileChangeType) String() string {
+	return proto.EnumName(FileChangeType_name, int32(x))
+}
+
+func (FileChangeType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_b48f014707f6595c, []int{5}
 }
 
 type Delimiter int32
@@ -124,6 +216,9 @@ const (
 	Delimiter_LINE Delimiter = 2
 	Delimiter_SQL  Delimiter = 3
 	Delimiter_CSV  Delimiter = 4
+	// PROTOBUF records are protobuf messages, each preceded by its size as a
+	// varint (as written by e.g. Java's writeDelimitedTo)
+	Delimiter_PROTOBUF Delimiter = 5
 )
 
 var Delimiter_name = map[int32]string{
@@ -132,14 +227,16 @@ var Delimiter_name = map[int32]string{
 	2: "LINE",
 	3: "SQL",
 	4: "CSV",
+	5: "PROTOBUF",
 }
 
 var Delimiter_value = map[string]int32{
-	"NONE": 0,
-	"JSON": 1,
-	"LINE": 2,
-	"SQL":  3,
-	"CSV":  4,
+	"NONE":     0,
+	"JSON":     1,
+	"LINE":     2,
+	"SQL":      3,
+	"CSV":      4,
+	"PROTOBUF": 5,
 }
 
 func (x Delimiter) String() string {
@@ -147,7 +244,7 @@ func (x Delimiter) String() string {
 }
 
 func (Delimiter) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{3}
+	return fileDescriptor_b48f014707f6595c, []int{6}
 }
 
 type Repo struct {
@@ -258,6 +355,8 @@ type BranchInfo struct {
 	Provenance       []*Branch `protobuf:"bytes,3,rep,name=provenance,proto3" json:"provenance,omitempty"`
 	Subvenance       []*Branch `protobuf:"bytes,5,rep,name=subvenance,proto3" json:"subvenance,omitempty"`
 	DirectProvenance []*Branch `protobuf:"bytes,6,rep,name=direct_provenance,json=directProvenance,proto3" json:"direct_provenance,omitempty"`
+	// protection restricts who and what may commit to the branch
+	Protection *BranchProtection `protobuf:"bytes,7,opt,name=protection,proto3" json:"protection,omitempty"`
 	// Deprecated field left for backward compatibility.
 	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -333,6 +432,13 @@ func (m *BranchInfo) GetDirectProvenance() []*Branch {
 	return nil
 }
 
+func (m *BranchInfo) GetProtection() *BranchProtection {
+	if m != nil {
+		return m.Protection
+	}
+	return nil
+}
+
 func (m *BranchInfo) GetName() string {
 	if m != nil {
 		return m.Name
@@ -340,6 +446,72 @@ func (m *BranchInfo) GetName() string {
 	return ""
 }
 
+// BranchProtection restricts the commits that may be made on a branch, e.g.
+// so that a production pipeline's output branch can't be written to by hand.
+// It's checked by StartCommit, BuildCommit, PutFile and DeleteFile, but not
+// for the commits that PFS creates when it propagates commits downstream.
+type BranchProtection struct {
+	// require_provenance rejects commits on the branch that have no
+	// provenance, such as files put on it by users, so that only pipelines
+	// (whose commits have their spec commit as provenance) and commits with
+	// explicit provenance can be made on it.
+	RequireProvenance bool `protobuf:"varint,1,opt,name=require_provenance,json=requireProvenance,proto3" json:"require_provenance,omitempty"`
+	// allowed_committers, if set, are the only users (e.g. "github:alice" or
+	// "robot:ci") who may commit to the branch. It's only checked if auth is
+	// active.
+	AllowedCommitters    []string `protobuf:"bytes,2,rep,name=allowed_committers,json=allowedCommitters,proto3" json:"allowed_committers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BranchProtection) Reset()         { *m = BranchProtection{} }
+func (m *BranchProtection) String() string { return proto.CompactTextString(m) }
+func (*BranchProtection) ProtoMessage()    {}
+func (*BranchProtection) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b48f014707f6595c, []int{3}
+}
+func (m *BranchProtection) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *BranchProtection) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_BranchProtection.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *BranchProtection) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BranchProtection.Merge(m, src)
+}
+func (m *BranchProtection) XXX_Size() int {
+	return m.Size()
+}
+func (m *BranchProtection) XXX_DiscardUnknown() {
+	xxx_messageInfo_BranchProtection.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BranchProtection proto.InternalMessageInfo
+
+func (m *BranchProtection) GetRequireProvenance() bool {
+	if m != nil {
+		return m.RequireProvenance
+	}
+	return false
+}
+
+func (m *BranchProtection) GetAllowedCommitters() []string {
+	if m != nil {
+		return m.AllowedCommitters
+	}
+	return nil
+}
+
 type BranchInfos struct {
 	BranchInfo           []*BranchInfo `protobuf:"bytes,1,rep,name=branch_info,json=branchInfo,proto3" json:"branch_info,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
@@ -351,7 +523,7 @@ func (m *BranchInfos) Reset()         { *m = BranchInfos{} }
 func (m *BranchInfos) String() string { return proto.CompactTextString(m) }
 func (*BranchInfos) ProtoMessage()    {}
 func (*BranchInfos) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{3}
+	return fileDescriptor_b48f014707f6595c, []int{4}
 }
 func (m *BranchInfos) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -399,7 +571,7 @@ func (m *File) Reset()         { *m = File{} }
 func (m *File) String() string { return proto.CompactTextString(m) }
 func (*File) ProtoMessage()    {}
 func (*File) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{4}
+	return fileDescriptor_b48f014707f6595c, []int{5}
 }
 func (m *File) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -453,7 +625,7 @@ func (m *Block) Reset()         { *m = Block{} }
 func (m *Block) String() string { return proto.CompactTextString(m) }
 func (*Block) ProtoMessage()    {}
 func (*Block) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{5}
+	return fileDescriptor_b48f014707f6595c, []int{6}
 }
 func (m *Block) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -500,7 +672,7 @@ func (m *Object) Reset()         { *m = Object{} }
 func (m *Object) String() string { return proto.CompactTextString(m) }
 func (*Object) ProtoMessage()    {}
 func (*Object) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{6}
+	return fileDescriptor_b48f014707f6595c, []int{7}
 }
 func (m *Object) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -547,7 +719,7 @@ func (m *Tag) Reset()         { *m = Tag{} }
 func (m *Tag) String() string { return proto.CompactTextString(m) }
 func (*Tag) ProtoMessage()    {}
 func (*Tag) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{7}
+	return fileDescriptor_b48f014707f6595c, []int{8}
 }
 func (m *Tag) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -590,6 +762,17 @@ type RepoInfo struct {
 	SizeBytes   uint64           `protobuf:"varint,3,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
 	Description string           `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
 	Branches    []*Branch        `protobuf:"bytes,7,rep,name=branches,proto3" json:"branches,omitempty"`
+	// index_extractors are the names of the extractors that index the content
+	// of the repo's files when its commits finish, for SearchFiles
+	IndexExtractors []string `protobuf:"bytes,8,rep,name=index_extractors,json=indexExtractors,proto3" json:"index_extractors,omitempty"`
+	// storage_policy controls how the blocks that hold the repo's file content
+	// are stored
+	StoragePolicy *StoragePolicy `protobuf:"bytes,9,opt,name=storage_policy,json=storagePolicy,proto3" json:"storage_policy,omitempty"`
+	// commit_ttl is how long the repo's commits are kept once they're
+	// finished. Older commits are deleted by ExpireCommits (which pachd runs
+	// periodically), unless they're the head of a branch or have live
+	// downstream commits. Commits are kept forever if it's unset.
+	CommitTTL *types.Duration `protobuf:"bytes,10,opt,name=commit_ttl,json=commitTtl,proto3" json:"commit_ttl,omitempty"`
 	// Set by ListRepo and InspectRepo if Pachyderm's auth system is active, but
 	// not stored in etcd. To set a user's auth scope for a repo, use the
 	// Pachyderm Auth API (in src/client/auth/auth.proto)
@@ -603,7 +786,7 @@ func (m *RepoInfo) Reset()         { *m = RepoInfo{} }
 func (m *RepoInfo) String() string { return proto.CompactTextString(m) }
 func (*RepoInfo) ProtoMessage()    {}
 func (*RepoInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{8}
+	return fileDescriptor_b48f014707f6595c, []int{9}
 }
 func (m *RepoInfo) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -667,6 +850,27 @@ func (m *RepoInfo) GetBranches() []*Branch {
 	return nil
 }
 
+func (m *RepoInfo) GetIndexExtractors() []string {
+	if m != nil {
+		return m.IndexExtractors
+	}
+	return nil
+}
+
+func (m *RepoInfo) GetStoragePolicy() *StoragePolicy {
+	if m != nil {
+		return m.StoragePolicy
+	}
+	return nil
+}
+
+func (m *RepoInfo) GetCommitTTL() *types.Duration {
+	if m != nil {
+		return m.CommitTTL
+	}
+	return nil
+}
+
 func (m *RepoInfo) GetAuthInfo() *RepoAuthInfo {
 	if m != nil {
 		return m.AuthInfo
@@ -674,6 +878,57 @@ func (m *RepoInfo) GetAuthInfo() *RepoAuthInfo {
 	return nil
 }
 
+// StoragePolicy controls how the blocks that hold a repo's file content are
+// stored
+type StoragePolicy struct {
+	// storage_class is the storage class of the blocks written by later
+	// commits. Blocks that are already written keep theirs.
+	StorageClass         StorageClass `protobuf:"varint,1,opt,name=storage_class,json=storageClass,proto3,enum=pfs.StorageClass" json:"storage_class,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *StoragePolicy) Reset()         { *m = StoragePolicy{} }
+func (m *StoragePolicy) String() string { return proto.CompactTextString(m) }
+func (*StoragePolicy) ProtoMessage()    {}
+func (*StoragePolicy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b48f014707f6595c, []int{10}
+}
+func (m *StoragePolicy) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StoragePolicy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_StoragePolicy.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *StoragePolicy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StoragePolicy.Merge(m, src)
+}
+func (m *StoragePolicy) XXX_Size() int {
+	return m.Size()
+}
+func (m *StoragePolicy) XXX_DiscardUnknown() {
+	xxx_messageInfo_StoragePolicy.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StoragePolicy proto.InternalMessageInfo
+
+func (m *StoragePolicy) GetStorageClass() StorageClass {
+	if m != nil {
+		return m.StorageClass
+	}
+	return StorageClass_STANDARD
+}
+
 // RepoAuthInfo includes the caller's access scope for a repo, and is returned
 // by ListRepo and InspectRepo but not persisted in etcd. It's used by the
 // Pachyderm dashboard to render repo access appropriately. To set a user's auth
@@ -691,7 +946,7 @@ func (m *RepoAuthInfo) Reset()         { *m = RepoAuthInfo{} }
 func (m *RepoAuthInfo) String() string { return proto.CompactTextString(m) }
 func (*RepoAuthInfo) ProtoMessage()    {}
 func (*RepoAuthInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{9}
+	return fileDescriptor_b48f014707f6595c, []int{11}
 }
 func (m *RepoAuthInfo) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -738,7 +993,7 @@ func (m *CommitOrigin) Reset()         { *m = CommitOrigin{} }
 func (m *CommitOrigin) String() string { return proto.CompactTextString(m) }
 func (*CommitOrigin) ProtoMessage()    {}
 func (*CommitOrigin) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{10}
+	return fileDescriptor_b48f014707f6595c, []int{12}
 }
 func (m *CommitOrigin) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -789,7 +1044,7 @@ func (m *Commit) Reset()         { *m = Commit{} }
 func (m *Commit) String() string { return proto.CompactTextString(m) }
 func (*Commit) ProtoMessage()    {}
 func (*Commit) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{11}
+	return fileDescriptor_b48f014707f6595c, []int{13}
 }
 func (m *Commit) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -846,7 +1101,7 @@ func (m *CommitRange) Reset()         { *m = CommitRange{} }
 func (m *CommitRange) String() string { return proto.CompactTextString(m) }
 func (*CommitRange) ProtoMessage()    {}
 func (*CommitRange) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{12}
+	return fileDescriptor_b48f014707f6595c, []int{14}
 }
 func (m *CommitRange) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -904,7 +1159,7 @@ func (m *CommitProvenance) Reset()         { *m = CommitProvenance{} }
 func (m *CommitProvenance) String() string { return proto.CompactTextString(m) }
 func (*CommitProvenance) ProtoMessage()    {}
 func (*CommitProvenance) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{13}
+	return fileDescriptor_b48f014707f6595c, []int{15}
 }
 func (m *CommitProvenance) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -986,7 +1241,7 @@ func (m *CommitInfo) Reset()         { *m = CommitInfo{} }
 func (m *CommitInfo) String() string { return proto.CompactTextString(m) }
 func (*CommitInfo) ProtoMessage()    {}
 func (*CommitInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{14}
+	return fileDescriptor_b48f014707f6595c, []int{16}
 }
 func (m *CommitInfo) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1148,20 +1403,27 @@ type FileInfo struct {
 	Committed *types.Timestamp `protobuf:"bytes,10,opt,name=committed,proto3" json:"committed,omitempty"`
 	// the base names (i.e. just the filenames, not the full paths) of
 	// the children
-	Children             []string    `protobuf:"bytes,6,rep,name=children,proto3" json:"children,omitempty"`
-	Objects              []*Object   `protobuf:"bytes,8,rep,name=objects,proto3" json:"objects,omitempty"`
-	BlockRefs            []*BlockRef `protobuf:"bytes,9,rep,name=blockRefs,proto3" json:"blockRefs,omitempty"`
-	Hash                 []byte      `protobuf:"bytes,7,opt,name=hash,proto3" json:"hash,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
-	XXX_unrecognized     []byte      `json:"-"`
-	XXX_sizecache        int32       `json:"-"`
+	Children  []string    `protobuf:"bytes,6,rep,name=children,proto3" json:"children,omitempty"`
+	Objects   []*Object   `protobuf:"bytes,8,rep,name=objects,proto3" json:"objects,omitempty"`
+	BlockRefs []*BlockRef `protobuf:"bytes,9,rep,name=blockRefs,proto3" json:"blockRefs,omitempty"`
+	Hash      []byte      `protobuf:"bytes,7,opt,name=hash,proto3" json:"hash,omitempty"`
+	// checksums are the file's checksums in the algorithms that the request
+	// asked for (see InspectFileRequest.checksums), in the order it asked for
+	// them
+	Checksums []*Checksum `protobuf:"bytes,11,rep,name=checksums,proto3" json:"checksums,omitempty"`
+	// file_count is the number of regular files below a directory. It's only
+	// set by WalkFile, if its request sets aggregate.
+	FileCount            int64    `protobuf:"varint,12,opt,name=file_count,json=fileCount,proto3" json:"file_count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *FileInfo) Reset()         { *m = FileInfo{} }
 func (m *FileInfo) String() string { return proto.CompactTextString(m) }
 func (*FileInfo) ProtoMessage()    {}
 func (*FileInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{15}
+	return fileDescriptor_b48f014707f6595c, []int{17}
 }
 func (m *FileInfo) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1246,6 +1508,75 @@ func (m *FileInfo) GetHash() []byte {
 	return nil
 }
 
+func (m *FileInfo) GetChecksums() []*Checksum {
+	if m != nil {
+		return m.Checksums
+	}
+	return nil
+}
+
+func (m *FileInfo) GetFileCount() int64 {
+	if m != nil {
+		return m.FileCount
+	}
+	return 0
+}
+
+type Checksum struct {
+	Algorithm            ChecksumAlgorithm `protobuf:"varint,1,opt,name=algorithm,proto3,enum=pfs.ChecksumAlgorithm" json:"algorithm,omitempty"`
+	Value                []byte            `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *Checksum) Reset()         { *m = Checksum{} }
+func (m *Checksum) String() string { return proto.CompactTextString(m) }
+func (*Checksum) ProtoMessage()    {}
+func (*Checksum) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b48f014707f6595c, []int{18}
+}
+func (m *Checksum) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Checksum) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Checksum.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Checksum) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Checksum.Merge(m, src)
+}
+func (m *Checksum) XXX_Size() int {
+	return m.Size()
+}
+func (m *Checksum) XXX_DiscardUnknown() {
+	xxx_messageInfo_Checksum.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Checksum proto.InternalMessageInfo
+
+func (m *Checksum) GetAlgorithm() ChecksumAlgorithm {
+	if m != nil {
+		return m.Algorithm
+	}
+	return ChecksumAlgorithm_CHECKSUM_NONE
+}
+
+func (m *Checksum) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
 type ByteRange struct {
 	Lower                uint64   `protobuf:"varint,1,opt,name=lower,proto3" json:"lower,omitempty"`
 	Upper                uint64   `protobuf:"varint,2,opt,name=upper,proto3" json:"upper,omitempty"`
@@ -1258,7 +1589,7 @@ func (m *ByteRange) Reset()         { *m = ByteRange{} }
 func (m *ByteRange) String() string { return proto.CompactTextString(m) }
 func (*ByteRange) ProtoMessage()    {}
 func (*ByteRange) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{16}
+	return fileDescriptor_b48f014707f6595c, []int{19}
 }
 func (m *ByteRange) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1313,7 +1644,7 @@ func (m *BlockRef) Reset()         { *m = BlockRef{} }
 func (m *BlockRef) String() string { return proto.CompactTextString(m) }
 func (*BlockRef) ProtoMessage()    {}
 func (*BlockRef) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{17}
+	return fileDescriptor_b48f014707f6595c, []int{20}
 }
 func (m *BlockRef) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1368,7 +1699,7 @@ func (m *ObjectInfo) Reset()         { *m = ObjectInfo{} }
 func (m *ObjectInfo) String() string { return proto.CompactTextString(m) }
 func (*ObjectInfo) ProtoMessage()    {}
 func (*ObjectInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{18}
+	return fileDescriptor_b48f014707f6595c, []int{21}
 }
 func (m *ObjectInfo) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1422,7 +1753,7 @@ func (m *Merge) Reset()         { *m = Merge{} }
 func (m *Merge) String() string { return proto.CompactTextString(m) }
 func (*Merge) ProtoMessage()    {}
 func (*Merge) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{19}
+	return fileDescriptor_b48f014707f6595c, []int{22}
 }
 func (m *Merge) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1469,7 +1800,7 @@ func (m *Shard) Reset()         { *m = Shard{} }
 func (m *Shard) String() string { return proto.CompactTextString(m) }
 func (*Shard) ProtoMessage()    {}
 func (*Shard) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{20}
+	return fileDescriptor_b48f014707f6595c, []int{23}
 }
 func (m *Shard) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1517,7 +1848,7 @@ func (m *PathRange) Reset()         { *m = PathRange{} }
 func (m *PathRange) String() string { return proto.CompactTextString(m) }
 func (*PathRange) ProtoMessage()    {}
 func (*PathRange) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{21}
+	return fileDescriptor_b48f014707f6595c, []int{24}
 }
 func (m *PathRange) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1561,19 +1892,29 @@ func (m *PathRange) GetUpper() string {
 }
 
 type CreateRepoRequest struct {
-	Repo                 *Repo    `protobuf:"bytes,1,opt,name=repo,proto3" json:"repo,omitempty"`
-	Description          string   `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	Update               bool     `protobuf:"varint,4,opt,name=update,proto3" json:"update,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Repo        *Repo  `protobuf:"bytes,1,opt,name=repo,proto3" json:"repo,omitempty"`
+	Description string `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Update      bool   `protobuf:"varint,4,opt,name=update,proto3" json:"update,omitempty"`
+	// index_extractors sets the repo's index extractors. If it's unset when
+	// updating a repo, the repo keeps its extractors, and if it's ["none"],
+	// they're removed.
+	IndexExtractors []string `protobuf:"bytes,5,rep,name=index_extractors,json=indexExtractors,proto3" json:"index_extractors,omitempty"`
+	// storage_policy sets the repo's storage policy. If it's unset when
+	// updating a repo, the repo keeps its policy.
+	StoragePolicy *StoragePolicy `protobuf:"bytes,6,opt,name=storage_policy,json=storagePolicy,proto3" json:"storage_policy,omitempty"`
+	// commit_ttl sets the repo's commit TTL. If it's unset when updating a
+	// repo, the repo keeps its TTL, and if it's 0, the TTL is removed.
+	CommitTTL            *types.Duration `protobuf:"bytes,7,opt,name=commit_ttl,json=commitTtl,proto3" json:"commit_ttl,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
 }
 
 func (m *CreateRepoRequest) Reset()         { *m = CreateRepoRequest{} }
 func (m *CreateRepoRequest) String() string { return proto.CompactTextString(m) }
 func (*CreateRepoRequest) ProtoMessage()    {}
 func (*CreateRepoRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{22}
+	return fileDescriptor_b48f014707f6595c, []int{25}
 }
 func (m *CreateRepoRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1623,6 +1964,27 @@ func (m *CreateRepoRequest) GetUpdate() bool {
 	return false
 }
 
+func (m *CreateRepoRequest) GetIndexExtractors() []string {
+	if m != nil {
+		return m.IndexExtractors
+	}
+	return nil
+}
+
+func (m *CreateRepoRequest) GetStoragePolicy() *StoragePolicy {
+	if m != nil {
+		return m.StoragePolicy
+	}
+	return nil
+}
+
+func (m *CreateRepoRequest) GetCommitTTL() *types.Duration {
+	if m != nil {
+		return m.CommitTTL
+	}
+	return nil
+}
+
 type InspectRepoRequest struct {
 	Repo                 *Repo    `protobuf:"bytes,1,opt,name=repo,proto3" json:"repo,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -1634,7 +1996,7 @@ func (m *InspectRepoRequest) Reset()         { *m = InspectRepoRequest{} }
 func (m *InspectRepoRequest) String() string { return proto.CompactTextString(m) }
 func (*InspectRepoRequest) ProtoMessage()    {}
 func (*InspectRepoRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{23}
+	return fileDescriptor_b48f014707f6595c, []int{26}
 }
 func (m *InspectRepoRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1680,7 +2042,7 @@ func (m *ListRepoRequest) Reset()         { *m = ListRepoRequest{} }
 func (m *ListRepoRequest) String() string { return proto.CompactTextString(m) }
 func (*ListRepoRequest) ProtoMessage()    {}
 func (*ListRepoRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{24}
+	return fileDescriptor_b48f014707f6595c, []int{27}
 }
 func (m *ListRepoRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1720,7 +2082,7 @@ func (m *ListRepoResponse) Reset()         { *m = ListRepoResponse{} }
 func (m *ListRepoResponse) String() string { return proto.CompactTextString(m) }
 func (*ListRepoResponse) ProtoMessage()    {}
 func (*ListRepoResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{25}
+	return fileDescriptor_b48f014707f6595c, []int{28}
 }
 func (m *ListRepoResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1769,7 +2131,7 @@ func (m *DeleteRepoRequest) Reset()         { *m = DeleteRepoRequest{} }
 func (m *DeleteRepoRequest) String() string { return proto.CompactTextString(m) }
 func (*DeleteRepoRequest) ProtoMessage()    {}
 func (*DeleteRepoRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{26}
+	return fileDescriptor_b48f014707f6595c, []int{29}
 }
 func (m *DeleteRepoRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1836,7 +2198,7 @@ func (m *StartCommitRequest) Reset()         { *m = StartCommitRequest{} }
 func (m *StartCommitRequest) String() string { return proto.CompactTextString(m) }
 func (*StartCommitRequest) ProtoMessage()    {}
 func (*StartCommitRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{27}
+	return fileDescriptor_b48f014707f6595c, []int{30}
 }
 func (m *StartCommitRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1912,7 +2274,7 @@ func (m *BuildCommitRequest) Reset()         { *m = BuildCommitRequest{} }
 func (m *BuildCommitRequest) String() string { return proto.CompactTextString(m) }
 func (*BuildCommitRequest) ProtoMessage()    {}
 func (*BuildCommitRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{28}
+	return fileDescriptor_b48f014707f6595c, []int{31}
 }
 func (m *BuildCommitRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2018,7 +2380,7 @@ func (m *FinishCommitRequest) Reset()         { *m = FinishCommitRequest{} }
 func (m *FinishCommitRequest) String() string { return proto.CompactTextString(m) }
 func (*FinishCommitRequest) ProtoMessage()    {}
 func (*FinishCommitRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{29}
+	return fileDescriptor_b48f014707f6595c, []int{32}
 }
 func (m *FinishCommitRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2109,7 +2471,7 @@ func (m *InspectCommitRequest) Reset()         { *m = InspectCommitRequest{} }
 func (m *InspectCommitRequest) String() string { return proto.CompactTextString(m) }
 func (*InspectCommitRequest) ProtoMessage()    {}
 func (*InspectCommitRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{30}
+	return fileDescriptor_b48f014707f6595c, []int{33}
 }
 func (m *InspectCommitRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2153,11 +2515,27 @@ func (m *InspectCommitRequest) GetBlockState() CommitState {
 }
 
 type ListCommitRequest struct {
-	Repo                 *Repo    `protobuf:"bytes,1,opt,name=repo,proto3" json:"repo,omitempty"`
-	From                 *Commit  `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
-	To                   *Commit  `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
-	Number               uint64   `protobuf:"varint,4,opt,name=number,proto3" json:"number,omitempty"`
-	Reverse              bool     `protobuf:"varint,5,opt,name=reverse,proto3" json:"reverse,omitempty"`
+	Repo    *Repo   `protobuf:"bytes,1,opt,name=repo,proto3" json:"repo,omitempty"`
+	From    *Commit `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To      *Commit `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	Number  uint64  `protobuf:"varint,4,opt,name=number,proto3" json:"number,omitempty"`
+	Reverse bool    `protobuf:"varint,5,opt,name=reverse,proto3" json:"reverse,omitempty"`
+	// The fields below filter the commits that are returned, on the server, and
+	// only commits that match count towards 'number'. Time ranges are
+	// inclusive, and commits that aren't finished never match a finished_*
+	// bound.
+	StartedAfter   *types.Timestamp `protobuf:"bytes,6,opt,name=started_after,json=startedAfter,proto3" json:"started_after,omitempty"`
+	StartedBefore  *types.Timestamp `protobuf:"bytes,7,opt,name=started_before,json=startedBefore,proto3" json:"started_before,omitempty"`
+	FinishedAfter  *types.Timestamp `protobuf:"bytes,8,opt,name=finished_after,json=finishedAfter,proto3" json:"finished_after,omitempty"`
+	FinishedBefore *types.Timestamp `protobuf:"bytes,9,opt,name=finished_before,json=finishedBefore,proto3" json:"finished_before,omitempty"`
+	// branch, if set, only returns commits that were made on this branch.
+	Branch string `protobuf:"bytes,10,opt,name=branch,proto3" json:"branch,omitempty"`
+	// provenance, if set, only returns commits with provenance in this repo.
+	Provenance *Repo `protobuf:"bytes,11,opt,name=provenance,proto3" json:"provenance,omitempty"`
+	// start_after, if set, skips commits up to and including the commit with
+	// this ID, so that a listing can be paged through by passing the ID of
+	// the last commit of the previous page.
+	StartAfter           string   `protobuf:"bytes,12,opt,name=start_after,json=startAfter,proto3" json:"start_after,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -2167,7 +2545,7 @@ func (m *ListCommitRequest) Reset()         { *m = ListCommitRequest{} }
 func (m *ListCommitRequest) String() string { return proto.CompactTextString(m) }
 func (*ListCommitRequest) ProtoMessage()    {}
 func (*ListCommitRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{31}
+	return fileDescriptor_b48f014707f6595c, []int{34}
 }
 func (m *ListCommitRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2231,6 +2609,55 @@ func (m *ListCommitRequest) GetReverse() bool {
 	return false
 }
 
+func (m *ListCommitRequest) GetStartedAfter() *types.Timestamp {
+	if m != nil {
+		return m.StartedAfter
+	}
+	return nil
+}
+
+func (m *ListCommitRequest) GetStartedBefore() *types.Timestamp {
+	if m != nil {
+		return m.StartedBefore
+	}
+	return nil
+}
+
+func (m *ListCommitRequest) GetFinishedAfter() *types.Timestamp {
+	if m != nil {
+		return m.FinishedAfter
+	}
+	return nil
+}
+
+func (m *ListCommitRequest) GetFinishedBefore() *types.Timestamp {
+	if m != nil {
+		return m.FinishedBefore
+	}
+	return nil
+}
+
+func (m *ListCommitRequest) GetBranch() string {
+	if m != nil {
+		return m.Branch
+	}
+	return ""
+}
+
+func (m *ListCommitRequest) GetProvenance() *Repo {
+	if m != nil {
+		return m.Provenance
+	}
+	return nil
+}
+
+func (m *ListCommitRequest) GetStartAfter() string {
+	if m != nil {
+		return m.StartAfter
+	}
+	return ""
+}
+
 type CommitInfos struct {
 	CommitInfo           []*CommitInfo `protobuf:"bytes,1,rep,name=commit_info,json=commitInfo,proto3" json:"commit_info,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
@@ -2242,7 +2669,7 @@ func (m *CommitInfos) Reset()         { *m = CommitInfos{} }
 func (m *CommitInfos) String() string { return proto.CompactTextString(m) }
 func (*CommitInfos) ProtoMessage()    {}
 func (*CommitInfos) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{32}
+	return fileDescriptor_b48f014707f6595c, []int{35}
 }
 func (m *CommitInfos) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2283,19 +2710,23 @@ type CreateBranchRequest struct {
 	// s_branch matches the field number and type of SetBranchRequest.Branch in
 	// Pachyderm 1.6--so that operations (generated by pachyderm 1.6's
 	// Admin.Export) can be deserialized by pachyderm 1.7 correctly
-	SBranch              string    `protobuf:"bytes,2,opt,name=s_branch,json=sBranch,proto3" json:"s_branch,omitempty"`
-	Branch               *Branch   `protobuf:"bytes,3,opt,name=branch,proto3" json:"branch,omitempty"`
-	Provenance           []*Branch `protobuf:"bytes,4,rep,name=provenance,proto3" json:"provenance,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
-	XXX_unrecognized     []byte    `json:"-"`
-	XXX_sizecache        int32     `json:"-"`
+	SBranch    string    `protobuf:"bytes,2,opt,name=s_branch,json=sBranch,proto3" json:"s_branch,omitempty"`
+	Branch     *Branch   `protobuf:"bytes,3,opt,name=branch,proto3" json:"branch,omitempty"`
+	Provenance []*Branch `protobuf:"bytes,4,rep,name=provenance,proto3" json:"provenance,omitempty"`
+	// protection sets the branch's protection, which requires OWNER access to
+	// the repo. If it's unset, the branch keeps its protection, and if it's
+	// empty, the protection is removed.
+	Protection           *BranchProtection `protobuf:"bytes,5,opt,name=protection,proto3" json:"protection,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *CreateBranchRequest) Reset()         { *m = CreateBranchRequest{} }
 func (m *CreateBranchRequest) String() string { return proto.CompactTextString(m) }
 func (*CreateBranchRequest) ProtoMessage()    {}
 func (*CreateBranchRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{33}
+	return fileDescriptor_b48f014707f6595c, []int{36}
 }
 func (m *CreateBranchRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2352,6 +2783,13 @@ func (m *CreateBranchRequest) GetProvenance() []*Branch {
 	return nil
 }
 
+func (m *CreateBranchRequest) GetProtection() *BranchProtection {
+	if m != nil {
+		return m.Protection
+	}
+	return nil
+}
+
 type InspectBranchRequest struct {
 	Branch               *Branch  `protobuf:"bytes,1,opt,name=branch,proto3" json:"branch,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -2363,7 +2801,7 @@ func (m *InspectBranchRequest) Reset()         { *m = InspectBranchRequest{} }
 func (m *InspectBranchRequest) String() string { return proto.CompactTextString(m) }
 func (*InspectBranchRequest) ProtoMessage()    {}
 func (*InspectBranchRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{34}
+	return fileDescriptor_b48f014707f6595c, []int{37}
 }
 func (m *InspectBranchRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2411,7 +2849,7 @@ func (m *ListBranchRequest) Reset()         { *m = ListBranchRequest{} }
 func (m *ListBranchRequest) String() string { return proto.CompactTextString(m) }
 func (*ListBranchRequest) ProtoMessage()    {}
 func (*ListBranchRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{35}
+	return fileDescriptor_b48f014707f6595c, []int{38}
 }
 func (m *ListBranchRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2466,7 +2904,7 @@ func (m *DeleteBranchRequest) Reset()         { *m = DeleteBranchRequest{} }
 func (m *DeleteBranchRequest) String() string { return proto.CompactTextString(m) }
 func (*DeleteBranchRequest) ProtoMessage()    {}
 func (*DeleteBranchRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{36}
+	return fileDescriptor_b48f014707f6595c, []int{39}
 }
 func (m *DeleteBranchRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2520,7 +2958,7 @@ func (m *DeleteCommitRequest) Reset()         { *m = DeleteCommitRequest{} }
 func (m *DeleteCommitRequest) String() string { return proto.CompactTextString(m) }
 func (*DeleteCommitRequest) ProtoMessage()    {}
 func (*DeleteCommitRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{37}
+	return fileDescriptor_b48f014707f6595c, []int{40}
 }
 func (m *DeleteCommitRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2556,6 +2994,62 @@ func (m *DeleteCommitRequest) GetCommit() *Commit {
 	return nil
 }
 
+type ExpireCommitsRequest struct {
+	Repo *Repo `protobuf:"bytes,1,opt,name=repo,proto3" json:"repo,omitempty"`
+	// dry_run returns the commits that would be expired without deleting them
+	DryRun               bool     `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExpireCommitsRequest) Reset()         { *m = ExpireCommitsRequest{} }
+func (m *ExpireCommitsRequest) String() string { return proto.CompactTextString(m) }
+func (*ExpireCommitsRequest) ProtoMessage()    {}
+func (*ExpireCommitsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b48f014707f6595c, []int{41}
+}
+func (m *ExpireCommitsRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ExpireCommitsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ExpireCommitsRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ExpireCommitsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExpireCommitsRequest.Merge(m, src)
+}
+func (m *ExpireCommitsRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *ExpireCommitsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExpireCommitsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExpireCommitsRequest proto.InternalMessageInfo
+
+func (m *ExpireCommitsRequest) GetRepo() *Repo {
+	if m != nil {
+		return m.Repo
+	}
+	return nil
+}
+
+func (m *ExpireCommitsRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
 type FlushCommitRequest struct {
 	Commits              []*Commit `protobuf:"bytes,1,rep,name=commits,proto3" json:"commits,omitempty"`
 	ToRepos              []*Repo   `protobuf:"bytes,2,rep,name=to_repos,json=toRepos,proto3" json:"to_repos,omitempty"`
@@ -2568,7 +3062,7 @@ func (m *FlushCommitRequest) Reset()         { *m = FlushCommitRequest{} }
 func (m *FlushCommitRequest) String() string { return proto.CompactTextString(m) }
 func (*FlushCommitRequest) ProtoMessage()    {}
 func (*FlushCommitRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{38}
+	return fileDescriptor_b48f014707f6595c, []int{42}
 }
 func (m *FlushCommitRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2618,17 +3112,22 @@ type SubscribeCommitRequest struct {
 	// only commits created since this commit are returned
 	From *Commit `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
 	// Don't return commits until they're in (at least) the desired state.
-	State                CommitState `protobuf:"varint,4,opt,name=state,proto3,enum=pfs.CommitState" json:"state,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
-	XXX_unrecognized     []byte      `json:"-"`
-	XXX_sizecache        int32       `json:"-"`
+	State CommitState `protobuf:"varint,4,opt,name=state,proto3,enum=pfs.CommitState" json:"state,omitempty"`
+	// If set, only commits that are valid are returned: commits that are
+	// finished and not empty (as the output commits of failed jobs are), and
+	// whose provenant commits are all finished and not empty. Implies state
+	// FINISHED.
+	ValidOnly            bool     `protobuf:"varint,6,opt,name=valid_only,json=validOnly,proto3" json:"valid_only,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *SubscribeCommitRequest) Reset()         { *m = SubscribeCommitRequest{} }
 func (m *SubscribeCommitRequest) String() string { return proto.CompactTextString(m) }
 func (*SubscribeCommitRequest) ProtoMessage()    {}
 func (*SubscribeCommitRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{39}
+	return fileDescriptor_b48f014707f6595c, []int{43}
 }
 func (m *SubscribeCommitRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2692,10 +3191,202 @@ func (m *SubscribeCommitRequest) GetState() CommitState {
 	return CommitState_STARTED
 }
 
+func (m *SubscribeCommitRequest) GetValidOnly() bool {
+	if m != nil {
+		return m.ValidOnly
+	}
+	return false
+}
+
+type SubscribeFileChangesRequest struct {
+	Repo   *Repo  `protobuf:"bytes,1,opt,name=repo,proto3" json:"repo,omitempty"`
+	Branch string `protobuf:"bytes,2,opt,name=branch,proto3" json:"branch,omitempty"`
+	// only changes in commits created since this commit are returned
+	From                 *Commit  `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SubscribeFileChangesRequest) Reset()         { *m = SubscribeFileChangesRequest{} }
+func (m *SubscribeFileChangesRequest) String() string { return proto.CompactTextString(m) }
+func (*SubscribeFileChangesRequest) ProtoMessage()    {}
+func (*SubscribeFileChangesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b48f014707f6595c, []int{44}
+}
+func (m *SubscribeFileChangesRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SubscribeFileChangesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SubscribeFileChangesRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SubscribeFileChangesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubscribeFileChangesRequest.Merge(m, src)
+}
+func (m *SubscribeFileChangesRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *SubscribeFileChangesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubscribeFileChangesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubscribeFileChangesRequest proto.InternalMessageInfo
+
+func (m *SubscribeFileChangesRequest) GetRepo() *Repo {
+	if m != nil {
+		return m.Repo
+	}
+	return nil
+}
+
+func (m *SubscribeFileChangesRequest) GetBranch() string {
+	if m != nil {
+		return m.Branch
+	}
+	return ""
+}
+
+func (m *SubscribeFileChangesRequest) GetFrom() *Commit {
+	if m != nil {
+		return m.From
+	}
+	return nil
+}
+
+type FileChange struct {
+	Path                 string         `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Type                 FileChangeType `protobuf:"varint,2,opt,name=type,proto3,enum=pfs.FileChangeType" json:"type,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *FileChange) Reset()         { *m = FileChange{} }
+func (m *FileChange) String() string { return proto.CompactTextString(m) }
+func (*FileChange) ProtoMessage()    {}
+func (*FileChange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b48f014707f6595c, []int{45}
+}
+func (m *FileChange) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *FileChange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_FileChange.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *FileChange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FileChange.Merge(m, src)
+}
+func (m *FileChange) XXX_Size() int {
+	return m.Size()
+}
+func (m *FileChange) XXX_DiscardUnknown() {
+	xxx_messageInfo_FileChange.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FileChange proto.InternalMessageInfo
+
+func (m *FileChange) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *FileChange) GetType() FileChangeType {
+	if m != nil {
+		return m.Type
+	}
+	return FileChangeType_FILE_CREATED
+}
+
+// FileChangeEvent lists the files that a finished commit changed, relative
+// to its parent commit.
+type FileChangeEvent struct {
+	Commit               *Commit       `protobuf:"bytes,1,opt,name=commit,proto3" json:"commit,omitempty"`
+	Changes              []*FileChange `protobuf:"bytes,2,rep,name=changes,proto3" json:"changes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *FileChangeEvent) Reset()         { *m = FileChangeEvent{} }
+func (m *FileChangeEvent) String() string { return proto.CompactTextString(m) }
+func (*FileChangeEvent) ProtoMessage()    {}
+func (*FileChangeEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b48f014707f6595c, []int{46}
+}
+func (m *FileChangeEvent) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *FileChangeEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_FileChangeEvent.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *FileChangeEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FileChangeEvent.Merge(m, src)
+}
+func (m *FileChangeEvent) XXX_Size() int {
+	return m.Size()
+}
+func (m *FileChangeEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_FileChangeEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FileChangeEvent proto.InternalMessageInfo
+
+func (m *FileChangeEvent) GetCommit() *Commit {
+	if m != nil {
+		return m.Commit
+	}
+	return nil
+}
+
+func (m *FileChangeEvent) GetChanges() []*FileChange {
+	if m != nil {
+		return m.Changes
+	}
+	return nil
+}
+
 type GetFileRequest struct {
-	File                 *File    `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
-	OffsetBytes          int64    `protobuf:"varint,2,opt,name=offset_bytes,json=offsetBytes,proto3" json:"offset_bytes,omitempty"`
-	SizeBytes            int64    `protobuf:"varint,3,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	File        *File `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	OffsetBytes int64 `protobuf:"varint,2,opt,name=offset_bytes,json=offsetBytes,proto3" json:"offset_bytes,omitempty"`
+	SizeBytes   int64 `protobuf:"varint,3,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	// delimiter, if set, splits the file's content into records, and
+	// offset_records and size_records (rather than offset_bytes and
+	// size_bytes) select the records that are returned. Only whole records
+	// are returned. SQL isn't supported.
+	Delimiter Delimiter `protobuf:"varint,4,opt,name=delimiter,proto3,enum=pfs.Delimiter" json:"delimiter,omitempty"`
+	// offset_records is the number of records that are skipped
+	OffsetRecords int64 `protobuf:"varint,5,opt,name=offset_records,json=offsetRecords,proto3" json:"offset_records,omitempty"`
+	// size_records, if nonzero, is the most records that are returned
+	SizeRecords          int64    `protobuf:"varint,6,opt,name=size_records,json=sizeRecords,proto3" json:"size_records,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -2705,7 +3396,7 @@ func (m *GetFileRequest) Reset()         { *m = GetFileRequest{} }
 func (m *GetFileRequest) String() string { return proto.CompactTextString(m) }
 func (*GetFileRequest) ProtoMessage()    {}
 func (*GetFileRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{40}
+	return fileDescriptor_b48f014707f6595c, []int{47}
 }
 func (m *GetFileRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2755,6 +3446,27 @@ func (m *GetFileRequest) GetSizeBytes() int64 {
 	return 0
 }
 
+func (m *GetFileRequest) GetDelimiter() Delimiter {
+	if m != nil {
+		return m.Delimiter
+	}
+	return Delimiter_NONE
+}
+
+func (m *GetFileRequest) GetOffsetRecords() int64 {
+	if m != nil {
+		return m.OffsetRecords
+	}
+	return 0
+}
+
+func (m *GetFileRequest) GetSizeRecords() int64 {
+	if m != nil {
+		return m.SizeRecords
+	}
+	return 0
+}
+
 // An OverwriteIndex specifies the index of objects from which new writes
 // are applied to.  Existing objects starting from the index are deleted.
 // We want a separate message for ObjectIndex because we want to be able to
@@ -2770,7 +3482,7 @@ func (m *OverwriteIndex) Reset()         { *m = OverwriteIndex{} }
 func (m *OverwriteIndex) String() string { return proto.CompactTextString(m) }
 func (*OverwriteIndex) ProtoMessage()    {}
 func (*OverwriteIndex) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{41}
+	return fileDescriptor_b48f014707f6595c, []int{48}
 }
 func (m *OverwriteIndex) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2841,17 +3553,21 @@ type PutFileRequest struct {
 	HeaderRecords int64 `protobuf:"varint,11,opt,name=header_records,json=headerRecords,proto3" json:"header_records,omitempty"`
 	// overwrite_index is the object index where the write starts from.  All
 	// existing objects starting from the index are deleted.
-	OverwriteIndex       *OverwriteIndex `protobuf:"bytes,10,opt,name=overwrite_index,json=overwriteIndex,proto3" json:"overwrite_index,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
-	XXX_unrecognized     []byte          `json:"-"`
-	XXX_sizecache        int32           `json:"-"`
+	OverwriteIndex *OverwriteIndex `protobuf:"bytes,10,opt,name=overwrite_index,json=overwriteIndex,proto3" json:"overwrite_index,omitempty"`
+	// checksums are computed as the content is written, so that InspectFile
+	// doesn't have to read the file to compute them. They can't be computed
+	// for content that's split with 'delimiter'.
+	Checksums            []ChecksumAlgorithm `protobuf:"varint,12,rep,packed,name=checksums,proto3,enum=pfs.ChecksumAlgorithm" json:"checksums,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
 }
 
 func (m *PutFileRequest) Reset()         { *m = PutFileRequest{} }
 func (m *PutFileRequest) String() string { return proto.CompactTextString(m) }
 func (*PutFileRequest) ProtoMessage()    {}
 func (*PutFileRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{42}
+	return fileDescriptor_b48f014707f6595c, []int{49}
 }
 func (m *PutFileRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2943,6 +3659,13 @@ func (m *PutFileRequest) GetOverwriteIndex() *OverwriteIndex {
 	return nil
 }
 
+func (m *PutFileRequest) GetChecksums() []ChecksumAlgorithm {
+	if m != nil {
+		return m.Checksums
+	}
+	return nil
+}
+
 // PutFileRecord is used to record PutFile requests in etcd temporarily.
 type PutFileRecord struct {
 	SizeBytes            int64           `protobuf:"varint,1,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
@@ -2957,7 +3680,7 @@ func (m *PutFileRecord) Reset()         { *m = PutFileRecord{} }
 func (m *PutFileRecord) String() string { return proto.CompactTextString(m) }
 func (*PutFileRecord) ProtoMessage()    {}
 func (*PutFileRecord) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{43}
+	return fileDescriptor_b48f014707f6595c, []int{50}
 }
 func (m *PutFileRecord) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3022,7 +3745,7 @@ func (m *PutFileRecords) Reset()         { *m = PutFileRecords{} }
 func (m *PutFileRecords) String() string { return proto.CompactTextString(m) }
 func (*PutFileRecords) ProtoMessage()    {}
 func (*PutFileRecords) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{44}
+	return fileDescriptor_b48f014707f6595c, []int{51}
 }
 func (m *PutFileRecords) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3099,7 +3822,7 @@ func (m *CopyFileRequest) Reset()         { *m = CopyFileRequest{} }
 func (m *CopyFileRequest) String() string { return proto.CompactTextString(m) }
 func (*CopyFileRequest) ProtoMessage()    {}
 func (*CopyFileRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{45}
+	return fileDescriptor_b48f014707f6595c, []int{52}
 }
 func (m *CopyFileRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3150,17 +3873,21 @@ func (m *CopyFileRequest) GetOverwrite() bool {
 }
 
 type InspectFileRequest struct {
-	File                 *File    `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	File *File `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	// checksums are computed from the file's content the first time they're
+	// asked for (unless they were computed when the content was written, see
+	// PutFileRequest.checksums), and are cached after that
+	Checksums            []ChecksumAlgorithm `protobuf:"varint,2,rep,packed,name=checksums,proto3,enum=pfs.ChecksumAlgorithm" json:"checksums,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
 }
 
 func (m *InspectFileRequest) Reset()         { *m = InspectFileRequest{} }
 func (m *InspectFileRequest) String() string { return proto.CompactTextString(m) }
 func (*InspectFileRequest) ProtoMessage()    {}
 func (*InspectFileRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{46}
+	return fileDescriptor_b48f014707f6595c, []int{53}
 }
 func (m *InspectFileRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3196,6 +3923,13 @@ func (m *InspectFileRequest) GetFile() *File {
 	return nil
 }
 
+func (m *InspectFileRequest) GetChecksums() []ChecksumAlgorithm {
+	if m != nil {
+		return m.Checksums
+	}
+	return nil
+}
+
 type ListFileRequest struct {
 	// File is the parent directory of the files we want to list. This sets the
 	// repo, the commit/branch, and path prefix of files we're interested in
@@ -3217,7 +3951,34 @@ type ListFileRequest struct {
 	//    were modified in.
 	// 3: etc.
 	//-1: Return all historical versions.
-	History              int64    `protobuf:"varint,3,opt,name=history,proto3" json:"history,omitempty"`
+	History int64 `protobuf:"varint,3,opt,name=history,proto3" json:"history,omitempty"`
+	// SizeOnly indicates that the returned FileInfos should only contain each
+	// file's path, type, size, hash and committed time, i.e. no commit, objects,
+	// block refs or children (even if 'full' is set), which makes responses
+	// much smaller when listing large directories.
+	SizeOnly bool `protobuf:"varint,4,opt,name=size_only,json=sizeOnly,proto3" json:"size_only,omitempty"`
+	// Summary indicates that instead of returning the files, ListFile should
+	// only return the number and total size of the regular files it would have
+	// returned (in FileInfos.count and FileInfos.size_bytes). It isn't
+	// supported by ListFileStream.
+	Summary bool `protobuf:"varint,5,opt,name=summary,proto3" json:"summary,omitempty"`
+	// Prefix, StartAfter, Limit and Recursive page through the files under the
+	// directory at File.Path, which can't be a glob pattern or be combined with
+	// History. Files are returned in path order (with '/' ordered before any
+	// other character), and the listing seeks to the first file it returns, so
+	// each page only costs as much as the files on it.
+	//
+	// Prefix only returns the files whose paths start with it (e.g. "/dir/a"
+	// returns "/dir/a", "/dir/ab" and so on).
+	Prefix string `protobuf:"bytes,6,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	// StartAfter only returns the files whose paths sort after it, so that a
+	// listing can be resumed after the last file of the previous page.
+	StartAfter string `protobuf:"bytes,7,opt,name=start_after,json=startAfter,proto3" json:"start_after,omitempty"`
+	// Limit, if > 0, is the maximum number of files returned.
+	Limit int64 `protobuf:"varint,8,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Recursive returns all of the regular files under File.Path, rather than
+	// its children.
+	Recursive            bool     `protobuf:"varint,9,opt,name=recursive,proto3" json:"recursive,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -3227,7 +3988,7 @@ func (m *ListFileRequest) Reset()         { *m = ListFileRequest{} }
 func (m *ListFileRequest) String() string { return proto.CompactTextString(m) }
 func (*ListFileRequest) ProtoMessage()    {}
 func (*ListFileRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{47}
+	return fileDescriptor_b48f014707f6595c, []int{54}
 }
 func (m *ListFileRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3277,8 +4038,62 @@ func (m *ListFileRequest) GetHistory() int64 {
 	return 0
 }
 
+func (m *ListFileRequest) GetSizeOnly() bool {
+	if m != nil {
+		return m.SizeOnly
+	}
+	return false
+}
+
+func (m *ListFileRequest) GetSummary() bool {
+	if m != nil {
+		return m.Summary
+	}
+	return false
+}
+
+func (m *ListFileRequest) GetPrefix() string {
+	if m != nil {
+		return m.Prefix
+	}
+	return ""
+}
+
+func (m *ListFileRequest) GetStartAfter() string {
+	if m != nil {
+		return m.StartAfter
+	}
+	return ""
+}
+
+func (m *ListFileRequest) GetLimit() int64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *ListFileRequest) GetRecursive() bool {
+	if m != nil {
+		return m.Recursive
+	}
+	return false
+}
+
 type WalkFileRequest struct {
-	File                 *File    `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	File *File `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	// MaxDepth, if > 0, is the deepest level below File.Path that's returned,
+	// e.g. 1 returns File.Path and its children
+	MaxDepth int64 `protobuf:"varint,2,opt,name=max_depth,json=maxDepth,proto3" json:"max_depth,omitempty"`
+	// FileType, if set, only returns files of that type (FILE or DIR). The
+	// directories are still walked.
+	FileType FileType `protobuf:"varint,3,opt,name=file_type,json=fileType,proto3,enum=pfs.FileType" json:"file_type,omitempty"`
+	// If Aggregate is set, each directory is returned after the files below it
+	// (rather than before them), with FileCount set to the number of regular
+	// files below it, including those below MaxDepth
+	Aggregate bool `protobuf:"varint,4,opt,name=aggregate,proto3" json:"aggregate,omitempty"`
+	// SizeOnly has the same meaning as in ListFileRequest
+	SizeOnly             bool     `protobuf:"varint,5,opt,name=size_only,json=sizeOnly,proto3" json:"size_only,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -3288,7 +4103,7 @@ func (m *WalkFileRequest) Reset()         { *m = WalkFileRequest{} }
 func (m *WalkFileRequest) String() string { return proto.CompactTextString(m) }
 func (*WalkFileRequest) ProtoMessage()    {}
 func (*WalkFileRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{48}
+	return fileDescriptor_b48f014707f6595c, []int{55}
 }
 func (m *WalkFileRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3324,9 +4139,40 @@ func (m *WalkFileRequest) GetFile() *File {
 	return nil
 }
 
+func (m *WalkFileRequest) GetMaxDepth() int64 {
+	if m != nil {
+		return m.MaxDepth
+	}
+	return 0
+}
+
+func (m *WalkFileRequest) GetFileType() FileType {
+	if m != nil {
+		return m.FileType
+	}
+	return FileType_RESERVED
+}
+
+func (m *WalkFileRequest) GetAggregate() bool {
+	if m != nil {
+		return m.Aggregate
+	}
+	return false
+}
+
+func (m *WalkFileRequest) GetSizeOnly() bool {
+	if m != nil {
+		return m.SizeOnly
+	}
+	return false
+}
+
 type GlobFileRequest struct {
-	Commit               *Commit  `protobuf:"bytes,1,opt,name=commit,proto3" json:"commit,omitempty"`
-	Pattern              string   `protobuf:"bytes,2,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	Commit  *Commit `protobuf:"bytes,1,opt,name=commit,proto3" json:"commit,omitempty"`
+	Pattern string  `protobuf:"bytes,2,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	// SizeOnly and Summary have the same meaning as in ListFileRequest
+	SizeOnly             bool     `protobuf:"varint,3,opt,name=size_only,json=sizeOnly,proto3" json:"size_only,omitempty"`
+	Summary              bool     `protobuf:"varint,4,opt,name=summary,proto3" json:"summary,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -3336,7 +4182,7 @@ func (m *GlobFileRequest) Reset()         { *m = GlobFileRequest{} }
 func (m *GlobFileRequest) String() string { return proto.CompactTextString(m) }
 func (*GlobFileRequest) ProtoMessage()    {}
 func (*GlobFileRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{49}
+	return fileDescriptor_b48f014707f6595c, []int{56}
 }
 func (m *GlobFileRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3379,19 +4225,36 @@ func (m *GlobFileRequest) GetPattern() string {
 	return ""
 }
 
+func (m *GlobFileRequest) GetSizeOnly() bool {
+	if m != nil {
+		return m.SizeOnly
+	}
+	return false
+}
+
+func (m *GlobFileRequest) GetSummary() bool {
+	if m != nil {
+		return m.Summary
+	}
+	return false
+}
+
 // FileInfos is the result of both ListFile and GlobFile
 type FileInfos struct {
-	FileInfo             []*FileInfo `protobuf:"bytes,1,rep,name=file_info,json=fileInfo,proto3" json:"file_info,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
-	XXX_unrecognized     []byte      `json:"-"`
-	XXX_sizecache        int32       `json:"-"`
+	FileInfo []*FileInfo `protobuf:"bytes,1,rep,name=file_info,json=fileInfo,proto3" json:"file_info,omitempty"`
+	// count and size_bytes are only set by requests with 'summary' set
+	Count                int64    `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	SizeBytes            uint64   `protobuf:"varint,3,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *FileInfos) Reset()         { *m = FileInfos{} }
 func (m *FileInfos) String() string { return proto.CompactTextString(m) }
 func (*FileInfos) ProtoMessage()    {}
 func (*FileInfos) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{50}
+	return fileDescriptor_b48f014707f6595c, []int{57}
 }
 func (m *FileInfos) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3427,6 +4290,20 @@ func (m *FileInfos) GetFileInfo() []*FileInfo {
 	return nil
 }
 
+func (m *FileInfos) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *FileInfos) GetSizeBytes() uint64 {
+	if m != nil {
+		return m.SizeBytes
+	}
+	return 0
+}
+
 type DiffFileRequest struct {
 	NewFile *File `protobuf:"bytes,1,opt,name=new_file,json=newFile,proto3" json:"new_file,omitempty"`
 	// OldFile may be left nil in which case the same path in the parent of
@@ -3442,7 +4319,7 @@ func (m *DiffFileRequest) Reset()         { *m = DiffFileRequest{} }
 func (m *DiffFileRequest) String() string { return proto.CompactTextString(m) }
 func (*DiffFileRequest) ProtoMessage()    {}
 func (*DiffFileRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{51}
+	return fileDescriptor_b48f014707f6595c, []int{58}
 }
 func (m *DiffFileRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3504,7 +4381,7 @@ func (m *DiffFileResponse) Reset()         { *m = DiffFileResponse{} }
 func (m *DiffFileResponse) String() string { return proto.CompactTextString(m) }
 func (*DiffFileResponse) ProtoMessage()    {}
 func (*DiffFileResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{52}
+	return fileDescriptor_b48f014707f6595c, []int{59}
 }
 func (m *DiffFileResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3547,25 +4424,32 @@ func (m *DiffFileResponse) GetOldFiles() []*FileInfo {
 	return nil
 }
 
-type DeleteFileRequest struct {
-	File                 *File    `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+// IndexedFile is a file's entry in the index of a commit: the attributes
+// that an index extractor found in its content.
+type IndexedFile struct {
+	Path       string            `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Extractor  string            `protobuf:"bytes,2,opt,name=extractor,proto3" json:"extractor,omitempty"`
+	Attributes map[string]string `protobuf:"bytes,3,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// hash is the hash of the file's content, so that files that are unchanged
+	// from the parent commit aren't read again
+	Hash                 []byte   `protobuf:"bytes,4,opt,name=hash,proto3" json:"hash,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *DeleteFileRequest) Reset()         { *m = DeleteFileRequest{} }
-func (m *DeleteFileRequest) String() string { return proto.CompactTextString(m) }
-func (*DeleteFileRequest) ProtoMessage()    {}
-func (*DeleteFileRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{53}
+func (m *IndexedFile) Reset()         { *m = IndexedFile{} }
+func (m *IndexedFile) String() string { return proto.CompactTextString(m) }
+func (*IndexedFile) ProtoMessage()    {}
+func (*IndexedFile) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b48f014707f6595c, []int{60}
 }
-func (m *DeleteFileRequest) XXX_Unmarshal(b []byte) error {
+func (m *IndexedFile) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *DeleteFileRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *IndexedFile) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_DeleteFileRequest.Marshal(b, m, deterministic)
+		return xxx_messageInfo_IndexedFile.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -3575,44 +4459,66 @@ func (m *DeleteFileRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, e
 		return b[:n], nil
 	}
 }
-func (m *DeleteFileRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_DeleteFileRequest.Merge(m, src)
+func (m *IndexedFile) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IndexedFile.Merge(m, src)
 }
-func (m *DeleteFileRequest) XXX_Size() int {
+func (m *IndexedFile) XXX_Size() int {
 	return m.Size()
 }
-func (m *DeleteFileRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_DeleteFileRequest.DiscardUnknown(m)
+func (m *IndexedFile) XXX_DiscardUnknown() {
+	xxx_messageInfo_IndexedFile.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_DeleteFileRequest proto.InternalMessageInfo
+var xxx_messageInfo_IndexedFile proto.InternalMessageInfo
 
-func (m *DeleteFileRequest) GetFile() *File {
+func (m *IndexedFile) GetPath() string {
 	if m != nil {
-		return m.File
+		return m.Path
+	}
+	return ""
+}
+
+func (m *IndexedFile) GetExtractor() string {
+	if m != nil {
+		return m.Extractor
+	}
+	return ""
+}
+
+func (m *IndexedFile) GetAttributes() map[string]string {
+	if m != nil {
+		return m.Attributes
 	}
 	return nil
 }
 
-type FsckRequest struct {
-	Fix                  bool     `protobuf:"varint,1,opt,name=fix,proto3" json:"fix,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *IndexedFile) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
 }
 
-func (m *FsckRequest) Reset()         { *m = FsckRequest{} }
-func (m *FsckRequest) String() string { return proto.CompactTextString(m) }
-func (*FsckRequest) ProtoMessage()    {}
-func (*FsckRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{54}
+// FileIndex is the index of a commit's files, sorted by path
+type FileIndex struct {
+	Files                []*IndexedFile `protobuf:"bytes,1,rep,name=files,proto3" json:"files,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
 }
-func (m *FsckRequest) XXX_Unmarshal(b []byte) error {
+
+func (m *FileIndex) Reset()         { *m = FileIndex{} }
+func (m *FileIndex) String() string { return proto.CompactTextString(m) }
+func (*FileIndex) ProtoMessage()    {}
+func (*FileIndex) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b48f014707f6595c, []int{61}
+}
+func (m *FileIndex) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *FsckRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *FileIndex) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_FsckRequest.Marshal(b, m, deterministic)
+		return xxx_messageInfo_FileIndex.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -3622,45 +4528,50 @@ func (m *FsckRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error)
 		return b[:n], nil
 	}
 }
-func (m *FsckRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_FsckRequest.Merge(m, src)
+func (m *FileIndex) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FileIndex.Merge(m, src)
 }
-func (m *FsckRequest) XXX_Size() int {
+func (m *FileIndex) XXX_Size() int {
 	return m.Size()
 }
-func (m *FsckRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_FsckRequest.DiscardUnknown(m)
+func (m *FileIndex) XXX_DiscardUnknown() {
+	xxx_messageInfo_FileIndex.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_FsckRequest proto.InternalMessageInfo
+var xxx_messageInfo_FileIndex proto.InternalMessageInfo
 
-func (m *FsckRequest) GetFix() bool {
+func (m *FileIndex) GetFiles() []*IndexedFile {
 	if m != nil {
-		return m.Fix
+		return m.Files
 	}
-	return false
-}
-
-type FsckResponse struct {
-	Fix                  string   `protobuf:"bytes,1,opt,name=fix,proto3" json:"fix,omitempty"`
-	Error                string   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	return nil
 }
 
-func (m *FsckResponse) Reset()         { *m = FsckResponse{} }
-func (m *FsckResponse) String() string { return proto.CompactTextString(m) }
-func (*FsckResponse) ProtoMessage()    {}
-func (*FsckResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{55}
+type SearchFilesRequest struct {
+	Commit *Commit `protobuf:"bytes,1,opt,name=commit,proto3" json:"commit,omitempty"`
+	// pattern is a glob pattern that the files' paths must match. If it's
+	// unset, all of the commit's indexed files are searched.
+	Pattern string `protobuf:"bytes,2,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	// attributes are the attributes that the files must have. An empty value
+	// matches any value of the attribute.
+	Attributes           map[string]string `protobuf:"bytes,3,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *SearchFilesRequest) Reset()         { *m = SearchFilesRequest{} }
+func (m *SearchFilesRequest) String() string { return proto.CompactTextString(m) }
+func (*SearchFilesRequest) ProtoMessage()    {}
+func (*SearchFilesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b48f014707f6595c, []int{62}
 }
-func (m *FsckResponse) XXX_Unmarshal(b []byte) error {
+func (m *SearchFilesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *FsckResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *SearchFilesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_FsckResponse.Marshal(b, m, deterministic)
+		return xxx_messageInfo_SearchFilesRequest.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -3670,53 +4581,59 @@ func (m *FsckResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error)
 		return b[:n], nil
 	}
 }
-func (m *FsckResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_FsckResponse.Merge(m, src)
+func (m *SearchFilesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchFilesRequest.Merge(m, src)
 }
-func (m *FsckResponse) XXX_Size() int {
+func (m *SearchFilesRequest) XXX_Size() int {
 	return m.Size()
 }
-func (m *FsckResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_FsckResponse.DiscardUnknown(m)
+func (m *SearchFilesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchFilesRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_FsckResponse proto.InternalMessageInfo
+var xxx_messageInfo_SearchFilesRequest proto.InternalMessageInfo
 
-func (m *FsckResponse) GetFix() string {
+func (m *SearchFilesRequest) GetCommit() *Commit {
 	if m != nil {
-		return m.Fix
+		return m.Commit
 	}
-	return ""
+	return nil
 }
 
-func (m *FsckResponse) GetError() string {
+func (m *SearchFilesRequest) GetPattern() string {
 	if m != nil {
-		return m.Error
+		return m.Pattern
 	}
 	return ""
 }
 
-type PutObjectRequest struct {
-	Value                []byte   `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
-	Tags                 []*Tag   `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"`
-	Block                *Block   `protobuf:"bytes,3,opt,name=block,proto3" json:"block,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *SearchFilesRequest) GetAttributes() map[string]string {
+	if m != nil {
+		return m.Attributes
+	}
+	return nil
 }
 
-func (m *PutObjectRequest) Reset()         { *m = PutObjectRequest{} }
-func (m *PutObjectRequest) String() string { return proto.CompactTextString(m) }
-func (*PutObjectRequest) ProtoMessage()    {}
-func (*PutObjectRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{56}
+type SearchFilesResponse struct {
+	Commit               *Commit        `protobuf:"bytes,1,opt,name=commit,proto3" json:"commit,omitempty"`
+	Files                []*IndexedFile `protobuf:"bytes,2,rep,name=files,proto3" json:"files,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
 }
-func (m *PutObjectRequest) XXX_Unmarshal(b []byte) error {
+
+func (m *SearchFilesResponse) Reset()         { *m = SearchFilesResponse{} }
+func (m *SearchFilesResponse) String() string { return proto.CompactTextString(m) }
+func (*SearchFilesResponse) ProtoMessage()    {}
+func (*SearchFilesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b48f014707f6595c, []int{63}
+}
+func (m *SearchFilesResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *PutObjectRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *SearchFilesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_PutObjectRequest.Marshal(b, m, deterministic)
+		return xxx_messageInfo_SearchFilesResponse.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -3726,52 +4643,267 @@ func (m *PutObjectRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, er
 		return b[:n], nil
 	}
 }
-func (m *PutObjectRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_PutObjectRequest.Merge(m, src)
+func (m *SearchFilesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchFilesResponse.Merge(m, src)
 }
-func (m *PutObjectRequest) XXX_Size() int {
+func (m *SearchFilesResponse) XXX_Size() int {
 	return m.Size()
 }
-func (m *PutObjectRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_PutObjectRequest.DiscardUnknown(m)
+func (m *SearchFilesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchFilesResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_PutObjectRequest proto.InternalMessageInfo
-
-func (m *PutObjectRequest) GetValue() []byte {
-	if m != nil {
-		return m.Value
-	}
-	return nil
-}
+var xxx_messageInfo_SearchFilesResponse proto.InternalMessageInfo
 
-func (m *PutObjectRequest) GetTags() []*Tag {
+func (m *SearchFilesResponse) GetCommit() *Commit {
 	if m != nil {
-		return m.Tags
+		return m.Commit
 	}
 	return nil
 }
 
-func (m *PutObjectRequest) GetBlock() *Block {
+func (m *SearchFilesResponse) GetFiles() []*IndexedFile {
 	if m != nil {
-		return m.Block
+		return m.Files
 	}
 	return nil
 }
 
-type CreateObjectRequest struct {
-	Object               *Object   `protobuf:"bytes,1,opt,name=object,proto3" json:"object,omitempty"`
-	BlockRef             *BlockRef `protobuf:"bytes,2,opt,name=block_ref,json=blockRef,proto3" json:"block_ref,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
-	XXX_unrecognized     []byte    `json:"-"`
-	XXX_sizecache        int32     `json:"-"`
+type DeleteFileRequest struct {
+	File                 *File    `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *CreateObjectRequest) Reset()         { *m = CreateObjectRequest{} }
+func (m *DeleteFileRequest) Reset()         { *m = DeleteFileRequest{} }
+func (m *DeleteFileRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteFileRequest) ProtoMessage()    {}
+func (*DeleteFileRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b48f014707f6595c, []int{64}
+}
+func (m *DeleteFileRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DeleteFileRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DeleteFileRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *DeleteFileRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteFileRequest.Merge(m, src)
+}
+func (m *DeleteFileRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *DeleteFileRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteFileRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteFileRequest proto.InternalMessageInfo
+
+func (m *DeleteFileRequest) GetFile() *File {
+	if m != nil {
+		return m.File
+	}
+	return nil
+}
+
+type FsckRequest struct {
+	Fix                  bool     `protobuf:"varint,1,opt,name=fix,proto3" json:"fix,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FsckRequest) Reset()         { *m = FsckRequest{} }
+func (m *FsckRequest) String() string { return proto.CompactTextString(m) }
+func (*FsckRequest) ProtoMessage()    {}
+func (*FsckRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b48f014707f6595c, []int{65}
+}
+func (m *FsckRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *FsckRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_FsckRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *FsckRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FsckRequest.Merge(m, src)
+}
+func (m *FsckRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *FsckRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_FsckRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FsckRequest proto.InternalMessageInfo
+
+func (m *FsckRequest) GetFix() bool {
+	if m != nil {
+		return m.Fix
+	}
+	return false
+}
+
+type FsckResponse struct {
+	Fix                  string   `protobuf:"bytes,1,opt,name=fix,proto3" json:"fix,omitempty"`
+	Error                string   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FsckResponse) Reset()         { *m = FsckResponse{} }
+func (m *FsckResponse) String() string { return proto.CompactTextString(m) }
+func (*FsckResponse) ProtoMessage()    {}
+func (*FsckResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b48f014707f6595c, []int{66}
+}
+func (m *FsckResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *FsckResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_FsckResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *FsckResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FsckResponse.Merge(m, src)
+}
+func (m *FsckResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *FsckResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_FsckResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FsckResponse proto.InternalMessageInfo
+
+func (m *FsckResponse) GetFix() string {
+	if m != nil {
+		return m.Fix
+	}
+	return ""
+}
+
+func (m *FsckResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type PutObjectRequest struct {
+	Value []byte `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Tags  []*Tag `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"`
+	Block *Block `protobuf:"bytes,3,opt,name=block,proto3" json:"block,omitempty"`
+	// storage_class is the storage class of the block that the object is
+	// written to. Only the first request of a stream sets it.
+	StorageClass         StorageClass `protobuf:"varint,4,opt,name=storage_class,json=storageClass,proto3,enum=pfs.StorageClass" json:"storage_class,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *PutObjectRequest) Reset()         { *m = PutObjectRequest{} }
+func (m *PutObjectRequest) String() string { return proto.CompactTextString(m) }
+func (*PutObjectRequest) ProtoMessage()    {}
+func (*PutObjectRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b48f014707f6595c, []int{67}
+}
+func (m *PutObjectRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PutObjectRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PutObjectRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *PutObjectRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PutObjectRequest.Merge(m, src)
+}
+func (m *PutObjectRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *PutObjectRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PutObjectRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PutObjectRequest proto.InternalMessageInfo
+
+func (m *PutObjectRequest) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *PutObjectRequest) GetTags() []*Tag {
+	if m != nil {
+		return m.Tags
+	}
+	return nil
+}
+
+func (m *PutObjectRequest) GetBlock() *Block {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+func (m *PutObjectRequest) GetStorageClass() StorageClass {
+	if m != nil {
+		return m.StorageClass
+	}
+	return StorageClass_STANDARD
+}
+
+type CreateObjectRequest struct {
+	Object               *Object   `protobuf:"bytes,1,opt,name=object,proto3" json:"object,omitempty"`
+	BlockRef             *BlockRef `protobuf:"bytes,2,opt,name=block_ref,json=blockRef,proto3" json:"block_ref,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *CreateObjectRequest) Reset()         { *m = CreateObjectRequest{} }
 func (m *CreateObjectRequest) String() string { return proto.CompactTextString(m) }
 func (*CreateObjectRequest) ProtoMessage()    {}
 func (*CreateObjectRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{57}
+	return fileDescriptor_b48f014707f6595c, []int{68}
 }
 func (m *CreateObjectRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3832,7 +4964,7 @@ func (m *GetObjectsRequest) Reset()         { *m = GetObjectsRequest{} }
 func (m *GetObjectsRequest) String() string { return proto.CompactTextString(m) }
 func (*GetObjectsRequest) ProtoMessage()    {}
 func (*GetObjectsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{58}
+	return fileDescriptor_b48f014707f6595c, []int{69}
 }
 func (m *GetObjectsRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3901,7 +5033,7 @@ func (m *PutBlockRequest) Reset()         { *m = PutBlockRequest{} }
 func (m *PutBlockRequest) String() string { return proto.CompactTextString(m) }
 func (*PutBlockRequest) ProtoMessage()    {}
 func (*PutBlockRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{59}
+	return fileDescriptor_b48f014707f6595c, []int{70}
 }
 func (m *PutBlockRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3955,7 +5087,7 @@ func (m *GetBlockRequest) Reset()         { *m = GetBlockRequest{} }
 func (m *GetBlockRequest) String() string { return proto.CompactTextString(m) }
 func (*GetBlockRequest) ProtoMessage()    {}
 func (*GetBlockRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{60}
+	return fileDescriptor_b48f014707f6595c, []int{71}
 }
 func (m *GetBlockRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -4009,7 +5141,7 @@ func (m *GetBlocksRequest) Reset()         { *m = GetBlocksRequest{} }
 func (m *GetBlocksRequest) String() string { return proto.CompactTextString(m) }
 func (*GetBlocksRequest) ProtoMessage()    {}
 func (*GetBlocksRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{61}
+	return fileDescriptor_b48f014707f6595c, []int{72}
 }
 func (m *GetBlocksRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -4076,7 +5208,7 @@ func (m *ListBlockRequest) Reset()         { *m = ListBlockRequest{} }
 func (m *ListBlockRequest) String() string { return proto.CompactTextString(m) }
 func (*ListBlockRequest) ProtoMessage()    {}
 func (*ListBlockRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{62}
+	return fileDescriptor_b48f014707f6595c, []int{73}
 }
 func (m *ListBlockRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -4117,7 +5249,7 @@ func (m *TagObjectRequest) Reset()         { *m = TagObjectRequest{} }
 func (m *TagObjectRequest) String() string { return proto.CompactTextString(m) }
 func (*TagObjectRequest) ProtoMessage()    {}
 func (*TagObjectRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{63}
+	return fileDescriptor_b48f014707f6595c, []int{74}
 }
 func (m *TagObjectRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -4170,7 +5302,7 @@ func (m *ListObjectsRequest) Reset()         { *m = ListObjectsRequest{} }
 func (m *ListObjectsRequest) String() string { return proto.CompactTextString(m) }
 func (*ListObjectsRequest) ProtoMessage()    {}
 func (*ListObjectsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{64}
+	return fileDescriptor_b48f014707f6595c, []int{75}
 }
 func (m *ListObjectsRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -4211,7 +5343,7 @@ func (m *ListTagsRequest) Reset()         { *m = ListTagsRequest{} }
 func (m *ListTagsRequest) String() string { return proto.CompactTextString(m) }
 func (*ListTagsRequest) ProtoMessage()    {}
 func (*ListTagsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{65}
+	return fileDescriptor_b48f014707f6595c, []int{76}
 }
 func (m *ListTagsRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -4266,7 +5398,7 @@ func (m *ListTagsResponse) Reset()         { *m = ListTagsResponse{} }
 func (m *ListTagsResponse) String() string { return proto.CompactTextString(m) }
 func (*ListTagsResponse) ProtoMessage()    {}
 func (*ListTagsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{66}
+	return fileDescriptor_b48f014707f6595c, []int{77}
 }
 func (m *ListTagsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -4320,7 +5452,7 @@ func (m *DeleteObjectsRequest) Reset()         { *m = DeleteObjectsRequest{} }
 func (m *DeleteObjectsRequest) String() string { return proto.CompactTextString(m) }
 func (*DeleteObjectsRequest) ProtoMessage()    {}
 func (*DeleteObjectsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{67}
+	return fileDescriptor_b48f014707f6595c, []int{78}
 }
 func (m *DeleteObjectsRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -4366,7 +5498,7 @@ func (m *DeleteObjectsResponse) Reset()         { *m = DeleteObjectsResponse{} }
 func (m *DeleteObjectsResponse) String() string { return proto.CompactTextString(m) }
 func (*DeleteObjectsResponse) ProtoMessage()    {}
 func (*DeleteObjectsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{68}
+	return fileDescriptor_b48f014707f6595c, []int{79}
 }
 func (m *DeleteObjectsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -4395,25 +5527,31 @@ func (m *DeleteObjectsResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_DeleteObjectsResponse proto.InternalMessageInfo
 
-type DeleteTagsRequest struct {
-	Tags                 []*Tag   `protobuf:"bytes,1,rep,name=tags,proto3" json:"tags,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
-
-func (m *DeleteTagsRequest) Reset()         { *m = DeleteTagsRequest{} }
-func (m *DeleteTagsRequest) String() string { return proto.CompactTextString(m) }
-func (*DeleteTagsRequest) ProtoMessage()    {}
-func (*DeleteTagsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{69}
-}
-func (m *DeleteTagsRequest) XXX_Unmarshal(b []byte) error {
+// PackObjectsRequest packs small objects, each of which is stored in its own
+// block, into one shared block (a pack). The objects' content and hashes don't
+// change, only the blocks they're read from.
+type PackObjectsRequest struct {
+	// objects are the objects to pack. Objects that aren't stored in blocks of
+	// their own (e.g. because they're already packed) are skipped.
+	Objects              []*Object    `protobuf:"bytes,1,rep,name=objects,proto3" json:"objects,omitempty"`
+	StorageClass         StorageClass `protobuf:"varint,2,opt,name=storage_class,json=storageClass,proto3,enum=pfs.StorageClass" json:"storage_class,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *PackObjectsRequest) Reset()         { *m = PackObjectsRequest{} }
+func (m *PackObjectsRequest) String() string { return proto.CompactTextString(m) }
+func (*PackObjectsRequest) ProtoMessage()    {}
+func (*PackObjectsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b48f014707f6595c, []int{80}
+}
+func (m *PackObjectsRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *DeleteTagsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *PackObjectsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_DeleteTagsRequest.Marshal(b, m, deterministic)
+		return xxx_messageInfo_PackObjectsRequest.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -4423,21 +5561,139 @@ func (m *DeleteTagsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, e
 		return b[:n], nil
 	}
 }
-func (m *DeleteTagsRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_DeleteTagsRequest.Merge(m, src)
+func (m *PackObjectsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PackObjectsRequest.Merge(m, src)
 }
-func (m *DeleteTagsRequest) XXX_Size() int {
+func (m *PackObjectsRequest) XXX_Size() int {
 	return m.Size()
 }
-func (m *DeleteTagsRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_DeleteTagsRequest.DiscardUnknown(m)
+func (m *PackObjectsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PackObjectsRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_DeleteTagsRequest proto.InternalMessageInfo
+var xxx_messageInfo_PackObjectsRequest proto.InternalMessageInfo
 
-func (m *DeleteTagsRequest) GetTags() []*Tag {
+func (m *PackObjectsRequest) GetObjects() []*Object {
 	if m != nil {
-		return m.Tags
+		return m.Objects
+	}
+	return nil
+}
+
+func (m *PackObjectsRequest) GetStorageClass() StorageClass {
+	if m != nil {
+		return m.StorageClass
+	}
+	return StorageClass_STANDARD
+}
+
+type PackObjectsResponse struct {
+	// block is the pack, unset if no objects were packed
+	Block                *Block   `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
+	Objects              int64    `protobuf:"varint,2,opt,name=objects,proto3" json:"objects,omitempty"`
+	SizeBytes            uint64   `protobuf:"varint,3,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PackObjectsResponse) Reset()         { *m = PackObjectsResponse{} }
+func (m *PackObjectsResponse) String() string { return proto.CompactTextString(m) }
+func (*PackObjectsResponse) ProtoMessage()    {}
+func (*PackObjectsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b48f014707f6595c, []int{81}
+}
+func (m *PackObjectsResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PackObjectsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PackObjectsResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *PackObjectsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PackObjectsResponse.Merge(m, src)
+}
+func (m *PackObjectsResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *PackObjectsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PackObjectsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PackObjectsResponse proto.InternalMessageInfo
+
+func (m *PackObjectsResponse) GetBlock() *Block {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+func (m *PackObjectsResponse) GetObjects() int64 {
+	if m != nil {
+		return m.Objects
+	}
+	return 0
+}
+
+func (m *PackObjectsResponse) GetSizeBytes() uint64 {
+	if m != nil {
+		return m.SizeBytes
+	}
+	return 0
+}
+
+type DeleteTagsRequest struct {
+	Tags                 []*Tag   `protobuf:"bytes,1,rep,name=tags,proto3" json:"tags,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteTagsRequest) Reset()         { *m = DeleteTagsRequest{} }
+func (m *DeleteTagsRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteTagsRequest) ProtoMessage()    {}
+func (*DeleteTagsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b48f014707f6595c, []int{82}
+}
+func (m *DeleteTagsRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DeleteTagsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DeleteTagsRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *DeleteTagsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteTagsRequest.Merge(m, src)
+}
+func (m *DeleteTagsRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *DeleteTagsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteTagsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteTagsRequest proto.InternalMessageInfo
+
+func (m *DeleteTagsRequest) GetTags() []*Tag {
+	if m != nil {
+		return m.Tags
 	}
 	return nil
 }
@@ -4452,7 +5708,7 @@ func (m *DeleteTagsResponse) Reset()         { *m = DeleteTagsResponse{} }
 func (m *DeleteTagsResponse) String() string { return proto.CompactTextString(m) }
 func (*DeleteTagsResponse) ProtoMessage()    {}
 func (*DeleteTagsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{70}
+	return fileDescriptor_b48f014707f6595c, []int{83}
 }
 func (m *DeleteTagsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -4492,7 +5748,7 @@ func (m *CheckObjectRequest) Reset()         { *m = CheckObjectRequest{} }
 func (m *CheckObjectRequest) String() string { return proto.CompactTextString(m) }
 func (*CheckObjectRequest) ProtoMessage()    {}
 func (*CheckObjectRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{71}
+	return fileDescriptor_b48f014707f6595c, []int{84}
 }
 func (m *CheckObjectRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -4539,7 +5795,7 @@ func (m *CheckObjectResponse) Reset()         { *m = CheckObjectResponse{} }
 func (m *CheckObjectResponse) String() string { return proto.CompactTextString(m) }
 func (*CheckObjectResponse) ProtoMessage()    {}
 func (*CheckObjectResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{72}
+	return fileDescriptor_b48f014707f6595c, []int{85}
 }
 func (m *CheckObjectResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -4586,7 +5842,7 @@ func (m *Objects) Reset()         { *m = Objects{} }
 func (m *Objects) String() string { return proto.CompactTextString(m) }
 func (*Objects) ProtoMessage()    {}
 func (*Objects) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{73}
+	return fileDescriptor_b48f014707f6595c, []int{86}
 }
 func (m *Objects) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -4634,7 +5890,7 @@ func (m *ObjectIndex) Reset()         { *m = ObjectIndex{} }
 func (m *ObjectIndex) String() string { return proto.CompactTextString(m) }
 func (*ObjectIndex) ProtoMessage()    {}
 func (*ObjectIndex) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b48f014707f6595c, []int{74}
+	return fileDescriptor_b48f014707f6595c, []int{87}
 }
 func (m *ObjectIndex) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -4678,19 +5934,24 @@ func (m *ObjectIndex) GetTags() map[string]*Object {
 }
 
 func init() {
+	proto.RegisterEnum("pfs.StorageClass", StorageClass_name, StorageClass_value)
 	proto.RegisterEnum("pfs.OriginKind", OriginKind_name, OriginKind_value)
 	proto.RegisterEnum("pfs.FileType", FileType_name, FileType_value)
+	proto.RegisterEnum("pfs.ChecksumAlgorithm", ChecksumAlgorithm_name, ChecksumAlgorithm_value)
 	proto.RegisterEnum("pfs.CommitState", CommitState_name, CommitState_value)
+	proto.RegisterEnum("pfs.FileChangeType", FileChangeType_name, FileChangeType_value)
 	proto.RegisterEnum("pfs.Delimiter", Delimiter_name, Delimiter_value)
 	proto.RegisterType((*Repo)(nil), "pfs.Repo")
 	proto.RegisterType((*Branch)(nil), "pfs.Branch")
 	proto.RegisterType((*BranchInfo)(nil), "pfs.BranchInfo")
+	proto.RegisterType((*BranchProtection)(nil), "pfs.BranchProtection")
 	proto.RegisterType((*BranchInfos)(nil), "pfs.BranchInfos")
 	proto.RegisterType((*File)(nil), "pfs.File")
 	proto.RegisterType((*Block)(nil), "pfs.Block")
 	proto.RegisterType((*Object)(nil), "pfs.Object")
 	proto.RegisterType((*Tag)(nil), "pfs.Tag")
 	proto.RegisterType((*RepoInfo)(nil), "pfs.RepoInfo")
+	proto.RegisterType((*StoragePolicy)(nil), "pfs.StoragePolicy")
 	proto.RegisterType((*RepoAuthInfo)(nil), "pfs.RepoAuthInfo")
 	proto.RegisterType((*CommitOrigin)(nil), "pfs.CommitOrigin")
 	proto.RegisterType((*Commit)(nil), "pfs.Commit")
@@ -4698,6 +5959,7 @@ func init() {
 	proto.RegisterType((*CommitProvenance)(nil), "pfs.CommitProvenance")
 	proto.RegisterType((*CommitInfo)(nil), "pfs.CommitInfo")
 	proto.RegisterType((*FileInfo)(nil), "pfs.FileInfo")
+	proto.RegisterType((*Checksum)(nil), "pfs.Checksum")
 	proto.RegisterType((*ByteRange)(nil), "pfs.ByteRange")
 	proto.RegisterType((*BlockRef)(nil), "pfs.BlockRef")
 	proto.RegisterType((*ObjectInfo)(nil), "pfs.ObjectInfo")
@@ -4720,8 +5982,12 @@ func init() {
 	proto.RegisterType((*ListBranchRequest)(nil), "pfs.ListBranchRequest")
 	proto.RegisterType((*DeleteBranchRequest)(nil), "pfs.DeleteBranchRequest")
 	proto.RegisterType((*DeleteCommitRequest)(nil), "pfs.DeleteCommitRequest")
+	proto.RegisterType((*ExpireCommitsRequest)(nil), "pfs.ExpireCommitsRequest")
 	proto.RegisterType((*FlushCommitRequest)(nil), "pfs.FlushCommitRequest")
 	proto.RegisterType((*SubscribeCommitRequest)(nil), "pfs.SubscribeCommitRequest")
+	proto.RegisterType((*SubscribeFileChangesRequest)(nil), "pfs.SubscribeFileChangesRequest")
+	proto.RegisterType((*FileChange)(nil), "pfs.FileChange")
+	proto.RegisterType((*FileChangeEvent)(nil), "pfs.FileChangeEvent")
 	proto.RegisterType((*GetFileRequest)(nil), "pfs.GetFileRequest")
 	proto.RegisterType((*OverwriteIndex)(nil), "pfs.OverwriteIndex")
 	proto.RegisterType((*PutFileRequest)(nil), "pfs.PutFileRequest")
@@ -4735,6 +6001,12 @@ func init() {
 	proto.RegisterType((*FileInfos)(nil), "pfs.FileInfos")
 	proto.RegisterType((*DiffFileRequest)(nil), "pfs.DiffFileRequest")
 	proto.RegisterType((*DiffFileResponse)(nil), "pfs.DiffFileResponse")
+	proto.RegisterType((*IndexedFile)(nil), "pfs.IndexedFile")
+	proto.RegisterMapType((map[string]string)(nil), "pfs.IndexedFile.AttributesEntry")
+	proto.RegisterType((*FileIndex)(nil), "pfs.FileIndex")
+	proto.RegisterType((*SearchFilesRequest)(nil), "pfs.SearchFilesRequest")
+	proto.RegisterMapType((map[string]string)(nil), "pfs.SearchFilesRequest.AttributesEntry")
+	proto.RegisterType((*SearchFilesResponse)(nil), "pfs.SearchFilesResponse")
 	proto.RegisterType((*DeleteFileRequest)(nil), "pfs.DeleteFileRequest")
 	proto.RegisterType((*FsckRequest)(nil), "pfs.FsckRequest")
 	proto.RegisterType((*FsckResponse)(nil), "pfs.FsckResponse")
@@ -4751,6 +6023,8 @@ func init() {
 	proto.RegisterType((*ListTagsResponse)(nil), "pfs.ListTagsResponse")
 	proto.RegisterType((*DeleteObjectsRequest)(nil), "pfs.DeleteObjectsRequest")
 	proto.RegisterType((*DeleteObjectsResponse)(nil), "pfs.DeleteObjectsResponse")
+	proto.RegisterType((*PackObjectsRequest)(nil), "pfs.PackObjectsRequest")
+	proto.RegisterType((*PackObjectsResponse)(nil), "pfs.PackObjectsResponse")
 	proto.RegisterType((*DeleteTagsRequest)(nil), "pfs.DeleteTagsRequest")
 	proto.RegisterType((*DeleteTagsResponse)(nil), "pfs.DeleteTagsResponse")
 	proto.RegisterType((*CheckObjectRequest)(nil), "pfs.CheckObjectRequest")
@@ -4764,223 +6038,287 @@ func init() {
 func init() { proto.RegisterFile("client/pfs/pfs.proto", fileDescriptor_b48f014707f6595c) }
 
 var fileDescriptor_b48f014707f6595c = []byte{
-	// 3447 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x3a, 0x5b, 0x6f, 0x1b, 0xc7,
-	0xd5, 0x5a, 0x5e, 0x77, 0x0f, 0x75, 0x59, 0x8d, 0x65, 0x99, 0xa6, 0xe3, 0x4b, 0xd6, 0x4e, 0x3e,
-	0x47, 0x49, 0x64, 0x45, 0xfa, 0x12, 0xdf, 0x92, 0x08, 0xd6, 0xcd, 0x91, 0xe3, 0xcf, 0xd6, 0xb7,
-	0x54, 0x52, 0x34, 0x68, 0x4b, 0xac, 0xc8, 0x21, 0xb9, 0xf1, 0x8a, 0xcb, 0xec, 0x2c, 0x2d, 0x2b,
-	0x7f, 0xa0, 0x4f, 0x7d, 0x2f, 0xd0, 0x3e, 0x14, 0x2d, 0xd0, 0xe7, 0xa2, 0x7f, 0xa1, 0x2f, 0x45,
-	0x81, 0x02, 0xed, 0x1f, 0x28, 0x0a, 0xf7, 0xbd, 0x3f, 0x20, 0x2f, 0x2d, 0xe6, 0xb6, 0x3b, 0x7b,
-	0xa1, 0x48, 0x05, 0xed, 0x83, 0xad, 0xd9, 0x39, 0x97, 0x39, 0x73, 0x6e, 0x73, 0xce, 0x91, 0x60,
-	0xa9, 0xed, 0xb9, 0x78, 0x10, 0xde, 0x19, 0x76, 0x09, 0xfd, 0xb7, 0x3a, 0x0c, 0xfc, 0xd0, 0x47,
-	0xc5, 0x61, 0x97, 0x34, 0xae, 0xf4, 0x7c, 0xbf, 0xe7, 0xe1, 0x3b, 0x6c, 0xeb, 0x68, 0xd4, 0xbd,
-	0x83, 0x8f, 0x87, 0xe1, 0x29, 0xc7, 0x68, 0x5c, 0x4f, 0x03, 0x43, 0xf7, 0x18, 0x93, 0xd0, 0x39,
-	0x1e, 0x0a, 0x84, 0x6b, 0x69, 0x84, 0x93, 0xc0, 0x19, 0x0e, 0x71, 0x20, 0x8e, 0x68, 0x2c, 0xf5,
-	0xfc, 0x9e, 0xcf, 0x96, 0x77, 0xe8, 0x4a, 0xec, 0x2e, 0x0b, 0x71, 0x9c, 0x51, 0xd8, 0x67, 0xff,
-	0xf1, 0x7d, 0xab, 0x01, 0x25, 0x1b, 0x0f, 0x7d, 0x84, 0xa0, 0x34, 0x70, 0x8e, 0x71, 0x5d, 0xbb,
-	0xa1, 0xdd, 0x36, 0x6c, 0xb6, 0xb6, 0x1e, 0x42, 0x65, 0x2b, 0x70, 0x06, 0xed, 0x3e, 0xba, 0x0a,
-	0xa5, 0x00, 0x0f, 0x7d, 0x06, 0xad, 0xad, 0x1b, 0xab, 0xf4, 0x42, 0x94, 0xcc, 0x66, 0xdb, 0x11,
-	0x71, 0x41, 0x21, 0xfe, 0x4e, 0x03, 0xe0, 0xd4, 0xfb, 0x83, 0xae, 0x8f, 0x6e, 0x42, 0xe5, 0x88,
-	0x7d, 0xd5, 0x4b, 0x8c, 0x47, 0x8d, 0xf1, 0xe0, 0x08, 0xb6, 0x00, 0xa1, 0xeb, 0x50, 0xea, 0x63,
-	0xa7, 0xc3, 0xf8, 0x48, 0x94, 0x6d, 0xff, 0xf8, 0xd8, 0x0d, 0x6d, 0x06, 0x40, 0xef, 0x02, 0x0c,
-	0x03, 0xff, 0x25, 0x1e, 0x38, 0x83, 0x36, 0xae, 0x17, 0x6f, 0x14, 0xd3, 0x9c, 0x14, 0x30, 0x45,
-	0x26, 0xa3, 0x23, 0x89, 0x5c, 0xce, 0x41, 0x8e, 0xc1, 0xe8, 0x1e, 0x2c, 0x76, 0xdc, 0x00, 0xb7,
-	0xc3, 0x96, 0x72, 0x40, 0x25, 0x4b, 0x63, 0x72, 0xac, 0x83, 0xf8, 0x98, 0x3c, 0xcd, 0x6d, 0x42,
-	0x2d, 0xbe, 0x3b, 0x41, 0x6b, 0x50, 0xe3, 0x37, 0x6c, 0xb9, 0x83, 0x2e, 0xd5, 0x22, 0x65, 0xbb,
-	0xa0, 0xb0, 0xa5, 0x68, 0x36, 0x1c, 0x45, 0x6b, 0x6b, 0x13, 0x4a, 0x7b, 0xae, 0x87, 0xa9, 0xda,
-	0xda, 0x4c, 0x01, 0x42, 0xf5, 0x09, 0x9d, 0x08, 0x10, 0x95, 0x60, 0xe8, 0x84, 0x7d, 0xa9, 0x7e,
-	0xba, 0xb6, 0xae, 0x40, 0x79, 0xcb, 0xf3, 0xdb, 0x2f, 0x28, 0xb0, 0xef, 0x90, 0xbe, 0x14, 0x8f,
-	0xae, 0xad, 0x37, 0xa0, 0xf2, 0xfc, 0xe8, 0x6b, 0xdc, 0x0e, 0x73, 0xa1, 0x97, 0xa1, 0x78, 0xe8,
-	0xf4, 0x72, 0xef, 0xf5, 0x2f, 0x0d, 0x74, 0x6a, 0x77, 0x66, 0xd2, 0x09, 0x4e, 0xf1, 0xbf, 0x50,
-	0x6d, 0x07, 0xd8, 0x09, 0xb1, 0xb4, 0x67, 0x63, 0x95, 0x7b, 0xee, 0xaa, 0xf4, 0xdc, 0xd5, 0x43,
-	0xe9, 0xda, 0xb6, 0x44, 0x45, 0x57, 0x01, 0x88, 0xfb, 0x2d, 0x6e, 0x1d, 0x9d, 0x86, 0x98, 0xd4,
-	0x8b, 0x37, 0xb4, 0xdb, 0x25, 0xdb, 0xa0, 0x3b, 0x5b, 0x74, 0x03, 0xdd, 0x80, 0x5a, 0x07, 0x93,
-	0x76, 0xe0, 0x0e, 0x43, 0xd7, 0x1f, 0xd4, 0xcb, 0x4c, 0x36, 0x75, 0x0b, 0xfd, 0x0f, 0xe8, 0x5c,
-	0x8f, 0x98, 0xd4, 0xab, 0x59, 0xfb, 0x45, 0x40, 0xb4, 0x0a, 0x06, 0x8d, 0x03, 0x6e, 0x92, 0x0a,
-	0x93, 0x70, 0x31, 0xba, 0xc3, 0xa3, 0x51, 0xc8, 0x8d, 0xa2, 0x3b, 0x62, 0xf5, 0xa4, 0xa4, 0x97,
-	0xcc, 0xb2, 0xf5, 0x29, 0xcc, 0xaa, 0x70, 0xb4, 0x0a, 0xb3, 0x4e, 0xbb, 0x8d, 0x09, 0x69, 0x79,
-	0xf8, 0x25, 0xf6, 0x98, 0x32, 0xe6, 0xd7, 0x6b, 0xab, 0x2c, 0xc4, 0x9a, 0x6d, 0x7f, 0x88, 0xed,
-	0x1a, 0x47, 0x78, 0x4a, 0xe1, 0xd6, 0x06, 0xcc, 0x72, 0xeb, 0x3d, 0x0f, 0xdc, 0x9e, 0x3b, 0x40,
-	0x37, 0xa1, 0xf4, 0xc2, 0x1d, 0x74, 0x04, 0x1d, 0xf7, 0x09, 0x0e, 0xfa, 0xdc, 0x1d, 0x74, 0x6c,
-	0x06, 0xb4, 0x36, 0xa1, 0xc2, 0x89, 0x26, 0xe9, 0x7c, 0x19, 0x0a, 0x2e, 0x57, 0xb7, 0xb1, 0x55,
-	0x79, 0xfd, 0xb7, 0xeb, 0x85, 0xfd, 0x1d, 0xbb, 0xe0, 0x76, 0xac, 0x26, 0xd4, 0x84, 0xcf, 0x38,
-	0x83, 0x1e, 0x46, 0x6f, 0x42, 0xd9, 0xf3, 0x4f, 0x70, 0x90, 0xe7, 0x54, 0x1c, 0x42, 0x51, 0x46,
-	0x34, 0xab, 0xe4, 0xc5, 0x22, 0x87, 0x58, 0x3f, 0x02, 0x93, 0x6f, 0x28, 0xc1, 0x30, 0x95, 0xbf,
-	0xc6, 0xb9, 0xa0, 0x30, 0x36, 0x17, 0x58, 0x7f, 0xae, 0x00, 0x70, 0x3a, 0x99, 0x3f, 0xce, 0xc3,
-	0x78, 0x61, 0x7c, 0x92, 0x79, 0x07, 0x2a, 0x3e, 0x53, 0x70, 0x7d, 0x51, 0x31, 0xba, 0x6a, 0x14,
-	0x5b, 0x20, 0xa4, 0xbd, 0x4d, 0xcf, 0x7a, 0xdb, 0x1a, 0xcc, 0x0d, 0x9d, 0x00, 0x0f, 0xc2, 0x96,
-	0x90, 0x2e, 0x47, 0x5d, 0xb3, 0x1c, 0x43, 0x58, 0x70, 0x0d, 0xe6, 0xda, 0x7d, 0xd7, 0xeb, 0x08,
-	0x02, 0x52, 0xaf, 0x29, 0x4e, 0x2a, 0x29, 0x18, 0x06, 0xff, 0x20, 0x34, 0x90, 0x48, 0xe8, 0x04,
-	0x34, 0x90, 0x8a, 0x93, 0x03, 0x49, 0xa0, 0xa2, 0x8f, 0x40, 0xef, 0xba, 0x03, 0x97, 0xf4, 0x71,
-	0x47, 0xa4, 0xdc, 0xb3, 0xc8, 0x22, 0xdc, 0x54, 0x00, 0x96, 0xd3, 0x01, 0xf8, 0x61, 0x22, 0x03,
-	0x9b, 0x4c, 0xf6, 0x8b, 0x8a, 0xec, 0xb1, 0x2f, 0x24, 0x72, 0xf1, 0x3b, 0x60, 0x06, 0xd8, 0xe9,
-	0x9c, 0xaa, 0xd9, 0x75, 0xf6, 0x86, 0x76, 0xbb, 0x68, 0x2f, 0xb0, 0x7d, 0xc5, 0x85, 0xd6, 0x12,
-	0x69, 0xdb, 0x60, 0x27, 0x98, 0xaa, 0x76, 0xa8, 0x0b, 0x27, 0x72, 0xf7, 0x75, 0x28, 0x85, 0x01,
-	0xc6, 0xf5, 0xaa, 0xa2, 0x7b, 0x9e, 0xdf, 0x6c, 0x06, 0xa0, 0xce, 0x4c, 0x7f, 0x92, 0xfa, 0x9c,
-	0xa2, 0x6b, 0x81, 0xc1, 0x21, 0xd4, 0x75, 0x3a, 0x4e, 0x38, 0x3a, 0x26, 0xf5, 0xf9, 0x2c, 0x17,
-	0x01, 0x42, 0x0f, 0xe0, 0xb2, 0x3c, 0x56, 0x1a, 0x9c, 0xb4, 0xc8, 0x88, 0x85, 0x77, 0x1d, 0xb1,
-	0xeb, 0x5c, 0x8a, 0x10, 0x84, 0xf9, 0x9a, 0x1c, 0x9c, 0x4f, 0xdb, 0x75, 0x5c, 0x6f, 0x14, 0xe0,
-	0xfa, 0x85, 0x7c, 0xda, 0x3d, 0x0e, 0x46, 0x1f, 0xc1, 0xa5, 0x2c, 0x6d, 0xe8, 0x87, 0x8e, 0x57,
-	0x5f, 0x62, 0x94, 0x17, 0xd3, 0x94, 0x87, 0x14, 0xf8, 0xa4, 0xa4, 0x57, 0xcc, 0xea, 0x93, 0x92,
-	0x0e, 0x66, 0xcd, 0xfa, 0x7d, 0x01, 0x74, 0xfa, 0xa4, 0xc8, 0xd4, 0xdd, 0x75, 0x3d, 0x9c, 0x48,
-	0x23, 0x14, 0x68, 0xb3, 0x6d, 0xb4, 0x02, 0x06, 0xfd, 0xd9, 0x0a, 0x4f, 0x87, 0xfc, 0x51, 0x9f,
-	0x5f, 0x9f, 0x8b, 0x70, 0x0e, 0x4f, 0x87, 0x98, 0xfa, 0x0b, 0x5f, 0x4d, 0x4a, 0xd8, 0xf7, 0xc0,
-	0xe0, 0x02, 0x53, 0xf7, 0x85, 0x89, 0x7e, 0x18, 0x23, 0xa3, 0x06, 0xe8, 0x2c, 0x0c, 0x02, 0x3c,
-	0x60, 0x0f, 0xb1, 0x61, 0x47, 0xdf, 0xe8, 0x2d, 0xa8, 0xfa, 0xcc, 0x34, 0xa4, 0xae, 0x67, 0x4d,
-	0x2a, 0x61, 0xe8, 0x5d, 0x30, 0x8e, 0xe8, 0x23, 0x68, 0xe3, 0x2e, 0x11, 0x9e, 0xc4, 0xef, 0xb1,
-	0x25, 0x76, 0xed, 0x18, 0x1e, 0x3d, 0x85, 0xd4, 0x8b, 0x66, 0xc5, 0x53, 0x78, 0x17, 0x0c, 0x7a,
-	0x0d, 0x9e, 0x35, 0x97, 0xd4, 0xac, 0x59, 0x92, 0x89, 0x72, 0x49, 0x4d, 0x94, 0x25, 0x99, 0x1b,
-	0x6d, 0xd0, 0xe5, 0x19, 0xe8, 0x06, 0x94, 0xd9, 0x29, 0x42, 0xdb, 0xa0, 0x48, 0xc0, 0x01, 0xe8,
-	0x16, 0x94, 0x03, 0x7a, 0x84, 0xc8, 0x1e, 0xf3, 0x1c, 0x43, 0x1e, 0x6c, 0x73, 0xa0, 0xf5, 0x63,
-	0x00, 0x7e, 0x41, 0x99, 0x10, 0xf9, 0x35, 0x13, 0x09, 0x51, 0x3a, 0x2c, 0x07, 0x51, 0x43, 0xb2,
-	0x13, 0x5a, 0x01, 0xee, 0x0a, 0xe6, 0x29, 0x05, 0xe8, 0x52, 0x01, 0xd6, 0x4d, 0x28, 0xff, 0x1f,
-	0x0e, 0x7a, 0x98, 0x2a, 0x7e, 0x18, 0xe0, 0xae, 0xfb, 0x0a, 0x13, 0x56, 0xaa, 0x18, 0x76, 0xf4,
-	0x6d, 0xbd, 0x0f, 0xe5, 0x66, 0xdf, 0x09, 0x3a, 0xb1, 0xc8, 0x9a, 0x22, 0xf2, 0x81, 0x13, 0xf6,
-	0x13, 0x22, 0xdf, 0x05, 0x23, 0xda, 0x4b, 0xea, 0xcf, 0xc8, 0xd5, 0x9f, 0x21, 0xf5, 0x17, 0xc0,
-	0xe2, 0x36, 0xab, 0x08, 0xd8, 0xe3, 0x86, 0xbf, 0x19, 0x61, 0x32, 0xf1, 0xf1, 0x4b, 0x65, 0xeb,
-	0x62, 0x36, 0x5b, 0x2f, 0x43, 0x65, 0x34, 0xec, 0x38, 0x21, 0x66, 0x19, 0x51, 0xb7, 0xc5, 0xd7,
-	0x93, 0x92, 0x5e, 0x30, 0x8b, 0xd6, 0x06, 0xa0, 0xfd, 0x01, 0x19, 0x52, 0xfd, 0x4d, 0x7d, 0xa8,
-	0x75, 0x09, 0x16, 0x9e, 0xba, 0x44, 0xa5, 0x78, 0x52, 0xd2, 0x35, 0xb3, 0x60, 0x7d, 0x0a, 0x66,
-	0x0c, 0x20, 0x43, 0x7f, 0x40, 0x58, 0x5c, 0x51, 0x22, 0xb5, 0x0a, 0x9c, 0x8b, 0x18, 0xf2, 0x72,
-	0x23, 0x10, 0x2b, 0xeb, 0x2b, 0x58, 0xdc, 0xc1, 0x1e, 0x3e, 0x97, 0x06, 0x96, 0xa0, 0xdc, 0xf5,
-	0x83, 0x36, 0xf7, 0x23, 0xdd, 0xe6, 0x1f, 0xc8, 0x84, 0xa2, 0xe3, 0x79, 0x4c, 0x1f, 0xba, 0x4d,
-	0x97, 0xd6, 0xef, 0x34, 0x40, 0x4d, 0xfa, 0x4e, 0x88, 0x8c, 0x2a, 0xb8, 0xdf, 0x84, 0x0a, 0x7f,
-	0xaa, 0x72, 0xdf, 0x58, 0x0e, 0x4a, 0x6b, 0xb9, 0x94, 0xab, 0x65, 0xf1, 0x0a, 0x73, 0x13, 0xc8,
-	0x87, 0x37, 0xf9, 0x74, 0x94, 0xa7, 0x7c, 0x3a, 0x84, 0x71, 0x7e, 0x5b, 0x00, 0xb4, 0x35, 0x8a,
-	0x5e, 0xc5, 0x73, 0x89, 0xbc, 0x9c, 0xe8, 0x3d, 0xc6, 0x09, 0x54, 0x99, 0xf6, 0x2d, 0x93, 0xcf,
-	0x4d, 0x71, 0xe2, 0x73, 0x53, 0x9d, 0xe2, 0xb9, 0xd1, 0xc7, 0x3f, 0x37, 0xf3, 0x50, 0xd8, 0xdf,
-	0x11, 0x35, 0x6e, 0x61, 0x7f, 0x27, 0x95, 0x6a, 0x8d, 0x54, 0xaa, 0x15, 0x8a, 0xfa, 0x4e, 0x83,
-	0x0b, 0x7b, 0xec, 0x31, 0xcf, 0x68, 0x6a, 0x72, 0x01, 0x95, 0x32, 0x6e, 0x21, 0x6b, 0xdc, 0xe9,
-	0x2f, 0x5f, 0x9e, 0xe2, 0xf2, 0xd5, 0xf1, 0x97, 0x4f, 0x5e, 0xb6, 0x92, 0x7e, 0x57, 0x96, 0xa0,
-	0xcc, 0xba, 0x66, 0x11, 0xc9, 0xfc, 0xc3, 0x1a, 0xc0, 0x92, 0x08, 0xe1, 0xef, 0x71, 0xf9, 0x0f,
-	0xa0, 0xc6, 0x93, 0x25, 0x09, 0x69, 0x8a, 0xe0, 0xef, 0x9e, 0x5a, 0x79, 0x34, 0xe9, 0xbe, 0x0d,
-	0x0c, 0x89, 0xad, 0xad, 0x5f, 0x6b, 0xb0, 0x48, 0xa3, 0x3c, 0x79, 0xda, 0x84, 0x28, 0xbd, 0x0e,
-	0xa5, 0x6e, 0xe0, 0x1f, 0xe7, 0x76, 0xb9, 0x14, 0x80, 0xae, 0x40, 0x21, 0xf4, 0x13, 0x1a, 0x16,
-	0xe0, 0x42, 0x48, 0x4b, 0xfc, 0xca, 0x60, 0x74, 0x7c, 0x84, 0x03, 0x76, 0xf3, 0x92, 0x2d, 0xbe,
-	0x50, 0x1d, 0xaa, 0x01, 0x7e, 0x89, 0x03, 0x82, 0x99, 0xc7, 0xe8, 0xb6, 0xfc, 0xa4, 0xcd, 0x68,
-	0x5c, 0x48, 0xb3, 0x66, 0x94, 0x5f, 0x38, 0xdb, 0x8c, 0xc6, 0x68, 0x36, 0xb4, 0xa3, 0xb5, 0xf5,
-	0x1b, 0x0d, 0x2e, 0xf0, 0x6c, 0x2c, 0x4a, 0x69, 0x71, 0x4f, 0xd9, 0xae, 0x6b, 0xe3, 0xda, 0xf5,
-	0xcb, 0xa0, 0x93, 0x96, 0x52, 0xea, 0x1b, 0x76, 0x95, 0x88, 0x89, 0xc2, 0xcd, 0x44, 0x92, 0x18,
-	0x53, 0xaa, 0x27, 0xdb, 0xfd, 0xd2, 0x99, 0xed, 0xbe, 0xf5, 0x30, 0xb2, 0x7d, 0x52, 0xca, 0xf8,
-	0x24, 0x6d, 0x7c, 0xb7, 0xf1, 0x94, 0xdb, 0x31, 0x49, 0x39, 0xc1, 0x8e, 0x8a, 0xc6, 0x0b, 0x49,
-	0x8d, 0x1f, 0xc0, 0x05, 0x9e, 0xbb, 0xcf, 0x2f, 0x49, 0x7e, 0x0e, 0xb7, 0x1e, 0x48, 0x8e, 0xe7,
-	0xf7, 0x6b, 0xcb, 0x01, 0xb4, 0xe7, 0x8d, 0xd2, 0xf9, 0xe0, 0x2d, 0xa8, 0xca, 0x0e, 0x44, 0xcb,
-	0x76, 0x20, 0x12, 0x86, 0x6e, 0x81, 0x1e, 0xfa, 0x2d, 0x7a, 0x5f, 0x52, 0x2f, 0x30, 0x3c, 0x45,
-	0x0f, 0xd5, 0xd0, 0xa7, 0x3f, 0x89, 0xf5, 0x07, 0x0d, 0x96, 0x9b, 0xa3, 0x23, 0x9a, 0x26, 0x8e,
-	0xf0, 0xb9, 0x82, 0x61, 0x39, 0xd1, 0x0b, 0x1a, 0x4a, 0x97, 0x56, 0xa2, 0xb6, 0x65, 0xbe, 0x3c,
-	0x36, 0x2b, 0x33, 0x94, 0x28, 0x9e, 0x8a, 0xe3, 0xe2, 0xe9, 0x6d, 0x28, 0xf3, 0x90, 0x2e, 0x8d,
-	0x09, 0x69, 0x0e, 0xb6, 0xbe, 0x81, 0xf9, 0xc7, 0x38, 0x64, 0x75, 0x70, 0x2c, 0xfc, 0x59, 0x75,
-	0xf2, 0x9b, 0x30, 0xeb, 0x77, 0xbb, 0x04, 0x87, 0x22, 0x4b, 0x15, 0x58, 0x31, 0x5e, 0xe3, 0x7b,
-	0x3c, 0x4f, 0x65, 0xcb, 0xe3, 0xa2, 0x92, 0xc6, 0xac, 0xb7, 0x61, 0xfe, 0xf9, 0x4b, 0x1c, 0x9c,
-	0x04, 0x6e, 0x88, 0xf7, 0x07, 0x1d, 0xfc, 0x8a, 0xda, 0xdf, 0xa5, 0x0b, 0x76, 0x66, 0xd1, 0xe6,
-	0x1f, 0xd6, 0x3f, 0x0b, 0x30, 0x7f, 0x30, 0x3a, 0x8f, 0x6c, 0x4b, 0x50, 0x7e, 0xe9, 0x78, 0x23,
-	0x9e, 0xa9, 0x67, 0x6d, 0xfe, 0x41, 0x6b, 0x81, 0x51, 0xe0, 0x89, 0x37, 0x85, 0x2e, 0xd1, 0x1b,
-	0xb4, 0x26, 0x69, 0x8f, 0x02, 0xe2, 0xbe, 0xc4, 0x2c, 0xcd, 0xea, 0x76, 0xbc, 0x81, 0xde, 0x03,
-	0xa3, 0x83, 0x3d, 0xf7, 0xd8, 0x0d, 0x71, 0xc0, 0xb2, 0xf5, 0xbc, 0x28, 0xf5, 0x76, 0xe4, 0xae,
-	0x1d, 0x23, 0xa0, 0xf7, 0x00, 0x85, 0x4e, 0xd0, 0xc3, 0x61, 0x8b, 0xb5, 0x0f, 0xca, 0x0b, 0x57,
-	0xb4, 0x4d, 0x0e, 0xa1, 0x12, 0xee, 0xf0, 0x0c, 0xbf, 0x02, 0x8b, 0x2a, 0x76, 0xfc, 0xaa, 0x15,
-	0xed, 0x85, 0x18, 0x99, 0xab, 0xf1, 0x2d, 0x98, 0xa7, 0x19, 0x05, 0x07, 0xad, 0x00, 0xb7, 0xfd,
-	0xa0, 0x43, 0xdb, 0x66, 0x8a, 0x38, 0xc7, 0x77, 0x6d, 0xbe, 0x89, 0x3e, 0x86, 0x05, 0x5f, 0xaa,
-	0xb3, 0xc5, 0xd5, 0xc8, 0x7b, 0x8e, 0x0b, 0xfc, 0x89, 0x49, 0xa8, 0xda, 0x9e, 0xf7, 0x13, 0xdf,
-	0xfc, 0x01, 0x15, 0x73, 0x9e, 0x9f, 0x69, 0x30, 0x17, 0x29, 0x9c, 0x32, 0x4f, 0x59, 0x52, 0x4b,
-	0x59, 0x12, 0x5d, 0x87, 0x1a, 0x2f, 0xba, 0x5b, 0xac, 0x8b, 0xe0, 0xde, 0x0c, 0x7c, 0xeb, 0x33,
-	0x87, 0xf4, 0xf3, 0x64, 0x2b, 0x4e, 0x2d, 0x9b, 0xf5, 0x27, 0x4d, 0x71, 0x00, 0x7e, 0xd9, 0x25,
-	0x28, 0x93, 0xa1, 0x27, 0x62, 0x5f, 0xb7, 0xf9, 0x07, 0x7a, 0x8f, 0x66, 0x25, 0xae, 0x22, 0x1e,
-	0xaf, 0x88, 0x97, 0xe6, 0x2a, 0xad, 0x2d, 0x51, 0xa8, 0xf5, 0x43, 0xff, 0xf8, 0x88, 0x84, 0xfe,
-	0x00, 0x8b, 0x0a, 0x31, 0xde, 0x40, 0x2b, 0x50, 0xe1, 0xfa, 0x15, 0x13, 0x84, 0x3c, 0x56, 0x02,
-	0x83, 0xe2, 0x76, 0x7d, 0x9f, 0xba, 0x49, 0x79, 0x3c, 0x2e, 0xc7, 0xb0, 0x5c, 0x58, 0xd8, 0xf6,
-	0x87, 0xa7, 0xaa, 0x37, 0x5f, 0x81, 0x22, 0x09, 0xda, 0x59, 0x67, 0xa6, 0xbb, 0x14, 0xd8, 0x21,
-	0x72, 0xb6, 0xa2, 0x02, 0x3b, 0x24, 0xa4, 0x57, 0x88, 0x74, 0x25, 0xaf, 0x10, 0x6d, 0x28, 0x45,
-	0xfd, 0xf4, 0xb1, 0x63, 0xfd, 0x84, 0x17, 0xf5, 0xe7, 0x88, 0x36, 0x04, 0xa5, 0xee, 0xc8, 0xf3,
-	0x44, 0xd2, 0x66, 0x6b, 0xfa, 0x3e, 0xf4, 0x5d, 0x12, 0xfa, 0xc1, 0xa9, 0x88, 0x7b, 0xf9, 0x69,
-	0xad, 0xc1, 0xc2, 0x0f, 0x1c, 0xef, 0xc5, 0x39, 0x24, 0x3a, 0x80, 0x85, 0xc7, 0x9e, 0x7f, 0xa4,
-	0x52, 0x4c, 0x55, 0xd3, 0xd4, 0xa1, 0x3a, 0x74, 0xc2, 0x10, 0x07, 0xb2, 0x98, 0x93, 0x9f, 0xb4,
-	0x35, 0x93, 0xe3, 0x00, 0x12, 0x35, 0xfc, 0x99, 0xc6, 0x44, 0xa2, 0xf0, 0x86, 0x9f, 0x55, 0x03,
-	0x27, 0xb0, 0xb0, 0xe3, 0x76, 0xbb, 0xaa, 0x28, 0xb7, 0x40, 0x1f, 0xe0, 0x93, 0x56, 0xfe, 0x05,
-	0xaa, 0x03, 0x7c, 0xc2, 0x66, 0xd9, 0xb7, 0x40, 0xf7, 0xbd, 0x0e, 0xc7, 0xca, 0x98, 0xb2, 0xea,
-	0x7b, 0x1d, 0x86, 0x55, 0x87, 0x2a, 0xe9, 0x3b, 0x9e, 0xe7, 0x9f, 0x08, 0x63, 0xca, 0x4f, 0xeb,
-	0x6b, 0x30, 0xe3, 0x83, 0xe3, 0x8e, 0x4a, 0x9e, 0x4c, 0xc6, 0x08, 0x2e, 0x8e, 0x67, 0x97, 0x94,
-	0xe7, 0xcb, 0xd8, 0x48, 0xe3, 0x0a, 0x21, 0x88, 0xb5, 0x2e, 0xbb, 0xaf, 0x73, 0xd8, 0xe8, 0x3a,
-	0xd4, 0xf6, 0x08, 0xed, 0xa5, 0x39, 0xb6, 0x09, 0xc5, 0xae, 0xfb, 0x4a, 0x04, 0x27, 0x5d, 0x5a,
-	0x1f, 0xc1, 0x2c, 0x47, 0x10, 0xc2, 0x2b, 0x18, 0x06, 0xc3, 0x60, 0x55, 0x6d, 0x10, 0xf8, 0x51,
-	0x33, 0xcc, 0x3e, 0xac, 0x3e, 0x98, 0x07, 0xa3, 0x50, 0xd4, 0xc7, 0x82, 0x7b, 0x94, 0xde, 0x35,
-	0x35, 0xbd, 0xbf, 0x01, 0xa5, 0xd0, 0xe9, 0xc9, 0xdb, 0xe9, 0x4c, 0xc2, 0x43, 0xa7, 0x67, 0xb3,
-	0xdd, 0x78, 0x10, 0x51, 0x1c, 0x33, 0x88, 0xb0, 0xba, 0xb2, 0xd0, 0x4b, 0x1e, 0xf6, 0x1f, 0x9f,
-	0x35, 0xfc, 0x42, 0x83, 0xc5, 0xc7, 0x58, 0x5c, 0x89, 0x28, 0x25, 0x89, 0x9c, 0xea, 0x68, 0x67,
-	0x4c, 0x75, 0xf2, 0x5e, 0xdd, 0xd2, 0xa4, 0x57, 0x37, 0xd1, 0x3c, 0x5c, 0x05, 0x60, 0xd3, 0xb3,
-	0x16, 0xdd, 0x12, 0x75, 0xb4, 0xc1, 0x76, 0x9a, 0xee, 0xb7, 0xd8, 0xda, 0x87, 0x85, 0x83, 0x51,
-	0x28, 0xc4, 0xe6, 0xa2, 0x4d, 0x9e, 0xe1, 0x44, 0x06, 0x29, 0x28, 0x06, 0xb1, 0x36, 0x60, 0xe1,
-	0x31, 0x3e, 0x27, 0x2b, 0xeb, 0x57, 0x1a, 0x98, 0x92, 0x2a, 0x52, 0x4e, 0x62, 0x96, 0xa5, 0x4d,
-	0x98, 0x65, 0xfd, 0xd7, 0x55, 0x84, 0xf8, 0x74, 0x43, 0xbd, 0x98, 0xf5, 0x05, 0x98, 0x87, 0x4e,
-	0xef, 0x7b, 0x78, 0xce, 0x99, 0x5e, 0x6b, 0x2d, 0x01, 0xa2, 0x47, 0x25, 0x7d, 0x85, 0x26, 0x44,
-	0xba, 0x7b, 0xe8, 0xf4, 0x22, 0x0d, 0x2d, 0x43, 0x85, 0xcf, 0xa9, 0x44, 0x44, 0x89, 0x2f, 0x5a,
-	0x3b, 0xb8, 0x83, 0xb6, 0x37, 0xea, 0xe0, 0x96, 0x90, 0x85, 0x67, 0xe9, 0x39, 0xb1, 0xcb, 0x39,
-	0x5b, 0x4d, 0x7e, 0x25, 0xce, 0x51, 0x44, 0x68, 0x03, 0x8a, 0xa1, 0xd3, 0x13, 0xb2, 0xc7, 0x82,
-	0xd1, 0x4d, 0xe5, 0x6a, 0x85, 0xb1, 0x57, 0xb3, 0x3e, 0x81, 0x25, 0x9e, 0x47, 0xbe, 0x97, 0xab,
-	0x5b, 0x97, 0xe0, 0x62, 0x8a, 0x9c, 0x0b, 0x66, 0x7d, 0x20, 0xf3, 0x93, 0xaa, 0x00, 0xa9, 0x47,
-	0x6d, 0x9c, 0x1e, 0x55, 0x12, 0xc1, 0xe8, 0x3e, 0xa0, 0xed, 0x3e, 0x6e, 0xbf, 0x38, 0xbf, 0xd9,
-	0xac, 0xf7, 0xe1, 0x42, 0x82, 0x54, 0xe8, 0x6c, 0x19, 0x2a, 0xf8, 0x95, 0x4b, 0x42, 0x22, 0x52,
-	0x9f, 0xf8, 0xb2, 0xd6, 0xa0, 0x2a, 0x6e, 0x31, 0xed, 0xed, 0x7f, 0x5a, 0x80, 0x9a, 0x9c, 0x78,
-	0xd2, 0xd2, 0xf8, 0x6e, 0x9a, 0xec, 0xaa, 0x42, 0xc6, 0x50, 0xc4, 0x9a, 0xec, 0x0e, 0xc2, 0xe0,
-	0x34, 0xce, 0x18, 0xab, 0x09, 0x07, 0x6b, 0x64, 0xa8, 0xa8, 0x46, 0x38, 0x09, 0xc3, 0x6b, 0xec,
-	0xc3, 0xac, 0xca, 0x88, 0x26, 0xea, 0x17, 0xf8, 0x54, 0x26, 0xea, 0x17, 0xf8, 0x14, 0xdd, 0x54,
-	0xa3, 0x3d, 0x13, 0x89, 0x1c, 0xf6, 0xa0, 0x70, 0x4f, 0x6b, 0xec, 0x80, 0x11, 0x71, 0xcf, 0xe1,
-	0xf3, 0x66, 0x92, 0x4f, 0x72, 0x5a, 0x12, 0x71, 0x59, 0x59, 0x01, 0x88, 0x7f, 0x29, 0x88, 0x74,
-	0x28, 0x7d, 0xd1, 0xdc, 0xb5, 0xcd, 0x19, 0xba, 0x7a, 0xf4, 0xc5, 0xe1, 0x73, 0x53, 0xa3, 0xab,
-	0xbd, 0xe6, 0xf6, 0xe7, 0x66, 0x61, 0xe5, 0x5d, 0x3e, 0xe7, 0x67, 0xc3, 0xf9, 0x59, 0xd0, 0xed,
-	0xdd, 0xe6, 0xae, 0xfd, 0xe5, 0xee, 0x0e, 0xc7, 0xde, 0xdb, 0x7f, 0xba, 0x6b, 0x6a, 0xa8, 0x0a,
-	0xc5, 0x9d, 0x7d, 0xdb, 0x2c, 0xac, 0x6c, 0xc8, 0xd9, 0x00, 0x6b, 0x84, 0x50, 0x0d, 0xaa, 0xcd,
-	0xc3, 0x47, 0xf6, 0x21, 0x43, 0x37, 0xa0, 0x6c, 0xef, 0x3e, 0xda, 0xf9, 0xa1, 0xa9, 0x51, 0x3e,
-	0x7b, 0xfb, 0xcf, 0xf6, 0x9b, 0x9f, 0xed, 0xee, 0x98, 0x85, 0x95, 0x87, 0x60, 0x44, 0xe5, 0x3f,
-	0x65, 0xfa, 0xec, 0xf9, 0xb3, 0x5d, 0xce, 0xfe, 0x49, 0xf3, 0xf9, 0x33, 0x2e, 0xcc, 0xd3, 0xfd,
-	0x67, 0xbb, 0x66, 0x81, 0x1e, 0xd4, 0xfc, 0xff, 0xa7, 0x66, 0x91, 0x2e, 0xb6, 0x9b, 0x5f, 0x9a,
-	0xa5, 0xf5, 0x5f, 0xce, 0x43, 0xf1, 0xd1, 0xc1, 0x3e, 0xfa, 0x14, 0x20, 0x9e, 0xf0, 0xa2, 0x65,
-	0x5e, 0xbc, 0xa4, 0x47, 0xbe, 0x8d, 0xe5, 0xcc, 0xef, 0x0a, 0x76, 0xd9, 0xa0, 0x67, 0x06, 0xdd,
-	0x85, 0x9a, 0x32, 0xad, 0x45, 0x97, 0x18, 0x83, 0xec, 0xfc, 0xb6, 0x91, 0x1c, 0xb0, 0x5a, 0x33,
-	0xe8, 0x3e, 0xe8, 0x72, 0x30, 0x8b, 0x96, 0x18, 0x30, 0x35, 0xc0, 0x6d, 0x5c, 0x4c, 0xed, 0x8a,
-	0x50, 0x99, 0xa1, 0x32, 0xc7, 0x33, 0x59, 0x21, 0x73, 0x66, 0x48, 0x7b, 0x86, 0xcc, 0x1f, 0x42,
-	0x4d, 0x19, 0xbb, 0x0a, 0x99, 0xb3, 0x83, 0xd8, 0x86, 0x5a, 0xca, 0x59, 0x33, 0x68, 0x0b, 0x66,
-	0xd5, 0x89, 0x1e, 0xaa, 0x8b, 0xca, 0x23, 0x33, 0xe4, 0x3b, 0xe3, 0xe8, 0x4f, 0x60, 0x2e, 0x31,
-	0x19, 0x43, 0x97, 0x55, 0x85, 0x25, 0xb9, 0xa4, 0x87, 0x41, 0xd6, 0x0c, 0xba, 0x07, 0x10, 0xcf,
-	0xb9, 0xc4, 0xcd, 0x33, 0x83, 0xaf, 0x86, 0x99, 0x22, 0x24, 0xd6, 0x0c, 0xda, 0xe4, 0x69, 0x55,
-	0x7a, 0x59, 0x80, 0x9d, 0xe3, 0xb1, 0xf4, 0xd9, 0x83, 0xd7, 0x34, 0x7a, 0x7b, 0x75, 0xf4, 0x21,
-	0x6e, 0x9f, 0x33, 0x0d, 0x39, 0xe3, 0xf6, 0x0f, 0xa1, 0xa6, 0x8c, 0x40, 0x84, 0xe2, 0xb3, 0x43,
-	0x91, 0x7c, 0x01, 0xb6, 0x61, 0x21, 0x35, 0xdb, 0x40, 0x57, 0xb8, 0xe5, 0x72, 0x27, 0x1e, 0xf9,
-	0x4c, 0x3e, 0x84, 0x9a, 0x32, 0xbe, 0x16, 0x12, 0x64, 0x07, 0xda, 0x39, 0xa6, 0x57, 0x27, 0x6f,
-	0xe2, 0xf2, 0x39, 0xc3, 0xb8, 0xa9, 0x4c, 0x2f, 0x98, 0x24, 0x4c, 0x9f, 0xe4, 0x92, 0xfe, 0xa3,
-	0x94, 0xd8, 0xf4, 0x82, 0x36, 0x36, 0x5d, 0x92, 0xd0, 0x4c, 0x11, 0x12, 0x2e, 0xbc, 0x3a, 0x06,
-	0x4b, 0x58, 0x6e, 0x5a, 0xe1, 0x1f, 0x40, 0x55, 0xf4, 0x90, 0xe8, 0x42, 0xb2, 0xa3, 0x9c, 0x40,
-	0x79, 0x5b, 0x43, 0x0f, 0x40, 0x97, 0x6d, 0xa6, 0x88, 0xf4, 0x54, 0xd7, 0x79, 0xc6, 0xb9, 0x9b,
-	0x50, 0x15, 0xb3, 0x20, 0x71, 0x6e, 0x72, 0x32, 0xd4, 0xb8, 0x92, 0xa1, 0x64, 0x75, 0xd3, 0x97,
-	0xac, 0xea, 0xa3, 0x06, 0x8f, 0xf3, 0x13, 0x63, 0x92, 0xc8, 0x4f, 0x2a, 0xa3, 0x64, 0x0b, 0x62,
-	0xcd, 0xa0, 0x75, 0x9e, 0x9f, 0x14, 0xa9, 0x53, 0xbd, 0x68, 0x63, 0x3e, 0x41, 0x42, 0x58, 0x4e,
-	0x9b, 0x97, 0x48, 0x22, 0xc4, 0xf2, 0x29, 0xd3, 0x87, 0xad, 0x69, 0x68, 0x03, 0x74, 0xd9, 0x8b,
-	0x0a, 0xa2, 0x54, 0x6b, 0x9a, 0x47, 0xb4, 0x0e, 0xba, 0x6c, 0x47, 0x05, 0x51, 0xaa, 0x3b, 0xcd,
-	0x97, 0x51, 0x22, 0x25, 0x64, 0x4c, 0x53, 0xe6, 0x1c, 0x77, 0x1f, 0x74, 0xd9, 0xf9, 0x09, 0xa2,
-	0x54, 0x07, 0x2a, 0x52, 0x76, 0xba, 0x3d, 0x54, 0x53, 0x36, 0x23, 0x56, 0x53, 0xf6, 0x74, 0x7e,
-	0xf0, 0x09, 0x7b, 0xeb, 0x70, 0x88, 0x1f, 0x79, 0x1e, 0x1a, 0x83, 0x76, 0x06, 0xf9, 0x1d, 0x28,
-	0xd1, 0x96, 0x0f, 0xf1, 0xf0, 0x50, 0xda, 0xc3, 0xc6, 0xa2, 0xb2, 0x23, 0xa5, 0x5d, 0xd3, 0xd6,
-	0xff, 0x6a, 0x80, 0xc1, 0xdf, 0x7f, 0xfa, 0x48, 0x6e, 0x80, 0x11, 0x75, 0x7e, 0xe8, 0xa2, 0xf4,
-	0xff, 0x44, 0xad, 0xd6, 0x50, 0x6b, 0x06, 0xe6, 0xf6, 0xf7, 0xd9, 0xa4, 0x88, 0x6f, 0x34, 0xd9,
-	0x4c, 0x68, 0x0c, 0xe5, 0xac, 0x42, 0x49, 0x18, 0xe9, 0x26, 0x40, 0x84, 0x45, 0xc6, 0x91, 0x9d,
-	0x15, 0x72, 0x51, 0xbe, 0x12, 0x32, 0xab, 0xf9, 0x6a, 0x4a, 0x2e, 0xe8, 0x3e, 0x18, 0x51, 0x6f,
-	0x88, 0xd4, 0xdb, 0x4d, 0x0e, 0xba, 0x5d, 0x80, 0xb8, 0xad, 0x14, 0xd6, 0xce, 0xf4, 0x99, 0x93,
-	0xd9, 0x7c, 0x0c, 0xba, 0x6c, 0x00, 0x85, 0xbf, 0xa5, 0xfa, 0xc1, 0x33, 0x75, 0xf0, 0x08, 0x74,
-	0xd9, 0xbd, 0x49, 0x17, 0x4f, 0xb6, 0x80, 0x93, 0x05, 0xd8, 0x66, 0x2a, 0xe0, 0x0d, 0xa0, 0x30,
-	0x43, 0xba, 0x21, 0x9c, 0xcc, 0x64, 0x1d, 0x8c, 0xa8, 0x47, 0x43, 0x71, 0x4d, 0x93, 0x90, 0x44,
-	0xe9, 0x3e, 0xc5, 0xcd, 0x8d, 0xa8, 0x87, 0x13, 0x34, 0xe9, 0x9e, 0xee, 0x4c, 0x6f, 0x97, 0x2f,
-	0x4d, 0x9e, 0xf5, 0x16, 0x12, 0x75, 0x37, 0xcb, 0x75, 0x5b, 0x50, 0x53, 0x5a, 0x08, 0x91, 0x24,
-	0xb3, 0xfd, 0x48, 0xa3, 0x9e, 0x05, 0x44, 0x11, 0xfe, 0x10, 0x6a, 0x4a, 0x7f, 0x28, 0x78, 0x64,
-	0x3b, 0xc6, 0x9c, 0xe3, 0xd7, 0x34, 0xf4, 0x19, 0xcc, 0x25, 0x1a, 0x2c, 0xf1, 0x36, 0xe6, 0xf5,
-	0x6c, 0x8d, 0x46, 0x1e, 0x28, 0x12, 0x63, 0x03, 0x2a, 0x8f, 0x31, 0xed, 0x1e, 0x51, 0xd4, 0x78,
-	0x4d, 0x36, 0xd1, 0x3b, 0x00, 0x42, 0x61, 0x49, 0xc2, 0x1c, 0x55, 0x3d, 0xe4, 0xcf, 0x02, 0x6d,
-	0x26, 0x94, 0xe4, 0xae, 0xb4, 0x7f, 0x4a, 0xd9, 0x9a, 0xe8, 0xf0, 0xe8, 0x39, 0x9b, 0x32, 0x0b,
-	0x32, 0x72, 0x35, 0x0b, 0xaa, 0x0c, 0x2e, 0x65, 0xf6, 0x15, 0x25, 0x57, 0xb7, 0xfd, 0xe3, 0xa1,
-	0xd3, 0x0e, 0xcf, 0x9f, 0x04, 0xb7, 0x36, 0xff, 0xf8, 0xfa, 0x9a, 0xf6, 0x97, 0xd7, 0xd7, 0xb4,
-	0xbf, 0xbf, 0xbe, 0xa6, 0xfd, 0xfc, 0x1f, 0xd7, 0x66, 0xbe, 0x7a, 0xbf, 0xe7, 0x86, 0xfd, 0xd1,
-	0xd1, 0x6a, 0xdb, 0x3f, 0xbe, 0x33, 0x74, 0xda, 0xfd, 0xd3, 0x0e, 0x0e, 0xd4, 0x15, 0x09, 0xda,
-	0x77, 0xe2, 0xbf, 0x9f, 0x3e, 0xaa, 0x30, 0x96, 0x1b, 0xff, 0x0e, 0x00, 0x00, 0xff, 0xff, 0xde,
-	0x8e, 0x62, 0x88, 0x54, 0x2d, 0x00, 0x00,
+	// 4473 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x7b, 0xcd, 0x6f, 0x1b, 0x49,
+	0x76, 0xb8, 0x9a, 0x9f, 0xdd, 0x8f, 0x14, 0xd9, 0x2a, 0xcb, 0x36, 0x87, 0x9e, 0xb1, 0x35, 0xed,
+	0xf1, 0x8c, 0xad, 0x19, 0xcb, 0x5e, 0x7b, 0xec, 0x19, 0xdb, 0x3b, 0xe3, 0x95, 0x48, 0x4a, 0x96,
+	0xc7, 0x63, 0x6b, 0x9b, 0xb2, 0x7f, 0xf8, 0x2d, 0x12, 0x10, 0x2d, 0xb2, 0x48, 0xf5, 0xba, 0xc9,
+	0xe6, 0x76, 0x37, 0x6d, 0x6b, 0x8f, 0xb9, 0xec, 0x29, 0x97, 0x00, 0x01, 0x02, 0xe4, 0x92, 0x00,
+	0x41, 0x8e, 0x41, 0x02, 0xe4, 0x8f, 0x08, 0x02, 0x04, 0xc8, 0x21, 0xa7, 0x04, 0x58, 0x04, 0xce,
+	0x29, 0xf7, 0x9c, 0x72, 0x08, 0x82, 0xfa, 0xea, 0xae, 0xfe, 0xa0, 0x48, 0x79, 0x37, 0x87, 0x19,
+	0x75, 0xd5, 0xfb, 0xa8, 0x57, 0xaf, 0x5e, 0xbd, 0xaf, 0xa2, 0x61, 0xbd, 0xef, 0xd8, 0x78, 0x12,
+	0xdc, 0x9a, 0x0e, 0x7d, 0xf2, 0xdf, 0xd6, 0xd4, 0x73, 0x03, 0x17, 0xe5, 0xa7, 0x43, 0xbf, 0x79,
+	0x79, 0xe4, 0xba, 0x23, 0x07, 0xdf, 0xa2, 0x53, 0x47, 0xb3, 0xe1, 0xad, 0xc1, 0xcc, 0xb3, 0x02,
+	0xdb, 0x9d, 0x30, 0xa4, 0xe6, 0xa5, 0x24, 0x1c, 0x8f, 0xa7, 0xc1, 0x09, 0x07, 0x5e, 0x49, 0x02,
+	0x03, 0x7b, 0x8c, 0xfd, 0xc0, 0x1a, 0x4f, 0x39, 0x42, 0x8a, 0xfb, 0x5b, 0xcf, 0x9a, 0x4e, 0xb1,
+	0xc7, 0x45, 0x68, 0xae, 0x8f, 0xdc, 0x91, 0x4b, 0x3f, 0x6f, 0x91, 0x2f, 0x3e, 0x7b, 0x81, 0x8b,
+	0x6b, 0xcd, 0x82, 0x63, 0xfa, 0x3f, 0x36, 0x6f, 0x34, 0xa1, 0x60, 0xe2, 0xa9, 0x8b, 0x10, 0x14,
+	0x26, 0xd6, 0x18, 0x37, 0x94, 0x0d, 0xe5, 0xba, 0x66, 0xd2, 0x6f, 0xe3, 0x11, 0x94, 0x76, 0x3c,
+	0x6b, 0xd2, 0x3f, 0x46, 0x9f, 0x40, 0xc1, 0xc3, 0x53, 0x97, 0x42, 0x2b, 0x77, 0xb4, 0x2d, 0xb2,
+	0x61, 0x42, 0x66, 0x16, 0x3c, 0x99, 0x38, 0x27, 0x11, 0xff, 0x5d, 0x0e, 0x80, 0x51, 0xef, 0x4f,
+	0x86, 0x2e, 0xba, 0x0a, 0xa5, 0x23, 0x3a, 0x6a, 0x14, 0x28, 0x8f, 0x0a, 0xe5, 0xc1, 0x10, 0x4c,
+	0x0e, 0x42, 0x57, 0xa0, 0x70, 0x8c, 0xad, 0x41, 0x23, 0x27, 0xa1, 0xb4, 0xdc, 0xf1, 0xd8, 0x0e,
+	0x4c, 0x0a, 0x40, 0x5f, 0x02, 0x4c, 0x3d, 0xf7, 0x0d, 0x9e, 0x58, 0x93, 0x3e, 0x6e, 0xe4, 0x37,
+	0xf2, 0x49, 0x4e, 0x12, 0x98, 0x20, 0xfb, 0xb3, 0x23, 0x81, 0x5c, 0xcc, 0x40, 0x8e, 0xc0, 0xe8,
+	0x5b, 0x58, 0x1b, 0xd8, 0x1e, 0xee, 0x07, 0x3d, 0x69, 0x81, 0x52, 0x9a, 0x46, 0x67, 0x58, 0x07,
+	0xd1, 0x32, 0xf7, 0xa8, 0x4c, 0x01, 0xee, 0x93, 0x13, 0x6e, 0x94, 0xa9, 0xe8, 0xe7, 0x25, 0x92,
+	0x83, 0x10, 0x68, 0x4a, 0x88, 0x99, 0x0a, 0x9f, 0x82, 0x9e, 0xa4, 0x41, 0x37, 0x01, 0x79, 0xf8,
+	0x57, 0x33, 0xdb, 0xc3, 0xb2, 0x64, 0x84, 0x4a, 0x35, 0xd7, 0x38, 0x44, 0x92, 0xe6, 0x26, 0x20,
+	0xcb, 0x71, 0xdc, 0xb7, 0x78, 0xd0, 0xeb, 0x53, 0xcd, 0x05, 0xd8, 0xf3, 0x1b, 0xb9, 0x8d, 0xfc,
+	0x75, 0xcd, 0x5c, 0xe3, 0x90, 0x56, 0x08, 0x30, 0x1e, 0x43, 0x25, 0x3a, 0x24, 0x1f, 0xdd, 0x86,
+	0x0a, 0x3b, 0x8a, 0x9e, 0x3d, 0x19, 0x92, 0xe3, 0x26, 0xfb, 0xaf, 0x4b, 0x9b, 0x21, 0x68, 0x26,
+	0x1c, 0x85, 0xdf, 0xc6, 0x63, 0x28, 0xec, 0xda, 0x0e, 0x26, 0xe7, 0xcb, 0xd6, 0xe3, 0x36, 0x12,
+	0x3b, 0x3c, 0x0e, 0x22, 0x7b, 0x9e, 0x5a, 0xc1, 0xb1, 0xb0, 0x13, 0xf2, 0x6d, 0x5c, 0x82, 0xe2,
+	0x8e, 0xe3, 0xf6, 0x5f, 0x13, 0xe0, 0xb1, 0xe5, 0x1f, 0x0b, 0x85, 0x90, 0x6f, 0xe3, 0x63, 0x28,
+	0xbd, 0x38, 0xfa, 0x25, 0xee, 0x07, 0x99, 0xd0, 0x8f, 0x20, 0x7f, 0x68, 0x8d, 0x32, 0x35, 0xf9,
+	0x37, 0x79, 0x50, 0x89, 0x81, 0x52, 0xdb, 0x5b, 0x60, 0xbd, 0x5f, 0x43, 0xb9, 0xef, 0x61, 0x2b,
+	0xc0, 0xc2, 0xf0, 0x9a, 0x5b, 0xec, 0x8a, 0x6d, 0x89, 0x2b, 0xb6, 0x75, 0x28, 0xee, 0xa0, 0x29,
+	0x50, 0xd1, 0x27, 0x00, 0xbe, 0xfd, 0x6b, 0xdc, 0x3b, 0x3a, 0x09, 0xb0, 0xdf, 0xc8, 0x6f, 0x28,
+	0xd7, 0x0b, 0xa6, 0x46, 0x66, 0x76, 0xc8, 0x04, 0xda, 0x80, 0xca, 0x00, 0xfb, 0x7d, 0xcf, 0x9e,
+	0x52, 0xb3, 0x28, 0x52, 0xd9, 0xe4, 0x29, 0xf4, 0x05, 0xa8, 0x4c, 0x8f, 0xd8, 0x6f, 0x94, 0xd3,
+	0x86, 0x16, 0x02, 0xd1, 0x0d, 0xd0, 0xed, 0xc9, 0x00, 0xbf, 0xeb, 0xe1, 0x77, 0x81, 0x67, 0xf5,
+	0x03, 0xd7, 0xf3, 0x1b, 0x2a, 0x3d, 0xd0, 0x3a, 0x9d, 0xef, 0x84, 0xd3, 0xe8, 0x01, 0xd4, 0xfc,
+	0xc0, 0xf5, 0xac, 0x11, 0xee, 0x4d, 0x5d, 0xc7, 0xee, 0x9f, 0x34, 0x34, 0xba, 0x23, 0x44, 0x39,
+	0x77, 0x19, 0xe8, 0x80, 0x42, 0xcc, 0x55, 0x5f, 0x1e, 0xa2, 0x3d, 0x00, 0x76, 0x4a, 0xbd, 0x20,
+	0x70, 0x1a, 0x40, 0xc9, 0x3e, 0x4a, 0x29, 0xa2, 0xcd, 0x3d, 0xd9, 0xce, 0xea, 0xfb, 0xdf, 0x5e,
+	0xd1, 0xd8, 0xf1, 0x1e, 0x1e, 0x3e, 0x33, 0x35, 0x46, 0x7b, 0x18, 0x38, 0x68, 0x0b, 0x34, 0xe2,
+	0x5f, 0x98, 0x05, 0x95, 0x28, 0x9f, 0xb5, 0x50, 0xe5, 0xdb, 0xb3, 0x80, 0xd9, 0x90, 0x6a, 0xf1,
+	0xaf, 0xa7, 0x05, 0xb5, 0xa0, 0x17, 0x8d, 0x3d, 0x58, 0x8d, 0x89, 0x87, 0xee, 0x83, 0x10, 0xb0,
+	0xd7, 0x77, 0x2c, 0xdf, 0xa7, 0xa7, 0x57, 0xe3, 0xac, 0x38, 0x6a, 0x8b, 0x00, 0xcc, 0xaa, 0x2f,
+	0x8d, 0x8c, 0xef, 0xa1, 0x2a, 0x2f, 0x84, 0xb6, 0xa0, 0x6a, 0xf5, 0xfb, 0xd8, 0xf7, 0x7b, 0x0e,
+	0x7e, 0x83, 0x1d, 0xce, 0xa6, 0xb2, 0x45, 0x7d, 0x60, 0xb7, 0xef, 0x4e, 0xb1, 0x59, 0x61, 0x08,
+	0xcf, 0x08, 0xdc, 0xb8, 0x0b, 0x55, 0xb6, 0xad, 0x17, 0x9e, 0x3d, 0xb2, 0x27, 0xe8, 0x2a, 0x14,
+	0x5e, 0xdb, 0x93, 0x01, 0xa7, 0x63, 0x77, 0x81, 0x81, 0x7e, 0xb0, 0x27, 0x03, 0x93, 0x02, 0x8d,
+	0xc7, 0x50, 0x62, 0x44, 0x8b, 0x6c, 0xed, 0x02, 0xe4, 0x6c, 0x66, 0x66, 0xda, 0x4e, 0xe9, 0xfd,
+	0x6f, 0xaf, 0xe4, 0xf6, 0xdb, 0x66, 0xce, 0x1e, 0x18, 0x5d, 0xa8, 0xf0, 0xbb, 0x62, 0x4d, 0x46,
+	0x18, 0x7d, 0x0a, 0x45, 0x72, 0x53, 0xbd, 0xac, 0xcb, 0xc4, 0x20, 0x04, 0x65, 0x46, 0xdc, 0x7e,
+	0x96, 0xb3, 0x64, 0x10, 0xe3, 0x0f, 0x40, 0x67, 0x13, 0x92, 0x7f, 0x58, 0xea, 0x9e, 0x46, 0xce,
+	0x3a, 0x37, 0xd7, 0x59, 0x1b, 0xff, 0x54, 0x02, 0x60, 0x74, 0xc2, 0xc1, 0x9f, 0x85, 0x71, 0x7d,
+	0x7e, 0x14, 0xb8, 0x01, 0x25, 0x97, 0x2a, 0xb8, 0xb1, 0x26, 0x59, 0x8f, 0x7c, 0x28, 0x26, 0x47,
+	0x48, 0xde, 0x32, 0x35, 0x7d, 0xcb, 0x6e, 0xc3, 0xea, 0xd4, 0xf2, 0xf0, 0x24, 0xe0, 0xee, 0x30,
+	0x4b, 0x5d, 0x55, 0x86, 0xc1, 0x46, 0x84, 0xa2, 0x7f, 0x6c, 0x3b, 0xc2, 0x7f, 0xfa, 0x8d, 0x8a,
+	0x74, 0x39, 0x05, 0x05, 0xc5, 0x60, 0x03, 0x9f, 0x38, 0x10, 0x3f, 0xb0, 0x3c, 0xe2, 0x40, 0xf2,
+	0x8b, 0x1d, 0x08, 0x47, 0x45, 0xf7, 0x41, 0x1d, 0xda, 0x13, 0xdb, 0x3f, 0xc6, 0x83, 0x46, 0x61,
+	0x21, 0x59, 0x88, 0x9b, 0x70, 0x3c, 0xc5, 0xa4, 0xe3, 0xb9, 0x17, 0x0b, 0x91, 0xfa, 0x46, 0x3e,
+	0x0c, 0x47, 0x49, 0x5b, 0x88, 0x05, 0xcb, 0x1b, 0xa0, 0x7b, 0xd8, 0x1a, 0x9c, 0xc8, 0x41, 0xa6,
+	0xba, 0xa1, 0x5c, 0xcf, 0x9b, 0x75, 0x3a, 0x1f, 0x91, 0xa1, 0xdb, 0xb1, 0xb8, 0xaa, 0xd1, 0x15,
+	0x74, 0x59, 0x3b, 0xc4, 0x84, 0x63, 0xc1, 0xf5, 0x0a, 0x14, 0x02, 0x0f, 0x63, 0x1e, 0x1c, 0x99,
+	0x26, 0x99, 0x5f, 0x37, 0x29, 0x80, 0x18, 0x33, 0xf9, 0xeb, 0x37, 0x56, 0x37, 0xf2, 0x49, 0x0c,
+	0x06, 0x21, 0xa6, 0x33, 0xb0, 0x82, 0xd9, 0xd8, 0x6f, 0xd4, 0xd2, 0x5c, 0x38, 0x08, 0x3d, 0x84,
+	0x8f, 0xc4, 0xb2, 0xe2, 0xc0, 0xfd, 0x9e, 0x3f, 0xa3, 0xd7, 0xbb, 0x81, 0xe8, 0x76, 0x2e, 0x86,
+	0x08, 0xfc, 0xf8, 0xba, 0x0c, 0x9c, 0x4d, 0x3b, 0xb4, 0x6c, 0x67, 0xe6, 0xe1, 0xc6, 0xb9, 0x6c,
+	0xda, 0x5d, 0x06, 0x46, 0xf7, 0xe1, 0x62, 0x9a, 0x36, 0x70, 0x03, 0xcb, 0x69, 0xac, 0x53, 0xca,
+	0xf3, 0x49, 0xca, 0x43, 0x02, 0x7c, 0x5a, 0x50, 0x4b, 0x7a, 0xf9, 0x69, 0x41, 0x05, 0xbd, 0x62,
+	0xfc, 0x4f, 0x0e, 0x54, 0x12, 0x4a, 0x45, 0xc8, 0x1a, 0xda, 0x0e, 0x8e, 0xb9, 0x11, 0x02, 0x34,
+	0xe9, 0x34, 0xda, 0x04, 0x8d, 0xfc, 0xed, 0x05, 0x27, 0x53, 0x96, 0x75, 0xd5, 0xee, 0xac, 0x86,
+	0x38, 0x87, 0x27, 0x53, 0x4c, 0xec, 0x85, 0x7d, 0x2d, 0x0a, 0x54, 0xdf, 0x82, 0x26, 0x12, 0x85,
+	0x41, 0x03, 0x16, 0xda, 0x61, 0x84, 0x8c, 0x9a, 0xa0, 0xd2, 0x6b, 0xe0, 0xe1, 0x09, 0xcd, 0x94,
+	0x34, 0x33, 0x1c, 0xa3, 0x6b, 0x50, 0x76, 0xe9, 0xd1, 0xb0, 0x50, 0x95, 0x38, 0x2e, 0x01, 0x43,
+	0x5f, 0x82, 0x76, 0x44, 0x82, 0xbf, 0x89, 0x87, 0x3e, 0xb7, 0x24, 0xb6, 0x8f, 0x1d, 0x3e, 0x6b,
+	0x46, 0xf0, 0x30, 0x05, 0x20, 0x56, 0x54, 0x65, 0x29, 0x00, 0x61, 0xd0, 0x3f, 0xc6, 0xfd, 0xd7,
+	0xfe, 0x6c, 0x2c, 0x2e, 0x2a, 0x63, 0xd0, 0xe2, 0xb3, 0x66, 0x04, 0x27, 0x9a, 0xa0, 0x5a, 0xeb,
+	0xbb, 0xb3, 0x49, 0xc0, 0xad, 0x9b, 0xea, 0xb1, 0x45, 0x26, 0x8c, 0x57, 0xa0, 0x0a, 0x2a, 0xf4,
+	0x35, 0x68, 0x96, 0x33, 0x72, 0x3d, 0x3b, 0x38, 0x1e, 0x73, 0xd7, 0x7f, 0x21, 0xc6, 0x77, 0x5b,
+	0x40, 0xcd, 0x08, 0x11, 0xad, 0x43, 0xf1, 0x8d, 0xe5, 0xcc, 0xd8, 0x91, 0x54, 0x4d, 0x36, 0x30,
+	0xbe, 0x01, 0x8d, 0xa8, 0x9a, 0x79, 0xf6, 0x75, 0xd9, 0xb3, 0x17, 0x84, 0x33, 0x5f, 0x97, 0x9d,
+	0x79, 0x41, 0xf8, 0x6f, 0x13, 0x54, 0xa1, 0x07, 0xb4, 0x01, 0x45, 0xaa, 0x09, 0x6e, 0x11, 0x20,
+	0x69, 0x89, 0x01, 0xd0, 0x67, 0x50, 0xf4, 0xc8, 0x12, 0xdc, 0xc3, 0xd5, 0x18, 0x86, 0x58, 0xd8,
+	0x64, 0x40, 0xe3, 0x0f, 0x01, 0xd8, 0x21, 0x08, 0xa7, 0xcd, 0x8e, 0x22, 0xe6, 0xb4, 0xc5, 0xa5,
+	0x62, 0x20, 0x62, 0x6c, 0x74, 0x85, 0x9e, 0x87, 0x87, 0x9c, 0x79, 0xe2, 0x90, 0x54, 0x71, 0x48,
+	0xc6, 0x55, 0x28, 0xfe, 0x88, 0xbd, 0x11, 0x26, 0xc6, 0x31, 0xf5, 0xf0, 0xd0, 0x7e, 0x87, 0x7d,
+	0x9a, 0x46, 0x6a, 0x66, 0x38, 0x36, 0x6e, 0x42, 0xb1, 0x7b, 0x6c, 0x79, 0x83, 0x48, 0x64, 0x45,
+	0x12, 0xf9, 0xc0, 0x0a, 0x8e, 0x63, 0x22, 0x7f, 0x03, 0x5a, 0x38, 0x17, 0xd7, 0x9f, 0x96, 0xa9,
+	0x3f, 0x4d, 0xe8, 0xef, 0x2f, 0x73, 0xb0, 0xd6, 0xa2, 0xe9, 0x1a, 0x8d, 0xc0, 0xf8, 0x57, 0x33,
+	0xec, 0x2f, 0x8c, 0xd0, 0x89, 0x90, 0x92, 0x4f, 0x87, 0x94, 0x0b, 0x50, 0x9a, 0x4d, 0x07, 0x56,
+	0x80, 0xa9, 0xdb, 0x56, 0x4d, 0x3e, 0xca, 0xcc, 0xd3, 0x8a, 0xcb, 0xe6, 0x69, 0xa5, 0x0f, 0xcb,
+	0xd3, 0xca, 0x1f, 0x9c, 0xa7, 0x3d, 0x2d, 0xa8, 0x39, 0x3d, 0x6f, 0xdc, 0x05, 0xb4, 0x3f, 0xf1,
+	0xa7, 0xe4, 0xbc, 0x97, 0xd6, 0x91, 0x71, 0x11, 0xea, 0xcf, 0x6c, 0x5f, 0xa6, 0x78, 0x5a, 0x50,
+	0x15, 0x3d, 0x67, 0x7c, 0x0f, 0x7a, 0x04, 0xf0, 0xa7, 0xee, 0xc4, 0xa7, 0xbe, 0x8a, 0x10, 0xc9,
+	0x15, 0xc5, 0x6a, 0xc8, 0x90, 0xe5, 0x82, 0x1e, 0xff, 0x32, 0x7e, 0x01, 0x6b, 0x6d, 0xec, 0xe0,
+	0x33, 0x1d, 0xd8, 0x3a, 0x14, 0x87, 0xae, 0xd7, 0x67, 0x76, 0xaf, 0x9a, 0x6c, 0x80, 0x74, 0xc8,
+	0x5b, 0x8e, 0x43, 0x8f, 0x4f, 0x35, 0xc9, 0xa7, 0xf1, 0xb7, 0x0a, 0xa0, 0x2e, 0x89, 0xbd, 0x3c,
+	0x4a, 0x71, 0xee, 0x57, 0xa1, 0xc4, 0xc2, 0x7f, 0x66, 0xde, 0xc2, 0x40, 0x49, 0xa3, 0x28, 0x64,
+	0x1a, 0x05, 0xcf, 0x6c, 0x98, 0xc5, 0xf0, 0x51, 0x22, 0x1c, 0x17, 0x97, 0x0c, 0xc7, 0xfc, 0x70,
+	0xfe, 0x3a, 0x07, 0x68, 0x67, 0x16, 0x66, 0x1a, 0x67, 0x12, 0xf9, 0x42, 0xac, 0xe0, 0x9e, 0x27,
+	0x50, 0x69, 0xd9, 0xfc, 0x40, 0x84, 0xf0, 0xfc, 0xc2, 0x10, 0x5e, 0x5e, 0x22, 0x84, 0xab, 0xf3,
+	0x43, 0x78, 0x0d, 0x72, 0xfb, 0x6d, 0x5e, 0x2f, 0xe5, 0xf6, 0xdb, 0x89, 0xf0, 0xa5, 0x25, 0xc2,
+	0x17, 0x57, 0xd4, 0x7f, 0x2b, 0x70, 0x6e, 0x97, 0x26, 0x48, 0x29, 0x4d, 0x2d, 0x4e, 0x4a, 0x13,
+	0x87, 0x9b, 0x4b, 0x1f, 0xee, 0xf2, 0x9b, 0x2f, 0x2e, 0xb1, 0xf9, 0xf2, 0xfc, 0xcd, 0xc7, 0x37,
+	0x5b, 0x4a, 0xc6, 0xea, 0x75, 0x28, 0xd2, 0x56, 0x11, 0x77, 0x3c, 0x6c, 0x60, 0x4c, 0x60, 0x9d,
+	0x5f, 0xe1, 0x0f, 0xd8, 0xfc, 0x4f, 0xa0, 0xc2, 0x9c, 0xbb, 0x1f, 0x10, 0x8f, 0xc6, 0x72, 0x09,
+	0x39, 0x9b, 0xeb, 0x92, 0x79, 0x13, 0x28, 0x12, 0xfd, 0x36, 0xfe, 0xa4, 0x00, 0x6b, 0xe4, 0x96,
+	0xc7, 0x57, 0x5b, 0x70, 0x4b, 0xaf, 0x40, 0x61, 0xe8, 0xb9, 0xe3, 0xcc, 0xd6, 0x0e, 0x01, 0xa0,
+	0x4b, 0x90, 0x0b, 0xdc, 0x46, 0x3e, 0x0d, 0xce, 0x05, 0xa4, 0x6c, 0x2a, 0x4d, 0x66, 0xe3, 0x23,
+	0xec, 0xd1, 0x9d, 0x17, 0x4c, 0x3e, 0x42, 0x0d, 0x28, 0x7b, 0xf8, 0x0d, 0xf6, 0x7c, 0x4c, 0x2d,
+	0x46, 0x35, 0xc5, 0x10, 0x3d, 0x86, 0x55, 0x9e, 0x68, 0xf7, 0xac, 0x61, 0x80, 0xbd, 0x46, 0x69,
+	0x61, 0x6a, 0x53, 0xe5, 0x04, 0xdb, 0x04, 0x1f, 0x6d, 0x43, 0x8d, 0x8f, 0x7b, 0x47, 0x78, 0xe8,
+	0x7a, 0x22, 0x7b, 0x3d, 0x8d, 0x83, 0x58, 0x72, 0x87, 0x12, 0x10, 0x16, 0x22, 0x6b, 0xe7, 0x42,
+	0xa8, 0x8b, 0x59, 0x08, 0x0a, 0x26, 0x45, 0x0b, 0xea, 0x21, 0x0b, 0x2e, 0x86, 0xb6, 0x90, 0x47,
+	0xb8, 0x2a, 0x97, 0x23, 0x72, 0x05, 0x10, 0x73, 0x05, 0x37, 0x62, 0xae, 0xa0, 0x92, 0x3c, 0xb8,
+	0xf8, 0xf5, 0xaf, 0xd0, 0xbd, 0xf1, 0x7d, 0x54, 0x29, 0x1f, 0xa0, 0x53, 0x54, 0x50, 0xd2, 0x48,
+	0x8a, 0x8a, 0x41, 0xda, 0x48, 0xe2, 0x51, 0x2a, 0xd5, 0x48, 0x8a, 0xd0, 0x4c, 0xe8, 0x87, 0xdf,
+	0xc6, 0xbf, 0x2a, 0x70, 0x8e, 0x05, 0x6b, 0x5e, 0x0e, 0x72, 0xbb, 0x12, 0x3d, 0x41, 0x65, 0x5e,
+	0x4f, 0xf0, 0x23, 0x50, 0xfd, 0x9e, 0x54, 0xae, 0x6a, 0x66, 0xd9, 0x67, 0x2c, 0xa4, 0x72, 0x33,
+	0x3f, 0xbf, 0xdc, 0x8c, 0xf7, 0x14, 0x0b, 0xa7, 0xf7, 0x14, 0xe3, 0xcd, 0xbe, 0xe2, 0x92, 0xcd,
+	0x3e, 0xe3, 0x51, 0x78, 0x45, 0xe3, 0x9b, 0x8b, 0x04, 0x54, 0xe6, 0x17, 0xda, 0xcf, 0xd8, 0x75,
+	0x8b, 0x53, 0x2e, 0xb8, 0x6e, 0xd2, 0xc5, 0xc8, 0xc5, 0x2e, 0x86, 0x71, 0x00, 0xe7, 0x58, 0x88,
+	0x3d, 0xbb, 0x24, 0xd9, 0xa1, 0xd6, 0x78, 0x28, 0x38, 0x9e, 0xdd, 0xfd, 0x18, 0xcf, 0x61, 0xbd,
+	0xf3, 0x6e, 0x6a, 0x7b, 0x9c, 0xd6, 0x5f, 0x72, 0x7b, 0x17, 0xa1, 0x3c, 0xf0, 0x4e, 0x7a, 0xde,
+	0x6c, 0xc2, 0x45, 0x29, 0x0d, 0xbc, 0x13, 0x73, 0x36, 0x31, 0x2c, 0x40, 0xbb, 0xce, 0x2c, 0x19,
+	0x06, 0xae, 0x41, 0x59, 0x14, 0xf3, 0x4a, 0xba, 0x98, 0x17, 0x30, 0xf4, 0x19, 0xa8, 0x81, 0xdb,
+	0x23, 0x0b, 0xb0, 0x8e, 0x69, 0x6c, 0xe1, 0x72, 0xe0, 0x92, 0xbf, 0xbe, 0xf1, 0x5e, 0x81, 0x0b,
+	0xdd, 0xd9, 0x11, 0x89, 0x0e, 0x47, 0xf8, 0x4c, 0x3e, 0xf0, 0x42, 0xac, 0xad, 0x22, 0xdf, 0xc3,
+	0x02, 0x31, 0xb1, 0x98, 0x39, 0xa5, 0x82, 0x31, 0x45, 0x09, 0xdd, 0x68, 0x7e, 0x9e, 0x1b, 0xfd,
+	0x1c, 0x8a, 0xcc, 0x93, 0x17, 0xe6, 0x78, 0x72, 0x06, 0x26, 0x91, 0xe6, 0x8d, 0xe5, 0xd8, 0x83,
+	0x9e, 0x3b, 0x71, 0x58, 0xf6, 0xa9, 0x9a, 0x1a, 0x9d, 0x79, 0x31, 0x71, 0x4e, 0x8c, 0x19, 0x5c,
+	0x0a, 0xf7, 0x48, 0x6a, 0xca, 0xd6, 0x31, 0xc9, 0xbe, 0xfd, 0xdf, 0x71, 0xa3, 0x8b, 0xa4, 0x37,
+	0xf6, 0x01, 0xa2, 0xd5, 0xc2, 0x76, 0xb1, 0x12, 0xb5, 0x8b, 0xd1, 0x17, 0x50, 0x90, 0x8a, 0xde,
+	0x73, 0x61, 0xd1, 0xcb, 0x48, 0x68, 0xe9, 0x4b, 0x11, 0x0c, 0x0b, 0xea, 0xd1, 0x7c, 0xe7, 0x0d,
+	0x9e, 0x2c, 0x67, 0x91, 0xe8, 0x06, 0x94, 0xfb, 0x6c, 0xb3, 0x8d, 0x9c, 0xe4, 0xb5, 0x22, 0x5e,
+	0xa6, 0x80, 0x1b, 0xff, 0xa9, 0x40, 0x6d, 0x0f, 0x07, 0x04, 0x24, 0x29, 0xe6, 0xb4, 0xba, 0xfd,
+	0x53, 0xa8, 0xba, 0xc3, 0xa1, 0x8f, 0x03, 0x1e, 0xe1, 0x73, 0xb4, 0x06, 0xad, 0xb0, 0x39, 0x16,
+	0xe3, 0xd3, 0xe5, 0x7a, 0x5e, 0x4e, 0x01, 0xbe, 0x02, 0x6d, 0x80, 0x1d, 0x7b, 0x6c, 0x07, 0x3c,
+	0x18, 0xd6, 0x78, 0xd9, 0xd4, 0x16, 0xb3, 0x66, 0x84, 0x80, 0xae, 0x41, 0x8d, 0xaf, 0xe7, 0xe1,
+	0xbe, 0xeb, 0x0d, 0x58, 0xbf, 0x28, 0x6f, 0xae, 0xb2, 0x59, 0x93, 0x4d, 0x12, 0xb1, 0xe8, 0x9a,
+	0x02, 0xa9, 0xc4, 0xc4, 0x22, 0x73, 0x1c, 0xc5, 0xf8, 0x1c, 0x6a, 0x2f, 0xde, 0x60, 0xef, 0xad,
+	0x67, 0x07, 0x78, 0x9f, 0x54, 0x33, 0xc4, 0x19, 0xd0, 0xb2, 0x86, 0xee, 0x35, 0x6f, 0xb2, 0x81,
+	0xf1, 0x57, 0x79, 0xa8, 0x1d, 0xcc, 0xce, 0xa2, 0x93, 0xb0, 0x68, 0xce, 0x4b, 0x45, 0x33, 0xc9,
+	0xdf, 0x67, 0x9e, 0xc3, 0xf3, 0x40, 0xf2, 0x89, 0x3e, 0x26, 0x75, 0x44, 0x7f, 0xe6, 0xf9, 0xf6,
+	0x1b, 0x2c, 0x0c, 0x36, 0x9c, 0x88, 0xeb, 0xa5, 0xbc, 0x48, 0x2f, 0x5f, 0x01, 0x0a, 0x2c, 0x6f,
+	0x84, 0x83, 0x1e, 0x6d, 0x08, 0x48, 0x59, 0x69, 0xde, 0xd4, 0x19, 0x84, 0x48, 0xd8, 0xa6, 0xf3,
+	0x68, 0x13, 0xd6, 0x64, 0xec, 0x28, 0x13, 0xcd, 0x9b, 0xf5, 0x08, 0x99, 0x9d, 0xcf, 0x35, 0xa8,
+	0x91, 0xa8, 0x84, 0xbd, 0x50, 0x99, 0x15, 0xa6, 0x71, 0x36, 0x2b, 0x34, 0xfe, 0x53, 0xa8, 0xbb,
+	0x42, 0x9d, 0x3d, 0xa6, 0x46, 0xd6, 0x7b, 0x61, 0x16, 0x1d, 0x57, 0xb5, 0x59, 0x73, 0xe3, 0xaa,
+	0xff, 0x5a, 0xee, 0x7a, 0x54, 0x37, 0xf2, 0xa7, 0x75, 0x27, 0x42, 0x44, 0x96, 0x2a, 0xf3, 0x76,
+	0xfb, 0x1f, 0x2b, 0xb0, 0x1a, 0x1e, 0x13, 0x11, 0x29, 0x61, 0x77, 0x4a, 0xd2, 0xee, 0xae, 0x40,
+	0x85, 0xb5, 0x03, 0x7a, 0xb4, 0x07, 0xc3, 0xee, 0x35, 0xb0, 0xa9, 0x27, 0xa4, 0x13, 0x93, 0xb1,
+	0xa3, 0xfc, 0xd2, 0x3b, 0x32, 0xfe, 0x51, 0x81, 0x5a, 0x4c, 0x1e, 0x9a, 0xec, 0xfa, 0x53, 0x87,
+	0x5f, 0x56, 0xd5, 0x64, 0x03, 0xf4, 0x15, 0x09, 0x6c, 0x4c, 0xb1, 0xec, 0x7a, 0xb2, 0x92, 0x39,
+	0x46, 0x6b, 0x0a, 0x14, 0x62, 0x33, 0x81, 0x3b, 0x3e, 0xf2, 0x03, 0x77, 0x82, 0x79, 0x2d, 0x18,
+	0x4d, 0xa0, 0x4d, 0x28, 0xb1, 0x53, 0xe1, 0xfd, 0xd7, 0x2c, 0x56, 0x1c, 0x83, 0xe0, 0x0e, 0x5d,
+	0x97, 0x18, 0x57, 0x71, 0x3e, 0x2e, 0xc3, 0x30, 0x6c, 0xa8, 0xb7, 0xdc, 0xe9, 0x89, 0x7c, 0x07,
+	0x2e, 0x41, 0xde, 0xf7, 0xfa, 0xe9, 0x2b, 0x40, 0x66, 0x09, 0x70, 0xe0, 0x8b, 0xce, 0xb4, 0x0c,
+	0x1c, 0xf8, 0x01, 0xd9, 0x42, 0xa8, 0x2b, 0xb1, 0x85, 0x70, 0xc2, 0xb0, 0xc3, 0xf2, 0xfd, 0x0c,
+	0x37, 0x2e, 0x66, 0x3e, 0xb9, 0x25, 0xcd, 0xc7, 0xf8, 0xa3, 0x1c, 0xab, 0xfa, 0xcf, 0xb0, 0x10,
+	0x82, 0xc2, 0x70, 0xe6, 0x38, 0x3c, 0x46, 0xd3, 0x6f, 0x92, 0x99, 0x1c, 0xdb, 0x7e, 0xe0, 0x7a,
+	0x27, 0xdc, 0xb9, 0x89, 0x21, 0xba, 0x04, 0xd4, 0xde, 0x58, 0x44, 0x62, 0x15, 0x8e, 0x4a, 0x26,
+	0x48, 0x40, 0x22, 0x64, 0xfe, 0x6c, 0x3c, 0xb6, 0xbc, 0x13, 0x91, 0xe9, 0xf3, 0x21, 0x09, 0x36,
+	0xac, 0xb3, 0x44, 0x9d, 0x82, 0x66, 0xf2, 0x51, 0x32, 0x65, 0x2d, 0x27, 0x53, 0x56, 0xda, 0x4a,
+	0x22, 0xfe, 0x80, 0xdf, 0x7b, 0x36, 0x88, 0xbb, 0x19, 0x2d, 0xe1, 0x66, 0x8c, 0xbf, 0x57, 0xa0,
+	0xfe, 0xff, 0x2c, 0xe7, 0xf5, 0x19, 0x94, 0x70, 0x09, 0xb4, 0xb1, 0xf5, 0xae, 0x37, 0xc0, 0x53,
+	0xfe, 0xf2, 0x99, 0x37, 0xd5, 0xb1, 0xf5, 0xae, 0x4d, 0xc6, 0xf1, 0x46, 0x6e, 0xfe, 0xf4, 0x46,
+	0xee, 0xc7, 0xa0, 0x59, 0xa3, 0x91, 0x87, 0x47, 0x51, 0xeb, 0x29, 0x9a, 0x88, 0x6b, 0xaf, 0x18,
+	0xd7, 0x9e, 0xf1, 0x1b, 0x05, 0xea, 0x7b, 0x8e, 0x7b, 0x24, 0x8b, 0xbd, 0x54, 0x34, 0x6c, 0x40,
+	0x79, 0x6a, 0x05, 0x01, 0xf6, 0x44, 0x5d, 0x2c, 0x86, 0xf1, 0xf5, 0xf2, 0xf3, 0x4f, 0xab, 0x10,
+	0x3b, 0x2d, 0xc3, 0x01, 0x4d, 0x34, 0xb9, 0xfd, 0x70, 0xf7, 0xa9, 0xd6, 0x90, 0x40, 0x61, 0xbb,
+	0x27, 0x5f, 0xe4, 0xb4, 0x58, 0xdf, 0x96, 0xa9, 0x90, 0x0d, 0x16, 0x34, 0xb7, 0x8d, 0xb7, 0x50,
+	0x6f, 0xdb, 0xc3, 0xa1, 0xbc, 0xed, 0xcf, 0x40, 0x9d, 0xe0, 0xb7, 0xbd, 0xec, 0x13, 0x2b, 0x4f,
+	0xf0, 0x5b, 0xf2, 0x41, 0xb0, 0x5c, 0x67, 0xc0, 0xb0, 0x52, 0xf7, 0xb2, 0xec, 0x3a, 0x03, 0x8a,
+	0x45, 0xb6, 0x79, 0x4c, 0x1f, 0xd5, 0xb9, 0x06, 0xc4, 0xd0, 0xf8, 0x25, 0xe8, 0xd1, 0xc2, 0x51,
+	0x23, 0x4c, 0xac, 0xec, 0xcf, 0xd9, 0x2d, 0x5f, 0x9e, 0x6a, 0x46, 0xac, 0x2f, 0x1c, 0x5d, 0x12,
+	0x97, 0x0b, 0xe1, 0x1b, 0xff, 0xa2, 0x40, 0x85, 0x7a, 0x51, 0xcc, 0xa4, 0xca, 0x4a, 0x9b, 0x3e,
+	0x06, 0x2d, 0xec, 0x4a, 0xf2, 0x93, 0x8c, 0x26, 0xd0, 0xcf, 0x00, 0xac, 0x20, 0xf0, 0xec, 0xa3,
+	0x19, 0xd3, 0x22, 0x59, 0x6e, 0x83, 0x2e, 0x27, 0xf1, 0xdd, 0xda, 0x0e, 0x51, 0x3a, 0x93, 0xc0,
+	0x3b, 0x31, 0x25, 0x9a, 0xb0, 0x37, 0x5f, 0x88, 0x7a, 0xf3, 0xcd, 0xef, 0xa0, 0x9e, 0x20, 0x21,
+	0x51, 0xfd, 0x35, 0x3e, 0xe1, 0x92, 0x91, 0xcf, 0x78, 0xcb, 0x5c, 0xe3, 0xd1, 0xff, 0x61, 0xee,
+	0x5b, 0xc5, 0xb8, 0x2b, 0x2c, 0x85, 0x44, 0xbc, 0xcf, 0xa1, 0x28, 0xeb, 0x4d, 0x4f, 0x0a, 0x67,
+	0x32, 0xb0, 0xf1, 0x6f, 0xa4, 0xc9, 0x87, 0x2d, 0xaf, 0x7f, 0x4c, 0x66, 0xfd, 0xdf, 0x93, 0xad,
+	0xef, 0x65, 0xe8, 0xe7, 0x0b, 0xd6, 0xaa, 0x4d, 0xad, 0x75, 0x9a, 0x9a, 0x7e, 0x57, 0x95, 0x1c,
+	0xc1, 0xb9, 0xd8, 0x82, 0xdc, 0xb0, 0x96, 0xda, 0x5d, 0xa8, 0xc1, 0xdc, 0xe9, 0x1a, 0xbc, 0x23,
+	0x5a, 0xb0, 0xcb, 0xbb, 0x38, 0xe3, 0x0a, 0x54, 0x76, 0xfd, 0xfe, 0x6b, 0x81, 0xad, 0x43, 0x9e,
+	0xb8, 0x63, 0x16, 0xb7, 0xc9, 0xa7, 0x71, 0x1f, 0xaa, 0x0c, 0x81, 0x4b, 0x2c, 0x61, 0x68, 0x14,
+	0x83, 0x6c, 0x1a, 0x7b, 0x5e, 0x68, 0x9c, 0x6c, 0x60, 0xfc, 0x85, 0x02, 0xfa, 0xc1, 0x2c, 0xe0,
+	0x5d, 0x32, 0xce, 0x3e, 0xd4, 0x8f, 0x22, 0x27, 0x8c, 0x1f, 0x43, 0x21, 0xb0, 0x46, 0x62, 0x7b,
+	0x2a, 0x15, 0xf1, 0xd0, 0x1a, 0x99, 0x74, 0x36, 0x7a, 0x3e, 0xc9, 0xcf, 0x7b, 0x3e, 0x49, 0xfd,
+	0xde, 0xa0, 0xb0, 0xdc, 0xef, 0x0d, 0x86, 0xa2, 0x6d, 0x11, 0x17, 0xf2, 0xf7, 0xfe, 0xb2, 0xf2,
+	0xe7, 0x0a, 0xac, 0xed, 0x61, 0xae, 0x0a, 0x5f, 0xaa, 0x6c, 0xc5, 0x3b, 0x9b, 0x72, 0xca, 0x3b,
+	0x5b, 0x56, 0xdd, 0x51, 0x58, 0x54, 0x77, 0xc4, 0x5a, 0x8f, 0x9f, 0x00, 0xd0, 0xf7, 0xcc, 0x1e,
+	0x99, 0xe2, 0x5d, 0x38, 0x8d, 0xce, 0x74, 0xed, 0x5f, 0x63, 0x63, 0x1f, 0xea, 0x07, 0xb3, 0x80,
+	0x8b, 0xcd, 0x44, 0x5b, 0xfc, 0x62, 0x95, 0xfd, 0x5c, 0x76, 0x17, 0xea, 0x7b, 0xf8, 0x8c, 0xac,
+	0xa8, 0xa1, 0x08, 0xaa, 0x50, 0x39, 0xb1, 0xd7, 0x45, 0x65, 0xc1, 0xeb, 0xe2, 0xff, 0xb9, 0x8a,
+	0x10, 0x7b, 0x1b, 0x91, 0x37, 0x66, 0xbc, 0x04, 0xfd, 0xd0, 0x1a, 0x7d, 0x80, 0xe5, 0x9c, 0x6a,
+	0xed, 0xc6, 0x3a, 0x20, 0xb2, 0x54, 0xdc, 0x56, 0x8c, 0x03, 0x96, 0xbf, 0x1d, 0x5a, 0xa3, 0x50,
+	0x43, 0x51, 0xee, 0xa4, 0xc4, 0x72, 0xa7, 0x6b, 0x50, 0xb3, 0x27, 0x7d, 0x67, 0x36, 0xc0, 0x3d,
+	0x2e, 0x0b, 0x4b, 0xe1, 0x56, 0xf9, 0x2c, 0xe3, 0x6c, 0x74, 0x41, 0x8f, 0x38, 0xf2, 0xab, 0xdd,
+	0x84, 0x7c, 0x60, 0x8d, 0xb8, 0xec, 0x91, 0x60, 0x64, 0x52, 0xda, 0x5a, 0x6e, 0xee, 0xd6, 0x8c,
+	0xef, 0x60, 0x9d, 0x39, 0xa0, 0x0f, 0x32, 0x75, 0xe3, 0x22, 0x9c, 0x4f, 0x90, 0x33, 0xc1, 0x0c,
+	0x1f, 0xd0, 0x81, 0xd5, 0x7f, 0xfd, 0x61, 0x17, 0x28, 0xe5, 0x1d, 0x72, 0xcb, 0x79, 0x87, 0x29,
+	0x9c, 0x8b, 0x2d, 0xca, 0x95, 0xb4, 0xf8, 0x6e, 0x34, 0x22, 0xb9, 0x58, 0xc2, 0x13, 0x8a, 0xb2,
+	0x20, 0xe5, 0xf9, 0x89, 0xf0, 0xdf, 0xf2, 0x39, 0x0b, 0x73, 0x51, 0xe6, 0x99, 0x8b, 0x4c, 0xc2,
+	0xf5, 0xf5, 0x00, 0x10, 0xad, 0x07, 0xce, 0x6e, 0x9d, 0xc6, 0x4d, 0x38, 0x17, 0x23, 0xe5, 0xbb,
+	0xbe, 0x00, 0x25, 0xfc, 0xce, 0xf6, 0x03, 0x9f, 0x87, 0x06, 0x3e, 0x32, 0x6e, 0x43, 0x99, 0x2b,
+	0x68, 0xd9, 0x43, 0xfe, 0x4d, 0x0e, 0x2a, 0xe2, 0x19, 0x9b, 0xa4, 0x07, 0xdf, 0x24, 0xc9, 0x3e,
+	0x91, 0xc8, 0x28, 0x0a, 0xff, 0xe6, 0x31, 0x39, 0x54, 0xe6, 0x56, 0xec, 0x1e, 0x35, 0x53, 0x54,
+	0x44, 0x23, 0x8c, 0x84, 0xe2, 0x35, 0xf7, 0xa1, 0x2a, 0x33, 0xca, 0x88, 0xde, 0x57, 0x65, 0xa7,
+	0x96, 0x72, 0x38, 0x51, 0x30, 0x6f, 0xb6, 0x41, 0x0b, 0xb9, 0x67, 0xf0, 0xf9, 0x34, 0xce, 0x27,
+	0xfe, 0xa4, 0x14, 0x72, 0xd9, 0xfc, 0x19, 0x54, 0x65, 0xf3, 0x43, 0x55, 0x50, 0xbb, 0x87, 0xdb,
+	0xcf, 0xdb, 0xdb, 0x66, 0x5b, 0x5f, 0x41, 0xe7, 0x61, 0x6d, 0xff, 0xf9, 0xae, 0xd9, 0xf9, 0xf9,
+	0xcb, 0xce, 0xf3, 0xc3, 0xde, 0x76, 0xab, 0xd5, 0xe9, 0x76, 0x75, 0x05, 0x55, 0xa0, 0xbc, 0x6d,
+	0xb6, 0x9e, 0xec, 0xbf, 0xea, 0xe8, 0xb9, 0xcd, 0x4d, 0x80, 0xe8, 0xf7, 0x6c, 0x48, 0x85, 0xc2,
+	0xcb, 0x6e, 0xc7, 0xd4, 0x57, 0xc8, 0xd7, 0xf6, 0xcb, 0xc3, 0x17, 0xba, 0x42, 0xbe, 0x76, 0xbb,
+	0xad, 0x1f, 0xf4, 0xdc, 0xe6, 0x97, 0xec, 0x27, 0x2a, 0xb4, 0x1c, 0xa9, 0x82, 0x6a, 0x76, 0xba,
+	0x1d, 0xf3, 0x55, 0xa7, 0xcd, 0xb0, 0x77, 0xf7, 0x9f, 0x75, 0x74, 0x05, 0x95, 0x21, 0xdf, 0xde,
+	0x37, 0xf5, 0xdc, 0xe6, 0x23, 0x58, 0x4b, 0x15, 0x94, 0x68, 0x0d, 0x56, 0x5b, 0x4f, 0x3a, 0xad,
+	0x1f, 0xba, 0x2f, 0x7f, 0xec, 0x3d, 0x7f, 0xf1, 0xbc, 0xa3, 0xaf, 0x20, 0x80, 0x52, 0xf7, 0xc9,
+	0xf6, 0x9d, 0x7b, 0xf7, 0x19, 0xf1, 0x8f, 0xed, 0x7b, 0x7a, 0x6e, 0xf3, 0x2e, 0x54, 0xa4, 0xae,
+	0x25, 0x91, 0xb8, 0x7b, 0xb8, 0x6d, 0x1e, 0xd2, 0xb5, 0x34, 0x28, 0x9a, 0x9d, 0xed, 0xf6, 0xff,
+	0xd7, 0x15, 0x22, 0xc4, 0xee, 0xfe, 0xf3, 0xfd, 0xee, 0x93, 0x4e, 0x5b, 0xcf, 0x6d, 0xee, 0x42,
+	0x2d, 0xde, 0x0b, 0x44, 0x3a, 0x54, 0x89, 0x58, 0xbd, 0x96, 0xd9, 0xd9, 0x66, 0xc4, 0x62, 0xe6,
+	0xe5, 0x41, 0x9b, 0xce, 0x28, 0xe1, 0x4c, 0xbb, 0xf3, 0xac, 0x73, 0x48, 0xf9, 0xec, 0x83, 0x16,
+	0xb6, 0x8d, 0xc8, 0xce, 0xb8, 0xa0, 0x2a, 0x14, 0x9e, 0x76, 0x5f, 0x3c, 0x67, 0x1a, 0x79, 0xb6,
+	0xff, 0xbc, 0xa3, 0xe7, 0x88, 0xc0, 0xdd, 0x9f, 0x3f, 0xd3, 0xf3, 0xe4, 0xa3, 0xd5, 0x7d, 0xa5,
+	0x17, 0x88, 0x48, 0x07, 0xe6, 0x8b, 0xc3, 0x17, 0x3b, 0x2f, 0x77, 0xf5, 0xe2, 0x9d, 0xff, 0xaa,
+	0x43, 0x7e, 0xfb, 0x60, 0x1f, 0x7d, 0x0f, 0x10, 0xfd, 0x14, 0x01, 0xf1, 0x72, 0x3b, 0xf9, 0xdb,
+	0x84, 0xe6, 0x85, 0xd4, 0xab, 0x4e, 0x87, 0x3e, 0xf1, 0xad, 0xa0, 0x6f, 0xa0, 0xc2, 0x0b, 0x7d,
+	0xca, 0xe0, 0x22, 0x4f, 0xdf, 0x92, 0x2f, 0xf7, 0xcd, 0xf8, 0xd3, 0xba, 0xb1, 0x82, 0x1e, 0x80,
+	0x2a, 0x9e, 0xe4, 0xd1, 0x3a, 0x05, 0x26, 0x9e, 0xee, 0x9b, 0xe7, 0x13, 0xb3, 0xfc, 0xfe, 0xaf,
+	0x10, 0x99, 0xa3, 0xd7, 0x78, 0x2e, 0x73, 0xea, 0x79, 0xfe, 0x14, 0x99, 0xef, 0x41, 0x45, 0x7a,
+	0x70, 0xe7, 0x32, 0xa7, 0x9f, 0xe0, 0x9b, 0x72, 0xbe, 0x6a, 0xac, 0xa0, 0x1d, 0xa8, 0xca, 0x6f,
+	0xb9, 0xa8, 0xc1, 0xd3, 0xcd, 0xd4, 0xf3, 0xee, 0x29, 0x4b, 0x7f, 0x07, 0xab, 0xb1, 0x37, 0x51,
+	0xf4, 0x91, 0xac, 0xb0, 0x38, 0x97, 0xe4, 0xb3, 0x94, 0xb1, 0x82, 0xbe, 0x05, 0x88, 0x5e, 0x38,
+	0xf9, 0xce, 0x53, 0x4f, 0x9e, 0x4d, 0x3d, 0x41, 0xe8, 0x1b, 0x2b, 0xe8, 0x31, 0x0b, 0x89, 0xc2,
+	0x76, 0x3d, 0x6c, 0x8d, 0xe7, 0xd2, 0xa7, 0x17, 0xbe, 0xad, 0x90, 0xdd, 0xcb, 0xaf, 0x29, 0x7c,
+	0xf7, 0x19, 0x0f, 0x2c, 0xa7, 0xec, 0xfe, 0x7b, 0x58, 0x8d, 0xbd, 0xaa, 0xf0, 0xdd, 0x67, 0xbd,
+	0xb4, 0x64, 0x6e, 0xe2, 0x11, 0x54, 0xa4, 0x57, 0x14, 0x7e, 0x70, 0xe9, 0x77, 0x95, 0xec, 0x0d,
+	0xb4, 0xa0, 0x9e, 0x78, 0x1e, 0x41, 0x97, 0xd8, 0xc9, 0x67, 0x3e, 0x9a, 0x64, 0x33, 0x31, 0x61,
+	0x3d, 0xeb, 0xfd, 0x01, 0x6d, 0xc4, 0x39, 0xa5, 0x9f, 0x26, 0x9a, 0xeb, 0x89, 0x76, 0x3d, 0x6d,
+	0xfd, 0x53, 0x9e, 0xf7, 0xa0, 0x22, 0xfd, 0x98, 0x82, 0xef, 0x2a, 0xfd, 0xf3, 0x8a, 0x0c, 0x73,
+	0x94, 0xdf, 0x25, 0xf9, 0x81, 0x64, 0x3c, 0x55, 0x2e, 0x65, 0x8e, 0x9c, 0x49, 0xcc, 0x1c, 0xe3,
+	0x5c, 0x92, 0x3f, 0xb7, 0x8f, 0xcc, 0x91, 0xd3, 0x46, 0xe6, 0x14, 0x27, 0xd4, 0x13, 0x84, 0x3e,
+	0x13, 0x5e, 0x7e, 0xed, 0x8b, 0x59, 0xd3, 0xb2, 0xc2, 0x3f, 0x84, 0x32, 0xef, 0x73, 0xa2, 0x73,
+	0xf1, 0xae, 0xe7, 0x02, 0xca, 0xeb, 0x0a, 0x7a, 0x08, 0xaa, 0x68, 0x85, 0x72, 0xef, 0x93, 0xe8,
+	0x8c, 0x9e, 0xb2, 0xee, 0x63, 0x28, 0xef, 0x61, 0x79, 0xdd, 0xf8, 0x5b, 0x4b, 0xf3, 0x52, 0x8a,
+	0x92, 0x66, 0x40, 0xaf, 0x68, 0x15, 0x41, 0x0e, 0x3c, 0xf2, 0x99, 0x94, 0x49, 0xcc, 0x67, 0xca,
+	0x8c, 0xe2, 0x9d, 0x15, 0x63, 0x05, 0xdd, 0x61, 0x3e, 0x53, 0x92, 0x3a, 0xd1, 0xf8, 0x6c, 0xd6,
+	0x62, 0x24, 0x3e, 0xf5, 0xb3, 0x35, 0x81, 0xc4, 0xaf, 0x7d, 0x36, 0x65, 0x72, 0xb1, 0xdb, 0x0a,
+	0xba, 0x0b, 0xaa, 0xe8, 0x29, 0x72, 0xa2, 0x44, 0x8b, 0x31, 0x8b, 0xe8, 0x0e, 0xa8, 0xa2, 0xa3,
+	0xc7, 0x89, 0x12, 0x0d, 0xbe, 0x6c, 0x19, 0x05, 0x52, 0x4c, 0xc6, 0x24, 0x65, 0xc6, 0x72, 0x0f,
+	0x40, 0x15, 0x0d, 0x2d, 0x4e, 0x94, 0x68, 0xac, 0x35, 0xcf, 0x27, 0x66, 0xc3, 0x30, 0xb2, 0x03,
+	0x15, 0xa9, 0x6b, 0x21, 0xc2, 0x40, 0xaa, 0x71, 0xd2, 0x6c, 0xa4, 0x01, 0xe9, 0x50, 0x44, 0x05,
+	0x90, 0x43, 0xd1, 0x72, 0xb6, 0xf4, 0x1d, 0x8d, 0xe8, 0x38, 0xc0, 0xdb, 0x8e, 0x83, 0xe6, 0xa0,
+	0x9d, 0x42, 0x7e, 0x0b, 0x0a, 0xa4, 0x7f, 0x81, 0xd8, 0x15, 0x93, 0x7a, 0x1d, 0xcd, 0x35, 0x69,
+	0x46, 0x48, 0x7b, 0x5b, 0xb9, 0xf3, 0xa7, 0x00, 0x1a, 0x4b, 0xd6, 0x48, 0xf0, 0xbf, 0x0b, 0x5a,
+	0xd8, 0xc5, 0x40, 0xe7, 0xc5, 0x1d, 0x8a, 0x25, 0xd6, 0x4d, 0x39, 0xc1, 0xa3, 0x57, 0xe7, 0x01,
+	0x7d, 0x11, 0x61, 0x13, 0x5d, 0xfa, 0xf6, 0x31, 0x87, 0xb2, 0x2a, 0x51, 0xfa, 0x94, 0xf4, 0x31,
+	0x40, 0x88, 0xe5, 0xcf, 0x23, 0x3b, 0xed, 0xda, 0x86, 0x3e, 0x8f, 0xcb, 0x2c, 0xfb, 0xbc, 0x25,
+	0xb9, 0xa0, 0x07, 0xa0, 0x85, 0xfd, 0x0a, 0x24, 0xef, 0x6e, 0xf1, 0xc5, 0xed, 0x00, 0x84, 0xa4,
+	0x3e, 0x3f, 0xed, 0x54, 0xef, 0x63, 0x31, 0x9b, 0x9f, 0x82, 0x2a, 0x9a, 0x12, 0xdc, 0x66, 0x13,
+	0x3d, 0x8a, 0x53, 0x75, 0xb0, 0x0d, 0xea, 0x1e, 0x8e, 0x51, 0x27, 0xda, 0x12, 0x8b, 0x05, 0x68,
+	0x81, 0x26, 0x68, 0xc4, 0x31, 0x24, 0x9b, 0x14, 0x8b, 0x99, 0xdc, 0x01, 0x2d, 0xec, 0x1b, 0xa0,
+	0x28, 0x57, 0x8b, 0x49, 0x22, 0x15, 0x90, 0x7c, 0xe7, 0x5a, 0xd8, 0x57, 0xe0, 0x34, 0xc9, 0x3e,
+	0xc3, 0xa9, 0xd6, 0x2e, 0xa2, 0x55, 0xd6, 0xe9, 0xd5, 0x63, 0x45, 0x12, 0xf5, 0x97, 0x3b, 0x50,
+	0x91, 0xea, 0x3d, 0x7e, 0xc3, 0xd3, 0xc5, 0x63, 0xb3, 0x91, 0x06, 0x84, 0x37, 0xfc, 0x11, 0x54,
+	0xa4, 0x9e, 0x05, 0xe7, 0x91, 0xee, 0x62, 0x64, 0x2c, 0x7f, 0x5b, 0x41, 0x4f, 0x60, 0x35, 0x56,
+	0xf4, 0xf3, 0xf8, 0x9a, 0xd5, 0x47, 0x68, 0x36, 0xb3, 0x40, 0xa1, 0x18, 0x77, 0xa1, 0xb4, 0x87,
+	0x49, 0x47, 0x03, 0x85, 0x55, 0xf2, 0xe2, 0x23, 0xba, 0x01, 0xc0, 0x15, 0x16, 0x27, 0xcc, 0x50,
+	0xd5, 0x23, 0x16, 0x5a, 0x48, 0xe5, 0x27, 0x05, 0x08, 0xa9, 0x56, 0x6f, 0x9e, 0x4f, 0xcc, 0x46,
+	0x5e, 0x85, 0xdc, 0xeb, 0xa8, 0x50, 0x8f, 0x79, 0x41, 0x99, 0xc1, 0xc5, 0xd4, 0xbc, 0xa4, 0xe4,
+	0x72, 0xcb, 0x1d, 0x4f, 0xad, 0x7e, 0xf0, 0x01, 0x4e, 0x70, 0x07, 0x2a, 0x52, 0x2f, 0x83, 0x9f,
+	0x50, 0xba, 0xa5, 0xd2, 0x6c, 0xa4, 0x01, 0x42, 0x80, 0x9d, 0xc7, 0xff, 0xf0, 0xfe, 0xb2, 0xf2,
+	0xcf, 0xef, 0x2f, 0x2b, 0xff, 0xfe, 0xfe, 0xb2, 0xf2, 0x67, 0xff, 0x71, 0x79, 0xe5, 0x17, 0x37,
+	0x47, 0x76, 0x70, 0x3c, 0x3b, 0xda, 0xea, 0xbb, 0xe3, 0x5b, 0x53, 0xab, 0x7f, 0x7c, 0x32, 0xc0,
+	0x9e, 0xfc, 0xe5, 0x7b, 0xfd, 0x5b, 0xd1, 0x3f, 0xb5, 0x3d, 0x2a, 0x51, 0xb1, 0xee, 0xfe, 0xef,
+	0x00, 0x60, 0x8b, 0x87, 0x02, 0x7f, 0x3b, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -5019,10 +6357,16 @@ type APIClient interface {
 	ListCommitStream(ctx context.Context, in *ListCommitRequest, opts ...grpc.CallOption) (API_ListCommitStreamClient, error)
 	// DeleteCommit deletes a commit.
 	DeleteCommit(ctx context.Context, in *DeleteCommitRequest, opts ...grpc.CallOption) (*types.Empty, error)
+	// ExpireCommits deletes the commits in a repo that are older than its
+	// commit_ttl, and returns them
+	ExpireCommits(ctx context.Context, in *ExpireCommitsRequest, opts ...grpc.CallOption) (*CommitInfos, error)
 	// FlushCommit waits for downstream commits to finish
 	FlushCommit(ctx context.Context, in *FlushCommitRequest, opts ...grpc.CallOption) (API_FlushCommitClient, error)
 	// SubscribeCommit subscribes for new commits on a given branch
 	SubscribeCommit(ctx context.Context, in *SubscribeCommitRequest, opts ...grpc.CallOption) (API_SubscribeCommitClient, error)
+	// SubscribeFileChanges subscribes for the file changes of new finished
+	// commits on a branch
+	SubscribeFileChanges(ctx context.Context, in *SubscribeFileChangesRequest, opts ...grpc.CallOption) (API_SubscribeFileChangesClient, error)
 	// BuildCommit builds a commit that's backed by the given tree
 	BuildCommit(ctx context.Context, in *BuildCommitRequest, opts ...grpc.CallOption) (*Commit, error)
 	// CreateBranch creates a new branch
@@ -5049,7 +6393,8 @@ type APIClient interface {
 	// TODO(msteffen): When the dash has been updated to use ListFileStream,
 	// replace ListFile with this RPC (https://github.com/pachyderm/dash/issues/201)
 	ListFileStream(ctx context.Context, in *ListFileRequest, opts ...grpc.CallOption) (API_ListFileStreamClient, error)
-	// WalkFile walks over all the files under a directory, including children of children.
+	// WalkFile walks over all the files under a directory, including children
+	// of children, optionally limited to a depth or a file type.
 	WalkFile(ctx context.Context, in *WalkFileRequest, opts ...grpc.CallOption) (API_WalkFileClient, error)
 	// GlobFile returns info about all files. This is deprecated in favor of
 	// GlobFileStream
@@ -5060,6 +6405,8 @@ type APIClient interface {
 	GlobFileStream(ctx context.Context, in *GlobFileRequest, opts ...grpc.CallOption) (API_GlobFileStreamClient, error)
 	// DiffFile returns the differences between 2 paths at 2 commits.
 	DiffFile(ctx context.Context, in *DiffFileRequest, opts ...grpc.CallOption) (*DiffFileResponse, error)
+	// SearchFiles returns the files in a commit whose indexed attributes match
+	SearchFiles(ctx context.Context, in *SearchFilesRequest, opts ...grpc.CallOption) (*SearchFilesResponse, error)
 	// DeleteFile deletes a file.
 	DeleteFile(ctx context.Context, in *DeleteFileRequest, opts ...grpc.CallOption) (*types.Empty, error)
 	// DeleteAll deletes everything
@@ -5189,6 +6536,15 @@ func (c *aPIClient) DeleteCommit(ctx context.Context, in *DeleteCommitRequest, o
 	return out, nil
 }
 
+func (c *aPIClient) ExpireCommits(ctx context.Context, in *ExpireCommitsRequest, opts ...grpc.CallOption) (*CommitInfos, error) {
+	out := new(CommitInfos)
+	err := c.cc.Invoke(ctx, "/pfs.API/ExpireCommits", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *aPIClient) FlushCommit(ctx context.Context, in *FlushCommitRequest, opts ...grpc.CallOption) (API_FlushCommitClient, error) {
 	stream, err := c.cc.NewStream(ctx, &_API_serviceDesc.Streams[1], "/pfs.API/FlushCommit", opts...)
 	if err != nil {
@@ -5253,6 +6609,38 @@ func (x *aPISubscribeCommitClient) Recv() (*CommitInfo, error) {
 	return m, nil
 }
 
+func (c *aPIClient) SubscribeFileChanges(ctx context.Context, in *SubscribeFileChangesRequest, opts ...grpc.CallOption) (API_SubscribeFileChangesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_API_serviceDesc.Streams[3], "/pfs.API/SubscribeFileChanges", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &aPISubscribeFileChangesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type API_SubscribeFileChangesClient interface {
+	Recv() (*FileChangeEvent, error)
+	grpc.ClientStream
+}
+
+type aPISubscribeFileChangesClient struct {
+	grpc.ClientStream
+}
+
+func (x *aPISubscribeFileChangesClient) Recv() (*FileChangeEvent, error) {
+	m := new(FileChangeEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *aPIClient) BuildCommit(ctx context.Context, in *BuildCommitRequest, opts ...grpc.CallOption) (*Commit, error) {
 	out := new(Commit)
 	err := c.cc.Invoke(ctx, "/pfs.API/BuildCommit", in, out, opts...)
@@ -5299,7 +6687,7 @@ func (c *aPIClient) DeleteBranch(ctx context.Context, in *DeleteBranchRequest, o
 }
 
 func (c *aPIClient) PutFile(ctx context.Context, opts ...grpc.CallOption) (API_PutFileClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_API_serviceDesc.Streams[3], "/pfs.API/PutFile", opts...)
+	stream, err := c.cc.NewStream(ctx, &_API_serviceDesc.Streams[4], "/pfs.API/PutFile", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -5342,7 +6730,7 @@ func (c *aPIClient) CopyFile(ctx context.Context, in *CopyFileRequest, opts ...g
 }
 
 func (c *aPIClient) GetFile(ctx context.Context, in *GetFileRequest, opts ...grpc.CallOption) (API_GetFileClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_API_serviceDesc.Streams[4], "/pfs.API/GetFile", opts...)
+	stream, err := c.cc.NewStream(ctx, &_API_serviceDesc.Streams[5], "/pfs.API/GetFile", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -5392,7 +6780,7 @@ func (c *aPIClient) ListFile(ctx context.Context, in *ListFileRequest, opts ...g
 }
 
 func (c *aPIClient) ListFileStream(ctx context.Context, in *ListFileRequest, opts ...grpc.CallOption) (API_ListFileStreamClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_API_serviceDesc.Streams[5], "/pfs.API/ListFileStream", opts...)
+	stream, err := c.cc.NewStream(ctx, &_API_serviceDesc.Streams[6], "/pfs.API/ListFileStream", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -5424,7 +6812,7 @@ func (x *aPIListFileStreamClient) Recv() (*FileInfo, error) {
 }
 
 func (c *aPIClient) WalkFile(ctx context.Context, in *WalkFileRequest, opts ...grpc.CallOption) (API_WalkFileClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_API_serviceDesc.Streams[6], "/pfs.API/WalkFile", opts...)
+	stream, err := c.cc.NewStream(ctx, &_API_serviceDesc.Streams[7], "/pfs.API/WalkFile", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -5465,7 +6853,7 @@ func (c *aPIClient) GlobFile(ctx context.Context, in *GlobFileRequest, opts ...g
 }
 
 func (c *aPIClient) GlobFileStream(ctx context.Context, in *GlobFileRequest, opts ...grpc.CallOption) (API_GlobFileStreamClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_API_serviceDesc.Streams[7], "/pfs.API/GlobFileStream", opts...)
+	stream, err := c.cc.NewStream(ctx, &_API_serviceDesc.Streams[8], "/pfs.API/GlobFileStream", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -5505,6 +6893,15 @@ func (c *aPIClient) DiffFile(ctx context.Context, in *DiffFileRequest, opts ...g
 	return out, nil
 }
 
+func (c *aPIClient) SearchFiles(ctx context.Context, in *SearchFilesRequest, opts ...grpc.CallOption) (*SearchFilesResponse, error) {
+	out := new(SearchFilesResponse)
+	err := c.cc.Invoke(ctx, "/pfs.API/SearchFiles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *aPIClient) DeleteFile(ctx context.Context, in *DeleteFileRequest, opts ...grpc.CallOption) (*types.Empty, error) {
 	out := new(types.Empty)
 	err := c.cc.Invoke(ctx, "/pfs.API/DeleteFile", in, out, opts...)
@@ -5524,7 +6921,7 @@ func (c *aPIClient) DeleteAll(ctx context.Context, in *types.Empty, opts ...grpc
 }
 
 func (c *aPIClient) Fsck(ctx context.Context, in *FsckRequest, opts ...grpc.CallOption) (API_FsckClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_API_serviceDesc.Streams[8], "/pfs.API/Fsck", opts...)
+	stream, err := c.cc.NewStream(ctx, &_API_serviceDesc.Streams[9], "/pfs.API/Fsck", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -5581,10 +6978,16 @@ type APIServer interface {
 	ListCommitStream(*ListCommitRequest, API_ListCommitStreamServer) error
 	// DeleteCommit deletes a commit.
 	DeleteCommit(context.Context, *DeleteCommitRequest) (*types.Empty, error)
+	// ExpireCommits deletes the commits in a repo that are older than its
+	// commit_ttl, and returns them
+	ExpireCommits(context.Context, *ExpireCommitsRequest) (*CommitInfos, error)
 	// FlushCommit waits for downstream commits to finish
 	FlushCommit(*FlushCommitRequest, API_FlushCommitServer) error
 	// SubscribeCommit subscribes for new commits on a given branch
 	SubscribeCommit(*SubscribeCommitRequest, API_SubscribeCommitServer) error
+	// SubscribeFileChanges subscribes for the file changes of new finished
+	// commits on a branch
+	SubscribeFileChanges(*SubscribeFileChangesRequest, API_SubscribeFileChangesServer) error
 	// BuildCommit builds a commit that's backed by the given tree
 	BuildCommit(context.Context, *BuildCommitRequest) (*Commit, error)
 	// CreateBranch creates a new branch
@@ -5611,7 +7014,8 @@ type APIServer interface {
 	// TODO(msteffen): When the dash has been updated to use ListFileStream,
 	// replace ListFile with this RPC (https://github.com/pachyderm/dash/issues/201)
 	ListFileStream(*ListFileRequest, API_ListFileStreamServer) error
-	// WalkFile walks over all the files under a directory, including children of children.
+	// WalkFile walks over all the files under a directory, including children
+	// of children, optionally limited to a depth or a file type.
 	WalkFile(*WalkFileRequest, API_WalkFileServer) error
 	// GlobFile returns info about all files. This is deprecated in favor of
 	// GlobFileStream
@@ -5622,6 +7026,8 @@ type APIServer interface {
 	GlobFileStream(*GlobFileRequest, API_GlobFileStreamServer) error
 	// DiffFile returns the differences between 2 paths at 2 commits.
 	DiffFile(context.Context, *DiffFileRequest) (*DiffFileResponse, error)
+	// SearchFiles returns the files in a commit whose indexed attributes match
+	SearchFiles(context.Context, *SearchFilesRequest) (*SearchFilesResponse, error)
 	// DeleteFile deletes a file.
 	DeleteFile(context.Context, *DeleteFileRequest) (*types.Empty, error)
 	// DeleteAll deletes everything
@@ -5664,12 +7070,18 @@ func (*UnimplementedAPIServer) ListCommitStream(req *ListCommitRequest, srv API_
 func (*UnimplementedAPIServer) DeleteCommit(ctx context.Context, req *DeleteCommitRequest) (*types.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteCommit not implemented")
 }
+func (*UnimplementedAPIServer) ExpireCommits(ctx context.Context, req *ExpireCommitsRequest) (*CommitInfos, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExpireCommits not implemented")
+}
 func (*UnimplementedAPIServer) FlushCommit(req *FlushCommitRequest, srv API_FlushCommitServer) error {
 	return status.Errorf(codes.Unimplemented, "method FlushCommit not implemented")
 }
 func (*UnimplementedAPIServer) SubscribeCommit(req *SubscribeCommitRequest, srv API_SubscribeCommitServer) error {
 	return status.Errorf(codes.Unimplemented, "method SubscribeCommit not implemented")
 }
+func (*UnimplementedAPIServer) SubscribeFileChanges(req *SubscribeFileChangesRequest, srv API_SubscribeFileChangesServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeFileChanges not implemented")
+}
 func (*UnimplementedAPIServer) BuildCommit(ctx context.Context, req *BuildCommitRequest) (*Commit, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BuildCommit not implemented")
 }
@@ -5715,6 +7127,9 @@ func (*UnimplementedAPIServer) GlobFileStream(req *GlobFileRequest, srv API_Glob
 func (*UnimplementedAPIServer) DiffFile(ctx context.Context, req *DiffFileRequest) (*DiffFileResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DiffFile not implemented")
 }
+func (*UnimplementedAPIServer) SearchFiles(ctx context.Context, req *SearchFilesRequest) (*SearchFilesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchFiles not implemented")
+}
 func (*UnimplementedAPIServer) DeleteFile(ctx context.Context, req *DeleteFileRequest) (*types.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteFile not implemented")
 }
@@ -5912,6 +7327,24 @@ func _API_DeleteCommit_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _API_ExpireCommits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExpireCommitsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).ExpireCommits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pfs.API/ExpireCommits",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).ExpireCommits(ctx, req.(*ExpireCommitsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _API_FlushCommit_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(FlushCommitRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -5954,6 +7387,27 @@ func (x *aPISubscribeCommitServer) Send(m *CommitInfo) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _API_SubscribeFileChanges_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeFileChangesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(APIServer).SubscribeFileChanges(m, &aPISubscribeFileChangesServer{stream})
+}
+
+type API_SubscribeFileChangesServer interface {
+	Send(*FileChangeEvent) error
+	grpc.ServerStream
+}
+
+type aPISubscribeFileChangesServer struct {
+	grpc.ServerStream
+}
+
+func (x *aPISubscribeFileChangesServer) Send(m *FileChangeEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _API_BuildCommit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(BuildCommitRequest)
 	if err := dec(in); err != nil {
@@ -6244,6 +7698,24 @@ func _API_DiffFile_Handler(srv interface{}, ctx context.Context, dec func(interf
 	return interceptor(ctx, in, info, handler)
 }
 
+func _API_SearchFiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchFilesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).SearchFiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pfs.API/SearchFiles",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).SearchFiles(ctx, req.(*SearchFilesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _API_DeleteFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DeleteFileRequest)
 	if err := dec(in); err != nil {
@@ -6341,6 +7813,10 @@ var _API_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteCommit",
 			Handler:    _API_DeleteCommit_Handler,
 		},
+		{
+			MethodName: "ExpireCommits",
+			Handler:    _API_ExpireCommits_Handler,
+		},
 		{
 			MethodName: "BuildCommit",
 			Handler:    _API_BuildCommit_Handler,
@@ -6381,6 +7857,10 @@ var _API_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DiffFile",
 			Handler:    _API_DiffFile_Handler,
 		},
+		{
+			MethodName: "SearchFiles",
+			Handler:    _API_SearchFiles_Handler,
+		},
 		{
 			MethodName: "DeleteFile",
 			Handler:    _API_DeleteFile_Handler,
@@ -6406,6 +7886,11 @@ var _API_serviceDesc = grpc.ServiceDesc{
 			Handler:       _API_SubscribeCommit_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SubscribeFileChanges",
+			Handler:       _API_SubscribeFileChanges_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "PutFile",
 			Handler:       _API_PutFile_Handler,
@@ -6466,6 +7951,9 @@ type ObjectAPIClient interface {
 	ListTags(ctx context.Context, in *ListTagsRequest, opts ...grpc.CallOption) (ObjectAPI_ListTagsClient, error)
 	DeleteTags(ctx context.Context, in *DeleteTagsRequest, opts ...grpc.CallOption) (*DeleteTagsResponse, error)
 	Compact(ctx context.Context, in *types.Empty, opts ...grpc.CallOption) (*types.Empty, error)
+	// PackObjects packs small objects into one block, so that they don't each
+	// cost an object (and an object store request to read) in object storage.
+	PackObjects(ctx context.Context, in *PackObjectsRequest, opts ...grpc.CallOption) (*PackObjectsResponse, error)
 }
 
 type objectAPIClient struct {
@@ -6940,6 +8428,15 @@ func (c *objectAPIClient) Compact(ctx context.Context, in *types.Empty, opts ...
 	return out, nil
 }
 
+func (c *objectAPIClient) PackObjects(ctx context.Context, in *PackObjectsRequest, opts ...grpc.CallOption) (*PackObjectsResponse, error) {
+	out := new(PackObjectsResponse)
+	err := c.cc.Invoke(ctx, "/pfs.ObjectAPI/PackObjects", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ObjectAPIServer is the server API for ObjectAPI service.
 type ObjectAPIServer interface {
 	PutObject(ObjectAPI_PutObjectServer) error
@@ -6964,6 +8461,9 @@ type ObjectAPIServer interface {
 	ListTags(*ListTagsRequest, ObjectAPI_ListTagsServer) error
 	DeleteTags(context.Context, *DeleteTagsRequest) (*DeleteTagsResponse, error)
 	Compact(context.Context, *types.Empty) (*types.Empty, error)
+	// PackObjects packs small objects into one block, so that they don't each
+	// cost an object (and an object store request to read) in object storage.
+	PackObjects(context.Context, *PackObjectsRequest) (*PackObjectsResponse, error)
 }
 
 // UnimplementedObjectAPIServer can be embedded to have forward compatible implementations.
@@ -7030,6 +8530,9 @@ func (*UnimplementedObjectAPIServer) DeleteTags(ctx context.Context, req *Delete
 func (*UnimplementedObjectAPIServer) Compact(ctx context.Context, req *types.Empty) (*types.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Compact not implemented")
 }
+func (*UnimplementedObjectAPIServer) PackObjects(ctx context.Context, req *PackObjectsRequest) (*PackObjectsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PackObjects not implemented")
+}
 
 func RegisterObjectAPIServer(s *grpc.Server, srv ObjectAPIServer) {
 	s.RegisterService(&_ObjectAPI_serviceDesc, srv)
@@ -7451,6 +8954,24 @@ func _ObjectAPI_Compact_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ObjectAPI_PackObjects_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PackObjectsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ObjectAPIServer).PackObjects(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pfs.ObjectAPI/PackObjects",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ObjectAPIServer).PackObjects(ctx, req.(*PackObjectsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _ObjectAPI_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "pfs.ObjectAPI",
 	HandlerType: (*ObjectAPIServer)(nil),
@@ -7487,6 +9008,10 @@ var _ObjectAPI_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Compact",
 			Handler:    _ObjectAPI_Compact_Handler,
 		},
+		{
+			MethodName: "PackObjects",
+			Handler:    _ObjectAPI_PackObjects_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -7657,6 +9182,18 @@ func (m *BranchInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.Protection != nil {
+		{
+			size, err := m.Protection.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintPfs(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x3a
+	}
 	if len(m.DirectProvenance) > 0 {
 		for iNdEx := len(m.DirectProvenance) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -7733,6 +9270,52 @@ func (m *BranchInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *BranchProtection) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *BranchProtection) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *BranchProtection) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.AllowedCommitters) > 0 {
+		for iNdEx := len(m.AllowedCommitters) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.AllowedCommitters[iNdEx])
+			copy(dAtA[i:], m.AllowedCommitters[iNdEx])
+			i = encodeVarintPfs(dAtA, i, uint64(len(m.AllowedCommitters[iNdEx])))
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.RequireProvenance {
+		i--
+		if m.RequireProvenance {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *BranchInfos) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -7946,9 +9529,42 @@ func (m *RepoInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	if len(m.Branches) > 0 {
-		for iNdEx := len(m.Branches) - 1; iNdEx >= 0; iNdEx-- {
-			{
+	if m.CommitTTL != nil {
+		{
+			size, err := m.CommitTTL.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintPfs(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x52
+	}
+	if m.StoragePolicy != nil {
+		{
+			size, err := m.StoragePolicy.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintPfs(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x4a
+	}
+	if len(m.IndexExtractors) > 0 {
+		for iNdEx := len(m.IndexExtractors) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.IndexExtractors[iNdEx])
+			copy(dAtA[i:], m.IndexExtractors[iNdEx])
+			i = encodeVarintPfs(dAtA, i, uint64(len(m.IndexExtractors[iNdEx])))
+			i--
+			dAtA[i] = 0x42
+		}
+	}
+	if len(m.Branches) > 0 {
+		for iNdEx := len(m.Branches) - 1; iNdEx >= 0; iNdEx-- {
+			{
 				size, err := m.Branches[iNdEx].MarshalToSizedBuffer(dAtA[:i])
 				if err != nil {
 					return 0, err
@@ -8011,6 +9627,38 @@ func (m *RepoInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *StoragePolicy) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StoragePolicy) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *StoragePolicy) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.StorageClass != 0 {
+		i = encodeVarintPfs(dAtA, i, uint64(m.StorageClass))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *RepoAuthInfo) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -8468,6 +10116,25 @@ func (m *FileInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.FileCount != 0 {
+		i = encodeVarintPfs(dAtA, i, uint64(m.FileCount))
+		i--
+		dAtA[i] = 0x60
+	}
+	if len(m.Checksums) > 0 {
+		for iNdEx := len(m.Checksums) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Checksums[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintPfs(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x5a
+		}
+	}
 	if m.Committed != nil {
 		{
 			size, err := m.Committed.MarshalToSizedBuffer(dAtA[:i])
@@ -8549,6 +10216,45 @@ func (m *FileInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *Checksum) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Checksum) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Checksum) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Value) > 0 {
+		i -= len(m.Value)
+		copy(dAtA[i:], m.Value)
+		i = encodeVarintPfs(dAtA, i, uint64(len(m.Value)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Algorithm != 0 {
+		i = encodeVarintPfs(dAtA, i, uint64(m.Algorithm))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *ByteRange) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -8828,6 +10534,39 @@ func (m *CreateRepoRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.CommitTTL != nil {
+		{
+			size, err := m.CommitTTL.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintPfs(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x3a
+	}
+	if m.StoragePolicy != nil {
+		{
+			size, err := m.StoragePolicy.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintPfs(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x32
+	}
+	if len(m.IndexExtractors) > 0 {
+		for iNdEx := len(m.IndexExtractors) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.IndexExtractors[iNdEx])
+			copy(dAtA[i:], m.IndexExtractors[iNdEx])
+			i = encodeVarintPfs(dAtA, i, uint64(len(m.IndexExtractors[iNdEx])))
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
 	if m.Update {
 		i--
 		if m.Update {
@@ -9370,6 +11109,80 @@ func (m *ListCommitRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.StartAfter) > 0 {
+		i -= len(m.StartAfter)
+		copy(dAtA[i:], m.StartAfter)
+		i = encodeVarintPfs(dAtA, i, uint64(len(m.StartAfter)))
+		i--
+		dAtA[i] = 0x62
+	}
+	if m.Provenance != nil {
+		{
+			size, err := m.Provenance.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintPfs(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x5a
+	}
+	if len(m.Branch) > 0 {
+		i -= len(m.Branch)
+		copy(dAtA[i:], m.Branch)
+		i = encodeVarintPfs(dAtA, i, uint64(len(m.Branch)))
+		i--
+		dAtA[i] = 0x52
+	}
+	if m.FinishedBefore != nil {
+		{
+			size, err := m.FinishedBefore.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintPfs(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x4a
+	}
+	if m.FinishedAfter != nil {
+		{
+			size, err := m.FinishedAfter.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintPfs(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x42
+	}
+	if m.StartedBefore != nil {
+		{
+			size, err := m.StartedBefore.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintPfs(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x3a
+	}
+	if m.StartedAfter != nil {
+		{
+			size, err := m.StartedAfter.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintPfs(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x32
+	}
 	if m.Reverse {
 		i--
 		if m.Reverse {
@@ -9489,6 +11302,18 @@ func (m *CreateBranchRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.Protection != nil {
+		{
+			size, err := m.Protection.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintPfs(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x2a
+	}
 	if len(m.Provenance) > 0 {
 		for iNdEx := len(m.Provenance) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -9713,6 +11538,55 @@ func (m *DeleteCommitRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *ExpireCommitsRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ExpireCommitsRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ExpireCommitsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.DryRun {
+		i--
+		if m.DryRun {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Repo != nil {
+		{
+			size, err := m.Repo.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintPfs(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *FlushCommitRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -9792,6 +11666,16 @@ func (m *SubscribeCommitRequest) MarshalToSizedBuffer(dAtA []byte) (int, error)
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.ValidOnly {
+		i--
+		if m.ValidOnly {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x30
+	}
 	if m.Prov != nil {
 		{
 			size, err := m.Prov.MarshalToSizedBuffer(dAtA[:i])
@@ -9843,7 +11727,7 @@ func (m *SubscribeCommitRequest) MarshalToSizedBuffer(dAtA []byte) (int, error)
 	return len(dAtA) - i, nil
 }
 
-func (m *GetFileRequest) Marshal() (dAtA []byte, err error) {
+func (m *SubscribeFileChangesRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -9853,12 +11737,12 @@ func (m *GetFileRequest) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *GetFileRequest) MarshalTo(dAtA []byte) (int, error) {
+func (m *SubscribeFileChangesRequest) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *GetFileRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *SubscribeFileChangesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
@@ -9867,15 +11751,180 @@ func (m *GetFileRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	if m.SizeBytes != 0 {
-		i = encodeVarintPfs(dAtA, i, uint64(m.SizeBytes))
-		i--
-		dAtA[i] = 0x18
-	}
-	if m.OffsetBytes != 0 {
-		i = encodeVarintPfs(dAtA, i, uint64(m.OffsetBytes))
-		i--
-		dAtA[i] = 0x10
+	if m.From != nil {
+		{
+			size, err := m.From.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintPfs(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Branch) > 0 {
+		i -= len(m.Branch)
+		copy(dAtA[i:], m.Branch)
+		i = encodeVarintPfs(dAtA, i, uint64(len(m.Branch)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Repo != nil {
+		{
+			size, err := m.Repo.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintPfs(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *FileChange) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *FileChange) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *FileChange) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.Type != 0 {
+		i = encodeVarintPfs(dAtA, i, uint64(m.Type))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Path) > 0 {
+		i -= len(m.Path)
+		copy(dAtA[i:], m.Path)
+		i = encodeVarintPfs(dAtA, i, uint64(len(m.Path)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *FileChangeEvent) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *FileChangeEvent) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *FileChangeEvent) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Changes) > 0 {
+		for iNdEx := len(m.Changes) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Changes[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintPfs(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Commit != nil {
+		{
+			size, err := m.Commit.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintPfs(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *GetFileRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetFileRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *GetFileRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.SizeRecords != 0 {
+		i = encodeVarintPfs(dAtA, i, uint64(m.SizeRecords))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.OffsetRecords != 0 {
+		i = encodeVarintPfs(dAtA, i, uint64(m.OffsetRecords))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.Delimiter != 0 {
+		i = encodeVarintPfs(dAtA, i, uint64(m.Delimiter))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.SizeBytes != 0 {
+		i = encodeVarintPfs(dAtA, i, uint64(m.SizeBytes))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.OffsetBytes != 0 {
+		i = encodeVarintPfs(dAtA, i, uint64(m.OffsetBytes))
+		i--
+		dAtA[i] = 0x10
 	}
 	if m.File != nil {
 		{
@@ -9948,6 +11997,24 @@ func (m *PutFileRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.Checksums) > 0 {
+		dAtA68 := make([]byte, len(m.Checksums)*10)
+		var j67 int
+		for _, num := range m.Checksums {
+			for num >= 1<<7 {
+				dAtA68[j67] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j67++
+			}
+			dAtA68[j67] = uint8(num)
+			j67++
+		}
+		i -= j67
+		copy(dAtA[i:], dAtA68[:j67])
+		i = encodeVarintPfs(dAtA, i, uint64(j67))
+		i--
+		dAtA[i] = 0x62
+	}
 	if m.HeaderRecords != 0 {
 		i = encodeVarintPfs(dAtA, i, uint64(m.HeaderRecords))
 		i--
@@ -10240,6 +12307,24 @@ func (m *InspectFileRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.Checksums) > 0 {
+		dAtA77 := make([]byte, len(m.Checksums)*10)
+		var j76 int
+		for _, num := range m.Checksums {
+			for num >= 1<<7 {
+				dAtA77[j76] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j76++
+			}
+			dAtA77[j76] = uint8(num)
+			j76++
+		}
+		i -= j76
+		copy(dAtA[i:], dAtA77[:j76])
+		i = encodeVarintPfs(dAtA, i, uint64(j76))
+		i--
+		dAtA[i] = 0x12
+	}
 	if m.File != nil {
 		{
 			size, err := m.File.MarshalToSizedBuffer(dAtA[:i])
@@ -10279,6 +12364,55 @@ func (m *ListFileRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.Recursive {
+		i--
+		if m.Recursive {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x48
+	}
+	if m.Limit != 0 {
+		i = encodeVarintPfs(dAtA, i, uint64(m.Limit))
+		i--
+		dAtA[i] = 0x40
+	}
+	if len(m.StartAfter) > 0 {
+		i -= len(m.StartAfter)
+		copy(dAtA[i:], m.StartAfter)
+		i = encodeVarintPfs(dAtA, i, uint64(len(m.StartAfter)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if len(m.Prefix) > 0 {
+		i -= len(m.Prefix)
+		copy(dAtA[i:], m.Prefix)
+		i = encodeVarintPfs(dAtA, i, uint64(len(m.Prefix)))
+		i--
+		dAtA[i] = 0x32
+	}
+	if m.Summary {
+		i--
+		if m.Summary {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.SizeOnly {
+		i--
+		if m.SizeOnly {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x20
+	}
 	if m.History != 0 {
 		i = encodeVarintPfs(dAtA, i, uint64(m.History))
 		i--
@@ -10333,6 +12467,36 @@ func (m *WalkFileRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.SizeOnly {
+		i--
+		if m.SizeOnly {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.Aggregate {
+		i--
+		if m.Aggregate {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.FileType != 0 {
+		i = encodeVarintPfs(dAtA, i, uint64(m.FileType))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.MaxDepth != 0 {
+		i = encodeVarintPfs(dAtA, i, uint64(m.MaxDepth))
+		i--
+		dAtA[i] = 0x10
+	}
 	if m.File != nil {
 		{
 			size, err := m.File.MarshalToSizedBuffer(dAtA[:i])
@@ -10372,6 +12536,26 @@ func (m *GlobFileRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.Summary {
+		i--
+		if m.Summary {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.SizeOnly {
+		i--
+		if m.SizeOnly {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x18
+	}
 	if len(m.Pattern) > 0 {
 		i -= len(m.Pattern)
 		copy(dAtA[i:], m.Pattern)
@@ -10418,6 +12602,16 @@ func (m *FileInfos) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.SizeBytes != 0 {
+		i = encodeVarintPfs(dAtA, i, uint64(m.SizeBytes))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Count != 0 {
+		i = encodeVarintPfs(dAtA, i, uint64(m.Count))
+		i--
+		dAtA[i] = 0x10
+	}
 	if len(m.FileInfo) > 0 {
 		for iNdEx := len(m.FileInfo) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -10551,7 +12745,7 @@ func (m *DiffFileResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *DeleteFileRequest) Marshal() (dAtA []byte, err error) {
+func (m *IndexedFile) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -10561,12 +12755,12 @@ func (m *DeleteFileRequest) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *DeleteFileRequest) MarshalTo(dAtA []byte) (int, error) {
+func (m *IndexedFile) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *DeleteFileRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *IndexedFile) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
@@ -10575,8 +12769,234 @@ func (m *DeleteFileRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	if m.File != nil {
-		{
+	if len(m.Hash) > 0 {
+		i -= len(m.Hash)
+		copy(dAtA[i:], m.Hash)
+		i = encodeVarintPfs(dAtA, i, uint64(len(m.Hash)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Attributes) > 0 {
+		for k := range m.Attributes {
+			v := m.Attributes[k]
+			baseI := i
+			i -= len(v)
+			copy(dAtA[i:], v)
+			i = encodeVarintPfs(dAtA, i, uint64(len(v)))
+			i--
+			dAtA[i] = 0x12
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintPfs(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintPfs(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.Extractor) > 0 {
+		i -= len(m.Extractor)
+		copy(dAtA[i:], m.Extractor)
+		i = encodeVarintPfs(dAtA, i, uint64(len(m.Extractor)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Path) > 0 {
+		i -= len(m.Path)
+		copy(dAtA[i:], m.Path)
+		i = encodeVarintPfs(dAtA, i, uint64(len(m.Path)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *FileIndex) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *FileIndex) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *FileIndex) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Files) > 0 {
+		for iNdEx := len(m.Files) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Files[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintPfs(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SearchFilesRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SearchFilesRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SearchFilesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Attributes) > 0 {
+		for k := range m.Attributes {
+			v := m.Attributes[k]
+			baseI := i
+			i -= len(v)
+			copy(dAtA[i:], v)
+			i = encodeVarintPfs(dAtA, i, uint64(len(v)))
+			i--
+			dAtA[i] = 0x12
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintPfs(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintPfs(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.Pattern) > 0 {
+		i -= len(m.Pattern)
+		copy(dAtA[i:], m.Pattern)
+		i = encodeVarintPfs(dAtA, i, uint64(len(m.Pattern)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Commit != nil {
+		{
+			size, err := m.Commit.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintPfs(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SearchFilesResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SearchFilesResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SearchFilesResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Files) > 0 {
+		for iNdEx := len(m.Files) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Files[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintPfs(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Commit != nil {
+		{
+			size, err := m.Commit.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintPfs(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *DeleteFileRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DeleteFileRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DeleteFileRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.File != nil {
+		{
 			size, err := m.File.MarshalToSizedBuffer(dAtA[:i])
 			if err != nil {
 				return 0, err
@@ -10692,6 +13112,11 @@ func (m *PutObjectRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.StorageClass != 0 {
+		i = encodeVarintPfs(dAtA, i, uint64(m.StorageClass))
+		i--
+		dAtA[i] = 0x20
+	}
 	if m.Block != nil {
 		{
 			size, err := m.Block.MarshalToSizedBuffer(dAtA[:i])
@@ -11246,7 +13671,7 @@ func (m *DeleteObjectsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *DeleteTagsRequest) Marshal() (dAtA []byte, err error) {
+func (m *PackObjectsRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -11256,12 +13681,12 @@ func (m *DeleteTagsRequest) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *DeleteTagsRequest) MarshalTo(dAtA []byte) (int, error) {
+func (m *PackObjectsRequest) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *DeleteTagsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *PackObjectsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
@@ -11270,10 +13695,15 @@ func (m *DeleteTagsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	if len(m.Tags) > 0 {
-		for iNdEx := len(m.Tags) - 1; iNdEx >= 0; iNdEx-- {
+	if m.StorageClass != 0 {
+		i = encodeVarintPfs(dAtA, i, uint64(m.StorageClass))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Objects) > 0 {
+		for iNdEx := len(m.Objects) - 1; iNdEx >= 0; iNdEx-- {
 			{
-				size, err := m.Tags[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				size, err := m.Objects[iNdEx].MarshalToSizedBuffer(dAtA[:i])
 				if err != nil {
 					return 0, err
 				}
@@ -11287,7 +13717,7 @@ func (m *DeleteTagsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *DeleteTagsResponse) Marshal() (dAtA []byte, err error) {
+func (m *PackObjectsResponse) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -11297,12 +13727,12 @@ func (m *DeleteTagsResponse) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *DeleteTagsResponse) MarshalTo(dAtA []byte) (int, error) {
+func (m *PackObjectsResponse) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *DeleteTagsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *PackObjectsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
@@ -11311,36 +13741,19 @@ func (m *DeleteTagsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	return len(dAtA) - i, nil
-}
-
-func (m *CheckObjectRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+	if m.SizeBytes != 0 {
+		i = encodeVarintPfs(dAtA, i, uint64(m.SizeBytes))
+		i--
+		dAtA[i] = 0x18
 	}
-	return dAtA[:n], nil
-}
-
-func (m *CheckObjectRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
-}
-
-func (m *CheckObjectRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.XXX_unrecognized != nil {
-		i -= len(m.XXX_unrecognized)
-		copy(dAtA[i:], m.XXX_unrecognized)
+	if m.Objects != 0 {
+		i = encodeVarintPfs(dAtA, i, uint64(m.Objects))
+		i--
+		dAtA[i] = 0x10
 	}
-	if m.Object != nil {
+	if m.Block != nil {
 		{
-			size, err := m.Object.MarshalToSizedBuffer(dAtA[:i])
+			size, err := m.Block.MarshalToSizedBuffer(dAtA[:i])
 			if err != nil {
 				return 0, err
 			}
@@ -11353,7 +13766,7 @@ func (m *CheckObjectRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *CheckObjectResponse) Marshal() (dAtA []byte, err error) {
+func (m *DeleteTagsRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -11363,12 +13776,119 @@ func (m *CheckObjectResponse) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *CheckObjectResponse) MarshalTo(dAtA []byte) (int, error) {
+func (m *DeleteTagsRequest) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *CheckObjectResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *DeleteTagsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Tags) > 0 {
+		for iNdEx := len(m.Tags) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Tags[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintPfs(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *DeleteTagsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DeleteTagsResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DeleteTagsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CheckObjectRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CheckObjectRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CheckObjectRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.Object != nil {
+		{
+			size, err := m.Object.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintPfs(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CheckObjectResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CheckObjectResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CheckObjectResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
@@ -11593,6 +14113,31 @@ func (m *BranchInfo) Size() (n int) {
 			n += 1 + l + sovPfs(uint64(l))
 		}
 	}
+	if m.Protection != nil {
+		l = m.Protection.Size()
+		n += 1 + l + sovPfs(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *BranchProtection) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.RequireProvenance {
+		n += 2
+	}
+	if len(m.AllowedCommitters) > 0 {
+		for _, s := range m.AllowedCommitters {
+			l = len(s)
+			n += 1 + l + sovPfs(uint64(l))
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -11716,6 +14261,35 @@ func (m *RepoInfo) Size() (n int) {
 			n += 1 + l + sovPfs(uint64(l))
 		}
 	}
+	if len(m.IndexExtractors) > 0 {
+		for _, s := range m.IndexExtractors {
+			l = len(s)
+			n += 1 + l + sovPfs(uint64(l))
+		}
+	}
+	if m.StoragePolicy != nil {
+		l = m.StoragePolicy.Size()
+		n += 1 + l + sovPfs(uint64(l))
+	}
+	if m.CommitTTL != nil {
+		l = m.CommitTTL.Size()
+		n += 1 + l + sovPfs(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *StoragePolicy) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.StorageClass != 0 {
+		n += 1 + sovPfs(uint64(m.StorageClass))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -11941,6 +14515,34 @@ func (m *FileInfo) Size() (n int) {
 		l = m.Committed.Size()
 		n += 1 + l + sovPfs(uint64(l))
 	}
+	if len(m.Checksums) > 0 {
+		for _, e := range m.Checksums {
+			l = e.Size()
+			n += 1 + l + sovPfs(uint64(l))
+		}
+	}
+	if m.FileCount != 0 {
+		n += 1 + sovPfs(uint64(m.FileCount))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *Checksum) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Algorithm != 0 {
+		n += 1 + sovPfs(uint64(m.Algorithm))
+	}
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovPfs(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -12076,6 +14678,20 @@ func (m *CreateRepoRequest) Size() (n int) {
 	if m.Update {
 		n += 2
 	}
+	if len(m.IndexExtractors) > 0 {
+		for _, s := range m.IndexExtractors {
+			l = len(s)
+			n += 1 + l + sovPfs(uint64(l))
+		}
+	}
+	if m.StoragePolicy != nil {
+		l = m.StoragePolicy.Size()
+		n += 1 + l + sovPfs(uint64(l))
+	}
+	if m.CommitTTL != nil {
+		l = m.CommitTTL.Size()
+		n += 1 + l + sovPfs(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -12310,6 +14926,34 @@ func (m *ListCommitRequest) Size() (n int) {
 	if m.Reverse {
 		n += 2
 	}
+	if m.StartedAfter != nil {
+		l = m.StartedAfter.Size()
+		n += 1 + l + sovPfs(uint64(l))
+	}
+	if m.StartedBefore != nil {
+		l = m.StartedBefore.Size()
+		n += 1 + l + sovPfs(uint64(l))
+	}
+	if m.FinishedAfter != nil {
+		l = m.FinishedAfter.Size()
+		n += 1 + l + sovPfs(uint64(l))
+	}
+	if m.FinishedBefore != nil {
+		l = m.FinishedBefore.Size()
+		n += 1 + l + sovPfs(uint64(l))
+	}
+	l = len(m.Branch)
+	if l > 0 {
+		n += 1 + l + sovPfs(uint64(l))
+	}
+	if m.Provenance != nil {
+		l = m.Provenance.Size()
+		n += 1 + l + sovPfs(uint64(l))
+	}
+	l = len(m.StartAfter)
+	if l > 0 {
+		n += 1 + l + sovPfs(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -12358,6 +15002,10 @@ func (m *CreateBranchRequest) Size() (n int) {
 			n += 1 + l + sovPfs(uint64(l))
 		}
 	}
+	if m.Protection != nil {
+		l = m.Protection.Size()
+		n += 1 + l + sovPfs(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -12434,6 +15082,25 @@ func (m *DeleteCommitRequest) Size() (n int) {
 	return n
 }
 
+func (m *ExpireCommitsRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Repo != nil {
+		l = m.Repo.Size()
+		n += 1 + l + sovPfs(uint64(l))
+	}
+	if m.DryRun {
+		n += 2
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
 func (m *FlushCommitRequest) Size() (n int) {
 	if m == nil {
 		return 0
@@ -12483,27 +15150,32 @@ func (m *SubscribeCommitRequest) Size() (n int) {
 		l = m.Prov.Size()
 		n += 1 + l + sovPfs(uint64(l))
 	}
+	if m.ValidOnly {
+		n += 2
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
 	return n
 }
 
-func (m *GetFileRequest) Size() (n int) {
+func (m *SubscribeFileChangesRequest) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	if m.File != nil {
-		l = m.File.Size()
+	if m.Repo != nil {
+		l = m.Repo.Size()
 		n += 1 + l + sovPfs(uint64(l))
 	}
-	if m.OffsetBytes != 0 {
-		n += 1 + sovPfs(uint64(m.OffsetBytes))
+	l = len(m.Branch)
+	if l > 0 {
+		n += 1 + l + sovPfs(uint64(l))
 	}
-	if m.SizeBytes != 0 {
-		n += 1 + sovPfs(uint64(m.SizeBytes))
+	if m.From != nil {
+		l = m.From.Size()
+		n += 1 + l + sovPfs(uint64(l))
 	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
@@ -12511,14 +15183,18 @@ func (m *GetFileRequest) Size() (n int) {
 	return n
 }
 
-func (m *OverwriteIndex) Size() (n int) {
+func (m *FileChange) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	if m.Index != 0 {
-		n += 1 + sovPfs(uint64(m.Index))
+	l = len(m.Path)
+	if l > 0 {
+		n += 1 + l + sovPfs(uint64(l))
+	}
+	if m.Type != 0 {
+		n += 1 + sovPfs(uint64(m.Type))
 	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
@@ -12526,29 +15202,97 @@ func (m *OverwriteIndex) Size() (n int) {
 	return n
 }
 
-func (m *PutFileRequest) Size() (n int) {
+func (m *FileChangeEvent) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	if m.File != nil {
-		l = m.File.Size()
-		n += 1 + l + sovPfs(uint64(l))
-	}
-	l = len(m.Value)
-	if l > 0 {
+	if m.Commit != nil {
+		l = m.Commit.Size()
 		n += 1 + l + sovPfs(uint64(l))
 	}
-	l = len(m.Url)
-	if l > 0 {
-		n += 1 + l + sovPfs(uint64(l))
+	if len(m.Changes) > 0 {
+		for _, e := range m.Changes {
+			l = e.Size()
+			n += 1 + l + sovPfs(uint64(l))
+		}
 	}
-	if m.Recursive {
-		n += 2
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
 	}
-	if m.Delimiter != 0 {
-		n += 1 + sovPfs(uint64(m.Delimiter))
+	return n
+}
+
+func (m *GetFileRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.File != nil {
+		l = m.File.Size()
+		n += 1 + l + sovPfs(uint64(l))
+	}
+	if m.OffsetBytes != 0 {
+		n += 1 + sovPfs(uint64(m.OffsetBytes))
+	}
+	if m.SizeBytes != 0 {
+		n += 1 + sovPfs(uint64(m.SizeBytes))
+	}
+	if m.Delimiter != 0 {
+		n += 1 + sovPfs(uint64(m.Delimiter))
+	}
+	if m.OffsetRecords != 0 {
+		n += 1 + sovPfs(uint64(m.OffsetRecords))
+	}
+	if m.SizeRecords != 0 {
+		n += 1 + sovPfs(uint64(m.SizeRecords))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *OverwriteIndex) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Index != 0 {
+		n += 1 + sovPfs(uint64(m.Index))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *PutFileRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.File != nil {
+		l = m.File.Size()
+		n += 1 + l + sovPfs(uint64(l))
+	}
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovPfs(uint64(l))
+	}
+	l = len(m.Url)
+	if l > 0 {
+		n += 1 + l + sovPfs(uint64(l))
+	}
+	if m.Recursive {
+		n += 2
+	}
+	if m.Delimiter != 0 {
+		n += 1 + sovPfs(uint64(m.Delimiter))
 	}
 	if m.TargetFileDatums != 0 {
 		n += 1 + sovPfs(uint64(m.TargetFileDatums))
@@ -12563,6 +15307,13 @@ func (m *PutFileRequest) Size() (n int) {
 	if m.HeaderRecords != 0 {
 		n += 1 + sovPfs(uint64(m.HeaderRecords))
 	}
+	if len(m.Checksums) > 0 {
+		l = 0
+		for _, e := range m.Checksums {
+			l += sovPfs(uint64(e))
+		}
+		n += 1 + sovPfs(uint64(l)) + l
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -12657,6 +15408,13 @@ func (m *InspectFileRequest) Size() (n int) {
 		l = m.File.Size()
 		n += 1 + l + sovPfs(uint64(l))
 	}
+	if len(m.Checksums) > 0 {
+		l = 0
+		for _, e := range m.Checksums {
+			l += sovPfs(uint64(e))
+		}
+		n += 1 + sovPfs(uint64(l)) + l
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -12679,6 +15437,26 @@ func (m *ListFileRequest) Size() (n int) {
 	if m.History != 0 {
 		n += 1 + sovPfs(uint64(m.History))
 	}
+	if m.SizeOnly {
+		n += 2
+	}
+	if m.Summary {
+		n += 2
+	}
+	l = len(m.Prefix)
+	if l > 0 {
+		n += 1 + l + sovPfs(uint64(l))
+	}
+	l = len(m.StartAfter)
+	if l > 0 {
+		n += 1 + l + sovPfs(uint64(l))
+	}
+	if m.Limit != 0 {
+		n += 1 + sovPfs(uint64(m.Limit))
+	}
+	if m.Recursive {
+		n += 2
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -12695,6 +15473,18 @@ func (m *WalkFileRequest) Size() (n int) {
 		l = m.File.Size()
 		n += 1 + l + sovPfs(uint64(l))
 	}
+	if m.MaxDepth != 0 {
+		n += 1 + sovPfs(uint64(m.MaxDepth))
+	}
+	if m.FileType != 0 {
+		n += 1 + sovPfs(uint64(m.FileType))
+	}
+	if m.Aggregate {
+		n += 2
+	}
+	if m.SizeOnly {
+		n += 2
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -12715,6 +15505,12 @@ func (m *GlobFileRequest) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovPfs(uint64(l))
 	}
+	if m.SizeOnly {
+		n += 2
+	}
+	if m.Summary {
+		n += 2
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -12733,6 +15529,12 @@ func (m *FileInfos) Size() (n int) {
 			n += 1 + l + sovPfs(uint64(l))
 		}
 	}
+	if m.Count != 0 {
+		n += 1 + sovPfs(uint64(m.Count))
+	}
+	if m.SizeBytes != 0 {
+		n += 1 + sovPfs(uint64(m.SizeBytes))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -12786,6 +15588,106 @@ func (m *DiffFileResponse) Size() (n int) {
 	return n
 }
 
+func (m *IndexedFile) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Path)
+	if l > 0 {
+		n += 1 + l + sovPfs(uint64(l))
+	}
+	l = len(m.Extractor)
+	if l > 0 {
+		n += 1 + l + sovPfs(uint64(l))
+	}
+	if len(m.Attributes) > 0 {
+		for k, v := range m.Attributes {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovPfs(uint64(len(k))) + 1 + len(v) + sovPfs(uint64(len(v)))
+			n += mapEntrySize + 1 + sovPfs(uint64(mapEntrySize))
+		}
+	}
+	l = len(m.Hash)
+	if l > 0 {
+		n += 1 + l + sovPfs(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *FileIndex) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Files) > 0 {
+		for _, e := range m.Files {
+			l = e.Size()
+			n += 1 + l + sovPfs(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *SearchFilesRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Commit != nil {
+		l = m.Commit.Size()
+		n += 1 + l + sovPfs(uint64(l))
+	}
+	l = len(m.Pattern)
+	if l > 0 {
+		n += 1 + l + sovPfs(uint64(l))
+	}
+	if len(m.Attributes) > 0 {
+		for k, v := range m.Attributes {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovPfs(uint64(len(k))) + 1 + len(v) + sovPfs(uint64(len(v)))
+			n += mapEntrySize + 1 + sovPfs(uint64(mapEntrySize))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *SearchFilesResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Commit != nil {
+		l = m.Commit.Size()
+		n += 1 + l + sovPfs(uint64(l))
+	}
+	if len(m.Files) > 0 {
+		for _, e := range m.Files {
+			l = e.Size()
+			n += 1 + l + sovPfs(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
 func (m *DeleteFileRequest) Size() (n int) {
 	if m == nil {
 		return 0
@@ -12857,6 +15759,9 @@ func (m *PutObjectRequest) Size() (n int) {
 		l = m.Block.Size()
 		n += 1 + l + sovPfs(uint64(l))
 	}
+	if m.StorageClass != 0 {
+		n += 1 + sovPfs(uint64(m.StorageClass))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -13088,6 +15993,49 @@ func (m *DeleteObjectsResponse) Size() (n int) {
 	return n
 }
 
+func (m *PackObjectsRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Objects) > 0 {
+		for _, e := range m.Objects {
+			l = e.Size()
+			n += 1 + l + sovPfs(uint64(l))
+		}
+	}
+	if m.StorageClass != 0 {
+		n += 1 + sovPfs(uint64(m.StorageClass))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *PackObjectsResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Block != nil {
+		l = m.Block.Size()
+		n += 1 + l + sovPfs(uint64(l))
+	}
+	if m.Objects != 0 {
+		n += 1 + sovPfs(uint64(m.Objects))
+	}
+	if m.SizeBytes != 0 {
+		n += 1 + sovPfs(uint64(m.SizeBytes))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
 func (m *DeleteTagsRequest) Size() (n int) {
 	if m == nil {
 		return 0
@@ -13654,22 +16602,164 @@ func (m *BranchInfo) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipPfs(dAtA[iNdEx:])
-			if err != nil {
-				return err
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Protection", wireType)
 			}
-			if skippy < 0 {
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
 				return ErrInvalidLengthPfs
 			}
-			if (iNdEx + skippy) < 0 {
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
 				return ErrInvalidLengthPfs
 			}
-			if (iNdEx + skippy) > l {
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			if m.Protection == nil {
+				m.Protection = &BranchProtection{}
+			}
+			if err := m.Protection.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPfs(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *BranchProtection) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPfs
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: BranchProtection: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: BranchProtection: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RequireProvenance", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.RequireProvenance = bool(v != 0)
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AllowedCommitters", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AllowedCommitters = append(m.AllowedCommitters, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPfs(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
 			iNdEx += skippy
 		}
 	}
@@ -14369,6 +17459,110 @@ func (m *RepoInfo) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IndexExtractors", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.IndexExtractors = append(m.IndexExtractors, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StoragePolicy", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.StoragePolicy == nil {
+				m.StoragePolicy = &StoragePolicy{}
+			}
+			if err := m.StoragePolicy.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CommitTTL", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.CommitTTL == nil {
+				m.CommitTTL = &types.Duration{}
+			}
+			if err := m.CommitTTL.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPfs(dAtA[iNdEx:])
@@ -14394,7 +17588,7 @@ func (m *RepoInfo) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *RepoAuthInfo) Unmarshal(dAtA []byte) error {
+func (m *StoragePolicy) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -14417,17 +17611,17 @@ func (m *RepoAuthInfo) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: RepoAuthInfo: wiretype end group for non-group")
+			return fmt.Errorf("proto: StoragePolicy: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: RepoAuthInfo: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: StoragePolicy: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field AccessLevel", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field StorageClass", wireType)
 			}
-			m.AccessLevel = 0
+			m.StorageClass = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowPfs
@@ -14437,7 +17631,7 @@ func (m *RepoAuthInfo) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.AccessLevel |= auth.Scope(b&0x7F) << shift
+				m.StorageClass |= StorageClass(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -14467,7 +17661,7 @@ func (m *RepoAuthInfo) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *CommitOrigin) Unmarshal(dAtA []byte) error {
+func (m *RepoAuthInfo) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -14490,17 +17684,17 @@ func (m *CommitOrigin) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: CommitOrigin: wiretype end group for non-group")
+			return fmt.Errorf("proto: RepoAuthInfo: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: CommitOrigin: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: RepoAuthInfo: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Kind", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field AccessLevel", wireType)
 			}
-			m.Kind = 0
+			m.AccessLevel = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowPfs
@@ -14510,7 +17704,7 @@ func (m *CommitOrigin) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Kind |= OriginKind(b&0x7F) << shift
+				m.AccessLevel |= auth.Scope(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -14540,7 +17734,7 @@ func (m *CommitOrigin) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Commit) Unmarshal(dAtA []byte) error {
+func (m *CommitOrigin) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -14563,17 +17757,17 @@ func (m *Commit) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Commit: wiretype end group for non-group")
+			return fmt.Errorf("proto: CommitOrigin: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Commit: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: CommitOrigin: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Repo", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Kind", wireType)
 			}
-			var msglen int
+			m.Kind = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowPfs
@@ -14583,16 +17777,89 @@ func (m *Commit) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.Kind |= OriginKind(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPfs(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
 				return ErrInvalidLengthPfs
 			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Commit) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPfs
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Commit: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Commit: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Repo", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
 				return ErrInvalidLengthPfs
 			}
 			if postIndex > l {
@@ -15792,6 +19059,166 @@ func (m *FileInfo) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Checksums", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Checksums = append(m.Checksums, &Checksum{})
+			if err := m.Checksums[len(m.Checksums)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 12:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FileCount", wireType)
+			}
+			m.FileCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FileCount |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPfs(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Checksum) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPfs
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Checksum: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Checksum: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Algorithm", wireType)
+			}
+			m.Algorithm = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Algorithm |= ChecksumAlgorithm(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = append(m.Value[:0], dAtA[iNdEx:postIndex]...)
+			if m.Value == nil {
+				m.Value = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPfs(dAtA[iNdEx:])
@@ -16572,31 +19999,135 @@ func (m *CreateRepoRequest) Unmarshal(dAtA []byte) error {
 				}
 			}
 			m.Update = bool(v != 0)
-		default:
-			iNdEx = preIndex
-			skippy, err := skipPfs(dAtA[iNdEx:])
-			if err != nil {
-				return err
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IndexExtractors", wireType)
 			}
-			if skippy < 0 {
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthPfs
 			}
-			if (iNdEx + skippy) < 0 {
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
 				return ErrInvalidLengthPfs
 			}
-			if (iNdEx + skippy) > l {
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
+			m.IndexExtractors = append(m.IndexExtractors, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StoragePolicy", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.StoragePolicy == nil {
+				m.StoragePolicy = &StoragePolicy{}
+			}
+			if err := m.StoragePolicy.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CommitTTL", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.CommitTTL == nil {
+				m.CommitTTL = &types.Duration{}
+			}
+			if err := m.CommitTTL.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPfs(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *InspectRepoRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
@@ -18012,63 +21543,45 @@ func (m *ListCommitRequest) Unmarshal(dAtA []byte) error {
 				}
 			}
 			m.Reverse = bool(v != 0)
-		default:
-			iNdEx = preIndex
-			skippy, err := skipPfs(dAtA[iNdEx:])
-			if err != nil {
-				return err
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartedAfter", wireType)
 			}
-			if skippy < 0 {
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
 				return ErrInvalidLengthPfs
 			}
-			if (iNdEx + skippy) < 0 {
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
 				return ErrInvalidLengthPfs
 			}
-			if (iNdEx + skippy) > l {
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *CommitInfos) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowPfs
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
+			if m.StartedAfter == nil {
+				m.StartedAfter = &types.Timestamp{}
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
+			if err := m.StartedAfter.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: CommitInfos: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: CommitInfos: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			iNdEx = postIndex
+		case 7:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CommitInfo", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field StartedBefore", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -18095,68 +21608,52 @@ func (m *CommitInfos) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.CommitInfo = append(m.CommitInfo, &CommitInfo{})
-			if err := m.CommitInfo[len(m.CommitInfo)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if m.StartedBefore == nil {
+				m.StartedBefore = &types.Timestamp{}
+			}
+			if err := m.StartedBefore.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipPfs(dAtA[iNdEx:])
-			if err != nil {
-				return err
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FinishedAfter", wireType)
 			}
-			if skippy < 0 {
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
 				return ErrInvalidLengthPfs
 			}
-			if (iNdEx + skippy) < 0 {
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
 				return ErrInvalidLengthPfs
 			}
-			if (iNdEx + skippy) > l {
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *CreateBranchRequest) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowPfs
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
+			if m.FinishedAfter == nil {
+				m.FinishedAfter = &types.Timestamp{}
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
+			if err := m.FinishedAfter.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: CreateBranchRequest: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: CreateBranchRequest: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			iNdEx = postIndex
+		case 9:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Head", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field FinishedBefore", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -18183,16 +21680,16 @@ func (m *CreateBranchRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Head == nil {
-				m.Head = &Commit{}
+			if m.FinishedBefore == nil {
+				m.FinishedBefore = &types.Timestamp{}
 			}
-			if err := m.Head.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.FinishedBefore.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 2:
+		case 10:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SBranch", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Branch", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -18220,11 +21717,11 @@ func (m *CreateBranchRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.SBranch = string(dAtA[iNdEx:postIndex])
+			m.Branch = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 3:
+		case 11:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Branch", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Provenance", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -18251,18 +21748,18 @@ func (m *CreateBranchRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Branch == nil {
-				m.Branch = &Branch{}
+			if m.Provenance == nil {
+				m.Provenance = &Repo{}
 			}
-			if err := m.Branch.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.Provenance.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 4:
+		case 12:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Provenance", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field StartAfter", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowPfs
@@ -18272,25 +21769,23 @@ func (m *CreateBranchRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthPfs
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthPfs
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Provenance = append(m.Provenance, &Branch{})
-			if err := m.Provenance[len(m.Provenance)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.StartAfter = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -18317,7 +21812,7 @@ func (m *CreateBranchRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *InspectBranchRequest) Unmarshal(dAtA []byte) error {
+func (m *CommitInfos) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -18340,15 +21835,15 @@ func (m *InspectBranchRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: InspectBranchRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: CommitInfos: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: InspectBranchRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: CommitInfos: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Branch", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field CommitInfo", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -18375,10 +21870,8 @@ func (m *InspectBranchRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Branch == nil {
-				m.Branch = &Branch{}
-			}
-			if err := m.Branch.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.CommitInfo = append(m.CommitInfo, &CommitInfo{})
+			if err := m.CommitInfo[len(m.CommitInfo)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -18407,7 +21900,7 @@ func (m *InspectBranchRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ListBranchRequest) Unmarshal(dAtA []byte) error {
+func (m *CreateBranchRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -18430,15 +21923,15 @@ func (m *ListBranchRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ListBranchRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: CreateBranchRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ListBranchRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: CreateBranchRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Repo", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Head", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -18465,18 +21958,18 @@ func (m *ListBranchRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Repo == nil {
-				m.Repo = &Repo{}
+			if m.Head == nil {
+				m.Head = &Commit{}
 			}
-			if err := m.Repo.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.Head.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Reverse", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SBranch", wireType)
 			}
-			var v int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowPfs
@@ -18486,67 +21979,25 @@ func (m *ListBranchRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.Reverse = bool(v != 0)
-		default:
-			iNdEx = preIndex
-			skippy, err := skipPfs(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if skippy < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthPfs
 			}
-			if (iNdEx + skippy) < 0 {
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
 				return ErrInvalidLengthPfs
 			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *DeleteBranchRequest) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowPfs
-			}
-			if iNdEx >= l {
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: DeleteBranchRequest: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: DeleteBranchRequest: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			m.SBranch = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
 			if wireType != 2 {
 				return fmt.Errorf("proto: wrong wireType = %d for field Branch", wireType)
 			}
@@ -18582,11 +22033,11 @@ func (m *DeleteBranchRequest) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
-		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Force", wireType)
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Provenance", wireType)
 			}
-			var v int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowPfs
@@ -18596,12 +22047,62 @@ func (m *DeleteBranchRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.Force = bool(v != 0)
+			if msglen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Provenance = append(m.Provenance, &Branch{})
+			if err := m.Provenance[len(m.Provenance)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Protection", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Protection == nil {
+				m.Protection = &BranchProtection{}
+			}
+			if err := m.Protection.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPfs(dAtA[iNdEx:])
@@ -18627,7 +22128,7 @@ func (m *DeleteBranchRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *DeleteCommitRequest) Unmarshal(dAtA []byte) error {
+func (m *InspectBranchRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -18650,15 +22151,15 @@ func (m *DeleteCommitRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: DeleteCommitRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: InspectBranchRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: DeleteCommitRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: InspectBranchRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Commit", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Branch", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -18685,10 +22186,10 @@ func (m *DeleteCommitRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Commit == nil {
-				m.Commit = &Commit{}
+			if m.Branch == nil {
+				m.Branch = &Branch{}
 			}
-			if err := m.Commit.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.Branch.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -18717,7 +22218,7 @@ func (m *DeleteCommitRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *FlushCommitRequest) Unmarshal(dAtA []byte) error {
+func (m *ListBranchRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -18740,15 +22241,15 @@ func (m *FlushCommitRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: FlushCommitRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: ListBranchRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: FlushCommitRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ListBranchRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Commits", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Repo", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -18775,16 +22276,18 @@ func (m *FlushCommitRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Commits = append(m.Commits, &Commit{})
-			if err := m.Commits[len(m.Commits)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if m.Repo == nil {
+				m.Repo = &Repo{}
+			}
+			if err := m.Repo.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ToRepos", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Reverse", wireType)
 			}
-			var msglen int
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowPfs
@@ -18794,26 +22297,12 @@ func (m *FlushCommitRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthPfs
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthPfs
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.ToRepos = append(m.ToRepos, &Repo{})
-			if err := m.ToRepos[len(m.ToRepos)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
+			m.Reverse = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPfs(dAtA[iNdEx:])
@@ -18839,7 +22328,7 @@ func (m *FlushCommitRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SubscribeCommitRequest) Unmarshal(dAtA []byte) error {
+func (m *DeleteBranchRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -18862,15 +22351,15 @@ func (m *SubscribeCommitRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SubscribeCommitRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: DeleteBranchRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SubscribeCommitRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: DeleteBranchRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Repo", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Branch", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -18897,18 +22386,18 @@ func (m *SubscribeCommitRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Repo == nil {
-				m.Repo = &Repo{}
+			if m.Branch == nil {
+				m.Branch = &Branch{}
 			}
-			if err := m.Repo.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.Branch.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Branch", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Force", wireType)
 			}
-			var stringLen uint64
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowPfs
@@ -18918,82 +22407,69 @@ func (m *SubscribeCommitRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			m.Force = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPfs(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
 				return ErrInvalidLengthPfs
 			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
+			if (iNdEx + skippy) < 0 {
 				return ErrInvalidLengthPfs
 			}
-			if postIndex > l {
+			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Branch = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field From", wireType)
-			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowPfs
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				msglen |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			if msglen < 0 {
-				return ErrInvalidLengthPfs
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthPfs
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DeleteCommitRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPfs
 			}
-			if postIndex > l {
+			if iNdEx >= l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.From == nil {
-				m.From = &Commit{}
-			}
-			if err := m.From.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field State", wireType)
-			}
-			m.State = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowPfs
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.State |= CommitState(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
 			}
-		case 5:
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DeleteCommitRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DeleteCommitRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Prov", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Commit", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -19020,10 +22496,10 @@ func (m *SubscribeCommitRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Prov == nil {
-				m.Prov = &CommitProvenance{}
+			if m.Commit == nil {
+				m.Commit = &Commit{}
 			}
-			if err := m.Prov.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.Commit.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -19052,7 +22528,7 @@ func (m *SubscribeCommitRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *GetFileRequest) Unmarshal(dAtA []byte) error {
+func (m *ExpireCommitsRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -19075,15 +22551,15 @@ func (m *GetFileRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: GetFileRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: ExpireCommitsRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: GetFileRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ExpireCommitsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field File", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Repo", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -19110,37 +22586,18 @@ func (m *GetFileRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.File == nil {
-				m.File = &File{}
+			if m.Repo == nil {
+				m.Repo = &Repo{}
 			}
-			if err := m.File.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.Repo.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field OffsetBytes", wireType)
-			}
-			m.OffsetBytes = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowPfs
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.OffsetBytes |= int64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SizeBytes", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field DryRun", wireType)
 			}
-			m.SizeBytes = 0
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowPfs
@@ -19150,11 +22607,12 @@ func (m *GetFileRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.SizeBytes |= int64(b&0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			m.DryRun = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPfs(dAtA[iNdEx:])
@@ -19180,7 +22638,7 @@ func (m *GetFileRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *OverwriteIndex) Unmarshal(dAtA []byte) error {
+func (m *FlushCommitRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -19203,17 +22661,17 @@ func (m *OverwriteIndex) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: OverwriteIndex: wiretype end group for non-group")
+			return fmt.Errorf("proto: FlushCommitRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: OverwriteIndex: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: FlushCommitRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Commits", wireType)
 			}
-			m.Index = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowPfs
@@ -19223,11 +22681,60 @@ func (m *OverwriteIndex) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Index |= int64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Commits = append(m.Commits, &Commit{})
+			if err := m.Commits[len(m.Commits)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ToRepos", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			if msglen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ToRepos = append(m.ToRepos, &Repo{})
+			if err := m.ToRepos[len(m.ToRepos)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPfs(dAtA[iNdEx:])
@@ -19253,7 +22760,7 @@ func (m *OverwriteIndex) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PutFileRequest) Unmarshal(dAtA []byte) error {
+func (m *SubscribeCommitRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -19276,15 +22783,15 @@ func (m *PutFileRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PutFileRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: SubscribeCommitRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PutFileRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SubscribeCommitRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field File", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Repo", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -19311,18 +22818,18 @@ func (m *PutFileRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.File == nil {
-				m.File = &File{}
+			if m.Repo == nil {
+				m.Repo = &Repo{}
 			}
-			if err := m.File.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.Repo.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 3:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Branch", wireType)
 			}
-			var byteLen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowPfs
@@ -19332,31 +22839,29 @@ func (m *PutFileRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthPfs
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthPfs
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Value = append(m.Value[:0], dAtA[iNdEx:postIndex]...)
-			if m.Value == nil {
-				m.Value = []byte{}
-			}
+			m.Branch = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 5:
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Url", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field From", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowPfs
@@ -19366,29 +22871,33 @@ func (m *PutFileRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthPfs
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthPfs
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Url = string(dAtA[iNdEx:postIndex])
+			if m.From == nil {
+				m.From = &Commit{}
+			}
+			if err := m.From.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
-		case 6:
+		case 4:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Recursive", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field State", wireType)
 			}
-			var v int
+			m.State = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowPfs
@@ -19398,72 +22907,14 @@ func (m *PutFileRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= int(b&0x7F) << shift
+				m.State |= CommitState(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.Recursive = bool(v != 0)
-		case 7:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Delimiter", wireType)
-			}
-			m.Delimiter = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowPfs
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Delimiter |= Delimiter(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 8:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TargetFileDatums", wireType)
-			}
-			m.TargetFileDatums = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowPfs
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.TargetFileDatums |= int64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 9:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TargetFileBytes", wireType)
-			}
-			m.TargetFileBytes = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowPfs
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.TargetFileBytes |= int64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 10:
+		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field OverwriteIndex", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Prov", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -19490,18 +22941,18 @@ func (m *PutFileRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.OverwriteIndex == nil {
-				m.OverwriteIndex = &OverwriteIndex{}
+			if m.Prov == nil {
+				m.Prov = &CommitProvenance{}
 			}
-			if err := m.OverwriteIndex.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.Prov.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 11:
+		case 6:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field HeaderRecords", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ValidOnly", wireType)
 			}
-			m.HeaderRecords = 0
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowPfs
@@ -19511,11 +22962,12 @@ func (m *PutFileRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.HeaderRecords |= int64(b&0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			m.ValidOnly = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPfs(dAtA[iNdEx:])
@@ -19541,7 +22993,7 @@ func (m *PutFileRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PutFileRecord) Unmarshal(dAtA []byte) error {
+func (m *SubscribeFileChangesRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -19564,17 +23016,17 @@ func (m *PutFileRecord) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PutFileRecord: wiretype end group for non-group")
+			return fmt.Errorf("proto: SubscribeFileChangesRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PutFileRecord: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SubscribeFileChangesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SizeBytes", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Repo", wireType)
 			}
-			m.SizeBytes = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowPfs
@@ -19584,14 +23036,31 @@ func (m *PutFileRecord) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.SizeBytes |= int64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			if msglen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Repo == nil {
+				m.Repo = &Repo{}
+			}
+			if err := m.Repo.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ObjectHash", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Branch", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -19619,11 +23088,11 @@ func (m *PutFileRecord) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ObjectHash = string(dAtA[iNdEx:postIndex])
+			m.Branch = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field OverwriteIndex", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field From", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -19650,10 +23119,10 @@ func (m *PutFileRecord) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.OverwriteIndex == nil {
-				m.OverwriteIndex = &OverwriteIndex{}
+			if m.From == nil {
+				m.From = &Commit{}
 			}
-			if err := m.OverwriteIndex.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.From.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -19682,7 +23151,7 @@ func (m *PutFileRecord) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PutFileRecords) Unmarshal(dAtA []byte) error {
+func (m *FileChange) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -19705,37 +23174,17 @@ func (m *PutFileRecords) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PutFileRecords: wiretype end group for non-group")
+			return fmt.Errorf("proto: FileChange: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PutFileRecords: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: FileChange: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Split", wireType)
-			}
-			var v int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowPfs
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				v |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			m.Split = bool(v != 0)
-		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Records", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Path", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowPfs
@@ -19745,31 +23194,29 @@ func (m *PutFileRecords) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthPfs
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthPfs
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Records = append(m.Records, &PutFileRecord{})
-			if err := m.Records[len(m.Records)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Path = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 3:
+		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Tombstone", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
 			}
-			var v int
+			m.Type = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowPfs
@@ -19779,15 +23226,68 @@ func (m *PutFileRecords) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= int(b&0x7F) << shift
+				m.Type |= FileChangeType(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.Tombstone = bool(v != 0)
-		case 4:
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPfs(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *FileChangeEvent) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPfs
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: FileChangeEvent: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: FileChangeEvent: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Commit", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -19814,16 +23314,16 @@ func (m *PutFileRecords) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Header == nil {
-				m.Header = &PutFileRecord{}
+			if m.Commit == nil {
+				m.Commit = &Commit{}
 			}
-			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.Commit.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 5:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Footer", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Changes", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -19850,10 +23350,8 @@ func (m *PutFileRecords) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Footer == nil {
-				m.Footer = &PutFileRecord{}
-			}
-			if err := m.Footer.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Changes = append(m.Changes, &FileChange{})
+			if err := m.Changes[len(m.Changes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -19882,7 +23380,7 @@ func (m *PutFileRecords) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *CopyFileRequest) Unmarshal(dAtA []byte) error {
+func (m *GetFileRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -19905,15 +23403,15 @@ func (m *CopyFileRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: CopyFileRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: GetFileRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: CopyFileRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: GetFileRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Src", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field File", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -19940,18 +23438,18 @@ func (m *CopyFileRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Src == nil {
-				m.Src = &File{}
+			if m.File == nil {
+				m.File = &File{}
 			}
-			if err := m.Src.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.File.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Dst", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OffsetBytes", wireType)
 			}
-			var msglen int
+			m.OffsetBytes = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowPfs
@@ -19961,31 +23459,1728 @@ func (m *CopyFileRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.OffsetBytes |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthPfs
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthPfs
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SizeBytes", wireType)
 			}
-			if postIndex > l {
+			m.SizeBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SizeBytes |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Delimiter", wireType)
+			}
+			m.Delimiter = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Delimiter |= Delimiter(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OffsetRecords", wireType)
+			}
+			m.OffsetRecords = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.OffsetRecords |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SizeRecords", wireType)
+			}
+			m.SizeRecords = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SizeRecords |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPfs(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *OverwriteIndex) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPfs
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: OverwriteIndex: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: OverwriteIndex: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+			}
+			m.Index = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Index |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPfs(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PutFileRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPfs
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PutFileRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PutFileRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field File", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.File == nil {
+				m.File = &File{}
+			}
+			if err := m.File.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = append(m.Value[:0], dAtA[iNdEx:postIndex]...)
+			if m.Value == nil {
+				m.Value = []byte{}
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Url", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Url = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Recursive", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Recursive = bool(v != 0)
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Delimiter", wireType)
+			}
+			m.Delimiter = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Delimiter |= Delimiter(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TargetFileDatums", wireType)
+			}
+			m.TargetFileDatums = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TargetFileDatums |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TargetFileBytes", wireType)
+			}
+			m.TargetFileBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TargetFileBytes |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OverwriteIndex", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.OverwriteIndex == nil {
+				m.OverwriteIndex = &OverwriteIndex{}
+			}
+			if err := m.OverwriteIndex.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HeaderRecords", wireType)
+			}
+			m.HeaderRecords = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.HeaderRecords |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 12:
+			if wireType == 0 {
+				var v ChecksumAlgorithm
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowPfs
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= ChecksumAlgorithm(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.Checksums = append(m.Checksums, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowPfs
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthPfs
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthPfs
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				if elementCount != 0 && len(m.Checksums) == 0 {
+					m.Checksums = make([]ChecksumAlgorithm, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v ChecksumAlgorithm
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowPfs
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= ChecksumAlgorithm(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.Checksums = append(m.Checksums, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field Checksums", wireType)
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPfs(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PutFileRecord) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPfs
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PutFileRecord: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PutFileRecord: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SizeBytes", wireType)
+			}
+			m.SizeBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SizeBytes |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ObjectHash", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ObjectHash = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OverwriteIndex", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.OverwriteIndex == nil {
+				m.OverwriteIndex = &OverwriteIndex{}
+			}
+			if err := m.OverwriteIndex.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPfs(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PutFileRecords) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPfs
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PutFileRecords: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PutFileRecords: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Split", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Split = bool(v != 0)
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Records", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Records = append(m.Records, &PutFileRecord{})
+			if err := m.Records[len(m.Records)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tombstone", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Tombstone = bool(v != 0)
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &PutFileRecord{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Footer", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Footer == nil {
+				m.Footer = &PutFileRecord{}
+			}
+			if err := m.Footer.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPfs(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CopyFileRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPfs
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CopyFileRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CopyFileRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Src", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Src == nil {
+				m.Src = &File{}
+			}
+			if err := m.Src.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Dst", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Dst == nil {
+				m.Dst = &File{}
+			}
+			if err := m.Dst.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Overwrite", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Overwrite = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPfs(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *InspectFileRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPfs
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: InspectFileRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: InspectFileRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field File", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.File == nil {
+				m.File = &File{}
+			}
+			if err := m.File.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType == 0 {
+				var v ChecksumAlgorithm
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowPfs
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= ChecksumAlgorithm(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.Checksums = append(m.Checksums, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowPfs
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthPfs
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthPfs
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				if elementCount != 0 && len(m.Checksums) == 0 {
+					m.Checksums = make([]ChecksumAlgorithm, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v ChecksumAlgorithm
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowPfs
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= ChecksumAlgorithm(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.Checksums = append(m.Checksums, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field Checksums", wireType)
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPfs(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ListFileRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPfs
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ListFileRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ListFileRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field File", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.File == nil {
+				m.File = &File{}
+			}
+			if err := m.File.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Full", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Full = bool(v != 0)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field History", wireType)
+			}
+			m.History = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.History |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SizeOnly", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.SizeOnly = bool(v != 0)
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Summary", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Summary = bool(v != 0)
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Prefix", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Prefix = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartAfter", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StartAfter = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
+			}
+			m.Limit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Limit |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Recursive", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Recursive = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPfs(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *WalkFileRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPfs
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WalkFileRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WalkFileRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field File", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.File == nil {
+				m.File = &File{}
+			}
+			if err := m.File.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxDepth", wireType)
+			}
+			m.MaxDepth = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxDepth |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FileType", wireType)
+			}
+			m.FileType = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FileType |= FileType(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Aggregate", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Aggregate = bool(v != 0)
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SizeOnly", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.SizeOnly = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPfs(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GlobFileRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPfs
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GlobFileRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GlobFileRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Commit", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Dst == nil {
-				m.Dst = &File{}
+			if m.Commit == nil {
+				m.Commit = &Commit{}
+			}
+			if err := m.Commit.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Pattern", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPfs
 			}
-			if err := m.Dst.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
 			}
+			m.Pattern = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Overwrite", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field SizeOnly", wireType)
 			}
 			var v int
 			for shift := uint(0); ; shift += 7 {
@@ -20002,7 +25197,27 @@ func (m *CopyFileRequest) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
-			m.Overwrite = bool(v != 0)
+			m.SizeOnly = bool(v != 0)
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Summary", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Summary = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPfs(dAtA[iNdEx:])
@@ -20028,7 +25243,7 @@ func (m *CopyFileRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *InspectFileRequest) Unmarshal(dAtA []byte) error {
+func (m *FileInfos) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -20051,15 +25266,15 @@ func (m *InspectFileRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: InspectFileRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: FileInfos: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: InspectFileRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: FileInfos: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field File", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field FileInfo", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -20086,13 +25301,49 @@ func (m *InspectFileRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.File == nil {
-				m.File = &File{}
-			}
-			if err := m.File.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.FileInfo = append(m.FileInfo, &FileInfo{})
+			if err := m.FileInfo[len(m.FileInfo)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
+			}
+			m.Count = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Count |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SizeBytes", wireType)
+			}
+			m.SizeBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SizeBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPfs(dAtA[iNdEx:])
@@ -20118,7 +25369,7 @@ func (m *InspectFileRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ListFileRequest) Unmarshal(dAtA []byte) error {
+func (m *DiffFileRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -20141,15 +25392,15 @@ func (m *ListFileRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ListFileRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: DiffFileRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ListFileRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: DiffFileRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field File", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field NewFile", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -20176,18 +25427,18 @@ func (m *ListFileRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.File == nil {
-				m.File = &File{}
+			if m.NewFile == nil {
+				m.NewFile = &File{}
 			}
-			if err := m.File.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.NewFile.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Full", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OldFile", wireType)
 			}
-			var v int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowPfs
@@ -20197,17 +25448,33 @@ func (m *ListFileRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.Full = bool(v != 0)
+			if msglen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.OldFile == nil {
+				m.OldFile = &File{}
+			}
+			if err := m.OldFile.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field History", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Shallow", wireType)
 			}
-			m.History = 0
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowPfs
@@ -20217,11 +25484,12 @@ func (m *ListFileRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.History |= int64(b&0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			m.Shallow = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPfs(dAtA[iNdEx:])
@@ -20247,7 +25515,7 @@ func (m *ListFileRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *WalkFileRequest) Unmarshal(dAtA []byte) error {
+func (m *DiffFileResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -20270,15 +25538,15 @@ func (m *WalkFileRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: WalkFileRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: DiffFileResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: WalkFileRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: DiffFileResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field File", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field NewFiles", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -20305,10 +25573,42 @@ func (m *WalkFileRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.File == nil {
-				m.File = &File{}
+			m.NewFiles = append(m.NewFiles, &FileInfo{})
+			if err := m.NewFiles[len(m.NewFiles)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
-			if err := m.File.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OldFiles", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OldFiles = append(m.OldFiles, &FileInfo{})
+			if err := m.OldFiles[len(m.OldFiles)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -20337,7 +25637,7 @@ func (m *WalkFileRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *GlobFileRequest) Unmarshal(dAtA []byte) error {
+func (m *IndexedFile) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -20356,19 +25656,83 @@ func (m *GlobFileRequest) Unmarshal(dAtA []byte) error {
 			if b < 0x80 {
 				break
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: GlobFileRequest: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: GlobFileRequest: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: IndexedFile: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: IndexedFile: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Path", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Path = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Extractor", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Extractor = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Commit", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Attributes", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -20395,18 +25759,109 @@ func (m *GlobFileRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Commit == nil {
-				m.Commit = &Commit{}
+			if m.Attributes == nil {
+				m.Attributes = make(map[string]string)
 			}
-			if err := m.Commit.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowPfs
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowPfs
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthPfs
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthPfs
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowPfs
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthPfs
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthPfs
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipPfs(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthPfs
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
 			}
+			m.Attributes[mapkey] = mapvalue
 			iNdEx = postIndex
-		case 2:
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Pattern", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Hash", wireType)
 			}
-			var stringLen uint64
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowPfs
@@ -20416,23 +25871,25 @@ func (m *GlobFileRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthPfs
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthPfs
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Pattern = string(dAtA[iNdEx:postIndex])
+			m.Hash = append(m.Hash[:0], dAtA[iNdEx:postIndex]...)
+			if m.Hash == nil {
+				m.Hash = []byte{}
+			}
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -20459,7 +25916,7 @@ func (m *GlobFileRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *FileInfos) Unmarshal(dAtA []byte) error {
+func (m *FileIndex) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -20482,15 +25939,15 @@ func (m *FileInfos) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: FileInfos: wiretype end group for non-group")
+			return fmt.Errorf("proto: FileIndex: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: FileInfos: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: FileIndex: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field FileInfo", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Files", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -20517,8 +25974,8 @@ func (m *FileInfos) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.FileInfo = append(m.FileInfo, &FileInfo{})
-			if err := m.FileInfo[len(m.FileInfo)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Files = append(m.Files, &IndexedFile{})
+			if err := m.Files[len(m.Files)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -20547,7 +26004,7 @@ func (m *FileInfos) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *DiffFileRequest) Unmarshal(dAtA []byte) error {
+func (m *SearchFilesRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -20570,15 +26027,15 @@ func (m *DiffFileRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: DiffFileRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: SearchFilesRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: DiffFileRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SearchFilesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field NewFile", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Commit", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -20605,18 +26062,18 @@ func (m *DiffFileRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.NewFile == nil {
-				m.NewFile = &File{}
+			if m.Commit == nil {
+				m.Commit = &Commit{}
 			}
-			if err := m.NewFile.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.Commit.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field OldFile", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Pattern", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowPfs
@@ -20626,33 +26083,29 @@ func (m *DiffFileRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthPfs
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthPfs
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.OldFile == nil {
-				m.OldFile = &File{}
-			}
-			if err := m.OldFile.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Pattern = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Shallow", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Attributes", wireType)
 			}
-			var v int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowPfs
@@ -20662,12 +26115,119 @@ func (m *DiffFileRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.Shallow = bool(v != 0)
+			if msglen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Attributes == nil {
+				m.Attributes = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowPfs
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowPfs
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthPfs
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthPfs
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowPfs
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthPfs
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthPfs
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipPfs(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthPfs
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Attributes[mapkey] = mapvalue
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPfs(dAtA[iNdEx:])
@@ -20693,7 +26253,7 @@ func (m *DiffFileRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *DiffFileResponse) Unmarshal(dAtA []byte) error {
+func (m *SearchFilesResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -20716,15 +26276,15 @@ func (m *DiffFileResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: DiffFileResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: SearchFilesResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: DiffFileResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SearchFilesResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field NewFiles", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Commit", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -20751,14 +26311,16 @@ func (m *DiffFileResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.NewFiles = append(m.NewFiles, &FileInfo{})
-			if err := m.NewFiles[len(m.NewFiles)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if m.Commit == nil {
+				m.Commit = &Commit{}
+			}
+			if err := m.Commit.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field OldFiles", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Files", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -20785,8 +26347,8 @@ func (m *DiffFileResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.OldFiles = append(m.OldFiles, &FileInfo{})
-			if err := m.OldFiles[len(m.OldFiles)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Files = append(m.Files, &IndexedFile{})
+			if err := m.Files[len(m.Files)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -21230,6 +26792,25 @@ func (m *PutObjectRequest) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StorageClass", wireType)
+			}
+			m.StorageClass = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StorageClass |= StorageClass(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPfs(dAtA[iNdEx:])
@@ -22491,6 +28072,241 @@ func (m *DeleteObjectsResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *PackObjectsRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPfs
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PackObjectsRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PackObjectsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Objects", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Objects = append(m.Objects, &Object{})
+			if err := m.Objects[len(m.Objects)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StorageClass", wireType)
+			}
+			m.StorageClass = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StorageClass |= StorageClass(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPfs(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PackObjectsResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPfs
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PackObjectsResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PackObjectsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Block", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPfs
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Block == nil {
+				m.Block = &Block{}
+			}
+			if err := m.Block.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Objects", wireType)
+			}
+			m.Objects = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Objects |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SizeBytes", wireType)
+			}
+			m.SizeBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPfs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SizeBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPfs(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthPfs
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *DeleteTagsRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
@@ -0,0 +1,42 @@
+// Package grpcstatus creates and recognizes the GRPC statuses of typed
+// errors (e.g. pfs.ErrCommitNotFound), so that clients can tell them apart
+// without matching their messages.
+package grpcstatus
+
+import (
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// New returns a status with 'code' and 'message', whose details identify the
+// error by the type of resource that it's about (e.g. "pfs.commit") and its
+// reason (e.g. "not found"). Clients recognize the error with Is, rather than
+// by matching its message.
+func New(code codes.Code, resourceType string, resourceName string, reason string, message string) *status.Status {
+	s := status.New(code, message)
+	withDetails, err := s.WithDetails(&errdetails.ResourceInfo{
+		ResourceType: resourceType,
+		ResourceName: resourceName,
+		Description:  reason,
+	})
+	if err != nil {
+		return s // Shouldn't happen, ResourceInfo always marshals
+	}
+	return withDetails
+}
+
+// Is returns true if 'err' has a status from New with 'resourceType' and
+// 'reason'
+func Is(err error, resourceType string, reason string) bool {
+	s, ok := status.FromError(err)
+	if !ok || s == nil {
+		return false
+	}
+	for _, detail := range s.Details() {
+		if info, ok := detail.(*errdetails.ResourceInfo); ok && info.ResourceType == resourceType && info.Description == reason {
+			return true
+		}
+	}
+	return false
+}
@@ -2,18 +2,66 @@ package grpcutil
 
 import (
 	"errors"
+	"strings"
 
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 // ScrubGRPC removes GRPC error code information from 'err' if it came from
-// GRPC (and returns it unchanged otherwise)
+// GRPC (and returns it unchanged otherwise). Errors whose status identifies
+// them (see grpcstatus.New) keep their status, so that they can still be
+// recognized with grpcstatus.Is, though their message is scrubbed too.
 func ScrubGRPC(err error) error {
 	if err == nil {
 		return nil
 	}
 	if s, ok := status.FromError(err); ok {
+		if len(s.Proto().Details) > 0 {
+			return &scrubbedError{s}
+		}
 		return errors.New(s.Message())
 	}
 	return err
 }
+
+// scrubbedError is a GRPC error without the code in its message
+type scrubbedError struct {
+	s *status.Status
+}
+
+func (e *scrubbedError) Error() string {
+	return e.s.Message()
+}
+
+func (e *scrubbedError) GRPCStatus() *status.Status {
+	return e.s
+}
+
+// unavailableMessages are the messages of GRPC's Unavailable errors, which
+// are still in 'err' after ScrubGRPC (or wrapping) removes its code
+var unavailableMessages = []string{
+	"transport is closing",
+	"connection refused",
+	"connection reset by peer",
+	"all SubConns are in TransientFailure",
+	"Error while dialing",
+}
+
+// IsUnavailable returns true if 'err' is because the server couldn't be
+// reached, e.g. because it's restarting, rather than an error from the
+// server itself
+func IsUnavailable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if s, ok := status.FromError(err); ok && s.Code() == codes.Unavailable {
+		return true
+	}
+	for _, msg := range unavailableMessages {
+		if strings.Contains(err.Error(), msg) {
+			return true
+		}
+	}
+	return false
+}
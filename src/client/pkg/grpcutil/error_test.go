@@ -0,0 +1,38 @@
+package grpcutil
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestIsUnavailable(t *testing.T) {
+	require.False(t, IsUnavailable(nil))
+	require.True(t, IsUnavailable(status.Error(codes.Unavailable, "pachd is shutting down")))
+	// The code is gone once errors are scrubbed, but the message is left
+	unavailable := ScrubGRPC(status.Error(codes.Unavailable, "transport is closing"))
+	require.True(t, IsUnavailable(fmt.Errorf("error downloadData: %v", unavailable)))
+	require.False(t, IsUnavailable(status.Error(codes.NotFound, "file not found")))
+	require.False(t, IsUnavailable(errors.New("file not found")))
+}
+
+func TestScrubGRPC(t *testing.T) {
+	require.NoError(t, ScrubGRPC(nil))
+	err := ScrubGRPC(status.Error(codes.NotFound, "file not found"))
+	require.Equal(t, "file not found", err.Error())
+	_, ok := status.FromError(err)
+	require.False(t, ok)
+
+	// Statuses with details keep them, only the message is scrubbed
+	s, err := status.New(codes.NotFound, "file not found").WithDetails(&errdetails.ResourceInfo{ResourceType: "file"})
+	require.NoError(t, err)
+	err = ScrubGRPC(s.Err())
+	require.Equal(t, "file not found", err.Error())
+	require.Equal(t, codes.NotFound, status.Code(err))
+	require.Equal(t, 1, len(status.Convert(err).Details()))
+}
@@ -43,6 +43,15 @@ const (
 	// PPSScratchSpace is where pps workers store data while it's waiting to be
 	// processed.
 	PPSScratchSpace = ".scratch"
+	// PPSPrevOutputName is the name under PPSInputPrefix at which pipelines
+	// with previous_output set find their previous output commit.
+	PPSPrevOutputName = "prev"
+	// PPSJobScratchName is the name under PPSInputPrefix at which pipelines
+	// with job_scratch set find their job's scratch directory.
+	PPSJobScratchName = "job-scratch"
+	// PPSMergeWorkerEnv is set to "true" in the pods of a pipeline's merge
+	// workers (see MergeSpec), which only merge hashtrees.
+	PPSMergeWorkerEnv = "PPS_MERGE_WORKER"
 	// PPSWorkerPortEnv is environment variable name for the port that workers
 	// use for their gRPC server
 	PPSWorkerPortEnv = "PPS_WORKER_GRPC_PORT"
@@ -52,6 +61,23 @@ const (
 	// PPSWorkerUserContainerName is the name of the container that runs
 	// the user code to process data.
 	PPSWorkerUserContainerName = "user"
+	// PPSWorkerUserCodeContainerName is the name of the container that runs
+	// the user code in pipelines with separate_container set. In those
+	// pipelines, the PPSWorkerUserContainerName container runs only the worker
+	// binary.
+	PPSWorkerUserCodeContainerName = "user-code"
+	// PPSWorkerExecSocket is the unix socket on which the user code container
+	// of a pipeline with separate_container set accepts commands from the
+	// worker.
+	PPSWorkerExecSocket = "/pach-bin/exec.sock"
+	// PPSDatumProcessorSocket is the unix socket on which workers serve the
+	// DatumProcessor service (see src/client/processor) to the user code of
+	// pipelines with datum_processor set.
+	PPSDatumProcessorSocket = "/pach-bin/processor.sock"
+	// PPSDatumProcessorSocketEnv is the env var that has the path of
+	// PPSDatumProcessorSocket, in the environment of the user code of
+	// pipelines with datum_processor set.
+	PPSDatumProcessorSocketEnv = "PACH_DATUM_PROCESSOR_SOCKET"
 	// PPSWorkerSidecarContainerName is the name of the sidecar container
 	// that runs alongside of each worker container.
 	PPSWorkerSidecarContainerName = "storage"
@@ -64,10 +90,20 @@ const (
 	// OutputCommitIDEnv is an env var that is added to the environment of user
 	// pipelined code and indicates the id of the output commit.
 	OutputCommitIDEnv = "PACH_OUTPUT_COMMIT_ID"
+	// DatumSeedEnv is an env var that is added to the environment of the user
+	// code of pipelines with transform.datum_seed set, and is a seed derived
+	// from the hash of the datum being processed.
+	DatumSeedEnv = "PACH_DATUM_SEED"
 	// PProfPortEnv is the env var that sets a custom pprof port
 	PProfPortEnv = "PPROF_PORT"
 	// PeerPortEnv is the env var that sets a custom peer port
 	PeerPortEnv = "PEER_PORT"
+	// WorkerLogSinkEnv is the env var that sets the log sink (see
+	// src/server/worker/logs) to which workers send their logs
+	WorkerLogSinkEnv = "WORKER_LOG_SINK"
+	// WorkerHashtreeCacheSizeEnv is the env var that sets the size of the
+	// cache of hashtrees that each worker keeps on its local disk
+	WorkerHashtreeCacheSizeEnv = "WORKER_HASHTREE_CACHE_SIZE"
 )
 
 // NewJob creates a pps.Job.
@@ -150,6 +186,21 @@ func NewCronInput(name string, spec string) *pps.Input {
 	}
 }
 
+// NewSQLInput returns an input which runs 'query' against the database whose
+// URL is in the Kubernetes secret 'secret', on the cron schedule 'spec'. The
+// result of the latest query will be exposed to jobs as
+// `/pfs/<name>/data.csv`, and its schema as `/pfs/<name>/schema.json`.
+func NewSQLInput(name, spec, query, secret string) *pps.Input {
+	return &pps.Input{
+		SQL: &pps.SQLInput{
+			Name:   name,
+			Spec:   spec,
+			Query:  query,
+			Secret: secret,
+		},
+	}
+}
+
 // NewJobInput creates a pps.JobInput.
 func NewJobInput(repoName string, commitID string, glob string) *pps.JobInput {
 	return &pps.JobInput{
@@ -290,12 +341,30 @@ func (c APIClient) ListJobF(pipelineName string, inputCommit []*pfs.Commit,
 // If toPipelines is non-nil then only the jobs between commits and those
 // pipelines in the DAG will be returned.
 func (c APIClient) FlushJob(commits []*pfs.Commit, toPipelines []string, f func(*pps.JobInfo) error) error {
+	return c.flushJob(newFlushJobRequest(commits, toPipelines), f)
+}
+
+// FlushJobProgress is like FlushJob, except that it calls f with the
+// JobInfo of a job each time the job's state or progress (its datum counts
+// and download/upload bytes) changes, rather than once it has finished. The
+// last JobInfo that f gets for each job is the one that it finished with.
+func (c APIClient) FlushJobProgress(commits []*pfs.Commit, toPipelines []string, f func(*pps.JobInfo) error) error {
+	req := newFlushJobRequest(commits, toPipelines)
+	req.Progress = true
+	return c.flushJob(req, f)
+}
+
+func newFlushJobRequest(commits []*pfs.Commit, toPipelines []string) *pps.FlushJobRequest {
 	req := &pps.FlushJobRequest{
 		Commits: commits,
 	}
 	for _, pipeline := range toPipelines {
 		req.ToPipelines = append(req.ToPipelines, NewPipeline(pipeline))
 	}
+	return req
+}
+
+func (c APIClient) flushJob(req *pps.FlushJobRequest, f func(*pps.JobInfo) error) error {
 	client, err := c.PpsAPIClient.FlushJob(c.Ctx(), req)
 	if err != nil {
 		return grpcutil.ScrubGRPC(err)
@@ -328,6 +397,46 @@ func (c APIClient) FlushJobAll(commits []*pfs.Commit, toPipelines []string) ([]*
 	return result, nil
 }
 
+// WaitJob calls f with the JobInfo of the job each time its state changes,
+// and returns once it has finished. If downstream is true, the jobs
+// downstream of the job are waited for as well.
+func (c APIClient) WaitJob(jobID string, downstream bool, f func(*pps.JobInfo) error) error {
+	return c.waitJob(&pps.WaitJobRequest{
+		Job:        NewJob(jobID),
+		Downstream: downstream,
+	}, f)
+}
+
+// WaitCommit is like WaitJob, but waits for the job whose output commit is
+// repoName@commitID (if any). If downstream is true, the jobs downstream of
+// the commit are waited for as well, so WaitCommit can wait for all of the
+// jobs that process an input commit.
+func (c APIClient) WaitCommit(repoName string, commitID string, downstream bool, f func(*pps.JobInfo) error) error {
+	return c.waitJob(&pps.WaitJobRequest{
+		Commit:     NewCommit(repoName, commitID),
+		Downstream: downstream,
+	}, f)
+}
+
+func (c APIClient) waitJob(req *pps.WaitJobRequest, f func(*pps.JobInfo) error) error {
+	client, err := c.PpsAPIClient.WaitJob(c.Ctx(), req)
+	if err != nil {
+		return grpcutil.ScrubGRPC(err)
+	}
+	for {
+		jobInfo, err := client.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return grpcutil.ScrubGRPC(err)
+		}
+		if err := f(jobInfo); err != nil {
+			return err
+		}
+	}
+}
+
 // DeleteJob deletes a job.
 func (c APIClient) DeleteJob(jobID string) error {
 	_, err := c.PpsAPIClient.DeleteJob(
@@ -350,6 +459,29 @@ func (c APIClient) StopJob(jobID string) error {
 	return grpcutil.ScrubGRPC(err)
 }
 
+// PauseJob pauses a job. Its workers finish the datums they're processing,
+// but don't start any more until the job is resumed with ResumeJob.
+func (c APIClient) PauseJob(jobID string) error {
+	_, err := c.PpsAPIClient.PauseJob(
+		c.Ctx(),
+		&pps.PauseJobRequest{
+			Job: NewJob(jobID),
+		},
+	)
+	return grpcutil.ScrubGRPC(err)
+}
+
+// ResumeJob resumes a job that was paused with PauseJob.
+func (c APIClient) ResumeJob(jobID string) error {
+	_, err := c.PpsAPIClient.ResumeJob(
+		c.Ctx(),
+		&pps.ResumeJobRequest{
+			Job: NewJob(jobID),
+		},
+	)
+	return grpcutil.ScrubGRPC(err)
+}
+
 // RestartDatum restarts a datum that's being processed as part of a job.
 // datumFilter is a slice of strings which are matched against either the Path
 // or Hash of the datum, the order of the strings in datumFilter is irrelevant.
@@ -364,6 +496,22 @@ func (c APIClient) RestartDatum(jobID string, datumFilter []string) error {
 	return grpcutil.ScrubGRPC(err)
 }
 
+// RestartJob reruns a failed or killed job over the same input commits,
+// processing only the datums that it didn't process successfully. It returns
+// the output commit of the new job.
+func (c APIClient) RestartJob(jobID string) (*pfs.Commit, error) {
+	response, err := c.PpsAPIClient.RestartJob(
+		c.Ctx(),
+		&pps.RestartJobRequest{
+			Job: NewJob(jobID),
+		},
+	)
+	if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	return response.OutputCommit, nil
+}
+
 // ListDatum returns info about all datums in a Job
 func (c APIClient) ListDatum(jobID string, pageSize int64, page int64) (*pps.ListDatumResponse, error) {
 	client, err := c.PpsAPIClient.ListDatumStream(
@@ -425,6 +573,37 @@ func (c APIClient) ListDatumF(jobID string, pageSize int64, page int64, f func(d
 	}
 }
 
+// ListDatumFilterF returns info about the datums in a Job that match the
+// filters in 'req' (see pps.ListDatumRequest), calling f with each datum info.
+// It returns the summary of the datums that matched.
+func (c APIClient) ListDatumFilterF(req *pps.ListDatumRequest, f func(di *pps.DatumInfo) error) (*pps.DatumSummary, error) {
+	client, err := c.PpsAPIClient.ListDatumStream(c.Ctx(), req)
+	if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	var summary *pps.DatumSummary
+	for {
+		resp, err := client.Recv()
+		if err == io.EOF {
+			return summary, nil
+		} else if err != nil {
+			return nil, grpcutil.ScrubGRPC(err)
+		}
+		if resp.Summary != nil {
+			summary = resp.Summary
+		}
+		if resp.DatumInfo == nil {
+			continue
+		}
+		if err := f(resp.DatumInfo); err != nil {
+			if err == errutil.ErrBreak {
+				return summary, nil
+			}
+			return nil, err
+		}
+	}
+}
+
 // InspectDatum returns info about a single datum
 func (c APIClient) InspectDatum(jobID string, datumID string) (*pps.DatumInfo, error) {
 	datumInfo, err := c.PpsAPIClient.InspectDatum(
@@ -442,6 +621,36 @@ func (c APIClient) InspectDatum(jobID string, datumID string) (*pps.DatumInfo, e
 	return datumInfo, nil
 }
 
+// InspectJobStats returns the aggregated datum stats of a job. The job's
+// pipeline must have stats enabled.
+func (c APIClient) InspectJobStats(jobID string) (*pps.JobStats, error) {
+	jobStats, err := c.PpsAPIClient.InspectJobStats(
+		c.Ctx(),
+		&pps.InspectJobStatsRequest{
+			Job: NewJob(jobID),
+		},
+	)
+	if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	return jobStats, nil
+}
+
+// InspectJobAttestation returns the attestation of the job with ID 'jobID',
+// once its signature has been verified.
+func (c APIClient) InspectJobAttestation(jobID string) (*pps.JobAttestationInfo, error) {
+	attestationInfo, err := c.PpsAPIClient.InspectJobAttestation(
+		c.Ctx(),
+		&pps.InspectJobAttestationRequest{
+			Job: NewJob(jobID),
+		},
+	)
+	if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	return attestationInfo, nil
+}
+
 // LogsIter iterates through log messages returned from pps.GetLogs. Logs can
 // be fetched with 'Next()'. The log message received can be examined with
 // 'Message()', and any errors can be examined with 'Err()'.
@@ -559,6 +768,17 @@ func (c APIClient) CreatePipeline(
 	return grpcutil.ScrubGRPC(err)
 }
 
+// ValidatePipeline returns the problems with the pipeline spec in 'request',
+// without creating or updating the pipeline. Diagnostics with the severity
+// DIAGNOSTIC_ERROR would cause CreatePipeline to fail.
+func (c APIClient) ValidatePipeline(request *pps.CreatePipelineRequest) ([]*pps.PipelineDiagnostic, error) {
+	response, err := c.PpsAPIClient.ValidatePipeline(c.Ctx(), request)
+	if err != nil {
+		return nil, grpcutil.ScrubGRPC(err)
+	}
+	return response.Diagnostics, nil
+}
+
 // InspectPipeline returns info about a specific pipeline.
 func (c APIClient) InspectPipeline(pipelineName string) (*pps.PipelineInfo, error) {
 	pipelineInfo, err := c.PpsAPIClient.InspectPipeline(
@@ -644,6 +864,19 @@ func (c APIClient) StopPipeline(name string) error {
 	return grpcutil.ScrubGRPC(err)
 }
 
+// SetWorkerConfig replaces the worker config of a pipeline. Its running
+// workers apply the new config without restarting.
+func (c APIClient) SetWorkerConfig(name string, config *pps.WorkerConfig) error {
+	_, err := c.PpsAPIClient.SetWorkerConfig(
+		c.Ctx(),
+		&pps.SetWorkerConfigRequest{
+			Pipeline: NewPipeline(name),
+			Config:   config,
+		},
+	)
+	return grpcutil.ScrubGRPC(err)
+}
+
 // RunPipeline runs a pipeline. It can be passed a list of commit provenance.
 // This will trigger a new job provenant on those commits, effectively running the pipeline on the data in those commits.
 func (c APIClient) RunPipeline(name string, provenance []*pfs.CommitProvenance, jobID string) error {
@@ -722,6 +955,17 @@ func (c APIClient) GarbageCollect(memoryBytes int64) error {
 	return grpcutil.ScrubGRPC(err)
 }
 
+// InspectDAG returns the DAG of all repos and pipelines, with the state of
+// each pipeline and its last job. The DAG's Dot field holds it in GraphViz's
+// DOT language.
+func (c APIClient) InspectDAG() (*pps.DAG, error) {
+	dag, err := c.PpsAPIClient.InspectDAG(
+		c.Ctx(),
+		&pps.InspectDAGRequest{},
+	)
+	return dag, grpcutil.ScrubGRPC(err)
+}
+
 // GetDatumTotalTime sums the timing stats from a DatumInfo
 func GetDatumTotalTime(s *pps.ProcessStats) time.Duration {
 	totalDuration := time.Duration(0)
@@ -0,0 +1,86 @@
+package pps
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/grpcstatus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The resource types and reasons in the status of PPS errors (see
+// grpcstatus.New), which identify them across GRPC boundaries
+const (
+	jobResource      = "pps.job"
+	pipelineResource = "pps.pipeline"
+
+	reasonNotFound = "not found"
+)
+
+// ErrJobNotFound represents a job-not-found error, for either a job ID or the
+// output commit of a job
+type ErrJobNotFound struct {
+	Job          *Job
+	OutputCommit *pfs.Commit
+}
+
+// ErrPipelineNotFound represents a pipeline-not-found error.
+type ErrPipelineNotFound struct {
+	Pipeline *Pipeline
+}
+
+func (e ErrJobNotFound) Error() string {
+	if e.Job == nil && e.OutputCommit != nil {
+		return fmt.Sprintf("job with output commit %s not found", e.OutputCommit.ID)
+	}
+	return fmt.Sprintf("job %s not found", e.Job.ID)
+}
+
+// GRPCStatus returns the status that 'e' is sent with
+func (e ErrJobNotFound) GRPCStatus() *status.Status {
+	name := ""
+	if e.Job != nil {
+		name = e.Job.ID
+	}
+	return grpcstatus.New(codes.NotFound, jobResource, name, reasonNotFound, e.Error())
+}
+
+func (e ErrPipelineNotFound) Error() string {
+	return fmt.Sprintf("pipeline \"%s\" not found", e.Pipeline.Name)
+}
+
+// GRPCStatus returns the status that 'e' is sent with
+func (e ErrPipelineNotFound) GRPCStatus() *status.Status {
+	return grpcstatus.New(codes.NotFound, pipelineResource, e.Pipeline.Name, reasonNotFound, e.Error())
+}
+
+// The messages of PPS errors. They're only matched if an error has lost its
+// status, e.g. because it was wrapped in another error.
+var (
+	jobNotFoundRe      = regexp.MustCompile(`job (with output commit )?[^ ]+ not found`)
+	pipelineNotFoundRe = regexp.MustCompile(`pipeline [^ ]+ not found`)
+)
+
+// IsJobNotFoundErr returns true if 'err' is an ErrJobNotFound
+func IsJobNotFoundErr(err error) bool {
+	if err == nil {
+		return false
+	}
+	if grpcstatus.Is(err, jobResource, reasonNotFound) {
+		return true
+	}
+	return jobNotFoundRe.MatchString(err.Error())
+}
+
+// IsPipelineNotFoundErr returns true if 'err' is an ErrPipelineNotFound
+func IsPipelineNotFoundErr(err error) bool {
+	if err == nil {
+		return false
+	}
+	if grpcstatus.Is(err, pipelineResource, reasonNotFound) {
+		return true
+	}
+	return pipelineNotFoundRe.MatchString(err.Error())
+}
@@ -87,6 +87,12 @@ func InputBranches(input *Input) []*pfs.Branch {
 				Name: "master",
 			})
 		}
+		if input.SQL != nil {
+			result = append(result, &pfs.Branch{
+				Repo: &pfs.Repo{Name: input.SQL.Repo},
+				Name: "master",
+			})
+		}
 		if input.Git != nil {
 			result = append(result, &pfs.Branch{
 				Repo: &pfs.Repo{Name: input.Git.Name},
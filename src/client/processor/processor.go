@@ -0,0 +1,95 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"google.golang.org/grpc"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
+)
+
+// Run gets datums from the worker that started this process, which must be
+// the user code of a pipeline with datum_processor set, and calls 'f' on each
+// of them in turn. If 'f' returns an error, the datum fails (or is retried)
+// just as it would if the user code of another pipeline exited with an
+// error, and Run moves on to the next one. 'logf' writes a line to the
+// datum's logs. Run only returns if it loses its connection to the worker.
+func Run(f func(datum *Datum, logf func(format string, args ...interface{})) error) error {
+	socketPath := os.Getenv(client.PPSDatumProcessorSocketEnv)
+	if socketPath == "" {
+		return fmt.Errorf("%s isn't set, is this the user code of a pipeline with datum_processor set?", client.PPSDatumProcessorSocketEnv)
+	}
+	conn, err := grpc.Dial(socketPath,
+		grpc.WithInsecure(),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(grpcutil.MaxMsgSize), grpc.MaxCallSendMsgSize(grpcutil.MaxMsgSize)),
+		grpc.WithDialer(func(addr string, timeout time.Duration) (net.Conn, error) {
+			return net.DialTimeout("unix", addr, timeout)
+		}),
+	)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	c := NewDatumProcessorClient(conn)
+	ctx := context.Background()
+	for {
+		datum, err := c.NextDatum(ctx, &types.Empty{})
+		if err != nil {
+			return fmt.Errorf("error getting the next datum: %v", err)
+		}
+		request := &CompleteDatumRequest{DatumID: datum.ID}
+		logs := &datumLogs{c: c, datumID: datum.ID}
+		if err := f(datum, logs.logf); err != nil {
+			request.Error = err.Error()
+		}
+		// The datum's logs are flushed before it's completed, as lines that
+		// arrive afterwards aren't logged as the datum's
+		if err := logs.close(); err != nil {
+			return err
+		}
+		if _, err := c.CompleteDatum(ctx, request); err != nil {
+			return fmt.Errorf("error completing datum %s: %v", datum.ID, err)
+		}
+	}
+}
+
+// datumLogs sends a datum's log lines to the worker. The Log stream is only
+// opened once the datum logs something.
+type datumLogs struct {
+	c       DatumProcessorClient
+	datumID string
+	stream  DatumProcessor_LogClient
+	err     error
+}
+
+func (l *datumLogs) logf(format string, args ...interface{}) {
+	if l.err != nil {
+		return
+	}
+	if l.stream == nil {
+		if l.stream, l.err = l.c.Log(context.Background()); l.err != nil {
+			return
+		}
+	}
+	l.err = l.stream.Send(&LogMessage{DatumID: l.datumID, Message: fmt.Sprintf(format, args...)})
+}
+
+// close waits for the worker to receive the datum's logs, and returns the
+// first error that sending them hit
+func (l *datumLogs) close() error {
+	if l.stream != nil {
+		if _, err := l.stream.CloseAndRecv(); err != nil && l.err == nil {
+			l.err = err
+		}
+	}
+	if l.err != nil {
+		return fmt.Errorf("error sending the logs of datum %s: %v", l.datumID, l.err)
+	}
+	return nil
+}
@@ -0,0 +1,1495 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: client/processor/processor.proto
+
+package processor
+
+import (
+	context "context"
+	fmt "fmt"
+	_ "github.com/gogo/protobuf/gogoproto"
+	types "github.com/gogo/protobuf/types"
+	proto "github.com/golang/protobuf/proto"
+	pfs "github.com/pachyderm/pachyderm/src/client/pfs"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type Datum struct {
+	// id is the datum's ID, as shown by 'pachctl list datum'
+	ID     string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	JobID  string   `protobuf:"bytes,2,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Inputs []*Input `protobuf:"bytes,3,rep,name=inputs,proto3" json:"inputs,omitempty"`
+	// env has the variables that Pachyderm sets for this datum, that a
+	// pipeline that doesn't set datum_processor gets in its environment (such
+	// as each input's name, set to its path), as "NAME=value" strings
+	Env                  []string `protobuf:"bytes,4,rep,name=env,proto3" json:"env,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Datum) Reset()         { *m = Datum{} }
+func (m *Datum) String() string { return proto.CompactTextString(m) }
+func (*Datum) ProtoMessage()    {}
+func (*Datum) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2fbd1da589deb014, []int{0}
+}
+func (m *Datum) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Datum) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Datum.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Datum) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Datum.Merge(m, src)
+}
+func (m *Datum) XXX_Size() int {
+	return m.Size()
+}
+func (m *Datum) XXX_DiscardUnknown() {
+	xxx_messageInfo_Datum.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Datum proto.InternalMessageInfo
+
+func (m *Datum) GetID() string {
+	if m != nil {
+		return m.ID
+	}
+	return ""
+}
+
+func (m *Datum) GetJobID() string {
+	if m != nil {
+		return m.JobID
+	}
+	return ""
+}
+
+func (m *Datum) GetInputs() []*Input {
+	if m != nil {
+		return m.Inputs
+	}
+	return nil
+}
+
+func (m *Datum) GetEnv() []string {
+	if m != nil {
+		return m.Env
+	}
+	return nil
+}
+
+type Input struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// path is where the input's file or directory is, in /pfs
+	Path                 string    `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	File                 *pfs.File `protobuf:"bytes,3,opt,name=file,proto3" json:"file,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *Input) Reset()         { *m = Input{} }
+func (m *Input) String() string { return proto.CompactTextString(m) }
+func (*Input) ProtoMessage()    {}
+func (*Input) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2fbd1da589deb014, []int{1}
+}
+func (m *Input) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Input) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Input.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Input) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Input.Merge(m, src)
+}
+func (m *Input) XXX_Size() int {
+	return m.Size()
+}
+func (m *Input) XXX_DiscardUnknown() {
+	xxx_messageInfo_Input.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Input proto.InternalMessageInfo
+
+func (m *Input) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Input) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *Input) GetFile() *pfs.File {
+	if m != nil {
+		return m.File
+	}
+	return nil
+}
+
+type CompleteDatumRequest struct {
+	DatumID string `protobuf:"bytes,1,opt,name=datum_id,json=datumId,proto3" json:"datum_id,omitempty"`
+	// error is set if the user code failed to process the datum, in which case
+	// the datum is retried or failed, as it would be if the user code exited
+	// with an error
+	Error                string   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CompleteDatumRequest) Reset()         { *m = CompleteDatumRequest{} }
+func (m *CompleteDatumRequest) String() string { return proto.CompactTextString(m) }
+func (*CompleteDatumRequest) ProtoMessage()    {}
+func (*CompleteDatumRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2fbd1da589deb014, []int{2}
+}
+func (m *CompleteDatumRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CompleteDatumRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CompleteDatumRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CompleteDatumRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompleteDatumRequest.Merge(m, src)
+}
+func (m *CompleteDatumRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *CompleteDatumRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompleteDatumRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CompleteDatumRequest proto.InternalMessageInfo
+
+func (m *CompleteDatumRequest) GetDatumID() string {
+	if m != nil {
+		return m.DatumID
+	}
+	return ""
+}
+
+func (m *CompleteDatumRequest) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type LogMessage struct {
+	DatumID              string   `protobuf:"bytes,1,opt,name=datum_id,json=datumId,proto3" json:"datum_id,omitempty"`
+	Message              string   `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogMessage) Reset()         { *m = LogMessage{} }
+func (m *LogMessage) String() string { return proto.CompactTextString(m) }
+func (*LogMessage) ProtoMessage()    {}
+func (*LogMessage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2fbd1da589deb014, []int{3}
+}
+func (m *LogMessage) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *LogMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_LogMessage.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *LogMessage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogMessage.Merge(m, src)
+}
+func (m *LogMessage) XXX_Size() int {
+	return m.Size()
+}
+func (m *LogMessage) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogMessage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogMessage proto.InternalMessageInfo
+
+func (m *LogMessage) GetDatumID() string {
+	if m != nil {
+		return m.DatumID
+	}
+	return ""
+}
+
+func (m *LogMessage) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*Datum)(nil), "processor.Datum")
+	proto.RegisterType((*Input)(nil), "processor.Input")
+	proto.RegisterType((*CompleteDatumRequest)(nil), "processor.CompleteDatumRequest")
+	proto.RegisterType((*LogMessage)(nil), "processor.LogMessage")
+}
+
+func init() { proto.RegisterFile("client/processor/processor.proto", fileDescriptor_2fbd1da589deb014) }
+
+var fileDescriptor_2fbd1da589deb014 = []byte{
+	// 447 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x52, 0x4d, 0x8b, 0xd3, 0x50,
+	0x14, 0x6d, 0x9a, 0xa6, 0x35, 0xb7, 0x28, 0xc3, 0xa3, 0x4a, 0xa8, 0xd8, 0x86, 0x2c, 0x24, 0xab,
+	0x04, 0x3a, 0x0b, 0x5d, 0x8f, 0x1d, 0x21, 0xc3, 0x58, 0xe4, 0xe1, 0xca, 0xcd, 0x90, 0x8f, 0xdb,
+	0x34, 0x43, 0xd2, 0x1b, 0x93, 0x17, 0x71, 0x16, 0xfe, 0x0f, 0x7f, 0x92, 0x3b, 0xfd, 0x05, 0x45,
+	0xe2, 0x1f, 0x91, 0xbc, 0xa4, 0xd3, 0x2a, 0x16, 0x66, 0x11, 0x38, 0xf7, 0x9e, 0xf3, 0xce, 0x7b,
+	0xe7, 0xde, 0x80, 0x19, 0xa6, 0x09, 0x6e, 0x85, 0x9b, 0x17, 0x14, 0x62, 0x59, 0x52, 0x71, 0x40,
+	0x4e, 0x5e, 0x90, 0x20, 0xa6, 0xdf, 0x37, 0xa6, 0x93, 0xbd, 0x78, 0x5d, 0x36, 0x5f, 0x2b, 0x98,
+	0x4e, 0x62, 0x8a, 0x49, 0x42, 0xb7, 0x41, 0x5d, 0xf7, 0x79, 0x4c, 0x14, 0xa7, 0xe8, 0xca, 0x2a,
+	0xa8, 0xd6, 0x2e, 0x66, 0xb9, 0xb8, 0x6b, 0x49, 0xeb, 0x2b, 0x68, 0x4b, 0x5f, 0x54, 0x19, 0x7b,
+	0x06, 0xfd, 0x24, 0x32, 0x14, 0x53, 0xb1, 0xf5, 0x8b, 0x61, 0xbd, 0x9b, 0xf7, 0xbd, 0x25, 0xef,
+	0x27, 0x11, 0x33, 0x61, 0x78, 0x4b, 0xc1, 0x4d, 0x12, 0x19, 0x7d, 0xc9, 0xe9, 0xf5, 0x6e, 0xae,
+	0x5d, 0x51, 0xe0, 0x2d, 0xb9, 0x76, 0x4b, 0x81, 0x17, 0x31, 0x1b, 0x86, 0xc9, 0x36, 0xaf, 0x44,
+	0x69, 0xa8, 0xa6, 0x6a, 0x8f, 0x17, 0x67, 0xce, 0xe1, 0xe1, 0x5e, 0x43, 0xf0, 0x8e, 0x67, 0x67,
+	0xa0, 0xe2, 0xf6, 0xb3, 0x31, 0x30, 0x55, 0x5b, 0xe7, 0x0d, 0xb4, 0x56, 0xa0, 0x49, 0x09, 0x63,
+	0x30, 0xd8, 0xfa, 0x19, 0xb6, 0x0f, 0xe0, 0x12, 0x37, 0xbd, 0xdc, 0x17, 0x9b, 0xf6, 0x62, 0x2e,
+	0x31, 0x7b, 0x01, 0x83, 0x75, 0x92, 0xa2, 0xa1, 0x9a, 0x8a, 0x3d, 0x5e, 0xe8, 0x4e, 0x13, 0xfe,
+	0x6d, 0x92, 0x22, 0x97, 0x6d, 0xeb, 0x03, 0x4c, 0xde, 0x50, 0x96, 0xa7, 0x28, 0x50, 0xc6, 0xe2,
+	0xf8, 0xa9, 0xc2, 0x52, 0xb0, 0x97, 0xf0, 0x28, 0x6a, 0xea, 0x9b, 0xfb, 0x8c, 0xe3, 0x7a, 0x37,
+	0x1f, 0x49, 0x8d, 0xb7, 0xe4, 0x23, 0x49, 0x7a, 0x11, 0x9b, 0x80, 0x86, 0x45, 0x41, 0x45, 0x77,
+	0x67, 0x5b, 0x58, 0x2b, 0x80, 0x6b, 0x8a, 0xdf, 0x61, 0x59, 0xfa, 0x31, 0x3e, 0xd8, 0xcb, 0x80,
+	0x51, 0xd6, 0x1e, 0xe9, 0xdc, 0xf6, 0xe5, 0xe2, 0x87, 0x02, 0x4f, 0xa4, 0xfc, 0xfd, 0x7e, 0x50,
+	0xec, 0x15, 0xe8, 0x2b, 0xfc, 0x22, 0xba, 0x5d, 0x38, 0xed, 0xca, 0x9c, 0xfd, 0xca, 0x9c, 0xcb,
+	0x66, 0x65, 0xd3, 0xe3, 0xc9, 0x4a, 0xa5, 0xd5, 0x63, 0x57, 0xf0, 0xf8, 0xaf, 0xc4, 0x6c, 0x7e,
+	0x24, 0xfa, 0xdf, 0x2c, 0xa6, 0x27, 0xdc, 0xad, 0x1e, 0x7b, 0x0d, 0xea, 0x35, 0xc5, 0xec, 0xe9,
+	0x91, 0xc3, 0x21, 0xf7, 0xe9, 0x73, 0xb6, 0x72, 0x71, 0xf9, 0xbd, 0x9e, 0x29, 0x3f, 0xeb, 0x99,
+	0xf2, 0xab, 0x9e, 0x29, 0xdf, 0x7e, 0xcf, 0x7a, 0x1f, 0xcf, 0xe3, 0x44, 0x6c, 0xaa, 0xc0, 0x09,
+	0x29, 0x73, 0x73, 0x3f, 0xdc, 0xdc, 0x45, 0x58, 0x1c, 0xa3, 0xb2, 0x08, 0xdd, 0x7f, 0xff, 0xf8,
+	0x60, 0x28, 0xad, 0xcf, 0xff, 0x0c, 0x00, 0x67, 0xca, 0x88, 0xae, 0x0c, 0x03, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// DatumProcessorClient is the client API for DatumProcessor service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type DatumProcessorClient interface {
+	// NextDatum blocks until the worker has a datum for the user code to
+	// process. The datum's inputs are in /pfs, as they are for other pipelines,
+	// and its output goes in /pfs/out.
+	NextDatum(ctx context.Context, in *types.Empty, opts ...grpc.CallOption) (*Datum, error)
+	// CompleteDatum reports that the user code is done with a datum, and
+	// whether it failed.
+	CompleteDatum(ctx context.Context, in *CompleteDatumRequest, opts ...grpc.CallOption) (*types.Empty, error)
+	// Log writes lines to the logs of the datums they name, just as lines
+	// written to the user code's stdout and stderr are.
+	Log(ctx context.Context, opts ...grpc.CallOption) (DatumProcessor_LogClient, error)
+}
+
+type datumProcessorClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewDatumProcessorClient(cc *grpc.ClientConn) DatumProcessorClient {
+	return &datumProcessorClient{cc}
+}
+
+func (c *datumProcessorClient) NextDatum(ctx context.Context, in *types.Empty, opts ...grpc.CallOption) (*Datum, error) {
+	out := new(Datum)
+	err := c.cc.Invoke(ctx, "/processor.DatumProcessor/NextDatum", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *datumProcessorClient) CompleteDatum(ctx context.Context, in *CompleteDatumRequest, opts ...grpc.CallOption) (*types.Empty, error) {
+	out := new(types.Empty)
+	err := c.cc.Invoke(ctx, "/processor.DatumProcessor/CompleteDatum", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *datumProcessorClient) Log(ctx context.Context, opts ...grpc.CallOption) (DatumProcessor_LogClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_DatumProcessor_serviceDesc.Streams[0], "/processor.DatumProcessor/Log", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &datumProcessorLogClient{stream}
+	return x, nil
+}
+
+type DatumProcessor_LogClient interface {
+	Send(*LogMessage) error
+	CloseAndRecv() (*types.Empty, error)
+	grpc.ClientStream
+}
+
+type datumProcessorLogClient struct {
+	grpc.ClientStream
+}
+
+func (x *datumProcessorLogClient) Send(m *LogMessage) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *datumProcessorLogClient) CloseAndRecv() (*types.Empty, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(types.Empty)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// DatumProcessorServer is the server API for DatumProcessor service.
+type DatumProcessorServer interface {
+	// NextDatum blocks until the worker has a datum for the user code to
+	// process. The datum's inputs are in /pfs, as they are for other pipelines,
+	// and its output goes in /pfs/out.
+	NextDatum(context.Context, *types.Empty) (*Datum, error)
+	// CompleteDatum reports that the user code is done with a datum, and
+	// whether it failed.
+	CompleteDatum(context.Context, *CompleteDatumRequest) (*types.Empty, error)
+	// Log writes lines to the logs of the datums they name, just as lines
+	// written to the user code's stdout and stderr are.
+	Log(DatumProcessor_LogServer) error
+}
+
+// UnimplementedDatumProcessorServer can be embedded to have forward compatible implementations.
+type UnimplementedDatumProcessorServer struct {
+}
+
+func (*UnimplementedDatumProcessorServer) NextDatum(ctx context.Context, req *types.Empty) (*Datum, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NextDatum not implemented")
+}
+func (*UnimplementedDatumProcessorServer) CompleteDatum(ctx context.Context, req *CompleteDatumRequest) (*types.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompleteDatum not implemented")
+}
+func (*UnimplementedDatumProcessorServer) Log(srv DatumProcessor_LogServer) error {
+	return status.Errorf(codes.Unimplemented, "method Log not implemented")
+}
+
+func RegisterDatumProcessorServer(s *grpc.Server, srv DatumProcessorServer) {
+	s.RegisterService(&_DatumProcessor_serviceDesc, srv)
+}
+
+func _DatumProcessor_NextDatum_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(types.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DatumProcessorServer).NextDatum(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/processor.DatumProcessor/NextDatum",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DatumProcessorServer).NextDatum(ctx, req.(*types.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DatumProcessor_CompleteDatum_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompleteDatumRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DatumProcessorServer).CompleteDatum(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/processor.DatumProcessor/CompleteDatum",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DatumProcessorServer).CompleteDatum(ctx, req.(*CompleteDatumRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DatumProcessor_Log_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DatumProcessorServer).Log(&datumProcessorLogServer{stream})
+}
+
+type DatumProcessor_LogServer interface {
+	SendAndClose(*types.Empty) error
+	Recv() (*LogMessage, error)
+	grpc.ServerStream
+}
+
+type datumProcessorLogServer struct {
+	grpc.ServerStream
+}
+
+func (x *datumProcessorLogServer) SendAndClose(m *types.Empty) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *datumProcessorLogServer) Recv() (*LogMessage, error) {
+	m := new(LogMessage)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _DatumProcessor_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "processor.DatumProcessor",
+	HandlerType: (*DatumProcessorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "NextDatum",
+			Handler:    _DatumProcessor_NextDatum_Handler,
+		},
+		{
+			MethodName: "CompleteDatum",
+			Handler:    _DatumProcessor_CompleteDatum_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Log",
+			Handler:       _DatumProcessor_Log_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "client/processor/processor.proto",
+}
+
+func (m *Datum) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Datum) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Datum) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Env) > 0 {
+		for iNdEx := len(m.Env) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Env[iNdEx])
+			copy(dAtA[i:], m.Env[iNdEx])
+			i = encodeVarintProcessor(dAtA, i, uint64(len(m.Env[iNdEx])))
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if len(m.Inputs) > 0 {
+		for iNdEx := len(m.Inputs) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Inputs[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintProcessor(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.JobID) > 0 {
+		i -= len(m.JobID)
+		copy(dAtA[i:], m.JobID)
+		i = encodeVarintProcessor(dAtA, i, uint64(len(m.JobID)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.ID) > 0 {
+		i -= len(m.ID)
+		copy(dAtA[i:], m.ID)
+		i = encodeVarintProcessor(dAtA, i, uint64(len(m.ID)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Input) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Input) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Input) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.File != nil {
+		{
+			size, err := m.File.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintProcessor(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Path) > 0 {
+		i -= len(m.Path)
+		copy(dAtA[i:], m.Path)
+		i = encodeVarintProcessor(dAtA, i, uint64(len(m.Path)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintProcessor(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CompleteDatumRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CompleteDatumRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CompleteDatumRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Error) > 0 {
+		i -= len(m.Error)
+		copy(dAtA[i:], m.Error)
+		i = encodeVarintProcessor(dAtA, i, uint64(len(m.Error)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.DatumID) > 0 {
+		i -= len(m.DatumID)
+		copy(dAtA[i:], m.DatumID)
+		i = encodeVarintProcessor(dAtA, i, uint64(len(m.DatumID)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *LogMessage) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LogMessage) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *LogMessage) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Message) > 0 {
+		i -= len(m.Message)
+		copy(dAtA[i:], m.Message)
+		i = encodeVarintProcessor(dAtA, i, uint64(len(m.Message)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.DatumID) > 0 {
+		i -= len(m.DatumID)
+		copy(dAtA[i:], m.DatumID)
+		i = encodeVarintProcessor(dAtA, i, uint64(len(m.DatumID)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintProcessor(dAtA []byte, offset int, v uint64) int {
+	offset -= sovProcessor(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *Datum) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ID)
+	if l > 0 {
+		n += 1 + l + sovProcessor(uint64(l))
+	}
+	l = len(m.JobID)
+	if l > 0 {
+		n += 1 + l + sovProcessor(uint64(l))
+	}
+	if len(m.Inputs) > 0 {
+		for _, e := range m.Inputs {
+			l = e.Size()
+			n += 1 + l + sovProcessor(uint64(l))
+		}
+	}
+	if len(m.Env) > 0 {
+		for _, s := range m.Env {
+			l = len(s)
+			n += 1 + l + sovProcessor(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *Input) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovProcessor(uint64(l))
+	}
+	l = len(m.Path)
+	if l > 0 {
+		n += 1 + l + sovProcessor(uint64(l))
+	}
+	if m.File != nil {
+		l = m.File.Size()
+		n += 1 + l + sovProcessor(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *CompleteDatumRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.DatumID)
+	if l > 0 {
+		n += 1 + l + sovProcessor(uint64(l))
+	}
+	l = len(m.Error)
+	if l > 0 {
+		n += 1 + l + sovProcessor(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *LogMessage) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.DatumID)
+	if l > 0 {
+		n += 1 + l + sovProcessor(uint64(l))
+	}
+	l = len(m.Message)
+	if l > 0 {
+		n += 1 + l + sovProcessor(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func sovProcessor(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozProcessor(x uint64) (n int) {
+	return sovProcessor(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *Datum) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowProcessor
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Datum: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Datum: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProcessor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthProcessor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthProcessor
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field JobID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProcessor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthProcessor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthProcessor
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.JobID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Inputs", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProcessor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthProcessor
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthProcessor
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Inputs = append(m.Inputs, &Input{})
+			if err := m.Inputs[len(m.Inputs)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Env", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProcessor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthProcessor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthProcessor
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Env = append(m.Env, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipProcessor(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthProcessor
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthProcessor
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Input) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowProcessor
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Input: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Input: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProcessor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthProcessor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthProcessor
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Path", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProcessor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthProcessor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthProcessor
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Path = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field File", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProcessor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthProcessor
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthProcessor
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.File == nil {
+				m.File = &pfs.File{}
+			}
+			if err := m.File.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipProcessor(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthProcessor
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthProcessor
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CompleteDatumRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowProcessor
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CompleteDatumRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CompleteDatumRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DatumID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProcessor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthProcessor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthProcessor
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DatumID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Error", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProcessor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthProcessor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthProcessor
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Error = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipProcessor(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthProcessor
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthProcessor
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *LogMessage) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowProcessor
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LogMessage: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LogMessage: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DatumID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProcessor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthProcessor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthProcessor
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DatumID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProcessor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthProcessor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthProcessor
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Message = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipProcessor(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthProcessor
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthProcessor
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipProcessor(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	depth := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowProcessor
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowProcessor
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+		case 1:
+			iNdEx += 8
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowProcessor
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if length < 0 {
+				return 0, ErrInvalidLengthProcessor
+			}
+			iNdEx += length
+		case 3:
+			depth++
+		case 4:
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupProcessor
+			}
+			depth--
+		case 5:
+			iNdEx += 4
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthProcessor
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
+	}
+	return 0, io.ErrUnexpectedEOF
+}
+
+var (
+	ErrInvalidLengthProcessor        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowProcessor          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupProcessor = fmt.Errorf("proto: unexpected end of group")
+)
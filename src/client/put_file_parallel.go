@@ -0,0 +1,245 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sync"
+
+	"github.com/pachyderm/pachyderm/src/client/limit"
+	"github.com/pachyderm/pachyderm/src/server/pkg/uuid"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// putFileManifest records the progress of a PutFileParallel upload, so that
+// it can be resumed
+type putFileManifest struct {
+	Repo string `json:"repo"`
+	// Target is the branch or commit that the caller passed in, and Commit is
+	// the open commit that the parts are put in
+	Target    string `json:"target"`
+	Commit    string `json:"commit"`
+	Path      string `json:"path"`
+	Size      int64  `json:"size"`
+	PartSize  int64  `json:"part_size"`
+	Overwrite bool   `json:"overwrite"`
+	// StartedCommit is set if PutFileParallel started Commit, and so has to
+	// finish it
+	StartedCommit bool   `json:"started_commit"`
+	UploadID      string `json:"upload_id"`
+	// Uploaded is set for each part that's been put in Commit
+	Uploaded []bool `json:"uploaded"`
+	// Copied is the number of parts that have been appended to Path
+	Copied int `json:"copied"`
+}
+
+func (m *putFileManifest) numParts() int {
+	return len(m.Uploaded)
+}
+
+// partsDir returns the directory in PFS that the parts are put in. It's a
+// hidden directory next to the file, which is deleted once the parts are
+// appended to the file.
+func (m *putFileManifest) partsDir() string {
+	return path.Join(path.Dir(m.Path), fmt.Sprintf(".%s.%s.parts", path.Base(m.Path), m.UploadID))
+}
+
+// partPath returns the path in PFS that part 'i' is put at
+func (m *putFileManifest) partPath(i int) string {
+	return path.Join(m.partsDir(), fmt.Sprintf("%06d", i))
+}
+
+func (m *putFileManifest) matches(repoName, target, p string, size, partSize int64, overwrite bool) bool {
+	return m.Repo == repoName && m.Target == target && m.Path == p &&
+		m.Size == size && m.PartSize == partSize && m.Overwrite == overwrite
+}
+
+func readPutFileManifest(manifestPath string) (*putFileManifest, error) {
+	data, err := ioutil.ReadFile(manifestPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	m := &putFileManifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("error parsing put file manifest %s: %v", manifestPath, err)
+	}
+	return m, nil
+}
+
+func writePutFileManifest(manifestPath string, m *putFileManifest) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	// write and rename, so that a crash can't leave a partial manifest
+	tmp := manifestPath + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, manifestPath)
+}
+
+// PutFileParallel puts 'size' bytes read from 'r' (typically a large local
+// file) into a file. The data is split into parts of 'partSize' bytes, and up
+// to 'parallelism' parts are uploaded at once, each over its own PutFile
+// stream, which is much faster than a single stream over high-latency links.
+// Once every part is uploaded, the parts are appended to the file in order
+// (or replace it, if 'overwrite' is set).
+//
+// If 'commitID' is a branch whose head is finished, a commit is started on
+// it and finished once the file is complete, so that the file appears in a
+// single commit.
+//
+// If 'manifestPath' is set, the progress of the upload is recorded in a local
+// file at that path. If the upload fails, calling PutFileParallel again with
+// the same arguments resumes it, skipping the parts that were already
+// uploaded, as long as the commit is still open. The manifest is removed once
+// the upload completes.
+func (c APIClient) PutFileParallel(repoName string, commitID string, p string, r io.ReaderAt, size int64, partSize int64, parallelism int, overwrite bool, manifestPath string) error {
+	if partSize <= 0 {
+		return fmt.Errorf("part size must be positive")
+	}
+	if parallelism <= 0 {
+		parallelism = 1
+	}
+	p = path.Clean("/" + p)
+
+	var m *putFileManifest
+	if manifestPath != "" {
+		var err error
+		if m, err = readPutFileManifest(manifestPath); err != nil {
+			return err
+		}
+		if m != nil && m.matches(repoName, commitID, p, size, partSize, overwrite) {
+			commitInfo, err := c.InspectCommit(repoName, m.Commit)
+			if err != nil || commitInfo.Finished != nil {
+				// the upload can't be resumed, so start over
+				m = nil
+			}
+		} else {
+			m = nil
+		}
+	}
+	if m == nil {
+		commit, started, err := c.openCommitForPut(repoName, commitID)
+		if err != nil {
+			return err
+		}
+		numParts := int((size + partSize - 1) / partSize)
+		if numParts == 0 {
+			numParts = 1 // an empty file is still put
+		}
+		m = &putFileManifest{
+			Repo:          repoName,
+			Target:        commitID,
+			Commit:        commit,
+			Path:          p,
+			Size:          size,
+			PartSize:      partSize,
+			Overwrite:     overwrite,
+			StartedCommit: started,
+			UploadID:      uuid.NewWithoutDashes(),
+			Uploaded:      make([]bool, numParts),
+		}
+	}
+	var manifestMu sync.Mutex
+	saveManifest := func() error {
+		if manifestPath == "" {
+			return nil
+		}
+		manifestMu.Lock()
+		defer manifestMu.Unlock()
+		return writePutFileManifest(manifestPath, m)
+	}
+	if err := saveManifest(); err != nil {
+		return err
+	}
+
+	// upload the parts that haven't been uploaded yet
+	var eg errgroup.Group
+	limiter := limit.New(parallelism)
+	for i := 0; i < m.numParts(); i++ {
+		if m.Uploaded[i] {
+			continue
+		}
+		i := i
+		limiter.Acquire()
+		eg.Go(func() error {
+			defer limiter.Release()
+			offset := int64(i) * partSize
+			length := partSize
+			if offset+length > size {
+				length = size - offset
+			}
+			if _, err := c.PutFileOverwrite(repoName, m.Commit, m.partPath(i), io.NewSectionReader(r, offset, length), 0); err != nil {
+				return fmt.Errorf("error putting part %d of %s: %v", i, p, err)
+			}
+			manifestMu.Lock()
+			m.Uploaded[i] = true
+			manifestMu.Unlock()
+			return saveManifest()
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+
+	// append the parts to the file, in order
+	if m.Copied == 0 && overwrite {
+		if err := c.DeleteFile(repoName, m.Commit, p); err != nil {
+			return err
+		}
+	}
+	for m.Copied < m.numParts() {
+		if err := c.CopyFile(repoName, m.Commit, m.partPath(m.Copied), repoName, m.Commit, p, false); err != nil {
+			return err
+		}
+		m.Copied++
+		if err := saveManifest(); err != nil {
+			return err
+		}
+	}
+	if err := c.DeleteFile(repoName, m.Commit, m.partsDir()); err != nil {
+		return err
+	}
+	if m.StartedCommit {
+		if err := c.FinishCommit(repoName, m.Commit); err != nil {
+			return err
+		}
+	}
+	if manifestPath != "" {
+		return os.Remove(manifestPath)
+	}
+	return nil
+}
+
+// openCommitForPut returns the ID of the open commit that 'commitID' refers
+// to. If it's a branch without an open head, a commit is started on it, and
+// the returned bool is set.
+func (c APIClient) openCommitForPut(repoName string, commitID string) (string, bool, error) {
+	if uuid.IsUUIDWithoutDashes(commitID) {
+		return commitID, false, nil
+	}
+	branchInfo, err := c.InspectBranch(repoName, commitID)
+	if err == nil && branchInfo.Head != nil {
+		commitInfo, err := c.InspectCommit(repoName, branchInfo.Head.ID)
+		if err != nil {
+			return "", false, err
+		}
+		if commitInfo.Finished == nil {
+			return commitInfo.Commit.ID, false, nil
+		}
+	}
+	commit, err := c.StartCommit(repoName, commitID)
+	if err != nil {
+		return "", false, err
+	}
+	return commit.ID, true, nil
+}
@@ -144,3 +144,53 @@ func (c APIClient) ExecuteInTransaction(f func(c *APIClient) error) (*transactio
 	}
 	return c.FinishTransaction(txn)
 }
+
+// ExecuteInMultiCommit starts a commit on each of 'branches' in a single
+// transaction, and calls 'f' with the new commits (in the same order as
+// 'branches'), so that it can write to them. If 'f' returns a nil error, all
+// of the commits are finished in a single transaction, so that downstream
+// branches see the writes to every repo at once, as a single commit. If 'f'
+// returns a non-nil error, the commits are deleted, and the error is returned
+// (along with the error from deleting them, if that fails too).
+//
+// ExecuteInMultiCommit runs entirely on the client side: it makes three
+// separate requests to pachd, so if the client dies, or the final transaction
+// fails, before the commits are finished or deleted, they're left open, and
+// the caller must finish or delete them.
+func (c APIClient) ExecuteInMultiCommit(branches []*pfs.Branch, f func(commits []*pfs.Commit) error) error {
+	commits := make([]*pfs.Commit, len(branches))
+	if _, err := c.ExecuteInTransaction(func(txnClient *APIClient) error {
+		for i, branch := range branches {
+			commit, err := txnClient.StartCommit(branch.Repo.Name, branch.Name)
+			if err != nil {
+				return err
+			}
+			commits[i] = commit
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if err := f(commits); err != nil {
+		if _, deleteErr := c.ExecuteInTransaction(func(txnClient *APIClient) error {
+			for _, commit := range commits {
+				if err := txnClient.DeleteCommit(commit.Repo.Name, commit.ID); err != nil {
+					return err
+				}
+			}
+			return nil
+		}); deleteErr != nil {
+			return fmt.Errorf("%v (and could not delete the commits, which are still open: %v)", err, deleteErr)
+		}
+		return err
+	}
+	_, err := c.ExecuteInTransaction(func(txnClient *APIClient) error {
+		for _, commit := range commits {
+			if err := txnClient.FinishCommit(commit.Repo.Name, commit.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return err
+}
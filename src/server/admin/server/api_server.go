@@ -219,10 +219,12 @@ func (a *apiServer) Extract(request *admin.ExtractRequest, extractServer admin.A
 					DataTotal:     ji.DataTotal,
 					DataFailed:    ji.DataFailed,
 					DataRecovered: ji.DataRecovered,
+					FailureCounts: ji.FailureCounts,
 					Stats:         ji.Stats,
 					StatsCommit:   ji.StatsCommit,
 					State:         ji.State,
 					Reason:        ji.Reason,
+					FailureType:   ji.FailureType,
 					Started:       ji.Started,
 					Finished:      ji.Finished,
 				}}})
@@ -691,12 +691,24 @@ This resets the cluster to its initial state.`,
 	}
 	subcommands = append(subcommands, cmdutil.CreateAlias(flushDocs, "flush"))
 
+	waitDocs := &cobra.Command{
+		Short: "Wait for a Pachyderm resource to finish.",
+		Long:  "Wait for a Pachyderm resource to finish.",
+	}
+	subcommands = append(subcommands, cmdutil.CreateAlias(waitDocs, "wait"))
+
 	subscribeDocs := &cobra.Command{
 		Short: "Wait for notifications of changes to a Pachyderm resource.",
 		Long:  "Wait for notifications of changes to a Pachyderm resource.",
 	}
 	subcommands = append(subcommands, cmdutil.CreateAlias(subscribeDocs, "subscribe"))
 
+	expireDocs := &cobra.Command{
+		Short: "Delete the expired instances of a Pachyderm resource.",
+		Long:  "Delete the expired instances of a Pachyderm resource.",
+	}
+	subcommands = append(subcommands, cmdutil.CreateAlias(expireDocs, "expire"))
+
 	putDocs := &cobra.Command{
 		Short: "Insert data into Pachyderm.",
 		Long:  "Insert data into Pachyderm.",
@@ -727,12 +739,24 @@ This resets the cluster to its initial state.`,
 	}
 	subcommands = append(subcommands, cmdutil.CreateAlias(diffDocs, "diff"))
 
+	searchDocs := &cobra.Command{
+		Short: "Find Pachyderm resources by their indexed attributes.",
+		Long:  "Find Pachyderm resources by their indexed attributes.",
+	}
+	subcommands = append(subcommands, cmdutil.CreateAlias(searchDocs, "search"))
+
 	stopDocs := &cobra.Command{
 		Short: "Cancel an ongoing task.",
 		Long:  "Cancel an ongoing task.",
 	}
 	subcommands = append(subcommands, cmdutil.CreateAlias(stopDocs, "stop"))
 
+	pauseDocs := &cobra.Command{
+		Short: "Pause an ongoing task, so that it can be resumed later.",
+		Long:  "Pause an ongoing task, so that it can be resumed later.",
+	}
+	subcommands = append(subcommands, cmdutil.CreateAlias(pauseDocs, "pause"))
+
 	restartDocs := &cobra.Command{
 		Short: "Cancel and restart an ongoing task.",
 		Long:  "Cancel and restart an ongoing task.",
@@ -808,18 +832,23 @@ func applyRootUsageFunc(rootCmd *cobra.Command) {
 			"delete",
 			"diff",
 			"edit",
+			"expire",
 			"finish",
 			"flush",
 			"get",
 			"glob",
 			"inspect",
 			"list",
+			"pause",
 			"put",
 			"restart",
+			"resume",
+			"search",
 			"start",
 			"stop",
 			"subscribe",
-			"update":
+			"update",
+			"wait":
 			actions = append(actions, subcmd)
 		case
 			"deploy",
@@ -10,6 +10,7 @@ import (
 	"runtime/debug"
 	"runtime/pprof"
 	"strconv"
+	"time"
 
 	etcd "github.com/coreos/etcd/clientv3"
 	units "github.com/docker/go-units"
@@ -124,6 +125,11 @@ func doSidecarMode(config interface{}) (retErr error) {
 		log.Errorf("Unrecognized log level %s, falling back to default of \"info\"", env.LogLevel)
 		log.SetLevel(log.InfoLevel)
 	}
+	if threshold, err := time.ParseDuration(env.EtcdSlowOperationThreshold); err != nil {
+		log.Errorf("invalid etcd slow operation threshold %q, falling back to default of %v: %v", env.EtcdSlowOperationThreshold, col.DefaultSlowOperationThreshold, err)
+	} else {
+		col.SetSlowOperationThreshold(threshold)
+	}
 	if env.EtcdPrefix == "" {
 		env.EtcdPrefix = col.DefaultPrefix
 	}
@@ -303,6 +309,11 @@ func doFullMode(config interface{}) (retErr error) {
 		log.Errorf("Unrecognized log level %s, falling back to default of \"info\"", env.LogLevel)
 		log.SetLevel(log.InfoLevel)
 	}
+	if threshold, err := time.ParseDuration(env.EtcdSlowOperationThreshold); err != nil {
+		log.Errorf("invalid etcd slow operation threshold %q, falling back to default of %v: %v", env.EtcdSlowOperationThreshold, col.DefaultSlowOperationThreshold, err)
+	} else {
+		col.SetSlowOperationThreshold(threshold)
+	}
 	if env.EtcdPrefix == "" {
 		env.EtcdPrefix = col.DefaultPrefix
 	}
@@ -376,7 +387,29 @@ func doFullMode(config interface{}) (retErr error) {
 		return fmt.Errorf("RunGitHookServer: %v", err)
 	})
 	eg.Go(func() error {
-		server, err := s3.Server(env.S3GatewayPort, env.Port)
+		limits := s3.Limits{
+			HTTP2:                env.S3HTTP2,
+			MaxRequests:          env.S3MaxRequests,
+			MaxClientRequests:    env.S3MaxClientRequests,
+			MaxConnectionStreams: env.S3MaxStreams,
+			BucketLimits:         env.S3BucketLimits,
+		}
+		if env.S3IdleTimeout != "" {
+			idleTimeout, err := time.ParseDuration(env.S3IdleTimeout)
+			if err != nil {
+				return fmt.Errorf("invalid s3gateway idle timeout %q: %v", env.S3IdleTimeout, err)
+			}
+			limits.IdleTimeout = idleTimeout
+		}
+		var metadataCacheTTL time.Duration
+		if env.S3MetadataCacheTTL != "" {
+			ttl, err := time.ParseDuration(env.S3MetadataCacheTTL)
+			if err != nil {
+				return fmt.Errorf("invalid s3gateway metadata cache TTL %q: %v", env.S3MetadataCacheTTL, err)
+			}
+			metadataCacheTTL = ttl
+		}
+		server, err := s3.Server(env.S3GatewayPort, env.Port, env.S3GatewayBucketPolicy, env.PFSChecksums, env.S3ReadAfterWrite, env.S3AuditSink, metadataCacheTTL, limits)
 		if err != nil {
 			return fmt.Errorf("s3gateway server: %v", err)
 		}
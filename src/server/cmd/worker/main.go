@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"io"
 	"net/http"
@@ -21,17 +22,41 @@ import (
 	"github.com/pachyderm/pachyderm/src/client/version/versionpb"
 	debugserver "github.com/pachyderm/pachyderm/src/server/debug/server"
 	"github.com/pachyderm/pachyderm/src/server/pkg/cmdutil"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
 	logutil "github.com/pachyderm/pachyderm/src/server/pkg/log"
 	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
 	"github.com/pachyderm/pachyderm/src/server/pkg/serviceenv"
 	"github.com/pachyderm/pachyderm/src/server/worker"
+	"github.com/pachyderm/pachyderm/src/server/worker/logs"
 
 	log "github.com/sirupsen/logrus"
 )
 
+var mode string
+var userCodeUser string
+
+func init() {
+	flag.StringVar(&mode, "mode", "full", "The worker currently supports two modes: full and user-code. The former is the worker itself. The latter runs in the user code container of pipelines with separate_container set, and runs the user code for the worker.")
+	flag.StringVar(&userCodeUser, "user", "", "In user-code mode, the user (in the form of a Dockerfile USER argument) to run the user code as. By default, the user code runs as the container's user.")
+	flag.Parse()
+}
+
 func main() {
 	log.SetFormatter(logutil.FormatterFunc(logutil.Pretty))
 
+	switch mode {
+	case "full":
+	case "user-code":
+		// The user's image is left alone (e.g. no certs are copied into it), as
+		// only the user code runs in it
+		if err := worker.ServeUserCode(client.PPSWorkerExecSocket, userCodeUser); err != nil {
+			log.Fatalf("error serving user code: %v", err)
+		}
+		return
+	default:
+		log.Fatalf("unrecognized mode: %s", mode)
+	}
+
 	// Copy the contents of /pach-bin/certs into /etc/ssl/certs. Don't return an
 	// error (which would cause 'Walk()' to exit early) but do record if any certs
 	// are known to be missing so we can inform the user
@@ -129,6 +154,11 @@ func getPipelineInfo(pachClient *client.APIClient, env *serviceenv.ServiceEnv) (
 func do(config interface{}) error {
 	tracing.InstallJaegerTracerFromEnv() // must run before InitWithKube
 	env := serviceenv.InitServiceEnv(serviceenv.NewConfiguration(config))
+	if threshold, err := time.ParseDuration(env.EtcdSlowOperationThreshold); err != nil {
+		log.Errorf("invalid etcd slow operation threshold %q, falling back to default of %v: %v", env.EtcdSlowOperationThreshold, col.DefaultSlowOperationThreshold, err)
+	} else {
+		col.SetSlowOperationThreshold(threshold)
+	}
 	// Expose PProf service
 	go func() {
 		log.Println(http.ListenAndServe(fmt.Sprintf(":%d", env.PProfPort), nil))
@@ -145,7 +175,11 @@ func do(config interface{}) error {
 
 	// Construct worker API server.
 	workerRcName := ppsutil.PipelineRcName(pipelineInfo.Pipeline.Name, pipelineInfo.Version)
-	apiServer, err := worker.NewAPIServer(pachClient, env.GetEtcdClient(), env.PPSEtcdPrefix, pipelineInfo, env.PodName, env.Namespace, env.StorageRoot)
+	logSink, err := logs.NewSink(env.LogSink)
+	if err != nil {
+		return fmt.Errorf("error creating log sink: %v", err)
+	}
+	apiServer, err := worker.NewAPIServer(pachClient, env.GetEtcdClient(), env.PPSEtcdPrefix, pipelineInfo, env.PodName, env.Namespace, env.StorageRoot, logSink)
 	if err != nil {
 		return err
 	}
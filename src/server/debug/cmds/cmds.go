@@ -18,18 +18,29 @@ import (
 func Cmds() []*cobra.Command {
 	var commands []*cobra.Command
 
+	var archive bool
 	dump := &cobra.Command{
 		Short: "Return a dump of running goroutines.",
-		Long:  "Return a dump of running goroutines.",
+		Long:  "Return a dump of running goroutines. With --archive, the dump is a gzipped tar archive that also includes each worker's driver state (its claimed chunks, current datum, scratch disk usage and recent logs).",
+		Example: `
+# Dump the goroutines of pachd and its workers
+$ {{alias}}
+
+# Save a dump of pachd and its workers' driver state, for support
+$ {{alias}} --archive > dump.tar.gz`,
 		Run: cmdutil.RunFixedArgs(0, func(args []string) error {
 			client, err := client.NewOnUserMachine("debug-dump")
 			if err != nil {
 				return err
 			}
 			defer client.Close()
+			if archive {
+				return client.DumpArchive(os.Stdout)
+			}
 			return client.Dump(os.Stdout)
 		}),
 	}
+	dump.Flags().BoolVar(&archive, "archive", false, "Write a gzipped tar archive including each worker's driver state, rather than plain text.")
 	commands = append(commands, cmdutil.CreateAlias(dump, "debug dump"))
 
 	var duration time.Duration
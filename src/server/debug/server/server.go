@@ -1,13 +1,19 @@
 package server
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"runtime/pprof"
+	"strings"
 	"time"
 
 	etcd "github.com/coreos/etcd/clientv3"
+	"github.com/gogo/protobuf/jsonpb"
 	"github.com/gogo/protobuf/types"
 	"github.com/pachyderm/pachyderm/src/client/debug"
 	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
@@ -36,6 +42,9 @@ type debugServer struct {
 }
 
 func (s *debugServer) Dump(request *debug.DumpRequest, server debug.Debug_DumpServer) error {
+	if request.Archive {
+		return s.dumpArchive(server)
+	}
 	profile := pprof.Lookup("goroutine")
 	if profile == nil {
 		return fmt.Errorf("unable to find goroutine profile")
@@ -74,6 +83,87 @@ func (s *debugServer) Dump(request *debug.DumpRequest, server debug.Debug_DumpSe
 	return nil
 }
 
+// dumpArchive writes a gzipped tar archive to 'server', with pachd's
+// goroutines, and the driver state, goroutines and recent logs of each
+// worker under workers/<worker>/
+func (s *debugServer) dumpArchive(server debug.Debug_DumpServer) (retErr error) {
+	gw := gzip.NewWriter(grpcutil.NewStreamingBytesWriter(server))
+	defer func() {
+		if err := gw.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+	tw := tar.NewWriter(gw)
+	defer func() {
+		if err := tw.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+	name := s.name
+	if name == "" {
+		name = "pachd"
+	}
+	profile := pprof.Lookup("goroutine")
+	if profile == nil {
+		return fmt.Errorf("unable to find goroutine profile")
+	}
+	var goroutines bytes.Buffer
+	if err := profile.WriteTo(&goroutines, 2); err != nil {
+		return err
+	}
+	if err := writeArchiveFile(tw, path.Join(name, "goroutines.txt"), goroutines.Bytes()); err != nil {
+		return err
+	}
+	cs, err := worker.Clients(server.Context(), "", s.etcdClient, s.etcdPrefix, s.workerGrpcPort)
+	if err != nil {
+		return err
+	}
+	marshaler := &jsonpb.Marshaler{Indent: "  "}
+	for i, c := range cs {
+		state, err := c.DriverState(server.Context(), &types.Empty{})
+		if err != nil {
+			// An unreachable worker shouldn't keep the others from being
+			// dumped
+			dir := path.Join("workers", fmt.Sprintf("unreachable-%d", i))
+			if err := writeArchiveFile(tw, path.Join(dir, "error.txt"), []byte(err.Error()+"\n")); err != nil {
+				return err
+			}
+			continue
+		}
+		dir := path.Join("workers", state.Status.WorkerID)
+		logTail := strings.Join(state.LogTail, "\n") + "\n"
+		if err := writeArchiveFile(tw, path.Join(dir, "logs.txt"), []byte(logTail)); err != nil {
+			return err
+		}
+		if err := writeArchiveFile(tw, path.Join(dir, "goroutines.txt"), state.Goroutines); err != nil {
+			return err
+		}
+		// The logs and goroutines are in their own files
+		state.LogTail, state.Goroutines = nil, nil
+		stateJSON, err := marshaler.MarshalToString(state)
+		if err != nil {
+			return err
+		}
+		if err := writeArchiveFile(tw, path.Join(dir, "state.json"), []byte(stateJSON+"\n")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeArchiveFile(tw *tar.Writer, name string, content []byte) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(content)),
+		ModTime: time.Now(),
+	}); err != nil {
+		return err
+	}
+	_, err := tw.Write(content)
+	return err
+}
+
 func (s *debugServer) Profile(request *debug.ProfileRequest, server debug.Debug_ProfileServer) error {
 	w := grpcutil.NewStreamingBytesWriter(server)
 	if request.Profile == "cpu" {
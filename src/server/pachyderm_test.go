@@ -1875,6 +1875,301 @@ func TestFlushCommit(t *testing.T) {
 	}
 }
 
+func TestWaitJob(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	c := getPachClient(t)
+	require.NoError(t, c.DeleteAll())
+	dataRepo := tu.UniqueString("TestWaitJob_data")
+	require.NoError(t, c.CreateRepo(dataRepo))
+
+	// Create a three-stage pipeline, the last of which fails
+	pipelineA := tu.UniqueString("TestWaitJob_A")
+	require.NoError(t, c.CreatePipeline(
+		pipelineA,
+		"",
+		[]string{"cp", path.Join("/pfs", dataRepo, "file"), "/pfs/out/file"},
+		nil,
+		&pps.ParallelismSpec{Constant: 1},
+		client.NewPFSInput(dataRepo, "/*"),
+		"",
+		false,
+	))
+	pipelineB := tu.UniqueString("TestWaitJob_B")
+	require.NoError(t, c.CreatePipeline(
+		pipelineB,
+		"",
+		[]string{"cp", path.Join("/pfs", pipelineA, "file"), "/pfs/out/file"},
+		nil,
+		&pps.ParallelismSpec{Constant: 1},
+		client.NewPFSInput(pipelineA, "/*"),
+		"",
+		false,
+	))
+	pipelineC := tu.UniqueString("TestWaitJob_C")
+	require.NoError(t, c.CreatePipeline(
+		pipelineC,
+		"",
+		[]string{"false"},
+		nil,
+		&pps.ParallelismSpec{Constant: 1},
+		client.NewPFSInput(pipelineB, "/*"),
+		"",
+		false,
+	))
+
+	commit, err := c.StartCommit(dataRepo, "master")
+	require.NoError(t, err)
+	_, err = c.PutFile(dataRepo, commit.ID, "file", strings.NewReader("foo\n"))
+	require.NoError(t, err)
+	require.NoError(t, c.FinishCommit(dataRepo, commit.ID))
+
+	// An input commit isn't the output of a job
+	require.YesError(t, c.WaitCommit(dataRepo, commit.ID, false, func(*pps.JobInfo) error { return nil }))
+
+	// Wait for all of the jobs that process the commit, which finish once each
+	finalStates := make(map[string]pps.JobState)
+	var jobA *pps.Job
+	require.NoError(t, c.WaitCommit(dataRepo, commit.ID, true, func(jobInfo *pps.JobInfo) error {
+		_, ok := finalStates[jobInfo.Pipeline.Name]
+		require.False(t, ok, "job %s was sent after it finished", jobInfo.Job.ID)
+		if ppsutil.IsTerminal(jobInfo.State) {
+			finalStates[jobInfo.Pipeline.Name] = jobInfo.State
+		}
+		if jobInfo.Pipeline.Name == pipelineA {
+			jobA = jobInfo.Job
+		}
+		return nil
+	}))
+	require.Equal(t, map[string]pps.JobState{
+		pipelineA: pps.JobState_JOB_SUCCESS,
+		pipelineB: pps.JobState_JOB_SUCCESS,
+		pipelineC: pps.JobState_JOB_FAILURE,
+	}, finalStates)
+
+	// Waiting for a finished job returns its final state right away
+	var jobInfos []*pps.JobInfo
+	require.NoError(t, c.WaitJob(jobA.ID, false, func(jobInfo *pps.JobInfo) error {
+		jobInfos = append(jobInfos, jobInfo)
+		return nil
+	}))
+	require.Equal(t, 1, len(jobInfos))
+	require.Equal(t, pps.JobState_JOB_SUCCESS, jobInfos[0].State)
+
+	// With downstream set, the jobs of B and C are included
+	jobInfos = nil
+	require.NoError(t, c.WaitJob(jobA.ID, true, func(jobInfo *pps.JobInfo) error {
+		jobInfos = append(jobInfos, jobInfo)
+		return nil
+	}))
+	require.Equal(t, 3, len(jobInfos))
+}
+
+func TestFlushJobProgress(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	c := getPachClient(t)
+	require.NoError(t, c.DeleteAll())
+	dataRepo := tu.UniqueString("TestFlushJobProgress_data")
+	require.NoError(t, c.CreateRepo(dataRepo))
+
+	pipelineA := tu.UniqueString("TestFlushJobProgress_A")
+	require.NoError(t, c.CreatePipeline(
+		pipelineA,
+		"",
+		[]string{"bash"},
+		[]string{
+			"sleep 1",
+			fmt.Sprintf("cp /pfs/%s/* /pfs/out/", dataRepo),
+		},
+		&pps.ParallelismSpec{Constant: 1},
+		client.NewPFSInput(dataRepo, "/*"),
+		"",
+		false,
+	))
+	pipelineB := tu.UniqueString("TestFlushJobProgress_B")
+	require.NoError(t, c.CreatePipeline(
+		pipelineB,
+		"",
+		[]string{"bash"},
+		[]string{fmt.Sprintf("cp /pfs/%s/* /pfs/out/", pipelineA)},
+		&pps.ParallelismSpec{Constant: 1},
+		client.NewPFSInput(pipelineA, "/*"),
+		"",
+		false,
+	))
+
+	numFiles := 5
+	commit, err := c.StartCommit(dataRepo, "master")
+	require.NoError(t, err)
+	for i := 0; i < numFiles; i++ {
+		_, err = c.PutFile(dataRepo, commit.ID, fmt.Sprintf("file-%d", i), strings.NewReader("foo\n"))
+		require.NoError(t, err)
+	}
+	require.NoError(t, c.FinishCommit(dataRepo, commit.ID))
+
+	// Each job's progress only goes forward, and its last JobInfo is the one
+	// that it finished with
+	last := make(map[string]*pps.JobInfo)
+	updates := 0
+	require.NoError(t, c.FlushJobProgress([]*pfs.Commit{commit}, nil, func(jobInfo *pps.JobInfo) error {
+		if prev, ok := last[jobInfo.Pipeline.Name]; ok {
+			require.False(t, ppsutil.IsTerminal(prev.State), "job %s was sent after it finished", jobInfo.Job.ID)
+			require.True(t, jobInfo.DataProcessed+jobInfo.DataSkipped >= prev.DataProcessed+prev.DataSkipped)
+		}
+		last[jobInfo.Pipeline.Name] = jobInfo
+		updates++
+		return nil
+	}))
+	require.Equal(t, 2, len(last))
+	for _, pipeline := range []string{pipelineA, pipelineB} {
+		require.Equal(t, pps.JobState_JOB_SUCCESS, last[pipeline].State)
+		require.Equal(t, int64(numFiles), last[pipeline].DataTotal)
+		require.Equal(t, int64(numFiles), last[pipeline].DataProcessed)
+	}
+	require.True(t, updates > 2)
+
+	// Only the jobs leading to the given pipelines are watched
+	last = make(map[string]*pps.JobInfo)
+	require.NoError(t, c.FlushJobProgress([]*pfs.Commit{commit}, []string{pipelineA}, func(jobInfo *pps.JobInfo) error {
+		last[jobInfo.Pipeline.Name] = jobInfo
+		return nil
+	}))
+	require.Equal(t, 1, len(last))
+	require.Equal(t, pps.JobState_JOB_SUCCESS, last[pipelineA].State)
+}
+
+func TestPauseJob(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	c := getPachClient(t)
+	require.NoError(t, c.DeleteAll())
+	dataRepo := tu.UniqueString("TestPauseJob_data")
+	require.NoError(t, c.CreateRepo(dataRepo))
+	numFiles := 20
+	commit, err := c.StartCommit(dataRepo, "master")
+	require.NoError(t, err)
+	for i := 0; i < numFiles; i++ {
+		_, err = c.PutFile(dataRepo, commit.ID, fmt.Sprintf("file-%d", i), strings.NewReader("foo\n"))
+		require.NoError(t, err)
+	}
+	require.NoError(t, c.FinishCommit(dataRepo, commit.ID))
+
+	pipeline := tu.UniqueString("TestPauseJob")
+	require.NoError(t, c.CreatePipeline(
+		pipeline,
+		"",
+		[]string{"bash"},
+		[]string{
+			"sleep 1",
+			fmt.Sprintf("cp /pfs/%s/* /pfs/out/", dataRepo),
+		},
+		&pps.ParallelismSpec{Constant: 1},
+		client.NewPFSInput(dataRepo, "/*"),
+		"",
+		false,
+	))
+
+	var jobID string
+	require.NoError(t, backoff.Retry(func() error {
+		jobInfos, err := c.ListJob(pipeline, nil, nil, -1, true)
+		require.NoError(t, err)
+		if len(jobInfos) != 1 || jobInfos[0].State != pps.JobState_JOB_RUNNING {
+			return fmt.Errorf("expected a running job")
+		}
+		jobID = jobInfos[0].Job.ID
+		return nil
+	}, backoff.NewTestingBackOff()))
+	require.NoError(t, c.PauseJob(jobID))
+	jobInfo, err := c.InspectJob(jobID, false)
+	require.NoError(t, err)
+	require.True(t, jobInfo.Paused)
+
+	// The job doesn't finish while it's paused
+	time.Sleep(time.Duration(numFiles) * time.Second)
+	jobInfo, err = c.InspectJob(jobID, false)
+	require.NoError(t, err)
+	require.False(t, ppsutil.IsTerminal(jobInfo.State))
+
+	require.NoError(t, c.ResumeJob(jobID))
+	jobInfo, err = c.InspectJob(jobID, true)
+	require.NoError(t, err)
+	require.Equal(t, pps.JobState_JOB_SUCCESS, jobInfo.State)
+	require.False(t, jobInfo.Paused)
+	require.Equal(t, int64(numFiles), jobInfo.DataProcessed+jobInfo.DataSkipped)
+	fileInfos, err := c.ListFile(pipeline, jobInfo.OutputCommit.ID, "")
+	require.NoError(t, err)
+	require.Equal(t, numFiles, len(fileInfos))
+
+	// A finished job can't be paused
+	require.YesError(t, c.PauseJob(jobID))
+}
+
+func TestJobAttestation(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	c := getPachClient(t)
+	require.NoError(t, c.DeleteAll())
+	dataRepo := tu.UniqueString("TestJobAttestation_data")
+	require.NoError(t, c.CreateRepo(dataRepo))
+	commit, err := c.StartCommit(dataRepo, "master")
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		_, err = c.PutFile(dataRepo, commit.ID, fmt.Sprintf("file-%d", i), strings.NewReader("foo\n"))
+		require.NoError(t, err)
+	}
+	require.NoError(t, c.FinishCommit(dataRepo, commit.ID))
+
+	pipeline := tu.UniqueString("TestJobAttestation")
+	_, err = c.PpsAPIClient.CreatePipeline(context.Background(),
+		&pps.CreatePipelineRequest{
+			Pipeline: client.NewPipeline(pipeline),
+			Transform: &pps.Transform{
+				Cmd: []string{"bash"},
+				Stdin: []string{
+					fmt.Sprintf("cp /pfs/%s/* /pfs/out/", dataRepo),
+				},
+				Env: map[string]string{"MODE": "fast", "TOKEN": "secret"},
+			},
+			Input:           client.NewPFSInput(dataRepo, "/*"),
+			EnableStats:     true,
+			AttestationSpec: &pps.AttestationSpec{Env: []string{"MODE"}},
+		})
+	require.NoError(t, err)
+
+	commitInfos, err := c.FlushCommitAll([]*pfs.Commit{commit}, nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(commitInfos))
+	jobInfos, err := c.ListJob(pipeline, nil, nil, -1, true)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(jobInfos))
+	jobInfo, err := c.InspectJob(jobInfos[0].Job.ID, true)
+	require.NoError(t, err)
+	require.Equal(t, pps.JobState_JOB_SUCCESS, jobInfo.State)
+
+	attestationInfo, err := c.InspectJobAttestation(jobInfo.Job.ID)
+	require.NoError(t, err)
+	attestation := attestationInfo.Attestation
+	require.Equal(t, jobInfo.Job.ID, attestation.Job.ID)
+	require.Equal(t, commit.ID, attestation.Inputs[0].ID)
+	require.Equal(t, jobInfo.OutputCommit.ID, attestation.OutputCommit.ID)
+	require.Equal(t, map[string]string{"MODE": "fast"}, attestation.Env)
+	require.NotEqual(t, "", attestation.ImageDigest)
+
+	// The attestation files aren't listed as datums
+	resp, err := c.ListDatum(jobInfo.Job.ID, 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, 5, len(resp.DatumInfos))
+}
+
 func TestFlushCommitFailures(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping integration tests in short mode")
@@ -7925,6 +8220,52 @@ func TestPipelineWithDatumTimeout(t *testing.T) {
 	require.Equal(t, timeout, seconds)
 }
 
+func TestPipelinePreviousOutput(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	c := getPachClient(t)
+	require.NoError(t, c.DeleteAll())
+
+	dataRepo := tu.UniqueString("TestPipelinePreviousOutput_data")
+	require.NoError(t, c.CreateRepo(dataRepo))
+
+	pipeline := tu.UniqueString("pipeline")
+	_, err := c.PpsAPIClient.CreatePipeline(
+		context.Background(),
+		&pps.CreatePipelineRequest{
+			Pipeline: client.NewPipeline(pipeline),
+			Transform: &pps.Transform{
+				Cmd: []string{"bash"},
+				Stdin: []string{
+					"count=$(cat /pfs/prev/count 2>/dev/null || echo 0)",
+					"echo $((count + 1)) >/pfs/out/count",
+				},
+			},
+			Input:          client.NewPFSInput(dataRepo, "/"),
+			PreviousOutput: true,
+		},
+	)
+	require.NoError(t, err)
+
+	for i := 1; i <= 3; i++ {
+		commit, err := c.StartCommit(dataRepo, "master")
+		require.NoError(t, err)
+		_, err = c.PutFile(dataRepo, commit.ID, fmt.Sprintf("file%d", i), strings.NewReader("foo"))
+		require.NoError(t, err)
+		require.NoError(t, c.FinishCommit(dataRepo, commit.ID))
+		commitIter, err := c.FlushCommit([]*pfs.Commit{commit}, []*pfs.Repo{client.NewRepo(pipeline)})
+		require.NoError(t, err)
+		commitInfos := collectCommitInfos(t, commitIter)
+		require.Equal(t, 1, len(commitInfos))
+
+		var buf bytes.Buffer
+		require.NoError(t, c.GetFile(pipeline, commitInfos[0].Commit.ID, "count", 0, 0, &buf))
+		require.Equal(t, fmt.Sprintf("%d\n", i), buf.String())
+	}
+}
+
 func TestPipelineWithDatumTimeoutControl(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping integration tests in short mode")
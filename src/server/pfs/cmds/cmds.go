@@ -2,6 +2,7 @@ package cmds
 
 import (
 	"bufio"
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"io"
@@ -13,22 +14,27 @@ import (
 	"path/filepath"
 	"strings"
 	gosync "sync"
+	"time"
 
 	"github.com/pachyderm/pachyderm/src/server/pkg/ppsconsts"
 
 	"golang.org/x/sync/errgroup"
 
+	"github.com/docker/go-units"
 	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/types"
 	"github.com/pachyderm/pachyderm/src/client"
 	"github.com/pachyderm/pachyderm/src/client/limit"
 	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
 	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
 	"github.com/pachyderm/pachyderm/src/server/pfs/pretty"
+	"github.com/pachyderm/pachyderm/src/server/pkg/backoff"
 	"github.com/pachyderm/pachyderm/src/server/pkg/cmdutil"
 	"github.com/pachyderm/pachyderm/src/server/pkg/errutil"
 	"github.com/pachyderm/pachyderm/src/server/pkg/pager"
 	"github.com/pachyderm/pachyderm/src/server/pkg/sync"
 	"github.com/pachyderm/pachyderm/src/server/pkg/tabwriter"
+	ppspretty "github.com/pachyderm/pachyderm/src/server/pps/pretty"
 	txncmds "github.com/pachyderm/pachyderm/src/server/transaction/cmds"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -67,6 +73,9 @@ or type (e.g. csv, binary, images, etc).`,
 	commands = append(commands, cmdutil.CreateDocsAlias(repoDocs, "repo", " repo$"))
 
 	var description string
+	var indexExtractors []string
+	var storageClass string
+	var commitTTL string
 	createRepo := &cobra.Command{
 		Use:   "{{alias}} <repo>",
 		Short: "Create a new repo.",
@@ -77,13 +86,24 @@ or type (e.g. csv, binary, images, etc).`,
 				return err
 			}
 			defer c.Close()
+			storagePolicy, err := parseStoragePolicy(storageClass)
+			if err != nil {
+				return err
+			}
+			ttl, err := parseCommitTTL(commitTTL)
+			if err != nil {
+				return err
+			}
 
 			err = txncmds.WithActiveTransaction(c, func(c *client.APIClient) error {
 				_, err = c.PfsAPIClient.CreateRepo(
 					c.Ctx(),
 					&pfsclient.CreateRepoRequest{
-						Repo:        client.NewRepo(args[0]),
-						Description: description,
+						Repo:            client.NewRepo(args[0]),
+						Description:     description,
+						IndexExtractors: indexExtractors,
+						StoragePolicy:   storagePolicy,
+						CommitTTL:       ttl,
 					},
 				)
 				return err
@@ -92,6 +112,9 @@ or type (e.g. csv, binary, images, etc).`,
 		}),
 	}
 	createRepo.Flags().StringVarP(&description, "description", "d", "", "A description of the repo.")
+	createRepo.Flags().StringSliceVar(&indexExtractors, "index-extractors", nil, "The index extractors (e.g. csv, image) that index the files in the repo's commits, so they can be found with 'search file'.")
+	createRepo.Flags().StringVar(&storageClass, "storage-class", "", "The storage class (standard, infrequent-access or archive) of the blocks that hold the content of the repo's files, if object storage supports storage classes.")
+	createRepo.Flags().StringVar(&commitTTL, "commit-ttl", "", "How long (e.g. 72h) the repo's commits are kept once they're finished, after which they're deleted (unless they're the head of a branch or have live downstream commits).")
 	commands = append(commands, cmdutil.CreateAlias(createRepo, "create repo"))
 
 	updateRepo := &cobra.Command{
@@ -104,14 +127,25 @@ or type (e.g. csv, binary, images, etc).`,
 				return err
 			}
 			defer c.Close()
+			storagePolicy, err := parseStoragePolicy(storageClass)
+			if err != nil {
+				return err
+			}
+			ttl, err := parseCommitTTL(commitTTL)
+			if err != nil {
+				return err
+			}
 
 			err = txncmds.WithActiveTransaction(c, func(c *client.APIClient) error {
 				_, err = c.PfsAPIClient.CreateRepo(
 					c.Ctx(),
 					&pfsclient.CreateRepoRequest{
-						Repo:        client.NewRepo(args[0]),
-						Description: description,
-						Update:      true,
+						Repo:            client.NewRepo(args[0]),
+						Description:     description,
+						IndexExtractors: indexExtractors,
+						StoragePolicy:   storagePolicy,
+						CommitTTL:       ttl,
+						Update:          true,
 					},
 				)
 				return err
@@ -120,6 +154,9 @@ or type (e.g. csv, binary, images, etc).`,
 		}),
 	}
 	updateRepo.Flags().StringVarP(&description, "description", "d", "", "A description of the repo.")
+	updateRepo.Flags().StringSliceVar(&indexExtractors, "index-extractors", nil, "The index extractors that index the files in the repo's commits. If unset, the repo keeps its index extractors, and 'none' removes them.")
+	updateRepo.Flags().StringVar(&storageClass, "storage-class", "", "The storage class (standard, infrequent-access or archive) of the blocks written by later commits. If unset, the repo keeps its storage class.")
+	updateRepo.Flags().StringVar(&commitTTL, "commit-ttl", "", "How long the repo's commits are kept once they're finished. If unset, the repo keeps its commit TTL, and 0 removes it.")
 	commands = append(commands, cmdutil.CreateAlias(updateRepo, "update repo"))
 
 	inspectRepo := &cobra.Command{
@@ -356,7 +393,22 @@ $ {{alias}} test -p XXX`,
 				CommitInfo:     commitInfo,
 				FullTimestamps: fullTimestamps,
 			}
-			return pretty.PrintDetailedCommitInfo(ci)
+			if err := pretty.PrintDetailedCommitInfo(ci); err != nil {
+				return err
+			}
+			// the output commits of pipelines that egress also show the
+			// status of their egress
+			for _, prov := range commitInfo.Provenance {
+				if prov.Commit.Repo.Name != ppsconsts.SpecRepo {
+					continue
+				}
+				jobInfo, err := c.InspectJobOutputCommit(commitInfo.Commit.Repo.Name, commitInfo.Commit.ID, false)
+				if err == nil && jobInfo.EgressStatus != nil {
+					fmt.Printf("Egress: %s\n", ppspretty.EgressStatus(jobInfo.EgressStatus))
+				}
+				break
+			}
+			return nil
 		}),
 	}
 	inspectCommit.Flags().AddFlagSet(rawFlags)
@@ -365,6 +417,8 @@ $ {{alias}} test -p XXX`,
 
 	var from string
 	var number int
+	var startedAfter, startedBefore, finishedAfter, finishedBefore string
+	var onBranch, provenance, startAfter string
 	listCommit := &cobra.Command{
 		Use:   "{{alias}} <repo>[@<branch>]",
 		Short: "Return all commits on a repo.",
@@ -380,7 +434,17 @@ $ {{alias}} foo@master
 $ {{alias}} foo@master -n 20
 
 # return commits in repo "foo" since commit XXX
-$ {{alias}} foo@master --from XXX`,
+$ {{alias}} foo@master --from XXX
+
+# return commits in repo "foo" that finished in the last day
+$ {{alias}} foo --finished-after 24h
+
+# return commits in repo "foo" that were made on branch "staging", and have
+# provenance in repo "bar"
+$ {{alias}} foo --on-branch staging --provenance bar
+
+# return the next 20 commits in repo "foo", after commit XXX
+$ {{alias}} foo -n 20 --start-after XXX`,
 		Run: cmdutil.RunFixedArgs(1, func(args []string) (retErr error) {
 			c, err := client.NewOnUserMachine("user")
 			if err != nil {
@@ -393,13 +457,43 @@ $ {{alias}} foo@master --from XXX`,
 				return err
 			}
 
+			req := &pfsclient.ListCommitRequest{
+				Repo:       branch.Repo,
+				Number:     uint64(number),
+				Branch:     onBranch,
+				StartAfter: startAfter,
+			}
+			if branch.Name != "" {
+				req.To = client.NewCommit(branch.Repo.Name, branch.Name)
+			}
+			if from != "" {
+				req.From = client.NewCommit(branch.Repo.Name, from)
+			}
+			if provenance != "" {
+				req.Provenance = client.NewRepo(provenance)
+			}
+			for _, bound := range []struct {
+				flag  string
+				value string
+				ts    **types.Timestamp
+			}{
+				{"started-after", startedAfter, &req.StartedAfter},
+				{"started-before", startedBefore, &req.StartedBefore},
+				{"finished-after", finishedAfter, &req.FinishedAfter},
+				{"finished-before", finishedBefore, &req.FinishedBefore},
+			} {
+				if *bound.ts, err = parseCommitTime(bound.value); err != nil {
+					return fmt.Errorf("invalid --%s: %v", bound.flag, err)
+				}
+			}
+
 			if raw {
-				return c.ListCommitF(branch.Repo.Name, branch.Name, from, uint64(number), false, func(ci *pfsclient.CommitInfo) error {
+				return c.ListCommitFilterF(req, func(ci *pfsclient.CommitInfo) error {
 					return marshaller.Marshal(os.Stdout, ci)
 				})
 			}
 			writer := tabwriter.NewWriter(os.Stdout, pretty.CommitHeader)
-			if err := c.ListCommitF(branch.Repo.Name, branch.Name, from, uint64(number), false, func(ci *pfsclient.CommitInfo) error {
+			if err := c.ListCommitFilterF(req, func(ci *pfsclient.CommitInfo) error {
 				pretty.PrintCommitInfo(writer, ci, fullTimestamps)
 				return nil
 			}); err != nil {
@@ -410,6 +504,13 @@ $ {{alias}} foo@master --from XXX`,
 	}
 	listCommit.Flags().StringVarP(&from, "from", "f", "", "list all commits since this commit")
 	listCommit.Flags().IntVarP(&number, "number", "n", 0, "list only this many commits; if set to zero, list all commits")
+	listCommit.Flags().StringVar(&startedAfter, "started-after", "", "list only commits that started at or after this time (RFC 3339, or a duration before now, e.g. 24h)")
+	listCommit.Flags().StringVar(&startedBefore, "started-before", "", "list only commits that started at or before this time (RFC 3339, or a duration before now)")
+	listCommit.Flags().StringVar(&finishedAfter, "finished-after", "", "list only commits that finished at or after this time (RFC 3339, or a duration before now)")
+	listCommit.Flags().StringVar(&finishedBefore, "finished-before", "", "list only commits that finished at or before this time (RFC 3339, or a duration before now)")
+	listCommit.Flags().StringVar(&onBranch, "on-branch", "", "list only commits that were made on this branch")
+	listCommit.Flags().StringVar(&provenance, "provenance", "", "list only commits with provenance in this repo")
+	listCommit.Flags().StringVar(&startAfter, "start-after", "", "list only commits after this one, e.g. the last commit of the previous page of results")
 	listCommit.MarkFlagCustom("from", "__pachctl_get_commit $(__parse_repo ${nouns[0]})")
 	listCommit.Flags().AddFlagSet(rawFlags)
 	listCommit.Flags().AddFlagSet(fullTimestampsFlags)
@@ -488,6 +589,7 @@ $ {{alias}} foo@XXX -r bar -r baz`,
 
 	var newCommits bool
 	var pipeline string
+	var validOnly bool
 	subscribeCommit := &cobra.Command{
 		Use:   "{{alias}} <repo>@<branch>",
 		Short: "Print commits as they are created (finished).",
@@ -500,7 +602,10 @@ $ {{alias}} test@master
 $ {{alias}} test@master --from XXX
 
 # subscribe to commits in repo "test" on branch "master", but only for new commits created from now on.
-$ {{alias}} test@master --new`,
+$ {{alias}} test@master --new
+
+# subscribe to commits in repo "test" on branch "master", but only once they're finished, if they and their provenance aren't empty.
+$ {{alias}} test@master --valid`,
 		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
 			branch, err := cmdutil.ParseBranch(args[0])
 			if err != nil {
@@ -529,7 +634,12 @@ $ {{alias}} test@master --new`,
 				prov = client.NewCommitProvenance(ppsconsts.SpecRepo, pipeline, pipelineInfo.SpecCommit.ID)
 			}
 
-			commitIter, err := c.SubscribeCommit(branch.Repo.Name, branch.Name, prov, from, pfsclient.CommitState_STARTED)
+			var commitIter client.CommitInfoIterator
+			if validOnly {
+				commitIter, err = c.SubscribeValidCommit(branch.Repo.Name, branch.Name, prov, from)
+			} else {
+				commitIter, err = c.SubscribeCommit(branch.Repo.Name, branch.Name, prov, from, pfsclient.CommitState_STARTED)
+			}
 			if err != nil {
 				return err
 			}
@@ -541,10 +651,70 @@ $ {{alias}} test@master --new`,
 	subscribeCommit.Flags().StringVar(&pipeline, "pipeline", "", "subscribe to all commits created by this pipeline")
 	subscribeCommit.MarkFlagCustom("from", "__pachctl_get_commit $(__parse_repo ${nouns[0]})")
 	subscribeCommit.Flags().BoolVar(&newCommits, "new", false, "subscribe to only new commits created from now on")
+	subscribeCommit.Flags().BoolVar(&validOnly, "valid", false, "subscribe to only finished commits that, along with their provenance, aren't empty (e.g. the output of failed jobs)")
 	subscribeCommit.Flags().AddFlagSet(rawFlags)
 	subscribeCommit.Flags().AddFlagSet(fullTimestampsFlags)
 	commands = append(commands, cmdutil.CreateAlias(subscribeCommit, "subscribe commit"))
 
+	var webhook string
+	subscribeFileChanges := &cobra.Command{
+		Use:   "{{alias}} <repo>@<branch>",
+		Short: "Print the files changed by commits as they are finished.",
+		Long:  "Print the files created, updated and deleted by each commit on the specified repo and branch, as the commits are finished.  By default, the changes of all existing commits on the branch are returned first.  With --webhook, each commit's changes are POSTed to a URL as JSON instead, and retried until the URL responds with a 2xx status.",
+		Example: `
+# print the files changed by commits in repo "test" on branch "master"
+$ {{alias}} test@master
+
+# only print the changes of new commits created from now on
+$ {{alias}} test@master --new
+
+# POST the changes of each new commit to a webhook
+$ {{alias}} test@master --new --webhook http://example.com/hook`,
+		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
+			branch, err := cmdutil.ParseBranch(args[0])
+			if err != nil {
+				return err
+			}
+			c, err := client.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+
+			if newCommits && from != "" {
+				return fmt.Errorf("--new and --from cannot be used together")
+			}
+
+			if newCommits {
+				from = branch.Name
+			}
+
+			if webhook != "" {
+				if _, err := url.ParseRequestURI(webhook); err != nil {
+					return fmt.Errorf("invalid webhook URL: %v", err)
+				}
+				httpClient := &http.Client{Timeout: webhookTimeout}
+				return c.SubscribeFileChangesF(branch.Repo.Name, branch.Name, from, func(event *pfsclient.FileChangeEvent) error {
+					return postFileChangeEvent(httpClient, webhook, event, backoff.NewExponentialBackOff(), os.Stderr)
+				})
+			}
+			writer := tabwriter.NewWriter(os.Stdout, pretty.FileChangeHeader)
+			return c.SubscribeFileChangesF(branch.Repo.Name, branch.Name, from, func(event *pfsclient.FileChangeEvent) error {
+				if raw {
+					return marshaller.Marshal(os.Stdout, event)
+				}
+				pretty.PrintFileChangeEvent(writer, event)
+				return writer.Flush()
+			})
+		}),
+	}
+	subscribeFileChanges.Flags().StringVar(&from, "from", "", "subscribe to the changes of all commits since this commit")
+	subscribeFileChanges.MarkFlagCustom("from", "__pachctl_get_commit $(__parse_repo ${nouns[0]})")
+	subscribeFileChanges.Flags().BoolVar(&newCommits, "new", false, "subscribe to only the changes of new commits created from now on")
+	subscribeFileChanges.Flags().StringVar(&webhook, "webhook", "", "POST the changes of each commit to this URL as JSON, instead of printing them")
+	subscribeFileChanges.Flags().AddFlagSet(rawFlags)
+	commands = append(commands, cmdutil.CreateAlias(subscribeFileChanges, "subscribe file-changes"))
+
 	deleteCommit := &cobra.Command{
 		Use:   "{{alias}} <repo>@<branch-or-commit>",
 		Short: "Delete an input commit.",
@@ -567,6 +737,45 @@ $ {{alias}} test@master --new`,
 	}
 	commands = append(commands, cmdutil.CreateAlias(deleteCommit, "delete commit"))
 
+	var dryRun bool
+	expireCommit := &cobra.Command{
+		Use:   "{{alias}} <repo>",
+		Short: "Delete the commits in a repo that are older than its commit TTL.",
+		Long:  "Delete the commits in a repo that are older than its commit TTL (see 'create repo --commit-ttl'), and print them. Pachyderm does this periodically, so this is mostly useful with --dry-run, to see what the next expiry will delete.",
+		Example: `
+# print the commits in repo "foo" that will be expired, without deleting them
+$ {{alias}} foo --dry-run`,
+		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
+			c, err := client.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+
+			commitInfos, err := c.ExpireCommits(args[0], dryRun)
+			if err != nil {
+				return err
+			}
+			if raw {
+				for _, ci := range commitInfos {
+					if err := marshaller.Marshal(os.Stdout, ci); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+			writer := tabwriter.NewWriter(os.Stdout, pretty.CommitHeader)
+			for _, ci := range commitInfos {
+				pretty.PrintCommitInfo(writer, ci, fullTimestamps)
+			}
+			return writer.Flush()
+		}),
+	}
+	expireCommit.Flags().BoolVar(&dryRun, "dry-run", false, "Print the commits that would be deleted, without deleting them.")
+	expireCommit.Flags().AddFlagSet(rawFlags)
+	expireCommit.Flags().AddFlagSet(fullTimestampsFlags)
+	commands = append(commands, cmdutil.CreateAlias(expireCommit, "expire commit"))
+
 	branchDocs := &cobra.Command{
 		Short: "Docs for branches.",
 		Long: `A branch in Pachyderm is an alias for a Commit ID.
@@ -611,6 +820,53 @@ Any pachctl command that can take a Commit ID, can take a branch name instead.`,
 	createBranch.MarkFlagCustom("head", "__pachctl_get_commit $(__parse_repo ${nouns[0]})")
 	commands = append(commands, cmdutil.CreateAlias(createBranch, "create branch"))
 
+	var requireProvenance bool
+	var allowedCommitters []string
+	var unprotect bool
+	updateBranch := &cobra.Command{
+		Use:   "{{alias}} <repo>@<branch>",
+		Short: "Update the protection of a branch.",
+		Long:  "Update the protection of a branch, which restricts the commits that may be made on it. Changing a branch's protection requires OWNER access to its repo.",
+		Example: `
+# only accept commits with provenance (e.g. pipeline output) on test@master
+$ {{alias}} test@master --require-provenance
+
+# only let alice and the ci robot commit to test@master
+$ {{alias}} test@master --allowed-committers github:alice,robot:ci
+
+# remove test@master's protection
+$ {{alias}} test@master --unprotect`,
+		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
+			branch, err := cmdutil.ParseBranch(args[0])
+			if err != nil {
+				return err
+			}
+			if unprotect && (requireProvenance || len(allowedCommitters) > 0) {
+				return fmt.Errorf("--unprotect can't be combined with other protection flags")
+			}
+			if !unprotect && !requireProvenance && len(allowedCommitters) == 0 {
+				return fmt.Errorf("one of --require-provenance, --allowed-committers or --unprotect must be set")
+			}
+			protection := &pfsclient.BranchProtection{
+				RequireProvenance: requireProvenance,
+				AllowedCommitters: allowedCommitters,
+			}
+			c, err := client.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+
+			return txncmds.WithActiveTransaction(c, func(c *client.APIClient) error {
+				return c.ProtectBranch(branch.Repo.Name, branch.Name, protection)
+			})
+		}),
+	}
+	updateBranch.Flags().BoolVar(&requireProvenance, "require-provenance", false, "Only accept commits with provenance on the branch, such as a pipeline's output, rather than files put on it directly.")
+	updateBranch.Flags().StringSliceVar(&allowedCommitters, "allowed-committers", nil, "The only users (e.g. github:alice or robot:ci) who may commit to the branch, if auth is active.")
+	updateBranch.Flags().BoolVar(&unprotect, "unprotect", false, "Remove the branch's protection.")
+	commands = append(commands, cmdutil.CreateAlias(updateBranch, "update branch"))
+
 	inspectBranch := &cobra.Command{
 		Use:   "{{alias}}  <repo>@<branch>",
 		Short: "Return info about a branch.",
@@ -719,6 +975,7 @@ from commits with 'get file'.`,
 	var headerRecords uint
 	var putFileCommit bool
 	var overwrite bool
+	var partSize uint64
 	putFile := &cobra.Command{
 		Use:   "{{alias}} <repo>@<branch-or-commit>[:<path/in/pfs>]",
 		Short: "Put a file into the filesystem.",
@@ -736,6 +993,10 @@ $ {{alias}} repo@branch:/path -f file
 # Put a file from the local filesystem as repo/branch/file:
 $ {{alias}} repo@branch -f file
 
+# Put a large file from the local filesystem in 256MB parts, uploading 8 parts
+# in parallel. If the upload fails, running the same command again resumes it.
+$ {{alias}} repo@branch:/path -f file --part-size 268435456 -p 8
+
 # Put the contents of a directory as repo/branch/path/dir/file:
 $ {{alias}} -r repo@branch:/path -f dir
 
@@ -786,6 +1047,9 @@ $ {{alias}} repo@branch -i http://host/path`,
 			if putFileCommit {
 				fmt.Fprintf(os.Stderr, "flag --commit / -c is deprecated; as of 1.7.2, you will get the same behavior without it\n")
 			}
+			if partSize > 0 && split != "" {
+				return fmt.Errorf("--part-size can't be used with --split")
+			}
 
 			limiter := limit.New(int(parallelism))
 			var sources []string
@@ -840,19 +1104,19 @@ $ {{alias}} repo@branch -i http://host/path`,
 						return fmt.Errorf("must specify filename when reading data from stdin")
 					}
 					eg.Go(func() error {
-						return putFileHelper(c, pfc, file.Commit.Repo.Name, file.Commit.ID, joinPaths("", source), source, recursive, overwrite, limiter, split, targetFileDatums, targetFileBytes, headerRecords, filesPut)
+						return putFileHelper(c, pfc, file.Commit.Repo.Name, file.Commit.ID, joinPaths("", source), source, recursive, overwrite, limiter, split, targetFileDatums, targetFileBytes, headerRecords, partSize, parallelism, filesPut)
 					})
 				} else if len(sources) == 1 {
 					// We have a single source and the user has specified a path,
 					// we use the path and ignore source (in terms of naming the file).
 					eg.Go(func() error {
-						return putFileHelper(c, pfc, file.Commit.Repo.Name, file.Commit.ID, file.Path, source, recursive, overwrite, limiter, split, targetFileDatums, targetFileBytes, headerRecords, filesPut)
+						return putFileHelper(c, pfc, file.Commit.Repo.Name, file.Commit.ID, file.Path, source, recursive, overwrite, limiter, split, targetFileDatums, targetFileBytes, headerRecords, partSize, parallelism, filesPut)
 					})
 				} else {
 					// We have multiple sources and the user has specified a path,
 					// we use that path as a prefix for the filepaths.
 					eg.Go(func() error {
-						return putFileHelper(c, pfc, file.Commit.Repo.Name, file.Commit.ID, joinPaths(file.Path, source), source, recursive, overwrite, limiter, split, targetFileDatums, targetFileBytes, headerRecords, filesPut)
+						return putFileHelper(c, pfc, file.Commit.Repo.Name, file.Commit.ID, joinPaths(file.Path, source), source, recursive, overwrite, limiter, split, targetFileDatums, targetFileBytes, headerRecords, partSize, parallelism, filesPut)
 					})
 				}
 			}
@@ -863,10 +1127,11 @@ $ {{alias}} repo@branch -i http://host/path`,
 	putFile.Flags().StringVarP(&inputFile, "input-file", "i", "", "Read filepaths or URLs from a file.  If - is used, paths are read from the standard input.")
 	putFile.Flags().BoolVarP(&recursive, "recursive", "r", false, "Recursively put the files in a directory.")
 	putFile.Flags().IntVarP(&parallelism, "parallelism", "p", DefaultParallelism, "The maximum number of files that can be uploaded in parallel.")
-	putFile.Flags().StringVar(&split, "split", "", "Split the input file into smaller files, subject to the constraints of --target-file-datums and --target-file-bytes. Permissible values are `line`, `json`, `sql` and `csv`.")
+	putFile.Flags().StringVar(&split, "split", "", "Split the input file into smaller files, subject to the constraints of --target-file-datums and --target-file-bytes. Permissible values are `line`, `json`, `sql`, `csv` and `protobuf` (varint-delimited messages).")
 	putFile.Flags().UintVar(&targetFileDatums, "target-file-datums", 0, "The upper bound of the number of datums that each file contains, the last file will contain fewer if the datums don't divide evenly; needs to be used with --split.")
 	putFile.Flags().UintVar(&targetFileBytes, "target-file-bytes", 0, "The target upper bound of the number of bytes that each file contains; needs to be used with --split.")
 	putFile.Flags().UintVar(&headerRecords, "header-records", 0, "the number of records that will be converted to a PFS 'header', and prepended to future retrievals of any subset of data from PFS; needs to be used with --split=(json|line|csv)")
+	putFile.Flags().Uint64Var(&partSize, "part-size", 0, "Upload local files larger than this many bytes in parts of this size, with up to --parallelism parts uploaded in parallel. Failed uploads are resumed by running the same command again. Can't be used with --split.")
 	putFile.Flags().BoolVarP(&putFileCommit, "commit", "c", false, "DEPRECATED: Put file(s) in a new commit.")
 	putFile.Flags().BoolVarP(&overwrite, "overwrite", "o", false, "Overwrite the existing content of the file, either from previous commits or previous calls to 'put file' within this commit.")
 	commands = append(commands, cmdutil.CreateAlias(putFile, "put file"))
@@ -901,6 +1166,8 @@ $ {{alias}} repo@branch -i http://host/path`,
 	commands = append(commands, cmdutil.CreateAlias(copyFile, "copy file"))
 
 	var outputPath string
+	var delimiter string
+	var offsetRecords, numRecords int64
 	getFile := &cobra.Command{
 		Use:   "{{alias}} <repo>@<branch-or-commit>:<path/in/pfs>",
 		Short: "Return the contents of a file.",
@@ -915,7 +1182,10 @@ $ {{alias}} foo@master^:XXX
 
 # get file "XXX" in the grandparent of the current head of branch "master"
 # in repo "foo"
-$ {{alias}} foo@master^2:XXX`,
+$ {{alias}} foo@master^2:XXX
+
+# get lines 100 to 199 of file "XXX" on branch "master" in repo "foo"
+$ {{alias}} foo@master:XXX --delimiter line --offset-records 100 --num-records 100`,
 		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
 			file, err := cmdutil.ParseFile(args[0])
 			if err != nil {
@@ -945,14 +1215,27 @@ $ {{alias}} foo@master^2:XXX`,
 				defer f.Close()
 				w = f
 			}
+			if delimiter != "" {
+				d, err := parseDelimiter(delimiter)
+				if err != nil {
+					return err
+				}
+				return c.GetFileRecords(file.Commit.Repo.Name, file.Commit.ID, file.Path, d, offsetRecords, numRecords, w)
+			} else if offsetRecords != 0 || numRecords != 0 {
+				return fmt.Errorf("--offset-records and --num-records need to be used with --delimiter")
+			}
 			return c.GetFile(file.Commit.Repo.Name, file.Commit.ID, file.Path, 0, 0, w)
 		}),
 	}
 	getFile.Flags().BoolVarP(&recursive, "recursive", "r", false, "Recursively download a directory.")
 	getFile.Flags().StringVarP(&outputPath, "output", "o", "", "The path where data will be downloaded.")
 	getFile.Flags().IntVarP(&parallelism, "parallelism", "p", DefaultParallelism, "The maximum number of files that can be downloaded in parallel")
+	getFile.Flags().StringVar(&delimiter, "delimiter", "", "Split the file into records, of which --offset-records and --num-records select whole ones. Permissible values are `line`, `json`, `csv` and `protobuf` (varint-delimited messages).")
+	getFile.Flags().Int64Var(&offsetRecords, "offset-records", 0, "The number of records to skip; needs to be used with --delimiter.")
+	getFile.Flags().Int64Var(&numRecords, "num-records", 0, "The most records to return (0 returns all of them); needs to be used with --delimiter.")
 	commands = append(commands, cmdutil.CreateAlias(getFile, "get file"))
 
+	var checksums string
 	inspectFile := &cobra.Command{
 		Use:   "{{alias}} <repo>@<branch-or-commit>:<path/in/pfs>",
 		Short: "Return info about a file.",
@@ -962,12 +1245,16 @@ $ {{alias}} foo@master^2:XXX`,
 			if err != nil {
 				return err
 			}
+			algorithms, err := pfsclient.ParseChecksumAlgorithms(checksums)
+			if err != nil {
+				return err
+			}
 			c, err := client.NewOnUserMachine("user")
 			if err != nil {
 				return err
 			}
 			defer c.Close()
-			fileInfo, err := c.InspectFile(file.Commit.Repo.Name, file.Commit.ID, file.Path)
+			fileInfo, err := c.InspectFileChecksums(file.Commit.Repo.Name, file.Commit.ID, file.Path, algorithms...)
 			if err != nil {
 				return err
 			}
@@ -981,9 +1268,13 @@ $ {{alias}} foo@master^2:XXX`,
 		}),
 	}
 	inspectFile.Flags().AddFlagSet(rawFlags)
+	inspectFile.Flags().StringVar(&checksums, "checksums", "", "A comma-separated list of checksums (sha256, md5) of the file to compute and print.")
 	commands = append(commands, cmdutil.CreateAlias(inspectFile, "inspect file"))
 
 	var history string
+	var summary bool
+	var maxDepth int64
+	var fileType string
 	listFile := &cobra.Command{
 		Use:   "{{alias}} <repo>@<branch-or-commit>[:<path/in/pfs>]",
 		Short: "Return the files in a directory.",
@@ -1007,7 +1298,18 @@ $ {{alias}} foo@master^2
 $ {{alias}} foo@master --history n
 
 # list all versions of top-level files on branch "master" in repo "foo"
-$ {{alias}} foo@master --history all`,
+$ {{alias}} foo@master --history all
+
+# print the number and total size of the files (not counting subdirectories)
+# in directory "dir" on branch "master" in repo "foo"
+$ {{alias}} foo@master:dir --summary
+
+# list all of the files and directories below directory "dir" on branch
+# "master" in repo "foo", down to two levels deep
+$ {{alias}} foo@master:dir --recursive --max-depth 2
+
+# list only the regular files below directory "dir"
+$ {{alias}} foo@master:dir -r --type file`,
 		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
 			file, err := cmdutil.ParseFile(args[0])
 			if err != nil {
@@ -1017,11 +1319,71 @@ $ {{alias}} foo@master --history all`,
 			if err != nil {
 				return fmt.Errorf("error parsing history flag: %v", err)
 			}
+			if !recursive && (maxDepth != 0 || fileType != "") {
+				return fmt.Errorf("--max-depth and --type can only be used with --recursive")
+			}
 			c, err := client.NewOnUserMachine("user")
 			if err != nil {
 				return err
 			}
 			defer c.Close()
+			if recursive {
+				if history != 0 {
+					return fmt.Errorf("--recursive cannot be used with --history")
+				}
+				request := &pfsclient.WalkFileRequest{File: file, MaxDepth: maxDepth}
+				switch strings.ToLower(fileType) {
+				case "":
+				case "file":
+					request.FileType = pfsclient.FileType_FILE
+				case "dir":
+					request.FileType = pfsclient.FileType_DIR
+				default:
+					return fmt.Errorf("invalid --type %q, must be \"file\" or \"dir\"", fileType)
+				}
+				if summary {
+					// Only the regular files are counted, as with ListFileSummary
+					if request.FileType == pfsclient.FileType_DIR {
+						return fmt.Errorf("--summary only counts regular files, so it cannot be used with --type dir")
+					}
+					request.FileType, request.SizeOnly = pfsclient.FileType_FILE, true
+					var count int64
+					var size uint64
+					if err := c.WalkFileF(request, func(fi *pfsclient.FileInfo) error {
+						count++
+						size += fi.SizeBytes
+						return nil
+					}); err != nil {
+						return err
+					}
+					fmt.Printf("%d files, %s\n", count, units.BytesSize(float64(size)))
+					return nil
+				}
+				if raw {
+					return c.WalkFileF(request, func(fi *pfsclient.FileInfo) error {
+						return marshaller.Marshal(os.Stdout, fi)
+					})
+				}
+				writer := tabwriter.NewWriter(os.Stdout, pretty.FileHeader)
+				if err := c.WalkFileF(request, func(fi *pfsclient.FileInfo) error {
+					pretty.PrintFileInfo(writer, fi, fullTimestamps, false)
+					return nil
+				}); err != nil {
+					return err
+				}
+				return writer.Flush()
+			}
+			if summary {
+				if history != 0 {
+					return fmt.Errorf("--summary cannot be used with --history")
+				}
+				count, size, err := c.ListFileSummary(file.Commit.Repo.Name, file.Commit.ID, file.Path)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("%d files, %s\n", count, units.BytesSize(float64(size)))
+				return nil
+			}
 			if raw {
 				return c.ListFileF(file.Commit.Repo.Name, file.Commit.ID, file.Path, history, func(fi *pfsclient.FileInfo) error {
 					return marshaller.Marshal(os.Stdout, fi)
@@ -1044,6 +1406,10 @@ $ {{alias}} foo@master --history all`,
 	listFile.Flags().AddFlagSet(rawFlags)
 	listFile.Flags().AddFlagSet(fullTimestampsFlags)
 	listFile.Flags().StringVar(&history, "history", "none", "Return revision history for files.")
+	listFile.Flags().BoolVar(&summary, "summary", false, "Only print the number and total size of the files (excluding directories).")
+	listFile.Flags().BoolVarP(&recursive, "recursive", "r", false, "List all of the files below the directory, rather than its children, in a single request.")
+	listFile.Flags().Int64Var(&maxDepth, "max-depth", 0, "With --recursive, the deepest level below the directory to list (0 is no limit).")
+	listFile.Flags().StringVar(&fileType, "type", "", "With --recursive, only list files of this type (\"file\" or \"dir\").")
 	commands = append(commands, cmdutil.CreateAlias(listFile, "list file"))
 
 	globFile := &cobra.Command{
@@ -1091,6 +1457,60 @@ $ {{alias}} "foo@master:data/*"`,
 	globFile.Flags().AddFlagSet(fullTimestampsFlags)
 	commands = append(commands, cmdutil.CreateAlias(globFile, "glob file"))
 
+	var attributes []string
+	searchFile := &cobra.Command{
+		Use:   "{{alias}} <repo>@<branch-or-commit>[:<pattern>]",
+		Short: "Return files in a commit that have been indexed with the given attributes.",
+		Long:  "Return files in a finished commit that the repo's index extractors have indexed with all of the given attributes, optionally only those that match a glob pattern.",
+		Example: `
+# Return the CSV files in repo "foo" on branch "master" that have a column
+# named "price".
+$ {{alias}} foo@master --attribute csv.column.price
+
+# Return the 640x480 images under directory "data".
+$ {{alias}} "foo@master:data/*" -a image.width=640 -a image.height=480`,
+		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
+			file, err := cmdutil.ParseFile(args[0])
+			if err != nil {
+				return err
+			}
+			attributeMap := make(map[string]string)
+			for _, attribute := range attributes {
+				parts := strings.SplitN(attribute, "=", 2)
+				if len(parts) == 1 {
+					// the file only has to have the attribute
+					parts = append(parts, "")
+				}
+				attributeMap[parts[0]] = parts[1]
+			}
+			c, err := client.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			files, err := c.SearchFiles(file.Commit.Repo.Name, file.Commit.ID, file.Path, attributeMap)
+			if err != nil {
+				return err
+			}
+			if raw {
+				for _, f := range files {
+					if err := marshaller.Marshal(os.Stdout, f); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+			writer := tabwriter.NewWriter(os.Stdout, pretty.IndexedFileHeader)
+			for _, f := range files {
+				pretty.PrintIndexedFile(writer, f)
+			}
+			return writer.Flush()
+		}),
+	}
+	searchFile.Flags().StringArrayVarP(&attributes, "attribute", "a", nil, "An attribute (name=value, or just name to match any value) that files must have. May be given more than once.")
+	searchFile.Flags().AddFlagSet(rawFlags)
+	commands = append(commands, cmdutil.CreateAlias(searchFile, "search file"))
+
 	var shallow bool
 	var nameOnly bool
 	var diffCmdArg string
@@ -1299,10 +1719,56 @@ Tags are a low-level resource and should not be accessed directly by most users.
 	return commands
 }
 
+// parseStoragePolicy parses the --storage-class flag of create and update
+// repo. It returns nil if the flag is unset.
+func parseStoragePolicy(storageClass string) (*pfsclient.StoragePolicy, error) {
+	if storageClass == "" {
+		return nil, nil
+	}
+	class, ok := pfsclient.StorageClass_value[strings.ToUpper(strings.Replace(storageClass, "-", "_", -1))]
+	if !ok {
+		return nil, fmt.Errorf("invalid storage class %q, must be one of standard, infrequent-access or archive", storageClass)
+	}
+	return &pfsclient.StoragePolicy{StorageClass: pfsclient.StorageClass(class)}, nil
+}
+
+// parseDelimiter parses the --split flag of put file and the --delimiter
+// flag of get file
+func parseDelimiter(delimiter string) (pfsclient.Delimiter, error) {
+	switch delimiter {
+	case "line":
+		return pfsclient.Delimiter_LINE, nil
+	case "json":
+		return pfsclient.Delimiter_JSON, nil
+	case "sql":
+		return pfsclient.Delimiter_SQL, nil
+	case "csv":
+		return pfsclient.Delimiter_CSV, nil
+	case "protobuf":
+		return pfsclient.Delimiter_PROTOBUF, nil
+	}
+	return pfsclient.Delimiter_NONE, fmt.Errorf("unrecognized delimiter '%s'; only accepts one of "+
+		"{json,line,sql,csv,protobuf}", delimiter)
+}
+
+// parseCommitTTL parses the --commit-ttl flag of create and update repo. It
+// returns nil if the flag is unset.
+func parseCommitTTL(commitTTL string) (*types.Duration, error) {
+	if commitTTL == "" {
+		return nil, nil
+	}
+	ttl, err := time.ParseDuration(commitTTL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid commit TTL %q: %v", commitTTL, err)
+	}
+	return types.DurationProto(ttl), nil
+}
+
 func putFileHelper(c *client.APIClient, pfc client.PutFileClient,
 	repo, commit, path, source string, recursive, overwrite bool, // destination
 	limiter limit.ConcurrencyLimiter,
 	split string, targetFileDatums, targetFileBytes, headerRecords uint, // split
+	partSize uint64, parallelism int, // parts
 	filesPut *gosync.Map) (retErr error) {
 	// Resolve the path, then trim any prefixed '../' to avoid sending bad paths
 	// to the server
@@ -1333,21 +1799,11 @@ func putFileHelper(c *client.APIClient, pfc client.PutFileClient,
 			return err
 		}
 
-		var delimiter pfsclient.Delimiter
-		switch split {
-		case "line":
-			delimiter = pfsclient.Delimiter_LINE
-		case "json":
-			delimiter = pfsclient.Delimiter_JSON
-		case "sql":
-			delimiter = pfsclient.Delimiter_SQL
-		case "csv":
-			delimiter = pfsclient.Delimiter_CSV
-		default:
-			return fmt.Errorf("unrecognized delimiter '%s'; only accepts one of "+
-				"{json,line,sql,csv}", split)
+		delimiter, err := parseDelimiter(split)
+		if err != nil {
+			return err
 		}
-		_, err := pfc.PutFileSplit(repo, commit, path, delimiter, int64(targetFileDatums), int64(targetFileBytes), int64(headerRecords), overwrite, reader)
+		_, err = pfc.PutFileSplit(repo, commit, path, delimiter, int64(targetFileDatums), int64(targetFileBytes), int64(headerRecords), overwrite, reader)
 		return err
 	}
 
@@ -1383,7 +1839,7 @@ func putFileHelper(c *client.APIClient, pfc client.PutFileClient,
 				// next one
 				return putFileHelper(c, pfc, repo, commit, childDest, filePath, false,
 					overwrite, limiter, split, targetFileDatums, targetFileBytes,
-					headerRecords, filesPut)
+					headerRecords, partSize, parallelism, filesPut)
 			})
 			return nil
 		}); err != nil {
@@ -1402,9 +1858,59 @@ func putFileHelper(c *client.APIClient, pfc client.PutFileClient,
 			retErr = err
 		}
 	}()
+	if partSize > 0 && split == "" {
+		info, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() && uint64(info.Size()) > partSize {
+			manifestPath, err := putFileManifestPath(repo, commit, path, source, info)
+			if err != nil {
+				return err
+			}
+			return c.PutFileParallel(repo, commit, path, f, info.Size(), int64(partSize), parallelism, overwrite, manifestPath)
+		}
+	}
 	return putFile(f)
 }
 
+// putFileManifestPath returns the local path of the manifest that records the
+// progress of putting 'source' in parts, so that a failed 'put file' can be
+// resumed by running it again. The name depends on the source file's size and
+// modification time, so that a modified file is put from scratch.
+func putFileManifestPath(repo, commit, path, source string, info os.FileInfo) (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(cacheDir, "pachyderm", "put-file")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	absSource, err := filepath.Abs(source)
+	if err != nil {
+		return "", err
+	}
+	key := fmt.Sprintf("%s\x00%s\x00%s\x00%s\x00%d\x00%d", repo, commit, path, absSource, info.Size(), info.ModTime().UnixNano())
+	return filepath.Join(dir, fmt.Sprintf("%x.json", sha256.Sum256([]byte(key)))), nil
+}
+
+// parseCommitTime parses the value of a list commit time flag, which is an
+// RFC 3339 time or a duration before now. It returns nil if 's' is empty.
+func parseCommitTime(s string) (*types.Timestamp, error) {
+	if s == "" {
+		return nil, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return types.TimestampProto(time.Now().Add(-d))
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil, fmt.Errorf("%q is neither an RFC 3339 time nor a duration", s)
+	}
+	return types.TimestampProto(t)
+}
+
 func joinPaths(prefix, filePath string) string {
 	if url, err := url.Parse(filePath); err == nil && url.Scheme != "" {
 		if url.Scheme == "pfs" {
@@ -0,0 +1,43 @@
+package cmds
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/server/pkg/backoff"
+)
+
+// webhookTimeout bounds each POST of a file change event to a webhook
+const webhookTimeout = 30 * time.Second
+
+// postFileChangeEvent POSTs 'event' to the webhook at 'url' as JSON, retrying
+// with exponential backoff until the webhook responds with a 2xx status or
+// 'b' gives up. Events are posted one at a time, so the webhook receives them
+// in commit order.
+func postFileChangeEvent(httpClient *http.Client, url string, event *pfs.FileChangeEvent, b backoff.BackOff, errOut io.Writer) error {
+	body := &bytes.Buffer{}
+	if err := (&jsonpb.Marshaler{}).Marshal(body, event); err != nil {
+		return err
+	}
+	return backoff.RetryNotify(func() error {
+		resp, err := httpClient.Post(url, "application/json", bytes.NewReader(body.Bytes()))
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		io.Copy(ioutil.Discard, resp.Body)
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("webhook responded with %s", resp.Status)
+		}
+		return nil
+	}, b, func(err error, d time.Duration) error {
+		fmt.Fprintf(errOut, "error posting the changes of commit %s to %s; retrying in %v: %v\n", event.Commit.ID, url, d, err)
+		return nil
+	})
+}
@@ -0,0 +1,49 @@
+package cmds
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/server/pkg/backoff"
+)
+
+func TestPostFileChangeEvent(t *testing.T) {
+	event := &pfs.FileChangeEvent{
+		Commit:  client.NewCommit("repo", "commit"),
+		Changes: []*pfs.FileChange{{Path: "/file", Type: pfs.FileChangeType_FILE_CREATED}},
+	}
+	var requests int
+	var received *pfs.FileChangeEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		// fail the first request, to check that it's retried
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		received = &pfs.FileChangeEvent{}
+		require.NoError(t, jsonpb.Unmarshal(r.Body, received))
+	}))
+	defer server.Close()
+
+	require.NoError(t, postFileChangeEvent(server.Client(), server.URL, event, backoff.NewConstantBackOff(time.Millisecond), ioutil.Discard))
+	require.Equal(t, 2, requests)
+	require.Equal(t, event, received)
+
+	// a webhook that never succeeds is given up on once the backoff stops
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	b := backoff.NewExponentialBackOff()
+	b.InitialInterval = time.Millisecond
+	b.MaxElapsedTime = 50 * time.Millisecond
+	require.YesError(t, postFileChangeEvent(failing.Client(), failing.URL, event, b, ioutil.Discard))
+}
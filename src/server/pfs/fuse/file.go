@@ -1,12 +1,15 @@
 package fuse
 
 import (
+	"bytes"
 	"context"
 	"io"
 	"io/ioutil"
 	"math"
 	"os"
+	"path"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -23,6 +26,13 @@ type file struct {
 	file    *os.File
 	counter *counter
 	err     error
+	// fs and pageFile are set if the file is read in pages, in which case
+	// file and counter are nil
+	fs       *filesystem
+	pageFile string
+	// next is the offset after the last read, so that sequential reads can be
+	// detected. It's accessed atomically.
+	next int64
 }
 
 func newFile(fs *filesystem, name string) (*file, fuse.Status) {
@@ -30,15 +40,6 @@ func newFile(fs *filesystem, name string) (*file, fuse.Status) {
 	if status != fuse.OK {
 		return nil, status
 	}
-	ctx, cancel := context.WithCancel(fs.c.Ctx())
-	c := fs.c.WithCtx(ctx)
-	f, err := ioutil.TempFile("", "pfs-fuse")
-	if err != nil {
-		return nil, fuse.ToStatus(err)
-	}
-	if err := os.Remove(f.Name()); err != nil {
-		return nil, fuse.ToStatus(err)
-	}
 	_, pfsFile, err := fs.parsePath(name)
 	if err != nil {
 		return nil, toStatus(err)
@@ -46,6 +47,25 @@ func newFile(fs *filesystem, name string) (*file, fuse.Status) {
 	if pfsFile == nil {
 		return nil, fuse.Status(syscall.EISDIR)
 	}
+	if fs.pages != nil {
+		return &file{
+			name:     name,
+			attr:     attr,
+			cancel:   func() {},
+			pfsFile:  pfsFile,
+			fs:       fs,
+			pageFile: path.Join(pfsFile.Commit.Repo.Name, pfsFile.Commit.ID, pfsFile.Path),
+		}, fuse.OK
+	}
+	f, err := ioutil.TempFile("", "pfs-fuse")
+	if err != nil {
+		return nil, fuse.ToStatus(err)
+	}
+	if err := os.Remove(f.Name()); err != nil {
+		return nil, fuse.ToStatus(err)
+	}
+	ctx, cancel := context.WithCancel(fs.c.Ctx())
+	c := fs.c.WithCtx(ctx)
 	counter := newCounter()
 	// Argument order is important here because it means that writes to w must
 	// complete writing to f before being written to counter. Thus counter can
@@ -83,6 +103,9 @@ func (f *file) InnerFile() nodefs.File {
 }
 
 func (f *file) Read(dest []byte, offset int64) (fuse.ReadResult, fuse.Status) {
+	if f.fs != nil {
+		return f.readPages(dest, offset)
+	}
 	waitn := offset + int64(len(dest))
 	if waitn > int64(f.attr.Size) {
 		waitn = int64(f.attr.Size)
@@ -98,6 +121,56 @@ func (f *file) Read(dest []byte, offset int64) (fuse.ReadResult, fuse.Status) {
 	return fuse.ReadResultFd(f.file.Fd(), offset, len(dest)), fuse.OK
 }
 
+// readPages reads the file from the mount's page cache, fetching the pages
+// that 'dest' covers if they aren't cached. Reads that start where the last
+// one ended also start fetching the next fs.readAhead pages, which are likely
+// to be read next.
+func (f *file) readPages(dest []byte, offset int64) (fuse.ReadResult, fuse.Status) {
+	pages := f.fs.pages
+	size := int64(f.attr.Size)
+	end := offset + int64(len(dest))
+	if end > size {
+		end = size
+	}
+	if end <= offset {
+		return fuse.ReadResultData(nil), fuse.OK
+	}
+	n := 0
+	for offset+int64(n) < end {
+		pos := offset + int64(n)
+		data, err := f.page(pos / pages.pageSize)
+		if err != nil {
+			return nil, toStatus(err)
+		}
+		start := pos % pages.pageSize
+		if start >= int64(len(data)) {
+			break // the file is shorter than its attr says
+		}
+		n += copy(dest[n:end-offset], data[start:])
+	}
+	if atomic.SwapInt64(&f.next, end) == offset && offset > 0 {
+		read := (end - 1) / pages.pageSize
+		last := (size - 1) / pages.pageSize
+		for i := read + 1; i <= read+int64(f.fs.readAhead) && i <= last; i++ {
+			if !pages.has(pageKey{f.pageFile, i}) {
+				go f.page(i)
+			}
+		}
+	}
+	return fuse.ReadResultData(dest[:n]), fuse.OK
+}
+
+// page returns the page 'index' of the file from the mount's page cache
+func (f *file) page(index int64) ([]byte, error) {
+	return f.fs.pages.get(pageKey{f.pageFile, index}, func(offset, size int64) ([]byte, error) {
+		var buf bytes.Buffer
+		if err := f.fs.c.GetFile(f.pfsFile.Commit.Repo.Name, f.pfsFile.Commit.ID, f.pfsFile.Path, offset, size, &buf); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	})
+}
+
 func (f *file) Flock(flags int) fuse.Status {
 	return fuse.ENOSYS
 }
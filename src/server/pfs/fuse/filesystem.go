@@ -23,10 +23,15 @@ const (
 
 // Mount pfs to mountPoint, opts may be left nil.
 func Mount(c *client.APIClient, mountPoint string, opts *Options) error {
-	nfs := pathfs.NewPathNodeFs(newFileSystem(c, opts.getCommits()), nil)
-	server, _, err := nodefs.MountRoot(mountPoint, nfs.Root(), opts.getFuse())
+	nfs := pathfs.NewPathNodeFs(newFileSystem(c, opts), nil)
+	conn := nodefs.NewFileSystemConnector(nfs.Root(), opts.getFuse())
+	mountOpts := &fuse.MountOptions{AllowOther: opts.getAllowOther()}
+	if fuseOpts := opts.getFuse(); fuseOpts != nil {
+		mountOpts.Debug = fuseOpts.Debug
+	}
+	server, err := fuse.NewServer(conn.RawFS(), mountPoint, mountOpts)
 	if err != nil {
-		return fmt.Errorf("nodefs.MountRoot: %v", err)
+		return fmt.Errorf("fuse.NewServer: %v", err)
 	}
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt)
@@ -37,6 +42,13 @@ func Mount(c *client.APIClient, mountPoint string, opts *Options) error {
 		}
 		server.Unmount()
 	}()
+	if mounted := opts.getMounted(); mounted != nil {
+		go func() {
+			if err := server.WaitMount(); err == nil {
+				close(mounted)
+			}
+		}()
+	}
 	server.Serve()
 	return nil
 }
@@ -46,9 +58,15 @@ type filesystem struct {
 	c         *client.APIClient
 	commits   map[string]string
 	commitsMu sync.RWMutex
+	// commitDirs is set if files are at <repo>/<commit>/<path>
+	commitDirs bool
+	// pages is nil if files are downloaded whole when they're opened
+	pages     *pageCache
+	readAhead int
 }
 
-func newFileSystem(c *client.APIClient, commits map[string]string) pathfs.FileSystem {
+func newFileSystem(c *client.APIClient, opts *Options) pathfs.FileSystem {
+	commits := opts.getCommits()
 	if commits == nil {
 		commits = make(map[string]string)
 	}
@@ -56,6 +74,9 @@ func newFileSystem(c *client.APIClient, commits map[string]string) pathfs.FileSy
 		FileSystem: pathfs.NewDefaultFileSystem(),
 		c:          c,
 		commits:    commits,
+		commitDirs: opts.getCommitDirs(),
+		pages:      opts.getPages(),
+		readAhead:  opts.getReadAhead(),
 	}
 }
 
@@ -70,6 +91,9 @@ func (fs *filesystem) OpenDir(name string, context *fuse.Context) ([]fuse.DirEnt
 		return nil, toStatus(err)
 	}
 	switch {
+	case r != nil && fs.commitDirs:
+		// commit directories aren't listed
+		return result, fuse.OK
 	case r != nil:
 		commit, err := fs.commit(r.Name)
 		if err != nil {
@@ -153,6 +177,8 @@ func (fs *filesystem) parsePath(name string) (*pfs.Repo, *pfs.File, error) {
 		return nil, nil, nil
 	case len(components) == 1:
 		return client.NewRepo(components[0]), nil, nil
+	case fs.commitDirs:
+		return nil, client.NewFile(components[0], components[1], path.Join(components[2:]...)), nil
 	default:
 		commit, err := fs.commit(components[0])
 		if err != nil {
@@ -212,6 +238,15 @@ func fileMode(fi *pfs.FileInfo) uint32 {
 }
 
 func (fs *filesystem) fileAttr(f *pfs.File) (*fuse.Attr, fuse.Status) {
+	if fs.commitDirs && f.Path == "" {
+		// the commit's directory
+		if _, err := fs.c.InspectCommit(f.Commit.Repo.Name, f.Commit.ID); err != nil {
+			return nil, toStatus(err)
+		}
+		return &fuse.Attr{
+			Mode: modeDir,
+		}, fuse.OK
+	}
 	fi, err := fs.c.InspectFile(f.Commit.Repo.Name, f.Commit.ID, f.Path)
 	if err != nil {
 		return nil, toStatus(err)
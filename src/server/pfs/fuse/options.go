@@ -11,10 +11,37 @@ type Options struct {
 	// the master commit of the repo at the time the repo is first requested
 	// will be used.
 	Commits map[string]string
+	// CommitDirs, if true, puts each repo's files under a directory per
+	// commit, at <repo>/<commit>/<path>, rather than at <repo>/<path> in the
+	// commit that Commits selects. Commit directories aren't listed, but any
+	// commit's directory can be opened by its ID. Commits is ignored.
+	CommitDirs bool
+	// PageSize, if nonzero, makes files be read in pages of this many bytes,
+	// which are fetched as they're read, rather than each file being
+	// downloaded whole when it's opened. So reading part of a file (e.g. the
+	// footer of a parquet file) doesn't wait for the rest of it. Pages are
+	// cached, and shared by all of the readers of a file.
+	PageSize int64
+	// CacheSize is the number of bytes of pages that are cached, if PageSize
+	// is set. It defaults to defaultCacheSize.
+	CacheSize int64
+	// ReadAhead is the number of pages, after the one that's read, that are
+	// fetched in the background when a file is read sequentially, if PageSize
+	// is set.
+	ReadAhead int
+	// AllowOther, if true, lets users other than the one that mounted pfs
+	// read it (e.g. user code that runs as another user).
+	AllowOther bool
 
 	Unmount chan struct{}
+	// Mounted, if set, is closed once pfs is mounted.
+	Mounted chan struct{}
 }
 
+// defaultCacheSize is the size of a mount's page cache if PageSize is set but
+// CacheSize isn't
+const defaultCacheSize = 256 * 1024 * 1024
+
 func (o *Options) getFuse() *nodefs.Options {
 	if o == nil {
 		return nil
@@ -35,3 +62,44 @@ func (o *Options) getUnmount() chan struct{} {
 	}
 	return o.Unmount
 }
+
+func (o *Options) getCommitDirs() bool {
+	if o == nil {
+		return false
+	}
+	return o.CommitDirs
+}
+
+// getPages returns the page cache of a mount, which is nil if files are
+// downloaded whole
+func (o *Options) getPages() *pageCache {
+	if o == nil || o.PageSize <= 0 {
+		return nil
+	}
+	cacheSize := o.CacheSize
+	if cacheSize <= 0 {
+		cacheSize = defaultCacheSize
+	}
+	return newPageCache(o.PageSize, cacheSize)
+}
+
+func (o *Options) getReadAhead() int {
+	if o == nil {
+		return 0
+	}
+	return o.ReadAhead
+}
+
+func (o *Options) getAllowOther() bool {
+	if o == nil {
+		return false
+	}
+	return o.AllowOther
+}
+
+func (o *Options) getMounted() chan struct{} {
+	if o == nil {
+		return nil
+	}
+	return o.Mounted
+}
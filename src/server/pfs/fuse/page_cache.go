@@ -0,0 +1,113 @@
+package fuse
+
+import (
+	"container/list"
+	"sync"
+)
+
+// pageKey identifies a page of a file: the page starting at byte
+// index*pageSize of file 'file' (the file's repo, commit and path)
+type pageKey struct {
+	file  string
+	index int64
+}
+
+// pageCache holds pages of file content, so that a file's content is only
+// fetched from pachd as far as it's read, and so that all of the readers of a
+// file (open copies of it, and the reads that the kernel issues concurrently
+// for one copy) share the pages that they read. While a page is being
+// fetched, other readers of it wait for that fetch rather than starting their
+// own.
+//
+// Pages are evicted, least recently used first, once the cache holds more
+// than its budget.
+type pageCache struct {
+	pageSize int64
+	budget   int64
+
+	mu   sync.Mutex
+	size int64
+	// lru holds the cache's *page, most recently used first
+	lru   *list.List
+	pages map[pageKey]*list.Element
+	// fetches holds the pages that are being fetched
+	fetches map[pageKey]*pageFetch
+}
+
+type page struct {
+	key  pageKey
+	data []byte
+}
+
+type pageFetch struct {
+	done chan struct{}
+	data []byte
+	err  error
+}
+
+func newPageCache(pageSize, budget int64) *pageCache {
+	return &pageCache{
+		pageSize: pageSize,
+		budget:   budget,
+		lru:      list.New(),
+		pages:    make(map[pageKey]*list.Element),
+		fetches:  make(map[pageKey]*pageFetch),
+	}
+}
+
+// get returns the page 'key', calling 'fetch' to get it if it isn't cached
+// or being fetched. 'fetch' is passed the offset and size of the page, and
+// may return less than 'size' bytes for the last page of a file. Failed
+// fetches aren't cached.
+func (c *pageCache) get(key pageKey, fetch func(offset, size int64) ([]byte, error)) ([]byte, error) {
+	c.mu.Lock()
+	if elem, ok := c.pages[key]; ok {
+		c.lru.MoveToFront(elem)
+		c.mu.Unlock()
+		return elem.Value.(*page).data, nil
+	}
+	if f, ok := c.fetches[key]; ok {
+		c.mu.Unlock()
+		<-f.done
+		return f.data, f.err
+	}
+	f := &pageFetch{done: make(chan struct{})}
+	c.fetches[key] = f
+	c.mu.Unlock()
+
+	f.data, f.err = fetch(key.index*c.pageSize, c.pageSize)
+	c.mu.Lock()
+	delete(c.fetches, key)
+	if f.err == nil {
+		c.pages[key] = c.lru.PushFront(&page{key: key, data: f.data})
+		c.size += int64(len(f.data))
+		c.evict()
+	}
+	c.mu.Unlock()
+	close(f.done)
+	return f.data, f.err
+}
+
+// has returns whether the page 'key' is cached or being fetched
+func (c *pageCache) has(key pageKey) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, cached := c.pages[key]
+	_, fetching := c.fetches[key]
+	return cached || fetching
+}
+
+// evict removes the least recently used pages until the cache is within its
+// budget. c.mu must be held.
+func (c *pageCache) evict() {
+	for c.size > c.budget {
+		elem := c.lru.Back()
+		if elem == nil {
+			return
+		}
+		p := elem.Value.(*page)
+		c.lru.Remove(elem)
+		delete(c.pages, p.key)
+		c.size -= int64(len(p.data))
+	}
+}
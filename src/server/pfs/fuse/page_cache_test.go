@@ -0,0 +1,79 @@
+package fuse
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestPageCache(t *testing.T) {
+	content := []byte("0123456789")
+	c := newPageCache(4, 8)
+	var mu sync.Mutex
+	fetches := 0
+	get := func(index int64) string {
+		data, err := c.get(pageKey{"repo/commit/file", index}, func(offset, size int64) ([]byte, error) {
+			mu.Lock()
+			fetches++
+			mu.Unlock()
+			end := offset + size
+			if end > int64(len(content)) {
+				end = int64(len(content))
+			}
+			return content[offset:end], nil
+		})
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	require.Equal(t, "0123", get(0))
+	require.Equal(t, "89", get(2)) // last page is short
+	require.Equal(t, "0123", get(0))
+	require.Equal(t, 2, fetches)
+
+	// page 2 is the least recently used, so it's evicted to make room for
+	// page 1
+	require.Equal(t, "4567", get(1))
+	require.Equal(t, 3, fetches)
+	require.False(t, c.has(pageKey{"repo/commit/file", 2}))
+	require.True(t, c.has(pageKey{"repo/commit/file", 0}))
+	require.Equal(t, int64(8), c.size)
+
+	// failed fetches aren't cached
+	_, err := c.get(pageKey{"repo/commit/other", 0}, func(offset, size int64) ([]byte, error) {
+		return nil, errors.New("failed")
+	})
+	require.YesError(t, err)
+	require.False(t, c.has(pageKey{"repo/commit/other", 0}))
+}
+
+func TestPageCacheConcurrentReaders(t *testing.T) {
+	c := newPageCache(4, 1024)
+	release := make(chan struct{})
+	var mu sync.Mutex
+	fetches := 0
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			data, err := c.get(pageKey{"repo/commit/file", 0}, func(offset, size int64) ([]byte, error) {
+				mu.Lock()
+				fetches++
+				mu.Unlock()
+				<-release
+				return bytes.Repeat([]byte("a"), int(size)), nil
+			})
+			require.NoError(t, err)
+			require.Equal(t, "aaaa", string(data))
+		}()
+	}
+	close(release)
+	wg.Wait()
+	// readers that arrive while a page is being fetched wait for that fetch,
+	// and later readers find it cached
+	require.Equal(t, 1, fetches)
+}
@@ -0,0 +1,37 @@
+package index
+
+import (
+	"encoding/csv"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	Register("csv", csvExtractor{})
+}
+
+// csvExtractor indexes the header of CSV files. It sets "csv.columns" to the
+// comma-separated column names, and "csv.column.<name>" to the position of
+// each column, so that files with a given column can be searched for.
+type csvExtractor struct{}
+
+func (csvExtractor) Match(p string) bool {
+	return strings.ToLower(path.Ext(p)) == ".csv"
+}
+
+func (csvExtractor) Extract(_ string, r io.Reader) (map[string]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	attributes := map[string]string{"csv.columns": strings.Join(header, ",")}
+	for i, column := range header {
+		attributes["csv.column."+column] = strconv.Itoa(i)
+	}
+	return attributes, nil
+}
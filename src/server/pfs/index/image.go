@@ -0,0 +1,41 @@
+package index
+
+import (
+	"image"
+	// image formats that DecodeConfig can read
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	Register("image", imageExtractor{})
+}
+
+// imageExtractor indexes the format and dimensions of PNG, JPEG and GIF
+// images, as "image.format", "image.width" and "image.height".
+type imageExtractor struct{}
+
+func (imageExtractor) Match(p string) bool {
+	switch strings.ToLower(path.Ext(p)) {
+	case ".png", ".jpg", ".jpeg", ".gif":
+		return true
+	}
+	return false
+}
+
+func (imageExtractor) Extract(_ string, r io.Reader) (map[string]string, error) {
+	config, format, err := image.DecodeConfig(r)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{
+		"image.format": format,
+		"image.width":  strconv.Itoa(config.Width),
+		"image.height": strconv.Itoa(config.Height),
+	}, nil
+}
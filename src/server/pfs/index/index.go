@@ -0,0 +1,72 @@
+// Package index contains the extractors that PFS uses to index the content of
+// files, so that they can be found by their attributes (e.g. the columns of a
+// CSV file) with SearchFiles. Repos choose the extractors that index their
+// files by name, from the ones registered with Register.
+package index
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// MaxRead is how much of a file's content extractors are given
+const MaxRead = 64 * 1024
+
+// An Extractor finds attributes of files from their content
+type Extractor interface {
+	// Match reports whether the extractor can index the file at 'path'
+	Match(path string) bool
+	// Extract returns the attributes of the file at 'path', given (at most)
+	// its first MaxRead bytes. Attribute names should start with the name
+	// the extractor is registered as, e.g. "csv.columns".
+	Extract(path string, r io.Reader) (map[string]string, error)
+}
+
+var (
+	mu         sync.RWMutex
+	extractors = make(map[string]Extractor)
+)
+
+// Register makes 'e' available to repos as the extractor 'name'. It's meant to
+// be called from the init function of the package that implements 'e', and
+// panics if 'name' is already registered.
+func Register(name string, e Extractor) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := extractors[name]; ok {
+		panic(fmt.Sprintf("index extractor %q is registered twice", name))
+	}
+	extractors[name] = e
+}
+
+// Get returns the extractor registered as 'name'
+func Get(name string) (Extractor, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	e, ok := extractors[name]
+	return e, ok
+}
+
+// Names returns the names of the registered extractors, sorted
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	var result []string
+	for name := range extractors {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Validate returns an error if any of 'names' isn't a registered extractor
+func Validate(names []string) error {
+	for _, name := range names {
+		if _, ok := Get(name); !ok {
+			return fmt.Errorf("unknown index extractor %q (the available extractors are %v)", name, Names())
+		}
+	}
+	return nil
+}
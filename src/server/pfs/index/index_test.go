@@ -0,0 +1,57 @@
+package index
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestRegistry(t *testing.T) {
+	require.Equal(t, []string{"csv", "image"}, Names())
+	require.NoError(t, Validate([]string{"csv", "image"}))
+	require.YesError(t, Validate([]string{"csv", "parquet"}))
+	require.Equal(t, "index extractor \"csv\" is registered twice", func() (msg interface{}) {
+		defer func() { msg = recover() }()
+		Register("csv", csvExtractor{})
+		return nil
+	}())
+}
+
+func TestCSV(t *testing.T) {
+	e, ok := Get("csv")
+	require.True(t, ok)
+	require.True(t, e.Match("/data/sales.CSV"))
+	require.False(t, e.Match("/data/sales.json"))
+	attributes, err := e.Extract("/sales.csv", strings.NewReader("region,item,\"unit price\"\neu,apples,3\nus,pears\n"))
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"csv.columns":           "region,item,unit price",
+		"csv.column.region":     "0",
+		"csv.column.item":       "1",
+		"csv.column.unit price": "2",
+	}, attributes)
+	_, err = e.Extract("/empty.csv", strings.NewReader(""))
+	require.YesError(t, err)
+}
+
+func TestImage(t *testing.T) {
+	e, ok := Get("image")
+	require.True(t, ok)
+	require.True(t, e.Match("/photos/cat.jpeg"))
+	require.False(t, e.Match("/photos/cat.tiff"))
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, 64, 48))))
+	attributes, err := e.Extract("/cat.png", &buf)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"image.format": "png",
+		"image.width":  "64",
+		"image.height": "48",
+	}, attributes)
+	_, err = e.Extract("/cat.png", strings.NewReader("not an image"))
+	require.YesError(t, err)
+}
@@ -9,15 +9,15 @@ import (
 
 func TestErrorMatching(t *testing.T) {
 	c := client.NewCommit("foo", "bar")
-	require.True(t, IsCommitNotFoundErr(ErrCommitNotFound{c}))
-	require.False(t, IsCommitNotFoundErr(ErrCommitDeleted{c}))
-	require.False(t, IsCommitNotFoundErr(ErrCommitFinished{c}))
+	require.True(t, IsCommitNotFoundErr(ErrCommitNotFound{Commit: c}))
+	require.False(t, IsCommitNotFoundErr(ErrCommitDeleted{Commit: c}))
+	require.False(t, IsCommitNotFoundErr(ErrCommitFinished{Commit: c}))
 
-	require.False(t, IsCommitDeletedErr(ErrCommitNotFound{c}))
-	require.True(t, IsCommitDeletedErr(ErrCommitDeleted{c}))
-	require.False(t, IsCommitDeletedErr(ErrCommitFinished{c}))
+	require.False(t, IsCommitDeletedErr(ErrCommitNotFound{Commit: c}))
+	require.True(t, IsCommitDeletedErr(ErrCommitDeleted{Commit: c}))
+	require.False(t, IsCommitDeletedErr(ErrCommitFinished{Commit: c}))
 
-	require.False(t, IsCommitFinishedErr(ErrCommitNotFound{c}))
-	require.False(t, IsCommitFinishedErr(ErrCommitDeleted{c}))
-	require.True(t, IsCommitFinishedErr(ErrCommitFinished{c}))
+	require.False(t, IsCommitFinishedErr(ErrCommitNotFound{Commit: c}))
+	require.False(t, IsCommitFinishedErr(ErrCommitDeleted{Commit: c}))
+	require.True(t, IsCommitFinishedErr(ErrCommitFinished{Commit: c}))
 }
@@ -5,6 +5,8 @@ import (
 	"html/template"
 	"io"
 	"os"
+	"sort"
+	"strings"
 
 	"github.com/docker/go-units"
 	"github.com/fatih/color"
@@ -27,6 +29,10 @@ const (
 	FileHeaderWithCommit = "COMMIT\tNAME\tTYPE\tCOMMITTED\tSIZE\t\n"
 	// DiffFileHeader is the header for files produced by diff file.
 	DiffFileHeader = "OP\t" + FileHeader
+	// FileChangeHeader is the header for file changes.
+	FileChangeHeader = "COMMIT\tCHANGE\tPATH\t\n"
+	// IndexedFileHeader is the header for files found by search file.
+	IndexedFileHeader = "PATH\tEXTRACTOR\tATTRIBUTES\t\n"
 )
 
 // PrintRepoInfo pretty-prints repo info.
@@ -67,7 +73,10 @@ Description: {{.Description}}{{end}}{{if .FullTimestamps}}
 Created: {{.Created}}{{else}}
 Created: {{prettyAgo .Created}}{{end}}
 Size of HEAD on master: {{prettySize .SizeBytes}}{{if .AuthInfo}}
-Access level: {{ .AuthInfo.AccessLevel.String }}{{end}}
+Access level: {{ .AuthInfo.AccessLevel.String }}{{end}}{{if .IndexExtractors}}
+Index extractors: {{join .IndexExtractors ", "}}{{end}}{{if .StoragePolicy}}
+Storage class: {{ .StoragePolicy.StorageClass.String }}{{end}}{{if .CommitTTL}}
+Commit TTL: {{prettyDuration .CommitTTL}}{{end}}
 `)
 	if err != nil {
 		return err
@@ -95,7 +104,9 @@ func PrintDetailedBranchInfo(branchInfo *pfs.BranchInfo) error {
 	template, err := template.New("BranchInfo").Funcs(funcMap).Parse(
 		`Name: {{.Branch.Repo.Name}}@{{.Branch.Name}}{{if .Head}}
 Head Commit: {{ .Head.Repo.Name}}@{{.Head.ID}} {{end}}{{if .Provenance}}
-Provenance: {{range .Provenance}} {{.Repo.Name}}@{{.Name}} {{end}} {{end}}
+Provenance: {{range .Provenance}} {{.Repo.Name}}@{{.Name}} {{end}} {{end}}{{if .Protection}}{{if .Protection.RequireProvenance}}
+Requires provenance: true{{end}}{{if .Protection.AllowedCommitters}}
+Allowed committers: {{join .Protection.AllowedCommitters ", "}}{{end}}{{end}}
 `)
 	if err != nil {
 		return err
@@ -213,6 +224,38 @@ func PrintDiffFileInfo(w io.Writer, added bool, fileInfo *pfs.FileInfo, fullTime
 	PrintFileInfo(w, fileInfo, fullTimestamps, false)
 }
 
+// PrintFileChangeEvent pretty-prints the file changes of a commit, one per
+// line.
+func PrintFileChangeEvent(w io.Writer, event *pfs.FileChangeEvent) {
+	for _, change := range event.Changes {
+		var changeType string
+		switch change.Type {
+		case pfs.FileChangeType_FILE_CREATED:
+			changeType = color.GreenString("created")
+		case pfs.FileChangeType_FILE_UPDATED:
+			changeType = color.YellowString("updated")
+		case pfs.FileChangeType_FILE_DELETED:
+			changeType = color.RedString("deleted")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t\n", event.Commit.ID, changeType, change.Path)
+	}
+}
+
+// PrintIndexedFile pretty-prints a file found by search file, with its
+// attributes sorted by name.
+func PrintIndexedFile(w io.Writer, file *pfs.IndexedFile) {
+	var names []string
+	for name := range file.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var attributes []string
+	for _, name := range names {
+		attributes = append(attributes, fmt.Sprintf("%s=%s", name, file.Attributes[name]))
+	}
+	fmt.Fprintf(w, "%s\t%s\t%s\t\n", file.Path, file.Extractor, strings.Join(attributes, ", "))
+}
+
 // PrintDetailedFileInfo pretty-prints detailed file info.
 func PrintDetailedFileInfo(fileInfo *pfs.FileInfo) error {
 	template, err := template.New("FileInfo").Funcs(funcMap).Parse(
@@ -220,7 +263,8 @@ func PrintDetailedFileInfo(fileInfo *pfs.FileInfo) error {
 Type: {{fileType .FileType}}
 Size: {{prettySize .SizeBytes}}
 Children: {{range .Children}} {{.}} {{end}}
-`)
+{{range .Checksums}}{{checksumAlgorithm .Algorithm}}: {{printf "%x" .Value}}
+{{end}}`)
 	if err != nil {
 		return err
 	}
@@ -234,10 +278,17 @@ func fileType(fileType pfs.FileType) string {
 	return "dir"
 }
 
+func checksumAlgorithm(algorithm pfs.ChecksumAlgorithm) string {
+	return strings.ToLower(algorithm.String())
+}
+
 var funcMap = template.FuncMap{
-	"prettyAgo":  pretty.Ago,
-	"prettySize": pretty.Size,
-	"fileType":   fileType,
+	"checksumAlgorithm": checksumAlgorithm,
+	"prettyAgo":         pretty.Ago,
+	"prettyDuration":    pretty.Duration,
+	"prettySize":        pretty.Size,
+	"fileType":          fileType,
+	"join":              strings.Join,
 }
 
 // CompactPrintBranch renders 'b' as a compact string, e.g.
@@ -0,0 +1,331 @@
+package s3
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/auth"
+	pfsClient "github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/s2"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// auditFlushInterval is how often audit records are written to the sink
+	auditFlushInterval = time.Second
+	// auditBatchSize is the most audit records that are written at once
+	auditBatchSize = 1000
+	// maxPendingAuditRecords is the most audit records that are held while
+	// the sink is failing. The oldest are dropped after that.
+	maxPendingAuditRecords = 100000
+)
+
+// auditRecord is the record of a mutating request, which the audit log writes
+// as a line of JSON
+type auditRecord struct {
+	Time      time.Time `json:"time"`
+	RequestID string    `json:"request_id"`
+	// User is the pachyderm user that made the request, it's empty if auth
+	// isn't active
+	User      string `json:"user"`
+	Operation string `json:"operation"`
+	Bucket    string `json:"bucket"`
+	Key       string `json:"key,omitempty"`
+	// Bytes is the size of the request body
+	Bytes  int64 `json:"bytes"`
+	Status int   `json:"status"`
+	// Error is the S3 error code of a failed request
+	Error string `json:"error,omitempty"`
+}
+
+// auditSink is where audit records are written
+type auditSink interface {
+	write(records []*auditRecord) error
+}
+
+// auditLog writes the records of the gateway's mutating requests to a sink,
+// in batches. Records wait at most auditFlushInterval to be written, and
+// they're retried while the sink is failing.
+type auditLog struct {
+	sink    auditSink
+	logger  *logrus.Entry
+	records chan *auditRecord
+}
+
+// newAuditLog returns an audit log that writes to the sink in 'spec': an
+// http(s) URL that batches of records are POSTed to as JSON lines, or the name
+// of a PFS repo that they're appended to. It returns nil if 'spec' is empty.
+func newAuditLog(c *controller, spec string, logger *logrus.Entry) *auditLog {
+	if spec == "" {
+		return nil
+	}
+	var sink auditSink
+	if strings.HasPrefix(spec, "http://") || strings.HasPrefix(spec, "https://") {
+		sink = &httpAuditSink{url: spec, client: &http.Client{Timeout: 30 * time.Second}}
+	} else {
+		sink = &pfsAuditSink{c: c, repo: spec}
+	}
+	a := &auditLog{
+		sink:    sink,
+		logger:  logger,
+		records: make(chan *auditRecord, auditBatchSize),
+	}
+	go a.run()
+	return a
+}
+
+func (a *auditLog) add(record *auditRecord) {
+	a.records <- record
+}
+
+func (a *auditLog) run() {
+	ticker := time.NewTicker(auditFlushInterval)
+	defer ticker.Stop()
+	var pending []*auditRecord
+	for {
+		select {
+		case record := <-a.records:
+			pending = append(pending, record)
+			if len(pending) < auditBatchSize {
+				continue
+			}
+		case <-ticker.C:
+		}
+		pending = a.flush(pending)
+	}
+}
+
+// flush writes 'pending' to the sink, and returns the records that weren't
+// written
+func (a *auditLog) flush(pending []*auditRecord) []*auditRecord {
+	for len(pending) > 0 {
+		n := len(pending)
+		if n > auditBatchSize {
+			n = auditBatchSize
+		}
+		if err := a.sink.write(pending[:n]); err != nil {
+			a.logger.Errorf("could not write %d audit records: %v", len(pending), err)
+			if dropped := len(pending) - maxPendingAuditRecords; dropped > 0 {
+				a.logger.Errorf("dropping the %d oldest audit records", dropped)
+				pending = pending[dropped:]
+			}
+			return pending
+		}
+		pending = pending[n:]
+	}
+	return nil
+}
+
+// pfsAuditSink appends audit records to a file per day (e.g.
+// /2020-01-02.jsonl) in the master branch of a PFS repo, which it creates if
+// it doesn't exist
+type pfsAuditSink struct {
+	c    *controller
+	repo string
+	pc   *client.APIClient // created by the first write
+}
+
+func (s *pfsAuditSink) write(records []*auditRecord) error {
+	buf := &bytes.Buffer{}
+	if err := encodeAuditRecords(buf, records); err != nil {
+		return err
+	}
+	if s.pc == nil {
+		pc, err := s.c.pachClient("")
+		if err != nil {
+			return err
+		}
+		s.pc = pc
+	}
+	pc := s.pc
+	file := path.Join("/", records[0].Time.UTC().Format("2006-01-02")+".jsonl")
+	if _, err := pc.PutFile(s.repo, "master", file, buf); err != nil {
+		if !pfsClient.IsRepoNotFoundErr(err) {
+			return err
+		}
+		if err := pc.CreateRepo(s.repo); err != nil {
+			return err
+		}
+		_, err = pc.PutFile(s.repo, "master", file, bytes.NewReader(buf.Bytes()))
+		return err
+	}
+	return nil
+}
+
+// httpAuditSink POSTs batches of audit records to a URL, as JSON lines
+type httpAuditSink struct {
+	url    string
+	client *http.Client
+}
+
+func (s *httpAuditSink) write(records []*auditRecord) error {
+	buf := &bytes.Buffer{}
+	if err := encodeAuditRecords(buf, records); err != nil {
+		return err
+	}
+	resp, err := s.client.Post(s.url, "application/x-ndjson", buf)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s responded with %s", s.url, resp.Status)
+	}
+	return nil
+}
+
+func encodeAuditRecords(w io.Writer, records []*auditRecord) error {
+	encoder := json.NewEncoder(w)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bucketSubresources are the bucket configurations (query parameters) that
+// can be written, and the name that they have in the operations that write
+// them, e.g. PutBucketCors
+var bucketSubresources = map[string]string{
+	"cors":      "Cors",
+	"lifecycle": "Lifecycle",
+	"policy":    "Policy",
+}
+
+// auditOperation returns the S3 operation of a mutating request, or false if
+// the request doesn't mutate anything
+func auditOperation(r *http.Request, bucket, key string) (string, bool) {
+	query := r.URL.Query()
+	_, uploadID := query["uploadId"]
+	if bucket == "" {
+		return "", false
+	}
+	if key == "" {
+		var subresource string
+		for name, operation := range bucketSubresources {
+			if _, ok := query[name]; ok {
+				subresource = operation
+			}
+		}
+		switch r.Method {
+		case "PUT":
+			if subresource == "" {
+				return "CreateBucket", true
+			}
+			return "PutBucket" + subresource, true
+		case "DELETE":
+			return "DeleteBucket" + subresource, true
+		case "POST":
+			if _, ok := query["delete"]; ok {
+				return "DeleteObjects", true
+			}
+		}
+		return "", false
+	}
+	switch r.Method {
+	case "PUT":
+		switch {
+		case uploadID:
+			return "UploadPart", true
+		case r.Header.Get("x-amz-copy-source") != "":
+			return "CopyObject", true
+		}
+		return "PutObject", true
+	case "DELETE":
+		if uploadID {
+			return "AbortMultipartUpload", true
+		}
+		return "DeleteObject", true
+	case "POST":
+		if uploadID {
+			return "CompleteMultipartUpload", true
+		}
+	}
+	return "", false
+}
+
+// auditResponseWriter keeps the status and error document of a response
+type auditResponseWriter struct {
+	http.ResponseWriter
+	status  int
+	errBody bytes.Buffer
+}
+
+func (w *auditResponseWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *auditResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if w.status >= 400 && w.errBody.Len() < maxLoggedErrorSize {
+		w.errBody.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// auditMiddleware adds a record of each mutating request to the audit log
+func (c *controller) auditMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		operation, ok := auditOperation(r, vars["bucket"], vars["key"])
+		if c.audit == nil || !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		// Writes are recorded against the bucket that they land in
+		bucket, err := writeBucket(r, vars["bucket"])
+		if err != nil {
+			bucket = vars["bucket"]
+		}
+		record := &auditRecord{
+			Time:      time.Now(),
+			RequestID: requestID(r),
+			Operation: operation,
+			Bucket:    bucket,
+			Key:       vars["key"],
+		}
+		body := &countingReader{r: r.Body}
+		r.Body = struct {
+			io.Reader
+			io.Closer
+		}{body, r.Body}
+		aw := &auditResponseWriter{ResponseWriter: w}
+		next.ServeHTTP(aw, r)
+
+		record.Bytes = body.n
+		record.Status = aw.status
+		if record.Status == 0 {
+			record.Status = http.StatusOK
+		}
+		if record.Status >= 400 {
+			s3Err := &s2.Error{}
+			if err := xml.Unmarshal(aw.errBody.Bytes(), s3Err); err == nil {
+				record.Error = s3Err.Code
+			}
+		}
+		// s2 sets the access key (an auth token) when it authenticates the
+		// request, which is resolved to its user
+		if accessKey := vars["authAccessKey"]; accessKey != "" {
+			if pc, err := c.pachClient(accessKey); err == nil {
+				if resp, err := pc.WhoAmI(pc.Ctx(), &auth.WhoAmIRequest{}); err == nil {
+					record.User = resp.Username
+				}
+				pc.Close()
+			}
+		}
+		c.audit.add(record)
+	})
+}
@@ -0,0 +1,127 @@
+package s3
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/s2"
+	"github.com/sirupsen/logrus"
+)
+
+type testAuditSink struct {
+	err     error
+	written []*auditRecord
+}
+
+func (s *testAuditSink) write(records []*auditRecord) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.written = append(s.written, records...)
+	return nil
+}
+
+func TestAuditOperation(t *testing.T) {
+	operation := func(method, url string, header ...string) string {
+		r := httptest.NewRequest(method, url, nil)
+		for i := 0; i+1 < len(header); i += 2 {
+			r.Header.Set(header[i], header[i+1])
+		}
+		vars := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 2)
+		var bucket, key string
+		bucket = vars[0]
+		if len(vars) > 1 {
+			key = vars[1]
+		}
+		op, ok := auditOperation(r, bucket, key)
+		if !ok {
+			return ""
+		}
+		return op
+	}
+	require.Equal(t, "", operation("GET", "/"))
+	require.Equal(t, "", operation("GET", "/master.images"))
+	require.Equal(t, "", operation("GET", "/master.images/foo"))
+	require.Equal(t, "", operation("HEAD", "/master.images/foo"))
+	require.Equal(t, "", operation("POST", "/master.images/foo?uploads"))
+	require.Equal(t, "CreateBucket", operation("PUT", "/master.images"))
+	require.Equal(t, "PutBucketCors", operation("PUT", "/master.images?cors"))
+	require.Equal(t, "PutBucketPolicy", operation("PUT", "/master.images?policy"))
+	require.Equal(t, "DeleteBucket", operation("DELETE", "/master.images"))
+	require.Equal(t, "DeleteBucketLifecycle", operation("DELETE", "/master.images?lifecycle"))
+	require.Equal(t, "DeleteObjects", operation("POST", "/master.images?delete"))
+	require.Equal(t, "PutObject", operation("PUT", "/master.images/foo"))
+	require.Equal(t, "CopyObject", operation("PUT", "/master.images/foo", "x-amz-copy-source", "/master.other/bar"))
+	require.Equal(t, "UploadPart", operation("PUT", "/master.images/foo?partNumber=1&uploadId=abc"))
+	require.Equal(t, "DeleteObject", operation("DELETE", "/master.images/foo"))
+	require.Equal(t, "AbortMultipartUpload", operation("DELETE", "/master.images/foo?uploadId=abc"))
+	require.Equal(t, "CompleteMultipartUpload", operation("POST", "/master.images/foo?uploadId=abc"))
+}
+
+func TestAuditLogFlush(t *testing.T) {
+	sink := &testAuditSink{err: errors.New("unavailable")}
+	a := &auditLog{sink: sink, logger: logrus.NewEntry(logrus.New())}
+	pending := make([]*auditRecord, maxPendingAuditRecords+10)
+	for i := range pending {
+		pending[i] = &auditRecord{Key: fmt.Sprint(i)}
+	}
+
+	// Records are kept while the sink is failing, up to a limit
+	left := a.flush(pending[:10])
+	require.Equal(t, 10, len(left))
+	left = a.flush(pending)
+	require.Equal(t, maxPendingAuditRecords, len(left))
+	require.Equal(t, pending[10], left[0])
+
+	// And are written, in batches, once it recovers
+	sink.err = nil
+	require.Equal(t, 0, len(a.flush(left)))
+	require.Equal(t, maxPendingAuditRecords, len(sink.written))
+	require.Equal(t, pending[10], sink.written[0])
+}
+
+func TestAuditMiddleware(t *testing.T) {
+	a := &auditLog{records: make(chan *auditRecord, 10)}
+	c := &controller{logger: logrus.NewEntry(logrus.New()), audit: a}
+	handler := c.auditMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		if string(body) == "forbidden" {
+			s2.WriteError(c.logger, w, r, s2.AccessDeniedError(r))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func(method, url, body string) {
+		r := withRequestID(httptest.NewRequest(method, url, strings.NewReader(body)), "abc123")
+		vars := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 2)
+		r = mux.SetURLVars(r, map[string]string{"bucket": vars[0], "key": vars[1]})
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	serve("GET", "/master.images/foo", "")
+	serve("PUT", "/master.images/foo", "foobar")
+	serve("PUT", "/master.images/bar", "forbidden")
+	require.Equal(t, 2, len(a.records))
+
+	record := <-a.records
+	require.Equal(t, "abc123", record.RequestID)
+	require.Equal(t, "PutObject", record.Operation)
+	require.Equal(t, "master.images", record.Bucket)
+	require.Equal(t, "foo", record.Key)
+	require.Equal(t, int64(6), record.Bytes)
+	require.Equal(t, http.StatusOK, record.Status)
+	require.Equal(t, "", record.Error)
+
+	record = <-a.records
+	require.Equal(t, "bar", record.Key)
+	require.Equal(t, http.StatusForbidden, record.Status)
+	require.Equal(t, "AccessDenied", record.Error)
+}
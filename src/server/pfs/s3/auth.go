@@ -8,6 +8,9 @@ import (
 )
 
 func (c *controller) SecretKey(r *http.Request, accessKey string, region *string) (*string, error) {
+	// auth runs before the router's other middleware, so that auth errors
+	// carry the gateway's request ID
+	useRequestID(r)
 	pc, err := c.pachClient(accessKey)
 	if err != nil {
 		return nil, fmt.Errorf("could not create a pach client for auth: %s", err)
@@ -35,6 +38,7 @@ func (c *controller) SecretKey(r *http.Request, accessKey string, region *string
 }
 
 func (c *controller) CustomAuth(r *http.Request) (bool, error) {
+	useRequestID(r)
 	pc, err := c.pachClient("")
 	if err != nil {
 		return false, fmt.Errorf("could not create a pach client for auth: %s", err)
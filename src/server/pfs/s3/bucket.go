@@ -7,9 +7,8 @@ import (
 
 	"github.com/gogo/protobuf/types"
 	"github.com/gorilla/mux"
-	glob "github.com/pachyderm/ohmyglob"
+	"github.com/pachyderm/pachyderm/src/client"
 	pfsClient "github.com/pachyderm/pachyderm/src/client/pfs"
-	pfsServer "github.com/pachyderm/pachyderm/src/server/pfs"
 	"github.com/pachyderm/pachyderm/src/server/pkg/ancestry"
 	"github.com/pachyderm/pachyderm/src/server/pkg/errutil"
 	"github.com/pachyderm/s2"
@@ -58,6 +57,9 @@ func (c controller) GetLocation(r *http.Request, bucket string) (string, error)
 }
 
 func (c controller) ListObjects(r *http.Request, bucket, prefix, marker, delimiter string, maxKeys int) (*s2.ListObjectsResult, error) {
+	if err := c.canRead(r, bucket); err != nil {
+		return nil, err
+	}
 	vars := mux.Vars(r)
 	pc, err := c.pachClient(vars["authAccessKey"])
 	if err != nil {
@@ -87,38 +89,29 @@ func (c controller) ListObjects(r *http.Request, bucket, prefix, marker, delimit
 		return &result, nil
 	}
 
+	// Page through the directory containing 'prefix' on the server, rather
+	// than globbing for every file under it. One more file than fits in the
+	// result is listed, to tell whether it's truncated.
 	recursive := delimiter == ""
-	var pattern string
-	if recursive {
-		pattern = fmt.Sprintf("%s**", glob.QuoteMeta(prefix))
-	} else {
-		pattern = fmt.Sprintf("%s*", glob.QuoteMeta(prefix))
+	dir := ""
+	if i := strings.LastIndex(prefix, "/"); i >= 0 {
+		dir = prefix[:i]
 	}
-
-	err = pc.GlobFileF(repo, branch, pattern, func(fileInfo *pfsClient.FileInfo) error {
-		if fileInfo.FileType == pfsClient.FileType_DIR {
-			if fileInfo.File.Path == "/" {
-				// skip the root directory
-				return nil
-			}
-			if recursive {
-				// skip directories if recursing
-				return nil
-			}
-		} else if fileInfo.FileType != pfsClient.FileType_FILE {
+	err = pc.ListFilePageF(&pfsClient.ListFileRequest{
+		File:       client.NewFile(repo, branch, dir),
+		SizeOnly:   true,
+		Prefix:     prefix,
+		StartAfter: marker,
+		Limit:      int64(maxKeys) + 1,
+		Recursive:  recursive,
+	}, func(fileInfo *pfsClient.FileInfo) error {
+		if fileInfo.FileType != pfsClient.FileType_FILE && fileInfo.FileType != pfsClient.FileType_DIR {
 			// skip anything that isn't a file or dir
 			return nil
 		}
 
 		fileInfo.File.Path = fileInfo.File.Path[1:] // strip leading slash
 
-		if !strings.HasPrefix(fileInfo.File.Path, prefix) {
-			return nil
-		}
-		if fileInfo.File.Path <= marker {
-			return nil
-		}
-
 		if len(result.Contents)+len(result.CommonPrefixes) >= maxKeys {
 			if maxKeys > 0 {
 				result.IsTruncated = true
@@ -143,6 +136,9 @@ func (c controller) ListObjects(r *http.Request, bucket, prefix, marker, delimit
 }
 
 func (c controller) CreateBucket(r *http.Request, bucket string) error {
+	if err := c.canWrite(r, bucket); err != nil {
+		return err
+	}
 	vars := mux.Vars(r)
 	pc, err := c.pachClient(vars["authAccessKey"])
 	if err != nil {
@@ -161,7 +157,7 @@ func (c controller) CreateBucket(r *http.Request, bucket string) error {
 			// since PFS' `CreateBranch` won't error out.
 			_, err := pc.InspectBranch(repo, branch)
 			if err != nil {
-				if !pfsServer.IsBranchNotFoundErr(err) {
+				if !pfsClient.IsBranchNotFoundErr(err) {
 					return s2.InternalError(r, err)
 				}
 			} else {
@@ -186,6 +182,9 @@ func (c controller) CreateBucket(r *http.Request, bucket string) error {
 }
 
 func (c controller) DeleteBucket(r *http.Request, bucket string) error {
+	if err := c.canWrite(r, bucket); err != nil {
+		return err
+	}
 	vars := mux.Vars(r)
 	pc, err := c.pachClient(vars["authAccessKey"])
 	if err != nil {
@@ -206,12 +205,13 @@ func (c controller) DeleteBucket(r *http.Request, bucket string) error {
 
 	if branchInfo.Head != nil {
 		hasFiles := false
-		err = pc.Walk(branchInfo.Branch.Repo.Name, branchInfo.Head.ID, "", func(fileInfo *pfsClient.FileInfo) error {
-			if fileInfo.FileType == pfsClient.FileType_FILE {
-				hasFiles = true
-				return errutil.ErrBreak
-			}
-			return nil
+		err = pc.WalkFileF(&pfsClient.WalkFileRequest{
+			File:     client.NewFile(branchInfo.Branch.Repo.Name, branchInfo.Head.ID, ""),
+			FileType: pfsClient.FileType_FILE,
+			SizeOnly: true,
+		}, func(fileInfo *pfsClient.FileInfo) error {
+			hasFiles = true
+			return errutil.ErrBreak
 		})
 		if err != nil {
 			return s2.InternalError(r, err)
@@ -226,6 +226,7 @@ func (c controller) DeleteBucket(r *http.Request, bucket string) error {
 	if err != nil {
 		return s2.InternalError(r, err)
 	}
+	c.metadata.invalidate(repo, branch)
 
 	repoInfo, err := pc.InspectRepo(repo)
 	if err != nil {
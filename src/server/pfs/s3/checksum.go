@@ -0,0 +1,366 @@
+package s3
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pachyderm/s2"
+)
+
+// Values of x-amz-content-sha256 for uploads with aws-chunked bodies, whose
+// chunks are signed (unless the payload is unsigned), and which may be
+// followed by trailing headers carrying checksums of the content
+const (
+	streamingPayload         = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD"
+	streamingPayloadTrailer  = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD-TRAILER"
+	streamingUnsignedTrailer = "STREAMING-UNSIGNED-PAYLOAD-TRAILER"
+)
+
+// emptySHA256 is the hex-encoded SHA256 hash of no content
+const emptySHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+var (
+	errMalformedChunk = errors.New("malformed aws-chunked body")
+	errChunkSignature = errors.New("chunk signature does not match")
+	errBadDigest      = errors.New("checksum does not match")
+	errInvalidDigest  = errors.New("checksum is not valid")
+
+	// authV4Credential parses the credential and seed signature of an AWS
+	// auth V4 authorization header
+	authV4Credential = regexp.MustCompile(`^AWS4-HMAC-SHA256 Credential=([^/]*)/([^/]*)/([^/]*)/s3/aws4_request, SignedHeaders=[^,]+, Signature=([0-9a-f]{64})$`)
+)
+
+// newChecksumHash returns the hash function of the checksum header (or
+// trailer) 'name', or nil if it isn't a checksum header. The values of all
+// of these headers are the base64-encoded checksum.
+func newChecksumHash(name string) hash.Hash {
+	switch strings.ToLower(name) {
+	case "content-md5":
+		return md5.New()
+	case "x-amz-checksum-crc32":
+		return crc32.NewIEEE()
+	case "x-amz-checksum-crc32c":
+		return crc32.New(crc32.MakeTable(crc32.Castagnoli))
+	case "x-amz-checksum-sha1":
+		return sha1.New()
+	case "x-amz-checksum-sha256":
+		return sha256.New()
+	}
+	return nil
+}
+
+// verifyChecksums checks 'content' against the checksums in 'headers' (the
+// headers or trailers of a request)
+func verifyChecksums(headers http.Header, content []byte) error {
+	for name, values := range headers {
+		h := newChecksumHash(name)
+		if h == nil {
+			continue
+		}
+		if len(values) != 1 {
+			return errInvalidDigest
+		}
+		expected, err := base64.StdEncoding.DecodeString(values[0])
+		if err != nil || len(expected) != h.Size() {
+			return errInvalidDigest
+		}
+		h.Write(content)
+		if !bytes.Equal(expected, h.Sum(nil)) {
+			return errBadDigest
+		}
+	}
+	return nil
+}
+
+// chunkSigner computes the signatures of the chunks of an aws-chunked body,
+// each of which signs the chunk and the previous chunk's signature
+type chunkSigner struct {
+	signingKey []byte
+	timestamp  string
+	scope      string
+	// prevSignature is the signature of the previous chunk (or the seed
+	// signature, from the request's authorization header)
+	prevSignature string
+}
+
+func newChunkSigner(secretKey, date, region, timestamp, seedSignature string) *chunkSigner {
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return &chunkSigner{
+		signingKey:    key,
+		timestamp:     timestamp,
+		scope:         fmt.Sprintf("%s/%s/s3/aws4_request", date, region),
+		prevSignature: seedSignature,
+	}
+}
+
+// verify checks that 'signature' is the signature of a chunk or of the
+// trailers, given the rest of the string to sign
+func (s *chunkSigner) verify(algorithm string, signature string, hashes ...string) bool {
+	stringToSign := strings.Join(append([]string{algorithm, s.timestamp, s.scope, s.prevSignature}, hashes...), "\n")
+	expected := hex.EncodeToString(hmacSHA256(s.signingKey, stringToSign))
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return false
+	}
+	s.prevSignature = signature
+	return true
+}
+
+func (s *chunkSigner) verifyChunk(signature string, chunk []byte) bool {
+	h := sha256.Sum256(chunk)
+	return s.verify("AWS4-HMAC-SHA256-PAYLOAD", signature, emptySHA256, hex.EncodeToString(h[:]))
+}
+
+func (s *chunkSigner) verifyTrailers(signature string, trailers []byte) bool {
+	h := sha256.Sum256(trailers)
+	return s.verify("AWS4-HMAC-SHA256-TRAILER", signature, hex.EncodeToString(h[:]))
+}
+
+func hmacSHA256(key []byte, content string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(content))
+	return mac.Sum(nil)
+}
+
+// readCRLFLine reads a line ending in "\r\n" from 'r', and returns it without
+// the line ending
+func readCRLFLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		if err == io.EOF && line == "" {
+			return "", io.EOF
+		}
+		return "", errMalformedChunk
+	}
+	if !strings.HasSuffix(line, "\r\n") {
+		return "", errMalformedChunk
+	}
+	return strings.TrimSuffix(line, "\r\n"), nil
+}
+
+// decodeAWSChunked decodes the aws-chunked body in 'body', and returns its
+// content and trailers (if 'withTrailers' is set). If 'signer' is non-nil,
+// the chunks (and trailers) must be signed.
+func decodeAWSChunked(body io.Reader, signer *chunkSigner, withTrailers bool) ([]byte, http.Header, error) {
+	r := bufio.NewReader(body)
+	var content bytes.Buffer
+	for {
+		line, err := readCRLFLine(r)
+		if err != nil {
+			return nil, nil, errMalformedChunk
+		}
+		sizeStr, signature := line, ""
+		if i := strings.IndexByte(line, ';'); i >= 0 {
+			sizeStr = line[:i]
+			if !strings.HasPrefix(line[i+1:], "chunk-signature=") {
+				return nil, nil, errMalformedChunk
+			}
+			signature = strings.TrimPrefix(line[i+1:], "chunk-signature=")
+		}
+		size, err := strconv.ParseInt(sizeStr, 16, 64)
+		if err != nil || size < 0 || size > maxRequestBodyLength {
+			return nil, nil, errMalformedChunk
+		}
+		chunk := make([]byte, size)
+		if _, err := io.ReadFull(r, chunk); err != nil {
+			return nil, nil, errMalformedChunk
+		}
+		if signer != nil && !signer.verifyChunk(signature, chunk) {
+			return nil, nil, errChunkSignature
+		}
+		content.Write(chunk)
+		if size == 0 {
+			break
+		}
+		if line, err := readCRLFLine(r); err != nil || line != "" {
+			return nil, nil, errMalformedChunk
+		}
+	}
+	trailers := make(http.Header)
+	var signedTrailers bytes.Buffer
+	var trailerSignature string
+	for {
+		line, err := readCRLFLine(r)
+		if err == io.EOF || (err == nil && line == "") {
+			break
+		} else if err != nil {
+			return nil, nil, err
+		}
+		if !withTrailers {
+			return nil, nil, errMalformedChunk
+		}
+		i := strings.IndexByte(line, ':')
+		if i < 0 {
+			return nil, nil, errMalformedChunk
+		}
+		name, value := strings.ToLower(strings.TrimSpace(line[:i])), strings.TrimSpace(line[i+1:])
+		if name == "x-amz-trailer-signature" {
+			trailerSignature = value
+			continue
+		}
+		trailers.Add(name, value)
+		fmt.Fprintf(&signedTrailers, "%s:%s\n", name, value)
+	}
+	if signer != nil && withTrailers && !signer.verifyTrailers(trailerSignature, signedTrailers.Bytes()) {
+		return nil, nil, errChunkSignature
+	}
+	return content.Bytes(), trailers, nil
+}
+
+// uploadNeedsVerifying returns true if the body of 'r' must be checked (or
+// decoded) before it's served. s2 checks Content-MD5 headers itself.
+func uploadNeedsVerifying(r *http.Request) bool {
+	if r.Method != http.MethodPut {
+		return false
+	}
+	payloadHash := r.Header.Get("X-Amz-Content-Sha256")
+	if strings.HasPrefix(payloadHash, "STREAMING-") || len(payloadHash) == sha256.Size*2 {
+		return true
+	}
+	for name := range r.Header {
+		if strings.HasPrefix(strings.ToLower(name), "x-amz-checksum-") {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyUpload checks the body of 'r', if it's an upload, against the
+// checksums and signatures that the client sent, and decodes it if it's
+// aws-chunked. This happens before the request is served, so that the writes
+// of corrupted content are rejected before anything is written to PFS. It
+// returns the request to serve, or false if it has written an error response.
+func (c *controller) verifyUpload(w http.ResponseWriter, r *http.Request) (*http.Request, bool) {
+	if !uploadNeedsVerifying(r) {
+		return r, true
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxRequestBodyLength+1))
+	r.Body.Close()
+	if err != nil {
+		c.writeError(w, r, s2.IncompleteBodyError(r))
+		return nil, false
+	}
+	if len(body) > maxRequestBodyLength {
+		c.writeError(w, r, s2.EntityTooLargeError(r))
+		return nil, false
+	}
+	content := body
+	payloadHash := r.Header.Get("X-Amz-Content-Sha256")
+	switch payloadHash {
+	case streamingPayload, streamingPayloadTrailer, streamingUnsignedTrailer:
+		var signer *chunkSigner
+		if payloadHash != streamingUnsignedTrailer {
+			if signer, err = c.newRequestChunkSigner(r); err != nil {
+				c.writeError(w, r, err)
+				return nil, false
+			}
+		}
+		var trailers http.Header
+		content, trailers, err = decodeAWSChunked(bytes.NewReader(body), signer, payloadHash != streamingPayload)
+		if err != nil {
+			c.writeError(w, r, uploadError(r, err))
+			return nil, false
+		}
+		if decodedLength := r.Header.Get("X-Amz-Decoded-Content-Length"); decodedLength != "" && decodedLength != strconv.Itoa(len(content)) {
+			c.writeError(w, r, s2.IncompleteBodyError(r))
+			return nil, false
+		}
+		// Every trailer that the client announced must be sent
+		for _, name := range strings.Split(r.Header.Get("X-Amz-Trailer"), ",") {
+			if name = strings.TrimSpace(name); name != "" && trailers.Get(name) == "" {
+				c.writeError(w, r, s2.IncompleteBodyError(r))
+				return nil, false
+			}
+		}
+		if err := verifyChecksums(trailers, content); err != nil {
+			c.writeError(w, r, uploadError(r, err))
+			return nil, false
+		}
+		// The rest of the request is served as if it had been sent without
+		// aws-chunked
+		var encodings []string
+		for _, encoding := range strings.Split(r.Header.Get("Content-Encoding"), ",") {
+			if encoding = strings.TrimSpace(encoding); encoding != "" && encoding != "aws-chunked" {
+				encodings = append(encodings, encoding)
+			}
+		}
+		if len(encodings) > 0 {
+			r.Header.Set("Content-Encoding", strings.Join(encodings, ","))
+		} else {
+			r.Header.Del("Content-Encoding")
+		}
+	default:
+		if len(payloadHash) == sha256.Size*2 {
+			actual := sha256.Sum256(content)
+			if hex.EncodeToString(actual[:]) != strings.ToLower(payloadHash) {
+				c.writeError(w, r, s2.BadDigestError(r))
+				return nil, false
+			}
+		}
+	}
+	if err := verifyChecksums(r.Header, content); err != nil {
+		c.writeError(w, r, uploadError(r, err))
+		return nil, false
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(content))
+	r.ContentLength = int64(len(content))
+	r.Header.Set("Content-Length", strconv.Itoa(len(content)))
+	return r, true
+}
+
+// newRequestChunkSigner returns a chunkSigner for the chunks of 'r', which
+// are signed with the secret key of its access key. The seed signature itself
+// is verified by s2's auth, which checks the request's authorization header.
+func (c *controller) newRequestChunkSigner(r *http.Request) (*chunkSigner, error) {
+	match := authV4Credential.FindStringSubmatch(r.Header.Get("Authorization"))
+	if match == nil {
+		return nil, s2.AuthorizationHeaderMalformedError(r)
+	}
+	accessKey, date, region, seedSignature := match[1], match[2], match[3], match[4]
+	timestamp := r.Header.Get("X-Amz-Date")
+	if timestamp == "" {
+		return nil, s2.AccessDeniedError(r)
+	}
+	secretKey, err := c.SecretKey(r, accessKey, &region)
+	if err != nil {
+		return nil, s2.InternalError(r, err)
+	}
+	if secretKey == nil {
+		return nil, s2.InvalidAccessKeyIDError(r)
+	}
+	return newChunkSigner(*secretKey, date, region, timestamp, seedSignature), nil
+}
+
+// uploadError converts the errors of decodeAWSChunked and verifyChecksums to
+// S3 errors
+func uploadError(r *http.Request, err error) error {
+	switch err {
+	case errChunkSignature:
+		return s2.SignatureDoesNotMatchError(r)
+	case errBadDigest:
+		return s2.BadDigestError(r)
+	case errInvalidDigest:
+		return s2.InvalidDigestError(r)
+	case errMalformedChunk:
+		return s2.IncompleteBodyError(r)
+	}
+	return s2.InternalError(r, err)
+}
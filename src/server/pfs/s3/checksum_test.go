@@ -0,0 +1,168 @@
+package s3
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash/crc32"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+// The example of a signed aws-chunked upload from the S3 docs ("Signature
+// Calculations for the Authorization Header: Transferring Payload in Multiple
+// Chunks")
+const (
+	exampleSecretKey     = "wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY"
+	exampleSeedSignature = "4f232c4386841ef735655705268965c44a0e4690baa4adea153f7db9fa80a0a9"
+)
+
+var exampleChunkSignatures = []string{
+	"ad80c730a21e5b8d04586a2213dd63b9a0e99e0e2307b0ade35a65485a288648",
+	"0055627c9e194cb4542bae2aa5492e3c1575bbb81b612b7d234b86a503ef5497",
+	"b6c6ea8a5354eaf15b3cb7646744f4275b71ea724fed81ceb9323e279d449df9",
+}
+
+func exampleSigner() *chunkSigner {
+	return newChunkSigner(exampleSecretKey, "20130524", "us-east-1", "20130524T000000Z", exampleSeedSignature)
+}
+
+// exampleBody returns the example's aws-chunked body, with 'trailer' after the
+// final chunk
+func exampleBody(signatures []string, trailer string) string {
+	var body strings.Builder
+	for i, size := range []int{65536, 1024, 0} {
+		fmt.Fprintf(&body, "%x;chunk-signature=%s\r\n%s\r\n", size, signatures[i], strings.Repeat("a", size))
+	}
+	if trailer == "" {
+		return body.String()
+	}
+	return strings.TrimSuffix(body.String(), "\r\n") + trailer
+}
+
+func TestDecodeAWSChunked(t *testing.T) {
+	content, _, err := decodeAWSChunked(strings.NewReader(exampleBody(exampleChunkSignatures, "")), exampleSigner(), false)
+	require.NoError(t, err)
+	require.Equal(t, strings.Repeat("a", 66560), string(content))
+
+	// A chunk whose signature doesn't match (here, because the signatures
+	// are out of order) is rejected
+	badSignatures := []string{exampleChunkSignatures[1], exampleChunkSignatures[0], exampleChunkSignatures[2]}
+	_, _, err = decodeAWSChunked(strings.NewReader(exampleBody(badSignatures, "")), exampleSigner(), false)
+	require.Equal(t, errChunkSignature, err)
+	// ...as is changed content
+	tampered := strings.Replace(exampleBody(exampleChunkSignatures, ""), "aaaa", "aaab", 1)
+	_, _, err = decodeAWSChunked(strings.NewReader(tampered), exampleSigner(), false)
+	require.Equal(t, errChunkSignature, err)
+	// ...and a truncated body
+	truncated := exampleBody(exampleChunkSignatures, "")[:1000]
+	_, _, err = decodeAWSChunked(strings.NewReader(truncated), exampleSigner(), false)
+	require.Equal(t, errMalformedChunk, err)
+}
+
+func TestDecodeAWSChunkedTrailers(t *testing.T) {
+	// Unsigned chunks, with a checksum trailer
+	sum := md5.Sum([]byte("foobar"))
+	checksum := base64.StdEncoding.EncodeToString(sum[:])
+	body := "3\r\nfoo\r\n3\r\nbar\r\n0\r\nContent-MD5:" + checksum + "\r\n\r\n"
+	content, trailers, err := decodeAWSChunked(strings.NewReader(body), nil, true)
+	require.NoError(t, err)
+	require.Equal(t, "foobar", string(content))
+	require.Equal(t, checksum, trailers.Get("Content-MD5"))
+	require.NoError(t, verifyChecksums(trailers, content))
+	require.Equal(t, errBadDigest, verifyChecksums(trailers, []byte("foobaz")))
+
+	// Trailers aren't allowed unless the payload has them
+	_, _, err = decodeAWSChunked(strings.NewReader(body), nil, false)
+	require.Equal(t, errMalformedChunk, err)
+
+	// Signed trailers: the trailer signature chains from the final chunk's
+	// signature
+	sum32 := make([]byte, 4)
+	binary.BigEndian.PutUint32(sum32, crc32.ChecksumIEEE([]byte(strings.Repeat("a", 66560))))
+	checksum = base64.StdEncoding.EncodeToString(sum32)
+	signer := exampleSigner()
+	signer.prevSignature = exampleChunkSignatures[len(exampleChunkSignatures)-1]
+	sig := trailerSignature(signer, "x-amz-checksum-crc32:"+checksum+"\n")
+	trailer := "x-amz-checksum-crc32:" + checksum + "\r\n"
+	signed := exampleBody(exampleChunkSignatures, trailer+"x-amz-trailer-signature:"+sig+"\r\n\r\n")
+	content, trailers, err = decodeAWSChunked(strings.NewReader(signed), exampleSigner(), true)
+	require.NoError(t, err)
+	require.NoError(t, verifyChecksums(trailers, content))
+	// Unsigned trailers are rejected
+	unsigned := exampleBody(exampleChunkSignatures, trailer+"\r\n")
+	_, _, err = decodeAWSChunked(strings.NewReader(unsigned), exampleSigner(), true)
+	require.Equal(t, errChunkSignature, err)
+}
+
+// trailerSignature returns the signature of 'trailers' after the chunks that
+// 'signer' has verified
+func trailerSignature(signer *chunkSigner, trailers string) string {
+	h := sha256.Sum256([]byte(trailers))
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256-TRAILER", signer.timestamp, signer.scope, signer.prevSignature, hex.EncodeToString(h[:])}, "\n")
+	return hex.EncodeToString(hmacSHA256(signer.signingKey, stringToSign))
+}
+
+func TestVerifyChecksums(t *testing.T) {
+	content := []byte("foobar")
+	sum := md5.Sum(content)
+	headers := http.Header{}
+	headers.Set("Content-MD5", base64.StdEncoding.EncodeToString(sum[:]))
+	// the CRC32 (IEEE) of "foobar" is 0x9ef61f95
+	headers.Set("X-Amz-Checksum-Crc32", base64.StdEncoding.EncodeToString([]byte{0x9e, 0xf6, 0x1f, 0x95}))
+	require.NoError(t, verifyChecksums(headers, content))
+	require.Equal(t, errBadDigest, verifyChecksums(headers, bytes.ToUpper(content)))
+	headers.Set("X-Amz-Checksum-Sha256", "not base64")
+	require.Equal(t, errInvalidDigest, verifyChecksums(headers, content))
+}
+
+func TestVerifyUpload(t *testing.T) {
+	c := &controller{}
+	sum := md5.Sum([]byte("foobar"))
+	checksum := base64.StdEncoding.EncodeToString(sum[:])
+	upload := func(body string) (*http.Request, *httptest.ResponseRecorder, bool) {
+		r := httptest.NewRequest("PUT", "/master.images/foo", strings.NewReader(body))
+		r.Header.Set("X-Amz-Content-Sha256", streamingUnsignedTrailer)
+		r.Header.Set("Content-Encoding", "aws-chunked")
+		r.Header.Set("X-Amz-Decoded-Content-Length", "6")
+		r.Header.Set("X-Amz-Trailer", "Content-MD5")
+		w := httptest.NewRecorder()
+		r, ok := c.verifyUpload(w, r)
+		return r, w, ok
+	}
+
+	// The body is decoded before the request is served
+	r, _, ok := upload("6\r\nfoobar\r\n0\r\nContent-MD5:" + checksum + "\r\n\r\n")
+	require.True(t, ok)
+	content, err := ioutil.ReadAll(r.Body)
+	require.NoError(t, err)
+	require.Equal(t, "foobar", string(content))
+	require.Equal(t, "6", r.Header.Get("Content-Length"))
+	require.Equal(t, "", r.Header.Get("Content-Encoding"))
+
+	// Mismatched checksums and missing trailers are rejected
+	_, w, ok := upload("6\r\nfoobaz\r\n0\r\nContent-MD5:" + checksum + "\r\n\r\n")
+	require.False(t, ok)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.True(t, strings.Contains(w.Body.String(), "BadDigest"))
+	_, w, ok = upload("6\r\nfoobar\r\n0\r\n\r\n")
+	require.False(t, ok)
+	require.True(t, strings.Contains(w.Body.String(), "IncompleteBody"))
+
+	// As are non-chunked uploads whose checksum headers don't match
+	r = httptest.NewRequest("PUT", "/master.images/foo", strings.NewReader("foobaz"))
+	r.Header.Set("X-Amz-Checksum-Sha256", base64.StdEncoding.EncodeToString(sha256.New().Sum(nil)))
+	w = httptest.NewRecorder()
+	_, ok = c.verifyUpload(w, r)
+	require.False(t, ok)
+	require.True(t, strings.Contains(w.Body.String(), "BadDigest"))
+}
@@ -0,0 +1,65 @@
+package s3
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/s2"
+)
+
+const (
+	// readAfterWriteHeader, if "true", makes a write wait for its commit to
+	// finish before it's responded to, as writes to buckets in
+	// controller.readAfterWrite do
+	readAfterWriteHeader = "x-pachyderm-read-after-write"
+	// commitWaitTimeout is how long a write waits for its commit to finish,
+	// which leaves time to respond within requestTimeout
+	commitWaitTimeout = 5 * time.Second
+)
+
+// parseReadAfterWriteBuckets parses a comma-separated list of bucket names,
+// e.g. "master.images,master.uploads"
+func parseReadAfterWriteBuckets(s string) map[string]bool {
+	buckets := make(map[string]bool)
+	for _, bucket := range strings.Split(s, ",") {
+		if bucket = strings.TrimSpace(bucket); bucket != "" {
+			buckets[bucket] = true
+		}
+	}
+	return buckets
+}
+
+func commitNotFinishedError(r *http.Request) *s2.Error {
+	return s2.NewError(r, http.StatusServiceUnavailable, "CommitNotFinished", "The object was written, but its commit didn't finish in time for it to be readable. It will be readable once the commit finishes.")
+}
+
+// waitForWrite makes a write to 'bucket' (the branch 'branch' of 'repo')
+// readable when it's responded to, if the bucket or the request asks for it.
+// Writes to a branch without an open commit are in a commit that's already
+// finished, but writes to a branch with an open commit aren't readable until
+// that commit is finished, so this waits for the branch's head commit
+// to finish.
+func (c *controller) waitForWrite(r *http.Request, pc *client.APIClient, bucket, repo, branch string) error {
+	if !c.readAfterWrite[bucket] && !strings.EqualFold(r.Header.Get(readAfterWriteHeader), "true") {
+		return nil
+	}
+	branchInfo, err := pc.InspectBranch(repo, branch)
+	if err != nil {
+		return maybeNotFoundError(r, err)
+	}
+	if branchInfo.Head == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), commitWaitTimeout)
+	defer cancel()
+	if _, err := pc.WithCtx(ctx).BlockCommit(repo, branchInfo.Head.ID); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return commitNotFinishedError(r)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package s3
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	tu "github.com/pachyderm/pachyderm/src/server/pkg/testutil"
+)
+
+func TestParseReadAfterWriteBuckets(t *testing.T) {
+	require.Equal(t, 0, len(parseReadAfterWriteBuckets("")))
+	require.Equal(t, map[string]bool{
+		"master.images":  true,
+		"master.uploads": true,
+	}, parseReadAfterWriteBuckets("master.images, master.uploads,"))
+}
+
+func TestWaitForWrite(t *testing.T) {
+	require.NoError(t, tu.WithRealEnv(func(env *tu.RealEnv) error {
+		pc := env.PachClient
+		require.NoError(t, pc.CreateRepo("images"))
+		commit, err := pc.StartCommit("images", "master")
+		require.NoError(t, err)
+		_, err = pc.PutFile("images", "master", "/foo", strings.NewReader("foo"))
+		require.NoError(t, err)
+
+		c := &controller{readAfterWrite: parseReadAfterWriteBuckets("master.images")}
+		// Writes that don't ask to be readable don't wait
+		r := httptest.NewRequest("PUT", "/master.other/foo", nil)
+		require.NoError(t, c.waitForWrite(r, pc, "master.other", "images", "master"))
+
+		// Writes to a read-after-write bucket, or with the header, wait for
+		// the open commit to finish
+		for _, r := range []struct {
+			bucket string
+			header bool
+		}{{"master.images", false}, {"master.other", true}} {
+			req := httptest.NewRequest("PUT", "/"+r.bucket+"/foo", nil)
+			if r.header {
+				req.Header.Set(readAfterWriteHeader, "true")
+			}
+			done := make(chan error, 1)
+			go func() { done <- c.waitForWrite(req, pc, r.bucket, "images", "master") }()
+			select {
+			case err := <-done:
+				t.Fatalf("write returned before its commit finished: %v", err)
+			case <-time.After(100 * time.Millisecond):
+			}
+			require.NoError(t, pc.FinishCommit("images", commit.ID))
+			require.NoError(t, <-done)
+			if commit, err = pc.StartCommit("images", "master"); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+}
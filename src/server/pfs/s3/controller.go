@@ -2,8 +2,12 @@ package s3
 
 import (
 	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
 
 	"github.com/pachyderm/pachyderm/src/client"
+	pfsClient "github.com/pachyderm/pachyderm/src/client/pfs"
 	"github.com/sirupsen/logrus"
 )
 
@@ -18,6 +22,34 @@ type controller struct {
 	// the maximum number of allowed parts that can be associated with any
 	// given file
 	maxAllowedParts int
+
+	// Access policies of buckets, keyed by bucket name. Buckets that aren't
+	// in the map are read-write.
+	policies map[string]bucketPolicy
+
+	// The checksums that objects are reported with
+	checksums []pfsClient.ChecksumAlgorithm
+
+	// Buckets whose writes wait for their commits to finish before they're
+	// responded to (see waitForWrite)
+	readAfterWrite map[string]bool
+
+	// CORS configurations of buckets
+	cors *corsCache
+
+	// Website configurations of buckets
+	website *websiteCache
+
+	// The log of mutating requests, it's nil if they aren't audited
+	audit *auditLog
+
+	// Limits on the requests of buckets, keyed by bucket name. Buckets that
+	// aren't in the map are only limited by the gateway's own limits.
+	bucketLimits map[string]bucketLimits
+
+	// The cached metadata of the objects that have been read, it's nil if
+	// metadata isn't cached
+	metadata *metadataCache
 }
 
 func (c *controller) pachClient(authToken string) (*client.APIClient, error) {
@@ -30,3 +62,13 @@ func (c *controller) pachClient(authToken string) (*client.APIClient, error) {
 	}
 	return pc, nil
 }
+
+// requestClient returns a client with the access key of 'r', whose calls are
+// cancelled along with the request (e.g. once its bucket's timeout passes)
+func (c *controller) requestClient(r *http.Request) (*client.APIClient, error) {
+	pc, err := c.pachClient(mux.Vars(r)["authAccessKey"])
+	if err != nil {
+		return nil, err
+	}
+	return pc.WithCtx(r.Context()), nil
+}
@@ -0,0 +1,458 @@
+package s3
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/pachyderm/pachyderm/src/client"
+	pfsClient "github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/s2"
+)
+
+// Name of the PFS repo holding the CORS configurations of buckets. Each
+// bucket's configuration is stored as XML in a file named after the bucket.
+const corsRepo = "_s3gateway_cors_"
+
+// The maximum number of rules in a CORS configuration (the same as S3's)
+const maxCORSRules = 100
+
+var corsMethods = map[string]bool{
+	"GET":    true,
+	"PUT":    true,
+	"POST":   true,
+	"DELETE": true,
+	"HEAD":   true,
+}
+
+// corsConfiguration is the XML document set by PutBucketCors
+type corsConfiguration struct {
+	XMLName xml.Name   `xml:"CORSConfiguration"`
+	Rules   []corsRule `xml:"CORSRule"`
+}
+
+type corsRule struct {
+	ID             string   `xml:"ID,omitempty"`
+	AllowedOrigins []string `xml:"AllowedOrigin"`
+	AllowedMethods []string `xml:"AllowedMethod"`
+	AllowedHeaders []string `xml:"AllowedHeader,omitempty"`
+	ExposeHeaders  []string `xml:"ExposeHeader,omitempty"`
+	MaxAgeSeconds  int      `xml:"MaxAgeSeconds,omitempty"`
+}
+
+func noSuchCORSConfigurationError(r *http.Request) *s2.Error {
+	return s2.NewError(r, http.StatusNotFound, "NoSuchCORSConfiguration", "The CORS configuration does not exist")
+}
+
+func corsForbiddenError(r *http.Request) *s2.Error {
+	return s2.NewError(r, http.StatusForbidden, "AccessForbidden", "CORSResponse: This CORS request is not allowed.")
+}
+
+func (cfg *corsConfiguration) validate(r *http.Request) error {
+	if len(cfg.Rules) == 0 || len(cfg.Rules) > maxCORSRules {
+		return s2.MalformedXMLError(r)
+	}
+	for _, rule := range cfg.Rules {
+		if len(rule.AllowedOrigins) == 0 || len(rule.AllowedMethods) == 0 {
+			return s2.MalformedXMLError(r)
+		}
+		for _, method := range rule.AllowedMethods {
+			if !corsMethods[method] {
+				return s2.NewError(r, http.StatusBadRequest, "InvalidRequest", fmt.Sprintf("Found unsupported HTTP method in CORS config. Unsupported method is %s", method))
+			}
+		}
+		for _, origin := range rule.AllowedOrigins {
+			if strings.Count(origin, "*") > 1 {
+				return s2.NewError(r, http.StatusBadRequest, "InvalidRequest", fmt.Sprintf("AllowedOrigin %q can not have more than one wildcard.", origin))
+			}
+		}
+		for _, header := range rule.AllowedHeaders {
+			if strings.Count(header, "*") > 1 {
+				return s2.NewError(r, http.StatusBadRequest, "InvalidRequest", fmt.Sprintf("AllowedHeader %q can not have more than one wildcard.", header))
+			}
+		}
+	}
+	return nil
+}
+
+// wildcardMatch matches 's' against 'pattern', which may contain a single
+// '*' that matches any (possibly empty) substring
+func wildcardMatch(pattern, s string) bool {
+	i := strings.Index(pattern, "*")
+	if i < 0 {
+		return pattern == s
+	}
+	prefix, suffix := pattern[:i], pattern[i+1:]
+	return len(s) >= len(prefix)+len(suffix) && strings.HasPrefix(s, prefix) && strings.HasSuffix(s, suffix)
+}
+
+// match returns the first rule that allows a request from 'origin' with
+// 'method' and the (preflighted) request headers 'headers', or nil if no rule
+// does
+func (cfg *corsConfiguration) match(origin, method string, headers []string) *corsRule {
+	for i := range cfg.Rules {
+		rule := &cfg.Rules[i]
+		if rule.allows(origin, method, headers) {
+			return rule
+		}
+	}
+	return nil
+}
+
+func (rule *corsRule) allows(origin, method string, headers []string) bool {
+	originOK := false
+	for _, allowed := range rule.AllowedOrigins {
+		if wildcardMatch(allowed, origin) {
+			originOK = true
+			break
+		}
+	}
+	if !originOK {
+		return false
+	}
+	methodOK := false
+	for _, allowed := range rule.AllowedMethods {
+		if allowed == method {
+			methodOK = true
+			break
+		}
+	}
+	if !methodOK {
+		return false
+	}
+	for _, header := range headers {
+		headerOK := false
+		for _, allowed := range rule.AllowedHeaders {
+			if wildcardMatch(strings.ToLower(allowed), strings.ToLower(header)) {
+				headerOK = true
+				break
+			}
+		}
+		if !headerOK {
+			return false
+		}
+	}
+	return true
+}
+
+// setHeaders sets the Access-Control-* headers of a response to a request
+// from 'origin' that 'rule' allows. 'preflight' is set for responses to
+// OPTIONS requests, which also report the allowed methods and headers.
+func (rule *corsRule) setHeaders(w http.ResponseWriter, origin string, headers []string, preflight bool) {
+	h := w.Header()
+	allowOrigin := origin
+	for _, allowed := range rule.AllowedOrigins {
+		if allowed == "*" {
+			allowOrigin = "*"
+			break
+		}
+	}
+	h.Set("Access-Control-Allow-Origin", allowOrigin)
+	if allowOrigin != "*" {
+		// S3 clients authenticate with signatures rather than cookies, but
+		// browsers still refuse credentialed requests to a wildcard origin
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+	h.Add("Vary", "Origin")
+	if len(rule.ExposeHeaders) > 0 {
+		h.Set("Access-Control-Expose-Headers", strings.Join(rule.ExposeHeaders, ", "))
+	}
+	if !preflight {
+		return
+	}
+	h.Add("Vary", "Access-Control-Request-Method")
+	h.Add("Vary", "Access-Control-Request-Headers")
+	h.Set("Access-Control-Allow-Methods", strings.Join(rule.AllowedMethods, ", "))
+	if len(headers) > 0 {
+		h.Set("Access-Control-Allow-Headers", strings.Join(headers, ", "))
+	}
+	if rule.MaxAgeSeconds > 0 {
+		h.Set("Access-Control-Max-Age", strconv.Itoa(rule.MaxAgeSeconds))
+	}
+}
+
+// requestHeaders parses the Access-Control-Request-Headers header of a
+// preflight request
+func requestHeaders(r *http.Request) []string {
+	var result []string
+	for _, value := range r.Header["Access-Control-Request-Headers"] {
+		for _, header := range strings.Split(value, ",") {
+			if header = strings.TrimSpace(header); header != "" {
+				result = append(result, header)
+			}
+		}
+	}
+	return result
+}
+
+func (c *controller) ensureCORSRepo(pc *client.APIClient) error {
+	_, err := pc.InspectBranch(corsRepo, "master")
+	if err != nil {
+		err = pc.UpdateRepo(corsRepo)
+		if err != nil {
+			return err
+		}
+
+		err = pc.CreateBranch(corsRepo, "master", "", nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// corsConfig reads the CORS configuration of 'bucket', returning nil if it
+// has none. Configurations are cached, so that preflight requests, which
+// browsers send without credentials, can be answered on clusters with auth.
+func (c *controller) corsConfig(pc *client.APIClient, bucket string) (*corsConfiguration, error) {
+	var buf bytes.Buffer
+	if err := pc.GetFile(corsRepo, "master", bucket, 0, 0, &buf); err != nil {
+		if pfsClient.IsFileNotFoundErr(err) || pfsClient.IsRepoNotFoundErr(err) || pfsClient.IsBranchNotFoundErr(err) || pfsClient.IsBranchNoHeadErr(err) {
+			c.cacheCORSConfig(bucket, nil)
+			return nil, nil
+		}
+		return nil, err
+	}
+	cfg := &corsConfiguration{}
+	if err := xml.Unmarshal(buf.Bytes(), cfg); err != nil {
+		return nil, err
+	}
+	c.cacheCORSConfig(bucket, cfg)
+	return cfg, nil
+}
+
+// corsCache holds the CORS configurations that the gateway has read or
+// written, keyed by bucket. A nil configuration means the bucket has none.
+type corsCache struct {
+	mu      sync.Mutex
+	configs map[string]*corsConfiguration
+}
+
+func newCORSCache() *corsCache {
+	return &corsCache{configs: make(map[string]*corsConfiguration)}
+}
+
+func (c *controller) cacheCORSConfig(bucket string, cfg *corsConfiguration) {
+	c.cors.mu.Lock()
+	defer c.cors.mu.Unlock()
+	c.cors.configs[bucket] = cfg
+}
+
+func (c *controller) cachedCORSConfig(bucket string) (*corsConfiguration, bool) {
+	c.cors.mu.Lock()
+	defer c.cors.mu.Unlock()
+	cfg, ok := c.cors.configs[bucket]
+	return cfg, ok
+}
+
+// corsMiddleware serves the `?cors` bucket subresource and adds
+// Access-Control-* headers to the responses of cross-origin requests. It's
+// attached to the s2 router, so it runs after requests were authenticated.
+func (c *controller) corsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		bucket := vars["bucket"]
+		if bucket == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if _, ok := r.URL.Query()["cors"]; ok {
+			if _, ok := vars["key"]; !ok {
+				c.serveBucketCors(w, r, bucket)
+				return
+			}
+		}
+		if origin := r.Header.Get("Origin"); origin != "" {
+			pc, err := c.pachClient(vars["authAccessKey"])
+			if err != nil {
+				c.writeError(w, r, err)
+				return
+			}
+			cfg, err := c.corsConfig(pc, bucket)
+			if err != nil {
+				// don't fail the request itself, browsers will refuse the
+				// response without CORS headers
+				c.logger.Errorf("could not read the CORS configuration of %s: %v", bucket, err)
+			} else if cfg != nil {
+				if rule := cfg.match(origin, r.Method, nil); rule != nil {
+					rule.setHeaders(w, origin, nil, false)
+				}
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (c *controller) serveBucketCors(w http.ResponseWriter, r *http.Request, bucket string) {
+	var err error
+	switch r.Method {
+	case "GET":
+		var cfg *corsConfiguration
+		if cfg, err = c.GetBucketCors(r, bucket); err == nil {
+			var body []byte
+			if body, err = xml.Marshal(cfg); err == nil {
+				w.Header().Set("Content-Type", "application/xml")
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprint(w, xml.Header)
+				w.Write(body)
+				return
+			}
+		}
+	case "PUT":
+		if err = c.PutBucketCors(r, bucket); err == nil {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+	case "DELETE":
+		if err = c.DeleteBucketCors(r, bucket); err == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	default:
+		err = s2.MethodNotAllowedError(r)
+	}
+	c.writeError(w, r, err)
+}
+
+// GetBucketCors returns the CORS configuration of a bucket
+func (c *controller) GetBucketCors(r *http.Request, bucket string) (*corsConfiguration, error) {
+	vars := mux.Vars(r)
+	pc, err := c.pachClient(vars["authAccessKey"])
+	if err != nil {
+		return nil, err
+	}
+	repo, branch, err := bucketArgs(r, bucket)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := pc.InspectBranch(repo, branch); err != nil {
+		return nil, maybeNotFoundError(r, err)
+	}
+
+	cfg, err := c.corsConfig(pc, bucket)
+	if err != nil {
+		return nil, s2.InternalError(r, err)
+	}
+	if cfg == nil {
+		return nil, noSuchCORSConfigurationError(r)
+	}
+	return cfg, nil
+}
+
+// PutBucketCors sets the CORS configuration of a bucket from the XML
+// document in the request body
+func (c *controller) PutBucketCors(r *http.Request, bucket string) error {
+	if err := c.canWrite(r, bucket); err != nil {
+		return err
+	}
+	vars := mux.Vars(r)
+	pc, err := c.pachClient(vars["authAccessKey"])
+	if err != nil {
+		return err
+	}
+	repo, branch, err := bucketArgs(r, bucket)
+	if err != nil {
+		return err
+	}
+	if _, err := pc.InspectBranch(repo, branch); err != nil {
+		return maybeNotFoundError(r, err)
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return s2.InternalError(r, err)
+	}
+	cfg := &corsConfiguration{}
+	if err := xml.Unmarshal(body, cfg); err != nil {
+		return s2.MalformedXMLError(r)
+	}
+	if err := cfg.validate(r); err != nil {
+		return err
+	}
+	if err := c.ensureCORSRepo(pc); err != nil {
+		return s2.InternalError(r, err)
+	}
+	if _, err := pc.PutFileOverwrite(corsRepo, "master", bucket, bytes.NewReader(body), 0); err != nil {
+		return s2.InternalError(r, err)
+	}
+	c.cacheCORSConfig(bucket, cfg)
+	return nil
+}
+
+// DeleteBucketCors removes the CORS configuration of a bucket
+func (c *controller) DeleteBucketCors(r *http.Request, bucket string) error {
+	if err := c.canWrite(r, bucket); err != nil {
+		return err
+	}
+	vars := mux.Vars(r)
+	pc, err := c.pachClient(vars["authAccessKey"])
+	if err != nil {
+		return err
+	}
+	repo, branch, err := bucketArgs(r, bucket)
+	if err != nil {
+		return err
+	}
+	if _, err := pc.InspectBranch(repo, branch); err != nil {
+		return maybeNotFoundError(r, err)
+	}
+
+	cfg, err := c.corsConfig(pc, bucket)
+	if err != nil {
+		return s2.InternalError(r, err)
+	}
+	if cfg != nil {
+		if err := pc.DeleteFile(corsRepo, "master", bucket); err != nil {
+			return s2.InternalError(r, err)
+		}
+	}
+	c.cacheCORSConfig(bucket, nil)
+	return nil
+}
+
+// servePreflight answers a CORS preflight (OPTIONS) request. Browsers don't
+// send credentials with these, so the bucket's configuration is read from the
+// cache if possible, and otherwise without an auth token.
+func (c *controller) servePreflight(w http.ResponseWriter, r *http.Request) {
+	origin := r.Header.Get("Origin")
+	method := r.Header.Get("Access-Control-Request-Method")
+	if origin == "" || method == "" {
+		c.writeError(w, r, s2.NewError(r, http.StatusBadRequest, "BadRequest", "Insufficient information. Origin request header needed."))
+		return
+	}
+	bucket := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 2)[0]
+	if bucket == "" {
+		c.writeError(w, r, corsForbiddenError(r))
+		return
+	}
+	cfg, ok := c.cachedCORSConfig(bucket)
+	if !ok {
+		pc, err := c.pachClient("")
+		if err != nil {
+			c.writeError(w, r, err)
+			return
+		}
+		if cfg, err = c.corsConfig(pc, bucket); err != nil {
+			c.logger.Errorf("could not read the CORS configuration of %s: %v", bucket, err)
+		}
+	}
+	headers := requestHeaders(r)
+	if cfg == nil {
+		c.writeError(w, r, corsForbiddenError(r))
+		return
+	}
+	rule := cfg.match(origin, method, headers)
+	if rule == nil {
+		c.writeError(w, r, corsForbiddenError(r))
+		return
+	}
+	rule.setHeaders(w, origin, headers, true)
+	w.WriteHeader(http.StatusOK)
+}
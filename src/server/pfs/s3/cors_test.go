@@ -0,0 +1,104 @@
+package s3
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+const testCORSConfig = `<CORSConfiguration>
+  <CORSRule>
+    <AllowedOrigin>https://*.example.com</AllowedOrigin>
+    <AllowedMethod>GET</AllowedMethod>
+    <AllowedMethod>HEAD</AllowedMethod>
+    <AllowedHeader>x-amz-*</AllowedHeader>
+    <AllowedHeader>Authorization</AllowedHeader>
+    <ExposeHeader>ETag</ExposeHeader>
+    <MaxAgeSeconds>3000</MaxAgeSeconds>
+  </CORSRule>
+  <CORSRule>
+    <AllowedOrigin>*</AllowedOrigin>
+    <AllowedMethod>GET</AllowedMethod>
+  </CORSRule>
+</CORSConfiguration>`
+
+func TestWildcardMatch(t *testing.T) {
+	require.True(t, wildcardMatch("*", ""))
+	require.True(t, wildcardMatch("*", "https://example.com"))
+	require.True(t, wildcardMatch("https://example.com", "https://example.com"))
+	require.False(t, wildcardMatch("https://example.com", "https://example.org"))
+	require.True(t, wildcardMatch("https://*.example.com", "https://www.example.com"))
+	require.False(t, wildcardMatch("https://*.example.com", "https://example.com"))
+	require.False(t, wildcardMatch("ab*ba", "aba"))
+}
+
+func TestCORSMatch(t *testing.T) {
+	cfg := &corsConfiguration{}
+	require.NoError(t, xml.Unmarshal([]byte(testCORSConfig), cfg))
+	r := httptest.NewRequest("PUT", "/master.images?cors", nil)
+	require.NoError(t, cfg.validate(r))
+
+	rule := cfg.match("https://www.example.com", "HEAD", []string{"X-Amz-Date", "authorization"})
+	require.NotNil(t, rule)
+	require.Equal(t, 3000, rule.MaxAgeSeconds)
+	// falls through to the second rule, which allows no request headers
+	rule = cfg.match("https://www.example.org", "GET", nil)
+	require.NotNil(t, rule)
+	require.Equal(t, []string{"*"}, rule.AllowedOrigins)
+	require.Nil(t, cfg.match("https://www.example.org", "GET", []string{"Content-Type"}))
+	require.Nil(t, cfg.match("https://www.example.org", "HEAD", nil))
+	require.Nil(t, cfg.match("https://www.example.com", "PUT", nil))
+
+	require.YesError(t, (&corsConfiguration{}).validate(r))
+	require.YesError(t, (&corsConfiguration{Rules: []corsRule{{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{"PATCH"},
+	}}}).validate(r))
+	require.YesError(t, (&corsConfiguration{Rules: []corsRule{{
+		AllowedOrigins: []string{"https://*.*.example.com"},
+		AllowedMethods: []string{"GET"},
+	}}}).validate(r))
+}
+
+func TestServePreflight(t *testing.T) {
+	cfg := &corsConfiguration{}
+	require.NoError(t, xml.Unmarshal([]byte(testCORSConfig), cfg))
+	c := &controller{cors: newCORSCache()}
+	c.cacheCORSConfig("master.images", cfg)
+	c.cacheCORSConfig("master.other", nil)
+
+	preflight := func(path, origin, method, headers string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("OPTIONS", path, nil)
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+		if method != "" {
+			r.Header.Set("Access-Control-Request-Method", method)
+		}
+		if headers != "" {
+			r.Header.Set("Access-Control-Request-Headers", headers)
+		}
+		w := httptest.NewRecorder()
+		c.servePreflight(w, r)
+		return w
+	}
+
+	w := preflight("/master.images/foo", "https://www.example.com", "GET", "x-amz-date, authorization")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "https://www.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	require.Equal(t, "GET, HEAD", w.Header().Get("Access-Control-Allow-Methods"))
+	require.Equal(t, "x-amz-date, authorization", w.Header().Get("Access-Control-Allow-Headers"))
+	require.Equal(t, "ETag", w.Header().Get("Access-Control-Expose-Headers"))
+	require.Equal(t, "3000", w.Header().Get("Access-Control-Max-Age"))
+
+	w = preflight("/master.images", "https://foo.org", "GET", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+
+	require.Equal(t, http.StatusForbidden, preflight("/master.images", "https://foo.org", "DELETE", "").Code)
+	require.Equal(t, http.StatusForbidden, preflight("/master.other", "https://foo.org", "GET", "").Code)
+	require.Equal(t, http.StatusBadRequest, preflight("/master.images", "", "GET", "").Code)
+}
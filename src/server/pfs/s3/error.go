@@ -1,9 +1,10 @@
 package s3
 
 import (
+	"fmt"
 	"net/http"
 
-	"github.com/pachyderm/pachyderm/src/server/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
 	"github.com/pachyderm/s2"
 )
 
@@ -19,6 +20,18 @@ func writeToOutputBranchError(r *http.Request) *s2.Error {
 	return s2.NewError(r, http.StatusBadRequest, "WriteToOutputBranch", "You cannot write to an output branch")
 }
 
+func invalidTargetBranchError(r *http.Request) *s2.Error {
+	return s2.NewError(r, http.StatusBadRequest, "InvalidArgument", "The target branch is invalid. Only alphanumeric characters, underscores, and dashes are allowed.")
+}
+
+func slowDownError(r *http.Request) *s2.Error {
+	return s2.NewError(r, http.StatusServiceUnavailable, "SlowDown", "Please reduce your request rate.")
+}
+
+func tooManyPartsError(r *http.Request, maxParts int) *s2.Error {
+	return s2.NewError(r, http.StatusBadRequest, "InvalidArgument", fmt.Sprintf("Multipart uploads to this bucket can't have more than %d parts", maxParts))
+}
+
 func maybeNotFoundError(r *http.Request, err error) *s2.Error {
 	if pfs.IsRepoNotFoundErr(err) || pfs.IsBranchNotFoundErr(err) {
 		return s2.NoSuchBucketError(r)
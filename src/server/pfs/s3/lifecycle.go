@@ -0,0 +1,412 @@
+package s3
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/gorilla/mux"
+	"github.com/pachyderm/pachyderm/src/client"
+	pfsClient "github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/s2"
+)
+
+// Name of the PFS repo holding the lifecycle configurations of buckets. Each
+// bucket's configuration is stored as XML in a file named after the bucket.
+const lifecycleRepo = "_s3gateway_lifecycle_"
+
+// The maximum number of rules in a lifecycle configuration (the same as S3's)
+const maxLifecycleRules = 1000
+
+// How often the gateway applies the expiration rules of buckets
+const lifecycleInterval = time.Hour
+
+// lifecycleConfiguration is the XML document set by
+// PutBucketLifecycleConfiguration. Only expiration rules are supported.
+type lifecycleConfiguration struct {
+	XMLName xml.Name        `xml:"LifecycleConfiguration"`
+	Rules   []lifecycleRule `xml:"Rule"`
+}
+
+type lifecycleRule struct {
+	ID string `xml:"ID,omitempty"`
+	// Prefix is the deprecated alternative to Filter
+	Prefix     string               `xml:"Prefix,omitempty"`
+	Filter     *lifecycleFilter     `xml:"Filter,omitempty"`
+	Status     string               `xml:"Status"`
+	Expiration *lifecycleExpiration `xml:"Expiration,omitempty"`
+	// Other holds the rule's unsupported actions, e.g. Transition
+	Other []unsupportedElement `xml:",any"`
+}
+
+type lifecycleFilter struct {
+	Prefix string `xml:"Prefix"`
+	// Other holds the filter's unsupported conditions, e.g. Tag
+	Other []unsupportedElement `xml:",any"`
+}
+
+type lifecycleExpiration struct {
+	Days int `xml:"Days"`
+	// Other holds the expiration's unsupported fields, e.g. Date
+	Other []unsupportedElement `xml:",any"`
+}
+
+type unsupportedElement struct {
+	XMLName xml.Name
+}
+
+func noSuchLifecycleConfigurationError(r *http.Request) *s2.Error {
+	return s2.NewError(r, http.StatusNotFound, "NoSuchLifecycleConfiguration", "The lifecycle configuration does not exist")
+}
+
+func unsupportedLifecycleError(r *http.Request, name string) *s2.Error {
+	return s2.NewError(r, http.StatusNotImplemented, "NotImplemented", fmt.Sprintf("%s is not supported in lifecycle configurations, only expiration after a number of days is", name))
+}
+
+func (cfg *lifecycleConfiguration) validate(r *http.Request) error {
+	if len(cfg.Rules) == 0 || len(cfg.Rules) > maxLifecycleRules {
+		return s2.MalformedXMLError(r)
+	}
+	ids := make(map[string]bool)
+	for _, rule := range cfg.Rules {
+		if len(rule.ID) > 255 {
+			return s2.NewError(r, http.StatusBadRequest, "InvalidArgument", "ID length should not exceed allowed limit of 255")
+		}
+		if rule.ID != "" {
+			if ids[rule.ID] {
+				return s2.NewError(r, http.StatusBadRequest, "InvalidArgument", "Rule ID must be unique. Found same ID for more than one rule")
+			}
+			ids[rule.ID] = true
+		}
+		if rule.Status != "Enabled" && rule.Status != "Disabled" {
+			return s2.MalformedXMLError(r)
+		}
+		if rule.Filter != nil && rule.Prefix != "" {
+			return s2.MalformedXMLError(r)
+		}
+		if len(rule.Other) > 0 {
+			return unsupportedLifecycleError(r, rule.Other[0].XMLName.Local)
+		}
+		if rule.Filter != nil && len(rule.Filter.Other) > 0 {
+			return unsupportedLifecycleError(r, rule.Filter.Other[0].XMLName.Local)
+		}
+		if rule.Expiration == nil {
+			return s2.MalformedXMLError(r)
+		}
+		if len(rule.Expiration.Other) > 0 {
+			return unsupportedLifecycleError(r, rule.Expiration.Other[0].XMLName.Local)
+		}
+		if rule.Expiration.Days <= 0 {
+			return s2.NewError(r, http.StatusBadRequest, "InvalidArgument", "'Days' for Expiration action must be a positive integer")
+		}
+	}
+	return nil
+}
+
+func (rule *lifecycleRule) prefix() string {
+	if rule.Filter != nil {
+		return rule.Filter.Prefix
+	}
+	return rule.Prefix
+}
+
+// expired returns true if an enabled rule expires the object at 'path', which
+// was last modified at 'modified'
+func (cfg *lifecycleConfiguration) expired(path string, modified, now time.Time) bool {
+	for _, rule := range cfg.Rules {
+		if rule.Status != "Enabled" || !strings.HasPrefix(path, rule.prefix()) {
+			continue
+		}
+		if modified.Add(time.Duration(rule.Expiration.Days) * 24 * time.Hour).Before(now) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *controller) ensureLifecycleRepo(pc *client.APIClient) error {
+	_, err := pc.InspectBranch(lifecycleRepo, "master")
+	if err != nil {
+		err = pc.UpdateRepo(lifecycleRepo)
+		if err != nil {
+			return err
+		}
+
+		err = pc.CreateBranch(lifecycleRepo, "master", "", nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lifecycleConfig reads the lifecycle configuration of 'bucket', returning nil
+// if it has none
+func (c *controller) lifecycleConfig(pc *client.APIClient, bucket string) (*lifecycleConfiguration, error) {
+	var buf bytes.Buffer
+	if err := pc.GetFile(lifecycleRepo, "master", bucket, 0, 0, &buf); err != nil {
+		if pfsClient.IsFileNotFoundErr(err) || pfsClient.IsRepoNotFoundErr(err) || pfsClient.IsBranchNotFoundErr(err) || pfsClient.IsBranchNoHeadErr(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	cfg := &lifecycleConfiguration{}
+	if err := xml.Unmarshal(buf.Bytes(), cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// lifecycleMiddleware serves the `?lifecycle` bucket subresource. It's
+// attached to the s2 router, so it runs after requests were authenticated.
+func (c *controller) lifecycleMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		if bucket := vars["bucket"]; bucket != "" {
+			if _, ok := r.URL.Query()["lifecycle"]; ok {
+				if _, ok := vars["key"]; !ok {
+					c.serveBucketLifecycle(w, r, bucket)
+					return
+				}
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (c *controller) serveBucketLifecycle(w http.ResponseWriter, r *http.Request, bucket string) {
+	var err error
+	switch r.Method {
+	case "GET":
+		var cfg *lifecycleConfiguration
+		if cfg, err = c.GetBucketLifecycle(r, bucket); err == nil {
+			var body []byte
+			if body, err = xml.Marshal(cfg); err == nil {
+				w.Header().Set("Content-Type", "application/xml")
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprint(w, xml.Header)
+				w.Write(body)
+				return
+			}
+		}
+	case "PUT":
+		if err = c.PutBucketLifecycle(r, bucket); err == nil {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+	case "DELETE":
+		if err = c.DeleteBucketLifecycle(r, bucket); err == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	default:
+		err = s2.MethodNotAllowedError(r)
+	}
+	c.writeError(w, r, err)
+}
+
+// GetBucketLifecycle returns the lifecycle configuration of a bucket
+func (c *controller) GetBucketLifecycle(r *http.Request, bucket string) (*lifecycleConfiguration, error) {
+	vars := mux.Vars(r)
+	pc, err := c.pachClient(vars["authAccessKey"])
+	if err != nil {
+		return nil, err
+	}
+	repo, branch, err := bucketArgs(r, bucket)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := pc.InspectBranch(repo, branch); err != nil {
+		return nil, maybeNotFoundError(r, err)
+	}
+
+	cfg, err := c.lifecycleConfig(pc, bucket)
+	if err != nil {
+		return nil, s2.InternalError(r, err)
+	}
+	if cfg == nil {
+		return nil, noSuchLifecycleConfigurationError(r)
+	}
+	return cfg, nil
+}
+
+// PutBucketLifecycle sets the lifecycle configuration of a bucket from the
+// XML document in the request body
+func (c *controller) PutBucketLifecycle(r *http.Request, bucket string) error {
+	if err := c.canWrite(r, bucket); err != nil {
+		return err
+	}
+	vars := mux.Vars(r)
+	pc, err := c.pachClient(vars["authAccessKey"])
+	if err != nil {
+		return err
+	}
+	repo, branch, err := bucketArgs(r, bucket)
+	if err != nil {
+		return err
+	}
+	if _, err := pc.InspectBranch(repo, branch); err != nil {
+		return maybeNotFoundError(r, err)
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return s2.InternalError(r, err)
+	}
+	cfg := &lifecycleConfiguration{}
+	if err := xml.Unmarshal(body, cfg); err != nil {
+		return s2.MalformedXMLError(r)
+	}
+	if err := cfg.validate(r); err != nil {
+		return err
+	}
+	if err := c.ensureLifecycleRepo(pc); err != nil {
+		return s2.InternalError(r, err)
+	}
+	if _, err := pc.PutFileOverwrite(lifecycleRepo, "master", bucket, bytes.NewReader(body), 0); err != nil {
+		return s2.InternalError(r, err)
+	}
+	return nil
+}
+
+// DeleteBucketLifecycle removes the lifecycle configuration of a bucket
+func (c *controller) DeleteBucketLifecycle(r *http.Request, bucket string) error {
+	if err := c.canWrite(r, bucket); err != nil {
+		return err
+	}
+	vars := mux.Vars(r)
+	pc, err := c.pachClient(vars["authAccessKey"])
+	if err != nil {
+		return err
+	}
+	repo, branch, err := bucketArgs(r, bucket)
+	if err != nil {
+		return err
+	}
+	if _, err := pc.InspectBranch(repo, branch); err != nil {
+		return maybeNotFoundError(r, err)
+	}
+
+	cfg, err := c.lifecycleConfig(pc, bucket)
+	if err != nil {
+		return s2.InternalError(r, err)
+	}
+	if cfg != nil {
+		if err := pc.DeleteFile(lifecycleRepo, "master", bucket); err != nil {
+			return s2.InternalError(r, err)
+		}
+	}
+	return nil
+}
+
+// runLifecycle applies the expiration rules of every bucket that has a
+// lifecycle configuration, every lifecycleInterval. It never returns.
+func (c *controller) runLifecycle() {
+	for {
+		if err := c.expireObjects(time.Now()); err != nil {
+			c.logger.Errorf("could not apply bucket lifecycle configurations: %v", err)
+		}
+		time.Sleep(lifecycleInterval)
+	}
+}
+
+// expireObjects deletes the objects that the lifecycle configurations of
+// buckets have expired as of 'now'. Each bucket's objects are deleted in a
+// single commit, so earlier commits still hold them.
+func (c *controller) expireObjects(now time.Time) error {
+	pc, err := c.pachClient("")
+	if err != nil {
+		return err
+	}
+	fileInfos, err := pc.ListFile(lifecycleRepo, "master", "/")
+	if err != nil {
+		if pfsClient.IsRepoNotFoundErr(err) || pfsClient.IsBranchNotFoundErr(err) || pfsClient.IsBranchNoHeadErr(err) {
+			return nil
+		}
+		return err
+	}
+	for _, fileInfo := range fileInfos {
+		bucket := strings.TrimPrefix(fileInfo.File.Path, "/")
+		cfg, err := c.lifecycleConfig(pc, bucket)
+		if err != nil {
+			return err
+		}
+		if cfg == nil {
+			continue
+		}
+		// keep going, so that one bucket can't stop the others from expiring
+		if err := c.expireBucket(pc, bucket, cfg, now); err != nil {
+			c.logger.Errorf("could not expire the objects of %s: %v", bucket, err)
+		}
+	}
+	return nil
+}
+
+func (c *controller) expireBucket(pc *client.APIClient, bucket string, cfg *lifecycleConfiguration, now time.Time) (retErr error) {
+	parts := strings.SplitN(bucket, ".", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid bucket name %q", bucket)
+	}
+	repo, branch := parts[1], parts[0]
+
+	branchInfo, err := pc.InspectBranch(repo, branch)
+	if err != nil {
+		if pfsClient.IsRepoNotFoundErr(err) || pfsClient.IsBranchNotFoundErr(err) {
+			// the bucket was deleted, but its configuration is harmless
+			return nil
+		}
+		return err
+	}
+	if branchInfo.Head == nil {
+		return nil
+	}
+
+	var expired []string
+	if err := pc.Walk(repo, branch, "/", func(fileInfo *pfsClient.FileInfo) error {
+		if fileInfo.FileType != pfsClient.FileType_FILE {
+			return nil
+		}
+		modified, err := types.TimestampFromProto(fileInfo.Committed)
+		if err != nil {
+			return err
+		}
+		key := strings.TrimPrefix(fileInfo.File.Path, "/")
+		if cfg.expired(key, modified, now) {
+			expired = append(expired, fileInfo.File.Path)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if len(expired) == 0 {
+		return nil
+	}
+	sort.Strings(expired)
+
+	commit, err := pc.StartCommit(repo, branch)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if retErr != nil {
+			pc.DeleteCommit(repo, commit.ID)
+		}
+	}()
+	for _, path := range expired {
+		if err := pc.DeleteFile(repo, commit.ID, path); err != nil {
+			return err
+		}
+	}
+	if err := pc.FinishCommit(repo, commit.ID); err != nil {
+		return err
+	}
+	c.logger.Infof("expired %d objects of %s", len(expired), bucket)
+	return nil
+}
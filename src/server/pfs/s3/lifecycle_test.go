@@ -0,0 +1,108 @@
+package s3
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/s2"
+)
+
+const testLifecycleConfig = `<LifecycleConfiguration>
+  <Rule>
+    <ID>logs</ID>
+    <Filter>
+      <Prefix>logs/</Prefix>
+    </Filter>
+    <Status>Enabled</Status>
+    <Expiration>
+      <Days>7</Days>
+    </Expiration>
+  </Rule>
+  <Rule>
+    <Prefix>tmp/</Prefix>
+    <Status>Enabled</Status>
+    <Expiration>
+      <Days>1</Days>
+    </Expiration>
+  </Rule>
+  <Rule>
+    <Filter>
+      <Prefix></Prefix>
+    </Filter>
+    <Status>Disabled</Status>
+    <Expiration>
+      <Days>1</Days>
+    </Expiration>
+  </Rule>
+</LifecycleConfiguration>`
+
+func TestLifecycleExpired(t *testing.T) {
+	cfg := &lifecycleConfiguration{}
+	require.NoError(t, xml.Unmarshal([]byte(testLifecycleConfig), cfg))
+	r := httptest.NewRequest("PUT", "/master.images?lifecycle", nil)
+	require.NoError(t, cfg.validate(r))
+
+	now := time.Now()
+	day := 24 * time.Hour
+	require.True(t, cfg.expired("logs/a", now.Add(-8*day), now))
+	require.False(t, cfg.expired("logs/a", now.Add(-6*day), now))
+	require.True(t, cfg.expired("tmp/a", now.Add(-2*day), now))
+	require.False(t, cfg.expired("tmp/a", now.Add(-day/2), now))
+	// the rule matching every object is disabled
+	require.False(t, cfg.expired("images/a", now.Add(-100*day), now))
+
+	// round trips
+	body, err := xml.Marshal(cfg)
+	require.NoError(t, err)
+	cfg2 := &lifecycleConfiguration{}
+	require.NoError(t, xml.Unmarshal(body, cfg2))
+	require.Equal(t, cfg.Rules, cfg2.Rules)
+}
+
+func TestLifecycleValidate(t *testing.T) {
+	r := httptest.NewRequest("PUT", "/master.images?lifecycle", nil)
+	validate := func(doc string) error {
+		t.Helper()
+		cfg := &lifecycleConfiguration{}
+		require.NoError(t, xml.Unmarshal([]byte(doc), cfg))
+		return cfg.validate(r)
+	}
+	requireStatus := func(status int, err error) {
+		t.Helper()
+		s2Err, ok := err.(*s2.Error)
+		require.True(t, ok)
+		require.Equal(t, status, s2Err.HTTPStatus)
+	}
+
+	requireStatus(http.StatusBadRequest, validate(`<LifecycleConfiguration></LifecycleConfiguration>`))
+	requireStatus(http.StatusBadRequest, validate(`<LifecycleConfiguration><Rule>
+	  <Status>Enabled</Status>
+	</Rule></LifecycleConfiguration>`))
+	requireStatus(http.StatusBadRequest, validate(`<LifecycleConfiguration><Rule>
+	  <Status>On</Status><Expiration><Days>1</Days></Expiration>
+	</Rule></LifecycleConfiguration>`))
+	requireStatus(http.StatusBadRequest, validate(`<LifecycleConfiguration><Rule>
+	  <Status>Enabled</Status><Expiration><Days>0</Days></Expiration>
+	</Rule></LifecycleConfiguration>`))
+	requireStatus(http.StatusBadRequest, validate(`<LifecycleConfiguration>
+	  <Rule><ID>a</ID><Status>Enabled</Status><Expiration><Days>1</Days></Expiration></Rule>
+	  <Rule><ID>a</ID><Status>Enabled</Status><Expiration><Days>2</Days></Expiration></Rule>
+	</LifecycleConfiguration>`))
+
+	// other actions and filters aren't supported
+	requireStatus(http.StatusNotImplemented, validate(`<LifecycleConfiguration><Rule>
+	  <Status>Enabled</Status>
+	  <Transition><Days>30</Days><StorageClass>GLACIER</StorageClass></Transition>
+	</Rule></LifecycleConfiguration>`))
+	requireStatus(http.StatusNotImplemented, validate(`<LifecycleConfiguration><Rule>
+	  <Filter><Tag><Key>a</Key><Value>b</Value></Tag></Filter>
+	  <Status>Enabled</Status><Expiration><Days>1</Days></Expiration>
+	</Rule></LifecycleConfiguration>`))
+	requireStatus(http.StatusNotImplemented, validate(`<LifecycleConfiguration><Rule>
+	  <Status>Enabled</Status><Expiration><Date>2030-01-01T00:00:00.000Z</Date></Expiration>
+	</Rule></LifecycleConfiguration>`))
+}
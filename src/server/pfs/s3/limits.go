@@ -0,0 +1,312 @@
+package s3
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	units "github.com/docker/go-units"
+	"github.com/gorilla/mux"
+	"github.com/pachyderm/s2"
+	"golang.org/x/net/http2"
+)
+
+// shedRetryAfter is the number of seconds after which clients are told to
+// retry the requests that are shed because the gateway is at its limits
+const shedRetryAfter = 1
+
+// Limits are the S3 gateway's limits on its clients' connections and
+// requests. Their zero values are no limit.
+type Limits struct {
+	// HTTP2 enables HTTP/2, which is negotiated over TLS
+	HTTP2 bool
+	// MaxRequests is the most requests that are served at once. Requests
+	// over the limit are shed with a 503 (SlowDown) and a Retry-After header.
+	MaxRequests int
+	// MaxClientRequests is the most requests that are served at once for a
+	// client (by its IP address), however many connections it makes.
+	// Requests over the limit are shed like those over MaxRequests.
+	MaxClientRequests int
+	// MaxConnectionStreams is the most concurrent HTTP/2 streams (requests)
+	// per connection. 0 is the http2 package's default of 250.
+	MaxConnectionStreams uint32
+	// IdleTimeout is how long a keep-alive connection is kept open between
+	// requests. A negative IdleTimeout disables keep-alive.
+	IdleTimeout time.Duration
+	// BucketLimits is a comma-separated list of `<bucket>=<limits>` pairs,
+	// where the limits are `;`-separated `<limit>:<value>` pairs, e.g.
+	// "master.uploads=max-object-size:1GB;max-parts:100;timeout:5s" (see
+	// bucketLimits)
+	BucketLimits string
+}
+
+// configure applies the limits to 'server'
+func (l Limits) configure(server *http.Server) error {
+	if l.MaxRequests < 0 || l.MaxClientRequests < 0 {
+		return fmt.Errorf("request limits can't be negative")
+	}
+	if l.IdleTimeout < 0 {
+		server.SetKeepAlivesEnabled(false)
+	} else {
+		server.IdleTimeout = l.IdleTimeout
+	}
+	if !l.HTTP2 {
+		// A non-nil, empty TLSNextProto stops net/http from enabling HTTP/2
+		server.TLSNextProto = map[string]func(*http.Server, *tls.Conn, http.Handler){}
+		return nil
+	}
+	if l.MaxConnectionStreams == 0 && l.IdleTimeout >= 0 {
+		return nil // net/http's own HTTP/2 support uses the defaults
+	}
+	h2 := &http2.Server{MaxConcurrentStreams: l.MaxConnectionStreams}
+	if l.IdleTimeout > 0 {
+		h2.IdleTimeout = l.IdleTimeout
+	}
+	return http2.ConfigureServer(server, h2)
+}
+
+// requestLimiter counts the requests that are being served, in total and per
+// client, to shed those over the gateway's Limits. A nil *requestLimiter
+// doesn't limit anything.
+type requestLimiter struct {
+	maxRequests       int
+	maxClientRequests int
+
+	mu             sync.Mutex
+	requests       int
+	clientRequests map[string]int
+}
+
+func newRequestLimiter(limits Limits) *requestLimiter {
+	if limits.MaxRequests == 0 && limits.MaxClientRequests == 0 {
+		return nil
+	}
+	return &requestLimiter{
+		maxRequests:       limits.MaxRequests,
+		maxClientRequests: limits.MaxClientRequests,
+		clientRequests:    make(map[string]int),
+	}
+}
+
+// acquire counts a request from 'client', and returns a func that has to be
+// called once it's been served. It returns false, and doesn't count the
+// request, if it's over the limits.
+func (l *requestLimiter) acquire(client string) (func(), bool) {
+	if l == nil {
+		return func() {}, true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.maxRequests > 0 && l.requests >= l.maxRequests {
+		return nil, false
+	}
+	if l.maxClientRequests > 0 && l.clientRequests[client] >= l.maxClientRequests {
+		return nil, false
+	}
+	l.requests++
+	l.clientRequests[client]++
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.requests--
+		if l.clientRequests[client]--; l.clientRequests[client] <= 0 {
+			delete(l.clientRequests, client)
+		}
+	}, true
+}
+
+// clientAddress returns the IP address of the client that sent 'r'
+func clientAddress(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// shed responds to a request that's over the gateway's limits
+func (c *controller) shed(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Retry-After", fmt.Sprint(shedRetryAfter))
+	s2.WriteError(c.logger, w, r, slowDownError(r))
+}
+
+// errObjectTooLarge is returned by the body of an upload once it's read past
+// its bucket's max-object-size
+var errObjectTooLarge = errors.New("object is larger than its bucket's max-object-size")
+
+// bucketLimits are the limits on a bucket's requests, which are enforced by
+// the gateway before their data reaches PFS. Their zero values are no limit.
+type bucketLimits struct {
+	// maxObjectSize is the largest object, in bytes, that can be uploaded
+	// to the bucket (by PutObject, or by the parts of a multipart upload)
+	maxObjectSize int64
+	// maxParts is the most parts that a multipart upload to the bucket can
+	// have
+	maxParts int
+	// timeout is how long the bucket's requests can take. It can't be
+	// longer than the gateway's own requestTimeout.
+	timeout time.Duration
+}
+
+// parseBucketLimits parses a comma-separated list of `<bucket>=<limits>`
+// pairs, e.g. "master.uploads=max-object-size:1GB;max-parts:100,master.images=timeout:2s"
+func parseBucketLimits(s string) (map[string]bucketLimits, error) {
+	result := make(map[string]bucketLimits)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid bucket limits %q, expected <bucket>=<limit>:<value>[;<limit>:<value>...]", pair)
+		}
+		bucket := parts[0]
+		if _, ok := result[bucket]; ok {
+			return nil, fmt.Errorf("bucket %q has more than one set of limits", bucket)
+		}
+		var limits bucketLimits
+		for _, limit := range strings.Split(parts[1], ";") {
+			nameValue := strings.SplitN(strings.TrimSpace(limit), ":", 2)
+			if len(nameValue) != 2 {
+				return nil, fmt.Errorf("invalid limit %q for bucket %q, expected <limit>:<value>", limit, bucket)
+			}
+			name, value := nameValue[0], nameValue[1]
+			switch name {
+			case "max-object-size":
+				size, err := units.RAMInBytes(value)
+				if err != nil || size <= 0 {
+					return nil, fmt.Errorf("invalid max-object-size %q for bucket %q", value, bucket)
+				}
+				limits.maxObjectSize = size
+			case "max-parts":
+				maxParts, err := strconv.Atoi(value)
+				if err != nil || maxParts <= 0 || maxParts > maxAllowedParts {
+					return nil, fmt.Errorf("invalid max-parts %q for bucket %q, must be between 1 and %d", value, bucket, maxAllowedParts)
+				}
+				limits.maxParts = maxParts
+			case "timeout":
+				timeout, err := time.ParseDuration(value)
+				if err != nil || timeout <= 0 {
+					return nil, fmt.Errorf("invalid timeout %q for bucket %q", value, bucket)
+				}
+				if timeout > requestTimeout {
+					return nil, fmt.Errorf("timeout %v for bucket %q is longer than the gateway's request timeout of %v", timeout, bucket, requestTimeout)
+				}
+				limits.timeout = timeout
+			default:
+				return nil, fmt.Errorf("unknown limit %q for bucket %q, must be one of max-object-size, max-parts or timeout", name, bucket)
+			}
+		}
+		result[bucket] = limits
+	}
+	return result, nil
+}
+
+// uploadSize returns the size of the object (or part) uploaded by 'r', or -1
+// if it's not known until the body has been read
+func uploadSize(r *http.Request) int64 {
+	if decodedLength := r.Header.Get("X-Amz-Decoded-Content-Length"); decodedLength != "" {
+		if size, err := strconv.ParseInt(decodedLength, 10, 64); err == nil {
+			return size
+		}
+	}
+	return r.ContentLength
+}
+
+// limitedBody is the body of an upload whose size isn't known in advance. It
+// fails once more than 'remaining' bytes have been read from it.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, errObjectTooLarge
+	}
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.ReadCloser.Read(p)
+	if b.remaining -= int64(n); b.remaining < 0 {
+		return 0, errObjectTooLarge
+	}
+	return n, err
+}
+
+// bucketLimitsMiddleware enforces the limits of the bucket that a request is
+// for (the bucket that its writes land in, see writeBucket): its deadline is
+// set to the bucket's timeout, and uploads that are larger than its
+// max-object-size are rejected before they're written to PFS
+func (c *controller) bucketLimitsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bucket, err := writeBucket(r, mux.Vars(r)["bucket"])
+		if err != nil {
+			next.ServeHTTP(w, r) // the handler reports the error, if it writes
+			return
+		}
+		limits, ok := c.bucketLimits[bucket]
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if limits.timeout > 0 {
+			ctx, cancel := context.WithTimeout(r.Context(), limits.timeout)
+			defer cancel()
+			r = r.WithContext(ctx)
+		}
+		if limits.maxObjectSize > 0 && r.Method == http.MethodPut && mux.Vars(r)["key"] != "" {
+			size := uploadSize(r)
+			if size > limits.maxObjectSize {
+				c.writeError(w, r, s2.EntityTooLargeError(r))
+				return
+			}
+			if size < 0 && r.Body != nil {
+				r.Body = &limitedBody{ReadCloser: r.Body, remaining: limits.maxObjectSize}
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// checkParts returns an error if a multipart upload to 'bucket' with 'parts'
+// parts is over the bucket's max-parts
+func (c *controller) checkParts(r *http.Request, bucket string, parts int) error {
+	if maxParts := c.bucketLimits[bucket].maxParts; maxParts > 0 && parts > maxParts {
+		return tooManyPartsError(r, maxParts)
+	}
+	return nil
+}
+
+// checkObjectSize returns an error if an object of 'size' bytes is over the
+// max-object-size of 'bucket'
+func (c *controller) checkObjectSize(r *http.Request, bucket string, size uint64) error {
+	if maxSize := c.bucketLimits[bucket].maxObjectSize; maxSize > 0 && size > uint64(maxSize) {
+		return s2.EntityTooLargeError(r)
+	}
+	return nil
+}
+
+// limitError returns the S3 error for 'err', if it was caused by a bucket's
+// limits (i.e. the request timed out, or its body was too large), and 'err'
+// otherwise
+func limitError(r *http.Request, err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case strings.Contains(err.Error(), errObjectTooLarge.Error()):
+		return s2.EntityTooLargeError(r)
+	case r.Context().Err() == context.DeadlineExceeded:
+		return s2.RequestTimeoutError(r)
+	}
+	return err
+}
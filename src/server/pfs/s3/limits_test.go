@@ -0,0 +1,172 @@
+package s3
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/s2"
+	"github.com/sirupsen/logrus"
+)
+
+func TestRequestLimiter(t *testing.T) {
+	// No limits
+	l := newRequestLimiter(Limits{})
+	require.True(t, l == nil)
+	_, ok := l.acquire("a")
+	require.True(t, ok)
+
+	l = newRequestLimiter(Limits{MaxRequests: 3, MaxClientRequests: 2})
+	releaseA1, ok := l.acquire("a")
+	require.True(t, ok)
+	_, ok = l.acquire("a")
+	require.True(t, ok)
+	// Client a is at its limit, but others aren't
+	_, ok = l.acquire("a")
+	require.False(t, ok)
+	releaseB, ok := l.acquire("b")
+	require.True(t, ok)
+	// And now the gateway is at its limit
+	_, ok = l.acquire("c")
+	require.False(t, ok)
+
+	releaseA1()
+	releaseB()
+	_, ok = l.acquire("c")
+	require.True(t, ok)
+	_, ok = l.acquire("a")
+	require.True(t, ok)
+	require.Equal(t, 3, l.requests)
+	require.Equal(t, 0, l.clientRequests["b"])
+}
+
+func TestLimitsConfigure(t *testing.T) {
+	server := &http.Server{}
+	require.NoError(t, Limits{}.configure(server))
+	require.True(t, server.TLSNextProto != nil)
+	require.Equal(t, 0, len(server.TLSNextProto))
+
+	server = &http.Server{}
+	require.NoError(t, Limits{HTTP2: true, IdleTimeout: time.Minute}.configure(server))
+	require.True(t, server.TLSNextProto == nil)
+	require.Equal(t, time.Minute, server.IdleTimeout)
+
+	server = &http.Server{}
+	require.NoError(t, Limits{HTTP2: true, MaxConnectionStreams: 10}.configure(server))
+	require.True(t, server.TLSNextProto["h2"] != nil)
+
+	require.YesError(t, Limits{MaxRequests: -1}.configure(&http.Server{}))
+}
+
+func TestShed(t *testing.T) {
+	c := &controller{logger: logrus.NewEntry(logrus.New())}
+	recorder := httptest.NewRecorder()
+	c.shed(recorder, httptest.NewRequest("GET", "/master.images/foo", nil))
+	require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	require.Equal(t, "1", recorder.Header().Get("Retry-After"))
+	require.True(t, strings.Contains(recorder.Body.String(), "SlowDown"))
+}
+
+func TestParseBucketLimits(t *testing.T) {
+	limits, err := parseBucketLimits("")
+	require.NoError(t, err)
+	require.Equal(t, 0, len(limits))
+
+	limits, err = parseBucketLimits("master.uploads=max-object-size:1KB;max-parts:3, master.images=timeout:2s")
+	require.NoError(t, err)
+	require.Equal(t, bucketLimits{maxObjectSize: 1024, maxParts: 3}, limits["master.uploads"])
+	require.Equal(t, bucketLimits{timeout: 2 * time.Second}, limits["master.images"])
+
+	for _, s := range []string{
+		"master.uploads",
+		"master.uploads=",
+		"master.uploads=max-parts",
+		"master.uploads=max-parts:0",
+		"master.uploads=max-parts:10001",
+		"master.uploads=max-object-size:big",
+		"master.uploads=timeout:1m",
+		"master.uploads=color:blue",
+		"master.uploads=max-parts:1,master.uploads=timeout:1s",
+	} {
+		_, err := parseBucketLimits(s)
+		require.YesError(t, err, s)
+	}
+}
+
+func TestBucketLimitsMiddleware(t *testing.T) {
+	c := &controller{
+		logger: logrus.NewEntry(logrus.New()),
+		bucketLimits: map[string]bucketLimits{
+			"master.uploads": {maxObjectSize: 4, timeout: time.Second},
+		},
+	}
+	var served *http.Request
+	handler := c.bucketLimitsMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served = r
+	}))
+	serve := func(bucket, body string, contentLength int64) *httptest.ResponseRecorder {
+		served = nil
+		r := httptest.NewRequest("PUT", "/"+bucket+"/foo", strings.NewReader(body))
+		r.ContentLength = contentLength
+		r = mux.SetURLVars(r, map[string]string{"bucket": bucket, "key": "foo"})
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, r)
+		return recorder
+	}
+
+	// Uploads within the limit are served, with the bucket's deadline
+	serve("master.uploads", "abc", 3)
+	require.True(t, served != nil)
+	_, ok := served.Context().Deadline()
+	require.True(t, ok)
+
+	// Uploads over it are rejected without being served
+	recorder := serve("master.uploads", "abcdef", 6)
+	require.True(t, served == nil)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.True(t, strings.Contains(recorder.Body.String(), "EntityTooLarge"))
+
+	// Unless their size isn't known, when their body fails once it's read
+	// past the limit
+	serve("master.uploads", "abcdef", -1)
+	require.True(t, served != nil)
+	_, err := ioutil.ReadAll(served.Body)
+	require.Equal(t, errObjectTooLarge, err)
+	serve("master.uploads", "abcd", -1)
+	body, err := ioutil.ReadAll(served.Body)
+	require.NoError(t, err)
+	require.Equal(t, "abcd", string(body))
+
+	// Other buckets aren't limited
+	serve("master.images", "abcdef", 6)
+	require.True(t, served != nil)
+	_, ok = served.Context().Deadline()
+	require.False(t, ok)
+}
+
+func TestBucketLimitErrors(t *testing.T) {
+	c := &controller{bucketLimits: map[string]bucketLimits{
+		"master.uploads": {maxObjectSize: 4, maxParts: 2},
+	}}
+	r := httptest.NewRequest("POST", "/master.uploads/foo", nil)
+	require.NoError(t, c.checkParts(r, "master.uploads", 2))
+	require.YesError(t, c.checkParts(r, "master.uploads", 3))
+	require.NoError(t, c.checkParts(r, "master.images", 3))
+	require.NoError(t, c.checkObjectSize(r, "master.uploads", 4))
+	require.YesError(t, c.checkObjectSize(r, "master.uploads", 5))
+
+	require.Equal(t, "EntityTooLarge", limitError(r, errors.New("rpc error: "+errObjectTooLarge.Error())).(*s2.Error).Code)
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	<-ctx.Done()
+	require.Equal(t, "RequestTimeout", limitError(r.WithContext(ctx), errors.New("context deadline exceeded")).(*s2.Error).Code)
+	other := errors.New("other")
+	require.Equal(t, other, limitError(r, other))
+}
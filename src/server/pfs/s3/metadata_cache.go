@@ -0,0 +1,325 @@
+package s3
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	pfsClient "github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxCachedBranches is the most branches whose metadata is cached, each
+	// of which has a commit subscription open while it's cached
+	maxCachedBranches = 1000
+	// metadataCacheIdle is how long a branch's metadata stays cached (and its
+	// subscription open) after it was last read, once the cache is full
+	metadataCacheIdle = 5 * time.Minute
+)
+
+// metadataCache holds the results of the InspectBranch and InspectFile calls
+// that objects are read with, so that clients that HEAD the same objects over
+// and over (e.g. Hadoop's FileSystem checks) don't each cost pachd RPCs. The
+// entries of a branch are invalidated when a commit is started on it, which
+// the cache finds out about from a commit subscription, or when the gateway
+// writes to it, and they expire after the cache's TTL in any case (e.g. if
+// the branch is moved to an older commit).
+//
+// Entries are keyed by access key as well, so that one client's reads are
+// never answered with another's. A nil *metadataCache doesn't cache anything.
+type metadataCache struct {
+	ttl    time.Duration
+	logger *logrus.Entry
+	// watch calls 'f' each time a commit is started on the branch 'branch' of
+	// 'repo' after the commit 'from' (which may be empty), until 'ctx' is
+	// cancelled or it fails
+	watch func(ctx context.Context, accessKey, repo, branch, from string, f func()) error
+
+	mu sync.Mutex
+	// branches are keyed by repo and branch
+	branches map[string]*cachedBranch
+}
+
+type cachedBranch struct {
+	cancel   context.CancelFunc
+	lastRead time.Time
+	// infos are keyed by access key
+	infos map[string]cachedBranchInfo
+	files map[cachedFileKey]cachedFileInfo
+}
+
+type cachedBranchInfo struct {
+	info    *pfsClient.BranchInfo
+	expires time.Time
+}
+
+type cachedFileKey struct {
+	accessKey string
+	// commit is the branch name or commit ID that the file was read at
+	commit string
+	path   string
+}
+
+type cachedFileInfo struct {
+	info    *pfsClient.FileInfo
+	expires time.Time
+}
+
+// newMetadataCache returns a cache whose entries expire after 'ttl', or nil
+// if 'ttl' isn't positive
+func newMetadataCache(ttl time.Duration, logger *logrus.Entry, watch func(ctx context.Context, accessKey, repo, branch, from string, f func()) error) *metadataCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &metadataCache{
+		ttl:      ttl,
+		logger:   logger,
+		watch:    watch,
+		branches: make(map[string]*cachedBranch),
+	}
+}
+
+func branchKey(repo, branch string) string {
+	return repo + "/" + branch
+}
+
+// branch returns the cached entries of a branch, if there are any. m.mu must
+// be held.
+func (m *metadataCache) branch(repo, branch string) *cachedBranch {
+	b, ok := m.branches[branchKey(repo, branch)]
+	if !ok {
+		return nil
+	}
+	b.lastRead = time.Now()
+	return b
+}
+
+// branchInfo returns the cached BranchInfo of a branch, or nil. It mustn't be
+// modified.
+func (m *metadataCache) branchInfo(accessKey, repo, branch string) *pfsClient.BranchInfo {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b := m.branch(repo, branch)
+	if b == nil {
+		return nil
+	}
+	entry, ok := b.infos[accessKey]
+	if !ok || time.Now().After(entry.expires) {
+		return nil
+	}
+	return entry.info
+}
+
+// putBranchInfo caches the BranchInfo of a branch, and starts watching the
+// branch for new commits if it isn't watched already
+func (m *metadataCache) putBranchInfo(accessKey, repo, branch string, info *pfsClient.BranchInfo) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b := m.branch(repo, branch)
+	if b == nil {
+		if len(m.branches) >= maxCachedBranches {
+			m.evictIdle()
+			if len(m.branches) >= maxCachedBranches {
+				return
+			}
+		}
+		from := ""
+		if info.Head != nil {
+			from = info.Head.ID
+		}
+		b = m.startWatch(accessKey, repo, branch, from)
+	}
+	b.infos[accessKey] = cachedBranchInfo{info: info, expires: time.Now().Add(m.ttl)}
+}
+
+// fileInfo returns the cached FileInfo of a file, or nil. It mustn't be
+// modified.
+func (m *metadataCache) fileInfo(accessKey, repo, branch, commit, path string) *pfsClient.FileInfo {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b := m.branch(repo, branch)
+	if b == nil {
+		return nil
+	}
+	entry, ok := b.files[cachedFileKey{accessKey: accessKey, commit: commit, path: path}]
+	if !ok || time.Now().After(entry.expires) {
+		return nil
+	}
+	return entry.info
+}
+
+// putFileInfo caches the FileInfo of a file of a branch whose BranchInfo is
+// cached. Files in open commits aren't cached, since they change without a
+// new commit being started.
+func (m *metadataCache) putFileInfo(accessKey, repo, branch, commit, path string, info *pfsClient.FileInfo) {
+	if m == nil || info.Committed == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b := m.branch(repo, branch)
+	if b == nil {
+		return
+	}
+	b.files[cachedFileKey{accessKey: accessKey, commit: commit, path: path}] = cachedFileInfo{info: info, expires: time.Now().Add(m.ttl)}
+}
+
+// invalidate drops the cached entries of a branch, which is still watched
+func (m *metadataCache) invalidate(repo, branch string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if b, ok := m.branches[branchKey(repo, branch)]; ok {
+		b.infos = make(map[string]cachedBranchInfo)
+		b.files = make(map[cachedFileKey]cachedFileInfo)
+	}
+}
+
+// startWatch adds a branch to the cache, and watches it (with the access key
+// that it was first read with) until it's evicted. If the watch fails (e.g.
+// because the branch was deleted), the branch is dropped from the cache,
+// since its entries can't be invalidated anymore. m.mu must be held.
+func (m *metadataCache) startWatch(accessKey, repo, branch, from string) *cachedBranch {
+	ctx, cancel := context.WithCancel(context.Background())
+	b := &cachedBranch{
+		cancel:   cancel,
+		lastRead: time.Now(),
+		infos:    make(map[string]cachedBranchInfo),
+		files:    make(map[cachedFileKey]cachedFileInfo),
+	}
+	key := branchKey(repo, branch)
+	m.branches[key] = b
+	go func() {
+		err := m.watch(ctx, accessKey, repo, branch, from, func() {
+			m.invalidate(repo, branch)
+		})
+		if err != nil && ctx.Err() == nil && m.logger != nil {
+			m.logger.Debugf("stopped caching the metadata of %s: %v", key, err)
+		}
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if m.branches[key] == b {
+			delete(m.branches, key)
+		}
+		cancel()
+	}()
+	return b
+}
+
+// evictIdle drops the branches that haven't been read for metadataCacheIdle.
+// m.mu must be held.
+func (m *metadataCache) evictIdle() {
+	for key, b := range m.branches {
+		if time.Since(b.lastRead) >= metadataCacheIdle {
+			b.cancel()
+			delete(m.branches, key)
+		}
+	}
+}
+
+// watchBranch is the metadataCache's watch, it subscribes to the commits of
+// the branch
+func (c *controller) watchBranch(ctx context.Context, accessKey, repo, branch, from string, f func()) error {
+	pc, err := c.pachClient(accessKey)
+	if err != nil {
+		return err
+	}
+	defer pc.Close()
+	return pc.WithCtx(ctx).SubscribeCommitF(repo, branch, nil, from, pfsClient.CommitState_STARTED, func(*pfsClient.CommitInfo) error {
+		f()
+		return nil
+	})
+}
+
+// inspectBranch is InspectBranch, answered from the metadata cache if it can
+// be
+func (c *controller) inspectBranch(r *http.Request, pc *client.APIClient, repo, branch string) (*pfsClient.BranchInfo, error) {
+	accessKey := mux.Vars(r)["authAccessKey"]
+	if branchInfo := c.metadata.branchInfo(accessKey, repo, branch); branchInfo != nil {
+		return branchInfo, nil
+	}
+	branchInfo, err := pc.InspectBranch(repo, branch)
+	if err != nil {
+		return nil, err
+	}
+	c.metadata.putBranchInfo(accessKey, repo, branch, branchInfo)
+	return branchInfo, nil
+}
+
+// inspectObject is InspectFileChecksums of the file 'file' at 'commit' (the
+// branch 'branch' or one of its commits), with the gateway's checksums,
+// answered from the metadata cache if it can be
+func (c *controller) inspectObject(r *http.Request, pc *client.APIClient, repo, branch, commit, file string) (*pfsClient.FileInfo, error) {
+	accessKey := mux.Vars(r)["authAccessKey"]
+	if fileInfo := c.metadata.fileInfo(accessKey, repo, branch, commit, file); fileInfo != nil {
+		return fileInfo, nil
+	}
+	fileInfo, err := pc.InspectFileChecksums(repo, commit, file, c.checksums...)
+	if err != nil {
+		return nil, err
+	}
+	c.metadata.putFileInfo(accessKey, repo, branch, commit, file, fileInfo)
+	return fileInfo, nil
+}
+
+// lazyFileReader reads a file whose size is already known, and only starts
+// reading it once its content is read, so that HEAD requests (which
+// http.ServeContent only seeks) and conditional GETs don't read files at all
+type lazyFileReader struct {
+	open   func() (io.ReadSeeker, error)
+	size   int64
+	offset int64
+	r      io.ReadSeeker
+}
+
+func (l *lazyFileReader) Read(p []byte) (int, error) {
+	if l.r == nil {
+		r, err := l.open()
+		if err != nil {
+			return 0, err
+		}
+		if _, err := r.Seek(l.offset, io.SeekStart); err != nil {
+			return 0, err
+		}
+		l.r = r
+	}
+	return l.r.Read(p)
+}
+
+func (l *lazyFileReader) Seek(offset int64, whence int) (int64, error) {
+	if l.r != nil {
+		return l.r.Seek(offset, whence)
+	}
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += l.offset
+	case io.SeekEnd:
+		offset += l.size
+	default:
+		return 0, fmt.Errorf("invalid whence %d", whence)
+	}
+	if offset < 0 {
+		return 0, fmt.Errorf("invalid offset %d", offset)
+	}
+	l.offset = offset
+	return offset, nil
+}
@@ -0,0 +1,122 @@
+package s3
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/pachyderm/pachyderm/src/client"
+	pfsClient "github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestMetadataCache(t *testing.T) {
+	started := make(chan func(), 1)
+	stop := make(chan error)
+	m := newMetadataCache(time.Minute, nil, func(ctx context.Context, accessKey, repo, branch, from string, f func()) error {
+		require.Equal(t, "alice", accessKey)
+		require.Equal(t, "head", from)
+		started <- f
+		select {
+		case err := <-stop:
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	require.True(t, newMetadataCache(0, nil, nil) == nil)
+
+	branchInfo := &pfsClient.BranchInfo{Head: client.NewCommit("images", "head")}
+	m.putBranchInfo("alice", "images", "master", branchInfo)
+	newCommit := <-started
+	require.Equal(t, branchInfo, m.branchInfo("alice", "images", "master"))
+	// Entries are only returned to the access key that they were read with
+	require.True(t, m.branchInfo("bob", "images", "master") == nil)
+	require.True(t, m.branchInfo("alice", "images", "staging") == nil)
+
+	// Files in open commits aren't cached
+	m.putFileInfo("alice", "images", "master", "master", "/open", &pfsClient.FileInfo{})
+	require.True(t, m.fileInfo("alice", "images", "master", "master", "/open") == nil)
+	fileInfo := &pfsClient.FileInfo{Committed: types.TimestampNow()}
+	m.putFileInfo("alice", "images", "master", "master", "/foo", fileInfo)
+	require.Equal(t, fileInfo, m.fileInfo("alice", "images", "master", "master", "/foo"))
+	require.True(t, m.fileInfo("alice", "images", "master", "head", "/foo") == nil)
+
+	// A new commit invalidates the branch's entries
+	newCommit()
+	require.True(t, m.branchInfo("alice", "images", "master") == nil)
+	require.True(t, m.fileInfo("alice", "images", "master", "master", "/foo") == nil)
+	m.putBranchInfo("alice", "images", "master", branchInfo)
+	require.Equal(t, branchInfo, m.branchInfo("alice", "images", "master"))
+
+	// Entries expire
+	m.ttl = time.Nanosecond
+	m.putBranchInfo("alice", "images", "master", branchInfo)
+	time.Sleep(time.Millisecond)
+	require.True(t, m.branchInfo("alice", "images", "master") == nil)
+
+	// Once the branch stops being watched, nothing is cached for it until
+	// it's watched again
+	m.ttl = time.Minute
+	stop <- errors.New("branch deleted")
+	require.NoErrorWithinT(t, time.Second, func() error {
+		for {
+			m.mu.Lock()
+			n := len(m.branches)
+			m.mu.Unlock()
+			if n == 0 {
+				return nil
+			}
+			time.Sleep(time.Millisecond)
+		}
+	})
+	m.putFileInfo("alice", "images", "master", "master", "/foo", fileInfo)
+	require.True(t, m.fileInfo("alice", "images", "master", "master", "/foo") == nil)
+	m.putBranchInfo("alice", "images", "master", branchInfo)
+	<-started
+	require.Equal(t, branchInfo, m.branchInfo("alice", "images", "master"))
+}
+
+func TestMetadataCacheNil(t *testing.T) {
+	var m *metadataCache
+	m.putBranchInfo("", "images", "master", &pfsClient.BranchInfo{})
+	require.True(t, m.branchInfo("", "images", "master") == nil)
+	m.putFileInfo("", "images", "master", "master", "/foo", &pfsClient.FileInfo{Committed: types.TimestampNow()})
+	require.True(t, m.fileInfo("", "images", "master", "master", "/foo") == nil)
+	m.invalidate("images", "master")
+}
+
+func TestLazyFileReader(t *testing.T) {
+	var opened int
+	l := &lazyFileReader{
+		open: func() (io.ReadSeeker, error) {
+			opened++
+			return strings.NewReader("foobar"), nil
+		},
+		size: 6,
+	}
+	// Seeking (as http.ServeContent does for HEAD requests) doesn't open the
+	// file
+	size, err := l.Seek(0, io.SeekEnd)
+	require.NoError(t, err)
+	require.Equal(t, int64(6), size)
+	_, err = l.Seek(3, io.SeekStart)
+	require.NoError(t, err)
+	require.Equal(t, 0, opened)
+
+	content, err := ioutil.ReadAll(l)
+	require.NoError(t, err)
+	require.Equal(t, "bar", string(content))
+	require.Equal(t, 1, opened)
+	_, err = l.Seek(0, io.SeekStart)
+	require.NoError(t, err)
+	content, err = ioutil.ReadAll(l)
+	require.NoError(t, err)
+	require.Equal(t, "foobar", string(content))
+	require.Equal(t, 1, opened)
+}
@@ -11,10 +11,8 @@ import (
 	"strings"
 
 	"github.com/gogo/protobuf/types"
-	"github.com/gorilla/mux"
 	"github.com/pachyderm/pachyderm/src/client"
 	pfsClient "github.com/pachyderm/pachyderm/src/client/pfs"
-	pfsServer "github.com/pachyderm/pachyderm/src/server/pfs"
 	"github.com/pachyderm/pachyderm/src/server/pkg/errutil"
 	"github.com/pachyderm/pachyderm/src/server/pkg/uuid"
 	"github.com/pachyderm/s2"
@@ -88,8 +86,14 @@ func (c *controller) ensureRepo(pc *client.APIClient) error {
 }
 
 func (c *controller) ListMultipart(r *http.Request, bucket, keyMarker, uploadIDMarker string, maxUploads int) (*s2.ListMultipartResult, error) {
-	vars := mux.Vars(r)
-	pc, err := c.pachClient(vars["authAccessKey"])
+	bucket, err := writeBucket(r, bucket)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.canRead(r, bucket); err != nil {
+		return nil, err
+	}
+	pc, err := c.requestClient(r)
 	if err != nil {
 		return nil, err
 	}
@@ -110,7 +114,7 @@ func (c *controller) ListMultipart(r *http.Request, bucket, keyMarker, uploadIDM
 	}
 
 	globPattern := path.Join(repo, branch, "*", "*", ".keep")
-	err = pc.GlobFileF(c.repo, "master", globPattern, func(fileInfo *pfsClient.FileInfo) error {
+	err = pc.GlobFileSizeOnlyF(c.repo, "master", globPattern, func(fileInfo *pfsClient.FileInfo) error {
 		_, _, key, uploadID, err := multipartKeepArgs(fileInfo.File.Path)
 		if err != nil {
 			return nil
@@ -147,8 +151,14 @@ func (c *controller) ListMultipart(r *http.Request, bucket, keyMarker, uploadIDM
 }
 
 func (c *controller) InitMultipart(r *http.Request, bucket, key string) (string, error) {
-	vars := mux.Vars(r)
-	pc, err := c.pachClient(vars["authAccessKey"])
+	bucket, err := writeBucket(r, bucket)
+	if err != nil {
+		return "", err
+	}
+	if err := c.canWrite(r, bucket); err != nil {
+		return "", err
+	}
+	pc, err := c.requestClient(r)
 	if err != nil {
 		return "", err
 	}
@@ -175,8 +185,14 @@ func (c *controller) InitMultipart(r *http.Request, bucket, key string) (string,
 }
 
 func (c *controller) AbortMultipart(r *http.Request, bucket, key, uploadID string) error {
-	vars := mux.Vars(r)
-	pc, err := c.pachClient(vars["authAccessKey"])
+	bucket, err := writeBucket(r, bucket)
+	if err != nil {
+		return err
+	}
+	if err := c.canWrite(r, bucket); err != nil {
+		return err
+	}
+	pc, err := c.requestClient(r)
 	if err != nil {
 		return err
 	}
@@ -206,8 +222,17 @@ func (c *controller) AbortMultipart(r *http.Request, bucket, key, uploadID strin
 }
 
 func (c *controller) CompleteMultipart(r *http.Request, bucket, key, uploadID string, parts []s2.Part) (*s2.CompleteMultipartResult, error) {
-	vars := mux.Vars(r)
-	pc, err := c.pachClient(vars["authAccessKey"])
+	bucket, err := writeBucket(r, bucket)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.canWrite(r, bucket); err != nil {
+		return nil, err
+	}
+	if err := c.checkParts(r, bucket, len(parts)); err != nil {
+		return nil, err
+	}
+	pc, err := c.requestClient(r)
 	if err != nil {
 		return nil, err
 	}
@@ -225,35 +250,25 @@ func (c *controller) CompleteMultipart(r *http.Request, bucket, key, uploadID st
 
 	_, err = pc.InspectFile(c.repo, "master", keepPath(repo, branch, key, uploadID))
 	if err != nil {
-		if pfsServer.IsFileNotFoundErr(err) {
+		if pfsClient.IsFileNotFoundErr(err) {
 			return nil, s2.NoSuchUploadError(r)
 		}
 		return nil, err
 	}
 
-	// check if the destination file already exists, and if so, delete it
-	_, err = pc.InspectFile(repo, branch, key)
-	if err != nil && !pfsServer.IsFileNotFoundErr(err) && !pfsServer.IsNoHeadErr(err) {
-		return nil, err
-	} else if err == nil {
-		err = pc.DeleteFile(repo, branch, key)
-		if err != nil {
-			if errutil.IsWriteToOutputBranchError(err) {
-				return nil, writeToOutputBranchError(r)
-			}
-			return nil, err
-		}
-	}
-
+	// the parts are checked before the destination is touched, so that an
+	// upload that's rejected doesn't leave part of an object behind
+	var size uint64
+	srcPaths := make([]string, len(parts))
 	for i, part := range parts {
-		srcPath := chunkPath(repo, branch, key, uploadID, part.PartNumber)
+		srcPaths[i] = chunkPath(repo, branch, key, uploadID, part.PartNumber)
 
-		fileInfo, err := pc.InspectFile(c.repo, "master", srcPath)
+		fileInfo, err := pc.InspectFile(c.repo, "master", srcPaths[i])
 		if err != nil {
-			if pfsServer.IsFileNotFoundErr(err) {
+			if pfsClient.IsFileNotFoundErr(err) {
 				return nil, s2.NoSuchUploadError(r)
 			}
-			return nil, err
+			return nil, limitError(r, err)
 		}
 
 		// Only verify the ETag when it's of the same length as PFS file
@@ -270,7 +285,22 @@ func (c *controller) CompleteMultipart(r *http.Request, bucket, key, uploadID st
 			return nil, s2.EntityTooSmallError(r)
 		}
 
-		err = pc.CopyFile(c.repo, "master", srcPath, repo, branch, key, false)
+		size += fileInfo.SizeBytes
+		if err := c.checkObjectSize(r, bucket, size); err != nil {
+			return nil, err
+		}
+	}
+
+	// The destination is changed from here on, even if the upload fails
+	// part-way through
+	defer c.metadata.invalidate(repo, branch)
+
+	// check if the destination file already exists, and if so, delete it
+	_, err = pc.InspectFile(repo, branch, key)
+	if err != nil && !pfsClient.IsFileNotFoundErr(err) && !pfsClient.IsBranchNoHeadErr(err) {
+		return nil, err
+	} else if err == nil {
+		err = pc.DeleteFile(repo, branch, key)
 		if err != nil {
 			if errutil.IsWriteToOutputBranchError(err) {
 				return nil, writeToOutputBranchError(r)
@@ -279,13 +309,26 @@ func (c *controller) CompleteMultipart(r *http.Request, bucket, key, uploadID st
 		}
 	}
 
+	for _, srcPath := range srcPaths {
+		err = pc.CopyFile(c.repo, "master", srcPath, repo, branch, key, false)
+		if err != nil {
+			if errutil.IsWriteToOutputBranchError(err) {
+				return nil, writeToOutputBranchError(r)
+			}
+			return nil, limitError(r, err)
+		}
+	}
+
 	err = pc.DeleteFile(c.repo, "master", parentDirPath(repo, branch, key, uploadID))
 	if err != nil {
 		return nil, err
 	}
+	if err := c.waitForWrite(r, pc, bucket, repo, branch); err != nil {
+		return nil, err
+	}
 
 	fileInfo, err := pc.InspectFile(repo, branch, key)
-	if err != nil && !pfsServer.IsOutputCommitNotFinishedErr(err) {
+	if err != nil && !pfsClient.IsOutputCommitNotFinishedErr(err) {
 		return nil, err
 	}
 
@@ -299,8 +342,14 @@ func (c *controller) CompleteMultipart(r *http.Request, bucket, key, uploadID st
 }
 
 func (c *controller) ListMultipartChunks(r *http.Request, bucket, key, uploadID string, partNumberMarker, maxParts int) (*s2.ListMultipartChunksResult, error) {
-	vars := mux.Vars(r)
-	pc, err := c.pachClient(vars["authAccessKey"])
+	bucket, err := writeBucket(r, bucket)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.canRead(r, bucket); err != nil {
+		return nil, err
+	}
+	pc, err := c.requestClient(r)
 	if err != nil {
 		return nil, err
 	}
@@ -324,7 +373,7 @@ func (c *controller) ListMultipartChunks(r *http.Request, bucket, key, uploadID
 	}
 
 	globPattern := path.Join(parentDirPath(repo, branch, key, uploadID), "*")
-	err = pc.GlobFileF(c.repo, "master", globPattern, func(fileInfo *pfsClient.FileInfo) error {
+	err = pc.GlobFileSizeOnlyF(c.repo, "master", globPattern, func(fileInfo *pfsClient.FileInfo) error {
 		_, _, _, _, partNumber, err := multipartChunkArgs(fileInfo.File.Path)
 		if err != nil {
 			return nil
@@ -353,8 +402,17 @@ func (c *controller) ListMultipartChunks(r *http.Request, bucket, key, uploadID
 }
 
 func (c *controller) UploadMultipartChunk(r *http.Request, bucket, key, uploadID string, partNumber int, reader io.Reader) (string, error) {
-	vars := mux.Vars(r)
-	pc, err := c.pachClient(vars["authAccessKey"])
+	bucket, err := writeBucket(r, bucket)
+	if err != nil {
+		return "", err
+	}
+	if err := c.canWrite(r, bucket); err != nil {
+		return "", err
+	}
+	if err := c.checkParts(r, bucket, partNumber); err != nil {
+		return "", err
+	}
+	pc, err := c.requestClient(r)
 	if err != nil {
 		return "", err
 	}
@@ -373,7 +431,7 @@ func (c *controller) UploadMultipartChunk(r *http.Request, bucket, key, uploadID
 
 	_, err = pc.InspectFile(c.repo, "master", keepPath(repo, branch, key, uploadID))
 	if err != nil {
-		if pfsServer.IsFileNotFoundErr(err) {
+		if pfsClient.IsFileNotFoundErr(err) {
 			return "", s2.NoSuchUploadError(r)
 		}
 		return "", err
@@ -382,7 +440,7 @@ func (c *controller) UploadMultipartChunk(r *http.Request, bucket, key, uploadID
 	path := chunkPath(repo, branch, key, uploadID, partNumber)
 	_, err = pc.PutFileOverwrite(c.repo, "master", path, reader, 0)
 	if err != nil {
-		return "", err
+		return "", limitError(r, err)
 	}
 
 	fileInfo, err := pc.InspectFile(c.repo, "master", path)
@@ -394,8 +452,14 @@ func (c *controller) UploadMultipartChunk(r *http.Request, bucket, key, uploadID
 }
 
 func (c *controller) DeleteMultipartChunk(r *http.Request, bucket, key, uploadID string, partNumber int) error {
-	vars := mux.Vars(r)
-	pc, err := c.pachClient(vars["authAccessKey"])
+	bucket, err := writeBucket(r, bucket)
+	if err != nil {
+		return err
+	}
+	if err := c.canWrite(r, bucket); err != nil {
+		return err
+	}
+	pc, err := c.requestClient(r)
 	if err != nil {
 		return err
 	}
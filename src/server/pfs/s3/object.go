@@ -1,22 +1,24 @@
 package s3
 
 import (
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
 
 	"github.com/gogo/protobuf/types"
-	"github.com/gorilla/mux"
 	pfsClient "github.com/pachyderm/pachyderm/src/client/pfs"
-	pfsServer "github.com/pachyderm/pachyderm/src/server/pfs"
 	"github.com/pachyderm/pachyderm/src/server/pkg/errutil"
 	"github.com/pachyderm/s2"
 )
 
 func (c *controller) GetObject(r *http.Request, bucket, file, version string) (*s2.GetObjectResult, error) {
-	vars := mux.Vars(r)
-	pc, err := c.pachClient(vars["authAccessKey"])
+	if err := c.canRead(r, bucket); err != nil {
+		return nil, err
+	}
+	pc, err := c.requestClient(r)
 	if err != nil {
 		return nil, err
 	}
@@ -25,7 +27,7 @@ func (c *controller) GetObject(r *http.Request, bucket, file, version string) (*
 		return nil, err
 	}
 
-	branchInfo, err := pc.InspectBranch(repo, branch)
+	branchInfo, err := c.inspectBranch(r, pc, repo, branch)
 	if err != nil {
 		return nil, maybeNotFoundError(r, err)
 	}
@@ -49,7 +51,7 @@ func (c *controller) GetObject(r *http.Request, bucket, file, version string) (*
 		commitID = commitInfo.Commit.ID
 	}
 
-	fileInfo, err := pc.InspectFile(branchInfo.Branch.Repo.Name, commitID, file)
+	fileInfo, err := c.inspectObject(r, pc, branchInfo.Branch.Repo.Name, branch, commitID, file)
 	if err != nil {
 		return nil, maybeNotFoundError(r, err)
 	}
@@ -59,15 +61,17 @@ func (c *controller) GetObject(r *http.Request, bucket, file, version string) (*
 		return nil, err
 	}
 
-	content, err := pc.GetFileReadSeeker(branchInfo.Branch.Repo.Name, commitID, file)
-	if err != nil {
-		return nil, err
+	content := &lazyFileReader{
+		open: func() (io.ReadSeeker, error) {
+			return pc.GetFileReadSeeker(branchInfo.Branch.Repo.Name, commitID, file)
+		},
+		size: int64(fileInfo.SizeBytes),
 	}
 
 	result := s2.GetObjectResult{
 		ModTime:      modTime,
 		Content:      content,
-		ETag:         fmt.Sprintf("%x", fileInfo.Hash),
+		ETag:         c.objectETag(r, fileInfo),
 		Version:      commitID,
 		DeleteMarker: false,
 	}
@@ -76,8 +80,14 @@ func (c *controller) GetObject(r *http.Request, bucket, file, version string) (*
 }
 
 func (c *controller) PutObject(r *http.Request, bucket, file string, reader io.Reader) (*s2.PutObjectResult, error) {
-	vars := mux.Vars(r)
-	pc, err := c.pachClient(vars["authAccessKey"])
+	bucket, err := writeBucket(r, bucket)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.canWrite(r, bucket); err != nil {
+		return nil, err
+	}
+	pc, err := c.requestClient(r)
 	if err != nil {
 		return nil, err
 	}
@@ -94,22 +104,26 @@ func (c *controller) PutObject(r *http.Request, bucket, file string, reader io.R
 		return nil, invalidFilePathError(r)
 	}
 
-	_, err = pc.PutFileOverwrite(branchInfo.Branch.Repo.Name, branchInfo.Branch.Name, file, reader, 0)
+	_, err = pc.PutFileOverwriteChecksums(branchInfo.Branch.Repo.Name, branchInfo.Branch.Name, file, reader, 0, c.checksums...)
 	if err != nil {
 		if errutil.IsWriteToOutputBranchError(err) {
 			return nil, writeToOutputBranchError(r)
 		}
+		return nil, limitError(r, err)
+	}
+	c.metadata.invalidate(repo, branch)
+	if err := c.waitForWrite(r, pc, bucket, branchInfo.Branch.Repo.Name, branchInfo.Branch.Name); err != nil {
 		return nil, err
 	}
 
-	fileInfo, err := pc.InspectFile(branchInfo.Branch.Repo.Name, branchInfo.Branch.Name, file)
-	if err != nil && !pfsServer.IsOutputCommitNotFinishedErr(err) {
+	fileInfo, err := pc.InspectFileChecksums(branchInfo.Branch.Repo.Name, branchInfo.Branch.Name, file, c.checksums...)
+	if err != nil && !pfsClient.IsOutputCommitNotFinishedErr(err) {
 		return nil, err
 	}
 
 	result := s2.PutObjectResult{}
 	if fileInfo != nil {
-		result.ETag = fmt.Sprintf("%x", fileInfo.Hash)
+		result.ETag = c.objectETag(r, fileInfo)
 		result.Version = fileInfo.File.Commit.ID
 	}
 
@@ -117,8 +131,14 @@ func (c *controller) PutObject(r *http.Request, bucket, file string, reader io.R
 }
 
 func (c *controller) DeleteObject(r *http.Request, bucket, file, version string) (*s2.DeleteObjectResult, error) {
-	vars := mux.Vars(r)
-	pc, err := c.pachClient(vars["authAccessKey"])
+	bucket, err := writeBucket(r, bucket)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.canWrite(r, bucket); err != nil {
+		return nil, err
+	}
+	pc, err := c.requestClient(r)
 	if err != nil {
 		return nil, err
 	}
@@ -147,6 +167,10 @@ func (c *controller) DeleteObject(r *http.Request, bucket, file, version string)
 		}
 		return nil, maybeNotFoundError(r, err)
 	}
+	c.metadata.invalidate(repo, branch)
+	if err := c.waitForWrite(r, pc, bucket, branchInfo.Branch.Repo.Name, branchInfo.Branch.Name); err != nil {
+		return nil, err
+	}
 
 	result := s2.DeleteObjectResult{
 		Version:      "",
@@ -155,3 +179,21 @@ func (c *controller) DeleteObject(r *http.Request, bucket, file, version string)
 
 	return &result, nil
 }
+
+// objectETag returns the ETag of the object whose file is 'fileInfo', and
+// sets the checksum headers of the response to 'r'. If the gateway reports
+// md5 checksums, the ETag is the file's md5 checksum, as it is in S3 (for
+// objects that weren't uploaded in parts). Otherwise, it's the file's PFS
+// hash.
+func (c *controller) objectETag(r *http.Request, fileInfo *pfsClient.FileInfo) string {
+	etag := fmt.Sprintf("%x", fileInfo.Hash)
+	for _, checksum := range fileInfo.Checksums {
+		switch checksum.Algorithm {
+		case pfsClient.ChecksumAlgorithm_MD5:
+			etag = hex.EncodeToString(checksum.Value)
+		case pfsClient.ChecksumAlgorithm_SHA256:
+			responseHeader(r).Set("x-amz-checksum-sha256", base64.StdEncoding.EncodeToString(checksum.Value))
+		}
+	}
+	return etag
+}
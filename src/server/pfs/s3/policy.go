@@ -0,0 +1,74 @@
+package s3
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pachyderm/s2"
+)
+
+// bucketPolicy restricts the operations that S3 clients may perform on a
+// bucket
+type bucketPolicy int
+
+const (
+	// policyReadWrite allows every operation. Buckets without a configured
+	// policy are read-write.
+	policyReadWrite bucketPolicy = iota
+	// policyReadOnly allows reading and listing objects, but not creating,
+	// modifying or deleting them (or the bucket)
+	policyReadOnly
+	// policyWriteOnly allows creating, overwriting and deleting objects, but
+	// not reading or listing them
+	policyWriteOnly
+)
+
+var bucketPolicyNames = map[string]bucketPolicy{
+	"read-write": policyReadWrite,
+	"read-only":  policyReadOnly,
+	"write-only": policyWriteOnly,
+}
+
+// parseBucketPolicies parses a comma-separated list of `<bucket>=<policy>`
+// pairs, e.g. "master.images=read-only,master.uploads=write-only"
+func parseBucketPolicies(s string) (map[string]bucketPolicy, error) {
+	policies := make(map[string]bucketPolicy)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid bucket policy %q, expected <bucket>=<policy>", pair)
+		}
+		policy, ok := bucketPolicyNames[parts[1]]
+		if !ok {
+			return nil, fmt.Errorf("invalid bucket policy %q for bucket %q, must be one of read-write, read-only or write-only", parts[1], parts[0])
+		}
+		if _, ok := policies[parts[0]]; ok {
+			return nil, fmt.Errorf("bucket %q has more than one policy", parts[0])
+		}
+		policies[parts[0]] = policy
+	}
+	return policies, nil
+}
+
+// canRead returns an error if the bucket's policy doesn't allow reading or
+// listing its objects
+func (c *controller) canRead(r *http.Request, bucket string) error {
+	if c.policies[bucket] == policyWriteOnly {
+		return s2.AccessDeniedError(r)
+	}
+	return nil
+}
+
+// canWrite returns an error if the bucket's policy doesn't allow modifying it
+// or its objects
+func (c *controller) canWrite(r *http.Request, bucket string) error {
+	if c.policies[bucket] == policyReadOnly {
+		return s2.AccessDeniedError(r)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package s3
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/s2"
+)
+
+func TestParseBucketPolicies(t *testing.T) {
+	policies, err := parseBucketPolicies("")
+	require.NoError(t, err)
+	require.Equal(t, 0, len(policies))
+
+	policies, err = parseBucketPolicies("master.images=read-only, master.uploads=write-only,dev.images=read-write")
+	require.NoError(t, err)
+	require.Equal(t, map[string]bucketPolicy{
+		"master.images":  policyReadOnly,
+		"master.uploads": policyWriteOnly,
+		"dev.images":     policyReadWrite,
+	}, policies)
+
+	_, err = parseBucketPolicies("master.images")
+	require.YesError(t, err)
+	_, err = parseBucketPolicies("=read-only")
+	require.YesError(t, err)
+	_, err = parseBucketPolicies("master.images=append-only")
+	require.YesError(t, err)
+	_, err = parseBucketPolicies("master.images=read-only,master.images=write-only")
+	require.YesError(t, err)
+}
+
+func TestBucketPolicyEnforcement(t *testing.T) {
+	c := &controller{policies: map[string]bucketPolicy{
+		"master.images":  policyReadOnly,
+		"master.uploads": policyWriteOnly,
+	}}
+	r := httptest.NewRequest("GET", "/master.images/foo", nil)
+	requireAccessDenied := func(err error) {
+		t.Helper()
+		s2Err, ok := err.(*s2.Error)
+		require.True(t, ok)
+		require.Equal(t, http.StatusForbidden, s2Err.HTTPStatus)
+	}
+
+	require.NoError(t, c.canRead(r, "master.images"))
+	requireAccessDenied(c.canWrite(r, "master.images"))
+
+	requireAccessDenied(c.canRead(r, "master.uploads"))
+	require.NoError(t, c.canWrite(r, "master.uploads"))
+
+	// listing multipart uploads and their parts is reading
+	_, err := c.ListMultipart(r, "master.uploads", "", "", 1000)
+	requireAccessDenied(err)
+	_, err = c.ListMultipartChunks(r, "master.uploads", "foo", "upload", 0, 1000)
+	requireAccessDenied(err)
+
+	// so is looking up the objects of a bucket website
+	_, err = c.inspectWebsiteObject(r, "master.uploads", "index.html")
+	requireAccessDenied(err)
+	require.False(t, c.objectExists(r, "master.uploads", "index.html"))
+
+	// buckets without a policy are read-write
+	require.NoError(t, c.canRead(r, "master.other"))
+	require.NoError(t, c.canWrite(r, "master.other"))
+}
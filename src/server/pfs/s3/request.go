@@ -0,0 +1,132 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/pachyderm/s2"
+	"github.com/sirupsen/logrus"
+)
+
+type ctxKey int
+
+const (
+	requestIDKey ctxKey = iota
+	responseHeaderKey
+	writeBucketKey
+)
+
+// The maximum size of an error response body that's kept to be logged
+const maxLoggedErrorSize = 4096
+
+// withRequestID returns a copy of 'r' that carries 'requestID'
+func withRequestID(r *http.Request, requestID string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), requestIDKey, requestID))
+}
+
+// requestID returns the ID that the gateway assigned to 'r'
+func requestID(r *http.Request) string {
+	id, _ := r.Context().Value(requestIDKey).(string)
+	return id
+}
+
+// withResponseHeader returns a copy of 'r' that carries the header of its
+// response, so that handlers that s2 calls can set headers that s2 doesn't
+// know about
+func withResponseHeader(r *http.Request, header http.Header) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), responseHeaderKey, header))
+}
+
+// responseHeader returns the header of the response to 'r'. Headers set on it
+// are dropped if 'r' doesn't carry its response header.
+func responseHeader(r *http.Request) http.Header {
+	if header, ok := r.Context().Value(responseHeaderKey).(http.Header); ok {
+		return header
+	}
+	return http.Header{}
+}
+
+// useRequestID makes s2, which generates its own request IDs, report the
+// gateway's request ID in the `RequestId` of error documents instead. It must
+// be called before s2 writes a response (s2 stores the ID in the route's
+// vars).
+func useRequestID(r *http.Request) {
+	if vars := mux.Vars(r); vars != nil {
+		vars["requestID"] = requestID(r)
+	}
+}
+
+// requestIDMiddleware is attached to the s2 router, see useRequestID
+func requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		useRequestID(r)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// writeError writes an S3 error document for 'err' (which is converted to an
+// InternalError unless it's an s2 error), for requests that are served
+// outside of the s2 router
+func (c *controller) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	s3Err := s2.NewFromGenericError(r, err)
+	s3Err.RequestID = requestID(r)
+	s2.WriteError(c.logger, w, r, s3Err)
+}
+
+// responseRecorder sets the request ID headers of every response, and keeps
+// the status and error document of the response so that they can be logged
+type responseRecorder struct {
+	http.ResponseWriter
+	requestID string
+	status    int
+	errBody   bytes.Buffer
+}
+
+func (w *responseRecorder) WriteHeader(status int) {
+	// s2 sets these headers from its own request ID, override them so that
+	// they always match the ID in the logs
+	w.Header().Set("x-amz-request-id", w.requestID)
+	w.Header().Set("x-amz-id-2", w.requestID)
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.status >= 400 && w.errBody.Len() < maxLoggedErrorSize {
+		w.errBody.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush sends buffered data to the client, so that streamed responses (e.g.
+// of SelectObjectContent) aren't held back
+func (w *responseRecorder) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// logResponse logs failed requests along with their request ID, S3 error
+// code and message
+func (w *responseRecorder) logResponse(logger *logrus.Entry, r *http.Request) {
+	if w.status < 400 {
+		return
+	}
+	logger = logger.WithField("requestID", w.requestID)
+	logf := logger.Infof
+	if w.status >= 500 {
+		logf = logger.Errorf
+	}
+	s3Err := &s2.Error{}
+	if err := xml.Unmarshal(w.errBody.Bytes(), s3Err); err != nil || s3Err.Code == "" {
+		logf("http response: %s %s: %d", r.Method, r.RequestURI, w.status)
+		return
+	}
+	logf("http response: %s %s: %d %s: %s", r.Method, r.RequestURI, w.status, s3Err.Code, s3Err.Message)
+}
@@ -0,0 +1,41 @@
+package s3
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/s2"
+	"github.com/sirupsen/logrus"
+)
+
+func TestRequestIDInErrors(t *testing.T) {
+	logger := logrus.New()
+	logs := &bytes.Buffer{}
+	logger.Out = logs
+	c := &controller{logger: logrus.NewEntry(logger), cors: newCORSCache()}
+	c.cacheCORSConfig("master.other", nil)
+
+	r := withRequestID(httptest.NewRequest("OPTIONS", "/master.other", nil), "abc123")
+	r.Header.Set("Origin", "https://foo.org")
+	r.Header.Set("Access-Control-Request-Method", "GET")
+	w := httptest.NewRecorder()
+	recorder := &responseRecorder{ResponseWriter: w, requestID: requestID(r)}
+	c.servePreflight(recorder, r)
+	recorder.logResponse(c.logger, r)
+
+	require.Equal(t, http.StatusForbidden, w.Code)
+	require.Equal(t, "abc123", w.Header().Get("x-amz-request-id"))
+	require.Equal(t, "abc123", w.Header().Get("x-amz-id-2"))
+	s3Err := &s2.Error{}
+	require.NoError(t, xml.Unmarshal(w.Body.Bytes(), s3Err))
+	require.Equal(t, "AccessForbidden", s3Err.Code)
+	require.Equal(t, "abc123", s3Err.RequestID)
+	require.Equal(t, "/master.other", s3Err.Resource)
+
+	require.True(t, bytes.Contains(logs.Bytes(), []byte("requestID=abc123")))
+	require.True(t, bytes.Contains(logs.Bytes(), []byte("403 AccessForbidden")))
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"time"
 
+	pfsClient "github.com/pachyderm/pachyderm/src/client/pfs"
 	"github.com/pachyderm/pachyderm/src/server/pkg/uuid"
 	"github.com/pachyderm/s2"
 	"github.com/sirupsen/logrus"
@@ -35,17 +36,74 @@ const (
 // Note: In `s3cmd`, you must set the access key and secret key, even though
 // this API will ignore them - otherwise, you'll get an opaque config error:
 // https://github.com/s3tools/s3cmd/issues/845#issuecomment-464885959
-func Server(port, pachdPort uint16) (*http.Server, error) {
+//
+// `bucketPolicies` is a comma-separated list of `<bucket>=<policy>` pairs,
+// where the policy is one of `read-write`, `read-only` or `write-only`.
+// Buckets that aren't listed are read-write.
+//
+// `checksums` is a comma-separated list of checksum algorithms (e.g.
+// `sha256,md5`, see pfs.ChecksumAlgorithm) that objects are reported with:
+// an md5 checksum is used as an object's ETag, and the others are set in
+// x-amz-checksum-* headers.
+//
+// `readAfterWriteBuckets` is a comma-separated list of buckets whose writes
+// are only responded to once they can be read, i.e. once the commits that
+// they're in are finished. Other writes can ask for this with the
+// `x-pachyderm-read-after-write: true` header.
+//
+// `auditSink` is where a record of each mutating request (e.g. PutObject or
+// DeleteBucket) is written: an http(s) URL that batches of records are POSTed
+// to as JSON lines, or the name of a PFS repo whose master branch they're
+// appended to (in a file per day). Requests aren't audited if it's empty.
+//
+// `limits` are the gateway's limits on its clients' connections and requests
+// (see Limits). Requests over them are shed with a 503 rather than queued, so
+// that one client can't take the gateway down for the others. Its
+// BucketLimits are enforced before a request's data reaches PFS, so that an
+// accidental huge upload is rejected rather than written to the bucket.
+//
+// `metadataCacheTTL` is how long the metadata of the objects that are read
+// (their branches' heads, sizes and checksums) is cached, so that repeated
+// HEAD requests don't each cost pachd RPCs. Cached metadata is invalidated
+// when a commit is started on its branch. Metadata isn't cached if it's 0.
+//
+// This also starts a goroutine that applies the expiration rules of buckets'
+// lifecycle configurations, which runs for the lifetime of the process.
+func Server(port, pachdPort uint16, bucketPolicies, checksums, readAfterWriteBuckets, auditSink string, metadataCacheTTL time.Duration, limits Limits) (*http.Server, error) {
 	logger := logrus.WithFields(logrus.Fields{
 		"source": "s3gateway",
 	})
 
+	policies, err := parseBucketPolicies(bucketPolicies)
+	if err != nil {
+		return nil, err
+	}
+	checksumAlgorithms, err := pfsClient.ParseChecksumAlgorithms(checksums)
+	if err != nil {
+		return nil, err
+	}
+	limitsByBucket, err := parseBucketLimits(limits.BucketLimits)
+	if err != nil {
+		return nil, err
+	}
 	c := &controller{
 		pachdPort:       pachdPort,
 		logger:          logger,
 		repo:            multipartRepo,
 		maxAllowedParts: maxAllowedParts,
+		policies:        policies,
+		checksums:       checksumAlgorithms,
+		readAfterWrite:  parseReadAfterWriteBuckets(readAfterWriteBuckets),
+		cors:            newCORSCache(),
+		website:         newWebsiteCache(),
+		bucketLimits:    limitsByBucket,
 	}
+	c.audit = newAuditLog(c, auditSink, logger)
+	c.metadata = newMetadataCache(metadataCacheTTL, logger, c.watchBranch)
+
+	limiter := newRequestLimiter(limits)
+
+	go c.runLifecycle()
 
 	s3Server := s2.NewS2(logger, maxRequestBodyLength, readBodyTimeout)
 	s3Server.Auth = c
@@ -54,6 +112,14 @@ func Server(port, pachdPort uint16) (*http.Server, error) {
 	s3Server.Object = c
 	s3Server.Multipart = c
 	router := s3Server.Router()
+	router.Use(requestIDMiddleware)
+	router.Use(c.corsMiddleware)
+	router.Use(writeBucketMiddleware)
+	router.Use(c.auditMiddleware)
+	router.Use(c.bucketLimitsMiddleware)
+	router.Use(c.lifecycleMiddleware)
+	router.Use(c.selectMiddleware)
+	router.Use(c.websiteMiddleware)
 
 	server := &http.Server{
 		Addr:         fmt.Sprintf(":%d", port),
@@ -61,23 +127,50 @@ func Server(port, pachdPort uint16) (*http.Server, error) {
 		WriteTimeout: requestTimeout,
 		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Set a request ID, if it hasn't been set by the client already.
-			// This can be used for tracing, and is included in error
-			// responses.
+			// This can be used for tracing, and is included in responses
+			// (including error documents) and in the logs.
 			requestID := r.Header.Get("X-Request-ID")
 			if requestID == "" {
 				requestID = uuid.NewWithoutDashes()
 				r.Header.Set("X-Request-ID", requestID)
 			}
-			w.Header().Set("x-amz-request-id", requestID)
+			r = withRequestID(r, requestID)
+			recorder := &responseRecorder{ResponseWriter: w, requestID: requestID}
+			r = withResponseHeader(r, recorder.Header())
+			recorder.Header().Set("x-amz-request-id", requestID)
+			recorder.Header().Set("x-amz-id-2", requestID)
+			defer recorder.logResponse(logger, r)
 
 			// Log that a request was made
-			logger.Infof("http request: %s %s", r.Method, r.RequestURI)
+			logger.WithField("requestID", requestID).Infof("http request: %s %s", r.Method, r.RequestURI)
+
+			release, acquired := limiter.acquire(clientAddress(r))
+			if !acquired {
+				c.shed(recorder, r)
+				return
+			}
+			defer release()
 
-			router.ServeHTTP(w, r)
+			// The s2 router has no OPTIONS routes, and preflight requests
+			// aren't authenticated
+			if r.Method == http.MethodOptions {
+				c.servePreflight(recorder, r)
+				return
+			}
+			// Uploads are checked (and aws-chunked bodies decoded) before
+			// s2 reads them
+			r, ok := c.verifyUpload(recorder, r)
+			if !ok {
+				return
+			}
+			router.ServeHTTP(recorder, r)
 		}),
 		// NOTE: this is not closed. If the standard logger gets customized, this will need to be fixed
 		ErrorLog: stdlog.New(logger.Writer(), "", 0),
 	}
+	if err := limits.configure(server); err != nil {
+		return nil, err
+	}
 
 	return server, nil
 }
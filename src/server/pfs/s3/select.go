@@ -0,0 +1,688 @@
+package s3
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/pachyderm/s2"
+)
+
+// The maximum size of the payload of a Records event (the same as S3's)
+const maxSelectPayload = 64 * 1024
+
+// The maximum size of a SelectObjectContent request body
+const maxSelectRequestSize = 256 * 1024
+
+// selectRequest is the XML document sent to SelectObjectContent
+type selectRequest struct {
+	XMLName             xml.Name            `xml:"SelectObjectContentRequest"`
+	Expression          string              `xml:"Expression"`
+	ExpressionType      string              `xml:"ExpressionType"`
+	InputSerialization  inputSerialization  `xml:"InputSerialization"`
+	OutputSerialization outputSerialization `xml:"OutputSerialization"`
+}
+
+type inputSerialization struct {
+	CompressionType string     `xml:"CompressionType"`
+	CSV             *csvInput  `xml:"CSV"`
+	JSON            *jsonInput `xml:"JSON"`
+	// Other holds unsupported formats, e.g. Parquet
+	Other []unsupportedElement `xml:",any"`
+}
+
+type csvInput struct {
+	// FileHeaderInfo is NONE (the default), USE or IGNORE
+	FileHeaderInfo  string `xml:"FileHeaderInfo"`
+	Comments        string `xml:"Comments"`
+	FieldDelimiter  string `xml:"FieldDelimiter"`
+	RecordDelimiter string `xml:"RecordDelimiter"`
+	QuoteCharacter  string `xml:"QuoteCharacter"`
+}
+
+type jsonInput struct {
+	// Type is DOCUMENT or LINES. Both are read the same way, as a sequence of
+	// JSON values.
+	Type string `xml:"Type"`
+}
+
+type outputSerialization struct {
+	CSV  *csvOutput  `xml:"CSV"`
+	JSON *jsonOutput `xml:"JSON"`
+}
+
+type csvOutput struct {
+	// QuoteFields is ASNEEDED (the default) or ALWAYS
+	QuoteFields     string `xml:"QuoteFields"`
+	FieldDelimiter  string `xml:"FieldDelimiter"`
+	RecordDelimiter string `xml:"RecordDelimiter"`
+}
+
+type jsonOutput struct {
+	RecordDelimiter string `xml:"RecordDelimiter"`
+}
+
+// selectStats is the payload of the Stats event
+type selectStats struct {
+	XMLName        xml.Name `xml:"Stats"`
+	BytesScanned   int64    `xml:"BytesScanned"`
+	BytesProcessed int64    `xml:"BytesProcessed"`
+	BytesReturned  int64    `xml:"BytesReturned"`
+}
+
+func invalidSelectError(r *http.Request, code, message string) *s2.Error {
+	return s2.NewError(r, http.StatusBadRequest, code, message)
+}
+
+func (req *selectRequest) validate(r *http.Request) error {
+	if req.ExpressionType != "SQL" {
+		return invalidSelectError(r, "InvalidExpressionType", "The ExpressionType is invalid. Only SQL expressions are supported.")
+	}
+	in := req.InputSerialization
+	if len(in.Other) > 0 {
+		return s2.NewError(r, http.StatusNotImplemented, "NotImplemented", fmt.Sprintf("%s input is not supported, only CSV and JSON are", in.Other[0].XMLName.Local))
+	}
+	if (in.CSV == nil) == (in.JSON == nil) {
+		return invalidSelectError(r, "MissingRequiredParameter", "The InputSerialization must specify one of CSV or JSON.")
+	}
+	switch strings.ToUpper(in.CompressionType) {
+	case "", "NONE", "GZIP", "BZIP2":
+	default:
+		return invalidSelectError(r, "InvalidCompressionFormat", "The file is not in a supported compression format. Only GZIP and BZIP2 are supported.")
+	}
+	if in.CSV != nil {
+		switch strings.ToUpper(in.CSV.FileHeaderInfo) {
+		case "", "NONE", "USE", "IGNORE":
+		default:
+			return invalidSelectError(r, "InvalidFileHeaderInfo", "The FileHeaderInfo is invalid. Only NONE, USE, and IGNORE are supported.")
+		}
+		if len([]rune(in.CSV.FieldDelimiter)) > 1 {
+			return invalidSelectError(r, "InvalidFieldDelimiter", "The field delimiter must be a single character.")
+		}
+		if d := in.CSV.RecordDelimiter; d != "" && d != "\n" && d != "\r\n" {
+			return invalidSelectError(r, "InvalidRecordDelimiter", "The record delimiter must be a newline.")
+		}
+		if q := in.CSV.QuoteCharacter; q != "" && q != `"` {
+			return invalidSelectError(r, "InvalidQuoteCharacter", "The quote character must be '\"'.")
+		}
+		if len([]rune(in.CSV.Comments)) > 1 {
+			return invalidSelectError(r, "InvalidCommentCharacter", "The comment character must be a single character.")
+		}
+	}
+	if in.JSON != nil {
+		switch strings.ToUpper(in.JSON.Type) {
+		case "DOCUMENT", "LINES":
+		default:
+			return invalidSelectError(r, "InvalidJsonType", "The JsonType is invalid. Only DOCUMENT and LINES are supported.")
+		}
+	}
+	out := req.OutputSerialization
+	if (out.CSV == nil) == (out.JSON == nil) {
+		return invalidSelectError(r, "MissingRequiredParameter", "The OutputSerialization must specify one of CSV or JSON.")
+	}
+	if out.CSV != nil {
+		if len([]rune(out.CSV.FieldDelimiter)) > 1 {
+			return invalidSelectError(r, "InvalidFieldDelimiter", "The field delimiter must be a single character.")
+		}
+		switch strings.ToUpper(out.CSV.QuoteFields) {
+		case "", "ASNEEDED", "ALWAYS":
+		default:
+			return invalidSelectError(r, "InvalidQuoteFields", "The QuoteFields is invalid. Only ALWAYS and ASNEEDED are supported.")
+		}
+	}
+	return nil
+}
+
+// selectMiddleware serves SelectObjectContent (`POST /<bucket>/<key>?select`),
+// which s2 doesn't implement. It's attached to the s2 router, so it runs after
+// requests were authenticated.
+func (c *controller) selectMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		if _, ok := r.URL.Query()["select"]; ok && r.Method == "POST" && vars["bucket"] != "" && vars["key"] != "" {
+			c.serveSelect(w, r, vars["bucket"], vars["key"])
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (c *controller) serveSelect(w http.ResponseWriter, r *http.Request, bucket, file string) {
+	if err := c.SelectObjectContent(w, r, bucket, file); err != nil {
+		c.writeError(w, r, err)
+	}
+}
+
+// SelectObjectContent runs the SQL expression of the request body over an
+// object, and streams the matching records to 'w' as an event stream. Errors
+// that are returned happened before anything was written to 'w'; errors that
+// happen afterwards are sent as error events.
+func (c *controller) SelectObjectContent(w http.ResponseWriter, r *http.Request, bucket, file string) error {
+	if r.URL.Query().Get("select-type") != "2" {
+		return invalidSelectError(r, "InvalidArgument", "The select-type must be 2.")
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxSelectRequestSize+1))
+	if err != nil {
+		return s2.InternalError(r, err)
+	}
+	if len(body) > maxSelectRequestSize {
+		return s2.NewError(r, http.StatusBadRequest, "MaxMessageLengthExceeded", "Your request was too big.")
+	}
+	req := &selectRequest{}
+	if err := xml.Unmarshal(body, req); err != nil {
+		return s2.MalformedXMLError(r)
+	}
+	if err := req.validate(r); err != nil {
+		return err
+	}
+	query, err := parseSelect(req.Expression)
+	if err != nil {
+		return invalidSelectError(r, "ParseInvalidExpression", fmt.Sprintf("The SQL expression is invalid: %v", err))
+	}
+
+	// GetObject checks the bucket's policy and resolves the object's version
+	result, err := c.GetObject(r, bucket, file, r.URL.Query().Get("versionId"))
+	if err != nil {
+		return err
+	}
+	scanned := &countingReader{r: result.Content}
+	content, err := decompress(scanned, req.InputSerialization.CompressionType)
+	if err != nil {
+		return invalidSelectError(r, "InvalidCompressionFormat", fmt.Sprintf("The object could not be decompressed: %v", err))
+	}
+	processed := &countingReader{r: content}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.WriteHeader(http.StatusOK)
+	events := &eventWriter{w: w}
+	returned, err := runSelect(query, &req.InputSerialization, &req.OutputSerialization, processed, events)
+	if err != nil {
+		c.logger.Errorf("error running S3 Select on %s/%s: %v", bucket, file, err)
+		events.writeError("InternalError", err.Error())
+		return nil
+	}
+	stats, err := xml.Marshal(&selectStats{
+		BytesScanned:   scanned.n,
+		BytesProcessed: processed.n,
+		BytesReturned:  returned,
+	})
+	if err != nil {
+		events.writeError("InternalError", err.Error())
+		return nil
+	}
+	events.writeEvent("Stats", "text/xml", stats)
+	events.writeEvent("End", "", nil)
+	return nil
+}
+
+func decompress(r io.Reader, compressionType string) (io.Reader, error) {
+	switch strings.ToUpper(compressionType) {
+	case "GZIP":
+		return gzip.NewReader(r)
+	case "BZIP2":
+		return bzip2.NewReader(r), nil
+	}
+	return r, nil
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+// runSelect runs 'query' over the records read from 'in' with 'input', and
+// writes the results to 'events' with 'output'. It returns the number of
+// bytes of results.
+func runSelect(query *selectQuery, input *inputSerialization, output *outputSerialization, in io.Reader, events *eventWriter) (int64, error) {
+	records := &recordsWriter{events: events}
+	write, err := newOutputWriter(query, output, records)
+	if err != nil {
+		return 0, err
+	}
+	var count, matched int64
+	if err := readRecords(input, in, func(record selectRecord) (bool, error) {
+		if query.where != nil {
+			ok, err := evalBool(query.where, record)
+			if err != nil {
+				return false, err
+			}
+			if ok == nil || !*ok {
+				return true, nil
+			}
+		}
+		matched++
+		if query.count {
+			count++
+		} else if err := write(record); err != nil {
+			return false, err
+		}
+		return query.limit < 0 || matched < query.limit, nil
+	}); err != nil {
+		return 0, err
+	}
+	if query.count {
+		if err := write(countRecord(count)); err != nil {
+			return 0, err
+		}
+	}
+	if err := records.flush(); err != nil {
+		return 0, err
+	}
+	return records.returned, nil
+}
+
+// countRecord is the single record that `SELECT COUNT(*)` returns
+type countRecord int64
+
+func (c countRecord) column(name string) (interface{}, bool) {
+	return nil, false
+}
+
+func (c countRecord) index(i int) (interface{}, bool) {
+	return float64(c), i == 0
+}
+
+// csvRecord is a row of a CSV object
+type csvRecord struct {
+	header []string
+	values []string
+}
+
+func (c *csvRecord) column(name string) (interface{}, bool) {
+	for i, column := range c.header {
+		if column == name && i < len(c.values) {
+			return c.values[i], true
+		}
+	}
+	return nil, false
+}
+
+func (c *csvRecord) index(i int) (interface{}, bool) {
+	if i < len(c.values) {
+		return c.values[i], true
+	}
+	return nil, false
+}
+
+// jsonRecord is a value of a JSON object. Records that aren't JSON objects
+// have a single column, `_1`.
+type jsonRecord struct {
+	value interface{}
+	// keys are the keys of 'value', if it's an object, in the order they
+	// appear in the input
+	keys []string
+}
+
+func (j *jsonRecord) column(name string) (interface{}, bool) {
+	object, ok := j.value.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	value, ok := object[name]
+	return value, ok
+}
+
+func (j *jsonRecord) index(i int) (interface{}, bool) {
+	if _, ok := j.value.(map[string]interface{}); !ok {
+		return j.value, i == 0
+	}
+	if i < len(j.keys) {
+		return j.column(j.keys[i])
+	}
+	return nil, false
+}
+
+// readRecords calls 'f' with each record of 'in', until it returns false
+func readRecords(input *inputSerialization, in io.Reader, f func(selectRecord) (bool, error)) error {
+	if input.CSV != nil {
+		return readCSVRecords(input.CSV, in, f)
+	}
+	return readJSONRecords(in, f)
+}
+
+func readCSVRecords(input *csvInput, in io.Reader, f func(selectRecord) (bool, error)) error {
+	reader := csv.NewReader(in)
+	reader.FieldsPerRecord = -1
+	if input.FieldDelimiter != "" {
+		reader.Comma = []rune(input.FieldDelimiter)[0]
+	}
+	if input.Comments != "" {
+		reader.Comment = []rune(input.Comments)[0]
+	}
+	var header []string
+	if headerInfo := strings.ToUpper(input.FileHeaderInfo); headerInfo == "USE" || headerInfo == "IGNORE" {
+		first, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if headerInfo == "USE" {
+			header = first
+		}
+	}
+	for {
+		values, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		more, err := f(&csvRecord{header: header, values: values})
+		if err != nil || !more {
+			return err
+		}
+	}
+}
+
+func readJSONRecords(in io.Reader, f func(selectRecord) (bool, error)) error {
+	decoder := json.NewDecoder(bufio.NewReader(in))
+	decoder.UseNumber()
+	for {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		record, err := parseJSONRecord(raw)
+		if err != nil {
+			return err
+		}
+		// the elements of a top-level array are records
+		if elements, ok := record.value.([]interface{}); ok {
+			var rawElements []json.RawMessage
+			if err := json.Unmarshal(raw, &rawElements); err != nil {
+				return err
+			}
+			for i := range elements {
+				element, err := parseJSONRecord(rawElements[i])
+				if err != nil {
+					return err
+				}
+				if more, err := f(element); err != nil || !more {
+					return err
+				}
+			}
+			continue
+		}
+		if more, err := f(record); err != nil || !more {
+			return err
+		}
+	}
+}
+
+// parseJSONRecord parses a JSON value, keeping the order of its keys if it's
+// an object
+func parseJSONRecord(raw json.RawMessage) (*jsonRecord, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	record := &jsonRecord{value: jsonValue(value)}
+	if _, ok := record.value.(map[string]interface{}); ok {
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.Token() // '{'
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			record.keys = append(record.keys, key.(string))
+			var skip json.RawMessage
+			if err := decoder.Decode(&skip); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return record, nil
+}
+
+// jsonValue converts the json.Numbers in a decoded value to float64s, which is
+// how numbers are evaluated
+func jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return v.String()
+		}
+		return f
+	case map[string]interface{}:
+		for key, element := range v {
+			v[key] = jsonValue(element)
+		}
+	case []interface{}:
+		for i, element := range v {
+			v[i] = jsonValue(element)
+		}
+	}
+	return value
+}
+
+// newOutputWriter returns a function that writes the projection of 'query'
+// for a record to 'w', serialized with 'output'
+func newOutputWriter(query *selectQuery, output *outputSerialization, w io.Writer) (func(selectRecord) error, error) {
+	project := func(record selectRecord) ([]string, []interface{}, error) {
+		if _, ok := record.(countRecord); ok {
+			value, _ := record.index(0)
+			return []string{"_1"}, []interface{}{value}, nil
+		}
+		if query.star {
+			return starColumns(record)
+		}
+		names := make([]string, len(query.items))
+		values := make([]interface{}, len(query.items))
+		for i, item := range query.items {
+			value, err := item.expr.eval(record)
+			if err != nil {
+				return nil, nil, err
+			}
+			names[i], values[i] = item.name, value
+		}
+		return names, values, nil
+	}
+	if output.JSON != nil {
+		delimiter := output.JSON.RecordDelimiter
+		if delimiter == "" {
+			delimiter = "\n"
+		}
+		return func(record selectRecord) error {
+			names, values, err := project(record)
+			if err != nil {
+				return err
+			}
+			var buf bytes.Buffer
+			buf.WriteString("{")
+			for i, name := range names {
+				if i > 0 {
+					buf.WriteString(",")
+				}
+				key, err := json.Marshal(name)
+				if err != nil {
+					return err
+				}
+				value, err := json.Marshal(values[i])
+				if err != nil {
+					return err
+				}
+				buf.Write(key)
+				buf.WriteString(":")
+				buf.Write(value)
+			}
+			buf.WriteString("}")
+			buf.WriteString(delimiter)
+			_, err = w.Write(buf.Bytes())
+			return err
+		}, nil
+	}
+	delimiter, recordDelimiter := ",", "\n"
+	if output.CSV.FieldDelimiter != "" {
+		delimiter = output.CSV.FieldDelimiter
+	}
+	if output.CSV.RecordDelimiter != "" {
+		recordDelimiter = output.CSV.RecordDelimiter
+	}
+	always := strings.ToUpper(output.CSV.QuoteFields) == "ALWAYS"
+	return func(record selectRecord) error {
+		_, values, err := project(record)
+		if err != nil {
+			return err
+		}
+		fields := make([]string, len(values))
+		for i, value := range values {
+			fields[i] = csvField(csvValue(value), delimiter, always)
+		}
+		_, err = io.WriteString(w, strings.Join(fields, delimiter)+recordDelimiter)
+		return err
+	}, nil
+}
+
+// starColumns returns all of the columns of a record, for `SELECT *`
+func starColumns(record selectRecord) ([]string, []interface{}, error) {
+	switch r := record.(type) {
+	case *csvRecord:
+		names := make([]string, len(r.values))
+		values := make([]interface{}, len(r.values))
+		for i, value := range r.values {
+			names[i] = fmt.Sprintf("_%d", i+1)
+			if i < len(r.header) {
+				names[i] = r.header[i]
+			}
+			values[i] = value
+		}
+		return names, values, nil
+	case *jsonRecord:
+		if _, ok := r.value.(map[string]interface{}); !ok {
+			return []string{"_1"}, []interface{}{r.value}, nil
+		}
+		values := make([]interface{}, len(r.keys))
+		for i, key := range r.keys {
+			values[i], _ = r.column(key)
+		}
+		return r.keys, values, nil
+	}
+	return nil, nil, fmt.Errorf("unexpected record type %T", record)
+}
+
+// csvValue returns the text of a value in CSV output. Nested JSON values are
+// written as JSON.
+func csvValue(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		text, err := json.Marshal(value)
+		if err == nil {
+			return string(text)
+		}
+	}
+	return toString(value)
+}
+
+// csvField quotes a CSV field if 'always' is set or it needs to be
+func csvField(field, delimiter string, always bool) string {
+	if always || strings.Contains(field, delimiter) || strings.ContainsAny(field, "\"\r\n") {
+		return `"` + strings.Replace(field, `"`, `""`, -1) + `"`
+	}
+	return field
+}
+
+// recordsWriter buffers results and writes them in Records events of up to
+// maxSelectPayload bytes
+type recordsWriter struct {
+	events   *eventWriter
+	buf      bytes.Buffer
+	returned int64
+}
+
+func (w *recordsWriter) Write(p []byte) (int, error) {
+	w.buf.Write(p)
+	w.returned += int64(len(p))
+	for w.buf.Len() >= maxSelectPayload {
+		if err := w.events.writeEvent("Records", "application/octet-stream", w.buf.Next(maxSelectPayload)); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+func (w *recordsWriter) flush() error {
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	return w.events.writeEvent("Records", "application/octet-stream", w.buf.Next(w.buf.Len()))
+}
+
+// eventWriter writes messages in the event stream format of
+// SelectObjectContent responses. Each message is:
+//
+//	total length (4 bytes) | headers length (4 bytes) | prelude CRC (4 bytes)
+//	| headers | payload | message CRC (4 bytes)
+//
+// where the CRCs are CRC32 checksums of everything before them, and each
+// header is a 1 byte name length, the name, the type 7 (string), a 2 byte
+// value length and the value.
+type eventWriter struct {
+	w io.Writer
+}
+
+func (e *eventWriter) writeEvent(eventType, contentType string, payload []byte) error {
+	headers := [][2]string{{":event-type", eventType}}
+	if contentType != "" {
+		headers = append(headers, [2]string{":content-type", contentType})
+	}
+	headers = append(headers, [2]string{":message-type", "event"})
+	return e.writeMessage(headers, payload)
+}
+
+func (e *eventWriter) writeError(code, message string) error {
+	return e.writeMessage([][2]string{
+		{":error-code", code},
+		{":error-message", message},
+		{":message-type", "error"},
+	}, nil)
+}
+
+func (e *eventWriter) writeMessage(headers [][2]string, payload []byte) error {
+	var h bytes.Buffer
+	for _, header := range headers {
+		h.WriteByte(byte(len(header[0])))
+		h.WriteString(header[0])
+		h.WriteByte(7)
+		binary.Write(&h, binary.BigEndian, uint16(len(header[1])))
+		h.WriteString(header[1])
+	}
+	var msg bytes.Buffer
+	binary.Write(&msg, binary.BigEndian, uint32(12+h.Len()+len(payload)+4))
+	binary.Write(&msg, binary.BigEndian, uint32(h.Len()))
+	binary.Write(&msg, binary.BigEndian, crc32.ChecksumIEEE(msg.Bytes()))
+	msg.Write(h.Bytes())
+	msg.Write(payload)
+	binary.Write(&msg, binary.BigEndian, crc32.ChecksumIEEE(msg.Bytes()))
+	if _, err := e.w.Write(msg.Bytes()); err != nil {
+		return err
+	}
+	if f, ok := e.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
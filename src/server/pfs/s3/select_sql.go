@@ -0,0 +1,851 @@
+package s3
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// This file implements the subset of the S3 Select SQL dialect that the
+// gateway supports:
+//
+//   SELECT <projection> FROM S3Object[[*]] [[AS] <alias>] [WHERE <condition>] [LIMIT <n>]
+//
+// The projection is `*`, `COUNT(*)` or a list of expressions, each optionally
+// named with `AS`. Expressions are column references (`s.name`, `name`,
+// `"quoted name"`, or `_1`, `_2`... for columns by position), string and
+// number literals, TRUE, FALSE and NULL, the comparison operators, IS [NOT]
+// NULL, [NOT] LIKE, [NOT] IN, AND, OR, NOT, and CAST(<expr> AS <type>) with
+// the types INT, INTEGER, FLOAT, DECIMAL, STRING and BOOL.
+//
+// As in S3, CSV values are strings, but comparing a string with a number
+// compares them as numbers if the string is one, so that e.g.
+// `WHERE s.price > 10` works without a CAST.
+
+// selectQuery is a parsed SELECT statement
+type selectQuery struct {
+	// star is set for `SELECT *`
+	star bool
+	// count is set for `SELECT COUNT(*)`
+	count bool
+	items []selectItem
+	where sqlExpr
+	// limit is -1 if the query has no LIMIT
+	limit int64
+}
+
+type selectItem struct {
+	expr sqlExpr
+	// name is the name of the item in JSON output
+	name string
+}
+
+// a selectRecord is a row of the object that a query runs on
+type selectRecord interface {
+	// column returns the value of the column named 'name', if it exists
+	column(name string) (interface{}, bool)
+	// index returns the value of the i'th (from 0) column, if it exists
+	index(i int) (interface{}, bool)
+}
+
+// A sqlExpr is evaluated for each record. Values are nil (NULL), strings,
+// float64s, bools, or, in JSON objects, []interface{}s and
+// map[string]interface{}s.
+type sqlExpr interface {
+	eval(r selectRecord) (interface{}, error)
+}
+
+func parseSelect(query string) (*selectQuery, error) {
+	tokens, err := lexSQL(query)
+	if err != nil {
+		return nil, err
+	}
+	p := &sqlParser{tokens: tokens}
+	q, err := p.parseSelect()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("unexpected %q at the end of the query", p.peek().text)
+	}
+	return q, nil
+}
+
+type tokenKind int
+
+const (
+	tokenIdent tokenKind = iota
+	// tokenQuotedIdent is a "double-quoted" identifier, which is never a
+	// keyword
+	tokenQuotedIdent
+	tokenString
+	tokenNumber
+	tokenSymbol
+)
+
+type sqlToken struct {
+	kind tokenKind
+	text string
+}
+
+func lexSQL(query string) ([]sqlToken, error) {
+	var tokens []sqlToken
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '\'' || r == '"':
+			kind := tokenString
+			if r == '"' {
+				kind = tokenQuotedIdent
+			}
+			var text strings.Builder
+			i++
+			for {
+				if i >= len(runes) {
+					return nil, fmt.Errorf("unterminated quote in %q", query)
+				}
+				if runes[i] == r {
+					// a doubled quote is an escaped quote
+					if i+1 < len(runes) && runes[i+1] == r {
+						text.WriteRune(r)
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				text.WriteRune(runes[i])
+				i++
+			}
+			tokens = append(tokens, sqlToken{kind, text.String()})
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.' || runes[i] == 'e' || runes[i] == 'E') {
+				i++
+			}
+			tokens = append(tokens, sqlToken{tokenNumber, string(runes[start:i])})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, sqlToken{tokenIdent, string(runes[start:i])})
+		default:
+			symbol := string(r)
+			if i+1 < len(runes) {
+				switch two := string(runes[i : i+2]); two {
+				case "<=", ">=", "<>", "!=":
+					symbol = two
+				}
+			}
+			if !strings.Contains("(),.*=<>[]-", symbol) && len(symbol) == 1 {
+				return nil, fmt.Errorf("unexpected character %q in %q", r, query)
+			}
+			tokens = append(tokens, sqlToken{tokenSymbol, symbol})
+			i += len(symbol)
+		}
+	}
+	return tokens, nil
+}
+
+type sqlParser struct {
+	tokens []sqlToken
+	pos    int
+	// alias is the name given to S3Object in the FROM clause, e.g. `s`
+	alias string
+}
+
+func (p *sqlParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *sqlParser) peek() sqlToken {
+	if p.done() {
+		return sqlToken{kind: tokenSymbol, text: "end of query"}
+	}
+	return p.tokens[p.pos]
+}
+
+// isKeyword reports whether the next token is the keyword 'keyword'
+func (p *sqlParser) isKeyword(keyword string) bool {
+	t := p.peek()
+	return !p.done() && t.kind == tokenIdent && strings.EqualFold(t.text, keyword)
+}
+
+// acceptKeyword consumes the next token if it's 'keyword'
+func (p *sqlParser) acceptKeyword(keyword string) bool {
+	if p.isKeyword(keyword) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *sqlParser) expectKeyword(keyword string) error {
+	if !p.acceptKeyword(keyword) {
+		return fmt.Errorf("expected %s, found %q", keyword, p.peek().text)
+	}
+	return nil
+}
+
+// acceptSymbol consumes the next token if it's 'symbol'
+func (p *sqlParser) acceptSymbol(symbol string) bool {
+	t := p.peek()
+	if !p.done() && t.kind == tokenSymbol && t.text == symbol {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *sqlParser) expectSymbol(symbol string) error {
+	if !p.acceptSymbol(symbol) {
+		return fmt.Errorf("expected %q, found %q", symbol, p.peek().text)
+	}
+	return nil
+}
+
+// keywords that can't be used as unquoted column names or aliases
+var sqlKeywords = map[string]bool{
+	"SELECT": true, "FROM": true, "WHERE": true, "LIMIT": true, "AS": true,
+	"AND": true, "OR": true, "NOT": true, "IS": true, "NULL": true,
+	"LIKE": true, "IN": true, "TRUE": true, "FALSE": true, "CAST": true,
+}
+
+func (p *sqlParser) parseIdent() (string, error) {
+	t := p.peek()
+	if p.done() || (t.kind != tokenIdent && t.kind != tokenQuotedIdent) || (t.kind == tokenIdent && sqlKeywords[strings.ToUpper(t.text)]) {
+		return "", fmt.Errorf("expected a name, found %q", t.text)
+	}
+	p.pos++
+	return t.text, nil
+}
+
+func (p *sqlParser) parseSelect() (*selectQuery, error) {
+	if err := p.expectKeyword("SELECT"); err != nil {
+		return nil, err
+	}
+	q := &selectQuery{limit: -1}
+	// The FROM clause is parsed first, as column references in the
+	// projection may use its alias
+	start := p.pos
+	depth := 0
+	for ; !p.done(); p.pos++ {
+		t := p.peek()
+		if t.kind == tokenSymbol && t.text == "(" {
+			depth++
+		} else if t.kind == tokenSymbol && t.text == ")" {
+			depth--
+		} else if depth == 0 && p.isKeyword("FROM") {
+			break
+		}
+	}
+	if err := p.parseFrom(); err != nil {
+		return nil, err
+	}
+	end := p.pos
+	p.pos = start
+	switch {
+	case p.acceptSymbol("*"):
+		q.star = true
+	case p.isKeyword("COUNT") && p.pos+1 < len(p.tokens) && p.tokens[p.pos+1].text == "(":
+		p.pos++
+		if err := p.expectSymbol("("); err != nil {
+			return nil, err
+		}
+		if err := p.expectSymbol("*"); err != nil {
+			return nil, err
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+		q.count = true
+	default:
+		for {
+			expr, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			item := selectItem{expr: expr, name: fmt.Sprintf("_%d", len(q.items)+1)}
+			if ref, ok := expr.(*columnRef); ok {
+				item.name = ref.name
+				if len(ref.path) > 0 {
+					item.name = ref.path[len(ref.path)-1]
+				} else if ref.position > 0 {
+					item.name = fmt.Sprintf("_%d", ref.position)
+				}
+			}
+			if p.acceptKeyword("AS") {
+				if item.name, err = p.parseIdent(); err != nil {
+					return nil, err
+				}
+			}
+			q.items = append(q.items, item)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+	}
+	if !p.isKeyword("FROM") {
+		return nil, fmt.Errorf("expected FROM, found %q", p.peek().text)
+	}
+	p.pos = end
+	if p.acceptKeyword("WHERE") {
+		where, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		q.where = where
+	}
+	if p.acceptKeyword("LIMIT") {
+		t := p.peek()
+		limit, err := strconv.ParseInt(t.text, 10, 64)
+		if p.done() || t.kind != tokenNumber || err != nil || limit < 0 {
+			return nil, fmt.Errorf("expected a number after LIMIT, found %q", t.text)
+		}
+		p.pos++
+		q.limit = limit
+	}
+	return q, nil
+}
+
+func (p *sqlParser) parseFrom() error {
+	if err := p.expectKeyword("FROM"); err != nil {
+		return err
+	}
+	if !p.acceptKeyword("S3Object") {
+		return fmt.Errorf("expected S3Object after FROM, found %q", p.peek().text)
+	}
+	if p.acceptSymbol("[") {
+		if err := p.expectSymbol("*"); err != nil {
+			return err
+		}
+		if err := p.expectSymbol("]"); err != nil {
+			return err
+		}
+	}
+	if p.acceptKeyword("AS") || (!p.done() && !p.isKeyword("WHERE") && !p.isKeyword("LIMIT")) {
+		alias, err := p.parseIdent()
+		if err != nil {
+			return err
+		}
+		p.alias = alias
+	}
+	return nil
+}
+
+func (p *sqlParser) parseExpr() (sqlExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalExpr{op: "OR", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *sqlParser) parseAnd() (sqlExpr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("AND") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalExpr{op: "AND", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *sqlParser) parseNot() (sqlExpr, error) {
+	if p.acceptKeyword("NOT") {
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{expr}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *sqlParser) parseComparison() (sqlExpr, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	if !p.done() && t.kind == tokenSymbol {
+		switch t.text {
+		case "=", "!=", "<>", "<", "<=", ">", ">=":
+			p.pos++
+			right, err := p.parsePrimary()
+			if err != nil {
+				return nil, err
+			}
+			return &comparisonExpr{op: t.text, left: left, right: right}, nil
+		}
+	}
+	if p.acceptKeyword("IS") {
+		not := p.acceptKeyword("NOT")
+		if err := p.expectKeyword("NULL"); err != nil {
+			return nil, err
+		}
+		var expr sqlExpr = &isNullExpr{left}
+		if not {
+			expr = &notExpr{expr}
+		}
+		return expr, nil
+	}
+	not := false
+	if p.isKeyword("NOT") && p.pos+1 < len(p.tokens) {
+		next := p.tokens[p.pos+1]
+		if next.kind == tokenIdent && (strings.EqualFold(next.text, "LIKE") || strings.EqualFold(next.text, "IN")) {
+			p.pos++
+			not = true
+		}
+	}
+	var expr sqlExpr
+	switch {
+	case p.acceptKeyword("LIKE"):
+		t := p.peek()
+		if p.done() || t.kind != tokenString {
+			return nil, fmt.Errorf("expected a string after LIKE, found %q", t.text)
+		}
+		p.pos++
+		expr = &likeExpr{left: left, pattern: likePattern(t.text)}
+	case p.acceptKeyword("IN"):
+		if err := p.expectSymbol("("); err != nil {
+			return nil, err
+		}
+		in := &inExpr{left: left}
+		for {
+			value, err := p.parsePrimary()
+			if err != nil {
+				return nil, err
+			}
+			in.values = append(in.values, value)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+		expr = in
+	default:
+		return left, nil
+	}
+	if not {
+		expr = &notExpr{expr}
+	}
+	return expr, nil
+}
+
+func (p *sqlParser) parsePrimary() (sqlExpr, error) {
+	t := p.peek()
+	if p.done() {
+		return nil, fmt.Errorf("unexpected end of query")
+	}
+	switch t.kind {
+	case tokenString:
+		p.pos++
+		return literal{t.text}, nil
+	case tokenNumber:
+		p.pos++
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", t.text)
+		}
+		return literal{f}, nil
+	case tokenSymbol:
+		switch {
+		case p.acceptSymbol("("):
+			expr, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectSymbol(")"); err != nil {
+				return nil, err
+			}
+			return expr, nil
+		case p.acceptSymbol("-"):
+			n := p.peek()
+			if p.done() || n.kind != tokenNumber {
+				return nil, fmt.Errorf("expected a number after '-', found %q", n.text)
+			}
+			p.pos++
+			f, err := strconv.ParseFloat(n.text, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q", n.text)
+			}
+			return literal{-f}, nil
+		}
+		return nil, fmt.Errorf("unexpected %q", t.text)
+	}
+	switch {
+	case p.acceptKeyword("TRUE"):
+		return literal{true}, nil
+	case p.acceptKeyword("FALSE"):
+		return literal{false}, nil
+	case p.acceptKeyword("NULL"):
+		return literal{nil}, nil
+	case p.acceptKeyword("CAST"):
+		if err := p.expectSymbol("("); err != nil {
+			return nil, err
+		}
+		expr, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectKeyword("AS"); err != nil {
+			return nil, err
+		}
+		typ := strings.ToUpper(p.peek().text)
+		switch typ {
+		case "INT", "INTEGER", "FLOAT", "DECIMAL", "STRING", "BOOL":
+			p.pos++
+		default:
+			return nil, fmt.Errorf("unsupported CAST type %q", p.peek().text)
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+		return &castExpr{expr: expr, typ: typ}, nil
+	}
+	return p.parseColumnRef()
+}
+
+var positionalColumn = regexp.MustCompile(`^_([1-9][0-9]*)$`)
+
+func (p *sqlParser) parseColumnRef() (sqlExpr, error) {
+	name, err := p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+	var path []string
+	for p.acceptSymbol(".") {
+		part, err := p.parseIdent()
+		if err != nil {
+			return nil, err
+		}
+		path = append(path, part)
+	}
+	// `s.name` refers to the column `name` of the record aliased as `s`
+	if len(path) > 0 && (strings.EqualFold(name, p.alias) || strings.EqualFold(name, "S3Object")) {
+		name, path = path[0], path[1:]
+	}
+	ref := &columnRef{name: name, path: path}
+	if m := positionalColumn.FindStringSubmatch(name); m != nil {
+		ref.position, _ = strconv.Atoi(m[1])
+	}
+	return ref, nil
+}
+
+type literal struct {
+	value interface{}
+}
+
+func (l literal) eval(selectRecord) (interface{}, error) {
+	return l.value, nil
+}
+
+// columnRef is a reference to a column, by name or (for `_1`, `_2`...) by
+// position, optionally followed by the path of a field within it, in JSON
+// objects
+type columnRef struct {
+	name string
+	// position is the column's position, from 1, or 0 if it's referred to by
+	// name
+	position int
+	path     []string
+}
+
+func (c *columnRef) eval(r selectRecord) (interface{}, error) {
+	value, ok := r.column(c.name)
+	if !ok && c.position > 0 {
+		value, ok = r.index(c.position - 1)
+	}
+	if !ok {
+		return nil, nil
+	}
+	for _, field := range c.path {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, nil
+		}
+		value = object[field]
+	}
+	return value, nil
+}
+
+type logicalExpr struct {
+	op          string
+	left, right sqlExpr
+}
+
+// eval implements SQL's three-valued logic, where NULL is unknown
+func (e *logicalExpr) eval(r selectRecord) (interface{}, error) {
+	left, err := evalBool(e.left, r)
+	if err != nil {
+		return nil, err
+	}
+	if left != nil && *left == (e.op == "OR") {
+		return *left, nil
+	}
+	right, err := evalBool(e.right, r)
+	if err != nil {
+		return nil, err
+	}
+	if right != nil && *right == (e.op == "OR") {
+		return *right, nil
+	}
+	if left == nil || right == nil {
+		return nil, nil
+	}
+	return *right, nil
+}
+
+type notExpr struct {
+	expr sqlExpr
+}
+
+func (e *notExpr) eval(r selectRecord) (interface{}, error) {
+	b, err := evalBool(e.expr, r)
+	if err != nil || b == nil {
+		return nil, err
+	}
+	return !*b, nil
+}
+
+// evalBool evaluates 'expr', which must be a boolean, NULL (returned as nil)
+// or a string or number that casts to a boolean
+func evalBool(expr sqlExpr, r selectRecord) (*bool, error) {
+	value, err := expr.eval(r)
+	if err != nil || value == nil {
+		return nil, err
+	}
+	b, err := toBool(value)
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+type comparisonExpr struct {
+	op          string
+	left, right sqlExpr
+}
+
+func (e *comparisonExpr) eval(r selectRecord) (interface{}, error) {
+	left, err := e.left.eval(r)
+	if err != nil {
+		return nil, err
+	}
+	right, err := e.right.eval(r)
+	if err != nil {
+		return nil, err
+	}
+	cmp, ok := compareValues(left, right)
+	if !ok {
+		return nil, nil
+	}
+	switch e.op {
+	case "=":
+		return cmp == 0, nil
+	case "!=", "<>":
+		return cmp != 0, nil
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	default:
+		return cmp >= 0, nil
+	}
+}
+
+// compareValues returns -1, 0 or 1 as 'a' is less than, equal to or greater
+// than 'b'. Numbers compare with strings that are numbers as numbers, and
+// everything else is compared as strings. It returns false if either value
+// is NULL, or if a number is compared with a string that isn't one.
+func compareValues(a, b interface{}) (int, bool) {
+	if a == nil || b == nil {
+		return 0, false
+	}
+	af, aNum := a.(float64)
+	bf, bNum := b.(float64)
+	if aNum || bNum {
+		var err error
+		if !aNum {
+			af, err = toFloat(a)
+		} else if !bNum {
+			bf, err = toFloat(b)
+		}
+		if err != nil {
+			return 0, false
+		}
+		switch {
+		case af < bf:
+			return -1, true
+		case af > bf:
+			return 1, true
+		}
+		return 0, true
+	}
+	return strings.Compare(toString(a), toString(b)), true
+}
+
+type isNullExpr struct {
+	expr sqlExpr
+}
+
+func (e *isNullExpr) eval(r selectRecord) (interface{}, error) {
+	value, err := e.expr.eval(r)
+	if err != nil {
+		return nil, err
+	}
+	return value == nil, nil
+}
+
+type likeExpr struct {
+	left    sqlExpr
+	pattern *regexp.Regexp
+}
+
+// likePattern translates a LIKE pattern, where '%' matches any string and '_'
+// any character, to a regexp
+func likePattern(pattern string) *regexp.Regexp {
+	var re strings.Builder
+	re.WriteString("(?s)^")
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			re.WriteString(".*")
+		case '_':
+			re.WriteString(".")
+		default:
+			re.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	re.WriteString("$")
+	return regexp.MustCompile(re.String())
+}
+
+func (e *likeExpr) eval(r selectRecord) (interface{}, error) {
+	value, err := e.left.eval(r)
+	if err != nil || value == nil {
+		return nil, err
+	}
+	return e.pattern.MatchString(toString(value)), nil
+}
+
+type inExpr struct {
+	left   sqlExpr
+	values []sqlExpr
+}
+
+func (e *inExpr) eval(r selectRecord) (interface{}, error) {
+	left, err := e.left.eval(r)
+	if err != nil || left == nil {
+		return nil, err
+	}
+	for _, v := range e.values {
+		value, err := v.eval(r)
+		if err != nil {
+			return nil, err
+		}
+		if cmp, ok := compareValues(left, value); ok && cmp == 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type castExpr struct {
+	expr sqlExpr
+	typ  string
+}
+
+func (e *castExpr) eval(r selectRecord) (interface{}, error) {
+	value, err := e.expr.eval(r)
+	if err != nil || value == nil {
+		return nil, err
+	}
+	switch e.typ {
+	case "INT", "INTEGER":
+		f, err := toFloat(value)
+		if err != nil {
+			return nil, err
+		}
+		return float64(int64(f)), nil
+	case "FLOAT", "DECIMAL":
+		return toFloat(value)
+	case "BOOL":
+		return toBool(value)
+	default:
+		return toString(value), nil
+	}
+}
+
+func toFloat(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("cannot cast %q to a number", v)
+		}
+		return f, nil
+	}
+	return 0, fmt.Errorf("cannot cast %v to a number", value)
+}
+
+func toBool(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case float64:
+		return v != 0, nil
+	case string:
+		b, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			return false, fmt.Errorf("cannot cast %q to a boolean", v)
+		}
+		return b, nil
+	}
+	return false, fmt.Errorf("cannot cast %v to a boolean", value)
+}
+
+// toString returns the text of a value, as it's written in CSV output
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,187 @@
+package s3
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+// readEvents decodes an event stream into its messages' headers and payloads,
+// checking their CRCs
+func readEvents(t *testing.T, stream []byte) ([]map[string]string, [][]byte) {
+	t.Helper()
+	var headers []map[string]string
+	var payloads [][]byte
+	r := bytes.NewReader(stream)
+	for r.Len() > 0 {
+		var prelude [12]byte
+		_, err := io.ReadFull(r, prelude[:])
+		require.NoError(t, err)
+		total := binary.BigEndian.Uint32(prelude[0:4])
+		headersLen := binary.BigEndian.Uint32(prelude[4:8])
+		require.Equal(t, crc32.ChecksumIEEE(prelude[:8]), binary.BigEndian.Uint32(prelude[8:12]))
+		rest := make([]byte, total-12)
+		_, err = io.ReadFull(r, rest)
+		require.NoError(t, err)
+		message := append(prelude[:], rest...)
+		require.Equal(t, crc32.ChecksumIEEE(message[:total-4]), binary.BigEndian.Uint32(message[total-4:]))
+
+		h := make(map[string]string)
+		hr := bytes.NewReader(rest[:headersLen])
+		for hr.Len() > 0 {
+			nameLen, _ := hr.ReadByte()
+			name := make([]byte, nameLen)
+			io.ReadFull(hr, name)
+			typ, _ := hr.ReadByte()
+			require.Equal(t, byte(7), typ)
+			var valueLen uint16
+			binary.Read(hr, binary.BigEndian, &valueLen)
+			value := make([]byte, valueLen)
+			io.ReadFull(hr, value)
+			h[string(name)] = string(value)
+		}
+		headers = append(headers, h)
+		payloads = append(payloads, rest[headersLen:len(rest)-4])
+	}
+	return headers, payloads
+}
+
+func runTestSelect(t *testing.T, expression string, input *inputSerialization, output *outputSerialization, data string) string {
+	t.Helper()
+	query, err := parseSelect(expression)
+	require.NoError(t, err)
+	var stream bytes.Buffer
+	returned, err := runSelect(query, input, output, strings.NewReader(data), &eventWriter{w: &stream})
+	require.NoError(t, err)
+	headers, payloads := readEvents(t, stream.Bytes())
+	var result bytes.Buffer
+	for i, h := range headers {
+		require.Equal(t, "Records", h[":event-type"])
+		require.Equal(t, "event", h[":message-type"])
+		result.Write(payloads[i])
+	}
+	require.Equal(t, int64(result.Len()), returned)
+	return result.String()
+}
+
+const selectCSV = `name,team,score
+alice,red,10
+bob,blue,7
+"carol, jr",red,12
+dave,green,
+`
+
+func TestSelectCSV(t *testing.T) {
+	useHeader := &inputSerialization{CSV: &csvInput{FileHeaderInfo: "USE"}}
+	csvOut := &outputSerialization{CSV: &csvOutput{}}
+	jsonOut := &outputSerialization{JSON: &jsonOutput{}}
+
+	require.Equal(t, "alice,red,10\nbob,blue,7\n\"carol, jr\",red,12\ndave,green,\n",
+		runTestSelect(t, "SELECT * FROM S3Object", useHeader, csvOut, selectCSV))
+	require.Equal(t, "alice\n\"carol, jr\"\n",
+		runTestSelect(t, "select s.name from s3object s where s.score > 9", useHeader, csvOut, selectCSV))
+	require.Equal(t, "bob,7\n",
+		runTestSelect(t, `SELECT name, "score" FROM S3Object WHERE team = 'blue' OR score < 0`, useHeader, csvOut, selectCSV))
+	require.Equal(t, "dave\n",
+		runTestSelect(t, "SELECT s.name FROM S3Object AS s WHERE s.score = ''", useHeader, csvOut, selectCSV))
+	require.Equal(t, "alice\n",
+		runTestSelect(t, "SELECT s.name FROM S3Object s WHERE s.team IN ('red', 'green') LIMIT 1", useHeader, csvOut, selectCSV))
+	require.Equal(t, "bob\ndave\n",
+		runTestSelect(t, "SELECT s.name FROM S3Object s WHERE NOT s.team LIKE 'r%'", useHeader, csvOut, selectCSV))
+	require.Equal(t, "2\n",
+		runTestSelect(t, "SELECT COUNT(*) FROM S3Object s WHERE s.team = 'red' AND CAST(s.score AS INT) >= 10", useHeader, csvOut, selectCSV))
+	require.Equal(t, `{"who":"alice","_2":"red"}`+"\n",
+		runTestSelect(t, "SELECT s.name AS who, s._2 FROM S3Object s WHERE s.name = 'alice'", useHeader, jsonOut, selectCSV))
+
+	// Without a header, columns are referred to by position, and the header
+	// is a record
+	noHeader := &inputSerialization{CSV: &csvInput{}}
+	require.Equal(t, "name\nalice\n",
+		runTestSelect(t, "SELECT _1 FROM S3Object LIMIT 2", noHeader, csvOut, selectCSV))
+	ignoreHeader := &inputSerialization{CSV: &csvInput{FileHeaderInfo: "IGNORE", FieldDelimiter: "|"}}
+	require.Equal(t, `"a|b";"c"`+"\r\n",
+		runTestSelect(t, "SELECT _2, _1 FROM S3Object", ignoreHeader,
+			&outputSerialization{CSV: &csvOutput{FieldDelimiter: ";", RecordDelimiter: "\r\n", QuoteFields: "ALWAYS"}},
+			"x|y\nc|\"a|b\"\n"))
+}
+
+func TestSelectJSON(t *testing.T) {
+	data := `{"name": "alice", "address": {"city": "Paris"}, "age": 31}
+{"name": "bob", "address": {"city": "Oslo"}, "age": 25, "tags": ["x"]}
+`
+	lines := &inputSerialization{JSON: &jsonInput{Type: "LINES"}}
+	jsonOut := &outputSerialization{JSON: &jsonOutput{}}
+	require.Equal(t, `{"name":"alice","address":{"city":"Paris"},"age":31}`+"\n",
+		runTestSelect(t, "SELECT * FROM S3Object s WHERE s.age > 30", lines, jsonOut, data))
+	require.Equal(t, `{"city":"Oslo","tags":["x"]}`+"\n",
+		runTestSelect(t, "SELECT s.address.city, s.tags FROM S3Object[*] s WHERE s.tags IS NOT NULL", lines, jsonOut, data))
+	require.Equal(t, "Paris,\nOslo,\"[\"\"x\"\"]\"\n",
+		runTestSelect(t, "SELECT s.address.city, s.tags FROM S3Object s", lines, &outputSerialization{CSV: &csvOutput{}}, data))
+
+	// The elements of a top-level array are records
+	document := &inputSerialization{JSON: &jsonInput{Type: "DOCUMENT"}}
+	require.Equal(t, "2\n",
+		runTestSelect(t, "SELECT COUNT(*) FROM S3Object[*] s WHERE s.ok", document, &outputSerialization{CSV: &csvOutput{}},
+			`[{"ok": true}, {"ok": false}, {"ok": true}, {}]`))
+}
+
+func TestParseSelect(t *testing.T) {
+	for _, query := range []string{
+		"",
+		"SELECT",
+		"SELECT * FROM",
+		"SELECT * FROM table",
+		"SELECT * FROM S3Object WHERE",
+		"SELECT * FROM S3Object LIMIT -1",
+		"SELECT * FROM S3Object s WHERE s.a = 'unterminated",
+		"SELECT s.a FROM S3Object s GROUP BY s.a",
+		"SELECT CAST(s.a AS DATE) FROM S3Object s",
+		"SELECT s.a ! 1 FROM S3Object s",
+	} {
+		_, err := parseSelect(query)
+		require.YesError(t, err, query)
+	}
+	q, err := parseSelect("SELECT s.a, s.b AS c, (s.d = 1) FROM S3Object s WHERE (s.a = 'it''s' OR s.b <> -1.5) AND s.c IS NULL LIMIT 10")
+	require.NoError(t, err)
+	require.Equal(t, int64(10), q.limit)
+	require.Equal(t, 3, len(q.items))
+	require.Equal(t, "a", q.items[0].name)
+	require.Equal(t, "c", q.items[1].name)
+	require.Equal(t, "_3", q.items[2].name)
+
+	record := &csvRecord{header: []string{"a", "b"}, values: []string{"it's", "2"}}
+	ok, err := evalBool(q.where, record)
+	require.NoError(t, err)
+	require.True(t, *ok)
+}
+
+func TestEventStream(t *testing.T) {
+	var stream bytes.Buffer
+	events := &eventWriter{w: &stream}
+	require.NoError(t, events.writeEvent("Records", "application/octet-stream", []byte("a,b\n")))
+	require.NoError(t, events.writeEvent("End", "", nil))
+	require.NoError(t, events.writeError("InternalError", "oops"))
+	headers, payloads := readEvents(t, stream.Bytes())
+	require.Equal(t, []map[string]string{
+		{":event-type": "Records", ":content-type": "application/octet-stream", ":message-type": "event"},
+		{":event-type": "End", ":message-type": "event"},
+		{":error-code": "InternalError", ":error-message": "oops", ":message-type": "error"},
+	}, headers)
+	require.Equal(t, "a,b\n", string(payloads[0]))
+	require.Equal(t, 0, len(payloads[1]))
+
+	// Results are split into payloads of at most maxSelectPayload bytes
+	stream.Reset()
+	records := &recordsWriter{events: events}
+	records.Write(bytes.Repeat([]byte("x"), maxSelectPayload+10))
+	require.NoError(t, records.flush())
+	_, payloads = readEvents(t, stream.Bytes())
+	require.Equal(t, 2, len(payloads))
+	require.Equal(t, maxSelectPayload, len(payloads[0]))
+	require.Equal(t, 10, len(payloads[1]))
+}
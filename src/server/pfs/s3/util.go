@@ -1,9 +1,12 @@
 package s3
 
 import (
+	"context"
 	"net/http"
 	"strings"
 
+	"github.com/gorilla/mux"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ancestry"
 	"github.com/pachyderm/s2"
 )
 
@@ -13,6 +16,11 @@ const globalStorageClass = "STANDARD"
 // The S3 location served back
 const globalLocation = "PACHYDERM"
 
+// targetBranchHeader selects the branch that a write lands on, in place of
+// the bucket's branch, so that e.g. writers can ingest into "staging" through
+// the bucket name that readers read "master" from
+const targetBranchHeader = "x-pachyderm-target-branch"
+
 // The S3 user associated with all PFS content
 var defaultUser = s2.User{ID: "00000000000000000000000000000000", DisplayName: "pachyderm"}
 
@@ -23,3 +31,79 @@ func bucketArgs(r *http.Request, bucket string) (string, string, error) {
 	}
 	return parts[1], parts[0], nil
 }
+
+// resolvedWriteBucket is the result of resolveWriteBucket for the bucket that
+// a request was sent to, which writeBucketMiddleware stores in the request
+type resolvedWriteBucket struct {
+	bucket string // the bucket that the request was sent to
+	target string
+	err    error
+}
+
+// writeBucketMiddleware resolves the bucket that a request's writes land in
+// once, so that the handlers and the middlewares that apply per-bucket
+// settings (limits, audit records and read-after-write) agree on it. Requests
+// whose handlers don't write (see usesWriteBucket) stay in the bucket that
+// they were sent to. It's attached to the s2 router before those middlewares.
+func writeBucketMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		if bucket := vars["bucket"]; bucket != "" {
+			resolved := &resolvedWriteBucket{bucket: bucket, target: bucket}
+			if _, isObject := vars["key"]; usesWriteBucket(r, isObject) {
+				resolved.target, resolved.err = resolveWriteBucket(r, bucket)
+			}
+			r = r.WithContext(context.WithValue(r.Context(), writeBucketKey, resolved))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// usesWriteBucket returns true if 'r' is served by a handler that calls
+// writeBucket: object writes, and multipart upload requests (which find the
+// uploads in the bucket that they're completed in)
+func usesWriteBucket(r *http.Request, isObject bool) bool {
+	query := r.URL.Query()
+	_, uploads := query["uploads"]
+	_, uploadID := query["uploadId"]
+	if !isObject {
+		_, del := query["delete"]
+		return (r.Method == "GET" && uploads) || (r.Method == "POST" && del)
+	}
+	switch r.Method {
+	case "PUT", "DELETE":
+		return true
+	case "POST":
+		return uploads || uploadID
+	case "GET":
+		return uploadID
+	}
+	return false
+}
+
+// writeBucket returns the bucket that a write to 'bucket' lands in (see
+// resolveWriteBucket), as resolved by writeBucketMiddleware if it ran
+func writeBucket(r *http.Request, bucket string) (string, error) {
+	if resolved, ok := r.Context().Value(writeBucketKey).(*resolvedWriteBucket); ok && resolved.bucket == bucket {
+		return resolved.target, resolved.err
+	}
+	return resolveWriteBucket(r, bucket)
+}
+
+// resolveWriteBucket returns the bucket that a write to 'bucket' lands in:
+// 'bucket' itself, or the bucket of the same repo's branch in the
+// targetBranchHeader header, if the request sets it
+func resolveWriteBucket(r *http.Request, bucket string) (string, error) {
+	branch := r.Header.Get(targetBranchHeader)
+	if branch == "" {
+		return bucket, nil
+	}
+	repo, _, err := bucketArgs(r, bucket)
+	if err != nil {
+		return "", err
+	}
+	if err := ancestry.ValidateName(branch); err != nil {
+		return "", invalidTargetBranchError(r)
+	}
+	return branch + "." + repo, nil
+}
@@ -0,0 +1,79 @@
+package s3
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/s2"
+)
+
+func TestWriteBucket(t *testing.T) {
+	r := httptest.NewRequest("PUT", "/master.images/foo", nil)
+	bucket, err := writeBucket(r, "master.images")
+	require.NoError(t, err)
+	require.Equal(t, "master.images", bucket)
+
+	r.Header.Set(targetBranchHeader, "staging")
+	bucket, err = writeBucket(r, "master.images")
+	require.NoError(t, err)
+	require.Equal(t, "staging.images", bucket)
+	repo, branch, err := bucketArgs(r, bucket)
+	require.NoError(t, err)
+	require.Equal(t, "images", repo)
+	require.Equal(t, "staging", branch)
+
+	// The target branch is checked against the policy of its own bucket
+	c := &controller{policies: map[string]bucketPolicy{"staging.images": policyReadOnly}}
+	require.YesError(t, c.canWrite(r, bucket))
+
+	for _, invalid := range []string{"staging.images", "a/b", "a b"} {
+		r.Header.Set(targetBranchHeader, invalid)
+		_, err = writeBucket(r, "master.images")
+		s2Err, ok := err.(*s2.Error)
+		require.True(t, ok)
+		require.Equal(t, http.StatusBadRequest, s2Err.HTTPStatus)
+	}
+	_, err = writeBucket(r, "images")
+	require.YesError(t, err)
+}
+
+func TestWriteBucketMiddleware(t *testing.T) {
+	resolve := func(method, target string, isObject bool) (string, error) {
+		vars := map[string]string{"bucket": "master.images"}
+		if isObject {
+			vars["key"] = "foo"
+		}
+		r := mux.SetURLVars(httptest.NewRequest(method, target, nil), vars)
+		r.Header.Set(targetBranchHeader, "staging")
+		var bucket string
+		var err error
+		writeBucketMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			bucket, err = writeBucket(r, "master.images")
+		})).ServeHTTP(httptest.NewRecorder(), r)
+		return bucket, err
+	}
+	for _, test := range []struct {
+		method, target string
+		isObject       bool
+		expected       string
+	}{
+		{"PUT", "/master.images/foo", true, "staging.images"},
+		{"DELETE", "/master.images/foo", true, "staging.images"},
+		{"PUT", "/master.images/foo?uploadId=1&partNumber=1", true, "staging.images"},
+		{"GET", "/master.images/foo?uploadId=1", true, "staging.images"},
+		{"POST", "/master.images?delete", false, "staging.images"},
+		{"GET", "/master.images?uploads", false, "staging.images"},
+		// reads and bucket requests ignore the target branch
+		{"GET", "/master.images/foo", true, "master.images"},
+		{"HEAD", "/master.images/foo", true, "master.images"},
+		{"POST", "/master.images/foo?select&select-type=2", true, "master.images"},
+		{"PUT", "/master.images", false, "master.images"},
+	} {
+		bucket, err := resolve(test.method, test.target, test.isObject)
+		require.NoError(t, err, test.method, test.target)
+		require.Equal(t, test.expected, bucket, test.method, test.target)
+	}
+}
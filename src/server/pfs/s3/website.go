@@ -0,0 +1,438 @@
+package s3
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/pachyderm/pachyderm/src/client"
+	pfsClient "github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/s2"
+)
+
+// Name of the PFS repo holding the website configurations of buckets. Each
+// bucket's configuration is stored as XML in a file named after the bucket.
+const websiteRepo = "_s3gateway_website_"
+
+// How long the gateway caches a bucket's website configuration, so that
+// serving objects doesn't cost an extra PFS read each. Configurations put
+// through this gateway take effect immediately, those put through another
+// gateway within this long.
+const websiteConfigTTL = 10 * time.Second
+
+// websiteConfiguration is the XML document set by PutBucketWebsite. Only
+// index and error documents are supported.
+type websiteConfiguration struct {
+	XMLName       xml.Name              `xml:"WebsiteConfiguration"`
+	IndexDocument *websiteIndexDocument `xml:"IndexDocument,omitempty"`
+	ErrorDocument *websiteErrorDocument `xml:"ErrorDocument,omitempty"`
+	// Other holds the configuration's unsupported settings, e.g.
+	// RedirectAllRequestsTo and RoutingRules
+	Other []unsupportedElement `xml:",any"`
+}
+
+type websiteIndexDocument struct {
+	Suffix string `xml:"Suffix"`
+}
+
+type websiteErrorDocument struct {
+	Key string `xml:"Key"`
+}
+
+func noSuchWebsiteConfigurationError(r *http.Request) *s2.Error {
+	return s2.NewError(r, http.StatusNotFound, "NoSuchWebsiteConfiguration", "The specified bucket does not have a website configuration")
+}
+
+func (cfg *websiteConfiguration) validate(r *http.Request) error {
+	if len(cfg.Other) > 0 {
+		return s2.NewError(r, http.StatusNotImplemented, "NotImplemented", fmt.Sprintf("%s is not supported in website configurations, only IndexDocument and ErrorDocument are", cfg.Other[0].XMLName.Local))
+	}
+	if cfg.IndexDocument == nil {
+		return s2.MalformedXMLError(r)
+	}
+	if suffix := cfg.IndexDocument.Suffix; suffix == "" || strings.Contains(suffix, "/") {
+		return s2.NewError(r, http.StatusBadRequest, "InvalidArgument", "The IndexDocument Suffix is not well formed")
+	}
+	if cfg.ErrorDocument != nil && (cfg.ErrorDocument.Key == "" || strings.HasSuffix(cfg.ErrorDocument.Key, "/")) {
+		return s2.NewError(r, http.StatusBadRequest, "InvalidArgument", "The ErrorDocument Key is not well formed")
+	}
+	return nil
+}
+
+// indexKey returns the key of the index document of the "directory" 'key',
+// which is the bucket's root if 'key' is empty
+func (cfg *websiteConfiguration) indexKey(key string) string {
+	if key != "" && !strings.HasSuffix(key, "/") {
+		key += "/"
+	}
+	return key + cfg.IndexDocument.Suffix
+}
+
+// isWebsiteRequest returns true if 'r', a GET or HEAD request for an object
+// (or for the root of a bucket, if 'isObject' is false), may be served from
+// a bucket's website. Requests with parameters (other than the signature of
+// a presigned URL) are regular S3 requests, e.g. for an object's version.
+// Requests for the root of a bucket are only served from its website if
+// they're from a browser, i.e. they accept HTML, as S3 clients list the
+// bucket's objects with them.
+func isWebsiteRequest(r *http.Request, isObject bool) bool {
+	if r.Method != "GET" && r.Method != "HEAD" {
+		return false
+	}
+	for param := range r.URL.Query() {
+		if !strings.HasPrefix(param, "X-Amz-") {
+			return false
+		}
+	}
+	return isObject || strings.Contains(r.Header.Get("Accept"), "text/html")
+}
+
+func (c *controller) ensureWebsiteRepo(pc *client.APIClient) error {
+	_, err := pc.InspectBranch(websiteRepo, "master")
+	if err != nil {
+		err = pc.UpdateRepo(websiteRepo)
+		if err != nil {
+			return err
+		}
+
+		err = pc.CreateBranch(websiteRepo, "master", "", nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// websiteConfig reads the website configuration of 'bucket', returning nil if
+// it has none
+func (c *controller) websiteConfig(pc *client.APIClient, bucket string) (*websiteConfiguration, error) {
+	var buf bytes.Buffer
+	if err := pc.GetFile(websiteRepo, "master", bucket, 0, 0, &buf); err != nil {
+		if pfsClient.IsFileNotFoundErr(err) || pfsClient.IsRepoNotFoundErr(err) || pfsClient.IsBranchNotFoundErr(err) || pfsClient.IsBranchNoHeadErr(err) {
+			c.cacheWebsiteConfig(bucket, nil)
+			return nil, nil
+		}
+		return nil, err
+	}
+	cfg := &websiteConfiguration{}
+	if err := xml.Unmarshal(buf.Bytes(), cfg); err != nil {
+		return nil, err
+	}
+	c.cacheWebsiteConfig(bucket, cfg)
+	return cfg, nil
+}
+
+// websiteCache holds the website configurations that the gateway has read or
+// written recently, keyed by bucket. A nil configuration means the bucket has
+// none.
+type websiteCache struct {
+	mu      sync.Mutex
+	configs map[string]cachedWebsiteConfig
+}
+
+type cachedWebsiteConfig struct {
+	cfg     *websiteConfiguration
+	expires time.Time
+}
+
+func newWebsiteCache() *websiteCache {
+	return &websiteCache{configs: make(map[string]cachedWebsiteConfig)}
+}
+
+func (c *controller) cacheWebsiteConfig(bucket string, cfg *websiteConfiguration) {
+	c.website.mu.Lock()
+	defer c.website.mu.Unlock()
+	c.website.configs[bucket] = cachedWebsiteConfig{cfg: cfg, expires: time.Now().Add(websiteConfigTTL)}
+}
+
+// cachedWebsiteConfig returns the website configuration of 'bucket', reading
+// it if it isn't cached
+func (c *controller) cachedWebsiteConfig(pc *client.APIClient, bucket string) (*websiteConfiguration, error) {
+	c.website.mu.Lock()
+	cached, ok := c.website.configs[bucket]
+	c.website.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.cfg, nil
+	}
+	return c.websiteConfig(pc, bucket)
+}
+
+// websiteMiddleware serves the `?website` bucket subresource, and serves the
+// GET and HEAD requests of buckets with a website configuration from their
+// index and error documents. It's attached to the s2 router, so it runs after
+// requests were authenticated.
+func (c *controller) websiteMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		bucket := vars["bucket"]
+		if bucket == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		key, isObject := vars["key"]
+		if _, ok := r.URL.Query()["website"]; ok && !isObject {
+			c.serveBucketWebsite(w, r, bucket)
+			return
+		}
+		if !isWebsiteRequest(r, isObject) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		// Website requests read the bucket's objects, so the bucket's policy
+		// is checked before anything is looked up in it
+		if err := c.canRead(r, bucket); err != nil {
+			c.writeError(w, r, err)
+			return
+		}
+		pc, err := c.pachClient(vars["authAccessKey"])
+		if err != nil {
+			c.writeError(w, r, err)
+			return
+		}
+		cfg, err := c.cachedWebsiteConfig(pc, bucket)
+		if err != nil {
+			// serve the request as a regular S3 request
+			c.logger.Errorf("could not read the website configuration of %s: %v", bucket, err)
+		}
+		if cfg == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		c.serveWebsite(w, r, next, cfg, bucket, key)
+	})
+}
+
+// serveWebsite serves a request for 'key' from a bucket with website
+// configuration 'cfg'. Requests for "directories" (the root of the bucket,
+// keys that end with a '/', and, redirected there, keys that are directories
+// in PFS) are served the directory's index document, and requests for
+// objects that don't exist are served the error document, if there is one.
+// Requests for objects that do exist are served by 'next', as usual.
+func (c *controller) serveWebsite(w http.ResponseWriter, r *http.Request, next http.Handler, cfg *websiteConfiguration, bucket, key string) {
+	if key == "" || strings.HasSuffix(key, "/") {
+		c.serveWebsiteObject(w, r, cfg, bucket, cfg.indexKey(key), http.StatusOK)
+		return
+	}
+	fileInfo, err := c.inspectWebsiteObject(r, bucket, key)
+	if err != nil && !pfsClient.IsFileNotFoundErr(err) {
+		// serve the error (or the object, e.g. if the error was transient)
+		// as a regular S3 request would
+		next.ServeHTTP(w, r)
+		return
+	}
+	if fileInfo != nil && fileInfo.FileType == pfsClient.FileType_FILE {
+		next.ServeHTTP(w, r)
+		return
+	}
+	if fileInfo != nil && c.objectExists(r, bucket, cfg.indexKey(key)) {
+		http.Redirect(w, r, "/"+bucket+"/"+(&url.URL{Path: key}).EscapedPath()+"/", http.StatusFound)
+		return
+	}
+	c.serveWebsiteError(w, r, cfg, bucket, s2.NoSuchKeyError(r))
+}
+
+// serveWebsiteObject serves the object 'key' with 'status', or the bucket's
+// error document if it doesn't exist
+func (c *controller) serveWebsiteObject(w http.ResponseWriter, r *http.Request, cfg *websiteConfiguration, bucket, key string, status int) {
+	result, err := c.GetObject(r, bucket, key, "")
+	if err != nil {
+		if s2Err, ok := err.(*s2.Error); ok && s2Err.Code == "NoSuchKey" && status == http.StatusOK {
+			c.serveWebsiteError(w, r, cfg, bucket, err)
+			return
+		}
+		c.writeError(w, r, err)
+		return
+	}
+	if result.ETag != "" {
+		w.Header().Set("ETag", fmt.Sprintf("\"%s\"", result.ETag))
+	}
+	if result.Version != "" {
+		w.Header().Set("x-amz-version-id", result.Version)
+	}
+	if status == http.StatusOK {
+		http.ServeContent(w, r, key, result.ModTime, result.Content)
+		return
+	}
+	if contentType := mime.TypeByExtension(path.Ext(key)); contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+	w.WriteHeader(status)
+	if r.Method != "HEAD" {
+		io.Copy(w, result.Content)
+	}
+}
+
+// serveWebsiteError serves the bucket's error document with a 404, or
+// 'err' if it has none
+func (c *controller) serveWebsiteError(w http.ResponseWriter, r *http.Request, cfg *websiteConfiguration, bucket string, err error) {
+	if cfg.ErrorDocument == nil {
+		c.writeError(w, r, err)
+		return
+	}
+	if !c.objectExists(r, bucket, cfg.ErrorDocument.Key) {
+		c.writeError(w, r, err)
+		return
+	}
+	c.serveWebsiteObject(w, r, cfg, bucket, cfg.ErrorDocument.Key, http.StatusNotFound)
+}
+
+// inspectWebsiteObject returns the file info of 'key' in the head of
+// 'bucket', which is cached along with the other object metadata
+func (c *controller) inspectWebsiteObject(r *http.Request, bucket, key string) (*pfsClient.FileInfo, error) {
+	if err := c.canRead(r, bucket); err != nil {
+		return nil, err
+	}
+	pc, err := c.requestClient(r)
+	if err != nil {
+		return nil, err
+	}
+	repo, branch, err := bucketArgs(r, bucket)
+	if err != nil {
+		return nil, err
+	}
+	return c.inspectObject(r, pc, repo, branch, branch, key)
+}
+
+// objectExists returns true if 'key' is a file in the head of 'bucket'
+func (c *controller) objectExists(r *http.Request, bucket, key string) bool {
+	fileInfo, err := c.inspectWebsiteObject(r, bucket, key)
+	return err == nil && fileInfo.FileType == pfsClient.FileType_FILE
+}
+
+func (c *controller) serveBucketWebsite(w http.ResponseWriter, r *http.Request, bucket string) {
+	var err error
+	switch r.Method {
+	case "GET":
+		var cfg *websiteConfiguration
+		if cfg, err = c.GetBucketWebsite(r, bucket); err == nil {
+			var body []byte
+			if body, err = xml.Marshal(cfg); err == nil {
+				w.Header().Set("Content-Type", "application/xml")
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprint(w, xml.Header)
+				w.Write(body)
+				return
+			}
+		}
+	case "PUT":
+		if err = c.PutBucketWebsite(r, bucket); err == nil {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+	case "DELETE":
+		if err = c.DeleteBucketWebsite(r, bucket); err == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	default:
+		err = s2.MethodNotAllowedError(r)
+	}
+	c.writeError(w, r, err)
+}
+
+// GetBucketWebsite returns the website configuration of a bucket
+func (c *controller) GetBucketWebsite(r *http.Request, bucket string) (*websiteConfiguration, error) {
+	vars := mux.Vars(r)
+	pc, err := c.pachClient(vars["authAccessKey"])
+	if err != nil {
+		return nil, err
+	}
+	repo, branch, err := bucketArgs(r, bucket)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := pc.InspectBranch(repo, branch); err != nil {
+		return nil, maybeNotFoundError(r, err)
+	}
+
+	cfg, err := c.websiteConfig(pc, bucket)
+	if err != nil {
+		return nil, s2.InternalError(r, err)
+	}
+	if cfg == nil {
+		return nil, noSuchWebsiteConfigurationError(r)
+	}
+	return cfg, nil
+}
+
+// PutBucketWebsite sets the website configuration of a bucket from the XML
+// document in the request body
+func (c *controller) PutBucketWebsite(r *http.Request, bucket string) error {
+	if err := c.canWrite(r, bucket); err != nil {
+		return err
+	}
+	vars := mux.Vars(r)
+	pc, err := c.pachClient(vars["authAccessKey"])
+	if err != nil {
+		return err
+	}
+	repo, branch, err := bucketArgs(r, bucket)
+	if err != nil {
+		return err
+	}
+	if _, err := pc.InspectBranch(repo, branch); err != nil {
+		return maybeNotFoundError(r, err)
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return s2.InternalError(r, err)
+	}
+	cfg := &websiteConfiguration{}
+	if err := xml.Unmarshal(body, cfg); err != nil {
+		return s2.MalformedXMLError(r)
+	}
+	if err := cfg.validate(r); err != nil {
+		return err
+	}
+	if err := c.ensureWebsiteRepo(pc); err != nil {
+		return s2.InternalError(r, err)
+	}
+	if _, err := pc.PutFileOverwrite(websiteRepo, "master", bucket, bytes.NewReader(body), 0); err != nil {
+		return s2.InternalError(r, err)
+	}
+	c.cacheWebsiteConfig(bucket, cfg)
+	return nil
+}
+
+// DeleteBucketWebsite removes the website configuration of a bucket
+func (c *controller) DeleteBucketWebsite(r *http.Request, bucket string) error {
+	if err := c.canWrite(r, bucket); err != nil {
+		return err
+	}
+	vars := mux.Vars(r)
+	pc, err := c.pachClient(vars["authAccessKey"])
+	if err != nil {
+		return err
+	}
+	repo, branch, err := bucketArgs(r, bucket)
+	if err != nil {
+		return err
+	}
+	if _, err := pc.InspectBranch(repo, branch); err != nil {
+		return maybeNotFoundError(r, err)
+	}
+
+	cfg, err := c.websiteConfig(pc, bucket)
+	if err != nil {
+		return s2.InternalError(r, err)
+	}
+	if cfg != nil {
+		if err := pc.DeleteFile(websiteRepo, "master", bucket); err != nil {
+			return s2.InternalError(r, err)
+		}
+	}
+	c.cacheWebsiteConfig(bucket, nil)
+	return nil
+}
@@ -0,0 +1,48 @@
+package s3
+
+import (
+	"encoding/xml"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestWebsiteConfiguration(t *testing.T) {
+	r := httptest.NewRequest("PUT", "/master.reports?website", nil)
+	parse := func(doc string) (*websiteConfiguration, error) {
+		cfg := &websiteConfiguration{}
+		require.NoError(t, xml.Unmarshal([]byte(doc), cfg))
+		return cfg, cfg.validate(r)
+	}
+
+	cfg, err := parse(`<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument><ErrorDocument><Key>404.html</Key></ErrorDocument></WebsiteConfiguration>`)
+	require.NoError(t, err)
+	require.Equal(t, "index.html", cfg.indexKey(""))
+	require.Equal(t, "docs/index.html", cfg.indexKey("docs/"))
+	require.Equal(t, "docs/index.html", cfg.indexKey("docs"))
+	require.Equal(t, "404.html", cfg.ErrorDocument.Key)
+
+	_, err = parse(`<WebsiteConfiguration><ErrorDocument><Key>404.html</Key></ErrorDocument></WebsiteConfiguration>`)
+	require.YesError(t, err)
+	_, err = parse(`<WebsiteConfiguration><IndexDocument><Suffix>a/index.html</Suffix></IndexDocument></WebsiteConfiguration>`)
+	require.YesError(t, err)
+	_, err = parse(`<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument><RoutingRules></RoutingRules></WebsiteConfiguration>`)
+	require.YesError(t, err)
+}
+
+func TestIsWebsiteRequest(t *testing.T) {
+	require.True(t, isWebsiteRequest(httptest.NewRequest("GET", "/master.reports/docs/", nil), true))
+	require.True(t, isWebsiteRequest(httptest.NewRequest("HEAD", "/master.reports/docs/", nil), true))
+	require.True(t, isWebsiteRequest(httptest.NewRequest("GET", "/master.reports/docs/?X-Amz-Signature=abc", nil), true))
+	require.False(t, isWebsiteRequest(httptest.NewRequest("PUT", "/master.reports/docs/a.html", nil), true))
+	require.False(t, isWebsiteRequest(httptest.NewRequest("GET", "/master.reports/a.html?versionId=1", nil), true))
+
+	// listing a bucket isn't served from its website, unless it's from a
+	// browser
+	r := httptest.NewRequest("GET", "/master.reports/", nil)
+	require.False(t, isWebsiteRequest(r, false))
+	r.Header.Set("Accept", "text/html,application/xhtml+xml")
+	require.True(t, isWebsiteRequest(r, false))
+	require.False(t, isWebsiteRequest(httptest.NewRequest("GET", "/master.reports/?list-type=2", nil), false))
+}
@@ -18,6 +18,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
 )
 
 // apiServer implements the public interface of the Pachyderm File System,
@@ -61,7 +62,7 @@ func (a *apiServer) CreateRepoInTransaction(
 	txnCtx *txnenv.TransactionContext,
 	request *pfs.CreateRepoRequest,
 ) error {
-	return a.driver.createRepo(txnCtx, request.Repo, request.Description, request.Update)
+	return a.driver.createRepo(txnCtx, request.Repo, request.Description, request.IndexExtractors, request.StoragePolicy, request.CommitTTL, request.Update)
 }
 
 // CreateRepo implements the protobuf pfs.CreateRepo RPC
@@ -227,6 +228,12 @@ func (a *apiServer) FinishCommit(ctx context.Context, request *pfs.FinishCommitR
 	}); err != nil {
 		return nil, err
 	}
+	if !a.env.NewStorageLayer {
+		// The request's context is cancelled when FinishCommit returns, but
+		// its credentials are needed to read the commit
+		md, _ := metadata.FromIncomingContext(ctx)
+		a.driver.indexCommitInBackground(a.env.GetPachClient(metadata.NewIncomingContext(context.Background(), md)), request.Commit)
+	}
 	return &types.Empty{}, nil
 }
 
@@ -243,7 +250,11 @@ func (a *apiServer) ListCommit(ctx context.Context, request *pfs.ListCommitReque
 	func() { a.Log(request, nil, nil, 0) }()
 	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
 
-	commitInfos, err := a.driver.listCommit(a.env.GetPachClient(ctx), request.Repo, request.To, request.From, request.Number, request.Reverse)
+	filter, err := newCommitFilter(request)
+	if err != nil {
+		return nil, err
+	}
+	commitInfos, err := a.driver.listCommit(a.env.GetPachClient(ctx), request.Repo, request.To, request.From, request.Number, request.Reverse, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -259,7 +270,11 @@ func (a *apiServer) ListCommitStream(request *pfs.ListCommitRequest, respServer
 	defer func(start time.Time) {
 		a.Log(request, fmt.Sprintf("stream containing %d commits", sent), retErr, time.Since(start))
 	}(time.Now())
-	return a.driver.listCommitF(a.env.GetPachClient(respServer.Context()), request.Repo, request.To, request.From, request.Number, request.Reverse, func(ci *pfs.CommitInfo) error {
+	filter, err := newCommitFilter(request)
+	if err != nil {
+		return err
+	}
+	return a.driver.listCommitF(a.env.GetPachClient(respServer.Context()), request.Repo, request.To, request.From, request.Number, request.Reverse, filter, func(ci *pfs.CommitInfo) error {
 		sent++
 		return respServer.Send(ci)
 	})
@@ -271,7 +286,7 @@ func (a *apiServer) CreateBranchInTransaction(
 	txnCtx *txnenv.TransactionContext,
 	request *pfs.CreateBranchRequest,
 ) error {
-	return a.driver.createBranch(txnCtx, request.Branch, request.Head, request.Provenance)
+	return a.driver.createBranch(txnCtx, request.Branch, request.Head, request.Provenance, request.Protection)
 }
 
 // CreateBranch implements the protobuf pfs.CreateBranch RPC
@@ -359,6 +374,20 @@ func (a *apiServer) DeleteCommit(ctx context.Context, request *pfs.DeleteCommitR
 	return &types.Empty{}, nil
 }
 
+// ExpireCommits implements the protobuf pfs.ExpireCommits RPC
+func (a *apiServer) ExpireCommits(ctx context.Context, request *pfs.ExpireCommitsRequest) (response *pfs.CommitInfos, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+
+	commitInfos, err := a.driver.expireCommits(a.env.GetPachClient(ctx), request.Repo, request.DryRun)
+	if err != nil {
+		return nil, err
+	}
+	return &pfs.CommitInfos{
+		CommitInfo: commitInfos,
+	}, nil
+}
+
 // FlushCommit implements the protobuf pfs.FlushCommit RPC
 func (a *apiServer) FlushCommit(request *pfs.FlushCommitRequest, stream pfs.API_FlushCommitServer) (retErr error) {
 	func() { a.Log(request, nil, nil, 0) }()
@@ -372,7 +401,22 @@ func (a *apiServer) SubscribeCommit(request *pfs.SubscribeCommitRequest, stream
 	func() { a.Log(request, nil, nil, 0) }()
 	defer func(start time.Time) { a.Log(request, nil, retErr, time.Since(start)) }(time.Now())
 
-	return a.driver.subscribeCommit(a.env.GetPachClient(stream.Context()), request.Repo, request.Branch, request.Prov, request.From, request.State, stream.Send)
+	return a.driver.subscribeCommit(a.env.GetPachClient(stream.Context()), request.Repo, request.Branch, request.Prov, request.From, request.State, request.ValidOnly, stream.Send)
+}
+
+// SubscribeFileChanges implements the protobuf pfs.SubscribeFileChanges RPC
+func (a *apiServer) SubscribeFileChanges(request *pfs.SubscribeFileChangesRequest, stream pfs.API_SubscribeFileChangesServer) (retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, nil, retErr, time.Since(start)) }(time.Now())
+
+	pachClient := a.env.GetPachClient(stream.Context())
+	return a.driver.subscribeCommit(pachClient, request.Repo, request.Branch, nil, request.From, pfs.CommitState_FINISHED, false, func(commitInfo *pfs.CommitInfo) error {
+		event, err := a.driver.fileChanges(pachClient, commitInfo)
+		if err != nil {
+			return err
+		}
+		return stream.Send(event)
+	})
 }
 
 // PutFile implements the protobuf pfs.PutFile RPC
@@ -422,6 +466,9 @@ func (a *apiServer) GetFile(request *pfs.GetFileRequest, apiGetFileServer pfs.AP
 		a.Log(request, nil, retErr, time.Since(start))
 	}(time.Now())
 
+	if request.Delimiter != pfs.Delimiter_NONE && (request.OffsetBytes != 0 || request.SizeBytes != 0) {
+		return fmt.Errorf("files can't be read by both bytes and records, use offset_records and size_records with a delimiter")
+	}
 	var file io.Reader
 	var err error
 	if a.env.NewStorageLayer {
@@ -432,6 +479,14 @@ func (a *apiServer) GetFile(request *pfs.GetFileRequest, apiGetFileServer pfs.AP
 	if err != nil {
 		return err
 	}
+	if request.Delimiter != pfs.Delimiter_NONE {
+		records, err := sliceRecords(file, request.Delimiter, request.OffsetRecords, request.SizeRecords)
+		if err != nil {
+			return err
+		}
+		defer records.Close()
+		file = records
+	}
 	return grpcutil.WriteToStreamingBytesServer(file, apiGetFileServer)
 }
 
@@ -448,7 +503,15 @@ func (a *apiServer) InspectFile(ctx context.Context, request *pfs.InspectFileReq
 		}
 	}(time.Now())
 
-	return a.driver.inspectFile(a.env.GetPachClient(ctx), request.File)
+	pachClient := a.env.GetPachClient(ctx)
+	fileInfo, err := a.driver.inspectFile(pachClient, request.File)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.driver.setChecksums(pachClient, fileInfo, request.Checksums); err != nil {
+		return nil, err
+	}
+	return fileInfo, nil
 }
 
 // ListFile implements the protobuf pfs.ListFile RPC
@@ -463,16 +526,16 @@ func (a *apiServer) ListFile(ctx context.Context, request *pfs.ListFileRequest)
 		}
 	}(time.Now())
 
-	var fileInfos []*pfs.FileInfo
-	if err := a.driver.listFile(a.env.GetPachClient(ctx), request.File, request.Full, request.History, func(fi *pfs.FileInfo) error {
-		fileInfos = append(fileInfos, fi)
-		return nil
-	}); err != nil {
+	page, err := newFilePage(request)
+	if err != nil {
 		return nil, err
 	}
-	return &pfs.FileInfos{
-		FileInfo: fileInfos,
-	}, nil
+	result := &pfs.FileInfos{}
+	if err := a.driver.listFile(a.env.GetPachClient(ctx), request.File, request.Full, request.History, page,
+		collectFileInfos(result, request.SizeOnly, request.Summary)); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
 // ListFileStream implements the protobuf pfs.ListFileStream RPC
@@ -482,7 +545,17 @@ func (a *apiServer) ListFileStream(request *pfs.ListFileRequest, respServer pfs.
 	defer func(start time.Time) {
 		a.Log(request, fmt.Sprintf("response stream with %d objects", sent), retErr, time.Since(start))
 	}(time.Now())
-	return a.driver.listFile(a.env.GetPachClient(respServer.Context()), request.File, request.Full, request.History, func(fi *pfs.FileInfo) error {
+	if request.Summary {
+		return fmt.Errorf("summary is not supported by ListFileStream, use ListFile instead")
+	}
+	page, err := newFilePage(request)
+	if err != nil {
+		return err
+	}
+	return a.driver.listFile(a.env.GetPachClient(respServer.Context()), request.File, request.Full, request.History, page, func(fi *pfs.FileInfo) error {
+		if request.SizeOnly {
+			fi = sizeOnlyFileInfo(fi)
+		}
 		sent++
 		return respServer.Send(fi)
 	})
@@ -495,10 +568,17 @@ func (a *apiServer) WalkFile(request *pfs.WalkFileRequest, server pfs.API_WalkFi
 	defer func(start time.Time) {
 		a.Log(request, fmt.Sprintf("response stream with %d objects", sent), retErr, time.Since(start))
 	}(time.Now())
-	return a.driver.walkFile(a.env.GetPachClient(server.Context()), request.File, func(fi *pfs.FileInfo) error {
+	walk, err := newFileWalk(request, func(fi *pfs.FileInfo) error {
 		sent++
 		return server.Send(fi)
 	})
+	if err != nil {
+		return err
+	}
+	if err := a.driver.walkFile(a.env.GetPachClient(server.Context()), request.File, walk.visit); err != nil {
+		return err
+	}
+	return walk.finish()
 }
 
 // GlobFile implements the protobuf pfs.GlobFile RPC
@@ -513,16 +593,12 @@ func (a *apiServer) GlobFile(ctx context.Context, request *pfs.GlobFileRequest)
 		}
 	}(time.Now())
 
-	var fileInfos []*pfs.FileInfo
-	if err := a.driver.globFile(a.env.GetPachClient(ctx), request.Commit, request.Pattern, func(fi *pfs.FileInfo) error {
-		fileInfos = append(fileInfos, fi)
-		return nil
-	}); err != nil {
+	result := &pfs.FileInfos{}
+	if err := a.driver.globFile(a.env.GetPachClient(ctx), request.Commit, request.Pattern,
+		collectFileInfos(result, request.SizeOnly, request.Summary)); err != nil {
 		return nil, err
 	}
-	return &pfs.FileInfos{
-		FileInfo: fileInfos,
-	}, nil
+	return result, nil
 }
 
 // GlobFileStream implements the protobuf pfs.GlobFileStream RPC
@@ -532,12 +608,52 @@ func (a *apiServer) GlobFileStream(request *pfs.GlobFileRequest, respServer pfs.
 	defer func(start time.Time) {
 		a.Log(request, fmt.Sprintf("response stream with %d objects", sent), retErr, time.Since(start))
 	}(time.Now())
+	if request.Summary {
+		return fmt.Errorf("summary is not supported by GlobFileStream, use GlobFile instead")
+	}
 	return a.driver.globFile(a.env.GetPachClient(respServer.Context()), request.Commit, request.Pattern, func(fi *pfs.FileInfo) error {
+		if request.SizeOnly {
+			fi = sizeOnlyFileInfo(fi)
+		}
 		sent++
 		return respServer.Send(fi)
 	})
 }
 
+// collectFileInfos returns a callback for listFile and globFile that adds
+// each FileInfo to 'result'. If 'summary' is set, it only adds the number and
+// total size of the regular files to 'result' instead (directories' sizes
+// include their children, so they aren't counted).
+func collectFileInfos(result *pfs.FileInfos, sizeOnly, summary bool) func(*pfs.FileInfo) error {
+	return func(fi *pfs.FileInfo) error {
+		if summary {
+			if fi.FileType == pfs.FileType_FILE {
+				result.Count++
+				result.SizeBytes += fi.SizeBytes
+			}
+			return nil
+		}
+		if sizeOnly {
+			fi = sizeOnlyFileInfo(fi)
+		}
+		result.FileInfo = append(result.FileInfo, fi)
+		return nil
+	}
+}
+
+// sizeOnlyFileInfo returns a copy of 'fi' without its commit, objects, block
+// refs and children
+func sizeOnlyFileInfo(fi *pfs.FileInfo) *pfs.FileInfo {
+	return &pfs.FileInfo{
+		File:      &pfs.File{Path: fi.File.Path},
+		FileType:  fi.FileType,
+		SizeBytes: fi.SizeBytes,
+		Committed: fi.Committed,
+		Hash:      fi.Hash,
+		FileCount: fi.FileCount,
+	}
+}
+
 // DiffFile implements the protobuf pfs.DiffFile RPC
 func (a *apiServer) DiffFile(ctx context.Context, request *pfs.DiffFileRequest) (response *pfs.DiffFileResponse, retErr error) {
 	func() { a.Log(request, nil, nil, 0) }()
@@ -562,6 +678,23 @@ func (a *apiServer) DiffFile(ctx context.Context, request *pfs.DiffFileRequest)
 	}, nil
 }
 
+// SearchFiles implements the protobuf pfs.SearchFiles RPC
+func (a *apiServer) SearchFiles(ctx context.Context, request *pfs.SearchFilesRequest) (response *pfs.SearchFilesResponse, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) {
+		if response != nil && len(response.Files) > client.MaxListItemsLog {
+			logrus.Infof("Response contains %d objects; logging the first %d", len(response.Files), client.MaxListItemsLog)
+			a.Log(request, &pfs.SearchFilesResponse{Commit: response.Commit, Files: response.Files[:client.MaxListItemsLog]}, retErr, time.Since(start))
+		} else {
+			a.Log(request, response, retErr, time.Since(start))
+		}
+	}(time.Now())
+	if a.env.NewStorageLayer {
+		return nil, fmt.Errorf("SearchFiles is not supported with the new storage layer")
+	}
+	return a.driver.searchFiles(a.env.GetPachClient(ctx), request.Commit, request.Pattern, request.Attributes)
+}
+
 // DeleteFile implements the protobuf pfs.DeleteFile RPC
 func (a *apiServer) DeleteFile(ctx context.Context, request *pfs.DeleteFileRequest) (response *types.Empty, retErr error) {
 	func() { a.Log(request, nil, nil, 0) }()
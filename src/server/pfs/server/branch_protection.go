@@ -0,0 +1,95 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/auth"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+	txnenv "github.com/pachyderm/pachyderm/src/server/pkg/transactionenv"
+)
+
+// checkBranchProtection returns an error if a commit may not be made on
+// 'branchInfo' by 'committer' (the caller's username, or "" if auth isn't
+// active). 'hasProvenance' is whether the commit has provenance.
+func checkBranchProtection(branchInfo *pfs.BranchInfo, committer string, hasProvenance bool) error {
+	protection := branchInfo.Protection
+	if protection == nil {
+		return nil
+	}
+	if protection.RequireProvenance && !hasProvenance {
+		return fmt.Errorf("branch %s@%s is protected, and only accepts commits with provenance (e.g. a pipeline's output)",
+			branchInfo.Branch.Repo.Name, branchInfo.Branch.Name)
+	}
+	if committer == "" || len(protection.AllowedCommitters) == 0 {
+		return nil
+	}
+	for _, allowed := range protection.AllowedCommitters {
+		if allowed == committer {
+			return nil
+		}
+	}
+	return fmt.Errorf("branch %s@%s is protected, and %s is not allowed to commit to it",
+		branchInfo.Branch.Repo.Name, branchInfo.Branch.Name, committer)
+}
+
+// committer returns the username of the caller, or "" if auth isn't active
+func committer(ctx context.Context, authClient auth.APIClient) (string, error) {
+	me, err := authClient.WhoAmI(ctx, &auth.WhoAmIRequest{})
+	if auth.IsErrNotActivated(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return me.Username, nil
+}
+
+// checkWriteProtection returns an error if the caller may not write files to
+// 'branch' of 'repo'. 'commitInfo' is the open commit that the files are
+// written to, or nil if a new commit will be started on the branch for them.
+// It's checked before the files are written, as a new commit's own check (in
+// makeCommit) only happens once they're uploaded.
+func (d *driver) checkWriteProtection(pachClient *client.APIClient, repo string, branch string, commitInfo *pfs.CommitInfo) error {
+	if commitInfo != nil {
+		branch = ""
+		if commitInfo.Branch != nil {
+			branch = commitInfo.Branch.Name
+		}
+	}
+	if branch == "" {
+		return nil
+	}
+	var branchInfo *pfs.BranchInfo
+	if err := d.txnEnv.WithReadContext(pachClient.Ctx(), func(txnCtx *txnenv.TransactionContext) error {
+		var err error
+		branchInfo, err = d.inspectBranch(txnCtx, client.NewBranch(repo, branch))
+		return err
+	}); err != nil {
+		if col.IsErrNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if branchInfo.Protection == nil {
+		return nil
+	}
+	// A new commit on the branch gets the branch's provenance
+	hasProvenance := len(branchInfo.Provenance) > 0
+	if commitInfo != nil {
+		hasProvenance = len(commitInfo.Provenance) > 0
+	}
+	me, err := committer(pachClient.Ctx(), pachClient.AuthAPIClient)
+	if err != nil {
+		return err
+	}
+	return checkBranchProtection(branchInfo, me, hasProvenance)
+}
+
+// isEmptyProtection returns true if 'protection' doesn't restrict commits, in
+// which case CreateBranch removes a branch's protection
+func isEmptyProtection(protection *pfs.BranchProtection) bool {
+	return !protection.RequireProvenance && len(protection.AllowedCommitters) == 0
+}
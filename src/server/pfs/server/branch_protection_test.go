@@ -0,0 +1,27 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestCheckBranchProtection(t *testing.T) {
+	branchInfo := &pfs.BranchInfo{Branch: client.NewBranch("repo", "master")}
+	require.NoError(t, checkBranchProtection(branchInfo, "github:alice", false))
+
+	branchInfo.Protection = &pfs.BranchProtection{RequireProvenance: true}
+	require.YesError(t, checkBranchProtection(branchInfo, "", false))
+	require.NoError(t, checkBranchProtection(branchInfo, "", true))
+
+	branchInfo.Protection = &pfs.BranchProtection{AllowedCommitters: []string{"robot:ci", "pipeline:edges"}}
+	require.NoError(t, checkBranchProtection(branchInfo, "robot:ci", false))
+	require.YesError(t, checkBranchProtection(branchInfo, "github:alice", true))
+	// committers are only checked if auth is active
+	require.NoError(t, checkBranchProtection(branchInfo, "", false))
+
+	require.False(t, isEmptyProtection(branchInfo.Protection))
+	require.True(t, isEmptyProtection(&pfs.BranchProtection{}))
+}
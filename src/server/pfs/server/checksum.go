@@ -0,0 +1,151 @@
+package server
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/server/pkg/errutil"
+	"github.com/pachyderm/pachyderm/src/server/pkg/hashtree"
+)
+
+// Checksums (see pfs.ChecksumAlgorithm) are cached in object storage, as
+// objects tagged with checksumTag. They're keyed by the hash of the file's
+// content (pfs.FileInfo.Hash), so any file with the same content shares them,
+// and a file whose content changes simply no longer finds them. Garbage
+// collection deletes them like any other tag that no commit refers to, in
+// which case they're computed again the next time they're asked for.
+
+// checksumTag is the tag of the object that caches the 'algorithm' checksum
+// of the content of files whose hash is 'fileHash'
+func checksumTag(algorithm pfs.ChecksumAlgorithm, fileHash []byte) string {
+	return fmt.Sprintf("checksum-%s-%x", strings.ToLower(algorithm.String()), fileHash)
+}
+
+// checksummer computes the checksums, in one or more algorithms, of the
+// content written to it
+type checksummer struct {
+	algorithms []pfs.ChecksumAlgorithm
+	hashes     []hash.Hash
+}
+
+// newChecksummer returns a checksummer for 'algorithms', ignoring repeats,
+// or nil if there are none
+func newChecksummer(algorithms []pfs.ChecksumAlgorithm) (*checksummer, error) {
+	if len(algorithms) == 0 {
+		return nil, nil
+	}
+	c := &checksummer{}
+	seen := make(map[pfs.ChecksumAlgorithm]bool)
+	for _, algorithm := range algorithms {
+		if seen[algorithm] {
+			continue
+		}
+		seen[algorithm] = true
+		var h hash.Hash
+		switch algorithm {
+		case pfs.ChecksumAlgorithm_SHA256:
+			h = sha256.New()
+		case pfs.ChecksumAlgorithm_MD5:
+			h = md5.New()
+		default:
+			return nil, fmt.Errorf("invalid checksum algorithm %v", algorithm)
+		}
+		c.algorithms = append(c.algorithms, algorithm)
+		c.hashes = append(c.hashes, h)
+	}
+	return c, nil
+}
+
+func (c *checksummer) Write(p []byte) (int, error) {
+	for _, h := range c.hashes {
+		h.Write(p)
+	}
+	return len(p), nil
+}
+
+// checksums returns the checksums of the content written so far
+func (c *checksummer) checksums() []*pfs.Checksum {
+	var result []*pfs.Checksum
+	for i, h := range c.hashes {
+		result = append(result, &pfs.Checksum{Algorithm: c.algorithms[i], Value: h.Sum(nil)})
+	}
+	return result
+}
+
+// putChecksums caches 'checksums' as those of files whose hash is 'fileHash'
+func putChecksums(pachClient *client.APIClient, fileHash []byte, checksums []*pfs.Checksum) error {
+	for _, checksum := range checksums {
+		if _, _, err := pachClient.PutObject(bytes.NewReader(checksum.Value), checksumTag(checksum.Algorithm, fileHash)); err != nil {
+			return fmt.Errorf("error caching %v checksum: %v", checksum.Algorithm, err)
+		}
+	}
+	return nil
+}
+
+// putRecordsChecksums caches 'checksums', which are of content that was
+// written (unsplit) as 'records', as those of files that consist of exactly
+// that content
+func putRecordsChecksums(pachClient *client.APIClient, records *pfs.PutFileRecords, checksums []*pfs.Checksum) error {
+	node := &hashtree.FileNodeProto{}
+	for _, record := range records.Records {
+		node.Objects = append(node.Objects, client.NewObject(record.ObjectHash))
+	}
+	return putChecksums(pachClient, hashtree.HashFileNode(node), checksums)
+}
+
+// setChecksums sets the checksums of 'fileInfo' in 'algorithms', getting
+// them from the cache if they're there, and otherwise computing them from
+// the file's content (in one read) and caching them
+func (d *driver) setChecksums(pachClient *client.APIClient, fileInfo *pfs.FileInfo, algorithms []pfs.ChecksumAlgorithm) error {
+	c, err := newChecksummer(algorithms)
+	if err != nil || c == nil {
+		return err
+	}
+	if fileInfo.FileType != pfs.FileType_FILE {
+		return fmt.Errorf("checksums can only be computed for files, %s is a directory", fileInfo.File.Path)
+	}
+	values := make(map[pfs.ChecksumAlgorithm][]byte)
+	var missing []pfs.ChecksumAlgorithm
+	for _, algorithm := range c.algorithms {
+		buf := &bytes.Buffer{}
+		if err := pachClient.GetTag(checksumTag(algorithm, fileInfo.Hash), buf); err != nil {
+			if !errutil.IsNotFoundError(err) {
+				return err
+			}
+			missing = append(missing, algorithm)
+			continue
+		}
+		values[algorithm] = buf.Bytes()
+	}
+	if len(missing) > 0 {
+		computed, err := newChecksummer(missing)
+		if err != nil {
+			return err
+		}
+		r, err := d.getFile(pachClient, fileInfo.File, 0, 0)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(computed, r); err != nil {
+			return fmt.Errorf("error reading %s to compute its checksums: %v", fileInfo.File.Path, err)
+		}
+		checksums := computed.checksums()
+		if err := putChecksums(pachClient, fileInfo.Hash, checksums); err != nil {
+			return err
+		}
+		for _, checksum := range checksums {
+			values[checksum.Algorithm] = checksum.Value
+		}
+	}
+	for _, algorithm := range c.algorithms {
+		fileInfo.Checksums = append(fileInfo.Checksums, &pfs.Checksum{Algorithm: algorithm, Value: values[algorithm]})
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package server
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestChecksummer(t *testing.T) {
+	c, err := newChecksummer(nil)
+	require.NoError(t, err)
+	require.Nil(t, c)
+	_, err = newChecksummer([]pfs.ChecksumAlgorithm{pfs.ChecksumAlgorithm_CHECKSUM_NONE})
+	require.YesError(t, err)
+
+	c, err = newChecksummer([]pfs.ChecksumAlgorithm{pfs.ChecksumAlgorithm_SHA256, pfs.ChecksumAlgorithm_MD5, pfs.ChecksumAlgorithm_SHA256})
+	require.NoError(t, err)
+	c.Write([]byte("foo"))
+	c.Write([]byte("bar"))
+	sha := sha256.Sum256([]byte("foobar"))
+	md := md5.Sum([]byte("foobar"))
+	require.Equal(t, []*pfs.Checksum{
+		{Algorithm: pfs.ChecksumAlgorithm_SHA256, Value: sha[:]},
+		{Algorithm: pfs.ChecksumAlgorithm_MD5, Value: md[:]},
+	}, c.checksums())
+
+	require.Equal(t, "checksum-md5-0102", checksumTag(pfs.ChecksumAlgorithm_MD5, []byte{1, 2}))
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"time"
+
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+)
+
+// commitFilter selects the commits that ListCommit returns, from the filter
+// fields of a ListCommitRequest. A nil commitFilter matches every commit.
+type commitFilter struct {
+	startedAfter, startedBefore   time.Time
+	finishedAfter, finishedBefore time.Time
+	branch                        string
+	provenance                    string
+	// startAfter is the commit that the listing resumes after. It's cleared
+	// once that commit has been seen.
+	startAfter string
+}
+
+// newCommitFilter returns the filter of 'request', or nil if it doesn't
+// filter commits
+func newCommitFilter(request *pfs.ListCommitRequest) (*commitFilter, error) {
+	f := &commitFilter{
+		branch:     request.Branch,
+		startAfter: request.StartAfter,
+	}
+	if request.Provenance != nil {
+		f.provenance = request.Provenance.Name
+	}
+	for _, bound := range []struct {
+		ts  *types.Timestamp
+		out *time.Time
+	}{
+		{request.StartedAfter, &f.startedAfter},
+		{request.StartedBefore, &f.startedBefore},
+		{request.FinishedAfter, &f.finishedAfter},
+		{request.FinishedBefore, &f.finishedBefore},
+	} {
+		if bound.ts == nil {
+			continue
+		}
+		t, err := types.TimestampFromProto(bound.ts)
+		if err != nil {
+			return nil, err
+		}
+		*bound.out = t
+	}
+	if *f == (commitFilter{}) {
+		return nil, nil
+	}
+	return f, nil
+}
+
+// match returns true if 'ci' should be returned
+func (f *commitFilter) match(ci *pfs.CommitInfo) bool {
+	if f == nil {
+		return true
+	}
+	if f.startAfter != "" {
+		if ci.Commit.ID == f.startAfter {
+			f.startAfter = ""
+		}
+		return false
+	}
+	if f.branch != "" && (ci.Branch == nil || ci.Branch.Name != f.branch) {
+		return false
+	}
+	if f.provenance != "" && !hasProvenanceIn(ci, f.provenance) {
+		return false
+	}
+	if !f.startedAfter.IsZero() || !f.startedBefore.IsZero() {
+		started, err := types.TimestampFromProto(ci.Started)
+		if err != nil || !inTimeRange(started, f.startedAfter, f.startedBefore) {
+			return false
+		}
+	}
+	if !f.finishedAfter.IsZero() || !f.finishedBefore.IsZero() {
+		if ci.Finished == nil {
+			return false
+		}
+		finished, err := types.TimestampFromProto(ci.Finished)
+		if err != nil || !inTimeRange(finished, f.finishedAfter, f.finishedBefore) {
+			return false
+		}
+	}
+	return true
+}
+
+// pastStart returns true if 'ci', and so (as commits start after their
+// parents) all of its ancestors, started before the filter's time range
+func (f *commitFilter) pastStart(ci *pfs.CommitInfo) bool {
+	if f == nil || f.startedAfter.IsZero() || f.startAfter != "" {
+		return false
+	}
+	started, err := types.TimestampFromProto(ci.Started)
+	return err == nil && started.Before(f.startedAfter)
+}
+
+func hasProvenanceIn(ci *pfs.CommitInfo, repo string) bool {
+	for _, prov := range ci.Provenance {
+		if prov.Commit.Repo.Name == repo {
+			return true
+		}
+	}
+	return false
+}
+
+// inTimeRange returns true if 't' is in [after, before], where a zero bound
+// is unbounded
+func inTimeRange(t, after, before time.Time) bool {
+	return (after.IsZero() || !t.Before(after)) && (before.IsZero() || !t.After(before))
+}
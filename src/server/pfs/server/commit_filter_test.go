@@ -0,0 +1,66 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestCommitFilter(t *testing.T) {
+	filter, err := newCommitFilter(&pfs.ListCommitRequest{Repo: client.NewRepo("repo")})
+	require.NoError(t, err)
+	require.True(t, filter == nil)
+	require.True(t, filter.match(&pfs.CommitInfo{}))
+
+	now := time.Now()
+	ts := func(t time.Time) *types.Timestamp {
+		result, _ := types.TimestampProto(t)
+		return result
+	}
+	commitInfo := func(id string, branch string, started time.Time, finished *time.Time, prov ...string) *pfs.CommitInfo {
+		ci := &pfs.CommitInfo{
+			Commit:  client.NewCommit("repo", id),
+			Branch:  client.NewBranch("repo", branch),
+			Started: ts(started),
+		}
+		if finished != nil {
+			ci.Finished = ts(*finished)
+		}
+		for _, repo := range prov {
+			ci.Provenance = append(ci.Provenance, client.NewCommitProvenance(repo, "master", "abc"))
+		}
+		return ci
+	}
+	hourAgo := now.Add(-time.Hour)
+	old := commitInfo("old", "master", now.Add(-2*time.Hour), &hourAgo)
+	recent := commitInfo("recent", "staging", now.Add(-time.Minute), nil, "input")
+
+	filter, err = newCommitFilter(&pfs.ListCommitRequest{StartedAfter: ts(now.Add(-30 * time.Minute))})
+	require.NoError(t, err)
+	require.False(t, filter.match(old))
+	require.True(t, filter.match(recent))
+	require.True(t, filter.pastStart(old))
+
+	// unfinished commits don't match a finished bound
+	filter, err = newCommitFilter(&pfs.ListCommitRequest{FinishedBefore: ts(now)})
+	require.NoError(t, err)
+	require.True(t, filter.match(old))
+	require.False(t, filter.match(recent))
+
+	filter, err = newCommitFilter(&pfs.ListCommitRequest{Branch: "staging", Provenance: client.NewRepo("input")})
+	require.NoError(t, err)
+	require.False(t, filter.match(old))
+	require.True(t, filter.match(recent))
+
+	// commits up to and including start_after are skipped
+	filter, err = newCommitFilter(&pfs.ListCommitRequest{StartAfter: "old"})
+	require.NoError(t, err)
+	require.False(t, filter.match(recent))
+	require.False(t, filter.match(old))
+	require.True(t, filter.match(recent))
+}
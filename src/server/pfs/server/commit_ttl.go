@@ -0,0 +1,206 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/auth"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	pfsserver "github.com/pachyderm/pachyderm/src/server/pfs"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+	txnenv "github.com/pachyderm/pachyderm/src/server/pkg/transactionenv"
+)
+
+var (
+	expiredCommits = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pachyderm",
+			Subsystem: "pfs",
+			Name:      "expired_commits",
+			Help:      "Number of commits deleted by their repo's commit_ttl, by repo",
+		},
+		[]string{"repo"},
+	)
+	registerExpiredCommitsOnce sync.Once
+)
+
+// commitsToExpire returns the commits in 'commitInfos' that finished more than
+// 'ttl' before 'now'. Commits that are the head of a branch (in 'heads') or
+// that have provenance (which PFS can't delete) are kept.
+func commitsToExpire(commitInfos []*pfs.CommitInfo, heads map[string]bool, ttl time.Duration, now time.Time) []*pfs.CommitInfo {
+	var result []*pfs.CommitInfo
+	for _, commitInfo := range commitInfos {
+		if heads[commitInfo.Commit.ID] || provenantOnInput(commitInfo.Provenance) {
+			continue
+		}
+		if expired(commitInfo, ttl, now) {
+			result = append(result, commitInfo)
+		}
+	}
+	return result
+}
+
+// expired returns true if 'commitInfo' finished more than 'ttl' before 'now'
+func expired(commitInfo *pfs.CommitInfo, ttl time.Duration, now time.Time) bool {
+	if commitInfo.Finished == nil || ttl <= 0 {
+		return false
+	}
+	finished, err := types.TimestampFromProto(commitInfo.Finished)
+	return err == nil && now.Sub(finished) > ttl
+}
+
+// expireCommits deletes the commits in 'repo' that are older than its
+// commit_ttl, and returns them. Commits whose downstream commits are still
+// live (i.e. they're open, the head of a branch, or not expired themselves)
+// are kept, as deleting a commit deletes its downstream commits too. If
+// 'dryRun' is set, the commits are only returned.
+func (d *driver) expireCommits(pachClient *client.APIClient, repo *pfs.Repo, dryRun bool) ([]*pfs.CommitInfo, error) {
+	// Validate arguments
+	if repo == nil {
+		return nil, errors.New("repo cannot be nil")
+	}
+	scope := auth.Scope_WRITER
+	if dryRun {
+		scope = auth.Scope_READER
+	}
+	if err := d.checkIsAuthorized(pachClient, repo, scope); err != nil {
+		return nil, err
+	}
+	registerExpiredCommitsOnce.Do(func() {
+		if err := prometheus.Register(expiredCommits); err != nil {
+			// metrics may be redundantly registered; ignore these errors
+			if _, ok := err.(prometheus.AlreadyRegisteredError); !ok {
+				logrus.Errorf("error registering prometheus metric: %v", err)
+			}
+		}
+	})
+
+	ctx := pachClient.Ctx()
+	ttls := make(map[string]time.Duration)
+	headsByRepo := make(map[string]map[string]bool)
+	// ttl returns the commit TTL of 'repoName' (0 if it has none)
+	ttl := func(repoName string) (time.Duration, error) {
+		if ttl, ok := ttls[repoName]; ok {
+			return ttl, nil
+		}
+		repoInfo := &pfs.RepoInfo{}
+		if err := d.repos.ReadOnly(ctx).Get(repoName, repoInfo); err != nil {
+			if col.IsErrNotFound(err) {
+				return 0, pfsserver.ErrRepoNotFound{Repo: client.NewRepo(repoName)}
+			}
+			return 0, err
+		}
+		var ttl time.Duration
+		if repoInfo.CommitTTL != nil {
+			var err error
+			if ttl, err = types.DurationFromProto(repoInfo.CommitTTL); err != nil {
+				return 0, err
+			}
+		}
+		ttls[repoName] = ttl
+		return ttl, nil
+	}
+	// heads returns the IDs of the branch heads in 'repoName'
+	heads := func(repoName string) (map[string]bool, error) {
+		if heads, ok := headsByRepo[repoName]; ok {
+			return heads, nil
+		}
+		heads := make(map[string]bool)
+		branchInfo := &pfs.BranchInfo{}
+		if err := d.branches(repoName).ReadOnly(ctx).List(branchInfo, col.DefaultOptions, func(string) error {
+			if branchInfo.Head != nil {
+				heads[branchInfo.Head.ID] = true
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+		headsByRepo[repoName] = heads
+		return heads, nil
+	}
+
+	repoTTL, err := ttl(repo.Name)
+	if err != nil {
+		return nil, err
+	}
+	if repoTTL == 0 {
+		return nil, nil
+	}
+	repoHeads, err := heads(repo.Name)
+	if err != nil {
+		return nil, err
+	}
+	var commitInfos []*pfs.CommitInfo
+	if err := d.listCommitF(pachClient, repo, nil, nil, 0, false, nil, func(commitInfo *pfs.CommitInfo) error {
+		commitInfos = append(commitInfos, commitInfo)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	// live returns true if a downstream commit of 'commitInfo' is live
+	live := func(commitInfo *pfs.CommitInfo) (bool, error) {
+		for _, subv := range commitInfo.Subvenance {
+			downstreamRepo := subv.Upper.Repo.Name
+			downstreamTTL, err := ttl(downstreamRepo)
+			if err != nil {
+				return false, err
+			}
+			downstreamHeads, err := heads(downstreamRepo)
+			if err != nil {
+				return false, err
+			}
+			// the range is the commits from subv.Upper down through its
+			// parents to subv.Lower
+			commit := subv.Upper
+			for commit != nil {
+				downstreamInfo := &pfs.CommitInfo{}
+				if err := d.commits(downstreamRepo).ReadOnly(ctx).Get(commit.ID, downstreamInfo); err != nil {
+					if col.IsErrNotFound(err) {
+						break
+					}
+					return false, err
+				}
+				if downstreamHeads[commit.ID] || !expired(downstreamInfo, downstreamTTL, now) {
+					return true, nil
+				}
+				if commit.ID == subv.Lower.ID {
+					break
+				}
+				commit = downstreamInfo.ParentCommit
+			}
+		}
+		return false, nil
+	}
+
+	var result []*pfs.CommitInfo
+	for _, commitInfo := range commitsToExpire(commitInfos, repoHeads, repoTTL, now) {
+		isLive, err := live(commitInfo)
+		if err != nil {
+			return nil, err
+		}
+		if isLive {
+			continue
+		}
+		if !dryRun {
+			if err := d.txnEnv.WithWriteContext(ctx, func(txnCtx *txnenv.TransactionContext) error {
+				return d.deleteCommit(txnCtx, commitInfo.Commit)
+			}); err != nil {
+				if pfsserver.IsCommitNotFoundErr(err) {
+					continue // deleted by someone else in the meantime
+				}
+				return nil, fmt.Errorf("error expiring commit %s/%s: %v", repo.Name, commitInfo.Commit.ID, err)
+			}
+			expiredCommits.WithLabelValues(repo.Name).Inc()
+		}
+		result = append(result, commitInfo)
+	}
+	return result, nil
+}
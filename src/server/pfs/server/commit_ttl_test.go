@@ -0,0 +1,43 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsconsts"
+)
+
+func TestCommitsToExpire(t *testing.T) {
+	now := time.Now()
+	commitInfo := func(id string, finishedAgo time.Duration, prov ...string) *pfs.CommitInfo {
+		ci := &pfs.CommitInfo{Commit: client.NewCommit("repo", id)}
+		if finishedAgo > 0 {
+			ci.Finished, _ = types.TimestampProto(now.Add(-finishedAgo))
+		}
+		for _, repo := range prov {
+			ci.Provenance = append(ci.Provenance, client.NewCommitProvenance(repo, "master", "abc"))
+		}
+		return ci
+	}
+	commitInfos := []*pfs.CommitInfo{
+		commitInfo("old", 3*time.Hour),
+		commitInfo("new", time.Minute),
+		commitInfo("open", 0),
+		commitInfo("head", 3*time.Hour),
+		commitInfo("output", 3*time.Hour, "input"),
+		commitInfo("spout", 3*time.Hour, ppsconsts.SpecRepo),
+	}
+	var ids []string
+	for _, ci := range commitsToExpire(commitInfos, map[string]bool{"head": true}, time.Hour, now) {
+		ids = append(ids, ci.Commit.ID)
+	}
+	require.Equal(t, []string{"old", "spout"}, ids)
+
+	// nothing expires without a TTL
+	require.Equal(t, 0, len(commitsToExpire(commitInfos, nil, 0, now)))
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/pachyderm/pachyderm/src/client/pfs"
 	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
 	pfsserver "github.com/pachyderm/pachyderm/src/server/pfs"
+	"github.com/pachyderm/pachyderm/src/server/pfs/index"
 	"github.com/pachyderm/pachyderm/src/server/pkg/ancestry"
 	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
 	"github.com/pachyderm/pachyderm/src/server/pkg/errutil"
@@ -117,6 +118,15 @@ type driver struct {
 	// memory limiter (useful for limiting operations that could use a lot of memory)
 	memoryLimiter *semaphore.Weighted
 
+	// checksums are computed for every file put, as well as those that the
+	// PutFile requests ask for (see PFS_CHECKSUMS)
+	checksums []pfs.ChecksumAlgorithm
+
+	// packThreshold is the size of the largest files whose objects are packed
+	// into shared blocks when their commit is finished (see
+	// PFS_PACK_THRESHOLD), if it's positive
+	packThreshold int64
+
 	// New storage layer.
 	storage *fileset.Storage
 	fs      *fileset.FileSet
@@ -155,6 +165,16 @@ func newDriver(
 		// Allow up to a third of the requested memory to be used for memory intensive operations
 		memoryLimiter: semaphore.NewWeighted(memoryRequest / 3),
 	}
+	checksums, err := pfs.ParseChecksumAlgorithms(env.PFSChecksums)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PFS_CHECKSUMS: %v", err)
+	}
+	d.checksums = checksums
+	packThreshold, err := parsePackThreshold(env.PFSPackThreshold)
+	if err != nil {
+		return nil, err
+	}
+	d.packThreshold = packThreshold
 
 	// Create spec repo (default repo)
 	repo := client.NewRepo(ppsconsts.SpecRepo)
@@ -232,11 +252,25 @@ func now() *types.Timestamp {
 	return t
 }
 
-func (d *driver) createRepo(txnCtx *txnenv.TransactionContext, repo *pfs.Repo, description string, update bool) error {
+func (d *driver) createRepo(txnCtx *txnenv.TransactionContext, repo *pfs.Repo, description string, indexExtractors []string, storagePolicy *pfs.StoragePolicy, commitTTL *types.Duration, update bool) error {
 	// Validate arguments
 	if repo == nil {
 		return errors.New("repo cannot be nil")
 	}
+	if commitTTL != nil {
+		ttl, err := types.DurationFromProto(commitTTL)
+		if err != nil {
+			return err
+		}
+		if ttl < 0 {
+			return fmt.Errorf("commit_ttl must be non-negative, not %v", ttl)
+		}
+	}
+	if !isNoIndexExtractors(indexExtractors) {
+		if err := index.Validate(indexExtractors); err != nil {
+			return err
+		}
+	}
 
 	// Check that the user is logged in (user doesn't need any access level to
 	// create a repo, but they must be authenticated if auth is active)
@@ -266,6 +300,24 @@ func (d *driver) createRepo(txnCtx *txnenv.TransactionContext, repo *pfs.Repo, d
 	created := now()
 	if err == nil {
 		created = existingRepoInfo.Created
+		// Updates that don't mention the index extractors keep them
+		if indexExtractors == nil {
+			indexExtractors = existingRepoInfo.IndexExtractors
+		}
+		// and likewise for the storage policy
+		if storagePolicy == nil {
+			storagePolicy = existingRepoInfo.StoragePolicy
+		}
+		// and the commit TTL
+		if commitTTL == nil {
+			commitTTL = existingRepoInfo.CommitTTL
+		}
+	}
+	if isNoIndexExtractors(indexExtractors) {
+		indexExtractors = nil
+	}
+	if commitTTL != nil && commitTTL.Seconds == 0 && commitTTL.Nanos == 0 {
+		commitTTL = nil
 	}
 
 	// Create ACL for new repo
@@ -287,9 +339,12 @@ func (d *driver) createRepo(txnCtx *txnenv.TransactionContext, repo *pfs.Repo, d
 	}
 
 	repoInfo := &pfs.RepoInfo{
-		Repo:        repo,
-		Created:     created,
-		Description: description,
+		Repo:            repo,
+		Created:         created,
+		Description:     description,
+		IndexExtractors: indexExtractors,
+		StoragePolicy:   storagePolicy,
+		CommitTTL:       commitTTL,
 	}
 	// Only Put the new repoInfo if something has changed.  This
 	// optimization is impactful because pps will frequently update the
@@ -300,6 +355,28 @@ func (d *driver) createRepo(txnCtx *txnenv.TransactionContext, repo *pfs.Repo, d
 	return nil
 }
 
+// storageClass returns the storage class of the blocks that hold the content
+// written to 'repo', from its storage policy
+func (d *driver) storageClass(ctx context.Context, repo *pfs.Repo) (pfs.StorageClass, error) {
+	repoInfo := &pfs.RepoInfo{}
+	if err := d.repos.ReadOnly(ctx).Get(repo.Name, repoInfo); err != nil {
+		if col.IsErrNotFound(err) {
+			return 0, pfsserver.ErrRepoNotFound{Repo: repo}
+		}
+		return 0, err
+	}
+	if repoInfo.StoragePolicy == nil {
+		return pfs.StorageClass_STANDARD, nil
+	}
+	return repoInfo.StoragePolicy.StorageClass, nil
+}
+
+// isNoIndexExtractors reports whether 'indexExtractors' is ["none"], which
+// removes a repo's index extractors
+func isNoIndexExtractors(indexExtractors []string) bool {
+	return len(indexExtractors) == 1 && indexExtractors[0] == "none"
+}
+
 func (d *driver) inspectRepo(
 	txnCtx *txnenv.TransactionContext,
 	repo *pfs.Repo,
@@ -1240,6 +1317,17 @@ func (d *driver) makeCommit(
 			if provenanceCount > 0 && treeRef == nil && !hasSpec {
 				return fmt.Errorf("cannot start a commit on an output branch")
 			}
+			if branchInfo.Protection != nil {
+				me, err := committer(txnCtx.ClientContext, txnCtx.Client.AuthAPIClient)
+				if err != nil {
+					return err
+				}
+				// The commit gets the branch's provenance, as well as its own
+				hasProvenance := len(provenance) > 0 || len(branchInfo.Provenance) > 0
+				if err := checkBranchProtection(branchInfo, me, hasProvenance); err != nil {
+					return err
+				}
+			}
 			// Point 'branch' at the new commit
 			branchInfo.Name = branch // set in case 'branch' is new
 			branchInfo.Head = newCommit
@@ -1417,7 +1505,7 @@ func (d *driver) finishCommit(txnCtx *txnenv.TransactionContext, commit *pfs.Com
 		return err
 	}
 	if commitInfo.Finished != nil {
-		return pfsserver.ErrCommitFinished{commit}
+		return pfsserver.ErrCommitFinished{Commit: commit}
 	}
 	if description != "" {
 		commitInfo.Description = description
@@ -1457,6 +1545,11 @@ func (d *driver) finishCommit(txnCtx *txnenv.TransactionContext, commit *pfs.Com
 			if err != nil {
 				return err
 			}
+			// The files keep their content, so the commit doesn't need to
+			// fail if they can't be packed
+			if err := d.packObjects(txnCtx.Client, commit); err != nil {
+				logrus.Errorf("could not pack the objects of commit %s: %v", commitKey(commit), err)
+			}
 			// Put the tree to object storage.
 			treeRef, err := hashtree.PutHashTree(txnCtx.Client, finishedTree)
 			if err != nil {
@@ -1674,7 +1767,7 @@ nextSubvBI:
 			// get the info for subvB's HEAD commit
 			subvBHeadInfo := &pfs.CommitInfo{}
 			if err := stmCommits.Get(subvBI.Head.ID, subvBHeadInfo); err != nil {
-				return pfsserver.ErrCommitNotFound{subvBI.Head}
+				return pfsserver.ErrCommitNotFound{Commit: subvBI.Head}
 			}
 			provIntersection := make(map[string]struct{})
 			for _, p := range subvBHeadInfo.Provenance {
@@ -1816,7 +1909,7 @@ func (d *driver) inspectCommit(pachClient *client.APIClient, commit *pfs.Commit,
 						return fmt.Errorf("unmarshal: %v", err)
 					}
 				case watch.EventDelete:
-					return pfsserver.ErrCommitDeleted{commit}
+					return pfsserver.ErrCommitDeleted{Commit: commit}
 				}
 				if _commitInfo.Finished != nil {
 					commitInfo = _commitInfo
@@ -1862,7 +1955,7 @@ func (d *driver) resolveCommit(stm col.STM, userCommit *pfs.Commit) (*pfs.Commit
 			return nil, err
 		}
 		if branchInfo.Head == nil {
-			return nil, pfsserver.ErrNoHead{branchInfo.Branch}
+			return nil, pfsserver.ErrNoHead{Branch: branchInfo.Branch}
 		}
 		commitBranch = branchInfo.Branch
 		commit.ID = branchInfo.Head.ID
@@ -1874,14 +1967,14 @@ func (d *driver) resolveCommit(stm col.STM, userCommit *pfs.Commit) (*pfs.Commit
 	if ancestryLength >= 0 {
 		for i := 0; i <= ancestryLength; i++ {
 			if commit == nil {
-				return nil, pfsserver.ErrCommitNotFound{userCommit}
+				return nil, pfsserver.ErrCommitNotFound{Commit: userCommit}
 			}
 			if err := commits.Get(commit.ID, commitInfo); err != nil {
 				if col.IsErrNotFound(err) {
 					if i == 0 {
-						return nil, pfsserver.ErrCommitNotFound{userCommit}
+						return nil, pfsserver.ErrCommitNotFound{Commit: userCommit}
 					}
-					return nil, pfsserver.ErrParentCommitNotFound{commit}
+					return nil, pfsserver.ErrParentCommitNotFound{Commit: commit}
 				}
 				return nil, err
 			}
@@ -1895,14 +1988,14 @@ func (d *driver) resolveCommit(stm col.STM, userCommit *pfs.Commit) (*pfs.Commit
 					commitInfo = &cis[i%len(cis)]
 					break
 				}
-				return nil, pfsserver.ErrCommitNotFound{userCommit}
+				return nil, pfsserver.ErrCommitNotFound{Commit: userCommit}
 			}
 			if err := commits.Get(commit.ID, &cis[i%len(cis)]); err != nil {
 				if col.IsErrNotFound(err) {
 					if i == 0 {
-						return nil, pfsserver.ErrCommitNotFound{userCommit}
+						return nil, pfsserver.ErrCommitNotFound{Commit: userCommit}
 					}
-					return nil, pfsserver.ErrParentCommitNotFound{commit}
+					return nil, pfsserver.ErrParentCommitNotFound{Commit: commit}
 				}
 			}
 			commit = cis[i%len(cis)].ParentCommit
@@ -1916,9 +2009,9 @@ func (d *driver) resolveCommit(stm col.STM, userCommit *pfs.Commit) (*pfs.Commit
 }
 
 func (d *driver) listCommit(pachClient *client.APIClient, repo *pfs.Repo,
-	to *pfs.Commit, from *pfs.Commit, number uint64, reverse bool) ([]*pfs.CommitInfo, error) {
+	to *pfs.Commit, from *pfs.Commit, number uint64, reverse bool, filter *commitFilter) ([]*pfs.CommitInfo, error) {
 	var result []*pfs.CommitInfo
-	if err := d.listCommitF(pachClient, repo, to, from, number, reverse, func(ci *pfs.CommitInfo) error {
+	if err := d.listCommitF(pachClient, repo, to, from, number, reverse, filter, func(ci *pfs.CommitInfo) error {
 		result = append(result, ci)
 		return nil
 	}); err != nil {
@@ -1927,8 +2020,10 @@ func (d *driver) listCommit(pachClient *client.APIClient, repo *pfs.Repo,
 	return result, nil
 }
 
+// listCommitF calls 'f' with the commits in 'repo' that match 'filter' (which
+// may be nil)
 func (d *driver) listCommitF(pachClient *client.APIClient, repo *pfs.Repo,
-	to *pfs.Commit, from *pfs.Commit, number uint64, reverse bool, f func(*pfs.CommitInfo) error) error {
+	to *pfs.Commit, from *pfs.Commit, number uint64, reverse bool, filter *commitFilter, f func(*pfs.CommitInfo) error) error {
 	// Validate arguments
 	if repo == nil {
 		return errors.New("repo cannot be nil")
@@ -1969,6 +2064,13 @@ func (d *driver) listCommitF(pachClient *client.APIClient, repo *pfs.Repo,
 			return err
 		}
 	}
+	// Make sure that the commit the listing resumes after exists, otherwise
+	// nothing would be returned
+	if filter != nil && filter.startAfter != "" {
+		if _, err := d.inspectCommit(pachClient, client.NewCommit(repo.Name, filter.startAfter), pfs.CommitState_STARTED); err != nil {
+			return err
+		}
+	}
 
 	// if number is 0, we return all commits that match the criteria
 	if number == 0 {
@@ -1993,14 +2095,16 @@ func (d *driver) listCommitF(pachClient *client.APIClient, repo *pfs.Repo,
 			// Sort in reverse provenance order, i.e. commits come before their provenance
 			sort.Slice(cis, func(i, j int) bool { return len(cis[i].Provenance) > len(cis[j].Provenance) })
 			for i, ci := range cis {
+				if reverse {
+					ci = cis[len(cis)-1-i]
+				}
+				if !filter.match(ci) {
+					continue
+				}
 				if number == 0 {
 					return errutil.ErrBreak
 				}
 				number--
-
-				if reverse {
-					ci = cis[len(cis)-1-i]
-				}
 				if err := f(ci); err != nil {
 					return err
 				}
@@ -2022,7 +2126,7 @@ func (d *driver) listCommitF(pachClient *client.APIClient, repo *pfs.Repo,
 			return err
 		}
 		// Call sendCis one last time to send whatever's pending in 'cis'
-		if err := sendCis(); err != nil {
+		if err := sendCis(); err != nil && err != errutil.ErrBreak {
 			return err
 		}
 	} else {
@@ -2035,13 +2139,19 @@ func (d *driver) listCommitF(pachClient *client.APIClient, repo *pfs.Repo,
 			if err := commits.Get(cursor.ID, &commitInfo); err != nil {
 				return err
 			}
+			if filter.pastStart(&commitInfo) {
+				break
+			}
+			cursor = commitInfo.ParentCommit
+			if !filter.match(&commitInfo) {
+				continue
+			}
 			if err := f(&commitInfo); err != nil {
 				if err == errutil.ErrBreak {
 					return nil
 				}
 				return err
 			}
-			cursor = commitInfo.ParentCommit
 			number--
 		}
 	}
@@ -2049,7 +2159,7 @@ func (d *driver) listCommitF(pachClient *client.APIClient, repo *pfs.Repo,
 }
 
 func (d *driver) subscribeCommit(pachClient *client.APIClient, repo *pfs.Repo, branch string, prov *pfs.CommitProvenance,
-	from *pfs.Commit, state pfs.CommitState, f func(*pfs.CommitInfo) error) error {
+	from *pfs.Commit, state pfs.CommitState, validOnly bool, f func(*pfs.CommitInfo) error) error {
 	// Validate arguments
 	if repo == nil {
 		return errors.New("repo cannot be nil")
@@ -2057,6 +2167,9 @@ func (d *driver) subscribeCommit(pachClient *client.APIClient, repo *pfs.Repo, b
 	if from != nil && from.Repo.Name != repo.Name {
 		return fmt.Errorf("the `from` commit needs to be from repo %s", repo.Name)
 	}
+	if validOnly {
+		state = pfs.CommitState_FINISHED
+	}
 
 	commits := d.commits(repo.Name).ReadOnly(pachClient.Ctx())
 	newCommitWatcher, err := commits.Watch(watch.WithSort(etcd.SortByCreateRevision, etcd.SortAscend))
@@ -2116,6 +2229,16 @@ func (d *driver) subscribeCommit(pachClient *client.APIClient, repo *pfs.Repo, b
 				if err != nil {
 					return err
 				}
+				if validOnly {
+					valid, err := d.isValidCommit(pachClient, commitInfo)
+					if err != nil {
+						return err
+					}
+					if !valid {
+						seen[commitInfo.Commit.ID] = true
+						continue
+					}
+				}
 				if err := f(commitInfo); err != nil {
 					return err
 				}
@@ -2127,6 +2250,35 @@ func (d *driver) subscribeCommit(pachClient *client.APIClient, repo *pfs.Repo, b
 	}
 }
 
+// isEmptyCommit returns true if the finished commit in 'commitInfo' was
+// finished without any data (e.g. it's the output commit of a failed job)
+func isEmptyCommit(commitInfo *pfs.CommitInfo) bool {
+	return commitInfo.Tree == nil && len(commitInfo.Trees) == 0
+}
+
+// isValidCommit returns true if the finished commit in 'commitInfo' isn't
+// empty, and its provenant commits are finished and aren't empty (see
+// pfs.SubscribeCommitRequest.ValidOnly). It blocks until the provenant commits
+// are finished.
+func (d *driver) isValidCommit(pachClient *client.APIClient, commitInfo *pfs.CommitInfo) (bool, error) {
+	if commitInfo.Finished == nil || isEmptyCommit(commitInfo) {
+		return false, nil
+	}
+	for _, prov := range commitInfo.Provenance {
+		provInfo, err := d.inspectCommit(pachClient, prov.Commit, pfs.CommitState_FINISHED)
+		if err != nil {
+			if pfsserver.IsCommitNotFoundErr(err) {
+				return false, nil // the provenant commit was deleted
+			}
+			return false, err
+		}
+		if isEmptyCommit(provInfo) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
 func (d *driver) flushCommit(pachClient *client.APIClient, fromCommits []*pfs.Commit, toRepos []*pfs.Repo, f func(*pfs.CommitInfo) error) error {
 	if len(fromCommits) == 0 {
 		return fmt.Errorf("fromCommits cannot be empty")
@@ -2520,7 +2672,7 @@ func (d *driver) resolveCommitProvenance(stm col.STM, userCommitProvenance *pfs.
 //
 // This invariant is assumed to hold for all branches upstream of 'branch', but not
 // for 'branch' itself once 'b.Provenance' has been set.
-func (d *driver) createBranch(txnCtx *txnenv.TransactionContext, branch *pfs.Branch, commit *pfs.Commit, provenance []*pfs.Branch) error {
+func (d *driver) createBranch(txnCtx *txnenv.TransactionContext, branch *pfs.Branch, commit *pfs.Commit, provenance []*pfs.Branch, protection *pfs.BranchProtection) error {
 	// Validate arguments
 	if branch == nil {
 		return errors.New("branch cannot be nil")
@@ -2533,6 +2685,12 @@ func (d *driver) createBranch(txnCtx *txnenv.TransactionContext, branch *pfs.Bra
 	if err := d.checkIsAuthorizedInTransaction(txnCtx, branch.Repo, auth.Scope_WRITER); err != nil {
 		return err
 	}
+	// Changing a branch's protection takes more access than committing to it
+	if protection != nil {
+		if err := d.checkIsAuthorizedInTransaction(txnCtx, branch.Repo, auth.Scope_OWNER); err != nil {
+			return err
+		}
+	}
 	// Validate request
 	if err := ancestry.ValidateName(branch.Name); err != nil {
 		return err
@@ -2588,6 +2746,12 @@ func (d *driver) createBranch(txnCtx *txnenv.TransactionContext, branch *pfs.Bra
 		branchInfo.Branch = branch
 		branchInfo.Head = commit
 		branchInfo.DirectProvenance = nil
+		if protection != nil {
+			branchInfo.Protection = protection
+			if isEmptyProtection(protection) {
+				branchInfo.Protection = nil
+			}
+		}
 		for _, provBranch := range provenance {
 			if provBranch.Repo.Name == branch.Repo.Name && provBranch.Name == branch.Name {
 				return fmt.Errorf("branch %s@%s cannot be in its own provenance", branch.Repo.Name, branch.Name)
@@ -2835,11 +2999,31 @@ func (d *driver) putFiles(pachClient *client.APIClient, s *putFileServer) error
 	var putFileRecords []*pfs.PutFileRecords
 	var mu sync.Mutex
 	oneOff, repo, branch, err := d.forEachPutFile(pachClient, s, func(req *pfs.PutFileRequest, r io.Reader) error {
+		// The checksums that pachd is configured to compute are only
+		// computed for unsplit content, as split content isn't one file
+		var algorithms []pfs.ChecksumAlgorithm
+		if req.Delimiter == pfs.Delimiter_NONE {
+			algorithms = append(algorithms, d.checksums...)
+		} else if len(req.Checksums) > 0 {
+			return fmt.Errorf("checksums can't be computed for content that's split")
+		}
+		c, err := newChecksummer(append(algorithms, req.Checksums...))
+		if err != nil {
+			return err
+		}
+		if c != nil {
+			r = io.TeeReader(r, c)
+		}
 		records, err := d.putFile(pachClient, req.File, req.Delimiter, req.TargetFileDatums,
 			req.TargetFileBytes, req.HeaderRecords, req.OverwriteIndex, r)
 		if err != nil {
 			return err
 		}
+		if c != nil {
+			if err := putRecordsChecksums(pachClient, records, c.checksums()); err != nil {
+				return err
+			}
+		}
 		mu.Lock()
 		defer mu.Unlock()
 		files = append(files, req.File)
@@ -2890,9 +3074,13 @@ func (d *driver) putFile(pachClient *client.APIClient, file *pfs.File, delimiter
 	if err := hashtree.ValidatePath(file.Path); err != nil {
 		return nil, err
 	}
+	class, err := d.storageClass(pachClient.Ctx(), file.Commit.Repo)
+	if err != nil {
+		return nil, err
+	}
 
 	if delimiter == pfs.Delimiter_NONE {
-		objects, size, err := pachClient.PutObjectSplit(reader)
+		objects, size, err := pachClient.PutObjectSplitWithClass(reader, class)
 		if err != nil {
 			return nil, err
 		}
@@ -2981,6 +3169,8 @@ func (d *driver) putFile(pachClient *client.APIClient, file *pfs.File, delimiter
 					}
 					value = csvBuffer.Bytes()
 				}
+			case pfs.Delimiter_PROTOBUF:
+				value, err = readProtobufRecord(bufioR)
 			default:
 				return nil, fmt.Errorf("unrecognized delimiter %s", delimiter.String())
 			}
@@ -3016,7 +3206,7 @@ func (d *driver) putFile(pachClient *client.APIClient, file *pfs.File, delimiter
 					eg.Go(func() error {
 						defer putObjectLimiter.Release()
 						defer d.memoryLimiter.Release(_bufferLen)
-						object, size, err := pachClient.PutObject(_buffer)
+						object, size, err := pachClient.PutObjectWithClass(_buffer, class)
 						if err != nil {
 							return err
 						}
@@ -3052,7 +3242,7 @@ func (d *driver) putFile(pachClient *client.APIClient, file *pfs.File, delimiter
 				putObjectLimiter.Acquire()
 				eg.Go(func() error {
 					defer putObjectLimiter.Release()
-					object, size, err := pachClient.PutObject(bytes.NewReader(value))
+					object, size, err := pachClient.PutObjectWithClass(bytes.NewReader(value), class)
 					if err != nil {
 						return err
 					}
@@ -3179,7 +3369,7 @@ func (d *driver) copyFile(pachClient *client.APIClient, src *pfs.File, dst *pfs.
 		dstIsOpenCommit = true
 	}
 	if !dstIsOpenCommit && branch == "" {
-		return pfsserver.ErrCommitFinished{dst.Commit}
+		return pfsserver.ErrCommitFinished{Commit: dst.Commit}
 	}
 	var paths []string
 	var records []*pfs.PutFileRecords // used if 'dst' is finished (atomic 'put file')
@@ -3574,7 +3764,7 @@ func (d *driver) getFile(pachClient *client.APIClient, file *pfs.File, offset in
 			objects = append(objects, footer) // apply final footer
 		}
 		if pathsFound == 0 {
-			return nil, pfsserver.ErrFileNotFound{file}
+			return nil, pfsserver.ErrFileNotFound{File: file}
 		}
 
 		// retrieve the content of all objects in 'objects'
@@ -3593,10 +3783,10 @@ func (d *driver) getFile(pachClient *client.APIClient, file *pfs.File, offset in
 	}
 	// Handle commits that use the newer hashtree format.
 	if commitInfo.Finished == nil {
-		return nil, pfsserver.ErrOutputCommitNotFinished{commitInfo.Commit}
+		return nil, pfsserver.ErrOutputCommitNotFinished{Commit: commitInfo.Commit}
 	}
 	if commitInfo.Trees == nil {
-		return nil, pfsserver.ErrFileNotFound{file}
+		return nil, pfsserver.ErrFileNotFound{File: file}
 	}
 	var rs []io.ReadCloser
 	// Handles the case when looking for a specific file/directory
@@ -3630,7 +3820,7 @@ func (d *driver) getFile(pachClient *client.APIClient, file *pfs.File, offset in
 		return nil, err
 	}
 	if !found {
-		return nil, pfsserver.ErrFileNotFound{file}
+		return nil, pfsserver.ErrFileNotFound{File: file}
 	}
 	getBlocksClient, err := pachClient.ObjectAPIClient.GetBlocks(
 		ctx,
@@ -3756,16 +3946,16 @@ func (d *driver) inspectFile(pachClient *client.APIClient, file *pfs.File) (fi *
 		defer destroyHashtree(tree)
 		node, err := tree.Get(file.Path)
 		if err != nil {
-			return nil, pfsserver.ErrFileNotFound{file}
+			return nil, pfsserver.ErrFileNotFound{File: file}
 		}
 		return nodeToFileInfoHeaderFooter(commitInfo, file.Path, node, tree, true)
 	}
 	// Handle commits that use the newer hashtree format.
 	if commitInfo.Finished == nil {
-		return nil, pfsserver.ErrOutputCommitNotFinished{commitInfo.Commit}
+		return nil, pfsserver.ErrOutputCommitNotFinished{Commit: commitInfo.Commit}
 	}
 	if commitInfo.Trees == nil {
-		return nil, pfsserver.ErrFileNotFound{file}
+		return nil, pfsserver.ErrFileNotFound{File: file}
 	}
 	rs, err := d.getTree(pachClient, commitInfo, file.Path)
 	if err != nil {
@@ -3780,12 +3970,12 @@ func (d *driver) inspectFile(pachClient *client.APIClient, file *pfs.File) (fi *
 	}()
 	node, err := hashtree.Get(rs, file.Path)
 	if err != nil {
-		return nil, pfsserver.ErrFileNotFound{file}
+		return nil, pfsserver.ErrFileNotFound{File: file}
 	}
 	return nodeToFileInfo(commitInfo, file.Path, node, true), nil
 }
 
-func (d *driver) listFile(pachClient *client.APIClient, file *pfs.File, full bool, history int64, f func(*pfs.FileInfo) error) (retErr error) {
+func (d *driver) listFile(pachClient *client.APIClient, file *pfs.File, full bool, history int64, page *filePage, f func(*pfs.FileInfo) error) (retErr error) {
 	// Validate arguments
 	if file == nil {
 		return errors.New("file cannot be nil")
@@ -3804,6 +3994,9 @@ func (d *driver) listFile(pachClient *client.APIClient, file *pfs.File, full boo
 	if err != nil {
 		return err
 	}
+	if page != nil {
+		return d.listFilePage(pachClient, commitInfo, file, full, page, f)
+	}
 	g, err := globlib.Compile(file.Path, '/')
 	if err != nil {
 		// TODO this should be a MalformedGlob error like the hashtree returns
@@ -3847,7 +4040,7 @@ func (d *driver) listFile(pachClient *client.APIClient, file *pfs.File, full boo
 	}
 	// Handle commits that use the newer hashtree format.
 	if commitInfo.Finished == nil {
-		return pfsserver.ErrOutputCommitNotFinished{commitInfo.Commit}
+		return pfsserver.ErrOutputCommitNotFinished{Commit: commitInfo.Commit}
 	}
 	if commitInfo.Trees == nil {
 		return nil
@@ -3871,6 +4064,47 @@ func (d *driver) listFile(pachClient *client.APIClient, file *pfs.File, full boo
 	})
 }
 
+// listFilePage calls f with the page of the files under the directory at
+// 'file' that 'page' selects
+func (d *driver) listFilePage(pachClient *client.APIClient, commitInfo *pfs.CommitInfo, file *pfs.File, full bool, page *filePage, f func(*pfs.FileInfo) error) (retErr error) {
+	// Handle commits that use the old hashtree format.
+	if !provenantOnInput(commitInfo.Provenance) || commitInfo.Tree != nil {
+		tree, err := d.getTreeForFile(pachClient, client.NewFile(file.Commit.Repo.Name, file.Commit.ID, ""))
+		if err != nil {
+			return err
+		}
+		defer destroyHashtree(tree)
+		return tree.ListFrom(file.Path, page.prefix, page.startAfter, page.recursive, page.filter(func(path string, node *hashtree.NodeProto) error {
+			fi, err := nodeToFileInfoHeaderFooter(commitInfo, path, node, tree, full)
+			if err != nil {
+				return err
+			}
+			return f(fi)
+		}))
+	}
+	// Handle commits that use the newer hashtree format.
+	if commitInfo.Finished == nil {
+		return pfsserver.ErrOutputCommitNotFinished{Commit: commitInfo.Commit}
+	}
+	if commitInfo.Trees == nil {
+		return nil
+	}
+	rs, err := d.getTrees(pachClient, commitInfo, page.rangePrefix(file.Path))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		for _, r := range rs {
+			if err := r.Close(); err != nil && retErr != nil {
+				retErr = err
+			}
+		}
+	}()
+	return hashtree.ListFrom(rs, file.Path, page.prefix, page.startAfter, page.recursive, page.filter(func(path string, node *hashtree.NodeProto) error {
+		return f(nodeToFileInfo(commitInfo, path, node, full))
+	}))
+}
+
 // fileHistory calls f with FileInfos for the file, starting with how it looked
 // at the referenced commit and then all past versions that are different.
 func (d *driver) fileHistory(pachClient *client.APIClient, file *pfs.File, history int64, f func(*pfs.FileInfo) error) error {
@@ -3942,7 +4176,7 @@ func (d *driver) walkFile(pachClient *client.APIClient, file *pfs.File, f func(*
 	}
 	// Handle commits that use the newer hashtree format.
 	if commitInfo.Finished == nil {
-		return pfsserver.ErrOutputCommitNotFinished{commitInfo.Commit}
+		return pfsserver.ErrOutputCommitNotFinished{Commit: commitInfo.Commit}
 	}
 	if commitInfo.Trees == nil {
 		return nil
@@ -4001,7 +4235,7 @@ func (d *driver) globFile(pachClient *client.APIClient, commit *pfs.Commit, patt
 	}
 	// Handle commits that use the newer hashtree format.
 	if commitInfo.Finished == nil {
-		return pfsserver.ErrOutputCommitNotFinished{commitInfo.Commit}
+		return pfsserver.ErrOutputCommitNotFinished{Commit: commitInfo.Commit}
 	}
 	if commitInfo.Trees == nil {
 		return nil
@@ -4136,13 +4370,16 @@ func (d *driver) deleteFile(pachClient *client.APIClient, file *pfs.File) error
 	}
 	if commitInfo.Finished != nil {
 		if branch == "" {
-			return pfsserver.ErrCommitFinished{file.Commit}
+			return pfsserver.ErrCommitFinished{Commit: file.Commit}
 		}
 		return d.txnEnv.WithWriteContext(pachClient.Ctx(), func(txnCtx *txnenv.TransactionContext) error {
 			_, err := d.makeCommit(txnCtx, "", client.NewCommit(file.Commit.Repo.Name, ""), branch, nil, nil, nil, nil, []string{file.Path}, []*pfs.PutFileRecords{&pfs.PutFileRecords{Tombstone: true}}, "", 0)
 			return err
 		})
 	}
+	if err := d.checkWriteProtection(pachClient, file.Commit.Repo.Name, branch, commitInfo); err != nil {
+		return err
+	}
 
 	return d.upsertPutFileRecords(pachClient, file, &pfs.PutFileRecords{Tombstone: true})
 }
@@ -4476,10 +4713,16 @@ func (d *driver) forEachPutFile(pachClient *client.APIClient, server pfs.API_Put
 				}
 				if commitInfo != nil && commitInfo.Finished != nil {
 					if branch == "" {
-						return false, "", "", pfsserver.ErrCommitFinished{commit}
+						return false, "", "", pfsserver.ErrCommitFinished{Commit: commit}
 					}
 					oneOff = true
 				}
+				if oneOff {
+					commitInfo = nil
+				}
+				if err := d.checkWriteProtection(pachClient, repo, branch, commitInfo); err != nil {
+					return false, "", "", err
+				}
 				commitID = commit.ID
 			} else if req.File.Commit.ID != rawCommitID {
 				err = fmt.Errorf("all requests in a put files call must have the same commit ID; expected '%s', got '%s'", rawCommitID, req.File.Commit.ID)
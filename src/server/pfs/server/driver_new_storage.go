@@ -49,7 +49,7 @@ func (d *driver) finishCommitNewStorageLayer(txnCtx *txnenv.TransactionContext,
 		return err
 	}
 	if commitInfo.Finished != nil {
-		return pfsserver.ErrCommitFinished{commit}
+		return pfsserver.ErrCommitFinished{Commit: commit}
 	}
 	if description != "" {
 		commitInfo.Description = description
@@ -107,13 +107,13 @@ func (d *driver) getFileNewStorageLayer(pachClient *client.APIClient, file *pfs.
 	hdr, err := r.Next()
 	if err != nil {
 		if err == io.EOF {
-			return nil, pfsserver.ErrFileNotFound{file}
+			return nil, pfsserver.ErrFileNotFound{File: file}
 		}
 		return nil, err
 	}
 	// (bryce) going to want an exact match option for storage layer.
 	if hdr.Hdr.Name != file.Path {
-		return nil, pfsserver.ErrFileNotFound{file}
+		return nil, pfsserver.ErrFileNotFound{File: file}
 	}
 	return r, nil
 }
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"sort"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+)
+
+// fileChanges diffs the finished commit 'commitInfo' against its parent, and
+// returns the files that it created, updated and deleted
+func (d *driver) fileChanges(pachClient *client.APIClient, commitInfo *pfs.CommitInfo) (*pfs.FileChangeEvent, error) {
+	newFiles, oldFiles, err := d.diffFile(pachClient, client.NewFile(commitInfo.Commit.Repo.Name, commitInfo.Commit.ID, "/"), nil, false)
+	if err != nil {
+		return nil, err
+	}
+	return &pfs.FileChangeEvent{
+		Commit:  commitInfo.Commit,
+		Changes: diffToFileChanges(newFiles, oldFiles),
+	}, nil
+}
+
+// diffToFileChanges converts the result of a diff into file changes, sorted
+// by path. Directories are left out, their changes are implied by the changes
+// of their files.
+func diffToFileChanges(newFiles, oldFiles []*pfs.FileInfo) []*pfs.FileChange {
+	old := make(map[string]bool)
+	for _, fi := range oldFiles {
+		if fi.FileType == pfs.FileType_FILE {
+			old[fi.File.Path] = true
+		}
+	}
+	var result []*pfs.FileChange
+	for _, fi := range newFiles {
+		if fi.FileType != pfs.FileType_FILE {
+			continue
+		}
+		change := &pfs.FileChange{Path: fi.File.Path, Type: pfs.FileChangeType_FILE_CREATED}
+		if old[fi.File.Path] {
+			change.Type = pfs.FileChangeType_FILE_UPDATED
+			delete(old, fi.File.Path)
+		}
+		result = append(result, change)
+	}
+	for path := range old {
+		result = append(result, &pfs.FileChange{Path: path, Type: pfs.FileChangeType_FILE_DELETED})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Path < result[j].Path
+	})
+	return result
+}
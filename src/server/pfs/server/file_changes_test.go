@@ -0,0 +1,31 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestDiffToFileChanges(t *testing.T) {
+	fileInfo := func(path string, fileType pfs.FileType) *pfs.FileInfo {
+		return &pfs.FileInfo{File: client.NewFile("repo", "commit", path), FileType: fileType}
+	}
+	newFiles := []*pfs.FileInfo{
+		fileInfo("/dir", pfs.FileType_DIR),
+		fileInfo("/dir/b", pfs.FileType_FILE),
+		fileInfo("/a", pfs.FileType_FILE),
+	}
+	oldFiles := []*pfs.FileInfo{
+		fileInfo("/dir", pfs.FileType_DIR),
+		fileInfo("/dir/b", pfs.FileType_FILE),
+		fileInfo("/c", pfs.FileType_FILE),
+	}
+	require.Equal(t, []*pfs.FileChange{
+		{Path: "/a", Type: pfs.FileChangeType_FILE_CREATED},
+		{Path: "/c", Type: pfs.FileChangeType_FILE_DELETED},
+		{Path: "/dir/b", Type: pfs.FileChangeType_FILE_UPDATED},
+	}, diffToFileChanges(newFiles, oldFiles))
+	require.Equal(t, 0, len(diffToFileChanges(nil, nil)))
+}
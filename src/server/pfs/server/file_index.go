@@ -0,0 +1,206 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+
+	"github.com/gogo/protobuf/proto"
+	globlib "github.com/pachyderm/ohmyglob"
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/auth"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/server/pfs/index"
+	"github.com/sirupsen/logrus"
+)
+
+// A commit's file index is built by the index extractors of its repo once the
+// commit is finished, and stored as an object tagged with fileIndexTag. Like
+// other tagged objects it can be garbage collected, so if a commit's index is
+// missing when it's searched, it's built again.
+
+// fileIndexTag returns the tag of the file index of 'commit', which must be
+// resolved to a commit ID
+func fileIndexTag(commit *pfs.Commit) string {
+	return fmt.Sprintf("file-index-%s-%s", commit.Repo.Name, commit.ID)
+}
+
+// readFileIndex returns the stored file index of 'commit', if there is one
+func readFileIndex(pachClient *client.APIClient, commit *pfs.Commit) (*pfs.FileIndex, error) {
+	var buf bytes.Buffer
+	if err := pachClient.GetTag(fileIndexTag(commit), &buf); err != nil {
+		return nil, err
+	}
+	fileIndex := &pfs.FileIndex{}
+	if err := proto.Unmarshal(buf.Bytes(), fileIndex); err != nil {
+		return nil, err
+	}
+	return fileIndex, nil
+}
+
+// extractor is one of a repo's index extractors
+type extractor struct {
+	name string
+	index.Extractor
+}
+
+// repoExtractors returns the index extractors of 'repo'. Extractors that are
+// no longer registered are skipped, so that the repo's other files are still
+// indexed.
+func repoExtractors(pachClient *client.APIClient, repo *pfs.Repo) ([]extractor, error) {
+	repoInfo, err := pachClient.InspectRepo(repo.Name)
+	if err != nil {
+		return nil, err
+	}
+	var extractors []extractor
+	for _, name := range repoInfo.IndexExtractors {
+		if e, ok := index.Get(name); ok {
+			extractors = append(extractors, extractor{name, e})
+		}
+	}
+	return extractors, nil
+}
+
+// indexCommit builds and stores the file index of the finished commit
+// 'commitInfo'. Files that are unchanged since the parent commit keep their
+// entries from the parent's index, if it has been stored. 'extractors' are
+// the extractors of the commit's repo.
+func (d *driver) indexCommit(pachClient *client.APIClient, commitInfo *pfs.CommitInfo, extractors []extractor) (*pfs.FileIndex, error) {
+	fileIndex := &pfs.FileIndex{}
+	previous := make(map[string]*pfs.IndexedFile)
+	if commitInfo.ParentCommit != nil {
+		if parentIndex, err := readFileIndex(pachClient, commitInfo.ParentCommit); err == nil {
+			for _, f := range parentIndex.Files {
+				previous[f.Path+"\x00"+f.Extractor] = f
+			}
+		}
+	}
+	if err := d.walkFile(pachClient, client.NewFile(commitInfo.Commit.Repo.Name, commitInfo.Commit.ID, "/"), func(fi *pfs.FileInfo) error {
+		if fi.FileType != pfs.FileType_FILE {
+			return nil
+		}
+		for _, e := range extractors {
+			if !e.Match(fi.File.Path) {
+				continue
+			}
+			if f, ok := previous[fi.File.Path+"\x00"+e.name]; ok && bytes.Equal(f.Hash, fi.Hash) {
+				fileIndex.Files = append(fileIndex.Files, f)
+				continue
+			}
+			r, err := d.getFile(pachClient, fi.File, 0, index.MaxRead)
+			if err != nil {
+				return err
+			}
+			attributes, err := e.Extract(fi.File.Path, io.LimitReader(r, index.MaxRead))
+			if err != nil {
+				// files that an extractor can't read simply aren't indexed
+				logrus.Debugf("index extractor %q could not index %s@%s:%s: %v", e.name, fi.File.Commit.Repo.Name, fi.File.Commit.ID, fi.File.Path, err)
+				continue
+			}
+			fileIndex.Files = append(fileIndex.Files, &pfs.IndexedFile{
+				Path:       fi.File.Path,
+				Extractor:  e.name,
+				Attributes: attributes,
+				Hash:       fi.Hash,
+			})
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(fileIndex.Files, func(i, j int) bool {
+		return fileIndex.Files[i].Path < fileIndex.Files[j].Path
+	})
+	data, err := proto.Marshal(fileIndex)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := pachClient.PutObject(bytes.NewReader(data), fileIndexTag(commitInfo.Commit)); err != nil {
+		return nil, err
+	}
+	return fileIndex, nil
+}
+
+// indexCommitInBackground indexes 'commit' if it's finished. It's best
+// effort: if the commit isn't finished yet (e.g. because it's being finished
+// in a transaction) or indexing fails, the commit is indexed when it's first
+// searched instead.
+func (d *driver) indexCommitInBackground(pachClient *client.APIClient, commit *pfs.Commit) {
+	go func() {
+		commitInfo, err := d.inspectCommit(pachClient, commit, pfs.CommitState_STARTED)
+		if err != nil || commitInfo.Finished == nil {
+			return
+		}
+		extractors, err := repoExtractors(pachClient, commit.Repo)
+		if err != nil || len(extractors) == 0 {
+			return
+		}
+		if _, err := d.indexCommit(pachClient, commitInfo, extractors); err != nil {
+			logrus.Errorf("error indexing the files in %s@%s: %v", commitInfo.Commit.Repo.Name, commitInfo.Commit.ID, err)
+		}
+	}()
+}
+
+func (d *driver) searchFiles(pachClient *client.APIClient, commit *pfs.Commit, pattern string, attributes map[string]string) (*pfs.SearchFilesResponse, error) {
+	// Validate arguments
+	if commit == nil {
+		return nil, fmt.Errorf("commit cannot be nil")
+	}
+	if commit.Repo == nil {
+		return nil, fmt.Errorf("commit repo cannot be nil")
+	}
+	if err := d.checkIsAuthorized(pachClient, commit.Repo, auth.Scope_READER); err != nil {
+		return nil, err
+	}
+	var g *globlib.Glob
+	if pattern != "" {
+		var err error
+		if g, err = globlib.Compile(path.Join("/", pattern), '/'); err != nil {
+			return nil, err
+		}
+	}
+	commitInfo, err := d.inspectCommit(pachClient, commit, pfs.CommitState_STARTED)
+	if err != nil {
+		return nil, err
+	}
+	if commitInfo.Finished == nil {
+		return nil, fmt.Errorf("commit %s@%s is not finished, so its files can't be searched yet", commitInfo.Commit.Repo.Name, commitInfo.Commit.ID)
+	}
+	result := &pfs.SearchFilesResponse{Commit: commitInfo.Commit}
+	extractors, err := repoExtractors(pachClient, commit.Repo)
+	if err != nil {
+		return nil, err
+	}
+	if len(extractors) == 0 {
+		return result, nil
+	}
+	fileIndex, err := readFileIndex(pachClient, commitInfo.Commit)
+	if err != nil {
+		if fileIndex, err = d.indexCommit(pachClient, commitInfo, extractors); err != nil {
+			return nil, err
+		}
+	}
+	for _, f := range fileIndex.Files {
+		if g != nil && !g.Match(f.Path) {
+			continue
+		}
+		if matchAttributes(f, attributes) {
+			result.Files = append(result.Files, f)
+		}
+	}
+	return result, nil
+}
+
+// matchAttributes reports whether 'f' has all of 'attributes'. An empty value
+// matches any value of the attribute.
+func matchAttributes(f *pfs.IndexedFile, attributes map[string]string) bool {
+	for name, value := range attributes {
+		actual, ok := f.Attributes[name]
+		if !ok || (value != "" && actual != value) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/server/pkg/errutil"
+	"github.com/pachyderm/pachyderm/src/server/pkg/hashtree"
+)
+
+// filePage selects the page of files that ListFile returns, from the paging
+// fields of a ListFileRequest. A nil filePage selects every file, as ListFile
+// did before it could page.
+type filePage struct {
+	prefix     string
+	startAfter string
+	limit      int64
+	recursive  bool
+}
+
+// newFilePage returns the page of 'request', or nil if it doesn't page
+func newFilePage(request *pfs.ListFileRequest) (*filePage, error) {
+	p := &filePage{
+		prefix:     request.Prefix,
+		startAfter: request.StartAfter,
+		limit:      request.Limit,
+		recursive:  request.Recursive,
+	}
+	if *p == (filePage{}) {
+		return nil, nil
+	}
+	if p.limit < 0 {
+		return nil, fmt.Errorf("limit must be >= 0, got %d", p.limit)
+	}
+	if request.History != 0 {
+		return nil, fmt.Errorf("prefix, start_after, limit and recursive can't be combined with history")
+	}
+	if request.File != nil && hashtree.IsGlob(request.File.Path) {
+		return nil, fmt.Errorf("prefix, start_after, limit and recursive can't be used with a glob pattern (%q)", request.File.Path)
+	}
+	return p, nil
+}
+
+// rangePrefix returns the path prefix of the hashtree nodes that the page of
+// the files under 'dir' is in
+func (p *filePage) rangePrefix(dir string) string {
+	if len(p.prefix) > len(dir) {
+		return p.prefix
+	}
+	return dir
+}
+
+// filter wraps a callback for hashtree.ListFrom, so that it's only called
+// with the files on the page
+func (p *filePage) filter(f func(string, *hashtree.NodeProto) error) func(string, *hashtree.NodeProto) error {
+	var n int64
+	return func(path string, node *hashtree.NodeProto) error {
+		if p.recursive && node.FileNode == nil {
+			return nil
+		}
+		if p.limit > 0 && n >= p.limit {
+			return errutil.ErrBreak
+		}
+		n++
+		return f(path, node)
+	}
+}
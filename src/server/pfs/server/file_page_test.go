@@ -0,0 +1,42 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/server/pkg/errutil"
+	"github.com/pachyderm/pachyderm/src/server/pkg/hashtree"
+)
+
+func TestFilePage(t *testing.T) {
+	page, err := newFilePage(&pfs.ListFileRequest{File: client.NewFile("repo", "master", "*")})
+	require.NoError(t, err)
+	require.True(t, page == nil)
+
+	_, err = newFilePage(&pfs.ListFileRequest{File: client.NewFile("repo", "master", "*"), Limit: 1})
+	require.YesError(t, err)
+	_, err = newFilePage(&pfs.ListFileRequest{File: client.NewFile("repo", "master", "dir"), History: 1, StartAfter: "/dir/a"})
+	require.YesError(t, err)
+	_, err = newFilePage(&pfs.ListFileRequest{File: client.NewFile("repo", "master", "dir"), Limit: -1})
+	require.YesError(t, err)
+
+	page, err = newFilePage(&pfs.ListFileRequest{File: client.NewFile("repo", "master", "dir"), Prefix: "/dir/a", Limit: 2, Recursive: true})
+	require.NoError(t, err)
+	require.Equal(t, "/dir/a", page.rangePrefix("dir"))
+	require.Equal(t, "/dir/sub", page.rangePrefix("/dir/sub"))
+
+	// recursive pages skip directories, and stop at the limit
+	var paths []string
+	f := page.filter(func(path string, _ *hashtree.NodeProto) error {
+		paths = append(paths, path)
+		return nil
+	})
+	file, dir := &hashtree.NodeProto{FileNode: &hashtree.FileNodeProto{}}, &hashtree.NodeProto{DirNode: &hashtree.DirectoryNodeProto{}}
+	require.NoError(t, f("/dir/a", dir))
+	require.NoError(t, f("/dir/a/b", file))
+	require.NoError(t, f("/dir/a/c", file))
+	require.Equal(t, errutil.ErrBreak, f("/dir/a/d", file))
+	require.Equal(t, []string{"/dir/a/b", "/dir/a/c"}, paths)
+}
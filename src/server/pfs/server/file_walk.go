@@ -0,0 +1,116 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+)
+
+// fileWalk selects the files that WalkFile returns, from the filter fields of
+// a WalkFileRequest, and aggregates directories if the request asks for it.
+// Files are visited in the order that the hashtree walks them, i.e. each
+// directory before the files below it.
+type fileWalk struct {
+	root      string
+	maxDepth  int64
+	fileType  pfs.FileType
+	aggregate bool
+	sizeOnly  bool
+	f         func(*pfs.FileInfo) error
+
+	// pending are the directories that are being aggregated (the ancestors
+	// of the last file visited), outermost first
+	pending []*pfs.FileInfo
+}
+
+func newFileWalk(request *pfs.WalkFileRequest, f func(*pfs.FileInfo) error) (*fileWalk, error) {
+	if request.MaxDepth < 0 {
+		return nil, fmt.Errorf("max_depth must be >= 0, got %d", request.MaxDepth)
+	}
+	switch request.FileType {
+	case pfs.FileType_RESERVED, pfs.FileType_FILE, pfs.FileType_DIR:
+	default:
+		return nil, fmt.Errorf("invalid file_type %v, must be FILE or DIR", request.FileType)
+	}
+	root := "/"
+	if request.File != nil {
+		root = cleanPath(request.File.Path)
+	}
+	return &fileWalk{
+		root:      root,
+		maxDepth:  request.MaxDepth,
+		fileType:  request.FileType,
+		aggregate: request.Aggregate,
+		sizeOnly:  request.SizeOnly,
+		f:         f,
+	}, nil
+}
+
+// cleanPath returns 'p' with a leading slash and without a trailing one
+func cleanPath(p string) string {
+	return "/" + strings.Trim(p, "/")
+}
+
+// depth returns how far below the walk's root 'p' is
+func (w *fileWalk) depth(p string) int64 {
+	rel := strings.Trim(strings.TrimPrefix(cleanPath(p), w.root), "/")
+	if rel == "" {
+		return 0
+	}
+	return int64(strings.Count(rel, "/") + 1)
+}
+
+// isAncestor returns true if 'dir' is a directory above 'p'
+func isAncestor(dir, p string) bool {
+	dir, p = cleanPath(dir), cleanPath(p)
+	return dir == "/" && p != "/" || strings.HasPrefix(p, dir+"/")
+}
+
+// visit is called with each file that the walk visits
+func (w *fileWalk) visit(fi *pfs.FileInfo) error {
+	if w.aggregate {
+		// The directories that 'fi' isn't below are complete
+		for len(w.pending) > 0 && !isAncestor(w.pending[len(w.pending)-1].File.Path, fi.File.Path) {
+			if err := w.send(w.pending[len(w.pending)-1]); err != nil {
+				return err
+			}
+			w.pending = w.pending[:len(w.pending)-1]
+		}
+		if fi.FileType == pfs.FileType_FILE {
+			for _, dir := range w.pending {
+				dir.FileCount++
+			}
+		}
+	}
+	if w.maxDepth > 0 && w.depth(fi.File.Path) > w.maxDepth {
+		return nil
+	}
+	if w.aggregate && fi.FileType == pfs.FileType_DIR {
+		w.pending = append(w.pending, fi)
+		return nil
+	}
+	return w.send(fi)
+}
+
+// finish sends the directories that are still being aggregated, once the
+// walk is done
+func (w *fileWalk) finish() error {
+	for i := len(w.pending) - 1; i >= 0; i-- {
+		if err := w.send(w.pending[i]); err != nil {
+			return err
+		}
+	}
+	w.pending = nil
+	return nil
+}
+
+func (w *fileWalk) send(fi *pfs.FileInfo) error {
+	if w.fileType != pfs.FileType_RESERVED && fi.FileType != w.fileType {
+		return nil
+	}
+	if w.sizeOnly {
+		fi = sizeOnlyFileInfo(fi)
+	}
+	return w.f(fi)
+}
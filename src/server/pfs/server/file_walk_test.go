@@ -0,0 +1,60 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestFileWalk(t *testing.T) {
+	dir := func(p string) *pfs.FileInfo {
+		return &pfs.FileInfo{File: &pfs.File{Path: p}, FileType: pfs.FileType_DIR}
+	}
+	file := func(p string) *pfs.FileInfo {
+		return &pfs.FileInfo{File: &pfs.File{Path: p}, FileType: pfs.FileType_FILE}
+	}
+	// The files of a walk of "/", in the order that the hashtree walks them
+	files := func() []*pfs.FileInfo {
+		return []*pfs.FileInfo{dir("/"), file("/a"), dir("/d"), file("/d/b"), dir("/d/e"), file("/d/e/c"), dir("/f"), file("/g")}
+	}
+	walk := func(request *pfs.WalkFileRequest) ([]string, map[string]int64) {
+		request.File = client.NewFile("repo", "master", "/")
+		var paths []string
+		counts := make(map[string]int64)
+		w, err := newFileWalk(request, func(fi *pfs.FileInfo) error {
+			paths = append(paths, fi.File.Path)
+			if fi.FileType == pfs.FileType_DIR {
+				counts[fi.File.Path] = fi.FileCount
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		for _, fi := range files() {
+			require.NoError(t, w.visit(fi))
+		}
+		require.NoError(t, w.finish())
+		return paths, counts
+	}
+
+	paths, _ := walk(&pfs.WalkFileRequest{})
+	require.Equal(t, []string{"/", "/a", "/d", "/d/b", "/d/e", "/d/e/c", "/f", "/g"}, paths)
+	paths, _ = walk(&pfs.WalkFileRequest{MaxDepth: 1})
+	require.Equal(t, []string{"/", "/a", "/d", "/f", "/g"}, paths)
+	paths, _ = walk(&pfs.WalkFileRequest{FileType: pfs.FileType_FILE})
+	require.Equal(t, []string{"/a", "/d/b", "/d/e/c", "/g"}, paths)
+	paths, _ = walk(&pfs.WalkFileRequest{FileType: pfs.FileType_DIR, MaxDepth: 1})
+	require.Equal(t, []string{"/", "/d", "/f"}, paths)
+
+	// Aggregated directories come after the files below them, and count the
+	// files below the max depth too
+	paths, counts := walk(&pfs.WalkFileRequest{Aggregate: true, MaxDepth: 1})
+	require.Equal(t, []string{"/a", "/d", "/f", "/g", "/"}, paths)
+	require.Equal(t, map[string]int64{"/": 4, "/d": 2, "/f": 0}, counts)
+
+	_, err := newFileWalk(&pfs.WalkFileRequest{MaxDepth: -1}, nil)
+	require.YesError(t, err)
+	_, err = newFileWalk(&pfs.WalkFileRequest{FileType: pfs.FileType(7)}, nil)
+	require.YesError(t, err)
+}
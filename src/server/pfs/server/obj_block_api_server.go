@@ -242,8 +242,11 @@ func (s *objBlockAPIServer) PutObject(server pfsclient.ObjectAPI_PutObjectServer
 			), retErr, time.Since(start))
 	}(time.Now())
 	defer drainObjectServer(server)
-	var err error
-	object, err = s.putObject(server.Context(), putObjectReader, func(w io.Writer, r io.Reader) (int64, error) {
+	class, err := putObjectReader.storageClass()
+	if err != nil {
+		return err
+	}
+	object, err = s.putObject(server.Context(), putObjectReader, class, func(w io.Writer, r io.Reader) (int64, error) {
 		buf := grpcutil.GetBuffer()
 		defer grpcutil.PutBuffer(buf)
 		return io.CopyBuffer(w, r, buf)
@@ -276,9 +279,13 @@ func (s *objBlockAPIServer) PutObjectSplit(server pfsclient.ObjectAPI_PutObjectS
 	putObjectReader := &putObjectReader{
 		server: server,
 	}
+	class, err := putObjectReader.storageClass()
+	if err != nil {
+		return err
+	}
 	var done bool
 	for !done {
-		object, err := s.putObject(server.Context(), putObjectReader, func(w io.Writer, r io.Reader) (int64, error) {
+		object, err := s.putObject(server.Context(), putObjectReader, class, func(w io.Writer, r io.Reader) (int64, error) {
 			size, err := io.CopyN(w, r, pfsclient.ChunkSize)
 			if err == io.EOF {
 				done = true
@@ -294,13 +301,13 @@ func (s *objBlockAPIServer) PutObjectSplit(server pfsclient.ObjectAPI_PutObjectS
 	return server.SendAndClose(&pfsclient.Objects{Objects: objects})
 }
 
-func (s *objBlockAPIServer) putObject(ctx context.Context, dataReader io.Reader, f func(io.Writer, io.Reader) (int64, error)) (_ *pfsclient.Object, retErr error) {
+func (s *objBlockAPIServer) putObject(ctx context.Context, dataReader io.Reader, class pfsclient.StorageClass, f func(io.Writer, io.Reader) (int64, error)) (_ *pfsclient.Object, retErr error) {
 	hash := pfsclient.NewHash()
 	r := io.TeeReader(dataReader, hash)
 	block := &pfsclient.Block{Hash: uuid.NewWithoutDashes()}
 	var size int64
 	if err := func() (retErr error) {
-		w, err := s.objClient.Writer(ctx, s.blockPath(block))
+		w, err := obj.WriterWithClass(ctx, s.objClient, s.blockPath(block), class)
 		if err != nil {
 			return err
 		}
@@ -360,7 +367,7 @@ func (s *objBlockAPIServer) PutObjects(server pfsclient.ObjectAPI_PutObjectsServ
 	putObjectReader := &putObjectReader{
 		server: server,
 	}
-	w, err := s.objClient.Writer(server.Context(), blockPath)
+	w, err := obj.WriterWithClass(server.Context(), s.objClient, blockPath, request.StorageClass)
 	if err != nil {
 		return err
 	}
@@ -413,8 +420,11 @@ func (s *objBlockAPIServer) GetObject(request *pfsclient.Object, getObjectServer
 	if (objectSize) >= uint64(s.objectCacheBytes/maxCachedObjectDenom) {
 		// The object is a substantial portion of the available cache space so
 		// we bypass the cache and stream it directly out of the underlying store.
-		blockPath := s.blockPath(objectInfo.BlockRef.Block)
-		r, err := s.objClient.Reader(getObjectServer.Context(), blockPath, objectInfo.BlockRef.Range.Lower, objectSize)
+		blockRef := objectInfo.BlockRef
+		r, err := s.objClient.Reader(getObjectServer.Context(), s.blockPath(blockRef.Block), blockRef.Range.Lower, objectSize)
+		if packedRef := s.packedBlockRef(getObjectServer.Context(), request, blockRef, err); packedRef != nil {
+			r, err = s.objClient.Reader(getObjectServer.Context(), s.blockPath(packedRef.Block), packedRef.Range.Lower, objectSize)
+		}
 		if err != nil {
 			return err
 		}
@@ -468,8 +478,11 @@ func (s *objBlockAPIServer) GetObjects(request *pfsclient.GetObjectsRequest, get
 			readSize = size
 		}
 		if request.TotalSize >= uint64(s.objectCacheBytes/maxCachedObjectDenom) {
-			blockPath := s.blockPath(objectInfo.BlockRef.Block)
-			r, err := s.objClient.Reader(getObjectsServer.Context(), blockPath, objectInfo.BlockRef.Range.Lower+offset, readSize)
+			blockRef := objectInfo.BlockRef
+			r, err := s.objClient.Reader(getObjectsServer.Context(), s.blockPath(blockRef.Block), blockRef.Range.Lower+offset, readSize)
+			if packedRef := s.packedBlockRef(getObjectsServer.Context(), object, blockRef, err); packedRef != nil {
+				r, err = s.objClient.Reader(getObjectsServer.Context(), s.blockPath(packedRef.Block), packedRef.Range.Lower+offset, readSize)
+			}
 			if err != nil {
 				return err
 			}
@@ -802,7 +815,8 @@ func (s *objBlockAPIServer) DeleteObjects(ctx context.Context, request *pfsclien
 				return err
 			}
 
-			if objectInfo != nil && objectInfo.BlockRef != nil && objectInfo.BlockRef.Block != nil {
+			// Packs hold other objects as well
+			if objectInfo != nil && objectInfo.BlockRef != nil && objectInfo.BlockRef.Block != nil && !isPackBlock(objectInfo.BlockRef.Block) {
 				blockPath := s.blockPath(objectInfo.BlockRef.Block)
 				if err := s.objClient.Delete(ctx, blockPath); err != nil && !s.isNotFoundErr(err) {
 					return err
@@ -964,7 +978,11 @@ func (s *objBlockAPIServer) compact(ctx context.Context) (retErr error) {
 	for _, file := range toDelete {
 		file := file
 		eg.Go(func() error {
-			return s.objClient.Delete(ctx, file)
+			// Packs are deleted once for each of their objects
+			if err := s.objClient.Delete(ctx, file); err != nil && !s.isNotFoundErr(err) {
+				return err
+			}
+			return nil
 		})
 	}
 	return eg.Wait()
@@ -1060,7 +1078,11 @@ func (s *objBlockAPIServer) objectGetter(ctx groupcache.Context, key string, des
 	}
 	// use context.Background() for tracing, as groupcache may not necessarily do
 	// this inline with any RPC
-	return s.readBlockRef(context.Background(), objectInfo.BlockRef, dest)
+	err := s.readBlockRef(context.Background(), objectInfo.BlockRef, dest)
+	if packedRef := s.packedBlockRef(context.Background(), objectInfo.Object, objectInfo.BlockRef, err); packedRef != nil {
+		return s.readBlockRef(context.Background(), packedRef, dest)
+	}
+	return err
 }
 
 func (s *objBlockAPIServer) tagGetter(ctx groupcache.Context, key string, dest groupcache.Sink) error {
@@ -1315,13 +1337,21 @@ type putObjectReader struct {
 	buffer    bytes.Buffer
 	tags      []*pfsclient.Tag
 	BytesRead int
+	// first is the first request of the stream, if storageClass received it
+	// before Read
+	first *pfsclient.PutObjectRequest
 }
 
 func (r *putObjectReader) Read(p []byte) (int, error) {
 	if r.buffer.Len() == 0 {
-		request, err := r.server.Recv()
-		if err != nil {
-			return 0, err
+		request := r.first
+		r.first = nil
+		if request == nil {
+			var err error
+			request, err = r.server.Recv()
+			if err != nil {
+				return 0, err
+			}
 		}
 		r.buffer.Reset()
 		// buffer.Write cannot error
@@ -1332,6 +1362,20 @@ func (r *putObjectReader) Read(p []byte) (int, error) {
 	return r.buffer.Read(p)
 }
 
+// storageClass returns the storage class set by the first request of the
+// stream. It must be called before Read.
+func (r *putObjectReader) storageClass() (pfsclient.StorageClass, error) {
+	request, err := r.server.Recv()
+	if err == io.EOF {
+		// An empty object
+		return pfsclient.StorageClass_STANDARD, nil
+	} else if err != nil {
+		return 0, err
+	}
+	r.first = request
+	return request.StorageClass, nil
+}
+
 func drainObjectServer(putObjectServer putObjectServer) {
 	for {
 		if _, err := putObjectServer.Recv(); err != nil {
@@ -0,0 +1,270 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/limit"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+	"github.com/pachyderm/pachyderm/src/server/pkg/obj"
+	"github.com/pachyderm/pachyderm/src/server/pkg/uuid"
+
+	units "github.com/docker/go-units"
+)
+
+const (
+	// packBlockPrefix is the prefix of the hashes of pack blocks, which hold
+	// the content of many objects, so that they can be told apart from the
+	// blocks of single objects
+	packBlockPrefix = "pack-"
+	// maxPackedObjectSize is the size of the largest objects that are packed,
+	// and so the largest PFS_PACK_THRESHOLD
+	maxPackedObjectSize = 4 * 1024 * 1024
+	// packConcurrency is how many objects PackObjects reads at once
+	packConcurrency = 25
+	// A commit's objects are packed into packs of at most packBatchObjects
+	// objects and (roughly) packBatchBytes bytes
+	packBatchObjects = 10000
+	packBatchBytes   = 64 * 1024 * 1024
+)
+
+func isPackBlock(block *pfs.Block) bool {
+	return block != nil && strings.HasPrefix(block.Hash, packBlockPrefix)
+}
+
+// PackObjects implements the protobuf pfs.PackObjects RPC. Each object that's
+// stored in a block of its own is copied into the pack, and then its BlockRef
+// is pointed at its range of the pack and its old block is deleted. Objects
+// keep their hashes, so the hashtrees that reference them don't change, and
+// they're read from the pack the same way as from any other block.
+//
+// Pack blocks are never deleted along with their objects, as other objects
+// may still be read from them.
+func (s *objBlockAPIServer) PackObjects(ctx context.Context, request *pfs.PackObjectsRequest) (response *pfs.PackObjectsResponse, retErr error) {
+	func() { s.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { s.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	block := &pfs.Block{Hash: packBlockPrefix + uuid.NewWithoutDashes()}
+	blockPath := s.blockPath(block)
+	w, err := obj.WriterWithClass(ctx, s.objClient, blockPath, request.StorageClass)
+	if err != nil {
+		return nil, err
+	}
+	pack := &blockWriter{w: w, block: block}
+	var mu sync.Mutex
+	packed := make(map[string]*pfs.BlockRef)
+	var oldBlocks []*pfs.Block
+	limiter := limit.New(packConcurrency)
+	var eg errgroup.Group
+	seen := make(map[string]bool)
+	for _, object := range request.Objects {
+		object := object
+		if seen[object.Hash] {
+			continue
+		}
+		seen[object.Hash] = true
+		limiter.Acquire()
+		eg.Go(func() error {
+			defer limiter.Release()
+			blockRef, data, err := s.unpackedObject(ctx, object)
+			if err != nil || blockRef == nil {
+				return err
+			}
+			packRef, err := pack.Write(data)
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			packed[object.Hash] = packRef
+			oldBlocks = append(oldBlocks, blockRef.Block)
+			return nil
+		})
+	}
+	err = eg.Wait()
+	if closeErr := pack.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	if err != nil || len(packed) == 0 {
+		if deleteErr := s.objClient.Delete(ctx, blockPath); deleteErr != nil && !s.isNotFoundErr(deleteErr) && err == nil {
+			err = deleteErr
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &pfs.PackObjectsResponse{}, nil
+	}
+	// The pack is complete, so the objects can be read from it. Their old
+	// blocks are only deleted once they've been pointed at the pack.
+	eg = errgroup.Group{}
+	for hash, packRef := range packed {
+		object, packRef := client.NewObject(hash), packRef
+		limiter.Acquire()
+		eg.Go(func() error {
+			defer limiter.Release()
+			return s.writeProto(ctx, s.objectPath(object), packRef)
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	eg = errgroup.Group{}
+	for _, oldBlock := range oldBlocks {
+		oldBlock := oldBlock
+		limiter.Acquire()
+		eg.Go(func() error {
+			defer limiter.Release()
+			if err := s.objClient.Delete(ctx, s.blockPath(oldBlock)); err != nil && !s.isNotFoundErr(err) {
+				return err
+			}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	return &pfs.PackObjectsResponse{
+		Block:     block,
+		Objects:   int64(len(packed)),
+		SizeBytes: pack.written,
+	}, nil
+}
+
+// unpackedObject returns the BlockRef and content of 'object' if it's stored
+// in a block of its own, and nil if it isn't (e.g. because it's been packed or
+// compacted already), or if it's too large to be packed
+func (s *objBlockAPIServer) unpackedObject(ctx context.Context, object *pfs.Object) (_ *pfs.BlockRef, _ []byte, retErr error) {
+	blockRef := &pfs.BlockRef{}
+	if err := s.readProto(ctx, s.objectPath(object), blockRef); err != nil {
+		if s.isNotFoundErr(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	if blockRef.Block == nil || blockRef.Range == nil || isPackBlock(blockRef.Block) ||
+		blockRef.Range.Lower != 0 || blockRef.Range.Upper > maxPackedObjectSize {
+		return nil, nil, nil
+	}
+	r, err := s.objClient.Reader(ctx, s.blockPath(blockRef.Block), 0, 0)
+	if err != nil {
+		if s.isNotFoundErr(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	defer func() {
+		if err := r.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+	// Read past the end of the object, to make sure that nothing else is
+	// stored in its block (e.g. if it was created with CreateObject)
+	size := blockRef.Range.Upper
+	data, err := ioutil.ReadAll(io.LimitReader(r, int64(size)+1))
+	if err != nil {
+		return nil, nil, err
+	}
+	if uint64(len(data)) != size {
+		return nil, nil, nil
+	}
+	return blockRef, data, nil
+}
+
+// packedBlockRef returns the current BlockRef of 'object' if reading it at
+// 'blockRef' failed with 'err' because it's been packed since 'blockRef' was
+// cached (and so its old block is gone), and nil otherwise
+func (s *objBlockAPIServer) packedBlockRef(ctx context.Context, object *pfs.Object, blockRef *pfs.BlockRef, err error) *pfs.BlockRef {
+	if err == nil || isPackBlock(blockRef.Block) || !s.isNotFoundErr(err) {
+		return nil
+	}
+	current := &pfs.BlockRef{}
+	if err := s.readProto(ctx, s.objectPath(object), current); err != nil || !isPackBlock(current.Block) || current.Range == nil {
+		return nil
+	}
+	return current
+}
+
+// parsePackThreshold parses PFS_PACK_THRESHOLD, the size of the largest files
+// that are packed when their commit is finished
+func parsePackThreshold(threshold string) (int64, error) {
+	if threshold == "" {
+		return 0, nil
+	}
+	result, err := units.RAMInBytes(threshold)
+	if err != nil {
+		return 0, fmt.Errorf("invalid PFS_PACK_THRESHOLD: %v", err)
+	}
+	if result < 0 || result > maxPackedObjectSize {
+		return 0, fmt.Errorf("invalid PFS_PACK_THRESHOLD %q: must be between 0 and %s", threshold, units.BytesSize(maxPackedObjectSize))
+	}
+	return result, nil
+}
+
+// packObjects packs the objects of the files written to the open commit
+// 'commit' that are no larger than the pack threshold, so that repos of many
+// small files don't cost an object in object storage (and an object store
+// request to read) per file. Objects that are shared with files written
+// before are packed too, and are read from the pack in every commit.
+func (d *driver) packObjects(pachClient *client.APIClient, commit *pfs.Commit) error {
+	if d.packThreshold <= 0 {
+		return nil
+	}
+	ctx := pachClient.Ctx()
+	class, err := d.storageClass(ctx, commit.Repo)
+	if err != nil {
+		return err
+	}
+	var batch []*pfs.Object
+	var batchSize int64
+	flush := func() error {
+		// Packing a single object doesn't save anything
+		if len(batch) > 1 {
+			if _, err := pachClient.ObjectAPIClient.PackObjects(ctx, &pfs.PackObjectsRequest{
+				Objects:      batch,
+				StorageClass: class,
+			}); err != nil {
+				return grpcutil.ScrubGRPC(err)
+			}
+		}
+		batch, batchSize = nil, 0
+		return nil
+	}
+	seen := make(map[string]bool)
+	add := func(record *pfs.PutFileRecord) error {
+		if record == nil || record.SizeBytes == 0 || record.SizeBytes > d.packThreshold || seen[record.ObjectHash] {
+			return nil
+		}
+		seen[record.ObjectHash] = true
+		batch = append(batch, client.NewObject(record.ObjectHash))
+		batchSize += record.SizeBytes
+		if len(batch) >= packBatchObjects || batchSize >= packBatchBytes {
+			return flush()
+		}
+		return nil
+	}
+	prefix, err := d.scratchFilePrefix(&pfs.File{Commit: commit})
+	if err != nil {
+		return err
+	}
+	records := &pfs.PutFileRecords{}
+	if err := d.putFileRecords.ReadOnly(ctx).ListPrefix(prefix, records, col.DefaultOptions, func(string) error {
+		for _, record := range append(records.Records, records.Header, records.Footer) {
+			if err := add(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	return flush()
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/golang/groupcache"
+	"golang.org/x/net/context"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/server/pkg/obj"
+)
+
+func TestPackObjects(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pack")
+	require.NoError(t, err)
+	objClient, err := obj.NewLocalClient(dir)
+	require.NoError(t, err)
+	s, err := newObjBlockAPIServer(dir, localBlockServerCacheBytes, "localhost:1", objClient, true)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	put := func(content string) *pfs.Object {
+		object, err := s.putObject(ctx, strings.NewReader(content), pfs.StorageClass_STANDARD, func(w io.Writer, r io.Reader) (int64, error) {
+			return io.Copy(w, r)
+		})
+		require.NoError(t, err)
+		return object
+	}
+	get := func(object *pfs.Object) string {
+		var data []byte
+		require.NoError(t, s.objectCache.Get(ctx, s.splitKey(object.Hash), groupcache.AllocatingByteSliceSink(&data)))
+		return string(data)
+	}
+	blocks := func() int {
+		var n int
+		require.NoError(t, objClient.Walk(ctx, s.blockDir(), func(string) error {
+			n++
+			return nil
+		}))
+		return n
+	}
+	foo, bar := put("foo"), put("bar")
+	// An object that shares its block with another one isn't packed
+	w, err := objClient.Writer(ctx, s.blockPath(client.NewBlock("shared")))
+	require.NoError(t, err)
+	_, err = w.Write([]byte("bazqux"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	baz := client.NewObject("baz")
+	_, err = s.CreateObject(ctx, &pfs.CreateObjectRequest{
+		Object:   baz,
+		BlockRef: &pfs.BlockRef{Block: client.NewBlock("shared"), Range: &pfs.ByteRange{Lower: 0, Upper: 3}},
+	})
+	require.NoError(t, err)
+	// Cache bar's BlockRef from before it's packed
+	_, err = s.InspectObject(ctx, bar)
+	require.NoError(t, err)
+	require.Equal(t, 3, blocks())
+
+	resp, err := s.PackObjects(ctx, &pfs.PackObjectsRequest{Objects: []*pfs.Object{foo, bar, baz, foo}})
+	require.NoError(t, err)
+	pack := resp.Block
+	require.True(t, isPackBlock(pack))
+	require.Equal(t, int64(2), resp.Objects)
+	require.Equal(t, uint64(6), resp.SizeBytes)
+	require.Equal(t, 2, blocks())
+	require.Equal(t, "foo", get(foo))
+	require.Equal(t, "bar", get(bar))
+	require.Equal(t, "baz", get(baz))
+
+	// Packed objects aren't packed again
+	resp, err = s.PackObjects(ctx, &pfs.PackObjectsRequest{Objects: []*pfs.Object{foo, bar}})
+	require.NoError(t, err)
+	require.Nil(t, resp.Block)
+	require.Equal(t, 2, blocks())
+
+	// Deleting a packed object doesn't delete the objects packed with it
+	_, err = s.DeleteObjects(ctx, &pfs.DeleteObjectsRequest{Objects: []*pfs.Object{foo}})
+	require.NoError(t, err)
+	require.True(t, objClient.Exists(ctx, s.blockPath(pack)))
+}
+
+func TestParsePackThreshold(t *testing.T) {
+	threshold, err := parsePackThreshold("")
+	require.NoError(t, err)
+	require.Equal(t, int64(0), threshold)
+	threshold, err = parsePackThreshold("64K")
+	require.NoError(t, err)
+	require.Equal(t, int64(64*1024), threshold)
+	_, err = parsePackThreshold("1G")
+	require.YesError(t, err)
+	_, err = parsePackThreshold("lots")
+	require.YesError(t, err)
+}
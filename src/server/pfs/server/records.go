@@ -0,0 +1,130 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+)
+
+// recordReader reads the records of a file's content that's split by a
+// delimiter. Records are returned as they are in the file, e.g. with their
+// trailing newline.
+type recordReader struct {
+	delimiter pfs.Delimiter
+	r         *bufio.Reader
+	decoder   *json.Decoder // only used for JSON records
+}
+
+func newRecordReader(r io.Reader, delimiter pfs.Delimiter) (*recordReader, error) {
+	rr := &recordReader{delimiter: delimiter, r: bufio.NewReader(r)}
+	switch delimiter {
+	case pfs.Delimiter_JSON:
+		rr.decoder = json.NewDecoder(rr.r)
+	case pfs.Delimiter_LINE, pfs.Delimiter_CSV, pfs.Delimiter_PROTOBUF:
+	default:
+		return nil, fmt.Errorf("records can't be read with delimiter %s", delimiter)
+	}
+	return rr, nil
+}
+
+// next returns the next record, or io.EOF after the last one
+func (rr *recordReader) next() ([]byte, error) {
+	var record []byte
+	var err error
+	switch rr.delimiter {
+	case pfs.Delimiter_JSON:
+		var value json.RawMessage
+		err = rr.decoder.Decode(&value)
+		record = value
+	case pfs.Delimiter_LINE:
+		record, err = rr.r.ReadBytes('\n')
+	case pfs.Delimiter_CSV:
+		record, err = readCSVRecord(rr.r)
+	case pfs.Delimiter_PROTOBUF:
+		record, err = readProtobufRecord(rr.r)
+	}
+	// The last record may not be terminated
+	if err == io.EOF && len(record) > 0 {
+		return record, nil
+	}
+	return record, err
+}
+
+// readCSVRecord reads a row of CSV from 'r', including its trailing newline.
+// Newlines in quoted fields don't end the row. Like bufio.Reader.ReadBytes,
+// it returns io.EOF along with the last row if it isn't terminated.
+func readCSVRecord(r *bufio.Reader) ([]byte, error) {
+	var record []byte
+	for {
+		line, err := r.ReadBytes('\n')
+		record = append(record, line...)
+		// Escaped quotes ("") are two quotes, so a row is complete once it
+		// has an even number of them
+		if err != nil || bytes.Count(record, []byte{'"'})%2 == 0 {
+			return record, err
+		}
+	}
+}
+
+// readProtobufRecord reads a protobuf message from 'r', which is preceded by
+// its size as a varint. The record includes the size. It returns io.EOF if
+// 'r' has no more records, and io.ErrUnexpectedEOF if it ends mid-record.
+func readProtobufRecord(r *bufio.Reader) ([]byte, error) {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if size > math.MaxInt32 {
+		return nil, fmt.Errorf("protobuf record of %d bytes is larger than the 2GB limit of protobuf messages", size)
+	}
+	record := make([]byte, binary.MaxVarintLen64+int(size))
+	n := binary.PutUvarint(record, size)
+	record = record[:n+int(size)]
+	if _, err := io.ReadFull(r, record[n:]); err != nil {
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return record, nil
+}
+
+// sliceRecords returns the records of 'r', split by 'delimiter', from the
+// offset'th on: at most 'size' of them, or all of them if 'size' is 0. The
+// returned reader must be closed.
+func sliceRecords(r io.Reader, delimiter pfs.Delimiter, offset, size int64) (io.ReadCloser, error) {
+	if offset < 0 || size < 0 {
+		return nil, fmt.Errorf("record offset and size can't be negative")
+	}
+	rr, err := newRecordReader(r, delimiter)
+	if err != nil {
+		return nil, err
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(func() error {
+			for i := int64(0); size == 0 || i < offset+size; i++ {
+				record, err := rr.next()
+				if err == io.EOF {
+					return nil
+				} else if err != nil {
+					return err
+				}
+				if i < offset {
+					continue
+				}
+				if _, err := pw.Write(record); err != nil {
+					return err
+				}
+			}
+			return nil
+		}())
+	}()
+	return pr, nil
+}
@@ -0,0 +1,79 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func readSlice(t *testing.T, content string, delimiter pfs.Delimiter, offset, size int64) string {
+	r, err := sliceRecords(strings.NewReader(content), delimiter, offset, size)
+	require.NoError(t, err)
+	defer r.Close()
+	result, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+	return string(result)
+}
+
+// protobufRecords returns 'messages', each preceded by its size as a varint
+func protobufRecords(messages ...string) string {
+	buf := &bytes.Buffer{}
+	for _, message := range messages {
+		size := make([]byte, binary.MaxVarintLen64)
+		buf.Write(size[:binary.PutUvarint(size, uint64(len(message)))])
+		buf.WriteString(message)
+	}
+	return buf.String()
+}
+
+func TestSliceRecords(t *testing.T) {
+	lines := "a\nb\nc\nd"
+	require.Equal(t, lines, readSlice(t, lines, pfs.Delimiter_LINE, 0, 0))
+	require.Equal(t, "b\nc\n", readSlice(t, lines, pfs.Delimiter_LINE, 1, 2))
+	require.Equal(t, "d", readSlice(t, lines, pfs.Delimiter_LINE, 3, 5))
+	require.Equal(t, "", readSlice(t, lines, pfs.Delimiter_LINE, 10, 0))
+
+	// Newlines in quoted fields don't end a row
+	csv := "a,b\n\"multi\nline\",\"with \"\"quotes\"\"\"\nc,d\n"
+	require.Equal(t, "\"multi\nline\",\"with \"\"quotes\"\"\"\n", readSlice(t, csv, pfs.Delimiter_CSV, 1, 1))
+	require.Equal(t, "c,d\n", readSlice(t, csv, pfs.Delimiter_CSV, 2, 0))
+
+	require.Equal(t, `{"b":2}[3]`, readSlice(t, `{"a":1} {"b":2} [3]`, pfs.Delimiter_JSON, 1, 0))
+
+	// A 200 byte message has a 2 byte size
+	long := strings.Repeat("x", 200)
+	messages := protobufRecords("foo", long, "", "bar")
+	require.Equal(t, protobufRecords(long, ""), readSlice(t, messages, pfs.Delimiter_PROTOBUF, 1, 2))
+	require.Equal(t, protobufRecords("bar"), readSlice(t, messages, pfs.Delimiter_PROTOBUF, 3, 0))
+
+	_, err := sliceRecords(strings.NewReader(""), pfs.Delimiter_SQL, 0, 0)
+	require.YesError(t, err)
+	_, err = sliceRecords(strings.NewReader(""), pfs.Delimiter_LINE, -1, 0)
+	require.YesError(t, err)
+}
+
+func TestReadProtobufRecord(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader(protobufRecords("foo")))
+	record, err := readProtobufRecord(r)
+	require.NoError(t, err)
+	require.Equal(t, protobufRecords("foo"), string(record))
+	_, err = readProtobufRecord(r)
+	require.Equal(t, io.EOF, err)
+
+	// A truncated record is an error
+	r = bufio.NewReader(strings.NewReader(protobufRecords("foo")[:3]))
+	_, err = readProtobufRecord(r)
+	require.Equal(t, io.ErrUnexpectedEOF, err)
+	records, err := sliceRecords(strings.NewReader(protobufRecords("foo")[:3]), pfs.Delimiter_PROTOBUF, 0, 0)
+	require.NoError(t, err)
+	defer records.Close()
+	_, err = ioutil.ReadAll(records)
+	require.YesError(t, err)
+}
@@ -3,9 +3,13 @@ package testing
 import (
 	"bufio"
 	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/binary"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math"
 	"math/rand"
 	"os"
 	"path"
@@ -22,6 +26,7 @@ import (
 	"github.com/gogo/protobuf/types"
 	pclient "github.com/pachyderm/pachyderm/src/client"
 	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
 	"github.com/pachyderm/pachyderm/src/client/pkg/require"
 	"github.com/pachyderm/pachyderm/src/server/pkg/ancestry"
 	"github.com/pachyderm/pachyderm/src/server/pkg/backoff"
@@ -1590,6 +1595,46 @@ func TestInspectFile2(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestInspectFileChecksums(t *testing.T) {
+	t.Parallel()
+	err := tu.WithRealEnv(func(env *tu.RealEnv) error {
+		repo := "test"
+		require.NoError(t, env.PachClient.CreateRepo(repo))
+
+		content := "foo\nbar\n"
+		sha := sha256.Sum256([]byte(content))
+		md := md5.Sum([]byte(content))
+		_, err := env.PachClient.StartCommit(repo, "master")
+		require.NoError(t, err)
+		_, err = env.PachClient.PutFile(repo, "master", "computed", strings.NewReader(content))
+		require.NoError(t, err)
+		_, err = env.PachClient.PutFileOverwriteChecksums(repo, "master", "cached", strings.NewReader(content), 0, pfs.ChecksumAlgorithm_SHA256)
+		require.NoError(t, err)
+		// split files are directories, which have no checksums
+		_, err = env.PachClient.PutFileSplit(repo, "master", "split", pfs.Delimiter_LINE, 0, 0, 0, false, strings.NewReader(content))
+		require.NoError(t, err)
+		require.NoError(t, env.PachClient.FinishCommit(repo, "master"))
+
+		for _, path := range []string{"computed", "cached"} {
+			fileInfo, err := env.PachClient.InspectFileChecksums(repo, "master", path, pfs.ChecksumAlgorithm_MD5, pfs.ChecksumAlgorithm_SHA256)
+			require.NoError(t, err)
+			require.Equal(t, 2, len(fileInfo.Checksums))
+			require.Equal(t, pfs.ChecksumAlgorithm_MD5, fileInfo.Checksums[0].Algorithm)
+			require.Equal(t, md[:], fileInfo.Checksums[0].Value)
+			require.Equal(t, pfs.ChecksumAlgorithm_SHA256, fileInfo.Checksums[1].Algorithm)
+			require.Equal(t, sha[:], fileInfo.Checksums[1].Value)
+		}
+		// checksums aren't returned unless they're asked for
+		fileInfo, err := env.PachClient.InspectFile(repo, "master", "computed")
+		require.NoError(t, err)
+		require.Equal(t, 0, len(fileInfo.Checksums))
+		_, err = env.PachClient.InspectFileChecksums(repo, "master", "split", pfs.ChecksumAlgorithm_SHA256)
+		require.YesError(t, err)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
 func TestInspectDir(t *testing.T) {
 	t.Parallel()
 	err := tu.WithRealEnv(func(env *tu.RealEnv) error {
@@ -2221,6 +2266,78 @@ func TestListCommit(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestListCommitFilter(t *testing.T) {
+	t.Parallel()
+	err := tu.WithRealEnv(func(env *tu.RealEnv) error {
+		input := tu.UniqueString("TestListCommitFilter_input")
+		repo := tu.UniqueString("TestListCommitFilter")
+		require.NoError(t, env.PachClient.CreateRepo(input))
+		require.NoError(t, env.PachClient.CreateRepo(repo))
+		listCommit := func(req *pfs.ListCommitRequest) []string {
+			req.Repo = pclient.NewRepo(repo)
+			var ids []string
+			require.NoError(t, env.PachClient.ListCommitFilterF(req, func(ci *pfs.CommitInfo) error {
+				ids = append(ids, ci.Commit.ID)
+				return nil
+			}))
+			return ids
+		}
+
+		var masterIDs []string
+		for i := 0; i < 3; i++ {
+			commit, err := env.PachClient.StartCommit(repo, "master")
+			require.NoError(t, err)
+			require.NoError(t, env.PachClient.FinishCommit(repo, commit.ID))
+			masterIDs = append([]string{commit.ID}, masterIDs...)
+		}
+		start := types.TimestampNow()
+		// a commit on another branch, with provenance in 'input', and one
+		// that's left open
+		require.NoError(t, env.PachClient.CreateBranch(input, "master", "", nil))
+		require.NoError(t, env.PachClient.CreateBranch(repo, "staging", "", []*pfs.Branch{pclient.NewBranch(input, "master")}))
+		inputCommit, err := env.PachClient.StartCommit(input, "master")
+		require.NoError(t, err)
+		require.NoError(t, env.PachClient.FinishCommit(input, inputCommit.ID))
+		stagingCommits, err := env.PachClient.ListCommit(repo, "staging", "", 0)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(stagingCommits))
+		stagingID := stagingCommits[0].Commit.ID
+		require.NoError(t, env.PachClient.FinishCommit(repo, stagingID))
+		openCommit, err := env.PachClient.StartCommit(repo, "master")
+		require.NoError(t, err)
+
+		require.Equal(t, []string{stagingID}, listCommit(&pfs.ListCommitRequest{Branch: "staging"}))
+		require.Equal(t, []string{stagingID}, listCommit(&pfs.ListCommitRequest{Provenance: pclient.NewRepo(input)}))
+		require.ElementsEqual(t, []string{openCommit.ID, stagingID}, listCommit(&pfs.ListCommitRequest{StartedAfter: start}))
+		require.Equal(t, []string{stagingID}, listCommit(&pfs.ListCommitRequest{FinishedAfter: start}))
+		require.Equal(t, masterIDs, listCommit(&pfs.ListCommitRequest{
+			To:            pclient.NewCommit(repo, masterIDs[0]),
+			StartedBefore: start,
+		}))
+
+		// page through master's finished commits, two at a time
+		var pages [][]string
+		req := &pfs.ListCommitRequest{To: pclient.NewCommit(repo, "master"), Number: 2, FinishedBefore: types.TimestampNow()}
+		for {
+			page := listCommit(req)
+			if len(page) == 0 {
+				break
+			}
+			pages = append(pages, page)
+			req.StartAfter = page[len(page)-1]
+		}
+		require.Equal(t, [][]string{masterIDs[:2], masterIDs[2:]}, pages)
+
+		_, err = env.PachClient.PfsAPIClient.ListCommit(env.PachClient.Ctx(), &pfs.ListCommitRequest{
+			Repo:       pclient.NewRepo(repo),
+			StartAfter: "nonexistent",
+		})
+		require.YesError(t, err)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
 func TestOffsetRead(t *testing.T) {
 	t.Parallel()
 	err := tu.WithRealEnv(func(env *tu.RealEnv) error {
@@ -2363,6 +2480,60 @@ func TestSubscribeCommit(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestSubscribeValidCommit(t *testing.T) {
+	t.Parallel()
+	err := tu.WithRealEnv(func(env *tu.RealEnv) error {
+		c := env.PachClient
+		require.NoError(t, c.CreateRepo("in"))
+		require.NoError(t, c.CreateRepo("out"))
+		require.NoError(t, c.CreateBranch("out", "master", "", []*pfs.Branch{pclient.NewBranch("in", "master")}))
+
+		commitIter, err := c.SubscribeValidCommit("out", "master", nil, "")
+		require.NoError(t, err)
+		defer commitIter.Close()
+
+		finishOut := func(empty bool) *pfs.Commit {
+			commitInfo, err := c.InspectCommit("out", "master")
+			require.NoError(t, err)
+			if !empty {
+				_, err = c.PutFile("out", commitInfo.Commit.ID, "file", strings.NewReader("foo"))
+				require.NoError(t, err)
+			}
+			_, err = c.PfsAPIClient.FinishCommit(c.Ctx(), &pfs.FinishCommitRequest{
+				Commit: commitInfo.Commit,
+				Empty:  empty,
+			})
+			require.NoError(t, err)
+			return commitInfo.Commit
+		}
+
+		// An empty output commit (e.g. of a failed job) isn't returned
+		_, err = c.PutFile("in", "master", "file", strings.NewReader("foo"))
+		require.NoError(t, err)
+		finishOut(true)
+		// Neither is a commit whose provenance is empty
+		inCommit, err := c.StartCommit("in", "master")
+		require.NoError(t, err)
+		_, err = c.PfsAPIClient.FinishCommit(c.Ctx(), &pfs.FinishCommitRequest{
+			Commit: inCommit,
+			Empty:  true,
+		})
+		require.NoError(t, err)
+		finishOut(false)
+		// But a commit that, like its provenance, isn't empty is
+		_, err = c.PutFile("in", "master", "file", strings.NewReader("bar"))
+		require.NoError(t, err)
+		valid := finishOut(false)
+
+		commitInfo, err := commitIter.Next()
+		require.NoError(t, err)
+		require.Equal(t, valid, commitInfo.Commit)
+		require.NotNil(t, commitInfo.Finished)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
 func TestInspectRepoSimple(t *testing.T) {
 	t.Parallel()
 	err := tu.WithRealEnv(func(env *tu.RealEnv) error {
@@ -3192,6 +3363,54 @@ func TestPutFileSplitBig(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestGetFileRecords(t *testing.T) {
+	t.Parallel()
+	err := tu.WithRealEnv(func(env *tu.RealEnv) error {
+		c := env.PachClient
+		repo := tu.UniqueString("TestGetFileRecords")
+		require.NoError(t, c.CreateRepo(repo))
+		_, err := c.PutFile(repo, "master", "lines", strings.NewReader("foo\nbar\nbaz\nfiz"))
+		require.NoError(t, err)
+		getRecords := func(path string, delimiter pfs.Delimiter, offset, size int64) string {
+			buf := &bytes.Buffer{}
+			require.NoError(t, c.GetFileRecords(repo, "master", path, delimiter, offset, size, buf))
+			return buf.String()
+		}
+		require.Equal(t, "bar\nbaz\n", getRecords("lines", pfs.Delimiter_LINE, 1, 2))
+		require.Equal(t, "fiz", getRecords("lines", pfs.Delimiter_LINE, 3, 0))
+		require.Equal(t, "", getRecords("lines", pfs.Delimiter_LINE, 4, 0))
+
+		// Files can't be read by both bytes and records, or by SQL records
+		getFileClient, err := c.PfsAPIClient.GetFile(c.Ctx(), &pfs.GetFileRequest{
+			File:        pclient.NewFile(repo, "master", "lines"),
+			OffsetBytes: 1,
+			Delimiter:   pfs.Delimiter_LINE,
+		})
+		require.NoError(t, err)
+		require.YesError(t, grpcutil.WriteFromStreamingBytesClient(getFileClient, ioutil.Discard))
+		require.YesError(t, c.GetFileRecords(repo, "master", "lines", pfs.Delimiter_SQL, 0, 0, ioutil.Discard))
+
+		// Files that are split into varint-delimited protobuf messages, which
+		// are read as records across the split files
+		protobufRecords := &bytes.Buffer{}
+		for _, message := range []string{"foo", strings.Repeat("x", 300), "bar"} {
+			size := make([]byte, binary.MaxVarintLen64)
+			protobufRecords.Write(size[:binary.PutUvarint(size, uint64(len(message)))])
+			protobufRecords.WriteString(message)
+		}
+		content := protobufRecords.String()
+		_, err = c.PutFileSplit(repo, "master", "messages", pfs.Delimiter_PROTOBUF, 0, 0, 0, false, strings.NewReader(content))
+		require.NoError(t, err)
+		fileInfos, err := c.ListFile(repo, "master", "messages")
+		require.NoError(t, err)
+		require.Equal(t, 3, len(fileInfos))
+		require.Equal(t, content, getRecords("messages/*", pfs.Delimiter_PROTOBUF, 0, 0))
+		require.Equal(t, content[4:], getRecords("messages/*", pfs.Delimiter_PROTOBUF, 1, 0))
+		return nil
+	})
+	require.NoError(t, err)
+}
+
 func TestPutFileSplitCSV(t *testing.T) {
 	t.Parallel()
 	err := tu.WithRealEnv(func(env *tu.RealEnv) error {
@@ -3559,6 +3778,264 @@ func TestDiff(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestSearchFiles(t *testing.T) {
+	t.Parallel()
+	err := tu.WithRealEnv(func(env *tu.RealEnv) error {
+		if testing.Short() {
+			t.Skip("Skipping integration tests in short mode")
+		}
+
+		repo := tu.UniqueString("TestSearchFiles")
+		_, err := env.PachClient.PfsAPIClient.CreateRepo(context.Background(), &pfs.CreateRepoRequest{
+			Repo:            pclient.NewRepo(repo),
+			IndexExtractors: []string{"parquet"},
+		})
+		require.YesError(t, err)
+		_, err = env.PachClient.PfsAPIClient.CreateRepo(context.Background(), &pfs.CreateRepoRequest{
+			Repo:            pclient.NewRepo(repo),
+			IndexExtractors: []string{"csv"},
+		})
+		require.NoError(t, err)
+
+		_, err = env.PachClient.StartCommit(repo, "master")
+		require.NoError(t, err)
+		_, err = env.PachClient.PutFile(repo, "master", "2020/jan.csv", strings.NewReader("region,item,price\neu,apples,3\n"))
+		require.NoError(t, err)
+		_, err = env.PachClient.PutFile(repo, "master", "2020/feb.csv", strings.NewReader("region,item\nus,pears\n"))
+		require.NoError(t, err)
+		_, err = env.PachClient.PutFile(repo, "master", "notes.txt", strings.NewReader("price,item\n"))
+		require.NoError(t, err)
+		// unfinished commits can't be searched
+		_, err = env.PachClient.SearchFiles(repo, "master", "", nil)
+		require.YesError(t, err)
+		require.NoError(t, env.PachClient.FinishCommit(repo, "master"))
+
+		files, err := env.PachClient.SearchFiles(repo, "master", "", map[string]string{"csv.column.price": ""})
+		require.NoError(t, err)
+		require.Equal(t, 1, len(files))
+		require.Equal(t, "/2020/jan.csv", files[0].Path)
+		require.Equal(t, "csv", files[0].Extractor)
+		require.Equal(t, "region,item,price", files[0].Attributes["csv.columns"])
+		files, err = env.PachClient.SearchFiles(repo, "master", "", map[string]string{"csv.column.item": "1"})
+		require.NoError(t, err)
+		require.Equal(t, 2, len(files))
+		files, err = env.PachClient.SearchFiles(repo, "master", "2020/f*", nil)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(files))
+		require.Equal(t, "/2020/feb.csv", files[0].Path)
+
+		// updates that don't mention the extractors keep them
+		require.NoError(t, env.PachClient.UpdateRepo(repo))
+		repoInfo, err := env.PachClient.InspectRepo(repo)
+		require.NoError(t, err)
+		require.Equal(t, []string{"csv"}, repoInfo.IndexExtractors)
+
+		_, err = env.PachClient.StartCommit(repo, "master")
+		require.NoError(t, err)
+		require.NoError(t, env.PachClient.DeleteFile(repo, "master", "2020/jan.csv"))
+		require.NoError(t, env.PachClient.FinishCommit(repo, "master"))
+		files, err = env.PachClient.SearchFiles(repo, "master", "", map[string]string{"csv.column.price": ""})
+		require.NoError(t, err)
+		require.Equal(t, 0, len(files))
+
+		_, err = env.PachClient.PfsAPIClient.CreateRepo(context.Background(), &pfs.CreateRepoRequest{
+			Repo:            pclient.NewRepo(repo),
+			IndexExtractors: []string{"none"},
+			Update:          true,
+		})
+		require.NoError(t, err)
+		repoInfo, err = env.PachClient.InspectRepo(repo)
+		require.NoError(t, err)
+		require.Equal(t, 0, len(repoInfo.IndexExtractors))
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestStoragePolicy(t *testing.T) {
+	t.Parallel()
+	err := tu.WithRealEnv(func(env *tu.RealEnv) error {
+		repo := tu.UniqueString("TestStoragePolicy")
+		_, err := env.PachClient.PfsAPIClient.CreateRepo(context.Background(), &pfs.CreateRepoRequest{
+			Repo:          pclient.NewRepo(repo),
+			StoragePolicy: &pfs.StoragePolicy{StorageClass: pfs.StorageClass_ARCHIVE},
+		})
+		require.NoError(t, err)
+		repoInfo, err := env.PachClient.InspectRepo(repo)
+		require.NoError(t, err)
+		require.Equal(t, pfs.StorageClass_ARCHIVE, repoInfo.StoragePolicy.StorageClass)
+
+		// writes to the repo still work with backends that don't have tiers
+		_, err = env.PachClient.PutFile(repo, "master", "foo", strings.NewReader("foo\n"))
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		require.NoError(t, env.PachClient.GetFile(repo, "master", "foo", 0, 0, &buf))
+		require.Equal(t, "foo\n", buf.String())
+
+		// updates that don't mention the policy keep it
+		require.NoError(t, env.PachClient.UpdateRepo(repo))
+		repoInfo, err = env.PachClient.InspectRepo(repo)
+		require.NoError(t, err)
+		require.Equal(t, pfs.StorageClass_ARCHIVE, repoInfo.StoragePolicy.StorageClass)
+
+		_, err = env.PachClient.PfsAPIClient.CreateRepo(context.Background(), &pfs.CreateRepoRequest{
+			Repo:          pclient.NewRepo(repo),
+			StoragePolicy: &pfs.StoragePolicy{StorageClass: pfs.StorageClass_INFREQUENT_ACCESS},
+			Update:        true,
+		})
+		require.NoError(t, err)
+		repoInfo, err = env.PachClient.InspectRepo(repo)
+		require.NoError(t, err)
+		require.Equal(t, pfs.StorageClass_INFREQUENT_ACCESS, repoInfo.StoragePolicy.StorageClass)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestCommitTTL(t *testing.T) {
+	t.Parallel()
+	err := tu.WithRealEnv(func(env *tu.RealEnv) error {
+		setTTL := func(repo string, ttl time.Duration) {
+			_, err := env.PachClient.PfsAPIClient.CreateRepo(context.Background(), &pfs.CreateRepoRequest{
+				Repo:      pclient.NewRepo(repo),
+				CommitTTL: types.DurationProto(ttl),
+				Update:    true,
+			})
+			require.NoError(t, err)
+		}
+		commitIDs := func(commitInfos []*pfs.CommitInfo) []string {
+			var ids []string
+			for _, ci := range commitInfos {
+				ids = append(ids, ci.Commit.ID)
+			}
+			sort.Strings(ids)
+			return ids
+		}
+
+		input := tu.UniqueString("TestCommitTTL_input")
+		output := tu.UniqueString("TestCommitTTL_output")
+		require.NoError(t, env.PachClient.CreateRepo(input))
+		require.NoError(t, env.PachClient.CreateRepo(output))
+		require.NoError(t, env.PachClient.CreateBranch(output, "master", "", []*pfs.Branch{pclient.NewBranch(input, "master")}))
+		var inputCommits []string
+		for i := 0; i < 3; i++ {
+			commit, err := env.PachClient.StartCommit(input, "master")
+			require.NoError(t, err)
+			require.NoError(t, env.PachClient.FinishCommit(input, commit.ID))
+			require.NoError(t, env.PachClient.FinishCommit(output, "master"))
+			inputCommits = append(inputCommits, commit.ID)
+		}
+		sort.Strings(inputCommits)
+
+		// Repos without a TTL keep their commits
+		commitInfos, err := env.PachClient.ExpireCommits(input, false)
+		require.NoError(t, err)
+		require.Equal(t, 0, len(commitInfos))
+
+		setTTL(input, time.Second)
+		repoInfo, err := env.PachClient.InspectRepo(input)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), repoInfo.CommitTTL.Seconds)
+		time.Sleep(2 * time.Second)
+
+		// The output repo has no TTL, so its commits are live, and so are
+		// their upstream commits
+		commitInfos, err = env.PachClient.ExpireCommits(input, false)
+		require.NoError(t, err)
+		require.Equal(t, 0, len(commitInfos))
+
+		// Once the output commits can expire, so can the input commits
+		// (except the head of master)
+		setTTL(output, time.Second)
+		commitInfos, err = env.PachClient.ExpireCommits(input, true)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(commitInfos))
+		commitInfos, err = env.PachClient.ListCommitByRepo(input)
+		require.NoError(t, err)
+		require.Equal(t, inputCommits, commitIDs(commitInfos))
+
+		expired, err := env.PachClient.ExpireCommits(input, false)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(expired))
+		commitInfos, err = env.PachClient.ListCommitByRepo(input)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(commitInfos))
+		head, err := env.PachClient.InspectCommit(input, "master")
+		require.NoError(t, err)
+		require.Equal(t, head.Commit.ID, commitInfos[0].Commit.ID)
+		require.Equal(t, inputCommits, commitIDs(append(expired, head)))
+		// and their downstream commits were deleted with them
+		commitInfos, err = env.PachClient.ListCommitByRepo(output)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(commitInfos))
+
+		// A TTL of 0 removes it
+		setTTL(input, 0)
+		repoInfo, err = env.PachClient.InspectRepo(input)
+		require.NoError(t, err)
+		require.True(t, repoInfo.CommitTTL == nil)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestBranchProtection(t *testing.T) {
+	t.Parallel()
+	err := tu.WithRealEnv(func(env *tu.RealEnv) error {
+		c := env.PachClient
+		upstream := tu.UniqueString("TestBranchProtection_upstream")
+		repo := tu.UniqueString("TestBranchProtection")
+		require.NoError(t, c.CreateRepo(upstream))
+		require.NoError(t, c.CreateRepo(repo))
+		_, err := c.PutFile(upstream, "master", "in", strings.NewReader("in"))
+		require.NoError(t, err)
+		_, err = c.PutFile(repo, "master", "manual", strings.NewReader("manual"))
+		require.NoError(t, err)
+
+		require.NoError(t, c.ProtectBranch(repo, "master", &pfs.BranchProtection{RequireProvenance: true}))
+		branchInfo, err := c.InspectBranch(repo, "master")
+		require.NoError(t, err)
+		require.True(t, branchInfo.Protection.RequireProvenance)
+		require.NotNil(t, branchInfo.Head)
+
+		// Direct writes are rejected
+		_, err = c.PutFile(repo, "master", "manual2", strings.NewReader("manual2"))
+		require.YesError(t, err)
+		require.Matches(t, "protected", err.Error())
+		_, err = c.StartCommit(repo, "master")
+		require.YesError(t, err)
+		require.YesError(t, c.DeleteFile(repo, "master", "manual"))
+
+		// but commits with provenance are accepted, and can have files put in
+		upstreamHead, err := c.InspectCommit(upstream, "master")
+		require.NoError(t, err)
+		commit, err := c.PfsAPIClient.StartCommit(c.Ctx(), &pfs.StartCommitRequest{
+			Parent:     pclient.NewCommit(repo, ""),
+			Branch:     "master",
+			Provenance: []*pfs.CommitProvenance{pclient.NewCommitProvenance(upstream, "master", upstreamHead.Commit.ID)},
+		})
+		require.NoError(t, err)
+		_, err = c.PutFile(repo, commit.ID, "derived", strings.NewReader("derived"))
+		require.NoError(t, err)
+		require.NoError(t, c.FinishCommit(repo, commit.ID))
+
+		// Other branches aren't protected
+		_, err = c.PutFile(repo, "staging", "manual2", strings.NewReader("manual2"))
+		require.NoError(t, err)
+
+		// An empty protection removes it
+		require.NoError(t, c.ProtectBranch(repo, "master", &pfs.BranchProtection{}))
+		branchInfo, err = c.InspectBranch(repo, "master")
+		require.NoError(t, err)
+		require.True(t, branchInfo.Protection == nil)
+		require.Equal(t, commit.ID, branchInfo.Head.ID)
+		_, err = c.PutFile(repo, "master", "manual2", strings.NewReader("manual2"))
+		require.NoError(t, err)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
 func TestGlobFile(t *testing.T) {
 	t.Parallel()
 	err := tu.WithRealEnv(func(env *tu.RealEnv) error {
@@ -3747,6 +4224,178 @@ func TestGlobFileF(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestListFileSizeOnly(t *testing.T) {
+	t.Parallel()
+	err := tu.WithRealEnv(func(env *tu.RealEnv) error {
+		if testing.Short() {
+			t.Skip("Skipping integration tests in short mode")
+		}
+
+		repo := tu.UniqueString("TestListFileSizeOnly")
+		require.NoError(t, env.PachClient.CreateRepo(repo))
+		_, err := env.PachClient.PutFile(repo, "master", "dir/foo", strings.NewReader("foo"))
+		require.NoError(t, err)
+		_, err = env.PachClient.PutFile(repo, "master", "dir/bar", strings.NewReader("barbar"))
+		require.NoError(t, err)
+
+		checkSizeOnly := func(fileInfos []*pfs.FileInfo) {
+			require.Equal(t, 2, len(fileInfos))
+			sizes := make(map[string]uint64)
+			for _, fi := range fileInfos {
+				require.Nil(t, fi.File.Commit)
+				require.Equal(t, 0, len(fi.Objects))
+				require.Equal(t, 0, len(fi.BlockRefs))
+				require.Equal(t, pfs.FileType_FILE, fi.FileType)
+				require.NotNil(t, fi.Committed)
+				require.True(t, len(fi.Hash) > 0)
+				sizes[fi.File.Path] = fi.SizeBytes
+			}
+			require.Equal(t, map[string]uint64{"/dir/foo": 3, "/dir/bar": 6}, sizes)
+		}
+		fileInfos, err := env.PachClient.PfsAPIClient.ListFile(env.PachClient.Ctx(), &pfs.ListFileRequest{
+			File:     pclient.NewFile(repo, "master", "dir"),
+			Full:     true,
+			SizeOnly: true,
+		})
+		require.NoError(t, err)
+		checkSizeOnly(fileInfos.FileInfo)
+		fileInfos, err = env.PachClient.PfsAPIClient.GlobFile(env.PachClient.Ctx(), &pfs.GlobFileRequest{
+			Commit:   pclient.NewCommit(repo, "master"),
+			Pattern:  "dir/*",
+			SizeOnly: true,
+		})
+		require.NoError(t, err)
+		checkSizeOnly(fileInfos.FileInfo)
+		var streamed []*pfs.FileInfo
+		require.NoError(t, env.PachClient.GlobFileSizeOnlyF(repo, "master", "dir/*", func(fi *pfs.FileInfo) error {
+			streamed = append(streamed, fi)
+			return nil
+		}))
+		checkSizeOnly(streamed)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestListFileSummary(t *testing.T) {
+	t.Parallel()
+	err := tu.WithRealEnv(func(env *tu.RealEnv) error {
+		if testing.Short() {
+			t.Skip("Skipping integration tests in short mode")
+		}
+
+		repo := tu.UniqueString("TestListFileSummary")
+		require.NoError(t, env.PachClient.CreateRepo(repo))
+		_, err := env.PachClient.PutFile(repo, "master", "foo", strings.NewReader("foo"))
+		require.NoError(t, err)
+		_, err = env.PachClient.PutFile(repo, "master", "dir/bar", strings.NewReader("barbar"))
+		require.NoError(t, err)
+		_, err = env.PachClient.PutFile(repo, "master", "dir/sub/baz", strings.NewReader("bazbazbaz"))
+		require.NoError(t, err)
+
+		// Directories aren't counted, and neither are their sizes
+		count, size, err := env.PachClient.ListFileSummary(repo, "master", "")
+		require.NoError(t, err)
+		require.Equal(t, int64(1), count)
+		require.Equal(t, uint64(3), size)
+		count, size, err = env.PachClient.ListFileSummary(repo, "master", "dir")
+		require.NoError(t, err)
+		require.Equal(t, int64(1), count)
+		require.Equal(t, uint64(6), size)
+		count, size, err = env.PachClient.GlobFileSummary(repo, "master", "**")
+		require.NoError(t, err)
+		require.Equal(t, int64(3), count)
+		require.Equal(t, uint64(18), size)
+
+		// Summaries aren't returned in place of the files
+		fileInfos, err := env.PachClient.PfsAPIClient.ListFile(env.PachClient.Ctx(), &pfs.ListFileRequest{
+			File:    pclient.NewFile(repo, "master", ""),
+			Summary: true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 0, len(fileInfos.FileInfo))
+
+		// The streaming variants don't support summaries
+		listStream, err := env.PachClient.PfsAPIClient.ListFileStream(env.PachClient.Ctx(), &pfs.ListFileRequest{
+			File:    pclient.NewFile(repo, "master", ""),
+			Summary: true,
+		})
+		require.NoError(t, err)
+		_, err = listStream.Recv()
+		require.YesError(t, err)
+		require.Matches(t, "not supported", err.Error())
+		globStream, err := env.PachClient.PfsAPIClient.GlobFileStream(env.PachClient.Ctx(), &pfs.GlobFileRequest{
+			Commit:  pclient.NewCommit(repo, "master"),
+			Pattern: "**",
+			Summary: true,
+		})
+		require.NoError(t, err)
+		_, err = globStream.Recv()
+		require.YesError(t, err)
+		require.Matches(t, "not supported", err.Error())
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestListFilePage(t *testing.T) {
+	t.Parallel()
+	err := tu.WithRealEnv(func(env *tu.RealEnv) error {
+		if testing.Short() {
+			t.Skip("Skipping integration tests in short mode")
+		}
+
+		repo := tu.UniqueString("TestListFilePage")
+		require.NoError(t, env.PachClient.CreateRepo(repo))
+		commit, err := env.PachClient.StartCommit(repo, "master")
+		require.NoError(t, err)
+		for _, file := range []string{"a", "b1", "b2", "b3", "dir/c", "dir/sub/d"} {
+			_, err = env.PachClient.PutFile(repo, commit.ID, file, strings.NewReader(file))
+			require.NoError(t, err)
+		}
+		require.NoError(t, env.PachClient.FinishCommit(repo, commit.ID))
+		listFile := func(req *pfs.ListFileRequest) []string {
+			if req.File == nil {
+				req.File = pclient.NewFile(repo, "master", "")
+			}
+			var paths []string
+			require.NoError(t, env.PachClient.ListFilePageF(req, func(fi *pfs.FileInfo) error {
+				paths = append(paths, fi.File.Path)
+				return nil
+			}))
+			return paths
+		}
+
+		require.Equal(t, []string{"/b1", "/b2", "/b3"}, listFile(&pfs.ListFileRequest{Prefix: "/b"}))
+		require.Equal(t, []string{"/dir/c", "/dir/sub/d"}, listFile(&pfs.ListFileRequest{Prefix: "/dir/", Recursive: true}))
+		require.Equal(t, []string{"/dir/c", "/dir/sub"}, listFile(&pfs.ListFileRequest{
+			File:   pclient.NewFile(repo, "master", "dir"),
+			Prefix: "/dir/",
+		}))
+
+		// page through the repo's files, two at a time
+		var pages [][]string
+		req := &pfs.ListFileRequest{Limit: 2, Recursive: true}
+		for {
+			page := listFile(req)
+			if len(page) == 0 {
+				break
+			}
+			pages = append(pages, page)
+			req.StartAfter = page[len(page)-1]
+		}
+		require.Equal(t, [][]string{{"/a", "/b1"}, {"/b2", "/b3"}, {"/dir/c", "/dir/sub/d"}}, pages)
+
+		_, err = env.PachClient.PfsAPIClient.ListFile(env.PachClient.Ctx(), &pfs.ListFileRequest{
+			File:  pclient.NewFile(repo, "master", "*"),
+			Limit: 2,
+		})
+		require.YesError(t, err)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
 // TestGetFileGlobOrder checks that GetFile(glob) streams data back in the
 // right order. GetFile(glob) is supposed to return a stream of data of the
 // form file1 + file2 + .. + fileN, where file1 is the lexicographically lowest
@@ -3897,6 +4546,89 @@ func TestCopyFile(t *testing.T) {
 	require.NoError(t, err)
 }
 
+// failingReaderAt fails reads at or after 'failAt', and records the lowest
+// offset that was read
+type failingReaderAt struct {
+	r         io.ReaderAt
+	failAt    int64
+	mu        sync.Mutex
+	minOffset int64
+}
+
+func (f *failingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	f.mu.Lock()
+	if off < f.minOffset {
+		f.minOffset = off
+	}
+	f.mu.Unlock()
+	if f.failAt >= 0 && off >= f.failAt {
+		return 0, fmt.Errorf("read failed")
+	}
+	return f.r.ReadAt(p, off)
+}
+
+func TestPutFileParallel(t *testing.T) {
+	t.Parallel()
+	err := tu.WithRealEnv(func(env *tu.RealEnv) error {
+		repo := tu.UniqueString("TestPutFileParallel")
+		require.NoError(t, env.PachClient.CreateRepo(repo))
+
+		data := make([]byte, 1000*1000+1)
+		rand.Read(data)
+		partSize := int64(100 * 1000)
+		require.NoError(t, env.PachClient.PutFileParallel(repo, "master", "dir/file", bytes.NewReader(data), int64(len(data)), partSize, 4, false, ""))
+
+		// the file is put in a single commit, without the parts
+		commitInfos, err := env.PachClient.ListCommit(repo, "master", "", 0)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(commitInfos))
+		require.NotNil(t, commitInfos[0].Finished)
+		var buf bytes.Buffer
+		require.NoError(t, env.PachClient.GetFile(repo, "master", "dir/file", 0, 0, &buf))
+		require.True(t, bytes.Equal(data, buf.Bytes()))
+		fileInfos, err := env.PachClient.ListFile(repo, "master", "dir")
+		require.NoError(t, err)
+		require.Equal(t, 1, len(fileInfos))
+
+		// a failed upload is resumed from the manifest
+		manifest := filepath.Join(tu.UniqueString("/tmp/manifest"), "manifest.json")
+		require.NoError(t, os.MkdirAll(filepath.Dir(manifest), 0755))
+		defer os.RemoveAll(filepath.Dir(manifest))
+		failing := &failingReaderAt{r: bytes.NewReader(data), failAt: 5 * partSize, minOffset: math.MaxInt64}
+		require.YesError(t, env.PachClient.PutFileParallel(repo, "master", "dir/file", failing, int64(len(data)), partSize, 1, true, manifest))
+		_, err = os.Stat(manifest)
+		require.NoError(t, err)
+		resumed := &failingReaderAt{r: bytes.NewReader(data), failAt: -1, minOffset: math.MaxInt64}
+		require.NoError(t, env.PachClient.PutFileParallel(repo, "master", "dir/file", resumed, int64(len(data)), partSize, 1, true, manifest))
+		// the first 5 parts weren't read again
+		require.Equal(t, 5*partSize, resumed.minOffset)
+		_, err = os.Stat(manifest)
+		require.True(t, os.IsNotExist(err))
+
+		commitInfos, err = env.PachClient.ListCommit(repo, "master", "", 0)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(commitInfos))
+		buf.Reset()
+		require.NoError(t, env.PachClient.GetFile(repo, "master", "dir/file", 0, 0, &buf))
+		require.True(t, bytes.Equal(data, buf.Bytes()))
+
+		// without 'overwrite', the parts are appended to the file, and an open
+		// head commit is used as is
+		_, err = env.PachClient.StartCommit(repo, "master")
+		require.NoError(t, err)
+		require.NoError(t, env.PachClient.PutFileParallel(repo, "master", "dir/file", bytes.NewReader(data), int64(len(data)), partSize, 4, false, ""))
+		require.NoError(t, env.PachClient.FinishCommit(repo, "master"))
+		buf.Reset()
+		require.NoError(t, env.PachClient.GetFile(repo, "master", "dir/file", 0, 0, &buf))
+		require.True(t, bytes.Equal(append(append([]byte{}, data...), data...), buf.Bytes()))
+		fileInfos, err = env.PachClient.ListFile(repo, "master", "dir")
+		require.NoError(t, err)
+		require.Equal(t, 1, len(fileInfos))
+		return nil
+	})
+	require.NoError(t, err)
+}
+
 func TestCopyFileHeaderFooter(t *testing.T) {
 	t.Parallel()
 	err := tu.WithRealEnv(func(env *tu.RealEnv) error {
@@ -5367,6 +6099,43 @@ func TestWalk(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestWalkFileFilters(t *testing.T) {
+	t.Parallel()
+	err := tu.WithRealEnv(func(env *tu.RealEnv) error {
+		repo := tu.UniqueString("TestWalkFileFilters")
+		require.NoError(t, env.PachClient.CreateRepo(repo))
+		_, err := env.PachClient.PutFile(repo, "master", "dir/bar", strings.NewReader("bar"))
+		require.NoError(t, err)
+		_, err = env.PachClient.PutFile(repo, "master", "dir/dir2/buzz", strings.NewReader("buzz"))
+		require.NoError(t, err)
+		_, err = env.PachClient.PutFile(repo, "master", "foo", strings.NewReader("foo"))
+		require.NoError(t, err)
+
+		walk := func(request *pfs.WalkFileRequest) ([]string, map[string]int64) {
+			request.File = pclient.NewFile(repo, "master", "")
+			var paths []string
+			counts := make(map[string]int64)
+			require.NoError(t, env.PachClient.WalkFileF(request, func(fi *pfs.FileInfo) error {
+				paths = append(paths, fi.File.Path)
+				if fi.FileType == pfs.FileType_DIR {
+					counts[fi.File.Path] = fi.FileCount
+				}
+				return nil
+			}))
+			return paths, counts
+		}
+		paths, _ := walk(&pfs.WalkFileRequest{MaxDepth: 1})
+		require.Equal(t, []string{"/", "/dir", "/foo"}, paths)
+		paths, _ = walk(&pfs.WalkFileRequest{FileType: pfs.FileType_FILE})
+		require.Equal(t, []string{"/dir/bar", "/dir/dir2/buzz", "/foo"}, paths)
+		paths, counts := walk(&pfs.WalkFileRequest{FileType: pfs.FileType_DIR, MaxDepth: 1, Aggregate: true})
+		require.Equal(t, []string{"/dir", "/"}, paths)
+		require.Equal(t, map[string]int64{"/": 3, "/dir": 2}, counts)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
 func TestReadSizeLimited(t *testing.T) {
 	t.Parallel()
 	err := tu.WithRealEnv(func(env *tu.RealEnv) error {
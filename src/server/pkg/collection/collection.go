@@ -34,12 +34,16 @@ var (
 	// the current requester to a key claim.
 	ErrNotClaimed = fmt.Errorf("NOT_CLAIMED")
 	ttl           = int64(30)
+
+	errClaimOverwritten = fmt.Errorf("claimed key was overwritten")
 )
 
 type collection struct {
 	etcdClient *etcd.Client
 	prefix     string
-	indexes    []*Index
+	// name labels the collection's metrics (see collectionName)
+	name    string
+	indexes []*Index
 	// The limit used when listing the collection. This gets automatically
 	// tuned when requests fail so it's stored per collection.
 	limit int64
@@ -70,6 +74,7 @@ func NewCollection(etcdClient *etcd.Client, prefix string, indexes []*Index, tem
 
 	return &collection{
 		prefix:     prefix,
+		name:       collectionName(prefix),
 		etcdClient: etcdClient,
 		indexes:    indexes,
 		limit:      defaultLimit,
@@ -101,6 +106,16 @@ func (c *collection) ReadOnly(ctx context.Context) ReadonlyCollection {
 }
 
 func (c *collection) Claim(ctx context.Context, key string, val proto.Message, f func(context.Context) error) error {
+	return c.claim(ctx, key, val, false, f)
+}
+
+func (c *collection) ClaimUnchanged(ctx context.Context, key string, val proto.Message, f func(context.Context) error) error {
+	return c.claim(ctx, key, val, true, f)
+}
+
+// claim implements Claim and ClaimUnchanged. If 'unchanged' is set, the claim
+// is only renewed while the key still holds the claimed value.
+func (c *collection) claim(ctx context.Context, key string, val proto.Message, unchanged bool, f func(context.Context) error) error {
 	var claimed bool
 	if _, err := NewSTM(ctx, c.etcdClient, func(stm STM) error {
 		readWriteC := c.ReadWrite(stm)
@@ -121,6 +136,7 @@ func (c *collection) Claim(ctx context.Context, key string, val proto.Message, f
 	}
 	claimCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
+	claimedVal := proto.Clone(val)
 	go func() {
 		for {
 			select {
@@ -132,6 +148,9 @@ func (c *collection) Claim(ctx context.Context, key string, val proto.Message, f
 					if err := readWriteC.Get(key, val); err != nil {
 						return err
 					}
+					if unchanged && !proto.Equal(val, claimedVal) {
+						return errClaimOverwritten
+					}
 					return readWriteC.PutTTL(key, val, ttl)
 				}); err != nil {
 					cancel()
@@ -498,6 +517,7 @@ func (c *readonlyCollection) get(key string, opts ...etcd.OpOption) (resp *etcd.
 }
 
 func (c *readonlyCollection) Get(key string, val proto.Message) error {
+	defer c.observe("Get", time.Now())
 	if err := watch.CheckType(c.template, val); err != nil {
 		return err
 	}
@@ -514,6 +534,7 @@ func (c *readonlyCollection) Get(key string, val proto.Message) error {
 }
 
 func (c *readonlyCollection) GetByIndex(index *Index, indexVal interface{}, val proto.Message, opts *Options, f func(key string) error) error {
+	defer c.observe("GetByIndex", time.Now())
 	span, _ := tracing.AddSpanToAnyExisting(c.ctx, "/etcd.RO/GetByIndex", "col", c.prefix, "index", index, "indexVal", indexVal)
 	defer tracing.FinishAnySpan(span)
 	if atomic.LoadInt64(&index.limit) == 0 {
@@ -553,6 +574,7 @@ func (c *readonlyCollection) GetBlock(key string, val proto.Message) error {
 }
 
 func (c *readonlyCollection) TTL(key string) (int64, error) {
+	defer c.observe("TTL", time.Now())
 	resp, err := c.get(c.Path(key))
 	if err != nil {
 		return 0, err
@@ -575,6 +597,7 @@ func (c *readonlyCollection) TTL(key string) (int64, error) {
 // called with each key, val will contain the value for the key.
 // You can break out of iteration by returning errutil.ErrBreak.
 func (c *readonlyCollection) ListPrefix(prefix string, val proto.Message, opts *Options, f func(string) error) error {
+	defer c.observe("ListPrefix", time.Now())
 	span, _ := tracing.AddSpanToAnyExisting(c.ctx, "/etcd.RO/ListPrefix", "col", c.prefix, "prefix", prefix)
 	defer tracing.FinishAnySpan(span)
 	queryPrefix := c.prefix
@@ -596,6 +619,7 @@ func (c *readonlyCollection) ListPrefix(prefix string, val proto.Message, opts *
 // f to perform a cast before it could be used.
 // You can break out of iteration by returning errutil.ErrBreak.
 func (c *readonlyCollection) List(val proto.Message, opts *Options, f func(key string) error) error {
+	defer c.observe("List", time.Now())
 	span, _ := tracing.AddSpanToAnyExisting(c.ctx, "/etcd.RO/List", "col", c.prefix)
 	defer tracing.FinishAnySpan(span)
 	if err := watch.CheckType(c.template, val); err != nil {
@@ -615,6 +639,7 @@ func (c *readonlyCollection) List(val proto.Message, opts *Options, f func(key s
 // f to perform a cast before it could be used.  You can break out of iteration
 // by returning errutil.ErrBreak.
 func (c *readonlyCollection) ListRev(val proto.Message, opts *Options, f func(key string, createRev int64) error) error {
+	defer c.observe("ListRev", time.Now())
 	span, _ := tracing.AddSpanToAnyExisting(c.ctx, "/etcd.RO/List", "col", c.prefix)
 	defer tracing.FinishAnySpan(span)
 	if err := watch.CheckType(c.template, val); err != nil {
@@ -637,6 +662,7 @@ func (c *readonlyCollection) list(prefix string, limitPtr *int64, opts *Options,
 }
 
 func (c *readonlyCollection) Count() (int64, error) {
+	defer c.observe("Count", time.Now())
 	resp, err := c.get(c.prefix, etcd.WithPrefix(), etcd.WithCountOnly())
 	if err != nil {
 		return 0, err
@@ -572,6 +572,41 @@ func TestTTLExtend(t *testing.T) {
 	require.True(t, actualTTL > TTL && actualTTL < LongerTTL, "actualTTL was %v", actualTTL)
 }
 
+func TestClaimUnchanged(t *testing.T) {
+	etcdClient := getEtcdClient()
+	uuidPrefix := uuid.NewWithoutDashes()
+
+	// Shorten the claim TTL, so that the claim is renewed every second
+	defer func(oldTTL int64) { ttl = oldTTL }(ttl)
+	ttl = 2
+
+	clxn := NewCollection(etcdClient, uuidPrefix, nil, &types.BoolValue{}, nil, nil)
+	require.NoError(t, clxn.ClaimUnchanged(context.Background(), "key", &types.BoolValue{}, func(ctx context.Context) error {
+		// The claim is renewed while the key holds the claimed value
+		time.Sleep(time.Duration(ttl+1) * time.Second)
+		require.NoError(t, ctx.Err())
+		require.NoError(t, clxn.ReadOnly(context.Background()).Get("key", &types.BoolValue{}))
+
+		// Once the key is overwritten, the claim ends
+		_, err := NewSTM(context.Background(), etcdClient, func(stm STM) error {
+			return clxn.ReadWrite(stm).Put("key", epsilon)
+		})
+		require.NoError(t, err)
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Duration(ttl*2) * time.Second):
+			t.Fatal("claim wasn't ended after the key was overwritten")
+		}
+		return nil
+	}))
+
+	// The new value wasn't given the claim's TTL
+	time.Sleep(time.Duration(ttl+1) * time.Second)
+	value := &types.BoolValue{}
+	require.NoError(t, clxn.ReadOnly(context.Background()).Get("key", value))
+	require.True(t, value.Value)
+}
+
 func TestIteration(t *testing.T) {
 	etcdClient := getEtcdClient()
 	t.Run("one-val-per-txn", func(t *testing.T) {
@@ -0,0 +1,150 @@
+package collection
+
+import (
+	"fmt"
+	"path"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultSlowOperationThreshold is how long an etcd operation (a read
+// through a collection, or an STM) can take before it's logged as slow, unless
+// SetSlowOperationThreshold sets another threshold.
+const DefaultSlowOperationThreshold = time.Second
+
+var (
+	operationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "pachyderm",
+			Subsystem: "etcd",
+			Name:      "operation_seconds",
+			Help:      "Latency of the etcd reads made through collections, by operation and collection",
+			Buckets:   prometheus.ExponentialBuckets(0.001, 4, 8),
+		},
+		[]string{"operation", "collection"},
+	)
+	stmSeconds = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: "pachyderm",
+			Subsystem: "etcd",
+			Name:      "stm_seconds",
+			Help:      "Latency of STM transactions, including their retries",
+			Buckets:   prometheus.ExponentialBuckets(0.001, 4, 8),
+		},
+	)
+	stmRetries = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: "pachyderm",
+			Subsystem: "etcd",
+			Name:      "stm_retries",
+			Help:      "Number of times STM transactions were retried because of conflicting writes",
+			Buckets:   []float64{0, 1, 2, 4, 8, 16, 32},
+		},
+	)
+	stmKeys = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "pachyderm",
+			Subsystem: "etcd",
+			Name:      "stm_keys",
+			Help:      "Number of keys that STM transactions read and wrote, by kind (read or write)",
+			Buckets:   prometheus.ExponentialBuckets(1, 4, 8),
+		},
+		[]string{"kind"},
+	)
+	registerMetricsOnce sync.Once
+
+	// slowOperationThreshold is a time.Duration, it's 0 if slow operations
+	// aren't logged
+	slowOperationThreshold = int64(DefaultSlowOperationThreshold)
+)
+
+// SetSlowOperationThreshold sets how long an etcd operation (a read through a
+// collection, or an STM) can take before it's logged as slow. If 'threshold'
+// is 0, no operations are logged.
+func SetSlowOperationThreshold(threshold time.Duration) {
+	atomic.StoreInt64(&slowOperationThreshold, int64(threshold))
+}
+
+func registerMetrics() {
+	registerMetricsOnce.Do(func() {
+		for _, c := range []prometheus.Collector{operationSeconds, stmSeconds, stmRetries, stmKeys} {
+			if err := prometheus.Register(c); err != nil {
+				// metrics may be redundantly registered; ignore these errors
+				if _, ok := err.(prometheus.AlreadyRegisteredError); !ok {
+					log.Errorf("error registering prometheus metric: %v", err)
+				}
+			}
+		}
+	})
+}
+
+// isSlow returns true if an operation that took 'd' is logged as slow
+func isSlow(d time.Duration) bool {
+	threshold := time.Duration(atomic.LoadInt64(&slowOperationThreshold))
+	return threshold > 0 && d >= threshold
+}
+
+// collectionName returns the name that a collection with 'prefix' is
+// labelled with in metrics and logs, which is the last element of 'prefix'
+// (e.g. "pipelines")
+func collectionName(prefix string) string {
+	return path.Base(strings.TrimSuffix(prefix, "/"))
+}
+
+// observe records the latency of 'operation', which started at 'start', and
+// logs it if it's slow
+func (c *collection) observe(operation string, start time.Time) {
+	registerMetrics()
+	d := time.Since(start)
+	operationSeconds.WithLabelValues(operation, c.name).Observe(d.Seconds())
+	if isSlow(d) {
+		log.Warnf("slow etcd operation: %s on collection %s took %v", operation, c.name, d)
+	}
+}
+
+// callSite is where an STM was started, for slow STM logs. It's only
+// formatted if the STM is slow.
+type callSite struct {
+	pc   uintptr
+	file string
+	line int
+}
+
+// stmCaller returns the call site of the function that started an STM:
+// 'skip' is the number of frames between stmCaller's caller and that
+// function
+func stmCaller(skip int) callSite {
+	pc, file, line, _ := runtime.Caller(skip + 2)
+	return callSite{pc: pc, file: file, line: line}
+}
+
+func (c callSite) String() string {
+	if c.file == "" {
+		return "unknown"
+	}
+	if f := runtime.FuncForPC(c.pc); f != nil {
+		return fmt.Sprintf("%s (%s:%d)", f.Name(), path.Base(c.file), c.line)
+	}
+	return fmt.Sprintf("%s:%d", path.Base(c.file), c.line)
+}
+
+// observeSTM records the latency, retries and size of an STM that started at
+// 'start', and logs it if it's slow
+func observeSTM(caller callSite, start time.Time, retries int, reads int, writes int) {
+	registerMetrics()
+	d := time.Since(start)
+	stmSeconds.Observe(d.Seconds())
+	stmRetries.Observe(float64(retries))
+	stmKeys.WithLabelValues("read").Observe(float64(reads))
+	stmKeys.WithLabelValues("write").Observe(float64(writes))
+	if isSlow(d) {
+		log.Warnf("slow etcd transaction: STM started by %s took %v (%d retries, %d keys read, %d keys written)",
+			caller, d, retries, reads, writes)
+	}
+}
@@ -0,0 +1,73 @@
+package collection
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/uuid"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestCollectionName(t *testing.T) {
+	require.Equal(t, "pipelines", collectionName("pachyderm_pps/pipelines/"))
+	require.Equal(t, "pipelines", collectionName("pipelines"))
+}
+
+func TestSlowOperationThreshold(t *testing.T) {
+	defer SetSlowOperationThreshold(DefaultSlowOperationThreshold)
+	require.False(t, isSlow(time.Millisecond))
+	require.True(t, isSlow(2*time.Second))
+	SetSlowOperationThreshold(0)
+	require.False(t, isSlow(time.Hour))
+}
+
+func sampleSum(t *testing.T, h prometheus.Histogram) (uint64, float64) {
+	m := &dto.Metric{}
+	require.NoError(t, h.Write(m))
+	return m.Histogram.GetSampleCount(), m.Histogram.GetSampleSum()
+}
+
+func TestSTMMetrics(t *testing.T) {
+	etcdClient := getEtcdClient()
+	jobInfos := NewCollection(etcdClient, uuid.NewWithoutDashes(), nil, &pps.JobInfo{}, nil, nil)
+	j1 := &pps.JobInfo{
+		Job:      client.NewJob("j1"),
+		Pipeline: client.NewPipeline("p1"),
+	}
+	_, err := NewSTM(context.Background(), etcdClient, func(stm STM) error {
+		return jobInfos.ReadWrite(stm).Put(j1.Job.ID, j1)
+	})
+	require.NoError(t, err)
+
+	count, retries := sampleSum(t, stmRetries)
+	// The first attempt conflicts with a write outside of the STM, so the
+	// STM is retried once
+	var attempts int
+	_, err = NewSTM(context.Background(), etcdClient, func(stm STM) error {
+		attempts++
+		job := &pps.JobInfo{}
+		if err := jobInfos.ReadWrite(stm).Get(j1.Job.ID, job); err != nil {
+			return err
+		}
+		if attempts == 1 {
+			if _, err := NewSTM(context.Background(), etcdClient, func(stm STM) error {
+				return jobInfos.ReadWrite(stm).Put(j1.Job.ID, j1)
+			}); err != nil {
+				return err
+			}
+		}
+		return jobInfos.ReadWrite(stm).Put(j1.Job.ID, job)
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, attempts)
+	newCount, newRetries := sampleSum(t, stmRetries)
+	// the inner STM and the retried one
+	require.Equal(t, count+2, newCount)
+	require.Equal(t, retries+1, newRetries)
+}
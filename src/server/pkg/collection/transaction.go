@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	v3 "github.com/coreos/etcd/clientv3"
 	"github.com/coreos/etcd/etcdserver/api/v3rpc/rpctypes"
@@ -57,6 +58,8 @@ type STM interface {
 	commit() *v3.TxnResponse
 	reset()
 	fetch(key string) *v3.GetResponse
+	// size returns the number of keys that the txn read and wrote
+	size() (reads int, writes int)
 }
 
 // stmError safely passes STM errors through panic to the STM error channel.
@@ -81,7 +84,8 @@ func newSTMSerializable(ctx context.Context, c *v3.Client, apply func(STM) error
 		stm:      stm{client: c, ctx: ctx},
 		prefetch: make(map[string]*v3.GetResponse),
 	}
-	return runSTM(s, apply, dryrun)
+	// skip newSTMSerializable and NewSTM (or NewDryrunSTM)
+	return runSTM(s, apply, dryrun, stmCaller(1))
 }
 
 type stmResponse struct {
@@ -89,7 +93,13 @@ type stmResponse struct {
 	err  error
 }
 
-func runSTM(s STM, apply func(STM) error, dryrun bool) (*v3.TxnResponse, error) {
+func runSTM(s STM, apply func(STM) error, dryrun bool, caller callSite) (*v3.TxnResponse, error) {
+	start := time.Now()
+	var attempts int
+	defer func() {
+		reads, writes := s.size()
+		observeSTM(caller, start, attempts-1, reads, writes)
+	}()
 	outc := make(chan stmResponse, 1)
 	go func() {
 		defer func() {
@@ -104,6 +114,7 @@ func runSTM(s STM, apply func(STM) error, dryrun bool) (*v3.TxnResponse, error)
 		}()
 		var out stmResponse
 		for {
+			attempts++
 			s.reset()
 			if out.err = apply(s); out.err != nil {
 				break
@@ -339,6 +350,10 @@ func (s *stm) writes() []v3.Op {
 	return writes
 }
 
+func (s *stm) size() (int, int) {
+	return len(s.rset), len(s.wset) + len(s.deletedPrefixes)
+}
+
 func (s *stm) reset() {
 	s.rset = make(map[string]*v3.GetResponse)
 	s.wset = make(map[string]stmPut)
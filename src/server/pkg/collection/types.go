@@ -30,6 +30,10 @@ type Collection interface {
 	// Claim attempts to claim a key and run the passed in callback with
 	// the context for the claim.
 	Claim(ctx context.Context, key string, val proto.Message, f func(context.Context) error) error
+	// ClaimUnchanged is the same as Claim, except that the claim ends (and its
+	// context is cancelled) once the key's value is overwritten, rather than
+	// renewing the new value's TTL
+	ClaimUnchanged(ctx context.Context, key string, val proto.Message, f func(context.Context) error) error
 }
 
 // Index specifies a secondary index on a collection.
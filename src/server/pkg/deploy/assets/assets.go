@@ -70,6 +70,16 @@ var (
 		Verbs:         []string{"get", "list", "watch", "create", "update", "delete"},
 		Resources:     []string{"secrets"},
 		ResourceNames: []string{client.StorageSecretName},
+	}, {
+		// pachd reads the database URLs of SQL inputs from secrets
+		APIGroups: []string{""},
+		Verbs:     []string{"get"},
+		Resources: []string{"secrets"},
+	}, {
+		// pachd creates the ingresses of service pipelines
+		APIGroups: []string{"extensions"},
+		Verbs:     []string{"get", "list", "watch", "create", "update", "delete"},
+		Resources: []string{"ingresses"},
 	}}
 
 	// The name of the local volume (mounted kubernetes secret) where pachd
@@ -209,6 +219,10 @@ type AssetOpts struct {
 	// auth) but is needed by tests
 	ExposeObjectAPI bool
 
+	// WorkerLogSink, if set, is the log sink (e.g. "loki://loki:3100") that
+	// pipeline workers send their logs to, in addition to stdout
+	WorkerLogSink string
+
 	// If set, the files indictated by 'TLS.ServerCert' and 'TLS.ServerKey' are
 	// placed into a Kubernetes secret and used by pachd nodes to authenticate
 	// during TLS
@@ -560,6 +574,7 @@ func PachdDeployment(opts *AssetOpts, objectStoreBackend backend, hostPath strin
 									},
 								},
 								{Name: "EXPOSE_OBJECT_API", Value: strconv.FormatBool(opts.ExposeObjectAPI)},
+								{Name: "WORKER_LOG_SINK", Value: opts.WorkerLogSink},
 							}, GetSecretEnvVars("")...),
 							Ports: []v1.ContainerPort{
 								{
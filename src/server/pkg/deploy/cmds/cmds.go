@@ -671,6 +671,7 @@ If <object store backend> is \"s3\", then the arguments are:
 	var pachdShards int
 	var registry string
 	var tlsCertKey string
+	var workerLogSink string
 	deploy := &cobra.Command{
 		Short: "Deploy a Pachyderm cluster.",
 		Long:  "Deploy a Pachyderm cluster.",
@@ -716,6 +717,7 @@ If <object store backend> is \"s3\", then the arguments are:
 				Namespace:               namespace,
 				NoExposeDockerSocket:    noExposeDockerSocket,
 				ExposeObjectAPI:         exposeObjectAPI,
+				WorkerLogSink:           workerLogSink,
 			}
 			if tlsCertKey != "" {
 				// TODO(msteffen): If either the cert path or the key path contains a
@@ -750,6 +752,7 @@ If <object store backend> is \"s3\", then the arguments are:
 	deploy.PersistentFlags().StringVar(&namespace, "namespace", "", "Kubernetes namespace to deploy Pachyderm to.")
 	deploy.PersistentFlags().BoolVar(&noExposeDockerSocket, "no-expose-docker-socket", false, "Don't expose the Docker socket to worker containers. This limits the privileges of workers which prevents them from automatically setting the container's working dir and user.")
 	deploy.PersistentFlags().BoolVar(&exposeObjectAPI, "expose-object-api", false, "If set, instruct pachd to serve its object/block API on its public port (not safe with auth enabled, do not set in production).")
+	deploy.PersistentFlags().StringVar(&workerLogSink, "worker-log-sink", "", "If set, pipeline workers also send their logs to this log sink, one of \"loki://<host>:<port>\", \"fluentd://<host>:<port>\" or \"syslog://<host>:<port>\" (see the docs for their options). Useful if the cluster doesn't collect the logs of its pods.")
 	deploy.PersistentFlags().StringVar(&tlsCertKey, "tls", "", "string of the form \"<cert path>,<key path>\" of the signed TLS certificate and private key that Pachd should use for TLS authentication (enables TLS-encrypted communication with Pachd)")
 	deploy.PersistentFlags().BoolVar(&newStorageLayer, "new-storage-layer", false, "(feature flag) Do not set, used for testing.")
 	deploy.PersistentFlags().StringVarP(&contextName, "context", "c", "", "Name of the context to add to the pachyderm config.")
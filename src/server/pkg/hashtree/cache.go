@@ -1,6 +1,7 @@
 package hashtree
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"reflect"
@@ -164,6 +165,12 @@ func (c *MergeCache) Delete(id int64) error {
 // The results are written to the passed in *Writer.
 // The base field is used as the base hashtree if it is non-nil
 func (c *MergeCache) Merge(w *Writer, base io.Reader, filter Filter) (retErr error) {
+	return c.MergeWithProgress(context.Background(), w, base, filter, nil)
+}
+
+// MergeWithProgress is like Merge, but it can be cancelled and reports its
+// progress (see the package-level MergeWithProgress).
+func (c *MergeCache) MergeWithProgress(ctx context.Context, w *Writer, base io.Reader, filter Filter, progress MergeProgress) (retErr error) {
 	var trees []*Reader
 	if base != nil {
 		trees = append(trees, NewReader(base, filter))
@@ -180,5 +187,5 @@ func (c *MergeCache) Merge(w *Writer, base io.Reader, filter Filter) (retErr err
 		}()
 		trees = append(trees, NewReader(r, filter))
 	}
-	return Merge(w, trees)
+	return MergeWithProgress(ctx, w, trees, progress)
 }
@@ -37,6 +37,9 @@ const (
 	IndexPath = "-index"
 	// IndexSize is the size of the index chunks.
 	IndexSize = uint64(1 << (10 * 2))
+	// MergeProgressInterval is the number of nodes a merge writes between
+	// checks for cancellation and calls to its progress callback.
+	MergeProgressInterval = 10000
 )
 
 var (
@@ -368,6 +371,115 @@ func Walk(rs []io.ReadCloser, walkPath string, f func(path string, node *NodePro
 	})
 }
 
+// listFromBounds returns the key prefix of the children of 'path', the key
+// prefix of the nodes that ListFrom visits (nil if it can't visit any), and
+// the key of 'after' (nil if it's empty). 'prefix' isn't cleaned, as a
+// trailing slash matters (i.e. "/dir/" doesn't match "/dir2").
+func listFromBounds(path, prefix, after string) (dir, p, afterKey []byte) {
+	dir = b(clean(path))
+	if !bytes.Equal(dir, nullByte) {
+		dir = append(dir, nullByte[0])
+	}
+	p = dir
+	if prefix != "" {
+		if !strings.HasPrefix(prefix, "/") {
+			prefix = "/" + prefix
+		}
+		p = slashEncode([]byte(prefix))
+		if !bytes.HasPrefix(p, dir) {
+			if !bytes.HasPrefix(dir, p) {
+				// 'prefix' is outside of 'path'
+				return dir, nil, nil
+			}
+			p = dir
+		}
+	}
+	if after != "" {
+		afterKey = b(clean(after))
+	}
+	return dir, p, afterKey
+}
+
+// successor returns the first key after the subtree of 'k'
+func successor(k []byte) []byte {
+	result := make([]byte, len(k), len(k)+1)
+	copy(result, k)
+	return append(result, 1)
+}
+
+// ListFrom calls f, in order, with the children of the directory at 'path'
+// (or every node under it, if 'recursive' is set) whose paths start with
+// 'prefix' and sort after 'after'.
+func (h *dbHashTree) ListFrom(path, prefix, after string, recursive bool, f func(path string, node *NodeProto) error) error {
+	dir, p, afterKey := listFromBounds(path, prefix, after)
+	if p == nil {
+		return nil
+	}
+	start := p
+	if bytes.Compare(afterKey, start) > 0 {
+		start = afterKey
+	}
+	return h.View(func(tx *bolt.Tx) error {
+		c := fs(tx).Cursor()
+		k, v := c.Seek(start)
+		for k != nil && bytes.HasPrefix(k, p) {
+			rest := k[len(dir):]
+			if i := bytes.IndexByte(rest, nullByte[0]); !recursive && i >= 0 {
+				// k is under a child of 'path' that either was visited
+				// already or sorts before 'after', so skip the child's subtree
+				k, v = c.Seek(successor(k[:len(dir)+i]))
+				continue
+			}
+			if len(rest) > 0 && !bytes.Equal(k, afterKey) {
+				node := &NodeProto{}
+				if err := node.Unmarshal(v); err != nil {
+					return err
+				}
+				if err := f(s(k), node); err != nil {
+					if err == errutil.ErrBreak {
+						return nil
+					}
+					return err
+				}
+			}
+			if recursive {
+				k, v = c.Next()
+			} else {
+				k, v = c.Seek(successor(k))
+			}
+		}
+		return nil
+	})
+}
+
+// ListFrom calls f, in order, with the children of the directory at 'path'
+// (or every node under it, if 'recursive' is set) whose paths start with
+// 'prefix' and sort after 'after'. Serialized trees can't be seeked, so the
+// nodes before 'after' are read and skipped.
+func ListFrom(rs []io.ReadCloser, path, prefix, after string, recursive bool, f func(path string, node *NodeProto) error) error {
+	dir, p, afterKey := listFromBounds(path, prefix, after)
+	if p == nil {
+		return nil
+	}
+	if err := nodes(rs, func(path string, node *NodeProto) error {
+		k := b(path)
+		if !bytes.HasPrefix(k, p) && bytes.Compare(k, p) > 0 {
+			// nodes are in order, so there are no more under 'prefix'
+			return errutil.ErrBreak
+		}
+		if !bytes.HasPrefix(k, p) || len(k) == len(dir) || bytes.Compare(k, afterKey) <= 0 {
+			return nil
+		}
+		if !recursive && bytes.IndexByte(k[len(dir):], nullByte[0]) >= 0 {
+			return nil
+		}
+		return f(path, node)
+	}); err != nil && err != errutil.ErrBreak {
+		return err
+	}
+	return nil
+}
+
 func diff(newTx, oldTx *bolt.Tx, newPath string, oldPath string, recursiveDepth int64, f func(string, *NodeProto, bool) error) error {
 	newNode, err := get(newTx, clean(newPath))
 	if err != nil && Code(err) != PathNotFound {
@@ -1271,11 +1383,28 @@ func (mq *mergePQ) swap(i, j int) {
 	mq.q[i], mq.q[j] = mq.q[j], mq.q[i]
 }
 
+// MergeProgress is called periodically during a merge with the number of
+// nodes that have been written so far. Returning an error aborts the merge.
+type MergeProgress func(nodes int64) error
+
 // Merge merges a collection of hashtree readers into a hashtree writer.
 func Merge(w *Writer, rs []*Reader) error {
+	return MergeWithProgress(context.Background(), w, rs, nil)
+}
+
+// MergeWithProgress is like Merge, except that it returns ctx's error if ctx
+// is cancelled (checked before the merge starts and every
+// MergeProgressInterval nodes), and it calls progress (if it's non-nil) every
+// MergeProgressInterval nodes and once the merge is done, so that the final
+// total is reported exactly once.
+func MergeWithProgress(ctx context.Context, w *Writer, rs []*Reader, progress MergeProgress) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	if len(rs) == 0 {
 		return nil
 	}
+	var written int64
 	mq := &mergePQ{q: make([]*nodeStream, len(rs)+1)}
 	// Setup first set of nodes
 	for _, r := range rs {
@@ -1298,6 +1427,20 @@ func Merge(w *Writer, rs []*Reader) error {
 		if err := w.Write(n); err != nil {
 			return err
 		}
+		written++
+		if written%MergeProgressInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if progress != nil {
+				if err := progress(written); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if progress != nil && (written == 0 || written%MergeProgressInterval != 0) {
+		return progress(written)
 	}
 	return nil
 }
@@ -1599,10 +1742,22 @@ func compare(a, b *ChildCursor) int {
 }
 
 // Ordered is an in memory version of the hashtree that is optimized and only works for lexicographically ordered inserts followed by serialization.
+// An Ordered created with NewSpillableOrdered moves finished nodes to a bolt
+// database on local disk, so its memory usage is bounded by the spill size.
 type Ordered struct {
 	fs       []*node
 	dirStack []*node
 	root     string
+	// spill is the on disk store for finished nodes, it's opened in
+	// storageRoot the first time spillSize is exceeded (spillSize is 0 for in
+	// memory trees)
+	spill       *bolt.DB
+	spillFile   string
+	spillSize   int
+	storageRoot string
+	// err is the first error encountered while spilling (PutFile and PutDir
+	// don't return errors, so it's returned by Serialize)
+	err error
 }
 
 type node struct {
@@ -1630,6 +1785,96 @@ func NewOrdered(root string) *Ordered {
 	return o
 }
 
+// NewSpillableOrdered creates a new ordered hashtree that keeps at most
+// spillSize finished nodes (files and closed directories) in memory. Once
+// that's exceeded, they're moved to a bolt database in storageRoot, which is
+// only created at that point. Close should be called to remove the database
+// when the tree is no longer needed.
+func NewSpillableOrdered(root string, storageRoot string, spillSize int) *Ordered {
+	o := NewOrdered(root)
+	o.storageRoot = storageRoot
+	o.spillSize = spillSize
+	return o
+}
+
+// Close removes the on disk store of a spillable ordered hashtree, it's a
+// no-op if nothing was spilled.
+func (o *Ordered) Close() error {
+	if o.spill == nil {
+		return nil
+	}
+	err := o.spill.Close()
+	if err := os.Remove(o.spillFile); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	o.spill = nil
+	return err
+}
+
+// openSpill creates the bolt database that finished nodes are spilled to.
+func (o *Ordered) openSpill() error {
+	file := dbFile(o.storageRoot)
+	if err := os.MkdirAll(pathlib.Dir(file), 0777); err != nil {
+		return err
+	}
+	db, err := bolt.Open(file, perm, nil)
+	if err != nil {
+		return err
+	}
+	db.NoSync = true
+	db.NoGrowSync = true
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(b(FsBucket))
+		return err
+	}); err != nil {
+		db.Close()
+		os.Remove(file)
+		return err
+	}
+	o.spill = db
+	o.spillFile = file
+	return nil
+}
+
+// maybeSpill moves the finished nodes in o.fs to the on disk store if there
+// are more than o.spillSize of them. Nodes that are still in the directory
+// stack may be modified by later inserts, so they're kept in memory (every
+// node in the directory stack is also in o.fs).
+func (o *Ordered) maybeSpill() {
+	if o.spillSize <= 0 || o.err != nil || len(o.fs)-len(o.dirStack) <= o.spillSize {
+		return
+	}
+	if o.spill == nil {
+		if o.err = o.openSpill(); o.err != nil {
+			return
+		}
+	}
+	open := make(map[*node]bool)
+	for _, n := range o.dirStack {
+		open[n] = true
+	}
+	var inMemory []*node
+	if o.err = o.spill.Update(func(tx *bolt.Tx) error {
+		for _, n := range o.fs {
+			if open[n] {
+				inMemory = append(inMemory, n)
+				continue
+			}
+			v, err := n.nodeProto.Marshal()
+			if err != nil {
+				return err
+			}
+			if err := fs(tx).Put(b(n.path), v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); o.err != nil {
+		return
+	}
+	o.fs = inMemory
+}
+
 // MkdirAll puts all of the parent directories of a given
 // path into the hashtree.
 func (o *Ordered) MkdirAll(path string) {
@@ -1666,6 +1911,7 @@ func (o *Ordered) putDir(path string, nodeProto *NodeProto) {
 	}
 	o.fs = append(o.fs, n)
 	o.dirStack = append(o.dirStack, n)
+	o.maybeSpill()
 }
 
 // PutFile puts a file in the hashtree.
@@ -1690,6 +1936,7 @@ func (o *Ordered) putFile(path string, nodeProto *NodeProto) {
 	o.fs = append(o.fs, n)
 	o.dirStack[len(o.dirStack)-1].hash.Write([]byte(fmt.Sprintf("%s:%s:", n.nodeProto.Name, n.nodeProto.Hash)))
 	o.dirStack[len(o.dirStack)-1].nodeProto.SubtreeSize += nodeProto.SubtreeSize
+	o.maybeSpill()
 }
 
 func (o *Ordered) handleEndOfDirectory(path string) {
@@ -1706,6 +1953,9 @@ func (o *Ordered) handleEndOfDirectory(path string) {
 
 // Serialize serializes an ordered hashtree.
 func (o *Ordered) Serialize(_w io.Writer) error {
+	if o.err != nil {
+		return o.err
+	}
 	w := NewWriter(_w)
 	// Unwind directory stack
 	for len(o.dirStack) > 1 {
@@ -1717,15 +1967,45 @@ func (o *Ordered) Serialize(_w io.Writer) error {
 		parent.nodeProto.SubtreeSize += child.nodeProto.SubtreeSize
 	}
 	o.fs[0].nodeProto.Hash = o.fs[0].hash.Sum(nil)
-	for _, n := range o.fs {
-		if err := w.Write(&MergeNode{
-			k:         b(n.path),
-			nodeProto: n.nodeProto,
-		}); err != nil {
-			return err
+	if o.spill == nil {
+		for _, n := range o.fs {
+			if err := w.Write(&MergeNode{
+				k:         b(n.path),
+				nodeProto: n.nodeProto,
+			}); err != nil {
+				return err
+			}
 		}
+		return nil
 	}
-	return nil
+	// Both the spilled nodes and the in memory nodes are sorted, so merge them
+	return o.spill.View(func(tx *bolt.Tx) error {
+		c := fs(tx).Cursor()
+		k, v := c.First()
+		i := 0
+		for k != nil || i < len(o.fs) {
+			if k != nil && (i == len(o.fs) || bytes.Compare(k, b(o.fs[i].path)) < 0) {
+				// The writer holds on to keys for its index, and bolt's keys
+				// are only valid during the transaction
+				if err := w.Write(&MergeNode{
+					k: append([]byte{}, k...),
+					v: v,
+				}); err != nil {
+					return err
+				}
+				k, v = c.Next()
+				continue
+			}
+			if err := w.Write(&MergeNode{
+				k:         b(o.fs[i].path),
+				nodeProto: o.fs[i].nodeProto,
+			}); err != nil {
+				return err
+			}
+			i++
+		}
+		return nil
+	})
 }
 
 // Unordered is an in memory version of the hashtree that supports random inserts. This will look more like the old version of hashtrees over time, with the key differences being that it supports arbitrary rooting and can easily be converted into a sorted tree.
@@ -2,14 +2,20 @@ package hashtree
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha256"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
 	"testing"
 
 	bolt "github.com/coreos/bbolt"
 	"github.com/golang/protobuf/proto"
 	"github.com/pachyderm/pachyderm/src/client/pfs"
 	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/server/pkg/errutil"
 )
 
 // obj parses a string as an Object
@@ -500,6 +506,72 @@ func TestWalk(t *testing.T) {
 	require.Equal(t, len(expectedPaths), i)
 }
 
+func TestListFrom(t *testing.T) {
+	// ListFrom is tested against a tree and its serialized form, which is
+	// written in key order (in which '/' sorts before other characters)
+	h := newHashTree(t)
+	o := NewOrdered("/")
+	for _, p := range []string{"/dir/", "/dir/bar", "/dir/baz/", "/dir/baz/buzz", "/dir/qux", "/dir.bar", "/dir2/", "/dir2/buzz", "/foo"} {
+		if strings.HasSuffix(p, "/") {
+			o.PutDir(strings.TrimSuffix(p, "/"))
+			continue
+		}
+		require.NoError(t, h.PutFile(p, obj(`hash:"20c27"`), 1))
+		o.PutFile(p, []byte(p), 1, &FileNodeProto{BlockRefs: blocks(``)})
+	}
+	require.NoError(t, h.Hash())
+	serialized := &bytes.Buffer{}
+	require.NoError(t, o.Serialize(serialized))
+
+	for _, c := range []struct {
+		path, prefix, after string
+		recursive           bool
+		expected            []string
+	}{
+		{"/", "", "", false, []string{"/dir", "/dir.bar", "/dir2", "/foo"}},
+		{"/", "/dir", "", false, []string{"/dir", "/dir.bar", "/dir2"}},
+		// a trailing slash only matches the directory's children
+		{"/dir", "/dir/", "", false, []string{"/dir/bar", "/dir/baz", "/dir/qux"}},
+		{"/dir", "dir/ba", "", false, []string{"/dir/bar", "/dir/baz"}},
+		// listing after a directory skips its subtree
+		{"/dir", "", "/dir/baz", false, []string{"/dir/qux"}},
+		{"/dir", "", "/dir/baz/buzz", false, []string{"/dir/qux"}},
+		{"/", "", "/dir", false, []string{"/dir.bar", "/dir2", "/foo"}},
+		{"/", "", "", true, []string{"/dir", "/dir/bar", "/dir/baz", "/dir/baz/buzz", "/dir/qux", "/dir.bar", "/dir2", "/dir2/buzz", "/foo"}},
+		{"/", "/dir/", "/dir/bar", true, []string{"/dir/baz", "/dir/baz/buzz", "/dir/qux"}},
+		{"/dir", "", "/dir2", false, nil},
+		{"/dir", "/foo", "", false, nil},
+		{"/missing", "", "", false, nil},
+		{"/foo", "", "", true, nil},
+	} {
+		var paths []string
+		require.NoError(t, h.ListFrom(c.path, c.prefix, c.after, c.recursive, func(path string, node *NodeProto) error {
+			paths = append(paths, path)
+			return nil
+		}))
+		require.Equal(t, c.expected, paths, "%+v", c)
+
+		paths = nil
+		rs := []io.ReadCloser{ioutil.NopCloser(bytes.NewReader(serialized.Bytes()))}
+		require.NoError(t, ListFrom(rs, c.path, c.prefix, c.after, c.recursive, func(path string, node *NodeProto) error {
+			paths = append(paths, path)
+			return nil
+		}))
+		require.Equal(t, c.expected, paths, "%+v", c)
+	}
+
+	// f can stop the listing early
+	var paths []string
+	require.NoError(t, h.ListFrom("/", "", "", true, func(path string, node *NodeProto) error {
+		if len(paths) == 2 {
+			return errutil.ErrBreak
+		}
+		paths = append(paths, path)
+		return nil
+	}))
+	require.Equal(t, []string{"/dir", "/dir/bar"}, paths)
+}
+
 // Test that HashTree methods return the right error codes
 func TestErrorCode(t *testing.T) {
 	require.Equal(t, OK, Code(nil))
@@ -766,3 +838,127 @@ func TestMergeFiles(t *testing.T) {
 
 	require.Equal(t, expectedBuf, resultBuf)
 }
+
+func TestSpillableOrdered(t *testing.T) {
+	o := NewOrdered("/")
+	s := NewSpillableOrdered("/", "", 3)
+	defer func() {
+		require.NoError(t, s.Close())
+	}()
+	for _, tree := range []*Ordered{o, s} {
+		for i := 0; i < 5; i++ {
+			dir := fmt.Sprintf("/dir-%d", i)
+			tree.PutDir(dir)
+			tree.PutDir(dir + "/sub")
+			for j := 0; j < 5; j++ {
+				tree.PutFile(fmt.Sprintf("%s/sub/file-%d", dir, j), []byte(fmt.Sprintf("%d%d", i, j)), 1, &FileNodeProto{BlockRefs: blocks(``)})
+			}
+			tree.PutFile(dir+"/zfile", []byte(fmt.Sprint(i)), 1, &FileNodeProto{BlockRefs: blocks(``)})
+		}
+	}
+	// Only the directory stack and the nodes since the last spill are in memory
+	require.True(t, len(s.fs) <= 3+len(s.dirStack))
+	oBuf, sBuf := &bytes.Buffer{}, &bytes.Buffer{}
+	require.NoError(t, o.Serialize(oBuf))
+	require.NoError(t, s.Serialize(sBuf))
+	require.Equal(t, oBuf.Bytes(), sBuf.Bytes())
+
+	// Close removes the spilled nodes from disk
+	require.NotNil(t, s.spill)
+	spillFile := s.spillFile
+	_, err := os.Stat(spillFile)
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+	_, err = os.Stat(spillFile)
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestSpillableOrderedSmall(t *testing.T) {
+	// Trees that never exceed the spill size don't touch the disk
+	s := NewSpillableOrdered("/", "", 3)
+	s.PutFile("/foo", []byte("foo"), 1, &FileNodeProto{BlockRefs: blocks(``)})
+	s.PutFile("/bar", []byte("bar"), 1, &FileNodeProto{BlockRefs: blocks(``)})
+	require.NoError(t, s.Serialize(&bytes.Buffer{}))
+	require.Nil(t, s.spill)
+	require.NoError(t, s.Close())
+}
+
+func TestSpillableOrderedKeyOrder(t *testing.T) {
+	// "/a/b" sorts before "/a-c" once slashes are encoded, even though '-'
+	// sorts before '/'. With a spill size of 2, "/a/b", "/a/c" and "/a/d" are
+	// spilled while "/a" (still open) and "/a-c" stay in memory, so
+	// Serialize has to merge them by their encoded keys.
+	o := NewOrdered("/")
+	spilled := NewSpillableOrdered("/", "", 2)
+	defer func() {
+		require.NoError(t, spilled.Close())
+	}()
+	for _, tree := range []*Ordered{o, spilled} {
+		tree.PutDir("/a")
+		for _, name := range []string{"/a/b", "/a/c", "/a/d", "/a-c"} {
+			tree.PutFile(name, []byte(name), 1, &FileNodeProto{BlockRefs: blocks(``)})
+		}
+	}
+	require.NotNil(t, spilled.spill)
+	require.Equal(t, 3, len(spilled.fs))
+	oBuf, sBuf := &bytes.Buffer{}, &bytes.Buffer{}
+	require.NoError(t, o.Serialize(oBuf))
+	require.NoError(t, spilled.Serialize(sBuf))
+	require.Equal(t, oBuf.Bytes(), sBuf.Bytes())
+
+	var paths []string
+	r := NewReader(bytes.NewReader(sBuf.Bytes()), nil)
+	for {
+		n, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		paths = append(paths, s(n.k))
+	}
+	require.Equal(t, []string{"", "/a", "/a/b", "/a/c", "/a/d", "/a-c"}, paths)
+}
+
+func TestMergeWithProgress(t *testing.T) {
+	serialized := func(files int) []byte {
+		u := NewUnordered("")
+		for i := 0; i < files; i++ {
+			u.PutFile(fmt.Sprintf("/file-%d", i), []byte(fmt.Sprint(i)), 1, blocks(``)...)
+		}
+		buf := &bytes.Buffer{}
+		require.NoError(t, u.Ordered().Serialize(buf))
+		return buf.Bytes()
+	}
+	mergeProgress := func(ctx context.Context, tree []byte) ([]int64, error) {
+		var progress []int64
+		err := MergeWithProgress(ctx, NewWriter(&bytes.Buffer{}),
+			[]*Reader{NewReader(bytes.NewReader(tree), nil)}, func(nodes int64) error {
+				progress = append(progress, nodes)
+				return nil
+			})
+		return progress, err
+	}
+
+	// The root directory is written in addition to the files
+	progress, err := mergeProgress(context.Background(), serialized(MergeProgressInterval))
+	require.NoError(t, err)
+	require.Equal(t, []int64{MergeProgressInterval, MergeProgressInterval + 1}, progress)
+	// A total that's a multiple of the interval is only reported once
+	progress, err = mergeProgress(context.Background(), serialized(MergeProgressInterval-1))
+	require.NoError(t, err)
+	require.Equal(t, []int64{MergeProgressInterval}, progress)
+	progress, err = mergeProgress(context.Background(), serialized(2))
+	require.NoError(t, err)
+	require.Equal(t, []int64{3}, progress)
+
+	// A cancelled merge doesn't write anything, even if it's smaller than the
+	// progress interval
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	progress, err = mergeProgress(ctx, serialized(2))
+	require.Equal(t, context.Canceled, err)
+	require.Equal(t, 0, len(progress))
+	err = MergeWithProgress(ctx, NewWriter(&bytes.Buffer{}),
+		[]*Reader{NewReader(bytes.NewReader(serialized(MergeProgressInterval)), nil)}, nil)
+	require.Equal(t, context.Canceled, err)
+}
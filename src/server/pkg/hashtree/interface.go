@@ -88,6 +88,12 @@ type HashTree interface {
 	// function returns an error, the walk stops and returns the error.
 	Walk(path string, f func(path string, node *NodeProto) error) error
 
+	// ListFrom calls f, in order, with the children of the directory at 'path'
+	// (or every node under it, if 'recursive' is set) whose paths start with
+	// 'prefix' and sort after 'after'. Either may be empty. Unlike List and
+	// Glob, it seeks to the first node that it visits.
+	ListFrom(path, prefix, after string, recursive bool, f func(path string, node *NodeProto) error) error
+
 	// Diff returns the diff of 2 HashTrees at particular Paths. It takes a
 	// callback function f, which will be called with paths that are not
 	// identical to the same path in the other HashTree.
@@ -21,6 +21,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/aws/aws-sdk-go/service/storagegateway"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
 	"github.com/pachyderm/pachyderm/src/client/pkg/tracing"
 	"github.com/pachyderm/pachyderm/src/server/pkg/backoff"
 	log "github.com/sirupsen/logrus"
@@ -225,10 +226,53 @@ func newAmazonClient(region, bucket string, creds *AmazonCreds, cloudfrontDistri
 }
 
 func (c *amazonClient) Writer(ctx context.Context, name string) (io.WriteCloser, error) {
+	return c.WriterWithClass(ctx, name, pfs.StorageClass_STANDARD)
+}
+
+// WriterWithClass implements the corresponding method in the TieredClient
+// interface
+func (c *amazonClient) WriterWithClass(ctx context.Context, name string, class pfs.StorageClass) (io.WriteCloser, error) {
+	if c.advancedConfig.Reverse {
+		name = reverse(name)
+	}
+	var storageClass *string
+	switch class {
+	case pfs.StorageClass_INFREQUENT_ACCESS:
+		storageClass = aws.String(s3.StorageClassStandardIa)
+	case pfs.StorageClass_ARCHIVE:
+		storageClass = aws.String(s3.StorageClassGlacier)
+	}
+	return newBackoffWriteCloser(ctx, c, newWriter(ctx, c, name, storageClass)), nil
+}
+
+// IsArchived implements the corresponding method in the TieredClient
+// interface
+func (c *amazonClient) IsArchived(err error) bool {
+	// S3 returns InvalidObjectState for reads of objects in Glacier
+	awsErr, ok := err.(awserr.Error)
+	return ok && awsErr.Code() == "InvalidObjectState"
+}
+
+// Restore implements the corresponding method in the TieredClient interface.
+// Restored objects can be read for a day, after which they're archived again.
+func (c *amazonClient) Restore(_ context.Context, name string) error {
 	if c.advancedConfig.Reverse {
 		name = reverse(name)
 	}
-	return newBackoffWriteCloser(ctx, c, newWriter(ctx, c, name)), nil
+	_, err := c.s3.RestoreObject(&s3.RestoreObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(name),
+		RestoreRequest: &s3.RestoreRequest{
+			Days: aws.Int64(1),
+			GlacierJobParameters: &s3.GlacierJobParameters{
+				Tier: aws.String(s3.TierStandard),
+			},
+		},
+	})
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "RestoreAlreadyInProgress" {
+		return nil
+	}
+	return err
 }
 
 func (c *amazonClient) Walk(_ context.Context, name string, fn func(name string) error) error {
@@ -268,6 +312,12 @@ func (c *amazonClient) Walk(_ context.Context, name string, fn func(name string)
 }
 
 func (c *amazonClient) Reader(ctx context.Context, name string, offset uint64, size uint64) (io.ReadCloser, error) {
+	return readRestoring(ctx, c, name, func() (io.ReadCloser, error) {
+		return c.reader(ctx, name, offset, size)
+	})
+}
+
+func (c *amazonClient) reader(ctx context.Context, name string, offset uint64, size uint64) (io.ReadCloser, error) {
 	if c.advancedConfig.Reverse {
 		name = reverse(name)
 	}
@@ -407,7 +457,7 @@ type amazonWriter struct {
 	pipe    *io.PipeWriter
 }
 
-func newWriter(ctx context.Context, client *amazonClient, name string) *amazonWriter {
+func newWriter(ctx context.Context, client *amazonClient, name string, storageClass *string) *amazonWriter {
 	reader, writer := io.Pipe()
 	w := &amazonWriter{
 		ctx:     ctx,
@@ -421,6 +471,7 @@ func newWriter(ctx context.Context, client *amazonClient, name string) *amazonWr
 			Bucket:          aws.String(client.bucket),
 			Key:             aws.String(name),
 			ContentEncoding: aws.String("application/octet-stream"),
+			StorageClass:    storageClass,
 		})
 		if err != nil {
 			reader.CloseWithError(err)
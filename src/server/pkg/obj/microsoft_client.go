@@ -5,11 +5,15 @@ import (
 	"encoding/base64"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/storage"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/pachyderm/pachyderm/src/client/limit"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
 	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
 	"github.com/pachyderm/pachyderm/src/client/pkg/tracing"
 )
@@ -39,15 +43,75 @@ func newMicrosoftClient(container string, accountName string, accountKey string)
 }
 
 func (c *microsoftClient) Writer(ctx context.Context, name string) (io.WriteCloser, error) {
-	return newMicrosoftWriter(ctx, c, name), nil
+	return newMicrosoftWriter(ctx, c, name, ""), nil
+}
+
+// WriterWithClass implements the corresponding method in the TieredClient
+// interface
+func (c *microsoftClient) WriterWithClass(ctx context.Context, name string, class pfs.StorageClass) (io.WriteCloser, error) {
+	var tier string
+	switch class {
+	case pfs.StorageClass_INFREQUENT_ACCESS:
+		tier = "Cool"
+	case pfs.StorageClass_ARCHIVE:
+		tier = "Archive"
+	}
+	return newMicrosoftWriter(ctx, c, name, tier), nil
 }
 
 func (c *microsoftClient) Reader(ctx context.Context, name string, offset uint64, size uint64) (io.ReadCloser, error) {
-	blobRange := blobRange(offset, size)
-	if blobRange == nil {
-		return c.container.GetBlobReference(name).Get(nil)
+	return readRestoring(ctx, c, name, func() (io.ReadCloser, error) {
+		blobRange := blobRange(offset, size)
+		if blobRange == nil {
+			return c.container.GetBlobReference(name).Get(nil)
+		}
+		return c.container.GetBlobReference(name).GetRange(&storage.GetBlobRangeOptions{Range: blobRange})
+	})
+}
+
+// IsArchived implements the corresponding method in the TieredClient
+// interface
+func (c *microsoftClient) IsArchived(err error) bool {
+	microsoftErr, ok := err.(storage.AzureStorageServiceError)
+	return ok && (microsoftErr.Code == "BlobArchived" || microsoftErr.Code == "BlobBeingRehydrated")
+}
+
+// Restore implements the corresponding method in the TieredClient
+// interface, by rehydrating the blob to the Hot tier
+func (c *microsoftClient) Restore(_ context.Context, name string) error {
+	return setBlobTier(c.container.GetBlobReference(name), "Hot")
+}
+
+// setBlobTier sets the access tier of 'blob'. The storage SDK doesn't support
+// Set Blob Tier, so it's sent with a SAS URI for the blob.
+func setBlobTier(blob *storage.Blob, tier string) error {
+	uri, err := blob.GetSASURI(storage.BlobSASOptions{
+		BlobServiceSASPermissions: storage.BlobServiceSASPermissions{Write: true},
+		SASOptions: storage.SASOptions{
+			Expiry:   time.Now().Add(time.Hour),
+			UseHTTPS: true,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("PUT", uri+"&comp=tier", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("x-ms-access-tier", tier)
+	req.Header.Set("x-ms-version", storage.DefaultAPIVersion)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
 	}
-	return c.container.GetBlobReference(name).GetRange(&storage.GetBlobRangeOptions{Range: blobRange})
+	defer resp.Body.Close()
+	// Set Blob Tier returns 202 while an archived blob is rehydrated
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("could not set the tier of blob %s to %s: %s: %s", blob.Name, tier, resp.Status, body)
+	}
+	return nil
 }
 
 func blobRange(offset, size uint64) *storage.BlobRange {
@@ -122,15 +186,18 @@ type microsoftWriter struct {
 	eg        *errgroup.Group
 	numBlocks int
 	err       error
+	// tier is the access tier of the blob, or "" for the account's default
+	tier string
 }
 
-func newMicrosoftWriter(ctx context.Context, client *microsoftClient, name string) *microsoftWriter {
+func newMicrosoftWriter(ctx context.Context, client *microsoftClient, name string, tier string) *microsoftWriter {
 	eg, cancelCtx := errgroup.WithContext(ctx)
 	w := &microsoftWriter{
 		ctx:     cancelCtx,
 		blob:    client.container.GetBlobReference(name),
 		limiter: limit.New(concurrency),
 		eg:      eg,
+		tier:    tier,
 	}
 	w.w = grpcutil.NewChunkWriteCloser(bufPool, w.writeBlock)
 	return w
@@ -187,5 +254,11 @@ func (w *microsoftWriter) Close() (retErr error) {
 	for i := range blocks {
 		blocks[i] = storage.Block{ID: blockID(i), Status: storage.BlockStatusUncommitted}
 	}
-	return w.blob.PutBlockList(blocks, nil)
+	if err := w.blob.PutBlockList(blocks, nil); err != nil {
+		return err
+	}
+	if w.tier != "" {
+		return setBlobTier(w.blob, w.tier)
+	}
+	return nil
 }
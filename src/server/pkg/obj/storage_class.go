@@ -0,0 +1,76 @@
+package obj
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/server/pkg/backoff"
+	log "github.com/sirupsen/logrus"
+)
+
+// restoreTimeout is how long a read of an archived object waits for it to be
+// restored. Restores from S3 Glacier and the Azure Archive tier usually take
+// hours.
+const restoreTimeout = 24 * time.Hour
+
+// TieredClient is implemented by the clients of object storage backends that
+// have storage tiers
+type TieredClient interface {
+	Client
+	// WriterWithClass is like Writer, but writes the object in the storage
+	// tier that 'class' maps to.
+	WriterWithClass(ctx context.Context, name string, class pfs.StorageClass) (io.WriteCloser, error)
+	// IsArchived returns true if err, returned by Reader, means that the
+	// object has to be restored before it can be read
+	IsArchived(err error) bool
+	// Restore starts restoring an archived object. It shouldn't error if the
+	// object is already being restored.
+	Restore(ctx context.Context, name string) error
+}
+
+// WriterWithClass returns a writer which writes to an object in 'class', if
+// 'c' supports storage classes. Otherwise 'class' is ignored.
+func WriterWithClass(ctx context.Context, c Client, name string, class pfs.StorageClass) (io.WriteCloser, error) {
+	if tc, ok := c.(TieredClient); ok && class != pfs.StorageClass_STANDARD {
+		return tc.WriterWithClass(ctx, name, class)
+	}
+	return c.Writer(ctx, name)
+}
+
+// readRestoring calls 'read' to read the object 'name' from 'c'. If the
+// object is archived, it restores it and calls 'read' again once it's been
+// restored, so that the reads of archived objects only take longer.
+func readRestoring(ctx context.Context, c TieredClient, name string, read func() (io.ReadCloser, error)) (io.ReadCloser, error) {
+	r, err := read()
+	if err == nil || !c.IsArchived(err) {
+		return r, err
+	}
+	log.Infof("restoring archived object %s before reading it", name)
+	if err := c.Restore(ctx, name); err != nil {
+		return nil, fmt.Errorf("could not restore archived object %s: %v", name, err)
+	}
+	b := backoff.NewExponentialBackOff()
+	b.InitialInterval = 10 * time.Second
+	b.MaxInterval = 5 * time.Minute
+	b.MaxElapsedTime = restoreTimeout
+	if err := backoff.RetryNotify(func() error {
+		var err error
+		r, err = read()
+		return err
+	}, b, func(err error, d time.Duration) error {
+		if !c.IsArchived(err) {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		log.Infof("object %s is still being restored; retrying in %v", name, d)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
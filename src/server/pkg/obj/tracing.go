@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/pachyderm/pachyderm/src/client/pfs"
 	"github.com/pachyderm/pachyderm/src/client/pkg/tracing"
 )
 
@@ -44,6 +45,36 @@ func (o *tracingObjClient) Writer(ctx context.Context, name string) (_ io.WriteC
 	return o.Client.Writer(ctx, name)
 }
 
+// WriterWithClass implements the corresponding method in the TieredClient
+// interface, if the wrapped client does
+func (o *tracingObjClient) WriterWithClass(ctx context.Context, name string, class pfs.StorageClass) (_ io.WriteCloser, retErr error) {
+	span, ctx := tracing.AddSpanToAnyExisting(ctx, "/"+o.provider+".Writer/Connect", "name", name, "class", class.String())
+	defer func() {
+		tracing.FinishAnySpan(span, "err", retErr)
+	}()
+	return WriterWithClass(ctx, o.Client, name, class)
+}
+
+// IsArchived implements the corresponding method in the TieredClient
+// interface
+func (o *tracingObjClient) IsArchived(err error) bool {
+	tc, ok := o.Client.(TieredClient)
+	return ok && tc.IsArchived(err)
+}
+
+// Restore implements the corresponding method in the TieredClient interface
+func (o *tracingObjClient) Restore(ctx context.Context, name string) (retErr error) {
+	span, ctx := tracing.AddSpanToAnyExisting(ctx, "/"+o.provider+"/Restore", "name", name)
+	defer func() {
+		tracing.FinishAnySpan(span, "err", retErr)
+	}()
+	tc, ok := o.Client.(TieredClient)
+	if !ok {
+		return fmt.Errorf("%s object storage doesn't support restoring objects", o.provider)
+	}
+	return tc.Restore(ctx, name)
+}
+
 // Reader implements the corresponding method in the Client interface
 func (o *tracingObjClient) Reader(ctx context.Context, name string, offset uint64, size uint64) (_ io.ReadCloser, retErr error) {
 	span, ctx := tracing.AddSpanToAnyExisting(ctx, "/"+o.provider+".Reader/Connect",
@@ -0,0 +1,101 @@
+package ppsutil
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"path"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+
+	etcd "github.com/coreos/etcd/clientv3"
+	"golang.org/x/crypto/ed25519"
+	"golang.org/x/net/context"
+)
+
+const (
+	// AttestationFile is the path of a job's attestation in its stats commit
+	AttestationFile = "/attestation"
+	// AttestationSignatureFile is the path of the (base64-encoded) signature
+	// of a job's attestation in its stats commit
+	AttestationSignatureFile = "/attestation.sig"
+
+	// attestationKeyPath is the etcd key, under the PPS etcd prefix, that holds
+	// the seed of the cluster's attestation key
+	attestationKeyPath = "attestation-key"
+)
+
+// GetAttestationKey returns the key that the cluster signs job attestations
+// with, creating it if it doesn't exist yet.
+func GetAttestationKey(ctx context.Context, etcdClient *etcd.Client, etcdPrefix string) (ed25519.PrivateKey, error) {
+	key := path.Join(etcdPrefix, attestationKeyPath)
+	resp, err := etcdClient.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count > 0 {
+		return keyFromSeed(resp.Kvs[0].Value)
+	}
+	_, newKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	// Only write the new key if there's still no key, so that everyone
+	// racing to create it ends up with the same one
+	txnResp, err := etcdClient.Txn(ctx).
+		If(etcd.Compare(etcd.CreateRevision(key), "=", 0)).
+		Then(etcd.OpPut(key, string(newKey.Seed()))).
+		Else(etcd.OpGet(key)).
+		Commit()
+	if err != nil {
+		return nil, err
+	}
+	if txnResp.Succeeded {
+		return newKey, nil
+	}
+	kvs := txnResp.Responses[0].GetResponseRange().Kvs
+	if len(kvs) == 0 {
+		return nil, fmt.Errorf("attestation key %s is missing; this is likely a bug", key)
+	}
+	return keyFromSeed(kvs[0].Value)
+}
+
+func keyFromSeed(seed []byte) (ed25519.PrivateKey, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("invalid attestation key: expected %d bytes, but got %d", ed25519.SeedSize, len(seed))
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+// SignAttestation serializes 'attestation' and signs it with 'key'. It
+// returns the contents of AttestationFile and AttestationSignatureFile.
+func SignAttestation(key ed25519.PrivateKey, attestation *pps.JobAttestation) ([]byte, []byte, error) {
+	m := &jsonpb.Marshaler{Indent: "  "}
+	var buf bytes.Buffer
+	if err := m.Marshal(&buf, attestation); err != nil {
+		return nil, nil, err
+	}
+	signature := ed25519.Sign(key, buf.Bytes())
+	return buf.Bytes(), []byte(base64.StdEncoding.EncodeToString(signature)), nil
+}
+
+// VerifyAttestation checks that 'signature', the contents of
+// AttestationSignatureFile, is a signature of 'attestation', the contents of
+// AttestationFile, by 'publicKey', and returns the parsed attestation and the
+// decoded signature.
+func VerifyAttestation(publicKey ed25519.PublicKey, attestation, signature []byte) (*pps.JobAttestation, []byte, error) {
+	sig, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(signature)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not decode attestation signature: %v", err)
+	}
+	if !ed25519.Verify(publicKey, attestation, sig) {
+		return nil, nil, fmt.Errorf("attestation signature is invalid")
+	}
+	result := &pps.JobAttestation{}
+	if err := jsonpb.Unmarshal(bytes.NewReader(attestation), result); err != nil {
+		return nil, nil, fmt.Errorf("could not parse attestation: %v", err)
+	}
+	return result, sig, nil
+}
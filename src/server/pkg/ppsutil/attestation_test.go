@@ -0,0 +1,44 @@
+package ppsutil
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"golang.org/x/crypto/ed25519"
+)
+
+func TestSignAttestation(t *testing.T) {
+	publicKey, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	attestation := &pps.JobAttestation{
+		Job:          client.NewJob("job"),
+		Pipeline:     client.NewPipeline("pipeline"),
+		Inputs:       []*pfs.Commit{client.NewCommit("input", "abc")},
+		Image:        "ubuntu:18.04",
+		ImageDigest:  "sha256:123",
+		Cmd:          []string{"sh"},
+		Env:          map[string]string{"MODE": "fast"},
+		OutputCommit: client.NewCommit("pipeline", "def"),
+	}
+	doc, sig, err := SignAttestation(key, attestation)
+	require.NoError(t, err)
+
+	result, _, err := VerifyAttestation(publicKey, doc, sig)
+	require.NoError(t, err)
+	require.Equal(t, attestation.ImageDigest, result.ImageDigest)
+	require.Equal(t, attestation.Env, result.Env)
+	require.Equal(t, "abc", result.Inputs[0].ID)
+
+	// Changed attestations and other keys' signatures are rejected
+	_, _, err = VerifyAttestation(publicKey, bytes.Replace(doc, []byte("fast"), []byte("slow"), 1), sig)
+	require.YesError(t, err)
+	otherPublicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	_, _, err = VerifyAttestation(otherPublicKey, doc, sig)
+	require.YesError(t, err)
+}
@@ -0,0 +1,79 @@
+package ppsutil
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+)
+
+// CmdTemplate is a compiled transform cmd (or stdin), whose args are Go
+// templates that are executed for each datum (see pps.Transform.TemplateCmd)
+type CmdTemplate struct {
+	templates []*template.Template
+}
+
+// CmdTemplateData is the datum that a CmdTemplate is executed for
+type CmdTemplateData struct {
+	JobID   string
+	DatumID string
+	// Inputs are the paths (under /pfs) of the datum's files, and Commits
+	// the IDs of their commits, by input name
+	Inputs  map[string]string
+	Commits map[string]string
+}
+
+// cmdTemplateFuncs returns the functions that templates can use, which look
+// up the inputs of 'data'. Templates are compiled with a nil 'data'.
+func cmdTemplateFuncs(data *CmdTemplateData) template.FuncMap {
+	lookup := func(values map[string]string) func(string) (string, error) {
+		return func(name string) (string, error) {
+			value, ok := values[name]
+			if !ok {
+				return "", fmt.Errorf("the datum has no input named %q", name)
+			}
+			return value, nil
+		}
+	}
+	if data == nil {
+		data = &CmdTemplateData{}
+	}
+	return template.FuncMap{
+		"input":  lookup(data.Inputs),
+		"commit": lookup(data.Commits),
+	}
+}
+
+// NewCmdTemplate compiles the args of a transform's cmd or stdin
+func NewCmdTemplate(args []string) (*CmdTemplate, error) {
+	t := &CmdTemplate{}
+	for i, arg := range args {
+		tmpl, err := template.New(fmt.Sprint(i)).Option("missingkey=error").Funcs(cmdTemplateFuncs(nil)).Parse(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template %q: %v", arg, err)
+		}
+		t.templates = append(t.templates, tmpl)
+	}
+	return t, nil
+}
+
+// Execute returns the args of the template, for the datum 'data'
+func (t *CmdTemplate) Execute(data *CmdTemplateData) ([]string, error) {
+	if t == nil || len(t.templates) == 0 {
+		return nil, nil
+	}
+	result := make([]string, len(t.templates))
+	for i, tmpl := range t.templates {
+		// The functions are bound to 'data' in a clone, so that templates can
+		// be executed concurrently
+		tmpl, err := tmpl.Clone()
+		if err != nil {
+			return nil, err
+		}
+		buf := &bytes.Buffer{}
+		if err := tmpl.Funcs(cmdTemplateFuncs(data)).Execute(buf, data); err != nil {
+			return nil, fmt.Errorf("error executing template %q: %v", tmpl.Root.String(), err)
+		}
+		result[i] = buf.String()
+	}
+	return result, nil
+}
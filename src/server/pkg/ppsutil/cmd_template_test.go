@@ -0,0 +1,42 @@
+package ppsutil
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestCmdTemplate(t *testing.T) {
+	tmpl, err := NewCmdTemplate([]string{"convert", `{{ input "images" }}`, "/pfs/out/{{ .DatumID }}-{{ commit \"images\" }}.png", "{{ .JobID }}"})
+	require.NoError(t, err)
+	args, err := tmpl.Execute(&CmdTemplateData{
+		JobID:   "job",
+		DatumID: "datum",
+		Inputs:  map[string]string{"images": "/pfs/images/cat.jpg"},
+		Commits: map[string]string{"images": "abc"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"convert", "/pfs/images/cat.jpg", "/pfs/out/datum-abc.png", "job"}, args)
+
+	// Inputs that the datum doesn't have are errors
+	_, err = tmpl.Execute(&CmdTemplateData{})
+	require.YesError(t, err)
+	require.Matches(t, "no input named \"images\"", err.Error())
+
+	// Args without templates are left alone, and empty args stay empty
+	tmpl, err = NewCmdTemplate([]string{"echo", "$HOME"})
+	require.NoError(t, err)
+	args, err = tmpl.Execute(&CmdTemplateData{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"echo", "$HOME"}, args)
+	tmpl, err = NewCmdTemplate(nil)
+	require.NoError(t, err)
+	args, err = tmpl.Execute(&CmdTemplateData{})
+	require.NoError(t, err)
+	require.True(t, args == nil)
+
+	for _, arg := range []string{"{{ .JobID", `{{ nope "images" }}`} {
+		_, err := NewCmdTemplate([]string{arg})
+		require.YesError(t, err, arg)
+	}
+}
@@ -0,0 +1,364 @@
+package ppsutil
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	glob "github.com/pachyderm/ohmyglob"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// CrossFilter is a compiled pps.Input.CrossFilter, which decides which of the
+// combinations of a cross input's files are datums.
+type CrossFilter struct {
+	expr *filterExpr
+}
+
+// filterKind is the type of a filter (sub)expression's value
+type filterKind int
+
+const (
+	filterString filterKind = iota
+	filterBool
+)
+
+func (k filterKind) String() string {
+	if k == filterString {
+		return "string"
+	}
+	return "bool"
+}
+
+// filterExpr is a type-checked filter (sub)expression. 'eval' returns a
+// value of type 'kind' (a string or bool) for the combination of files
+// in 'paths' (keyed by input name).
+type filterExpr struct {
+	kind filterKind
+	eval func(paths map[string]string) interface{}
+}
+
+// filterInputs are the inputs that a cross filter can refer to
+type filterInputs struct {
+	names map[string]bool
+	// globs holds the globs of the PFS inputs, by name. An input's glob is
+	// nil if more than one input (in a union) has its name.
+	globs map[string]*glob.Glob
+}
+
+// CompileCrossFilter compiles the cross filter of 'input', returning nil if
+// it doesn't have one
+func CompileCrossFilter(input *pps.Input) (*CrossFilter, error) {
+	if input.CrossFilter == "" {
+		return nil, nil
+	}
+	if input.Cross == nil {
+		return nil, fmt.Errorf("cross_filter can only be set on a cross input")
+	}
+	inputs := &filterInputs{
+		names: make(map[string]bool),
+		globs: make(map[string]*glob.Glob),
+	}
+	for _, input := range input.Cross {
+		var err error
+		pps.VisitInput(input, func(input *pps.Input) {
+			var name string
+			switch {
+			case input.Pfs != nil:
+				name = input.Pfs.Name
+				g, compileErr := glob.Compile(input.Pfs.Glob, '/')
+				if compileErr != nil && err == nil {
+					err = compileErr
+				}
+				if _, ok := inputs.globs[name]; ok {
+					g = nil
+				}
+				inputs.globs[name] = g
+			case input.Cron != nil:
+				name = input.Cron.Name
+			case input.Git != nil:
+				name = input.Git.Name
+			case input.SQL != nil:
+				name = input.SQL.Name
+			default:
+				return
+			}
+			inputs.names[name] = true
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	node, err := parser.ParseExpr(input.CrossFilter)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing cross_filter %q: %v", input.CrossFilter, err)
+	}
+	expr, err := inputs.compile(node)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cross_filter %q: %v", input.CrossFilter, err)
+	}
+	if expr.kind != filterBool {
+		return nil, fmt.Errorf("invalid cross_filter %q: it's a %v, not a bool", input.CrossFilter, expr.kind)
+	}
+	return &CrossFilter{expr: expr}, nil
+}
+
+// Match returns true if the combination of files in 'paths' (keyed by the
+// name of the input that each is from) is a datum
+func (f *CrossFilter) Match(paths map[string]string) bool {
+	return f.expr.eval(paths).(bool)
+}
+
+func constant(kind filterKind, value interface{}) *filterExpr {
+	return &filterExpr{kind: kind, eval: func(map[string]string) interface{} { return value }}
+}
+
+func (in *filterInputs) compile(node ast.Expr) (*filterExpr, error) {
+	switch node := node.(type) {
+	case *ast.ParenExpr:
+		return in.compile(node.X)
+	case *ast.BasicLit:
+		if node.Kind != token.STRING {
+			return nil, fmt.Errorf("unsupported literal %s", node.Value)
+		}
+		s, err := strconv.Unquote(node.Value)
+		if err != nil {
+			return nil, err
+		}
+		return constant(filterString, s), nil
+	case *ast.Ident:
+		switch node.Name {
+		case "true":
+			return constant(filterBool, true), nil
+		case "false":
+			return constant(filterBool, false), nil
+		}
+		return in.path(node.Name)
+	case *ast.UnaryExpr:
+		if node.Op != token.NOT {
+			return nil, fmt.Errorf("unsupported operator %v", node.Op)
+		}
+		x, err := in.compileKind(node.X, filterBool)
+		if err != nil {
+			return nil, err
+		}
+		return &filterExpr{kind: filterBool, eval: func(paths map[string]string) interface{} {
+			return !x.eval(paths).(bool)
+		}}, nil
+	case *ast.BinaryExpr:
+		return in.compileBinary(node)
+	case *ast.CallExpr:
+		return in.compileCall(node)
+	}
+	return nil, fmt.Errorf("unsupported expression %T", node)
+}
+
+// compileKind compiles 'node', which must be of type 'kind'
+func (in *filterInputs) compileKind(node ast.Expr, kind filterKind) (*filterExpr, error) {
+	expr, err := in.compile(node)
+	if err != nil {
+		return nil, err
+	}
+	if expr.kind != kind {
+		return nil, fmt.Errorf("expected a %v, got a %v", kind, expr.kind)
+	}
+	return expr, nil
+}
+
+// path returns an expression for the path of the file from the input 'name'.
+// It's "" for the inputs that aren't in a datum (e.g. in a union).
+func (in *filterInputs) path(name string) (*filterExpr, error) {
+	if !in.names[name] {
+		return nil, fmt.Errorf("no input named %q in the cross", name)
+	}
+	return &filterExpr{kind: filterString, eval: func(paths map[string]string) interface{} {
+		return paths[name]
+	}}, nil
+}
+
+func (in *filterInputs) compileBinary(node *ast.BinaryExpr) (*filterExpr, error) {
+	x, err := in.compile(node.X)
+	if err != nil {
+		return nil, err
+	}
+	y, err := in.compile(node.Y)
+	if err != nil {
+		return nil, err
+	}
+	if x.kind != y.kind {
+		return nil, fmt.Errorf("mismatched types %v and %v for %v", x.kind, y.kind, node.Op)
+	}
+	kind := x.kind
+	switch node.Op {
+	case token.LAND, token.LOR:
+		if kind != filterBool {
+			return nil, fmt.Errorf("%v is only defined on bools", node.Op)
+		}
+		and := node.Op == token.LAND
+		return &filterExpr{kind: filterBool, eval: func(paths map[string]string) interface{} {
+			if x.eval(paths).(bool) == and {
+				return y.eval(paths)
+			}
+			return !and
+		}}, nil
+	case token.EQL, token.NEQ:
+		eq := node.Op == token.EQL
+		return &filterExpr{kind: filterBool, eval: func(paths map[string]string) interface{} {
+			return (x.eval(paths) == y.eval(paths)) == eq
+		}}, nil
+	case token.LSS, token.LEQ, token.GTR, token.GEQ:
+		if kind == filterBool {
+			return nil, fmt.Errorf("%v is not defined on bools", node.Op)
+		}
+		op := node.Op
+		return &filterExpr{kind: filterBool, eval: func(paths map[string]string) interface{} {
+			cmp := strings.Compare(x.eval(paths).(string), y.eval(paths).(string))
+			switch op {
+			case token.LSS:
+				return cmp < 0
+			case token.LEQ:
+				return cmp <= 0
+			case token.GTR:
+				return cmp > 0
+			default:
+				return cmp >= 0
+			}
+		}}, nil
+	}
+	return nil, fmt.Errorf("unsupported operator %v", node.Op)
+}
+
+// inputName returns the input named by 'node', which is either an identifier
+// or a string literal
+func inputName(node ast.Expr) (string, error) {
+	switch node := node.(type) {
+	case *ast.Ident:
+		return node.Name, nil
+	case *ast.BasicLit:
+		if node.Kind == token.STRING {
+			return strconv.Unquote(node.Value)
+		}
+	}
+	return "", fmt.Errorf("expected an input name, got %T", node)
+}
+
+var stringFuncs = map[string]func(string) string{
+	"base": path.Base,
+	"dir":  path.Dir,
+	"ext":  path.Ext,
+}
+
+var predicates = map[string]func(string, string) bool{
+	"hasPrefix": strings.HasPrefix,
+	"hasSuffix": strings.HasSuffix,
+	"contains":  strings.Contains,
+}
+
+func (in *filterInputs) compileCall(node *ast.CallExpr) (*filterExpr, error) {
+	fun, ok := node.Fun.(*ast.Ident)
+	if !ok {
+		return nil, fmt.Errorf("unsupported function %T", node.Fun)
+	}
+	args := node.Args
+	arity := func(n int) error {
+		if len(args) != n {
+			return fmt.Errorf("%s takes %d arguments, got %d", fun.Name, n, len(args))
+		}
+		return nil
+	}
+	switch fun.Name {
+	case "path":
+		if err := arity(1); err != nil {
+			return nil, err
+		}
+		name, err := inputName(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return in.path(name)
+	case "group":
+		if err := arity(2); err != nil {
+			return nil, err
+		}
+		name, err := inputName(args[0])
+		if err != nil {
+			return nil, err
+		}
+		g, ok := in.globs[name]
+		switch {
+		case !ok:
+			return nil, fmt.Errorf("no pfs input named %q in the cross", name)
+		case g == nil:
+			return nil, fmt.Errorf("group is ambiguous, as more than one input is named %q", name)
+		}
+		lit, ok := args[1].(*ast.BasicLit)
+		if !ok || lit.Kind != token.INT {
+			return nil, fmt.Errorf("group's second argument must be an int literal")
+		}
+		replacement := "$" + lit.Value
+		return &filterExpr{kind: filterString, eval: func(paths map[string]string) interface{} {
+			p, ok := paths[name]
+			if !ok {
+				return ""
+			}
+			return g.Replace(p, replacement)
+		}}, nil
+	case "match":
+		if err := arity(2); err != nil {
+			return nil, err
+		}
+		s, err := in.compileKind(args[0], filterString)
+		if err != nil {
+			return nil, err
+		}
+		lit, ok := args[1].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return nil, fmt.Errorf("match's second argument must be a string literal")
+		}
+		pattern, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return nil, err
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		return &filterExpr{kind: filterBool, eval: func(paths map[string]string) interface{} {
+			return re.MatchString(s.eval(paths).(string))
+		}}, nil
+	}
+	if f, ok := stringFuncs[fun.Name]; ok {
+		if err := arity(1); err != nil {
+			return nil, err
+		}
+		s, err := in.compileKind(args[0], filterString)
+		if err != nil {
+			return nil, err
+		}
+		return &filterExpr{kind: filterString, eval: func(paths map[string]string) interface{} {
+			return f(s.eval(paths).(string))
+		}}, nil
+	}
+	if f, ok := predicates[fun.Name]; ok {
+		if err := arity(2); err != nil {
+			return nil, err
+		}
+		s, err := in.compileKind(args[0], filterString)
+		if err != nil {
+			return nil, err
+		}
+		t, err := in.compileKind(args[1], filterString)
+		if err != nil {
+			return nil, err
+		}
+		return &filterExpr{kind: filterBool, eval: func(paths map[string]string) interface{} {
+			return f(s.eval(paths).(string), t.eval(paths).(string))
+		}}, nil
+	}
+	return nil, fmt.Errorf("unknown function %s", fun.Name)
+}
@@ -0,0 +1,63 @@
+package ppsutil
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestCrossFilter(t *testing.T) {
+	images := client.NewPFSInputOpts("images", "images", "master", "/(*).png", "", false)
+	labels := client.NewPFSInputOpts("labels", "labels", "master", "/(*)/*.json", "", false)
+	cross := func(filter string) *pps.Input {
+		input := client.NewCrossInput(images, labels)
+		input.CrossFilter = filter
+		return input
+	}
+	for _, test := range []struct {
+		filter string
+		paths  map[string]string
+		match  bool
+	}{
+		{`group(images, 1) == group(labels, 1)`, map[string]string{"images": "/cat.png", "labels": "/cat/a.json"}, true},
+		{`group(images, 1) == group(labels, 1)`, map[string]string{"images": "/cat.png", "labels": "/dog/a.json"}, false},
+		{`group("images", 1) != group("labels", 1)`, map[string]string{"images": "/cat.png", "labels": "/dog/a.json"}, true},
+		{`hasPrefix(labels, "/cat/") && !hasSuffix(images, ".tmp")`, map[string]string{"images": "/cat.png", "labels": "/cat/a.json"}, true},
+		{`base(labels) == "a.json" || contains(images, "dog")`, map[string]string{"images": "/cat.png", "labels": "/cat/b.json"}, false},
+		{`ext(path("images")) == ".png" && dir(labels) == "/cat"`, map[string]string{"images": "/cat.png", "labels": "/cat/b.json"}, true},
+		{`match(labels, "^/[a-c]+/") && images < labels`, map[string]string{"images": "/cat.png", "labels": "/cab/b.json"}, false},
+		{`(true)`, map[string]string{}, true},
+	} {
+		filter, err := CompileCrossFilter(cross(test.filter))
+		require.NoError(t, err, test.filter)
+		require.Equal(t, test.match, filter.Match(test.paths), test.filter)
+	}
+
+	filter, err := CompileCrossFilter(cross(""))
+	require.NoError(t, err)
+	require.True(t, filter == nil)
+	for _, invalid := range []string{
+		`images ==`,
+		`images`,
+		`videos == labels`,
+		`group(images, "1") == ""`,
+		`group(images) == ""`,
+		`images == true`,
+		`-1 == 1`,
+		`match(images, "(") `,
+		`unknown(images)`,
+		`1 == 1`,
+	} {
+		_, err := CompileCrossFilter(cross(invalid))
+		require.YesError(t, err, invalid)
+	}
+	union := client.NewUnionInput(images, images)
+	filtered := client.NewCrossInput(union, labels)
+	filtered.CrossFilter = `group(images, 1) == ""`
+	_, err = CompileCrossFilter(filtered)
+	require.YesError(t, err)
+	_, err = CompileCrossFilter(&pps.Input{Pfs: images.Pfs, CrossFilter: "true"})
+	require.YesError(t, err)
+}
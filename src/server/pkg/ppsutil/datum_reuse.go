@@ -0,0 +1,45 @@
+package ppsutil
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// DatumReuseSalt returns the salt of a pipeline that reuses datums (see
+// pps.PipelineInfo.ReuseDatums). It's a hash of the parts of the pipeline's
+// spec that determine the output of its datums (its name, transform and
+// input), so pipelines with the same spec have the same salt, and so the
+// same datum hashes. Other settings, e.g. its parallelism or resource
+// requests, don't change the salt.
+func DatumReuseSalt(pipelineInfo *pps.PipelineInfo) (string, error) {
+	msgs := []proto.Message{pipelineInfo.Transform}
+	if pipelineInfo.Input != nil {
+		input := proto.Clone(pipelineInfo.Input).(*pps.Input)
+		pps.VisitInput(input, func(input *pps.Input) {
+			// A cron input's start time defaults to the pipeline's creation
+			// time, and its ticks are files that are hashed with the datums
+			// anyway
+			if input.Cron != nil {
+				input.Cron.Start = nil
+			}
+		})
+		msgs = append(msgs, input)
+	}
+	// jsonpb sorts map keys (e.g. in the transform's env), so the same spec
+	// always hashes the same way
+	m := &jsonpb.Marshaler{}
+	hash := sha256.New()
+	hash.Write([]byte(pipelineInfo.Pipeline.Name))
+	for _, msg := range msgs {
+		s, err := m.MarshalToString(msg)
+		if err != nil {
+			return "", err
+		}
+		hash.Write([]byte(s))
+	}
+	return hex.EncodeToString(hash.Sum(nil)[:16]), nil
+}
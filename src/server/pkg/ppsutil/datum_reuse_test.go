@@ -0,0 +1,56 @@
+package ppsutil
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestDatumReuseSalt(t *testing.T) {
+	pipelineInfo := func() *pps.PipelineInfo {
+		return &pps.PipelineInfo{
+			Pipeline: client.NewPipeline("p"),
+			Transform: &pps.Transform{
+				Cmd: []string{"cp", "-r", "/pfs/in", "/pfs/out"},
+				Env: map[string]string{"A": "1", "B": "2", "C": "3"},
+			},
+			Input: client.NewCrossInput(
+				client.NewPFSInput("in", "/*"),
+				&pps.Input{Cron: &pps.CronInput{Name: "tick", Spec: "@every 1m", Start: types.TimestampNow()}},
+			),
+			ParallelismSpec: &pps.ParallelismSpec{Constant: 1},
+		}
+	}
+	salt, err := DatumReuseSalt(pipelineInfo())
+	require.NoError(t, err)
+	require.Equal(t, 32, len(salt))
+
+	same := pipelineInfo()
+	same.ParallelismSpec.Constant = 4
+	same.Input.Cross[1].Cron.Start = nil
+	s, err := DatumReuseSalt(same)
+	require.NoError(t, err)
+	require.Equal(t, salt, s)
+
+	for _, change := range []func(*pps.PipelineInfo){
+		func(p *pps.PipelineInfo) { p.Pipeline.Name = "q" },
+		func(p *pps.PipelineInfo) { p.Transform.Cmd[0] = "mv" },
+		func(p *pps.PipelineInfo) { p.Transform.Env["A"] = "2" },
+		func(p *pps.PipelineInfo) { p.Input.Cross[0].Pfs.Glob = "/" },
+	} {
+		different := pipelineInfo()
+		change(different)
+		s, err := DatumReuseSalt(different)
+		require.NoError(t, err)
+		require.NotEqual(t, salt, s)
+	}
+
+	// Spouts have no input
+	spout := pipelineInfo()
+	spout.Input = nil
+	_, err = DatumReuseSalt(spout)
+	require.NoError(t, err)
+}
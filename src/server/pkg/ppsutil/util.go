@@ -17,7 +17,9 @@ import (
 	"bytes"
 	"fmt"
 	"math"
+	"os"
 	"path"
+	"strconv"
 	"strings"
 	"time"
 
@@ -114,6 +116,30 @@ func GetLimitsResourceListFromPipeline(pipelineInfo *pps.PipelineInfo) (*v1.Reso
 	return getResourceListFromSpec(pipelineInfo.ResourceLimits, pipelineInfo.CacheSize)
 }
 
+// GetMergeResourceLists returns the resources that the pipeline's merge
+// workers request and are limited to. Unless the pipeline's MergeSpec sets
+// them, they're the pipeline's.
+func GetMergeResourceLists(pipelineInfo *pps.PipelineInfo) (requests *v1.ResourceList, limits *v1.ResourceList, retErr error) {
+	requestsSpec, limitsSpec := pipelineInfo.ResourceRequests, pipelineInfo.ResourceLimits
+	if pipelineInfo.MergeSpec != nil && pipelineInfo.MergeSpec.ResourceRequests != nil {
+		requestsSpec = pipelineInfo.MergeSpec.ResourceRequests
+	}
+	if pipelineInfo.MergeSpec != nil && pipelineInfo.MergeSpec.ResourceLimits != nil {
+		limitsSpec = pipelineInfo.MergeSpec.ResourceLimits
+	}
+	if requestsSpec != nil {
+		if requests, retErr = getResourceListFromSpec(requestsSpec, pipelineInfo.CacheSize); retErr != nil {
+			return nil, nil, retErr
+		}
+	}
+	if limitsSpec != nil {
+		if limits, retErr = getResourceListFromSpec(limitsSpec, pipelineInfo.CacheSize); retErr != nil {
+			return nil, nil, retErr
+		}
+	}
+	return requests, limits, nil
+}
+
 // getNumNodes attempts to retrieve the number of nodes in the current k8s
 // cluster
 func getNumNodes(kubeClient *kube.Clientset) (int, error) {
@@ -148,6 +174,17 @@ func GetExpectedNumWorkers(kubeClient *kube.Clientset, spec *ppsclient.Paralleli
 	return 0, fmt.Errorf("unable to interpret ParallelismSpec %+v", spec)
 }
 
+// GetPlannedNumWorkers returns the number of workers that the jobs of
+// 'pipelineInfo' are split up for: the max_workers of its autoscaling_spec,
+// if it has one, as it may scale up to that many workers during a job, and
+// otherwise the number of workers that its parallelism_spec starts.
+func GetPlannedNumWorkers(kubeClient *kube.Clientset, pipelineInfo *ppsclient.PipelineInfo) (int, error) {
+	if pipelineInfo.AutoscalingSpec != nil {
+		return int(pipelineInfo.AutoscalingSpec.MaxWorkers), nil
+	}
+	return GetExpectedNumWorkers(kubeClient, pipelineInfo.ParallelismSpec)
+}
+
 // GetExpectedNumHashtrees computes the expected number of hashtrees that
 // Pachyderm will create given the HashtreeSpec 'spec'.
 func GetExpectedNumHashtrees(spec *ppsclient.HashtreeSpec) (int64, error) {
@@ -159,6 +196,23 @@ func GetExpectedNumHashtrees(spec *ppsclient.HashtreeSpec) (int64, error) {
 	return 0, fmt.Errorf("unable to interpret HashtreeSpec %+v", spec)
 }
 
+// ParseFileMode parses one of a transform's octal permission strings (umask,
+// dir_mode or file_mode). It returns nil if 'mode' is unset.
+func ParseFileMode(mode string) (*os.FileMode, error) {
+	if mode == "" {
+		return nil, nil
+	}
+	m, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse %q as an octal file mode: %v", mode, err)
+	}
+	if m&^uint64(os.ModePerm) != 0 {
+		return nil, fmt.Errorf("file mode %q has bits set outside of 0777", mode)
+	}
+	result := os.FileMode(m)
+	return &result, nil
+}
+
 // GetPipelineInfo retrieves and returns a valid PipelineInfo from PFS. It does
 // the PFS read/unmarshalling of bytes as well as filling in missing fields
 func GetPipelineInfo(pachClient *client.APIClient, ptr *pps.EtcdPipelineInfo) (*pps.PipelineInfo, error) {
@@ -175,9 +229,52 @@ func GetPipelineInfo(pachClient *client.APIClient, ptr *pps.EtcdPipelineInfo) (*
 	result.JobCounts = ptr.JobCounts
 	result.LastJobState = ptr.LastJobState
 	result.SpecCommit = ptr.SpecCommit
+	result.WorkerConfig = ptr.WorkerConfig
 	return result, nil
 }
 
+// UpdateInPlace copies the fields of 'update' that can change while a
+// pipeline's workers keep running (its spec commit, parallelism, resource
+// requests and limits, description and whether it's stopped) into
+// 'pipelineInfo'. Running workers keep the resources they were created with;
+// only workers created after the update get the new ones.
+func UpdateInPlace(pipelineInfo, update *pps.PipelineInfo) {
+	pipelineInfo.SpecCommit = update.SpecCommit
+	pipelineInfo.ParallelismSpec = update.ParallelismSpec
+	pipelineInfo.ResourceRequests = update.ResourceRequests
+	pipelineInfo.ResourceLimits = update.ResourceLimits
+	pipelineInfo.Description = update.Description
+	pipelineInfo.Stopped = update.Stopped
+}
+
+// UpdatableInPlace returns true if 'newInfo' differs from 'oldInfo' only in
+// fields that UpdateInPlace copies. Such updates keep the pipeline's version,
+// so that PPS patches the pipeline's existing RC (whose name includes the
+// version) instead of replacing its workers.
+func UpdatableInPlace(oldInfo, newInfo *pps.PipelineInfo) bool {
+	o := proto.Clone(oldInfo).(*pps.PipelineInfo)
+	n := proto.Clone(newInfo).(*pps.PipelineInfo)
+	UpdateInPlace(o, n)
+	if newInfo.PodSpec != "" || newInfo.PodPatch != "" {
+		// The pod spec or patch may set the workers' resources itself, so
+		// PPS can't patch them in place
+		o.ResourceRequests = oldInfo.ResourceRequests
+		o.ResourceLimits = oldInfo.ResourceLimits
+	}
+	for _, info := range []*pps.PipelineInfo{o, n} {
+		// These fields describe the pipeline's history rather than its spec
+		info.Version = 0
+		info.CreatedAt = nil
+		info.State = 0
+		info.Reason = ""
+		info.RecentError = ""
+		info.JobCounts = nil
+		info.LastJobState = 0
+		info.GithookURL = ""
+	}
+	return proto.Equal(o, n)
+}
+
 // FailPipeline updates the pipeline's state to failed and sets the failure reason
 func FailPipeline(ctx context.Context, etcdClient *etcd.Client, pipelinesCollection col.Collection, pipelineName string, reason string) error {
 	_, err := col.NewSTM(ctx, etcdClient, func(stm col.STM) error {
@@ -194,6 +291,48 @@ func FailPipeline(ctx context.Context, etcdClient *etcd.Client, pipelinesCollect
 	return err
 }
 
+// CrashPipeline updates the pipeline's state to crashing and sets the reason.
+// Unlike failed pipelines, crashing pipelines keep their workers. Paused and
+// failed pipelines are left alone.
+func CrashPipeline(ctx context.Context, etcdClient *etcd.Client, pipelinesCollection col.Collection, pipelineName string, reason string) error {
+	_, err := col.NewSTM(ctx, etcdClient, func(stm col.STM) error {
+		pipelines := pipelinesCollection.ReadWrite(stm)
+		pipelinePtr := new(pps.EtcdPipelineInfo)
+		if err := pipelines.Get(pipelineName, pipelinePtr); err != nil {
+			return err
+		}
+		if pipelinePtr.State == pps.PipelineState_PIPELINE_PAUSED ||
+			pipelinePtr.State == pps.PipelineState_PIPELINE_FAILURE {
+			return nil
+		}
+		pipelinePtr.State = pps.PipelineState_PIPELINE_CRASHING
+		pipelinePtr.Reason = reason
+		pipelines.Put(pipelineName, pipelinePtr)
+		return nil
+	})
+	return err
+}
+
+// RecoverPipeline moves a crashing pipeline back to running, and leaves
+// pipelines in any other state alone
+func RecoverPipeline(ctx context.Context, etcdClient *etcd.Client, pipelinesCollection col.Collection, pipelineName string) error {
+	_, err := col.NewSTM(ctx, etcdClient, func(stm col.STM) error {
+		pipelines := pipelinesCollection.ReadWrite(stm)
+		pipelinePtr := new(pps.EtcdPipelineInfo)
+		if err := pipelines.Get(pipelineName, pipelinePtr); err != nil {
+			return err
+		}
+		if pipelinePtr.State != pps.PipelineState_PIPELINE_CRASHING {
+			return nil
+		}
+		pipelinePtr.State = pps.PipelineState_PIPELINE_RUNNING
+		pipelinePtr.Reason = ""
+		pipelines.Put(pipelineName, pipelinePtr)
+		return nil
+	})
+	return err
+}
+
 // JobInput fills in the commits for a JobInfo
 func JobInput(pipelineInfo *pps.PipelineInfo, outputCommitInfo *pfs.CommitInfo) *pps.Input {
 	// branchToCommit maps strings of the form "<repo>/<branch>" to PFS commits
@@ -214,6 +353,11 @@ func JobInput(pipelineInfo *pps.PipelineInfo, outputCommitInfo *pfs.CommitInfo)
 				input.Cron.Commit = commit.ID
 			}
 		}
+		if input.SQL != nil {
+			if commit, ok := branchToCommit[key(input.SQL.Repo, "master")]; ok {
+				input.SQL.Commit = commit.ID
+			}
+		}
 		if input.Git != nil {
 			if commit, ok := branchToCommit[key(input.Git.Name, input.Git.Branch)]; ok {
 				input.Git.Commit = commit.ID
@@ -223,33 +367,74 @@ func JobInput(pipelineInfo *pps.PipelineInfo, outputCommitInfo *pfs.CommitInfo)
 	return jobInput
 }
 
+// HasMountedInputs returns whether any of the PFS inputs in 'input' are
+// mounted (i.e. have mount set), in which case the pipeline's workers mount
+// PFS with FUSE.
+func HasMountedInputs(input *pps.Input) bool {
+	var result bool
+	pps.VisitInput(input, func(input *pps.Input) {
+		if input.Pfs != nil && input.Pfs.Mount {
+			result = true
+		}
+	})
+	return result
+}
+
 // PipelineReqFromInfo converts a PipelineInfo into a CreatePipelineRequest.
 func PipelineReqFromInfo(pipelineInfo *ppsclient.PipelineInfo) *ppsclient.CreatePipelineRequest {
 	return &ppsclient.CreatePipelineRequest{
-		Pipeline:         pipelineInfo.Pipeline,
-		Transform:        pipelineInfo.Transform,
-		ParallelismSpec:  pipelineInfo.ParallelismSpec,
-		HashtreeSpec:     pipelineInfo.HashtreeSpec,
-		Egress:           pipelineInfo.Egress,
-		OutputBranch:     pipelineInfo.OutputBranch,
-		ResourceRequests: pipelineInfo.ResourceRequests,
-		ResourceLimits:   pipelineInfo.ResourceLimits,
-		Input:            pipelineInfo.Input,
-		Description:      pipelineInfo.Description,
-		CacheSize:        pipelineInfo.CacheSize,
-		EnableStats:      pipelineInfo.EnableStats,
-		MaxQueueSize:     pipelineInfo.MaxQueueSize,
-		Service:          pipelineInfo.Service,
-		ChunkSpec:        pipelineInfo.ChunkSpec,
-		DatumTimeout:     pipelineInfo.DatumTimeout,
-		JobTimeout:       pipelineInfo.JobTimeout,
-		Salt:             pipelineInfo.Salt,
-		PodSpec:          pipelineInfo.PodSpec,
-		PodPatch:         pipelineInfo.PodPatch,
-		Spout:            pipelineInfo.Spout,
-		SchedulingSpec:   pipelineInfo.SchedulingSpec,
-		DatumTries:       pipelineInfo.DatumTries,
-		Standby:          pipelineInfo.Standby,
+		Pipeline:               pipelineInfo.Pipeline,
+		Transform:              pipelineInfo.Transform,
+		ParallelismSpec:        pipelineInfo.ParallelismSpec,
+		HashtreeSpec:           pipelineInfo.HashtreeSpec,
+		Egress:                 pipelineInfo.Egress,
+		OutputBranch:           pipelineInfo.OutputBranch,
+		ResourceRequests:       pipelineInfo.ResourceRequests,
+		ResourceLimits:         pipelineInfo.ResourceLimits,
+		Input:                  pipelineInfo.Input,
+		Description:            pipelineInfo.Description,
+		CacheSize:              pipelineInfo.CacheSize,
+		EnableStats:            pipelineInfo.EnableStats,
+		MaxQueueSize:           pipelineInfo.MaxQueueSize,
+		PrefetchSize:           pipelineInfo.PrefetchSize,
+		Service:                pipelineInfo.Service,
+		ChunkSpec:              pipelineInfo.ChunkSpec,
+		DatumTimeout:           pipelineInfo.DatumTimeout,
+		DatumTimeoutPerMB:      pipelineInfo.DatumTimeoutPerMB,
+		DownloadTimeout:        pipelineInfo.DownloadTimeout,
+		JobTimeout:             pipelineInfo.JobTimeout,
+		Salt:                   pipelineInfo.Salt,
+		PodSpec:                pipelineInfo.PodSpec,
+		PodPatch:               pipelineInfo.PodPatch,
+		Spout:                  pipelineInfo.Spout,
+		SchedulingSpec:         pipelineInfo.SchedulingSpec,
+		DatumTries:             pipelineInfo.DatumTries,
+		Standby:                pipelineInfo.Standby,
+		Metadata:               pipelineInfo.Metadata,
+		SpeculationFactor:      pipelineInfo.SpeculationFactor,
+		RetryOomDatums:         pipelineInfo.RetryOomDatums,
+		JobRetention:           pipelineInfo.JobRetention,
+		PreviousOutput:         pipelineInfo.PreviousOutput,
+		Cache:                  pipelineInfo.Cache,
+		JobScratch:             pipelineInfo.JobScratch,
+		InputWriteCheck:        pipelineInfo.InputWriteCheck,
+		MergeSpec:              pipelineInfo.MergeSpec,
+		AttestationSpec:        pipelineInfo.AttestationSpec,
+		MetricsPush:            pipelineInfo.MetricsPush,
+		Defer:                  pipelineInfo.Defer,
+		StatsSampleRate:        pipelineInfo.StatsSampleRate,
+		Quarantine:             pipelineInfo.Quarantine,
+		BandwidthLimit:         pipelineInfo.BandwidthLimit,
+		CPUPinning:             pipelineInfo.CPUPinning,
+		ReuseDatums:            pipelineInfo.ReuseDatums,
+		ScratchVolume:          pipelineInfo.ScratchVolume,
+		MaxFailedDatumsPercent: pipelineInfo.MaxFailedDatumsPercent,
+		EmptyJobPolicy:         pipelineInfo.EmptyJobPolicy,
+		AutoscalingSpec:        pipelineInfo.AutoscalingSpec,
+		Notifications:          pipelineInfo.Notifications,
+		DatumTreeCompression:   pipelineInfo.DatumTreeCompression,
+		JobOrder:               pipelineInfo.JobOrder,
+		MaxOutstandingJobs:     pipelineInfo.MaxOutstandingJobs,
 	}
 }
 
@@ -268,7 +453,8 @@ func IsTerminal(state pps.JobState) bool {
 }
 
 // UpdateJobState performs the operations involved with a job state transition.
-func UpdateJobState(pipelines col.ReadWriteCollection, jobs col.ReadWriteCollection, jobPtr *pps.EtcdJobInfo, state pps.JobState, reason string) error {
+// 'failureType' classifies 'reason' when the job failed because of a datum.
+func UpdateJobState(pipelines col.ReadWriteCollection, jobs col.ReadWriteCollection, jobPtr *pps.EtcdJobInfo, state pps.JobState, reason string, failureType pps.FailureType) error {
 	if jobPtr.State == pps.JobState_JOB_FAILURE {
 		return fmt.Errorf("cannot put %q in state %s as it's already in state JOB_FAILURE", jobPtr.Job.ID, state.String())
 	}
@@ -296,11 +482,13 @@ func UpdateJobState(pipelines col.ReadWriteCollection, jobs col.ReadWriteCollect
 		jobPtr.Started, err = types.TimestampProto(time.Now())
 	} else if IsTerminal(state) {
 		jobPtr.Finished, err = types.TimestampProto(time.Now())
+		jobPtr.Paused = false
 	}
 	if err != nil {
 		return err
 	}
 	jobPtr.State = state
 	jobPtr.Reason = reason
+	jobPtr.FailureType = failureType
 	return jobs.Put(jobPtr.Job.ID, jobPtr)
 }
@@ -0,0 +1,100 @@
+package ppsutil
+
+import (
+	"os"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestParseFileMode(t *testing.T) {
+	for _, test := range []struct {
+		mode     string
+		expected os.FileMode
+		unset    bool
+		err      bool
+	}{
+		{mode: "", unset: true},
+		{mode: "0022", expected: 0022},
+		{mode: "0755", expected: 0755},
+		{mode: "644", expected: 0644},
+		{mode: "0999", err: true},
+		{mode: "rwxr-xr-x", err: true},
+		{mode: "01777", err: true},
+	} {
+		mode, err := ParseFileMode(test.mode)
+		if test.err {
+			require.YesError(t, err, test.mode)
+			continue
+		}
+		require.NoError(t, err, test.mode)
+		if test.unset {
+			require.Nil(t, mode, test.mode)
+			continue
+		}
+		require.NotNil(t, mode, test.mode)
+		require.Equal(t, test.expected, *mode, test.mode)
+	}
+}
+
+func TestUpdatableInPlace(t *testing.T) {
+	spec := func() *pps.PipelineInfo {
+		return &pps.PipelineInfo{
+			Pipeline:        &pps.Pipeline{Name: "foo"},
+			Version:         1,
+			Transform:       &pps.Transform{Image: "ubuntu", Cmd: []string{"true"}},
+			ParallelismSpec: &pps.ParallelismSpec{Constant: 1},
+			Salt:            "salt",
+			SpecCommit:      &pfs.Commit{ID: "1"},
+			State:           pps.PipelineState_PIPELINE_RUNNING,
+		}
+	}
+	oldInfo := spec()
+	require.True(t, UpdatableInPlace(oldInfo, spec()))
+
+	newInfo := spec()
+	newInfo.Version = 2
+	newInfo.SpecCommit = &pfs.Commit{ID: "2"}
+	newInfo.State = pps.PipelineState_PIPELINE_STARTING
+	newInfo.ParallelismSpec = &pps.ParallelismSpec{Constant: 4}
+	newInfo.ResourceRequests = &pps.ResourceSpec{Cpu: 2}
+	newInfo.ResourceLimits = &pps.ResourceSpec{Memory: "1G"}
+	newInfo.Description = "more workers"
+	newInfo.Stopped = true
+	require.True(t, UpdatableInPlace(oldInfo, newInfo))
+	// Neither side is modified
+	require.Equal(t, uint64(1), oldInfo.ParallelismSpec.Constant)
+
+	UpdateInPlace(oldInfo, newInfo)
+	require.Equal(t, "2", oldInfo.SpecCommit.ID)
+	require.Equal(t, uint64(4), oldInfo.ParallelismSpec.Constant)
+	require.Equal(t, "1G", oldInfo.ResourceLimits.Memory)
+	require.Equal(t, "more workers", oldInfo.Description)
+	require.True(t, oldInfo.Stopped)
+	require.Equal(t, uint64(1), oldInfo.Version)
+
+	for _, update := range []func(*pps.PipelineInfo){
+		func(info *pps.PipelineInfo) { info.Transform.Image = "alpine" },
+		func(info *pps.PipelineInfo) { info.Transform.Cmd = []string{"false"} },
+		func(info *pps.PipelineInfo) { info.Salt = "pepper" },
+		func(info *pps.PipelineInfo) { info.CacheSize = "1G" },
+		func(info *pps.PipelineInfo) {
+			info.PodPatch = `[{"op": "add", "path": "/hostNetwork", "value": true}]`
+		},
+	} {
+		newInfo := spec()
+		update(newInfo)
+		require.False(t, UpdatableInPlace(spec(), newInfo))
+	}
+
+	// Resources set by a pod patch can't be changed in place
+	oldInfo = spec()
+	oldInfo.PodPatch = `[{"op": "remove", "path": "/containers/0/resources"}]`
+	newInfo = spec()
+	newInfo.PodPatch = oldInfo.PodPatch
+	require.True(t, UpdatableInPlace(oldInfo, newInfo))
+	newInfo.ResourceLimits = &pps.ResourceSpec{Memory: "1G"}
+	require.False(t, UpdatableInPlace(oldInfo, newInfo))
+}
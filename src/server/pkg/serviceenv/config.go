@@ -20,6 +20,10 @@ type GlobalConfiguration struct {
 	PPSEtcdPrefix string `env:"PPS_ETCD_PREFIX,default=pachyderm_pps"`
 	Namespace     string `env:"NAMESPACE,default=default"`
 	StorageRoot   string `env:"PACH_ROOT,default=/pach"`
+
+	// EtcdSlowOperationThreshold is how long an etcd operation can take
+	// before it's logged as slow (see collection.SetSlowOperationThreshold)
+	EtcdSlowOperationThreshold string `env:"ETCD_SLOW_OPERATION_THRESHOLD,default=1s"`
 }
 
 // PachdFullConfiguration contains the full pachd configuration.
@@ -54,6 +58,20 @@ type PachdSpecificConfiguration struct {
 	MemoryRequest         string `env:"PACHD_MEMORY_REQUEST,default=1T"`
 	WorkerUsesRoot        bool   `env:"WORKER_USES_ROOT,default=true"`
 	S3GatewayPort         uint16 `env:"S3GATEWAY_PORT,default=600"`
+	S3GatewayBucketPolicy string `env:"S3GATEWAY_BUCKET_POLICY,default="`
+	S3ReadAfterWrite      string `env:"S3GATEWAY_READ_AFTER_WRITE,default="`
+	S3AuditSink           string `env:"S3GATEWAY_AUDIT_SINK,default="`
+	S3HTTP2               bool   `env:"S3GATEWAY_HTTP2,default=true"`
+	S3MaxRequests         int    `env:"S3GATEWAY_MAX_REQUESTS,default=0"`
+	S3MaxClientRequests   int    `env:"S3GATEWAY_MAX_CLIENT_REQUESTS,default=0"`
+	S3MaxStreams          uint32 `env:"S3GATEWAY_MAX_CONNECTION_STREAMS,default=0"`
+	S3IdleTimeout         string `env:"S3GATEWAY_IDLE_TIMEOUT,default="`
+	S3BucketLimits        string `env:"S3GATEWAY_BUCKET_LIMITS,default="`
+	S3MetadataCacheTTL    string `env:"S3GATEWAY_METADATA_CACHE_TTL,default=30s"`
+	WorkerLogSink         string `env:"WORKER_LOG_SINK,default="`
+	WorkerTreeCacheSize   string `env:"WORKER_HASHTREE_CACHE_SIZE,default="`
+	PFSChecksums          string `env:"PFS_CHECKSUMS,default="`
+	PFSPackThreshold      string `env:"PFS_PACK_THRESHOLD,default="`
 }
 
 // StorageConfiguration contains the storage configuration.
@@ -79,6 +97,9 @@ type WorkerSpecificConfiguration struct {
 	PPSSpecCommitID string `env:"PPS_SPEC_COMMIT,required"`
 	// The name of this pod
 	PodName string `env:"PPS_POD_NAME,required"`
+	// The log sink that the worker sends its logs to, if any (see
+	// src/server/worker/logs)
+	LogSink string `env:"WORKER_LOG_SINK,default="`
 }
 
 // FeatureFlags contains the configuration for feature flags.
@@ -0,0 +1,170 @@
+package sync
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// PipeStats are the stats of a named pipe that a Puller serves a lazy file
+// through
+type PipeStats struct {
+	// Path is the path of the pipe
+	Path string
+	// BytesServed is how much of the file's content was written to the pipe
+	BytesServed int64
+	// WaitTime is how long the pipe waited for the file's content to be
+	// downloaded, i.e. how long reads of the file may have stalled
+	WaitTime time.Duration
+	// BlockedTime is how long content that was downloaded waited for the
+	// reader of the pipe to read it
+	BlockedTime time.Duration
+	// Opened and Closed are when the reader opened the pipe and when it was
+	// closed. Both are zero if the reader never opened the pipe.
+	Opened, Closed time.Time
+}
+
+// SetReadAhead sets how many bytes of each file that later calls to Pull
+// serve through pipes are downloaded ahead of the reads of the pipe. If
+// it's 0 (the default), the content is downloaded as it's read.
+func (p *Puller) SetReadAhead(size int64) {
+	p.Lock()
+	defer p.Unlock()
+	p.readAhead = size
+}
+
+// PipeStats returns the stats of the pipes that this puller created. The
+// stats of a pipe are only complete once it's closed, so PipeStats should
+// be called after CleanUp.
+func (p *Puller) PipeStats() []*PipeStats {
+	p.Lock()
+	defer p.Unlock()
+	result := make([]*PipeStats, len(p.pipeStats))
+	copy(result, p.pipeStats)
+	return result
+}
+
+// statsWriter writes to a pipe, and adds the time spent in writes (waiting
+// for the reader) and between them (waiting for content) to 'stats'
+type statsWriter struct {
+	w     io.Writer
+	stats *PipeStats
+	last  time.Time
+}
+
+func newStatsWriter(w io.Writer, stats *PipeStats) *statsWriter {
+	return &statsWriter{w: w, stats: stats, last: time.Now()}
+}
+
+func (s *statsWriter) Write(p []byte) (int, error) {
+	start := time.Now()
+	s.stats.WaitTime += start.Sub(s.last)
+	n, err := s.w.Write(p)
+	s.last = time.Now()
+	s.stats.BlockedTime += s.last.Sub(start)
+	s.stats.BytesServed += int64(n)
+	return n, err
+}
+
+// readAhead copies what 'f' writes to 'w' through a buffer of 'size' bytes,
+// so that 'f' can download content before the reader of 'w' reads it
+func readAhead(w io.Writer, size int64, f func(io.Writer) error) error {
+	b := newReadAheadBuffer(size)
+	errCh := make(chan error, 1)
+	go func() {
+		err := f(b)
+		b.closeWrite(err)
+		errCh <- err
+	}()
+	_, err := io.Copy(w, b)
+	// If the reader of 'w' stopped reading, 'f' is unblocked
+	b.closeRead()
+	if fErr := <-errCh; err == nil {
+		err = fErr
+	}
+	return err
+}
+
+// errReadAheadClosed is returned to the writer of a readAheadBuffer once no
+// more of its content will be read
+var errReadAheadClosed = errors.New("read-ahead buffer closed")
+
+// readAheadBuffer is an in-memory pipe that holds up to 'size' bytes
+// written to it that haven't been read yet. Writes block while it's full,
+// and reads block while it's empty.
+type readAheadBuffer struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+	buf  bytes.Buffer
+	size int
+
+	writeClosed bool
+	writeErr    error
+	readClosed  bool
+}
+
+func newReadAheadBuffer(size int64) *readAheadBuffer {
+	b := &readAheadBuffer{size: int(size)}
+	b.cond = sync.NewCond(&b.mu)
+	return b
+}
+
+func (b *readAheadBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var written int
+	for len(p) > 0 {
+		for b.buf.Len() >= b.size && !b.readClosed {
+			b.cond.Wait()
+		}
+		if b.readClosed {
+			return written, errReadAheadClosed
+		}
+		n := b.size - b.buf.Len()
+		if n > len(p) {
+			n = len(p)
+		}
+		b.buf.Write(p[:n])
+		p = p[n:]
+		written += n
+		b.cond.Broadcast()
+	}
+	return written, nil
+}
+
+func (b *readAheadBuffer) Read(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for b.buf.Len() == 0 && !b.writeClosed {
+		b.cond.Wait()
+	}
+	if b.buf.Len() == 0 {
+		if b.writeErr != nil {
+			return 0, b.writeErr
+		}
+		return 0, io.EOF
+	}
+	n, _ := b.buf.Read(p)
+	b.cond.Broadcast()
+	return n, nil
+}
+
+// closeWrite signals that no more content will be written, because of 'err'
+// if it's non-nil
+func (b *readAheadBuffer) closeWrite(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.writeClosed = true
+	b.writeErr = err
+	b.cond.Broadcast()
+}
+
+// closeRead signals that no more content will be read
+func (b *readAheadBuffer) closeRead() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.readClosed = true
+	b.cond.Broadcast()
+}
@@ -0,0 +1,62 @@
+package sync
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestReadAhead(t *testing.T) {
+	content := strings.Repeat("0123456789", 1000)
+	var out bytes.Buffer
+	require.NoError(t, readAhead(&out, 64, func(w io.Writer) error {
+		_, err := io.Copy(w, strings.NewReader(content))
+		return err
+	}))
+	require.Equal(t, content, out.String())
+
+	// Errors of the download are returned once the content before them
+	// has been copied
+	out.Reset()
+	require.YesError(t, readAhead(&out, 64, func(w io.Writer) error {
+		if _, err := w.Write([]byte("foo")); err != nil {
+			return err
+		}
+		return errors.New("download failed")
+	}))
+	require.Equal(t, "foo", out.String())
+}
+
+// failingWriter fails every write, like a pipe whose reader went away
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+func TestReadAheadReaderClosed(t *testing.T) {
+	// The download is unblocked, rather than waiting on the full buffer
+	// forever, if the reader stops reading
+	var downloadErr error
+	require.YesError(t, readAhead(failingWriter{}, 8, func(w io.Writer) error {
+		_, downloadErr = io.Copy(w, strings.NewReader(strings.Repeat("x", 1000)))
+		return downloadErr
+	}))
+	require.Equal(t, errReadAheadClosed, downloadErr)
+}
+
+func TestStatsWriter(t *testing.T) {
+	stats := &PipeStats{}
+	w := newStatsWriter(ioutil.Discard, stats)
+	_, err := w.Write([]byte("foo"))
+	require.NoError(t, err)
+	_, err = w.Write([]byte("barbaz"))
+	require.NoError(t, err)
+	require.Equal(t, int64(9), stats.BytesServed)
+	require.True(t, stats.WaitTime > 0)
+}
@@ -6,8 +6,8 @@ import (
 	"io"
 	"os"
 	"path/filepath"
-	"sync/atomic"
 	"syscall"
+	"time"
 )
 
 func (p *Puller) makePipe(path string, f func(io.Writer) error) error {
@@ -17,10 +17,14 @@ func (p *Puller) makePipe(path string, f func(io.Writer) error) error {
 	if err := syscall.Mkfifo(path, 0666); err != nil {
 		return err
 	}
+	stats := &PipeStats{Path: path}
+	var readAheadSize int64
 	func() {
 		p.Lock()
 		defer p.Unlock()
 		p.pipes[path] = true
+		p.pipeStats = append(p.pipeStats, stats)
+		readAheadSize = p.readAhead
 	}()
 	// This goro will block until the user's code opens the
 	// fifo.  That means we need to "abandon" this goro so that
@@ -52,12 +56,15 @@ func (p *Puller) makePipe(path string, f func(io.Writer) error) error {
 			}() {
 				return nil
 			}
-			w := &sizeWriter{w: file}
-			if err := f(w); err != nil {
-				return err
+			stats.Opened = time.Now()
+			defer func() { stats.Closed = time.Now() }()
+			w := newStatsWriter(p.throttledWriter(file), stats)
+			if readAheadSize > 0 {
+				return readAhead(w, readAheadSize, func(w io.Writer) error {
+					return f(&sizeWriter{w: w, size: &p.size})
+				})
 			}
-			atomic.AddInt64(&p.size, w.size)
-			return nil
+			return f(&sizeWriter{w: w, size: &p.size})
 		}(); err != nil {
 			select {
 			case p.errCh <- err:
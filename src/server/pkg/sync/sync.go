@@ -34,6 +34,13 @@ type Puller struct {
 	wg sync.WaitGroup
 	// size is the total amount this puller has pulled
 	size int64
+	// readAhead is how much of the files served through pipes is downloaded
+	// ahead of the reads of the pipes
+	readAhead int64
+	// pipeStats are the stats of the pipes that have been created
+	pipeStats []*PipeStats
+	// throttle limits the bandwidth of the files this puller downloads
+	throttle *Throttle
 }
 
 // NewPuller creates a new Puller struct.
@@ -44,17 +51,25 @@ func NewPuller() *Puller {
 	}
 }
 
+// sizeWriter adds the bytes written through it to 'size' as they're written,
+// so that Size reports the progress of files that are still being pulled
 type sizeWriter struct {
 	w    io.Writer
-	size int64
+	size *int64
 }
 
 func (s *sizeWriter) Write(p []byte) (int, error) {
 	n, err := s.w.Write(p)
-	s.size += int64(n)
+	atomic.AddInt64(s.size, int64(n))
 	return n, err
 }
 
+// Size returns the number of bytes that this puller has pulled so far. It's
+// safe to call while a pull is running.
+func (p *Puller) Size() int64 {
+	return atomic.LoadInt64(&p.size)
+}
+
 func (p *Puller) makeFile(path string, f func(io.Writer) error) (retErr error) {
 	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
 		return err
@@ -68,12 +83,7 @@ func (p *Puller) makeFile(path string, f func(io.Writer) error) (retErr error) {
 			retErr = err
 		}
 	}()
-	w := &sizeWriter{w: file}
-	if err := f(w); err != nil {
-		return err
-	}
-	atomic.AddInt64(&p.size, w.size)
-	return nil
+	return f(&sizeWriter{w: p.throttledWriter(file), size: &p.size})
 }
 
 // Pull clones an entire repo at a certain commit.
@@ -290,9 +300,7 @@ func (p *Puller) CleanUp() (int64, error) {
 			result = err
 		}
 	}
-	size := p.size
-	p.size = 0
-	return size, result
+	return atomic.SwapInt64(&p.size, 0), result
 }
 
 // Push puts files under root into an open commit.
@@ -0,0 +1,104 @@
+package sync
+
+import (
+	"context"
+	"io"
+
+	"golang.org/x/time/rate"
+)
+
+// Throttle limits the bandwidth of the transfers that it's shared by. A nil
+// Throttle doesn't limit anything.
+type Throttle struct {
+	limiter *rate.Limiter
+}
+
+// NewThrottle returns a Throttle of 'bytesPerSecond', or nil if
+// 'bytesPerSecond' isn't positive.
+func NewThrottle(bytesPerSecond int64) *Throttle {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+	// Up to a second's worth of bytes can be transferred at once, so that an
+	// idle transfer doesn't build up a burst bigger than that
+	return &Throttle{limiter: rate.NewLimiter(rate.Limit(bytesPerSecond), int(bytesPerSecond))}
+}
+
+// SetThrottle makes later calls to Pull (and PullDiff and PullTree) download
+// files at the bandwidth of 't', which may be shared with other transfers.
+func (p *Puller) SetThrottle(t *Throttle) {
+	p.Lock()
+	defer p.Unlock()
+	p.throttle = t
+}
+
+// throttledWriter returns 'w', throttled by the puller's throttle
+func (p *Puller) throttledWriter(w io.Writer) io.Writer {
+	p.Lock()
+	defer p.Unlock()
+	return p.throttle.Writer(context.Background(), w)
+}
+
+// wait blocks until 'n' bytes can be transferred. Transfers bigger than the
+// limiter's burst wait for it in parts.
+func (t *Throttle) wait(ctx context.Context, n int) error {
+	for n > 0 {
+		m := n
+		if burst := t.limiter.Burst(); m > burst {
+			m = burst
+		}
+		if err := t.limiter.WaitN(ctx, m); err != nil {
+			return err
+		}
+		n -= m
+	}
+	return nil
+}
+
+// Writer returns a writer that writes to 'w' at the throttle's bandwidth
+func (t *Throttle) Writer(ctx context.Context, w io.Writer) io.Writer {
+	if t == nil {
+		return w
+	}
+	return &throttledWriter{ctx: ctx, t: t, w: w}
+}
+
+// Reader returns a reader that reads from 'r' at the throttle's bandwidth
+func (t *Throttle) Reader(ctx context.Context, r io.Reader) io.Reader {
+	if t == nil {
+		return r
+	}
+	return &throttledReader{ctx: ctx, t: t, r: r}
+}
+
+type throttledWriter struct {
+	ctx context.Context
+	t   *Throttle
+	w   io.Writer
+}
+
+func (w *throttledWriter) Write(p []byte) (int, error) {
+	if err := w.t.wait(w.ctx, len(p)); err != nil {
+		return 0, err
+	}
+	return w.w.Write(p)
+}
+
+type throttledReader struct {
+	ctx context.Context
+	t   *Throttle
+	r   io.Reader
+}
+
+// Read waits for the bytes that it read, rather than the ones it was asked
+// for, so that reads near the end of 'r' don't wait for bytes that aren't
+// there
+func (r *throttledReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		if waitErr := r.t.wait(r.ctx, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,36 @@
+package sync
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestThrottle(t *testing.T) {
+	var nilThrottle *Throttle
+	require.Nil(t, NewThrottle(0))
+	w := &bytes.Buffer{}
+	require.Equal(t, io.Writer(w), nilThrottle.Writer(context.Background(), w))
+
+	// The throttle starts with a second's worth of bytes, so transferring
+	// 1.5 seconds' worth takes at least half a second
+	const bytesPerSecond = 1024 * 1024
+	data := bytes.Repeat([]byte("a"), bytesPerSecond*3/2)
+	throttle := NewThrottle(bytesPerSecond)
+	start := time.Now()
+	n, err := throttle.Writer(context.Background(), ioutil.Discard).Write(data)
+	require.NoError(t, err)
+	require.Equal(t, len(data), n)
+	require.True(t, time.Since(start) >= 400*time.Millisecond)
+
+	// readers and writers share the throttle's bandwidth
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = io.Copy(ioutil.Discard, throttle.Reader(ctx, bytes.NewReader(data)))
+	require.YesError(t, err)
+}
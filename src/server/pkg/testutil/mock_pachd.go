@@ -376,8 +376,10 @@ type inspectCommitFunc func(context.Context, *pfs.InspectCommitRequest) (*pfs.Co
 type listCommitFunc func(context.Context, *pfs.ListCommitRequest) (*pfs.CommitInfos, error)
 type listCommitStreamFunc func(*pfs.ListCommitRequest, pfs.API_ListCommitStreamServer) error
 type deleteCommitFunc func(context.Context, *pfs.DeleteCommitRequest) (*types.Empty, error)
+type expireCommitsFunc func(context.Context, *pfs.ExpireCommitsRequest) (*pfs.CommitInfos, error)
 type flushCommitFunc func(*pfs.FlushCommitRequest, pfs.API_FlushCommitServer) error
 type subscribeCommitFunc func(*pfs.SubscribeCommitRequest, pfs.API_SubscribeCommitServer) error
+type subscribeFileChangesFunc func(*pfs.SubscribeFileChangesRequest, pfs.API_SubscribeFileChangesServer) error
 type buildCommitFunc func(context.Context, *pfs.BuildCommitRequest) (*pfs.Commit, error)
 type createBranchFunc func(context.Context, *pfs.CreateBranchRequest) (*types.Empty, error)
 type inspectBranchFunc func(context.Context, *pfs.InspectBranchRequest) (*pfs.BranchInfo, error)
@@ -393,6 +395,7 @@ type walkFileFunc func(*pfs.WalkFileRequest, pfs.API_WalkFileServer) error
 type globFileFunc func(context.Context, *pfs.GlobFileRequest) (*pfs.FileInfos, error)
 type globFileStreamFunc func(*pfs.GlobFileRequest, pfs.API_GlobFileStreamServer) error
 type diffFileFunc func(context.Context, *pfs.DiffFileRequest) (*pfs.DiffFileResponse, error)
+type searchFilesFunc func(context.Context, *pfs.SearchFilesRequest) (*pfs.SearchFilesResponse, error)
 type deleteFileFunc func(context.Context, *pfs.DeleteFileRequest) (*types.Empty, error)
 type deleteAllPFSFunc func(context.Context, *types.Empty) (*types.Empty, error)
 type fsckFunc func(*pfs.FsckRequest, pfs.API_FsckServer) error
@@ -407,8 +410,10 @@ type mockInspectCommit struct{ handler inspectCommitFunc }
 type mockListCommit struct{ handler listCommitFunc }
 type mockListCommitStream struct{ handler listCommitStreamFunc }
 type mockDeleteCommit struct{ handler deleteCommitFunc }
+type mockExpireCommits struct{ handler expireCommitsFunc }
 type mockFlushCommit struct{ handler flushCommitFunc }
 type mockSubscribeCommit struct{ handler subscribeCommitFunc }
+type mockSubscribeFileChanges struct{ handler subscribeFileChangesFunc }
 type mockBuildCommit struct{ handler buildCommitFunc }
 type mockCreateBranch struct{ handler createBranchFunc }
 type mockInspectBranch struct{ handler inspectBranchFunc }
@@ -424,77 +429,84 @@ type mockWalkFile struct{ handler walkFileFunc }
 type mockGlobFile struct{ handler globFileFunc }
 type mockGlobFileStream struct{ handler globFileStreamFunc }
 type mockDiffFile struct{ handler diffFileFunc }
+type mockSearchFiles struct{ handler searchFilesFunc }
 type mockDeleteFile struct{ handler deleteFileFunc }
 type mockDeleteAllPFS struct{ handler deleteAllPFSFunc }
 type mockFsck struct{ handler fsckFunc }
 
-func (mock *mockCreateRepo) Use(cb createRepoFunc)             { mock.handler = cb }
-func (mock *mockInspectRepo) Use(cb inspectRepoFunc)           { mock.handler = cb }
-func (mock *mockListRepo) Use(cb listRepoFunc)                 { mock.handler = cb }
-func (mock *mockDeleteRepo) Use(cb deleteRepoFunc)             { mock.handler = cb }
-func (mock *mockStartCommit) Use(cb startCommitFunc)           { mock.handler = cb }
-func (mock *mockFinishCommit) Use(cb finishCommitFunc)         { mock.handler = cb }
-func (mock *mockInspectCommit) Use(cb inspectCommitFunc)       { mock.handler = cb }
-func (mock *mockListCommit) Use(cb listCommitFunc)             { mock.handler = cb }
-func (mock *mockListCommitStream) Use(cb listCommitStreamFunc) { mock.handler = cb }
-func (mock *mockDeleteCommit) Use(cb deleteCommitFunc)         { mock.handler = cb }
-func (mock *mockFlushCommit) Use(cb flushCommitFunc)           { mock.handler = cb }
-func (mock *mockSubscribeCommit) Use(cb subscribeCommitFunc)   { mock.handler = cb }
-func (mock *mockBuildCommit) Use(cb buildCommitFunc)           { mock.handler = cb }
-func (mock *mockCreateBranch) Use(cb createBranchFunc)         { mock.handler = cb }
-func (mock *mockInspectBranch) Use(cb inspectBranchFunc)       { mock.handler = cb }
-func (mock *mockListBranch) Use(cb listBranchFunc)             { mock.handler = cb }
-func (mock *mockDeleteBranch) Use(cb deleteBranchFunc)         { mock.handler = cb }
-func (mock *mockPutFile) Use(cb putFileFunc)                   { mock.handler = cb }
-func (mock *mockCopyFile) Use(cb copyFileFunc)                 { mock.handler = cb }
-func (mock *mockGetFile) Use(cb getFileFunc)                   { mock.handler = cb }
-func (mock *mockInspectFile) Use(cb inspectFileFunc)           { mock.handler = cb }
-func (mock *mockListFile) Use(cb listFileFunc)                 { mock.handler = cb }
-func (mock *mockListFileStream) Use(cb listFileStreamFunc)     { mock.handler = cb }
-func (mock *mockWalkFile) Use(cb walkFileFunc)                 { mock.handler = cb }
-func (mock *mockGlobFile) Use(cb globFileFunc)                 { mock.handler = cb }
-func (mock *mockGlobFileStream) Use(cb globFileStreamFunc)     { mock.handler = cb }
-func (mock *mockDiffFile) Use(cb diffFileFunc)                 { mock.handler = cb }
-func (mock *mockDeleteFile) Use(cb deleteFileFunc)             { mock.handler = cb }
-func (mock *mockDeleteAllPFS) Use(cb deleteAllPFSFunc)         { mock.handler = cb }
-func (mock *mockFsck) Use(cb fsckFunc)                         { mock.handler = cb }
+func (mock *mockCreateRepo) Use(cb createRepoFunc)                     { mock.handler = cb }
+func (mock *mockInspectRepo) Use(cb inspectRepoFunc)                   { mock.handler = cb }
+func (mock *mockListRepo) Use(cb listRepoFunc)                         { mock.handler = cb }
+func (mock *mockDeleteRepo) Use(cb deleteRepoFunc)                     { mock.handler = cb }
+func (mock *mockStartCommit) Use(cb startCommitFunc)                   { mock.handler = cb }
+func (mock *mockFinishCommit) Use(cb finishCommitFunc)                 { mock.handler = cb }
+func (mock *mockInspectCommit) Use(cb inspectCommitFunc)               { mock.handler = cb }
+func (mock *mockListCommit) Use(cb listCommitFunc)                     { mock.handler = cb }
+func (mock *mockListCommitStream) Use(cb listCommitStreamFunc)         { mock.handler = cb }
+func (mock *mockDeleteCommit) Use(cb deleteCommitFunc)                 { mock.handler = cb }
+func (mock *mockExpireCommits) Use(cb expireCommitsFunc)               { mock.handler = cb }
+func (mock *mockFlushCommit) Use(cb flushCommitFunc)                   { mock.handler = cb }
+func (mock *mockSubscribeCommit) Use(cb subscribeCommitFunc)           { mock.handler = cb }
+func (mock *mockSubscribeFileChanges) Use(cb subscribeFileChangesFunc) { mock.handler = cb }
+func (mock *mockBuildCommit) Use(cb buildCommitFunc)                   { mock.handler = cb }
+func (mock *mockCreateBranch) Use(cb createBranchFunc)                 { mock.handler = cb }
+func (mock *mockInspectBranch) Use(cb inspectBranchFunc)               { mock.handler = cb }
+func (mock *mockListBranch) Use(cb listBranchFunc)                     { mock.handler = cb }
+func (mock *mockDeleteBranch) Use(cb deleteBranchFunc)                 { mock.handler = cb }
+func (mock *mockPutFile) Use(cb putFileFunc)                           { mock.handler = cb }
+func (mock *mockCopyFile) Use(cb copyFileFunc)                         { mock.handler = cb }
+func (mock *mockGetFile) Use(cb getFileFunc)                           { mock.handler = cb }
+func (mock *mockInspectFile) Use(cb inspectFileFunc)                   { mock.handler = cb }
+func (mock *mockListFile) Use(cb listFileFunc)                         { mock.handler = cb }
+func (mock *mockListFileStream) Use(cb listFileStreamFunc)             { mock.handler = cb }
+func (mock *mockWalkFile) Use(cb walkFileFunc)                         { mock.handler = cb }
+func (mock *mockGlobFile) Use(cb globFileFunc)                         { mock.handler = cb }
+func (mock *mockGlobFileStream) Use(cb globFileStreamFunc)             { mock.handler = cb }
+func (mock *mockDiffFile) Use(cb diffFileFunc)                         { mock.handler = cb }
+func (mock *mockSearchFiles) Use(cb searchFilesFunc)                   { mock.handler = cb }
+func (mock *mockDeleteFile) Use(cb deleteFileFunc)                     { mock.handler = cb }
+func (mock *mockDeleteAllPFS) Use(cb deleteAllPFSFunc)                 { mock.handler = cb }
+func (mock *mockFsck) Use(cb fsckFunc)                                 { mock.handler = cb }
 
 type pfsServerAPI struct {
 	mock *mockPFSServer
 }
 
 type mockPFSServer struct {
-	api              pfsServerAPI
-	CreateRepo       mockCreateRepo
-	InspectRepo      mockInspectRepo
-	ListRepo         mockListRepo
-	DeleteRepo       mockDeleteRepo
-	StartCommit      mockStartCommit
-	FinishCommit     mockFinishCommit
-	InspectCommit    mockInspectCommit
-	ListCommit       mockListCommit
-	ListCommitStream mockListCommitStream
-	DeleteCommit     mockDeleteCommit
-	FlushCommit      mockFlushCommit
-	SubscribeCommit  mockSubscribeCommit
-	BuildCommit      mockBuildCommit
-	CreateBranch     mockCreateBranch
-	InspectBranch    mockInspectBranch
-	ListBranch       mockListBranch
-	DeleteBranch     mockDeleteBranch
-	PutFile          mockPutFile
-	CopyFile         mockCopyFile
-	GetFile          mockGetFile
-	InspectFile      mockInspectFile
-	ListFile         mockListFile
-	ListFileStream   mockListFileStream
-	WalkFile         mockWalkFile
-	GlobFile         mockGlobFile
-	GlobFileStream   mockGlobFileStream
-	DiffFile         mockDiffFile
-	DeleteFile       mockDeleteFile
-	DeleteAll        mockDeleteAllPFS
-	Fsck             mockFsck
+	api                  pfsServerAPI
+	CreateRepo           mockCreateRepo
+	InspectRepo          mockInspectRepo
+	ListRepo             mockListRepo
+	DeleteRepo           mockDeleteRepo
+	StartCommit          mockStartCommit
+	FinishCommit         mockFinishCommit
+	InspectCommit        mockInspectCommit
+	ListCommit           mockListCommit
+	ListCommitStream     mockListCommitStream
+	DeleteCommit         mockDeleteCommit
+	ExpireCommits        mockExpireCommits
+	FlushCommit          mockFlushCommit
+	SubscribeCommit      mockSubscribeCommit
+	SubscribeFileChanges mockSubscribeFileChanges
+	BuildCommit          mockBuildCommit
+	CreateBranch         mockCreateBranch
+	InspectBranch        mockInspectBranch
+	ListBranch           mockListBranch
+	DeleteBranch         mockDeleteBranch
+	PutFile              mockPutFile
+	CopyFile             mockCopyFile
+	GetFile              mockGetFile
+	InspectFile          mockInspectFile
+	ListFile             mockListFile
+	ListFileStream       mockListFileStream
+	WalkFile             mockWalkFile
+	GlobFile             mockGlobFile
+	GlobFileStream       mockGlobFileStream
+	DiffFile             mockDiffFile
+	SearchFiles          mockSearchFiles
+	DeleteFile           mockDeleteFile
+	DeleteAll            mockDeleteAllPFS
+	Fsck                 mockFsck
 }
 
 func (api *pfsServerAPI) CreateRepo(ctx context.Context, req *pfs.CreateRepoRequest) (*types.Empty, error) {
@@ -557,6 +569,12 @@ func (api *pfsServerAPI) DeleteCommit(ctx context.Context, req *pfs.DeleteCommit
 	}
 	return nil, fmt.Errorf("unhandled pachd mock pfs.DeleteCommit")
 }
+func (api *pfsServerAPI) ExpireCommits(ctx context.Context, req *pfs.ExpireCommitsRequest) (*pfs.CommitInfos, error) {
+	if api.mock.ExpireCommits.handler != nil {
+		return api.mock.ExpireCommits.handler(ctx, req)
+	}
+	return nil, fmt.Errorf("unhandled pachd mock pfs.ExpireCommits")
+}
 func (api *pfsServerAPI) FlushCommit(req *pfs.FlushCommitRequest, serv pfs.API_FlushCommitServer) error {
 	if api.mock.FlushCommit.handler != nil {
 		return api.mock.FlushCommit.handler(req, serv)
@@ -569,6 +587,12 @@ func (api *pfsServerAPI) SubscribeCommit(req *pfs.SubscribeCommitRequest, serv p
 	}
 	return fmt.Errorf("unhandled pachd mock pfs.SubscribeCommit")
 }
+func (api *pfsServerAPI) SubscribeFileChanges(req *pfs.SubscribeFileChangesRequest, serv pfs.API_SubscribeFileChangesServer) error {
+	if api.mock.SubscribeFileChanges.handler != nil {
+		return api.mock.SubscribeFileChanges.handler(req, serv)
+	}
+	return fmt.Errorf("unhandled pachd mock pfs.SubscribeFileChanges")
+}
 func (api *pfsServerAPI) BuildCommit(ctx context.Context, req *pfs.BuildCommitRequest) (*pfs.Commit, error) {
 	if api.mock.BuildCommit.handler != nil {
 		return api.mock.BuildCommit.handler(ctx, req)
@@ -659,6 +683,12 @@ func (api *pfsServerAPI) DiffFile(ctx context.Context, req *pfs.DiffFileRequest)
 	}
 	return nil, fmt.Errorf("unhandled pachd mock pfs.DiffFile")
 }
+func (api *pfsServerAPI) SearchFiles(ctx context.Context, req *pfs.SearchFilesRequest) (*pfs.SearchFilesResponse, error) {
+	if api.mock.SearchFiles.handler != nil {
+		return api.mock.SearchFiles.handler(ctx, req)
+	}
+	return nil, fmt.Errorf("unhandled pachd mock pfs.SearchFiles")
+}
 func (api *pfsServerAPI) DeleteFile(ctx context.Context, req *pfs.DeleteFileRequest) (*types.Empty, error) {
 	if api.mock.DeleteFile.handler != nil {
 		return api.mock.DeleteFile.handler(ctx, req)
@@ -685,22 +715,31 @@ type inspectJobFunc func(context.Context, *pps.InspectJobRequest) (*pps.JobInfo,
 type listJobFunc func(context.Context, *pps.ListJobRequest) (*pps.JobInfos, error)
 type listJobStreamFunc func(*pps.ListJobRequest, pps.API_ListJobStreamServer) error
 type flushJobFunc func(*pps.FlushJobRequest, pps.API_FlushJobServer) error
+type waitJobFunc func(*pps.WaitJobRequest, pps.API_WaitJobServer) error
 type deleteJobFunc func(context.Context, *pps.DeleteJobRequest) (*types.Empty, error)
 type stopJobFunc func(context.Context, *pps.StopJobRequest) (*types.Empty, error)
+type pauseJobFunc func(context.Context, *pps.PauseJobRequest) (*types.Empty, error)
+type resumeJobFunc func(context.Context, *pps.ResumeJobRequest) (*types.Empty, error)
 type inspectDatumFunc func(context.Context, *pps.InspectDatumRequest) (*pps.DatumInfo, error)
+type inspectJobStatsFunc func(context.Context, *pps.InspectJobStatsRequest) (*pps.JobStats, error)
+type inspectJobAttestationFunc func(context.Context, *pps.InspectJobAttestationRequest) (*pps.JobAttestationInfo, error)
 type listDatumFunc func(context.Context, *pps.ListDatumRequest) (*pps.ListDatumResponse, error)
 type listDatumStreamFunc func(*pps.ListDatumRequest, pps.API_ListDatumStreamServer) error
 type restartDatumFunc func(context.Context, *pps.RestartDatumRequest) (*types.Empty, error)
+type restartJobFunc func(context.Context, *pps.RestartJobRequest) (*pps.RestartJobResponse, error)
 type createPipelineFunc func(context.Context, *pps.CreatePipelineRequest) (*types.Empty, error)
 type inspectPipelineFunc func(context.Context, *pps.InspectPipelineRequest) (*pps.PipelineInfo, error)
 type listPipelineFunc func(context.Context, *pps.ListPipelineRequest) (*pps.PipelineInfos, error)
 type deletePipelineFunc func(context.Context, *pps.DeletePipelineRequest) (*types.Empty, error)
 type startPipelineFunc func(context.Context, *pps.StartPipelineRequest) (*types.Empty, error)
 type stopPipelineFunc func(context.Context, *pps.StopPipelineRequest) (*types.Empty, error)
+type setWorkerConfigFunc func(context.Context, *pps.SetWorkerConfigRequest) (*types.Empty, error)
 type runPipelineFunc func(context.Context, *pps.RunPipelineRequest) (*types.Empty, error)
 type runCronFunc func(context.Context, *pps.RunCronRequest) (*types.Empty, error)
+type validatePipelineFunc func(context.Context, *pps.CreatePipelineRequest) (*pps.ValidatePipelineResponse, error)
 type deleteAllPPSFunc func(context.Context, *types.Empty) (*types.Empty, error)
 type getLogsFunc func(*pps.GetLogsRequest, pps.API_GetLogsServer) error
+type inspectDAGFunc func(context.Context, *pps.InspectDAGRequest) (*pps.DAG, error)
 type garbageCollectFunc func(context.Context, *pps.GarbageCollectRequest) (*pps.GarbageCollectResponse, error)
 type activateAuthPPSFunc func(context.Context, *pps.ActivateAuthRequest) (*pps.ActivateAuthResponse, error)
 
@@ -709,78 +748,105 @@ type mockInspectJob struct{ handler inspectJobFunc }
 type mockListJob struct{ handler listJobFunc }
 type mockListJobStream struct{ handler listJobStreamFunc }
 type mockFlushJob struct{ handler flushJobFunc }
+type mockWaitJob struct{ handler waitJobFunc }
 type mockDeleteJob struct{ handler deleteJobFunc }
 type mockStopJob struct{ handler stopJobFunc }
+type mockPauseJob struct{ handler pauseJobFunc }
+type mockResumeJob struct{ handler resumeJobFunc }
 type mockInspectDatum struct{ handler inspectDatumFunc }
+type mockInspectJobStats struct{ handler inspectJobStatsFunc }
+type mockInspectJobAttestation struct{ handler inspectJobAttestationFunc }
 type mockListDatum struct{ handler listDatumFunc }
 type mockListDatumStream struct{ handler listDatumStreamFunc }
 type mockRestartDatum struct{ handler restartDatumFunc }
+type mockRestartJob struct{ handler restartJobFunc }
 type mockCreatePipeline struct{ handler createPipelineFunc }
 type mockInspectPipeline struct{ handler inspectPipelineFunc }
 type mockListPipeline struct{ handler listPipelineFunc }
 type mockDeletePipeline struct{ handler deletePipelineFunc }
 type mockStartPipeline struct{ handler startPipelineFunc }
 type mockStopPipeline struct{ handler stopPipelineFunc }
+type mockSetWorkerConfig struct{ handler setWorkerConfigFunc }
 type mockRunPipeline struct{ handler runPipelineFunc }
 type mockRunCron struct{ handler runCronFunc }
+type mockValidatePipeline struct{ handler validatePipelineFunc }
 type mockDeleteAllPPS struct{ handler deleteAllPPSFunc }
 type mockGetLogs struct{ handler getLogsFunc }
+type mockInspectDAG struct{ handler inspectDAGFunc }
 type mockGarbageCollect struct{ handler garbageCollectFunc }
 type mockActivateAuthPPS struct{ handler activateAuthPPSFunc }
 
-func (mock *mockCreateJob) Use(cb createJobFunc)             { mock.handler = cb }
-func (mock *mockInspectJob) Use(cb inspectJobFunc)           { mock.handler = cb }
-func (mock *mockListJob) Use(cb listJobFunc)                 { mock.handler = cb }
-func (mock *mockListJobStream) Use(cb listJobStreamFunc)     { mock.handler = cb }
-func (mock *mockFlushJob) Use(cb flushJobFunc)               { mock.handler = cb }
-func (mock *mockDeleteJob) Use(cb deleteJobFunc)             { mock.handler = cb }
-func (mock *mockStopJob) Use(cb stopJobFunc)                 { mock.handler = cb }
-func (mock *mockInspectDatum) Use(cb inspectDatumFunc)       { mock.handler = cb }
-func (mock *mockListDatum) Use(cb listDatumFunc)             { mock.handler = cb }
-func (mock *mockListDatumStream) Use(cb listDatumStreamFunc) { mock.handler = cb }
-func (mock *mockRestartDatum) Use(cb restartDatumFunc)       { mock.handler = cb }
-func (mock *mockCreatePipeline) Use(cb createPipelineFunc)   { mock.handler = cb }
-func (mock *mockInspectPipeline) Use(cb inspectPipelineFunc) { mock.handler = cb }
-func (mock *mockListPipeline) Use(cb listPipelineFunc)       { mock.handler = cb }
-func (mock *mockDeletePipeline) Use(cb deletePipelineFunc)   { mock.handler = cb }
-func (mock *mockStartPipeline) Use(cb startPipelineFunc)     { mock.handler = cb }
-func (mock *mockStopPipeline) Use(cb stopPipelineFunc)       { mock.handler = cb }
-func (mock *mockRunPipeline) Use(cb runPipelineFunc)         { mock.handler = cb }
-func (mock *mockRunCron) Use(cb runCronFunc)                 { mock.handler = cb }
-func (mock *mockDeleteAllPPS) Use(cb deleteAllPPSFunc)       { mock.handler = cb }
-func (mock *mockGetLogs) Use(cb getLogsFunc)                 { mock.handler = cb }
-func (mock *mockGarbageCollect) Use(cb garbageCollectFunc)   { mock.handler = cb }
-func (mock *mockActivateAuthPPS) Use(cb activateAuthPPSFunc) { mock.handler = cb }
+func (mock *mockCreateJob) Use(cb createJobFunc)                         { mock.handler = cb }
+func (mock *mockInspectJob) Use(cb inspectJobFunc)                       { mock.handler = cb }
+func (mock *mockListJob) Use(cb listJobFunc)                             { mock.handler = cb }
+func (mock *mockListJobStream) Use(cb listJobStreamFunc)                 { mock.handler = cb }
+func (mock *mockFlushJob) Use(cb flushJobFunc)                           { mock.handler = cb }
+func (mock *mockWaitJob) Use(cb waitJobFunc)                             { mock.handler = cb }
+func (mock *mockDeleteJob) Use(cb deleteJobFunc)                         { mock.handler = cb }
+func (mock *mockStopJob) Use(cb stopJobFunc)                             { mock.handler = cb }
+func (mock *mockPauseJob) Use(cb pauseJobFunc)                           { mock.handler = cb }
+func (mock *mockResumeJob) Use(cb resumeJobFunc)                         { mock.handler = cb }
+func (mock *mockInspectDatum) Use(cb inspectDatumFunc)                   { mock.handler = cb }
+func (mock *mockInspectJobStats) Use(cb inspectJobStatsFunc)             { mock.handler = cb }
+func (mock *mockInspectJobAttestation) Use(cb inspectJobAttestationFunc) { mock.handler = cb }
+func (mock *mockListDatum) Use(cb listDatumFunc)                         { mock.handler = cb }
+func (mock *mockListDatumStream) Use(cb listDatumStreamFunc)             { mock.handler = cb }
+func (mock *mockRestartDatum) Use(cb restartDatumFunc)                   { mock.handler = cb }
+func (mock *mockRestartJob) Use(cb restartJobFunc)                       { mock.handler = cb }
+func (mock *mockCreatePipeline) Use(cb createPipelineFunc)               { mock.handler = cb }
+func (mock *mockInspectPipeline) Use(cb inspectPipelineFunc)             { mock.handler = cb }
+func (mock *mockListPipeline) Use(cb listPipelineFunc)                   { mock.handler = cb }
+func (mock *mockDeletePipeline) Use(cb deletePipelineFunc)               { mock.handler = cb }
+func (mock *mockStartPipeline) Use(cb startPipelineFunc)                 { mock.handler = cb }
+func (mock *mockStopPipeline) Use(cb stopPipelineFunc)                   { mock.handler = cb }
+func (mock *mockSetWorkerConfig) Use(cb setWorkerConfigFunc)             { mock.handler = cb }
+func (mock *mockRunPipeline) Use(cb runPipelineFunc)                     { mock.handler = cb }
+func (mock *mockRunCron) Use(cb runCronFunc)                             { mock.handler = cb }
+func (mock *mockValidatePipeline) Use(cb validatePipelineFunc)           { mock.handler = cb }
+func (mock *mockDeleteAllPPS) Use(cb deleteAllPPSFunc)                   { mock.handler = cb }
+func (mock *mockGetLogs) Use(cb getLogsFunc)                             { mock.handler = cb }
+func (mock *mockInspectDAG) Use(cb inspectDAGFunc)                       { mock.handler = cb }
+func (mock *mockGarbageCollect) Use(cb garbageCollectFunc)               { mock.handler = cb }
+func (mock *mockActivateAuthPPS) Use(cb activateAuthPPSFunc)             { mock.handler = cb }
 
 type ppsServerAPI struct {
 	mock *mockPPSServer
 }
 
 type mockPPSServer struct {
-	api             ppsServerAPI
-	CreateJob       mockCreateJob
-	InspectJob      mockInspectJob
-	ListJob         mockListJob
-	ListJobStream   mockListJobStream
-	FlushJob        mockFlushJob
-	DeleteJob       mockDeleteJob
-	StopJob         mockStopJob
-	InspectDatum    mockInspectDatum
-	ListDatum       mockListDatum
-	ListDatumStream mockListDatumStream
-	RestartDatum    mockRestartDatum
-	CreatePipeline  mockCreatePipeline
-	InspectPipeline mockInspectPipeline
-	ListPipeline    mockListPipeline
-	DeletePipeline  mockDeletePipeline
-	StartPipeline   mockStartPipeline
-	StopPipeline    mockStopPipeline
-	RunPipeline     mockRunPipeline
-	RunCron         mockRunCron
-	DeleteAll       mockDeleteAllPPS
-	GetLogs         mockGetLogs
-	GarbageCollect  mockGarbageCollect
-	ActivateAuth    mockActivateAuthPPS
+	api                   ppsServerAPI
+	CreateJob             mockCreateJob
+	InspectJob            mockInspectJob
+	ListJob               mockListJob
+	ListJobStream         mockListJobStream
+	FlushJob              mockFlushJob
+	WaitJob               mockWaitJob
+	DeleteJob             mockDeleteJob
+	StopJob               mockStopJob
+	PauseJob              mockPauseJob
+	ResumeJob             mockResumeJob
+	InspectDatum          mockInspectDatum
+	InspectJobStats       mockInspectJobStats
+	InspectJobAttestation mockInspectJobAttestation
+	ListDatum             mockListDatum
+	ListDatumStream       mockListDatumStream
+	RestartDatum          mockRestartDatum
+	RestartJob            mockRestartJob
+	CreatePipeline        mockCreatePipeline
+	InspectPipeline       mockInspectPipeline
+	ListPipeline          mockListPipeline
+	DeletePipeline        mockDeletePipeline
+	StartPipeline         mockStartPipeline
+	StopPipeline          mockStopPipeline
+	SetWorkerConfig       mockSetWorkerConfig
+	RunPipeline           mockRunPipeline
+	RunCron               mockRunCron
+	ValidatePipeline      mockValidatePipeline
+	DeleteAll             mockDeleteAllPPS
+	GetLogs               mockGetLogs
+	InspectDAG            mockInspectDAG
+	GarbageCollect        mockGarbageCollect
+	ActivateAuth          mockActivateAuthPPS
 }
 
 func (api *ppsServerAPI) CreateJob(ctx context.Context, req *pps.CreateJobRequest) (*pps.Job, error) {
@@ -813,6 +879,12 @@ func (api *ppsServerAPI) FlushJob(req *pps.FlushJobRequest, serv pps.API_FlushJo
 	}
 	return fmt.Errorf("unhandled pachd mock pps.FlushJob")
 }
+func (api *ppsServerAPI) WaitJob(req *pps.WaitJobRequest, serv pps.API_WaitJobServer) error {
+	if api.mock.WaitJob.handler != nil {
+		return api.mock.WaitJob.handler(req, serv)
+	}
+	return fmt.Errorf("unhandled pachd mock pps.WaitJob")
+}
 func (api *ppsServerAPI) DeleteJob(ctx context.Context, req *pps.DeleteJobRequest) (*types.Empty, error) {
 	if api.mock.DeleteJob.handler != nil {
 		return api.mock.DeleteJob.handler(ctx, req)
@@ -825,12 +897,36 @@ func (api *ppsServerAPI) StopJob(ctx context.Context, req *pps.StopJobRequest) (
 	}
 	return nil, fmt.Errorf("unhandled pachd mock pps.StopJob")
 }
+func (api *ppsServerAPI) PauseJob(ctx context.Context, req *pps.PauseJobRequest) (*types.Empty, error) {
+	if api.mock.PauseJob.handler != nil {
+		return api.mock.PauseJob.handler(ctx, req)
+	}
+	return nil, fmt.Errorf("unhandled pachd mock pps.PauseJob")
+}
+func (api *ppsServerAPI) ResumeJob(ctx context.Context, req *pps.ResumeJobRequest) (*types.Empty, error) {
+	if api.mock.ResumeJob.handler != nil {
+		return api.mock.ResumeJob.handler(ctx, req)
+	}
+	return nil, fmt.Errorf("unhandled pachd mock pps.ResumeJob")
+}
 func (api *ppsServerAPI) InspectDatum(ctx context.Context, req *pps.InspectDatumRequest) (*pps.DatumInfo, error) {
 	if api.mock.InspectDatum.handler != nil {
 		return api.mock.InspectDatum.handler(ctx, req)
 	}
 	return nil, fmt.Errorf("unhandled pachd mock pps.InspectDatum")
 }
+func (api *ppsServerAPI) InspectJobStats(ctx context.Context, req *pps.InspectJobStatsRequest) (*pps.JobStats, error) {
+	if api.mock.InspectJobStats.handler != nil {
+		return api.mock.InspectJobStats.handler(ctx, req)
+	}
+	return nil, fmt.Errorf("unhandled pachd mock pps.InspectJobStats")
+}
+func (api *ppsServerAPI) InspectJobAttestation(ctx context.Context, req *pps.InspectJobAttestationRequest) (*pps.JobAttestationInfo, error) {
+	if api.mock.InspectJobAttestation.handler != nil {
+		return api.mock.InspectJobAttestation.handler(ctx, req)
+	}
+	return nil, fmt.Errorf("unhandled pachd mock pps.InspectJobAttestation")
+}
 func (api *ppsServerAPI) ListDatum(ctx context.Context, req *pps.ListDatumRequest) (*pps.ListDatumResponse, error) {
 	if api.mock.ListDatum.handler != nil {
 		return api.mock.ListDatum.handler(ctx, req)
@@ -849,6 +945,12 @@ func (api *ppsServerAPI) RestartDatum(ctx context.Context, req *pps.RestartDatum
 	}
 	return nil, fmt.Errorf("unhandled pachd mock pps.RestartDatum")
 }
+func (api *ppsServerAPI) RestartJob(ctx context.Context, req *pps.RestartJobRequest) (*pps.RestartJobResponse, error) {
+	if api.mock.RestartJob.handler != nil {
+		return api.mock.RestartJob.handler(ctx, req)
+	}
+	return nil, fmt.Errorf("unhandled pachd mock pps.RestartJob")
+}
 func (api *ppsServerAPI) CreatePipeline(ctx context.Context, req *pps.CreatePipelineRequest) (*types.Empty, error) {
 	if api.mock.CreatePipeline.handler != nil {
 		return api.mock.CreatePipeline.handler(ctx, req)
@@ -885,6 +987,12 @@ func (api *ppsServerAPI) StopPipeline(ctx context.Context, req *pps.StopPipeline
 	}
 	return nil, fmt.Errorf("unhandled pachd mock pps.StopPipeline")
 }
+func (api *ppsServerAPI) SetWorkerConfig(ctx context.Context, req *pps.SetWorkerConfigRequest) (*types.Empty, error) {
+	if api.mock.SetWorkerConfig.handler != nil {
+		return api.mock.SetWorkerConfig.handler(ctx, req)
+	}
+	return nil, fmt.Errorf("unhandled pachd mock pps.SetWorkerConfig")
+}
 func (api *ppsServerAPI) RunPipeline(ctx context.Context, req *pps.RunPipelineRequest) (*types.Empty, error) {
 	if api.mock.RunPipeline.handler != nil {
 		return api.mock.RunPipeline.handler(ctx, req)
@@ -897,6 +1005,12 @@ func (api *ppsServerAPI) RunCron(ctx context.Context, req *pps.RunCronRequest) (
 	}
 	return nil, fmt.Errorf("unhandled pachd mock pps.RunCron")
 }
+func (api *ppsServerAPI) ValidatePipeline(ctx context.Context, req *pps.CreatePipelineRequest) (*pps.ValidatePipelineResponse, error) {
+	if api.mock.ValidatePipeline.handler != nil {
+		return api.mock.ValidatePipeline.handler(ctx, req)
+	}
+	return nil, fmt.Errorf("unhandled pachd mock pps.ValidatePipeline")
+}
 func (api *ppsServerAPI) DeleteAll(ctx context.Context, req *types.Empty) (*types.Empty, error) {
 	if api.mock.DeleteAll.handler != nil {
 		return api.mock.DeleteAll.handler(ctx, req)
@@ -909,6 +1023,12 @@ func (api *ppsServerAPI) GetLogs(req *pps.GetLogsRequest, serv pps.API_GetLogsSe
 	}
 	return fmt.Errorf("unhandled pachd mock pps.GetLogs")
 }
+func (api *ppsServerAPI) InspectDAG(ctx context.Context, req *pps.InspectDAGRequest) (*pps.DAG, error) {
+	if api.mock.InspectDAG.handler != nil {
+		return api.mock.InspectDAG.handler(ctx, req)
+	}
+	return nil, fmt.Errorf("unhandled pachd mock pps.InspectDAG")
+}
 func (api *ppsServerAPI) GarbageCollect(ctx context.Context, req *pps.GarbageCollectRequest) (*pps.GarbageCollectResponse, error) {
 	if api.mock.GarbageCollect.handler != nil {
 		return api.mock.GarbageCollect.handler(ctx, req)
@@ -1042,6 +1162,7 @@ type inspectTagFunc func(context.Context, *pfs.Tag) (*pfs.ObjectInfo, error)
 type listTagsFunc func(*pfs.ListTagsRequest, pfs.ObjectAPI_ListTagsServer) error
 type deleteTagsFunc func(context.Context, *pfs.DeleteTagsRequest) (*pfs.DeleteTagsResponse, error)
 type compactFunc func(context.Context, *types.Empty) (*types.Empty, error)
+type packObjectsFunc func(context.Context, *pfs.PackObjectsRequest) (*pfs.PackObjectsResponse, error)
 
 type mockPutObject struct{ handler putObjectFunc }
 type mockPutObjectSplit struct{ handler putObjectSplitFunc }
@@ -1063,6 +1184,7 @@ type mockInspectTag struct{ handler inspectTagFunc }
 type mockListTags struct{ handler listTagsFunc }
 type mockDeleteTags struct{ handler deleteTagsFunc }
 type mockCompact struct{ handler compactFunc }
+type mockPackObjects struct{ handler packObjectsFunc }
 
 func (mock *mockPutObject) Use(cb putObjectFunc)           { mock.handler = cb }
 func (mock *mockPutObjectSplit) Use(cb putObjectSplitFunc) { mock.handler = cb }
@@ -1084,6 +1206,7 @@ func (mock *mockInspectTag) Use(cb inspectTagFunc)         { mock.handler = cb }
 func (mock *mockListTags) Use(cb listTagsFunc)             { mock.handler = cb }
 func (mock *mockDeleteTags) Use(cb deleteTagsFunc)         { mock.handler = cb }
 func (mock *mockCompact) Use(cb compactFunc)               { mock.handler = cb }
+func (mock *mockPackObjects) Use(cb packObjectsFunc)       { mock.handler = cb }
 
 type objectServerAPI struct {
 	mock *mockObjectServer
@@ -1111,6 +1234,7 @@ type mockObjectServer struct {
 	ListTags       mockListTags
 	DeleteTags     mockDeleteTags
 	Compact        mockCompact
+	PackObjects    mockPackObjects
 }
 
 func (api *objectServerAPI) PutObject(serv pfs.ObjectAPI_PutObjectServer) error {
@@ -1233,6 +1357,12 @@ func (api *objectServerAPI) Compact(ctx context.Context, req *types.Empty) (*typ
 	}
 	return nil, fmt.Errorf("unhandled pachd mock object.Compact")
 }
+func (api *objectServerAPI) PackObjects(ctx context.Context, req *pfs.PackObjectsRequest) (*pfs.PackObjectsResponse, error) {
+	if api.mock.PackObjects.handler != nil {
+		return api.mock.PackObjects.handler(ctx, req)
+	}
+	return nil, fmt.Errorf("unhandled pachd mock object.PackObjects")
+}
 
 // MockPachd provides an interface for running the interface for a Pachd API
 // server locally without any of its dependencies. Tests may mock out specific
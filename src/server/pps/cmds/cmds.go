@@ -10,15 +10,19 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"syscall"
+	"time"
 
 	pachdclient "github.com/pachyderm/pachyderm/src/client"
 	"github.com/pachyderm/pachyderm/src/client/pfs"
 	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
 	"github.com/pachyderm/pachyderm/src/client/pkg/tracing/extended"
 	ppsclient "github.com/pachyderm/pachyderm/src/client/pps"
+	pfspretty "github.com/pachyderm/pachyderm/src/server/pfs/pretty"
 	"github.com/pachyderm/pachyderm/src/server/pkg/cmdutil"
+	"github.com/pachyderm/pachyderm/src/server/pkg/errutil"
 	"github.com/pachyderm/pachyderm/src/server/pkg/pager"
 	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
 	"github.com/pachyderm/pachyderm/src/server/pkg/serde"
@@ -85,6 +89,8 @@ If the job fails, the output commit will not be populated with data.`,
 	commands = append(commands, cmdutil.CreateDocsAlias(jobDocs, "job", " job$"))
 
 	var block bool
+	var rawStats bool
+	var attestation bool
 	inspectJob := &cobra.Command{
 		Use:   "{{alias}} <job>",
 		Short: "Return info about a job.",
@@ -102,6 +108,32 @@ If the job fails, the output commit will not be populated with data.`,
 			if jobInfo == nil {
 				cmdutil.ErrorAndExit("job %s not found.", args[0])
 			}
+			if rawStats {
+				jobStats, err := client.InspectJobStats(jobInfo.Job.ID)
+				if err != nil {
+					cmdutil.ErrorAndExit("error from InspectJobStats: %s", err.Error())
+				}
+				if raw {
+					return encoder(output).EncodeProto(jobStats)
+				} else if output != "" {
+					cmdutil.ErrorAndExit("cannot set --output (-o) without --raw")
+				}
+				pretty.PrintDetailedJobStats(os.Stdout, jobStats)
+				return nil
+			}
+			if attestation {
+				attestationInfo, err := client.InspectJobAttestation(jobInfo.Job.ID)
+				if err != nil {
+					cmdutil.ErrorAndExit("error from InspectJobAttestation: %s", err.Error())
+				}
+				if raw {
+					return encoder(output).EncodeProto(attestationInfo)
+				} else if output != "" {
+					cmdutil.ErrorAndExit("cannot set --output (-o) without --raw")
+				}
+				pretty.PrintJobAttestationInfo(os.Stdout, attestationInfo)
+				return nil
+			}
 			if raw {
 				return encoder(output).EncodeProto(jobInfo)
 			} else if output != "" {
@@ -115,6 +147,8 @@ If the job fails, the output commit will not be populated with data.`,
 		}),
 	}
 	inspectJob.Flags().BoolVarP(&block, "block", "b", false, "block until the job has either succeeded or failed")
+	inspectJob.Flags().BoolVar(&rawStats, "raw-stats", false, "print the download, process and upload times, sizes and failures of the job's datums, aggregated from its stats commit")
+	inspectJob.Flags().BoolVar(&attestation, "attestation", false, "verify and print the job's attestation, which records its input commits, image, command and output")
 	inspectJob.Flags().AddFlagSet(rawFlags)
 	inspectJob.Flags().AddFlagSet(fullTimestampsFlags)
 	inspectJob.Flags().AddFlagSet(outputFlags)
@@ -199,16 +233,22 @@ $ {{alias}} -p foo -i bar@YYY`,
 	commands = append(commands, cmdutil.CreateAlias(listJob, "list job"))
 
 	var pipelines cmdutil.RepeatedStringArg
+	var progress bool
 	flushJob := &cobra.Command{
 		Use:   "{{alias}} <repo>@<branch-or-commit> ...",
 		Short: "Wait for all jobs caused by the specified commits to finish and return them.",
-		Long:  "Wait for all jobs caused by the specified commits to finish and return them.",
+		Long: `Wait for all jobs caused by the specified commits to finish and return them.
+
+With --progress, the progress of each job (its datums and the bytes that it has downloaded and uploaded) is shown as it runs, in the order of the pipelines in the DAG. pachctl then exits with a non-zero status, after summarizing why, if a job doesn't succeed. With --raw, a JobInfo is printed each time a job's state or progress changes.`,
 		Example: `
 # Return jobs caused by foo@XXX and bar@YYY.
 $ {{alias}} foo@XXX bar@YYY
 
 # Return jobs caused by foo@XXX leading to pipelines bar and baz.
-$ {{alias}} foo@XXX -p bar -p baz`,
+$ {{alias}} foo@XXX -p bar -p baz
+
+# Show the progress of the jobs caused by foo@XXX as they run.
+$ {{alias}} foo@XXX --progress`,
 		Run: cmdutil.Run(func(args []string) error {
 			commits, err := cmdutil.ParseCommits(args)
 			if err != nil {
@@ -221,6 +261,18 @@ $ {{alias}} foo@XXX -p bar -p baz`,
 			}
 			defer c.Close()
 
+			if progress {
+				var view *jobProgressView
+				if !raw {
+					view, err = newJobProgressView(c, os.Stdout, terminal.IsTerminal(int(os.Stdout.Fd())), fullTimestamps)
+					if err != nil {
+						return err
+					}
+				}
+				return waitForJobs(c, 0, raw, output, fullTimestamps, view, func(c *pachdclient.APIClient, f func(*ppsclient.JobInfo) error) error {
+					return c.FlushJobProgress(commits, pipelines, f)
+				})
+			}
 			jobInfos, err := c.FlushJobAll(commits, pipelines)
 			if err != nil {
 				return err
@@ -247,11 +299,78 @@ $ {{alias}} foo@XXX -p bar -p baz`,
 	}
 	flushJob.Flags().VarP(&pipelines, "pipeline", "p", "Wait only for jobs leading to a specific set of pipelines")
 	flushJob.MarkFlagCustom("pipeline", "__pachctl_get_pipeline")
+	flushJob.Flags().BoolVar(&progress, "progress", false, "Show the progress of the jobs as they run, rather than just returning them once they finish.")
 	flushJob.Flags().AddFlagSet(rawFlags)
 	flushJob.Flags().AddFlagSet(fullTimestampsFlags)
 	flushJob.Flags().AddFlagSet(outputFlags)
 	commands = append(commands, cmdutil.CreateAlias(flushJob, "flush job"))
 
+	var downstream bool
+	var timeout time.Duration
+	waitJob := &cobra.Command{
+		Use:   "{{alias}} <job>",
+		Short: "Wait for a job to finish.",
+		Long: `Wait for a job to finish, printing each state that it enters. pachctl exits with a non-zero status, after summarizing why, if the job doesn't succeed.
+
+With --downstream, the jobs that process the job's output, directly or indirectly, are waited for as well.`,
+		Example: `
+# Wait for job XXX to finish
+$ {{alias}} XXX
+
+# Wait for job XXX and the jobs downstream of it, for at most an hour
+$ {{alias}} XXX --downstream --timeout 1h`,
+		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
+			c, err := pachdclient.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			return waitForJobs(c, timeout, raw, output, fullTimestamps, nil, func(c *pachdclient.APIClient, f func(*ppsclient.JobInfo) error) error {
+				return c.WaitJob(args[0], downstream, f)
+			})
+		}),
+	}
+	waitJob.Flags().BoolVar(&downstream, "downstream", false, "Also wait for the jobs downstream of the job.")
+	waitJob.Flags().DurationVar(&timeout, "timeout", 0, "Return an error if the jobs haven't finished after this long, e.g. 30m (0 waits forever).")
+	waitJob.Flags().AddFlagSet(rawFlags)
+	waitJob.Flags().AddFlagSet(fullTimestampsFlags)
+	waitJob.Flags().AddFlagSet(outputFlags)
+	commands = append(commands, cmdutil.CreateAlias(waitJob, "wait job"))
+
+	waitCommit := &cobra.Command{
+		Use:   "{{alias}} <repo>@<branch-or-commit>",
+		Short: "Wait for the job that creates a commit to finish.",
+		Long: `Wait for the job whose output commit is the specified commit to finish, printing each state that it enters. pachctl exits with a non-zero status, after summarizing why, if the job doesn't succeed.
+
+With --downstream, the jobs downstream of the commit are waited for as well. This waits for all of the jobs that process an input commit, e.g. in CI after putting data into a repo.`,
+		Example: `
+# Wait for the job that creates the head commit of edges@master
+$ {{alias}} edges@master
+
+# Wait for all of the jobs that process the head commit of images@master
+$ {{alias}} images@master --downstream`,
+		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
+			commit, err := cmdutil.ParseCommit(args[0])
+			if err != nil {
+				return err
+			}
+			c, err := pachdclient.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			return waitForJobs(c, timeout, raw, output, fullTimestamps, nil, func(c *pachdclient.APIClient, f func(*ppsclient.JobInfo) error) error {
+				return c.WaitCommit(commit.Repo.Name, commit.ID, downstream, f)
+			})
+		}),
+	}
+	waitCommit.Flags().BoolVar(&downstream, "downstream", false, "Also wait for the jobs downstream of the commit.")
+	waitCommit.Flags().DurationVar(&timeout, "timeout", 0, "Return an error if the jobs haven't finished after this long, e.g. 30m (0 waits forever).")
+	waitCommit.Flags().AddFlagSet(rawFlags)
+	waitCommit.Flags().AddFlagSet(fullTimestampsFlags)
+	waitCommit.Flags().AddFlagSet(outputFlags)
+	commands = append(commands, cmdutil.CreateAlias(waitCommit, "wait commit"))
+
 	deleteJob := &cobra.Command{
 		Use:   "{{alias}} <job>",
 		Short: "Delete a job.",
@@ -288,6 +407,44 @@ $ {{alias}} foo@XXX -p bar -p baz`,
 	}
 	commands = append(commands, cmdutil.CreateAlias(stopJob, "stop job"))
 
+	pauseJob := &cobra.Command{
+		Use:   "{{alias}} <job>",
+		Short: "Pause a job.",
+		Long: `Pause a job. The job's workers finish the datums they're processing, but
+don't start any more until the job is resumed with 'pachctl resume job'. The
+datums that finished aren't processed again.`,
+		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
+			client, err := pachdclient.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+			if err := client.PauseJob(args[0]); err != nil {
+				cmdutil.ErrorAndExit("error from PauseJob: %s", err.Error())
+			}
+			return nil
+		}),
+	}
+	commands = append(commands, cmdutil.CreateAlias(pauseJob, "pause job"))
+
+	resumeJob := &cobra.Command{
+		Use:   "{{alias}} <job>",
+		Short: "Resume a paused job.",
+		Long:  "Resume a paused job. Its workers pick up the datums that haven't been processed.",
+		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
+			client, err := pachdclient.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+			if err := client.ResumeJob(args[0]); err != nil {
+				cmdutil.ErrorAndExit("error from ResumeJob: %s", err.Error())
+			}
+			return nil
+		}),
+	}
+	commands = append(commands, cmdutil.CreateAlias(resumeJob, "resume job"))
+
 	datumDocs := &cobra.Command{
 		Short: "Docs for datums.",
 		Long: `Datums are the small independent units of processing for Pachyderm jobs.
@@ -327,12 +484,53 @@ each datum.`,
 	}
 	commands = append(commands, cmdutil.CreateAlias(restartDatum, "restart datum"))
 
+	restartJob := &cobra.Command{
+		Use:   "{{alias}} <job>",
+		Short: "Restart the failed datums of a job.",
+		Long: `Restart the failed datums of a job.
+
+This reruns a failed or killed job over the same input commits, as a new job.
+Only the datums that the job didn't process successfully (because they failed,
+or because the job stopped before it reached them) are processed again, while
+the output of the rest is reused. The new job's output commit is printed.`,
+		Example: `
+# restart the failed datums of job "XXX", and find the new job
+$ {{alias}} XXX
+$ pachctl list job --output <commit printed above>`,
+		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
+			client, err := pachdclient.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+			commit, err := client.RestartJob(args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Println(pfspretty.CompactPrintCommit(commit))
+			return nil
+		}),
+	}
+	commands = append(commands, cmdutil.CreateAlias(restartJob, "restart job"))
+
 	var pageSize int64
 	var page int64
+	var states []string
+	var inputGlob string
+	var summaryOnly bool
 	listDatum := &cobra.Command{
 		Use:   "{{alias}} <job>",
 		Short: "Return the datums in a job.",
 		Long:  "Return the datums in a job.",
+		Example: `
+# return the datums in job "XXX"
+$ {{alias}} XXX
+
+# return the failed datums in job "XXX" with an input file under /images
+$ {{alias}} XXX --state failed --input "/images/*"
+
+# return only the number of datums in job "XXX" in each state
+$ {{alias}} XXX --summary`,
 		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
 			client, err := pachdclient.NewOnUserMachine("user")
 			if err != nil {
@@ -345,16 +543,49 @@ each datum.`,
 			if page < 0 {
 				return fmt.Errorf("page must be zero or positive")
 			}
+			req := &ppsclient.ListDatumRequest{
+				Job:       pachdclient.NewJob(args[0]),
+				PageSize:  pageSize,
+				Page:      page,
+				InputGlob: inputGlob,
+			}
+			for _, state := range states {
+				s, ok := ppsclient.DatumState_value[strings.ToUpper(state)]
+				if !ok {
+					return fmt.Errorf("invalid datum state %q, must be one of failed, success, skipped, starting or recovered", state)
+				}
+				req.State = append(req.State, ppsclient.DatumState(s))
+			}
+			if summaryOnly {
+				summary, err := client.ListDatumFilterF(req, func(*ppsclient.DatumInfo) error {
+					return errutil.ErrBreak
+				})
+				if err != nil {
+					return err
+				}
+				if summary == nil {
+					summary = &ppsclient.DatumSummary{}
+				}
+				if raw {
+					return encoder(output).EncodeProto(summary)
+				} else if output != "" {
+					cmdutil.ErrorAndExit("cannot set --output (-o) without --raw")
+				}
+				writer := tabwriter.NewWriter(os.Stdout, "")
+				pretty.PrintDatumSummary(writer, summary)
+				return writer.Flush()
+			}
 			if raw {
 				e := encoder(output)
-				return client.ListDatumF(args[0], pageSize, page, func(di *ppsclient.DatumInfo) error {
+				_, err := client.ListDatumFilterF(req, func(di *ppsclient.DatumInfo) error {
 					return e.EncodeProto(di)
 				})
+				return err
 			} else if output != "" {
 				cmdutil.ErrorAndExit("cannot set --output (-o) without --raw")
 			}
 			writer := tabwriter.NewWriter(os.Stdout, pretty.DatumHeader)
-			if err := client.ListDatumF(args[0], pageSize, page, func(di *ppsclient.DatumInfo) error {
+			if _, err := client.ListDatumFilterF(req, func(di *ppsclient.DatumInfo) error {
 				pretty.PrintDatumInfo(writer, di)
 				return nil
 			}); err != nil {
@@ -365,6 +596,9 @@ each datum.`,
 	}
 	listDatum.Flags().Int64Var(&pageSize, "pageSize", 0, "Specify the number of results sent back in a single page")
 	listDatum.Flags().Int64Var(&page, "page", 0, "Specify the page of results to send")
+	listDatum.Flags().StringSliceVar(&states, "state", nil, "Return only the datums in these states (failed, success, skipped, starting or recovered)")
+	listDatum.Flags().StringVar(&inputGlob, "input", "", "Return only the datums with an input file whose path matches this glob")
+	listDatum.Flags().BoolVar(&summaryOnly, "summary", false, "Return only the number of matching datums in each state")
 	listDatum.Flags().AddFlagSet(rawFlags)
 	listDatum.Flags().AddFlagSet(outputFlags)
 	commands = append(commands, cmdutil.CreateAlias(listDatum, "list datum"))
@@ -491,11 +725,12 @@ All jobs created by a pipeline will create commits in the pipeline's output repo
 	var registry string
 	var username string
 	var pipelinePath string
+	var dryRun bool
 	createPipeline := &cobra.Command{
 		Short: "Create a new pipeline.",
 		Long:  "Create a new pipeline from a pipeline specification. For details on the format, see http://docs.pachyderm.io/en/latest/reference/pipeline_spec.html.",
 		Run: cmdutil.RunFixedArgs(0, func(args []string) (retErr error) {
-			return pipelineHelper(false, build, pushImages, registry, username, pipelinePath, false)
+			return pipelineHelper(false, build, pushImages, registry, username, pipelinePath, false, dryRun)
 		}),
 	}
 	createPipeline.Flags().StringVarP(&pipelinePath, "file", "f", "-", "The JSON file containing the pipeline, it can be a url or local file. - reads from stdin.")
@@ -503,6 +738,7 @@ All jobs created by a pipeline will create commits in the pipeline's output repo
 	createPipeline.Flags().BoolVarP(&pushImages, "push-images", "p", false, "If true, push local docker images into the docker registry.")
 	createPipeline.Flags().StringVarP(&registry, "registry", "r", "index.docker.io", "The registry to push images to.")
 	createPipeline.Flags().StringVarP(&username, "username", "u", "", "The username to push images as.")
+	createPipeline.Flags().BoolVar(&dryRun, "dry-run", false, "If true, report the problems with the pipeline specification without creating the pipeline.")
 	commands = append(commands, cmdutil.CreateAlias(createPipeline, "create pipeline"))
 
 	var reprocess bool
@@ -510,7 +746,7 @@ All jobs created by a pipeline will create commits in the pipeline's output repo
 		Short: "Update an existing Pachyderm pipeline.",
 		Long:  "Update a Pachyderm pipeline with a new pipeline specification. For details on the format, see http://docs.pachyderm.io/en/latest/reference/pipeline_spec.html.",
 		Run: cmdutil.RunFixedArgs(0, func(args []string) (retErr error) {
-			return pipelineHelper(reprocess, build, pushImages, registry, username, pipelinePath, true)
+			return pipelineHelper(reprocess, build, pushImages, registry, username, pipelinePath, true, dryRun)
 		}),
 	}
 	updatePipeline.Flags().StringVarP(&pipelinePath, "file", "f", "-", "The JSON file containing the pipeline, it can be a url or local file. - reads from stdin.")
@@ -518,17 +754,21 @@ All jobs created by a pipeline will create commits in the pipeline's output repo
 	updatePipeline.Flags().BoolVarP(&pushImages, "push-images", "p", false, "If true, push local docker images into the docker registry.")
 	updatePipeline.Flags().StringVarP(&registry, "registry", "r", "index.docker.io", "The registry to push images to.")
 	updatePipeline.Flags().StringVarP(&username, "username", "u", "", "The username to push images as.")
+	updatePipeline.Flags().BoolVar(&dryRun, "dry-run", false, "If true, report the problems with the pipeline specification without updating the pipeline.")
 	updatePipeline.Flags().BoolVar(&reprocess, "reprocess", false, "If true, reprocess datums that were already processed by previous version of the pipeline.")
 	commands = append(commands, cmdutil.CreateAlias(updatePipeline, "update pipeline"))
 
 	runPipeline := &cobra.Command{
 		Use:   "{{alias}} <pipeline> [<repo>@<branch>[=<commit>]...]",
 		Short: "Run an existing Pachyderm pipeline on the specified commits-branch pairs.",
-		Long:  "Run a Pachyderm pipeline on the datums from specific commit-branch pairs. If only the branch is given, the head commit of the branch is used to complete the pair. Note: pipelines run automatically when data is committed to them. This command is for the case where you want to run the pipeline on a specific set of data, or if you want to rerun the pipeline. If a commit or branch is not specified, it will default to using the HEAD of master.",
+		Long:  "Run a Pachyderm pipeline on the datums from specific commit-branch pairs. If only the branch is given, the head commit of the branch is used to complete the pair. Note: pipelines run automatically when data is committed to them. This command is for the case where you want to run the pipeline on a specific set of data, or if you want to rerun the pipeline. If a commit or branch is not specified, it will default to using the HEAD of master. If the pipeline defers processing and no commits are specified, its pending input is processed as one job.",
 		Example: `
 		# Rerun the latest job for the "filter" pipeline
 		$ {{alias}} filter
 
+		# Process the pending input of the "nightly" pipeline, which defers processing
+		$ {{alias}} nightly
+
 		# Process the pipeline "filter" on the data from commit-branch pairs "repo1@A=a23e4" and "repo2@B=bf363"
 		$ {{alias}} filter repo1@A=a23e4 repo2@B=bf363
 
@@ -832,6 +1072,93 @@ All jobs created by a pipeline will create commits in the pipeline's output repo
 	}
 	commands = append(commands, cmdutil.CreateAlias(stopPipeline, "stop pipeline"))
 
+	var transferConcurrency int64
+	var datumTimeoutMultiplier float64
+	var logLevel string
+	updateWorkerConfig := &cobra.Command{
+		Use:   "{{alias}} <pipeline>",
+		Short: "Change the tunables of a pipeline's running workers.",
+		Long: `Change the tunables of a pipeline's running workers. The workers apply the
+new values without restarting, including in the middle of a job. Tunables
+whose flags aren't passed keep their current values.`,
+		Example: `
+# Halve the number of concurrent downloads of the workers of pipeline "edges"
+$ {{alias}} edges --transfer-concurrency 50
+
+# Give the datums of pipeline "edges" twice as long to finish, and log more
+$ {{alias}} edges --datum-timeout-multiplier 2 --log-level debug`,
+		Run: cmdutil.RunFixedArgs(1, func(args []string) error {
+			client, err := pachdclient.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+			pipelineInfo, err := client.InspectPipeline(args[0])
+			if err != nil {
+				return err
+			}
+			config := pipelineInfo.WorkerConfig
+			if config == nil {
+				config = &ppsclient.WorkerConfig{}
+			}
+			if transferConcurrency >= 0 {
+				config.TransferConcurrency = transferConcurrency
+			}
+			if datumTimeoutMultiplier >= 0 {
+				config.DatumTimeoutMultiplier = datumTimeoutMultiplier
+			}
+			if logLevel != "" {
+				config.LogLevel = logLevel
+			}
+			if err := client.SetWorkerConfig(args[0], config); err != nil {
+				cmdutil.ErrorAndExit("error from SetWorkerConfig: %s", err.Error())
+			}
+			return nil
+		}),
+	}
+	updateWorkerConfig.Flags().Int64Var(&transferConcurrency, "transfer-concurrency", -1, "The number of files that each worker downloads at once, or 0 for the default (100).")
+	updateWorkerConfig.Flags().Float64Var(&datumTimeoutMultiplier, "datum-timeout-multiplier", -1, "The multiplier of the pipeline's datum timeouts, or 0 for 1.")
+	updateWorkerConfig.Flags().StringVar(&logLevel, "log-level", "", "The level of the workers' logs: debug, info, warning or error.")
+	commands = append(commands, cmdutil.CreateAlias(updateWorkerConfig, "update worker-config"))
+
+	graph := &cobra.Command{
+		Short: "Print the DAG of repos and pipelines.",
+		Long: `Print the DAG of repos and pipelines, in GraphViz's DOT language.
+
+Repos are drawn as cylinders labeled with their size, and pipelines as boxes
+colored by their state and labeled with the state of their last job. An edge
+runs from each repo to the pipelines that read it, and from each pipeline to
+its output repo.`,
+		Example: `
+# Render the DAG as an image
+$ {{alias}} | dot -Tpng > dag.png
+
+# Print the DAG's nodes and edges as json
+$ {{alias}} --raw`,
+		Run: cmdutil.RunFixedArgs(0, func(args []string) error {
+			client, err := pachdclient.NewOnUserMachine("user")
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+			dag, err := client.InspectDAG()
+			if err != nil {
+				return err
+			}
+			if raw {
+				dag.Dot = ""
+				return encoder(output).EncodeProto(dag)
+			} else if output != "" {
+				cmdutil.ErrorAndExit("cannot set --output (-o) without --raw")
+			}
+			fmt.Print(dag.Dot)
+			return nil
+		}),
+	}
+	graph.Flags().AddFlagSet(rawFlags)
+	graph.Flags().AddFlagSet(outputFlags)
+	commands = append(commands, cmdutil.CreateAlias(graph, "graph"))
+
 	var memory string
 	garbageCollect := &cobra.Command{
 		Short: "Garbage collect unused data.",
@@ -877,7 +1204,10 @@ you can increase the amount of memory used for the bloom filters with the
 	return commands
 }
 
-func pipelineHelper(reprocess bool, build bool, pushImages bool, registry string, username string, pipelinePath string, update bool) error {
+func pipelineHelper(reprocess bool, build bool, pushImages bool, registry string, username string, pipelinePath string, update bool, dryRun bool) error {
+	if dryRun && (build || pushImages) {
+		return fmt.Errorf("`--dry-run` can't be used with `--build` or `--push-images`")
+	}
 	pipelineReader, err := ppsutil.NewPipelineManifestReader(pipelinePath)
 	if err != nil {
 		return err
@@ -887,6 +1217,7 @@ func pipelineHelper(reprocess bool, build bool, pushImages bool, registry string
 		return fmt.Errorf("error connecting to pachd: %v", err)
 	}
 	defer client.Close()
+	var errCount int
 	for {
 		request, err := pipelineReader.NextCreatePipelineRequest()
 		if err == io.EOF {
@@ -903,6 +1234,19 @@ func pipelineHelper(reprocess bool, build bool, pushImages bool, registry string
 			request.Update = true
 			request.Reprocess = reprocess
 		}
+		if dryRun {
+			diagnostics, err := client.ValidatePipeline(request)
+			if err != nil {
+				return err
+			}
+			printPipelineDiagnostics(request, diagnostics)
+			for _, d := range diagnostics {
+				if d.Severity == ppsclient.DiagnosticSeverity_DIAGNOSTIC_ERROR {
+					errCount++
+				}
+			}
+			continue
+		}
 		if build || pushImages {
 			if build && pushImages {
 				fmt.Fprintln(os.Stderr, "WARNING: `--push-images` is redundant, as it's already enabled with `--build`")
@@ -953,9 +1297,213 @@ func pipelineHelper(reprocess bool, build bool, pushImages bool, registry string
 			return grpcutil.ScrubGRPC(err)
 		}
 	}
+	if errCount > 0 {
+		return fmt.Errorf("found %d error(s) in the pipeline specification", errCount)
+	}
 	return nil
 }
 
+// waitForJobs prints the JobInfos that 'wait' streams as the states of the
+// jobs change. If any of the jobs doesn't succeed, it summarizes why,
+// listing the failed datums of jobs with stats enabled, and returns an error
+// so that pachctl exits with a non-zero status.
+func waitForJobs(c *pachdclient.APIClient, timeout time.Duration, raw bool, output string, fullTimestamps bool, view *jobProgressView,
+	wait func(*pachdclient.APIClient, func(*ppsclient.JobInfo) error) error) error {
+	if timeout > 0 {
+		ctx, cancel := context.WithTimeout(c.Ctx(), timeout)
+		defer cancel()
+		c = c.WithCtx(ctx)
+	}
+	var e serde.Encoder
+	if raw {
+		e = encoder(output)
+	} else if output != "" {
+		cmdutil.ErrorAndExit("cannot set --output (-o) without --raw")
+	}
+	var finished int
+	var failed []*ppsclient.JobInfo
+	if err := wait(c, func(jobInfo *ppsclient.JobInfo) error {
+		if raw {
+			if err := e.EncodeProto(jobInfo); err != nil {
+				return err
+			}
+		} else if view != nil {
+			if err := view.update(jobInfo); err != nil {
+				return err
+			}
+		} else {
+			pretty.PrintJobTransition(os.Stdout, jobInfo, fullTimestamps)
+		}
+		if ppsutil.IsTerminal(jobInfo.State) {
+			finished++
+			if jobInfo.State != ppsclient.JobState_JOB_SUCCESS {
+				failed = append(failed, jobInfo)
+			}
+		}
+		return nil
+	}); err != nil {
+		if c.Ctx().Err() == context.DeadlineExceeded {
+			return fmt.Errorf("jobs didn't finish within %v", timeout)
+		}
+		return err
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	for _, jobInfo := range failed {
+		var failedDatums []*ppsclient.DatumInfo
+		if jobInfo.EnableStats {
+			if err := c.ListDatumF(jobInfo.Job.ID, 0, 0, func(datumInfo *ppsclient.DatumInfo) error {
+				if datumInfo.State == ppsclient.DatumState_FAILED {
+					failedDatums = append(failedDatums, datumInfo)
+				}
+				return nil
+			}); err != nil {
+				fmt.Fprintf(os.Stderr, "could not list the failed datums of job %s: %v\n", jobInfo.Job.ID, err)
+			}
+		}
+		pretty.PrintJobFailure(os.Stderr, jobInfo, failedDatums)
+	}
+	return fmt.Errorf("%d of %d jobs didn't succeed", len(failed), finished)
+}
+
+// jobProgressView shows the progress of the jobs that 'flush job --progress'
+// waits for. On a terminal, it's a table with a row per job, in the order of
+// their pipelines in the DAG, that's redrawn in place as the jobs run.
+// Otherwise, each state that a job enters is printed, as in 'wait job'.
+type jobProgressView struct {
+	w              io.Writer
+	redraw         bool
+	fullTimestamps bool
+	// depths are the depths of pipelines in the DAG, i.e. the length of the
+	// longest chain of pipelines upstream of them
+	depths   map[string]int
+	jobInfos map[string]*ppsclient.JobInfo
+	// lines is the number of lines that were drawn last, which are drawn over
+	lines int
+}
+
+func newJobProgressView(c *pachdclient.APIClient, w io.Writer, redraw, fullTimestamps bool) (*jobProgressView, error) {
+	pipelineInfos, err := c.ListPipeline()
+	if err != nil {
+		return nil, err
+	}
+	return &jobProgressView{
+		w:              w,
+		redraw:         redraw,
+		fullTimestamps: fullTimestamps,
+		depths:         pipelineDepths(pipelineInfos),
+		jobInfos:       make(map[string]*ppsclient.JobInfo),
+	}, nil
+}
+
+// pipelineDepths returns the depth of each pipeline in the DAG: 0 for
+// pipelines whose inputs are all input repos, and one more than the deepest
+// pipeline that they read from for the others
+func pipelineDepths(pipelineInfos []*ppsclient.PipelineInfo) map[string]int {
+	inputs := make(map[string][]string)
+	for _, pipelineInfo := range pipelineInfos {
+		name := pipelineInfo.Pipeline.Name
+		inputs[name] = nil
+		ppsclient.VisitInput(pipelineInfo.Input, func(input *ppsclient.Input) {
+			if input.Pfs != nil {
+				inputs[name] = append(inputs[name], input.Pfs.Repo)
+			}
+		})
+	}
+	depths := make(map[string]int)
+	var depth func(pipeline string) int
+	depth = func(pipeline string) int {
+		if d, ok := depths[pipeline]; ok {
+			return d
+		}
+		depths[pipeline] = 0 // guards against cycles, which the DAG can't have
+		d := 0
+		for _, repo := range inputs[pipeline] {
+			if _, ok := inputs[repo]; ok && depth(repo)+1 > d {
+				d = depth(repo) + 1
+			}
+		}
+		depths[pipeline] = d
+		return d
+	}
+	for pipeline := range inputs {
+		depth(pipeline)
+	}
+	return depths
+}
+
+func (v *jobProgressView) update(jobInfo *ppsclient.JobInfo) error {
+	prev := v.jobInfos[jobInfo.Job.ID]
+	v.jobInfos[jobInfo.Job.ID] = jobInfo
+	if !v.redraw {
+		if prev == nil || prev.State != jobInfo.State {
+			pretty.PrintJobTransition(v.w, jobInfo, v.fullTimestamps)
+		}
+		return nil
+	}
+	jobInfos := make([]*ppsclient.JobInfo, 0, len(v.jobInfos))
+	for _, ji := range v.jobInfos {
+		jobInfos = append(jobInfos, ji)
+	}
+	sort.Slice(jobInfos, func(i, j int) bool {
+		a, b := jobInfos[i], jobInfos[j]
+		if v.depths[a.Pipeline.Name] != v.depths[b.Pipeline.Name] {
+			return v.depths[a.Pipeline.Name] < v.depths[b.Pipeline.Name]
+		}
+		if a.Pipeline.Name != b.Pipeline.Name {
+			return a.Pipeline.Name < b.Pipeline.Name
+		}
+		return a.Job.ID < b.Job.ID
+	})
+	table := &bytes.Buffer{}
+	writer := tabwriter.NewWriter(table, pretty.JobProgressHeader)
+	for _, ji := range jobInfos {
+		pretty.PrintJobProgress(writer, ji, v.depths[ji.Pipeline.Name])
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	// Move the cursor up over the last table, and clear each of its lines as
+	// the new one is drawn over it
+	frame := &bytes.Buffer{}
+	if v.lines > 0 {
+		fmt.Fprintf(frame, "\x1b[%dA", v.lines)
+	}
+	v.lines = 0
+	scanner := bufio.NewScanner(table)
+	for scanner.Scan() {
+		fmt.Fprintf(frame, "\x1b[2K%s\n", scanner.Text())
+		v.lines++
+	}
+	_, err := v.w.Write(frame.Bytes())
+	return err
+}
+
+// printPipelineDiagnostics prints the problems that ValidatePipeline found
+// with the pipeline spec in 'request', one per line
+func printPipelineDiagnostics(request *ppsclient.CreatePipelineRequest, diagnostics []*ppsclient.PipelineDiagnostic) {
+	var name string
+	if request.Pipeline != nil {
+		name = request.Pipeline.Name
+	}
+	if len(diagnostics) == 0 {
+		fmt.Printf("%s: no problems found\n", name)
+		return
+	}
+	for _, d := range diagnostics {
+		severity := "error"
+		if d.Severity == ppsclient.DiagnosticSeverity_DIAGNOSTIC_WARNING {
+			severity = "warning"
+		}
+		if d.Path == "" {
+			fmt.Printf("%s: %s: %s\n", name, severity, d.Message)
+		} else {
+			fmt.Printf("%s: %s: %s: %s\n", name, severity, d.Path, d.Message)
+		}
+	}
+}
+
 // ByCreationTime is an implementation of sort.Interface which
 // sorts pps job info by creation time, ascending.
 type ByCreationTime []*ppsclient.JobInfo
@@ -2,12 +2,15 @@ package pretty
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/docker/go-units"
 	"github.com/fatih/color"
@@ -24,10 +27,16 @@ const (
 	PipelineHeader = "NAME\tVERSION\tINPUT\tCREATED\tSTATE / LAST JOB\tDESCRIPTION\t\n"
 	// JobHeader is the header for jobs
 	JobHeader = "ID\tPIPELINE\tSTARTED\tDURATION\tRESTART\tPROGRESS\tDL\tUL\tSTATE\t\n"
+	// JobProgressHeader is the header for the progress of running jobs
+	JobProgressHeader = "PIPELINE\tJOB\tPROGRESS\tDATUMS\tDL\tUL\tSTATE\t\n"
 	// DatumHeader is the header for datums
 	DatumHeader = "ID\tSTATUS\tTIME\t\n"
+	// JobStatsHeader is the header for the aggregated datum stats of a job
+	JobStatsHeader = "\tMEAN\tP5\tP50\tP95\tP99\t\n"
 	// jobReasonLen is the amount of the job reason that we print
 	jobReasonLen = 25
+	// jobProgressBarWidth is the width of the progress bars of jobs
+	jobProgressBarWidth = 20
 )
 
 func safeTrim(s string, l int) string {
@@ -59,6 +68,57 @@ func PrintJobInfo(w io.Writer, jobInfo *ppsclient.JobInfo, fullTimestamps bool)
 	}
 	fmt.Fprintf(w, "%s\t", pretty.Size(jobInfo.Stats.DownloadBytes))
 	fmt.Fprintf(w, "%s\t", pretty.Size(jobInfo.Stats.UploadBytes))
+	if jobInfo.State == ppsclient.JobState_JOB_FAILURE && len(jobInfo.FailureCounts) > 0 {
+		fmt.Fprintf(w, "%s: %s\t", jobState(jobInfo.State), failureCounts(jobInfo.FailureCounts))
+	} else if jobInfo.State == ppsclient.JobState_JOB_FAILURE {
+		fmt.Fprintf(w, "%s: %s\t", jobState(jobInfo.State), safeTrim(jobInfo.Reason, jobReasonLen))
+	} else if jobInfo.Paused {
+		fmt.Fprintf(w, "%s (paused)\t", jobState(jobInfo.State))
+	} else {
+		fmt.Fprintf(w, "%s\t", jobState(jobInfo.State))
+	}
+	fmt.Fprintln(w)
+}
+
+// PrintJobTransition prints the state that a job entered, as a single line,
+// for commands that stream the states of jobs as they change
+func PrintJobTransition(w io.Writer, jobInfo *ppsclient.JobInfo, fullTimestamps bool) {
+	now := time.Now()
+	if fullTimestamps {
+		fmt.Fprintf(w, "%s ", now.Format(time.RFC3339))
+	} else {
+		fmt.Fprintf(w, "%s ", now.Format("15:04:05"))
+	}
+	fmt.Fprintf(w, "%s job %s: %s", jobInfo.Pipeline.Name, jobInfo.Job.ID, jobState(jobInfo.State))
+	if jobInfo.Finished != nil {
+		fmt.Fprintf(w, " (%d + %d / %d datums", jobInfo.DataProcessed, jobInfo.DataSkipped, jobInfo.DataTotal)
+		fmt.Fprintf(w, " in %s)", pretty.TimeDifference(jobInfo.Started, jobInfo.Finished))
+	}
+	if jobInfo.Reason != "" && jobInfo.State != ppsclient.JobState_JOB_SUCCESS {
+		fmt.Fprintf(w, ": %s", jobInfo.Reason)
+	}
+	fmt.Fprintln(w)
+}
+
+// PrintJobProgress prints a job's progress, as a single line, for commands
+// that redraw the progress of jobs as they run. The pipeline's name is
+// indented by 'depth', its depth in the DAG.
+func PrintJobProgress(w io.Writer, jobInfo *ppsclient.JobInfo, depth int) {
+	fmt.Fprintf(w, "%s%s\t", strings.Repeat("  ", depth), jobInfo.Pipeline.Name)
+	fmt.Fprintf(w, "%s\t", jobInfo.Job.ID)
+	done := jobInfo.DataProcessed + jobInfo.DataSkipped + jobInfo.DataRecovered
+	var filled int
+	if jobInfo.DataTotal > 0 {
+		filled = int(int64(jobProgressBarWidth) * (done + jobInfo.DataFailed) / jobInfo.DataTotal)
+		if filled > jobProgressBarWidth {
+			filled = jobProgressBarWidth
+		}
+	}
+	fmt.Fprintf(w, "%s%s\t", pretty.ProgressBar(filled, int(done), 0, int(jobInfo.DataFailed)),
+		strings.Repeat(" ", jobProgressBarWidth-filled))
+	fmt.Fprintf(w, "%d/%d\t", done, jobInfo.DataTotal)
+	fmt.Fprintf(w, "%s\t", pretty.Size(jobInfo.Stats.DownloadBytes))
+	fmt.Fprintf(w, "%s\t", pretty.Size(jobInfo.Stats.UploadBytes))
 	if jobInfo.State == ppsclient.JobState_JOB_FAILURE {
 		fmt.Fprintf(w, "%s: %s\t", jobState(jobInfo.State), safeTrim(jobInfo.Reason, jobReasonLen))
 	} else {
@@ -67,6 +127,31 @@ func PrintJobInfo(w io.Writer, jobInfo *ppsclient.JobInfo, fullTimestamps bool)
 	fmt.Fprintln(w)
 }
 
+// PrintJobFailure summarizes why a job didn't succeed. 'failedDatums' are
+// the job's failed datums, which are only known if the job has stats
+// enabled.
+func PrintJobFailure(w io.Writer, jobInfo *ppsclient.JobInfo, failedDatums []*ppsclient.DatumInfo) {
+	fmt.Fprintf(w, "job %s (pipeline %s): %s", jobInfo.Job.ID, jobInfo.Pipeline.Name, jobState(jobInfo.State))
+	if jobInfo.Reason != "" {
+		fmt.Fprintf(w, ": %s", jobInfo.Reason)
+	}
+	fmt.Fprintln(w)
+	if jobInfo.DataFailed > 0 {
+		fmt.Fprintf(w, "  %d of %d datums failed", jobInfo.DataFailed, jobInfo.DataTotal)
+		if len(jobInfo.FailureCounts) > 0 {
+			fmt.Fprintf(w, " (%s)", failureCounts(jobInfo.FailureCounts))
+		}
+		fmt.Fprintln(w)
+	}
+	for _, datumInfo := range failedDatums {
+		var paths []string
+		for _, fileInfo := range datumInfo.Data {
+			paths = append(paths, fmt.Sprintf("%s@%s:%s", fileInfo.File.Commit.Repo.Name, fileInfo.File.Commit.ID, fileInfo.File.Path))
+		}
+		fmt.Fprintf(w, "  datum %s: %s\n", datumInfo.Datum.ID, strings.Join(paths, ", "))
+	}
+}
+
 // PrintPipelineInfo pretty-prints pipeline info.
 func PrintPipelineInfo(w io.Writer, pipelineInfo *ppsclient.PipelineInfo, fullTimestamps bool) {
 	fmt.Fprintf(w, "%s\t", pipelineInfo.Pipeline.Name)
@@ -84,7 +169,7 @@ func PrintPipelineInfo(w io.Writer, pipelineInfo *ppsclient.PipelineInfo, fullTi
 
 // PrintWorkerStatusHeader pretty prints a worker status header.
 func PrintWorkerStatusHeader(w io.Writer) {
-	fmt.Fprint(w, "WORKER\tJOB\tDATUM\tSTARTED\tQUEUE\t\n")
+	fmt.Fprint(w, "WORKER\tJOB\tDATUM\tSTARTED\tQUEUE\tTRANSFER\t\n")
 }
 
 // PrintWorkerStatus pretty prints a worker status.
@@ -101,9 +186,23 @@ func PrintWorkerStatus(w io.Writer, workerStatus *ppsclient.WorkerStatus, fullTi
 		fmt.Fprintf(w, "%s\t", pretty.Ago(workerStatus.Started))
 	}
 	fmt.Fprintf(w, "%d\t", workerStatus.QueueSize)
+	fmt.Fprintf(w, "%s\t", transferProgress(workerStatus.Transfer))
 	fmt.Fprintln(w)
 }
 
+// transferProgress returns a short description of a worker's running
+// download or upload, e.g. "download 1.5GiB/4GiB (20MiB/s)"
+func transferProgress(p *ppsclient.TransferProgress) string {
+	if p == nil {
+		return "-"
+	}
+	total := "?"
+	if p.BytesTotal > 0 {
+		total = pretty.Size(uint64(p.BytesTotal))
+	}
+	return fmt.Sprintf("%s %s/%s (%s/s)", p.Phase, pretty.Size(uint64(p.BytesDone)), total, pretty.Size(uint64(p.BytesPerSecond)))
+}
+
 // PrintableJobInfo is a wrapper around JobInfo containing any formatting options
 // used within the template to conditionally print information.
 type PrintableJobInfo struct {
@@ -127,19 +226,23 @@ Parent: {{.ParentJob.ID}} {{end}}{{if .FullTimestamps}}
 Started: {{.Started}}{{else}}
 Started: {{prettyAgo .Started}} {{end}}{{if .Finished}}
 Duration: {{prettyTimeDifference .Started .Finished}} {{end}}
-State: {{jobState .State}}
-Reason: {{.Reason}}
+State: {{jobState .State}}{{if .Paused}} (paused){{end}}
+Reason: {{.Reason}}{{if .FailureType}} ({{failureType .FailureType}}){{end}}
 Processed: {{.DataProcessed}}
-Failed: {{.DataFailed}}
+Failed: {{.DataFailed}}{{if .FailureCounts}} ({{failureCounts .FailureCounts}}){{end}}
 Skipped: {{.DataSkipped}}
 Recovered: {{.DataRecovered}}
-Total: {{.DataTotal}}
+Total: {{.DataTotal}}{{if .EmptyReason}}
+No Datums: {{.EmptyReason.Message}}{{range .EmptyReason.Inputs}}{{if .SamplePaths}}
+  Sample files of {{.Name}}: {{join .SamplePaths ", "}}{{end}}{{end}}{{end}}{{if .NodesMerged}}
+Nodes Merged: {{.NodesMerged}}{{end}}
 Data Downloaded: {{prettySize .Stats.DownloadBytes}}
 Data Uploaded: {{prettySize .Stats.UploadBytes}}
 Download Time: {{prettyDuration .Stats.DownloadTime}}
 Process Time: {{prettyDuration .Stats.ProcessTime}}
 Upload Time: {{prettyDuration .Stats.UploadTime}}
-Datum Timeout: {{.DatumTimeout}}
+Datum Timeout: {{.DatumTimeout}}{{ if .DownloadTimeout }}
+Download Timeout: {{.DownloadTimeout}}{{end}}
 Job Timeout: {{.JobTimeout}}
 Worker Status:
 {{workerStatus .}}Restarts: {{.Restart}}
@@ -161,7 +264,8 @@ Transform:
 {{prettyTransform .Transform}} {{if .OutputCommit}}
 Output Commit: {{.OutputCommit.ID}} {{end}} {{ if .StatsCommit }}
 Stats Commit: {{.StatsCommit.ID}} {{end}} {{ if .Egress }}
-Egress: {{.Egress.URL}} {{end}}
+Egress: {{egress .Egress}} {{end}} {{ if .EgressStatus }}
+Egress Status: {{egressStatus .EgressStatus}} {{end}}
 `)
 	if err != nil {
 		return err
@@ -197,7 +301,9 @@ Created: {{prettyAgo .CreatedAt}} {{end}}
 State: {{pipelineState .State}}
 Stopped: {{ .Stopped }}
 Reason: {{.Reason}}
-Parallelism Spec: {{.ParallelismSpec}}
+Parallelism Spec: {{.ParallelismSpec}}{{ if .AutoscalingSpec }}
+Autoscaling: {{.AutoscalingSpec.MinWorkers}} to {{.AutoscalingSpec.MaxWorkers}} workers{{end}}{{ if .Notifications }}
+Notifications: {{len .Notifications}}{{end}}
 {{ if .ResourceRequests }}ResourceRequests:
   CPU: {{ .ResourceRequests.Cpu }}
   Memory: {{ .ResourceRequests.Memory }} {{end}}
@@ -207,15 +313,19 @@ Parallelism Spec: {{.ParallelismSpec}}
   {{ if .ResourceLimits.Gpu }}GPU:
     Type: {{ .ResourceLimits.Gpu.Type }} 
     Number: {{ .ResourceLimits.Gpu.Number }} {{end}} {{end}}
-Datum Timeout: {{.DatumTimeout}}
+Datum Timeout: {{.DatumTimeout}}{{ if .DownloadTimeout }}
+Download Timeout: {{.DownloadTimeout}}{{end}}
 Job Timeout: {{.JobTimeout}}
 Input:
 {{pipelineInput .PipelineInfo}}
 {{ if .GithookURL }}Githook URL: {{.GithookURL}} {{end}}
+{{ if .Service }}Service: {{service .Service}} {{end}}
 Output Branch: {{.OutputBranch}}
 Transform:
 {{prettyTransform .Transform}}
-{{ if .Egress }}Egress: {{.Egress.URL}} {{end}}
+{{ if .Egress }}Egress: {{egress .Egress}} {{end}}
+{{ if .Cache }}Cache: {{.Cache.Path}}{{ if .Cache.SizeLimit }} (up to {{.Cache.SizeLimit}}){{end}} {{end}}
+{{ if .WorkerConfig }}Worker Config: {{workerConfig .WorkerConfig}} {{end}}
 {{if .RecentError}} Recent Error: {{.RecentError}} {{end}}
 Job Counts:
 {{jobCounts .JobCounts}}
@@ -242,6 +352,17 @@ func PrintDatumInfo(w io.Writer, datumInfo *ppsclient.DatumInfo) {
 	fmt.Fprintln(w)
 }
 
+// PrintDatumSummary pretty-prints the counts of the datums that a ListDatum
+// request matched, by state
+func PrintDatumSummary(w io.Writer, summary *ppsclient.DatumSummary) {
+	fmt.Fprintf(w, "Total\t%d\n", summary.Total)
+	fmt.Fprintf(w, "Failed\t%d\n", summary.Failed)
+	fmt.Fprintf(w, "Success\t%d\n", summary.Success)
+	fmt.Fprintf(w, "Skipped\t%d\n", summary.Skipped)
+	fmt.Fprintf(w, "Starting\t%d\n", summary.Starting)
+	fmt.Fprintf(w, "Recovered\t%d\n", summary.Recovered)
+}
+
 // PrintDetailedDatumInfo pretty-prints detailed info about a datum
 func PrintDetailedDatumInfo(w io.Writer, datumInfo *ppsclient.DatumInfo) {
 	fmt.Fprintf(w, "ID\t%s\n", datumInfo.Datum.ID)
@@ -292,6 +413,113 @@ func PrintDetailedDatumInfo(w io.Writer, datumInfo *ppsclient.DatumInfo) {
 		PrintFile(tw, d.File)
 	}
 	tw.Flush()
+	if len(datumInfo.Stats.LazyFiles) > 0 {
+		fmt.Fprintf(w, "Lazy Files:\n")
+		tw = ansiterm.NewTabWriter(w, 10, 1, 3, ' ', 0)
+		fmt.Fprint(tw, "PATH\tSERVED\tWAIT TIME\tBLOCKED TIME\tOPEN FOR\t\n")
+		for _, f := range datumInfo.Stats.LazyFiles {
+			printLazyFileStats(tw, f)
+		}
+		tw.Flush()
+	}
+}
+
+func printLazyFileStats(w io.Writer, f *ppsclient.LazyFileStats) {
+	duration := func(d *types.Duration) string {
+		dur, err := types.DurationFromProto(d)
+		if err != nil {
+			return "-"
+		}
+		return dur.String()
+	}
+	openFor := "never opened"
+	if f.Opened != nil && f.Closed != nil {
+		opened, err1 := types.TimestampFromProto(f.Opened)
+		closed, err2 := types.TimestampFromProto(f.Closed)
+		if err1 == nil && err2 == nil {
+			openFor = closed.Sub(opened).String()
+		}
+	}
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t\n", f.Path, pretty.Size(f.BytesServed),
+		duration(f.WaitTime), duration(f.BlockedTime), openFor)
+}
+
+// PrintDetailedJobStats pretty prints the aggregated datum stats of a job.
+func PrintDetailedJobStats(w io.Writer, jobStats *ppsclient.JobStats) {
+	fmt.Fprintf(w, "Job ID\t%s\n", jobStats.Job.ID)
+	fmt.Fprintf(w, "Datums Processed\t%d\n", jobStats.DatumsProcessed)
+	fmt.Fprintf(w, "Datums Skipped\t%d\n", jobStats.DatumsSkipped)
+	fmt.Fprintf(w, "Datums Failed\t%d\n", jobStats.DatumsFailed)
+	fmt.Fprintf(w, "Stats:\n")
+	tw := ansiterm.NewTabWriter(w, 10, 1, 3, ' ', 0)
+	fmt.Fprint(tw, JobStatsHeader)
+	seconds := func(s float64) string {
+		return time.Duration(s * float64(time.Second)).String()
+	}
+	size := func(b float64) string {
+		return pretty.Size(uint64(b))
+	}
+	stats := jobStats.Stats
+	if stats == nil {
+		stats = &ppsclient.AggregateProcessStats{}
+	}
+	printAggregate(tw, "Download Time", stats.DownloadTime, seconds)
+	printAggregate(tw, "Process Time", stats.ProcessTime, seconds)
+	printAggregate(tw, "Upload Time", stats.UploadTime, seconds)
+	printAggregate(tw, "Data Downloaded", stats.DownloadBytes, size)
+	printAggregate(tw, "Data Uploaded", stats.UploadBytes, size)
+	tw.Flush()
+	if len(jobStats.Failures) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "Failures:\n")
+	tw = ansiterm.NewTabWriter(w, 10, 1, 3, ' ', 0)
+	fmt.Fprint(tw, "COUNT\tMESSAGE\t\n")
+	for _, failure := range jobStats.Failures {
+		fmt.Fprintf(tw, "%d\t%s\t\n", failure.Count, safeTrim(failure.Message, 80))
+	}
+	tw.Flush()
+}
+
+// PrintJobAttestationInfo pretty-prints a job's verified attestation
+func PrintJobAttestationInfo(w io.Writer, attestationInfo *ppsclient.JobAttestationInfo) {
+	a := attestationInfo.Attestation
+	fmt.Fprintf(w, "Job ID\t%s\n", a.Job.ID)
+	fmt.Fprintf(w, "Pipeline\t%s (version %d)\n", a.Pipeline.Name, a.PipelineVersion)
+	fmt.Fprintf(w, "Created\t%s\n", pretty.Ago(a.Created))
+	fmt.Fprintf(w, "Image\t%s\n", a.Image)
+	fmt.Fprintf(w, "Image Digest\t%s\n", a.ImageDigest)
+	fmt.Fprintf(w, "Cmd\t%s\n", strings.Join(a.Cmd, " "))
+	fmt.Fprintf(w, "Inputs:\n")
+	for _, commit := range a.Inputs {
+		fmt.Fprintf(w, "  %s@%s\n", commit.Repo.Name, commit.ID)
+	}
+	if len(a.Env) > 0 {
+		fmt.Fprintf(w, "Env:\n")
+		var names []string
+		for name := range a.Env {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(w, "  %s=%s\n", name, a.Env[name])
+		}
+	}
+	fmt.Fprintf(w, "Output Commit\t%s@%s\n", a.OutputCommit.Repo.Name, a.OutputCommit.ID)
+	fmt.Fprintf(w, "Output Trees:\n")
+	for _, tree := range a.OutputTrees {
+		fmt.Fprintf(w, "  %s\n", tree.Hash)
+	}
+	fmt.Fprintf(w, "Signature\tverified with key %s\n", base64.StdEncoding.EncodeToString(attestationInfo.PublicKey))
+}
+
+func printAggregate(w io.Writer, name string, a *ppsclient.Aggregate, format func(float64) string) {
+	if a == nil {
+		a = &ppsclient.Aggregate{}
+	}
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t\n", name, format(a.Mean),
+		format(a.FifthPercentile), format(a.Median),
+		format(a.NinetyFifthPercentile), format(a.NinetyNinthPercentile))
 }
 
 // PrintFileHeader prints the header for a pfs file.
@@ -336,6 +564,125 @@ func jobState(jobState ppsclient.JobState) string {
 	return "-"
 }
 
+func failureType(failureType ppsclient.FailureType) string {
+	switch failureType {
+	case ppsclient.FailureType_USER_CODE_NONZERO_EXIT:
+		return "user-code-nonzero-exit"
+	case ppsclient.FailureType_DATUM_TIMEOUT:
+		return "timeout"
+	case ppsclient.FailureType_OOM_KILLED:
+		return "oom-killed"
+	case ppsclient.FailureType_DOWNLOAD_ERROR:
+		return "download-error"
+	case ppsclient.FailureType_UPLOAD_ERROR:
+		return "upload-error"
+	case ppsclient.FailureType_SPECIAL_FILE:
+		return "special-file"
+	case ppsclient.FailureType_INPUT_MODIFIED:
+		return "input-modified"
+	case ppsclient.FailureType_USER_CODE_ERROR:
+		return "user-code-error"
+	}
+	return "unknown"
+}
+
+func egress(egress *ppsclient.Egress) string {
+	if egress.Postgres != nil {
+		return egress.Postgres.URL
+	}
+	return egress.URL
+}
+
+// service summarizes a pipeline's service, e.g. "NodePort 10.0.0.1, ports
+// user-port:8080->30080 metrics:9090, ingress example.com/app, 2/3 endpoints
+// ready"
+func service(service *ppsclient.Service) string {
+	parts := []string{service.Type}
+	if service.IP != "" {
+		parts[0] += " " + service.IP
+	}
+	var ports []string
+	port := func(name string, internalPort, externalPort int32) {
+		p := fmt.Sprintf("%s:%d", name, internalPort)
+		if externalPort != 0 {
+			p += fmt.Sprintf("->%d", externalPort)
+		}
+		ports = append(ports, p)
+	}
+	if service.InternalPort != 0 {
+		port("user-port", service.InternalPort, service.ExternalPort)
+	}
+	for _, p := range service.Ports {
+		port(p.Name, p.InternalPort, p.ExternalPort)
+	}
+	parts = append(parts, "ports "+strings.Join(ports, " "))
+	if service.Ingress != nil {
+		path := service.Ingress.Path
+		if path == "" {
+			path = "/"
+		}
+		parts = append(parts, "ingress "+service.Ingress.Host+path)
+	}
+	parts = append(parts, fmt.Sprintf("%d/%d endpoints ready", service.ReadyEndpoints, service.ReadyEndpoints+service.NotReadyEndpoints))
+	return strings.Join(parts, ", ")
+}
+
+// workerConfig summarizes the tunables that a worker config sets, e.g.
+// "transfer concurrency 50, log level debug"
+func workerConfig(config *ppsclient.WorkerConfig) string {
+	var parts []string
+	if config.TransferConcurrency != 0 {
+		parts = append(parts, fmt.Sprintf("transfer concurrency %d", config.TransferConcurrency))
+	}
+	if config.DatumTimeoutMultiplier != 0 {
+		parts = append(parts, fmt.Sprintf("datum timeout multiplier %v", config.DatumTimeoutMultiplier))
+	}
+	if config.LogLevel != "" {
+		parts = append(parts, fmt.Sprintf("log level %s", config.LogLevel))
+	}
+	if len(parts) == 0 {
+		return "defaults"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// EgressStatus summarizes the egress of a job's output commit, e.g.
+// "failure after 4 attempts: <error>"
+func EgressStatus(status *ppsclient.EgressStatus) string {
+	attempts := "attempts"
+	if status.Attempts == 1 {
+		attempts = "attempt"
+	}
+	switch status.State {
+	case ppsclient.EgressState_EGRESS_SUCCESS:
+		return fmt.Sprintf("%s after %d %s", color.GreenString("success"), status.Attempts, attempts)
+	case ppsclient.EgressState_EGRESS_FAILURE:
+		return fmt.Sprintf("%s after %d %s: %s", color.RedString("failure"), status.Attempts, attempts, status.Reason)
+	}
+	result := fmt.Sprintf("%s (%d failed %s)", color.YellowString("running"), status.Attempts, attempts)
+	if status.Reason != "" {
+		result += ": " + status.Reason
+	}
+	return result
+}
+
+// failureCounts summarizes the failed datums of a job by failure type, e.g.
+// "2 oom-killed, 1 timeout"
+func failureCounts(counts map[int32]int64) string {
+	var types []int32
+	for t := range counts {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	var result []string
+	for _, t := range types {
+		if counts[t] > 0 {
+			result = append(result, fmt.Sprintf("%d %s", counts[t], failureType(ppsclient.FailureType(t))))
+		}
+	}
+	return strings.Join(result, ", ")
+}
+
 func pipelineState(pipelineState ppsclient.PipelineState) string {
 	switch pipelineState {
 	case ppsclient.PipelineState_PIPELINE_STARTING:
@@ -350,6 +697,8 @@ func pipelineState(pipelineState ppsclient.PipelineState) string {
 		return color.New(color.FgYellow).SprintFunc()("paused")
 	case ppsclient.PipelineState_PIPELINE_STANDBY:
 		return color.New(color.FgYellow).SprintFunc()("standby")
+	case ppsclient.PipelineState_PIPELINE_CRASHING:
+		return color.New(color.FgRed).SprintFunc()("crashing")
 	}
 	return "-"
 }
@@ -431,6 +780,8 @@ func ShorthandInput(input *ppsclient.Input) string {
 		return "(" + strings.Join(subInput, " ∪ ") + ")"
 	case input.Cron != nil:
 		return fmt.Sprintf("%s:%s", input.Cron.Name, input.Cron.Spec)
+	case input.SQL != nil:
+		return fmt.Sprintf("%s:sql:%s", input.SQL.Name, input.SQL.Spec)
 	}
 	return ""
 }
@@ -447,5 +798,12 @@ var funcMap = template.FuncMap{
 	"prettyDuration":       pretty.Duration,
 	"prettySize":           pretty.Size,
 	"jobCounts":            jobCounts,
+	"failureType":          failureType,
+	"egress":               egress,
+	"workerConfig":         workerConfig,
+	"service":              service,
+	"egressStatus":         EgressStatus,
+	"failureCounts":        failureCounts,
 	"prettyTransform":      prettyTransform,
+	"join":                 strings.Join,
 }
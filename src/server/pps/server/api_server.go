@@ -7,6 +7,9 @@ import (
 	goerr "errors"
 	"fmt"
 	"io"
+	"math"
+	"net"
+	"net/url"
 	"path"
 	"path/filepath"
 	"sort"
@@ -31,6 +34,7 @@ import (
 	"github.com/pachyderm/pachyderm/src/server/pkg/hashtree"
 	"github.com/pachyderm/pachyderm/src/server/pkg/log"
 	"github.com/pachyderm/pachyderm/src/server/pkg/metrics"
+	"github.com/pachyderm/pachyderm/src/server/pkg/obj"
 	"github.com/pachyderm/pachyderm/src/server/pkg/ppsconsts"
 	"github.com/pachyderm/pachyderm/src/server/pkg/ppsdb"
 	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
@@ -45,7 +49,9 @@ import (
 
 	"github.com/gogo/protobuf/jsonpb"
 	"github.com/gogo/protobuf/types"
+	"github.com/prometheus/common/model"
 	logrus "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ed25519"
 	"golang.org/x/net/context"
 
 	"golang.org/x/sync/errgroup"
@@ -63,6 +69,13 @@ const (
 	// DefaultDatumTries is the default number of times a datum will be tried
 	// before we give up and consider the job failed.
 	DefaultDatumTries = 3
+	// DefaultKafkaBatchSize and DefaultKafkaBatchTimeout bound the batches of
+	// messages that Kafka spouts commit, when their spec doesn't.
+	DefaultKafkaBatchSize    = 1000
+	DefaultKafkaBatchTimeout = 10 * time.Second
+	// DefaultMetricsPushInterval is how often workers push their metrics, when
+	// the pipeline's metrics_push doesn't say.
+	DefaultMetricsPushInterval = 15 * time.Second
 )
 
 var (
@@ -71,7 +84,7 @@ var (
 )
 
 func newErrPipelineNotFound(pipeline string) error {
-	return fmt.Errorf("pipeline %v not found", pipeline)
+	return pps.ErrPipelineNotFound{Pipeline: client.NewPipeline(pipeline)}
 }
 
 func newErrPipelineExists(pipeline string) error {
@@ -137,6 +150,11 @@ func validateNames(names map[string]bool, input *pps.Input) error {
 			return fmt.Errorf(`name "%s" was used more than once`, input.Cron.Name)
 		}
 		names[input.Cron.Name] = true
+	case input.SQL != nil:
+		if names[input.SQL.Name] {
+			return fmt.Errorf(`name "%s" was used more than once`, input.SQL.Name)
+		}
+		names[input.SQL.Name] = true
 	case input.Union != nil:
 		for _, input := range input.Union {
 			namesCopy := make(map[string]bool)
@@ -194,6 +212,12 @@ func (a *apiServer) validateInput(pachClient *client.APIClient, pipelineName str
 				case len(input.Pfs.Glob) == 0:
 					return fmt.Errorf("input must specify a glob")
 				}
+				if err := validateReadAhead(input.Pfs); err != nil {
+					return err
+				}
+				if err := validateMount(input.Pfs); err != nil {
+					return err
+				}
 				// Note that input.Pfs.Commit is empty if a) this is a job b) one of
 				// the job pipeline's input branches has no commits yet
 				if job && input.Pfs.Commit != "" {
@@ -214,6 +238,9 @@ func (a *apiServer) validateInput(pachClient *client.APIClient, pipelineName str
 				}
 				set = true
 			}
+			if _, err := ppsutil.CompileCrossFilter(input); err != nil {
+				return err
+			}
 			if input.Join != nil {
 				if set {
 					return fmt.Errorf("multiple input types set")
@@ -244,6 +271,15 @@ func (a *apiServer) validateInput(pachClient *client.APIClient, pipelineName str
 					return err
 				}
 			}
+			if input.SQL != nil {
+				if set {
+					return fmt.Errorf("multiple input types set")
+				}
+				set = true
+				if err := validateSQLInput(input.SQL); err != nil {
+					return err
+				}
+			}
 			if !set {
 				return fmt.Errorf("no input set")
 			}
@@ -262,6 +298,265 @@ func validateTransform(transform *pps.Transform) error {
 	if transform.Image == "" {
 		return fmt.Errorf("pipeline transform must contain an image")
 	}
+	if _, err := ppsutil.ParseFileMode(transform.Umask); err != nil {
+		return fmt.Errorf("invalid transform.umask: %v", err)
+	}
+	if _, err := ppsutil.ParseFileMode(transform.DirMode); err != nil {
+		return fmt.Errorf("invalid transform.dir_mode: %v", err)
+	}
+	if _, err := ppsutil.ParseFileMode(transform.FileMode); err != nil {
+		return fmt.Errorf("invalid transform.file_mode: %v", err)
+	}
+	names := map[string]bool{"init": true}
+	for _, c := range transform.InitContainers {
+		if c.Name == "" || c.Image == "" {
+			return fmt.Errorf("init containers must have a name and an image")
+		}
+		if names[c.Name] {
+			return fmt.Errorf("init container name %q is already in use", c.Name)
+		}
+		names[c.Name] = true
+	}
+	if len(transform.EnvAllowlist) > 0 && !transform.ScrubEnv {
+		return fmt.Errorf("transform.env_allowlist can only be set with transform.scrub_env")
+	}
+	for _, name := range transform.EnvAllowlist {
+		if name == "" || strings.ContainsAny(name, "= ") {
+			return fmt.Errorf("invalid environment variable name %q in transform.env_allowlist", name)
+		}
+	}
+	return nil
+}
+
+// validateReadAhead checks the read_ahead of a PFS input, which only lazy
+// inputs can set
+func validateReadAhead(input *pps.PFSInput) error {
+	if input.ReadAhead == "" {
+		return nil
+	}
+	if !input.Lazy {
+		return fmt.Errorf("input %s sets read_ahead, but isn't lazy", input.Name)
+	}
+	size, err := resource.ParseQuantity(input.ReadAhead)
+	if err != nil {
+		return fmt.Errorf("could not parse read_ahead of input %s: %v", input.Name, err)
+	}
+	if size.Sign() <= 0 {
+		return fmt.Errorf("read_ahead of input %s must be positive", input.Name)
+	}
+	return nil
+}
+
+// validateMount checks the mount of a PFS input, which only lazy inputs can
+// set, and which replaces the named pipes that read_ahead and empty_files
+// apply to
+func validateMount(input *pps.PFSInput) error {
+	if !input.Mount {
+		return nil
+	}
+	switch {
+	case !input.Lazy:
+		return fmt.Errorf("input %s sets mount, but isn't lazy", input.Name)
+	case input.ReadAhead != "":
+		return fmt.Errorf("input %s can't set both mount and read_ahead", input.Name)
+	case input.EmptyFiles:
+		return fmt.Errorf("input %s can't set both mount and empty_files", input.Name)
+	}
+	return nil
+}
+
+func validateCache(cache *pps.Cache) error {
+	if cache == nil {
+		return nil
+	}
+	p := path.Clean(cache.Path)
+	switch {
+	case !path.IsAbs(p):
+		return fmt.Errorf("path must be absolute, not %q", cache.Path)
+	case p == "/":
+		return fmt.Errorf("path can't be /")
+	case p == client.PPSInputPrefix || strings.HasPrefix(p, client.PPSInputPrefix+"/"):
+		return fmt.Errorf("path can't be in %s", client.PPSInputPrefix)
+	}
+	if cache.SizeLimit != "" {
+		limit, err := resource.ParseQuantity(cache.SizeLimit)
+		if err != nil {
+			return fmt.Errorf("could not parse size_limit: %v", err)
+		}
+		if limit.Sign() <= 0 {
+			return fmt.Errorf("size_limit must be positive, not %s", cache.SizeLimit)
+		}
+	}
+	return nil
+}
+
+func validateScratchVolume(volume *pps.ScratchVolume) error {
+	if volume == nil {
+		return nil
+	}
+	if volume.Capacity != "" {
+		capacity, err := resource.ParseQuantity(volume.Capacity)
+		if err != nil {
+			return fmt.Errorf("could not parse capacity: %v", err)
+		}
+		if capacity.Sign() <= 0 {
+			return fmt.Errorf("capacity must be positive, not %s", volume.Capacity)
+		}
+	}
+	switch v1.StorageMedium(volume.Medium) {
+	case v1.StorageMediumDefault, v1.StorageMediumMemory:
+	default:
+		return fmt.Errorf("medium must be \"\" or %q, not %q", v1.StorageMediumMemory, volume.Medium)
+	}
+	if volume.ClaimName != "" && volume.Medium != "" {
+		return fmt.Errorf("medium can't be set with claim_name")
+	}
+	return nil
+}
+
+func validateDefer(d *pps.Defer) error {
+	if d.Commits < 0 {
+		return fmt.Errorf("commits can't be negative")
+	}
+	if d.CronSpec != "" {
+		if _, err := cron.ParseStandard(d.CronSpec); err != nil {
+			return fmt.Errorf("could not parse cron_spec: %v", err)
+		}
+	}
+	return nil
+}
+
+// metricsPushLabels are the labels that workers add to the metrics that they
+// push themselves, which a pipeline's metrics_push.labels can't override
+var metricsPushLabels = map[string]bool{"pipeline": true, "job": true, "state": true, "worker": true}
+
+func validateMetricsPush(metricsPush *pps.MetricsPush) error {
+	if metricsPush == nil {
+		return nil
+	}
+	if metricsPush.PushgatewayURL == "" && metricsPush.StatsdAddress == "" {
+		return fmt.Errorf("must set pushgateway_url or statsd_address")
+	}
+	if metricsPush.PushgatewayURL != "" {
+		u, err := url.Parse(metricsPush.PushgatewayURL)
+		if err != nil {
+			return fmt.Errorf("could not parse pushgateway_url: %v", err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("pushgateway_url must be an http(s) URL, not %q", metricsPush.PushgatewayURL)
+		}
+	}
+	if metricsPush.StatsdAddress != "" {
+		if _, _, err := net.SplitHostPort(metricsPush.StatsdAddress); err != nil {
+			return fmt.Errorf("statsd_address must be host:port: %v", err)
+		}
+	}
+	for name := range metricsPush.Labels {
+		if !model.LabelName(name).IsValid() || strings.HasPrefix(name, "__") {
+			return fmt.Errorf("invalid label name %q", name)
+		}
+		if metricsPushLabels[name] {
+			return fmt.Errorf("label %q is set by the workers, and can't be overridden", name)
+		}
+	}
+	if metricsPush.Interval != nil {
+		interval, err := types.DurationFromProto(metricsPush.Interval)
+		if err != nil {
+			return err
+		}
+		if interval <= 0 {
+			return fmt.Errorf("interval must be positive, not %v", interval)
+		}
+	}
+	return nil
+}
+
+func validateEgress(egress *pps.Egress) error {
+	if egress == nil {
+		return nil
+	}
+	if egress.Postgres == nil {
+		if _, err := obj.ParseURL(egress.URL); err != nil {
+			return err
+		}
+		return nil
+	}
+	if egress.URL != "" {
+		return fmt.Errorf("egress can't set both a URL and a Postgres database")
+	}
+	u, err := url.Parse(egress.Postgres.URL)
+	if err != nil {
+		return fmt.Errorf("could not parse database URL: %v", err)
+	}
+	switch u.Scheme {
+	case "postgres", "postgresql":
+	case "":
+		return fmt.Errorf("database URL must be set")
+	default:
+		return fmt.Errorf("unsupported database %q (only postgres is supported)", u.Scheme)
+	}
+	if _, ok := u.User.Password(); ok {
+		return fmt.Errorf("database URL must not include a password, use password_env")
+	}
+	switch egress.Postgres.FileFormat {
+	case "", "csv":
+	case "text":
+		if egress.Postgres.Header {
+			return fmt.Errorf("header can only be set for csv files")
+		}
+	default:
+		return fmt.Errorf("unsupported file format %q (must be \"csv\" or \"text\")", egress.Postgres.FileFormat)
+	}
+	return nil
+}
+
+func validateSchedulingSpec(spec *pps.SchedulingSpec) error {
+	if spec == nil {
+		return nil
+	}
+	for _, t := range spec.Tolerations {
+		switch v1.TolerationOperator(t.Operator) {
+		case "", v1.TolerationOpEqual:
+		case v1.TolerationOpExists:
+			if t.Value != "" {
+				return fmt.Errorf("toleration for %q uses the Exists operator, so it can't have a value", t.Key)
+			}
+		default:
+			return fmt.Errorf("toleration for %q has unknown operator %q", t.Key, t.Operator)
+		}
+		switch v1.TaintEffect(t.Effect) {
+		case "", v1.TaintEffectNoSchedule, v1.TaintEffectPreferNoSchedule, v1.TaintEffectNoExecute:
+		default:
+			return fmt.Errorf("toleration for %q has unknown effect %q", t.Key, t.Effect)
+		}
+		if t.TolerationSeconds != 0 && v1.TaintEffect(t.Effect) != v1.TaintEffectNoExecute {
+			return fmt.Errorf("toleration for %q sets toleration_seconds, which is only valid for the NoExecute effect", t.Key)
+		}
+	}
+	var requirements []*pps.NodeSelectorRequirement
+	requirements = append(requirements, spec.RequiredNodeAffinity...)
+	requirements = append(requirements, spec.PreferredNodeAffinity...)
+	for _, r := range requirements {
+		if r.Key == "" {
+			return fmt.Errorf("node affinity requirements must have a key")
+		}
+		switch v1.NodeSelectorOperator(r.Operator) {
+		case v1.NodeSelectorOpIn, v1.NodeSelectorOpNotIn:
+			if len(r.Values) == 0 {
+				return fmt.Errorf("node affinity requirement for %q needs at least one value", r.Key)
+			}
+		case v1.NodeSelectorOpExists, v1.NodeSelectorOpDoesNotExist:
+			if len(r.Values) != 0 {
+				return fmt.Errorf("node affinity requirement for %q can't have values with operator %q", r.Key, r.Operator)
+			}
+		case v1.NodeSelectorOpGt, v1.NodeSelectorOpLt:
+			if len(r.Values) != 1 {
+				return fmt.Errorf("node affinity requirement for %q needs exactly one value with operator %q", r.Key, r.Operator)
+			}
+		default:
+			return fmt.Errorf("node affinity requirement for %q has unknown operator %q", r.Key, r.Operator)
+		}
+	}
 	return nil
 }
 
@@ -497,11 +792,12 @@ func (a *apiServer) CreateJob(ctx context.Context, request *pps.CreateJobRequest
 			DataTotal:     request.DataTotal,
 			DataFailed:    request.DataFailed,
 			DataRecovered: request.DataRecovered,
+			FailureCounts: request.FailureCounts,
 			StatsCommit:   request.StatsCommit,
 			Started:       request.Started,
 			Finished:      request.Finished,
 		}
-		return ppsutil.UpdateJobState(a.pipelines.ReadWrite(stm), a.jobs.ReadWrite(stm), jobPtr, request.State, request.Reason)
+		return ppsutil.UpdateJobState(a.pipelines.ReadWrite(stm), a.jobs.ReadWrite(stm), jobPtr, request.State, request.Reason, request.FailureType)
 	})
 	if err != nil {
 		return nil, err
@@ -541,7 +837,7 @@ func (a *apiServer) InspectJob(ctx context.Context, request *pps.InspectJobReque
 			return nil, err
 		}
 		if request.Job == nil {
-			return nil, fmt.Errorf("job with output commit %s not found", request.OutputCommit.ID)
+			return nil, pps.ErrJobNotFound{OutputCommit: request.OutputCommit}
 		}
 	}
 
@@ -722,20 +1018,28 @@ func (a *apiServer) jobInfoFromPtr(pachClient *client.APIClient, jobPtr *pps.Etc
 		DataTotal:     jobPtr.DataTotal,
 		DataFailed:    jobPtr.DataFailed,
 		DataRecovered: jobPtr.DataRecovered,
+		FailureCounts: jobPtr.FailureCounts,
 		Stats:         jobPtr.Stats,
 		StatsCommit:   jobPtr.StatsCommit,
 		State:         jobPtr.State,
 		Reason:        jobPtr.Reason,
+		FailureType:   jobPtr.FailureType,
+		EgressStatus:  jobPtr.EgressStatus,
+		Paused:        jobPtr.Paused,
+		EmptyReason:   jobPtr.EmptyReason,
 		Started:       jobPtr.Started,
 		Finished:      jobPtr.Finished,
 	}
+	for _, nodes := range jobPtr.ShardNodesMerged {
+		result.NodesMerged += nodes
+	}
 	commitInfo, err := pachClient.InspectCommit(jobPtr.OutputCommit.Repo.Name, jobPtr.OutputCommit.ID)
 	if err != nil {
 		if isNotFoundErr(err) {
 			if _, err := a.DeleteJob(pachClient.Ctx(), &pps.DeleteJobRequest{Job: jobPtr.Job}); err != nil {
 				return nil, err
 			}
-			return nil, fmt.Errorf("job %s not found", jobPtr.Job.ID)
+			return nil, pps.ErrJobNotFound{Job: jobPtr.Job}
 		}
 		return nil, err
 	}
@@ -777,6 +1081,8 @@ func (a *apiServer) jobInfoFromPtr(pachClient *client.APIClient, jobPtr *pps.Etc
 		result.Salt = pipelineInfo.Salt
 		result.ChunkSpec = pipelineInfo.ChunkSpec
 		result.DatumTimeout = pipelineInfo.DatumTimeout
+		result.DatumTimeoutPerMB = pipelineInfo.DatumTimeoutPerMB
+		result.DownloadTimeout = pipelineInfo.DownloadTimeout
 		result.JobTimeout = pipelineInfo.JobTimeout
 		result.DatumTries = pipelineInfo.DatumTries
 		result.SchedulingSpec = pipelineInfo.SchedulingSpec
@@ -837,6 +1143,16 @@ func (a *apiServer) FlushJob(request *pps.FlushJobRequest, resp pps.API_FlushJob
 	if err != nil {
 		return err
 	}
+	if request.Progress {
+		outputCommits, err := a.flushJobCommits(pachClient, request.Commits, request.ToPipelines)
+		if err != nil {
+			return err
+		}
+		return a.watchJobs(ctx, pachClient, nil, outputCommits, true, func(jobInfo *pps.JobInfo) error {
+			sent++
+			return resp.Send(jobInfo)
+		})
+	}
 	var toRepos []*pfs.Repo
 	for _, pipeline := range request.ToPipelines {
 		toRepos = append(toRepos, client.NewRepo(pipeline.Name))
@@ -872,6 +1188,242 @@ func (a *apiServer) FlushJob(request *pps.FlushJobRequest, resp pps.API_FlushJob
 	})
 }
 
+// flushJobCommits returns the commits that FlushJob waits for: the commits
+// that all of 'commits' are provenance of, in the output repos of
+// 'toPipelines' if any are given. Like FlushCommit, it doesn't wait for the
+// commits to finish, so their jobs can be watched as they run.
+func (a *apiServer) flushJobCommits(pachClient *client.APIClient, commits []*pfs.Commit, toPipelines []*pps.Pipeline) ([]*pfs.Commit, error) {
+	if len(commits) == 0 {
+		return nil, fmt.Errorf("must specify at least one commit")
+	}
+	var keys []string
+	subvenance := make(map[string]*pfs.Commit)
+	for i, commit := range commits {
+		commitInfo, err := pachClient.InspectCommit(commit.Repo.Name, commit.ID)
+		if err != nil {
+			return nil, err
+		}
+		seen := make(map[string]bool)
+		for _, subvCommit := range commitInfo.Subvenance {
+			key := path.Join(subvCommit.Upper.Repo.Name, subvCommit.Upper.ID)
+			seen[key] = true
+			if i == 0 && subvenance[key] == nil {
+				keys = append(keys, key)
+				subvenance[key] = subvCommit.Upper
+			}
+		}
+		for key := range subvenance {
+			if !seen[key] {
+				delete(subvenance, key)
+			}
+		}
+	}
+	toRepos := make(map[string]bool)
+	for _, pipeline := range toPipelines {
+		toRepos[pipeline.Name] = true
+	}
+	var result []*pfs.Commit
+	for _, key := range keys {
+		commit, ok := subvenance[key]
+		if !ok || (len(toRepos) > 0 && !toRepos[commit.Repo.Name]) {
+			continue
+		}
+		result = append(result, commit)
+	}
+	return result, nil
+}
+
+// WaitJob implements the protobuf pps.WaitJob RPC
+func (a *apiServer) WaitJob(request *pps.WaitJobRequest, resp pps.API_WaitJobServer) (retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	sent := 0
+	defer func(start time.Time) {
+		a.Log(request, fmt.Sprintf("stream containing %d JobInfos", sent), retErr, time.Since(start))
+	}(time.Now())
+	pachClient := a.env.GetPachClient(resp.Context())
+	ctx, err := checkLoggedIn(pachClient)
+	if err != nil {
+		return err
+	}
+	if (request.Job == nil) == (request.Commit == nil) {
+		return fmt.Errorf("must specify either a Job or a Commit")
+	}
+
+	// Collect the jobs to wait for. Jobs are created shortly after their output
+	// commits, so the jobs downstream of Commit are found by the output commits
+	// in its subvenance, which may not have a job yet.
+	var jobIDs []string
+	commit := request.Commit
+	if request.Job != nil {
+		jobPtr := &pps.EtcdJobInfo{}
+		if err := a.jobs.ReadOnly(ctx).Get(request.Job.ID, jobPtr); err != nil {
+			return err
+		}
+		jobIDs = append(jobIDs, request.Job.ID)
+		commit = jobPtr.OutputCommit
+	}
+	commitInfo, err := pachClient.InspectCommit(commit.Repo.Name, commit.ID)
+	if err != nil {
+		return err
+	}
+	var outputCommits []*pfs.Commit
+	if request.Job == nil {
+		isOutput, err := a.isJobOutputCommit(pachClient, commitInfo.Commit)
+		if err != nil {
+			return err
+		}
+		if isOutput {
+			outputCommits = append(outputCommits, commitInfo.Commit)
+		} else if !request.Downstream {
+			return fmt.Errorf("commit %s@%s isn't the output commit of a job, "+
+				"wait for the jobs downstream of it instead", commitInfo.Commit.Repo.Name, commitInfo.Commit.ID)
+		}
+	}
+	if request.Downstream {
+		seen := make(map[string]bool)
+		for _, subvCommit := range commitInfo.Subvenance {
+			key := path.Join(subvCommit.Upper.Repo.Name, subvCommit.Upper.ID)
+			if !seen[key] {
+				seen[key] = true
+				outputCommits = append(outputCommits, subvCommit.Upper)
+			}
+		}
+	}
+
+	return a.watchJobs(ctx, pachClient, jobIDs, outputCommits, false, func(jobInfo *pps.JobInfo) error {
+		sent++
+		return resp.Send(jobInfo)
+	})
+}
+
+// watchJobs calls 'send' with the JobInfos of the jobs in 'jobIDs', and of
+// the jobs whose output commits are in 'outputCommits' (which may not have
+// been created yet), each time their states change (or, if 'progress' is
+// set, their progress changes), until they've all finished. 'send' is never
+// called concurrently.
+func (a *apiServer) watchJobs(ctx context.Context, pachClient *client.APIClient, jobIDs []string, outputCommits []*pfs.Commit, progress bool, send func(*pps.JobInfo) error) error {
+	var mu sync.Mutex
+	sendLocked := func(jobInfo *pps.JobInfo) error {
+		mu.Lock()
+		defer mu.Unlock()
+		return send(jobInfo)
+	}
+	var eg errgroup.Group
+	for _, jobID := range jobIDs {
+		jobID := jobID
+		eg.Go(func() error {
+			watcher, err := a.jobs.ReadOnly(ctx).WatchOne(jobID)
+			if err != nil {
+				return err
+			}
+			defer watcher.Close()
+			return a.sendJobUpdates(pachClient, watcher, progress, sendLocked)
+		})
+	}
+	for _, commit := range outputCommits {
+		commit := commit
+		eg.Go(func() error {
+			isOutput, err := a.isJobOutputCommit(pachClient, commit)
+			if err != nil || !isOutput {
+				return err
+			}
+			watcher, err := a.jobs.ReadOnly(ctx).WatchByIndex(ppsdb.JobsOutputIndex, commit)
+			if err != nil {
+				return err
+			}
+			defer watcher.Close()
+			return a.sendJobUpdates(pachClient, watcher, progress, sendLocked)
+		})
+	}
+	return eg.Wait()
+}
+
+// isJobOutputCommit returns true if 'commit' is (or will be) the output commit
+// of a job, i.e. it's a commit to the output branch of a pipeline. Commits
+// that the caller can't access are skipped, as in FlushCommit.
+func (a *apiServer) isJobOutputCommit(pachClient *client.APIClient, commit *pfs.Commit) (bool, error) {
+	pipelineInfo, err := a.inspectPipeline(pachClient, commit.Repo.Name)
+	if err != nil {
+		if isNotFoundErr(err) || auth.IsErrNotAuthorized(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	commitInfo, err := pachClient.InspectCommit(commit.Repo.Name, commit.ID)
+	if err != nil {
+		if isNotFoundErr(err) || auth.IsErrNotAuthorized(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	// Commits to the pipeline's stats branch don't have jobs
+	return commitInfo.Branch == nil || commitInfo.Branch.Name == pipelineInfo.OutputBranch, nil
+}
+
+// jobUpdate is the part of a job that sendJobUpdates watches: it sends the
+// job's JobInfo each time this changes
+type jobUpdate struct {
+	state                                        pps.JobState
+	processed, skipped, failed, recovered, total int64
+	downloadBytes, uploadBytes                   uint64
+}
+
+func newJobUpdate(jobPtr *pps.EtcdJobInfo, progress bool) jobUpdate {
+	update := jobUpdate{state: jobPtr.State}
+	if progress {
+		update.processed = jobPtr.DataProcessed
+		update.skipped = jobPtr.DataSkipped
+		update.failed = jobPtr.DataFailed
+		update.recovered = jobPtr.DataRecovered
+		update.total = jobPtr.DataTotal
+		if jobPtr.Stats != nil {
+			update.downloadBytes = jobPtr.Stats.DownloadBytes
+			update.uploadBytes = jobPtr.Stats.UploadBytes
+		}
+	}
+	return update
+}
+
+// sendJobUpdates calls 'send' with the JobInfo of the job that 'watcher'
+// watches each time its state (or, if 'progress' is set, its progress)
+// changes, until the job finishes
+func (a *apiServer) sendJobUpdates(pachClient *client.APIClient, watcher watch.Watcher, progress bool, send func(*pps.JobInfo) error) error {
+	last := jobUpdate{state: pps.JobState(-1)}
+	for {
+		ev, ok := <-watcher.Watch()
+		if !ok {
+			return fmt.Errorf("the stream for job updates closed unexpectedly")
+		}
+		switch ev.Type {
+		case watch.EventError:
+			return ev.Err
+		case watch.EventDelete:
+			return fmt.Errorf("job %s was deleted", ev.Key)
+		case watch.EventPut:
+			var jobID string
+			jobPtr := &pps.EtcdJobInfo{}
+			if err := ev.Unmarshal(&jobID, jobPtr); err != nil {
+				return err
+			}
+			update := newJobUpdate(jobPtr, progress)
+			if update == last {
+				continue
+			}
+			last = update
+			jobInfo, err := a.jobInfoFromPtr(pachClient, jobPtr, false)
+			if err != nil {
+				return err
+			}
+			if err := send(jobInfo); err != nil {
+				return err
+			}
+			if ppsutil.IsTerminal(update.state) {
+				return nil
+			}
+		}
+	}
+}
+
 // DeleteJob implements the protobuf pps.DeleteJob RPC
 func (a *apiServer) DeleteJob(ctx context.Context, request *pps.DeleteJobRequest) (response *types.Empty, retErr error) {
 	func() { a.Log(request, nil, nil, 0) }()
@@ -918,6 +1470,57 @@ func (a *apiServer) StopJob(ctx context.Context, request *pps.StopJobRequest) (r
 	return &types.Empty{}, nil
 }
 
+// PauseJob implements the protobuf pps.PauseJob RPC
+func (a *apiServer) PauseJob(ctx context.Context, request *pps.PauseJobRequest) (response *types.Empty, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	if err := a.setJobPaused(a.env.GetPachClient(ctx), request.Job, true); err != nil {
+		return nil, err
+	}
+	return &types.Empty{}, nil
+}
+
+// ResumeJob implements the protobuf pps.ResumeJob RPC
+func (a *apiServer) ResumeJob(ctx context.Context, request *pps.ResumeJobRequest) (response *types.Empty, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	if err := a.setJobPaused(a.env.GetPachClient(ctx), request.Job, false); err != nil {
+		return nil, err
+	}
+	return &types.Empty{}, nil
+}
+
+// setJobPaused pauses or resumes 'job'. The job's workers watch its
+// EtcdJobInfo, and stop starting datums while it's paused.
+func (a *apiServer) setJobPaused(pachClient *client.APIClient, job *pps.Job, paused bool) error {
+	ctx, err := checkLoggedIn(pachClient)
+	if err != nil {
+		return err
+	}
+	if job == nil || job.ID == "" {
+		return fmt.Errorf("must specify a job")
+	}
+	jobPtr := &pps.EtcdJobInfo{}
+	if err := a.jobs.ReadOnly(ctx).Get(job.ID, jobPtr); err != nil {
+		return err
+	}
+	// Pausing a job requires the same access as updating its pipeline
+	if err := a.authorizePipelineOp(pachClient, pipelineOpUpdate, nil, jobPtr.Pipeline.Name); err != nil {
+		return err
+	}
+	_, err = col.NewSTM(ctx, a.env.GetEtcdClient(), func(stm col.STM) error {
+		jobPtr := &pps.EtcdJobInfo{}
+		return a.jobs.ReadWrite(stm).Update(job.ID, jobPtr, func() error {
+			if ppsutil.IsTerminal(jobPtr.State) {
+				return fmt.Errorf("job %s is already finished (state: %s)", job.ID, jobPtr.State)
+			}
+			jobPtr.Paused = paused
+			return nil
+		})
+	})
+	return err
+}
+
 // RestartDatum implements the protobuf pps.RestartDatum RPC
 func (a *apiServer) RestartDatum(ctx context.Context, request *pps.RestartDatumRequest) (response *types.Empty, retErr error) {
 	func() { a.Log(request, nil, nil, 0) }()
@@ -941,13 +1544,78 @@ func (a *apiServer) RestartDatum(ctx context.Context, request *pps.RestartDatumR
 	return &types.Empty{}, nil
 }
 
+// RestartJob implements the protobuf pps.RestartJob RPC
+func (a *apiServer) RestartJob(ctx context.Context, request *pps.RestartJobRequest) (response *pps.RestartJobResponse, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	pachClient := a.env.GetPachClient(ctx)
+	ctx, err := checkLoggedIn(pachClient)
+	if err != nil {
+		return nil, err
+	}
+	if request.Job == nil || request.Job.ID == "" {
+		return nil, fmt.Errorf("must specify a job")
+	}
+	jobInfo, err := a.InspectJob(ctx, &pps.InspectJobRequest{
+		Job: request.Job,
+	})
+	if err != nil {
+		return nil, err
+	}
+	pipelineInfo, err := a.inspectPipeline(pachClient, jobInfo.Pipeline.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRestartable(jobInfo, pipelineInfo); err != nil {
+		return nil, err
+	}
+	// The new job's datums that the old job processed successfully are
+	// skipped, as their output is tagged with their hash
+	commit, err := a.runPipeline(pachClient, &pps.RunPipelineRequest{
+		Pipeline: jobInfo.Pipeline,
+		JobID:    jobInfo.Job.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pps.RestartJobResponse{OutputCommit: commit}, nil
+}
+
+// checkRestartable returns an error if 'jobInfo' can't be restarted by
+// RestartJob. Only finished jobs that didn't succeed can be, and only by the
+// version of their pipeline that ran them, as other versions process
+// different datums (and so would process all of them again).
+func checkRestartable(jobInfo *pps.JobInfo, pipelineInfo *pps.PipelineInfo) error {
+	if pipelineInfo.Service != nil || pipelineInfo.Spout != nil {
+		return fmt.Errorf("the jobs of service and spout pipelines can't be restarted")
+	}
+	switch jobInfo.State {
+	case pps.JobState_JOB_FAILURE, pps.JobState_JOB_KILLED:
+	case pps.JobState_JOB_SUCCESS:
+		return fmt.Errorf("job %s succeeded, so it has no failed datums to restart", jobInfo.Job.ID)
+	default:
+		return fmt.Errorf("job %s is still %s, it can only be restarted once it's finished",
+			jobInfo.Job.ID, strings.ToLower(strings.TrimPrefix(jobInfo.State.String(), "JOB_")))
+	}
+	if jobInfo.PipelineVersion != pipelineInfo.Version {
+		return fmt.Errorf("job %s was run by version %d of pipeline %s, which has since been updated to version %d",
+			jobInfo.Job.ID, jobInfo.PipelineVersion, pipelineInfo.Pipeline.Name, pipelineInfo.Version)
+	}
+	return nil
+}
+
 // listDatum contains our internal implementation of ListDatum, which is shared
 // between ListDatum and ListDatumStream. When ListDatum is removed, this should
 // be inlined into ListDatumStream
-func (a *apiServer) listDatum(pachClient *client.APIClient, job *pps.Job, page, pageSize int64) (response *pps.ListDatumResponse, retErr error) {
+func (a *apiServer) listDatum(pachClient *client.APIClient, request *pps.ListDatumRequest) (response *pps.ListDatumResponse, retErr error) {
 	if _, err := checkLoggedIn(pachClient); err != nil {
 		return nil, err
 	}
+	filter, err := newDatumFilter(request)
+	if err != nil {
+		return nil, err
+	}
+	job, page, pageSize := request.Job, request.Page, request.PageSize
 	response = &pps.ListDatumResponse{}
 	ctx := pachClient.Ctx()
 	pfsClient := pachClient.PfsAPIClient
@@ -988,6 +1656,21 @@ func (a *apiServer) listDatum(pachClient *client.APIClient, job *pps.Job, page,
 		}
 		return 0, 0, goerr.New("getPageBounds: unreachable code")
 	}
+	// paginate sets the datums of the response to the requested page of
+	// 'datumInfos'
+	paginate := func(datumInfos []*pps.DatumInfo) error {
+		if pageSize > 0 && len(datumInfos) > 0 {
+			response.Page = page
+			response.TotalPages = getTotalPages(len(datumInfos))
+			start, end, err := getPageBounds(len(datumInfos))
+			if err != nil {
+				return err
+			}
+			datumInfos = datumInfos[start:end]
+		}
+		response.DatumInfos = datumInfos
+		return nil
+	}
 
 	df, err := workerpkg.NewDatumIterator(pachClient, jobInfo.Input)
 	if err != nil {
@@ -997,7 +1680,8 @@ func (a *apiServer) listDatum(pachClient *client.APIClient, job *pps.Job, page,
 	if jobInfo.StatsCommit == nil {
 		start := 0
 		end := df.Len()
-		if pageSize > 0 {
+		// Without a filter, only the requested page's datums are computed
+		if pageSize > 0 && filter == nil {
 			var err error
 			start, end, err = getPageBounds(df.Len())
 			if err != nil {
@@ -1022,7 +1706,15 @@ func (a *apiServer) listDatum(pachClient *client.APIClient, job *pps.Job, page,
 			}
 			datumInfos = append(datumInfos, datumInfo)
 		}
-		response.DatumInfos = datumInfos
+		if filter == nil {
+			response.Summary = &pps.DatumSummary{Total: int64(df.Len()), Starting: int64(df.Len())}
+			response.DatumInfos = datumInfos
+			return response, nil
+		}
+		datumInfos, response.Summary = filterDatums(filter, datumInfos)
+		if err := paginate(datumInfos); err != nil {
+			return nil, err
+		}
 		return response, nil
 	}
 
@@ -1041,9 +1733,11 @@ func (a *apiServer) listDatum(pachClient *client.APIClient, job *pps.Job, page,
 	}
 	// Omit files at the top level that correspond to aggregate job stats
 	blacklist := map[string]bool{
-		"stats": true,
-		"logs":  true,
-		"pfs":   true,
+		"stats":                            true,
+		"logs":                             true,
+		"pfs":                              true,
+		path.Base(ppsutil.AttestationFile): true,
+		path.Base(ppsutil.AttestationSignatureFile): true,
 	}
 	pathToDatumHash := func(path string) (string, error) {
 		_, datumHash := filepath.Split(path)
@@ -1094,16 +1788,10 @@ func (a *apiServer) listDatum(pachClient *client.APIClient, job *pps.Job, page,
 	sort.Slice(datumInfos, func(i, j int) bool {
 		return datumInfos[i].State < datumInfos[j].State
 	})
-	if pageSize > 0 {
-		response.Page = page
-		response.TotalPages = getTotalPages(len(datumInfos))
-		start, end, err := getPageBounds(len(datumInfos))
-		if err != nil {
-			return nil, err
-		}
-		datumInfos = datumInfos[start:end]
+	datumInfos, response.Summary = filterDatums(filter, datumInfos)
+	if err := paginate(datumInfos); err != nil {
+		return nil, err
 	}
-	response.DatumInfos = datumInfos
 	return response, nil
 }
 
@@ -1117,13 +1805,14 @@ func (a *apiServer) ListDatum(ctx context.Context, request *pps.ListDatumRequest
 				TotalPages: response.TotalPages,
 				Page:       response.Page,
 				DatumInfos: response.DatumInfos[:client.MaxListItemsLog],
+				Summary:    response.Summary,
 			}
 			a.Log(request, logResponse, retErr, time.Since(start))
 		} else {
 			a.Log(request, response, retErr, time.Since(start))
 		}
 	}(time.Now())
-	return a.listDatum(a.env.GetPachClient(ctx), request.Job, request.Page, request.PageSize)
+	return a.listDatum(a.env.GetPachClient(ctx), request)
 }
 
 // ListDatumStream implements the protobuf pps.ListDatumStream RPC
@@ -1133,16 +1822,23 @@ func (a *apiServer) ListDatumStream(req *pps.ListDatumRequest, resp pps.API_List
 	defer func(start time.Time) {
 		a.Log(req, fmt.Sprintf("stream containing %d DatumInfos", sent), retErr, time.Since(start))
 	}(time.Now())
-	ldr, err := a.listDatum(a.env.GetPachClient(resp.Context()), req.Job, req.Page, req.PageSize)
+	ldr, err := a.listDatum(a.env.GetPachClient(resp.Context()), req)
 	if err != nil {
 		return err
 	}
+	if len(ldr.DatumInfos) == 0 && (len(req.State) > 0 || req.InputGlob != "") {
+		// Send the summary, so that the client can tell why nothing matched.
+		// Requests without filters (i.e. from older clients, which don't
+		// expect responses without a datum) don't get one.
+		return resp.Send(&pps.ListDatumStreamResponse{Summary: ldr.Summary})
+	}
 	first := true
 	for _, di := range ldr.DatumInfos {
 		r := &pps.ListDatumStreamResponse{}
 		if first {
 			r.Page = ldr.Page
 			r.TotalPages = ldr.TotalPages
+			r.Summary = ldr.Summary
 			first = false
 		}
 		r.DatumInfo = di
@@ -1258,7 +1954,203 @@ func (a *apiServer) InspectDatum(ctx context.Context, request *pps.InspectDatumR
 		return nil, err
 	}
 
-	return datumInfo, nil
+	return datumInfo, nil
+}
+
+// InspectJobAttestation implements the protobuf pps.InspectJobAttestation RPC
+func (a *apiServer) InspectJobAttestation(ctx context.Context, request *pps.InspectJobAttestationRequest) (response *pps.JobAttestationInfo, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	pachClient := a.env.GetPachClient(ctx)
+	ctx, err := checkLoggedIn(pachClient)
+	if err != nil {
+		return nil, err
+	}
+	jobInfo, err := a.InspectJob(ctx, &pps.InspectJobRequest{Job: request.Job})
+	if err != nil {
+		return nil, err
+	}
+	// authorize InspectJobAttestation (must have READER access to all inputs,
+	// like InspectJobStats)
+	if err := a.authorizePipelineOp(pachClient,
+		pipelineOpListDatum,
+		jobInfo.Input,
+		jobInfo.Pipeline.Name,
+	); err != nil {
+		return nil, err
+	}
+	if !jobInfo.EnableStats {
+		return nil, fmt.Errorf("stats not enabled on %v, so its jobs have no attestations", jobInfo.Pipeline.Name)
+	}
+	if jobInfo.StatsCommit == nil {
+		return nil, fmt.Errorf("job not finished, no attestation yet")
+	}
+	statsCommit := jobInfo.StatsCommit
+	var attestation, signature bytes.Buffer
+	if err := pachClient.GetFile(statsCommit.Repo.Name, statsCommit.ID, ppsutil.AttestationFile, 0, 0, &attestation); err != nil {
+		return nil, fmt.Errorf("could not read the attestation of job %s (only jobs that succeed have one): %v", jobInfo.Job.ID, err)
+	}
+	if err := pachClient.GetFile(statsCommit.Repo.Name, statsCommit.ID, ppsutil.AttestationSignatureFile, 0, 0, &signature); err != nil {
+		return nil, fmt.Errorf("could not read the signature of the attestation of job %s: %v", jobInfo.Job.ID, err)
+	}
+	key, err := ppsutil.GetAttestationKey(ctx, a.env.GetEtcdClient(), a.etcdPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("could not get the attestation key: %v", err)
+	}
+	publicKey := key.Public().(ed25519.PublicKey)
+	result, sig, err := ppsutil.VerifyAttestation(publicKey, attestation.Bytes(), signature.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("could not verify the attestation of job %s: %v", jobInfo.Job.ID, err)
+	}
+	// A valid attestation of another job doesn't attest to this one
+	if result.Job == nil || result.Job.ID != jobInfo.Job.ID {
+		return nil, fmt.Errorf("could not verify the attestation of job %s: it's the attestation of another job", jobInfo.Job.ID)
+	}
+	return &pps.JobAttestationInfo{
+		Attestation: result,
+		Signature:   sig,
+		PublicKey:   publicKey,
+	}, nil
+}
+
+// InspectJobStats implements the protobuf pps.InspectJobStats RPC
+func (a *apiServer) InspectJobStats(ctx context.Context, request *pps.InspectJobStatsRequest) (response *pps.JobStats, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	pachClient := a.env.GetPachClient(ctx)
+	ctx, err := checkLoggedIn(pachClient)
+	if err != nil {
+		return nil, err
+	}
+	jobInfo, err := a.InspectJob(ctx, &pps.InspectJobRequest{Job: request.Job})
+	if err != nil {
+		return nil, err
+	}
+	// authorize InspectJobStats (must have READER access to all inputs, like
+	// ListDatum)
+	if err := a.authorizePipelineOp(pachClient,
+		pipelineOpListDatum,
+		jobInfo.Input,
+		jobInfo.Pipeline.Name,
+	); err != nil {
+		return nil, err
+	}
+	if !jobInfo.EnableStats {
+		return nil, fmt.Errorf("stats not enabled on %v", jobInfo.Pipeline.Name)
+	}
+	if jobInfo.StatsCommit == nil {
+		return nil, fmt.Errorf("job not finished, no stats output yet")
+	}
+	statsCommit := jobInfo.StatsCommit
+	response = &pps.JobStats{Job: jobInfo.Job}
+
+	// Datums whose job marker belongs to another job were skipped, and their
+	// stats were copied from the parent stats commit
+	var processed []string
+	if err := pachClient.GlobFileF(statsCommit.Repo.Name, statsCommit.ID, "/*/job:*", func(fi *pfs.FileInfo) error {
+		datumID, marker := path.Split(fi.File.Path)
+		if strings.TrimPrefix(marker, "job:") != jobInfo.Job.ID {
+			response.DatumsSkipped++
+			return nil
+		}
+		processed = append(processed, path.Base(datumID))
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	response.DatumsProcessed = int64(len(processed))
+
+	var downloadTime, processTime, uploadTime, downloadBytes, uploadBytes []float64
+	failures := make(map[string]int64)
+	var buf bytes.Buffer
+	for _, datumID := range processed {
+		buf.Reset()
+		if err := pachClient.GetFile(statsCommit.Repo.Name, statsCommit.ID, fmt.Sprintf("/%v/stats", datumID), 0, 0, &buf); err != nil {
+			return nil, err
+		}
+		stats := &pps.ProcessStats{}
+		if err := jsonpb.Unmarshal(&buf, stats); err != nil {
+			return nil, err
+		}
+		downloadTime = append(downloadTime, durationSeconds(stats.DownloadTime))
+		processTime = append(processTime, durationSeconds(stats.ProcessTime))
+		uploadTime = append(uploadTime, durationSeconds(stats.UploadTime))
+		downloadBytes = append(downloadBytes, float64(stats.DownloadBytes))
+		uploadBytes = append(uploadBytes, float64(stats.UploadBytes))
+
+		buf.Reset()
+		if err := pachClient.GetFile(statsCommit.Repo.Name, statsCommit.ID, fmt.Sprintf("/%v/failure", datumID), 0, 0, &buf); err != nil {
+			if isNotFoundErr(err) {
+				continue
+			}
+			return nil, err
+		}
+		response.DatumsFailed++
+		failures[strings.TrimSpace(buf.String())]++
+	}
+	response.Stats = &pps.AggregateProcessStats{
+		DownloadTime:  aggregate(downloadTime),
+		ProcessTime:   aggregate(processTime),
+		UploadTime:    aggregate(uploadTime),
+		DownloadBytes: aggregate(downloadBytes),
+		UploadBytes:   aggregate(uploadBytes),
+	}
+	for message, count := range failures {
+		response.Failures = append(response.Failures, &pps.FailureCount{
+			Message: message,
+			Count:   count,
+		})
+	}
+	// most common failures first
+	sort.Slice(response.Failures, func(i, j int) bool {
+		if response.Failures[i].Count != response.Failures[j].Count {
+			return response.Failures[i].Count > response.Failures[j].Count
+		}
+		return response.Failures[i].Message < response.Failures[j].Message
+	})
+	return response, nil
+}
+
+// durationSeconds converts 'd' to seconds, treating a missing duration as 0
+func durationSeconds(d *types.Duration) float64 {
+	duration, err := types.DurationFromProto(d)
+	if err != nil {
+		return 0
+	}
+	return duration.Seconds()
+}
+
+// aggregate computes summary statistics of 'values'. Percentiles use the
+// nearest-rank method.
+func aggregate(values []float64) *pps.Aggregate {
+	result := &pps.Aggregate{Count: int64(len(values))}
+	if len(values) == 0 {
+		return result
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	var sum float64
+	for _, v := range sorted {
+		sum += v
+	}
+	result.Mean = sum / float64(len(sorted))
+	var squares float64
+	for _, v := range sorted {
+		squares += (v - result.Mean) * (v - result.Mean)
+	}
+	result.Stddev = math.Sqrt(squares / float64(len(sorted)))
+	percentile := func(p float64) float64 {
+		rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+		if rank < 0 {
+			rank = 0
+		}
+		return sorted[rank]
+	}
+	result.FifthPercentile = percentile(5)
+	result.Median = percentile(50)
+	result.NinetyFifthPercentile = percentile(95)
+	result.NinetyNinthPercentile = percentile(99)
+	return result
 }
 
 // GetLogs implements the protobuf pps.GetLogs RPC
@@ -1570,6 +2462,31 @@ func (a *apiServer) validatePipeline(pachClient *client.APIClient, pipelineInfo
 	if _, err := resource.ParseQuantity(pipelineInfo.CacheSize); err != nil {
 		return fmt.Errorf("could not parse cacheSize '%s': %v", pipelineInfo.CacheSize, err)
 	}
+	if pipelineInfo.PrefetchSize != "" {
+		if _, err := resource.ParseQuantity(pipelineInfo.PrefetchSize); err != nil {
+			return fmt.Errorf("could not parse prefetchSize '%s': %v", pipelineInfo.PrefetchSize, err)
+		}
+	}
+	if limit := pipelineInfo.BandwidthLimit; limit != nil {
+		for name, value := range map[string]string{"download": limit.Download, "upload": limit.Upload} {
+			if value == "" {
+				continue
+			}
+			quantity, err := resource.ParseQuantity(value)
+			if err != nil {
+				return fmt.Errorf("could not parse bandwidth_limit.%s '%s': %v", name, value, err)
+			}
+			if quantity.Sign() <= 0 {
+				return fmt.Errorf("bandwidth_limit.%s must be positive, not '%s'", name, value)
+			}
+		}
+	}
+	if pipelineInfo.CPUPinning && pipelineInfo.Transform.SeparateContainer {
+		return fmt.Errorf("cpu_pinning isn't supported with separate_container")
+	}
+	if pipelineInfo.Transform.SeparateContainer && ppsutil.HasMountedInputs(pipelineInfo.Input) {
+		return fmt.Errorf("mounted inputs aren't supported with separate_container")
+	}
 	if pipelineInfo.JobTimeout != nil {
 		_, err := types.DurationFromProto(pipelineInfo.JobTimeout)
 		if err != nil {
@@ -1582,6 +2499,228 @@ func (a *apiServer) validatePipeline(pachClient *client.APIClient, pipelineInfo
 			return err
 		}
 	}
+	if pipelineInfo.DatumTimeoutPerMB != nil {
+		datumTimeoutPerMB, err := types.DurationFromProto(pipelineInfo.DatumTimeoutPerMB)
+		if err != nil {
+			return err
+		}
+		if datumTimeoutPerMB < 0 {
+			return fmt.Errorf("datum_timeout_per_mb can't be negative")
+		}
+	}
+	if pipelineInfo.DownloadTimeout != nil {
+		downloadTimeout, err := types.DurationFromProto(pipelineInfo.DownloadTimeout)
+		if err != nil {
+			return err
+		}
+		if downloadTimeout <= 0 {
+			return fmt.Errorf("download_timeout must be positive")
+		}
+	}
+	if pipelineInfo.ChunkSpec != nil && pipelineInfo.ChunkSpec.TargetDuration != nil {
+		targetDuration, err := types.DurationFromProto(pipelineInfo.ChunkSpec.TargetDuration)
+		if err != nil {
+			return err
+		}
+		if targetDuration <= 0 {
+			return goerr.New("ChunkSpec.TargetDuration must be positive")
+		}
+		if !pipelineInfo.EnableStats {
+			return goerr.New("ChunkSpec.TargetDuration requires enable_stats")
+		}
+	}
+	if spec := pipelineInfo.ChunkSpec; spec != nil && spec.Strategy == pps.ChunkStrategy_CHUNKS_SIZE_BALANCED {
+		if spec.Number != 0 || spec.SizeBytes != 0 || spec.TargetDuration != nil {
+			return goerr.New("ChunkSpec.Strategy CHUNKS_SIZE_BALANCED chooses the sizes of chunks itself, so it can't be combined with ChunkSpec.Number, SizeBytes or TargetDuration")
+		}
+	}
+	if pipelineInfo.StatsSampleRate != 0 {
+		if pipelineInfo.StatsSampleRate < 0 || pipelineInfo.StatsSampleRate > 1 {
+			return fmt.Errorf("stats_sample_rate must be between 0 and 1, not %v", pipelineInfo.StatsSampleRate)
+		}
+		if !pipelineInfo.EnableStats {
+			return goerr.New("stats_sample_rate requires enable_stats")
+		}
+	}
+	if pipelineInfo.MaxFailedDatumsPercent < 0 || pipelineInfo.MaxFailedDatumsPercent > 100 {
+		return fmt.Errorf("max_failed_datums_percent must be between 0 and 100, not %v", pipelineInfo.MaxFailedDatumsPercent)
+	}
+	if err := validateSchedulingSpec(pipelineInfo.SchedulingSpec); err != nil {
+		return fmt.Errorf("invalid scheduling spec: %v", err)
+	}
+	if pipelineInfo.SpeculationFactor != 0 && pipelineInfo.SpeculationFactor < 1 {
+		return fmt.Errorf("speculation factor must be 0 (disabled) or at least 1, not %v",
+			pipelineInfo.SpeculationFactor)
+	}
+	if pipelineInfo.SpeculationFactor != 0 && pipelineInfo.Service != nil {
+		return goerr.New("services can't use speculative execution")
+	}
+	if err := validateJobRetention(pipelineInfo.JobRetention); err != nil {
+		return fmt.Errorf("invalid job retention: %v", err)
+	}
+	if err := validateEgress(pipelineInfo.Egress); err != nil {
+		return fmt.Errorf("invalid egress: %v", err)
+	}
+	if err := validateCache(pipelineInfo.Cache); err != nil {
+		return fmt.Errorf("invalid cache: %v", err)
+	}
+	if err := validateScratchVolume(pipelineInfo.ScratchVolume); err != nil {
+		return fmt.Errorf("invalid scratch_volume: %v", err)
+	}
+	if err := validateMetricsPush(pipelineInfo.MetricsPush); err != nil {
+		return fmt.Errorf("invalid metrics_push: %v", err)
+	}
+	if pipelineInfo.Defer != nil {
+		if pipelineInfo.Service != nil || pipelineInfo.Spout != nil {
+			return goerr.New("services and spouts can't defer processing")
+		}
+		if err := validateDefer(pipelineInfo.Defer); err != nil {
+			return fmt.Errorf("invalid defer: %v", err)
+		}
+	}
+	if pipelineInfo.PreviousOutput {
+		if pipelineInfo.Service != nil || pipelineInfo.Spout != nil {
+			return goerr.New("services and spouts can't mount their previous output")
+		}
+		var named bool
+		pps.VisitInput(pipelineInfo.Input, func(input *pps.Input) {
+			switch {
+			case input.Pfs != nil && input.Pfs.Name == client.PPSPrevOutputName,
+				input.Cron != nil && input.Cron.Name == client.PPSPrevOutputName,
+				input.SQL != nil && input.SQL.Name == client.PPSPrevOutputName,
+				input.Git != nil && input.Git.Name == client.PPSPrevOutputName:
+				named = true
+			}
+		})
+		if named {
+			return fmt.Errorf("no input can be named %q, as the pipeline mounts its previous output at /pfs/%s",
+				client.PPSPrevOutputName, client.PPSPrevOutputName)
+		}
+	}
+	if pipelineInfo.JobScratch {
+		if pipelineInfo.Service != nil || pipelineInfo.Spout != nil {
+			return goerr.New("services and spouts don't run jobs, so they can't have a job scratch directory")
+		}
+		var named bool
+		pps.VisitInput(pipelineInfo.Input, func(input *pps.Input) {
+			switch {
+			case input.Pfs != nil && input.Pfs.Name == client.PPSJobScratchName,
+				input.Cron != nil && input.Cron.Name == client.PPSJobScratchName,
+				input.SQL != nil && input.SQL.Name == client.PPSJobScratchName,
+				input.Git != nil && input.Git.Name == client.PPSJobScratchName:
+				named = true
+			}
+		})
+		if named {
+			return fmt.Errorf("no input can be named %q, as the pipeline mounts its job scratch directory at /pfs/%s",
+				client.PPSJobScratchName, client.PPSJobScratchName)
+		}
+	}
+	if pipelineInfo.Quarantine != nil {
+		if pipelineInfo.Service != nil || pipelineInfo.Spout != nil {
+			return goerr.New("services and spouts don't process datums, so they can't have a quarantine repo")
+		}
+		repo := pipelineInfo.Quarantine.Repo
+		if err := ancestry.ValidateName(repo); err != nil {
+			return fmt.Errorf("invalid quarantine repo: %v", err)
+		}
+		if repo == pipelineInfo.Pipeline.Name {
+			return goerr.New("a pipeline's quarantine repo can't be its output repo")
+		}
+		var isInput bool
+		pps.VisitInput(pipelineInfo.Input, func(input *pps.Input) {
+			if input.Pfs != nil && input.Pfs.Repo == repo {
+				isInput = true
+			}
+		})
+		if isInput {
+			return fmt.Errorf("a pipeline's quarantine repo can't be one of its inputs, but %q is", repo)
+		}
+	}
+	if spec := pipelineInfo.AutoscalingSpec; spec != nil {
+		if pipelineInfo.Service != nil || pipelineInfo.Spout != nil {
+			return goerr.New("services and spouts don't process datums, so they can't be autoscaled")
+		}
+		if pipelineInfo.ParallelismSpec != nil &&
+			(pipelineInfo.ParallelismSpec.Constant != 0 || pipelineInfo.ParallelismSpec.Coefficient != 0) {
+			return goerr.New("a pipeline can set at most one of parallelism_spec and autoscaling_spec")
+		}
+		if spec.MinWorkers == 0 {
+			return goerr.New("autoscaling_spec.min_workers must be at least 1")
+		}
+		if spec.MaxWorkers < spec.MinWorkers {
+			return goerr.New("autoscaling_spec.max_workers can't be less than min_workers")
+		}
+		if spec.ScaleDownDelay != nil {
+			scaleDownDelay, err := types.DurationFromProto(spec.ScaleDownDelay)
+			if err != nil {
+				return err
+			}
+			if scaleDownDelay < 0 {
+				return goerr.New("autoscaling_spec.scale_down_delay can't be negative")
+			}
+		}
+	}
+	for _, n := range pipelineInfo.Notifications {
+		if err := validateNotification(n); err != nil {
+			return err
+		}
+	}
+	if _, ok := pps.DatumTreeCompression_name[int32(pipelineInfo.DatumTreeCompression)]; !ok {
+		return fmt.Errorf("unknown datum_tree_compression %d", pipelineInfo.DatumTreeCompression)
+	}
+	if _, ok := pps.JobOrder_name[int32(pipelineInfo.JobOrder)]; !ok {
+		return fmt.Errorf("unknown job_order %d", pipelineInfo.JobOrder)
+	}
+	if pipelineInfo.MergeSpec != nil {
+		if pipelineInfo.Service != nil || pipelineInfo.Spout != nil {
+			return goerr.New("services and spouts don't merge datums, so they can't have merge workers")
+		}
+		if pipelineInfo.MergeSpec.Workers == 0 {
+			return goerr.New("merge_spec.workers must be at least 1")
+		}
+	}
+	if pipelineInfo.Transform.DatumProcessor {
+		if pipelineInfo.Service != nil || pipelineInfo.Spout != nil {
+			return goerr.New("services and spouts don't process datums, so they can't have a datum processor")
+		}
+		if len(pipelineInfo.Transform.Cmd) == 0 {
+			return goerr.New("a pipeline with transform.datum_processor set must set transform.cmd")
+		}
+	}
+	if pipelineInfo.Transform.TemplateCmd {
+		if pipelineInfo.Service != nil || pipelineInfo.Spout != nil || pipelineInfo.Transform.DatumProcessor {
+			return goerr.New("transform.template_cmd is executed for each datum, so it can't be set for services, spouts or datum processors")
+		}
+		for _, args := range [][]string{pipelineInfo.Transform.Cmd, pipelineInfo.Transform.Stdin, pipelineInfo.Transform.ErrCmd, pipelineInfo.Transform.ErrStdin} {
+			if _, err := ppsutil.NewCmdTemplate(args); err != nil {
+				return fmt.Errorf("invalid transform: %v", err)
+			}
+		}
+	}
+	if stdoutFile := pipelineInfo.Transform.StdoutFile; stdoutFile != "" {
+		if pipelineInfo.Service != nil || pipelineInfo.Spout != nil || pipelineInfo.Transform.DatumProcessor {
+			return goerr.New("transform.stdout_file is written for each datum, so it can't be set for services, spouts or datum processors")
+		}
+		if clean := path.Clean(stdoutFile); path.IsAbs(stdoutFile) || clean == "." || clean == ".." || strings.HasPrefix(clean, "../") {
+			return fmt.Errorf("transform.stdout_file must be a file path relative to /pfs/out, but it's %q", stdoutFile)
+		}
+	}
+	if pipelineInfo.Transform.DatumSeed && (pipelineInfo.Service != nil || pipelineInfo.Spout != nil || pipelineInfo.Transform.DatumProcessor) {
+		return goerr.New("transform.datum_seed is set for each datum, so it can't be set for services, spouts or datum processors")
+	}
+	if pipelineInfo.InputWriteCheck != nil && (pipelineInfo.Service != nil || pipelineInfo.Spout != nil) {
+		return goerr.New("services and spouts don't process datums, so their inputs can't be checked for writes")
+	}
+	if pipelineInfo.Metadata != nil {
+		reserved := labels("")
+		reserved[pipelineNameLabel] = ""
+		for k := range pipelineInfo.Metadata.Labels {
+			if _, ok := reserved[k]; ok {
+				return fmt.Errorf("invalid metadata: label %q is reserved for Pachyderm", k)
+			}
+		}
+	}
 	if pipelineInfo.PodSpec != "" && !json.Valid([]byte(pipelineInfo.PodSpec)) {
 		return fmt.Errorf("malformed PodSpec")
 	}
@@ -1598,11 +2737,78 @@ func (a *apiServer) validatePipeline(pachClient *client.APIClient, pipelineInfo
 		if !validServiceTypes[v1.ServiceType(pipelineInfo.Service.Type)] {
 			return fmt.Errorf("the following service type %s is not allowed", pipelineInfo.Service.Type)
 		}
+		if err := validateService(pipelineInfo.Service); err != nil {
+			return err
+		}
 	}
 	if pipelineInfo.Spout != nil {
 		if pipelineInfo.EnableStats {
 			return fmt.Errorf("spouts are not allowed to have a stats branch")
 		}
+		if err := validateKafkaSpout(pipelineInfo.Spout); err != nil {
+			return err
+		}
+		if pipelineInfo.Spout.Service != nil {
+			if err := validateService(pipelineInfo.Spout.Service); err != nil {
+				return err
+			}
+		}
+		if err := validateSpoutBatch(pipelineInfo.Spout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateSpoutBatch(spout *pps.Spout) error {
+	if spout.BatchBytes < 0 {
+		return fmt.Errorf("spout batch_bytes must be non-negative, not %d", spout.BatchBytes)
+	}
+	if spout.BatchInterval != nil {
+		interval, err := types.DurationFromProto(spout.BatchInterval)
+		if err != nil {
+			return err
+		}
+		if interval <= 0 {
+			return fmt.Errorf("spout batch_interval must be positive, not %v", interval)
+		}
+	}
+	if spout.Kafka != nil && (spout.BatchBytes != 0 || spout.BatchInterval != nil) {
+		return fmt.Errorf("kafka spouts are batched by kafka.batch_size and kafka.batch_timeout, not batch_bytes and batch_interval")
+	}
+	return nil
+}
+
+func validateKafkaSpout(spout *pps.Spout) error {
+	kafka := spout.Kafka
+	if kafka == nil {
+		return nil
+	}
+	switch {
+	case len(kafka.Brokers) == 0:
+		return fmt.Errorf("kafka spouts must specify at least one broker")
+	case kafka.Topic == "":
+		return fmt.Errorf("kafka spouts must specify a topic")
+	case kafka.BatchSize < 0:
+		return fmt.Errorf("kafka spout batch_size must be non-negative, not %d", kafka.BatchSize)
+	case spout.Overwrite:
+		return fmt.Errorf("kafka spouts can't overwrite their output")
+	case spout.Service != nil:
+		return fmt.Errorf("kafka spouts can't have a service")
+	}
+	switch kafka.Serialization {
+	case "", "raw", "json":
+	default:
+		return fmt.Errorf("unsupported kafka spout serialization %q (must be \"raw\" or \"json\")", kafka.Serialization)
+	}
+	if kafka.BatchTimeout != nil {
+		timeout, err := types.DurationFromProto(kafka.BatchTimeout)
+		if err != nil {
+			return err
+		}
+		if timeout <= 0 {
+			return fmt.Errorf("kafka spout batch_timeout must be positive, not %v", timeout)
+		}
 	}
 	return nil
 }
@@ -1616,6 +2822,9 @@ func branchProvenance(input *pps.Input) []*pfs.Branch {
 		if input.Cron != nil {
 			result = append(result, client.NewBranch(input.Cron.Repo, "master"))
 		}
+		if input.SQL != nil {
+			result = append(result, client.NewBranch(input.SQL.Repo, "master"))
+		}
 		if input.Git != nil {
 			result = append(result, client.NewBranch(input.Git.Name, input.Git.Branch))
 		}
@@ -1754,6 +2963,8 @@ func (a *apiServer) fixPipelineInputRepoACLs(pachClient *client.APIClient, pipel
 				repo = input.Pfs.Repo
 			case input.Cron != nil:
 				repo = input.Cron.Repo
+			case input.SQL != nil:
+				repo = input.SQL.Repo
 			case input.Git != nil:
 				repo = input.Git.Name
 			default:
@@ -1761,6 +2972,9 @@ func (a *apiServer) fixPipelineInputRepoACLs(pachClient *client.APIClient, pipel
 			}
 			remove[repo] = struct{}{}
 		})
+		if prevPipelineInfo.Quarantine != nil {
+			remove[prevPipelineInfo.Quarantine.Repo] = struct{}{}
+		}
 	}
 
 	// Figure out which repos 'pipeline' is using
@@ -1783,6 +2997,8 @@ func (a *apiServer) fixPipelineInputRepoACLs(pachClient *client.APIClient, pipel
 				repo = input.Pfs.Repo
 			case input.Cron != nil:
 				repo = input.Cron.Repo
+			case input.SQL != nil:
+				repo = input.SQL.Repo
 			case input.Git != nil:
 				repo = input.Git.Name
 			default:
@@ -1795,6 +3011,12 @@ func (a *apiServer) fixPipelineInputRepoACLs(pachClient *client.APIClient, pipel
 			}
 		})
 	}
+	// The pipeline writes its failed datums to its quarantine repo
+	var quarantineRepo string
+	if pipelineInfo != nil && pipelineInfo.Quarantine != nil {
+		quarantineRepo = pipelineInfo.Quarantine.Repo
+		delete(remove, quarantineRepo)
+	}
 	if pipelineName == "" {
 		return fmt.Errorf("fixPipelineInputRepoACLs called with both current and " +
 			"previous pipelineInfos == to nil; this is a bug")
@@ -1833,6 +3055,19 @@ func (a *apiServer) fixPipelineInputRepoACLs(pachClient *client.APIClient, pipel
 			})
 		})
 	}
+	// Add pipeline to its quarantine repo's ACL as a WRITER
+	if quarantineRepo != "" {
+		eg.Go(func() error {
+			return a.sudo(pachClient, func(superUserClient *client.APIClient) error {
+				_, err := superUserClient.SetScope(superUserClient.Ctx(), &auth.SetScopeRequest{
+					Repo:     quarantineRepo,
+					Username: auth.PipelinePrefix + pipelineName,
+					Scope:    auth.Scope_WRITER,
+				})
+				return grpcutil.ScrubGRPC(err)
+			})
+		})
+	}
 	// Add pipeline to its output repo's ACL as a WRITER if it's new
 	if prevPipelineInfo == nil {
 		eg.Go(func() error {
@@ -1852,6 +3087,42 @@ func (a *apiServer) fixPipelineInputRepoACLs(pachClient *client.APIClient, pipel
 	return nil
 }
 
+// createQuarantineRepo creates the quarantine repo of 'pipelineInfo' (see
+// pps.Quarantine), if it doesn't exist. The pipeline is made a writer of the
+// repo by fixPipelineInputRepoACLs, so if auth is active, the caller must be
+// a writer of it too.
+func (a *apiServer) createQuarantineRepo(pachClient *client.APIClient, pipelineInfo *pps.PipelineInfo) error {
+	repo := pipelineInfo.Quarantine.Repo
+	if _, err := pachClient.PfsAPIClient.CreateRepo(pachClient.Ctx(),
+		&pfs.CreateRepoRequest{
+			Repo:        client.NewRepo(repo),
+			Description: fmt.Sprintf("Quarantine repo for the failed datums of pipeline %s.", pipelineInfo.Pipeline.Name),
+		}); err != nil && !isAlreadyExistsErr(err) {
+		return err
+	}
+	me, err := pachClient.WhoAmI(pachClient.Ctx(), &auth.WhoAmIRequest{})
+	if auth.IsErrNotActivated(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	resp, err := pachClient.Authorize(pachClient.Ctx(), &auth.AuthorizeRequest{
+		Repo:  repo,
+		Scope: auth.Scope_WRITER,
+	})
+	if err != nil {
+		return err
+	}
+	if !resp.Authorized {
+		return &auth.ErrNotAuthorized{
+			Subject:  me.Username,
+			Repo:     repo,
+			Required: auth.Scope_WRITER,
+		}
+	}
+	return nil
+}
+
 // CreatePipeline implements the protobuf pps.CreatePipeline RPC
 //
 // Implementation note:
@@ -1897,38 +3168,11 @@ func (a *apiServer) CreatePipeline(ctx context.Context, request *pps.CreatePipel
 	ctx = pachClient.Ctx() // GetPachClient propagates auth info to inner ctx
 	pfsClient := pachClient.PfsAPIClient
 	// Reprocess overrides the salt in the request
+	reuseDatums := request.ReuseDatums && request.Salt == "" && !request.Reprocess
 	if request.Salt == "" || request.Reprocess {
 		request.Salt = uuid.NewWithoutDashes()
 	}
-	pipelineInfo := &pps.PipelineInfo{
-		Pipeline:         request.Pipeline,
-		Version:          1,
-		Transform:        request.Transform,
-		TFJob:            request.TFJob,
-		ParallelismSpec:  request.ParallelismSpec,
-		HashtreeSpec:     request.HashtreeSpec,
-		Input:            request.Input,
-		OutputBranch:     request.OutputBranch,
-		Egress:           request.Egress,
-		CreatedAt:        now(),
-		ResourceRequests: request.ResourceRequests,
-		ResourceLimits:   request.ResourceLimits,
-		Description:      request.Description,
-		CacheSize:        request.CacheSize,
-		EnableStats:      request.EnableStats,
-		Salt:             request.Salt,
-		MaxQueueSize:     request.MaxQueueSize,
-		Service:          request.Service,
-		Spout:            request.Spout,
-		ChunkSpec:        request.ChunkSpec,
-		DatumTimeout:     request.DatumTimeout,
-		JobTimeout:       request.JobTimeout,
-		Standby:          request.Standby,
-		DatumTries:       request.DatumTries,
-		SchedulingSpec:   request.SchedulingSpec,
-		PodSpec:          request.PodSpec,
-		PodPatch:         request.PodPatch,
-	}
+	pipelineInfo := newPipelineInfo(request)
 	if err := setPipelineDefaults(pipelineInfo); err != nil {
 		return nil, err
 	}
@@ -1936,6 +3180,16 @@ func (a *apiServer) CreatePipeline(ctx context.Context, request *pps.CreatePipel
 	if err := a.validatePipeline(pachClient, pipelineInfo); err != nil {
 		return nil, err
 	}
+	if reuseDatums {
+		// A pipeline with the same spec gets the same salt, and so it reuses
+		// the output of the datums that the pipeline processed (an update
+		// keeps the existing pipeline's salt, below)
+		salt, err := ppsutil.DatumReuseSalt(pipelineInfo)
+		if err != nil {
+			return nil, err
+		}
+		pipelineInfo.Salt = salt
+	}
 
 	var visitErr error
 	pps.VisitInput(pipelineInfo.Input, func(input *pps.Input) {
@@ -1948,6 +3202,15 @@ func (a *apiServer) CreatePipeline(ctx context.Context, request *pps.CreatePipel
 				visitErr = err
 			}
 		}
+		if input.SQL != nil {
+			if _, err := pachClient.PfsAPIClient.CreateRepo(pachClient.Ctx(),
+				&pfs.CreateRepoRequest{
+					Repo:        client.NewRepo(input.SQL.Repo),
+					Description: fmt.Sprintf("SQL input repo for pipeline %s.", request.Pipeline.Name),
+				}); err != nil && !isAlreadyExistsErr(err) {
+				visitErr = err
+			}
+		}
 		if input.Git != nil {
 			if _, err := pachClient.PfsAPIClient.CreateRepo(pachClient.Ctx(),
 				&pfs.CreateRepoRequest{
@@ -1970,6 +3233,11 @@ func (a *apiServer) CreatePipeline(ctx context.Context, request *pps.CreatePipel
 	if err := a.authorizePipelineOp(pachClient, operation, pipelineInfo.Input, pipelineInfo.Pipeline.Name); err != nil {
 		return nil, err
 	}
+	if pipelineInfo.Quarantine != nil {
+		if err := a.createQuarantineRepo(pachClient, pipelineInfo); err != nil {
+			return nil, err
+		}
+	}
 	pipelineName := pipelineInfo.Pipeline.Name
 	pps.SortInput(pipelineInfo.Input) // Makes datum hashes comparable
 	update := false
@@ -2038,6 +3306,15 @@ func (a *apiServer) CreatePipeline(ctx context.Context, request *pps.CreatePipel
 				if !request.Reprocess {
 					pipelineInfo.Salt = oldPipelineInfo.Salt
 				}
+				// If only e.g. the pipeline's parallelism changed, keep its version,
+				// so that the PPS master patches the existing RC and the running
+				// workers pick up the new spec, rather than restarting the pipeline
+				inPlace := !request.Reprocess &&
+					pipelinePtr.State != pps.PipelineState_PIPELINE_FAILURE &&
+					ppsutil.UpdatableInPlace(oldPipelineInfo, pipelineInfo)
+				if inPlace {
+					pipelineInfo.Version = oldPipelineInfo.Version
+				}
 				// Must create spec commit before restoring output branch provenance, so
 				// that no commits are created with a mismatched spec commit
 				specCommit, err := a.makePipelineInfoCommit(pachClient, pipelineInfo)
@@ -2046,9 +3323,11 @@ func (a *apiServer) CreatePipeline(ctx context.Context, request *pps.CreatePipel
 				}
 				// Update pipelinePtr to point to new commit
 				pipelinePtr.SpecCommit = specCommit
-				pipelinePtr.State = pps.PipelineState_PIPELINE_STARTING
-				// Clear any failure reasons
-				pipelinePtr.Reason = ""
+				if !inPlace {
+					pipelinePtr.State = pps.PipelineState_PIPELINE_STARTING
+					// Clear any failure reasons
+					pipelinePtr.Reason = ""
+				}
 				return nil
 			})
 		}); err != nil {
@@ -2185,6 +3464,80 @@ func (a *apiServer) CreatePipeline(ctx context.Context, request *pps.CreatePipel
 	return &types.Empty{}, nil
 }
 
+// ValidatePipeline implements the protobuf pps.ValidatePipeline RPC. It
+// reports the problems with the pipeline spec in 'request' without creating
+// or updating the pipeline.
+func (a *apiServer) ValidatePipeline(ctx context.Context, request *pps.CreatePipelineRequest) (response *pps.ValidatePipelineResponse, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	pachClient := a.env.GetPachClient(ctx)
+	return &pps.ValidatePipelineResponse{
+		Diagnostics: a.lintPipeline(pachClient, request),
+	}, nil
+}
+
+// newPipelineInfo returns the PipelineInfo of the first version of the
+// pipeline created by 'request', before defaults are set
+func newPipelineInfo(request *pps.CreatePipelineRequest) *pps.PipelineInfo {
+	return &pps.PipelineInfo{
+		Pipeline:               request.Pipeline,
+		Version:                1,
+		Transform:              request.Transform,
+		TFJob:                  request.TFJob,
+		ParallelismSpec:        request.ParallelismSpec,
+		HashtreeSpec:           request.HashtreeSpec,
+		Input:                  request.Input,
+		OutputBranch:           request.OutputBranch,
+		Egress:                 request.Egress,
+		CreatedAt:              now(),
+		ResourceRequests:       request.ResourceRequests,
+		ResourceLimits:         request.ResourceLimits,
+		Description:            request.Description,
+		CacheSize:              request.CacheSize,
+		EnableStats:            request.EnableStats,
+		Salt:                   request.Salt,
+		MaxQueueSize:           request.MaxQueueSize,
+		PrefetchSize:           request.PrefetchSize,
+		Service:                request.Service,
+		Spout:                  request.Spout,
+		ChunkSpec:              request.ChunkSpec,
+		DatumTimeout:           request.DatumTimeout,
+		DatumTimeoutPerMB:      request.DatumTimeoutPerMB,
+		DownloadTimeout:        request.DownloadTimeout,
+		JobTimeout:             request.JobTimeout,
+		Standby:                request.Standby,
+		DatumTries:             request.DatumTries,
+		SchedulingSpec:         request.SchedulingSpec,
+		PodSpec:                request.PodSpec,
+		PodPatch:               request.PodPatch,
+		Metadata:               request.Metadata,
+		SpeculationFactor:      request.SpeculationFactor,
+		RetryOomDatums:         request.RetryOomDatums,
+		JobRetention:           request.JobRetention,
+		PreviousOutput:         request.PreviousOutput,
+		Cache:                  request.Cache,
+		JobScratch:             request.JobScratch,
+		InputWriteCheck:        request.InputWriteCheck,
+		MergeSpec:              request.MergeSpec,
+		AttestationSpec:        request.AttestationSpec,
+		MetricsPush:            request.MetricsPush,
+		Defer:                  request.Defer,
+		StatsSampleRate:        request.StatsSampleRate,
+		Quarantine:             request.Quarantine,
+		BandwidthLimit:         request.BandwidthLimit,
+		CPUPinning:             request.CPUPinning,
+		ReuseDatums:            request.ReuseDatums,
+		ScratchVolume:          request.ScratchVolume,
+		MaxFailedDatumsPercent: request.MaxFailedDatumsPercent,
+		EmptyJobPolicy:         request.EmptyJobPolicy,
+		AutoscalingSpec:        request.AutoscalingSpec,
+		Notifications:          request.Notifications,
+		DatumTreeCompression:   request.DatumTreeCompression,
+		JobOrder:               request.JobOrder,
+		MaxOutstandingJobs:     request.MaxOutstandingJobs,
+	}
+}
+
 // setPipelineDefaults sets the default values for a pipeline info
 func setPipelineDefaults(pipelineInfo *pps.PipelineInfo) error {
 	now := time.Now()
@@ -2209,6 +3562,17 @@ func setPipelineDefaults(pipelineInfo *pps.PipelineInfo) error {
 				input.Cron.Repo = fmt.Sprintf("%s_%s", pipelineInfo.Pipeline.Name, input.Cron.Name)
 			}
 		}
+		if input.SQL != nil {
+			if input.SQL.Repo == "" {
+				input.SQL.Repo = fmt.Sprintf("%s_%s", pipelineInfo.Pipeline.Name, input.SQL.Name)
+			}
+			if input.SQL.Key == "" {
+				input.SQL.Key = sqlInputDefaultKey
+			}
+			if input.SQL.Format == "" {
+				input.SQL.Format = "csv"
+			}
+		}
 		if input.Git != nil {
 			if input.Git.Branch == "" {
 				input.Git.Branch = "master"
@@ -2236,6 +3600,9 @@ func setPipelineDefaults(pipelineInfo *pps.PipelineInfo) error {
 	if pipelineInfo.MaxQueueSize < 1 {
 		pipelineInfo.MaxQueueSize = 1
 	}
+	if pipelineInfo.MaxOutstandingJobs < 1 {
+		pipelineInfo.MaxOutstandingJobs = 1
+	}
 	if pipelineInfo.DatumTries == 0 {
 		pipelineInfo.DatumTries = DefaultDatumTries
 	}
@@ -2247,6 +3614,21 @@ func setPipelineDefaults(pipelineInfo *pps.PipelineInfo) error {
 	if pipelineInfo.Spout != nil && pipelineInfo.Spout.Service != nil && pipelineInfo.Spout.Service.Type == "" {
 		pipelineInfo.Spout.Service.Type = string(v1.ServiceTypeNodePort)
 	}
+	if pipelineInfo.Spout != nil && pipelineInfo.Spout.Kafka != nil {
+		kafka := pipelineInfo.Spout.Kafka
+		if kafka.Serialization == "" {
+			kafka.Serialization = "raw"
+		}
+		if kafka.BatchSize == 0 {
+			kafka.BatchSize = DefaultKafkaBatchSize
+		}
+		if kafka.BatchTimeout == nil {
+			kafka.BatchTimeout = types.DurationProto(DefaultKafkaBatchTimeout)
+		}
+	}
+	if pipelineInfo.MetricsPush != nil && pipelineInfo.MetricsPush.Interval == nil {
+		pipelineInfo.MetricsPush.Interval = types.DurationProto(DefaultMetricsPushInterval)
+	}
 	return nil
 }
 
@@ -2273,7 +3655,7 @@ func (a *apiServer) inspectPipeline(pachClient *client.APIClient, name string) (
 	pipelinePtr := pps.EtcdPipelineInfo{}
 	if err := a.pipelines.ReadOnly(pachClient.Ctx()).Get(name, &pipelinePtr); err != nil {
 		if col.IsErrNotFound(err) {
-			return nil, fmt.Errorf("pipeline \"%s\" not found", name)
+			return nil, newErrPipelineNotFound(name)
 		}
 		return nil, err
 	}
@@ -2287,7 +3669,7 @@ func (a *apiServer) inspectPipeline(pachClient *client.APIClient, name string) (
 		if err != nil {
 			return nil, err
 		}
-		service, err := kubeClient.CoreV1().Services(a.namespace).Get(fmt.Sprintf("%s-user", rcName), metav1.GetOptions{})
+		service, err := kubeClient.CoreV1().Services(a.namespace).Get(userServiceName(rcName), metav1.GetOptions{})
 		if err != nil {
 			if !isNotFoundErr(err) {
 				return nil, err
@@ -2295,6 +3677,10 @@ func (a *apiServer) inspectPipeline(pachClient *client.APIClient, name string) (
 		} else {
 			pipelineInfo.Service.IP = service.Spec.ClusterIP
 		}
+		pipelineInfo.Service.ReadyEndpoints, pipelineInfo.Service.NotReadyEndpoints, err = a.serviceEndpoints(kubeClient, userServiceName(rcName))
+		if err != nil {
+			return nil, err
+		}
 	}
 	var hasGitInput bool
 	pps.VisitInput(pipelineInfo.Input, func(input *pps.Input) {
@@ -2396,7 +3782,7 @@ func (a *apiServer) listPipelinePtr(pachClient *client.APIClient,
 	} else {
 		if err := a.pipelines.ReadOnly(pachClient.Ctx()).Get(pipeline.Name, p); err != nil {
 			if col.IsErrNotFound(err) {
-				return fmt.Errorf("pipeline \"%s\" not found", pipeline.Name)
+				return newErrPipelineNotFound(pipeline.Name)
 			}
 			return err
 		}
@@ -2561,13 +3947,18 @@ func (a *apiServer) deletePipeline(pachClient *client.APIClient, request *pps.De
 		}
 		return nil
 	})
-	// Delete cron input repos
+	// Delete cron and SQL input repos
 	pps.VisitInput(pipelineInfo.Input, func(input *pps.Input) {
 		if input.Cron != nil {
 			eg.Go(func() error {
 				return pachClient.DeleteRepo(input.Cron.Repo, request.Force)
 			})
 		}
+		if input.SQL != nil {
+			eg.Go(func() error {
+				return pachClient.DeleteRepo(input.SQL.Repo, request.Force)
+			})
+		}
 	})
 	if err := eg.Wait(); err != nil {
 		return nil, err
@@ -2659,9 +4050,17 @@ func (a *apiServer) StopPipeline(ctx context.Context, request *pps.StopPipelineR
 func (a *apiServer) RunPipeline(ctx context.Context, request *pps.RunPipelineRequest) (response *types.Empty, retErr error) {
 	func() { a.Log(request, nil, nil, 0) }()
 	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	if _, err := a.runPipeline(a.env.GetPachClient(ctx), request); err != nil {
+		return nil, err
+	}
+	return &types.Empty{}, nil
+}
 
-	pachClient := a.env.GetPachClient(ctx)
-	ctx = pachClient.Ctx() // pachClient will propagate auth info
+// runPipeline starts an output commit of the pipeline in 'request' with
+// the provenance in 'request', and returns it. It returns nil if the pipeline
+// defers processing and was triggered instead.
+func (a *apiServer) runPipeline(pachClient *client.APIClient, request *pps.RunPipelineRequest) (*pfs.Commit, error) {
+	ctx := pachClient.Ctx() // pachClient will propagate auth info
 	pfsClient := pachClient.PfsAPIClient
 	ppsClient := pachClient.PpsAPIClient
 
@@ -2669,6 +4068,14 @@ func (a *apiServer) RunPipeline(ctx context.Context, request *pps.RunPipelineReq
 	if err != nil {
 		return nil, err
 	}
+	if pipelineInfo.Defer != nil && len(request.Provenance) == 0 && request.JobID == "" {
+		// The pipeline's pending input is processed, rather than a new output
+		// commit being started
+		if err := a.triggerPipeline(pachClient, request.Pipeline.Name); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
 	// make sure the user isn't trying to run pipeline on an empty branch
 	branch, err := pfsClient.InspectBranch(ctx, &pfs.InspectBranchRequest{
 		Branch: client.NewBranch(request.Pipeline.Name, pipelineInfo.OutputBranch),
@@ -2789,7 +4196,7 @@ func (a *apiServer) RunPipeline(ctx context.Context, request *pps.RunPipelineReq
 	specProvenance := client.NewCommitProvenance(ppsconsts.SpecRepo, request.Pipeline.Name, specCommit.Commit.ID)
 	provenance = append(provenance, specProvenance)
 
-	_, err = pfsClient.StartCommit(ctx, &pfs.StartCommitRequest{
+	commit, err := pfsClient.StartCommit(ctx, &pfs.StartCommitRequest{
 		Parent: &pfs.Commit{
 			Repo: &pfs.Repo{
 				Name: request.Pipeline.Name,
@@ -2800,9 +4207,84 @@ func (a *apiServer) RunPipeline(ctx context.Context, request *pps.RunPipelineReq
 	if err != nil {
 		return nil, err
 	}
+	if pipelineInfo.Defer != nil {
+		// make sure the new output commit is processed, and not deferred
+		if err := a.triggerPipeline(pachClient, request.Pipeline.Name); err != nil {
+			return nil, err
+		}
+	}
+	return commit, nil
+}
+
+// triggerPipeline triggers a pipeline that defers processing, so that its
+// master processes all of the output commits that are pending as one job
+func (a *apiServer) triggerPipeline(pachClient *client.APIClient, pipelineName string) error {
+	if err := a.authorizePipelineOp(pachClient, pipelineOpUpdate, nil, pipelineName); err != nil {
+		return err
+	}
+	_, err := col.NewSTM(pachClient.Ctx(), a.env.GetEtcdClient(), func(stm col.STM) error {
+		pipelinePtr := &pps.EtcdPipelineInfo{}
+		return a.pipelines.ReadWrite(stm).Update(pipelineName, pipelinePtr, func() error {
+			pipelinePtr.Triggered = types.TimestampNow()
+			return nil
+		})
+	})
+	return err
+}
+
+// SetWorkerConfig implements the protobuf pps.SetWorkerConfig RPC
+func (a *apiServer) SetWorkerConfig(ctx context.Context, request *pps.SetWorkerConfigRequest) (response *types.Empty, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	pachClient := a.env.GetPachClient(ctx)
+	ctx, err := checkLoggedIn(pachClient)
+	if err != nil {
+		return nil, err
+	}
+	if request.Pipeline == nil || request.Pipeline.Name == "" {
+		return nil, fmt.Errorf("must specify a pipeline")
+	}
+	if err := validateWorkerConfig(request.Config); err != nil {
+		return nil, fmt.Errorf("invalid worker config: %v", err)
+	}
+	// Changing the workers' config requires the same access as updating
+	// their pipeline
+	if err := a.authorizePipelineOp(pachClient, pipelineOpUpdate, nil, request.Pipeline.Name); err != nil {
+		return nil, err
+	}
+	// The pipeline's workers watch its EtcdPipelineInfo, and apply the new
+	// config as soon as it's written
+	if _, err := col.NewSTM(ctx, a.env.GetEtcdClient(), func(stm col.STM) error {
+		pipelinePtr := &pps.EtcdPipelineInfo{}
+		return a.pipelines.ReadWrite(stm).Update(request.Pipeline.Name, pipelinePtr, func() error {
+			pipelinePtr.WorkerConfig = request.Config
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
 	return &types.Empty{}, nil
 }
 
+// workerLogLevels are the log levels that a WorkerConfig can set
+var workerLogLevels = map[string]bool{"": true, "debug": true, "info": true, "warning": true, "error": true}
+
+func validateWorkerConfig(config *pps.WorkerConfig) error {
+	if config == nil {
+		return nil
+	}
+	if config.TransferConcurrency < 0 {
+		return fmt.Errorf("transfer_concurrency can't be negative")
+	}
+	if config.DatumTimeoutMultiplier < 0 {
+		return fmt.Errorf("datum_timeout_multiplier can't be negative")
+	}
+	if !workerLogLevels[config.LogLevel] {
+		return fmt.Errorf("log_level must be debug, info, warning or error, not %q", config.LogLevel)
+	}
+	return nil
+}
+
 func (a *apiServer) RunCron(ctx context.Context, request *pps.RunCronRequest) (response *types.Empty, retErr error) {
 	func() { a.Log(request, nil, nil, 0) }()
 	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
@@ -0,0 +1,215 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestValidateTransformFileModes(t *testing.T) {
+	require.NoError(t, validateTransform(&pps.Transform{
+		Image:    "ubuntu",
+		Umask:    "0022",
+		DirMode:  "0755",
+		FileMode: "0644",
+	}))
+	require.YesError(t, validateTransform(&pps.Transform{Image: "ubuntu", Umask: "022x"}))
+	require.YesError(t, validateTransform(&pps.Transform{Image: "ubuntu", DirMode: "01777"}))
+	require.YesError(t, validateTransform(&pps.Transform{Image: "ubuntu", FileMode: "0888"}))
+}
+
+func TestValidateTransformInitContainers(t *testing.T) {
+	require.NoError(t, validateTransform(&pps.Transform{
+		Image:          "ubuntu",
+		InitContainers: []*pps.InitContainer{{Name: "fetch", Image: "alpine"}},
+	}))
+	require.YesError(t, validateTransform(&pps.Transform{
+		Image:          "ubuntu",
+		InitContainers: []*pps.InitContainer{{Name: "init", Image: "alpine"}},
+	}))
+	require.YesError(t, validateTransform(&pps.Transform{
+		Image:          "ubuntu",
+		InitContainers: []*pps.InitContainer{{Name: "fetch"}},
+	}))
+	require.YesError(t, validateTransform(&pps.Transform{
+		Image: "ubuntu",
+		InitContainers: []*pps.InitContainer{
+			{Name: "fetch", Image: "alpine"},
+			{Name: "fetch", Image: "alpine"},
+		},
+	}))
+}
+
+func TestValidateSchedulingSpec(t *testing.T) {
+	require.NoError(t, validateSchedulingSpec(nil))
+	require.NoError(t, validateSchedulingSpec(&pps.SchedulingSpec{
+		Tolerations: []*pps.Toleration{
+			{Key: "gpu", Operator: "Exists"},
+			{Key: "spot", Value: "true", Effect: "NoExecute", TolerationSeconds: 30},
+		},
+		RequiredNodeAffinity: []*pps.NodeSelectorRequirement{
+			{Key: "kubernetes.io/arch", Operator: "In", Values: []string{"arm64"}},
+		},
+		PreferredNodeAffinity: []*pps.NodeSelectorRequirement{
+			{Key: "spot", Operator: "Exists"},
+		},
+	}))
+	for _, spec := range []*pps.SchedulingSpec{
+		{Tolerations: []*pps.Toleration{{Key: "gpu", Operator: "Maybe"}}},
+		{Tolerations: []*pps.Toleration{{Key: "gpu", Operator: "Exists", Value: "true"}}},
+		{Tolerations: []*pps.Toleration{{Key: "gpu", Effect: "NoWay"}}},
+		{Tolerations: []*pps.Toleration{{Key: "gpu", Effect: "NoSchedule", TolerationSeconds: 10}}},
+		{RequiredNodeAffinity: []*pps.NodeSelectorRequirement{{Key: "arch", Operator: "In"}}},
+		{RequiredNodeAffinity: []*pps.NodeSelectorRequirement{{Operator: "Exists"}}},
+		{PreferredNodeAffinity: []*pps.NodeSelectorRequirement{{Key: "cpus", Operator: "Gt", Values: []string{"1", "2"}}}},
+		{PreferredNodeAffinity: []*pps.NodeSelectorRequirement{{Key: "arch", Operator: "Like"}}},
+	} {
+		require.YesError(t, validateSchedulingSpec(spec))
+	}
+}
+
+func TestValidateEgress(t *testing.T) {
+	require.NoError(t, validateEgress(nil))
+	require.NoError(t, validateEgress(&pps.Egress{URL: "s3://bucket/dir"}))
+	require.NoError(t, validateEgress(&pps.Egress{Postgres: &pps.PostgresEgress{
+		URL:         "postgres://loader@db:5432/warehouse?sslmode=require",
+		PasswordEnv: "DB_PASSWORD",
+		Header:      true,
+	}}))
+	for _, egress := range []*pps.Egress{
+		{URL: "ftp://server/dir"},
+		{URL: "s3://bucket", Postgres: &pps.PostgresEgress{URL: "postgres://loader@db/warehouse"}},
+		{Postgres: &pps.PostgresEgress{}},
+		{Postgres: &pps.PostgresEgress{URL: "snowflake://loader@account/warehouse"}},
+		{Postgres: &pps.PostgresEgress{URL: "postgres://loader:hunter2@db/warehouse"}},
+		{Postgres: &pps.PostgresEgress{URL: "postgres://loader@db/warehouse", FileFormat: "parquet"}},
+		{Postgres: &pps.PostgresEgress{URL: "postgres://loader@db/warehouse", FileFormat: "text", Header: true}},
+	} {
+		require.YesError(t, validateEgress(egress))
+	}
+}
+
+func TestValidateCache(t *testing.T) {
+	require.NoError(t, validateCache(nil))
+	require.NoError(t, validateCache(&pps.Cache{Path: "/cache"}))
+	require.NoError(t, validateCache(&pps.Cache{Path: "/cache", SizeLimit: "10G"}))
+	for _, cache := range []*pps.Cache{
+		{Path: "cache"},
+		{Path: "/"},
+		{Path: "/pfs"},
+		{Path: "/pfs/cache"},
+		{Path: "/cache", SizeLimit: "lots"},
+		{Path: "/cache", SizeLimit: "0"},
+	} {
+		require.YesError(t, validateCache(cache))
+	}
+}
+
+func TestValidateKafkaSpout(t *testing.T) {
+	require.NoError(t, validateKafkaSpout(&pps.Spout{}))
+	require.NoError(t, validateKafkaSpout(&pps.Spout{Kafka: &pps.KafkaSpout{
+		Brokers:       []string{"kafka:9092"},
+		Topic:         "events",
+		Serialization: "json",
+		BatchTimeout:  types.DurationProto(time.Second),
+	}}))
+	for _, spout := range []*pps.Spout{
+		{Kafka: &pps.KafkaSpout{Topic: "events"}},
+		{Kafka: &pps.KafkaSpout{Brokers: []string{"kafka:9092"}}},
+		{Kafka: &pps.KafkaSpout{Brokers: []string{"kafka:9092"}, Topic: "events", BatchSize: -1}},
+		{Kafka: &pps.KafkaSpout{Brokers: []string{"kafka:9092"}, Topic: "events", Serialization: "avro"}},
+		{Kafka: &pps.KafkaSpout{Brokers: []string{"kafka:9092"}, Topic: "events", BatchTimeout: types.DurationProto(0)}},
+		{Kafka: &pps.KafkaSpout{Brokers: []string{"kafka:9092"}, Topic: "events"}, Overwrite: true},
+		{Kafka: &pps.KafkaSpout{Brokers: []string{"kafka:9092"}, Topic: "events"}, Service: &pps.Service{}},
+	} {
+		require.YesError(t, validateKafkaSpout(spout))
+	}
+}
+
+func TestValidateMetricsPush(t *testing.T) {
+	require.NoError(t, validateMetricsPush(nil))
+	require.NoError(t, validateMetricsPush(&pps.MetricsPush{
+		PushgatewayURL: "http://pushgateway.monitoring:9091",
+		StatsdAddress:  "statsd.monitoring:8125",
+		Labels:         map[string]string{"team": "vision"},
+		Interval:       types.DurationProto(time.Minute),
+	}))
+	for _, metricsPush := range []*pps.MetricsPush{
+		{},
+		{PushgatewayURL: "pushgateway:9091"},
+		{StatsdAddress: "statsd"},
+		{StatsdAddress: "statsd:8125", Labels: map[string]string{"team-name": "vision"}},
+		{StatsdAddress: "statsd:8125", Labels: map[string]string{"pipeline": "other"}},
+		{StatsdAddress: "statsd:8125", Interval: types.DurationProto(0)},
+	} {
+		require.YesError(t, validateMetricsPush(metricsPush))
+	}
+}
+
+func TestValidateSpoutBatch(t *testing.T) {
+	require.NoError(t, validateSpoutBatch(&pps.Spout{}))
+	require.NoError(t, validateSpoutBatch(&pps.Spout{BatchBytes: 1 << 20, BatchInterval: types.DurationProto(time.Minute)}))
+	for _, spout := range []*pps.Spout{
+		{BatchBytes: -1},
+		{BatchInterval: types.DurationProto(0)},
+		{Kafka: &pps.KafkaSpout{Brokers: []string{"kafka:9092"}, Topic: "events"}, BatchBytes: 1 << 20},
+	} {
+		require.YesError(t, validateSpoutBatch(spout))
+	}
+}
+
+func TestValidateDefer(t *testing.T) {
+	require.NoError(t, validateDefer(&pps.Defer{}))
+	require.NoError(t, validateDefer(&pps.Defer{Commits: 100, CronSpec: "0 2 * * *"}))
+	require.YesError(t, validateDefer(&pps.Defer{Commits: -1}))
+	require.YesError(t, validateDefer(&pps.Defer{CronSpec: "nightly"}))
+}
+
+func TestValidateWorkerConfig(t *testing.T) {
+	require.NoError(t, validateWorkerConfig(nil))
+	require.NoError(t, validateWorkerConfig(&pps.WorkerConfig{}))
+	require.NoError(t, validateWorkerConfig(&pps.WorkerConfig{TransferConcurrency: 50, DatumTimeoutMultiplier: 2, LogLevel: "debug"}))
+	require.YesError(t, validateWorkerConfig(&pps.WorkerConfig{TransferConcurrency: -1}))
+	require.YesError(t, validateWorkerConfig(&pps.WorkerConfig{DatumTimeoutMultiplier: -1}))
+	require.YesError(t, validateWorkerConfig(&pps.WorkerConfig{LogLevel: "verbose"}))
+}
+
+func TestAggregate(t *testing.T) {
+	require.Equal(t, &pps.Aggregate{}, aggregate(nil))
+
+	var values []float64
+	for i := 100; i > 0; i-- {
+		values = append(values, float64(i))
+	}
+	a := aggregate(values)
+	require.Equal(t, int64(100), a.Count)
+	require.Equal(t, 50.5, a.Mean)
+	require.Equal(t, 5.0, a.FifthPercentile)
+	require.Equal(t, 50.0, a.Median)
+	require.Equal(t, 95.0, a.NinetyFifthPercentile)
+	require.Equal(t, 99.0, a.NinetyNinthPercentile)
+	require.True(t, a.Stddev > 28.8 && a.Stddev < 28.9)
+
+	a = aggregate([]float64{3})
+	require.Equal(t, 3.0, a.FifthPercentile)
+	require.Equal(t, 3.0, a.NinetyNinthPercentile)
+	require.Equal(t, 0.0, a.Stddev)
+}
+
+func TestCheckRestartable(t *testing.T) {
+	pipelineInfo := &pps.PipelineInfo{Pipeline: &pps.Pipeline{Name: "edges"}, Version: 2}
+	job := func(state pps.JobState, version uint64) *pps.JobInfo {
+		return &pps.JobInfo{Job: &pps.Job{ID: "job"}, State: state, PipelineVersion: version}
+	}
+	require.NoError(t, checkRestartable(job(pps.JobState_JOB_FAILURE, 2), pipelineInfo))
+	require.NoError(t, checkRestartable(job(pps.JobState_JOB_KILLED, 2), pipelineInfo))
+	require.YesError(t, checkRestartable(job(pps.JobState_JOB_SUCCESS, 2), pipelineInfo))
+	require.YesError(t, checkRestartable(job(pps.JobState_JOB_RUNNING, 2), pipelineInfo))
+	// Jobs of older versions of the pipeline process different datums
+	require.YesError(t, checkRestartable(job(pps.JobState_JOB_FAILURE, 1), pipelineInfo))
+	pipelineInfo.Spout = &pps.Spout{}
+	require.YesError(t, checkRestartable(job(pps.JobState_JOB_FAILURE, 2), pipelineInfo))
+}
@@ -0,0 +1,177 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsdb"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
+	workerpkg "github.com/pachyderm/pachyderm/src/server/worker"
+)
+
+// autoscaleInterval is how often the PPS master rescales the workers of
+// pipelines with an autoscaling_spec
+const autoscaleInterval = 30 * time.Second
+
+// workerStartupTime is roughly how long a new worker takes to start
+// processing, after its pipeline is scaled up. The autoscaler doesn't add
+// workers for chunks that the running workers will have processed by then.
+const workerStartupTime = time.Minute
+
+// defaultScaleDownDelay is how long a pipeline with an autoscaling_spec is
+// idle before it's scaled down, if the spec doesn't set scale_down_delay
+const defaultScaleDownDelay = 5 * time.Minute
+
+// clampWorkers returns 'n', raised to 'spec.MinWorkers' or lowered to
+// 'spec.MaxWorkers' if it's outside of them
+func clampWorkers(spec *pps.AutoscalingSpec, n int) int {
+	if n < int(spec.MinWorkers) {
+		return int(spec.MinWorkers)
+	}
+	if n > int(spec.MaxWorkers) {
+		return int(spec.MaxWorkers)
+	}
+	return n
+}
+
+// desiredWorkers returns the number of workers that a pipeline with
+// autoscaling spec 'spec' and 'current' workers should have, given the
+// backlog of its running jobs. Workers are only added, as removing them
+// mid-job would interrupt the chunks that they're processing (the pipeline is
+// scaled down once it's idle), and only if the current workers wouldn't
+// process the unclaimed chunks before the new workers start.
+func desiredWorkers(spec *pps.AutoscalingSpec, current int, backlogs []*workerpkg.Backlog) int {
+	var needed, unclaimed int
+	var chunkTime time.Duration
+	for _, backlog := range backlogs {
+		needed += backlog.Running + backlog.Unclaimed
+		unclaimed += backlog.Unclaimed
+		if backlog.ChunkTime > chunkTime {
+			chunkTime = backlog.ChunkTime
+		}
+	}
+	if needed <= current {
+		return clampWorkers(spec, current)
+	}
+	if chunkTime > 0 {
+		drained := float64(current) * workerStartupTime.Seconds() / chunkTime.Seconds()
+		if float64(unclaimed) <= drained {
+			return clampWorkers(spec, current)
+		}
+	}
+	return clampWorkers(spec, needed)
+}
+
+// autoscalePipeline periodically sets the number of workers of a pipeline
+// with an autoscaling_spec, from the backlogs of its running jobs. It's a
+// helper function called by monitorPipeline, and returns once pachClient's
+// context is cancelled.
+func (a *apiServer) autoscalePipeline(pachClient *client.APIClient, pipelineInfo *pps.PipelineInfo) error {
+	spec := pipelineInfo.AutoscalingSpec
+	scaleDownDelay := defaultScaleDownDelay
+	if spec.ScaleDownDelay != nil {
+		d, err := types.DurationFromProto(spec.ScaleDownDelay)
+		if err != nil {
+			return err // Shouldn't happen, as the spec is validated in CreatePipeline
+		}
+		scaleDownDelay = d
+	}
+	ticker := time.NewTicker(autoscaleInterval)
+	defer ticker.Stop()
+	idleSince := time.Now()
+	for {
+		select {
+		case <-ticker.C:
+		case <-pachClient.Ctx().Done():
+			return context.Canceled
+		}
+		busy, backlogs, err := a.pipelineBacklog(pachClient.Ctx(), pipelineInfo)
+		if err != nil {
+			log.Errorf("PPS master: error getting the backlog of %q: %v", pipelineInfo.Pipeline.Name, err)
+			continue
+		}
+		if busy {
+			idleSince = time.Now()
+		}
+		if err := a.scaleAutoscaledPipeline(pachClient.Ctx(), pipelineInfo, func(current int) int {
+			if !busy {
+				if time.Since(idleSince) < scaleDownDelay {
+					return clampWorkers(spec, current)
+				}
+				return int(spec.MinWorkers)
+			}
+			return desiredWorkers(spec, current, backlogs)
+		}); err != nil {
+			log.Errorf("PPS master: error autoscaling %q: %v", pipelineInfo.Pipeline.Name, err)
+		}
+	}
+}
+
+// pipelineBacklog returns whether the pipeline has any unfinished jobs, and
+// the backlogs of those that have been planned
+func (a *apiServer) pipelineBacklog(ctx context.Context, pipelineInfo *pps.PipelineInfo) (bool, []*workerpkg.Backlog, error) {
+	var jobIDs []string
+	jobPtr := &pps.EtcdJobInfo{}
+	if err := a.jobs.ReadOnly(ctx).GetByIndex(ppsdb.JobsPipelineIndex, pipelineInfo.Pipeline, jobPtr, col.DefaultOptions, func(string) error {
+		if !ppsutil.IsTerminal(jobPtr.State) {
+			jobIDs = append(jobIDs, jobPtr.Job.ID)
+		}
+		return nil
+	}); err != nil {
+		return false, nil, err
+	}
+	var backlogs []*workerpkg.Backlog
+	for _, jobID := range jobIDs {
+		backlog, err := workerpkg.GetBacklog(ctx, a.env.GetEtcdClient(), a.etcdPrefix, jobID)
+		if err != nil {
+			return false, nil, err
+		}
+		if backlog != nil {
+			backlogs = append(backlogs, backlog)
+		}
+	}
+	return len(jobIDs) > 0, backlogs, nil
+}
+
+// scaleAutoscaledPipeline sets the replicas of the pipeline's RC to
+// desired(current replicas), while the pipeline is running. Standby and
+// paused pipelines are scaled by the pipeline controller instead.
+func (a *apiServer) scaleAutoscaledPipeline(ctx context.Context, pipelineInfo *pps.PipelineInfo, desired func(current int) int) error {
+	ptr := &pps.EtcdPipelineInfo{}
+	if err := a.pipelines.ReadOnly(ctx).Get(pipelineInfo.Pipeline.Name, ptr); err != nil {
+		return err
+	}
+	if ptr.State != pps.PipelineState_PIPELINE_RUNNING {
+		return nil
+	}
+	rcs := a.env.GetKubeClient().CoreV1().ReplicationControllers(a.namespace)
+	rc, err := rcs.Get(ppsutil.PipelineRcName(pipelineInfo.Pipeline.Name, pipelineInfo.Version), metav1.GetOptions{})
+	if err != nil {
+		if isNotFoundErr(err) {
+			return nil // the pipeline is being restarted
+		}
+		return err
+	}
+	var current int
+	if rc.Spec.Replicas != nil {
+		current = int(*rc.Spec.Replicas)
+	}
+	n := desired(current)
+	if n == current {
+		return nil
+	}
+	log.Infof("PPS master: autoscaling %q from %d to %d workers", pipelineInfo.Pipeline.Name, current, n)
+	replicas := int32(n)
+	rc.Spec.Replicas = &replicas
+	// A conflicting update (e.g. from the pipeline controller) fails this one,
+	// which is retried on the next tick with the RC's new replicas
+	_, err = rcs.Update(rc)
+	return err
+}
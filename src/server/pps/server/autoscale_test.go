@@ -0,0 +1,35 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	workerpkg "github.com/pachyderm/pachyderm/src/server/worker"
+)
+
+func TestDesiredWorkers(t *testing.T) {
+	spec := &pps.AutoscalingSpec{MinWorkers: 2, MaxWorkers: 10}
+	require.Equal(t, 2, clampWorkers(spec, 0))
+	require.Equal(t, 10, clampWorkers(spec, 20))
+
+	// a job that hasn't been planned yet doesn't change the workers
+	require.Equal(t, 2, desiredWorkers(spec, 2, nil))
+	// workers are added for unclaimed chunks, up to max_workers
+	require.Equal(t, 5, desiredWorkers(spec, 2, []*workerpkg.Backlog{{Running: 2, Unclaimed: 3}}))
+	require.Equal(t, 10, desiredWorkers(spec, 2, []*workerpkg.Backlog{{Running: 2, Unclaimed: 30}}))
+	require.Equal(t, 7, desiredWorkers(spec, 2, []*workerpkg.Backlog{
+		{Running: 2, Unclaimed: 3},
+		{Unclaimed: 2},
+	}))
+	// workers aren't removed while a job runs
+	require.Equal(t, 8, desiredWorkers(spec, 8, []*workerpkg.Backlog{{Running: 1}}))
+
+	// chunks that the current workers will process before new workers start
+	// don't add workers
+	fast := []*workerpkg.Backlog{{Running: 2, Unclaimed: 3, ChunkTime: 10 * time.Second}}
+	require.Equal(t, 2, desiredWorkers(spec, 2, fast))
+	slow := []*workerpkg.Backlog{{Running: 2, Unclaimed: 3, ChunkTime: 10 * time.Minute}}
+	require.Equal(t, 5, desiredWorkers(spec, 2, slow))
+}
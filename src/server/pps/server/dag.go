@@ -0,0 +1,193 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	units "github.com/docker/go-units"
+	"golang.org/x/net/context"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsconsts"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsdb"
+)
+
+// InspectDAG implements the protobuf pps.InspectDAG RPC
+func (a *apiServer) InspectDAG(ctx context.Context, request *pps.InspectDAGRequest) (response *pps.DAG, retErr error) {
+	func() { a.Log(request, nil, nil, 0) }()
+	defer func(start time.Time) { a.Log(request, nil, retErr, time.Since(start)) }(time.Now())
+	pachClient := a.env.GetPachClient(ctx)
+	ctx, err := checkLoggedIn(pachClient)
+	if err != nil {
+		return nil, err
+	}
+	repoInfos, err := pachClient.ListRepo()
+	if err != nil {
+		return nil, err
+	}
+	var pipelineInfos []*pps.PipelineInfo
+	if err := a.listPipeline(pachClient, &pps.ListPipelineRequest{}, func(pipelineInfo *pps.PipelineInfo) error {
+		pipelineInfos = append(pipelineInfos, pipelineInfo)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	lastJobs := make(map[string]*pps.EtcdJobInfo)
+	for _, pipelineInfo := range pipelineInfos {
+		jobPtr, err := a.lastJob(ctx, pachClient, pipelineInfo)
+		if err != nil {
+			return nil, err
+		}
+		if jobPtr != nil {
+			lastJobs[pipelineInfo.Pipeline.Name] = jobPtr
+		}
+	}
+	dag := newDAG(repoInfos, pipelineInfos, lastJobs)
+	dag.Dot = dagDOT(dag)
+	return dag, nil
+}
+
+// lastJob returns the job of the head of 'pipelineInfo's output branch, or
+// nil if the branch has no head or its head wasn't created by a job (e.g.
+// because it's a spout's)
+func (a *apiServer) lastJob(ctx context.Context, pachClient *client.APIClient, pipelineInfo *pps.PipelineInfo) (*pps.EtcdJobInfo, error) {
+	branchInfo, err := pachClient.InspectBranch(pipelineInfo.Pipeline.Name, pipelineInfo.OutputBranch)
+	if err != nil {
+		if isNotFoundErr(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if branchInfo.Head == nil {
+		return nil, nil
+	}
+	var result *pps.EtcdJobInfo
+	jobPtr := &pps.EtcdJobInfo{}
+	if err := a.jobs.ReadOnly(ctx).GetByIndex(ppsdb.JobsOutputIndex, branchInfo.Head, jobPtr, col.DefaultOptions, func(string) error {
+		result = jobPtr
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func repoNodeID(repo string) string {
+	return "repo:" + repo
+}
+
+func pipelineNodeID(pipeline string) string {
+	return "pipeline:" + pipeline
+}
+
+// newDAG returns the DAG of 'repoInfos' and 'pipelineInfos', in which each
+// pipeline has an edge from each repo it reads and an edge to its output repo.
+// 'lastJobs' holds the last job of each pipeline, by pipeline name.
+func newDAG(repoInfos []*pfs.RepoInfo, pipelineInfos []*pps.PipelineInfo, lastJobs map[string]*pps.EtcdJobInfo) *pps.DAG {
+	nodes := make(map[string]*pps.DAGNode)
+	repoNode := func(repo string) string {
+		id := repoNodeID(repo)
+		if nodes[id] == nil {
+			nodes[id] = &pps.DAGNode{ID: id, Name: repo, Type: pps.DAGNodeType_DAG_REPO}
+		}
+		return id
+	}
+	for _, repoInfo := range repoInfos {
+		if repoInfo.Repo.Name == ppsconsts.SpecRepo {
+			continue
+		}
+		nodes[repoNode(repoInfo.Repo.Name)].SizeBytes = repoInfo.SizeBytes
+	}
+	type edgeKey struct{ from, to, branch string }
+	edges := make(map[edgeKey]bool)
+	for _, pipelineInfo := range pipelineInfos {
+		name := pipelineInfo.Pipeline.Name
+		node := &pps.DAGNode{
+			ID:              pipelineNodeID(name),
+			Name:            name,
+			Type:            pps.DAGNodeType_DAG_PIPELINE,
+			State:           pipelineInfo.State,
+			Reason:          pipelineInfo.Reason,
+			LastJobState:    pipelineInfo.LastJobState,
+			JobCounts:       pipelineInfo.JobCounts,
+			ParallelismSpec: pipelineInfo.ParallelismSpec,
+		}
+		if jobPtr := lastJobs[name]; jobPtr != nil {
+			node.LastJob = jobPtr.Job
+			node.LastJobState = jobPtr.State
+		}
+		nodes[node.ID] = node
+		for _, branch := range pps.InputBranches(pipelineInfo.Input) {
+			edges[edgeKey{repoNode(branch.Repo.Name), node.ID, branch.Name}] = true
+		}
+		edges[edgeKey{node.ID, repoNode(name), pipelineInfo.OutputBranch}] = true
+	}
+	result := &pps.DAG{}
+	for _, node := range nodes {
+		result.Nodes = append(result.Nodes, node)
+	}
+	sort.Slice(result.Nodes, func(i, j int) bool {
+		return result.Nodes[i].ID < result.Nodes[j].ID
+	})
+	for edge := range edges {
+		result.Edges = append(result.Edges, &pps.DAGEdge{From: edge.from, To: edge.to, Branch: edge.branch})
+	}
+	sort.Slice(result.Edges, func(i, j int) bool {
+		ei, ej := result.Edges[i], result.Edges[j]
+		if ei.From != ej.From {
+			return ei.From < ej.From
+		}
+		if ei.To != ej.To {
+			return ei.To < ej.To
+		}
+		return ei.Branch < ej.Branch
+	})
+	return result
+}
+
+// pipelineColor is the fill color of a pipeline in state 'state' in the DOT
+// output
+func pipelineColor(state pps.PipelineState) string {
+	switch state {
+	case pps.PipelineState_PIPELINE_RUNNING:
+		return "palegreen"
+	case pps.PipelineState_PIPELINE_FAILURE, pps.PipelineState_PIPELINE_CRASHING:
+		return "lightcoral"
+	case pps.PipelineState_PIPELINE_PAUSED, pps.PipelineState_PIPELINE_STANDBY:
+		return "lightgray"
+	default:
+		return "lightyellow"
+	}
+}
+
+// dagDOT renders 'dag' in GraphViz's DOT language, with repos drawn as
+// cylinders and pipelines as boxes colored by their state
+func dagDOT(dag *pps.DAG) string {
+	var buf bytes.Buffer
+	buf.WriteString("digraph pachyderm {\n")
+	buf.WriteString("  rankdir=LR;\n")
+	for _, node := range dag.Nodes {
+		switch node.Type {
+		case pps.DAGNodeType_DAG_REPO:
+			fmt.Fprintf(&buf, "  %q [shape=cylinder, label=%q];\n", node.ID,
+				fmt.Sprintf("%s\n%s", node.Name, units.BytesSize(float64(node.SizeBytes))))
+		case pps.DAGNodeType_DAG_PIPELINE:
+			label := fmt.Sprintf("%s\n%s", node.Name, strings.ToLower(strings.TrimPrefix(node.State.String(), "PIPELINE_")))
+			if node.LastJob != nil {
+				label += fmt.Sprintf("\nlast job: %s", strings.ToLower(strings.TrimPrefix(node.LastJobState.String(), "JOB_")))
+			}
+			fmt.Fprintf(&buf, "  %q [shape=box, style=filled, fillcolor=%s, label=%q];\n", node.ID, pipelineColor(node.State), label)
+		}
+	}
+	for _, edge := range dag.Edges {
+		fmt.Fprintf(&buf, "  %q -> %q [label=%q];\n", edge.From, edge.To, edge.Branch)
+	}
+	buf.WriteString("}\n")
+	return buf.String()
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsconsts"
+)
+
+func TestNewDAG(t *testing.T) {
+	repoInfos := []*pfs.RepoInfo{
+		{Repo: client.NewRepo("images"), SizeBytes: 1024},
+		{Repo: client.NewRepo("edges"), SizeBytes: 10},
+		{Repo: client.NewRepo(ppsconsts.SpecRepo)},
+	}
+	pipelineInfos := []*pps.PipelineInfo{
+		{
+			Pipeline:     client.NewPipeline("edges"),
+			Input:        client.NewPFSInput("images", "/*"),
+			OutputBranch: "master",
+			State:        pps.PipelineState_PIPELINE_RUNNING,
+		},
+		{
+			Pipeline: client.NewPipeline("montage"),
+			Input: client.NewCrossInput(
+				client.NewPFSInput("images", "/"),
+				client.NewPFSInput("edges", "/"),
+			),
+			OutputBranch: "master",
+			State:        pps.PipelineState_PIPELINE_FAILURE,
+			Reason:       "image not found",
+		},
+	}
+	lastJobs := map[string]*pps.EtcdJobInfo{
+		"edges": {Job: client.NewJob("job"), State: pps.JobState_JOB_SUCCESS},
+	}
+	dag := newDAG(repoInfos, pipelineInfos, lastJobs)
+
+	var ids []string
+	for _, node := range dag.Nodes {
+		ids = append(ids, node.ID)
+	}
+	// montage's output repo hasn't been listed, but it's still in the DAG
+	require.Equal(t, []string{"pipeline:edges", "pipeline:montage", "repo:edges", "repo:images", "repo:montage"}, ids)
+	require.Equal(t, pps.DAGNodeType_DAG_PIPELINE, dag.Nodes[0].Type)
+	require.Equal(t, "job", dag.Nodes[0].LastJob.ID)
+	require.Equal(t, pps.JobState_JOB_SUCCESS, dag.Nodes[0].LastJobState)
+	require.Nil(t, dag.Nodes[1].LastJob)
+	require.Equal(t, "image not found", dag.Nodes[1].Reason)
+	require.Equal(t, pps.DAGNodeType_DAG_REPO, dag.Nodes[3].Type)
+	require.Equal(t, uint64(1024), dag.Nodes[3].SizeBytes)
+
+	var edges []string
+	for _, edge := range dag.Edges {
+		edges = append(edges, edge.From+"->"+edge.To)
+	}
+	require.Equal(t, []string{
+		"pipeline:edges->repo:edges",
+		"pipeline:montage->repo:montage",
+		"repo:edges->pipeline:montage",
+		"repo:images->pipeline:edges",
+		"repo:images->pipeline:montage",
+	}, edges)
+}
+
+func TestDAGDOT(t *testing.T) {
+	dag := newDAG(
+		[]*pfs.RepoInfo{{Repo: client.NewRepo("images"), SizeBytes: 2048}},
+		[]*pps.PipelineInfo{{
+			Pipeline:     client.NewPipeline("edges"),
+			Input:        client.NewPFSInputOpts("", "images", "master", "/*", "", false),
+			OutputBranch: "master",
+			State:        pps.PipelineState_PIPELINE_CRASHING,
+		}},
+		map[string]*pps.EtcdJobInfo{
+			"edges": {Job: client.NewJob("job"), State: pps.JobState_JOB_FAILURE},
+		},
+	)
+	dot := dagDOT(dag)
+	require.True(t, strings.HasPrefix(dot, "digraph pachyderm {\n"))
+	require.True(t, strings.Contains(dot, `"repo:images" [shape=cylinder, label="images\n2KiB"];`))
+	require.True(t, strings.Contains(dot, `"pipeline:edges" [shape=box, style=filled, fillcolor=lightcoral, label="edges\ncrashing\nlast job: failure"];`))
+	require.True(t, strings.Contains(dot, `"repo:images" -> "pipeline:edges" [label="master"];`))
+	require.True(t, strings.Contains(dot, `"pipeline:edges" -> "repo:edges" [label="master"];`))
+}
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"fmt"
+	"path"
+
+	globlib "github.com/pachyderm/ohmyglob"
+
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// datumFilter selects the datums that ListDatum returns, from the filter
+// fields of a ListDatumRequest. A nil datumFilter matches every datum.
+type datumFilter struct {
+	states map[pps.DatumState]bool
+	glob   *globlib.Glob
+}
+
+// newDatumFilter returns the filter of 'request', or nil if it doesn't
+// filter datums
+func newDatumFilter(request *pps.ListDatumRequest) (*datumFilter, error) {
+	if len(request.State) == 0 && request.InputGlob == "" {
+		return nil, nil
+	}
+	f := &datumFilter{}
+	if len(request.State) > 0 {
+		f.states = make(map[pps.DatumState]bool)
+		for _, state := range request.State {
+			f.states[state] = true
+		}
+	}
+	if request.InputGlob != "" {
+		g, err := globlib.Compile(path.Join("/", request.InputGlob), '/')
+		if err != nil {
+			return nil, fmt.Errorf("invalid input glob %q: %v", request.InputGlob, err)
+		}
+		f.glob = g
+	}
+	return f, nil
+}
+
+// matchInputs returns true if one of the input files of 'datumInfo' matches
+// the filter's glob
+func (f *datumFilter) matchInputs(datumInfo *pps.DatumInfo) bool {
+	if f == nil || f.glob == nil {
+		return true
+	}
+	for _, fileInfo := range datumInfo.Data {
+		if fileInfo.File != nil && f.glob.Match(path.Join("/", fileInfo.File.Path)) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchState returns true if 'datumInfo' is in one of the filter's states
+func (f *datumFilter) matchState(datumInfo *pps.DatumInfo) bool {
+	return f == nil || f.states == nil || f.states[datumInfo.State]
+}
+
+// filterDatums returns the datums in 'datumInfos' that match 'f', and a
+// summary of those that match its glob
+func filterDatums(f *datumFilter, datumInfos []*pps.DatumInfo) ([]*pps.DatumInfo, *pps.DatumSummary) {
+	summary := &pps.DatumSummary{}
+	var result []*pps.DatumInfo
+	for _, datumInfo := range datumInfos {
+		if !f.matchInputs(datumInfo) {
+			continue
+		}
+		summary.Total++
+		switch datumInfo.State {
+		case pps.DatumState_FAILED:
+			summary.Failed++
+		case pps.DatumState_SUCCESS:
+			summary.Success++
+		case pps.DatumState_SKIPPED:
+			summary.Skipped++
+		case pps.DatumState_STARTING:
+			summary.Starting++
+		case pps.DatumState_RECOVERED:
+			summary.Recovered++
+		}
+		if f.matchState(datumInfo) {
+			result = append(result, datumInfo)
+		}
+	}
+	return result, summary
+}
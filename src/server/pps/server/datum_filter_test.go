@@ -0,0 +1,60 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestDatumFilter(t *testing.T) {
+	filter, err := newDatumFilter(&pps.ListDatumRequest{Job: client.NewJob("job")})
+	require.NoError(t, err)
+	require.True(t, filter == nil)
+
+	datumInfo := func(id string, state pps.DatumState, paths ...string) *pps.DatumInfo {
+		di := &pps.DatumInfo{Datum: &pps.Datum{ID: id}, State: state}
+		for _, p := range paths {
+			di.Data = append(di.Data, &pfs.FileInfo{File: client.NewFile("in", "master", p)})
+		}
+		return di
+	}
+	datumInfos := []*pps.DatumInfo{
+		datumInfo("a", pps.DatumState_FAILED, "/images/a.png"),
+		datumInfo("b", pps.DatumState_SUCCESS, "/images/b.png"),
+		datumInfo("c", pps.DatumState_FAILED, "/text/c.txt"),
+		datumInfo("d", pps.DatumState_SKIPPED, "/text/d.txt", "images/d.png"),
+	}
+	ids := func(datumInfos []*pps.DatumInfo) []string {
+		var result []string
+		for _, di := range datumInfos {
+			result = append(result, di.Datum.ID)
+		}
+		return result
+	}
+
+	result, summary := filterDatums(nil, datumInfos)
+	require.Equal(t, []string{"a", "b", "c", "d"}, ids(result))
+	require.Equal(t, &pps.DatumSummary{Total: 4, Failed: 2, Success: 1, Skipped: 1}, summary)
+
+	// The summary counts the datums that match the glob, in any state
+	filter, err = newDatumFilter(&pps.ListDatumRequest{
+		State:     []pps.DatumState{pps.DatumState_FAILED, pps.DatumState_SKIPPED},
+		InputGlob: "images/*",
+	})
+	require.NoError(t, err)
+	result, summary = filterDatums(filter, datumInfos)
+	require.Equal(t, []string{"a", "d"}, ids(result))
+	require.Equal(t, &pps.DatumSummary{Total: 3, Failed: 1, Success: 1, Skipped: 1}, summary)
+
+	filter, err = newDatumFilter(&pps.ListDatumRequest{State: []pps.DatumState{pps.DatumState_RECOVERED}})
+	require.NoError(t, err)
+	result, summary = filterDatums(filter, datumInfos)
+	require.Equal(t, 0, len(result))
+	require.Equal(t, int64(4), summary.Total)
+
+	_, err = newDatumFilter(&pps.ListDatumRequest{InputGlob: "[a-"})
+	require.YesError(t, err)
+}
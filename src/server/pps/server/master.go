@@ -64,6 +64,8 @@ func (a *apiServer) master() {
 		kubeClient := a.env.GetKubeClient()
 
 		log.Infof("PPS master: launching master process")
+		go a.pruneJobs(pachClient.WithCtx(ctx))
+		go a.expireCommits(pachClient.WithCtx(ctx))
 
 		// TODO(msteffen) requestly only keys, since pipeline_controller.go reads
 		// fresh values for each event anyway
@@ -192,7 +194,7 @@ func (a *apiServer) deletePipelineResources(ctx context.Context, pipelineName st
 	a.cancelMonitor(pipelineName)
 
 	kubeClient := a.env.GetKubeClient()
-	// Delete any services associated with op.pipeline
+	// Delete any services (and ingresses) associated with op.pipeline
 	selector := fmt.Sprintf("%s=%s", pipelineNameLabel, pipelineName)
 	opts := &metav1.DeleteOptions{
 		OrphanDependents: &falseVal,
@@ -208,6 +210,17 @@ func (a *apiServer) deletePipelineResources(ctx context.Context, pipelineName st
 			}
 		}
 	}
+	ingresses, err := kubeClient.ExtensionsV1beta1().Ingresses(a.namespace).List(metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return fmt.Errorf("could not list ingresses: %v", err)
+	}
+	for _, ingress := range ingresses.Items {
+		if err := kubeClient.ExtensionsV1beta1().Ingresses(a.namespace).Delete(ingress.Name, opts); err != nil {
+			if !isNotFoundErr(err) {
+				return fmt.Errorf("could not delete ingress %q: %v", ingress.Name, err)
+			}
+		}
+	}
 	rcs, err := kubeClient.CoreV1().ReplicationControllers(a.namespace).List(metav1.ListOptions{LabelSelector: selector})
 	if err != nil {
 		return fmt.Errorf("could not list RCs: %v", err)
@@ -264,10 +277,11 @@ func (a *apiServer) setPipelineState(pachClient *client.APIClient, pipelineInfo
 
 func (a *apiServer) monitorPipeline(pachClient *client.APIClient, pipelineInfo *pps.PipelineInfo) {
 	log.Printf("PPS master: monitoring pipeline %q", pipelineInfo.Pipeline.Name)
-	// If this exits (e.g. b/c Standby is false, and pipeline has no cron inputs),
-	// remove this fn's cancel() call from a.monitorCancels (if it hasn't already
-	// been removed, e.g. by deletePipelineResources cancelling this call), so
-	// that it can be called again
+	// If this exits (e.g. b/c Standby is false, and pipeline has no cron or SQL
+	// inputs, autoscaling_spec or notifications), remove this fn's cancel()
+	// call from a.monitorCancels (if it hasn't already been removed, e.g. by
+	// deletePipelineResources cancelling this call), so that it can be called
+	// again
 	defer a.cancelMonitor(pipelineInfo.Pipeline.Name)
 	var eg errgroup.Group
 	pps.VisitInput(pipelineInfo.Input, func(in *pps.Input) {
@@ -278,7 +292,26 @@ func (a *apiServer) monitorPipeline(pachClient *client.APIClient, pipelineInfo *
 				}, backoff.NewInfiniteBackOff(), notifyCtx(pachClient.Ctx(), "cron for "+in.Cron.Name))
 			})
 		}
+		if in.SQL != nil {
+			eg.Go(func() error {
+				return backoff.RetryNotify(func() error {
+					return a.makeSQLCommits(pachClient, in)
+				}, backoff.NewInfiniteBackOff(), notifyCtx(pachClient.Ctx(), "sql for "+in.SQL.Name))
+			})
+		}
 	})
+	if pipelineInfo.AutoscalingSpec != nil {
+		eg.Go(func() error {
+			return a.autoscalePipeline(pachClient, pipelineInfo)
+		})
+	}
+	if len(pipelineInfo.Notifications) > 0 {
+		eg.Go(func() error {
+			return backoff.RetryNotify(func() error {
+				return a.notifyJobs(pachClient, pipelineInfo)
+			}, backoff.NewInfiniteBackOff(), notifyCtx(pachClient.Ctx(), "notifications for "+pipelineInfo.Pipeline.Name))
+		})
+	}
 	if pipelineInfo.Standby {
 		// Capacity 1 gives us a bit of buffer so we don't needlessly go into
 		// standby when SubscribeCommit takes too long to return.
@@ -0,0 +1,365 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/backoff"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsdb"
+	"github.com/pachyderm/pachyderm/src/server/pkg/watch"
+)
+
+// notificationRetryTime is how long the PPS master retries a notification
+// that it can't deliver before dropping it
+const notificationRetryTime = 10 * time.Minute
+
+// notificationRequestTimeout bounds each attempt to deliver a notification
+const notificationRequestTimeout = 30 * time.Second
+
+// newNotificationBackOff returns the backoff with which failed notifications
+// are retried (tests retry faster)
+var newNotificationBackOff = func() backoff.BackOff {
+	b := backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = notificationRetryTime
+	return b
+}
+
+// jobNotification describes a job event. Webhooks receive it as JSON, and
+// webhook URLs are templates over its fields.
+type jobNotification struct {
+	Pipeline      string     `json:"pipeline"`
+	JobID         string     `json:"job_id"`
+	Event         string     `json:"event"`
+	State         string     `json:"state"`
+	Reason        string     `json:"reason,omitempty"`
+	OutputCommit  string     `json:"output_commit,omitempty"`
+	Started       *time.Time `json:"started,omitempty"`
+	Finished      *time.Time `json:"finished,omitempty"`
+	DataProcessed int64      `json:"data_processed"`
+	DataSkipped   int64      `json:"data_skipped"`
+	DataFailed    int64      `json:"data_failed"`
+	DataTotal     int64      `json:"data_total"`
+}
+
+func newJobNotification(jobPtr *pps.EtcdJobInfo, event pps.JobEvent) *jobNotification {
+	n := &jobNotification{
+		Pipeline:      jobPtr.Pipeline.Name,
+		JobID:         jobPtr.Job.ID,
+		Event:         event.String(),
+		State:         jobPtr.State.String(),
+		Reason:        jobPtr.Reason,
+		DataProcessed: jobPtr.DataProcessed,
+		DataSkipped:   jobPtr.DataSkipped,
+		DataFailed:    jobPtr.DataFailed,
+		DataTotal:     jobPtr.DataTotal,
+	}
+	if jobPtr.OutputCommit != nil {
+		n.OutputCommit = jobPtr.OutputCommit.ID
+	}
+	if t, err := types.TimestampFromProto(jobPtr.Started); err == nil {
+		n.Started = &t
+	}
+	if t, err := types.TimestampFromProto(jobPtr.Finished); err == nil {
+		n.Finished = &t
+	}
+	return n
+}
+
+// summary is the one-line text of a notification that's sent to Slack or by
+// email
+func (n *jobNotification) summary() string {
+	var what string
+	switch n.Event {
+	case pps.JobEvent_JOB_EVENT_STARTED.String():
+		what = "started"
+	case pps.JobEvent_JOB_EVENT_SUCCESS.String():
+		what = "succeeded"
+	case pps.JobEvent_JOB_EVENT_FAILURE.String():
+		what = "failed"
+	case pps.JobEvent_JOB_EVENT_KILLED.String():
+		what = "was killed"
+	case pps.JobEvent_JOB_EVENT_EGRESS_COMPLETE.String():
+		what = "finished its egress"
+	default:
+		what = n.Event
+	}
+	result := fmt.Sprintf("Job %s of pipeline %s %s", n.JobID, n.Pipeline, what)
+	if n.DataTotal > 0 {
+		result += fmt.Sprintf(" (%d datums processed, %d skipped, %d failed, of %d)",
+			n.DataProcessed, n.DataSkipped, n.DataFailed, n.DataTotal)
+	}
+	if n.Reason != "" {
+		result += ": " + n.Reason
+	}
+	return result
+}
+
+// jobStatus is the part of a job's state that job events are derived from
+type jobStatus struct {
+	state  pps.JobState
+	egress pps.EgressState
+}
+
+func jobStatusOf(jobPtr *pps.EtcdJobInfo) jobStatus {
+	status := jobStatus{state: jobPtr.State}
+	if jobPtr.EgressStatus != nil {
+		status.egress = jobPtr.EgressStatus.State
+	}
+	return status
+}
+
+// jobEvents returns the events that a job had, if its status went from
+// 'prev' to 'cur'
+func jobEvents(prev, cur jobStatus) []pps.JobEvent {
+	var events []pps.JobEvent
+	if cur.state != prev.state {
+		switch cur.state {
+		case pps.JobState_JOB_RUNNING:
+			events = append(events, pps.JobEvent_JOB_EVENT_STARTED)
+		case pps.JobState_JOB_SUCCESS:
+			events = append(events, pps.JobEvent_JOB_EVENT_SUCCESS)
+		case pps.JobState_JOB_FAILURE:
+			events = append(events, pps.JobEvent_JOB_EVENT_FAILURE)
+		case pps.JobState_JOB_KILLED:
+			events = append(events, pps.JobEvent_JOB_EVENT_KILLED)
+		}
+	}
+	if cur.egress == pps.EgressState_EGRESS_SUCCESS && prev.egress != pps.EgressState_EGRESS_SUCCESS {
+		events = append(events, pps.JobEvent_JOB_EVENT_EGRESS_COMPLETE)
+	}
+	return events
+}
+
+// wantsEvent returns whether notification 'n' is sent for 'event'
+func wantsEvent(n *pps.Notification, event pps.JobEvent) bool {
+	if len(n.Events) == 0 {
+		return true
+	}
+	for _, e := range n.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// validateNotification checks a notification in a pipeline spec
+func validateNotification(n *pps.Notification) error {
+	var set int
+	for _, isSet := range []bool{n.Webhook != nil, n.Slack != nil, n.Email != nil} {
+		if isSet {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("a notification must set exactly one of webhook, slack and email")
+	}
+	for _, e := range n.Events {
+		if _, ok := pps.JobEvent_name[int32(e)]; !ok {
+			return fmt.Errorf("notification has unknown event %d", e)
+		}
+	}
+	switch {
+	case n.Webhook != nil:
+		if n.Webhook.URL == "" {
+			return fmt.Errorf("webhook notification must set url")
+		}
+		if _, err := webhookURL(n.Webhook, &jobNotification{}); err != nil {
+			return fmt.Errorf("invalid webhook notification url %q: %v", n.Webhook.URL, err)
+		}
+	case n.Slack != nil:
+		if n.Slack.WebhookURL == "" {
+			return fmt.Errorf("slack notification must set webhook_url")
+		}
+	case n.Email != nil:
+		if len(n.Email.To) == 0 || n.Email.From == "" {
+			return fmt.Errorf("email notification must set to and from")
+		}
+		if _, _, err := net.SplitHostPort(n.Email.SMTPServer); err != nil {
+			return fmt.Errorf("invalid email notification smtp_server %q (must be host:port): %v", n.Email.SMTPServer, err)
+		}
+	}
+	return nil
+}
+
+// webhookURL expands the URL template of a webhook notification
+func webhookURL(webhook *pps.WebhookNotification, payload *jobNotification) (string, error) {
+	t, err := template.New("url").Option("missingkey=error").Parse(webhook.URL)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, payload); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// notifyJobs sends the notifications of a pipeline with notifications set
+// for its jobs' events, as the jobs collection changes. It's a helper
+// function called by monitorPipeline, and returns once pachClient's context
+// is cancelled. Events that happened before it started (e.g. while the PPS
+// master was failing over) aren't sent.
+func (a *apiServer) notifyJobs(pachClient *client.APIClient, pipelineInfo *pps.PipelineInfo) error {
+	ctx := pachClient.Ctx()
+	seen := make(map[string]jobStatus)
+	jobPtr := &pps.EtcdJobInfo{}
+	if err := a.jobs.ReadOnly(ctx).GetByIndex(ppsdb.JobsPipelineIndex, pipelineInfo.Pipeline, jobPtr, col.DefaultOptions, func(jobID string) error {
+		seen[jobID] = jobStatusOf(jobPtr)
+		return nil
+	}); err != nil {
+		return err
+	}
+	watcher, err := a.jobs.ReadOnly(ctx).WatchByIndex(ppsdb.JobsPipelineIndex, pipelineInfo.Pipeline)
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+	for {
+		var ev *watch.Event
+		var ok bool
+		select {
+		case ev, ok = <-watcher.Watch():
+		case <-ctx.Done():
+			return context.Canceled
+		}
+		if !ok {
+			return fmt.Errorf("job watch closed unexpectedly")
+		}
+		switch ev.Type {
+		case watch.EventError:
+			return ev.Err
+		case watch.EventDelete:
+			delete(seen, string(ev.Key))
+			continue
+		}
+		var jobID string
+		jobPtr := &pps.EtcdJobInfo{}
+		if err := ev.Unmarshal(&jobID, jobPtr); err != nil {
+			return err
+		}
+		status := jobStatusOf(jobPtr)
+		events := jobEvents(seen[jobID], status)
+		seen[jobID] = status
+		for _, event := range events {
+			payload := newJobNotification(jobPtr, event)
+			for _, n := range pipelineInfo.Notifications {
+				if wantsEvent(n, event) {
+					go a.deliverNotification(ctx, n, payload)
+				}
+			}
+		}
+	}
+}
+
+// deliverNotification sends a notification, retrying with backoff if it
+// fails
+func (a *apiServer) deliverNotification(ctx context.Context, n *pps.Notification, payload *jobNotification) {
+	if err := backoff.RetryNotify(func() error {
+		var auth smtp.Auth
+		if n.Email != nil && n.Email.Secret != "" {
+			var err error
+			if auth, err = a.smtpAuth(n.Email); err != nil {
+				return err
+			}
+		}
+		ctx, cancel := context.WithTimeout(ctx, notificationRequestTimeout)
+		defer cancel()
+		return sendNotification(ctx, n, payload, auth)
+	}, newNotificationBackOff(), notifyCtx(ctx, fmt.Sprintf("%s notification for job %s", payload.Event, payload.JobID))); err != nil {
+		log.Errorf("PPS master: giving up on %s notification for job %s of %q: %v", payload.Event, payload.JobID, payload.Pipeline, err)
+	}
+}
+
+// smtpAuth reads the credentials of an email notification from its secret
+func (a *apiServer) smtpAuth(email *pps.EmailNotification) (smtp.Auth, error) {
+	secret, err := a.env.GetKubeClient().CoreV1().Secrets(a.namespace).Get(email.Secret, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error reading secret %q of email notification: %v", email.Secret, err)
+	}
+	host, _, err := net.SplitHostPort(email.SMTPServer)
+	if err != nil {
+		return nil, err
+	}
+	return smtp.PlainAuth("", string(secret.Data["username"]), string(secret.Data["password"]), host), nil
+}
+
+// sendNotification makes one attempt to deliver notification 'n' of
+// 'payload'. 'auth' authenticates email notifications, and may be nil.
+func sendNotification(ctx context.Context, n *pps.Notification, payload *jobNotification, auth smtp.Auth) error {
+	switch {
+	case n.Webhook != nil:
+		url, err := webhookURL(n.Webhook, payload)
+		if err != nil {
+			return err
+		}
+		body, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		return postJSON(ctx, url, n.Webhook.Headers, body)
+	case n.Slack != nil:
+		msg := map[string]string{"text": payload.summary()}
+		if n.Slack.Channel != "" {
+			msg["channel"] = n.Slack.Channel
+		}
+		body, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		return postJSON(ctx, n.Slack.WebhookURL, nil, body)
+	case n.Email != nil:
+		summary := payload.summary()
+		details, err := json.MarshalIndent(payload, "", "  ")
+		if err != nil {
+			return err
+		}
+		msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s\r\n\r\n%s\r\n",
+			n.Email.From, strings.Join(n.Email.To, ", "), summary, summary, details)
+		return smtp.SendMail(n.Email.SMTPServer, auth, n.Email.From, n.Email.To, []byte(msg))
+	}
+	return nil
+}
+
+// postJSON POSTs a JSON body to 'url', and fails unless it gets a 2xx
+// response
+func postJSON(ctx context.Context, url string, headers map[string]string, body []byte) error {
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		// The URL isn't in the error, as it may include credentials (e.g. a
+		// Slack webhook's token)
+		return fmt.Errorf("notification endpoint returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
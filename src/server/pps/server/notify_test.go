@@ -0,0 +1,113 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/backoff"
+)
+
+func TestJobEvents(t *testing.T) {
+	running := jobStatus{state: pps.JobState_JOB_RUNNING}
+	require.Equal(t, []pps.JobEvent{pps.JobEvent_JOB_EVENT_STARTED}, jobEvents(jobStatus{}, running))
+	// updates that don't change the state (e.g. datum counts) aren't events
+	require.Equal(t, 0, len(jobEvents(running, running)))
+	require.Equal(t, 0, len(jobEvents(running, jobStatus{state: pps.JobState_JOB_MERGING})))
+	require.Equal(t, []pps.JobEvent{pps.JobEvent_JOB_EVENT_FAILURE},
+		jobEvents(running, jobStatus{state: pps.JobState_JOB_FAILURE}))
+
+	egressed := jobStatus{state: pps.JobState_JOB_RUNNING, egress: pps.EgressState_EGRESS_SUCCESS}
+	require.Equal(t, []pps.JobEvent{pps.JobEvent_JOB_EVENT_EGRESS_COMPLETE}, jobEvents(running, egressed))
+	require.Equal(t, []pps.JobEvent{pps.JobEvent_JOB_EVENT_SUCCESS},
+		jobEvents(egressed, jobStatus{state: pps.JobState_JOB_SUCCESS, egress: pps.EgressState_EGRESS_SUCCESS}))
+
+	n := &pps.Notification{Events: []pps.JobEvent{pps.JobEvent_JOB_EVENT_FAILURE}}
+	require.True(t, wantsEvent(n, pps.JobEvent_JOB_EVENT_FAILURE))
+	require.False(t, wantsEvent(n, pps.JobEvent_JOB_EVENT_SUCCESS))
+	require.True(t, wantsEvent(&pps.Notification{}, pps.JobEvent_JOB_EVENT_SUCCESS))
+}
+
+func TestValidateNotification(t *testing.T) {
+	require.NoError(t, validateNotification(&pps.Notification{
+		Webhook: &pps.WebhookNotification{URL: "http://ci/{{.Pipeline}}?job={{.JobID}}"},
+	}))
+	require.NoError(t, validateNotification(&pps.Notification{
+		Email: &pps.EmailNotification{To: []string{"team@example.com"}, From: "pachyderm@example.com", SMTPServer: "smtp:587"},
+	}))
+	for _, n := range []*pps.Notification{
+		{},
+		{
+			Webhook: &pps.WebhookNotification{URL: "http://ci"},
+			Slack:   &pps.SlackNotification{WebhookURL: "http://slack"},
+		},
+		{Webhook: &pps.WebhookNotification{URL: "http://ci/{{.NoSuchField}}"}},
+		{Slack: &pps.SlackNotification{}},
+		{Email: &pps.EmailNotification{To: []string{"team@example.com"}, From: "pachyderm@example.com", SMTPServer: "smtp"}},
+		{Events: []pps.JobEvent{100}, Slack: &pps.SlackNotification{WebhookURL: "http://slack"}},
+	} {
+		require.YesError(t, validateNotification(n))
+	}
+}
+
+func TestSendNotification(t *testing.T) {
+	var mu sync.Mutex
+	var requests []*http.Request
+	var bodies [][]byte
+	fail := 1
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, r)
+		bodies = append(bodies, body)
+		if fail > 0 {
+			fail--
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+	payload := newJobNotification(&pps.EtcdJobInfo{
+		Job:       client.NewJob("abc"),
+		Pipeline:  client.NewPipeline("edges"),
+		State:     pps.JobState_JOB_FAILURE,
+		Reason:    "datum failed",
+		DataTotal: 3,
+	}, pps.JobEvent_JOB_EVENT_FAILURE)
+
+	// webhooks get the payload at their expanded URL, and failed deliveries
+	// are retried
+	n := &pps.Notification{Webhook: &pps.WebhookNotification{
+		URL:     server.URL + "/hooks/{{.Pipeline}}?job={{.JobID}}",
+		Headers: map[string]string{"Authorization": "Bearer token"},
+	}}
+	newNotificationBackOff = func() backoff.BackOff { return backoff.NewTestingBackOff() }
+	a := &apiServer{}
+	a.deliverNotification(context.Background(), n, payload)
+	require.Equal(t, 2, len(requests))
+	require.Equal(t, "/hooks/edges", requests[1].URL.Path)
+	require.Equal(t, "abc", requests[1].URL.Query().Get("job"))
+	require.Equal(t, "Bearer token", requests[1].Header.Get("Authorization"))
+	var got jobNotification
+	require.NoError(t, json.Unmarshal(bodies[1], &got))
+	require.Equal(t, "JOB_EVENT_FAILURE", got.Event)
+	require.Equal(t, "datum failed", got.Reason)
+	require.Equal(t, int64(3), got.DataTotal)
+
+	// slack gets a summary
+	require.NoError(t, sendNotification(context.Background(), &pps.Notification{
+		Slack: &pps.SlackNotification{WebhookURL: server.URL, Channel: "#data"},
+	}, payload, nil))
+	var msg map[string]string
+	require.NoError(t, json.Unmarshal(bodies[2], &msg))
+	require.Equal(t, "#data", msg["channel"])
+	require.Equal(t, "Job abc of pipeline edges failed (0 datums processed, 0 skipped, 0 failed, of 3): datum failed", msg["text"])
+}
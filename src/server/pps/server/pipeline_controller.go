@@ -96,6 +96,14 @@ func (a *apiServer) step(pachClient *client.APIClient, pipeline string, keyVer,
 		pachClient = pachClient.WithCtx(ctx) //lint:ignore SA4006 pachClient is unused but we want the right one in scope in case someone uses it below in the future
 	}
 
+	// Patch the RC if the pipeline was updated in place
+	if op.rc != nil && op.ptr.State != pps.PipelineState_PIPELINE_FAILURE &&
+		op.rcIsFresh() && op.rc.ObjectMeta.Annotations[specCommitAnnotation] != op.ptr.SpecCommit.ID {
+		if err := op.updatePipelineInPlace(); err != nil {
+			return err
+		}
+	}
+
 	// Bring 'pipeline' into the correct state by taking appropriate action
 	switch op.ptr.State {
 	case pps.PipelineState_PIPELINE_STARTING, pps.PipelineState_PIPELINE_RESTARTING:
@@ -118,7 +126,9 @@ func (a *apiServer) step(pachClient *client.APIClient, pipeline string, keyVer,
 				return err
 			}
 		}
-	case pps.PipelineState_PIPELINE_RUNNING:
+	case pps.PipelineState_PIPELINE_RUNNING, pps.PipelineState_PIPELINE_CRASHING:
+		// crashing pipelines keep their workers, which report when they start
+		// successfully again
 		if !op.rcIsFresh() {
 			return op.restartPipeline("stale RC") // step() will be called again after etcd write
 		}
@@ -226,7 +236,9 @@ func (op *pipelineOp) getRC(expectation rcExpectation) (retErr error) {
 
 	kubeClient := op.apiServer.env.GetKubeClient()
 	namespace := op.apiServer.namespace
-	selector := fmt.Sprintf("%s=%s", pipelineNameLabel, op.name)
+	// The RC of the pipeline's merge workers (if any) is managed with op.rc,
+	// see updateMergeRCs
+	selector := fmt.Sprintf("%s=%s,!%s", pipelineNameLabel, op.name, mergeWorkersLabel)
 
 	// count error types separately, so that this only errors if the pipeline is
 	// stuck and not changing
@@ -316,7 +328,10 @@ func (op *pipelineOp) rcIsFresh() bool {
 		log.Errorf("PPS master: auth token in %q is stale %s != %s",
 			op.name, rcAuthTokenHash, hashAuthToken(op.ptr.AuthToken))
 		return false
-	case rcSpecCommit != op.ptr.SpecCommit.ID:
+	case rcSpecCommit != op.ptr.SpecCommit.ID && (expectedName == "" || rcName != expectedName):
+		// If the RC has the current version's name, the pipeline was updated in
+		// place (see ppsutil.UpdatableInPlace), and updatePipelineInPlace will
+		// patch the RC's spec commit and resources
 		log.Errorf("PPS master: spec commit in %q looks stale %s != %s",
 			op.name, rcSpecCommit, op.ptr.SpecCommit.ID)
 		return false
@@ -381,8 +396,8 @@ func (op *pipelineOp) createPipelineResources() error {
 }
 
 // startPipelineMonitor spawns a monitorPipeline() goro for this pipeline (if
-// one doesn't exist already), which manages standby and cron inputs, and
-// updates the the pipeline state.
+// one doesn't exist already), which manages standby, cron and SQL inputs,
+// autoscaling and notifications, and updates the the pipeline state.
 // Note: this is called by every run through step(), so must be idempotent
 func (op *pipelineOp) startPipelineMonitor() {
 	op.apiServer.monitorCancelsMu.Lock()
@@ -510,6 +525,76 @@ func (op *pipelineOp) updateRC(update func(rc *v1.ReplicationController)) error
 	})
 }
 
+// updatePipelineInPlace points the RC associated with op's pipeline at op's
+// current spec commit, and sets the resources of its user code container,
+// after the pipeline was updated without changing its version. The running
+// workers reload the spec themselves (see worker.APIServer.reloadPipelineInfo)
+// but keep their old resources, as k8s can't resize a running pod; patching
+// the RC means that workers created later (e.g. by scaleUpPipeline) start
+// with the new spec and resources.
+//
+// Like other functions in this file, it takes responsibility for
+// failing/restarting op's pipeline if it can't update its RC (via updateRC)
+func (op *pipelineOp) updatePipelineInPlace() error {
+	log.Infof("PPS master: updating RC for %q in place to spec commit %s",
+		op.name, op.ptr.SpecCommit.ID)
+	options, err := op.apiServer.getWorkerOptions(op.ptr, op.pipelineInfo)
+	if err != nil {
+		return op.failPipeline(fmt.Sprintf("could not generate RC options: %v", err))
+	}
+	if err := op.updateRC(func(rc *v1.ReplicationController) {
+		op.rc.DeepCopyInto(rc)
+		setRCSpecCommit(rc, op.ptr.SpecCommit.ID)
+		setRCResources(rc, userCodeContainerName(options), userCodeResources(options))
+	}); err != nil {
+		return err
+	}
+	if err := op.updateMergeRCs(func(rc *v1.ReplicationController) {
+		setRCSpecCommit(rc, op.ptr.SpecCommit.ID)
+	}); err != nil {
+		return err
+	}
+	// re-read the RC, so that later updates aren't rejected as stale
+	return op.getRC(rcExpected)
+}
+
+// setRCResources sets the resources of the container named 'container' in
+// 'rc' to 'resources'
+func setRCResources(rc *v1.ReplicationController, container string, resources v1.ResourceRequirements) {
+	if rc.Spec.Template == nil {
+		return
+	}
+	for i := range rc.Spec.Template.Spec.Containers {
+		if rc.Spec.Template.Spec.Containers[i].Name == container {
+			rc.Spec.Template.Spec.Containers[i].Resources = resources
+		}
+	}
+}
+
+// setRCSpecCommit sets the spec commit annotation and worker env var in 'rc'
+// to 'specCommit'
+func setRCSpecCommit(rc *v1.ReplicationController, specCommit string) {
+	if rc.ObjectMeta.Annotations == nil {
+		rc.ObjectMeta.Annotations = make(map[string]string)
+	}
+	rc.ObjectMeta.Annotations[specCommitAnnotation] = specCommit
+	if rc.Spec.Template == nil {
+		return
+	}
+	if rc.Spec.Template.ObjectMeta.Annotations == nil {
+		rc.Spec.Template.ObjectMeta.Annotations = make(map[string]string)
+	}
+	rc.Spec.Template.ObjectMeta.Annotations[specCommitAnnotation] = specCommit
+	for i := range rc.Spec.Template.Spec.Containers {
+		env := rc.Spec.Template.Spec.Containers[i].Env
+		for j := range env {
+			if env[j].Name == client.PPSSpecCommitEnv {
+				env[j].Value = specCommit
+			}
+		}
+	}
+}
+
 // scaleUpPipeline edits the RC associated with op's pipeline & spins up the
 // configured number of workers.
 //
@@ -534,14 +619,32 @@ func (op *pipelineOp) scaleUpPipeline() (retErr error) {
 		log.Errorf("PPS master: error getting number of workers (defaulting to 1 worker): %v", err)
 		parallelism = 1
 	}
+	if spec := op.pipelineInfo.AutoscalingSpec; spec != nil {
+		// autoscalePipeline sets the number of workers of autoscaled pipelines,
+		// so keep it, unless the pipeline was scaled down to 0
+		current := 0
+		if op.rc.Spec.Replicas != nil {
+			current = int(*op.rc.Spec.Replicas)
+		}
+		parallelism = clampWorkers(spec, current)
+	}
 
 	// update pipeline RC
-	return op.updateRC(func(rc *v1.ReplicationController) {
+	if err := op.updateRC(func(rc *v1.ReplicationController) {
 		if rc.Spec.Replicas != nil && *op.rc.Spec.Replicas == int32(parallelism) {
 			return // prior attempt succeeded
 		}
 		rc.Spec.Replicas = new(int32)
 		*rc.Spec.Replicas = int32(parallelism)
+	}); err != nil {
+		return err
+	}
+	var mergeWorkers int32
+	if op.pipelineInfo.MergeSpec != nil {
+		mergeWorkers = int32(op.pipelineInfo.MergeSpec.Workers)
+	}
+	return op.updateMergeRCs(func(rc *v1.ReplicationController) {
+		rc.Spec.Replicas = &mergeWorkers
 	})
 }
 
@@ -562,11 +665,66 @@ func (op *pipelineOp) scaleDownPipeline() (retErr error) {
 		tracing.FinishAnySpan(span)
 	}()
 
-	return op.updateRC(func(rc *v1.ReplicationController) {
+	if err := op.updateRC(func(rc *v1.ReplicationController) {
 		if rc.Spec.Replicas != nil && *op.rc.Spec.Replicas == 0 {
 			return // prior attempt succeeded
 		}
 		rc.Spec.Replicas = &zero
+	}); err != nil {
+		return err
+	}
+	return op.updateMergeRCs(func(rc *v1.ReplicationController) {
+		rc.Spec.Replicas = &zero
+	})
+}
+
+// updateMergeRCs applies 'update' to the RC of the merge workers of op's
+// pipeline (see pps.MergeSpec), if it has one, and writes it to kubernetes.
+// The RCs of the merge workers of older versions of the pipeline are deleted
+// instead. Unlike op.rc, a missing merge worker RC isn't an error: it's
+// created (or re-created) with the pipeline's RC by createPipelineResources.
+//
+// Like other functions in this file, it takes responsibility for failing op's
+// pipeline if it can't update the RC, and then returns an error to the caller
+// to indicate that the caller shouldn't continue with further operations
+func (op *pipelineOp) updateMergeRCs(update func(rc *v1.ReplicationController)) error {
+	kubeClient := op.apiServer.env.GetKubeClient()
+	namespace := op.apiServer.namespace
+	selector := fmt.Sprintf("%s=%s,%s", pipelineNameLabel, op.name, mergeWorkersLabel)
+	var expectedName string
+	if op.pipelineInfo.MergeSpec != nil {
+		expectedName = mergeRcName(ppsutil.PipelineRcName(op.name, op.pipelineInfo.Version))
+	}
+
+	var errCount int
+	return backoff.RetryNotify(func() error {
+		rcs, err := kubeClient.CoreV1().ReplicationControllers(namespace).List(
+			metav1.ListOptions{LabelSelector: selector})
+		if err != nil && !isNotFoundErr(err) {
+			return err
+		}
+		for i := range rcs.Items {
+			rc := &rcs.Items[i]
+			if rc.Name != expectedName {
+				if err := kubeClient.CoreV1().ReplicationControllers(namespace).Delete(
+					rc.Name, &metav1.DeleteOptions{OrphanDependents: &falseVal}); err != nil && !isNotFoundErr(err) {
+					return fmt.Errorf("could not delete stale merge worker RC %q: %v", rc.Name, err)
+				}
+				continue
+			}
+			update(rc)
+			if _, err := kubeClient.CoreV1().ReplicationControllers(namespace).Update(rc); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, backoff.NewInfiniteBackOff(), func(err error, d time.Duration) error {
+		if errCount++; errCount >= maxErrCount {
+			return op.failPipeline(fmt.Sprintf("failed to update merge worker RC after %d attempts: %v",
+				errCount, err))
+		}
+		log.Errorf("PPS master: error updating merge worker RC for pipeline %q: %v; retrying in %v", op.name, err, d)
+		return nil
 	})
 }
 
@@ -602,6 +760,21 @@ func (op *pipelineOp) restartPipeline(reason string) error {
 			if err != nil && !isNotFoundErr(err) {
 				return fmt.Errorf("could not delete RC %q: %v", op.rc.Name, err)
 			}
+			// The merge workers (if any) are just as stale, and
+			// createPipelineResources doesn't replace an existing RC
+			mergeRCs, err := kubeClient.CoreV1().ReplicationControllers(namespace).List(metav1.ListOptions{
+				LabelSelector: fmt.Sprintf("%s=%s,%s", pipelineNameLabel, op.name, mergeWorkersLabel),
+			})
+			if err != nil && !isNotFoundErr(err) {
+				return fmt.Errorf("could not list merge worker RCs: %v", err)
+			}
+			for _, rc := range mergeRCs.Items {
+				err := kubeClient.CoreV1().ReplicationControllers(namespace).Delete(
+					rc.Name, &metav1.DeleteOptions{OrphanDependents: &falseVal})
+				if err != nil && !isNotFoundErr(err) {
+					return fmt.Errorf("could not delete RC %q: %v", rc.Name, err)
+				}
+			}
 		}
 		// create up-to-date RC
 		if err := op.createPipelineResources(); err != nil {
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetRCSpecCommit(t *testing.T) {
+	rc := &v1.ReplicationController{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{specCommitAnnotation: "old"},
+		},
+		Spec: v1.ReplicationControllerSpec{
+			Template: &v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{
+						{Name: "user", Env: []v1.EnvVar{
+							{Name: client.PPSSpecCommitEnv, Value: "old"},
+							{Name: "FOO", Value: "old"},
+						}},
+						{Name: "storage"},
+					},
+				},
+			},
+		},
+	}
+	setRCSpecCommit(rc, "new")
+	require.Equal(t, "new", rc.ObjectMeta.Annotations[specCommitAnnotation])
+	require.Equal(t, "new", rc.Spec.Template.ObjectMeta.Annotations[specCommitAnnotation])
+	require.Equal(t, "new", rc.Spec.Template.Spec.Containers[0].Env[0].Value)
+	require.Equal(t, "old", rc.Spec.Template.Spec.Containers[0].Env[1].Value)
+}
+
+func TestSetRCResources(t *testing.T) {
+	rc := &v1.ReplicationController{
+		Spec: v1.ReplicationControllerSpec{
+			Template: &v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{
+						{Name: client.PPSWorkerUserContainerName},
+						{Name: client.PPSWorkerUserCodeContainerName},
+					},
+				},
+			},
+		},
+	}
+	resources := v1.ResourceRequirements{
+		Limits: v1.ResourceList{v1.ResourceMemory: resource.MustParse("1G")},
+	}
+	setRCResources(rc, client.PPSWorkerUserCodeContainerName, resources)
+	require.Equal(t, 0, len(rc.Spec.Template.Spec.Containers[0].Resources.Limits))
+	limit := rc.Spec.Template.Spec.Containers[1].Resources.Limits[v1.ResourceMemory]
+	require.Equal(t, "1G", limit.String())
+}
@@ -0,0 +1,435 @@
+package server
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+	globlib "github.com/pachyderm/ohmyglob"
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ancestry"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
+	"github.com/robfig/cron"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// pipelineLinter collects the diagnostics of a pipeline spec. Unlike
+// validatePipeline, which returns the first problem it finds, it reports
+// every problem, with the JSON path of the field at fault, and it also
+// warns about specs that are valid but likely mistaken.
+type pipelineLinter struct {
+	diagnostics []*pps.PipelineDiagnostic
+}
+
+func (l *pipelineLinter) errorf(path string, format string, args ...interface{}) {
+	l.diagnostics = append(l.diagnostics, &pps.PipelineDiagnostic{
+		Severity: pps.DiagnosticSeverity_DIAGNOSTIC_ERROR,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (l *pipelineLinter) warnf(path string, format string, args ...interface{}) {
+	l.diagnostics = append(l.diagnostics, &pps.PipelineDiagnostic{
+		Severity: pps.DiagnosticSeverity_DIAGNOSTIC_WARNING,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (l *pipelineLinter) hasErrors() bool {
+	for _, d := range l.diagnostics {
+		if d.Severity == pps.DiagnosticSeverity_DIAGNOSTIC_ERROR {
+			return true
+		}
+	}
+	return false
+}
+
+// lintPipeline returns the diagnostics of the pipeline spec in 'request'. It
+// doesn't modify 'request' or create anything, but it checks that the
+// pipeline's input repos exist, and whether the pipeline exists already.
+func (a *apiServer) lintPipeline(pachClient *client.APIClient, request *pps.CreatePipelineRequest) []*pps.PipelineDiagnostic {
+	// setPipelineDefaults modifies the request's inputs
+	request = proto.Clone(request).(*pps.CreatePipelineRequest)
+	l := &pipelineLinter{}
+	l.lintName(pachClient, request)
+	if request.Transform == nil {
+		l.errorf("transform", "pipeline must specify a transform")
+		return l.diagnostics
+	}
+	l.lintTransform(request.Transform)
+	if request.Input == nil {
+		if request.Spout == nil {
+			l.errorf("input", "pipeline must specify an input (only spouts can do without)")
+		}
+	} else {
+		l.lintInput(pachClient, request.Input, "input", make(map[string]string), request)
+	}
+	l.lintResources(request)
+	l.lintEgress(request.Egress)
+	l.lintAttestationSpec(request)
+	if request.Cache != nil {
+		if err := validateCache(request.Cache); err != nil {
+			l.errorf("cache", "%v", err)
+		}
+	}
+
+	// Everything that validatePipeline checks should have been reported with a
+	// path above, but it's the authority on whether the pipeline can be
+	// created
+	if !l.hasErrors() {
+		pipelineInfo := newPipelineInfo(request)
+		if err := setPipelineDefaults(pipelineInfo); err != nil {
+			l.errorf("", "%v", err)
+		} else if err := a.validatePipeline(pachClient, pipelineInfo); err != nil {
+			l.errorf("", "%v", grpcutil.ScrubGRPC(err))
+		}
+	}
+	return l.diagnostics
+}
+
+func (l *pipelineLinter) lintName(pachClient *client.APIClient, request *pps.CreatePipelineRequest) {
+	if request.Pipeline == nil || request.Pipeline.Name == "" {
+		l.errorf("pipeline.name", "pipeline must have a name")
+		return
+	}
+	name := request.Pipeline.Name
+	if err := ancestry.ValidateName(name); err != nil {
+		l.errorf("pipeline.name", "invalid pipeline name: %v", err)
+		return
+	}
+	if first := name[0]; !(first >= 'a' && first <= 'z' || first >= 'A' && first <= 'Z' || first >= '0' && first <= '9') {
+		l.errorf("pipeline.name", "pipeline names must start with an alphanumeric character")
+	}
+	if len(name) > 63 {
+		l.errorf("pipeline.name", "pipeline name is %d characters long, but must have at most 63", len(name))
+	}
+	_, err := pachClient.InspectPipeline(name)
+	switch {
+	case err == nil && !request.Update:
+		l.errorf("pipeline.name", "pipeline %q already exists, use `update pipeline` to change it", name)
+	case err != nil && request.Update && isNotFoundErr(err):
+		l.errorf("pipeline.name", "pipeline %q doesn't exist, so it can't be updated", name)
+	}
+	if request.TFJob != nil {
+		l.errorf("tf_job", "embedding TFJobs in pipelines is not supported yet")
+	}
+}
+
+func (l *pipelineLinter) lintTransform(transform *pps.Transform) {
+	switch {
+	case transform.Image == "" && len(transform.Cmd) == 0:
+		l.errorf("transform.cmd", "transform.cmd must be set unless transform.image is, as the default image (%s) has no entrypoint", DefaultUserImage)
+	case transform.Image == "":
+		l.warnf("transform.image", "transform.image isn't set, so the pipeline runs in %s", DefaultUserImage)
+	case len(transform.Cmd) == 0:
+		l.warnf("transform.cmd", "transform.cmd isn't set, so the entrypoint of %s is run", transform.Image)
+	}
+	if len(transform.Cmd) > 0 && transform.Cmd[0] == "" {
+		l.errorf("transform.cmd[0]", "the command to run can't be empty")
+	}
+	if len(transform.Stdin) > 0 && len(transform.Cmd) == 0 {
+		l.warnf("transform.stdin", "transform.stdin is written to the image's entrypoint, as transform.cmd isn't set")
+	}
+	if len(transform.ErrStdin) > 0 && len(transform.ErrCmd) == 0 {
+		l.errorf("transform.err_stdin", "transform.err_stdin is set, but transform.err_cmd isn't")
+	}
+	for _, field := range []struct{ name, mode string }{
+		{"umask", transform.Umask},
+		{"dir_mode", transform.DirMode},
+		{"file_mode", transform.FileMode},
+	} {
+		if _, err := ppsutil.ParseFileMode(field.mode); err != nil {
+			l.errorf("transform."+field.name, "%v", err)
+		}
+	}
+	if transform.WorkingDir != "" && isReservedPath(transform.WorkingDir) {
+		l.warnf("transform.working_dir", "%s is managed by Pachyderm, files written to the working directory %q aren't kept",
+			client.PPSInputPrefix, transform.WorkingDir)
+	}
+	names := map[string]bool{"init": true}
+	for i, c := range transform.InitContainers {
+		p := fmt.Sprintf("transform.init_containers[%d]", i)
+		if c.Name == "" {
+			l.errorf(p+".name", "init containers must have a name")
+		} else if names[c.Name] {
+			l.errorf(p+".name", "init container name %q is already in use", c.Name)
+		}
+		names[c.Name] = true
+		if c.Image == "" {
+			l.errorf(p+".image", "init containers must have an image")
+		}
+	}
+	for i, secret := range transform.Secrets {
+		p := fmt.Sprintf("transform.secrets[%d]", i)
+		if secret.Name == "" {
+			l.errorf(p+".name", "secrets must have a name")
+		}
+		switch {
+		case secret.MountPath == "" && secret.EnvVar == "":
+			l.warnf(p, "secret %q has neither a mount_path nor an env_var, so it isn't used", secret.Name)
+		case secret.MountPath != "" && isReservedPath(secret.MountPath):
+			l.errorf(p+".mount_path", "secrets can't be mounted in %s, which is managed by Pachyderm", client.PPSInputPrefix)
+		}
+		if secret.EnvVar != "" && secret.Key == "" {
+			l.errorf(p+".key", "secret %q must specify the key to put in %s", secret.Name, secret.EnvVar)
+		}
+	}
+	if len(transform.EnvAllowlist) > 0 && !transform.ScrubEnv {
+		l.errorf("transform.env_allowlist", "transform.env_allowlist can only be set with transform.scrub_env")
+	}
+	for i, name := range transform.EnvAllowlist {
+		if name == "" || strings.ContainsAny(name, "= ") {
+			l.errorf(fmt.Sprintf("transform.env_allowlist[%d]", i), "invalid environment variable name %q", name)
+		}
+	}
+}
+
+// isReservedPath returns true if 'p' is in /pfs, where Pachyderm mounts a
+// datum's inputs and output
+func isReservedPath(p string) bool {
+	p = path.Clean(p)
+	return p == client.PPSInputPrefix || strings.HasPrefix(p, client.PPSInputPrefix+"/")
+}
+
+// lintInput reports the problems of 'input', which is at 'p' in the spec.
+// 'names' maps the names of the inputs that are visible alongside 'input'
+// (i.e. all of the datum's inputs but those in other branches of a union) to
+// their paths.
+func (l *pipelineLinter) lintInput(pachClient *client.APIClient, input *pps.Input, p string, names map[string]string, request *pps.CreatePipelineRequest) {
+	var set []string
+	for _, t := range []struct {
+		name string
+		set  bool
+	}{
+		{"pfs", input.Pfs != nil},
+		{"cross", input.Cross != nil},
+		{"join", input.Join != nil},
+		{"union", input.Union != nil},
+		{"cron", input.Cron != nil},
+		{"git", input.Git != nil},
+		{"sql", input.SQL != nil},
+	} {
+		if t.set {
+			set = append(set, t.name)
+		}
+	}
+	switch len(set) {
+	case 0:
+		l.errorf(p, "no input set")
+		return
+	case 1:
+	default:
+		l.errorf(p, "multiple input types set (%s), each input must set exactly one", strings.Join(set, ", "))
+		return
+	}
+	switch {
+	case input.Pfs != nil:
+		p := p + ".pfs"
+		name := input.Pfs.Name
+		if name == "" {
+			name = input.Pfs.Repo
+		}
+		l.lintInputName(name, p, names, request)
+		if input.Pfs.Repo == "" {
+			l.errorf(p+".repo", "input must specify a repo")
+		} else if _, err := pachClient.InspectRepo(input.Pfs.Repo); err != nil {
+			l.errorf(p+".repo", "%v", grpcutil.ScrubGRPC(err))
+		}
+		if err := validateReadAhead(input.Pfs); err != nil {
+			l.errorf(p+".read_ahead", "%v", err)
+		}
+		if err := validateMount(input.Pfs); err != nil {
+			l.errorf(p+".mount", "%v", err)
+		}
+		if input.Pfs.Glob == "" {
+			l.errorf(p+".glob", "input must specify a glob")
+		} else if _, err := globlib.Compile(path.Join("/", input.Pfs.Glob), '/'); err != nil {
+			l.errorf(p+".glob", "invalid glob pattern %q: %v", input.Pfs.Glob, err)
+		}
+	case input.Cron != nil:
+		p := p + ".cron"
+		l.lintInputName(input.Cron.Name, p, names, request)
+		if _, err := cron.ParseStandard(input.Cron.Spec); err != nil {
+			l.errorf(p+".spec", "invalid cron spec %q: %v", input.Cron.Spec, err)
+		}
+	case input.Git != nil:
+		p := p + ".git"
+		if err := pps.ValidateGitCloneURL(input.Git.URL); err != nil {
+			l.errorf(p+".URL", "%v", err)
+		}
+		name := input.Git.Name
+		if name == "" {
+			name = strings.Split(path.Base(input.Git.URL), ".")[0]
+		}
+		l.lintInputName(name, p, names, request)
+	case input.SQL != nil:
+		p := p + ".sql"
+		l.lintInputName(input.SQL.Name, p, names, request)
+		if err := validateSQLInput(input.SQL); err != nil {
+			l.errorf(p, "%v", err)
+		}
+	case input.Cross != nil:
+		for i, input := range input.Cross {
+			l.lintInput(pachClient, input, fmt.Sprintf("%s.cross[%d]", p, i), names, request)
+		}
+	case input.Join != nil:
+		for i, input := range input.Join {
+			l.lintInput(pachClient, input, fmt.Sprintf("%s.join[%d]", p, i), names, request)
+		}
+	case input.Union != nil:
+		// The inputs of a union can share names, but the inputs crossed with
+		// the union can't have any of their names
+		unionNames := make(map[string]string)
+		for i, input := range input.Union {
+			branchNames := make(map[string]string)
+			for name, p := range names {
+				branchNames[name] = p
+			}
+			l.lintInput(pachClient, input, fmt.Sprintf("%s.union[%d]", p, i), branchNames, request)
+			for name, p := range branchNames {
+				if _, ok := unionNames[name]; !ok {
+					unionNames[name] = p
+				}
+			}
+		}
+		for name, p := range unionNames {
+			names[name] = p
+		}
+	}
+}
+
+// lintInputName reports the problems of 'name', the name of the input at 'p',
+// and adds it to 'names'
+func (l *pipelineLinter) lintInputName(name string, p string, names map[string]string, request *pps.CreatePipelineRequest) {
+	switch {
+	case name == "":
+		l.errorf(p+".name", "input must specify a name")
+		return
+	case name == "out":
+		l.errorf(p+".name", "input can't be named \"out\", as Pachyderm creates /pfs/out to collect the job's output")
+	case name == client.PPSScratchSpace:
+		l.errorf(p+".name", "input can't be named %q, which Pachyderm uses internally", name)
+	case name == client.PPSPrevOutputName && request.PreviousOutput:
+		l.errorf(p+".name", "input can't be named %q, as the pipeline mounts its previous output at /pfs/%s", name, name)
+	case name == client.PPSJobScratchName && request.JobScratch:
+		l.errorf(p+".name", "input can't be named %q, as the pipeline mounts its job scratch directory at /pfs/%s", name, name)
+	case strings.Contains(name, "/"):
+		l.errorf(p+".name", "input names can't contain \"/\"")
+	}
+	if other, ok := names[name]; ok {
+		l.errorf(p+".name", "input name %q is already used by %s", name, other)
+		return
+	}
+	names[name] = p
+}
+
+func (l *pipelineLinter) lintResources(request *pps.CreatePipelineRequest) {
+	l.lintResourceSpecs("resource_requests", request.ResourceRequests, "resource_limits", request.ResourceLimits)
+	if request.MergeSpec != nil {
+		l.lintResourceSpecs("merge_spec.resource_requests", request.MergeSpec.ResourceRequests,
+			"merge_spec.resource_limits", request.MergeSpec.ResourceLimits)
+	}
+	if request.CacheSize != "" {
+		cacheSize, err := resource.ParseQuantity(request.CacheSize)
+		if err != nil {
+			l.errorf("cache_size", "could not parse %q: %v", request.CacheSize, err)
+		} else if request.ResourceLimits != nil && request.ResourceLimits.Memory != "" {
+			if memory, err := resource.ParseQuantity(request.ResourceLimits.Memory); err == nil && cacheSize.Cmp(memory) > 0 {
+				l.warnf("cache_size", "cache_size (%s) is more than resource_limits.memory (%s)", request.CacheSize, request.ResourceLimits.Memory)
+			}
+		}
+	}
+	if request.PrefetchSize != "" {
+		if _, err := resource.ParseQuantity(request.PrefetchSize); err != nil {
+			l.errorf("prefetch_size", "could not parse %q: %v", request.PrefetchSize, err)
+		}
+	}
+}
+
+// lintResourceSpecs reports the problems of the resource requests and limits
+// at 'requestsPath' and 'limitsPath'
+func (l *pipelineLinter) lintResourceSpecs(requestsPath string, requests *pps.ResourceSpec, limitsPath string, limits *pps.ResourceSpec) {
+	parse := func(p string, spec *pps.ResourceSpec) (memory, disk *resource.Quantity) {
+		if spec == nil {
+			return nil, nil
+		}
+		if spec.Cpu < 0 {
+			l.errorf(p+".cpu", "cpu can't be negative")
+		}
+		if spec.Memory != "" {
+			if q, err := resource.ParseQuantity(spec.Memory); err != nil {
+				l.errorf(p+".memory", "could not parse %q: %v", spec.Memory, err)
+			} else {
+				memory = &q
+			}
+		}
+		if spec.Disk != "" {
+			if q, err := resource.ParseQuantity(spec.Disk); err != nil {
+				l.errorf(p+".disk", "could not parse %q: %v", spec.Disk, err)
+			} else {
+				disk = &q
+			}
+		}
+		if spec.Gpu != nil {
+			switch {
+			case spec.Gpu.Type == "":
+				l.errorf(p+".gpu.type", "the type of GPU must be set, e.g. \"nvidia.com/gpu\"")
+			case spec.Gpu.Number <= 0:
+				l.errorf(p+".gpu.number", "the number of GPUs must be positive")
+			}
+		}
+		return memory, disk
+	}
+	requestedMemory, requestedDisk := parse(requestsPath, requests)
+	memoryLimit, diskLimit := parse(limitsPath, limits)
+	if requests == nil || limits == nil {
+		if requests != nil && requests.Gpu != nil {
+			l.warnf(requestsPath+".gpu", "Kubernetes only schedules GPUs that are in the limits, set %s.gpu instead", limitsPath)
+		}
+		return
+	}
+	if limits.Cpu > 0 && requests.Cpu > limits.Cpu {
+		l.errorf(requestsPath+".cpu", "requested cpu (%v) is more than %s.cpu (%v)", requests.Cpu, limitsPath, limits.Cpu)
+	}
+	if requestedMemory != nil && memoryLimit != nil && requestedMemory.Cmp(*memoryLimit) > 0 {
+		l.errorf(requestsPath+".memory", "requested memory (%s) is more than %s.memory (%s)", requests.Memory, limitsPath, limits.Memory)
+	}
+	if requestedDisk != nil && diskLimit != nil && requestedDisk.Cmp(*diskLimit) > 0 {
+		l.errorf(requestsPath+".disk", "requested disk (%s) is more than %s.disk (%s)", requests.Disk, limitsPath, limits.Disk)
+	}
+	if requests.Gpu != nil && limits.Gpu == nil {
+		l.warnf(requestsPath+".gpu", "Kubernetes only schedules GPUs that are in the limits, set %s.gpu instead", limitsPath)
+	}
+}
+
+func (l *pipelineLinter) lintEgress(egress *pps.Egress) {
+	if egress == nil {
+		return
+	}
+	p := "egress.URL"
+	if egress.Postgres != nil {
+		p = "egress.postgres"
+	}
+	if err := validateEgress(egress); err != nil {
+		l.errorf(p, "%v", err)
+	}
+}
+
+func (l *pipelineLinter) lintAttestationSpec(request *pps.CreatePipelineRequest) {
+	if request.AttestationSpec == nil {
+		return
+	}
+	if !request.EnableStats {
+		l.warnf("attestation_spec", "attestation_spec has no effect unless enable_stats is set, as attestations are written to the stats commits")
+	}
+	for i, name := range request.AttestationSpec.Env {
+		if _, ok := request.Transform.Env[name]; !ok {
+			l.warnf(fmt.Sprintf("attestation_spec.env[%d]", i), "%q isn't in transform.env, so it won't be recorded", name)
+		}
+	}
+}
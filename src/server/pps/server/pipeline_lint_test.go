@@ -0,0 +1,123 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// diagnosticPaths returns the paths of the diagnostics with 'severity'
+func diagnosticPaths(l *pipelineLinter, severity pps.DiagnosticSeverity) []string {
+	var paths []string
+	for _, d := range l.diagnostics {
+		if d.Severity == severity {
+			paths = append(paths, d.Path)
+		}
+	}
+	return paths
+}
+
+func TestLintTransform(t *testing.T) {
+	l := &pipelineLinter{}
+	l.lintTransform(&pps.Transform{Image: "alpine", Cmd: []string{"sh"}})
+	require.Equal(t, 0, len(l.diagnostics))
+
+	l = &pipelineLinter{}
+	l.lintTransform(&pps.Transform{})
+	require.Equal(t, []string{"transform.cmd"}, diagnosticPaths(l, pps.DiagnosticSeverity_DIAGNOSTIC_ERROR))
+
+	l = &pipelineLinter{}
+	l.lintTransform(&pps.Transform{
+		Cmd:   []string{"sh"},
+		Umask: "999",
+		InitContainers: []*pps.InitContainer{
+			{Name: "setup", Image: "alpine"},
+			{Name: "setup"},
+		},
+		Secrets: []*pps.Secret{
+			{Name: "creds", MountPath: "/pfs/creds"},
+			{Name: "unused"},
+		},
+	})
+	require.Equal(t, []string{
+		"transform.umask",
+		"transform.init_containers[1].name",
+		"transform.init_containers[1].image",
+		"transform.secrets[0].mount_path",
+	}, diagnosticPaths(l, pps.DiagnosticSeverity_DIAGNOSTIC_ERROR))
+	require.Equal(t, []string{"transform.image", "transform.secrets[1]"},
+		diagnosticPaths(l, pps.DiagnosticSeverity_DIAGNOSTIC_WARNING))
+}
+
+func TestLintInputNames(t *testing.T) {
+	cron := func(name string) *pps.Input {
+		return &pps.Input{Cron: &pps.CronInput{Name: name, Spec: "@every 1m"}}
+	}
+	request := &pps.CreatePipelineRequest{PreviousOutput: true}
+	lint := func(input *pps.Input) []string {
+		l := &pipelineLinter{}
+		l.lintInput(nil, input, "input", make(map[string]string), request)
+		return diagnosticPaths(l, pps.DiagnosticSeverity_DIAGNOSTIC_ERROR)
+	}
+
+	// The branches of a union can share names, but not with the inputs they're
+	// crossed with
+	require.Equal(t, 0, len(lint(&pps.Input{Union: []*pps.Input{cron("a"), cron("a")}})))
+	require.Equal(t, []string{"input.cross[1].union[1].cron.name"}, lint(&pps.Input{Cross: []*pps.Input{
+		cron("a"),
+		{Union: []*pps.Input{cron("b"), cron("a")}},
+	}}))
+	require.Equal(t, []string{"input.cross[1].cron.name"}, lint(&pps.Input{Cross: []*pps.Input{
+		{Union: []*pps.Input{cron("b"), cron("a")}},
+		cron("b"),
+	}}))
+	// Reserved names
+	require.Equal(t, []string{"input.cross[0].cron.name", "input.cross[1].cron.name"}, lint(&pps.Input{Cross: []*pps.Input{
+		cron("out"),
+		cron("prev"),
+	}}))
+	// Cron specs and multiple input types
+	require.Equal(t, []string{"input.cron.spec"}, lint(&pps.Input{Cron: &pps.CronInput{Name: "tick", Spec: "never"}}))
+	require.Equal(t, []string{"input"}, lint(&pps.Input{Cron: &pps.CronInput{Name: "tick"}, Cross: []*pps.Input{cron("a")}}))
+}
+
+func TestLintResourceSpecs(t *testing.T) {
+	l := &pipelineLinter{}
+	l.lintResourceSpecs("resource_requests", &pps.ResourceSpec{Memory: "1G", Cpu: 1},
+		"resource_limits", &pps.ResourceSpec{Memory: "2G", Cpu: 2})
+	require.Equal(t, 0, len(l.diagnostics))
+
+	l = &pipelineLinter{}
+	l.lintResourceSpecs("resource_requests", &pps.ResourceSpec{
+		Memory: "4G",
+		Cpu:    -1,
+		Disk:   "lots",
+		Gpu:    &pps.GPUSpec{Number: 1},
+	}, "resource_limits", &pps.ResourceSpec{Memory: "2G"})
+	require.Equal(t, []string{
+		"resource_requests.cpu",
+		"resource_requests.disk",
+		"resource_requests.gpu.type",
+		"resource_requests.memory",
+	}, diagnosticPaths(l, pps.DiagnosticSeverity_DIAGNOSTIC_ERROR))
+	require.Equal(t, []string{"resource_requests.gpu"}, diagnosticPaths(l, pps.DiagnosticSeverity_DIAGNOSTIC_WARNING))
+}
+
+func TestLintAttestationSpec(t *testing.T) {
+	l := &pipelineLinter{}
+	l.lintAttestationSpec(&pps.CreatePipelineRequest{
+		Transform:       &pps.Transform{Env: map[string]string{"MODE": "fast"}},
+		EnableStats:     true,
+		AttestationSpec: &pps.AttestationSpec{Env: []string{"MODE"}},
+	})
+	require.Equal(t, 0, len(l.diagnostics))
+
+	l = &pipelineLinter{}
+	l.lintAttestationSpec(&pps.CreatePipelineRequest{
+		Transform:       &pps.Transform{Env: map[string]string{"MODE": "fast"}},
+		AttestationSpec: &pps.AttestationSpec{Env: []string{"MODE", "TOKEN"}},
+	})
+	require.Equal(t, []string{"attestation_spec", "attestation_spec.env[1]"},
+		diagnosticPaths(l, pps.DiagnosticSeverity_DIAGNOSTIC_WARNING))
+}
@@ -0,0 +1,232 @@
+package server
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsdb"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
+	workerpkg "github.com/pachyderm/pachyderm/src/server/worker"
+)
+
+// jobPruneInterval is how often the PPS master prunes the jobs of pipelines
+// with a job_retention policy
+const jobPruneInterval = 10 * time.Minute
+
+// commitExpiryInterval is how often the PPS master expires the commits of
+// repos with a commit_ttl
+const commitExpiryInterval = 10 * time.Minute
+
+var (
+	prunedJobs = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pachyderm",
+			Subsystem: "pps",
+			Name:      "pruned_jobs",
+			Help:      "Number of finished jobs deleted by their pipeline's job_retention policy, by pipeline",
+		},
+		[]string{"pipeline"},
+	)
+	registerPrunedJobsOnce sync.Once
+)
+
+func validateJobRetention(retention *pps.JobRetention) error {
+	if retention == nil {
+		return nil
+	}
+	if retention.KeepLast < 0 {
+		return fmt.Errorf("keep_last must be non-negative, not %d", retention.KeepLast)
+	}
+	if retention.MaxAge != nil {
+		maxAge, err := types.DurationFromProto(retention.MaxAge)
+		if err != nil {
+			return err
+		}
+		if maxAge <= 0 {
+			return fmt.Errorf("max_age must be positive, not %v", maxAge)
+		}
+	}
+	return nil
+}
+
+// jobsToPrune returns the jobs in 'jobs' that 'retention' doesn't keep as of
+// 'now'. Jobs that haven't finished, and the most recently finished job (which
+// the pipeline's next job builds on), are always kept.
+func jobsToPrune(retention *pps.JobRetention, jobs []*pps.EtcdJobInfo, now time.Time) []*pps.EtcdJobInfo {
+	if retention == nil {
+		return nil
+	}
+	var finished []*pps.EtcdJobInfo
+	for _, jobPtr := range jobs {
+		if ppsutil.IsTerminal(jobPtr.State) && jobPtr.Finished != nil {
+			finished = append(finished, jobPtr)
+		}
+	}
+	// newest first
+	sort.SliceStable(finished, func(i, j int) bool {
+		iFinished, _ := types.TimestampFromProto(finished[i].Finished)
+		jFinished, _ := types.TimestampFromProto(finished[j].Finished)
+		return iFinished.After(jFinished)
+	})
+	var maxAge time.Duration
+	if retention.MaxAge != nil {
+		maxAge, _ = types.DurationFromProto(retention.MaxAge)
+	}
+	var result []*pps.EtcdJobInfo
+	for i, jobPtr := range finished {
+		if i == 0 {
+			continue
+		}
+		if retention.KeepLast > 0 && int64(i) >= retention.KeepLast {
+			result = append(result, jobPtr)
+			continue
+		}
+		if maxAge > 0 {
+			if finishedAt, err := types.TimestampFromProto(jobPtr.Finished); err == nil && now.Sub(finishedAt) > maxAge {
+				result = append(result, jobPtr)
+			}
+		}
+	}
+	return result
+}
+
+// pruneJobs prunes the finished jobs of pipelines with a job_retention
+// policy every jobPruneInterval, until pachClient's context is cancelled
+// (i.e. this PPS server stops being the master)
+func (a *apiServer) pruneJobs(pachClient *client.APIClient) {
+	registerPrunedJobsOnce.Do(func() {
+		if err := prometheus.Register(prunedJobs); err != nil {
+			// metrics may be redundantly registered; ignore these errors
+			if _, ok := err.(prometheus.AlreadyRegisteredError); !ok {
+				log.Errorf("error registering prometheus metric: %v", err)
+			}
+		}
+	})
+	ticker := time.NewTicker(jobPruneInterval)
+	defer ticker.Stop()
+	for {
+		if err := a.sudo(pachClient, a.pruneAllJobs); err != nil {
+			log.Errorf("PPS master: error pruning jobs: %v", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-pachClient.Ctx().Done():
+			return
+		}
+	}
+}
+
+func (a *apiServer) pruneAllJobs(pachClient *client.APIClient) error {
+	var pipelines []string
+	pipelinePtr := &pps.EtcdPipelineInfo{}
+	if err := a.pipelines.ReadOnly(pachClient.Ctx()).List(pipelinePtr, col.DefaultOptions, func(pipelineName string) error {
+		pipelines = append(pipelines, pipelineName)
+		return nil
+	}); err != nil {
+		return err
+	}
+	for _, pipelineName := range pipelines {
+		if err := a.prunePipelineJobs(pachClient, pipelineName); err != nil {
+			// keep pruning the other pipelines
+			log.Errorf("PPS master: error pruning the jobs of pipeline %q: %v", pipelineName, err)
+		}
+	}
+	return nil
+}
+
+func (a *apiServer) prunePipelineJobs(pachClient *client.APIClient, pipelineName string) error {
+	ctx := pachClient.Ctx()
+	pipelinePtr := &pps.EtcdPipelineInfo{}
+	if err := a.pipelines.ReadOnly(ctx).Get(pipelineName, pipelinePtr); err != nil {
+		return err
+	}
+	pipelineInfo, err := ppsutil.GetPipelineInfo(pachClient, pipelinePtr)
+	if err != nil {
+		return err
+	}
+	if pipelineInfo.JobRetention == nil {
+		return nil
+	}
+	var jobs []*pps.EtcdJobInfo
+	jobPtr := &pps.EtcdJobInfo{}
+	if err := a.jobs.ReadOnly(ctx).GetByIndex(ppsdb.JobsPipelineIndex, pipelineInfo.Pipeline, jobPtr, col.DefaultOptions, func(string) error {
+		jobs = append(jobs, proto.Clone(jobPtr).(*pps.EtcdJobInfo))
+		return nil
+	}); err != nil {
+		return err
+	}
+	for _, jobPtr := range jobsToPrune(pipelineInfo.JobRetention, jobs, time.Now()) {
+		if err := a.pruneJob(pachClient, jobPtr); err != nil {
+			return fmt.Errorf("error pruning job %s: %v", jobPtr.Job.ID, err)
+		}
+		prunedJobs.WithLabelValues(pipelineName).Inc()
+	}
+	return nil
+}
+
+// pruneJob deletes a finished job's workers' state and the job itself from
+// etcd. The job's output and stats commits are kept, as PFS can't delete
+// commits with provenance.
+func (a *apiServer) pruneJob(pachClient *client.APIClient, jobPtr *pps.EtcdJobInfo) error {
+	_, err := col.NewSTM(pachClient.Ctx(), a.env.GetEtcdClient(), func(stm col.STM) error {
+		if err := workerpkg.DeleteJobState(stm, a.env.GetEtcdClient(), a.etcdPrefix, jobPtr.Job.ID); err != nil {
+			return err
+		}
+		if err := a.jobs.ReadWrite(stm).Delete(jobPtr.Job.ID); err != nil && !col.IsErrNotFound(err) {
+			return err
+		}
+		return nil
+	})
+	return err
+}
+
+// expireCommits expires the commits of repos with a commit_ttl every
+// commitExpiryInterval, until pachClient's context is cancelled (i.e. this PPS
+// server stops being the master). The PPS master does this, rather than PFS,
+// as it's the only process that runs once per cluster.
+func (a *apiServer) expireCommits(pachClient *client.APIClient) {
+	ticker := time.NewTicker(commitExpiryInterval)
+	defer ticker.Stop()
+	for {
+		if err := a.sudo(pachClient, expireAllCommits); err != nil {
+			log.Errorf("PPS master: error expiring commits: %v", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-pachClient.Ctx().Done():
+			return
+		}
+	}
+}
+
+func expireAllCommits(pachClient *client.APIClient) error {
+	repoInfos, err := pachClient.ListRepo()
+	if err != nil {
+		return err
+	}
+	for _, repoInfo := range repoInfos {
+		if repoInfo.CommitTTL == nil {
+			continue
+		}
+		commitInfos, err := pachClient.ExpireCommits(repoInfo.Repo.Name, false)
+		if err != nil {
+			// keep expiring the other repos' commits
+			log.Errorf("PPS master: error expiring the commits of repo %q: %v", repoInfo.Repo.Name, err)
+			continue
+		}
+		if len(commitInfos) > 0 {
+			log.Infof("PPS master: expired %d commits in repo %q", len(commitInfos), repoInfo.Repo.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestValidateJobRetention(t *testing.T) {
+	require.NoError(t, validateJobRetention(nil))
+	require.NoError(t, validateJobRetention(&pps.JobRetention{KeepLast: 10, MaxAge: types.DurationProto(time.Hour)}))
+	require.YesError(t, validateJobRetention(&pps.JobRetention{KeepLast: -1}))
+	require.YesError(t, validateJobRetention(&pps.JobRetention{MaxAge: types.DurationProto(0)}))
+}
+
+func TestJobsToPrune(t *testing.T) {
+	now := time.Now()
+	job := func(id string, state pps.JobState, age time.Duration) *pps.EtcdJobInfo {
+		jobPtr := &pps.EtcdJobInfo{Job: client.NewJob(id), State: state}
+		if state != pps.JobState_JOB_RUNNING {
+			jobPtr.Finished, _ = types.TimestampProto(now.Add(-age))
+		}
+		return jobPtr
+	}
+	jobs := []*pps.EtcdJobInfo{
+		job("c", pps.JobState_JOB_FAILURE, 3*time.Hour),
+		job("a", pps.JobState_JOB_SUCCESS, 5*time.Hour),
+		job("running", pps.JobState_JOB_RUNNING, 0),
+		job("d", pps.JobState_JOB_SUCCESS, 2*time.Hour),
+		job("b", pps.JobState_JOB_KILLED, 4*time.Hour),
+	}
+	ids := func(jobs []*pps.EtcdJobInfo) []string {
+		var result []string
+		for _, jobPtr := range jobs {
+			result = append(result, jobPtr.Job.ID)
+		}
+		return result
+	}
+
+	require.Equal(t, 0, len(jobsToPrune(nil, jobs, now)))
+	require.Equal(t, []string{"b", "a"}, ids(jobsToPrune(&pps.JobRetention{KeepLast: 2}, jobs, now)))
+	require.Equal(t, []string{"a"}, ids(jobsToPrune(&pps.JobRetention{MaxAge: types.DurationProto(270 * time.Minute)}, jobs, now)))
+	require.Equal(t, []string{"c", "b", "a"}, ids(jobsToPrune(&pps.JobRetention{
+		KeepLast: 3,
+		MaxAge:   types.DurationProto(150 * time.Minute),
+	}, jobs, now)))
+	// the most recently finished job is always kept
+	require.Equal(t, []string{"c", "b", "a"}, ids(jobsToPrune(&pps.JobRetention{MaxAge: types.DurationProto(time.Minute)}, jobs, now)))
+}
@@ -0,0 +1,171 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pachyderm/pachyderm/src/client/pps"
+
+	v1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	kube "k8s.io/client-go/kubernetes"
+)
+
+// userPortName is the name of the port of a service's internal_port
+const userPortName = "user-port"
+
+// userServiceName returns the name of the kubernetes service (and ingress)
+// of the service pipeline whose RC is 'rcName'
+func userServiceName(rcName string) string {
+	return rcName + "-user"
+}
+
+// validateService validates the ports and ingress of a pipeline's service
+func validateService(service *pps.Service) error {
+	if service.InternalPort == 0 && len(service.Ports) == 0 {
+		return fmt.Errorf("a service must have an internal_port or ports")
+	}
+	names := make(map[string]bool)
+	if service.InternalPort != 0 {
+		names[userPortName] = true
+	}
+	validPort := func(port int32) bool { return port > 0 && port < 1<<16 }
+	for _, port := range service.Ports {
+		switch {
+		case port.Name == "":
+			return fmt.Errorf("service ports must have a name")
+		case names[port.Name]:
+			return fmt.Errorf("service port name %q is used more than once", port.Name)
+		case !validPort(port.InternalPort):
+			return fmt.Errorf("service port %q has an invalid internal_port %d", port.Name, port.InternalPort)
+		case port.ExternalPort != 0 && !validPort(port.ExternalPort):
+			return fmt.Errorf("service port %q has an invalid external_port %d", port.Name, port.ExternalPort)
+		}
+		switch v1.Protocol(strings.ToUpper(port.Protocol)) {
+		case "", v1.ProtocolTCP, v1.ProtocolUDP:
+		default:
+			return fmt.Errorf("service port %q has an invalid protocol %q, must be TCP or UDP", port.Name, port.Protocol)
+		}
+		names[port.Name] = true
+	}
+	if ingress := service.Ingress; ingress != nil {
+		if ingress.Path != "" && !strings.HasPrefix(ingress.Path, "/") {
+			return fmt.Errorf("ingress path %q must start with \"/\"", ingress.Path)
+		}
+		if ingress.TLSSecret != "" && ingress.Host == "" {
+			return fmt.Errorf("an ingress with a tls_secret must have a host")
+		}
+		port := ingress.Port
+		if port == "" {
+			port = userPortName
+		}
+		if !names[port] {
+			return fmt.Errorf("the service has no port %q for its ingress to route to", port)
+		}
+	}
+	return nil
+}
+
+// userServicePorts returns the ports of the kubernetes service of 'service':
+// its internal_port and its ports. External ports are the service's ports,
+// and its node ports if it's a NodePort service.
+func userServicePorts(service *pps.Service) []v1.ServicePort {
+	nodePort := v1.ServiceType(service.Type) == v1.ServiceTypeNodePort
+	var result []v1.ServicePort
+	add := func(name string, internalPort, externalPort int32, protocol string) {
+		port := v1.ServicePort{
+			Name:       name,
+			Port:       externalPort,
+			TargetPort: intstr.FromInt(int(internalPort)),
+			Protocol:   v1.Protocol(strings.ToUpper(protocol)),
+		}
+		if port.Port == 0 {
+			port.Port = internalPort
+		} else if nodePort {
+			port.NodePort = externalPort
+		}
+		result = append(result, port)
+	}
+	if service.InternalPort != 0 {
+		add(userPortName, service.InternalPort, service.ExternalPort, "")
+	}
+	for _, port := range service.Ports {
+		add(port.Name, port.InternalPort, port.ExternalPort, port.Protocol)
+	}
+	return result
+}
+
+// userIngress returns the ingress of 'service', whose kubernetes service is
+// 'serviceName', or nil if it doesn't have one
+func userIngress(serviceName string, labels map[string]string, service *pps.Service) *extensions.Ingress {
+	ingress := service.Ingress
+	if ingress == nil {
+		return nil
+	}
+	path, port := ingress.Path, ingress.Port
+	if path == "" {
+		path = "/"
+	}
+	if port == "" {
+		port = userPortName
+	}
+	result := &extensions.Ingress{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Ingress",
+			APIVersion: "extensions/v1beta1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        serviceName,
+			Labels:      labels,
+			Annotations: ingress.Annotations,
+		},
+		Spec: extensions.IngressSpec{
+			Rules: []extensions.IngressRule{{
+				Host: ingress.Host,
+				IngressRuleValue: extensions.IngressRuleValue{
+					HTTP: &extensions.HTTPIngressRuleValue{
+						Paths: []extensions.HTTPIngressPath{{
+							Path: path,
+							Backend: extensions.IngressBackend{
+								ServiceName: serviceName,
+								ServicePort: intstr.FromString(port),
+							},
+						}},
+					},
+				},
+			}},
+		},
+	}
+	if ingress.TLSSecret != "" {
+		result.Spec.TLS = []extensions.IngressTLS{{
+			Hosts:      []string{ingress.Host},
+			SecretName: ingress.TLSSecret,
+		}}
+	}
+	return result
+}
+
+// serviceEndpoints returns the number of ready and not ready endpoints of the
+// kubernetes service 'name', i.e. of the pods behind it
+func (a *apiServer) serviceEndpoints(kubeClient *kube.Clientset, name string) (int64, int64, error) {
+	endpoints, err := kubeClient.CoreV1().Endpoints(a.namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		if isNotFoundErr(err) {
+			return 0, 0, nil
+		}
+		return 0, 0, err
+	}
+	// A pod is in more than one subset if its ports differ from other pods'
+	readyIPs, notReadyIPs := make(map[string]bool), make(map[string]bool)
+	for _, subset := range endpoints.Subsets {
+		for _, address := range subset.Addresses {
+			readyIPs[address.IP] = true
+		}
+		for _, address := range subset.NotReadyAddresses {
+			notReadyIPs[address.IP] = true
+		}
+	}
+	return int64(len(readyIPs)), int64(len(notReadyIPs)), nil
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestValidateService(t *testing.T) {
+	require.NoError(t, validateService(&pps.Service{InternalPort: 8080, ExternalPort: 30080}))
+	require.NoError(t, validateService(&pps.Service{
+		Ports: []*pps.ServicePort{
+			{Name: "http", InternalPort: 8080},
+			{Name: "dns", InternalPort: 53, ExternalPort: 30053, Protocol: "udp"},
+		},
+		Ingress: &pps.ServiceIngress{Host: "example.com", Path: "/app", Port: "http", TLSSecret: "cert"},
+	}))
+	require.NoError(t, validateService(&pps.Service{InternalPort: 8080, Ingress: &pps.ServiceIngress{}}))
+
+	require.YesError(t, validateService(&pps.Service{}))
+	require.YesError(t, validateService(&pps.Service{Ports: []*pps.ServicePort{{InternalPort: 80}}}))
+	require.YesError(t, validateService(&pps.Service{InternalPort: 8080, Ports: []*pps.ServicePort{{Name: "user-port", InternalPort: 80}}}))
+	require.YesError(t, validateService(&pps.Service{Ports: []*pps.ServicePort{{Name: "http", InternalPort: 70000}}}))
+	require.YesError(t, validateService(&pps.Service{Ports: []*pps.ServicePort{{Name: "http", InternalPort: 80, Protocol: "sctp"}}}))
+	// The ingress must route to one of the service's ports
+	require.YesError(t, validateService(&pps.Service{Ports: []*pps.ServicePort{{Name: "http", InternalPort: 80}}, Ingress: &pps.ServiceIngress{}}))
+	require.YesError(t, validateService(&pps.Service{InternalPort: 8080, Ingress: &pps.ServiceIngress{Path: "app"}}))
+	require.YesError(t, validateService(&pps.Service{InternalPort: 8080, Ingress: &pps.ServiceIngress{TLSSecret: "cert"}}))
+}
+
+func TestUserServicePorts(t *testing.T) {
+	ports := userServicePorts(&pps.Service{
+		Type:         string(v1.ServiceTypeNodePort),
+		InternalPort: 8080,
+		ExternalPort: 30080,
+		Ports: []*pps.ServicePort{
+			{Name: "metrics", InternalPort: 9090},
+			{Name: "dns", InternalPort: 53, ExternalPort: 30053, Protocol: "udp"},
+		},
+	})
+	require.Equal(t, []v1.ServicePort{
+		{Name: "user-port", Port: 30080, NodePort: 30080, TargetPort: intstr.FromInt(8080)},
+		{Name: "metrics", Port: 9090, TargetPort: intstr.FromInt(9090)},
+		{Name: "dns", Port: 30053, NodePort: 30053, TargetPort: intstr.FromInt(53), Protocol: v1.ProtocolUDP},
+	}, ports)
+
+	// Only NodePort services have node ports
+	ports = userServicePorts(&pps.Service{Type: string(v1.ServiceTypeClusterIP), InternalPort: 8080, ExternalPort: 80})
+	require.Equal(t, int32(80), ports[0].Port)
+	require.Equal(t, int32(0), ports[0].NodePort)
+}
+
+func TestUserIngress(t *testing.T) {
+	require.True(t, userIngress("pipeline-edges-v1-user", nil, &pps.Service{InternalPort: 8080}) == nil)
+
+	labels := map[string]string{"pipelineName": "edges"}
+	ingress := userIngress("pipeline-edges-v1-user", labels, &pps.Service{
+		InternalPort: 8080,
+		Ingress:      &pps.ServiceIngress{Annotations: map[string]string{"kubernetes.io/ingress.class": "nginx"}},
+	})
+	require.Equal(t, "pipeline-edges-v1-user", ingress.Name)
+	require.Equal(t, labels, ingress.Labels)
+	require.Equal(t, "nginx", ingress.Annotations["kubernetes.io/ingress.class"])
+	require.Equal(t, 1, len(ingress.Spec.Rules))
+	require.Equal(t, "", ingress.Spec.Rules[0].Host)
+	path := ingress.Spec.Rules[0].HTTP.Paths[0]
+	require.Equal(t, "/", path.Path)
+	require.Equal(t, "pipeline-edges-v1-user", path.Backend.ServiceName)
+	require.Equal(t, intstr.FromString("user-port"), path.Backend.ServicePort)
+	require.Equal(t, 0, len(ingress.Spec.TLS))
+
+	ingress = userIngress("pipeline-edges-v1-user", labels, &pps.Service{
+		Ports:   []*pps.ServicePort{{Name: "http", InternalPort: 80}},
+		Ingress: &pps.ServiceIngress{Host: "edges.example.com", Path: "/api", Port: "http", TLSSecret: "edges-cert"},
+	})
+	require.Equal(t, "edges.example.com", ingress.Spec.Rules[0].Host)
+	path = ingress.Spec.Rules[0].HTTP.Paths[0]
+	require.Equal(t, "/api", path.Path)
+	require.Equal(t, intstr.FromString("http"), path.Backend.ServicePort)
+	require.Equal(t, []string{"edges.example.com"}, ingress.Spec.TLS[0].Hosts)
+	require.Equal(t, "edges-cert", ingress.Spec.TLS[0].SecretName)
+}
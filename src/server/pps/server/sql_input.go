@@ -0,0 +1,236 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgtype"
+	"github.com/jackc/pgx/v4"
+	"github.com/robfig/cron"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	pfsServer "github.com/pachyderm/pachyderm/src/server/pfs"
+)
+
+const (
+	// sqlInputDataFile and sqlInputSchemaFile are the files that each commit
+	// to a SQL input's repo contains: the query's result, and its schema
+	sqlInputDataFile   = "data.csv"
+	sqlInputSchemaFile = "schema.json"
+	// sqlInputDefaultKey is the default key of a SQL input's secret
+	sqlInputDefaultKey = "url"
+)
+
+func validateSQLInput(in *pps.SQLInput) error {
+	switch {
+	case in.Name == "":
+		return fmt.Errorf("input must specify a name")
+	case in.Name == "out":
+		return fmt.Errorf("input cannot be named \"out\", as pachyderm " +
+			"already creates /pfs/out to collect job output")
+	case in.Query == "":
+		return fmt.Errorf("sql input must specify a query")
+	case in.Secret == "":
+		return fmt.Errorf("sql input must specify the secret that holds the database's URL")
+	}
+	if _, err := cron.ParseStandard(in.Spec); err != nil {
+		return fmt.Errorf("error parsing cron-spec: %v", err)
+	}
+	switch in.Format {
+	case "", "csv":
+	default:
+		return fmt.Errorf("unsupported sql input format %q (must be \"csv\")", in.Format)
+	}
+	return nil
+}
+
+// sqlSchema is the content of a SQL input's schema file
+type sqlSchema struct {
+	Query   string       `json:"query"`
+	Columns []*sqlColumn `json:"columns"`
+}
+
+type sqlColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// newSQLSchema returns the schema of the result of 'query', whose columns are
+// described by 'fields'. Each column's type is named by 'connInfo', or is its
+// OID (e.g. "oid:600") if it's not a type that connInfo knows.
+func newSQLSchema(query string, fields []pgproto3.FieldDescription, connInfo *pgtype.ConnInfo) *sqlSchema {
+	schema := &sqlSchema{Query: query, Columns: []*sqlColumn{}}
+	for _, f := range fields {
+		typ := fmt.Sprintf("oid:%d", f.DataTypeOID)
+		if dataType, ok := connInfo.DataTypeForOID(f.DataTypeOID); ok {
+			typ = dataType.Name
+		}
+		schema.Columns = append(schema.Columns, &sqlColumn{Name: string(f.Name), Type: typ})
+	}
+	return schema
+}
+
+// makeSQLCommits runs a single SQL input's query on its schedule, and commits
+// each result to the input's repo. It's a helper function called by
+// monitorPipeline.
+func (a *apiServer) makeSQLCommits(pachClient *client.APIClient, in *pps.Input) error {
+	schedule, err := cron.ParseStandard(in.SQL.Spec)
+	if err != nil {
+		return err // Shouldn't happen, as the input is validated in CreatePipeline
+	}
+	// make sure there isn't an unfinished commit on the branch, and if there
+	// is, delete it. The last finished commit is the time of the last query.
+	var latestTime time.Time
+	commitInfo, err := pachClient.InspectCommit(in.SQL.Repo, "master")
+	if err != nil && !pfsServer.IsNoHeadErr(err) {
+		return err
+	} else if commitInfo != nil && commitInfo.Finished == nil {
+		if err = pachClient.DeleteCommit(in.SQL.Repo, "master"); err != nil {
+			return err
+		}
+		commitInfo, err = pachClient.InspectCommit(in.SQL.Repo, "master")
+		if err != nil && !pfsServer.IsNoHeadErr(err) {
+			return err
+		}
+	}
+	if commitInfo != nil && commitInfo.Finished != nil {
+		if latestTime, err = types.TimestampFromProto(commitInfo.Finished); err != nil {
+			return err
+		}
+	}
+
+	for {
+		// the first query runs right away, and later ones on the schedule
+		if !latestTime.IsZero() {
+			select {
+			case <-time.After(time.Until(schedule.Next(latestTime))):
+			case <-pachClient.Ctx().Done():
+				return pachClient.Ctx().Err()
+			}
+		}
+		if err := a.makeSQLCommit(pachClient, in.SQL); err != nil {
+			return err
+		}
+		// missed ticks (e.g. while pachd was down) aren't made up, since they
+		// would all query the database as it is now
+		latestTime = time.Now()
+	}
+}
+
+// makeSQLCommit runs a SQL input's query once, and commits its result
+func (a *apiServer) makeSQLCommit(pachClient *client.APIClient, in *pps.SQLInput) (retErr error) {
+	url, err := a.sqlInputURL(in)
+	if err != nil {
+		return err
+	}
+	ctx := pachClient.Ctx()
+	conn, err := pgx.Connect(ctx, url)
+	if err != nil {
+		return fmt.Errorf("error connecting to the database of sql input %q: %v", in.Name, err)
+	}
+	defer conn.Close(ctx)
+	commit, err := pachClient.StartCommit(in.Repo, "master")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if retErr != nil {
+			pachClient.DeleteCommit(in.Repo, commit.ID)
+		}
+	}()
+
+	// the query runs in a read-only transaction, so that it can't modify the
+	// database
+	tx, err := conn.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	r, w := io.Pipe()
+	putErr := make(chan error, 1)
+	go func() {
+		_, err := pachClient.PutFileOverwrite(in.Repo, commit.ID, sqlInputDataFile, r, 0)
+		r.CloseWithError(err)
+		putErr <- err
+	}()
+	schema, queryErr := writeSQLResult(ctx, tx, in.Query, w)
+	w.CloseWithError(queryErr)
+	if err := <-putErr; err != nil && queryErr == nil {
+		return err
+	}
+	if queryErr != nil {
+		return fmt.Errorf("error running the query of sql input %q: %v", in.Name, queryErr)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	schemaJSON, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return err
+	}
+	if _, err := pachClient.PutFileOverwrite(in.Repo, commit.ID, sqlInputSchemaFile, bytes.NewReader(schemaJSON), 0); err != nil {
+		return err
+	}
+	return pachClient.FinishCommit(in.Repo, commit.ID)
+}
+
+// writeSQLResult runs 'query' in 'tx', and writes its result to 'w' as CSV
+// with a header row. It returns the result's schema.
+func writeSQLResult(ctx context.Context, tx pgx.Tx, query string, w io.Writer) (*sqlSchema, error) {
+	// every value is returned in Postgres' text format, which is what's
+	// written to the CSV file
+	rows, err := tx.Query(ctx, query, pgx.QueryResultFormats{pgx.TextFormatCode})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	fields := rows.FieldDescriptions()
+	schema := newSQLSchema(query, fields, tx.Conn().ConnInfo())
+	csvW := csv.NewWriter(w)
+	header := make([]string, len(fields))
+	for i, f := range fields {
+		header[i] = string(f.Name)
+	}
+	if err := csvW.Write(header); err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		// NULLs are written as empty values
+		values := rows.RawValues()
+		record := make([]string, len(values))
+		for i, value := range values {
+			record[i] = string(value)
+		}
+		if err := csvW.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	csvW.Flush()
+	return schema, csvW.Error()
+}
+
+// sqlInputURL reads the database URL of a SQL input from its secret
+func (a *apiServer) sqlInputURL(in *pps.SQLInput) (string, error) {
+	secret, err := a.env.GetKubeClient().CoreV1().Secrets(a.namespace).Get(in.Secret, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("error reading secret %q of sql input %q: %v", in.Secret, in.Name, err)
+	}
+	url, ok := secret.Data[in.Key]
+	if !ok {
+		return "", fmt.Errorf("secret %q of sql input %q has no key %q", in.Secret, in.Name, in.Key)
+	}
+	return string(url), nil
+}
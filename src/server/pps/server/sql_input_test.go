@@ -0,0 +1,52 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgtype"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestValidateSQLInput(t *testing.T) {
+	valid := func() *pps.SQLInput {
+		return &pps.SQLInput{Name: "users", Spec: "@every 1h", Query: "SELECT * FROM users", Secret: "db"}
+	}
+	require.NoError(t, validateSQLInput(valid()))
+	in := valid()
+	in.Format = "csv"
+	require.NoError(t, validateSQLInput(in))
+
+	for _, f := range []func(*pps.SQLInput){
+		func(in *pps.SQLInput) { in.Name = "" },
+		func(in *pps.SQLInput) { in.Name = "out" },
+		func(in *pps.SQLInput) { in.Query = "" },
+		func(in *pps.SQLInput) { in.Secret = "" },
+		func(in *pps.SQLInput) { in.Spec = "not a schedule" },
+		func(in *pps.SQLInput) { in.Format = "parquet" },
+		func(in *pps.SQLInput) { in.Format = "xml" },
+	} {
+		in := valid()
+		f(in)
+		require.YesError(t, validateSQLInput(in))
+	}
+}
+
+func TestSQLSchema(t *testing.T) {
+	// 16384 is the OID of a type that isn't built in, e.g. an enum
+	schema := newSQLSchema("SELECT id, mood FROM users", []pgproto3.FieldDescription{
+		{Name: []byte("id"), DataTypeOID: pgtype.Int8OID},
+		{Name: []byte("mood"), DataTypeOID: 16384},
+	}, pgtype.NewConnInfo())
+	schemaJSON, err := json.Marshal(schema)
+	require.NoError(t, err)
+	require.Equal(t, `{"query":"SELECT id, mood FROM users","columns":[{"name":"id","type":"int8"},{"name":"mood","type":"oid:16384"}]}`, string(schemaJSON))
+
+	// a result with no columns still has a list of columns
+	schemaJSON, err = json.Marshal(newSQLSchema("SELECT", nil, pgtype.NewConnInfo()))
+	require.NoError(t, err)
+	require.Equal(t, `{"query":"SELECT","columns":[]}`, string(schemaJSON))
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 
 	jsonpatch "github.com/evanphx/json-patch"
@@ -23,7 +24,6 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/intstr"
 	kube "k8s.io/client-go/kubernetes"
 )
 
@@ -32,6 +32,9 @@ const (
 	pachVersionAnnotation     = "version"
 	specCommitAnnotation      = "specCommit"
 	hashedAuthTokenAnnotation = "authTokenHash"
+	// mergeWorkersLabel marks the RC (and pods) of a pipeline's merge workers,
+	// which don't count as the pipeline's RC
+	mergeWorkersLabel = "mergeWorkers"
 )
 
 // Parameters used when creating the kubernetes replication controller in charge
@@ -52,6 +55,19 @@ type workerOptions struct {
 	schedulingSpec   *pps.SchedulingSpec // the SchedulingSpec for the pipeline
 	podSpec          string
 	podPatch         string
+	initContainers   []*pps.InitContainer // User containers run before the worker starts
+	// If separateContainer is set, the user code runs in its own container,
+	// rather than in the worker's
+	separateContainer bool
+	// The user (transform.user) that the user code container runs the user
+	// code as, if separateContainer is set
+	userCodeUser string
+	// If mountInputs is set, the user container can mount PFS with FUSE, for
+	// the pipeline's mounted inputs (see PFSInput.mount)
+	mountInputs bool
+	// k8s labels attached to the RC and workers in addition to 'labels'. They
+	// aren't part of the RC's selector.
+	userLabels map[string]string
 
 	// Secrets that we mount in the worker container (e.g. for reading/writing to
 	// s3)
@@ -178,6 +194,7 @@ func (a *apiServer) workerPodSpec(options *workerOptions) (v1.PodSpec, error) {
 				Image:           workerImage,
 				Command:         []string{"/pach/worker.sh"},
 				ImagePullPolicy: v1.PullPolicy(pullPolicy),
+				Env:             []v1.EnvVar{podNameEnv()},
 				VolumeMounts:    options.volumeMounts,
 			},
 		},
@@ -217,23 +234,63 @@ func (a *apiServer) workerPodSpec(options *workerOptions) (v1.PodSpec, error) {
 		TerminationGracePeriodSeconds: &zeroVal,
 		SecurityContext:               securityContext,
 	}
+	podSpec.InitContainers = append(podSpec.InitContainers,
+		userInitContainers(options.initContainers, options.volumeMounts, v1.PullPolicy(pullPolicy))...)
 	if options.schedulingSpec != nil {
 		podSpec.NodeSelector = options.schedulingSpec.NodeSelector
 		podSpec.PriorityClassName = options.schedulingSpec.PriorityClassName
+		podSpec.Tolerations = tolerations(options.schedulingSpec)
+		podSpec.Affinity = nodeAffinity(options.schedulingSpec)
 	}
-	resourceRequirements := v1.ResourceRequirements{
-		Requests: map[v1.ResourceName]resource.Quantity{
-			v1.ResourceCPU:    cpuZeroQuantity,
-			v1.ResourceMemory: memZeroQuantity,
-		},
-	}
-	if options.resourceRequests != nil {
-		resourceRequirements.Requests = *options.resourceRequests
+	resourceRequirements := userCodeResources(options)
+	podSpec.Containers[0].Resources = resourceRequirements
+	if options.mountInputs {
+		podSpec.Volumes = append(podSpec.Volumes, v1.Volume{
+			Name: "fuse",
+			VolumeSource: v1.VolumeSource{
+				HostPath: &v1.HostPathVolumeSource{
+					Path: "/dev/fuse",
+				},
+			},
+		})
+		podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, v1.VolumeMount{
+			Name:      "fuse",
+			MountPath: "/dev/fuse",
+		})
+		// Mounting requires CAP_SYS_ADMIN
+		podSpec.Containers[0].SecurityContext = &v1.SecurityContext{
+			Capabilities: &v1.Capabilities{
+				Add: []v1.Capability{"SYS_ADMIN"},
+			},
+		}
 	}
-	if options.resourceLimits != nil {
-		resourceRequirements.Limits = *options.resourceLimits
+	if options.separateContainer {
+		// The worker runs from the worker image, and the user code runs
+		// in its own container, which gets the pipeline's resources
+		podSpec.Containers[0].Image = workerImage
+		podSpec.Containers[0].Command = []string{"/pach/worker"}
+		podSpec.Containers[0].Resources = v1.ResourceRequirements{
+			Requests: map[v1.ResourceName]resource.Quantity{
+				v1.ResourceCPU:    cpuZeroQuantity,
+				v1.ResourceMemory: memZeroQuantity,
+			},
+		}
+		// The user code container runs the user code as transform.user itself,
+		// so the worker can't ask it to run anything as a different user
+		userCodeCommand := []string{"/pach-bin/worker", "--mode", "user-code"}
+		if options.userCodeUser != "" {
+			userCodeCommand = append(userCodeCommand, "--user", options.userCodeUser)
+		}
+		podSpec.Containers = append(podSpec.Containers, v1.Container{
+			Name:            client.PPSWorkerUserCodeContainerName,
+			Image:           options.userImage,
+			Command:         userCodeCommand,
+			ImagePullPolicy: v1.PullPolicy(pullPolicy),
+			Env:             []v1.EnvVar{podNameEnv()},
+			Resources:       resourceRequirements,
+			VolumeMounts:    userVolumeMounts,
+		})
 	}
-	podSpec.Containers[0].Resources = resourceRequirements
 	if options.podSpec != "" || options.podPatch != "" {
 		jsonPodSpec, err := json.Marshal(&podSpec)
 		if err != nil {
@@ -267,6 +324,130 @@ func (a *apiServer) workerPodSpec(options *workerOptions) (v1.PodSpec, error) {
 	return podSpec, nil
 }
 
+// podNameEnv returns the env var that holds the pod's name. Every container
+// that mounts /pfs has it, as a scratch volume's mount may use it (see
+// scratchVolume).
+func podNameEnv() v1.EnvVar {
+	return v1.EnvVar{
+		Name: client.PPSPodNameEnv,
+		ValueFrom: &v1.EnvVarSource{
+			FieldRef: &v1.ObjectFieldSelector{
+				APIVersion: "v1",
+				FieldPath:  "metadata.name",
+			},
+		},
+	}
+}
+
+// scratchVolume returns the volume that's mounted at /pfs (see
+// pps.ScratchVolume), and its mount
+func scratchVolume(spec *pps.ScratchVolume) (v1.Volume, v1.VolumeMount, error) {
+	volume := v1.Volume{Name: client.PPSWorkerVolume}
+	mount := v1.VolumeMount{
+		Name:      client.PPSWorkerVolume,
+		MountPath: client.PPSInputPrefix,
+	}
+	if spec == nil {
+		spec = &pps.ScratchVolume{}
+	}
+	if spec.ClaimName != "" {
+		volume.PersistentVolumeClaim = &v1.PersistentVolumeClaimVolumeSource{ClaimName: spec.ClaimName}
+		// Workers may share the claim, so each one uses its own directory
+		mount.SubPathExpr = fmt.Sprintf("$(%s)", client.PPSPodNameEnv)
+		return volume, mount, nil
+	}
+	emptyDir := &v1.EmptyDirVolumeSource{Medium: v1.StorageMedium(spec.Medium)}
+	if spec.Capacity != "" {
+		capacity, err := resource.ParseQuantity(spec.Capacity)
+		if err != nil {
+			return v1.Volume{}, v1.VolumeMount{}, fmt.Errorf("could not parse scratch volume capacity: %v", err)
+		}
+		emptyDir.SizeLimit = &capacity
+	}
+	volume.EmptyDir = emptyDir
+	return volume, mount, nil
+}
+
+// userInitContainers returns the k8s containers for a transform's
+// init_containers. They have the same volumes as Pachyderm's own init
+// container, so they can write to /pfs and read the transform's secrets.
+func userInitContainers(initContainers []*pps.InitContainer, volumeMounts []v1.VolumeMount, pullPolicy v1.PullPolicy) []v1.Container {
+	var result []v1.Container
+	for _, c := range initContainers {
+		var env []v1.EnvVar
+		for name, value := range c.Env {
+			env = append(env, v1.EnvVar{Name: name, Value: value})
+		}
+		// Sort the env so that the pod spec doesn't change between calls
+		sort.Slice(env, func(i, j int) bool { return env[i].Name < env[j].Name })
+		env = append(env, podNameEnv())
+		result = append(result, v1.Container{
+			Name:            c.Name,
+			Image:           c.Image,
+			Command:         c.Cmd,
+			Env:             env,
+			ImagePullPolicy: pullPolicy,
+			VolumeMounts:    volumeMounts,
+		})
+	}
+	return result
+}
+
+// tolerations converts the tolerations in 'spec' to k8s tolerations
+func tolerations(spec *pps.SchedulingSpec) []v1.Toleration {
+	var result []v1.Toleration
+	for _, t := range spec.Tolerations {
+		toleration := v1.Toleration{
+			Key:      t.Key,
+			Operator: v1.TolerationOperator(t.Operator),
+			Value:    t.Value,
+			Effect:   v1.TaintEffect(t.Effect),
+		}
+		if t.TolerationSeconds > 0 {
+			seconds := t.TolerationSeconds
+			toleration.TolerationSeconds = &seconds
+		}
+		result = append(result, toleration)
+	}
+	return result
+}
+
+// nodeAffinity converts the node affinity requirements in 'spec' to a k8s
+// affinity, it returns nil if 'spec' doesn't have any
+func nodeAffinity(spec *pps.SchedulingSpec) *v1.Affinity {
+	if len(spec.RequiredNodeAffinity) == 0 && len(spec.PreferredNodeAffinity) == 0 {
+		return nil
+	}
+	requirements := func(rs []*pps.NodeSelectorRequirement) []v1.NodeSelectorRequirement {
+		var result []v1.NodeSelectorRequirement
+		for _, r := range rs {
+			result = append(result, v1.NodeSelectorRequirement{
+				Key:      r.Key,
+				Operator: v1.NodeSelectorOperator(r.Operator),
+				Values:   r.Values,
+			})
+		}
+		return result
+	}
+	affinity := &v1.NodeAffinity{}
+	if len(spec.RequiredNodeAffinity) > 0 {
+		affinity.RequiredDuringSchedulingIgnoredDuringExecution = &v1.NodeSelector{
+			NodeSelectorTerms: []v1.NodeSelectorTerm{{
+				MatchExpressions: requirements(spec.RequiredNodeAffinity),
+			}},
+		}
+	}
+	if len(spec.PreferredNodeAffinity) > 0 {
+		affinity.PreferredDuringSchedulingIgnoredDuringExecution = []v1.PreferredSchedulingTerm{{
+			Weight: 100,
+			Preference: v1.NodeSelectorTerm{
+				MatchExpressions: requirements(spec.PreferredNodeAffinity),
+			},
+		}}
+	}
+	return &v1.Affinity{NodeAffinity: affinity}
+}
+
 // We don't want to expose pipeline auth tokens, so we hash it. This will be
 // visible to any user with k8s cluster access
 // Note: This hash shouldn't be used for authentication in any way. We just use
@@ -329,15 +510,7 @@ func (a *apiServer) getWorkerOptions(ptr *pps.EtcdPipelineInfo, pipelineInfo *pp
 			},
 		},
 	})
-	workerEnv = append(workerEnv, v1.EnvVar{
-		Name: client.PPSPodNameEnv,
-		ValueFrom: &v1.EnvVarSource{
-			FieldRef: &v1.ObjectFieldSelector{
-				APIVersion: "v1",
-				FieldPath:  "metadata.name",
-			},
-		},
-	})
+	workerEnv = append(workerEnv, podNameEnv())
 	// Set the etcd prefix env
 	workerEnv = append(workerEnv, v1.EnvVar{
 		Name:  client.PPSEtcdPrefixEnv,
@@ -369,6 +542,18 @@ func (a *apiServer) getWorkerOptions(ptr *pps.EtcdPipelineInfo, pipelineInfo *pp
 		Name:  client.PeerPortEnv,
 		Value: strconv.FormatUint(uint64(a.peerPort), 10),
 	})
+	if a.env.WorkerLogSink != "" {
+		workerEnv = append(workerEnv, v1.EnvVar{
+			Name:  client.WorkerLogSinkEnv,
+			Value: a.env.WorkerLogSink,
+		})
+	}
+	if a.env.WorkerTreeCacheSize != "" {
+		workerEnv = append(workerEnv, v1.EnvVar{
+			Name:  client.WorkerHashtreeCacheSizeEnv,
+			Value: a.env.WorkerTreeCacheSize,
+		})
+	}
 
 	var volumes []v1.Volume
 	var volumeMounts []v1.VolumeMount
@@ -402,6 +587,22 @@ func (a *apiServer) getWorkerOptions(ptr *pps.EtcdPipelineInfo, pipelineInfo *pp
 		}
 	}
 
+	// The cache is an emptyDir, so it lasts as long as the worker's pod, and a
+	// new version of the pipeline (which gets new pods) starts with an empty
+	// cache
+	if pipelineInfo.Cache != nil {
+		volumes = append(volumes, v1.Volume{
+			Name: "pach-cache",
+			VolumeSource: v1.VolumeSource{
+				EmptyDir: &v1.EmptyDirVolumeSource{},
+			},
+		})
+		volumeMounts = append(volumeMounts, v1.VolumeMount{
+			Name:      "pach-cache",
+			MountPath: pipelineInfo.Cache.Path,
+		})
+	}
+
 	volumes = append(volumes, v1.Volume{
 		Name: "pach-bin",
 		VolumeSource: v1.VolumeSource{
@@ -413,16 +614,12 @@ func (a *apiServer) getWorkerOptions(ptr *pps.EtcdPipelineInfo, pipelineInfo *pp
 		MountPath: "/pach-bin",
 	})
 
-	volumes = append(volumes, v1.Volume{
-		Name: client.PPSWorkerVolume,
-		VolumeSource: v1.VolumeSource{
-			EmptyDir: &v1.EmptyDirVolumeSource{},
-		},
-	})
-	volumeMounts = append(volumeMounts, v1.VolumeMount{
-		Name:      client.PPSWorkerVolume,
-		MountPath: client.PPSInputPrefix,
-	})
+	workerVolume, workerVolumeMount, err := scratchVolume(pipelineInfo.ScratchVolume)
+	if err != nil {
+		return nil, err
+	}
+	volumes = append(volumes, workerVolume)
+	volumeMounts = append(volumeMounts, workerVolumeMount)
 	var imagePullSecrets []v1.LocalObjectReference
 	for _, secret := range transform.ImagePullSecrets {
 		imagePullSecrets = append(imagePullSecrets, v1.LocalObjectReference{Name: secret})
@@ -457,28 +654,70 @@ func (a *apiServer) getWorkerOptions(ptr *pps.EtcdPipelineInfo, pipelineInfo *pp
 			}
 		}
 	}
+	var userLabels map[string]string
+	if pipelineInfo.Metadata != nil {
+		// Pachyderm's own annotations take precedence
+		for k, v := range pipelineInfo.Metadata.Annotations {
+			if _, ok := annotations[k]; !ok {
+				annotations[k] = v
+			}
+		}
+		userLabels = pipelineInfo.Metadata.Labels
+	}
 
 	// Generate options for new RC
 	return &workerOptions{
-		rcName:           rcName,
-		labels:           labels,
-		annotations:      annotations,
-		parallelism:      int32(0), // pipelines start w/ 0 workers & are scaled up
-		resourceRequests: resourceRequests,
-		resourceLimits:   resourceLimits,
-		userImage:        userImage,
-		workerEnv:        workerEnv,
-		volumes:          volumes,
-		volumeMounts:     volumeMounts,
-		imagePullSecrets: imagePullSecrets,
-		cacheSize:        pipelineInfo.CacheSize,
-		service:          service,
-		schedulingSpec:   pipelineInfo.SchedulingSpec,
-		podSpec:          pipelineInfo.PodSpec,
-		podPatch:         pipelineInfo.PodPatch,
+		rcName:            rcName,
+		labels:            labels,
+		annotations:       annotations,
+		parallelism:       int32(0), // pipelines start w/ 0 workers & are scaled up
+		resourceRequests:  resourceRequests,
+		resourceLimits:    resourceLimits,
+		userImage:         userImage,
+		workerEnv:         workerEnv,
+		volumes:           volumes,
+		volumeMounts:      volumeMounts,
+		imagePullSecrets:  imagePullSecrets,
+		cacheSize:         pipelineInfo.CacheSize,
+		service:           service,
+		schedulingSpec:    pipelineInfo.SchedulingSpec,
+		podSpec:           pipelineInfo.PodSpec,
+		podPatch:          pipelineInfo.PodPatch,
+		initContainers:    transform.InitContainers,
+		separateContainer: transform.SeparateContainer,
+		userCodeUser:      transform.User,
+		mountInputs:       ppsutil.HasMountedInputs(pipelineInfo.Input),
+		userLabels:        userLabels,
 	}, nil
 }
 
+// userCodeResources returns the resources of the container that runs the user
+// code in the workers with the given options
+func userCodeResources(options *workerOptions) v1.ResourceRequirements {
+	resourceRequirements := v1.ResourceRequirements{
+		Requests: map[v1.ResourceName]resource.Quantity{
+			v1.ResourceCPU:    resource.MustParse("0"),
+			v1.ResourceMemory: resource.MustParse("0M"),
+		},
+	}
+	if options.resourceRequests != nil {
+		resourceRequirements.Requests = *options.resourceRequests
+	}
+	if options.resourceLimits != nil {
+		resourceRequirements.Limits = *options.resourceLimits
+	}
+	return resourceRequirements
+}
+
+// userCodeContainerName returns the name of the container that runs the user
+// code in the workers with the given options
+func userCodeContainerName(options *workerOptions) string {
+	if options.separateContainer {
+		return client.PPSWorkerUserCodeContainerName
+	}
+	return client.PPSWorkerUserContainerName
+}
+
 // noValidOptions error may be returned by createWorkerSvcAndRc to indicate that
 // getWorkerOptions returned an error to it (getWorkerOptions does not return
 // noValidOptions). This is a mechanism for createWorkerSvcAndRc to signal to
@@ -500,35 +739,15 @@ func (a *apiServer) createWorkerSvcAndRc(ctx context.Context, ptr *pps.EtcdPipel
 	if err != nil {
 		return noValidOptionsErr{err}
 	}
-	podSpec, err := a.workerPodSpec(options)
-	if err != nil {
+	if err := a.createWorkerRc(options); err != nil {
 		return err
 	}
-	rc := &v1.ReplicationController{
-		TypeMeta: metav1.TypeMeta{
-			Kind:       "ReplicationController",
-			APIVersion: "v1",
-		},
-		ObjectMeta: metav1.ObjectMeta{
-			Name:        options.rcName,
-			Labels:      options.labels,
-			Annotations: options.annotations,
-		},
-		Spec: v1.ReplicationControllerSpec{
-			Selector: options.labels,
-			Replicas: &options.parallelism,
-			Template: &v1.PodTemplateSpec{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:        options.rcName,
-					Labels:      options.labels,
-					Annotations: options.annotations,
-				},
-				Spec: podSpec,
-			},
-		},
-	}
-	if _, err := a.env.GetKubeClient().CoreV1().ReplicationControllers(a.namespace).Create(rc); err != nil {
-		if !isAlreadyExistsErr(err) {
+	if pipelineInfo.MergeSpec != nil {
+		mergeOptions, err := mergeWorkerOptions(options, pipelineInfo)
+		if err != nil {
+			return noValidOptionsErr{err}
+		}
+		if err := a.createWorkerRc(mergeOptions); err != nil {
 			return err
 		}
 	}
@@ -568,31 +787,20 @@ func (a *apiServer) createWorkerSvcAndRc(ctx context.Context, ptr *pps.EtcdPipel
 	}
 
 	if options.service != nil {
-		var servicePort = []v1.ServicePort{
-			{
-				Port:       options.service.ExternalPort,
-				TargetPort: intstr.FromInt(int(options.service.InternalPort)),
-				Name:       "user-port",
-			},
-		}
-		var serviceType = v1.ServiceType(options.service.Type)
-		if serviceType == v1.ServiceTypeNodePort {
-			servicePort[0].NodePort = options.service.ExternalPort
-		}
 		service := &v1.Service{
 			TypeMeta: metav1.TypeMeta{
 				Kind:       "Service",
 				APIVersion: "v1",
 			},
 			ObjectMeta: metav1.ObjectMeta{
-				Name:        options.rcName + "-user",
+				Name:        userServiceName(options.rcName),
 				Labels:      options.labels,
 				Annotations: options.annotations,
 			},
 			Spec: v1.ServiceSpec{
 				Selector: options.labels,
-				Type:     serviceType,
-				Ports:    servicePort,
+				Type:     v1.ServiceType(options.service.Type),
+				Ports:    userServicePorts(options.service),
 			},
 		}
 		if _, err := a.env.GetKubeClient().CoreV1().Services(a.namespace).Create(service); err != nil {
@@ -600,6 +808,13 @@ func (a *apiServer) createWorkerSvcAndRc(ctx context.Context, ptr *pps.EtcdPipel
 				return err
 			}
 		}
+		if ingress := userIngress(service.Name, options.labels, options.service); ingress != nil {
+			if _, err := a.env.GetKubeClient().ExtensionsV1beta1().Ingresses(a.namespace).Create(ingress); err != nil {
+				if !isAlreadyExistsErr(err) {
+					return err
+				}
+			}
+		}
 	}
 
 	// True if the pipeline has a git input
@@ -617,6 +832,83 @@ func (a *apiServer) createWorkerSvcAndRc(ctx context.Context, ptr *pps.EtcdPipel
 	return nil
 }
 
+// createWorkerRc creates the RC of the workers described by 'options', if it
+// doesn't exist yet
+func (a *apiServer) createWorkerRc(options *workerOptions) error {
+	podSpec, err := a.workerPodSpec(options)
+	if err != nil {
+		return err
+	}
+	podLabels := make(map[string]string)
+	for k, v := range options.userLabels {
+		podLabels[k] = v
+	}
+	for k, v := range options.labels {
+		podLabels[k] = v
+	}
+	rc := &v1.ReplicationController{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ReplicationController",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        options.rcName,
+			Labels:      podLabels,
+			Annotations: options.annotations,
+		},
+		Spec: v1.ReplicationControllerSpec{
+			Selector: options.labels,
+			Replicas: &options.parallelism,
+			Template: &v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        options.rcName,
+					Labels:      podLabels,
+					Annotations: options.annotations,
+				},
+				Spec: podSpec,
+			},
+		},
+	}
+	if _, err := a.env.GetKubeClient().CoreV1().ReplicationControllers(a.namespace).Create(rc); err != nil {
+		if !isAlreadyExistsErr(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeRcName returns the name of the RC of the merge workers of the pipeline
+// whose RC is named 'rcName'
+func mergeRcName(rcName string) string {
+	return rcName + "-merge"
+}
+
+// mergeWorkerOptions returns the options of the RC of a pipeline's merge
+// workers (see MergeSpec), given 'options', the options of its RC. Merge
+// workers run in the same pods as the pipeline's other workers, with their
+// own resources, and they're found with their own labels: they must not be
+// selected by the pipeline's RC or services.
+func mergeWorkerOptions(options *workerOptions, pipelineInfo *pps.PipelineInfo) (*workerOptions, error) {
+	resourceRequests, resourceLimits, err := ppsutil.GetMergeResourceLists(pipelineInfo)
+	if err != nil {
+		return nil, fmt.Errorf("could not determine merge worker resources: %v", err)
+	}
+	result := *options
+	result.rcName = mergeRcName(options.rcName)
+	result.labels = labels(result.rcName)
+	result.labels[pipelineNameLabel] = pipelineInfo.Pipeline.Name
+	result.labels[mergeWorkersLabel] = "true"
+	result.resourceRequests = resourceRequests
+	result.resourceLimits = resourceLimits
+	result.workerEnv = append(append([]v1.EnvVar{}, options.workerEnv...), v1.EnvVar{
+		Name:  client.PPSMergeWorkerEnv,
+		Value: "true",
+	})
+	result.service = nil
+	result.mountInputs = false // merge workers don't read inputs
+	return &result, nil
+}
+
 func (a *apiServer) checkOrDeployGithookService() error {
 	kubeClient := a.env.GetKubeClient()
 	_, err := getGithookService(kubeClient, a.namespace)
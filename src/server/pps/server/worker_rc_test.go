@@ -0,0 +1,123 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestTolerations(t *testing.T) {
+	result := tolerations(&pps.SchedulingSpec{
+		Tolerations: []*pps.Toleration{
+			{Key: "gpu", Operator: "Exists", Effect: "NoSchedule"},
+			{Key: "spot", Value: "true", Effect: "NoExecute", TolerationSeconds: 30},
+		},
+	})
+	require.Equal(t, 2, len(result))
+	require.Equal(t, v1.TolerationOpExists, result[0].Operator)
+	require.Equal(t, v1.TaintEffectNoSchedule, result[0].Effect)
+	require.Nil(t, result[0].TolerationSeconds)
+	require.Equal(t, "true", result[1].Value)
+	require.Equal(t, int64(30), *result[1].TolerationSeconds)
+}
+
+func TestNodeAffinity(t *testing.T) {
+	require.Nil(t, nodeAffinity(&pps.SchedulingSpec{}))
+	affinity := nodeAffinity(&pps.SchedulingSpec{
+		RequiredNodeAffinity: []*pps.NodeSelectorRequirement{
+			{Key: "kubernetes.io/arch", Operator: "In", Values: []string{"arm64"}},
+		},
+	})
+	require.NotNil(t, affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution)
+	require.Equal(t, 0, len(affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution))
+	terms := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	require.Equal(t, 1, len(terms))
+	require.Equal(t, []v1.NodeSelectorRequirement{{
+		Key:      "kubernetes.io/arch",
+		Operator: v1.NodeSelectorOpIn,
+		Values:   []string{"arm64"},
+	}}, terms[0].MatchExpressions)
+
+	affinity = nodeAffinity(&pps.SchedulingSpec{
+		PreferredNodeAffinity: []*pps.NodeSelectorRequirement{
+			{Key: "spot", Operator: "Exists"},
+		},
+	})
+	require.Nil(t, affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution)
+	require.Equal(t, 1, len(affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution))
+}
+
+func TestUserInitContainers(t *testing.T) {
+	mounts := []v1.VolumeMount{{Name: "pfs", MountPath: "/pfs"}}
+	containers := userInitContainers([]*pps.InitContainer{{
+		Name:  "fetch",
+		Image: "alpine",
+		Cmd:   []string{"sh", "-c", "echo hi"},
+		Env:   map[string]string{"B": "2", "A": "1"},
+	}}, mounts, v1.PullIfNotPresent)
+	require.Equal(t, 1, len(containers))
+	require.Equal(t, "fetch", containers[0].Name)
+	require.Equal(t, "alpine", containers[0].Image)
+	require.Equal(t, []string{"sh", "-c", "echo hi"}, containers[0].Command)
+	require.Equal(t, []v1.EnvVar{{Name: "A", Value: "1"}, {Name: "B", Value: "2"}, podNameEnv()}, containers[0].Env)
+	require.Equal(t, mounts, containers[0].VolumeMounts)
+}
+
+func TestScratchVolume(t *testing.T) {
+	volume, mount, err := scratchVolume(nil)
+	require.NoError(t, err)
+	require.Equal(t, client.PPSWorkerVolume, volume.Name)
+	require.Equal(t, &v1.EmptyDirVolumeSource{}, volume.EmptyDir)
+	require.Equal(t, v1.VolumeMount{Name: client.PPSWorkerVolume, MountPath: client.PPSInputPrefix}, mount)
+
+	volume, _, err = scratchVolume(&pps.ScratchVolume{Capacity: "10G", Medium: "Memory"})
+	require.NoError(t, err)
+	require.Equal(t, v1.StorageMediumMemory, volume.EmptyDir.Medium)
+	require.Equal(t, int64(10*1000*1000*1000), volume.EmptyDir.SizeLimit.Value())
+
+	volume, mount, err = scratchVolume(&pps.ScratchVolume{ClaimName: "fast-disk", Capacity: "10G"})
+	require.NoError(t, err)
+	require.Nil(t, volume.EmptyDir)
+	require.Equal(t, "fast-disk", volume.PersistentVolumeClaim.ClaimName)
+	require.Equal(t, "$("+client.PPSPodNameEnv+")", mount.SubPathExpr)
+
+	require.YesError(t, validateScratchVolume(&pps.ScratchVolume{Capacity: "lots"}))
+	require.YesError(t, validateScratchVolume(&pps.ScratchVolume{Capacity: "-1G"}))
+	require.YesError(t, validateScratchVolume(&pps.ScratchVolume{Medium: "SSD"}))
+	require.YesError(t, validateScratchVolume(&pps.ScratchVolume{ClaimName: "fast-disk", Medium: "Memory"}))
+	require.NoError(t, validateScratchVolume(&pps.ScratchVolume{ClaimName: "fast-disk", Capacity: "100Gi"}))
+}
+
+func TestMergeWorkerOptions(t *testing.T) {
+	options := &workerOptions{
+		rcName:    "pipeline-edges-v1",
+		labels:    map[string]string{"app": "pipeline-edges-v1", pipelineNameLabel: "edges"},
+		workerEnv: []v1.EnvVar{{Name: "A", Value: "1"}},
+		service:   &pps.Service{InternalPort: 8000},
+	}
+	pipelineInfo := &pps.PipelineInfo{
+		Pipeline:         client.NewPipeline("edges"),
+		ResourceRequests: &pps.ResourceSpec{Memory: "1G", Cpu: 1},
+		MergeSpec: &pps.MergeSpec{
+			Workers:          2,
+			ResourceRequests: &pps.ResourceSpec{Memory: "8G", Cpu: 2},
+		},
+	}
+	merge, err := mergeWorkerOptions(options, pipelineInfo)
+	require.NoError(t, err)
+	require.Equal(t, "pipeline-edges-v1-merge", merge.rcName)
+	require.Equal(t, "pipeline-edges-v1-merge", merge.labels["app"])
+	require.Equal(t, "edges", merge.labels[pipelineNameLabel])
+	require.Equal(t, "true", merge.labels[mergeWorkersLabel])
+	require.Equal(t, []v1.EnvVar{{Name: "A", Value: "1"}, {Name: client.PPSMergeWorkerEnv, Value: "true"}}, merge.workerEnv)
+	require.Nil(t, merge.service)
+	memory := (*merge.resourceRequests)[v1.ResourceMemory]
+	require.Equal(t, "8G", memory.String())
+	require.Nil(t, merge.resourceLimits)
+	// The pipeline's own options are unchanged
+	require.Equal(t, "pipeline-edges-v1", options.labels["app"])
+	require.Equal(t, 1, len(options.workerEnv))
+}
@@ -1,7 +1,9 @@
 package testing
 
 import (
+	"bytes"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/pachyderm/pachyderm/src/client"
@@ -243,6 +245,61 @@ func TestMultiCommit(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestMultiRepoCommit(t *testing.T) {
+	t.Parallel()
+	err := tu.WithRealEnv(func(env *tu.RealEnv) error {
+		require.NoError(t, env.PachClient.CreateRepo("A"))
+		require.NoError(t, env.PachClient.CreateRepo("B"))
+		require.NoError(t, env.PachClient.CreateRepo("C"))
+		require.NoError(t, env.PachClient.CreateBranch("C", "master", "", []*pfs.Branch{client.NewBranch("A", "master"), client.NewBranch("B", "master")}))
+
+		branches := []*pfs.Branch{client.NewBranch("A", "master"), client.NewBranch("B", "master")}
+		var commits []*pfs.Commit
+		require.NoError(t, env.PachClient.ExecuteInMultiCommit(branches, func(c []*pfs.Commit) error {
+			commits = c
+			require.Equal(t, 2, len(commits))
+			for _, commit := range commits {
+				if _, err := env.PachClient.PutFile(commit.Repo.Name, commit.ID, "file", strings.NewReader(commit.Repo.Name)); err != nil {
+					return err
+				}
+			}
+			// Neither commit is visible downstream as finished yet
+			commitInfos, err := env.PachClient.ListCommit("C", "", "", 0)
+			require.NoError(t, err)
+			require.Equal(t, 1, len(commitInfos))
+			require.Nil(t, commitInfos[0].Finished)
+			return nil
+		}))
+
+		for _, commit := range commits {
+			commitInfo, err := env.PachClient.InspectCommit(commit.Repo.Name, commit.ID)
+			require.NoError(t, err)
+			require.NotNil(t, commitInfo.Finished)
+			var buf bytes.Buffer
+			require.NoError(t, env.PachClient.GetFile(commit.Repo.Name, commit.ID, "file", 0, 0, &buf))
+			require.Equal(t, commit.Repo.Name, buf.String())
+		}
+
+		// Both commits are the provenance of a single downstream commit
+		commitInfos, err := env.PachClient.ListCommit("C", "", "", 0)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(commitInfos))
+		require.ElementsEqualUnderFn(t, expectProv(commits...), commitInfos[0].Provenance, provStr)
+
+		// If the callback fails, the commits are deleted
+		require.YesError(t, env.PachClient.ExecuteInMultiCommit(branches, func(c []*pfs.Commit) error {
+			return fmt.Errorf("failed")
+		}))
+		for _, repo := range []string{"A", "B", "C"} {
+			commitInfos, err := env.PachClient.ListCommit(repo, "", "", 0)
+			require.NoError(t, err)
+			require.Equal(t, 1, len(commitInfos))
+		}
+		return nil
+	})
+	require.NoError(t, err)
+}
+
 // Helper functions for tests below
 func provStr(i interface{}) interface{} {
 	cp := i.(*pfs.CommitProvenance)
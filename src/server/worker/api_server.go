@@ -15,6 +15,7 @@ import (
 	"os/user"
 	"path"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -25,13 +26,16 @@ import (
 
 	"github.com/LK4D4/joincontext"
 	etcd "github.com/coreos/etcd/clientv3"
+	units "github.com/docker/go-units"
 	"github.com/gogo/protobuf/jsonpb"
 	"github.com/gogo/protobuf/types"
 	"golang.org/x/net/context"
 	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
 	"gopkg.in/go-playground/webhooks.v5/github"
 	"gopkg.in/src-d/go-git.v4"
 	gitPlumbing "gopkg.in/src-d/go-git.v4/plumbing"
+	"k8s.io/apimachinery/pkg/api/resource"
 	kube "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 
@@ -56,6 +60,7 @@ import (
 	filesync "github.com/pachyderm/pachyderm/src/server/pkg/sync"
 	"github.com/pachyderm/pachyderm/src/server/pkg/uuid"
 	"github.com/pachyderm/pachyderm/src/server/pkg/watch"
+	"github.com/pachyderm/pachyderm/src/server/worker/logs"
 )
 
 const (
@@ -70,6 +75,18 @@ const (
 	shardTTL          = 30
 	noShard           = int64(-1)
 	parentTreeBufSize = 50 * (1 << (10 * 2))
+	// The maximum number of nodes of a datum's output hashtree that are kept
+	// in memory, the rest are spilled to disk
+	outputTreeSpillSize = 100000
+	// The minimum amount of time between updates of a job's merge progress
+	mergeProgressInterval = 10 * time.Second
+	// The minimum amount of time a chunk runs for before another worker may
+	// speculatively run a duplicate attempt of it
+	minSpeculationDelay = time.Minute
+	// Claims of workers running a duplicate attempt of a straggling chunk
+	speculativeChunkPrefix = "/speculative_chunk"
+	// Journals of the datums of chunks that are done (see chunkJournal)
+	chunkJournalPrefix = "/chunk_journal"
 )
 
 type ctxKey int
@@ -84,15 +101,78 @@ var (
 	statsTagSuffix    = "_stats"
 )
 
+// datumError is an error that failed a datum, along with a classification of
+// its cause
+type datumError struct {
+	failureType pps.FailureType
+	err         error
+}
+
+func (e *datumError) Error() string {
+	return e.err.Error()
+}
+
+// classify tags 'err' with 'failureType', unless 'err' has already been
+// classified
+func classify(failureType pps.FailureType, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(*datumError); ok {
+		return err
+	}
+	return &datumError{failureType: failureType, err: err}
+}
+
+// failureType returns the classification of 'err', or FAILURE_UNKNOWN if it
+// wasn't classified
+func failureType(err error) pps.FailureType {
+	if err, ok := err.(*datumError); ok {
+		return err.failureType
+	}
+	return pps.FailureType_FAILURE_UNKNOWN
+}
+
+// exitFailureType classifies the exit status of user code that failed
+// without timing out. 'oomKilled' reports whether the OOM killer killed a
+// process in the worker's cgroup while the user code ran, which is only
+// meaningful if 'oomKnown' is true. Otherwise, a SIGKILL that Pachyderm
+// didn't send (or exit status 137, which is how shells report it for their
+// children) most likely came from the OOM killer.
+func exitFailureType(status syscall.WaitStatus, oomKilled, oomKnown bool) pps.FailureType {
+	if oomKnown {
+		if oomKilled {
+			return pps.FailureType_OOM_KILLED
+		}
+		return pps.FailureType_USER_CODE_NONZERO_EXIT
+	}
+	if (status.Signaled() && status.Signal() == syscall.SIGKILL) || status.ExitStatus() == 128+int(syscall.SIGKILL) {
+		return pps.FailureType_OOM_KILLED
+	}
+	return pps.FailureType_USER_CODE_NONZERO_EXIT
+}
+
 // APIServer implements the worker API
 type APIServer struct {
 	pachClient *client.APIClient
 	kubeClient *kube.Clientset
 	etcdClient *etcd.Client
 	etcdPrefix string
+	// replicas holds connections to other pachd replicas, which downloads
+	// fail over to if pachClient's pachd is unavailable
+	replicas *pachdReplicas
+	// logTail and claims are reported in the worker's DriverState
+	logTail *logTail
+	claims  *chunkClaims
+	// config is the pipeline's worker config, which can change while the
+	// worker runs
+	config *workerConfig
 
 	// Information needed to process input data and upload output
 	pipelineInfo *pps.PipelineInfo
+	// pipelineInfoMu guards the fields of pipelineInfo that the master
+	// updates when the pipeline is updated in place (see reloadPipelineInfo)
+	pipelineInfoMu sync.RWMutex
 
 	// Information attached to log lines
 	logMsgTemplate pps.LogMessage
@@ -114,9 +194,15 @@ type APIServer struct {
 	stats *pps.ProcessStats
 	// queueSize is the number of items enqueued
 	queueSize int64
+	// The running download or upload, if there is one
+	transfer *transfer
 
 	// The total number of workers for this pipeline
 	numWorkers int
+	// mergeWorker is set if this worker is one of the pipeline's merge workers
+	// (see pps.MergeSpec), which merge hashtree shards but don't process
+	// datums, and don't run the pipeline's master
+	mergeWorker bool
 	// The namespace in which pachyderm is deployed
 	namespace string
 	// The jobs collection
@@ -140,6 +226,29 @@ type APIServer struct {
 	uid *uint32
 	gid *uint32
 
+	// umask, dirMode and fileMode are parsed from the pipeline's transform,
+	// they're nil if the transform doesn't set them
+	umask    *os.FileMode
+	dirMode  *os.FileMode
+	fileMode *os.FileMode
+
+	// prefetchBytes bounds the size of the input data downloaded for queued
+	// datums, it's 0 if the pipeline doesn't set a prefetch size
+	prefetchBytes int64
+	// downloadThrottle and uploadThrottle limit the bandwidth of this
+	// worker's input downloads and output uploads to its share of the
+	// pipeline's bandwidth limit. They're nil if there is no limit.
+	downloadThrottle, uploadThrottle *filesync.Throttle
+	// outputBlocks are the PutObjects streams that datums upload their
+	// output over
+	outputBlocks *outputBlockPool
+	// cpuPinning caches the CPUs that user code is pinned to, if the pipeline
+	// sets cpu_pinning
+	cpuPinning cpuPinning
+	// kubeInfoCache caches what the worker knows about its pod and node, which
+	// is added to the errors of failed datums
+	kubeInfoCache kubeInfoCache
+
 	// hashtreeStorage is the where we store on disk hashtrees
 	hashtreeStorage string
 
@@ -155,8 +264,22 @@ type APIServer struct {
 	chunkCache, chunkStatsCache *hashtree.MergeCache
 	// datumCache caches datum hashtrees during a job and can merge them (datumStatsCache applies to stats)
 	datumCache, datumStatsCache *hashtree.MergeCache
+	// treeCache caches serialized hashtrees across jobs, for both merging and
+	// serving chunks
+	treeCache *treeCache
 	// clients are the worker clients (used for the shuffle step by mergers)
 	clients map[string]Client
+
+	// logSink is where the worker sends its logs, in addition to stdout. It's
+	// nil if the cluster isn't deployed with a log sink.
+	logSink logs.Sink
+
+	// processor hands datums to the user code of pipelines with
+	// datum_processor set, it's nil otherwise
+	processor *datumProcessor
+	// mounted is set if PFS is mounted at inputMountPoint, for the pipeline's
+	// mounted inputs
+	mounted bool
 }
 
 type taggedLogger struct {
@@ -168,6 +291,15 @@ type taggedLogger struct {
 	objSize      int64
 	msgCh        chan string
 	eg           errgroup.Group
+	sink         logs.Sink
+	tail         *logTail
+	config       *workerConfig
+	// held, if set, holds the datum's logs in memory instead of writing them
+	// to putObjClient (see holdLogs)
+	held *heldLogs
+	// userOutput, if set, collects the datum's user code output, which is
+	// copied to the pipeline's quarantine repo if the datum fails
+	userOutput *userOutput
 }
 
 // DatumID computes the id for a datum, this value is used in ListDatum and
@@ -189,6 +321,9 @@ func (a *APIServer) getTaggedLogger(pachClient *client.APIClient, jobID string,
 		stderrLog: log.Logger{},
 		marshaler: &jsonpb.Marshaler{},
 		msgCh:     make(chan string, logBuffer),
+		sink:      a.logSink,
+		tail:      a.logTail,
+		config:    a.config,
 	}
 	result.stderrLog.SetOutput(os.Stderr)
 	result.stderrLog.SetFlags(log.LstdFlags | log.Llongfile) // Log file/line
@@ -247,9 +382,26 @@ func (logger *taggedLogger) Logf(formatString string, args ...interface{}) {
 		return
 	}
 	fmt.Println(msg)
-	if logger.putObjClient != nil {
+	if logger.tail != nil {
+		logger.tail.add(msg)
+	}
+	if logger.sink != nil {
+		template := logger.template // Copy struct
+		logger.sink.Write(&template)
+	}
+	if logger.putObjClient != nil || (logger.held != nil && !logger.held.closed) {
 		logger.msgCh <- msg + "\n"
 	}
+	if logger.userOutput != nil && logger.template.User {
+		logger.userOutput.add(logger.template.Message)
+	}
+}
+
+// Debugf is like Logf, but only logs if the worker's log level is debug
+func (logger *taggedLogger) Debugf(formatString string, args ...interface{}) {
+	if logger.config.debug() {
+		logger.Logf(formatString, args...)
+	}
 }
 
 func (logger *taggedLogger) Write(p []byte) (_ int, retErr error) {
@@ -276,6 +428,9 @@ func (logger *taggedLogger) Write(p []byte) (_ int, retErr error) {
 }
 
 func (logger *taggedLogger) Close() (*pfs.Object, int64, error) {
+	if logger.held != nil {
+		logger.held.closed = true
+	}
 	close(logger.msgCh)
 	if logger.putObjClient != nil {
 		if err := logger.eg.Wait(); err != nil {
@@ -287,6 +442,10 @@ func (logger *taggedLogger) Close() (*pfs.Object, int64, error) {
 		logger.putObjClient = nil
 		return object, logger.objSize, err
 	}
+	if logger.held != nil {
+		// the held logs are written by writeStats, if at all
+		return nil, 0, logger.eg.Wait()
+	}
 	return nil, 0, nil
 }
 
@@ -297,6 +456,11 @@ func (logger *taggedLogger) clone() *taggedLogger {
 		marshaler:    &jsonpb.Marshaler{},
 		putObjClient: logger.putObjClient,
 		msgCh:        logger.msgCh,
+		sink:         logger.sink,
+		tail:         logger.tail,
+		config:       logger.config,
+		held:         logger.held,
+		userOutput:   logger.userOutput,
 	}
 }
 
@@ -306,8 +470,80 @@ func (logger *taggedLogger) userLogger() *taggedLogger {
 	return result
 }
 
+// userCmd returns the command line used to run 'cmd' as user code. If the
+// transform sets a umask, 'cmd' is wrapped in a shell that sets it, so that
+// it only applies to the user's process and not to the worker, whose other
+// goroutines keep creating files (datum downloads, hashtrees) concurrently.
+func (a *APIServer) userCmd(cmd []string) []string {
+	if a.umask == nil {
+		return cmd
+	}
+	return append([]string{"/bin/sh", "-c", fmt.Sprintf(`umask %04o && exec "$@"`, *a.umask), "sh"}, cmd...)
+}
+
+// templateCmd returns 'cmd' and 'stdin' with their templates executed for
+// the datum that's being processed, if the transform's template_cmd is set
+func (a *APIServer) templateCmd(cmd, stdin []string) ([]string, []string, error) {
+	if !a.pipelineInfo.Transform.TemplateCmd {
+		return cmd, stdin, nil
+	}
+	a.statusMu.Lock()
+	jobID, data := a.jobID, a.data
+	a.statusMu.Unlock()
+	templateData := &ppsutil.CmdTemplateData{
+		JobID:   jobID,
+		DatumID: a.DatumID(data),
+		Inputs:  make(map[string]string),
+		Commits: make(map[string]string),
+	}
+	for _, input := range data {
+		templateData.Inputs[input.Name] = filepath.Join(client.PPSInputPrefix, input.Name, input.FileInfo.File.Path)
+		templateData.Commits[input.Name] = input.FileInfo.File.Commit.ID
+	}
+	var result [2][]string
+	for i, args := range [][]string{cmd, stdin} {
+		t, err := ppsutil.NewCmdTemplate(args)
+		if err != nil {
+			return nil, nil, err
+		}
+		if result[i], err = t.Execute(templateData); err != nil {
+			return nil, nil, err
+		}
+	}
+	return result[0], result[1], nil
+}
+
+// chmodUserFile sets the mode of 'name' to the transform's dir_mode or
+// file_mode, depending on what kind of file it is. Symlinks and other special
+// files are left alone.
+func (a *APIServer) chmodUserFile(name string, info os.FileInfo) error {
+	switch {
+	case info.IsDir() && a.dirMode != nil:
+		return os.Chmod(name, *a.dirMode)
+	case info.Mode().IsRegular() && a.fileMode != nil:
+		return os.Chmod(name, *a.fileMode)
+	}
+	return nil
+}
+
+// setUserPermissions makes 'root' and everything under it owned by the
+// transform's user (if one is set) and applies the transform's dir_mode and
+// file_mode to it. The walk doesn't follow symlinks, so the datum's directory
+// has to be passed in directly rather than through the links in /pfs.
+func (a *APIServer) setUserPermissions(root string) error {
+	return filepath.Walk(root, func(name string, info os.FileInfo, err error) error {
+		if err == nil && a.uid != nil && a.gid != nil {
+			err = os.Chown(name, int(*a.uid), int(*a.gid))
+		}
+		if err == nil {
+			err = a.chmodUserFile(name, info)
+		}
+		return err
+	})
+}
+
 // NewAPIServer creates an APIServer for a given pipeline
-func NewAPIServer(pachClient *client.APIClient, etcdClient *etcd.Client, etcdPrefix string, pipelineInfo *pps.PipelineInfo, workerName string, namespace string, hashtreeStorage string) (*APIServer, error) {
+func NewAPIServer(pachClient *client.APIClient, etcdClient *etcd.Client, etcdPrefix string, pipelineInfo *pps.PipelineInfo, workerName string, namespace string, hashtreeStorage string, logSink logs.Sink) (*APIServer, error) {
 	initPrometheus()
 
 	span, ctx := extended.AddPipelineSpanToAnyTrace(pachClient.Ctx(),
@@ -338,6 +574,11 @@ func NewAPIServer(pachClient *client.APIClient, etcdClient *etcd.Client, etcdPre
 		},
 		workerName:      workerName,
 		namespace:       namespace,
+		replicas:        newPachdReplicas(),
+		logTail:         newLogTail(),
+		claims:          newChunkClaims(),
+		config:          &workerConfig{},
+		logSink:         logSink,
 		jobs:            ppsdb.Jobs(etcdClient, etcdPrefix),
 		pipelines:       ppsdb.Pipelines(etcdClient, etcdPrefix),
 		plans:           col.NewCollection(etcdClient, path.Join(etcdPrefix, planPrefix), nil, &Plan{}, nil, nil),
@@ -346,18 +587,22 @@ func NewAPIServer(pachClient *client.APIClient, etcdClient *etcd.Client, etcdPre
 		claimedShard:    make(chan context.Context, 1),
 		shard:           noShard,
 		clients:         make(map[string]Client),
+		mergeWorker:     os.Getenv(client.PPSMergeWorkerEnv) == "true",
 	}
 	logger, err := server.getTaggedLogger(pachClient, "", nil, false)
 	if err != nil {
 		return nil, err
 	}
+	if pipelineInfo.Transform.DatumProcessor {
+		server.processor = newDatumProcessor(server, logger)
+	}
 	resp, err := pachClient.Enterprise.GetState(context.Background(), &enterprise.GetStateRequest{})
 	if err != nil {
 		logger.Logf("failed to get enterprise state with error: %v\n", err)
 	} else {
 		server.exportStats = resp.State == enterprise.State_ACTIVE
 	}
-	numWorkers, err := ppsutil.GetExpectedNumWorkers(kubeClient, pipelineInfo.ParallelismSpec)
+	numWorkers, err := ppsutil.GetPlannedNumWorkers(kubeClient, pipelineInfo)
 	if err != nil {
 		logger.Logf("error getting number of workers, default to 1 worker: %v", err)
 		numWorkers = 1
@@ -380,6 +625,15 @@ func NewAPIServer(pachClient *client.APIClient, etcdClient *etcd.Client, etcdPre
 	server.chunkStatsCache = hashtree.NewMergeCache(filepath.Join(root, "chunk", "stats"))
 	server.datumCache = hashtree.NewMergeCache(filepath.Join(root, "datum"))
 	server.datumStatsCache = hashtree.NewMergeCache(filepath.Join(root, "datum", "stats"))
+	if err := os.MkdirAll(filepath.Join(root, "tree"), 0777); err != nil {
+		return nil, err
+	}
+	cacheSize, err := treeCacheSize(os.Getenv(client.WorkerHashtreeCacheSizeEnv))
+	if err != nil {
+		logger.Logf("%v, default to %d bytes", err, defaultTreeCacheSize)
+		cacheSize = defaultTreeCacheSize
+	}
+	server.treeCache = newTreeCache(filepath.Join(root, "tree"), cacheSize, pipelineInfo.Pipeline.Name)
 	var noDocker bool
 	if _, err := os.Stat("/var/run/docker.sock"); err != nil {
 		noDocker = true
@@ -430,7 +684,56 @@ func NewAPIServer(pachClient *client.APIClient, etcdClient *etcd.Client, etcdPre
 			server.gid = &gid32
 		}
 	}
+	if server.umask, err = ppsutil.ParseFileMode(pipelineInfo.Transform.Umask); err != nil {
+		return nil, err
+	}
+	if server.dirMode, err = ppsutil.ParseFileMode(pipelineInfo.Transform.DirMode); err != nil {
+		return nil, err
+	}
+	if server.fileMode, err = ppsutil.ParseFileMode(pipelineInfo.Transform.FileMode); err != nil {
+		return nil, err
+	}
+	if pipelineInfo.PrefetchSize != "" {
+		prefetchSize, err := resource.ParseQuantity(pipelineInfo.PrefetchSize)
+		if err != nil {
+			return nil, err
+		}
+		server.prefetchBytes = prefetchSize.Value()
+	}
+	if err := checkScratchVolume(client.PPSInputPrefix, pipelineInfo.ScratchVolume); err != nil {
+		return nil, err
+	}
+	// The streams aren't tied to a job, so they don't use the job's context
+	server.outputBlocks = newOutputBlockPool(func() (pfs.ObjectAPI_PutObjectsClient, error) {
+		return server.pachClient.ObjectAPIClient.PutObjects(server.pachClient.Ctx())
+	}, outputStreams)
+	if limit := pipelineInfo.BandwidthLimit; limit != nil {
+		if server.downloadThrottle, err = workerThrottle(limit.Download, numWorkers); err != nil {
+			return nil, err
+		}
+		if server.uploadThrottle, err = workerThrottle(limit.Upload, numWorkers); err != nil {
+			return nil, err
+		}
+	}
+	// Fail fast if the image is broken, rather than failing every datum
+	if err := server.verifyImage(); err != nil {
+		if crashErr := ppsutil.CrashPipeline(ctx, etcdClient, server.pipelines, pipelineInfo.Pipeline.Name, err.Error()); crashErr != nil {
+			return nil, fmt.Errorf("error marking pipeline as crashing (%v) after error verifying image: %v", crashErr, err)
+		}
+		return nil, fmt.Errorf("error verifying image: %v", err)
+	}
+	if !server.mergeWorker && ppsutil.HasMountedInputs(pipelineInfo.Input) {
+		if err := server.mountInputs(); err != nil {
+			return nil, err
+		}
+		server.mounted = true
+	}
+	if err := ppsutil.RecoverPipeline(ctx, etcdClient, server.pipelines, pipelineInfo.Pipeline.Name); err != nil {
+		return nil, err
+	}
 	switch {
+	case server.mergeWorker:
+		// only the pipeline's other workers run its master
 	case pipelineInfo.Service != nil:
 		go server.master("service", server.serviceSpawner)
 	case pipelineInfo.Spout != nil:
@@ -438,6 +741,10 @@ func NewAPIServer(pachClient *client.APIClient, etcdClient *etcd.Client, etcdPre
 	default:
 		go server.master("pipeline", server.jobSpawner)
 	}
+	if pipelineInfo.MetricsPush != nil {
+		go server.pushMetrics(logger)
+	}
+	go server.watchWorkerConfig()
 	go server.worker()
 	return server, nil
 }
@@ -514,7 +821,10 @@ func (a *APIServer) reportDownloadTimeStats(start time.Time, stats *pps.ProcessS
 	}
 }
 
-func (a *APIServer) downloadData(pachClient *client.APIClient, logger *taggedLogger, inputs []*Input, puller *filesync.Puller, stats *pps.ProcessStats, statsTree *hashtree.Ordered) (_ string, retErr error) {
+// downloadData downloads the datum 'inputs' into a new scratch directory, and
+// returns the directory. If the pipeline mounts its previous output,
+// 'prevCommit' is the output commit to mount (nil if there is none).
+func (a *APIServer) downloadData(pachClient *client.APIClient, logger *taggedLogger, inputs []*Input, prevCommit *pfs.Commit, puller *filesync.Puller, stats *pps.ProcessStats, statsTree *hashtree.Ordered) (_ string, retErr error) {
 	defer a.reportDownloadTimeStats(time.Now(), stats, logger)
 	logger.Logf("starting to download data")
 	defer func(start time.Time) {
@@ -524,50 +834,97 @@ func (a *APIServer) downloadData(pachClient *client.APIClient, logger *taggedLog
 			logger.Logf("finished downloading data after %v", time.Since(start))
 		}
 	}(time.Now())
+	defer a.trackTransfer(logger, "download", datumSize(inputs), puller.Size)()
 	dir := filepath.Join(client.PPSInputPrefix, client.PPSScratchSpace, uuid.NewWithoutDashes())
 	// Create output directory (currently /pfs/out)
 	outPath := filepath.Join(dir, "out")
 	if a.pipelineInfo.Spout != nil {
 		// Spouts need to create a named pipe at /pfs/out
-		if err := os.MkdirAll(filepath.Dir(outPath), 0700); err != nil {
-			return "", fmt.Errorf("mkdirall :%v", err)
-		}
-		if err := createSpoutFifo(outPath); err != nil {
-			return "", fmt.Errorf("mkfifo :%v", err)
-		}
-		_, err := pachClient.InspectFile(a.pipelineInfo.Pipeline.Name, a.pipelineInfo.OutputBranch, "marker")
-		if err != nil && strings.Contains(err.Error(), "not found") {
-			if err := puller.Pull(pachClient, filepath.Join(dir, "marker"), a.pipelineInfo.Pipeline.Name, a.pipelineInfo.OutputBranch, "/marker", false, false, concurrency, nil, ""); err != nil {
-				return "", err
-			}
+		if err := a.prepareSpoutOutput(pachClient, puller, dir, outPath); err != nil {
+			return dir, err
 		}
 	} else {
 		if err := os.MkdirAll(outPath, 0777); err != nil {
-			return "", err
+			return dir, err
+		}
+	}
+	if a.pipelineInfo.PreviousOutput {
+		prevPath := filepath.Join(dir, client.PPSPrevOutputName)
+		if err := os.MkdirAll(prevPath, 0777); err != nil {
+			return dir, err
+		}
+		// The previous output can be large, and the user code may only read
+		// part of it, so it's always lazy
+		if prevCommit != nil {
+			if err := puller.Pull(pachClient, prevPath, prevCommit.Repo.Name, prevCommit.ID, "/", true, false, a.config.transferConcurrency(), nil, ""); err != nil {
+				return dir, fmt.Errorf("error downloading previous output: %v", err)
+			}
 		}
 	}
 	for _, input := range inputs {
 		if input.GitURL != "" {
 			if err := a.downloadGitData(pachClient, dir, input); err != nil {
-				return "", err
+				return dir, err
 			}
 			continue
 		}
 		file := input.FileInfo.File
 		root := filepath.Join(dir, input.Name, file.Path)
+		if a.mounted && mountedInput(input) {
+			logger.Debugf("linking mounted input %s (%s@%s:%s)", input.Name, file.Commit.Repo.Name, file.Commit.ID, file.Path)
+			if err := linkMountedInput(input, root); err != nil {
+				return dir, err
+			}
+			continue
+		}
 		var statsRoot string
 		if statsTree != nil {
 			statsRoot = path.Join(input.Name, file.Path)
 			parent, _ := path.Split(statsRoot)
 			statsTree.MkdirAll(parent)
 		}
-		if err := puller.Pull(pachClient, root, file.Commit.Repo.Name, file.Commit.ID, file.Path, input.Lazy, input.EmptyFiles, concurrency, statsTree, statsRoot); err != nil {
-			return "", err
+		var readAhead int64
+		if input.Lazy && input.ReadAhead != "" {
+			size, err := resource.ParseQuantity(input.ReadAhead)
+			if err != nil {
+				return dir, fmt.Errorf("could not parse read_ahead of input %s: %v", input.Name, err)
+			}
+			readAhead = size.Value()
+		}
+		puller.SetReadAhead(readAhead)
+		logger.Debugf("downloading input %s (%s@%s:%s, lazy: %t)", input.Name, file.Commit.Repo.Name, file.Commit.ID, file.Path, input.Lazy)
+		if err := puller.Pull(pachClient, root, file.Commit.Repo.Name, file.Commit.ID, file.Path, input.Lazy, input.EmptyFiles, a.config.transferConcurrency(), statsTree, statsRoot); err != nil {
+			return dir, err
 		}
 	}
+	puller.SetReadAhead(0)
 	return dir, nil
 }
 
+// lazyFileStats converts the stats of the pipes that 'puller' served the
+// lazy files of the datum downloaded to 'dir' through, to their paths in /pfs
+func lazyFileStats(dir string, puller *filesync.Puller) []*pps.LazyFileStats {
+	var result []*pps.LazyFileStats
+	for _, stats := range puller.PipeStats() {
+		p := stats.Path
+		if rel, err := filepath.Rel(dir, stats.Path); err == nil {
+			p = filepath.Join(client.PPSInputPrefix, rel)
+		}
+		lazyFile := &pps.LazyFileStats{
+			Path:        p,
+			BytesServed: uint64(stats.BytesServed),
+			WaitTime:    types.DurationProto(stats.WaitTime),
+			BlockedTime: types.DurationProto(stats.BlockedTime),
+		}
+		if !stats.Opened.IsZero() {
+			lazyFile.Opened, _ = types.TimestampProto(stats.Opened)
+			lazyFile.Closed, _ = types.TimestampProto(stats.Closed)
+		}
+		result = append(result, lazyFile)
+	}
+	return result
+}
+
 func (a *APIServer) linkData(inputs []*Input, dir string) error {
 	// Make sure that previously symlinked outputs are removed.
 	err := a.unlinkData(inputs)
@@ -582,6 +939,18 @@ func (a *APIServer) linkData(inputs []*Input, dir string) error {
 		}
 	}
 
+	if a.pipelineInfo.PreviousOutput {
+		if err := os.Symlink(filepath.Join(dir, client.PPSPrevOutputName), filepath.Join(client.PPSInputPrefix, client.PPSPrevOutputName)); err != nil {
+			return err
+		}
+	}
+
+	if a.pipelineInfo.JobScratch {
+		if err := os.Symlink(filepath.Join(dir, client.PPSJobScratchName), filepath.Join(client.PPSInputPrefix, client.PPSJobScratchName)); err != nil {
+			return err
+		}
+	}
+
 	err = os.Symlink(filepath.Join(dir, "marker"), filepath.Join(client.PPSInputPrefix, "marker"))
 	if err != nil {
 		return err
@@ -663,20 +1032,76 @@ func (a *APIServer) runUserCode(ctx context.Context, logger *taggedLogger, envir
 		defer cancel()
 		ctx = datumTimeoutCtx
 	}
+	if a.pipelineInfo.Transform.DatumProcessor {
+		a.statusMu.Lock()
+		data := a.data
+		a.statusMu.Unlock()
+		return a.processor.process(ctx, logger, a.processorDatum(logger, data, environ))
+	}
+	var stdout io.Writer = logger.userLogger()
+	if a.pipelineInfo.Transform.StdoutFile != "" {
+		f, err := a.createStdoutFile(filepath.Join(client.PPSInputPrefix, "out"))
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := f.Close(); err != nil && retErr == nil {
+				retErr = err
+			}
+		}()
+		stdout = f
+	}
+	return a.execUserCode(ctx, stdout, logger.userLogger(), environ)
+}
 
-	// Run user code
-	cmd := exec.CommandContext(ctx, a.pipelineInfo.Transform.Cmd[0], a.pipelineInfo.Transform.Cmd[1:]...)
-	if a.pipelineInfo.Transform.Stdin != nil {
-		cmd.Stdin = strings.NewReader(strings.Join(a.pipelineInfo.Transform.Stdin, "\n") + "\n")
+// createStdoutFile creates the transform's stdout_file under 'outDir', for the
+// user code's stdout to be written to. It's truncated each time the user code
+// runs, so that a datum that's retried doesn't keep the output of its failed
+// runs.
+func (a *APIServer) createStdoutFile(outDir string) (*os.File, error) {
+	name := filepath.Join(outDir, a.pipelineInfo.Transform.StdoutFile)
+	if err := os.MkdirAll(filepath.Dir(name), 0777); err != nil {
+		return nil, fmt.Errorf("error creating stdout_file: %v", err)
 	}
-	cmd.Stdout = logger.userLogger()
-	cmd.Stderr = logger.userLogger()
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return nil, fmt.Errorf("error creating stdout_file: %v", err)
+	}
+	// The rest of /pfs/out was handed to the transform's user before the
+	// user code started
+	if (a.uid != nil && a.gid != nil) || a.fileMode != nil {
+		if err := a.setUserPermissions(name); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("error setting the permissions of stdout_file: %v", err)
+		}
+	}
+	return f, nil
+}
+
+// execUserCode runs the transform's cmd, writing its output to 'stdout' and
+// 'stderr'
+func (a *APIServer) execUserCode(ctx context.Context, stdout, stderr io.Writer, environ []string) error {
+	userCmd, stdin, err := a.templateCmd(a.pipelineInfo.Transform.Cmd, a.pipelineInfo.Transform.Stdin)
+	if err != nil {
+		return err
+	}
+	args := a.userCmd(userCmd)
+	if a.pipelineInfo.Transform.SeparateContainer {
+		return a.runInUserContainer(ctx, stdout, stderr, args, stdin, environ, true)
+	}
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	if stdin != nil {
+		cmd.Stdin = strings.NewReader(strings.Join(stdin, "\n") + "\n")
+	}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
 	cmd.Env = environ
 	if a.uid != nil && a.gid != nil {
 		cmd.SysProcAttr = makeCmdCredentials(*a.uid, *a.gid)
 	}
 	cmd.Dir = a.pipelineInfo.Transform.WorkingDir
-	err := cmd.Start()
+	oomKillsBefore, oomKnown := oomKills()
+	err = a.startUserCode(cmd)
 	if err != nil {
 		return fmt.Errorf("error cmd.Start: %v", err)
 	}
@@ -688,6 +1113,9 @@ func (a *APIServer) runUserCode(ctx context.Context, logger *taggedLogger, envir
 	}
 	if isDone(ctx) {
 		if err = ctx.Err(); err != nil {
+			if err == context.DeadlineExceeded {
+				return classify(pps.FailureType_DATUM_TIMEOUT, err)
+			}
 			return err
 		}
 	}
@@ -711,6 +1139,14 @@ func (a *APIServer) runUserCode(ctx context.Context, logger *taggedLogger, envir
 						return nil
 					}
 				}
+				// The datum didn't time out (checked above), so the exit status
+				// is the user code's own doing, or the OOM killer's
+				oomKillsAfter, ok := oomKills()
+				failure := exitFailureType(status, oomKillsAfter > oomKillsBefore, oomKnown && ok)
+				if failure == pps.FailureType_OOM_KILLED {
+					return classify(failure, fmt.Errorf("user code was killed by the OOM killer: error cmd.WaitIO: %v", err))
+				}
+				return classify(failure, fmt.Errorf("error cmd.WaitIO: %v", err))
 			}
 		}
 		return fmt.Errorf("error cmd.WaitIO: %v", err)
@@ -729,9 +1165,17 @@ func (a *APIServer) runUserErrorHandlingCode(ctx context.Context, logger *tagged
 		}
 	}(time.Now())
 
-	cmd := exec.CommandContext(ctx, a.pipelineInfo.Transform.ErrCmd[0], a.pipelineInfo.Transform.ErrCmd[1:]...)
-	if a.pipelineInfo.Transform.ErrStdin != nil {
-		cmd.Stdin = strings.NewReader(strings.Join(a.pipelineInfo.Transform.ErrStdin, "\n") + "\n")
+	errCmd, errStdin, err := a.templateCmd(a.pipelineInfo.Transform.ErrCmd, a.pipelineInfo.Transform.ErrStdin)
+	if err != nil {
+		return err
+	}
+	args := a.userCmd(errCmd)
+	if a.pipelineInfo.Transform.SeparateContainer {
+		return a.runInUserContainer(ctx, logger.userLogger(), logger.userLogger(), args, errStdin, environ, false)
+	}
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	if errStdin != nil {
+		cmd.Stdin = strings.NewReader(strings.Join(errStdin, "\n") + "\n")
 	}
 	cmd.Stdout = logger.userLogger()
 	cmd.Stderr = logger.userLogger()
@@ -740,7 +1184,7 @@ func (a *APIServer) runUserErrorHandlingCode(ctx context.Context, logger *tagged
 		cmd.SysProcAttr = makeCmdCredentials(*a.uid, *a.gid)
 	}
 	cmd.Dir = a.pipelineInfo.Transform.WorkingDir
-	err := cmd.Start()
+	err = a.startUserCode(cmd)
 	if err != nil {
 		return fmt.Errorf("error cmd.Start: %v", err)
 	}
@@ -808,7 +1252,11 @@ func (a *APIServer) reportUploadStats(start time.Time, stats *pps.ProcessStats,
 	}
 }
 
-func (a *APIServer) uploadOutput(pachClient *client.APIClient, dir string, tag string, logger *taggedLogger, inputs []*Input, stats *pps.ProcessStats, statsTree *hashtree.Ordered, datumIdx int64) (retErr error) {
+// uploadOutput uploads the output of the datum in 'dir' and writes the
+// datum's hashtree. The files that previous attempts at the datum uploaded
+// completely are recorded in 'landed' and aren't uploaded again if they're
+// unchanged; the files that this attempt uploads completely are added to it.
+func (a *APIServer) uploadOutput(pachClient *client.APIClient, dir string, tag string, logger *taggedLogger, inputs []*Input, stats *pps.ProcessStats, statsTree *hashtree.Ordered, datumIdx int64, landed *landedOutput, class pfs.StorageClass) (retErr error) {
 	defer a.reportUploadStats(time.Now(), stats, logger)
 	logger.Logf("starting to upload output")
 	defer func(start time.Time) {
@@ -818,22 +1266,29 @@ func (a *APIServer) uploadOutput(pachClient *client.APIClient, dir string, tag s
 			logger.Logf("finished uploading output after %v", time.Since(start))
 		}
 	}(time.Now())
-	// Setup client for writing file data
-	putObjsClient, err := pachClient.ObjectAPIClient.PutObjects(pachClient.Ctx())
-	if err != nil {
-		return err
-	}
-	block := &pfs.Block{Hash: uuid.NewWithoutDashes()}
-	if err := putObjsClient.Send(&pfs.PutObjectRequest{
-		Block: block,
-	}); err != nil {
-		return err
-	}
+	// Setup writer for file data
+	blockWriter := newOutputBlockWriter(a.outputBlocks, landed, class)
+	defer func() {
+		// If the upload failed, the blocks that it wrote to still have to
+		// be finished, as other datums may share them
+		if err := blockWriter.close(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
 	outputPath := filepath.Join(dir, "out")
+	var uploaded int64
+	defer a.trackTransfer(logger, "upload", outputSize(outputPath), func() int64 { return atomic.LoadInt64(&uploaded) })()
 	buf := grpcutil.GetBuffer()
 	defer grpcutil.PutBuffer(buf)
-	var offset uint64
+	var reusedFiles, reusedBytes int64
 	var tree *hashtree.Ordered
+	defer func() {
+		if tree != nil {
+			if err := tree.Close(); err != nil && retErr == nil {
+				retErr = err
+			}
+		}
+	}()
 	// Upload all files in output directory
 	if err := filepath.Walk(outputPath, func(filePath string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -843,7 +1298,7 @@ func (a *APIServer) uploadOutput(pachClient *client.APIClient, dir string, tag s
 			return fmt.Errorf("file path is not valid utf-8: %s", filePath)
 		}
 		if filePath == outputPath {
-			tree = hashtree.NewOrdered("/")
+			tree = hashtree.NewSpillableOrdered("/", a.hashtreeStorage, outputTreeSpillSize)
 			return nil
 		}
 		relPath, err := filepath.Rel(outputPath, filePath)
@@ -960,53 +1415,62 @@ func (a *APIServer) uploadOutput(pachClient *client.APIClient, dir string, tag s
 				retErr = err
 			}
 		}()
-		var size int64
-		h := pfs.NewHash()
-		r := io.TeeReader(f, h)
-		// Write local file to object storage block
-		for {
-			n, err := r.Read(buf)
-			if n == 0 && err != nil {
-				if err == io.EOF {
-					break
-				}
-				return err
-			}
-			if err := putObjsClient.Send(&pfs.PutObjectRequest{
-				Value: buf[:n],
-			}); err != nil {
+		// If a previous attempt at the datum uploaded the same file, reuse it
+		file, err := landed.reuse(relPath, f, info.Size(), buf)
+		if err != nil {
+			return err
+		}
+		if file != nil {
+			reusedFiles++
+			reusedBytes += file.size
+			atomic.AddInt64(&uploaded, file.size)
+		} else {
+			// Write local file to object storage block
+			if file, err = blockWriter.write(relPath, a.uploadThrottle.Reader(pachClient.Ctx(), f), buf, &uploaded); err != nil {
 				return err
 			}
-			size += int64(n)
-		}
-		n := &hashtree.FileNodeProto{
-			BlockRefs: []*pfs.BlockRef{
-				&pfs.BlockRef{
-					Block: block,
-					Range: &pfs.ByteRange{
-						Lower: offset,
-						Upper: offset + uint64(size),
-					},
-				},
-			},
-		}
-		hash := h.Sum(nil)
-		tree.PutFile(relPath, hash, size, n)
+			stats.UploadBytes += uint64(file.size)
+		}
+		n := &hashtree.FileNodeProto{BlockRefs: file.blockRefs}
+		tree.PutFile(relPath, file.hash, file.size, n)
 		if statsTree != nil {
-			statsTree.PutFile(relPath, hash, size, n)
+			statsTree.PutFile(relPath, file.hash, file.size, n)
 		}
-		offset += uint64(size)
-		stats.UploadBytes += uint64(size)
 		return nil
 	}); err != nil {
+		if err == errSpecialFile {
+			return classify(pps.FailureType_SPECIAL_FILE, fmt.Errorf("error walking output: %v", err))
+		}
 		return fmt.Errorf("error walking output: %v", err)
 	}
-	if _, err := putObjsClient.CloseAndRecv(); err != nil && err != io.EOF {
+	if err := blockWriter.close(); err != nil {
+		return err
+	}
+	if reusedFiles > 0 {
+		logger.Logf("reused %d output files (%s) uploaded by a previous attempt", reusedFiles, units.BytesSize(float64(reusedBytes)))
+	}
+	// Serialize datum hashtree to a local file, so that large hashtrees don't
+	// have to fit in memory
+	f, err := ioutil.TempFile(a.hashtreeStorage, "datum")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := f.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+		if err := os.Remove(f.Name()); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+	bufW := bufio.NewWriter(f)
+	if err := tree.Serialize(bufW); err != nil {
+		return err
+	}
+	if err := bufW.Flush(); err != nil {
 		return err
 	}
-	// Serialize datum hashtree
-	b := &bytes.Buffer{}
-	if err := tree.Serialize(b); err != nil {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
 		return err
 	}
 	// Write datum hashtree to object storage
@@ -1019,11 +1483,21 @@ func (a *APIServer) uploadOutput(pachClient *client.APIClient, dir string, tag s
 			retErr = err
 		}
 	}()
-	if _, err := w.Write(b.Bytes()); err != nil {
+	treeW, err := newDatumTreeWriter(w, a.pipelineInfo.DatumTreeCompression)
+	if err != nil {
+		return err
+	}
+	if _, err := io.CopyBuffer(treeW, f, buf); err != nil {
+		return err
+	}
+	if err := treeW.Close(); err != nil {
+		return err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
 		return err
 	}
 	// Cache datum hashtree locally
-	return a.datumCache.Put(datumIdx, bytes.NewReader(b.Bytes()))
+	return a.datumCache.Put(datumIdx, f)
 }
 
 // HashDatum computes and returns the hash of datum + pipeline, with a
@@ -1088,6 +1562,9 @@ func (a *APIServer) Status(ctx context.Context, _ *types.Empty) (*pps.WorkerStat
 		Data:      a.datum(),
 		QueueSize: atomic.LoadInt64(&a.queueSize),
 	}
+	if a.transfer != nil {
+		result.Transfer = a.transfer.progress()
+	}
 	return result, nil
 }
 
@@ -1130,29 +1607,37 @@ func (a *APIServer) datum() []*pps.InputFile {
 	return result
 }
 
-func (a *APIServer) userCodeEnv(jobID string, outputCommitID string, data []*Input) []string {
-	result := os.Environ()
+func (a *APIServer) userCodeEnv(jobID string, outputCommitID string, tag string, data []*Input) []string {
+	result := a.baseEnv()
 	for _, input := range data {
 		result = append(result, fmt.Sprintf("%s=%s", input.Name, filepath.Join(client.PPSInputPrefix, input.Name, input.FileInfo.File.Path)))
 		result = append(result, fmt.Sprintf("%s_COMMIT=%s", input.Name, input.FileInfo.File.Commit.ID))
 	}
 	result = append(result, fmt.Sprintf("%s=%s", client.JobIDEnv, jobID))
 	result = append(result, fmt.Sprintf("%s=%s", client.OutputCommitIDEnv, outputCommitID))
+	if a.pipelineInfo.Transform.DatumSeed {
+		result = append(result, fmt.Sprintf("%s=%d", client.DatumSeedEnv, datumSeed(tag)))
+	}
 	return result
 }
 
 type processResult struct {
 	failedDatumID   string
+	failureType     pps.FailureType
+	failureCounts   map[int32]int64 // failed datums, keyed by pps.FailureType
 	datumsProcessed int64
 	datumsSkipped   int64
 	datumsRecovered int64
 	datumsFailed    int64
 	recoveredDatums *pfs.Object
+	// stats are the process stats of the chunk's datums, which are added to
+	// the job's when the chunk is marked complete
+	stats *pps.ProcessStats
 }
 
-type processFunc func(low, high int64) (*processResult, error)
+type processFunc func(ctx context.Context, low, high int64) (*processResult, error)
 
-func (a *APIServer) acquireDatums(ctx context.Context, jobID string, plan *Plan, logger *taggedLogger, process processFunc) error {
+func (a *APIServer) acquireDatums(ctx context.Context, jobID string, plan *Plan, logger *taggedLogger, pause *jobPause, process processFunc) error {
 	chunks := a.chunks(jobID)
 	watcher, err := chunks.ReadOnly(ctx).Watch(watch.WithFilterPut())
 	if err != nil {
@@ -1160,30 +1645,64 @@ func (a *APIServer) acquireDatums(ctx context.Context, jobID string, plan *Plan,
 	}
 	var complete bool
 	for !complete {
+		// Don't claim chunks while the job is paused
+		if err := pause.wait(ctx); err != nil {
+			return err
+		}
+		pauseChanged := pause.changes()
 		// We set complete to true and then unset it if we find an incomplete chunk
 		complete = true
-		// Attempt to claim a chunk
-		low, high := int64(0), int64(0)
-		for _, high = range plan.Chunks {
-			var chunkState ChunkState
-			if err := chunks.Claim(ctx, fmt.Sprint(high), &chunkState, func(ctx context.Context) error {
-				return a.processChunk(ctx, jobID, low, high, process)
+		var claimed bool
+		states := make([]*ChunkState, len(plan.Chunks))
+		// Attempt to claim a chunk, in the order that the pipeline's chunk
+		// strategy gives this worker
+		for _, i := range getChunkStrategy(a.pipelineInfo.ChunkSpec).claimOrder(plan, a.workerName) {
+			if pause.isPaused() {
+				complete = false
+				break
+			}
+			low, high := int64(0), plan.Chunks[i]
+			if i > 0 {
+				low = plan.Chunks[i-1]
+			}
+			chunkState := &ChunkState{Started: types.TimestampNow()}
+			// The claim ends once the chunk's state is overwritten (when any
+			// attempt at it finishes), so that renewing it doesn't put a TTL
+			// on the finished state
+			if err := chunks.ClaimUnchanged(ctx, fmt.Sprint(high), chunkState, func(ctx context.Context) error {
+				defer a.claims.add(jobID, low, high, false)()
+				logger.Debugf("claimed chunk %d (datums %d to %d)", high, low, high)
+				err := a.runChunk(ctx, jobID, low, high, logger, process)
+				if err == errJobPaused {
+					return a.releaseChunk(ctx, jobID, high)
+				}
+				return err
 			}); err == col.ErrNotClaimed {
 				// Check if a different worker is processing this chunk
 				if chunkState.State == State_RUNNING {
 					complete = false
 				}
+				states[i] = chunkState
 			} else if err != nil {
 				return fmt.Errorf("error claiming/processing chunk: %v", err)
+			} else {
+				claimed = true
 			}
-			low = high
 		}
-		// Wait for a deletion event (ttl expired) before attempting to claim a chunk again
+		// If this worker is idle, help any other worker that's straggling
+		if !complete && !claimed && a.pipelineInfo.SpeculationFactor > 0 {
+			if err := a.speculate(ctx, jobID, plan, states, logger, process); err != nil {
+				return err
+			}
+		}
+		// Wait for a deletion event (ttl expired), or for the job to be paused
+		// or resumed, before attempting to claim a chunk again
 		select {
 		case e := <-watcher.Watch():
 			if e.Type == watch.EventError {
 				return fmt.Errorf("chunk watch error: %v", e.Err)
 			}
+		case <-pauseChanged:
 		case <-ctx.Done():
 			return ctx.Err()
 		}
@@ -1191,12 +1710,172 @@ func (a *APIServer) acquireDatums(ctx context.Context, jobID string, plan *Plan,
 	return nil
 }
 
+// speculate runs a duplicate attempt of one of the chunks in 'plan' that
+// another worker is straggling on (see stragglers()), if there is one that no
+// other worker is already speculating on. 'states' are the chunks' states, as
+// read by acquireDatums.
+func (a *APIServer) speculate(ctx context.Context, jobID string, plan *Plan, states []*ChunkState, logger *taggedLogger, process processFunc) error {
+	speculativeChunks := a.speculativeChunks(jobID)
+	for _, i := range stragglers(plan, states, a.pipelineInfo.SpeculationFactor, time.Now()) {
+		low, high := int64(0), plan.Chunks[i]
+		if i > 0 {
+			low = plan.Chunks[i-1]
+		}
+		claim := &ChunkState{Started: types.TimestampNow()}
+		if err := speculativeChunks.Claim(ctx, fmt.Sprint(high), claim, func(ctx context.Context) error {
+			defer a.claims.add(jobID, low, high, true)()
+			logger.Logf("speculatively processing chunk %d (datums %d to %d), which another worker is straggling on", high, low, high)
+			if err := a.runChunk(ctx, jobID, low, high, logger, process); err != errJobPaused {
+				return err
+			}
+			// The worker that claimed the chunk releases it
+			return nil
+		}); err == col.ErrNotClaimed {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("error claiming/processing speculative chunk: %v", err)
+		}
+		return nil
+	}
+	return nil
+}
+
+// stragglers returns the indexes (into plan.Chunks) of the running chunks in
+// 'states' that have run for more than 'factor' times as long as the
+// completed chunks' median time per datum predicts, slowest first. No chunk
+// is a straggler until at least one chunk has completed, or before it has run
+// for minSpeculationDelay.
+func stragglers(plan *Plan, states []*ChunkState, factor float64, now time.Time) []int {
+	size := func(i int) int64 {
+		if i == 0 {
+			return plan.Chunks[0]
+		}
+		return plan.Chunks[i] - plan.Chunks[i-1]
+	}
+	var perDatum []float64
+	for i, state := range states {
+		if state == nil || state.State != State_COMPLETE || state.ProcessTime == nil || size(i) <= 0 {
+			continue
+		}
+		processTime, err := types.DurationFromProto(state.ProcessTime)
+		if err != nil {
+			continue
+		}
+		perDatum = append(perDatum, float64(processTime)/float64(size(i)))
+	}
+	if len(perDatum) == 0 {
+		return nil
+	}
+	sort.Float64s(perDatum)
+	median := perDatum[len(perDatum)/2]
+	if len(perDatum)%2 == 0 {
+		median = (perDatum[len(perDatum)/2-1] + median) / 2
+	}
+	var result []int
+	overrun := make(map[int]float64)
+	for i, state := range states {
+		if state == nil || state.State != State_RUNNING || state.Started == nil {
+			continue
+		}
+		started, err := types.TimestampFromProto(state.Started)
+		if err != nil {
+			continue
+		}
+		elapsed := now.Sub(started)
+		expected := median * float64(size(i))
+		if elapsed < minSpeculationDelay || float64(elapsed) <= factor*expected {
+			continue
+		}
+		result = append(result, i)
+		overrun[i] = float64(elapsed) / expected
+	}
+	sort.SliceStable(result, func(i, j int) bool { return overrun[result[i]] > overrun[result[j]] })
+	return result
+}
+
+// releaseChunk releases this worker's claim on the chunk ending at 'high',
+// which it stopped processing because the job was paused, so that any worker
+// can claim it once the job is resumed
+func (a *APIServer) releaseChunk(ctx context.Context, jobID string, high int64) error {
+	_, err := col.NewSTM(ctx, a.etcdClient, func(stm col.STM) error {
+		chunks := a.chunks(jobID).ReadWrite(stm)
+		chunkState := &ChunkState{}
+		if err := chunks.Get(fmt.Sprint(high), chunkState); err != nil {
+			if col.IsErrNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		// A speculative attempt may have finished the chunk
+		if chunkState.State != State_RUNNING {
+			return nil
+		}
+		return chunks.Delete(fmt.Sprint(high))
+	})
+	return err
+}
+
+// runChunk processes the datums from low to high. If speculative execution is
+// enabled, another worker may be processing the same chunk, so runChunk stops
+// (without an error) if the other worker finishes it first.
+func (a *APIServer) runChunk(ctx context.Context, jobID string, low, high int64, logger *taggedLogger, process processFunc) error {
+	if a.pipelineInfo.SpeculationFactor <= 0 {
+		return a.processChunk(ctx, jobID, low, high, process)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var finishedElsewhere int32
+	go func() {
+		if err := a.chunks(jobID).ReadOnly(ctx).WatchOneF(fmt.Sprint(high), func(e *watch.Event) error {
+			if e.Type == watch.EventError {
+				return e.Err
+			}
+			if e.Type != watch.EventPut {
+				return nil
+			}
+			var key string
+			chunkState := &ChunkState{}
+			if err := e.Unmarshal(&key, chunkState); err != nil {
+				return err
+			}
+			if key != fmt.Sprint(high) || chunkState.State == State_RUNNING {
+				return nil
+			}
+			atomic.StoreInt32(&finishedElsewhere, 1)
+			cancel()
+			return errutil.ErrBreak
+		}); err != nil && ctx.Err() == nil {
+			logger.Logf("error watching chunk %d: %v", high, err)
+		}
+	}()
+	err := a.processChunk(ctx, jobID, low, high, process)
+	if err != nil && atomic.LoadInt32(&finishedElsewhere) == 1 {
+		logger.Logf("discarding attempt at chunk %d, which another worker finished first", high)
+		return nil
+	}
+	return err
+}
+
 func (a *APIServer) processChunk(ctx context.Context, jobID string, low, high int64, process processFunc) error {
-	processResult, err := process(low, high)
+	start := time.Now()
+	processResult, err := process(ctx, low, high)
 	if err != nil {
 		return err
 	}
 	if _, err := col.NewSTM(ctx, a.etcdClient, func(stm col.STM) error {
+		chunks := a.chunks(jobID).ReadWrite(stm)
+		// Another (speculative) attempt at this chunk may have finished first,
+		// in which case this attempt is discarded
+		chunkState := &ChunkState{}
+		if err := chunks.Get(fmt.Sprint(high), chunkState); err == nil && chunkState.State != State_RUNNING {
+			return nil
+		} else if err != nil && !col.IsErrNotFound(err) {
+			return err
+		}
+		// The chunk is done, so its journal isn't needed anymore
+		if err := a.chunkJournals(jobID).ReadWrite(stm).Delete(fmt.Sprint(high)); err != nil && !col.IsErrNotFound(err) {
+			return err
+		}
 		jobs := a.jobs.ReadWrite(stm)
 		jobPtr := &pps.EtcdJobInfo{}
 		if err := jobs.Update(jobID, jobPtr, func() error {
@@ -1204,22 +1883,37 @@ func (a *APIServer) processChunk(ctx context.Context, jobID string, low, high in
 			jobPtr.DataSkipped += processResult.datumsSkipped
 			jobPtr.DataRecovered += processResult.datumsRecovered
 			jobPtr.DataFailed += processResult.datumsFailed
+			for failureType, count := range processResult.failureCounts {
+				if jobPtr.FailureCounts == nil {
+					jobPtr.FailureCounts = make(map[int32]int64)
+				}
+				jobPtr.FailureCounts[failureType] += count
+			}
+			if processResult.stats != nil {
+				if jobPtr.Stats == nil {
+					jobPtr.Stats = &pps.ProcessStats{}
+				}
+				if err := mergeStats(jobPtr.Stats, processResult.stats); err != nil {
+					return fmt.Errorf("failed to merge Stats: %v", err)
+				}
+			}
 			return nil
 		}); err != nil {
 			return err
 		}
-		chunks := a.chunks(jobID).ReadWrite(stm)
 		if processResult.failedDatumID != "" {
 			return chunks.Put(fmt.Sprint(high), &ChunkState{
-				State:   State_FAILED,
-				DatumID: processResult.failedDatumID,
-				Address: os.Getenv(client.PPSWorkerIPEnv),
+				State:       State_FAILED,
+				DatumID:     processResult.failedDatumID,
+				FailureType: processResult.failureType,
+				Address:     os.Getenv(client.PPSWorkerIPEnv),
 			})
 		}
 		return chunks.Put(fmt.Sprint(high), &ChunkState{
 			State:           State_COMPLETE,
 			Address:         os.Getenv(client.PPSWorkerIPEnv),
 			RecoveredDatums: processResult.recoveredDatums,
+			ProcessTime:     types.DurationProto(time.Since(start)),
 		})
 	}); err != nil {
 		return err
@@ -1287,7 +1981,7 @@ func (a *APIServer) mergeDatums(jobCtx context.Context, pachClient *client.APICl
 			// get parent hashtree reader if it is being used
 			var parentHashtree, parentStatsHashtree io.Reader
 			if useParentHashTree {
-				r, err := a.getParentHashTree(ctx, pachClient, objClient, jobInfo.OutputCommit, a.shard)
+				r, err := a.getParentHashTree(ctx, pachClient, objClient, jobInfo.OutputCommit, jobInfo.Started, a.shard)
 				if err != nil {
 					return err
 				}
@@ -1299,7 +1993,7 @@ func (a *APIServer) mergeDatums(jobCtx context.Context, pachClient *client.APICl
 				parentHashtree = bufio.NewReaderSize(r, parentTreeBufSize)
 				// get parent stats hashtree reader if it is being used
 				if a.pipelineInfo.EnableStats {
-					r, err := a.getParentHashTree(ctx, pachClient, objClient, jobInfo.StatsCommit, a.shard)
+					r, err := a.getParentHashTree(ctx, pachClient, objClient, jobInfo.StatsCommit, jobInfo.Started, a.shard)
 					if err != nil {
 						return err
 					}
@@ -1323,17 +2017,20 @@ func (a *APIServer) mergeDatums(jobCtx context.Context, pachClient *client.APICl
 						logger.Logf("finished merging output after %v", time.Since(start))
 					}
 				}(time.Now())
+				progress := &mergeProgress{a: a, ctx: ctx, jobID: jobID, shard: a.shard, logger: logger, last: time.Now()}
 				if a.pipelineInfo.EnableStats {
-					statsTree, statsSize, err = a.merge(pachClient, objClient, true, parentStatsHashtree)
+					statsTree, statsSize, err = a.merge(ctx, pachClient, objClient, true, parentStatsHashtree, progress.update)
 					if err != nil {
 						return err
 					}
+					progress.finish()
 				}
 				if !failed {
-					tree, size, err = a.merge(pachClient, objClient, false, parentHashtree)
+					tree, size, err = a.merge(ctx, pachClient, objClient, false, parentHashtree, progress.update)
 					if err != nil {
 						return err
 					}
+					progress.finish()
 				}
 				return nil
 			}(); err != nil {
@@ -1424,12 +2121,14 @@ func (a *APIServer) computeTags(df DatumIterator, low, high int64, skip map[stri
 func (a *APIServer) getChunkFromObjectStorage(ctx context.Context, pachClient *client.APIClient, objClient obj.Client, tags []*pfs.Tag, id int64, failed bool) error {
 	// Download, merge, and cache datum hashtrees for a chunk if it succeeded
 	if !failed {
-		ts, err := a.getHashtrees(ctx, pachClient, objClient, tags, hashtree.NewFilter(a.numShards, a.shard))
+		ts, release, err := a.getHashtrees(ctx, pachClient, objClient, tags, hashtree.NewFilter(a.numShards, a.shard), false)
 		if err != nil {
 			return err
 		}
 		buf := &bytes.Buffer{}
-		if err := hashtree.Merge(hashtree.NewWriter(buf), ts); err != nil {
+		err = hashtree.Merge(hashtree.NewWriter(buf), ts)
+		release()
+		if err != nil {
 			return err
 		}
 		if err := a.chunkCache.Put(id, buf); err != nil {
@@ -1442,12 +2141,15 @@ func (a *APIServer) getChunkFromObjectStorage(ctx context.Context, pachClient *c
 		for _, tag := range tags {
 			statsTags = append(statsTags, client.NewTag(tag.Name+statsTagSuffix))
 		}
-		ts, err := a.getHashtrees(ctx, pachClient, objClient, statsTags, hashtree.NewFilter(a.numShards, a.shard))
+		// Datums that weren't sampled (see StatsSampleRate) have no stats
+		ts, release, err := a.getHashtrees(ctx, pachClient, objClient, statsTags, hashtree.NewFilter(a.numShards, a.shard), true)
 		if err != nil {
 			return err
 		}
 		buf := &bytes.Buffer{}
-		if err := hashtree.Merge(hashtree.NewWriter(buf), ts); err != nil {
+		err = hashtree.Merge(hashtree.NewWriter(buf), ts)
+		release()
+		if err != nil {
 			return err
 		}
 		if err := a.chunkStatsCache.Put(id, buf); err != nil {
@@ -1457,7 +2159,65 @@ func (a *APIServer) getChunkFromObjectStorage(ctx context.Context, pachClient *c
 	return nil
 }
 
-func (a *APIServer) merge(pachClient *client.APIClient, objClient obj.Client, stats bool, parent io.Reader) (*pfs.Object, uint64, error) {
+// mergeProgress periodically records how many nodes a shard's merges have
+// written in its job's ShardNodesMerged, so that the progress shows up in
+// InspectJob. A new mergeProgress is used for each attempt at merging a
+// shard, and it overwrites the shard's count rather than adding to it, so
+// retried merges aren't counted twice.
+type mergeProgress struct {
+	a      *APIServer
+	ctx    context.Context
+	jobID  string
+	shard  int64
+	logger *taggedLogger
+	// merged is the number of nodes written by the merges that have already
+	// finished, nodes also includes the current merge
+	merged   int64
+	nodes    int64
+	reported int64
+	last     time.Time
+}
+
+func (p *mergeProgress) update(nodes int64) error {
+	p.nodes = p.merged + nodes
+	if time.Since(p.last) >= mergeProgressInterval {
+		p.flush()
+	}
+	return nil
+}
+
+// finish is called after each of the shard's merges (output and stats)
+// completes, so that the next merge's count is added to it.
+func (p *mergeProgress) finish() {
+	p.merged = p.nodes
+	p.flush()
+}
+
+// flush writes the shard's progress to etcd. Errors are only logged, failing
+// to report progress shouldn't fail the merge.
+func (p *mergeProgress) flush() {
+	p.last = time.Now()
+	if p.nodes == p.reported {
+		return
+	}
+	if _, err := col.NewSTM(p.ctx, p.a.etcdClient, func(stm col.STM) error {
+		jobs := p.a.jobs.ReadWrite(stm)
+		jobPtr := &pps.EtcdJobInfo{}
+		return jobs.Update(p.jobID, jobPtr, func() error {
+			if jobPtr.ShardNodesMerged == nil {
+				jobPtr.ShardNodesMerged = make(map[int64]int64)
+			}
+			jobPtr.ShardNodesMerged[p.shard] = p.nodes
+			return nil
+		})
+	}); err != nil {
+		p.logger.Logf("error updating merge progress: %v", err)
+		return
+	}
+	p.reported = p.nodes
+}
+
+func (a *APIServer) merge(ctx context.Context, pachClient *client.APIClient, objClient obj.Client, stats bool, parent io.Reader, progress hashtree.MergeProgress) (*pfs.Object, uint64, error) {
 	var tree *pfs.Object
 	var size uint64
 	if err := func() (retErr error) {
@@ -1465,26 +2225,41 @@ func (a *APIServer) merge(pachClient *client.APIClient, objClient obj.Client, st
 		if err != nil {
 			return err
 		}
-		w := hashtree.NewWriter(objW)
+		// The merged tree is also written to the tree cache, as it's the
+		// parent tree of the next job
+		cacheW, err := a.treeCache.create()
+		if err != nil {
+			objW.Close()
+			return err
+		}
+		w := hashtree.NewWriter(io.MultiWriter(objW, cacheW))
 		filter := hashtree.NewFilter(a.numShards, a.shard)
 		if stats {
-			err = a.chunkStatsCache.Merge(w, parent, filter)
+			err = a.chunkStatsCache.MergeWithProgress(ctx, w, parent, filter, progress)
 		} else {
-			err = a.chunkCache.Merge(w, parent, filter)
+			err = a.chunkCache.MergeWithProgress(ctx, w, parent, filter, progress)
 		}
 		size = w.Size()
 		if err != nil {
 			objW.Close()
+			cacheW.abort()
 			return err
 		}
 		// Get object hash for hashtree
 		if err := objW.Close(); err != nil {
+			cacheW.abort()
 			return err
 		}
 		tree, err = objW.Object()
 		if err != nil {
+			cacheW.abort()
 			return err
 		}
+		if ref, err := cacheW.commit(tree.Hash); err != nil {
+			a.getWorkerLogger().Logf("error caching merged hashtree %s: %v", tree.Hash, err)
+		} else {
+			ref.Close()
+		}
 		// Get index and write it out
 		idx, err := w.Index()
 		if err != nil {
@@ -1497,7 +2272,14 @@ func (a *APIServer) merge(pachClient *client.APIClient, objClient obj.Client, st
 	return tree, size, nil
 }
 
-func (a *APIServer) getParentCommitInfo(ctx context.Context, pachClient *client.APIClient, commit *pfs.Commit) (*pfs.CommitInfo, error) {
+// getParentCommitInfo returns the nearest ancestor of 'commit' (the output or
+// stats commit of a job that started at 'started') that has data, which the
+// job builds on, or nil if there isn't one. If the pipeline's jobs wait for
+// their parents (see waitsForParentJobs), it blocks until each ancestor is
+// finished. Otherwise, it skips the ancestors that weren't finished when the
+// job started, so that the job doesn't wait for jobs that may be queued
+// behind it, and all of its workers find the same ancestor.
+func (a *APIServer) getParentCommitInfo(ctx context.Context, pachClient *client.APIClient, commit *pfs.Commit, started *types.Timestamp) (*pfs.CommitInfo, error) {
 	outputCommitID := commit.ID
 	commitInfo, err := pachClient.PfsAPIClient.InspectCommit(ctx,
 		&pfs.InspectCommitRequest{
@@ -1506,18 +2288,19 @@ func (a *APIServer) getParentCommitInfo(ctx context.Context, pachClient *client.
 	if err != nil {
 		return nil, err
 	}
+	wait := a.waitsForParentJobs()
 	for commitInfo.ParentCommit != nil {
-		a.getWorkerLogger().Logf("blocking on parent commit %q before writing to output commit %q",
-			commitInfo.ParentCommit.ID, outputCommitID)
-		parentCommitInfo, err := pachClient.PfsAPIClient.InspectCommit(ctx,
-			&pfs.InspectCommitRequest{
-				Commit:     commitInfo.ParentCommit,
-				BlockState: pfs.CommitState_FINISHED,
-			})
+		request := &pfs.InspectCommitRequest{Commit: commitInfo.ParentCommit}
+		if wait {
+			a.getWorkerLogger().Logf("blocking on parent commit %q before writing to output commit %q",
+				commitInfo.ParentCommit.ID, outputCommitID)
+			request.BlockState = pfs.CommitState_FINISHED
+		}
+		parentCommitInfo, err := pachClient.PfsAPIClient.InspectCommit(ctx, request)
 		if err != nil {
 			return nil, err
 		}
-		if parentCommitInfo.Trees != nil {
+		if parentCommitInfo.Trees != nil && (wait || finishedBefore(parentCommitInfo, started)) {
 			return parentCommitInfo, nil
 		}
 		commitInfo = parentCommitInfo
@@ -1525,57 +2308,64 @@ func (a *APIServer) getParentCommitInfo(ctx context.Context, pachClient *client.
 	return nil, nil
 }
 
-func (a *APIServer) getHashtrees(ctx context.Context, pachClient *client.APIClient, objClient obj.Client, tags []*pfs.Tag, filter hashtree.Filter) ([]*hashtree.Reader, error) {
+// finishedBefore returns true if the commit in 'commitInfo' was finished
+// before 't'
+func finishedBefore(commitInfo *pfs.CommitInfo, t *types.Timestamp) bool {
+	if commitInfo.Finished == nil || t == nil {
+		return false
+	}
+	return commitInfo.Finished.Compare(t) < 0
+}
+
+// getHashtrees reads the datum hashtrees tagged with 'tags' through the tree
+// cache, filtered by 'filter'. If 'skipMissing' is set, tags that don't exist
+// are skipped. The readers may only be used until 'release' is called.
+func (a *APIServer) getHashtrees(ctx context.Context, pachClient *client.APIClient, objClient obj.Client, tags []*pfs.Tag, filter hashtree.Filter, skipMissing bool) (_ []*hashtree.Reader, release func(), retErr error) {
 	limiter := limit.New(hashtree.DefaultMergeConcurrency)
 	var eg errgroup.Group
 	var mu sync.Mutex
 	var rs []*hashtree.Reader
+	var refs []*treeRef
+	release = func() {
+		for _, ref := range refs {
+			ref.Close()
+		}
+	}
+	defer func() {
+		if retErr != nil {
+			release()
+		}
+	}()
 	for _, tag := range tags {
 		tag := tag
 		limiter.Acquire()
-		eg.Go(func() (retErr error) {
+		eg.Go(func() error {
 			defer limiter.Release()
 			// Get datum hashtree info
 			info, err := pachClient.InspectTag(ctx, tag)
 			if err != nil {
-				return err
-			}
-			path, err := obj.BlockPathFromEnv(info.BlockRef.Block)
-			if err != nil {
-				return err
-			}
-			// Read the full datum hashtree in memory
-			objR, err := objClient.Reader(ctx, path, 0, 0)
-			if err != nil {
-				return err
-			}
-			defer func() {
-				if err := objR.Close(); err != nil && retErr == nil {
-					retErr = err
+				if skipMissing && errutil.IsNotFoundError(err) {
+					return nil
 				}
-			}()
-			fullTree, err := ioutil.ReadAll(objR)
-			if err != nil {
 				return err
 			}
-			// Filter out unecessary keys
-			filteredTree := &bytes.Buffer{}
-			w := hashtree.NewWriter(filteredTree)
-			r := hashtree.NewReader(bytes.NewBuffer(fullTree), filter)
-			if err := w.Copy(r); err != nil {
+			ref, err := a.getCachedTree(ctx, objClient, info)
+			if err != nil {
 				return err
 			}
-			// Add it to the list of readers
+			// Add it to the list of readers, the filter drops unnecessary keys
+			// as the tree is read
 			mu.Lock()
 			defer mu.Unlock()
-			rs = append(rs, hashtree.NewReader(filteredTree, nil))
+			refs = append(refs, ref)
+			rs = append(rs, hashtree.NewReader(ref.Reader(), filter))
 			return nil
 		})
 	}
 	if err := eg.Wait(); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return rs, nil
+	return rs, release, nil
 }
 
 func (a *APIServer) getDatumMap(ctx context.Context, pachClient *client.APIClient, object *pfs.Object) (_ map[string]bool, retErr error) {
@@ -1606,8 +2396,8 @@ func (a *APIServer) getDatumMap(ctx context.Context, pachClient *client.APIClien
 	return datums, nil
 }
 
-func (a *APIServer) getParentHashTree(ctx context.Context, pachClient *client.APIClient, objClient obj.Client, commit *pfs.Commit, merge int64) (io.ReadCloser, error) {
-	parentCommitInfo, err := a.getParentCommitInfo(ctx, pachClient, commit)
+func (a *APIServer) getParentHashTree(ctx context.Context, pachClient *client.APIClient, objClient obj.Client, commit *pfs.Commit, started *types.Timestamp, merge int64) (io.ReadCloser, error) {
+	parentCommitInfo, err := a.getParentCommitInfo(ctx, pachClient, commit, started)
 	if err != nil {
 		return nil, err
 	}
@@ -1618,11 +2408,14 @@ func (a *APIServer) getParentHashTree(ctx context.Context, pachClient *client.AP
 	if err != nil {
 		return nil, err
 	}
-	path, err := obj.BlockPathFromEnv(info.BlockRef.Block)
+	ref, err := a.getCachedTree(ctx, objClient, info)
 	if err != nil {
 		return nil, err
 	}
-	return objClient.Reader(ctx, path, 0, 0)
+	return struct {
+		io.Reader
+		io.Closer
+	}{ref.Reader(), ref}, nil
 }
 
 func writeIndex(pachClient *client.APIClient, objClient obj.Client, tree *pfs.Object, idx []byte) (retErr error) {
@@ -1659,7 +2452,7 @@ func isDone(ctx context.Context) bool {
 // cancelCtxIfJobFails watches jobID's JobPtr, and if its state is changed to a
 // terminal state (KILLED, FAILED, or SUCCESS) cancel the jobCtx so we kill any
 // user processes
-func (a *APIServer) cancelCtxIfJobFails(jobCtx context.Context, jobCancel func(), jobID string) {
+func (a *APIServer) cancelCtxIfJobFails(jobCtx context.Context, jobCancel func(), jobID string, pause *jobPause) {
 	logger := a.getWorkerLogger() // this worker's formatting logger
 
 	backoff.RetryNotify(func() error {
@@ -1694,6 +2487,7 @@ func (a *APIServer) cancelCtxIfJobFails(jobCtx context.Context, jobCancel func()
 						logger.Logf("job %q put in terminal state %q; cancelling", jobID, jobPtr.State)
 						jobCancel() // cancel the job
 					}
+					pause.set(jobPtr.Paused)
 				case watch.EventDelete:
 					logger.Logf("job %q deleted; cancelling", jobID)
 					jobCancel() // cancel the job
@@ -1721,11 +2515,15 @@ func (a *APIServer) cancelCtxIfJobFails(jobCtx context.Context, jobCancel func()
 //  - claims those chunks with acquireDatums
 //  - processes the chunks with processDatums
 //  - merges the chunks with mergeDatums
+// If the pipeline has merge workers (see pps.MergeSpec), they only claim
+// shards and merge, and the other workers only claim and process chunks.
 func (a *APIServer) worker() {
 	logger := a.getWorkerLogger() // this worker's formatting logger
 
 	// claim a shard if one is available or becomes available
-	go a.claimShard(a.pachClient.Ctx())
+	if a.mergesDatums() {
+		go a.claimShard(a.pachClient.Ctx())
+	}
 
 	// Process incoming jobs
 	backoff.RetryNotify(func() (retErr error) {
@@ -1778,7 +2576,8 @@ func (a *APIServer) worker() {
 				// the EtcdJobInfo is marked 'FAILED', call jobCancel().
 				// ('watcher' above can't detect job state changes--it's watching
 				// an index and so only emits when jobs are created or deleted).
-				go a.cancelCtxIfJobFails(jobCtx, jobCancel, jobID)
+				pause := newJobPause()
+				go a.cancelCtxIfJobFails(jobCtx, jobCancel, jobID, pause)
 
 				// Inspect the job and make sure it's relevant, as this worker may be old
 				jobInfo, err := pachClient.InspectJob(jobID, false)
@@ -1808,11 +2607,12 @@ func (a *APIServer) worker() {
 				if err != nil {
 					return fmt.Errorf("error from NewDatumFactory: %v", err)
 				}
+				df = orderDatums(df, jobInfo.ChunkSpec, jobInfo.Job.ID, a.DatumID)
 
 				// Compute the datums to skip
 				skip := make(map[string]bool)
 				var useParentHashTree bool
-				parentCommitInfo, err := a.getParentCommitInfo(jobCtx, pachClient, jobInfo.OutputCommit)
+				parentCommitInfo, err := a.getParentCommitInfo(jobCtx, pachClient, jobInfo.OutputCommit, jobInfo.Started)
 				if err != nil {
 					return err
 				}
@@ -1840,26 +2640,31 @@ func (a *APIServer) worker() {
 				if err != nil {
 					return err
 				}
+				pause.set(jobInfo.Paused)
 				eg, ctx := errgroup.WithContext(jobCtx)
 				// If a datum fails, acquireDatums updates the relevant lock in
 				// etcd, which causes the master to fail the job (which is
 				// handled above in the JOB_FAILURE case). There's no need to
 				// handle failed datums here, just failed etcd writes.
-				eg.Go(func() error {
-					return a.acquireDatums(
-						ctx, jobID, plan, logger,
-						func(low, high int64) (*processResult, error) {
-							processResult, err := a.processDatums(pachClient, logger, jobInfo, df, low, high, skip, useParentHashTree)
-							if err != nil {
-								return nil, err
-							}
-							return processResult, nil
-						},
-					)
-				})
-				eg.Go(func() error {
-					return a.mergeDatums(ctx, pachClient, jobInfo, jobID, plan, logger, df, skip, useParentHashTree)
-				})
+				if !a.mergeWorker {
+					eg.Go(func() error {
+						return a.acquireDatums(
+							ctx, jobID, plan, logger, pause,
+							func(ctx context.Context, low, high int64) (*processResult, error) {
+								processResult, err := a.processDatums(pachClient.WithCtx(ctx), logger, jobInfo, df, low, high, skip, useParentHashTree, pause)
+								if err != nil {
+									return nil, err
+								}
+								return processResult, nil
+							},
+						)
+					})
+				}
+				if a.mergesDatums() {
+					eg.Go(func() error {
+						return a.mergeDatums(ctx, pachClient, jobInfo, jobID, plan, logger, df, skip, useParentHashTree)
+					})
+				}
 				if err := eg.Wait(); err != nil {
 					if jobCtx.Err() == context.Canceled {
 						return nil
@@ -1878,6 +2683,12 @@ func (a *APIServer) worker() {
 	})
 }
 
+// mergesDatums returns true if this worker merges the datums' hashtrees. If
+// the pipeline has merge workers, only they do.
+func (a *APIServer) mergesDatums() bool {
+	return a.mergeWorker || a.pipelineInfo.MergeSpec == nil
+}
+
 func (a *APIServer) claimShard(ctx context.Context) {
 	watcher, err := a.shards.ReadOnly(ctx).Watch(watch.WithFilterPut())
 	if err != nil {
@@ -1916,7 +2727,7 @@ func (a *APIServer) claimShard(ctx context.Context) {
 // returns the id of the failed datum it also may return a variety of errors
 // such as network errors.
 func (a *APIServer) processDatums(pachClient *client.APIClient, logger *taggedLogger, jobInfo *pps.JobInfo,
-	df DatumIterator, low, high int64, skip map[string]bool, useParentHashTree bool) (result *processResult, retErr error) {
+	df DatumIterator, low, high int64, skip map[string]bool, useParentHashTree bool, pause *jobPause) (result *processResult, retErr error) {
 	defer func() {
 		if err := a.datumCache.Clear(); err != nil && retErr == nil {
 			logger.Logf("error clearing datum cache: %v", err)
@@ -1932,27 +2743,99 @@ func (a *APIServer) processDatums(pachClient *client.APIClient, logger *taggedLo
 	}
 	stats := &pps.ProcessStats{}
 	var statsMu sync.Mutex
-	result = &processResult{}
+	result = &processResult{failureCounts: make(map[int32]int64)}
 	var eg errgroup.Group
 	limiter := limit.New(int(a.pipelineInfo.MaxQueueSize))
 	var recoveredDatums []string
 	var recoverMu sync.Mutex
-	for i := low; i < high; i++ {
+	var failureMu sync.Mutex
+	// Every try of a datum holds aloneMu for reading, except for the tries of
+	// datums that were OOM-killed (if retry_oom_datums is set), which hold it
+	// for writing so that they run alone
+	var aloneMu sync.RWMutex
+	// Datums in the queue download their inputs while another datum's user
+	// code runs, prefetch bounds how much data they can download ahead of time
+	prefetch := newPrefetchBudget(a.prefetchBytes)
+	// The output is written in the storage class of the output repo
+	outputClass := pfs.StorageClass_STANDARD
+	outputRepoInfo, err := pachClient.InspectRepo(jobInfo.OutputRepo.Name)
+	if err != nil {
+		return nil, err
+	}
+	if outputRepoInfo.StoragePolicy != nil {
+		outputClass = outputRepoInfo.StoragePolicy.StorageClass
+	}
+	var prevCommit *pfs.Commit
+	if a.pipelineInfo.PreviousOutput {
+		parentCommitInfo, err := a.getParentCommitInfo(ctx, pachClient, jobInfo.OutputCommit, jobInfo.Started)
+		if err != nil {
+			return nil, err
+		}
+		if parentCommitInfo != nil {
+			prevCommit = parentCommitInfo.Commit
+		}
+	}
+	// Resume the chunk after the datums that a previous attempt at it
+	// journaled, if any
+	journal, err := a.resumeChunk(pachClient, logger, jobInfo.Job.ID, low, high, skip, useParentHashTree)
+	if err != nil {
+		return nil, err
+	}
+	result.datumsSkipped += journal.state.CompletedSkipped
+	defer func() {
+		// Journal the datums that finished since the last flush, so that they
+		// aren't processed again when the chunk is retried (a chunk that
+		// finishes doesn't need its journal)
+		if retErr == nil {
+			return
+		}
+		if state := journal.flush(); state != nil {
+			if err := a.writeChunkJournal(ctx, jobInfo.Job.ID, high, state); err != nil {
+				logger.Logf("error journaling chunk %d: %v", high, err)
+			}
+		}
+	}()
+	var paused bool
+	for i := journal.next(); i < high; i++ {
 		datumIdx := i
 
 		limiter.Acquire()
+		// If the job was paused, don't start any more datums, but let the ones
+		// that are running finish
+		if pause.isPaused() {
+			limiter.Release()
+			paused = true
+			break
+		}
 		atomic.AddInt64(&a.queueSize, 1)
 		eg.Go(func() (retErr error) {
 			defer limiter.Release()
 			defer atomic.AddInt64(&a.queueSize, -1)
 
 			data := df.DatumN(int(datumIdx))
-			logger, err := a.getTaggedLogger(pachClient, jobInfo.Job.ID, data, a.pipelineInfo.EnableStats)
+			// Hash inputs
+			tag := HashDatum(a.pipelineInfo.Pipeline.Name, a.pipelineInfo.Salt, data)
+			// Only the stats of sampled datums (and of failed datums, whose
+			// logs are held until they finish) are written
+			sampled := statsSampled(tag, a.pipelineInfo.StatsSampleRate)
+			logger, err := a.getTaggedLogger(pachClient, jobInfo.Job.ID, data, a.pipelineInfo.EnableStats && sampled)
 			if err != nil {
 				return err
 			}
-			// Hash inputs
-			tag := HashDatum(a.pipelineInfo.Pipeline.Name, a.pipelineInfo.Salt, data)
+			// Datums that are done are journaled once their output (and
+			// stats) have been written. The journal is written in batches,
+			// see chunkJournal.complete.
+			var completed, skipped bool
+			defer func() {
+				if retErr != nil || !completed {
+					return
+				}
+				if state := journal.complete(datumIdx, tag, skipped); state != nil {
+					if err := a.writeChunkJournal(ctx, jobInfo.Job.ID, high, state); err != nil {
+						logger.Logf("error journaling chunk %d: %v", high, err)
+					}
+				}
+			}()
 			if skip[tag] {
 				if !useParentHashTree {
 					if err := a.cacheHashtree(pachClient, tag, datumIdx); err != nil {
@@ -1961,6 +2844,7 @@ func (a *APIServer) processDatums(pachClient *client.APIClient, logger *taggedLo
 				}
 				atomic.AddInt64(&result.datumsSkipped, 1)
 				logger.Logf("skipping datum")
+				completed, skipped = true, true
 				return nil
 			}
 			if _, err := pachClient.InspectTag(ctx, client.NewTag(tag)); err == nil {
@@ -1969,11 +2853,28 @@ func (a *APIServer) processDatums(pachClient *client.APIClient, logger *taggedLo
 				}
 				atomic.AddInt64(&result.datumsSkipped, 1)
 				logger.Logf("skipping datum")
+				completed, skipped = true, true
 				return nil
 			}
+			if a.pipelineInfo.EnableStats && !sampled {
+				logger.holdLogs()
+			}
+			// The output of the datum's last failed try is stashed, and its
+			// user code output collected, in case the datum is quarantined
+			var stash string
+			if a.pipelineInfo.Quarantine != nil {
+				logger.userOutput = &userOutput{}
+				stash = filepath.Join(client.PPSInputPrefix, client.PPSScratchSpace, "quarantine-"+uuid.NewWithoutDashes())
+				defer func() {
+					if err := os.RemoveAll(stash); err != nil && retErr == nil {
+						retErr = err
+					}
+				}()
+			}
 			subStats := &pps.ProcessStats{}
 			var inputTree, outputTree *hashtree.Ordered
 			var statsTree *hashtree.Unordered
+			var failed bool
 			if a.pipelineInfo.EnableStats {
 				statsRoot := path.Join("/", logger.template.DatumID)
 				inputTree = hashtree.NewOrdered(path.Join(statsRoot, "pfs"))
@@ -1981,39 +2882,57 @@ func (a *APIServer) processDatums(pachClient *client.APIClient, logger *taggedLo
 				statsTree = hashtree.NewUnordered(statsRoot)
 				// Write job id to stats tree
 				statsTree.PutFile(fmt.Sprintf("job:%s", jobInfo.Job.ID), nil, 0)
-				// Write index in datum factory to stats tree
-				object, size, err := pachClient.PutObject(strings.NewReader(fmt.Sprint(int(datumIdx))))
-				if err != nil {
-					return err
-				}
-				objectInfo, err := pachClient.InspectObject(object.Hash)
-				if err != nil {
-					return err
-				}
-				h, err := pfs.DecodeHash(object.Hash)
-				if err != nil {
-					return err
-				}
-				statsTree.PutFile("index", h, size, objectInfo.BlockRef)
 				defer func() {
+					if !sampled && !failed {
+						if _, _, err := logger.Close(); err != nil && retErr == nil {
+							retErr = err
+						}
+						return
+					}
 					if err := a.writeStats(pachClient, objClient, tag, subStats, logger, inputTree, outputTree, statsTree, datumIdx); err != nil && retErr == nil {
 						retErr = err
 					}
 				}()
 			}
 
-			env := a.userCodeEnv(jobInfo.Job.ID, jobInfo.OutputCommit.ID, data)
+			env := a.userCodeEnv(jobInfo.Job.ID, jobInfo.OutputCommit.ID, tag, data)
 			var dir string
 			var failures int64
+			var alone bool
+			landed := newLandedOutput()
 			if err := backoff.RetryNotify(func() error {
 				if isDone(ctx) {
 					return ctx.Err() // timeout or cancelled job--don't run datum
 				}
+				if stash != "" {
+					// a previous try's output is stale
+					if err := os.RemoveAll(stash); err != nil {
+						return err
+					}
+				}
+				if alone {
+					aloneMu.Lock()
+					defer aloneMu.Unlock()
+				} else {
+					aloneMu.RLock()
+					defer aloneMu.RUnlock()
+				}
 				// Download input data
-				puller := filesync.NewPuller()
+				release, err := prefetch.acquire(ctx, datumSize(data))
+				if err != nil {
+					return err
+				}
+				defer release()
+				// The download fails (and the datum is retried) if it takes
+				// longer than the download timeout
+				downloadClient, timer, err := newDownloadTimer(pachClient, jobInfo.DownloadTimeout)
+				if err != nil {
+					return err
+				}
+				defer timer.close()
 				// TODO parent tag shouldn't be nil
-				var err error
-				dir, err = a.downloadData(pachClient, logger, data, puller, subStats, inputTree)
+				var puller *filesync.Puller
+				dir, puller, err = a.downloadDataWithFailover(downloadClient, logger, data, prevCommit, subStats, inputTree)
 				// We run these cleanup functions no matter what, so that if
 				// downloadData partially succeeded, we still clean up the resources.
 				defer func() {
@@ -2028,12 +2947,33 @@ func (a *APIServer) processDatums(pachClient *client.APIClient, logger *taggedLo
 					if _, err := puller.CleanUp(); err != nil && retErr == nil {
 						retErr = err
 					}
+					// Record the lazy files of the last attempt, even if it
+					// failed, as stalled reads may be why
+					subStats.LazyFiles = lazyFileStats(dir, puller)
 				}()
 				if err != nil {
-					return fmt.Errorf("error downloadData: %v", err)
+					if timeoutErr := timer.stop(); timeoutErr != nil {
+						err = timeoutErr
+					}
+					return classify(pps.FailureType_DOWNLOAD_ERROR, fmt.Errorf("error downloadData: %v", err))
+				}
+				var jobScratch map[string]jobScratchFile
+				if a.pipelineInfo.JobScratch {
+					if jobScratch, err = a.downloadJobScratch(downloadClient, puller, dir, jobInfo.Job.ID); err != nil {
+						if timeoutErr := timer.stop(); timeoutErr != nil {
+							err = timeoutErr
+						}
+						return classify(pps.FailureType_DOWNLOAD_ERROR, fmt.Errorf("error downloadJobScratch: %v", err))
+					}
+				}
+				if err := timer.stop(); err != nil {
+					return classify(pps.FailureType_DOWNLOAD_ERROR, err)
 				}
 				a.runMu.Lock()
 				defer a.runMu.Unlock()
+				// This datum's inputs stop counting against the prefetch budget
+				// once its user code gets to run
+				release()
 				// shadow ctx and pachClient for the context of processing this one datum
 				ctx, cancel := context.WithCancel(ctx)
 				pachClient := pachClient.WithCtx(ctx)
@@ -2059,22 +2999,39 @@ func (a *APIServer) processDatums(pachClient *client.APIClient, logger *taggedLo
 				}()
 				// If the pipeline spec set a custom user to execute the
 				// process, make sure `/pfs` and its content are owned by it
-				if a.uid != nil && a.gid != nil {
-					filepath.Walk("/pfs", func(name string, info os.FileInfo, err error) error {
-						if err == nil {
-							err = os.Chown(name, int(*a.uid), int(*a.gid))
-						}
-						return err
-					})
+				// (and have the modes requested by the transform). The inputs
+				// and /pfs/out are symlinks into 'dir', so walk it as well.
+				if (a.uid != nil && a.gid != nil) || a.dirMode != nil || a.fileMode != nil {
+					a.setUserPermissions(client.PPSInputPrefix)
+					a.setUserPermissions(dir)
 				}
-				if err := a.runUserCode(ctx, logger, env, subStats, jobInfo.DatumTimeout); err != nil {
+				timeout, err := datumTimeout(jobInfo, data)
+				if err != nil {
+					return err
+				}
+				if timeout, err = a.config.scaleDatumTimeout(timeout); err != nil {
+					return err
+				}
+				stopInputWatch := a.watchInputWrites(logger, dir)
+				err = a.runUserCode(ctx, logger, env, subStats, timeout)
+				inputWriteErr := stopInputWatch()
+				a.evictCache(logger)
+				if err != nil {
 					if a.pipelineInfo.Transform.ErrCmd != nil && failures == jobInfo.DatumTries-1 {
-						if err = a.runUserErrorHandlingCode(ctx, logger, env, subStats, jobInfo.DatumTimeout); err != nil {
+						if err = a.runUserErrorHandlingCode(ctx, logger, env, subStats, timeout); err != nil {
 							return fmt.Errorf("error runUserErrorHandlingCode: %v", err)
 						}
 						return errDatumRecovered
 					}
-					return fmt.Errorf("error runUserCode: %v", err)
+					if stash != "" {
+						if err := stashOutput(dir, stash); err != nil {
+							logger.Logf("error stashing the output of the failed datum: %v", err)
+						}
+					}
+					return classify(failureType(err), fmt.Errorf("error runUserCode: %v", err))
+				}
+				if inputWriteErr != nil {
+					return inputWriteErr
 				}
 				// CleanUp is idempotent so we can call it however many times we want.
 				// The reason we are calling it here is that the puller could've
@@ -2085,17 +3042,36 @@ func (a *APIServer) processDatums(pachClient *client.APIClient, logger *taggedLo
 				downSize, err := puller.CleanUp()
 				if err != nil {
 					logger.Logf("puller encountered an error while cleaning up: %+v", err)
-					return err
+					return classify(pps.FailureType_DOWNLOAD_ERROR, err)
 				}
 				atomic.AddUint64(&subStats.DownloadBytes, uint64(downSize))
 				a.reportDownloadSizeStats(float64(downSize), logger)
-				return a.uploadOutput(pachClient, dir, tag, logger, data, subStats, outputTree, datumIdx)
+				if err := a.uploadOutput(pachClient, dir, tag, logger, data, subStats, outputTree, datumIdx, landed, outputClass); err != nil {
+					return classify(pps.FailureType_UPLOAD_ERROR, err)
+				}
+				if a.pipelineInfo.JobScratch {
+					if err := a.uploadJobScratch(pachClient, dir, jobInfo.Job.ID, jobScratch); err != nil {
+						return classify(pps.FailureType_UPLOAD_ERROR, fmt.Errorf("error uploadJobScratch: %v", err))
+					}
+				}
+				return nil
 			}, &backoff.ZeroBackOff{}, func(err error, d time.Duration) error {
 				if isDone(ctx) {
 					return ctx.Err() // timeout or cancelled job, err out and don't retry
 				}
 				failures++
+				if a.pipelineInfo.RetryOomDatums && !alone && failureType(err) == pps.FailureType_OOM_KILLED {
+					// Give the datum at least one more try, with all of the
+					// worker's memory to itself
+					alone = true
+					if failures >= jobInfo.DatumTries {
+						failures = jobInfo.DatumTries - 1
+					}
+					logger.Logf("datum was killed by the OOM killer, retrying it alone on this worker")
+					return nil
+				}
 				if failures >= jobInfo.DatumTries {
+					err = a.annotateFailure(err)
 					logger.Logf("failed to process datum with error: %+v", err)
 					if statsTree != nil {
 						object, size, err := pachClient.PutObject(strings.NewReader(err.Error()))
@@ -2113,11 +3089,19 @@ func (a *APIServer) processDatums(pachClient *client.APIClient, logger *taggedLo
 							statsTree.PutFile("failure", h, size, objectInfo.BlockRef)
 						}
 					}
+					if a.pipelineInfo.Quarantine != nil {
+						if err := a.quarantineDatum(pachClient, logger, data, stash, err); err != nil {
+							logger.Logf("error copying the failed datum to quarantine repo %s: %v", a.pipelineInfo.Quarantine.Repo, err)
+						} else {
+							logger.Logf("copied the failed datum to quarantine repo %s", a.pipelineInfo.Quarantine.Repo)
+						}
+					}
 					return err
 				}
 				logger.Logf("failed processing datum: %v, retrying in %v", err, d)
 				return nil
 			}); err == errDatumRecovered {
+				failed = true
 				// keep track of the recovered datums
 				recoverMu.Lock()
 				defer recoverMu.Unlock()
@@ -2125,10 +3109,16 @@ func (a *APIServer) processDatums(pachClient *client.APIClient, logger *taggedLo
 				atomic.AddInt64(&result.datumsRecovered, 1)
 				return nil
 			} else if err != nil {
+				failed = true
+				failureMu.Lock()
+				defer failureMu.Unlock()
 				result.failedDatumID = a.DatumID(data)
+				result.failureType = failureType(err)
+				result.failureCounts[int32(result.failureType)]++
 				atomic.AddInt64(&result.datumsFailed, 1)
 				return nil
 			}
+			completed = true
 			statsMu.Lock()
 			defer statsMu.Unlock()
 			if err := mergeStats(stats, subStats); err != nil {
@@ -2140,6 +3130,12 @@ func (a *APIServer) processDatums(pachClient *client.APIClient, logger *taggedLo
 	if err := eg.Wait(); err != nil {
 		return nil, err
 	}
+	if paused {
+		// The output of the datums that finished is uploaded and tagged, so
+		// they're skipped when the rest of the chunk is processed
+		logger.Logf("job paused, stopped processing datums %d to %d", low, high)
+		return nil, errJobPaused
+	}
 
 	// put the list of recovered datums in a pfs.Object, and save it as part of the result
 	if len(recoveredDatums) > 0 {
@@ -2158,23 +3154,9 @@ func (a *APIServer) processDatums(pachClient *client.APIClient, logger *taggedLo
 		result.recoveredDatums = recoveredDatumsObj
 	}
 
-	if _, err := col.NewSTM(ctx, a.etcdClient, func(stm col.STM) error {
-		jobs := a.jobs.ReadWrite(stm)
-		jobID := jobInfo.Job.ID
-		jobPtr := &pps.EtcdJobInfo{}
-		if err := jobs.Get(jobID, jobPtr); err != nil {
-			return err
-		}
-		if jobPtr.Stats == nil {
-			jobPtr.Stats = &pps.ProcessStats{}
-		}
-		if err := mergeStats(jobPtr.Stats, stats); err != nil {
-			logger.Logf("failed to merge Stats: %v", err)
-		}
-		return jobs.Put(jobID, jobPtr)
-	}); err != nil {
-		return nil, err
-	}
+	// The stats are written along with the chunk's completion (see
+	// processChunk), rather than in a write of their own
+	result.stats = stats
 	result.datumsProcessed = high - low - result.datumsSkipped - result.datumsFailed - result.datumsRecovered
 	// Merge datum hashtrees into a chunk hashtree, then cache it.
 	if err := a.mergeChunk(logger, high, result); err != nil {
@@ -2183,28 +3165,182 @@ func (a *APIServer) processDatums(pachClient *client.APIClient, logger *taggedLo
 	return result, nil
 }
 
+// datumSize returns the total size of the input files in 'data' that are
+// downloaded before the datum's user code runs. Lazy and empty_files inputs
+// aren't downloaded up front, so they aren't counted.
+func datumSize(data []*Input) int64 {
+	var size int64
+	for _, input := range data {
+		if input.Lazy || input.EmptyFiles {
+			continue
+		}
+		size += int64(input.FileInfo.SizeBytes)
+	}
+	return size
+}
+
+// datumTimeout returns the timeout of the user code processing 'data': the
+// job's datum_timeout, plus its datum_timeout_per_mb for every MB of the
+// datum's input. It returns nil if neither is set.
+func datumTimeout(jobInfo *pps.JobInfo, data []*Input) (*types.Duration, error) {
+	if jobInfo.DatumTimeoutPerMB == nil {
+		return jobInfo.DatumTimeout, nil
+	}
+	var timeout time.Duration
+	if jobInfo.DatumTimeout != nil {
+		var err error
+		if timeout, err = types.DurationFromProto(jobInfo.DatumTimeout); err != nil {
+			return nil, err
+		}
+	}
+	perMB, err := types.DurationFromProto(jobInfo.DatumTimeoutPerMB)
+	if err != nil {
+		return nil, err
+	}
+	// Lazy inputs are counted too, as the user code still reads them
+	var size int64
+	for _, input := range data {
+		if !input.EmptyFiles {
+			size += int64(input.FileInfo.SizeBytes)
+		}
+	}
+	timeout += time.Duration(float64(perMB) * float64(size) / float64(units.MiB))
+	return types.DurationProto(timeout), nil
+}
+
+// downloadTimer cancels the download of a datum's inputs if it takes longer
+// than the job's download_timeout. A nil downloadTimer never cancels it.
+type downloadTimer struct {
+	timeout  time.Duration
+	timer    *time.Timer
+	cancel   context.CancelFunc
+	stopped  bool
+	timedOut bool
+}
+
+// newDownloadTimer returns a client for downloading a datum's inputs, whose
+// calls are cancelled once 'timeout' passes, unless the download has been
+// stopped first. Lazy inputs are read with the client while the user code
+// runs, so once the download is stopped the client is only cancelled by
+// close.
+func newDownloadTimer(pachClient *client.APIClient, timeout *types.Duration) (*client.APIClient, *downloadTimer, error) {
+	if timeout == nil {
+		return pachClient, nil, nil
+	}
+	d, err := types.DurationFromProto(timeout)
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx, cancel := context.WithCancel(pachClient.Ctx())
+	return pachClient.WithCtx(ctx), &downloadTimer{
+		timeout: d,
+		timer:   time.AfterFunc(d, cancel),
+		cancel:  cancel,
+	}, nil
+}
+
+// stop is called once the download is done, it returns an error if the
+// download was cancelled because it timed out
+func (t *downloadTimer) stop() error {
+	if t == nil {
+		return nil
+	}
+	if !t.stopped {
+		t.stopped = true
+		t.timedOut = !t.timer.Stop()
+	}
+	if !t.timedOut {
+		return nil
+	}
+	return fmt.Errorf("downloading the datum's inputs took longer than the download timeout of %v", t.timeout)
+}
+
+// close cancels the client, after the try of the datum is done
+func (t *downloadTimer) close() {
+	if t != nil {
+		t.timer.Stop()
+		t.cancel()
+	}
+}
+
+// prefetchBudget bounds the total size of the inputs that queued datums have
+// downloaded but not yet handed to their user code. A nil prefetchBudget
+// doesn't bound anything.
+type prefetchBudget struct {
+	limit int64
+	sem   *semaphore.Weighted
+}
+
+// newPrefetchBudget returns a prefetchBudget of 'limit' bytes, or nil if
+// 'limit' isn't positive.
+func newPrefetchBudget(limit int64) *prefetchBudget {
+	if limit <= 0 {
+		return nil
+	}
+	return &prefetchBudget{
+		limit: limit,
+		sem:   semaphore.NewWeighted(limit),
+	}
+}
+
+// acquire blocks until 'size' bytes of the budget are available. A datum
+// bigger than the whole budget still gets to run, it just can't overlap with
+// any other downloads. The returned function gives the bytes back, and may be
+// called more than once.
+func (p *prefetchBudget) acquire(ctx context.Context, size int64) (func(), error) {
+	if p == nil {
+		return func() {}, nil
+	}
+	if size > p.limit {
+		size = p.limit
+	}
+	if err := p.sem.Acquire(ctx, size); err != nil {
+		return nil, err
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() { p.sem.Release(size) })
+	}, nil
+}
+
 func (a *APIServer) cacheHashtree(pachClient *client.APIClient, tag string, datumIdx int64) (retErr error) {
-	buf := &bytes.Buffer{}
-	if err := pachClient.GetTag(tag, buf); err != nil {
+	ref, err := a.getTaggedTree(pachClient, tag)
+	if err != nil {
 		return err
 	}
-	if err := a.datumCache.Put(datumIdx, buf); err != nil {
+	defer ref.Close()
+	if err := a.datumCache.Put(datumIdx, ref.Reader()); err != nil {
 		return err
 	}
 	if a.pipelineInfo.EnableStats {
-		buf.Reset()
-		if err := pachClient.GetTag(tag+statsTagSuffix, buf); err != nil {
+		statsRef, err := a.getTaggedTree(pachClient, tag+statsTagSuffix)
+		if err != nil {
 			// We are okay with not finding the stats hashtree.
 			// This allows users to enable stats on a pipeline
 			// with pre-existing jobs.
 			return nil
 		}
-		return a.datumStatsCache.Put(datumIdx, buf)
+		defer statsRef.Close()
+		return a.datumStatsCache.Put(datumIdx, statsRef.Reader())
 	}
 	return nil
 }
 
 func (a *APIServer) writeStats(pachClient *client.APIClient, objClient obj.Client, tag string, stats *pps.ProcessStats, logger *taggedLogger, inputTree, outputTree *hashtree.Ordered, statsTree *hashtree.Unordered, datumIdx int64) (retErr error) {
+	// Write index in datum factory to stats tree
+	object, size, err := pachClient.PutObject(strings.NewReader(fmt.Sprint(int(datumIdx))))
+	if err != nil {
+		return err
+	}
+	objectInfo, err := pachClient.InspectObject(object.Hash)
+	if err != nil {
+		return err
+	}
+	h, err := pfs.DecodeHash(object.Hash)
+	if err != nil {
+		return err
+	}
+	statsTree.PutFile("index", h, size, objectInfo.BlockRef)
 	// Store stats and add stats file
 	marshaler := &jsonpb.Marshaler{}
 	statsString, err := marshaler.MarshalToString(stats)
@@ -2212,16 +3348,16 @@ func (a *APIServer) writeStats(pachClient *client.APIClient, objClient obj.Clien
 		logger.stderrLog.Printf("could not serialize stats: %s\n", err)
 		return err
 	}
-	object, size, err := pachClient.PutObject(strings.NewReader(statsString))
+	object, size, err = pachClient.PutObject(strings.NewReader(statsString))
 	if err != nil {
 		logger.stderrLog.Printf("could not put stats object: %s\n", err)
 		return err
 	}
-	objectInfo, err := pachClient.InspectObject(object.Hash)
+	objectInfo, err = pachClient.InspectObject(object.Hash)
 	if err != nil {
 		return err
 	}
-	h, err := pfs.DecodeHash(object.Hash)
+	h, err = pfs.DecodeHash(object.Hash)
 	if err != nil {
 		return err
 	}
@@ -2231,6 +3367,11 @@ func (a *APIServer) writeStats(pachClient *client.APIClient, objClient obj.Clien
 	if err != nil {
 		return err
 	}
+	if object == nil && logger.held != nil && logger.held.buf.Len() > 0 {
+		if object, size, err = pachClient.PutObject(&logger.held.buf); err != nil {
+			return err
+		}
+	}
 	if object != nil {
 		objectInfo, err := pachClient.InspectObject(object.Hash)
 		if err != nil {
@@ -2268,7 +3409,14 @@ func (a *APIServer) writeStats(pachClient *client.APIClient, objClient obj.Clien
 			retErr = err
 		}
 	}()
-	if _, err := objW.Write(buf.Bytes()); err != nil {
+	treeW, err := newDatumTreeWriter(objW, a.pipelineInfo.DatumTreeCompression)
+	if err != nil {
+		return err
+	}
+	if _, err := treeW.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	if err := treeW.Close(); err != nil {
 		return err
 	}
 	// Cache datum stats hashtree locally
@@ -0,0 +1,353 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func fileMode(mode os.FileMode) *os.FileMode {
+	return &mode
+}
+
+func TestUserCmdUmask(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("umask is not supported on windows")
+	}
+	a := &APIServer{}
+	require.Equal(t, []string{"touch", "foo"}, a.userCmd([]string{"touch", "foo"}))
+
+	dir, err := ioutil.TempDir("", "pachyderm_test_user_cmd")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	a.umask = fileMode(0077)
+	name := filepath.Join(dir, "foo")
+	args := a.userCmd([]string{"touch", name})
+	require.NoError(t, exec.Command(args[0], args[1:]...).Run())
+	info, err := os.Stat(name)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// The worker's own umask is untouched, so files it creates keep their
+	// default modes
+	other := filepath.Join(dir, "bar")
+	require.NoError(t, ioutil.WriteFile(other, nil, 0644))
+	info, err = os.Stat(other)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0044), info.Mode().Perm()&0044)
+}
+
+func TestTemplateCmd(t *testing.T) {
+	a := &APIServer{pipelineInfo: &pps.PipelineInfo{Transform: &pps.Transform{}}}
+	cmd := []string{"convert", `{{ input "images" }}`, "/pfs/out/{{ .DatumID }}.png"}
+	stdin := []string{"echo {{ .JobID }} {{ commit \"images\" }}"}
+	// Templates are only executed if template_cmd is set
+	args, in, err := a.templateCmd(cmd, stdin)
+	require.NoError(t, err)
+	require.Equal(t, cmd, args)
+	require.Equal(t, stdin, in)
+
+	a.pipelineInfo.Transform.TemplateCmd = true
+	a.jobID = "job"
+	a.data = []*Input{{
+		Name:     "images",
+		FileInfo: &pfs.FileInfo{File: &pfs.File{Commit: &pfs.Commit{ID: "abc"}, Path: "/cat.jpg"}},
+	}}
+	args, in, err = a.templateCmd(cmd, stdin)
+	require.NoError(t, err)
+	require.Equal(t, []string{"convert", "/pfs/images/cat.jpg", "/pfs/out/" + a.DatumID(a.data) + ".png"}, args)
+	require.Equal(t, []string{"echo job abc"}, in)
+	_, _, err = a.templateCmd([]string{`{{ input "labels" }}`}, nil)
+	require.YesError(t, err)
+}
+
+func TestSetUserPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not supported on windows")
+	}
+	dir, err := ioutil.TempDir("", "pachyderm_test_user_permissions")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "input", "sub"), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "input", "sub", "file"), []byte("foo"), 0600))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "out"), 0700))
+	link := filepath.Join(dir, "link")
+	require.NoError(t, os.Symlink(filepath.Join(dir, "input"), link))
+
+	a := &APIServer{dirMode: fileMode(0755), fileMode: fileMode(0644)}
+	require.NoError(t, a.setUserPermissions(dir))
+	for name, mode := range map[string]os.FileMode{
+		"input":          0755,
+		"input/sub":      0755,
+		"input/sub/file": 0644,
+		"out":            0755,
+		"":               0755,
+	} {
+		info, err := os.Stat(filepath.Join(dir, name))
+		require.NoError(t, err)
+		require.Equal(t, mode, info.Mode().Perm(), name)
+	}
+	// Symlinks are left alone
+	info, err := os.Lstat(link)
+	require.NoError(t, err)
+	require.Equal(t, os.ModeSymlink, info.Mode()&os.ModeSymlink)
+}
+
+func TestCreateStdoutFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not supported on windows")
+	}
+	dir, err := ioutil.TempDir("", "pachyderm_test_stdout_file")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	a := &APIServer{
+		pipelineInfo: &pps.PipelineInfo{Transform: &pps.Transform{StdoutFile: "counts/lines"}},
+		fileMode:     fileMode(0640),
+	}
+	f, err := a.createStdoutFile(dir)
+	require.NoError(t, err)
+	cmd := exec.Command("echo", "foo")
+	cmd.Stdout = f
+	require.NoError(t, cmd.Run())
+	require.NoError(t, f.Close())
+	name := filepath.Join(dir, "counts", "lines")
+	content, err := ioutil.ReadFile(name)
+	require.NoError(t, err)
+	require.Equal(t, "foo\n", string(content))
+	info, err := os.Stat(name)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0640), info.Mode().Perm())
+
+	// A retried datum's file only holds the output of its last try
+	f, err = a.createStdoutFile(dir)
+	require.NoError(t, err)
+	_, err = f.WriteString("ba")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	content, err = ioutil.ReadFile(name)
+	require.NoError(t, err)
+	require.Equal(t, "ba", string(content))
+}
+
+func TestDatumSize(t *testing.T) {
+	data := []*Input{
+		{FileInfo: &pfs.FileInfo{SizeBytes: 10}},
+		{FileInfo: &pfs.FileInfo{SizeBytes: 20}},
+		{FileInfo: &pfs.FileInfo{SizeBytes: 40}, Lazy: true},
+		{FileInfo: &pfs.FileInfo{SizeBytes: 80}, EmptyFiles: true},
+	}
+	require.Equal(t, int64(30), datumSize(data))
+	require.Equal(t, int64(0), datumSize(nil))
+}
+
+func TestDatumTimeout(t *testing.T) {
+	data := []*Input{
+		{FileInfo: &pfs.FileInfo{SizeBytes: 1 << 20}},
+		{FileInfo: &pfs.FileInfo{SizeBytes: 1 << 20}, Lazy: true},
+		{FileInfo: &pfs.FileInfo{SizeBytes: 1 << 30}, EmptyFiles: true},
+	}
+	timeout := func(jobInfo *pps.JobInfo) *types.Duration {
+		t.Helper()
+		result, err := datumTimeout(jobInfo, data)
+		require.NoError(t, err)
+		return result
+	}
+	require.Nil(t, timeout(&pps.JobInfo{}))
+	require.Equal(t, types.DurationProto(time.Minute), timeout(&pps.JobInfo{DatumTimeout: types.DurationProto(time.Minute)}))
+	require.Equal(t, types.DurationProto(time.Minute+20*time.Second), timeout(&pps.JobInfo{
+		DatumTimeout:      types.DurationProto(time.Minute),
+		DatumTimeoutPerMB: types.DurationProto(10 * time.Second),
+	}))
+	require.Equal(t, types.DurationProto(time.Second), timeout(&pps.JobInfo{DatumTimeoutPerMB: types.DurationProto(500 * time.Millisecond)}))
+}
+
+func TestDownloadTimer(t *testing.T) {
+	pachClient := &client.APIClient{}
+	// Without a timeout, the client is never cancelled
+	downloadClient, timer, err := newDownloadTimer(pachClient, nil)
+	require.NoError(t, err)
+	require.Equal(t, pachClient, downloadClient)
+	require.NoError(t, timer.stop())
+	timer.close()
+
+	// A download that finishes in time isn't cancelled until the try is done
+	downloadClient, timer, err = newDownloadTimer(pachClient, types.DurationProto(50*time.Millisecond))
+	require.NoError(t, err)
+	require.NoError(t, timer.stop())
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, downloadClient.Ctx().Err())
+	require.NoError(t, timer.stop())
+	timer.close()
+	require.YesError(t, downloadClient.Ctx().Err())
+
+	// A download that doesn't is cancelled
+	downloadClient, timer, err = newDownloadTimer(pachClient, types.DurationProto(time.Millisecond))
+	require.NoError(t, err)
+	<-downloadClient.Ctx().Done()
+	require.YesError(t, timer.stop())
+	require.YesError(t, timer.stop())
+	timer.close()
+}
+
+func TestPrefetchBudget(t *testing.T) {
+	ctx := context.Background()
+	// An unset budget never blocks
+	unbounded := newPrefetchBudget(0)
+	require.Nil(t, unbounded)
+	release, err := unbounded.acquire(ctx, 1<<40)
+	require.NoError(t, err)
+	release()
+
+	p := newPrefetchBudget(100)
+	// A datum bigger than the budget is clamped to the whole budget...
+	release, err = p.acquire(ctx, 1000)
+	require.NoError(t, err)
+	// ...so nothing else fits until it's released
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = p.acquire(timeoutCtx, 1)
+	require.YesError(t, err)
+	release()
+	// Releasing twice doesn't give back more than was acquired
+	release()
+
+	release1, err := p.acquire(ctx, 60)
+	require.NoError(t, err)
+	acquired := make(chan struct{})
+	go func() {
+		release2, err := p.acquire(ctx, 60)
+		require.NoError(t, err)
+		close(acquired)
+		release2()
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("acquired more than the prefetch budget")
+	case <-time.After(10 * time.Millisecond):
+	}
+	release1()
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("acquire didn't unblock after release")
+	}
+	release, err = p.acquire(ctx, 100)
+	require.NoError(t, err)
+	release()
+}
+
+func TestStragglers(t *testing.T) {
+	now := time.Now()
+	started := func(ago time.Duration) *types.Timestamp {
+		ts, err := types.TimestampProto(now.Add(-ago))
+		require.NoError(t, err)
+		return ts
+	}
+	complete := func(d time.Duration) *ChunkState {
+		return &ChunkState{State: State_COMPLETE, ProcessTime: types.DurationProto(d)}
+	}
+	// Chunks of 10, 10, 20, 10 and 10 datums
+	plan := &Plan{Chunks: []int64{10, 20, 40, 50, 60}}
+	states := []*ChunkState{
+		complete(10 * time.Minute), // 1 minute per datum
+		complete(20 * time.Minute), // 2 minutes per datum
+		{State: State_RUNNING, Started: started(80 * time.Minute)},
+		{State: State_RUNNING, Started: started(25 * time.Minute)},
+		{State: State_RUNNING, Started: started(50 * time.Minute)},
+	}
+	// The median is 1.5 minutes per datum, so with a factor of 2 a chunk of 10
+	// datums straggles after 30 minutes, and a chunk of 20 after an hour.
+	// Slower chunks come first.
+	require.Equal(t, []int{4, 2}, stragglers(plan, states, 2, now))
+	require.Equal(t, 0, len(stragglers(plan, states, 4, now)))
+
+	// Nothing straggles until some chunk has completed
+	require.Equal(t, 0, len(stragglers(plan, []*ChunkState{
+		nil, nil, {State: State_RUNNING, Started: started(time.Hour)}, nil, nil,
+	}, 2, now)))
+
+	// or before minSpeculationDelay, however fast other chunks are
+	require.Equal(t, 0, len(stragglers(plan, []*ChunkState{
+		complete(time.Millisecond), nil, nil, nil,
+		{State: State_RUNNING, Started: started(minSpeculationDelay / 2)},
+	}, 2, now)))
+}
+
+func TestClassify(t *testing.T) {
+	require.Nil(t, classify(pps.FailureType_UPLOAD_ERROR, nil))
+	require.Equal(t, pps.FailureType_FAILURE_UNKNOWN, failureType(errors.New("foo")))
+
+	err := classify(pps.FailureType_SPECIAL_FILE, errSpecialFile)
+	require.Equal(t, errSpecialFile.Error(), err.Error())
+	require.Equal(t, pps.FailureType_SPECIAL_FILE, failureType(err))
+	// Errors keep their first classification
+	require.Equal(t, pps.FailureType_SPECIAL_FILE, failureType(classify(pps.FailureType_UPLOAD_ERROR, err)))
+}
+
+func TestExitFailureType(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals are not supported on windows")
+	}
+	status := func(script string) syscall.WaitStatus {
+		err := exec.Command("sh", "-c", script).Run()
+		exitErr, ok := err.(*exec.ExitError)
+		require.True(t, ok)
+		return exitErr.Sys().(syscall.WaitStatus)
+	}
+	// without cgroup memory events, SIGKILL is assumed to be the OOM killer
+	require.Equal(t, pps.FailureType_USER_CODE_NONZERO_EXIT, exitFailureType(status("exit 3"), false, false))
+	require.Equal(t, pps.FailureType_USER_CODE_NONZERO_EXIT, exitFailureType(status("kill -TERM $$"), false, false))
+	require.Equal(t, pps.FailureType_OOM_KILLED, exitFailureType(status("kill -KILL $$"), false, false))
+	require.Equal(t, pps.FailureType_OOM_KILLED, exitFailureType(status("exit 137"), false, false))
+	// with them, only OOM kills in the cgroup count
+	require.Equal(t, pps.FailureType_USER_CODE_NONZERO_EXIT, exitFailureType(status("kill -KILL $$"), false, true))
+	require.Equal(t, pps.FailureType_OOM_KILLED, exitFailureType(status("exit 1"), true, true))
+}
+
+func TestParseOOMKills(t *testing.T) {
+	// cgroup v2 memory.events
+	kills, ok := parseOOMKills(strings.NewReader("low 0\nhigh 0\nmax 12\noom 3\noom_kill 2\n"))
+	require.True(t, ok)
+	require.Equal(t, int64(2), kills)
+	// cgroup v1 memory.oom_control
+	kills, ok = parseOOMKills(strings.NewReader("oom_kill_disable 0\nunder_oom 0\noom_kill 5\n"))
+	require.True(t, ok)
+	require.Equal(t, int64(5), kills)
+	// cgroup v1 memory.oom_control from before Linux 4.13
+	_, ok = parseOOMKills(strings.NewReader("oom_kill_disable 0\nunder_oom 0\n"))
+	require.False(t, ok)
+}
+
+func TestFinishedBefore(t *testing.T) {
+	started := &types.Timestamp{Seconds: 100}
+	require.False(t, finishedBefore(&pfs.CommitInfo{}, started))
+	require.True(t, finishedBefore(&pfs.CommitInfo{Finished: &types.Timestamp{Seconds: 99}}, started))
+	require.False(t, finishedBefore(&pfs.CommitInfo{Finished: &types.Timestamp{Seconds: 101}}, started))
+	require.False(t, finishedBefore(&pfs.CommitInfo{Finished: &types.Timestamp{Seconds: 99}}, nil))
+}
+
+func TestWaitsForParentJobs(t *testing.T) {
+	a := &APIServer{pipelineInfo: &pps.PipelineInfo{}}
+	require.Equal(t, 1, a.maxOutstandingJobs())
+	require.True(t, a.waitsForParentJobs())
+	a.pipelineInfo.MaxOutstandingJobs = 4
+	require.Equal(t, 4, a.maxOutstandingJobs())
+	require.False(t, a.waitsForParentJobs())
+	a.pipelineInfo.MaxOutstandingJobs = 1
+	a.pipelineInfo.JobOrder = pps.JobOrder_NEWEST_JOB_FIRST
+	require.False(t, a.waitsForParentJobs())
+}
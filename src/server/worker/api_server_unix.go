@@ -19,3 +19,13 @@ func makeCmdCredentials(uid uint32, gid uint32) *syscall.SysProcAttr {
 		},
 	}
 }
+
+// availableBytes returns the space available to unprivileged users in the
+// filesystem that holds 'path'
+func availableBytes(path string) (int64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+	return int64(stat.Bavail) * int64(stat.Bsize), nil
+}
@@ -16,3 +16,7 @@ func createSpoutFifo(path string) error {
 func makeCmdCredentials(uid uint32, gid uint32) *syscall.SysProcAttr {
 	return nil
 }
+
+func availableBytes(path string) (int64, error) {
+	return 0, fmt.Errorf("unimplemented on windows")
+}
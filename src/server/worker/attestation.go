@@ -0,0 +1,162 @@
+package worker
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/hashtree"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// attestation returns the attestation of the job in 'jobInfo', whose output
+// is in 'trees'
+func (a *APIServer) attestation(jobInfo *pps.JobInfo, trees []*pfs.Object, logger *taggedLogger) *pps.JobAttestation {
+	// The attestation is still worth having without the digest, as it has the
+	// image's name
+	digest, err := a.imageDigest()
+	if err != nil {
+		logger.Logf("could not get the digest of the transform image for the job's attestation: %v", err)
+	}
+	attestation := &pps.JobAttestation{
+		Job:             jobInfo.Job,
+		Pipeline:        jobInfo.Pipeline,
+		PipelineVersion: jobInfo.PipelineVersion,
+		Image:           a.pipelineInfo.Transform.Image,
+		ImageDigest:     digest,
+		Cmd:             a.pipelineInfo.Transform.Cmd,
+		OutputCommit:    jobInfo.OutputCommit,
+		OutputTrees:     trees,
+		Created:         types.TimestampNow(),
+	}
+	pps.VisitInput(jobInfo.Input, func(input *pps.Input) {
+		switch {
+		case input.Pfs != nil && input.Pfs.Commit != "":
+			attestation.Inputs = append(attestation.Inputs, client.NewCommit(input.Pfs.Repo, input.Pfs.Commit))
+		case input.Cron != nil && input.Cron.Commit != "":
+			attestation.Inputs = append(attestation.Inputs, client.NewCommit(input.Cron.Repo, input.Cron.Commit))
+		case input.SQL != nil && input.SQL.Commit != "":
+			attestation.Inputs = append(attestation.Inputs, client.NewCommit(input.SQL.Repo, input.SQL.Commit))
+		case input.Git != nil && input.Git.Commit != "":
+			attestation.Inputs = append(attestation.Inputs, client.NewCommit(input.Git.Name, input.Git.Commit))
+		}
+	})
+	if a.pipelineInfo.AttestationSpec != nil {
+		for _, name := range a.pipelineInfo.AttestationSpec.Env {
+			if value, ok := a.pipelineInfo.Transform.Env[name]; ok {
+				if attestation.Env == nil {
+					attestation.Env = make(map[string]string)
+				}
+				attestation.Env[name] = value
+			}
+		}
+	}
+	return attestation
+}
+
+// imageDigest returns the digest of the image that this worker's user
+// container runs, which (unlike the image's tag) identifies its content
+func (a *APIServer) imageDigest() (string, error) {
+	pod, err := a.kubeClient.CoreV1().Pods(a.namespace).Get(a.workerName, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == client.PPSWorkerUserContainerName {
+			return parseImageDigest(status.ImageID), nil
+		}
+	}
+	return "", fmt.Errorf("pod %s has no %q container", a.workerName, client.PPSWorkerUserContainerName)
+}
+
+// parseImageDigest returns the digest in a container status's image ID, e.g.
+// "docker-pullable://ubuntu@sha256:abc..." or "sha256:abc..."
+func parseImageDigest(imageID string) string {
+	if i := strings.LastIndex(imageID, "@"); i >= 0 {
+		return imageID[i+1:]
+	}
+	return strings.TrimPrefix(imageID, "docker://")
+}
+
+// writeAttestation signs the attestation of the job in 'jobInfo', and adds it
+// at ppsutil.AttestationFile (and its signature at
+// ppsutil.AttestationSignatureFile) to 'statsTrees', the hashtree shards of
+// the job's stats commit. It returns the new shards, and the size of the
+// files it added.
+func (a *APIServer) writeAttestation(pachClient *client.APIClient, jobInfo *pps.JobInfo, trees []*pfs.Object, statsTrees []*pfs.Object, logger *taggedLogger) ([]*pfs.Object, uint64, error) {
+	attestation := a.attestation(jobInfo, trees, logger)
+	key, err := ppsutil.GetAttestationKey(pachClient.Ctx(), a.etcdClient, a.etcdPrefix)
+	if err != nil {
+		return nil, 0, fmt.Errorf("could not get the attestation key: %v", err)
+	}
+	doc, sig, err := ppsutil.SignAttestation(key, attestation)
+	if err != nil {
+		return nil, 0, err
+	}
+	tree := hashtree.NewUnordered("/")
+	var size uint64
+	for p, content := range map[string][]byte{
+		ppsutil.AttestationFile:          doc,
+		ppsutil.AttestationSignatureFile: sig,
+	} {
+		object, n, err := pachClient.PutObject(bytes.NewReader(content))
+		if err != nil {
+			return nil, 0, err
+		}
+		objectInfo, err := pachClient.InspectObject(object.Hash)
+		if err != nil {
+			return nil, 0, err
+		}
+		h, err := pfs.DecodeHash(object.Hash)
+		if err != nil {
+			return nil, 0, err
+		}
+		tree.PutFile(p, h, n, objectInfo.BlockRef)
+		size += uint64(n)
+	}
+	treeBuf := &bytes.Buffer{}
+	if err := tree.Ordered().Serialize(treeBuf); err != nil {
+		return nil, 0, err
+	}
+	if len(statsTrees) == 0 {
+		statsTrees = []*pfs.Object{nil}
+	}
+	// Merge each node of the attestation's tree into the shard that it
+	// belongs to, as the workers' merges do
+	numShards := int64(len(statsTrees))
+	result := make([]*pfs.Object, len(statsTrees))
+	copy(result, statsTrees)
+	merged := make(map[int64]bool)
+	for _, p := range []string{"/", ppsutil.AttestationFile, ppsutil.AttestationSignatureFile} {
+		shard := int64(hashtree.PathToTree(p, numShards))
+		if merged[shard] {
+			continue
+		}
+		merged[shard] = true
+		rs := []*hashtree.Reader{hashtree.NewReader(bytes.NewReader(treeBuf.Bytes()), hashtree.NewFilter(numShards, shard))}
+		if statsTrees[shard] != nil {
+			shardBuf := &bytes.Buffer{}
+			if err := pachClient.GetObject(statsTrees[shard].Hash, shardBuf); err != nil {
+				return nil, 0, err
+			}
+			rs = append(rs, hashtree.NewReader(shardBuf, nil))
+		}
+		buf := &bytes.Buffer{}
+		if err := hashtree.Merge(hashtree.NewWriter(buf), rs); err != nil {
+			return nil, 0, err
+		}
+		object, _, err := pachClient.PutObject(buf)
+		if err != nil {
+			return nil, 0, err
+		}
+		result[shard] = object
+	}
+	return result, size, nil
+}
@@ -0,0 +1,14 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestParseImageDigest(t *testing.T) {
+	require.Equal(t, "sha256:abc", parseImageDigest("docker-pullable://ubuntu@sha256:abc"))
+	require.Equal(t, "sha256:abc", parseImageDigest("docker-pullable://registry:5000/team/ubuntu@sha256:abc"))
+	require.Equal(t, "sha256:abc", parseImageDigest("docker://sha256:abc"))
+	require.Equal(t, "sha256:abc", parseImageDigest("sha256:abc"))
+}
@@ -0,0 +1,40 @@
+package worker
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	filesync "github.com/pachyderm/pachyderm/src/server/pkg/sync"
+)
+
+// workerThrottle returns the throttle of one of 'numWorkers' workers that
+// share the bandwidth 'limit' (see pps.BandwidthLimit), or nil if 'limit' is
+// empty
+func workerThrottle(limit string, numWorkers int) (*filesync.Throttle, error) {
+	if limit == "" {
+		return nil, nil
+	}
+	quantity, err := resource.ParseQuantity(limit)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse bandwidth limit %q: %v", limit, err)
+	}
+	if numWorkers < 1 {
+		numWorkers = 1
+	}
+	bytesPerSecond := quantity.Value() / int64(numWorkers)
+	if bytesPerSecond < 1 {
+		// A limit that's too small to split still lets each worker make
+		// progress
+		bytesPerSecond = 1
+	}
+	return filesync.NewThrottle(bytesPerSecond), nil
+}
+
+// newPuller returns a puller whose downloads are throttled by the pipeline's
+// download bandwidth limit
+func (a *APIServer) newPuller() *filesync.Puller {
+	puller := filesync.NewPuller()
+	puller.SetThrottle(a.downloadThrottle)
+	return puller
+}
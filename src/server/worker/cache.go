@@ -0,0 +1,80 @@
+package worker
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// cachedFile is a file in a pipeline's cache
+type cachedFile struct {
+	path     string
+	size     int64
+	lastUsed time.Time
+}
+
+// evictCache evicts the least recently used files from the pipeline's cache,
+// until it's under its size limit. It's called after each datum, once the
+// user code is done with the cache (only one datum runs at a time, see runMu).
+func (a *APIServer) evictCache(logger *taggedLogger) {
+	cache := a.pipelineInfo.Cache
+	if cache == nil || cache.SizeLimit == "" {
+		return
+	}
+	limit, err := resource.ParseQuantity(cache.SizeLimit)
+	if err != nil {
+		return // Shouldn't happen, as the cache is validated in CreatePipeline
+	}
+	evicted, size, err := evictLRU(cache.Path, limit.Value())
+	if err != nil {
+		logger.Logf("error evicting files from the cache at %s: %v", cache.Path, err)
+		return
+	}
+	if len(evicted) > 0 {
+		logger.Logf("evicted %d files from the cache at %s, which now holds %d bytes", len(evicted), cache.Path, size)
+	}
+}
+
+// evictLRU removes the least recently used files under 'dir' until their
+// total size is at most 'limit'. It returns the files that it removed, and
+// the total size of the rest.
+func evictLRU(dir string, limit int64) ([]string, int64, error) {
+	var files []*cachedFile
+	var total int64
+	if err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil // the user code may be removing files itself
+			}
+			return err
+		}
+		if info.Mode().IsRegular() {
+			files = append(files, &cachedFile{path: path, size: info.Size(), lastUsed: fileLastUsed(info)})
+			total += info.Size()
+		}
+		return nil
+	}); err != nil {
+		return nil, total, err
+	}
+	if total <= limit {
+		return nil, total, nil
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].lastUsed.Before(files[j].lastUsed)
+	})
+	var evicted []string
+	for _, f := range files {
+		if total <= limit {
+			break
+		}
+		if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+			return evicted, total, err
+		}
+		evicted = append(evicted, f.path)
+		total -= f.size
+	}
+	return evicted, total, nil
+}
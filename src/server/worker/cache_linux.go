@@ -0,0 +1,20 @@
+package worker
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// fileLastUsed returns the last time that the file was read or written. Some
+// filesystems don't update access times (or only do so lazily), so the
+// modification time is used if it's later.
+func fileLastUsed(info os.FileInfo) time.Time {
+	lastUsed := info.ModTime()
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		if atime := time.Unix(int64(stat.Atim.Sec), int64(stat.Atim.Nsec)); atime.After(lastUsed) {
+			lastUsed = atime
+		}
+	}
+	return lastUsed
+}
@@ -0,0 +1,14 @@
+// +build !linux
+
+package worker
+
+import (
+	"os"
+	"time"
+)
+
+// fileLastUsed returns the last time that the file was written. Workers run
+// on Linux, this is only used in tests.
+func fileLastUsed(info os.FileInfo) time.Time {
+	return info.ModTime()
+}
@@ -0,0 +1,47 @@
+package worker
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestEvictLRU(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cache")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	now := time.Now()
+	write := func(name string, size int, age time.Duration) {
+		p := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0777))
+		require.NoError(t, ioutil.WriteFile(p, make([]byte, size), 0666))
+		require.NoError(t, os.Chtimes(p, now.Add(-age), now.Add(-age)))
+	}
+	write("weights/a", 100, 3*time.Hour)
+	write("weights/b", 100, time.Hour)
+	write("c", 100, 2*time.Hour)
+
+	// under the limit, nothing is evicted
+	evicted, size, err := evictLRU(dir, 300)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(evicted))
+	require.Equal(t, int64(300), size)
+
+	// over it, the least recently used files are
+	evicted, size, err = evictLRU(dir, 150)
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(dir, "weights/a"), filepath.Join(dir, "c")}, evicted)
+	require.Equal(t, int64(100), size)
+	_, err = os.Stat(filepath.Join(dir, "weights/b"))
+	require.NoError(t, err)
+
+	// a cache that hasn't been created yet is empty
+	evicted, size, err = evictLRU(filepath.Join(dir, "missing"), 0)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(evicted))
+	require.Equal(t, int64(0), size)
+}
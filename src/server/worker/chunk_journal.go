@@ -0,0 +1,181 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+)
+
+// maxJournaledTags is the most datums that are journaled for a chunk, which
+// keeps its journal well within etcd's limit on the size of a value. The
+// datums after them are still skipped on restart, once their tags are found.
+const maxJournaledTags = 10000
+
+const (
+	// chunkJournalFlushDatums and chunkJournalFlushInterval bound how often a
+	// chunk's journal is written to etcd: once this many datums have been
+	// journaled since the last write, or once a datum is journaled this long
+	// after it. Datums that aren't written when a chunk is retried are only
+	// redone (or skipped, if their output was tagged).
+	chunkJournalFlushDatums   = 100
+	chunkJournalFlushInterval = 10 * time.Second
+)
+
+// chunkJournal tracks the datums of a chunk that are done, so that the longest
+// run of them at the start of the chunk can be journaled in etcd. Datums
+// finish out of order, so those after the first unfinished datum are held
+// until it finishes. Failed and recovered datums are never journaled, so the
+// journal stops before the first of them.
+type chunkJournal struct {
+	low int64
+
+	mu sync.Mutex
+	// state is the journaled run of datums, from low
+	state *ChunkState
+	// done are the datums after the run that are done, by index, and whether
+	// they were skipped
+	done map[int64]journaledDatum
+	// flushed is the length of the journal when it was last returned to be
+	// written, at lastFlush
+	flushed       int
+	lastFlush     time.Time
+	flushDatums   int
+	flushInterval time.Duration
+}
+
+type journaledDatum struct {
+	tag     string
+	skipped bool
+}
+
+// newChunkJournal returns a journal for the chunk starting at datum 'low',
+// which resumes from the journal 'state' of a previous attempt
+func newChunkJournal(low int64, state *ChunkState) *chunkJournal {
+	return &chunkJournal{
+		low:   low,
+		state: &ChunkState{CompletedTags: append([]string(nil), state.CompletedTags...), CompletedSkipped: state.CompletedSkipped},
+		done:  make(map[int64]journaledDatum),
+
+		flushed:       len(state.CompletedTags),
+		lastFlush:     time.Now(),
+		flushDatums:   chunkJournalFlushDatums,
+		flushInterval: chunkJournalFlushInterval,
+	}
+}
+
+// next returns the index of the first datum that isn't journaled
+func (j *chunkJournal) next() int64 {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.low + int64(len(j.state.CompletedTags))
+}
+
+// complete records that the datum at 'datumIdx' (whose tag is 'tag') is done.
+// If that extends the run of journaled datums enough that it's due to be
+// written (see chunkJournalFlushDatums), it returns the new journal, which is
+// a copy that the caller can write to etcd, otherwise it returns nil.
+func (j *chunkJournal) complete(datumIdx int64, tag string, skipped bool) *ChunkState {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.done[datumIdx] = journaledDatum{tag: tag, skipped: skipped}
+	var extended bool
+	for len(j.state.CompletedTags) < maxJournaledTags {
+		next := j.low + int64(len(j.state.CompletedTags))
+		datum, ok := j.done[next]
+		if !ok {
+			break
+		}
+		delete(j.done, next)
+		j.state.CompletedTags = append(j.state.CompletedTags, datum.tag)
+		if datum.skipped {
+			j.state.CompletedSkipped++
+		}
+		extended = true
+	}
+	if !extended {
+		return nil
+	}
+	if len(j.state.CompletedTags)-j.flushed < j.flushDatums && time.Since(j.lastFlush) < j.flushInterval {
+		return nil
+	}
+	return j.flushLocked()
+}
+
+// flush returns the journal to be written, if it has changed since it was
+// last returned, otherwise it returns nil. It's called when the chunk is
+// abandoned, so that none of the datums that are done are lost.
+func (j *chunkJournal) flush() *ChunkState {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if len(j.state.CompletedTags) == j.flushed {
+		return nil
+	}
+	return j.flushLocked()
+}
+
+func (j *chunkJournal) flushLocked() *ChunkState {
+	j.flushed = len(j.state.CompletedTags)
+	j.lastFlush = time.Now()
+	return &ChunkState{
+		CompletedTags:    append([]string(nil), j.state.CompletedTags...),
+		CompletedSkipped: j.state.CompletedSkipped,
+	}
+}
+
+// readChunkJournal returns the journal of the chunk ending at 'high', which
+// is empty if no datums of the chunk have been journaled
+func (a *APIServer) readChunkJournal(ctx context.Context, jobID string, high int64) (*ChunkState, error) {
+	state := &ChunkState{}
+	if err := a.chunkJournals(jobID).ReadOnly(ctx).Get(fmt.Sprint(high), state); err != nil && !col.IsErrNotFound(err) {
+		return nil, err
+	}
+	return state, nil
+}
+
+// writeChunkJournal writes the journal 'state' of the chunk ending at 'high',
+// unless a longer one has been written already (by an attempt that finished
+// datums more quickly)
+func (a *APIServer) writeChunkJournal(ctx context.Context, jobID string, high int64, state *ChunkState) error {
+	_, err := col.NewSTM(ctx, a.etcdClient, func(stm col.STM) error {
+		journals := a.chunkJournals(jobID).ReadWrite(stm)
+		current := &ChunkState{}
+		if err := journals.Get(fmt.Sprint(high), current); err != nil && !col.IsErrNotFound(err) {
+			return err
+		}
+		if len(current.CompletedTags) >= len(state.CompletedTags) {
+			return nil
+		}
+		return journals.Put(fmt.Sprint(high), state)
+	})
+	return err
+}
+
+// resumeChunk returns the journal of the chunk from 'low' to 'high', after
+// restoring the hashtrees of the datums that a previous attempt at the chunk
+// journaled, as if they'd just been processed or skipped
+func (a *APIServer) resumeChunk(pachClient *client.APIClient, logger *taggedLogger, jobID string, low, high int64, skip map[string]bool, useParentHashTree bool) (*chunkJournal, error) {
+	state, err := a.readChunkJournal(pachClient.Ctx(), jobID, high)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(state.CompletedTags)) > high-low {
+		// The journal isn't of this chunk
+		state = &ChunkState{}
+	}
+	for i, tag := range state.CompletedTags {
+		if skip[tag] && useParentHashTree {
+			continue
+		}
+		if err := a.cacheHashtree(pachClient, tag, low+int64(i)); err != nil {
+			return nil, err
+		}
+	}
+	if len(state.CompletedTags) > 0 {
+		logger.Logf("resuming chunk %d at datum %d, after %d datums that a previous attempt finished", high, low+int64(len(state.CompletedTags)), len(state.CompletedTags))
+	}
+	return newChunkJournal(low, state), nil
+}
@@ -0,0 +1,59 @@
+package worker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestChunkJournal(t *testing.T) {
+	j := newChunkJournal(10, &ChunkState{})
+	j.flushDatums = 1
+	require.Equal(t, int64(10), j.next())
+
+	// Datums that finish after the first unfinished one are held
+	require.True(t, j.complete(11, "b", false) == nil)
+	require.True(t, j.complete(12, "c", true) == nil)
+	state := j.complete(10, "a", false)
+	require.Equal(t, []string{"a", "b", "c"}, state.CompletedTags)
+	require.Equal(t, int64(1), state.CompletedSkipped)
+	require.Equal(t, int64(13), j.next())
+
+	// The returned journal is a copy
+	state.CompletedTags[0] = "changed"
+	require.Equal(t, "a", j.state.CompletedTags[0])
+
+	// A journal resumes from a previous attempt's
+	j = newChunkJournal(10, state)
+	j.flushDatums = 1
+	require.Equal(t, int64(13), j.next())
+	state = j.complete(13, "d", true)
+	require.Equal(t, []string{"changed", "b", "c", "d"}, state.CompletedTags)
+	require.Equal(t, int64(2), state.CompletedSkipped)
+}
+
+func TestChunkJournalFlush(t *testing.T) {
+	j := newChunkJournal(0, &ChunkState{CompletedTags: []string{"a"}})
+	j.flushDatums = 3
+	require.True(t, j.flush() == nil)
+
+	// The journal is returned once 3 more datums are done
+	require.True(t, j.complete(1, "b", false) == nil)
+	require.True(t, j.complete(3, "d", false) == nil)
+	require.True(t, j.complete(2, "c", false) != nil) // 2 and 3
+	require.True(t, j.flush() == nil)
+
+	// flush returns the datums that haven't been returned yet
+	require.True(t, j.complete(4, "e", false) == nil)
+	state := j.flush()
+	require.Equal(t, []string{"a", "b", "c", "d", "e"}, state.CompletedTags)
+	require.True(t, j.flush() == nil)
+
+	// The journal is also returned once the interval has passed
+	j.flushInterval = time.Millisecond
+	time.Sleep(2 * time.Millisecond)
+	state = j.complete(5, "f", true)
+	require.Equal(t, 6, len(state.CompletedTags))
+	require.Equal(t, int64(1), state.CompletedSkipped)
+}
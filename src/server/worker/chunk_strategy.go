@@ -0,0 +1,184 @@
+package worker
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"sort"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// chunkStrategy decides how the datums of a pipeline's jobs are split into
+// chunks, which the master does once per job, and in which order each worker
+// tries to claim the chunks. A pipeline selects its strategy with
+// chunk_spec.strategy.
+type chunkStrategy interface {
+	// plan splits the datums in 'df' into chunks, see newPlan. 'datumID'
+	// returns the ID of the i'th datum.
+	plan(df DatumIterator, spec *pps.ChunkSpec, parallelism int, numHashtrees int64, datumCost func(i int) time.Duration, datumID func(i int) string) *Plan
+	// claimOrder returns the indexes of plan.Chunks in the order in which the
+	// worker named 'workerName' tries to claim them
+	claimOrder(plan *Plan, workerName string) []int
+}
+
+var chunkStrategies = map[pps.ChunkStrategy]chunkStrategy{
+	pps.ChunkStrategy_CHUNKS_IN_ORDER:      inOrderStrategy{},
+	pps.ChunkStrategy_CHUNKS_SIZE_BALANCED: sizeBalancedStrategy{},
+	pps.ChunkStrategy_CHUNKS_LARGEST_FIRST: largestFirstStrategy{},
+	pps.ChunkStrategy_CHUNKS_LOCALITY:      localityStrategy{},
+}
+
+// getChunkStrategy returns the strategy that 'spec' selects
+func getChunkStrategy(spec *pps.ChunkSpec) chunkStrategy {
+	if spec == nil {
+		return inOrderStrategy{}
+	}
+	if strategy, ok := chunkStrategies[spec.Strategy]; ok {
+		return strategy
+	}
+	return inOrderStrategy{}
+}
+
+// inOrderStrategy is the default strategy: datums are chunked by
+// chunk_spec.number, size_bytes or target_duration, and claimed in order
+type inOrderStrategy struct{}
+
+func (inOrderStrategy) plan(df DatumIterator, spec *pps.ChunkSpec, parallelism int, numHashtrees int64, datumCost func(i int) time.Duration, datumID func(i int) string) *Plan {
+	return newPlan(df, spec, parallelism, numHashtrees, datumCost)
+}
+
+func (inOrderStrategy) claimOrder(plan *Plan, workerName string) []int {
+	return inOrder(plan)
+}
+
+func inOrder(plan *Plan) []int {
+	order := make([]int, len(plan.Chunks))
+	for i := range order {
+		order[i] = i
+	}
+	return order
+}
+
+// sizeBalancedStrategy chunks datums so that each chunk's inputs are about
+// the same size, with about ten chunks per worker
+type sizeBalancedStrategy struct{}
+
+func (sizeBalancedStrategy) plan(df DatumIterator, spec *pps.ChunkSpec, parallelism int, numHashtrees int64, datumCost func(i int) time.Duration, datumID func(i int) string) *Plan {
+	sizes := make([]int64, df.Len())
+	var total int64
+	for i := range sizes {
+		sizes[i] = datumSize(df.DatumN(i))
+		total += sizes[i]
+	}
+	if total == 0 {
+		// Without any sizes, balance the number of datums instead
+		for i := range sizes {
+			sizes[i] = 1
+		}
+		total = int64(len(sizes))
+	}
+	numChunks := int64(parallelism * 10)
+	if numChunks <= 0 {
+		numChunks = 1
+	}
+	plan := &Plan{Merges: numHashtrees}
+	// Cut a chunk each time the datums so far reach the next multiple of
+	// total/numChunks, so that rounding doesn't accumulate
+	var size int64
+	next := int64(1)
+	for i := 0; i < df.Len()-1; i++ {
+		size += sizes[i]
+		if next < numChunks && size*numChunks >= next*total {
+			plan.Chunks = append(plan.Chunks, int64(i+1))
+			for next < numChunks && size*numChunks >= next*total {
+				next++
+			}
+		}
+	}
+	plan.Chunks = append(plan.Chunks, int64(df.Len()))
+	return plan
+}
+
+func (sizeBalancedStrategy) claimOrder(plan *Plan, workerName string) []int {
+	return inOrder(plan)
+}
+
+// largestFirstStrategy claims chunks in order of their estimated cost, the
+// most expensive first, so that the job isn't held up by an expensive chunk
+// that's claimed last
+type largestFirstStrategy struct{}
+
+func (largestFirstStrategy) plan(df DatumIterator, spec *pps.ChunkSpec, parallelism int, numHashtrees int64, datumCost func(i int) time.Duration, datumID func(i int) string) *Plan {
+	plan := newPlan(df, spec, parallelism, numHashtrees, datumCost)
+	cost := func(i int) int64 {
+		if datumCost != nil {
+			return int64(datumCost(i))
+		}
+		return datumSize(df.DatumN(i))
+	}
+	costs := make([]int64, len(plan.Chunks))
+	low := 0
+	for i, high := range plan.Chunks {
+		for j := low; j < int(high); j++ {
+			costs[i] += cost(j)
+		}
+		low = int(high)
+	}
+	order := inOrder(plan)
+	sort.SliceStable(order, func(i, j int) bool {
+		return costs[order[i]] > costs[order[j]]
+	})
+	for _, i := range order {
+		plan.Order = append(plan.Order, int64(i))
+	}
+	return plan
+}
+
+func (largestFirstStrategy) claimOrder(plan *Plan, workerName string) []int {
+	if len(plan.Order) != len(plan.Chunks) {
+		return inOrder(plan)
+	}
+	order := make([]int, len(plan.Order))
+	for i, chunk := range plan.Order {
+		order[i] = int(chunk)
+	}
+	return order
+}
+
+// localityStrategy has each worker claim the chunks that it prefers first,
+// by a hash of the worker's name and the ID of each chunk's first datum
+// (rendezvous hashing). Workers keep their names, and a chunk's first datum
+// is usually the same, from job to job, so a worker tends to get the same
+// datums, whose inputs may already be in the pipeline's cache.
+type localityStrategy struct{}
+
+func (localityStrategy) plan(df DatumIterator, spec *pps.ChunkSpec, parallelism int, numHashtrees int64, datumCost func(i int) time.Duration, datumID func(i int) string) *Plan {
+	plan := newPlan(df, spec, parallelism, numHashtrees, datumCost)
+	low := 0
+	for _, high := range plan.Chunks {
+		if low < df.Len() {
+			plan.Keys = append(plan.Keys, datumID(low))
+		} else {
+			plan.Keys = append(plan.Keys, "")
+		}
+		low = int(high)
+	}
+	return plan
+}
+
+func (localityStrategy) claimOrder(plan *Plan, workerName string) []int {
+	order := inOrder(plan)
+	if len(plan.Keys) != len(plan.Chunks) {
+		return order
+	}
+	scores := make([]uint64, len(plan.Keys))
+	for i, key := range plan.Keys {
+		hash := sha256.Sum256([]byte(workerName + "/" + key))
+		scores[i] = binary.BigEndian.Uint64(hash[:8])
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return scores[order[i]] > scores[order[j]]
+	})
+	return order
+}
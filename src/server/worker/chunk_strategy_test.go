@@ -0,0 +1,96 @@
+package worker
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// testDatums returns an iterator over datums with inputs of 'sizes' bytes
+func testDatums(sizes ...uint64) DatumIterator {
+	df := &pfsDatumIterator{}
+	for _, size := range sizes {
+		df.inputs = append(df.inputs, &Input{FileInfo: &pfs.FileInfo{SizeBytes: size}})
+	}
+	return df
+}
+
+func testDatumID(i int) string {
+	return fmt.Sprint("datum-", i)
+}
+
+func TestSizeBalancedStrategy(t *testing.T) {
+	strategy := getChunkStrategy(&pps.ChunkSpec{Strategy: pps.ChunkStrategy_CHUNKS_SIZE_BALANCED})
+	// One worker, so about ten chunks of 10 bytes each
+	sizes := []uint64{10, 5, 5, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 40, 10, 10, 10}
+	plan := strategy.plan(testDatums(sizes...), &pps.ChunkSpec{}, 1, 1, nil, testDatumID)
+	require.Equal(t, []int64{1, 3, 13, 14, 15, 16, 17}, plan.Chunks)
+	require.Equal(t, int64(1), plan.Merges)
+	require.Equal(t, []int{0, 1, 2, 3, 4, 5, 6}, strategy.claimOrder(plan, "worker"))
+
+	// Datums without sizes are balanced by number
+	plan = strategy.plan(testDatums(make([]uint64, 40)...), &pps.ChunkSpec{}, 2, 1, nil, testDatumID)
+	require.Equal(t, 20, len(plan.Chunks))
+	require.Equal(t, int64(2), plan.Chunks[0])
+	require.Equal(t, int64(40), plan.Chunks[19])
+}
+
+func TestLargestFirstStrategy(t *testing.T) {
+	strategy := getChunkStrategy(&pps.ChunkSpec{Strategy: pps.ChunkStrategy_CHUNKS_LARGEST_FIRST})
+	df := testDatums(1, 1, 30, 1, 20, 20, 1, 1)
+	plan := strategy.plan(df, &pps.ChunkSpec{Number: 2}, 1, 1, nil, testDatumID)
+	require.Equal(t, []int64{2, 4, 6, 8}, plan.Chunks)
+	require.Equal(t, []int{2, 1, 0, 3}, strategy.claimOrder(plan, "worker"))
+
+	// Estimated costs are used if there are any
+	cost := func(i int) time.Duration {
+		if i == 7 {
+			return time.Hour
+		}
+		return time.Second
+	}
+	plan = strategy.plan(df, &pps.ChunkSpec{Number: 2}, 1, 1, cost, testDatumID)
+	require.Equal(t, []int{3, 0, 1, 2}, strategy.claimOrder(plan, "worker"))
+}
+
+func TestLocalityStrategy(t *testing.T) {
+	strategy := getChunkStrategy(&pps.ChunkSpec{Strategy: pps.ChunkStrategy_CHUNKS_LOCALITY})
+	plan := strategy.plan(testDatums(make([]uint64, 20)...), &pps.ChunkSpec{Number: 2}, 1, 1, nil, testDatumID)
+	require.Equal(t, 10, len(plan.Keys))
+	require.Equal(t, testDatumID(0), plan.Keys[0])
+	require.Equal(t, testDatumID(2), plan.Keys[1])
+
+	// Each worker tries every chunk, in an order of its own that's the same
+	// every time
+	a, b := strategy.claimOrder(plan, "worker-a"), strategy.claimOrder(plan, "worker-b")
+	require.Equal(t, a, strategy.claimOrder(plan, "worker-a"))
+	require.NotEqual(t, a, b)
+	sorted := append([]int(nil), a...)
+	sort.Ints(sorted)
+	require.Equal(t, inOrder(plan), sorted)
+
+	// A chunk with the same first datum is ranked the same in another job
+	other := strategy.plan(testDatums(make([]uint64, 21)...), &pps.ChunkSpec{Number: 2}, 1, 1, nil, testDatumID)
+	var ranked []int
+	for _, i := range strategy.claimOrder(other, "worker-a") {
+		if i < 10 {
+			ranked = append(ranked, i)
+		}
+	}
+	require.Equal(t, a, ranked)
+}
+
+func TestDefaultChunkStrategy(t *testing.T) {
+	df := testDatums(make([]uint64, 10)...)
+	for _, spec := range []*pps.ChunkSpec{nil, {Number: 3}} {
+		strategy := getChunkStrategy(spec)
+		plan := strategy.plan(df, &pps.ChunkSpec{Number: 3}, 1, 1, nil, testDatumID)
+		require.Equal(t, []int64{3, 6, 9, 10}, plan.Chunks)
+		require.Equal(t, []int{0, 1, 2, 3}, strategy.claimOrder(plan, "worker"))
+	}
+}
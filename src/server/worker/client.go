@@ -6,11 +6,13 @@ import (
 	"os"
 	"path"
 	"strconv"
+	"time"
 
 	"github.com/gogo/protobuf/types"
 	"github.com/pachyderm/pachyderm/src/client"
 	"github.com/pachyderm/pachyderm/src/client/debug"
 	"github.com/pachyderm/pachyderm/src/client/pps"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
 
 	etcd "github.com/coreos/etcd/clientv3"
 	"google.golang.org/grpc"
@@ -69,6 +71,73 @@ func Cancel(ctx context.Context, pipelineRcName string, etcdClient *etcd.Client,
 	return nil
 }
 
+// DeleteJobState deletes the plan, chunks (and their journals) and merges that a pipeline's
+// workers stored in etcd while processing the job 'jobID', as part of 'stm'.
+// Jobs' state is normally cleaned up when they finish, but not if they fail.
+func DeleteJobState(stm col.STM, etcdClient *etcd.Client, etcdPrefix string, jobID string) error {
+	plans := col.NewCollection(etcdClient, path.Join(etcdPrefix, planPrefix), nil, &Plan{}, nil, nil)
+	if err := plans.ReadWrite(stm).Delete(jobID); err != nil && !col.IsErrNotFound(err) {
+		return err
+	}
+	col.NewCollection(etcdClient, path.Join(etcdPrefix, chunkPrefix, jobID), nil, &ChunkState{}, nil, nil).ReadWrite(stm).DeleteAll()
+	col.NewCollection(etcdClient, path.Join(etcdPrefix, speculativeChunkPrefix, jobID), nil, &ChunkState{}, nil, nil).ReadWrite(stm).DeleteAll()
+	col.NewCollection(etcdClient, path.Join(etcdPrefix, chunkJournalPrefix, jobID), nil, &ChunkState{}, nil, nil).ReadWrite(stm).DeleteAll()
+	col.NewCollection(etcdClient, path.Join(etcdPrefix, mergePrefix, jobID), nil, &MergeState{}, nil, nil).ReadWrite(stm).DeleteAll()
+	return nil
+}
+
+// Backlog is the work that's left in a running job, according to the job's
+// plan and chunk claims in etcd
+type Backlog struct {
+	// Unclaimed is the number of chunks that no worker has claimed yet
+	Unclaimed int
+	// Running is the number of chunks that workers are processing
+	Running int
+	// ChunkTime is the mean processing time of the job's completed chunks, or
+	// 0 if none has completed
+	ChunkTime time.Duration
+}
+
+// GetBacklog returns the backlog of the job 'jobID'. It returns nil if the job
+// hasn't been planned yet, i.e. its chunks aren't known.
+func GetBacklog(ctx context.Context, etcdClient *etcd.Client, etcdPrefix string, jobID string) (*Backlog, error) {
+	plans := col.NewCollection(etcdClient, path.Join(etcdPrefix, planPrefix), nil, &Plan{}, nil, nil)
+	plan := &Plan{}
+	if err := plans.ReadOnly(ctx).Get(jobID, plan); err != nil {
+		if col.IsErrNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	chunks := col.NewCollection(etcdClient, path.Join(etcdPrefix, chunkPrefix, jobID), nil, &ChunkState{}, nil, nil)
+	backlog := &Backlog{}
+	claimed, completed := 0, 0
+	var processTime time.Duration
+	chunkState := &ChunkState{}
+	if err := chunks.ReadOnly(ctx).List(chunkState, col.DefaultOptions, func(string) error {
+		claimed++
+		switch chunkState.State {
+		case State_RUNNING:
+			backlog.Running++
+		case State_COMPLETE:
+			if d, err := types.DurationFromProto(chunkState.ProcessTime); err == nil {
+				processTime += d
+				completed++
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if claimed < len(plan.Chunks) {
+		backlog.Unclaimed = len(plan.Chunks) - claimed
+	}
+	if completed > 0 {
+		backlog.ChunkTime = processTime / time.Duration(completed)
+	}
+	return backlog, nil
+}
+
 // Conns returns a slice of connections to worker servers.
 // pipelineRcName is the name of the pipeline's RC and can be gotten with
 // ppsutil.PipelineRcName. You can also pass "" for pipelineRcName to get all
@@ -0,0 +1,127 @@
+package worker
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/server/pkg/exec"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// cpuPinningRefresh is how long the CPUs that user code is pinned to are
+// used before this worker's index among its node's workers is looked up
+// again, as workers come and go
+const cpuPinningRefresh = time.Minute
+
+// cpuPinning caches the CPUs that a worker's user code is pinned to (see
+// pps.PipelineInfo.CPUPinning)
+type cpuPinning struct {
+	mu      sync.Mutex
+	cpus    []int
+	updated time.Time
+}
+
+// startUserCode starts 'cmd', which runs user code, pinned to this worker's
+// CPUs if the pipeline sets cpu_pinning. User code that can't be pinned runs
+// on all of the worker's CPUs.
+func (a *APIServer) startUserCode(cmd *exec.Cmd) error {
+	if !a.pipelineInfo.CPUPinning {
+		return cmd.Start()
+	}
+	cpus, err := a.pinnedCPUs()
+	if err != nil {
+		log.Errorf("not pinning user code to CPUs: %v", err)
+		return cmd.Start()
+	}
+	return startPinned(cmd, cpus)
+}
+
+// pinnedCPUs returns the CPUs that this worker's user code is pinned to
+func (a *APIServer) pinnedCPUs() ([]int, error) {
+	a.cpuPinning.mu.Lock()
+	defer a.cpuPinning.mu.Unlock()
+	if a.cpuPinning.cpus != nil && time.Since(a.cpuPinning.updated) < cpuPinningRefresh {
+		return a.cpuPinning.cpus, nil
+	}
+	cpus, err := a.computePinnedCPUs()
+	if err != nil {
+		if a.cpuPinning.cpus == nil {
+			return nil, err
+		}
+		// Keep the CPUs we have until the lookup succeeds
+		log.Errorf("error updating the CPUs that user code is pinned to: %v", err)
+		return a.cpuPinning.cpus, nil
+	}
+	if fmt.Sprint(cpus) != fmt.Sprint(a.cpuPinning.cpus) {
+		log.Infof("pinning user code to CPUs %v", cpus)
+	}
+	a.cpuPinning.cpus = cpus
+	a.cpuPinning.updated = time.Now()
+	return cpus, nil
+}
+
+func (a *APIServer) computePinnedCPUs() ([]int, error) {
+	cpus, err := availableCPUs()
+	if err != nil {
+		return nil, fmt.Errorf("could not get the available CPUs: %v", err)
+	}
+	index, count, err := a.workerIndexOnNode()
+	if err != nil {
+		return nil, err
+	}
+	return partitionCPUs(cpus, index, count), nil
+}
+
+// workerIndexOnNode returns this worker's index among the pipeline's running
+// workers on the same node (in the order of their pod names), and the number
+// of those workers
+func (a *APIServer) workerIndexOnNode() (int, int, error) {
+	pods := a.kubeClient.CoreV1().Pods(a.namespace)
+	pod, err := pods.Get(a.workerName, metav1.GetOptions{})
+	if err != nil {
+		return 0, 0, fmt.Errorf("could not get pod %s: %v", a.workerName, err)
+	}
+	if pod.Spec.NodeName == "" {
+		return 0, 0, fmt.Errorf("pod %s isn't scheduled on a node", a.workerName)
+	}
+	rcName := ppsutil.PipelineRcName(a.pipelineInfo.Pipeline.Name, a.pipelineInfo.Version)
+	podList, err := pods.List(metav1.ListOptions{
+		LabelSelector: metav1.FormatLabelSelector(metav1.SetAsLabelSelector(map[string]string{"app": rcName})),
+		FieldSelector: "spec.nodeName=" + pod.Spec.NodeName,
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("could not list the pipeline's pods on node %s: %v", pod.Spec.NodeName, err)
+	}
+	var names []string
+	for _, p := range podList.Items {
+		if p.DeletionTimestamp != nil || p.Status.Phase == v1.PodSucceeded || p.Status.Phase == v1.PodFailed {
+			continue
+		}
+		names = append(names, p.Name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		if name == a.workerName {
+			return i, len(names), nil
+		}
+	}
+	return 0, 0, fmt.Errorf("pod %s isn't one of the pipeline's pods on node %s", a.workerName, pod.Spec.NodeName)
+}
+
+// partitionCPUs splits 'cpus' into 'count' disjoint, contiguous sets of
+// (nearly) equal size, and returns the 'index'th of them. If there are more
+// workers than CPUs, CPUs are shared round-robin.
+func partitionCPUs(cpus []int, index int, count int) []int {
+	if len(cpus) == 0 || count <= 1 || index < 0 || index >= count {
+		return cpus
+	}
+	if count >= len(cpus) {
+		return []int{cpus[index%len(cpus)]}
+	}
+	return cpus[index*len(cpus)/count : (index+1)*len(cpus)/count]
+}
@@ -0,0 +1,60 @@
+package worker
+
+import (
+	"runtime"
+
+	"github.com/pachyderm/pachyderm/src/server/pkg/exec"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+// availableCPUs returns the CPUs that the worker may run on
+func availableCPUs() ([]int, error) {
+	var set unix.CPUSet
+	if err := unix.SchedGetaffinity(0, &set); err != nil {
+		return nil, err
+	}
+	var cpus []int
+	for cpu := 0; len(cpus) < set.Count(); cpu++ {
+		if set.IsSet(cpu) {
+			cpus = append(cpus, cpu)
+		}
+	}
+	return cpus, nil
+}
+
+// startPinned starts 'cmd' pinned to 'cpus'. A child process inherits the
+// CPU affinity of the thread that forks it, so 'cmd' is started from a locked
+// thread whose affinity is set to 'cpus' (as taskset does) and then restored.
+func startPinned(cmd *exec.Cmd, cpus []int) error {
+	errCh := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+		var old unix.CPUSet
+		if err := unix.SchedGetaffinity(0, &old); err != nil {
+			runtime.UnlockOSThread()
+			log.Errorf("not pinning user code to CPUs: %v", err)
+			errCh <- cmd.Start()
+			return
+		}
+		var set unix.CPUSet
+		for _, cpu := range cpus {
+			set.Set(cpu)
+		}
+		if err := unix.SchedSetaffinity(0, &set); err != nil {
+			runtime.UnlockOSThread()
+			log.Errorf("not pinning user code to CPUs %v: %v", cpus, err)
+			errCh <- cmd.Start()
+			return
+		}
+		errCh <- cmd.Start()
+		if err := unix.SchedSetaffinity(0, &old); err != nil {
+			// The thread is left locked, so that it exits with this goroutine
+			// rather than running other goroutines on 'cpus'
+			log.Errorf("could not restore the CPU affinity of a thread: %v", err)
+			return
+		}
+		runtime.UnlockOSThread()
+	}()
+	return <-errCh
+}
@@ -0,0 +1,25 @@
+// +build !linux
+
+package worker
+
+import (
+	"runtime"
+
+	"github.com/pachyderm/pachyderm/src/server/pkg/exec"
+)
+
+// availableCPUs returns the CPUs that the worker may run on. Workers run on
+// Linux, this is only used in tests.
+func availableCPUs() ([]int, error) {
+	cpus := make([]int, runtime.NumCPU())
+	for i := range cpus {
+		cpus[i] = i
+	}
+	return cpus, nil
+}
+
+// startPinned starts 'cmd' unpinned, as CPU affinity is only supported on
+// Linux
+func startPinned(cmd *exec.Cmd, cpus []int) error {
+	return cmd.Start()
+}
@@ -0,0 +1,52 @@
+package worker
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/server/pkg/exec"
+)
+
+func TestPartitionCPUs(t *testing.T) {
+	cpus := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	require.Equal(t, cpus, partitionCPUs(cpus, 0, 1))
+	require.Equal(t, []int{0, 1, 2, 3, 4}, partitionCPUs(cpus, 0, 2))
+	require.Equal(t, []int{5, 6, 7, 8, 9}, partitionCPUs(cpus, 1, 2))
+	// The sets are disjoint and cover all of the CPUs, even if they can't be
+	// the same size
+	var all []int
+	for i := 0; i < 3; i++ {
+		set := partitionCPUs(cpus, i, 3)
+		require.True(t, len(set) == 3 || len(set) == 4)
+		all = append(all, set...)
+	}
+	require.Equal(t, cpus, all)
+	// More workers than CPUs share them
+	require.Equal(t, []int{3}, partitionCPUs([]int{2, 3}, 3, 4))
+	// An index that's out of range (e.g. a stale count) isn't pinned
+	require.Equal(t, cpus, partitionCPUs(cpus, 2, 2))
+}
+
+func TestStartPinned(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("CPU pinning is only supported on Linux")
+	}
+	cpus, err := availableCPUs()
+	require.NoError(t, err)
+	pinned := cpus[len(cpus)-1:]
+	cmd := exec.Command("grep", "Cpus_allowed_list", "/proc/self/status")
+	stdout := &bytes.Buffer{}
+	cmd.Stdout = stdout
+	require.NoError(t, startPinned(cmd, pinned))
+	require.NoError(t, cmd.Wait())
+	require.Equal(t, fmt.Sprint(pinned[0]), strings.TrimSpace(strings.TrimPrefix(stdout.String(), "Cpus_allowed_list:")))
+
+	// This process's affinity is unchanged
+	after, err := availableCPUs()
+	require.NoError(t, err)
+	require.Equal(t, cpus, after)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/pachyderm/pachyderm/src/client"
 	"github.com/pachyderm/pachyderm/src/client/pfs"
 	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
 
 	"github.com/cevaris/ordered_map"
 )
@@ -63,6 +64,8 @@ func newPFSDatumIterator(pachClient *client.APIClient, input *pps.PFSInput) (Dat
 			Lazy:       input.Lazy,
 			Branch:     input.Branch,
 			EmptyFiles: input.EmptyFiles,
+			ReadAhead:  input.ReadAhead,
+			Mount:      input.Mount,
 		})
 	}
 	// We sort the inputs so that the order is deterministic. Note that it's
@@ -201,10 +204,14 @@ type crossDatumIterator struct {
 	location  int
 }
 
-func newCrossDatumIterator(pachClient *client.APIClient, cross []*pps.Input) (DatumIterator, error) {
+func newCrossDatumIterator(pachClient *client.APIClient, input *pps.Input) (DatumIterator, error) {
+	filter, err := ppsutil.CompileCrossFilter(input)
+	if err != nil {
+		return nil, err
+	}
 	result := &crossDatumIterator{}
 	defer result.Reset()
-	for _, iterator := range cross {
+	for _, iterator := range input.Cross {
 		datumIterator, err := NewDatumIterator(pachClient, iterator)
 		if err != nil {
 			return nil, err
@@ -212,6 +219,10 @@ func newCrossDatumIterator(pachClient *client.APIClient, cross []*pps.Input) (Da
 		result.iterators = append(result.iterators, datumIterator)
 	}
 	result.location = -1
+	if filter != nil {
+		result.Reset()
+		return newFilteredDatumIterator(result, filter), nil
+	}
 	return result, nil
 }
 
@@ -299,6 +310,49 @@ func (d *crossDatumIterator) DatumN(n int) []*Input {
 	return result
 }
 
+// filteredDatumIterator iterates over the datums of a cross input for which
+// its cross filter is true
+type filteredDatumIterator struct {
+	datums   [][]*Input
+	location int
+}
+
+func newFilteredDatumIterator(cross DatumIterator, filter *ppsutil.CrossFilter) DatumIterator {
+	result := &filteredDatumIterator{location: -1}
+	for cross.Next() {
+		datum := cross.Datum()
+		paths := make(map[string]string)
+		for _, input := range datum {
+			paths[input.Name] = input.FileInfo.File.Path
+		}
+		if filter.Match(paths) {
+			result.datums = append(result.datums, datum)
+		}
+	}
+	return result
+}
+
+func (d *filteredDatumIterator) Reset() {
+	d.location = -1
+}
+
+func (d *filteredDatumIterator) Len() int {
+	return len(d.datums)
+}
+
+func (d *filteredDatumIterator) Next() bool {
+	d.location++
+	return d.location < len(d.datums)
+}
+
+func (d *filteredDatumIterator) Datum() []*Input {
+	return d.datums[d.location]
+}
+
+func (d *filteredDatumIterator) DatumN(n int) []*Input {
+	return d.datums[n]
+}
+
 type joinDatumIterator struct {
 	datums   [][]*Input
 	location int
@@ -435,6 +489,18 @@ func newCronDatumIterator(pachClient *client.APIClient, input *pps.CronInput) (D
 	})
 }
 
+// newSQLDatumIterator exposes the latest result of a SQL input's query as a
+// single datum
+func newSQLDatumIterator(pachClient *client.APIClient, input *pps.SQLInput) (DatumIterator, error) {
+	return newPFSDatumIterator(pachClient, &pps.PFSInput{
+		Name:   input.Name,
+		Repo:   input.Repo,
+		Branch: "master",
+		Commit: input.Commit,
+		Glob:   "/",
+	})
+}
+
 // NewDatumIterator creates a datumIterator for an input.
 func NewDatumIterator(pachClient *client.APIClient, input *pps.Input) (DatumIterator, error) {
 	switch {
@@ -443,11 +509,13 @@ func NewDatumIterator(pachClient *client.APIClient, input *pps.Input) (DatumIter
 	case input.Union != nil:
 		return newUnionDatumIterator(pachClient, input.Union)
 	case input.Cross != nil:
-		return newCrossDatumIterator(pachClient, input.Cross)
+		return newCrossDatumIterator(pachClient, input)
 	case input.Join != nil:
 		return newJoinDatumIterator(pachClient, input.Join)
 	case input.Cron != nil:
 		return newCronDatumIterator(pachClient, input.Cron)
+	case input.SQL != nil:
+		return newSQLDatumIterator(pachClient, input.SQL)
 	case input.Git != nil:
 		return newGitDatumIterator(pachClient, input.Git)
 	}
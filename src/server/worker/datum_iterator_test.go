@@ -6,8 +6,10 @@ import (
 	"testing"
 
 	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
 	"github.com/pachyderm/pachyderm/src/client/pkg/require"
 	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
 	tu "github.com/pachyderm/pachyderm/src/server/pkg/testutil"
 )
 
@@ -228,6 +230,32 @@ func BenchmarkDI8(b *testing.B)  { benchmarkDatumIterators(8, b) }
 func BenchmarkDI16(b *testing.B) { benchmarkDatumIterators(16, b) }
 func BenchmarkDI32(b *testing.B) { benchmarkDatumIterators(32, b) }
 
+func TestFilteredDatumIterator(t *testing.T) {
+	inputs := func(name string, paths ...string) []*Input {
+		var result []*Input
+		for _, p := range paths {
+			result = append(result, &Input{
+				Name:     name,
+				FileInfo: &pfs.FileInfo{File: client.NewFile(name, "master", p)},
+			})
+		}
+		return result
+	}
+	cross, err := newCrossListDatumIterator(nil, [][]*Input{
+		inputs("a", "/cat.png", "/dog.png"),
+		inputs("b", "/cat/1.json", "/dog/2.json", "/fish/3.json"),
+	})
+	require.NoError(t, err)
+	input := client.NewCrossInput(
+		client.NewPFSInputOpts("a", "a", "master", "/(*).png", "", false),
+		client.NewPFSInputOpts("b", "b", "master", "/(*)/*.json", "", false),
+	)
+	input.CrossFilter = `group(a, 1) == group(b, 1)`
+	filter, err := ppsutil.CompileCrossFilter(input)
+	require.NoError(t, err)
+	validateDI(t, newFilteredDatumIterator(cross, filter), "/cat.png/cat/1.json", "/dog.png/dog/2.json")
+}
+
 func validateDI(t testing.TB, di DatumIterator, datums ...string) {
 	i := 0
 	clone := di
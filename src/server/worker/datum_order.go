@@ -0,0 +1,103 @@
+package worker
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// orderedDatumIterator iterates over the datums of another iterator in a
+// different order: its i'th datum is the order[i]'th datum of 'datums'
+type orderedDatumIterator struct {
+	datums   DatumIterator
+	order    []int
+	location int
+}
+
+// orderDatums returns an iterator over the datums of 'df' in the order that
+// 'spec' selects (see pps.DatumOrder). The order only depends on the datums
+// and 'jobID', so that the master and all of the workers, which chunk the
+// datums by their indexes, agree on it. 'datumID' returns the ID of a datum.
+func orderDatums(df DatumIterator, spec *pps.ChunkSpec, jobID string, datumID func([]*Input) string) DatumIterator {
+	if spec == nil || spec.DatumOrder == pps.DatumOrder_DATUMS_IN_INPUT_ORDER {
+		return df
+	}
+	order := make([]int, df.Len())
+	for i := range order {
+		order[i] = i
+	}
+	switch spec.DatumOrder {
+	case pps.DatumOrder_DATUMS_BY_HASH:
+		keys := make([]string, df.Len())
+		for i := range keys {
+			keys[i] = datumID(df.DatumN(i))
+		}
+		sort.SliceStable(order, func(i, j int) bool {
+			return keys[order[i]] < keys[order[j]]
+		})
+	case pps.DatumOrder_DATUMS_BY_PATH:
+		keys := make([]string, df.Len())
+		for i := range keys {
+			keys[i] = datumPath(df.DatumN(i))
+		}
+		sort.SliceStable(order, func(i, j int) bool {
+			return keys[order[i]] < keys[order[j]]
+		})
+	case pps.DatumOrder_DATUMS_LARGEST_FIRST:
+		sizes := make([]int64, df.Len())
+		for i := range sizes {
+			sizes[i] = datumSize(df.DatumN(i))
+		}
+		sort.SliceStable(order, func(i, j int) bool {
+			return sizes[order[i]] > sizes[order[j]]
+		})
+	case pps.DatumOrder_DATUMS_SHUFFLED:
+		h := fnv.New64a()
+		h.Write([]byte(jobID))
+		r := rand.New(rand.NewSource(int64(h.Sum64())))
+		r.Shuffle(len(order), func(i, j int) {
+			order[i], order[j] = order[j], order[i]
+		})
+	default:
+		return df
+	}
+	return &orderedDatumIterator{datums: df, order: order, location: -1}
+}
+
+// datumPath returns a key that sorts datums by the names of their inputs and
+// then the paths of their input files. Its inputs are sorted by name already
+// (see sortInputs).
+func datumPath(data []*Input) string {
+	var key strings.Builder
+	for _, input := range data {
+		key.WriteString(input.Name)
+		key.WriteByte(0)
+		key.WriteString(input.FileInfo.File.Path)
+		key.WriteByte(0)
+	}
+	return key.String()
+}
+
+func (d *orderedDatumIterator) Reset() {
+	d.location = -1
+}
+
+func (d *orderedDatumIterator) Len() int {
+	return len(d.order)
+}
+
+func (d *orderedDatumIterator) Next() bool {
+	d.location++
+	return d.location < len(d.order)
+}
+
+func (d *orderedDatumIterator) Datum() []*Input {
+	return d.datums.DatumN(d.order[d.location])
+}
+
+func (d *orderedDatumIterator) DatumN(n int) []*Input {
+	return d.datums.DatumN(d.order[n])
+}
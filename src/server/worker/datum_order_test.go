@@ -0,0 +1,57 @@
+package worker
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// testPathDatums returns an iterator over datums with one input file each, at
+// 'paths', whose sizes are their indexes
+func testPathDatums(paths ...string) DatumIterator {
+	df := &pfsDatumIterator{}
+	for i, path := range paths {
+		df.inputs = append(df.inputs, &Input{
+			Name: "in",
+			FileInfo: &pfs.FileInfo{
+				File:      &pfs.File{Path: path},
+				SizeBytes: uint64(i),
+			},
+		})
+	}
+	return df
+}
+
+func datumPaths(df DatumIterator) []string {
+	var paths []string
+	df.Reset()
+	for df.Next() {
+		paths = append(paths, df.Datum()[0].FileInfo.File.Path)
+	}
+	return paths
+}
+
+func TestOrderDatums(t *testing.T) {
+	df := testPathDatums("/c", "/a", "/d", "/b")
+	pathID := func(data []*Input) string { return data[0].FileInfo.File.Path }
+	order := func(datumOrder pps.DatumOrder, jobID string) DatumIterator {
+		return orderDatums(df, &pps.ChunkSpec{DatumOrder: datumOrder}, jobID, pathID)
+	}
+
+	require.Equal(t, df, orderDatums(df, nil, "job", pathID))
+	require.Equal(t, df, order(pps.DatumOrder_DATUMS_IN_INPUT_ORDER, "job"))
+	require.Equal(t, []string{"/a", "/b", "/c", "/d"}, datumPaths(order(pps.DatumOrder_DATUMS_BY_PATH, "job")))
+	require.Equal(t, []string{"/a", "/b", "/c", "/d"}, datumPaths(order(pps.DatumOrder_DATUMS_BY_HASH, "job")))
+	largest := order(pps.DatumOrder_DATUMS_LARGEST_FIRST, "job")
+	require.Equal(t, []string{"/b", "/d", "/a", "/c"}, datumPaths(largest))
+	require.Equal(t, "/d", largest.DatumN(1)[0].FileInfo.File.Path)
+
+	// Shuffling is the same for every worker, and only changes the order
+	shuffled := datumPaths(order(pps.DatumOrder_DATUMS_SHUFFLED, "job"))
+	require.Equal(t, shuffled, datumPaths(order(pps.DatumOrder_DATUMS_SHUFFLED, "job")))
+	sort.Strings(shuffled)
+	require.Equal(t, []string{"/a", "/b", "/c", "/d"}, shuffled)
+}
@@ -0,0 +1,273 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/gogo/protobuf/types"
+	"google.golang.org/grpc"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/client/processor"
+)
+
+// In pipelines with datum_processor set, the user code isn't run once per
+// datum. The worker starts it once and serves it the DatumProcessor service
+// (see src/client/processor) on client.PPSDatumProcessorSocket, from which
+// it gets each datum in turn. The user code is restarted when it exits, and
+// when a datum times out or is cancelled, as it may be stuck on the datum.
+//
+// The user code's stdout and stderr are logged as the logs of the datum it's
+// processing, if there is one, and as the worker's logs otherwise.
+
+// errProcessorExited is returned for a datum that the user code exited
+// without completing, when it exited cleanly
+var errProcessorExited = errors.New("user code exited before completing the datum")
+
+type datumProcessor struct {
+	a *APIServer
+	// logger logs the user code's output while it isn't processing a datum
+	logger *taggedLogger
+
+	listenOnce sync.Once
+	listenErr  error
+
+	// datums hands the datum being processed to NextDatum
+	datums chan *processor.Datum
+
+	mu sync.Mutex
+	// run is the running user code process, if there is one
+	run *processorRun
+	// current is the datum being processed, if there is one
+	current *processorDatum
+}
+
+// processorRun is a run of the user code
+type processorRun struct {
+	cancel context.CancelFunc
+	// exited is closed when the user code exits, after which err is set
+	exited chan struct{}
+	err    error
+}
+
+// processorDatum is a datum that's been handed to the user code
+type processorDatum struct {
+	datum  *processor.Datum
+	logger *taggedLogger
+	// done receives the datum's result when the user code completes it
+	done chan error
+}
+
+func newDatumProcessor(a *APIServer, logger *taggedLogger) *datumProcessor {
+	return &datumProcessor{
+		a:      a,
+		logger: logger.userLogger(),
+		datums: make(chan *processor.Datum),
+	}
+}
+
+// processorDatum returns the datum that's handed to the user code for 'data'
+func (a *APIServer) processorDatum(logger *taggedLogger, data []*Input, environ []string) *processor.Datum {
+	datum := &processor.Datum{
+		ID:    logger.template.DatumID,
+		JobID: logger.template.JobID,
+	}
+	own := make(map[string]bool)
+	for _, kv := range a.baseEnv() {
+		own[kv] = true
+	}
+	for _, kv := range environ {
+		if !own[kv] {
+			datum.Env = append(datum.Env, kv)
+		}
+	}
+	for _, input := range data {
+		datum.Inputs = append(datum.Inputs, &processor.Input{
+			Name: input.Name,
+			Path: filepath.Join(client.PPSInputPrefix, input.Name, input.FileInfo.File.Path),
+			File: input.FileInfo.File,
+		})
+	}
+	return datum
+}
+
+// process hands 'datum' to the user code, starting it if it isn't running,
+// and waits for the user code to complete it. 'logger' is the datum's
+// logger.
+func (p *datumProcessor) process(ctx context.Context, logger *taggedLogger, datum *processor.Datum) error {
+	p.listenOnce.Do(func() { p.listenErr = p.listen(client.PPSDatumProcessorSocket) })
+	if p.listenErr != nil {
+		return fmt.Errorf("error serving the datum processor socket: %v", p.listenErr)
+	}
+	run := p.start()
+	current := &processorDatum{
+		datum:  datum,
+		logger: logger.userLogger(),
+		done:   make(chan error, 1),
+	}
+	p.setCurrent(current)
+	defer p.setCurrent(nil)
+	select {
+	case p.datums <- datum:
+		select {
+		case err := <-current.done:
+			return err
+		case <-run.exited:
+		case <-ctx.Done():
+		}
+	case <-run.exited:
+	case <-ctx.Done():
+	}
+	if ctx.Err() != nil {
+		// The user code may be stuck on the datum, so it's restarted before
+		// the next one
+		run.cancel()
+		<-run.exited
+		if ctx.Err() == context.DeadlineExceeded {
+			return classify(pps.FailureType_DATUM_TIMEOUT, ctx.Err())
+		}
+		return ctx.Err()
+	}
+	if run.err != nil {
+		return run.err
+	}
+	return errProcessorExited
+}
+
+// start starts the user code if it isn't running, and returns its run
+func (p *datumProcessor) start() *processorRun {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.run != nil {
+		select {
+		case <-p.run.exited:
+		default:
+			return p.run
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	run := &processorRun{cancel: cancel, exited: make(chan struct{})}
+	p.run = run
+	environ := append(p.a.baseEnv(), fmt.Sprintf("%s=%s", client.PPSDatumProcessorSocketEnv, client.PPSDatumProcessorSocket))
+	output := p.output()
+	go func() {
+		defer close(run.exited)
+		defer cancel()
+		p.logf("starting the datum processor")
+		run.err = p.a.execUserCode(ctx, output, output, environ)
+		p.logf("the datum processor exited: %v", run.err)
+	}()
+	return run
+}
+
+// listen serves the DatumProcessor service on the unix socket 'socketPath'
+func (p *datumProcessor) listen(socketPath string) error {
+	// the socket may be left over from a previous run of the worker
+	if err := os.Remove(socketPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return err
+	}
+	// the user code may run as a different user
+	if err := os.Chmod(socketPath, 0777); err != nil {
+		listener.Close()
+		return err
+	}
+	server := grpc.NewServer(grpc.MaxRecvMsgSize(grpcutil.MaxMsgSize), grpc.MaxSendMsgSize(grpcutil.MaxMsgSize))
+	processor.RegisterDatumProcessorServer(server, p)
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			p.logf("error serving the datum processor socket: %v", err)
+		}
+	}()
+	return nil
+}
+
+func (p *datumProcessor) setCurrent(current *processorDatum) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.current = current
+}
+
+// logf logs a line to the worker's logs
+func (p *datumProcessor) logf(format string, args ...interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.logger.Logf(format, args...)
+}
+
+// output returns the writer that the user code's stdout and stderr are
+// written to
+func (p *datumProcessor) output() io.Writer {
+	return writerFunc(func(b []byte) (int, error) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if p.current != nil {
+			return p.current.logger.Write(b)
+		}
+		return p.logger.Write(b)
+	})
+}
+
+// NextDatum implements the DatumProcessor service
+func (p *datumProcessor) NextDatum(ctx context.Context, _ *types.Empty) (*processor.Datum, error) {
+	select {
+	case datum := <-p.datums:
+		return datum, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// CompleteDatum implements the DatumProcessor service
+func (p *datumProcessor) CompleteDatum(ctx context.Context, request *processor.CompleteDatumRequest) (*types.Empty, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.current == nil || p.current.datum.ID != request.DatumID {
+		return nil, fmt.Errorf("datum %q isn't being processed", request.DatumID)
+	}
+	var err error
+	if request.Error != "" {
+		err = classify(pps.FailureType_USER_CODE_ERROR, fmt.Errorf("user code failed to process the datum: %s", request.Error))
+	}
+	select {
+	case p.current.done <- err:
+	default:
+		return nil, fmt.Errorf("datum %q was already completed", request.DatumID)
+	}
+	return &types.Empty{}, nil
+}
+
+// Log implements the DatumProcessor service
+func (p *datumProcessor) Log(server processor.DatumProcessor_LogServer) error {
+	for {
+		msg, err := server.Recv()
+		if err == io.EOF {
+			return server.SendAndClose(&types.Empty{})
+		}
+		if err != nil {
+			return err
+		}
+		func() {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			// Lines for datums that are no longer being processed are logged
+			// as the worker's
+			if p.current != nil && p.current.datum.ID == msg.DatumID {
+				p.current.logger.Logf("%s", msg.Message)
+				return
+			}
+			p.logger.Logf("%s", msg.Message)
+		}()
+	}
+}
@@ -0,0 +1,83 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/jsonpb"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/client/processor"
+)
+
+func TestDatumProcessor(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("datum processors are served on a unix socket")
+	}
+	dir, err := ioutil.TempDir("", "datum-processor")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	socketPath := filepath.Join(dir, "processor.sock")
+
+	tail := newLogTail()
+	newLogger := func() *taggedLogger {
+		return &taggedLogger{marshaler: &jsonpb.Marshaler{}, msgCh: make(chan string, logBuffer), tail: tail}
+	}
+	p := newDatumProcessor(nil, newLogger())
+	p.listenOnce.Do(func() { p.listenErr = p.listen(socketPath) })
+	require.NoError(t, p.listenErr)
+	// The user code is run by the test, rather than started by process
+	run := &processorRun{exited: make(chan struct{})}
+	run.cancel = func() { close(run.exited) }
+	p.run = run
+
+	os.Setenv(client.PPSDatumProcessorSocketEnv, socketPath)
+	defer os.Unsetenv(client.PPSDatumProcessorSocketEnv)
+	go processor.Run(func(datum *processor.Datum, logf func(string, ...interface{})) error {
+		logf("processing %s", datum.ID)
+		switch datum.ID {
+		case "bad":
+			return errors.New("bad datum")
+		case "stuck":
+			select {}
+		}
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	require.NoError(t, p.process(ctx, newLogger(), &processor.Datum{ID: "good"}))
+	err = p.process(ctx, newLogger(), &processor.Datum{ID: "bad"})
+	require.YesError(t, err)
+	require.Equal(t, pps.FailureType_USER_CODE_ERROR, failureType(err))
+	require.True(t, strings.Contains(err.Error(), "bad datum"))
+	var logged []string
+	for _, line := range tail.get() {
+		if strings.Contains(line, "processing") {
+			logged = append(logged, line)
+		}
+	}
+	require.Equal(t, 2, len(logged))
+	require.Matches(t, "processing good", logged[0])
+
+	// a datum that times out kills the user code
+	timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	err = p.process(timeoutCtx, newLogger(), &processor.Datum{ID: "stuck"})
+	require.YesError(t, err)
+	require.Equal(t, pps.FailureType_DATUM_TIMEOUT, failureType(err))
+	select {
+	case <-run.exited:
+	default:
+		t.Fatal("user code wasn't killed")
+	}
+}
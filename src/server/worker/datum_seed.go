@@ -0,0 +1,17 @@
+package worker
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+)
+
+// datumSeed returns the PACH_DATUM_SEED of the datum with hash 'tag' (see
+// pps.Transform.DatumSeed). It's derived from the hash alone, so every try of
+// the datum, on any worker and in any job of the pipeline, gets the same
+// seed. It fits in a signed 64-bit integer, so that user code in any language
+// can parse it.
+func datumSeed(tag string) uint64 {
+	h := sha256.Sum256([]byte("seed:" + tag))
+	return binary.BigEndian.Uint64(h[:8]) & math.MaxInt64
+}
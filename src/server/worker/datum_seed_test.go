@@ -0,0 +1,21 @@
+package worker
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestDatumSeed(t *testing.T) {
+	seeds := make(map[uint64]bool)
+	for i := 0; i < 1000; i++ {
+		tag := fmt.Sprintf("datum-%d", i)
+		seed := datumSeed(tag)
+		require.Equal(t, seed, datumSeed(tag))
+		require.True(t, seed <= math.MaxInt64)
+		seeds[seed] = true
+	}
+	require.Equal(t, 1000, len(seeds))
+}
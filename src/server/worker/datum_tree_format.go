@@ -0,0 +1,91 @@
+package worker
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// datumTreeMagic starts the header of datum hashtrees that are compressed.
+// Uncompressed datum hashtrees (the only kind written by older workers) have
+// no header, and start with the little-endian int64 length of their first
+// record, which is never anywhere near as large as the magic and version
+// read as one, so readers can tell the two apart.
+const datumTreeMagic = "PDTREE"
+
+// datumTreeVersion is the version of the datum hashtree header, which follows
+// datumTreeMagic and is followed by the tree's pps.DatumTreeCompression
+const datumTreeVersion = 1
+
+// datumTreeHeaderSize is the size of the magic, version and compression
+const datumTreeHeaderSize = len(datumTreeMagic) + 2
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+// newDatumTreeWriter returns a writer that writes a serialized datum hashtree
+// to 'w' with 'compression'. The returned writer must be closed to flush the
+// tree, which doesn't close 'w'.
+func newDatumTreeWriter(w io.Writer, compression pps.DatumTreeCompression) (io.WriteCloser, error) {
+	if compression == pps.DatumTreeCompression_TREE_UNCOMPRESSED {
+		// Uncompressed trees are written without a header, so that workers
+		// that predate compression can still read them
+		return nopWriteCloser{w}, nil
+	}
+	header := append([]byte(datumTreeMagic), datumTreeVersion, byte(compression))
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+	switch compression {
+	case pps.DatumTreeCompression_TREE_SNAPPY:
+		return snappy.NewBufferedWriter(w), nil
+	case pps.DatumTreeCompression_TREE_ZSTD:
+		return zstd.NewWriter(w)
+	}
+	return nil, fmt.Errorf("unknown datum tree compression %v", compression)
+}
+
+// copyDatumTree copies the datum hashtree read from 'r' to 'w', serialized
+// as it was before it was compressed
+func copyDatumTree(w io.Writer, r io.Reader) error {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(datumTreeHeaderSize)
+	if err != nil && err != io.EOF {
+		return err
+	}
+	if len(header) < datumTreeHeaderSize || !bytes.HasPrefix(header, []byte(datumTreeMagic)) {
+		// The tree has no header, so it's uncompressed
+		_, err := io.Copy(w, br)
+		return err
+	}
+	if version := header[len(datumTreeMagic)]; version != datumTreeVersion {
+		return fmt.Errorf("datum tree has version %d, which is newer than this worker's (%d)", version, datumTreeVersion)
+	}
+	compression := pps.DatumTreeCompression(header[len(datumTreeMagic)+1])
+	if _, err := br.Discard(datumTreeHeaderSize); err != nil {
+		return err
+	}
+	switch compression {
+	case pps.DatumTreeCompression_TREE_SNAPPY:
+		_, err := io.Copy(w, snappy.NewReader(br))
+		return err
+	case pps.DatumTreeCompression_TREE_ZSTD:
+		zr, err := zstd.NewReader(br, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return err
+		}
+		defer zr.Close()
+		_, err = io.Copy(w, zr)
+		return err
+	}
+	return fmt.Errorf("datum tree has unknown compression %d", compression)
+}
@@ -0,0 +1,44 @@
+package worker
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/hashtree"
+)
+
+func TestDatumTreeFormat(t *testing.T) {
+	tree := hashtree.NewOrdered("/")
+	tree.PutFile("/c", []byte("c"), 1, &hashtree.FileNodeProto{})
+	tree.PutDir("/dir")
+	for _, name := range []string{"/dir/a", "/dir/b"} {
+		tree.PutFile(name, []byte(name), int64(len(name)), &hashtree.FileNodeProto{})
+	}
+	serialized := &bytes.Buffer{}
+	require.NoError(t, tree.Serialize(serialized))
+
+	for compression := range pps.DatumTreeCompression_name {
+		written := &bytes.Buffer{}
+		w, err := newDatumTreeWriter(written, pps.DatumTreeCompression(compression))
+		require.NoError(t, err)
+		_, err = w.Write(serialized.Bytes())
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		if compression == int32(pps.DatumTreeCompression_TREE_UNCOMPRESSED) {
+			// uncompressed trees are written as older workers wrote them
+			require.Equal(t, serialized.Bytes(), written.Bytes())
+		}
+		read := &bytes.Buffer{}
+		require.NoError(t, copyDatumTree(read, written))
+		require.Equal(t, serialized.Bytes(), read.Bytes())
+	}
+
+	// empty trees, and trees from newer workers
+	read := &bytes.Buffer{}
+	require.NoError(t, copyDatumTree(read, &bytes.Buffer{}))
+	require.Equal(t, 0, read.Len())
+	newer := append([]byte(datumTreeMagic), datumTreeVersion+1, byte(pps.DatumTreeCompression_TREE_ZSTD))
+	require.YesError(t, copyDatumTree(read, bytes.NewReader(newer)))
+}
@@ -0,0 +1,209 @@
+package worker
+
+import (
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/robfig/cron"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/watch"
+)
+
+// deferredCommits wraps the output commit iterator of a pipeline that defers
+// processing (see pps.Defer). New output commits are held back until the
+// pipeline is triggered, at which point all of the pending commits but the
+// newest are finished without a job, and the newest (whose provenance
+// includes all of the pending input) is passed on to the job spawner.
+type deferredCommits struct {
+	a          *APIServer
+	pachClient *client.APIClient
+	iter       client.CommitInfoIterator
+	spec       *pps.Defer
+	schedule   cron.Schedule
+
+	commits  chan *pfs.CommitInfo
+	errs     chan error
+	triggers chan time.Time
+
+	// triggered is when RunPipeline last triggered the pipeline
+	triggered time.Time
+	// pending are the output commits that are held back, in order
+	pending []*pfs.CommitInfo
+	// ready are the commits to return from Next, in order
+	ready []*pfs.CommitInfo
+}
+
+func (a *APIServer) newDeferredCommits(pachClient *client.APIClient, iter client.CommitInfoIterator) (*deferredCommits, error) {
+	d := &deferredCommits{
+		a:          a,
+		pachClient: pachClient,
+		iter:       iter,
+		spec:       a.pipelineInfo.Defer,
+		commits:    make(chan *pfs.CommitInfo),
+		errs:       make(chan error, 2),
+		triggers:   make(chan time.Time),
+	}
+	if d.spec.CronSpec != "" {
+		schedule, err := cron.ParseStandard(d.spec.CronSpec)
+		if err != nil {
+			return nil, err
+		}
+		d.schedule = schedule
+	}
+	go func() {
+		for {
+			commitInfo, err := iter.Next()
+			if err != nil {
+				d.errs <- err
+				return
+			}
+			select {
+			case d.commits <- commitInfo:
+			case <-pachClient.Ctx().Done():
+				return
+			}
+		}
+	}()
+	go d.watchTriggers()
+	return d, nil
+}
+
+// watchTriggers sends the times that RunPipeline triggers the pipeline to
+// d.triggers, starting with the last time it was triggered
+func (d *deferredCommits) watchTriggers() {
+	ctx := d.pachClient.Ctx()
+	if err := d.a.pipelines.ReadOnly(ctx).WatchOneF(d.a.pipelineInfo.Pipeline.Name, func(e *watch.Event) error {
+		if e.Type == watch.EventError {
+			return e.Err
+		}
+		if e.Type != watch.EventPut {
+			return nil
+		}
+		var key string
+		ptr := &pps.EtcdPipelineInfo{}
+		if err := e.Unmarshal(&key, ptr); err != nil {
+			return err
+		}
+		if ptr.Triggered == nil {
+			return nil
+		}
+		triggered, err := types.TimestampFromProto(ptr.Triggered)
+		if err != nil {
+			return err
+		}
+		select {
+		case d.triggers <- triggered:
+		case <-ctx.Done():
+		}
+		return nil
+	}); err != nil && ctx.Err() == nil {
+		d.errs <- err
+	}
+}
+
+// Next returns the next output commit that the job spawner should handle
+func (d *deferredCommits) Next() (*pfs.CommitInfo, error) {
+	logger := d.a.getMasterLogger()
+	for len(d.ready) == 0 {
+		var timer *time.Timer
+		var cronTick <-chan time.Time
+		if d.schedule != nil && len(d.pending) > 0 {
+			timer = time.NewTimer(time.Until(d.schedule.Next(time.Now())))
+			cronTick = timer.C
+		}
+		cronFired := false
+		select {
+		case commitInfo := <-d.commits:
+			pending, err := d.isPending(commitInfo)
+			if err != nil {
+				return nil, err
+			}
+			if pending {
+				d.pending = append(d.pending, commitInfo)
+				logger.Logf("deferring output commit %q (%d commits pending)", commitInfo.Commit.ID, len(d.pending))
+			} else {
+				// finished commits and commits that already have a job
+				// (e.g. from before the master restarted) are handled as usual
+				d.ready = append(d.ready, commitInfo)
+			}
+		case triggered := <-d.triggers:
+			d.triggered = triggered
+		case <-cronTick:
+			cronFired = true
+		case err := <-d.errs:
+			return nil, err
+		case <-d.pachClient.Ctx().Done():
+			return nil, d.pachClient.Ctx().Err()
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if n := triggeredCommits(d.pending, d.spec, d.triggered, cronFired); n > 0 {
+			if err := d.release(n); err != nil {
+				return nil, err
+			}
+		}
+	}
+	commitInfo := d.ready[0]
+	d.ready = d.ready[1:]
+	return commitInfo, nil
+}
+
+// Close closes the underlying commit iterator
+func (d *deferredCommits) Close() {
+	d.iter.Close()
+}
+
+// isPending returns true if 'commitInfo' is an output commit that's waiting
+// for the pipeline to be triggered
+func (d *deferredCommits) isPending(commitInfo *pfs.CommitInfo) (bool, error) {
+	if commitInfo.Finished != nil {
+		return false, nil
+	}
+	jobInfos, err := d.pachClient.ListJob("", nil, commitInfo.Commit, -1, true)
+	if err != nil {
+		return false, err
+	}
+	return len(jobInfos) == 0, nil
+}
+
+// release passes the first 'n' pending commits on to the job spawner. All but
+// the last are finished empty, so that only one job processes their input.
+func (d *deferredCommits) release(n int) error {
+	commits := d.pending[:n]
+	last := commits[len(commits)-1]
+	for _, commitInfo := range commits[:len(commits)-1] {
+		if _, err := d.pachClient.PfsAPIClient.FinishCommit(d.pachClient.Ctx(), &pfs.FinishCommitRequest{
+			Commit: commitInfo.Commit,
+			Empty:  true,
+		}); err != nil && !pfs.IsCommitFinishedErr(err) {
+			return err
+		}
+	}
+	d.a.getMasterLogger().Logf("pipeline triggered, processing %d pending commits in output commit %q", n, last.Commit.ID)
+	d.ready = append(d.ready, commits...)
+	d.pending = d.pending[n:]
+	return nil
+}
+
+// triggeredCommits returns how many of the 'pending' output commits the
+// pipeline has been triggered to process: all of them if there are at least
+// spec.Commits or the cron schedule fired, or else those started before
+// RunPipeline last triggered the pipeline.
+func triggeredCommits(pending []*pfs.CommitInfo, spec *pps.Defer, triggered time.Time, cronFired bool) int {
+	if cronFired || (spec.Commits > 0 && int64(len(pending)) >= spec.Commits) {
+		return len(pending)
+	}
+	n := 0
+	for _, commitInfo := range pending {
+		started, err := types.TimestampFromProto(commitInfo.Started)
+		if err != nil || started.After(triggered) {
+			break
+		}
+		n++
+	}
+	return n
+}
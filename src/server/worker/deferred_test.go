@@ -0,0 +1,32 @@
+package worker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestTriggeredCommits(t *testing.T) {
+	start := time.Now()
+	var pending []*pfs.CommitInfo
+	for i := 0; i < 3; i++ {
+		started, err := types.TimestampProto(start.Add(time.Duration(i) * time.Minute))
+		require.NoError(t, err)
+		pending = append(pending, &pfs.CommitInfo{Started: started})
+	}
+
+	// Nothing is processed until the pipeline is triggered
+	require.Equal(t, 0, triggeredCommits(pending, &pps.Defer{}, time.Time{}, false))
+	// RunPipeline triggers the commits that started before it was called
+	require.Equal(t, 2, triggeredCommits(pending, &pps.Defer{}, start.Add(90*time.Second), false))
+	require.Equal(t, 0, triggeredCommits(pending, &pps.Defer{}, start.Add(-time.Second), false))
+	// The trigger conditions trigger all of the pending commits
+	require.Equal(t, 3, triggeredCommits(pending, &pps.Defer{}, time.Time{}, true))
+	require.Equal(t, 3, triggeredCommits(pending, &pps.Defer{Commits: 3}, time.Time{}, false))
+	require.Equal(t, 0, triggeredCommits(pending, &pps.Defer{Commits: 4}, time.Time{}, false))
+}
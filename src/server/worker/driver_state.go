@@ -0,0 +1,150 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"sort"
+	"sync"
+
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client"
+)
+
+// logTailSize is the number of log lines that a worker keeps for its driver
+// state
+const logTailSize = 200
+
+// logTail holds a worker's most recent log lines
+type logTail struct {
+	mu    sync.Mutex
+	lines []string
+	// next is the index in lines that the next line is written to, once
+	// lines is full
+	next int
+}
+
+func newLogTail() *logTail {
+	return &logTail{lines: make([]string, 0, logTailSize)}
+}
+
+func (t *logTail) add(line string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.lines) < logTailSize {
+		t.lines = append(t.lines, line)
+		return
+	}
+	t.lines[t.next] = line
+	t.next = (t.next + 1) % logTailSize
+}
+
+// get returns the lines in 't', oldest first
+func (t *logTail) get() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	result := make([]string, 0, len(t.lines))
+	result = append(result, t.lines[t.next:]...)
+	return append(result, t.lines[:t.next]...)
+}
+
+// chunkClaims tracks the chunks that a worker has claimed
+type chunkClaims struct {
+	mu     sync.Mutex
+	claims map[*ClaimedChunk]bool
+}
+
+func newChunkClaims() *chunkClaims {
+	return &chunkClaims{claims: make(map[*ClaimedChunk]bool)}
+}
+
+// add records that the worker claimed the chunk of job 'jobID' with datums
+// [low, high). The returned function removes it, once the worker's claim is
+// released.
+func (c *chunkClaims) add(jobID string, low, high int64, speculative bool) func() {
+	claim := &ClaimedChunk{
+		JobID:       jobID,
+		Low:         low,
+		High:        high,
+		Speculative: speculative,
+		Claimed:     types.TimestampNow(),
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.claims[claim] = true
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(c.claims, claim)
+	}
+}
+
+// get returns the claimed chunks, ordered by job and datum range
+func (c *chunkClaims) get() []*ClaimedChunk {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var result []*ClaimedChunk
+	for claim := range c.claims {
+		result = append(result, claim)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].JobID != result[j].JobID {
+			return result[i].JobID < result[j].JobID
+		}
+		if result[i].High != result[j].High {
+			return result[i].High < result[j].High
+		}
+		return !result[i].Speculative && result[j].Speculative
+	})
+	return result
+}
+
+// DriverState returns a snapshot of the worker's internal state, for
+// debugging
+func (a *APIServer) DriverState(ctx context.Context, _ *types.Empty) (*DriverState, error) {
+	status, err := a.Status(ctx, &types.Empty{})
+	if err != nil {
+		return nil, err
+	}
+	scratchBytes, err := diskUsage(filepath.Join(client.PPSInputPrefix, client.PPSScratchSpace))
+	if err != nil {
+		return nil, fmt.Errorf("could not measure scratch space: %v", err)
+	}
+	var goroutines bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&goroutines, 2); err != nil {
+		return nil, err
+	}
+	return &DriverState{
+		Status:        status,
+		ClaimedChunks: a.claims.get(),
+		ScratchBytes:  scratchBytes,
+		LogTail:       a.logTail.get(),
+		Goroutines:    goroutines.Bytes(),
+	}, nil
+}
+
+// diskUsage returns the size of the regular files under 'dir', and 0 if
+// 'dir' doesn't exist. Lazy inputs are named pipes, and aren't counted.
+func diskUsage(dir string) (int64, error) {
+	var size int64
+	if err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				// datums are removed as they finish
+				return nil
+			}
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+	return size, nil
+}
@@ -0,0 +1,53 @@
+package worker
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestLogTail(t *testing.T) {
+	tail := newLogTail()
+	require.Equal(t, 0, len(tail.get()))
+	for i := 0; i < logTailSize+10; i++ {
+		tail.add(fmt.Sprint(i))
+	}
+	lines := tail.get()
+	require.Equal(t, logTailSize, len(lines))
+	require.Equal(t, "10", lines[0])
+	require.Equal(t, fmt.Sprint(logTailSize+9), lines[len(lines)-1])
+}
+
+func TestChunkClaims(t *testing.T) {
+	claims := newChunkClaims()
+	release := claims.add("job", 10, 20, true)
+	claims.add("job", 0, 10, false)
+	claims.add("job", 10, 20, false)
+	chunks := claims.get()
+	require.Equal(t, 3, len(chunks))
+	require.Equal(t, int64(10), chunks[0].High)
+	require.False(t, chunks[1].Speculative)
+	require.True(t, chunks[2].Speculative)
+	release()
+	require.Equal(t, 2, len(claims.get()))
+}
+
+func TestDiskUsage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "disk_usage")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	size, err := diskUsage(filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	require.Equal(t, int64(0), size)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "datum", "input"), 0777))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "datum", "input", "a"), []byte("foo"), 0666))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "datum", "b"), []byte("barbaz"), 0666))
+	size, err = diskUsage(dir)
+	require.NoError(t, err)
+	require.Equal(t, int64(9), size)
+}
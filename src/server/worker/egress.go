@@ -0,0 +1,165 @@
+package worker
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/obj"
+	pfs_sync "github.com/pachyderm/pachyderm/src/server/pkg/sync"
+)
+
+// egressDriver copies a job's finished output commit to an external system.
+// It's retried if it fails, so it must be idempotent.
+type egressDriver interface {
+	egress(pachClient *client.APIClient, commit *pfs.Commit) error
+}
+
+func newEgressDriver(egress *pps.Egress) (egressDriver, error) {
+	switch {
+	case egress.Postgres != nil:
+		return &postgresEgressDriver{egress.Postgres}, nil
+	case egress.URL != "":
+		url, err := obj.ParseURL(egress.URL)
+		if err != nil {
+			return nil, err
+		}
+		return &objectEgressDriver{url}, nil
+	default:
+		return nil, fmt.Errorf("egress must set a URL or a Postgres database")
+	}
+}
+
+// objectEgressDriver copies the output commit to object storage
+type objectEgressDriver struct {
+	url *obj.ObjectStoreURL
+}
+
+func (d *objectEgressDriver) egress(pachClient *client.APIClient, commit *pfs.Commit) error {
+	objClient, err := obj.NewClientFromURLAndSecret(d.url, false)
+	if err != nil {
+		return err
+	}
+	return pfs_sync.PushObj(pachClient, commit, objClient, d.url.Object)
+}
+
+// postgresEgressDriver loads the output commit into the tables of a Postgres
+// database
+type postgresEgressDriver struct {
+	spec *pps.PostgresEgress
+}
+
+// egressTable is a table that a postgresEgressDriver loads, and the files that
+// it loads into it
+type egressTable struct {
+	name  string
+	files []string
+}
+
+func (d *postgresEgressDriver) egress(pachClient *client.APIClient, commit *pfs.Commit) (retErr error) {
+	ctx := pachClient.Ctx()
+	config, err := pgx.ParseConfig(d.spec.URL)
+	if err != nil {
+		return fmt.Errorf("error parsing the database URL: %v", err)
+	}
+	if d.spec.PasswordEnv != "" {
+		config.Password = os.Getenv(d.spec.PasswordEnv)
+	}
+	tables, err := d.tables(pachClient, commit)
+	if err != nil {
+		return err
+	}
+	conn, err := pgx.ConnectConfig(ctx, config)
+	if err != nil {
+		return fmt.Errorf("error connecting to the database: %v", err)
+	}
+	defer conn.Close(ctx)
+	// load every table in one transaction, so that a failed attempt doesn't
+	// leave the database half-updated
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if retErr != nil {
+			tx.Rollback(ctx)
+		}
+	}()
+	for _, table := range tables {
+		if _, err := tx.Exec(ctx, "DELETE FROM "+pgx.Identifier{table.name}.Sanitize()); err != nil {
+			return fmt.Errorf("error clearing table %q: %v", table.name, err)
+		}
+		for _, file := range table.files {
+			if err := d.copyFile(pachClient, conn, commit, table.name, file); err != nil {
+				return fmt.Errorf("error loading %s into table %q: %v", file, table.name, err)
+			}
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// tables maps each top-level file or directory of 'commit' to the table that
+// it's loaded into
+func (d *postgresEgressDriver) tables(pachClient *client.APIClient, commit *pfs.Commit) ([]*egressTable, error) {
+	fileInfos, err := pachClient.ListFile(commit.Repo.Name, commit.ID, "/")
+	if err != nil {
+		return nil, err
+	}
+	var result []*egressTable
+	for _, fileInfo := range fileInfos {
+		table := &egressTable{name: tableName(fileInfo.File.Path)}
+		if fileInfo.FileType == pfs.FileType_FILE {
+			table.files = []string{fileInfo.File.Path}
+		} else if err := pachClient.Walk(commit.Repo.Name, commit.ID, fileInfo.File.Path, func(fi *pfs.FileInfo) error {
+			if fi.FileType == pfs.FileType_FILE {
+				table.files = append(table.files, fi.File.Path)
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+		result = append(result, table)
+	}
+	return result, nil
+}
+
+func (d *postgresEgressDriver) copyFile(pachClient *client.APIClient, conn *pgx.Conn, commit *pfs.Commit, table, file string) error {
+	var options []string
+	switch d.spec.FileFormat {
+	case "", "csv":
+		options = append(options, "FORMAT csv")
+		if d.spec.Header {
+			options = append(options, "HEADER true")
+		}
+	case "text":
+		options = append(options, "FORMAT text")
+	default:
+		return fmt.Errorf("unsupported file format %q", d.spec.FileFormat)
+	}
+	r, w := io.Pipe()
+	go func() {
+		w.CloseWithError(pachClient.GetFile(commit.Repo.Name, commit.ID, file, 0, 0, w))
+	}()
+	defer r.Close()
+	// the file is streamed to the server as is, so that it's parsed by
+	// Postgres itself
+	_, err := conn.PgConn().CopyFrom(pachClient.Ctx(), r, fmt.Sprintf("COPY %s FROM STDIN WITH (%s)", pgx.Identifier{table}.Sanitize(), strings.Join(options, ", ")))
+	return err
+}
+
+// tableName returns the name of the table that the top-level file or
+// directory at 'p' is loaded into: its name without its extension
+func tableName(p string) string {
+	name := path.Base(p)
+	if ext := path.Ext(name); ext != "" && ext != name {
+		name = strings.TrimSuffix(name, ext)
+	}
+	return name
+}
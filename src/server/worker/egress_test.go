@@ -0,0 +1,32 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestNewEgressDriver(t *testing.T) {
+	driver, err := newEgressDriver(&pps.Egress{URL: "s3://bucket/dir"})
+	require.NoError(t, err)
+	objDriver, ok := driver.(*objectEgressDriver)
+	require.True(t, ok)
+	require.Equal(t, "bucket", objDriver.url.Bucket)
+	require.Equal(t, "dir", objDriver.url.Object)
+
+	driver, err = newEgressDriver(&pps.Egress{Postgres: &pps.PostgresEgress{URL: "postgres://loader@db/warehouse"}})
+	require.NoError(t, err)
+	_, ok = driver.(*postgresEgressDriver)
+	require.True(t, ok)
+
+	_, err = newEgressDriver(&pps.Egress{})
+	require.YesError(t, err)
+}
+
+func TestTableName(t *testing.T) {
+	require.Equal(t, "users", tableName("/users.csv"))
+	require.Equal(t, "users", tableName("/users"))
+	require.Equal(t, "users.2020", tableName("/users.2020.csv"))
+	require.Equal(t, ".hidden", tableName("/.hidden"))
+}
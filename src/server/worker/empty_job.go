@@ -0,0 +1,82 @@
+package worker
+
+import (
+	"fmt"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/errutil"
+)
+
+// maxEmptyJobSamplePaths is the most files of each input that are sampled in
+// a job's EmptyJobReason
+const maxEmptyJobSamplePaths = 10
+
+// emptyJobReason explains why 'input' produced no datums. Each of its PFS
+// inputs is described with the datums that it produces on its own and a few
+// of the files in its commit.
+func emptyJobReason(pachClient *client.APIClient, input *pps.Input) (*pps.EmptyJobReason, error) {
+	reason := &pps.EmptyJobReason{}
+	var visitErr error
+	pps.VisitInput(input, func(input *pps.Input) {
+		if input.Pfs == nil || visitErr != nil {
+			return
+		}
+		emptyInput := &pps.EmptyJobInput{
+			Name: input.Pfs.Name,
+			Glob: input.Pfs.Glob,
+		}
+		reason.Inputs = append(reason.Inputs, emptyInput)
+		if input.Pfs.Commit == "" {
+			return // the input's branch has no commits
+		}
+		emptyInput.Commit = client.NewCommit(input.Pfs.Repo, input.Pfs.Commit)
+		df, err := NewDatumIterator(pachClient, &pps.Input{Pfs: input.Pfs})
+		if err != nil {
+			visitErr = err
+			return
+		}
+		emptyInput.Datums = int64(df.Len())
+		if err := pachClient.Walk(input.Pfs.Repo, input.Pfs.Commit, "/", func(fi *pfs.FileInfo) error {
+			if fi.FileType != pfs.FileType_FILE {
+				return nil
+			}
+			emptyInput.SamplePaths = append(emptyInput.SamplePaths, fi.File.Path)
+			if len(emptyInput.SamplePaths) >= maxEmptyJobSamplePaths {
+				return errutil.ErrBreak
+			}
+			return nil
+		}); err != nil {
+			visitErr = err
+			return
+		}
+	})
+	if visitErr != nil {
+		return nil, visitErr
+	}
+	reason.Message = emptyJobMessage(reason.Inputs)
+	return reason, nil
+}
+
+// emptyJobMessage summarizes why a job with 'inputs' has no datums: the first
+// input that has no datums on its own, or the way that they're combined, if
+// they all have some
+func emptyJobMessage(inputs []*pps.EmptyJobInput) string {
+	if len(inputs) == 0 {
+		return "the job has no PFS inputs"
+	}
+	for _, input := range inputs {
+		switch {
+		case input.Commit == nil:
+			return fmt.Sprintf("input %q has no commits", input.Name)
+		case input.Datums > 0:
+			continue
+		case len(input.SamplePaths) == 0:
+			return fmt.Sprintf("input %q is empty (%s@%s has no files)", input.Name, input.Commit.Repo.Name, input.Commit.ID)
+		default:
+			return fmt.Sprintf("glob %q of input %q matched no files in %s@%s", input.Glob, input.Name, input.Commit.Repo.Name, input.Commit.ID)
+		}
+	}
+	return "each of the job's inputs has datums, but combining them produced none (e.g. the files of a join's inputs have no join_on values in common)"
+}
@@ -0,0 +1,33 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestEmptyJobMessage(t *testing.T) {
+	require.Equal(t, "the job has no PFS inputs", emptyJobMessage(nil))
+
+	images := &pps.EmptyJobInput{
+		Name:        "images",
+		Commit:      client.NewCommit("images", "abc"),
+		Glob:        "/*.png",
+		SamplePaths: []string{"/a.jpg", "/b.jpg"},
+	}
+	require.Equal(t, `glob "/*.png" of input "images" matched no files in images@abc`, emptyJobMessage([]*pps.EmptyJobInput{images}))
+
+	// The first input without datums is the reason
+	labels := &pps.EmptyJobInput{Name: "labels", Commit: client.NewCommit("labels", "def"), Glob: "/*", Datums: 2}
+	require.Equal(t, `input "empty" is empty (empty@ghi has no files)`, emptyJobMessage([]*pps.EmptyJobInput{
+		labels,
+		{Name: "empty", Commit: client.NewCommit("empty", "ghi"), Glob: "/*"},
+		images,
+	}))
+	require.Equal(t, `input "new" has no commits`, emptyJobMessage([]*pps.EmptyJobInput{{Name: "new", Glob: "/*"}, images}))
+
+	images.Datums = 2
+	require.Matches(t, "combining them produced none", emptyJobMessage([]*pps.EmptyJobInput{labels, images}))
+}
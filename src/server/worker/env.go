@@ -0,0 +1,78 @@
+package worker
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// alwaysAllowedEnv are the variables of the worker's environment that user
+// code gets even if its transform sets scrub_env: the ones that come from the
+// image, and the ones that Pachyderm sets in the pod for the user code.
+var alwaysAllowedEnv = []string{
+	"PATH",
+	"HOME",
+	client.PPSPodNameEnv,
+	client.PPSPipelineNameEnv,
+	client.PPSNamespaceEnv,
+	client.PPSSpecCommitEnv,
+	client.PPSJobIDEnv,
+}
+
+// baseEnv returns the environment that the user code's environment is built
+// on: the worker's whole environment, or only its allowed variables if the
+// transform sets scrub_env.
+func (a *APIServer) baseEnv() []string {
+	if !a.pipelineInfo.Transform.ScrubEnv {
+		return os.Environ()
+	}
+	return scrubEnv(os.Environ(), allowedEnv(a.pipelineInfo.Transform))
+}
+
+// allowedEnv returns the names of the variables that user code gets from the
+// worker's environment if 'transform' sets scrub_env
+func allowedEnv(transform *pps.Transform) map[string]bool {
+	allowed := make(map[string]bool)
+	for _, name := range alwaysAllowedEnv {
+		allowed[name] = true
+	}
+	for name := range transform.Env {
+		allowed[name] = true
+	}
+	for _, secret := range transform.Secrets {
+		if secret.EnvVar != "" {
+			allowed[secret.EnvVar] = true
+		}
+	}
+	for _, name := range transform.EnvAllowlist {
+		allowed[name] = true
+	}
+	return allowed
+}
+
+// scrubEnv returns the variables in 'environ' whose names are in 'allowed',
+// sorted by name so that the result doesn't depend on the order in which the
+// pod's variables were declared
+func scrubEnv(environ []string, allowed map[string]bool) []string {
+	var result []string
+	for _, kv := range environ {
+		if allowed[envName(kv)] {
+			result = append(result, kv)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return envName(result[i]) < envName(result[j])
+	})
+	return result
+}
+
+// envName returns the name of the environment variable "name=value"
+func envName(kv string) string {
+	if i := strings.Index(kv, "="); i >= 0 {
+		return kv[:i]
+	}
+	return kv
+}
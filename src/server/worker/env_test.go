@@ -0,0 +1,37 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestScrubEnv(t *testing.T) {
+	transform := &pps.Transform{
+		Env:          map[string]string{"A1": "spec"},
+		Secrets:      []*pps.Secret{{Name: "creds", Key: "token", EnvVar: "TOKEN"}, {Name: "certs", MountPath: "/certs"}},
+		EnvAllowlist: []string{"LANG"},
+	}
+	environ := []string{
+		"TOKEN=secret",
+		"PATH=/usr/bin",
+		"AWS_SECRET_ACCESS_KEY=leaked",
+		"A1=spec",
+		"PPS_POD_NAME=pipeline-v1-abcde",
+		"PACH_ROOT=/pach",
+		"LANG=C",
+		"A=1",
+		"certs=",
+	}
+	require.Equal(t, []string{
+		"A1=spec",
+		"LANG=C",
+		"PATH=/usr/bin",
+		"PPS_POD_NAME=pipeline-v1-abcde",
+		"TOKEN=secret",
+	}, scrubEnv(environ, allowedEnv(transform)))
+
+	transform.Env["A"] = "2"
+	require.Equal(t, []string{"A=1", "A1=spec"}, scrubEnv([]string{"A1=spec", "A=1"}, allowedEnv(transform)))
+}
@@ -0,0 +1,126 @@
+package worker
+
+import (
+	"fmt"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
+	"github.com/pachyderm/pachyderm/src/server/pkg/errutil"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
+	"github.com/pachyderm/pachyderm/src/server/pkg/watch"
+)
+
+// minDatumsForFailureRatio is the number of datums that a job processes
+// before max_failed_datums_percent is checked, so that a job isn't failed by
+// its first noisy failure
+const minDatumsForFailureRatio = 10
+
+// tooManyFailedDatums returns a reason to fail 'jobPtr' if more than
+// 'maxPercent' of the datums that it's processed so far have failed, or ""
+// otherwise. Skipped datums aren't processed, and recovered datums (see
+// err_cmd) didn't fail.
+func tooManyFailedDatums(jobPtr *pps.EtcdJobInfo, maxPercent float64) string {
+	if maxPercent <= 0 || jobPtr.DataFailed == 0 {
+		return ""
+	}
+	processed := jobPtr.DataProcessed + jobPtr.DataRecovered + jobPtr.DataFailed
+	minDatums := int64(minDatumsForFailureRatio)
+	if jobPtr.DataTotal-jobPtr.DataSkipped < minDatums {
+		minDatums = jobPtr.DataTotal - jobPtr.DataSkipped
+	}
+	if processed < minDatums {
+		return ""
+	}
+	if float64(jobPtr.DataFailed)*100 <= maxPercent*float64(processed) {
+		return ""
+	}
+	return fmt.Sprintf("%d of the %d datums processed so far failed, more than max_failed_datums_percent (%v%%)",
+		jobPtr.DataFailed, processed, maxPercent)
+}
+
+// mostCommonFailure returns the type of the most common failure in
+// 'failureCounts' (see EtcdJobInfo.FailureCounts)
+func mostCommonFailure(failureCounts map[int32]int64) pps.FailureType {
+	failureType := pps.FailureType_FAILURE_UNKNOWN
+	var most int64
+	for t, count := range failureCounts {
+		if count > most || (count == most && t < int32(failureType)) {
+			failureType, most = pps.FailureType(t), count
+		}
+	}
+	return failureType
+}
+
+// abortOnFailedDatums watches the job's counts of processed and failed datums
+// and, once tooManyFailedDatums says so, fails the job and finishes its
+// output commit without waiting for its remaining datums. The job's workers
+// then stop, as it's in a terminal state, and so does waitJob, as its output
+// commit is finished. It returns once the job is done, or pachClient's
+// context is.
+func (a *APIServer) abortOnFailedDatums(pachClient *client.APIClient, jobInfo *pps.JobInfo, logger *taggedLogger) {
+	ctx := pachClient.Ctx()
+	maxPercent := a.pipelineInfo.MaxFailedDatumsPercent
+	var reason string
+	var failureType pps.FailureType
+	if err := a.jobs.ReadOnly(ctx).WatchOneF(jobInfo.Job.ID, func(e *watch.Event) error {
+		if e.Type != watch.EventPut {
+			return nil
+		}
+		var jobID string
+		jobPtr := &pps.EtcdJobInfo{}
+		if err := e.Unmarshal(&jobID, jobPtr); err != nil {
+			return err
+		}
+		if ppsutil.IsTerminal(jobPtr.State) {
+			return errutil.ErrBreak
+		}
+		if reason = tooManyFailedDatums(jobPtr, maxPercent); reason == "" {
+			return nil
+		}
+		failureType = mostCommonFailure(jobPtr.FailureCounts)
+		return errutil.ErrBreak
+	}); err != nil {
+		if ctx.Err() == nil {
+			logger.Logf("error watching the job's failed datums: %v", err)
+		}
+		return
+	}
+	if reason == "" {
+		return
+	}
+	logger.Logf("failing the job early: %s", reason)
+	if _, err := col.NewSTM(ctx, a.etcdClient, func(stm col.STM) error {
+		jobPtr := &pps.EtcdJobInfo{}
+		if err := a.jobs.ReadWrite(stm).Get(jobInfo.Job.ID, jobPtr); err != nil {
+			return err
+		}
+		// The job may have finished in the meantime
+		if ppsutil.IsTerminal(jobPtr.State) {
+			reason = ""
+			return nil
+		}
+		return ppsutil.UpdateJobState(a.pipelines.ReadWrite(stm), a.jobs.ReadWrite(stm), jobPtr, pps.JobState_JOB_FAILURE, reason, failureType)
+	}); err != nil {
+		logger.Logf("error failing the job: %v", err)
+		return
+	}
+	if reason == "" {
+		return
+	}
+	if jobInfo.EnableStats {
+		if _, err := pachClient.PfsAPIClient.FinishCommit(ctx, &pfs.FinishCommitRequest{
+			Commit: jobInfo.StatsCommit,
+			Empty:  true,
+		}); err != nil && !pfs.IsCommitFinishedErr(err) {
+			logger.Logf("error from FinishCommit for stats while failing the job: %v", err)
+		}
+	}
+	if _, err := pachClient.PfsAPIClient.FinishCommit(ctx, &pfs.FinishCommitRequest{
+		Commit: jobInfo.OutputCommit,
+		Empty:  true,
+	}); err != nil && !pfs.IsCommitFinishedErr(err) {
+		logger.Logf("error from FinishCommit while failing the job: %v", err)
+	}
+}
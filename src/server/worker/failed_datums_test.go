@@ -0,0 +1,42 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestTooManyFailedDatums(t *testing.T) {
+	job := func(total, processed, failed, skipped, recovered int64) *pps.EtcdJobInfo {
+		return &pps.EtcdJobInfo{
+			DataTotal:     total,
+			DataProcessed: processed,
+			DataFailed:    failed,
+			DataSkipped:   skipped,
+			DataRecovered: recovered,
+		}
+	}
+	// Disabled
+	require.Equal(t, "", tooManyFailedDatums(job(100, 0, 50, 0, 0), 0))
+	// Too few datums processed to tell
+	require.Equal(t, "", tooManyFailedDatums(job(100, 0, 5, 0, 0), 10))
+	// At the threshold, and over it
+	require.Equal(t, "", tooManyFailedDatums(job(100, 18, 2, 0, 0), 10))
+	require.NotEqual(t, "", tooManyFailedDatums(job(100, 17, 3, 0, 0), 10))
+	// Recovered datums count as processed, skipped ones don't
+	require.Equal(t, "", tooManyFailedDatums(job(100, 8, 3, 0, 20), 10))
+	require.NotEqual(t, "", tooManyFailedDatums(job(100, 8, 3, 20, 0), 10))
+	// Jobs with fewer than minDatumsForFailureRatio datums are checked once
+	// they're all processed
+	require.Equal(t, "", tooManyFailedDatums(job(4, 0, 3, 0, 0), 50))
+	require.NotEqual(t, "", tooManyFailedDatums(job(4, 1, 3, 0, 0), 50))
+}
+
+func TestMostCommonFailure(t *testing.T) {
+	require.Equal(t, pps.FailureType_FAILURE_UNKNOWN, mostCommonFailure(nil))
+	require.Equal(t, pps.FailureType_OOM_KILLED, mostCommonFailure(map[int32]int64{
+		int32(pps.FailureType_USER_CODE_ERROR): 2,
+		int32(pps.FailureType_OOM_KILLED):      5,
+	}))
+}
@@ -0,0 +1,62 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// maxReportedInputWrites is how many of the paths that user code wrote to
+// in a datum's inputs are reported. The rest are only counted.
+const maxReportedInputWrites = 10
+
+// watchInputWrites starts watching the inputs of the datum downloaded to
+// 'dir' for writes, if the pipeline has an input_write_check. Everything in
+// 'dir' other than the datum's output and the job scratch directory is an
+// input. The returned function stops watching and logs the paths that were
+// written to. If the input_write_check fails datums, it also returns an error
+// classified as INPUT_MODIFIED.
+func (a *APIServer) watchInputWrites(logger *taggedLogger, dir string) func() error {
+	check := a.pipelineInfo.InputWriteCheck
+	if check == nil {
+		return func() error { return nil }
+	}
+	w, err := watchInputs(dir, []string{"out", client.PPSJobScratchName})
+	if err != nil {
+		logger.Logf("could not watch the datum's inputs for writes: %v", err)
+		return func() error { return nil }
+	}
+	return func() error {
+		written, overflowed := w.stop()
+		if overflowed {
+			logger.Logf("too many changes to the datum's inputs were made at once to report them all")
+		}
+		if len(written) == 0 {
+			return nil
+		}
+		message := inputWritesMessage(written)
+		logger.Logf("%s", message)
+		if check.Fail {
+			return classify(pps.FailureType_INPUT_MODIFIED, errors.New(message))
+		}
+		return nil
+	}
+}
+
+// inputWritesMessage describes the writes to 'written', the paths (relative
+// to the datum's directory) in a datum's inputs that user code wrote to
+func inputWritesMessage(written []string) string {
+	var paths []string
+	for i, p := range written {
+		if i == maxReportedInputWrites {
+			paths = append(paths, fmt.Sprintf("and %d more", len(written)-i))
+			break
+		}
+		paths = append(paths, filepath.Join(client.PPSInputPrefix, p))
+	}
+	return fmt.Sprintf("user code wrote to the datum's inputs: %s", strings.Join(paths, ", "))
+}
@@ -0,0 +1,206 @@
+package worker
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// inputWatchMask is the inotify events that count as writes to an input
+const inputWatchMask = syscall.IN_MODIFY | syscall.IN_ATTRIB | syscall.IN_CREATE |
+	syscall.IN_DELETE | syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO
+
+// inputWatcher watches the directories under a datum's directory for writes
+// with inotify. inotify watches aren't recursive, so each directory is
+// watched, including the ones that are created while watching.
+type inputWatcher struct {
+	dir  string
+	skip map[string]bool // top-level names in dir that aren't inputs
+	fd   int
+	file *os.File
+	done chan struct{}
+
+	mu         sync.Mutex
+	watches    map[int32]string // watch descriptor -> directory, relative to dir
+	written    map[string]bool
+	pipes      map[string]bool
+	overflowed bool
+}
+
+// watchInputs starts watching the files in 'dir' for writes, other than the
+// ones under the top-level names in 'skip'
+func watchInputs(dir string, skip []string) (*inputWatcher, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_NONBLOCK | syscall.IN_CLOEXEC)
+	if err != nil {
+		return nil, err
+	}
+	// The fd is non-blocking, so reads from 'file' use the runtime's poller
+	// and can be interrupted with a deadline
+	w := &inputWatcher{
+		dir:     dir,
+		skip:    make(map[string]bool),
+		fd:      fd,
+		file:    os.NewFile(uintptr(fd), "inotify"),
+		done:    make(chan struct{}),
+		watches: make(map[int32]string),
+		written: make(map[string]bool),
+		pipes:   make(map[string]bool),
+	}
+	for _, name := range skip {
+		w.skip[name] = true
+	}
+	if err := w.file.SetReadDeadline(time.Time{}); err != nil {
+		w.file.Close()
+		return nil, err
+	}
+	if err := w.watchTree("", false); err != nil {
+		w.file.Close()
+		return nil, err
+	}
+	go func() {
+		defer close(w.done)
+		buf := make([]byte, 64*1024)
+		for {
+			n, err := w.file.Read(buf)
+			if err != nil {
+				return // stopped
+			}
+			w.handleEvents(buf[:n])
+		}
+	}()
+	return w, nil
+}
+
+// watchTree watches the directory 'rel' (relative to w.dir) and the
+// directories under it. If 'created' is set, the directory was just created,
+// so the files that are already in it are writes too.
+func (w *inputWatcher) watchTree(rel string, created bool) error {
+	root := filepath.Join(w.dir, rel)
+	return filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			if created && os.IsNotExist(err) {
+				return nil // already moved or deleted again
+			}
+			return err
+		}
+		r, err := filepath.Rel(w.dir, p)
+		if err != nil {
+			return err
+		}
+		if r == "." {
+			r = ""
+		} else if w.skipped(r) {
+			return filepath.SkipDir
+		}
+		if created && r != rel {
+			w.written[r] = true
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		wd, err := syscall.InotifyAddWatch(w.fd, p, inputWatchMask|syscall.IN_ONLYDIR|syscall.IN_DONT_FOLLOW)
+		if err != nil {
+			if created {
+				return nil
+			}
+			return err
+		}
+		w.watches[int32(wd)] = r
+		return nil
+	})
+}
+
+// skipped returns true if 'rel' (relative to w.dir) isn't an input
+func (w *inputWatcher) skipped(rel string) bool {
+	return w.skip[strings.SplitN(filepath.ToSlash(rel), "/", 2)[0]]
+}
+
+// handleEvents records the writes in 'buf', a sequence of inotify events
+func (w *inputWatcher) handleEvents(buf []byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for len(buf) >= syscall.SizeofInotifyEvent {
+		event := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[0]))
+		end := syscall.SizeofInotifyEvent + int(event.Len)
+		if end > len(buf) {
+			return
+		}
+		name := strings.TrimRight(string(buf[syscall.SizeofInotifyEvent:end]), "\x00")
+		buf = buf[end:]
+
+		if event.Mask&syscall.IN_Q_OVERFLOW != 0 {
+			w.overflowed = true
+			continue
+		}
+		dir, ok := w.watches[event.Wd]
+		if !ok {
+			continue
+		}
+		if event.Mask&syscall.IN_IGNORED != 0 {
+			delete(w.watches, event.Wd) // the directory was deleted
+			continue
+		}
+		rel := filepath.Join(dir, name)
+		if w.skipped(rel) {
+			continue
+		}
+		isDir := event.Mask&syscall.IN_ISDIR != 0
+		if event.Mask&syscall.IN_MODIFY != 0 && !isDir && w.isPipe(rel) {
+			continue // lazy inputs are named pipes that the worker writes to
+		}
+		w.written[rel] = true
+		if isDir && event.Mask&(syscall.IN_CREATE|syscall.IN_MOVED_TO) != 0 {
+			w.watchTree(rel, true)
+		}
+	}
+}
+
+// isPipe returns true if 'rel' (relative to w.dir) is a named pipe
+func (w *inputWatcher) isPipe(rel string) bool {
+	if w.pipes[rel] {
+		return true
+	}
+	info, err := os.Lstat(filepath.Join(w.dir, rel))
+	if err != nil || info.Mode()&os.ModeNamedPipe == 0 {
+		return false
+	}
+	w.pipes[rel] = true
+	return true
+}
+
+// stop stops watching, and returns the paths (relative to the datum's
+// directory) that were written to, in order. It also returns true if the
+// kernel dropped events, in which case some writes may be missing.
+func (w *inputWatcher) stop() ([]string, bool) {
+	// Interrupt the reading goroutine, then read the events that were queued
+	// before it stopped. The user code has exited, so no more are expected.
+	w.file.SetReadDeadline(time.Now())
+	<-w.done
+	w.file.SetReadDeadline(time.Time{})
+	if conn, err := w.file.SyscallConn(); err == nil {
+		buf := make([]byte, 64*1024)
+		conn.Read(func(fd uintptr) bool {
+			for {
+				n, err := syscall.Read(int(fd), buf)
+				if err != nil || n <= 0 {
+					return true
+				}
+				w.handleEvents(buf[:n])
+			}
+		})
+	}
+	w.file.Close()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var written []string
+	for p := range w.written {
+		written = append(written, p)
+	}
+	sort.Strings(written)
+	return written, w.overflowed
+}
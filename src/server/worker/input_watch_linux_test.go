@@ -0,0 +1,56 @@
+package worker
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestWatchInputs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "input-watch")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	for _, d := range []string{"in/nested", "out", "unchanged"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, d), 0777))
+	}
+	for _, f := range []string{"in/a", "in/nested/b", "unchanged/c"} {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, f), []byte("data"), 0666))
+	}
+	require.NoError(t, syscall.Mkfifo(filepath.Join(dir, "in/lazy"), 0666))
+
+	w, err := watchInputs(dir, []string{"out"})
+	require.NoError(t, err)
+	// Reading inputs and writing output isn't reported
+	_, err = ioutil.ReadFile(filepath.Join(dir, "unchanged/c"))
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "out/result"), []byte("data"), 0666))
+	// Neither are writes to named pipes, which the worker uses for lazy inputs
+	pipe, err := os.OpenFile(filepath.Join(dir, "in/lazy"), os.O_RDWR, 0)
+	require.NoError(t, err)
+	_, err = pipe.Write([]byte("data"))
+	require.NoError(t, err)
+	require.NoError(t, pipe.Close())
+	// Modifying, deleting and creating inputs is, including in new directories
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "in/a"), []byte("changed"), 0666))
+	require.NoError(t, os.Remove(filepath.Join(dir, "in/nested/b")))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "in/new/dir"), 0777))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "in/new/dir/d"), []byte("data"), 0666))
+	written, overflowed := w.stop()
+	require.False(t, overflowed)
+	require.Equal(t, []string{"in/a", "in/nested/b", "in/new", "in/new/dir", "in/new/dir/d"}, written)
+}
+
+func TestInputWritesMessage(t *testing.T) {
+	require.Equal(t, "user code wrote to the datum's inputs: /pfs/in/a, /pfs/in/b",
+		inputWritesMessage([]string{"in/a", "in/b"}))
+	var written []string
+	for i := 0; i < maxReportedInputWrites+3; i++ {
+		written = append(written, "in/a")
+	}
+	require.True(t, strings.HasSuffix(inputWritesMessage(written), "/pfs/in/a, and 3 more"))
+}
@@ -0,0 +1,19 @@
+// +build !linux
+
+package worker
+
+import (
+	"errors"
+)
+
+// inputWatcher watches a datum's inputs for writes. Workers run on Linux,
+// where it's implemented with inotify.
+type inputWatcher struct{}
+
+func watchInputs(dir string, skip []string) (*inputWatcher, error) {
+	return nil, errors.New("watching for writes is only supported on Linux")
+}
+
+func (w *inputWatcher) stop() ([]string, bool) {
+	return nil, false
+}
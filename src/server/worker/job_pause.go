@@ -0,0 +1,77 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// errJobPaused is returned by processDatums when it stops starting datums
+// because the job was paused
+var errJobPaused = errors.New("job paused")
+
+// jobPause tracks whether the job that a worker is processing is paused
+// (see PauseJob). It's updated by cancelCtxIfJobFails, which watches the
+// job's EtcdJobInfo. A nil *jobPause is never paused.
+type jobPause struct {
+	mu     sync.Mutex
+	paused bool
+	// changed is closed (and replaced) whenever 'paused' changes
+	changed chan struct{}
+}
+
+func newJobPause() *jobPause {
+	return &jobPause{changed: make(chan struct{})}
+}
+
+func (p *jobPause) set(paused bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.paused == paused {
+		return
+	}
+	p.paused = paused
+	close(p.changed)
+	p.changed = make(chan struct{})
+}
+
+// isPaused returns true if the job is paused
+func (p *jobPause) isPaused() bool {
+	if p == nil {
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.paused
+}
+
+// wait blocks until the job isn't paused, or 'ctx' is done
+func (p *jobPause) wait(ctx context.Context) error {
+	if p == nil {
+		return nil
+	}
+	for {
+		p.mu.Lock()
+		paused, changed := p.paused, p.changed
+		p.mu.Unlock()
+		if !paused {
+			return nil
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// changes returns a channel that's closed when the job is next paused or
+// resumed
+func (p *jobPause) changes() <-chan struct{} {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.changed
+}
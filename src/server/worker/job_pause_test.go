@@ -0,0 +1,44 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestJobPause(t *testing.T) {
+	// A nil jobPause is never paused
+	var none *jobPause
+	require.False(t, none.isPaused())
+	require.NoError(t, none.wait(context.Background()))
+
+	pause := newJobPause()
+	require.NoError(t, pause.wait(context.Background()))
+	changed := pause.changes()
+	pause.set(true)
+	require.True(t, pause.isPaused())
+	select {
+	case <-changed:
+	default:
+		t.Fatal("pausing the job didn't signal a change")
+	}
+
+	// wait returns once the job is resumed
+	resumed := make(chan error)
+	go func() { resumed <- pause.wait(context.Background()) }()
+	select {
+	case <-resumed:
+		t.Fatal("wait returned while the job was paused")
+	case <-time.After(50 * time.Millisecond):
+	}
+	pause.set(false)
+	require.NoError(t, <-resumed)
+
+	// or once its context is done
+	pause.set(true)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.YesError(t, pause.wait(ctx))
+}
@@ -0,0 +1,71 @@
+package worker
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// jobQueue holds the output commits that the job spawner has received but not
+// yet started jobs for, while the pipeline runs max_outstanding_jobs jobs.
+// They're popped in the pipeline's job_order.
+type jobQueue struct {
+	order pps.JobOrder
+
+	mu      sync.Mutex
+	commits []*pfs.CommitInfo
+	// pushed is signalled when a commit is pushed
+	pushed chan struct{}
+}
+
+func newJobQueue(order pps.JobOrder) *jobQueue {
+	return &jobQueue{
+		order:  order,
+		pushed: make(chan struct{}, 1),
+	}
+}
+
+// push adds an output commit to the queue
+func (q *jobQueue) push(commitInfo *pfs.CommitInfo) {
+	q.mu.Lock()
+	q.commits = append(q.commits, commitInfo)
+	q.mu.Unlock()
+	select {
+	case q.pushed <- struct{}{}:
+	default:
+	}
+}
+
+// pop removes the output commit whose job should start next from the queue,
+// blocking until there is one or 'ctx' is cancelled
+func (q *jobQueue) pop(ctx context.Context) (*pfs.CommitInfo, error) {
+	for {
+		if commitInfo := q.tryPop(); commitInfo != nil {
+			return commitInfo, nil
+		}
+		select {
+		case <-q.pushed:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (q *jobQueue) tryPop() *pfs.CommitInfo {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.commits) == 0 {
+		return nil
+	}
+	var commitInfo *pfs.CommitInfo
+	if q.order == pps.JobOrder_NEWEST_JOB_FIRST {
+		commitInfo = q.commits[len(q.commits)-1]
+		q.commits = q.commits[:len(q.commits)-1]
+	} else {
+		commitInfo = q.commits[0]
+		q.commits = q.commits[1:]
+	}
+	return commitInfo
+}
@@ -0,0 +1,58 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestJobQueue(t *testing.T) {
+	commit := func(id string) *pfs.CommitInfo {
+		return &pfs.CommitInfo{Commit: client.NewCommit("out", id)}
+	}
+	pop := func(q *jobQueue) string {
+		commitInfo, err := q.pop(context.Background())
+		require.NoError(t, err)
+		return commitInfo.Commit.ID
+	}
+
+	oldest := newJobQueue(pps.JobOrder_OLDEST_JOB_FIRST)
+	newest := newJobQueue(pps.JobOrder_NEWEST_JOB_FIRST)
+	for _, id := range []string{"a", "b", "c"} {
+		oldest.push(commit(id))
+		newest.push(commit(id))
+	}
+	require.Equal(t, "a", pop(oldest))
+	require.Equal(t, "c", pop(newest))
+	// commits pushed later are ordered with the ones still queued
+	oldest.push(commit("d"))
+	newest.push(commit("d"))
+	require.Equal(t, "b", pop(oldest))
+	require.Equal(t, "d", pop(newest))
+	require.Equal(t, "c", pop(oldest))
+	require.Equal(t, "b", pop(newest))
+	require.Equal(t, "d", pop(oldest))
+	require.Equal(t, "a", pop(newest))
+
+	// pop blocks until a commit is pushed
+	popped := make(chan string)
+	go func() { popped <- pop(oldest) }()
+	select {
+	case id := <-popped:
+		t.Fatalf("pop returned %q from an empty queue", id)
+	case <-time.After(50 * time.Millisecond):
+	}
+	oldest.push(commit("e"))
+	require.Equal(t, "e", <-popped)
+
+	// or until its context is cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := oldest.pop(ctx)
+	require.YesError(t, err)
+}
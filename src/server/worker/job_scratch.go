@@ -0,0 +1,146 @@
+package worker
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	filesync "github.com/pachyderm/pachyderm/src/server/pkg/sync"
+)
+
+// jobScratchRepo returns the name of the PFS repo that holds the scratch
+// directory of job 'jobID'
+func jobScratchRepo(jobID string) string {
+	return "__job_scratch_" + jobID
+}
+
+// createJobScratch creates the repo holding a job's scratch directory. It's
+// called by the master when the job starts, and may be called again if the
+// master restarts.
+func (a *APIServer) createJobScratch(pachClient *client.APIClient, jobInfo *pps.JobInfo) error {
+	_, err := pachClient.PfsAPIClient.CreateRepo(pachClient.Ctx(), &pfs.CreateRepoRequest{
+		Repo:        client.NewRepo(jobScratchRepo(jobInfo.Job.ID)),
+		Description: "Scratch directory of job " + jobInfo.Job.ID,
+		Update:      true,
+	})
+	return err
+}
+
+// deleteJobScratch deletes the repo holding a job's scratch directory, once
+// the job has finished
+func (a *APIServer) deleteJobScratch(pachClient *client.APIClient, jobInfo *pps.JobInfo, logger *taggedLogger) {
+	if err := pachClient.DeleteRepo(jobScratchRepo(jobInfo.Job.ID), true); err != nil && !pfs.IsRepoNotFoundErr(err) {
+		logger.Logf("error deleting the scratch directory of job %s: %v", jobInfo.Job.ID, err)
+	}
+}
+
+// jobScratchFile is the state of a file in a job's scratch directory, which is
+// used to find the files that a datum changed
+type jobScratchFile struct {
+	size    int64
+	modTime time.Time
+}
+
+func snapshotJobScratch(root string) (map[string]jobScratchFile, error) {
+	files := make(map[string]jobScratchFile)
+	if err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = jobScratchFile{size: info.Size(), modTime: info.ModTime()}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// changedJobScratchFiles returns the files in 'after' that are new or were
+// modified since 'before', and the files in 'before' that were deleted
+func changedJobScratchFiles(before, after map[string]jobScratchFile) (changed []string, deleted []string) {
+	for path, file := range after {
+		if prev, ok := before[path]; !ok || prev != file {
+			changed = append(changed, path)
+		}
+	}
+	for path := range before {
+		if _, ok := after[path]; !ok {
+			deleted = append(deleted, path)
+		}
+	}
+	return changed, deleted
+}
+
+// downloadJobScratch downloads the current contents of a job's scratch
+// directory into 'dir', and returns their state, so that uploadJobScratch can
+// find the files that the datum changed
+func (a *APIServer) downloadJobScratch(pachClient *client.APIClient, puller *filesync.Puller, dir string, jobID string) (map[string]jobScratchFile, error) {
+	root := filepath.Join(dir, client.PPSJobScratchName)
+	if err := os.MkdirAll(root, 0777); err != nil {
+		return nil, err
+	}
+	repo := jobScratchRepo(jobID)
+	branchInfo, err := pachClient.InspectBranch(repo, "master")
+	if err != nil && !pfs.IsBranchNotFoundErr(err) {
+		return nil, err
+	}
+	if branchInfo != nil && branchInfo.Head != nil {
+		// datums may modify the files, so they can't be lazy
+		if err := puller.Pull(pachClient, root, repo, branchInfo.Head.ID, "/", false, false, a.config.transferConcurrency(), nil, ""); err != nil {
+			return nil, err
+		}
+	}
+	return snapshotJobScratch(root)
+}
+
+// uploadJobScratch writes the files that a datum created, modified or deleted
+// in the job's scratch directory back to the scratch repo
+func (a *APIServer) uploadJobScratch(pachClient *client.APIClient, dir string, jobID string, before map[string]jobScratchFile) (retErr error) {
+	root := filepath.Join(dir, client.PPSJobScratchName)
+	after, err := snapshotJobScratch(root)
+	if err != nil {
+		return err
+	}
+	changed, deleted := changedJobScratchFiles(before, after)
+	repo := jobScratchRepo(jobID)
+	if len(changed) > 0 {
+		pfc, err := pachClient.NewPutFileClient()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := pfc.Close(); err != nil && retErr == nil {
+				retErr = err
+			}
+		}()
+		for _, path := range changed {
+			if err := func() error {
+				f, err := os.Open(filepath.Join(root, filepath.FromSlash(path)))
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				_, err = pfc.PutFileOverwrite(repo, "master", path, f, 0)
+				return err
+			}(); err != nil {
+				return err
+			}
+		}
+	}
+	for _, path := range deleted {
+		if err := pachClient.DeleteFile(repo, "master", path); err != nil && !pfs.IsFileNotFoundErr(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package worker
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestChangedJobScratchFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "job-scratch")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	then := time.Now().Add(-time.Hour)
+	write := func(name string, data string) {
+		p := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0777))
+		require.NoError(t, ioutil.WriteFile(p, []byte(data), 0666))
+	}
+	write("dict/a", "a")
+	write("dict/b", "b")
+	write("c", "c")
+	for _, name := range []string{"dict/a", "dict/b", "c"} {
+		require.NoError(t, os.Chtimes(filepath.Join(dir, name), then, then))
+	}
+	before, err := snapshotJobScratch(dir)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(before))
+
+	// a datum modifies one file, deletes one, and creates one
+	write("dict/a", "aa")
+	require.NoError(t, os.Remove(filepath.Join(dir, "c")))
+	write("shuffle/0", "0")
+	after, err := snapshotJobScratch(dir)
+	require.NoError(t, err)
+	changed, deleted := changedJobScratchFiles(before, after)
+	sort.Strings(changed)
+	require.Equal(t, []string{"dict/a", "shuffle/0"}, changed)
+	require.Equal(t, []string{"c"}, deleted)
+
+	changed, deleted = changedJobScratchFiles(after, after)
+	require.Equal(t, 0, len(changed))
+	require.Equal(t, 0, len(deleted))
+}
@@ -0,0 +1,306 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	kafka "github.com/segmentio/kafka-go"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/backoff"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsconsts"
+)
+
+// kafkaMarkerFile is the spout marker in which a Kafka spout stores the
+// offsets that it has committed
+const kafkaMarkerFile = "marker"
+
+// kafkaMarker is the content of a Kafka spout's marker
+type kafkaMarker struct {
+	Topic string `json:"topic"`
+	// Offsets is the next offset to consume, by partition
+	Offsets map[int]int64 `json:"offsets"`
+}
+
+func parseKafkaMarker(topic string, data []byte) (*kafkaMarker, error) {
+	marker := &kafkaMarker{}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, marker); err != nil {
+			return nil, fmt.Errorf("error parsing the kafka spout's marker: %v", err)
+		}
+	}
+	if marker.Topic != topic || marker.Offsets == nil {
+		// the spout was updated to consume another topic, so start over
+		marker = &kafkaMarker{Topic: topic, Offsets: make(map[int]int64)}
+	}
+	return marker, nil
+}
+
+// consumed returns true if 'msg' was already committed to PFS
+func (m *kafkaMarker) consumed(msg kafka.Message) bool {
+	next, ok := m.Offsets[msg.Partition]
+	return ok && msg.Offset < next
+}
+
+// kafkaBatchFiles returns the files that a batch of messages is written to,
+// by path, and the number of messages that were skipped because they couldn't
+// be serialized
+func kafkaBatchFiles(serialization string, msgs []kafka.Message) (map[string][]byte, int) {
+	files := make(map[string][]byte)
+	var skipped int
+	switch serialization {
+	case "json":
+		// each partition's messages go in one file, named by the offset of
+		// the partition's first message in the batch
+		first := make(map[int]int64)
+		for _, msg := range msgs {
+			var line bytes.Buffer
+			if err := json.Compact(&line, msg.Value); err != nil {
+				skipped++
+				continue
+			}
+			if _, ok := first[msg.Partition]; !ok {
+				first[msg.Partition] = msg.Offset
+			}
+			p := fmt.Sprintf("%d/%020d.jsonl", msg.Partition, first[msg.Partition])
+			files[p] = append(append(files[p], line.Bytes()...), '\n')
+		}
+	default:
+		for _, msg := range msgs {
+			files[fmt.Sprintf("%d/%020d", msg.Partition, msg.Offset)] = msg.Value
+		}
+	}
+	return files, skipped
+}
+
+// nextKafkaBatch reads messages from 'msgs' until it has 'size' of them, or
+// 'timeout' has passed since the first one. Messages that 'marker' has
+// already consumed are dropped.
+func nextKafkaBatch(ctx context.Context, msgs <-chan kafka.Message, errs <-chan error, marker *kafkaMarker, size int64, timeout time.Duration) ([]kafka.Message, error) {
+	var batch []kafka.Message
+	var deadline <-chan time.Time
+	for int64(len(batch)) < size {
+		select {
+		case msg := <-msgs:
+			if marker.consumed(msg) {
+				continue
+			}
+			if batch == nil {
+				deadline = time.After(timeout)
+			}
+			batch = append(batch, msg)
+		case <-deadline:
+			return batch, nil
+		case err := <-errs:
+			return nil, err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return batch, nil
+}
+
+// runKafkaSpout consumes the spout's Kafka topic, and commits each batch of
+// messages to the output branch along with the marker, until 'ctx' is
+// cancelled
+func (a *APIServer) runKafkaSpout(ctx context.Context, logger *taggedLogger) error {
+	return backoff.RetryNotify(func() error {
+		return a.consumeKafka(ctx, logger)
+	}, backoff.NewInfiniteBackOff(), func(err error, d time.Duration) error {
+		select {
+		case <-ctx.Done():
+			return err
+		default:
+			logger.Logf("error running kafka spout: %+v, retrying in: %+v", err, d)
+			return nil
+		}
+	})
+}
+
+func (a *APIServer) consumeKafka(ctx context.Context, logger *taggedLogger) error {
+	spec := a.pipelineInfo.Spout.Kafka
+	pachClient := a.pachClient.WithCtx(ctx)
+	marker, err := a.readKafkaMarker(pachClient, spec.Topic)
+	if err != nil {
+		return err
+	}
+	readers, err := newKafkaReaders(ctx, spec, marker)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	msgs := make(chan kafka.Message)
+	errs := make(chan error, len(readers))
+	for _, r := range readers {
+		r := r
+		defer r.Close()
+		go func() {
+			for {
+				msg, err := r.FetchMessage(ctx)
+				if err != nil {
+					errs <- err
+					return
+				}
+				select {
+				case msgs <- msg:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	timeout, err := types.DurationFromProto(spec.BatchTimeout)
+	if err != nil {
+		return err
+	}
+	for {
+		batch, err := nextKafkaBatch(ctx, msgs, errs, marker, spec.BatchSize, timeout)
+		if err != nil {
+			return err
+		}
+		for _, msg := range batch {
+			marker.Offsets[msg.Partition] = msg.Offset + 1
+		}
+		files, skipped := kafkaBatchFiles(spec.Serialization, batch)
+		if err := a.commitKafkaBatch(pachClient, files, marker); err != nil {
+			return err
+		}
+		logger.Logf("committed %d messages from kafka topic %s", len(batch)-skipped, spec.Topic)
+		if skipped > 0 {
+			logger.Logf("skipped %d messages from kafka topic %s that couldn't be serialized as %s", skipped, spec.Topic, spec.Serialization)
+		}
+		if spec.Group != "" {
+			// the marker is the source of truth, so it's fine if this fails:
+			// the messages would be delivered again, and dropped
+			if err := readers[0].CommitMessages(ctx, batch...); err != nil {
+				logger.Logf("error committing offsets to consumer group %s: %v", spec.Group, err)
+			}
+		}
+	}
+}
+
+// newKafkaReaders returns a reader for the spout's consumer group, or if it
+// doesn't have one, a reader for each partition of its topic, starting from
+// the offsets in 'marker'
+func newKafkaReaders(ctx context.Context, spec *pps.KafkaSpout, marker *kafkaMarker) ([]*kafka.Reader, error) {
+	if spec.Group != "" {
+		return []*kafka.Reader{kafka.NewReader(kafka.ReaderConfig{
+			Brokers: spec.Brokers,
+			GroupID: spec.Group,
+			Topic:   spec.Topic,
+		})}, nil
+	}
+	var partitions []kafka.Partition
+	var err error
+	for _, broker := range spec.Brokers {
+		var conn *kafka.Conn
+		if conn, err = kafka.DialContext(ctx, "tcp", broker); err != nil {
+			continue
+		}
+		partitions, err = conn.ReadPartitions(spec.Topic)
+		conn.Close()
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading the partitions of kafka topic %s: %v", spec.Topic, err)
+	}
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i].ID < partitions[j].ID })
+	var readers []*kafka.Reader
+	for _, partition := range partitions {
+		r := kafka.NewReader(kafka.ReaderConfig{
+			Brokers:   spec.Brokers,
+			Topic:     spec.Topic,
+			Partition: partition.ID,
+		})
+		offset, ok := marker.Offsets[partition.ID]
+		if !ok {
+			offset = kafka.FirstOffset
+		}
+		if err := r.SetOffset(offset); err != nil {
+			for _, r := range append(readers, r) {
+				r.Close()
+			}
+			return nil, err
+		}
+		readers = append(readers, r)
+	}
+	return readers, nil
+}
+
+// readKafkaMarker reads the spout's marker from the head of its output
+// branch. If the head is unfinished (because a previous attempt failed while
+// committing a batch), it's deleted first.
+func (a *APIServer) readKafkaMarker(pachClient *client.APIClient, topic string) (*kafkaMarker, error) {
+	repo, branch := a.pipelineInfo.Pipeline.Name, a.pipelineInfo.OutputBranch
+	commitInfo, err := pachClient.InspectCommit(repo, branch)
+	if err != nil && !pfs.IsBranchNoHeadErr(err) {
+		return nil, err
+	}
+	if commitInfo != nil && commitInfo.Finished == nil {
+		if err := pachClient.DeleteCommit(repo, commitInfo.Commit.ID); err != nil {
+			return nil, err
+		}
+	}
+	var buf bytes.Buffer
+	if err := pachClient.GetFile(repo, branch, kafkaMarkerFile, 0, 0, &buf); err != nil &&
+		!pfs.IsBranchNoHeadErr(err) && !pfs.IsFileNotFoundErr(err) {
+		return nil, err
+	}
+	return parseKafkaMarker(topic, buf.Bytes())
+}
+
+// commitKafkaBatch commits 'files' and 'marker' to the spout's output branch
+// in a single commit
+func (a *APIServer) commitKafkaBatch(pachClient *client.APIClient, files map[string][]byte, marker *kafkaMarker) (retErr error) {
+	repo := a.pipelineInfo.Pipeline.Name
+	markerJSON, err := json.Marshal(marker)
+	if err != nil {
+		return err
+	}
+	commit, err := pachClient.PfsAPIClient.StartCommit(pachClient.Ctx(), &pfs.StartCommitRequest{
+		Parent:     client.NewCommit(repo, ""),
+		Branch:     a.pipelineInfo.OutputBranch,
+		Provenance: []*pfs.CommitProvenance{client.NewCommitProvenance(ppsconsts.SpecRepo, repo, a.specCommit().ID)},
+	})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if retErr != nil {
+			pachClient.DeleteCommit(repo, commit.ID)
+		}
+	}()
+	pfc, err := pachClient.NewPutFileClient()
+	if err != nil {
+		return err
+	}
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		if _, err := pfc.PutFile(repo, commit.ID, p, bytes.NewReader(files[p])); err != nil {
+			pfc.Close()
+			return err
+		}
+	}
+	if _, err := pfc.PutFileOverwrite(repo, commit.ID, kafkaMarkerFile, bytes.NewReader(markerJSON), 0); err != nil {
+		pfc.Close()
+		return err
+	}
+	if err := pfc.Close(); err != nil {
+		return err
+	}
+	return pachClient.FinishCommit(repo, commit.ID)
+}
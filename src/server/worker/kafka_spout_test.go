@@ -0,0 +1,72 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	kafka "github.com/segmentio/kafka-go"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestParseKafkaMarker(t *testing.T) {
+	marker, err := parseKafkaMarker("events", nil)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(marker.Offsets))
+
+	marker, err = parseKafkaMarker("events", []byte(`{"topic":"events","offsets":{"0":10,"2":3}}`))
+	require.NoError(t, err)
+	require.Equal(t, map[int]int64{0: 10, 2: 3}, marker.Offsets)
+	require.True(t, marker.consumed(kafka.Message{Partition: 0, Offset: 9}))
+	require.False(t, marker.consumed(kafka.Message{Partition: 0, Offset: 10}))
+	require.False(t, marker.consumed(kafka.Message{Partition: 1, Offset: 0}))
+
+	// a marker for another topic is ignored
+	marker, err = parseKafkaMarker("orders", []byte(`{"topic":"events","offsets":{"0":10}}`))
+	require.NoError(t, err)
+	require.Equal(t, 0, len(marker.Offsets))
+
+	_, err = parseKafkaMarker("events", []byte("not json"))
+	require.YesError(t, err)
+}
+
+func TestKafkaBatchFiles(t *testing.T) {
+	msgs := []kafka.Message{
+		{Partition: 0, Offset: 7, Value: []byte(`{"a": 1}`)},
+		{Partition: 1, Offset: 3, Value: []byte(`{"b": 2}`)},
+		{Partition: 0, Offset: 8, Value: []byte(`not json`)},
+		{Partition: 0, Offset: 9, Value: []byte(`[3]`)},
+	}
+	files, skipped := kafkaBatchFiles("raw", msgs)
+	require.Equal(t, 0, skipped)
+	require.Equal(t, 4, len(files))
+	require.Equal(t, "not json", string(files["0/00000000000000000008"]))
+
+	files, skipped = kafkaBatchFiles("json", msgs)
+	require.Equal(t, 1, skipped)
+	require.Equal(t, 2, len(files))
+	require.Equal(t, "{\"a\":1}\n[3]\n", string(files["0/00000000000000000007.jsonl"]))
+	require.Equal(t, "{\"b\":2}\n", string(files["1/00000000000000000003.jsonl"]))
+}
+
+func TestNextKafkaBatch(t *testing.T) {
+	marker := &kafkaMarker{Topic: "events", Offsets: map[int]int64{0: 2}}
+	msgs := make(chan kafka.Message, 10)
+	errs := make(chan error)
+	for i := int64(0); i < 5; i++ {
+		msgs <- kafka.Message{Partition: 0, Offset: i}
+	}
+	// already-consumed messages are dropped, and the batch is cut at its size
+	batch, err := nextKafkaBatch(context.Background(), msgs, errs, marker, 2, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(batch))
+	require.Equal(t, int64(2), batch[0].Offset)
+	require.Equal(t, int64(3), batch[1].Offset)
+
+	// or when the timeout passes
+	batch, err = nextKafkaBatch(context.Background(), msgs, errs, marker, 10, 10*time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(batch))
+	require.Equal(t, int64(4), batch[0].Offset)
+}
@@ -0,0 +1,195 @@
+package worker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// kubeInfoRefresh is how long the worker's view of its pod and node is used
+// before it's looked up again
+const kubeInfoRefresh = 30 * time.Second
+
+// nodePressureConditions are the node conditions under which the kubelet
+// evicts pods to reclaim resources
+var nodePressureConditions = []v1.NodeConditionType{
+	v1.NodeMemoryPressure,
+	v1.NodeDiskPressure,
+	v1.NodePIDPressure,
+}
+
+// kubeInfo is what a worker knows about its own pod and the node that it's
+// scheduled on
+type kubeInfo struct {
+	// node is the name of the worker's node, and allocatable is the resources
+	// of the node that are available to pods
+	node        string
+	allocatable v1.ResourceList
+	// limits are the resource limits of the worker's user container
+	limits v1.ResourceList
+	// eviction is why the worker's pod is about to be evicted, or "" if it
+	// isn't
+	eviction string
+}
+
+// String describes 'k' for datum failures, e.g. "on node n1 (allocatable:
+// cpu=4, memory=16Gi; limits: memory=2Gi)"
+func (k *kubeInfo) String() string {
+	var details []string
+	if len(k.allocatable) > 0 {
+		details = append(details, "allocatable: "+formatResources(k.allocatable))
+	}
+	if len(k.limits) > 0 {
+		details = append(details, "limits: "+formatResources(k.limits))
+	}
+	result := "on node " + k.node
+	if len(details) > 0 {
+		result += " (" + strings.Join(details, "; ") + ")"
+	}
+	if k.eviction != "" {
+		result += ", while " + k.eviction
+	}
+	return result
+}
+
+// kubeInfoCache caches a worker's kubeInfo
+type kubeInfoCache struct {
+	mu      sync.Mutex
+	info    *kubeInfo
+	updated time.Time
+}
+
+// kubeInfo returns what the worker knows about its pod and node. It's looked
+// up at most every kubeInfoRefresh.
+func (a *APIServer) kubeInfo() (*kubeInfo, error) {
+	a.kubeInfoCache.mu.Lock()
+	defer a.kubeInfoCache.mu.Unlock()
+	if a.kubeInfoCache.info != nil && time.Since(a.kubeInfoCache.updated) < kubeInfoRefresh {
+		return a.kubeInfoCache.info, nil
+	}
+	info, err := a.computeKubeInfo()
+	if err != nil {
+		return nil, err
+	}
+	if info.eviction != "" && (a.kubeInfoCache.info == nil || a.kubeInfoCache.info.eviction == "") {
+		log.Warnf("worker is about to be evicted: %s", info.eviction)
+	}
+	a.kubeInfoCache.info = info
+	a.kubeInfoCache.updated = time.Now()
+	return info, nil
+}
+
+func (a *APIServer) computeKubeInfo() (*kubeInfo, error) {
+	if a.kubeClient == nil {
+		return nil, fmt.Errorf("no kubernetes client")
+	}
+	pod, err := a.kubeClient.CoreV1().Pods(a.namespace).Get(a.workerName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("could not get pod %s: %v", a.workerName, err)
+	}
+	info := &kubeInfo{
+		node:   pod.Spec.NodeName,
+		limits: userContainerLimits(pod),
+	}
+	var node *v1.Node
+	if pod.Spec.NodeName != "" {
+		// The worker may not be allowed to read nodes, in which case it only
+		// knows about its pod
+		node, err = a.kubeClient.CoreV1().Nodes().Get(pod.Spec.NodeName, metav1.GetOptions{})
+		if err != nil {
+			log.Debugf("could not get node %s: %v", pod.Spec.NodeName, err)
+			node = nil
+		} else {
+			info.allocatable = node.Status.Allocatable
+		}
+	}
+	info.eviction = evictionReason(pod, node)
+	return info, nil
+}
+
+// userContainerLimits returns the resource limits of 'pod's user container
+func userContainerLimits(pod *v1.Pod) v1.ResourceList {
+	for _, container := range pod.Spec.Containers {
+		if container.Name == client.PPSWorkerUserContainerName {
+			return container.Resources.Limits
+		}
+	}
+	return nil
+}
+
+// evictionReason returns why 'pod' is about to be evicted from 'node' (which
+// may be nil if it's unknown), or "" if it isn't: the pod is being deleted, or
+// its node is cordoned (e.g. to be drained), tainted so that its pods are
+// evicted, or under resource pressure
+func evictionReason(pod *v1.Pod, node *v1.Node) string {
+	if pod.DeletionTimestamp != nil {
+		return fmt.Sprintf("pod %s is being deleted", pod.Name)
+	}
+	if pod.Status.Reason == "Evicted" {
+		return fmt.Sprintf("pod %s is evicted: %s", pod.Name, pod.Status.Message)
+	}
+	if node == nil {
+		return ""
+	}
+	if node.Spec.Unschedulable {
+		return fmt.Sprintf("node %s is cordoned", node.Name)
+	}
+	for _, taint := range node.Spec.Taints {
+		if taint.Effect == v1.TaintEffectNoExecute && !toleratesTaint(pod, &taint) {
+			return fmt.Sprintf("node %s has taint %s", node.Name, taint.ToString())
+		}
+	}
+	for _, condition := range node.Status.Conditions {
+		for _, pressure := range nodePressureConditions {
+			if condition.Type == pressure && condition.Status == v1.ConditionTrue {
+				return fmt.Sprintf("node %s has %s", node.Name, condition.Type)
+			}
+		}
+	}
+	return ""
+}
+
+// toleratesTaint returns true if 'pod' tolerates 'taint' indefinitely, and so
+// isn't evicted by it
+func toleratesTaint(pod *v1.Pod, taint *v1.Taint) bool {
+	for _, toleration := range pod.Spec.Tolerations {
+		if toleration.ToleratesTaint(taint) && toleration.TolerationSeconds == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// formatResources formats 'resources' as e.g. "cpu=4, memory=16Gi", sorted by
+// resource name
+func formatResources(resources v1.ResourceList) string {
+	var result []string
+	for name, quantity := range resources {
+		result = append(result, fmt.Sprintf("%s=%s", name, quantity.String()))
+	}
+	sort.Strings(result)
+	return strings.Join(result, ", ")
+}
+
+// annotateFailure adds the context of the worker's node (its resources, and
+// whether the worker is about to be evicted) to 'err', the error of a datum
+// that failed, keeping its classification. Failures such as OOM kills and
+// timeouts are often the node's doing rather than the user code's.
+func (a *APIServer) annotateFailure(err error) error {
+	info, kubeErr := a.kubeInfo()
+	if kubeErr != nil {
+		log.Debugf("not annotating datum failure: %v", kubeErr)
+		return err
+	}
+	return &datumError{
+		failureType: failureType(err),
+		err:         fmt.Errorf("%v (%s)", err, info),
+	}
+}
@@ -0,0 +1,75 @@
+package worker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEvictionReason(t *testing.T) {
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pipeline-foo-v1-abcde"}}
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "n1"}}
+	require.Equal(t, "", evictionReason(pod, nil))
+	require.Equal(t, "", evictionReason(pod, node))
+
+	node.Status.Conditions = []v1.NodeCondition{
+		{Type: v1.NodeReady, Status: v1.ConditionTrue},
+		{Type: v1.NodeMemoryPressure, Status: v1.ConditionFalse},
+	}
+	require.Equal(t, "", evictionReason(pod, node))
+	node.Status.Conditions[1].Status = v1.ConditionTrue
+	require.Equal(t, "node n1 has MemoryPressure", evictionReason(pod, node))
+
+	node.Spec.Taints = []v1.Taint{{Key: "maintenance", Effect: v1.TaintEffectNoExecute}}
+	require.Equal(t, "node n1 has taint maintenance:NoExecute", evictionReason(pod, node))
+	// Taints that the pod tolerates don't evict it
+	pod.Spec.Tolerations = []v1.Toleration{{Key: "maintenance", Operator: v1.TolerationOpExists}}
+	require.Equal(t, "node n1 has MemoryPressure", evictionReason(pod, node))
+
+	node.Spec.Unschedulable = true
+	require.Equal(t, "node n1 is cordoned", evictionReason(pod, node))
+	now := metav1.NewTime(time.Now())
+	pod.DeletionTimestamp = &now
+	require.Equal(t, "pod pipeline-foo-v1-abcde is being deleted", evictionReason(pod, node))
+}
+
+func TestKubeInfoString(t *testing.T) {
+	pod := &v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{
+		{Name: client.PPSWorkerSidecarContainerName},
+		{Name: client.PPSWorkerUserContainerName, Resources: v1.ResourceRequirements{
+			Limits: v1.ResourceList{v1.ResourceMemory: resource.MustParse("2Gi")},
+		}},
+	}}}
+	info := &kubeInfo{
+		node: "n1",
+		allocatable: v1.ResourceList{
+			v1.ResourceMemory: resource.MustParse("16Gi"),
+			v1.ResourceCPU:    resource.MustParse("4"),
+		},
+		limits: userContainerLimits(pod),
+	}
+	require.Equal(t, "on node n1 (allocatable: cpu=4, memory=16Gi; limits: memory=2Gi)", info.String())
+	info.eviction = "node n1 is cordoned"
+	require.Equal(t, "on node n1 (allocatable: cpu=4, memory=16Gi; limits: memory=2Gi), while node n1 is cordoned", info.String())
+	require.Equal(t, "on node n1", (&kubeInfo{node: "n1"}).String())
+}
+
+func TestAnnotateFailure(t *testing.T) {
+	a := &APIServer{}
+	err := classify(pps.FailureType_OOM_KILLED, errors.New("killed"))
+	// Without a kubernetes client, failures aren't annotated
+	require.Equal(t, err, a.annotateFailure(err))
+
+	a.kubeInfoCache.info = &kubeInfo{node: "n1", eviction: "node n1 has MemoryPressure"}
+	a.kubeInfoCache.updated = time.Now()
+	annotated := a.annotateFailure(err)
+	require.Equal(t, "killed (on node n1, while node n1 has MemoryPressure)", annotated.Error())
+	require.Equal(t, pps.FailureType_OOM_KILLED, failureType(annotated))
+}
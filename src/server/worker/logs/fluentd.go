@@ -0,0 +1,156 @@
+package logs
+
+import (
+	"encoding/binary"
+	"net"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// fluentdBackend sends messages to fluentd (or fluent-bit) with the forward
+// protocol, as records tagged "<tag>.<pipeline>"
+type fluentdBackend struct {
+	addr string
+	tag  string
+	conn net.Conn
+}
+
+func newFluentdBackend(addr, tag string) *fluentdBackend {
+	return &fluentdBackend{addr: addr, tag: tag}
+}
+
+func (b *fluentdBackend) send(msgs []*pps.LogMessage) error {
+	// group the messages by tag, preserving their order, so that each tag is
+	// sent as one forward mode message
+	var tags []string
+	entries := make(map[string][]*pps.LogMessage)
+	for _, msg := range msgs {
+		tag := b.tag + "." + msg.PipelineName
+		if _, ok := entries[tag]; !ok {
+			tags = append(tags, tag)
+		}
+		entries[tag] = append(entries[tag], msg)
+	}
+	var buf []byte
+	for _, tag := range tags {
+		buf = encodeForward(buf, tag, entries[tag])
+	}
+	if b.conn == nil {
+		conn, err := net.DialTimeout("tcp", b.addr, 10*time.Second)
+		if err != nil {
+			return err
+		}
+		b.conn = conn
+	}
+	b.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	if _, err := b.conn.Write(buf); err != nil {
+		// reconnect on the next try
+		b.conn.Close()
+		b.conn = nil
+		return err
+	}
+	return nil
+}
+
+func (b *fluentdBackend) close() error {
+	if b.conn == nil {
+		return nil
+	}
+	return b.conn.Close()
+}
+
+// encodeForward appends the forward mode message [tag, [[time, record]...]]
+// for 'msgs' to 'buf', in msgpack
+func encodeForward(buf []byte, tag string, msgs []*pps.LogMessage) []byte {
+	buf = appendArrayHeader(buf, 2)
+	buf = appendString(buf, tag)
+	buf = appendArrayHeader(buf, len(msgs))
+	for _, msg := range msgs {
+		buf = appendArrayHeader(buf, 2)
+		buf = appendInt(buf, timestamp(msg).Unix())
+		buf = appendMapHeader(buf, 7)
+		buf = appendString(buf, "pipelineName")
+		buf = appendString(buf, msg.PipelineName)
+		buf = appendString(buf, "jobId")
+		buf = appendString(buf, msg.JobID)
+		buf = appendString(buf, "workerId")
+		buf = appendString(buf, msg.WorkerID)
+		buf = appendString(buf, "datumId")
+		buf = appendString(buf, msg.DatumID)
+		buf = appendString(buf, "master")
+		buf = appendBool(buf, msg.Master)
+		buf = appendString(buf, "user")
+		buf = appendBool(buf, msg.User)
+		buf = appendString(buf, "message")
+		buf = appendString(buf, msg.Message)
+	}
+	return buf
+}
+
+// The msgpack encodings used by encodeForward (see
+// https://github.com/msgpack/msgpack/blob/master/spec.md)
+
+func appendArrayHeader(buf []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(buf, 0x90|byte(n))
+	case n < 1<<16:
+		return appendUint16(append(buf, 0xdc), uint16(n))
+	default:
+		return appendUint32(append(buf, 0xdd), uint32(n))
+	}
+}
+
+func appendMapHeader(buf []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(buf, 0x80|byte(n))
+	case n < 1<<16:
+		return appendUint16(append(buf, 0xde), uint16(n))
+	default:
+		return appendUint32(append(buf, 0xdf), uint32(n))
+	}
+}
+
+func appendString(buf []byte, s string) []byte {
+	switch n := len(s); {
+	case n < 32:
+		buf = append(buf, 0xa0|byte(n))
+	case n < 1<<8:
+		buf = append(buf, 0xd9, byte(n))
+	case n < 1<<16:
+		buf = appendUint16(append(buf, 0xda), uint16(n))
+	default:
+		buf = appendUint32(append(buf, 0xdb), uint32(n))
+	}
+	return append(buf, s...)
+}
+
+func appendBool(buf []byte, b bool) []byte {
+	if b {
+		return append(buf, 0xc3)
+	}
+	return append(buf, 0xc2)
+}
+
+func appendInt(buf []byte, i int64) []byte {
+	if i >= 0 && i < 128 {
+		return append(buf, byte(i))
+	}
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(i))
+	return append(append(buf, 0xd3), b[:]...)
+}
+
+func appendUint16(buf []byte, i uint16) []byte {
+	var b [2]byte
+	binary.BigEndian.PutUint16(b[:], i)
+	return append(buf, b[:]...)
+}
+
+func appendUint32(buf []byte, i uint32) []byte {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], i)
+	return append(buf, b[:]...)
+}
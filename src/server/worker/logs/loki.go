@@ -0,0 +1,106 @@
+package logs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// lokiPushPath is the path of Loki's push API, used if the sink doesn't set one
+const lokiPushPath = "/loki/api/v1/push"
+
+// lokiBackend sends messages to Loki's push API. Each worker's user and
+// worker messages are separate streams, labeled with the pipeline and worker;
+// the job and datum are left in the (JSON) log line, to keep the number of
+// streams small.
+type lokiBackend struct {
+	url    string
+	tenant string
+	client *http.Client
+}
+
+func newLokiBackend(u *url.URL) *lokiBackend {
+	scheme := "http"
+	if u.Scheme == "loki+https" {
+		scheme = "https"
+	}
+	path := u.Path
+	if path == "" || path == "/" {
+		path = lokiPushPath
+	}
+	return &lokiBackend{
+		url:    (&url.URL{Scheme: scheme, Host: u.Host, Path: path}).String(),
+		tenant: u.Query().Get("tenant"),
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+type lokiPushRequest struct {
+	Streams []*lokiStream `json:"streams"`
+}
+
+func (b *lokiBackend) send(msgs []*pps.LogMessage) error {
+	req := &lokiPushRequest{}
+	streams := make(map[string]*lokiStream)
+	for _, msg := range msgs {
+		l, err := line(msg)
+		if err != nil {
+			return err
+		}
+		labels := map[string]string{
+			"source":   "pachyderm",
+			"pipeline": msg.PipelineName,
+			"worker":   msg.WorkerID,
+			"user":     strconv.FormatBool(msg.User),
+		}
+		key := strings.Join([]string{labels["pipeline"], labels["worker"], labels["user"]}, "\x00")
+		stream, ok := streams[key]
+		if !ok {
+			stream = &lokiStream{Stream: labels}
+			streams[key] = stream
+			req.Streams = append(req.Streams, stream)
+		}
+		stream.Values = append(stream.Values, [2]string{strconv.FormatInt(timestamp(msg).UnixNano(), 10), l})
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequest("POST", b.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if b.tenant != "" {
+		httpReq.Header.Set("X-Scope-OrgID", b.tenant)
+	}
+	resp, err := b.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("loki responded with %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	return nil
+}
+
+func (b *lokiBackend) close() error {
+	return nil
+}
@@ -0,0 +1,181 @@
+// Package logs ships the log messages of pipeline workers to log aggregators
+// (Loki, fluentd or syslog), for clusters that don't collect the logs of
+// their Kubernetes pods.
+package logs
+
+import (
+	"fmt"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/types"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/backoff"
+)
+
+const (
+	// bufferSize is the number of messages a sink queues before it starts
+	// dropping them, so that a slow or unreachable aggregator never blocks the
+	// worker
+	bufferSize = 10000
+	// batchSize is the maximum number of messages sent to an aggregator at once
+	batchSize = 500
+	// flushInterval is how long a message may wait for its batch to fill up
+	flushInterval = time.Second
+	// maxRetryTime bounds how long a batch is retried before it's dropped
+	maxRetryTime = 30 * time.Second
+	// defaultTag is the tag (fluentd) or app name (syslog) of messages if the
+	// sink doesn't set one
+	defaultTag = "pachyderm"
+)
+
+// Sink receives the log messages of a worker and sends them to a log
+// aggregator in the background. Batches that fail are retried as a whole, so
+// messages may be delivered more than once.
+type Sink interface {
+	// Write queues 'msg' to be sent. It never blocks; if the sink's buffer is
+	// full, 'msg' is dropped. 'msg' must not be modified afterwards.
+	Write(msg *pps.LogMessage)
+	// Close sends the queued messages and closes the sink. Write must not be
+	// called after Close.
+	Close() error
+}
+
+// NewSink creates the Sink described by the URL 'spec', or returns nil if
+// 'spec' is empty. The supported sinks are:
+//
+//	loki://<host>:<port>[/<path>][?tenant=<org ID>]  (also loki+https://)
+//	fluentd://<host>:<port>[?tag=<tag>]
+//	syslog://<host>:<port>[?tag=<app name>]          (UDP, or syslog+tcp://)
+func NewSink(spec string) (Sink, error) {
+	if spec == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(spec)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse log sink %q: %v", spec, err)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("log sink %q has no host", spec)
+	}
+	var b backend
+	switch u.Scheme {
+	case "loki", "loki+http", "loki+https":
+		b = newLokiBackend(u)
+	case "fluentd":
+		b = newFluentdBackend(u.Host, tag(u))
+	case "syslog", "syslog+udp":
+		b = newSyslogBackend("udp", u.Host, tag(u))
+	case "syslog+tcp":
+		b = newSyslogBackend("tcp", u.Host, tag(u))
+	default:
+		return nil, fmt.Errorf("unknown log sink type %q (must be one of loki, fluentd or syslog)", u.Scheme)
+	}
+	return newBufferedSink(b), nil
+}
+
+func tag(u *url.URL) string {
+	if t := u.Query().Get("tag"); t != "" {
+		return t
+	}
+	return defaultTag
+}
+
+// backend sends batches of messages to a log aggregator. Its methods are only
+// called from the sink's background goroutine.
+type backend interface {
+	send(msgs []*pps.LogMessage) error
+	close() error
+}
+
+// bufferedSink queues messages and sends them to its backend in batches
+type bufferedSink struct {
+	backend backend
+	msgCh   chan *pps.LogMessage
+	done    chan struct{}
+	dropped int64 // accessed atomically
+}
+
+func newBufferedSink(b backend) *bufferedSink {
+	s := &bufferedSink{
+		backend: b,
+		msgCh:   make(chan *pps.LogMessage, bufferSize),
+		done:    make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+func (s *bufferedSink) Write(msg *pps.LogMessage) {
+	select {
+	case s.msgCh <- msg:
+	default:
+		atomic.AddInt64(&s.dropped, 1)
+	}
+}
+
+func (s *bufferedSink) Close() error {
+	close(s.msgCh)
+	<-s.done
+	return s.backend.close()
+}
+
+func (s *bufferedSink) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	var batch []*pps.LogMessage
+	flush := func() {
+		if dropped := atomic.SwapInt64(&s.dropped, 0); dropped > 0 {
+			log.Errorf("log sink buffer is full, dropped %d log messages", dropped)
+		}
+		if len(batch) == 0 {
+			return
+		}
+		b := backoff.NewExponentialBackOff()
+		b.MaxElapsedTime = maxRetryTime
+		if err := backoff.RetryNotify(func() error {
+			return s.backend.send(batch)
+		}, b, func(err error, d time.Duration) error {
+			log.Warnf("error sending logs to log sink; retrying in %v: %v", d, err)
+			return nil
+		}); err != nil {
+			log.Errorf("could not send logs to log sink, dropped %d log messages: %v", len(batch), err)
+		}
+		batch = nil
+	}
+	for {
+		select {
+		case msg, ok := <-s.msgCh:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, msg)
+			if len(batch) >= batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+var marshaler = &jsonpb.Marshaler{}
+
+// line returns 'msg' as the JSON line that workers print to stdout
+func line(msg *pps.LogMessage) (string, error) {
+	return marshaler.MarshalToString(msg)
+}
+
+// timestamp returns the time at which 'msg' was logged
+func timestamp(msg *pps.LogMessage) time.Time {
+	if ts, err := types.TimestampFromProto(msg.Ts); err == nil {
+		return ts
+	}
+	return time.Now()
+}
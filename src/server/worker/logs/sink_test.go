@@ -0,0 +1,140 @@
+package logs
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func testMessage(text string) *pps.LogMessage {
+	ts, _ := types.TimestampProto(time.Unix(1500000000, 0))
+	return &pps.LogMessage{
+		PipelineName: "pipeline",
+		JobID:        "job",
+		WorkerID:     "pipeline-v1-abcde",
+		User:         true,
+		Message:      text,
+		Ts:           ts,
+	}
+}
+
+func TestNewSink(t *testing.T) {
+	s, err := NewSink("")
+	require.NoError(t, err)
+	require.True(t, s == nil)
+	_, err = NewSink("kafka://localhost:9092")
+	require.YesError(t, err)
+	_, err = NewSink("loki://")
+	require.YesError(t, err)
+
+	u, err := url.Parse("loki+https://loki:3100")
+	require.NoError(t, err)
+	b := newLokiBackend(u)
+	require.Equal(t, "https://loki:3100/loki/api/v1/push", b.url)
+	u, err = url.Parse("loki://loki:3100/api/prom/push?tenant=team")
+	require.NoError(t, err)
+	b = newLokiBackend(u)
+	require.Equal(t, "http://loki:3100/api/prom/push", b.url)
+	require.Equal(t, "team", b.tenant)
+}
+
+func TestLokiSink(t *testing.T) {
+	pushes := make(chan *lokiPushRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, lokiPushPath, r.URL.Path)
+		require.Equal(t, "team", r.Header.Get("X-Scope-OrgID"))
+		req := &lokiPushRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(req))
+		pushes <- req
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	s, err := NewSink("loki://" + strings.TrimPrefix(server.URL, "http://") + "?tenant=team")
+	require.NoError(t, err)
+	s.Write(testMessage("foo"))
+	s.Write(testMessage("bar"))
+	require.NoError(t, s.Close())
+
+	req := <-pushes
+	require.Equal(t, 1, len(req.Streams))
+	require.Equal(t, map[string]string{
+		"source":   "pachyderm",
+		"pipeline": "pipeline",
+		"worker":   "pipeline-v1-abcde",
+		"user":     "true",
+	}, req.Streams[0].Stream)
+	require.Equal(t, 2, len(req.Streams[0].Values))
+	require.Equal(t, "1500000000000000000", req.Streams[0].Values[0][0])
+	require.True(t, strings.Contains(req.Streams[0].Values[0][1], `"message":"foo"`))
+	require.True(t, strings.Contains(req.Streams[0].Values[1][1], `"message":"bar"`))
+}
+
+func TestEncodeForward(t *testing.T) {
+	msg := &pps.LogMessage{Message: "hi"}
+	buf := encodeForward(nil, "t", []*pps.LogMessage{msg})
+	// [ "t", [ [ <time>, {...} ] ] ]
+	require.Equal(t, []byte{0x92, 0xa1, 't', 0x91, 0x92, 0xd3}, buf[:6])
+	// the record map follows the 8 byte time
+	require.Equal(t, byte(0x87), buf[14])
+	require.True(t, strings.HasSuffix(string(buf), "\xa7message\xa2hi"))
+
+	require.Equal(t, []byte{0xd9, 40}, appendString(nil, strings.Repeat("a", 40))[:2])
+	require.Equal(t, []byte{0xdc, 0x01, 0x00}, appendArrayHeader(nil, 256))
+	require.Equal(t, []byte{0x05}, appendInt(nil, 5))
+}
+
+func TestSyslogSink(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	received := make(chan string, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		// read one message, framed by its length
+		r := bufio.NewReader(conn)
+		length, err := r.ReadString(' ')
+		if err != nil {
+			return
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(length))
+		if err != nil {
+			return
+		}
+		msg := make([]byte, n)
+		if _, err := io.ReadFull(r, msg); err != nil {
+			return
+		}
+		received <- string(msg)
+	}()
+
+	s, err := NewSink("syslog+tcp://" + l.Addr().String() + "?tag=app")
+	require.NoError(t, err)
+	s.Write(testMessage("foo"))
+	require.NoError(t, s.Close())
+
+	m, err := formatSyslog(testMessage("foo"), "app")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(m, "<14>1 2017-07-14T02:40:00Z pipeline-v1-abcde app - pipeline - {"), m)
+	require.True(t, strings.Contains(m, `"message":"foo"`))
+	require.Equal(t, m, <-received)
+	require.Equal(t, "-", syslogField(" ", 10))
+	require.Equal(t, "abc", syslogField("a b c d", 3))
+}
@@ -0,0 +1,96 @@
+package logs
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// syslogPriority is the priority of every message: facility "user" (1) and
+// severity "informational" (6)
+const syslogPriority = 1*8 + 6
+
+// syslogBackend sends messages to a syslog server in the RFC 5424 format, with
+// the worker as the hostname and the pipeline as the message ID. Over TCP,
+// messages are framed with octet counting (RFC 6587).
+type syslogBackend struct {
+	network string
+	addr    string
+	tag     string
+	conn    net.Conn
+}
+
+func newSyslogBackend(network, addr, tag string) *syslogBackend {
+	return &syslogBackend{network: network, addr: addr, tag: tag}
+}
+
+func (b *syslogBackend) send(msgs []*pps.LogMessage) error {
+	if b.conn == nil {
+		conn, err := net.DialTimeout(b.network, b.addr, 10*time.Second)
+		if err != nil {
+			return err
+		}
+		b.conn = conn
+	}
+	b.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	for _, msg := range msgs {
+		m, err := formatSyslog(msg, b.tag)
+		if err != nil {
+			return err
+		}
+		if b.network == "tcp" {
+			m = fmt.Sprintf("%d %s", len(m), m)
+		}
+		if _, err := b.conn.Write([]byte(m)); err != nil {
+			b.conn.Close()
+			b.conn = nil
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *syslogBackend) close() error {
+	if b.conn == nil {
+		return nil
+	}
+	return b.conn.Close()
+}
+
+// formatSyslog formats 'msg' as an RFC 5424 syslog message, whose body is the
+// JSON log line
+func formatSyslog(msg *pps.LogMessage, tag string) (string, error) {
+	l, err := line(msg)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("<%d>1 %s %s %s - %s - %s",
+		syslogPriority,
+		timestamp(msg).UTC().Format(time.RFC3339Nano),
+		syslogField(msg.WorkerID, 255),
+		syslogField(tag, 48),
+		syslogField(msg.PipelineName, 32),
+		l), nil
+}
+
+// syslogField returns 's' in a form that can be used as a syslog header
+// field: printable ASCII without spaces, at most 'maxLen' long, or "-" if
+// it's empty
+func syslogField(s string, maxLen int) string {
+	s = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return -1
+		}
+		return r
+	}, s)
+	if len(s) > maxLen {
+		s = s[:maxLen]
+	}
+	if s == "" {
+		return "-"
+	}
+	return s
+}
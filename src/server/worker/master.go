@@ -1,11 +1,10 @@
 package worker
 
 import (
-	"archive/tar"
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
-	"io"
 	"log"
 	"os"
 	"path"
@@ -13,24 +12,23 @@ import (
 	"time"
 
 	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
 	"github.com/gogo/protobuf/types"
 
 	"github.com/pachyderm/pachyderm/src/client"
 	"github.com/pachyderm/pachyderm/src/client/auth"
+	"github.com/pachyderm/pachyderm/src/client/limit"
 	"github.com/pachyderm/pachyderm/src/client/pfs"
 	"github.com/pachyderm/pachyderm/src/client/pkg/pbutil"
 	"github.com/pachyderm/pachyderm/src/client/pps"
-	pfsserver "github.com/pachyderm/pachyderm/src/server/pfs"
 	"github.com/pachyderm/pachyderm/src/server/pkg/backoff"
 	col "github.com/pachyderm/pachyderm/src/server/pkg/collection"
 	"github.com/pachyderm/pachyderm/src/server/pkg/dlock"
 	"github.com/pachyderm/pachyderm/src/server/pkg/errutil"
-	"github.com/pachyderm/pachyderm/src/server/pkg/obj"
 	"github.com/pachyderm/pachyderm/src/server/pkg/ppsconsts"
 	"github.com/pachyderm/pachyderm/src/server/pkg/ppsutil"
-	filesync "github.com/pachyderm/pachyderm/src/server/pkg/sync"
-	pfs_sync "github.com/pachyderm/pachyderm/src/server/pkg/sync"
 	"github.com/pachyderm/pachyderm/src/server/pkg/watch"
+	"golang.org/x/sync/errgroup"
 )
 
 const (
@@ -42,6 +40,8 @@ func (a *APIServer) getMasterLogger() *taggedLogger {
 		template:  a.logMsgTemplate, // Copy struct
 		stderrLog: log.Logger{},
 		marshaler: &jsonpb.Marshaler{},
+		tail:      a.logTail,
+		config:    a.config,
 	}
 	result.stderrLog.SetOutput(os.Stderr)
 	result.stderrLog.SetFlags(log.LstdFlags | log.Llongfile) // Log file/line
@@ -54,6 +54,8 @@ func (a *APIServer) getWorkerLogger() *taggedLogger {
 		template:  a.logMsgTemplate, // Copy struct
 		stderrLog: log.Logger{},
 		marshaler: &jsonpb.Marshaler{},
+		tail:      a.logTail,
+		config:    a.config,
 	}
 	result.stderrLog.SetOutput(os.Stderr)
 	result.stderrLog.SetFlags(log.LstdFlags | log.Llongfile) // Log file/line
@@ -83,6 +85,10 @@ func (a *APIServer) master(masterType string, spawner func(*client.APIClient) er
 			return err
 		}
 		defer masterLock.Unlock(ctx)
+		if err := a.reloadPipelineInfo(pachClient); err != nil {
+			return err
+		}
+		go a.watchInPlaceUpdates(pachClient, cancel)
 		logger.Logf("Launching %v master process", masterType)
 		return spawner(pachClient)
 	}, b, func(err error, d time.Duration) error {
@@ -97,145 +103,291 @@ func (a *APIServer) master(masterType string, spawner func(*client.APIClient) er
 	})
 }
 
-func (a *APIServer) jobSpawner(pachClient *client.APIClient) error {
-	logger := a.getMasterLogger()
-	// Listen for new commits, and create jobs when they arrive
-	commitIter, err := pachClient.SubscribeCommit(a.pipelineInfo.Pipeline.Name, "",
-		client.NewCommitProvenance(ppsconsts.SpecRepo, a.pipelineInfo.Pipeline.Name, a.pipelineInfo.SpecCommit.ID),
-		"", pfs.CommitState_READY)
+// reloadPipelineInfo picks up any in-place update (see
+// ppsutil.UpdatableInPlace) made to the pipeline since this worker read its
+// spec. The fields that such an update changes are only read by the master,
+// so this is safe to call from master() while no spawner is running.
+func (a *APIServer) reloadPipelineInfo(pachClient *client.APIClient) error {
+	ptr := &pps.EtcdPipelineInfo{}
+	if err := a.pipelines.ReadOnly(pachClient.Ctx()).Get(a.pipelineInfo.Pipeline.Name, ptr); err != nil {
+		return fmt.Errorf("could not read pipeline %q: %v", a.pipelineInfo.Pipeline.Name, err)
+	}
+	if ptr.SpecCommit.ID == a.specCommit().ID {
+		return nil
+	}
+	pipelineInfo, err := ppsutil.GetPipelineInfo(pachClient, ptr)
 	if err != nil {
 		return err
 	}
-	defer commitIter.Close()
-	for {
-		commitInfo, err := commitIter.Next()
-		if err != nil {
+	if pipelineInfo.Version != a.pipelineInfo.Version {
+		return nil // this worker is stale, and PPS will replace it
+	}
+	a.getMasterLogger().Logf("reloading spec commit %s (pipeline updated in place)", ptr.SpecCommit.ID)
+	a.pipelineInfoMu.Lock()
+	defer a.pipelineInfoMu.Unlock()
+	ppsutil.UpdateInPlace(a.pipelineInfo, pipelineInfo)
+	return nil
+}
+
+// specCommit returns the pipeline's current spec commit, which changes if the
+// pipeline is updated in place
+func (a *APIServer) specCommit() *pfs.Commit {
+	a.pipelineInfoMu.RLock()
+	defer a.pipelineInfoMu.RUnlock()
+	return a.pipelineInfo.SpecCommit
+}
+
+// plannedNumWorkers returns the number of workers that the pipeline is
+// expected to have, which changes if its parallelism is updated in place
+func (a *APIServer) plannedNumWorkers() (int, error) {
+	a.pipelineInfoMu.RLock()
+	pipelineInfo := &pps.PipelineInfo{
+		ParallelismSpec: a.pipelineInfo.ParallelismSpec,
+		AutoscalingSpec: a.pipelineInfo.AutoscalingSpec,
+	}
+	a.pipelineInfoMu.RUnlock()
+	return ppsutil.GetPlannedNumWorkers(a.kubeClient, pipelineInfo)
+}
+
+// watchInPlaceUpdates calls 'cancel' if the pipeline is updated in place while
+// this worker is the master, so that master() restarts the spawner with the
+// new spec commit (spawners only see output commits that are provenant on the
+// spec commit they started with).
+func (a *APIServer) watchInPlaceUpdates(pachClient *client.APIClient, cancel func()) {
+	name, specCommit, version := a.pipelineInfo.Pipeline.Name, a.specCommit().ID, a.pipelineInfo.Version
+	if err := a.pipelines.ReadOnly(pachClient.Ctx()).WatchOneF(name, func(e *watch.Event) error {
+		if e.Type == watch.EventError {
+			return e.Err
+		}
+		if e.Type != watch.EventPut {
+			return nil
+		}
+		var key string
+		ptr := &pps.EtcdPipelineInfo{}
+		if err := e.Unmarshal(&key, ptr); err != nil {
 			return err
 		}
-		if commitInfo.Finished != nil {
-			continue
+		if ptr.SpecCommit.ID == specCommit {
+			return nil
 		}
-		// Inspect the commit and check again if it has been finished (it may have
-		// been closed since it was queued, e.g. by StopPipeline or StopJob)
-		commitInfo, err = pachClient.InspectCommit(commitInfo.Commit.Repo.Name, commitInfo.Commit.ID)
+		pipelineInfo, err := ppsutil.GetPipelineInfo(pachClient, ptr)
 		if err != nil {
 			return err
 		}
-		// Determine stats commit.
-		var statsCommit *pfs.Commit
-		if a.pipelineInfo.EnableStats {
-			for _, commitRange := range commitInfo.Subvenance {
-				if commitRange.Lower.Repo.Name == a.pipelineInfo.Pipeline.Name && commitRange.Upper.Repo.Name == a.pipelineInfo.Pipeline.Name {
-					statsCommit = commitRange.Lower
-				}
-			}
-		}
-		if commitInfo.Finished != nil {
-			// Finish stats commit if it has not been finished.
-			if statsCommit != nil {
-				if _, err := pachClient.PfsAPIClient.FinishCommit(pachClient.Ctx(), &pfs.FinishCommitRequest{
-					Commit: statsCommit,
-					Empty:  true,
-				}); err != nil && !pfsserver.IsCommitFinishedErr(err) {
-					return err
-				}
-			}
-			// Make sure that the job has been correctly finished as the commit has.
-			ji, err := pachClient.InspectJobOutputCommit(commitInfo.Commit.Repo.Name, commitInfo.Commit.ID, false)
-			if err != nil {
-				// If no job was created for the commit, then we are done.
-				if strings.Contains(err.Error(), fmt.Sprintf("job with output commit %s not found", commitInfo.Commit.ID)) {
-					continue
-				}
-				return err
-			}
-			if !ppsutil.IsTerminal(ji.State) {
-				if len(commitInfo.Trees) == 0 {
-					if err := a.updateJobState(pachClient.Ctx(), ji,
-						pps.JobState_JOB_KILLED, "output commit is finished without data, but job state has not been updated"); err != nil {
-						return fmt.Errorf("could not kill job with finished output commit: %v", err)
-					}
-				} else {
-					if err := a.updateJobState(pachClient.Ctx(), ji, pps.JobState_JOB_SUCCESS, ""); err != nil {
-						return fmt.Errorf("could not mark job with finished output commit as successful: %v", err)
-					}
-				}
-			}
-			continue // commit finished after queueing
+		if pipelineInfo.Version == version {
+			cancel()
+			return errutil.ErrBreak
 		}
+		return nil
+	}); err != nil && pachClient.Ctx().Err() == nil {
+		a.getMasterLogger().Logf("error watching for in-place updates to %q: %v", name, err)
+	}
+}
 
-		// Check if a job was previously created for this commit. If not, make one
-		var jobInfo *pps.JobInfo // job for commitInfo (new or old)
-		jobInfos, err := pachClient.ListJob("", nil, commitInfo.Commit, -1, true)
+func (a *APIServer) jobSpawner(pachClient *client.APIClient) error {
+	eg, ctx := errgroup.WithContext(pachClient.Ctx())
+	pachClient = pachClient.WithCtx(ctx)
+	// Listen for new commits, and create jobs when they arrive
+	commitIter, err := pachClient.SubscribeCommit(a.pipelineInfo.Pipeline.Name, "",
+		client.NewCommitProvenance(ppsconsts.SpecRepo, a.pipelineInfo.Pipeline.Name, a.specCommit().ID),
+		"", pfs.CommitState_READY)
+	if err != nil {
+		return err
+	}
+	if a.pipelineInfo.Defer != nil {
+		// Hold new output commits back until the pipeline is triggered
+		deferred, err := a.newDeferredCommits(pachClient, commitIter)
 		if err != nil {
+			commitIter.Close()
 			return err
 		}
-		if len(jobInfos) > 1 {
-			return fmt.Errorf("multiple jobs found for commit: %s/%s", commitInfo.Commit.Repo.Name, commitInfo.Commit.ID)
-		} else if len(jobInfos) < 1 {
-			job, err := pachClient.CreateJob(a.pipelineInfo.Pipeline.Name, commitInfo.Commit, statsCommit)
+		commitIter = deferred
+	}
+	defer commitIter.Close()
+	// New output commits are queued, and their jobs are started from the
+	// queue, so that at most max_outstanding_jobs jobs run at once
+	queue := newJobQueue(a.pipelineInfo.JobOrder)
+	eg.Go(func() error {
+		for {
+			commitInfo, err := commitIter.Next()
 			if err != nil {
 				return err
 			}
-			logger.Logf("creating new job %q for output commit %q", job.ID, commitInfo.Commit.ID)
-			// get jobInfo to look up spec commit, pipeline version, etc (if this
-			// worker is stale and about to be killed, the new job may have a newer
-			// pipeline version than the master. Or if the commit is stale, it may
-			// have an older pipeline version than the master)
-			jobInfo, err = pachClient.InspectJob(job.ID, false)
-			if err != nil {
-				return err
+			if commitInfo.Finished != nil {
+				continue
 			}
-		} else {
-			// get latest job state
-			jobInfo, err = pachClient.InspectJob(jobInfos[0].Job.ID, false)
-			logger.Logf("found existing job %q for output commit %q", jobInfo.Job.ID, commitInfo.Commit.ID)
+			queue.push(commitInfo)
+		}
+	})
+	eg.Go(func() error {
+		limiter := limit.New(a.maxOutstandingJobs())
+		for {
+			// A commit is only popped once a job can start, so that it's
+			// picked from all of the commits queued by then
+			limiter.Acquire()
+			commitInfo, err := queue.pop(ctx)
 			if err != nil {
 				return err
 			}
+			eg.Go(func() error {
+				defer limiter.Release()
+				// Each job gets its own logger, as loggers aren't thread-safe
+				return a.spawnJob(pachClient, commitInfo, a.getMasterLogger())
+			})
 		}
+	})
+	return eg.Wait()
+}
 
-		switch {
-		case ppsutil.IsTerminal(jobInfo.State):
-			if jobInfo.StatsCommit != nil {
-				if _, err := pachClient.PfsAPIClient.FinishCommit(pachClient.Ctx(), &pfs.FinishCommitRequest{
-					Commit: jobInfo.StatsCommit,
-					Empty:  true,
-				}); err != nil && !pfsserver.IsCommitFinishedErr(err) {
-					return err
-				}
+// maxOutstandingJobs returns how many of the pipeline's jobs may run at once
+func (a *APIServer) maxOutstandingJobs() int {
+	if a.pipelineInfo.MaxOutstandingJobs < 1 {
+		return 1
+	}
+	return int(a.pipelineInfo.MaxOutstandingJobs)
+}
+
+// waitsForParentJobs returns true if each of the pipeline's jobs waits for
+// the job of its output commit's parent to finish, and builds on that job's
+// output (skipping the datums that it processed). That's the case if the
+// pipeline runs one job at a time, in commit order. Otherwise, the parent's
+// job may still be queued, or run after this one, so jobs build on the output
+// commits that were finished when they started instead (see
+// getParentCommitInfo).
+func (a *APIServer) waitsForParentJobs() bool {
+	return a.maxOutstandingJobs() == 1 && a.pipelineInfo.JobOrder == pps.JobOrder_OLDEST_JOB_FIRST
+}
+
+// spawnJob creates the job for the output commit in 'commitInfo' (unless it
+// exists already), and returns once the job is finished
+func (a *APIServer) spawnJob(pachClient *client.APIClient, commitInfo *pfs.CommitInfo, logger *taggedLogger) error {
+	// Inspect the commit and check again if it has been finished (it may have
+	// been closed since it was queued, e.g. by StopPipeline or StopJob)
+	commitInfo, err := pachClient.InspectCommit(commitInfo.Commit.Repo.Name, commitInfo.Commit.ID)
+	if err != nil {
+		return err
+	}
+	// Determine stats commit.
+	var statsCommit *pfs.Commit
+	if a.pipelineInfo.EnableStats {
+		for _, commitRange := range commitInfo.Subvenance {
+			if commitRange.Lower.Repo.Name == a.pipelineInfo.Pipeline.Name && commitRange.Upper.Repo.Name == a.pipelineInfo.Pipeline.Name {
+				statsCommit = commitRange.Lower
 			}
+		}
+	}
+	if commitInfo.Finished != nil {
+		// Finish stats commit if it has not been finished.
+		if statsCommit != nil {
 			if _, err := pachClient.PfsAPIClient.FinishCommit(pachClient.Ctx(), &pfs.FinishCommitRequest{
-				Commit: jobInfo.OutputCommit,
+				Commit: statsCommit,
 				Empty:  true,
-			}); err != nil && !pfsserver.IsCommitFinishedErr(err) {
+			}); err != nil && !pfs.IsCommitFinishedErr(err) {
 				return err
 			}
-			// ignore finished jobs (e.g. old pipeline & already killed)
-			continue
-		case jobInfo.PipelineVersion < a.pipelineInfo.Version:
-			// kill unfinished jobs from old pipelines (should generally be cleaned
-			// up by PPS master, but the PPS master can fail, and if these jobs
-			// aren't killed, future jobs will hang indefinitely waiting for their
-			// parents to finish)
-			if err := a.updateJobState(pachClient.Ctx(), jobInfo,
-				pps.JobState_JOB_KILLED, "pipeline has been updated"); err != nil {
-				return fmt.Errorf("could not kill stale job: %v", err)
+		}
+		// Make sure that the job has been correctly finished as the commit has.
+		ji, err := pachClient.InspectJobOutputCommit(commitInfo.Commit.Repo.Name, commitInfo.Commit.ID, false)
+		if err != nil {
+			// If no job was created for the commit, then we are done.
+			if pps.IsJobNotFoundErr(err) {
+				return nil
 			}
-			continue
-		case jobInfo.PipelineVersion > a.pipelineInfo.Version:
-			return fmt.Errorf("job %s's version (%d) greater than pipeline's "+
-				"version (%d), this should automatically resolve when the worker "+
-				"is updated", jobInfo.Job.ID, jobInfo.PipelineVersion, a.pipelineInfo.Version)
+			return err
 		}
+		if !ppsutil.IsTerminal(ji.State) {
+			if len(commitInfo.Trees) == 0 {
+				if err := a.updateJobState(pachClient.Ctx(), ji,
+					pps.JobState_JOB_KILLED, "output commit is finished without data, but job state has not been updated", pps.FailureType_FAILURE_UNKNOWN); err != nil {
+					return fmt.Errorf("could not kill job with finished output commit: %v", err)
+				}
+			} else {
+				if err := a.updateJobState(pachClient.Ctx(), ji, pps.JobState_JOB_SUCCESS, "", pps.FailureType_FAILURE_UNKNOWN); err != nil {
+					return fmt.Errorf("could not mark job with finished output commit as successful: %v", err)
+				}
+			}
+		}
+		return nil // commit finished after queueing
+	}
 
-		// Now that the jobInfo is persisted, wait until all input commits are
-		// ready, split the input datums into chunks and merge the results of
-		// chunks as they're processed
-		if err := a.waitJob(pachClient, jobInfo, logger); err != nil {
+	// Check if a job was previously created for this commit. If not, make one
+	var jobInfo *pps.JobInfo // job for commitInfo (new or old)
+	jobInfos, err := pachClient.ListJob("", nil, commitInfo.Commit, -1, true)
+	if err != nil {
+		return err
+	}
+	if len(jobInfos) > 1 {
+		return fmt.Errorf("multiple jobs found for commit: %s/%s", commitInfo.Commit.Repo.Name, commitInfo.Commit.ID)
+	} else if len(jobInfos) < 1 {
+		job, err := pachClient.CreateJob(a.pipelineInfo.Pipeline.Name, commitInfo.Commit, statsCommit)
+		if err != nil {
+			return err
+		}
+		logger.Logf("creating new job %q for output commit %q", job.ID, commitInfo.Commit.ID)
+		// get jobInfo to look up spec commit, pipeline version, etc (if this
+		// worker is stale and about to be killed, the new job may have a newer
+		// pipeline version than the master. Or if the commit is stale, it may
+		// have an older pipeline version than the master)
+		jobInfo, err = pachClient.InspectJob(job.ID, false)
+		if err != nil {
 			return err
 		}
+	} else {
+		// get latest job state
+		jobInfo, err = pachClient.InspectJob(jobInfos[0].Job.ID, false)
+		logger.Logf("found existing job %q for output commit %q", jobInfo.Job.ID, commitInfo.Commit.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case ppsutil.IsTerminal(jobInfo.State):
+		if err := finishJobCommitsEmpty(pachClient, jobInfo); err != nil {
+			return err
+		}
+		// ignore finished jobs (e.g. old pipeline & already killed)
+		return nil
+	case jobInfo.PipelineVersion < a.pipelineInfo.Version:
+		// kill unfinished jobs from old pipelines (should generally be cleaned
+		// up by PPS master, but the PPS master can fail, and if these jobs
+		// aren't killed, future jobs will hang indefinitely waiting for their
+		// parents to finish)
+		if err := a.updateJobState(pachClient.Ctx(), jobInfo,
+			pps.JobState_JOB_KILLED, "pipeline has been updated", pps.FailureType_FAILURE_UNKNOWN); err != nil {
+			return fmt.Errorf("could not kill stale job: %v", err)
+		}
+		return nil
+	case jobInfo.PipelineVersion > a.pipelineInfo.Version:
+		return fmt.Errorf("job %s's version (%d) greater than pipeline's "+
+			"version (%d), this should automatically resolve when the worker "+
+			"is updated", jobInfo.Job.ID, jobInfo.PipelineVersion, a.pipelineInfo.Version)
 	}
+
+	// Now that the jobInfo is persisted, wait until all input commits are
+	// ready, split the input datums into chunks and merge the results of
+	// chunks as they're processed
+	return a.waitJob(pachClient, jobInfo, logger)
+}
+
+// finishJobCommitsEmpty finishes the output commit (and stats commit, if any)
+// of a job that won't run, if they aren't finished already
+func finishJobCommitsEmpty(pachClient *client.APIClient, jobInfo *pps.JobInfo) error {
+	if jobInfo.StatsCommit != nil {
+		if _, err := pachClient.PfsAPIClient.FinishCommit(pachClient.Ctx(), &pfs.FinishCommitRequest{
+			Commit: jobInfo.StatsCommit,
+			Empty:  true,
+		}); err != nil && !pfs.IsCommitFinishedErr(err) {
+			return err
+		}
+	}
+	if _, err := pachClient.PfsAPIClient.FinishCommit(pachClient.Ctx(), &pfs.FinishCommitRequest{
+		Commit: jobInfo.OutputCommit,
+		Empty:  true,
+	}); err != nil && !pfs.IsCommitFinishedErr(err) {
+		return err
+	}
+	return nil
 }
 
 func (a *APIServer) spoutSpawner(pachClient *client.APIClient) error {
@@ -247,7 +399,7 @@ func (a *APIServer) spoutSpawner(pachClient *client.APIClient) error {
 	if err != nil {
 		return fmt.Errorf("getTaggedLogger: %v", err)
 	}
-	puller := filesync.NewPuller()
+	puller := a.newPuller()
 
 	if err := a.unlinkData(nil); err != nil {
 		return fmt.Errorf("unlinkData: %v", err)
@@ -258,7 +410,7 @@ func (a *APIServer) spoutSpawner(pachClient *client.APIClient) error {
 			return fmt.Errorf("os.RemoveAll: %v", err)
 		}
 	}
-	dir, err = a.downloadData(pachClient, logger, nil, puller, &pps.ProcessStats{}, nil)
+	dir, err = a.downloadData(pachClient, logger, nil, nil, puller, &pps.ProcessStats{}, nil)
 	if err != nil {
 		return err
 	}
@@ -276,7 +428,7 @@ func (a *APIServer) spoutSpawner(pachClient *client.APIClient) error {
 func (a *APIServer) serviceSpawner(pachClient *client.APIClient) error {
 	ctx := pachClient.Ctx()
 	commitIter, err := pachClient.SubscribeCommit(a.pipelineInfo.Pipeline.Name, "",
-		client.NewCommitProvenance(ppsconsts.SpecRepo, a.pipelineInfo.Pipeline.Name, a.pipelineInfo.SpecCommit.ID),
+		client.NewCommitProvenance(ppsconsts.SpecRepo, a.pipelineInfo.Pipeline.Name, a.specCommit().ID),
 		"", pfs.CommitState_READY)
 	if err != nil {
 		return err
@@ -312,7 +464,7 @@ func (a *APIServer) serviceSpawner(pachClient *client.APIClient) error {
 		if err != nil {
 			return fmt.Errorf("getTaggedLogger: %v", err)
 		}
-		puller := filesync.NewPuller()
+		puller := a.newPuller()
 		// If this is our second time through the loop cleanup the old data.
 		if dir != "" {
 			if err := a.unlinkData(data); err != nil {
@@ -322,7 +474,7 @@ func (a *APIServer) serviceSpawner(pachClient *client.APIClient) error {
 				return fmt.Errorf("os.RemoveAll: %v", err)
 			}
 		}
-		dir, err = a.downloadData(pachClient, logger, data, puller, &pps.ProcessStats{}, nil)
+		dir, err = a.downloadData(pachClient, logger, data, nil, puller, &pps.ProcessStats{}, nil)
 		if err != nil {
 			return err
 		}
@@ -345,7 +497,7 @@ func (a *APIServer) serviceSpawner(pachClient *client.APIClient) error {
 				if err := jobs.Get(job.ID, jobPtr); err != nil {
 					return err
 				}
-				return ppsutil.UpdateJobState(a.pipelines.ReadWrite(stm), a.jobs.ReadWrite(stm), jobPtr, pps.JobState_JOB_RUNNING, "")
+				return ppsutil.UpdateJobState(a.pipelines.ReadWrite(stm), a.jobs.ReadWrite(stm), jobPtr, pps.JobState_JOB_RUNNING, "", pps.FailureType_FAILURE_UNKNOWN)
 			}); err != nil {
 				logger.Logf("error updating job state: %+v", err)
 			}
@@ -361,7 +513,7 @@ func (a *APIServer) serviceSpawner(pachClient *client.APIClient) error {
 					if err := jobs.Get(job.ID, jobPtr); err != nil {
 						return err
 					}
-					return ppsutil.UpdateJobState(a.pipelines.ReadWrite(stm), a.jobs.ReadWrite(stm), jobPtr, pps.JobState_JOB_SUCCESS, "")
+					return ppsutil.UpdateJobState(a.pipelines.ReadWrite(stm), a.jobs.ReadWrite(stm), jobPtr, pps.JobState_JOB_SUCCESS, "", pps.FailureType_FAILURE_UNKNOWN)
 				}); err != nil {
 					logger.Logf("error updating job progress: %+v", err)
 				}
@@ -397,14 +549,38 @@ func (a *APIServer) chunks(jobID string) col.Collection {
 	return col.NewCollection(a.etcdClient, path.Join(a.etcdPrefix, chunkPrefix, jobID), nil, &ChunkState{}, nil, nil)
 }
 
+func (a *APIServer) speculativeChunks(jobID string) col.Collection {
+	return col.NewCollection(a.etcdClient, path.Join(a.etcdPrefix, speculativeChunkPrefix, jobID), nil, &ChunkState{}, nil, nil)
+}
+
+func (a *APIServer) chunkJournals(jobID string) col.Collection {
+	return col.NewCollection(a.etcdClient, path.Join(a.etcdPrefix, chunkJournalPrefix, jobID), nil, &ChunkState{}, nil, nil)
+}
+
 func (a *APIServer) merges(jobID string) col.Collection {
 	return col.NewCollection(a.etcdClient, path.Join(a.etcdPrefix, mergePrefix, jobID), nil, &MergeState{}, nil, nil)
 }
 
-func newPlan(df DatumIterator, spec *pps.ChunkSpec, parallelism int, numHashtrees int64) *Plan {
+// newPlan splits the datums in 'df' into chunks. If spec.TargetDuration is set
+// and 'datumCost' (which estimates how long the i'th datum takes to process)
+// is non-nil, each chunk is sized to take about spec.TargetDuration.
+func newPlan(df DatumIterator, spec *pps.ChunkSpec, parallelism int, numHashtrees int64, datumCost func(i int) time.Duration) *Plan {
 	if spec == nil {
 		spec = &pps.ChunkSpec{}
 	}
+	if target, err := types.DurationFromProto(spec.TargetDuration); err == nil && target > 0 && datumCost != nil {
+		plan := &Plan{}
+		var cost time.Duration
+		for i := 0; i < df.Len()-1; i++ {
+			if cost += datumCost(i); cost >= target {
+				plan.Chunks = append(plan.Chunks, int64(i+1))
+				cost = 0
+			}
+		}
+		plan.Chunks = append(plan.Chunks, int64(df.Len()))
+		plan.Merges = numHashtrees
+		return plan
+	}
 	if spec.Number == 0 && spec.SizeBytes == 0 {
 		spec.Number = int64(df.Len() / (parallelism * 10))
 		if spec.Number == 0 {
@@ -432,6 +608,121 @@ func newPlan(df DatumIterator, spec *pps.ChunkSpec, parallelism int, numHashtree
 	return plan
 }
 
+// datumCostModel estimates how long a datum takes to process, based on the
+// datum stats of a pipeline's previous job
+type datumCostModel struct {
+	// processing time (in nanoseconds) = fixed + perByte * input size
+	fixed, perByte float64
+	// the datums that the previous job's output contains, which will most
+	// likely be skipped
+	seen map[string]bool
+}
+
+// newDatumCostModel fits a datumCostModel to the stats of previously
+// processed datums, by least squares. It returns nil if 'stats' is empty.
+func newDatumCostModel(stats []*pps.ProcessStats, seen map[string]bool) *datumCostModel {
+	if len(stats) == 0 {
+		return nil
+	}
+	sizes := make([]float64, len(stats))
+	times := make([]float64, len(stats))
+	var meanSize, meanTime float64
+	for i, s := range stats {
+		sizes[i] = float64(s.DownloadBytes)
+		for _, d := range []*types.Duration{s.DownloadTime, s.ProcessTime, s.UploadTime} {
+			if duration, err := types.DurationFromProto(d); err == nil {
+				times[i] += float64(duration)
+			}
+		}
+		meanSize += sizes[i] / float64(len(stats))
+		meanTime += times[i] / float64(len(stats))
+	}
+	var covariance, variance float64
+	for i := range stats {
+		covariance += (sizes[i] - meanSize) * (times[i] - meanTime)
+		variance += (sizes[i] - meanSize) * (sizes[i] - meanSize)
+	}
+	m := &datumCostModel{fixed: meanTime, seen: seen}
+	if variance > 0 && covariance > 0 {
+		m.perByte = covariance / variance
+		m.fixed = meanTime - m.perByte*meanSize
+		if m.fixed < 0 {
+			// keep the estimates positive, scaling with size alone
+			m.fixed, m.perByte = 0, meanTime/meanSize
+		}
+	}
+	return m
+}
+
+// cost returns the estimated processing time of the datum with ID 'datumID'
+// and inputs of 'size' bytes
+func (m *datumCostModel) cost(datumID string, size int64) time.Duration {
+	if m.seen[datumID] {
+		return 0
+	}
+	return time.Duration(m.fixed + m.perByte*float64(size))
+}
+
+// datumCost returns a function estimating how long each datum in 'df' takes to
+// process, for chunk_spec.target_duration, or nil if it can't be estimated
+// (see newPlan)
+func (a *APIServer) datumCost(pachClient *client.APIClient, jobInfo *pps.JobInfo, df DatumIterator, logger *taggedLogger) func(int) time.Duration {
+	if jobInfo.ChunkSpec == nil || jobInfo.ChunkSpec.TargetDuration == nil || jobInfo.StatsCommit == nil {
+		return nil
+	}
+	model, err := a.readDatumCostModel(pachClient, jobInfo.StatsCommit)
+	if err != nil {
+		logger.Logf("could not read previous datum stats, falling back to static chunking: %v", err)
+		return nil
+	}
+	if model == nil {
+		return nil
+	}
+	return func(i int) time.Duration {
+		data := df.DatumN(i)
+		return model.cost(a.DatumID(data), datumSize(data))
+	}
+}
+
+// readDatumCostModel fits a datumCostModel to the datum stats in the parent of
+// 'statsCommit' (i.e. the stats of the pipeline's previous job). It returns nil
+// if there's no such commit, or it has no datum stats.
+func (a *APIServer) readDatumCostModel(pachClient *client.APIClient, statsCommit *pfs.Commit) (*datumCostModel, error) {
+	commitInfo, err := pachClient.InspectCommit(statsCommit.Repo.Name, statsCommit.ID)
+	if err != nil {
+		return nil, err
+	}
+	parent := commitInfo.ParentCommit
+	if parent == nil {
+		return nil, nil
+	}
+	// Each datum's stats are in /<datum ID>/stats
+	seen := make(map[string]bool)
+	if err := pachClient.GlobFileSizeOnlyF(parent.Repo.Name, parent.ID, "/*/stats", func(fi *pfs.FileInfo) error {
+		seen[path.Base(path.Dir(fi.File.Path))] = true
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if len(seen) == 0 {
+		return nil, nil
+	}
+	var buf bytes.Buffer
+	if err := pachClient.GetFile(parent.Repo.Name, parent.ID, "/*/stats", 0, 0, &buf); err != nil {
+		return nil, err
+	}
+	var stats []*pps.ProcessStats
+	decoder := json.NewDecoder(&buf)
+	for decoder.More() {
+		s := &pps.ProcessStats{}
+		if err := jsonpb.UnmarshalNext(decoder, s); err != nil {
+			return nil, fmt.Errorf("could not parse datum stats: %v", err)
+		}
+		stats = append(stats, s)
+	}
+	return newDatumCostModel(stats, seen), nil
+}
+
 func (a *APIServer) failedInputs(ctx context.Context, jobInfo *pps.JobInfo) ([]string, error) {
 	var failedInputs []string
 	var vistErr error
@@ -457,6 +748,9 @@ func (a *APIServer) failedInputs(ctx context.Context, jobInfo *pps.JobInfo) ([]s
 		if input.Cron != nil && input.Cron.Commit != "" {
 			blockCommit(input.Cron.Name, client.NewCommit(input.Cron.Repo, input.Cron.Commit))
 		}
+		if input.SQL != nil && input.SQL.Commit != "" {
+			blockCommit(input.SQL.Name, client.NewCommit(input.SQL.Repo, input.SQL.Commit))
+		}
 		if input.Git != nil && input.Git.Commit != "" {
 			blockCommit(input.Git.Name, client.NewCommit(input.Git.Name, input.Git.Commit))
 		}
@@ -469,6 +763,11 @@ func (a *APIServer) failedInputs(ctx context.Context, jobInfo *pps.JobInfo) ([]s
 // stats into a commit in the stats branch as well)
 func (a *APIServer) waitJob(pachClient *client.APIClient, jobInfo *pps.JobInfo, logger *taggedLogger) (retErr error) {
 	logger.Logf("waiting on job %q (pipeline version: %d, state: %s)", jobInfo.Job.ID, jobInfo.PipelineVersion, jobInfo.State)
+	if a.pipelineInfo.JobScratch {
+		// the job's scratch directory is deleted when the job finishes, however
+		// it finishes, so this uses the un-cancelled client
+		defer a.deleteJobScratch(pachClient, jobInfo, logger)
+	}
 	ctx, cancel := context.WithCancel(pachClient.Ctx())
 	pachClient = pachClient.WithCtx(ctx)
 
@@ -482,7 +781,7 @@ func (a *APIServer) waitJob(pachClient *client.APIClient, jobInfo *pps.JobInfo,
 					BlockState: pfs.CommitState_FINISHED,
 				})
 			if err != nil {
-				if pfsserver.IsCommitNotFoundErr(err) || pfsserver.IsCommitDeletedErr(err) {
+				if pfs.IsCommitNotFoundErr(err) || pfs.IsCommitDeletedErr(err) {
 					defer cancel() // whether we return error or nil, job is done
 					// Output commit was deleted. Delete job as well
 					if _, err := col.NewSTM(ctx, a.etcdClient, func(stm col.STM) error {
@@ -513,12 +812,12 @@ func (a *APIServer) waitJob(pachClient *client.APIClient, jobInfo *pps.JobInfo,
 						if _, err = pachClient.PfsAPIClient.FinishCommit(ctx, &pfs.FinishCommitRequest{
 							Commit: jobInfo.StatsCommit,
 							Empty:  true,
-						}); err != nil && !pfsserver.IsCommitFinishedErr(err) {
+						}); err != nil && !pfs.IsCommitFinishedErr(err) {
 							logger.Logf("error from FinishCommit for stats while cleaning up job: %+v", err)
 						}
 					}
 					if !ppsutil.IsTerminal(jobPtr.State) {
-						return ppsutil.UpdateJobState(a.pipelines.ReadWrite(stm), a.jobs.ReadWrite(stm), jobPtr, pps.JobState_JOB_KILLED, "")
+						return ppsutil.UpdateJobState(a.pipelines.ReadWrite(stm), a.jobs.ReadWrite(stm), jobPtr, pps.JobState_JOB_KILLED, "", pps.FailureType_FAILURE_UNKNOWN)
 					}
 					return nil
 				}); err != nil {
@@ -569,25 +868,7 @@ func (a *APIServer) waitJob(pachClient *client.APIClient, jobInfo *pps.JobInfo,
 			return err
 		}
 		if len(failedInputs) > 0 {
-			reason := fmt.Sprintf("inputs %s failed", strings.Join(failedInputs, ", "))
-			if jobInfo.EnableStats {
-				if _, err = pachClient.PfsAPIClient.FinishCommit(ctx, &pfs.FinishCommitRequest{
-					Commit: jobInfo.StatsCommit,
-					Empty:  true,
-				}); err != nil && !pfsserver.IsCommitFinishedErr(err) {
-					return err
-				}
-			}
-			if err := a.updateJobState(ctx, jobInfo, pps.JobState_JOB_FAILURE, reason); err != nil {
-				return err
-			}
-			if _, err := pachClient.PfsAPIClient.FinishCommit(ctx, &pfs.FinishCommitRequest{
-				Commit: jobInfo.OutputCommit,
-				Empty:  true,
-			}); err != nil && !pfsserver.IsCommitFinishedErr(err) {
-				return err
-			}
-			return nil
+			return a.failJob(pachClient, jobInfo, fmt.Sprintf("inputs %s failed", strings.Join(failedInputs, ", ")))
 		}
 		// Create a datum factory pointing at the job's inputs and split up the
 		// input data into chunks
@@ -595,14 +876,26 @@ func (a *APIServer) waitJob(pachClient *client.APIClient, jobInfo *pps.JobInfo,
 		if err != nil {
 			return err
 		}
-		parallelism, err := ppsutil.GetExpectedNumWorkers(a.kubeClient, a.pipelineInfo.ParallelismSpec)
+		df = orderDatums(df, jobInfo.ChunkSpec, jobInfo.Job.ID, a.DatumID)
+		var emptyReason *pps.EmptyJobReason
+		if df.Len() == 0 {
+			if emptyReason, err = emptyJobReason(pachClient, jobInfo.Input); err != nil {
+				return fmt.Errorf("error explaining why the job has no datums: %v", err)
+			}
+		}
+		parallelism, err := a.plannedNumWorkers()
 		if err != nil {
-			return fmt.Errorf("error from GetExpectedNumWorkers: %v", err)
+			return fmt.Errorf("error from GetPlannedNumWorkers: %v", err)
 		}
 		numHashtrees, err := ppsutil.GetExpectedNumHashtrees(a.pipelineInfo.HashtreeSpec)
 		if err != nil {
 			return fmt.Errorf("error from GetExpectedNumHashtrees: %v", err)
 		}
+		if a.pipelineInfo.JobScratch {
+			if err := a.createJobScratch(pachClient, jobInfo); err != nil {
+				return fmt.Errorf("error creating the job's scratch directory: %v", err)
+			}
+		}
 		plan := &Plan{}
 		// Read the job document, and either resume (if we're recovering from a
 		// crash) or mark it running. Also write the input chunks calculated above
@@ -618,14 +911,16 @@ func (a *APIServer) waitJob(pachClient *client.APIClient, jobInfo *pps.JobInfo,
 				return nil
 			}
 			jobPtr.DataTotal = int64(df.Len())
-			if err := ppsutil.UpdateJobState(a.pipelines.ReadWrite(stm), a.jobs.ReadWrite(stm), jobPtr, pps.JobState_JOB_RUNNING, ""); err != nil {
+			jobPtr.EmptyReason = emptyReason
+			if err := ppsutil.UpdateJobState(a.pipelines.ReadWrite(stm), a.jobs.ReadWrite(stm), jobPtr, pps.JobState_JOB_RUNNING, "", pps.FailureType_FAILURE_UNKNOWN); err != nil {
 				return err
 			}
 			plansCol := a.plans.ReadWrite(stm)
 			if err := plansCol.Get(jobID, plan); err == nil {
 				return nil
 			}
-			plan = newPlan(df, jobInfo.ChunkSpec, parallelism, numHashtrees)
+			plan = getChunkStrategy(jobInfo.ChunkSpec).plan(df, jobInfo.ChunkSpec, parallelism, numHashtrees,
+				a.datumCost(pachClient, jobInfo, df, logger), func(i int) string { return a.DatumID(df.DatumN(i)) })
 			return plansCol.Put(jobID, plan)
 		}); err != nil {
 			return err
@@ -635,6 +930,8 @@ func (a *APIServer) waitJob(pachClient *client.APIClient, jobInfo *pps.JobInfo,
 				if _, err := col.NewSTM(ctx, a.etcdClient, func(stm col.STM) error {
 					chunksCol := a.chunks(jobID).ReadWrite(stm)
 					chunksCol.DeleteAll()
+					a.speculativeChunks(jobID).ReadWrite(stm).DeleteAll()
+					a.chunkJournals(jobID).ReadWrite(stm).DeleteAll()
 					plansCol := a.plans.ReadWrite(stm)
 					return plansCol.Delete(jobID)
 				}); err != nil {
@@ -642,9 +939,23 @@ func (a *APIServer) waitJob(pachClient *client.APIClient, jobInfo *pps.JobInfo,
 				}
 			}
 		}()
+		if emptyReason != nil {
+			switch a.pipelineInfo.EmptyJobPolicy {
+			case pps.EmptyJobPolicy_WARN_EMPTY:
+				logger.Logf("warning: the job has no datums: %s", emptyReason.Message)
+			case pps.EmptyJobPolicy_FAIL_EMPTY:
+				return a.failJob(pachClient, jobInfo, "the job has no datums: "+emptyReason.Message)
+			}
+		}
+		if a.pipelineInfo.MaxFailedDatumsPercent > 0 {
+			abortCtx, cancelAbort := context.WithCancel(ctx)
+			defer cancelAbort()
+			go a.abortOnFailedDatums(pachClient.WithCtx(abortCtx), jobInfo, logger)
+		}
 		// Watch the chunks in order
 		chunks := a.chunks(jobInfo.Job.ID).ReadOnly(ctx)
 		var failedDatumID string
+		var failureType pps.FailureType
 		recoveredDatums := make(map[string]bool)
 		for _, high := range plan.Chunks {
 			chunkState := &ChunkState{}
@@ -659,6 +970,7 @@ func (a *APIServer) waitJob(pachClient *client.APIClient, jobInfo *pps.JobInfo,
 				if chunkState.State != State_RUNNING {
 					if chunkState.State == State_FAILED {
 						failedDatumID = chunkState.DatumID
+						failureType = chunkState.FailureType
 					} else if chunkState.State == State_COMPLETE {
 						// if the chunk has been completed, grab the recovered datums from the chunk
 						chunkRecoveredDatums, err := a.getDatumMap(ctx, pachClient, chunkState.RecoveredDatums)
@@ -676,7 +988,7 @@ func (a *APIServer) waitJob(pachClient *client.APIClient, jobInfo *pps.JobInfo,
 				return err
 			}
 		}
-		if err := a.updateJobState(ctx, jobInfo, pps.JobState_JOB_MERGING, ""); err != nil {
+		if err := a.updateJobState(ctx, jobInfo, pps.JobState_JOB_MERGING, "", pps.FailureType_FAILURE_UNKNOWN); err != nil {
 			return err
 		}
 		var trees []*pfs.Object
@@ -710,11 +1022,20 @@ func (a *APIServer) waitJob(pachClient *client.APIClient, jobInfo *pps.JobInfo,
 			}
 		}
 		if jobInfo.EnableStats {
+			// Jobs that produced output attest to how they produced it
+			if failedDatumID == "" {
+				var attestationSize uint64
+				statsTrees, attestationSize, err = a.writeAttestation(pachClient, jobInfo, trees, statsTrees, logger)
+				if err != nil {
+					return fmt.Errorf("error writing the job's attestation: %v", err)
+				}
+				statsSize += attestationSize
+			}
 			if _, err = pachClient.PfsAPIClient.FinishCommit(ctx, &pfs.FinishCommitRequest{
 				Commit:    jobInfo.StatsCommit,
 				Trees:     statsTrees,
 				SizeBytes: statsSize,
-			}); err != nil && !pfsserver.IsCommitFinishedErr(err) {
+			}); err != nil && !pfs.IsCommitFinishedErr(err) {
 				return err
 			}
 		}
@@ -723,13 +1044,13 @@ func (a *APIServer) waitJob(pachClient *client.APIClient, jobInfo *pps.JobInfo,
 		// killed.
 		if failedDatumID != "" {
 			reason := fmt.Sprintf("failed to process datum: %v", failedDatumID)
-			if err := a.updateJobState(ctx, jobInfo, pps.JobState_JOB_FAILURE, reason); err != nil {
+			if err := a.updateJobState(ctx, jobInfo, pps.JobState_JOB_FAILURE, reason, failureType); err != nil {
 				return err
 			}
 			if _, err = pachClient.PfsAPIClient.FinishCommit(ctx, &pfs.FinishCommitRequest{
 				Commit: jobInfo.OutputCommit,
 				Empty:  true,
-			}); err != nil && !pfsserver.IsCommitFinishedErr(err) {
+			}); err != nil && !pfs.IsCommitFinishedErr(err) {
 				return err
 			}
 			return nil
@@ -759,8 +1080,8 @@ func (a *APIServer) waitJob(pachClient *client.APIClient, jobInfo *pps.JobInfo,
 			SizeBytes: size,
 			Datums:    datums,
 		})
-		if err != nil && !pfsserver.IsCommitFinishedErr(err) {
-			if pfsserver.IsCommitNotFoundErr(err) || pfsserver.IsCommitDeletedErr(err) {
+		if err != nil && !pfs.IsCommitFinishedErr(err) {
+			if pfs.IsCommitNotFoundErr(err) || pfs.IsCommitDeletedErr(err) {
 				// output commit was deleted during e.g. FinishCommit, which means this job
 				// should be deleted. Goro from top of waitJob() will observe the deletion,
 				// delete the jobPtr and call cancel()--wait for that.
@@ -772,9 +1093,9 @@ func (a *APIServer) waitJob(pachClient *client.APIClient, jobInfo *pps.JobInfo,
 		// Handle egress
 		if err := a.egress(pachClient, logger, jobInfo); err != nil {
 			reason := fmt.Sprintf("egress error: %v", err)
-			return a.updateJobState(ctx, jobInfo, pps.JobState_JOB_FAILURE, reason)
+			return a.updateJobState(ctx, jobInfo, pps.JobState_JOB_FAILURE, reason, pps.FailureType_FAILURE_UNKNOWN)
 		}
-		return a.updateJobState(ctx, jobInfo, pps.JobState_JOB_SUCCESS, "")
+		return a.updateJobState(ctx, jobInfo, pps.JobState_JOB_SUCCESS, "", pps.FailureType_FAILURE_UNKNOWN)
 	}, backoff.NewInfiniteBackOff(), func(err error, d time.Duration) error {
 		logger.Logf("error in waitJob %v, retrying in %v", err, d)
 		select {
@@ -801,7 +1122,31 @@ func (a *APIServer) waitJob(pachClient *client.APIClient, jobInfo *pps.JobInfo,
 	return nil
 }
 
-func (a *APIServer) updateJobState(ctx context.Context, info *pps.JobInfo, state pps.JobState, reason string) error {
+// failJob fails a job before any of its datums are processed, and finishes
+// its output and stats commits as empty
+func (a *APIServer) failJob(pachClient *client.APIClient, jobInfo *pps.JobInfo, reason string) error {
+	ctx := pachClient.Ctx()
+	if jobInfo.EnableStats {
+		if _, err := pachClient.PfsAPIClient.FinishCommit(ctx, &pfs.FinishCommitRequest{
+			Commit: jobInfo.StatsCommit,
+			Empty:  true,
+		}); err != nil && !pfs.IsCommitFinishedErr(err) {
+			return err
+		}
+	}
+	if err := a.updateJobState(ctx, jobInfo, pps.JobState_JOB_FAILURE, reason, pps.FailureType_FAILURE_UNKNOWN); err != nil {
+		return err
+	}
+	if _, err := pachClient.PfsAPIClient.FinishCommit(ctx, &pfs.FinishCommitRequest{
+		Commit: jobInfo.OutputCommit,
+		Empty:  true,
+	}); err != nil && !pfs.IsCommitFinishedErr(err) {
+		return err
+	}
+	return nil
+}
+
+func (a *APIServer) updateJobState(ctx context.Context, info *pps.JobInfo, state pps.JobState, reason string, failureType pps.FailureType) error {
 	_, err := col.NewSTM(ctx, a.etcdClient, func(stm col.STM) error {
 		jobs := a.jobs.ReadWrite(stm)
 		jobID := info.Job.ID
@@ -809,7 +1154,7 @@ func (a *APIServer) updateJobState(ctx context.Context, info *pps.JobInfo, state
 		if err := jobs.Get(jobID, jobPtr); err != nil {
 			return err
 		}
-		return ppsutil.UpdateJobState(a.pipelines.ReadWrite(stm), a.jobs.ReadWrite(stm), jobPtr, state, reason)
+		return ppsutil.UpdateJobState(a.pipelines.ReadWrite(stm), a.jobs.ReadWrite(stm), jobPtr, state, reason, failureType)
 	})
 	return err
 }
@@ -834,121 +1179,80 @@ func (a *APIServer) deleteJob(stm col.STM, jobPtr *pps.EtcdJobInfo) error {
 }
 
 func (a *APIServer) egress(pachClient *client.APIClient, logger *taggedLogger, jobInfo *pps.JobInfo) error {
+	if jobInfo.Egress == nil {
+		return nil
+	}
 	// copy the pach client (preserving auth info) so we can set a different
 	// number of concurrent streams
 	pachClient = pachClient.WithCtx(pachClient.Ctx())
 	pachClient.SetMaxConcurrentStreams(100)
-	var egressFailureCount int
-	return backoff.RetryNotify(func() (retErr error) {
-		if jobInfo.Egress != nil {
-			logger.Logf("Starting egress upload for job (%v)", jobInfo)
+	status := &pps.EgressStatus{
+		State:   pps.EgressState_EGRESS_RUNNING,
+		Started: types.TimestampNow(),
+	}
+	driver, err := newEgressDriver(jobInfo.Egress)
+	if err == nil {
+		a.updateEgressStatus(pachClient.Ctx(), logger, jobInfo, status)
+		var egressFailureCount int
+		err = backoff.RetryNotify(func() error {
+			status.Attempts++
+			logger.Logf("Starting egress for job (%v), attempt %d", jobInfo.Job.ID, status.Attempts)
 			start := time.Now()
-			url, err := obj.ParseURL(jobInfo.Egress.URL)
-			if err != nil {
+			if err := driver.egress(pachClient, jobInfo.OutputCommit); err != nil {
 				return err
 			}
-			objClient, err := obj.NewClientFromURLAndSecret(url, false)
-			if err != nil {
-				return err
-			}
-			if err := pfs_sync.PushObj(pachClient, jobInfo.OutputCommit, objClient, url.Object); err != nil {
+			logger.Logf("Completed egress for job (%v), duration (%v)", jobInfo.Job.ID, time.Since(start))
+			return nil
+		}, backoff.NewInfiniteBackOff(), func(err error, d time.Duration) error {
+			status.Reason = err.Error()
+			egressFailureCount++
+			if egressFailureCount > 3 {
 				return err
 			}
-			logger.Logf("Completed egress upload for job (%v), duration (%v)", jobInfo, time.Since(start))
-		}
-		return nil
-	}, backoff.NewInfiniteBackOff(), func(err error, d time.Duration) error {
-		egressFailureCount++
-		if egressFailureCount > 3 {
-			return err
-		}
-		logger.Logf("egress failed: %v; retrying in %v", err, d)
-		return nil
-	})
+			a.updateEgressStatus(pachClient.Ctx(), logger, jobInfo, status)
+			logger.Logf("egress failed: %v; retrying in %v", err, d)
+			return nil
+		})
+	}
+	status.State = pps.EgressState_EGRESS_SUCCESS
+	if err != nil {
+		status.State = pps.EgressState_EGRESS_FAILURE
+		status.Reason = err.Error()
+	}
+	status.Finished = types.TimestampNow()
+	a.updateEgressStatus(pachClient.Ctx(), logger, jobInfo, status)
+	return err
 }
 
-func (a *APIServer) receiveSpout(ctx context.Context, logger *taggedLogger) error {
-	return backoff.RetryNotify(func() error {
-		repo := a.pipelineInfo.Pipeline.Name
-		for {
-			// this extra closure is so that we can scope the defer
-			if err := func() (retErr error) {
-				// open a read connection to the /pfs/out named pipe
-				out, err := os.Open("/pfs/out")
-				if err != nil {
-					return err
-				}
-				// and close it at the end of each loop
-				defer func() {
-					if err := out.Close(); err != nil && retErr == nil {
-						// this lets us pass the error through if Close fails
-						retErr = err
-					}
-				}()
-				outTar := tar.NewReader(out)
-
-				// start commit
-				commit, err := a.pachClient.PfsAPIClient.StartCommit(a.pachClient.Ctx(), &pfs.StartCommitRequest{
-					Parent:     client.NewCommit(repo, ""),
-					Branch:     a.pipelineInfo.OutputBranch,
-					Provenance: []*pfs.CommitProvenance{client.NewCommitProvenance(ppsconsts.SpecRepo, repo, a.pipelineInfo.SpecCommit.ID)},
-				})
-				if err != nil {
-					return err
-				}
-
-				// finish the commit even if there was an issue
-				defer func() {
-					if err := a.pachClient.FinishCommit(repo, commit.ID); err != nil && retErr == nil {
-						// this lets us pass the error through if FinishCommit fails
-						retErr = err
-					}
-				}()
-				// this loops through all the files in the tar that we've read from /pfs/out
-				for {
-					fileHeader, err := outTar.Next()
-					if err == io.EOF {
-						break
-					}
-					if err != nil {
-						return err
-					}
-					// put files into pachyderm
-					if a.pipelineInfo.Spout.Overwrite || strings.Contains(fileHeader.Name, "marker") {
-						_, err = a.pachClient.PutFileOverwrite(repo, commit.ID, fileHeader.Name, outTar, 0)
-						if err != nil {
-							return err
-						}
-					} else {
-						_, err = a.pachClient.PutFile(repo, commit.ID, fileHeader.Name, outTar)
-						if err != nil {
-							return err
-						}
-					}
-				}
-				return nil
-			}(); err != nil {
-				return err
-			}
-		}
-	}, backoff.NewInfiniteBackOff(), func(err error, d time.Duration) error {
-		select {
-		case <-ctx.Done():
-			return err
-		default:
-			logger.Logf("error running spout: %+v, retrying in: %+v", err, d)
+// updateEgressStatus records the progress of a job's egress in its etcd
+// record. Failing to record it doesn't fail the egress.
+func (a *APIServer) updateEgressStatus(ctx context.Context, logger *taggedLogger, jobInfo *pps.JobInfo, status *pps.EgressStatus) {
+	if _, err := col.NewSTM(ctx, a.etcdClient, func(stm col.STM) error {
+		jobPtr := &pps.EtcdJobInfo{}
+		return a.jobs.ReadWrite(stm).Update(jobInfo.Job.ID, jobPtr, func() error {
+			jobPtr.EgressStatus = proto.Clone(status).(*pps.EgressStatus)
 			return nil
-		}
-	})
+		})
+	}); err != nil {
+		logger.Logf("error updating the egress status of job %s: %v", jobInfo.Job.ID, err)
+	}
 }
 
 func (a *APIServer) runService(ctx context.Context, logger *taggedLogger) error {
-	return backoff.RetryNotify(func() error {
-		// if we have a spout, then asynchronously receive spout data
-		if a.pipelineInfo.Spout != nil {
-			go a.receiveSpout(ctx, logger)
+	// Kafka spouts consume their topic themselves, instead of running user code
+	if a.pipelineInfo.Spout != nil && a.pipelineInfo.Spout.Kafka != nil {
+		return a.runKafkaSpout(ctx, logger)
+	}
+	// if we have a spout, then asynchronously receive spout data
+	if a.pipelineInfo.Spout != nil {
+		spout, err := newSpoutManager(&pfsSpoutCommitter{a.pachClient, a.pipelineInfo, a.specCommit}, a.pipelineInfo.Spout)
+		if err != nil {
+			return err
 		}
-		return a.runUserCode(ctx, logger, nil, &pps.ProcessStats{}, nil)
+		go spout.run(ctx, "/pfs/out", logger)
+	}
+	return backoff.RetryNotify(func() error {
+		return a.runUserCode(ctx, logger, a.baseEnv(), &pps.ProcessStats{}, nil)
 	}, backoff.NewInfiniteBackOff(), func(err error, d time.Duration) error {
 		select {
 		case <-ctx.Done():
@@ -0,0 +1,64 @@
+package worker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestNewDatumCostModel(t *testing.T) {
+	stats := func(size uint64, d time.Duration) *pps.ProcessStats {
+		return &pps.ProcessStats{
+			DownloadBytes: size,
+			DownloadTime:  types.DurationProto(d / 4),
+			ProcessTime:   types.DurationProto(d / 2),
+			UploadTime:    types.DurationProto(d / 4),
+		}
+	}
+	require.Nil(t, newDatumCostModel(nil, nil))
+
+	// 1s + 1ms per byte
+	m := newDatumCostModel([]*pps.ProcessStats{
+		stats(0, time.Second),
+		stats(1000, 2*time.Second),
+		stats(2000, 3*time.Second),
+	}, map[string]bool{"seen": true})
+	require.Equal(t, time.Second, m.cost("new", 0))
+	require.Equal(t, 11*time.Second, m.cost("new", 10000))
+	// Datums that were processed before will be skipped
+	require.Equal(t, time.Duration(0), m.cost("seen", 10000))
+
+	// If time doesn't grow with size, every datum costs the mean
+	m = newDatumCostModel([]*pps.ProcessStats{
+		stats(1000, 3*time.Second),
+		stats(2000, time.Second),
+	}, nil)
+	require.Equal(t, 2*time.Second, m.cost("new", 0))
+	require.Equal(t, 2*time.Second, m.cost("new", 5000))
+}
+
+func TestNewPlanTargetDuration(t *testing.T) {
+	df := &pfsDatumIterator{}
+	for i := 0; i < 10; i++ {
+		df.inputs = append(df.inputs, &Input{FileInfo: &pfs.FileInfo{}})
+	}
+	// The first five datums are slow, the rest are fast
+	cost := func(i int) time.Duration {
+		if i < 5 {
+			return time.Minute
+		}
+		return time.Second
+	}
+	spec := &pps.ChunkSpec{TargetDuration: types.DurationProto(2 * time.Minute)}
+	plan := newPlan(df, spec, 1, 1, cost)
+	require.Equal(t, []int64{2, 4, 10}, plan.Chunks)
+	require.Equal(t, int64(1), plan.Merges)
+
+	// Without estimates, datums are chunked as usual
+	plan = newPlan(df, &pps.ChunkSpec{TargetDuration: types.DurationProto(time.Minute), Number: 3}, 1, 1, nil)
+	require.Equal(t, []int64{3, 6, 9, 10}, plan.Chunks)
+}
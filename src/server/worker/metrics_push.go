@@ -0,0 +1,238 @@
+package worker
+
+import (
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+const (
+	// workerMetricsPrefix is the prefix of the metrics in stats.go, which are
+	// the ones that are pushed (rather than every metric the worker exports)
+	workerMetricsPrefix = "pachyderm_worker_"
+	// pushgatewayJob is the job label of the metrics that workers push to a
+	// Pushgateway
+	pushgatewayJob = "pachyderm_worker"
+	// maxStatsdPacket is the largest UDP packet sent to StatsD, which keeps
+	// packets from being fragmented on most networks
+	maxStatsdPacket = 1432
+)
+
+// metricsPusher pushes the worker's metrics to a monitoring system
+type metricsPusher interface {
+	push() error
+}
+
+func newMetricsPushers(spec *pps.MetricsPush, workerName string, gatherer prometheus.Gatherer) []metricsPusher {
+	var pushers []metricsPusher
+	if spec.PushgatewayURL != "" {
+		pusher := push.New(spec.PushgatewayURL, pushgatewayJob).
+			Gatherer(pushgatewayMetrics(gatherer)).
+			Grouping("worker", workerName)
+		for name, value := range spec.Labels {
+			pusher = pusher.Grouping(name, value)
+		}
+		pushers = append(pushers, &pushgatewayPusher{pusher})
+	}
+	if spec.StatsdAddress != "" {
+		labels := map[string]string{"worker": workerName}
+		for name, value := range spec.Labels {
+			labels[name] = value
+		}
+		pushers = append(pushers, &statsdPusher{
+			address:  spec.StatsdAddress,
+			gatherer: workerMetrics(gatherer),
+			labels:   labels,
+			last:     make(map[string]float64),
+		})
+	}
+	return pushers
+}
+
+// pushMetrics pushes the worker's metrics every interval of the pipeline's
+// metrics_push. Failed pushes are logged, and the metrics are pushed again
+// at the next interval.
+func (a *APIServer) pushMetrics(logger *taggedLogger) {
+	spec := a.pipelineInfo.MetricsPush
+	pushers := newMetricsPushers(spec, a.workerName, prometheus.DefaultGatherer)
+	interval, err := types.DurationFromProto(spec.Interval)
+	if err != nil {
+		logger.Logf("error parsing metrics push interval: %v", err)
+		return
+	}
+	for range time.Tick(interval) {
+		for _, pusher := range pushers {
+			if err := pusher.push(); err != nil {
+				logger.Logf("error pushing metrics: %v", err)
+			}
+		}
+	}
+}
+
+// workerMetrics returns the metrics in 'gatherer' that are in stats.go
+func workerMetrics(gatherer prometheus.Gatherer) prometheus.Gatherer {
+	return prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) {
+		families, err := gatherer.Gather()
+		var result []*dto.MetricFamily
+		for _, family := range families {
+			if strings.HasPrefix(family.GetName(), workerMetricsPrefix) {
+				result = append(result, family)
+			}
+		}
+		return result, err
+	})
+}
+
+// pushgatewayMetrics returns the metrics in 'gatherer' that are in stats.go,
+// with their "job" label (the Pachyderm job) renamed to "exported_job", as
+// Prometheus does when it scrapes a conflicting label. The Pushgateway's
+// "job" label is the group that the metrics are pushed to.
+func pushgatewayMetrics(gatherer prometheus.Gatherer) prometheus.Gatherer {
+	workerGatherer := workerMetrics(gatherer)
+	return prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) {
+		families, err := workerGatherer.Gather()
+		for _, family := range families {
+			for _, metric := range family.Metric {
+				for _, label := range metric.Label {
+					if label.GetName() == "job" {
+						label.Name = proto.String("exported_job")
+					}
+				}
+			}
+		}
+		return families, err
+	})
+}
+
+// pushgatewayPusher pushes the worker's metrics to a Prometheus Pushgateway
+type pushgatewayPusher struct {
+	pusher *push.Pusher
+}
+
+func (p *pushgatewayPusher) push() error {
+	// Push replaces the worker's group, so metrics of jobs that the worker
+	// no longer exports are removed along with it
+	return p.pusher.Push()
+}
+
+// statsdPusher sends the worker's metrics to a StatsD server. Prometheus
+// counters are cumulative, while StatsD counters are increments, so it sends
+// the change in each counter since the last push.
+type statsdPusher struct {
+	address  string
+	conn     net.Conn
+	gatherer prometheus.Gatherer
+	labels   map[string]string
+	last     map[string]float64
+}
+
+func (p *statsdPusher) push() error {
+	families, err := p.gatherer.Gather()
+	if err != nil {
+		return err
+	}
+	if p.conn == nil {
+		// the connection is made lazily, so that the address is resolved
+		// again if it couldn't be
+		if p.conn, err = net.Dial("udp", p.address); err != nil {
+			return err
+		}
+	}
+	var packet []byte
+	for _, line := range statsdLines(families, p.labels, p.last) {
+		if len(packet) > 0 && len(packet)+1+len(line) > maxStatsdPacket {
+			if _, err := p.conn.Write(packet); err != nil {
+				return err
+			}
+			packet = packet[:0]
+		}
+		if len(packet) > 0 {
+			packet = append(packet, '\n')
+		}
+		packet = append(packet, line...)
+	}
+	if len(packet) > 0 {
+		if _, err := p.conn.Write(packet); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// statsdLines returns the StatsD lines of 'families', tagged with their
+// labels and 'labels'. 'last' holds each counter's value at the last push,
+// and is updated.
+func statsdLines(families []*dto.MetricFamily, labels map[string]string, last map[string]float64) []string {
+	var lines []string
+	counter := func(name, tags string, value float64) {
+		key := name + "|" + tags
+		delta := value - last[key]
+		if delta < 0 {
+			// the counter was reset
+			delta = value
+		}
+		last[key] = value
+		if delta != 0 {
+			lines = append(lines, name+":"+formatStatsdValue(delta)+"|c"+tags)
+		}
+	}
+	for _, family := range families {
+		name := family.GetName()
+		for _, metric := range family.Metric {
+			tags := statsdTags(metric.Label, labels)
+			switch family.GetType() {
+			case dto.MetricType_COUNTER:
+				counter(name, tags, metric.GetCounter().GetValue())
+			case dto.MetricType_GAUGE:
+				lines = append(lines, name+":"+formatStatsdValue(metric.GetGauge().GetValue())+"|g"+tags)
+			case dto.MetricType_HISTOGRAM:
+				counter(name+"_sum", tags, metric.GetHistogram().GetSampleSum())
+				counter(name+"_count", tags, float64(metric.GetHistogram().GetSampleCount()))
+			case dto.MetricType_SUMMARY:
+				counter(name+"_sum", tags, metric.GetSummary().GetSampleSum())
+				counter(name+"_count", tags, float64(metric.GetSummary().GetSampleCount()))
+			}
+		}
+	}
+	return lines
+}
+
+// statsdTags returns the DogStatsD tags of a metric with 'metricLabels',
+// plus 'labels', sorted by name
+func statsdTags(metricLabels []*dto.LabelPair, labels map[string]string) string {
+	var tags []string
+	for _, label := range metricLabels {
+		tags = append(tags, label.GetName()+":"+label.GetValue())
+	}
+	for _, name := range sortedKeys(labels) {
+		tags = append(tags, name+":"+labels[name])
+	}
+	if len(tags) == 0 {
+		return ""
+	}
+	sort.Strings(tags)
+	return "|#" + strings.Join(tags, ",")
+}
+
+func formatStatsdValue(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,88 @@
+package worker
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// testMetrics returns a registry with a counter and a histogram like the ones
+// in stats.go, and a metric that isn't in stats.go
+func testMetrics(t *testing.T) (*prometheus.Registry, *prometheus.CounterVec, *prometheus.HistogramVec) {
+	registry := prometheus.NewRegistry()
+	count := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "pachyderm",
+		Subsystem: "worker",
+		Name:      "datum_count",
+	}, []string{"pipeline", "job", "state"})
+	procTime := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "pachyderm",
+		Subsystem: "worker",
+		Name:      "datum_proc_time",
+	}, []string{"pipeline", "job"})
+	other := prometheus.NewGauge(prometheus.GaugeOpts{Name: "other"})
+	for _, c := range []prometheus.Collector{count, procTime, other} {
+		require.NoError(t, registry.Register(c))
+	}
+	return registry, count, procTime
+}
+
+func TestStatsdLines(t *testing.T) {
+	registry, count, procTime := testMetrics(t)
+	count.WithLabelValues("edges", "abc", "finished").Add(3)
+	procTime.WithLabelValues("edges", "abc").Observe(1.5)
+	gatherer := workerMetrics(registry)
+	labels := map[string]string{"team": "vision", "worker": "w"}
+	last := make(map[string]float64)
+
+	families, err := gatherer.Gather()
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"pachyderm_worker_datum_count:3|c|#job:abc,pipeline:edges,state:finished,team:vision,worker:w",
+		"pachyderm_worker_datum_proc_time_sum:1.5|c|#job:abc,pipeline:edges,team:vision,worker:w",
+		"pachyderm_worker_datum_proc_time_count:1|c|#job:abc,pipeline:edges,team:vision,worker:w",
+	}, statsdLines(families, labels, last))
+
+	// Only the increments since the last push are sent
+	count.WithLabelValues("edges", "abc", "finished").Add(2)
+	families, err = gatherer.Gather()
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"pachyderm_worker_datum_count:2|c|#job:abc,pipeline:edges,state:finished,team:vision,worker:w",
+	}, statsdLines(families, labels, last))
+}
+
+func TestPushgatewayPusher(t *testing.T) {
+	registry, count, _ := testMetrics(t)
+	count.WithLabelValues("edges", "abc", "finished").Add(3)
+	var path, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		content, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		body = string(content)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	pushers := newMetricsPushers(&pps.MetricsPush{
+		PushgatewayURL: server.URL,
+		Labels:         map[string]string{"team": "vision"},
+	}, "w", registry)
+	require.Equal(t, 1, len(pushers))
+	require.NoError(t, pushers[0].push())
+	require.True(t, strings.HasPrefix(path, "/metrics/job/pachyderm_worker/"))
+	require.True(t, strings.Contains(path, "/worker/w"))
+	require.True(t, strings.Contains(path, "/team/vision"))
+	// The Pachyderm job is pushed as exported_job, and other metrics aren't
+	// pushed
+	require.True(t, strings.Contains(body, "exported_job"))
+	require.False(t, strings.Contains(body, "other"))
+}
@@ -0,0 +1,74 @@
+package worker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/server/pfs/fuse"
+)
+
+const (
+	// mountPageSize is the size of the pages in which a worker's mount reads
+	// the files of mounted inputs
+	mountPageSize = 1024 * 1024
+	// mountReadAhead is the number of pages that the mount fetches ahead of
+	// sequential reads
+	mountReadAhead = 4
+	// mountTimeout is how long a worker waits for its mount to come up
+	mountTimeout = time.Minute
+)
+
+// inputMountPoint is where the workers of pipelines with mounted inputs mount
+// PFS. It's outside of /pfs, as the worker clears /pfs between datums.
+var inputMountPoint = filepath.Join(os.TempDir(), "pfs-mount")
+
+// mountInputs mounts PFS at inputMountPoint, with a directory per commit, for
+// the pipeline's mounted inputs (see PFSInput.mount). The mount lasts as long
+// as the worker, and its page cache is shared by all of the datums that the
+// worker processes.
+func (a *APIServer) mountInputs() error {
+	if err := os.MkdirAll(inputMountPoint, 0755); err != nil {
+		return err
+	}
+	opts := &fuse.Options{
+		CommitDirs: true,
+		PageSize:   mountPageSize,
+		ReadAhead:  mountReadAhead,
+		// The user code may run as a different user than the worker
+		AllowOther: true,
+		Mounted:    make(chan struct{}),
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- fuse.Mount(a.pachClient, inputMountPoint, opts)
+	}()
+	select {
+	case <-opts.Mounted:
+		return nil
+	case err := <-errCh:
+		if err == nil {
+			err = fmt.Errorf("unmounted")
+		}
+		return fmt.Errorf("error mounting PFS for mounted inputs: %v", err)
+	case <-time.After(mountTimeout):
+		return fmt.Errorf("timed out mounting PFS for mounted inputs")
+	}
+}
+
+// linkMountedInput links 'root', where the file 'input' of a datum would be
+// downloaded to, to the file in the worker's mount, from which the user code
+// reads it
+func linkMountedInput(input *Input, root string) error {
+	if err := os.MkdirAll(filepath.Dir(root), 0777); err != nil {
+		return err
+	}
+	file := input.FileInfo.File
+	return os.Symlink(filepath.Join(inputMountPoint, file.Commit.Repo.Name, file.Commit.ID, file.Path), root)
+}
+
+// mountedInput returns whether 'input' is read from the worker's mount
+func mountedInput(input *Input) bool {
+	return input.Lazy && input.Mount
+}
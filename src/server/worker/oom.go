@@ -0,0 +1,52 @@
+package worker
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// The files in which the kernel counts the processes of the worker's cgroup
+// that the OOM killer killed, for cgroup v2 and v1 (which only has the
+// counter since Linux 4.13)
+var oomEventFiles = []string{
+	"/sys/fs/cgroup/memory.events",
+	"/sys/fs/cgroup/memory/memory.oom_control",
+}
+
+// oomKills returns the number of processes in the worker's cgroup that the
+// OOM killer has killed, and false if the kernel doesn't expose it.
+func oomKills() (int64, bool) {
+	for _, path := range oomEventFiles {
+		f, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		kills, ok := parseOOMKills(f)
+		f.Close()
+		if ok {
+			return kills, true
+		}
+	}
+	return 0, false
+}
+
+// parseOOMKills reads the `oom_kill` counter from a memory.events or
+// memory.oom_control file
+func parseOOMKills(r io.Reader) (int64, bool) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || fields[0] != "oom_kill" {
+			continue
+		}
+		kills, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return 0, false
+		}
+		return kills, true
+	}
+	return 0, false
+}
@@ -0,0 +1,355 @@
+package worker
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/server/pkg/uuid"
+)
+
+// outputBlockSize is the size after which uploadOutput starts a new block.
+// Splitting a datum's output into blocks bounds how much of it is uploaded
+// again when the upload fails and the datum is retried, as the files in the
+// blocks that were written completely are reused.
+const outputBlockSize = 64 * 1024 * 1024
+
+// outputStreams is the most PutObjects streams that a worker's datums upload
+// their output over at once
+const outputStreams = 4
+
+// landedFile is an output file whose content is in a block that was written
+// completely
+type landedFile struct {
+	hash      []byte
+	size      int64
+	blockRefs []*pfs.BlockRef
+}
+
+// landedOutput records the output files of a datum that its previous
+// attempts uploaded into blocks that were written completely. When the datum
+// is retried, the files that it outputs again unchanged reuse those blocks
+// rather than being uploaded again.
+type landedOutput struct {
+	// mu guards files, as a block that's shared with other datums may be
+	// written completely (and so land files) by one of them
+	mu    sync.Mutex
+	files map[string]*landedFile // keyed by path, relative to /pfs/out
+}
+
+func newLandedOutput() *landedOutput {
+	return &landedOutput{files: make(map[string]*landedFile)}
+}
+
+// get returns the landed file at 'relPath', if it has 'size' bytes.
+// Otherwise the file has changed, so it returns nil.
+func (l *landedOutput) get(relPath string, size int64) *landedFile {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if f, ok := l.files[relPath]; ok && f.size == size {
+		return f
+	}
+	return nil
+}
+
+func (l *landedOutput) add(relPath string, f *landedFile) {
+	if l != nil {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.files[relPath] = f
+	}
+}
+
+// reuse returns the landed file at 'relPath' if the content of 'f', which
+// has 'size' bytes, is the same. Otherwise it returns nil, after seeking
+// back to the start of 'f' so that it can be uploaded.
+func (l *landedOutput) reuse(relPath string, f *os.File, size int64, buf []byte) (*landedFile, error) {
+	landed := l.get(relPath, size)
+	if landed == nil {
+		return nil, nil
+	}
+	h := pfs.NewHash()
+	if _, err := io.CopyBuffer(h, f, buf); err != nil {
+		return nil, err
+	}
+	if bytes.Equal(h.Sum(nil), landed.hash) {
+		return landed, nil
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// outputBlockPool multiplexes the output uploads of all of a worker's datums
+// over a few PutObjects streams, rather than each datum opening its own. A
+// stream writes one block at a time, which the files of many datums may
+// share, so each datum's outputBlockWriter keeps track of the blocks that it
+// wrote to, and the datum's output is only uploaded once they've all been
+// written completely.
+type outputBlockPool struct {
+	putObjects func() (pfs.ObjectAPI_PutObjectsClient, error)
+	size       int
+
+	mu sync.Mutex
+	// cond is broadcast whenever a stream is released
+	cond    *sync.Cond
+	idle    []*outputStream
+	streams int // the number of streams, idle or in use
+}
+
+func newOutputBlockPool(putObjects func() (pfs.ObjectAPI_PutObjectsClient, error), size int) *outputBlockPool {
+	p := &outputBlockPool{
+		putObjects: putObjects,
+		size:       size,
+	}
+	p.cond = sync.NewCond(&p.mu)
+	return p
+}
+
+// outputStream is one of the pool's streams. It's only used by the writer that
+// acquired it.
+type outputStream struct {
+	block *outputBlock // nil if the stream isn't writing a block
+}
+
+// outputBlock is a block that a pool stream is writing
+type outputBlock struct {
+	client  pfs.ObjectAPI_PutObjectsClient
+	block   *pfs.Block
+	class   pfs.StorageClass
+	offset  uint64
+	pending []pendingFile // the files that land when the block is written
+	// done is closed when the block has been written completely, or has
+	// failed with err
+	done chan struct{}
+	err  error
+}
+
+// pendingFile is a file, of a datum whose landed output is 'landed', in a
+// block that hasn't been written completely yet
+type pendingFile struct {
+	landed  *landedOutput
+	relPath string
+	file    *landedFile
+}
+
+// acquire returns a stream for writing a file of storage class 'class',
+// waiting for one if they're all in use. It prefers a stream that's writing a
+// block of 'class', then one that isn't writing a block, then a new stream,
+// and only then one that's writing a block of another class.
+func (p *outputBlockPool) acquire(class pfs.StorageClass) *outputStream {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for {
+		best := -1
+		for i, s := range p.idle {
+			if s.block == nil || s.block.class == class {
+				best = i
+				if s.block != nil {
+					break
+				}
+			}
+		}
+		if best < 0 && p.streams < p.size {
+			p.streams++
+			return &outputStream{}
+		}
+		if best < 0 && len(p.idle) > 0 {
+			best = 0
+		}
+		if best >= 0 {
+			s := p.idle[best]
+			p.idle = append(p.idle[:best], p.idle[best+1:]...)
+			return s
+		}
+		p.cond.Wait()
+	}
+}
+
+func (p *outputBlockPool) release(s *outputStream) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.idle = append(p.idle, s)
+	p.cond.Broadcast()
+}
+
+// flush waits for 'b' to be written completely, finishing it if it's still
+// being written, and returns its error
+func (p *outputBlockPool) flush(b *outputBlock) error {
+	p.mu.Lock()
+	for {
+		select {
+		case <-b.done:
+			p.mu.Unlock()
+			return b.err
+		default:
+		}
+		for i, s := range p.idle {
+			if s.block == b {
+				p.idle = append(p.idle[:i], p.idle[i+1:]...)
+				p.mu.Unlock()
+				s.finish(nil)
+				p.release(s)
+				return b.err
+			}
+		}
+		// the stream writing 'b' is in use
+		p.cond.Wait()
+	}
+}
+
+// start starts writing a new block of storage class 'class'
+func (s *outputStream) start(putObjects func() (pfs.ObjectAPI_PutObjectsClient, error), class pfs.StorageClass) error {
+	client, err := putObjects()
+	if err != nil {
+		return err
+	}
+	b := &outputBlock{
+		client: client,
+		block:  &pfs.Block{Hash: uuid.NewWithoutDashes()},
+		class:  class,
+		done:   make(chan struct{}),
+	}
+	s.block = b
+	if err := client.Send(&pfs.PutObjectRequest{
+		Block:        b.block,
+		StorageClass: class,
+	}); err != nil {
+		s.finish(err)
+		return err
+	}
+	return nil
+}
+
+// finish finishes writing the stream's block, if any, landing its files if
+// it was written completely. 'sendErr' is the error, if any, with which
+// sending the block's content failed, in which case the block fails with it.
+func (s *outputStream) finish(sendErr error) error {
+	b := s.block
+	if b == nil {
+		return nil
+	}
+	s.block = nil
+	_, err := b.client.CloseAndRecv()
+	if err == io.EOF {
+		err = nil
+	}
+	if sendErr != nil {
+		err = sendErr
+	}
+	b.err = err
+	if err == nil {
+		for _, f := range b.pending {
+			f.landed.add(f.relPath, f.file)
+		}
+	}
+	close(b.done)
+	return err
+}
+
+// outputBlockWriter writes the files of a datum's output to the blocks of a
+// pool's streams. Once a block has been written completely, its files are
+// added to 'landed'.
+type outputBlockWriter struct {
+	pool   *outputBlockPool
+	landed *landedOutput
+	class  pfs.StorageClass
+	// blocks are the blocks that the writer wrote files to, which close
+	// waits for
+	blocks map[*outputBlock]bool
+}
+
+func newOutputBlockWriter(pool *outputBlockPool, landed *landedOutput, class pfs.StorageClass) *outputBlockWriter {
+	return &outputBlockWriter{
+		pool:   pool,
+		landed: landed,
+		class:  class,
+		blocks: make(map[*outputBlock]bool),
+	}
+}
+
+// write uploads the content of the output file at 'relPath' from 'r', adding
+// the number of bytes written to 'uploaded' as it goes. The file isn't
+// necessarily uploaded until close returns.
+func (w *outputBlockWriter) write(relPath string, r io.Reader, buf []byte, uploaded *int64) (*landedFile, error) {
+	s := w.pool.acquire(w.class)
+	defer w.pool.release(s)
+	if s.block != nil && s.block.class != w.class {
+		// Another datum's block, whose error is reported when that datum
+		// closes its writer
+		s.finish(nil)
+	}
+	if s.block == nil {
+		if err := s.start(w.pool.putObjects, w.class); err != nil {
+			return nil, err
+		}
+	}
+	b := s.block
+	var size int64
+	h := pfs.NewHash()
+	r = io.TeeReader(r, h)
+	for {
+		n, err := r.Read(buf)
+		if n == 0 && err != nil {
+			if err == io.EOF {
+				break
+			}
+			// The part of the file that was written is skipped, so that
+			// the block can still be finished for the other files in it
+			b.offset += uint64(size)
+			return nil, err
+		}
+		if err := b.client.Send(&pfs.PutObjectRequest{
+			Value: buf[:n],
+		}); err != nil {
+			w.blocks[b] = true
+			s.finish(err)
+			return nil, err
+		}
+		size += int64(n)
+		atomic.AddInt64(uploaded, int64(n))
+	}
+	f := &landedFile{
+		hash: h.Sum(nil),
+		size: size,
+		blockRefs: []*pfs.BlockRef{
+			&pfs.BlockRef{
+				Block: b.block,
+				Range: &pfs.ByteRange{
+					Lower: b.offset,
+					Upper: b.offset + uint64(size),
+				},
+			},
+		},
+	}
+	b.offset += uint64(size)
+	b.pending = append(b.pending, pendingFile{landed: w.landed, relPath: relPath, file: f})
+	w.blocks[b] = true
+	if b.offset >= outputBlockSize {
+		if err := s.finish(nil); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// close waits for the blocks that the writer wrote to to be written
+// completely, finishing the ones that are still being written, and returns
+// the first error that any of them failed with
+func (w *outputBlockWriter) close() error {
+	var retErr error
+	for b := range w.blocks {
+		if err := w.pool.flush(b); err != nil && retErr == nil {
+			retErr = err
+		}
+	}
+	w.blocks = make(map[*outputBlock]bool)
+	return retErr
+}
@@ -0,0 +1,186 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+)
+
+// fakePutObjectsClient records the requests sent on a PutObjects stream
+type fakePutObjectsClient struct {
+	grpc.ClientStream
+	requests []*pfs.PutObjectRequest
+	closed   bool
+	closeErr error
+}
+
+func (c *fakePutObjectsClient) Send(request *pfs.PutObjectRequest) error {
+	c.requests = append(c.requests, request)
+	return nil
+}
+
+func (c *fakePutObjectsClient) CloseAndRecv() (*types.Empty, error) {
+	c.closed = true
+	return nil, c.closeErr
+}
+
+func TestOutputBlockWriter(t *testing.T) {
+	var clients []*fakePutObjectsClient
+	landed := newLandedOutput()
+	w := newOutputBlockWriter(newOutputBlockPool(func() (pfs.ObjectAPI_PutObjectsClient, error) {
+		c := &fakePutObjectsClient{}
+		clients = append(clients, c)
+		return c, nil
+	}, 1), landed, pfs.StorageClass_ARCHIVE)
+	buf := make([]byte, 1024)
+	var uploaded int64
+
+	a, err := w.write("a", strings.NewReader("foo"), buf, &uploaded)
+	require.NoError(t, err)
+	b, err := w.write("dir/b", strings.NewReader("barbaz"), buf, &uploaded)
+	require.NoError(t, err)
+	require.Equal(t, int64(9), uploaded)
+	require.Equal(t, 1, len(clients))
+	require.Equal(t, pfs.StorageClass_ARCHIVE, clients[0].requests[0].StorageClass)
+	require.Equal(t, uint64(3), b.blockRefs[0].Range.Lower)
+	require.Equal(t, uint64(9), b.blockRefs[0].Range.Upper)
+	require.Equal(t, a.blockRefs[0].Block, b.blockRefs[0].Block)
+	// Files only land once their block is written completely
+	require.Nil(t, landed.get("a", 3))
+	require.NoError(t, w.close())
+	require.True(t, clients[0].closed)
+	require.Equal(t, a, landed.get("a", 3))
+	require.Equal(t, b, landed.get("dir/b", 6))
+	require.Nil(t, landed.get("a", 4))
+
+	// A block that isn't written completely doesn't land
+	_, err = w.write("c", strings.NewReader("qux"), buf, &uploaded)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(clients))
+	require.NotEqual(t, a.blockRefs[0].Block, clients[1].requests[0].Block)
+	clients[1].closeErr = errors.New("connection reset")
+	require.YesError(t, w.close())
+	require.Nil(t, landed.get("c", 3))
+}
+
+func TestOutputBlockPool(t *testing.T) {
+	var mu sync.Mutex
+	var clients []*fakePutObjectsClient
+	pool := newOutputBlockPool(func() (pfs.ObjectAPI_PutObjectsClient, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		c := &fakePutObjectsClient{}
+		clients = append(clients, c)
+		return c, nil
+	}, 2)
+	buf := make([]byte, 1024)
+	var uploaded int64
+
+	// Datums' files share a block, which the first datum to close finishes
+	l1, l2 := newLandedOutput(), newLandedOutput()
+	w1 := newOutputBlockWriter(pool, l1, pfs.StorageClass_STANDARD)
+	w2 := newOutputBlockWriter(pool, l2, pfs.StorageClass_STANDARD)
+	a, err := w1.write("a", strings.NewReader("foo"), buf, &uploaded)
+	require.NoError(t, err)
+	b, err := w2.write("b", strings.NewReader("barbaz"), buf, &uploaded)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(clients))
+	require.Equal(t, a.blockRefs[0].Block, b.blockRefs[0].Block)
+	require.Equal(t, uint64(3), b.blockRefs[0].Range.Lower)
+	require.NoError(t, w2.close())
+	require.True(t, clients[0].closed)
+	require.Equal(t, a, l1.get("a", 3))
+	require.Equal(t, b, l2.get("b", 6))
+	require.NoError(t, w1.close())
+
+	// A file that fails to be read doesn't fail the block for the others
+	_, err = w1.write("c", iotest.TimeoutReader(strings.NewReader("qux")), buf, &uploaded)
+	require.YesError(t, err)
+	d, err := w2.write("d", strings.NewReader("quux"), buf, &uploaded)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), d.blockRefs[0].Range.Lower)
+	require.NoError(t, w2.close())
+	require.Equal(t, d, l2.get("d", 4))
+
+	// Blocks of different storage classes don't mix
+	archive := newOutputBlockWriter(pool, newLandedOutput(), pfs.StorageClass_ARCHIVE)
+	_, err = w1.write("e", strings.NewReader("e"), buf, &uploaded)
+	require.NoError(t, err)
+	f, err := archive.write("f", strings.NewReader("f"), buf, &uploaded)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), f.blockRefs[0].Range.Lower)
+	require.Equal(t, pfs.StorageClass_ARCHIVE, clients[len(clients)-1].requests[0].StorageClass)
+
+	// Concurrent datums don't open more streams than the pool's size
+	var eg errgroup.Group
+	for i := 0; i < 8; i++ {
+		w := newOutputBlockWriter(pool, newLandedOutput(), pfs.StorageClass_STANDARD)
+		eg.Go(func() error {
+			buf := make([]byte, 1024)
+			for j := 0; j < 10; j++ {
+				if _, err := w.write(fmt.Sprintf("file-%d", j), strings.NewReader("content"), buf, &uploaded); err != nil {
+					return err
+				}
+			}
+			return w.close()
+		})
+	}
+	require.NoError(t, eg.Wait())
+	require.NoError(t, w1.close())
+	require.NoError(t, archive.close())
+	require.Equal(t, 2, pool.streams)
+}
+
+func TestLandedOutputReuse(t *testing.T) {
+	dir, err := ioutil.TempDir("", "landed-output")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	landed := newLandedOutput()
+	w := newOutputBlockWriter(newOutputBlockPool(func() (pfs.ObjectAPI_PutObjectsClient, error) {
+		return &fakePutObjectsClient{}, nil
+	}, 1), landed, pfs.StorageClass_STANDARD)
+	buf := make([]byte, 1024)
+	var uploaded int64
+	file, err := w.write("a", strings.NewReader("foo"), buf, &uploaded)
+	require.NoError(t, err)
+	require.NoError(t, w.close())
+
+	open := func(content string) *os.File {
+		p := filepath.Join(dir, "a")
+		require.NoError(t, ioutil.WriteFile(p, []byte(content), 0666))
+		f, err := os.Open(p)
+		require.NoError(t, err)
+		return f
+	}
+	// The same content is reused
+	f := open("foo")
+	defer f.Close()
+	reused, err := landed.reuse("a", f, 3, buf)
+	require.NoError(t, err)
+	require.Equal(t, file, reused)
+	// Different content of the same size isn't, and can still be read
+	g := open("bar")
+	defer g.Close()
+	reused, err = landed.reuse("a", g, 3, buf)
+	require.NoError(t, err)
+	require.Nil(t, reused)
+	content, err := ioutil.ReadAll(g)
+	require.NoError(t, err)
+	require.Equal(t, "bar", string(content))
+	// Files that no attempt uploaded aren't either
+	reused, err = landed.reuse("b", g, 3, buf)
+	require.NoError(t, err)
+	require.Nil(t, reused)
+}
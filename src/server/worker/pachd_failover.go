@@ -0,0 +1,152 @@
+package worker
+
+import (
+	"fmt"
+	"math/rand"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/hashtree"
+	filesync "github.com/pachyderm/pachyderm/src/server/pkg/sync"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// pachdServiceName is the name of the kubernetes service in front of the
+	// cluster's pachd replicas
+	pachdServiceName = "pachd"
+	// pachdGRPCPortName is the name of the pachd service's GRPC port
+	pachdGRPCPortName = "api-grpc-port"
+	// defaultPachdGRPCPort is the port of pachd's GRPC API, if the pachd
+	// service doesn't name it
+	defaultPachdGRPCPort = 650
+)
+
+// pachdReplicas holds connections to the cluster's pachd replicas, which
+// downloads are retried against if the worker's sidecar is unavailable
+// (e.g. because it's restarting during a rolling upgrade of pachd)
+type pachdReplicas struct {
+	mu      sync.Mutex
+	clients map[string]*client.APIClient
+}
+
+func newPachdReplicas() *pachdReplicas {
+	return &pachdReplicas{clients: make(map[string]*client.APIClient)}
+}
+
+// client returns a client connected to the pachd at 'address', which is
+// reused by later downloads
+func (r *pachdReplicas) client(address string) (*client.APIClient, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if c, ok := r.clients[address]; ok {
+		return c, nil
+	}
+	c, err := client.NewFromAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	r.clients[address] = c
+	return c, nil
+}
+
+// pachdReplicaAddresses returns the GRPC addresses of the cluster's ready
+// pachd replicas, in random order so that the workers of a pipeline don't
+// all fail over to the same one. They're looked up each time, as the
+// replicas change during an upgrade. If the replicas can't be listed, the
+// pachd service's address is returned instead.
+func (a *APIServer) pachdReplicaAddresses() ([]string, error) {
+	var addresses []string
+	endpoints, err := a.kubeClient.CoreV1().Endpoints(a.namespace).Get(pachdServiceName, metav1.GetOptions{})
+	if err == nil {
+		addresses = endpointAddresses(endpoints)
+		rand.Shuffle(len(addresses), func(i, j int) { addresses[i], addresses[j] = addresses[j], addresses[i] })
+	}
+	if len(addresses) > 0 {
+		return addresses, nil
+	}
+	host, port := os.Getenv("PACHD_SERVICE_HOST"), os.Getenv("PACHD_SERVICE_PORT")
+	if host == "" || port == "" {
+		if err == nil {
+			err = fmt.Errorf("service %s has no ready endpoints", pachdServiceName)
+		}
+		return nil, err
+	}
+	return []string{net.JoinHostPort(host, port)}, nil
+}
+
+// endpointAddresses returns the GRPC addresses of the ready pods in
+// 'endpoints', the endpoints of the pachd service
+func endpointAddresses(endpoints *v1.Endpoints) []string {
+	var addresses []string
+	for _, subset := range endpoints.Subsets {
+		port := int32(defaultPachdGRPCPort)
+		for _, p := range subset.Ports {
+			if p.Name == pachdGRPCPortName {
+				port = p.Port
+			}
+		}
+		for _, address := range subset.Addresses {
+			addresses = append(addresses, net.JoinHostPort(address.IP, strconv.Itoa(int(port))))
+		}
+	}
+	return addresses
+}
+
+// downloadDataWithFailover is like downloadData, but if the download fails
+// because the worker's sidecar is unavailable, it's retried against the
+// cluster's other pachd replicas, rather than failing the datum attempt. It
+// returns the puller the data was downloaded with, which serves the datum's
+// lazy inputs.
+func (a *APIServer) downloadDataWithFailover(pachClient *client.APIClient, logger *taggedLogger, inputs []*Input, prevCommit *pfs.Commit, stats *pps.ProcessStats, statsTree *hashtree.Ordered) (string, *filesync.Puller, error) {
+	puller := a.newPuller()
+	dir, err := a.downloadData(pachClient, logger, inputs, prevCommit, puller, stats, statsTree)
+	if err == nil || !grpcutil.IsUnavailable(err) {
+		return dir, puller, err
+	}
+	addresses, resolveErr := a.pachdReplicaAddresses()
+	if resolveErr != nil {
+		logger.Logf("could not find other pachd replicas to retry the download against: %v", resolveErr)
+		return dir, puller, err
+	}
+	for _, address := range addresses {
+		replica, dialErr := a.replicas.client(address)
+		if dialErr != nil {
+			logger.Logf("could not connect to pachd replica at %s: %v", address, dialErr)
+			continue
+		}
+		// Clean up the failed download before retrying it. The replica gets
+		// the same context (and so auth token) as the sidecar's client.
+		if cleanUpErr := cleanUpDownload(dir, puller); cleanUpErr != nil {
+			return "", puller, cleanUpErr
+		}
+		logger.Logf("pachd is unavailable (%v), retrying the download against the pachd replica at %s", err, address)
+		puller = a.newPuller()
+		dir, err = a.downloadData(replica.WithCtx(pachClient.Ctx()), logger, inputs, prevCommit, puller, stats, statsTree)
+		if err == nil || !grpcutil.IsUnavailable(err) {
+			return dir, puller, err
+		}
+	}
+	return dir, puller, err
+}
+
+// cleanUpDownload removes a datum that was (partially) downloaded to 'dir'
+// with 'puller'
+func cleanUpDownload(dir string, puller *filesync.Puller) error {
+	// the puller's pipes must be cleaned up before they're removed
+	if _, err := puller.CleanUp(); err != nil {
+		return err
+	}
+	if dir == "" {
+		return nil
+	}
+	return os.RemoveAll(dir)
+}
@@ -0,0 +1,31 @@
+package worker
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestEndpointAddresses(t *testing.T) {
+	endpoints := &v1.Endpoints{
+		Subsets: []v1.EndpointSubset{
+			{
+				Addresses: []v1.EndpointAddress{{IP: "10.0.0.1"}, {IP: "10.0.0.2"}},
+				// Not-ready replicas aren't retried against
+				NotReadyAddresses: []v1.EndpointAddress{{IP: "10.0.0.3"}},
+				Ports: []v1.EndpointPort{
+					{Name: "api-http-port", Port: 652},
+					{Name: pachdGRPCPortName, Port: 1650},
+				},
+			},
+			{
+				// The default port is used if the GRPC port isn't named
+				Addresses: []v1.EndpointAddress{{IP: "10.0.0.4"}},
+			},
+		},
+	}
+	require.Equal(t, []string{"10.0.0.1:1650", "10.0.0.2:1650", "10.0.0.4:650"}, endpointAddresses(endpoints))
+	require.Equal(t, 0, len(endpointAddresses(&v1.Endpoints{})))
+}
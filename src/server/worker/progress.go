@@ -0,0 +1,110 @@
+package worker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	units "github.com/docker/go-units"
+	"github.com/gogo/protobuf/types"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// progressInterval is how often the progress of a running download or upload
+// is logged
+var progressInterval = 30 * time.Second
+
+// transfer is a running download of a datum's inputs or upload of its output
+type transfer struct {
+	phase   string // "download" or "upload"
+	total   int64  // 0 if unknown
+	done    func() int64
+	started time.Time
+	// rate is the throughput over the last progressInterval, in bytes per
+	// second. It's guarded by the APIServer's statusMu.
+	rate float64
+}
+
+func (t *transfer) progress() *pps.TransferProgress {
+	started, _ := types.TimestampProto(t.started)
+	return &pps.TransferProgress{
+		Phase:          t.phase,
+		BytesDone:      t.done(),
+		BytesTotal:     t.total,
+		BytesPerSecond: t.rate,
+		Started:        started,
+	}
+}
+
+// progressMessage returns the log line reporting that 'done' bytes of a
+// transfer have been moved at 'rate' bytes per second
+func progressMessage(phase string, done, total int64, rate float64) string {
+	if total <= 0 {
+		return fmt.Sprintf("%s progress: %s (%s/s)", phase, units.BytesSize(float64(done)), units.BytesSize(rate))
+	}
+	return fmt.Sprintf("%s progress: %s of %s (%.1f%%, %s/s)", phase,
+		units.BytesSize(float64(done)), units.BytesSize(float64(total)),
+		100*float64(done)/float64(total), units.BytesSize(rate))
+}
+
+// trackTransfer reports the progress of a download or upload that's expected
+// to move 'total' bytes (0 if unknown), of which 'done' returns the number
+// moved so far. Until the returned function is called, the progress is
+// logged every progressInterval, and it's part of the worker's status.
+func (a *APIServer) trackTransfer(logger *taggedLogger, phase string, total int64, done func() int64) func() {
+	t := &transfer{phase: phase, total: total, done: done, started: time.Now()}
+	func() {
+		a.statusMu.Lock()
+		defer a.statusMu.Unlock()
+		a.transfer = t
+	}()
+	stop := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+		last, lastTime := int64(0), t.started
+		for {
+			select {
+			case <-stop:
+				return
+			case now := <-ticker.C:
+				current := done()
+				rate := float64(current-last) / now.Sub(lastTime).Seconds()
+				last, lastTime = current, now
+				func() {
+					a.statusMu.Lock()
+					defer a.statusMu.Unlock()
+					t.rate = rate
+				}()
+				logger.Logf("%s", progressMessage(phase, current, total, rate))
+			}
+		}
+	}()
+	return func() {
+		close(stop)
+		<-finished
+		a.statusMu.Lock()
+		defer a.statusMu.Unlock()
+		// With prefetching, another datum's download may have started since
+		if a.transfer == t {
+			a.transfer = nil
+		}
+	}
+}
+
+// outputSize returns the size of the regular files under 'outputPath', which
+// is what uploadOutput is expected to upload. Symlinks aren't counted, as
+// links to input files aren't uploaded again.
+func outputSize(outputPath string) int64 {
+	var size int64
+	filepath.Walk(outputPath, func(_ string, info os.FileInfo, err error) error {
+		if err == nil && info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
@@ -0,0 +1,27 @@
+package worker
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestProgressMessage(t *testing.T) {
+	require.Equal(t, "download progress: 512MiB of 2GiB (25.0%, 10MiB/s)", progressMessage("download", 512<<20, 2<<30, 10<<20))
+	require.Equal(t, "upload progress: 1KiB (512B/s)", progressMessage("upload", 1<<10, 0, 512))
+}
+
+func TestOutputSize(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pachyderm_test_output_size")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0777))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "a"), make([]byte, 10), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "sub", "b"), make([]byte, 20), 0644))
+	require.NoError(t, os.Symlink(filepath.Join(dir, "a"), filepath.Join(dir, "link")))
+	require.Equal(t, int64(30), outputSize(dir))
+	require.Equal(t, int64(0), outputSize(filepath.Join(dir, "missing")))
+}
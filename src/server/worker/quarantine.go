@@ -0,0 +1,125 @@
+package worker
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pachyderm/pachyderm/src/client"
+)
+
+// maxQuarantinedLogBytes is the most of a datum's user code output that's
+// copied to the pipeline's quarantine repo. Later lines are dropped.
+const maxQuarantinedLogBytes = 16 * 1024 * 1024
+
+// userOutput collects the output of a datum's user code, over all of its
+// tries, so that it can be quarantined if the datum fails
+type userOutput struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (o *userOutput) add(line string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.buf.Len()+len(line)+1 <= maxQuarantinedLogBytes {
+		o.buf.WriteString(line)
+		o.buf.WriteString("\n")
+	}
+}
+
+func (o *userOutput) bytes() []byte {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.buf.Bytes()
+}
+
+// stashOutput moves the output that a datum's user code wrote in 'dir' before
+// it failed to 'stash', where it outlives 'dir' until the datum either
+// succeeds or is quarantined
+func stashOutput(dir string, stash string) error {
+	if err := os.RemoveAll(stash); err != nil {
+		return err
+	}
+	if err := os.Rename(filepath.Join(dir, "out"), stash); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// quarantineDatum copies a datum that failed with 'failure' to the pipeline's
+// quarantine repo (see pps.Quarantine): its inputs, by reference, the output
+// in 'stash' (see stashOutput) and its user code output.
+func (a *APIServer) quarantineDatum(pachClient *client.APIClient, logger *taggedLogger, data []*Input, stash string, failure error) error {
+	repo := a.pipelineInfo.Quarantine.Repo
+	root := path.Join("/", logger.template.JobID, logger.template.DatumID)
+	// The files are put before the inputs are copied, as CopyFile can't
+	// create the repo's master branch
+	if err := putQuarantineFiles(pachClient, repo, root, logger, stash, failure); err != nil {
+		return err
+	}
+	for _, input := range data {
+		file := input.FileInfo.File
+		if err := pachClient.CopyFile(file.Commit.Repo.Name, file.Commit.ID, file.Path,
+			repo, "master", path.Join(root, "pfs", input.Name, file.Path), true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// putQuarantineFiles writes a failed datum's user code output, error and
+// stashed output under 'root' in the quarantine repo 'repo'
+func putQuarantineFiles(pachClient *client.APIClient, repo string, root string, logger *taggedLogger, stash string, failure error) (retErr error) {
+	pfc, err := pachClient.NewPutFileClient()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := pfc.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+	var logs []byte
+	if logger.userOutput != nil {
+		logs = logger.userOutput.bytes()
+	}
+	if _, err := pfc.PutFileOverwrite(repo, "master", path.Join(root, "logs"), bytes.NewReader(logs), 0); err != nil {
+		return err
+	}
+	if _, err := pfc.PutFileOverwrite(repo, "master", path.Join(root, "failure"), strings.NewReader(failure.Error()), 0); err != nil {
+		return err
+	}
+	return filepath.Walk(stash, func(filePath string, info os.FileInfo, err error) error {
+		if os.IsNotExist(err) && filePath == stash {
+			return nil // the datum didn't get to write any output
+		}
+		if err != nil {
+			return err
+		}
+		// Output files may be symlinks (e.g. to input files), which are
+		// followed, but symlinked directories aren't walked
+		if info.Mode()&os.ModeSymlink != 0 {
+			if info, err = os.Stat(filePath); err != nil {
+				return err
+			}
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(stash, filePath)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(filePath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = pfc.PutFileOverwrite(repo, "master", path.Join(root, "pfs", "out", filepath.ToSlash(rel)), f, 0)
+		return err
+	})
+}
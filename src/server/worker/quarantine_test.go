@@ -0,0 +1,62 @@
+package worker
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	tu "github.com/pachyderm/pachyderm/src/server/pkg/testutil"
+)
+
+func TestQuarantineDatum(t *testing.T) {
+	dir, err := ioutil.TempDir("", "quarantine")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "out", "sub"), 0777))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "out", "sub", "partial"), []byte("partial"), 0666))
+	stash := filepath.Join(dir, "stash")
+	require.NoError(t, stashOutput(dir, stash))
+	_, err = os.Stat(filepath.Join(dir, "out"))
+	require.True(t, os.IsNotExist(err))
+
+	require.NoError(t, tu.WithRealEnv(func(env *tu.RealEnv) error {
+		c := env.PachClient
+		require.NoError(t, c.CreateRepo("input"))
+		require.NoError(t, c.CreateRepo("quarantine"))
+		_, err := c.PutFile("input", "master", "/dir/file", strings.NewReader("input"))
+		require.NoError(t, err)
+		fileInfo, err := c.InspectFile("input", "master", "/dir/file")
+		require.NoError(t, err)
+
+		a := &APIServer{pipelineInfo: &pps.PipelineInfo{Quarantine: &pps.Quarantine{Repo: "quarantine"}}}
+		logger := &taggedLogger{userOutput: &userOutput{}}
+		logger.template.JobID = "job"
+		logger.template.DatumID = "datum"
+		logger.userOutput.add("stdout")
+		logger.userOutput.add("stderr")
+		data := []*Input{{Name: "in", FileInfo: fileInfo}}
+		require.NoError(t, a.quarantineDatum(c, logger, data, stash, errors.New("exit status 1")))
+
+		get := func(path string) string {
+			buf := &bytes.Buffer{}
+			require.NoError(t, c.GetFile("quarantine", "master", path, 0, 0, buf))
+			return buf.String()
+		}
+		require.Equal(t, "input", get("/job/datum/pfs/in/dir/file"))
+		require.Equal(t, "partial", get("/job/datum/pfs/out/sub/partial"))
+		require.Equal(t, "stdout\nstderr\n", get("/job/datum/logs"))
+		require.Equal(t, "exit status 1", get("/job/datum/failure"))
+
+		// a datum that failed before writing any output
+		logger.template.DatumID = "empty"
+		require.NoError(t, a.quarantineDatum(c, logger, data, filepath.Join(dir, "missing"), errors.New("error downloadData")))
+		require.Equal(t, "error downloadData", get("/job/empty/failure"))
+		return nil
+	}))
+}
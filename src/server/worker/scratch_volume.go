@@ -0,0 +1,31 @@
+package worker
+
+import (
+	"fmt"
+
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// checkScratchVolume checks that the volume at 'dir' (/pfs, see
+// pps.ScratchVolume) has the capacity that the pipeline requires available,
+// so that a worker on a node without enough space fails when it starts,
+// rather than its datums failing when the disk fills up
+func checkScratchVolume(dir string, volume *pps.ScratchVolume) error {
+	if volume == nil || volume.Capacity == "" {
+		return nil
+	}
+	capacity, err := resource.ParseQuantity(volume.Capacity)
+	if err != nil {
+		return err // Shouldn't happen, as the volume is validated in CreatePipeline
+	}
+	available, err := availableBytes(dir)
+	if err != nil {
+		return fmt.Errorf("could not check the space available in %s: %v", dir, err)
+	}
+	if available < capacity.Value() {
+		return fmt.Errorf("%s has %d bytes available, but the pipeline's scratch_volume requires %s (%d bytes)",
+			dir, available, volume.Capacity, capacity.Value())
+	}
+	return nil
+}
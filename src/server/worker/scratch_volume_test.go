@@ -0,0 +1,20 @@
+package worker
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestCheckScratchVolume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scratch")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, checkScratchVolume(dir, nil))
+	require.NoError(t, checkScratchVolume(dir, &pps.ScratchVolume{}))
+	require.NoError(t, checkScratchVolume(dir, &pps.ScratchVolume{Capacity: "1Ki"}))
+	require.YesError(t, checkScratchVolume(dir, &pps.ScratchVolume{Capacity: "1E"}))
+}
@@ -0,0 +1,98 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	osexec "os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// smokeTimeout bounds how long a transform's smoke_cmd may run
+	smokeTimeout = 5 * time.Minute
+	// maxSmokeOutput is how much of a failed smoke_cmd's output (its end) is
+	// kept for the pipeline's reason
+	maxSmokeOutput = 1024
+)
+
+// verifyImage checks that the pipeline's image can run its transform, before
+// the worker claims any work. It runs the transform's smoke_cmd if it has one,
+// and otherwise only checks that the transform's command exists.
+func (a *APIServer) verifyImage() error {
+	transform := a.pipelineInfo.Transform
+	if len(transform.SmokeCmd) == 0 {
+		// With separate_container, the command is in the user code container,
+		// so it's only found (or not) when the worker runs it
+		if len(transform.Cmd) == 0 || transform.SeparateContainer {
+			return nil
+		}
+		return checkCmdExists(transform.Cmd[0], transform.WorkingDir)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), smokeTimeout)
+	defer cancel()
+	args := a.userCmd(transform.SmokeCmd)
+	var output []byte
+	var err error
+	if transform.SeparateContainer {
+		buf := &bytes.Buffer{}
+		var resp *execResponse
+		if resp, err = execInUserContainer(ctx, a.execRequest(args, nil, a.baseEnv()), buf, buf); err == nil {
+			err = resp.err()
+		}
+		output = buf.Bytes()
+	} else {
+		// The standard os/exec is used rather than pkg/exec, as the latter
+		// doesn't wait for the command's output to be copied if it fails,
+		// and the output is what's reported
+		cmd := osexec.CommandContext(ctx, args[0], args[1:]...)
+		cmd.Env = a.baseEnv()
+		if a.uid != nil && a.gid != nil {
+			cmd.SysProcAttr = makeCmdCredentials(*a.uid, *a.gid)
+		}
+		cmd.Dir = transform.WorkingDir
+		output, err = cmd.CombinedOutput()
+	}
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			err = fmt.Errorf("timed out after %v", smokeTimeout)
+		}
+		return fmt.Errorf("smoke command %v failed: %v: %s", transform.SmokeCmd, err, tail(string(output), maxSmokeOutput))
+	}
+	return nil
+}
+
+// checkCmdExists returns an error if 'name' isn't an executable file,
+// resolving it the way the user code's command is resolved: through $PATH if
+// it's a bare name, or relative to 'dir' otherwise.
+func checkCmdExists(name, dir string) error {
+	if filepath.Base(name) == name {
+		if _, err := osexec.LookPath(name); err != nil {
+			return fmt.Errorf("transform command %q not found in the image: %v", name, err)
+		}
+		return nil
+	}
+	if !filepath.IsAbs(name) {
+		name = filepath.Join(dir, name)
+	}
+	info, err := os.Stat(name)
+	if err != nil {
+		return fmt.Errorf("transform command %q not found in the image: %v", name, err)
+	}
+	if info.IsDir() || info.Mode()&0111 == 0 {
+		return fmt.Errorf("transform command %q is not executable", name)
+	}
+	return nil
+}
+
+// tail returns (at most) the last 'n' bytes of 's'
+func tail(s string, n int) string {
+	s = strings.TrimSpace(s)
+	if len(s) <= n {
+		return s
+	}
+	return "..." + s[len(s)-n:]
+}
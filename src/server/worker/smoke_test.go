@@ -0,0 +1,55 @@
+package worker
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestCheckCmdExists(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not supported on windows")
+	}
+	dir, err := ioutil.TempDir("", "pachyderm_test_smoke")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "run.sh"), []byte("#!/bin/sh\n"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "data.txt"), nil, 0644))
+
+	require.NoError(t, checkCmdExists("sh", ""))
+	require.YesError(t, checkCmdExists("pachyderm-no-such-command", ""))
+	require.NoError(t, checkCmdExists(filepath.Join(dir, "run.sh"), ""))
+	require.NoError(t, checkCmdExists("./run.sh", dir))
+	require.YesError(t, checkCmdExists("./missing.sh", dir))
+	require.YesError(t, checkCmdExists("./data.txt", dir))
+	require.YesError(t, checkCmdExists(dir, ""))
+}
+
+func TestVerifyImage(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sh is not available on windows")
+	}
+	a := &APIServer{pipelineInfo: &pps.PipelineInfo{Transform: &pps.Transform{
+		Cmd: []string{"sh"},
+	}}}
+	require.NoError(t, a.verifyImage())
+
+	a.pipelineInfo.Transform.SmokeCmd = []string{"sh", "-c", "true"}
+	require.NoError(t, a.verifyImage())
+
+	a.pipelineInfo.Transform.SmokeCmd = []string{"sh", "-c", "echo libfoo.so: not found >&2; exit 127"}
+	err := a.verifyImage()
+	require.YesError(t, err)
+	require.True(t, strings.Contains(err.Error(), "libfoo.so: not found"))
+}
+
+func TestTail(t *testing.T) {
+	require.Equal(t, "abc", tail("abc\n", 3))
+	require.Equal(t, "...bc", tail("abc", 2))
+}
@@ -0,0 +1,265 @@
+package worker
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/backoff"
+	"github.com/pachyderm/pachyderm/src/server/pkg/ppsconsts"
+	filesync "github.com/pachyderm/pachyderm/src/server/pkg/sync"
+)
+
+// spoutMarkerFile is the name of a spout's marker, in its output repo and
+// next to its /pfs/out named pipe
+const spoutMarkerFile = "marker"
+
+// spoutCommitter writes the files that a spout receives to its output
+// branch
+type spoutCommitter interface {
+	startCommit() (*pfs.Commit, error)
+	putFile(commit *pfs.Commit, path string, r io.Reader, overwrite bool) (int, error)
+	finishCommit(commit *pfs.Commit) error
+}
+
+// pfsSpoutCommitter is the spoutCommitter that writes to the spout's output
+// repo in PFS
+type pfsSpoutCommitter struct {
+	pachClient   *client.APIClient
+	pipelineInfo *pps.PipelineInfo
+	// specCommit returns the pipeline's current spec commit
+	specCommit func() *pfs.Commit
+}
+
+func (c *pfsSpoutCommitter) startCommit() (*pfs.Commit, error) {
+	repo := c.pipelineInfo.Pipeline.Name
+	return c.pachClient.PfsAPIClient.StartCommit(c.pachClient.Ctx(), &pfs.StartCommitRequest{
+		Parent:     client.NewCommit(repo, ""),
+		Branch:     c.pipelineInfo.OutputBranch,
+		Provenance: []*pfs.CommitProvenance{client.NewCommitProvenance(ppsconsts.SpecRepo, repo, c.specCommit().ID)},
+	})
+}
+
+func (c *pfsSpoutCommitter) putFile(commit *pfs.Commit, path string, r io.Reader, overwrite bool) (int, error) {
+	if overwrite {
+		return c.pachClient.PutFileOverwrite(commit.Repo.Name, commit.ID, path, r, 0)
+	}
+	return c.pachClient.PutFile(commit.Repo.Name, commit.ID, path, r)
+}
+
+func (c *pfsSpoutCommitter) finishCommit(commit *pfs.Commit) error {
+	return c.pachClient.FinishCommit(commit.Repo.Name, commit.ID)
+}
+
+// spoutManager reads the tar streams that a spout's code writes to its
+// /pfs/out named pipe, and commits the files in them to the spout's output
+// branch. Files are put into the same commit until the batch is full (see
+// pps.Spout.BatchBytes and BatchInterval); if the spout doesn't batch its
+// commits, each tar stream is committed on its own.
+type spoutManager struct {
+	committer     spoutCommitter
+	overwrite     bool
+	batchBytes    int64
+	batchInterval time.Duration
+
+	// mu guards the open commit, which is shared by the tar streams and the
+	// goroutine that finishes the batch when its interval is up
+	mu      sync.Mutex
+	commit  *pfs.Commit
+	started time.Time
+	size    int64
+}
+
+func newSpoutManager(committer spoutCommitter, spout *pps.Spout) (*spoutManager, error) {
+	m := &spoutManager{
+		committer:  committer,
+		overwrite:  spout.Overwrite,
+		batchBytes: spout.BatchBytes,
+	}
+	if spout.BatchInterval != nil {
+		interval, err := types.DurationFromProto(spout.BatchInterval)
+		if err != nil {
+			return nil, err
+		}
+		m.batchInterval = interval
+	}
+	return m, nil
+}
+
+// isSpoutMarker returns true if 'path' is (in) the spout's marker, which is
+// always overwritten, as it records where the spout's source is up to
+func isSpoutMarker(path string) bool {
+	return strings.Contains(path, spoutMarkerFile)
+}
+
+// run reads tar streams from the named pipe at 'fifo' until 'ctx' is
+// cancelled
+func (m *spoutManager) run(ctx context.Context, fifo string, logger *taggedLogger) error {
+	if m.batchInterval > 0 {
+		go m.finishBatches(ctx, logger)
+	}
+	return backoff.RetryNotify(func() error {
+		for {
+			if err := m.receiveFifo(fifo); err != nil {
+				return err
+			}
+		}
+	}, backoff.NewInfiniteBackOff(), func(err error, d time.Duration) error {
+		select {
+		case <-ctx.Done():
+			return err
+		default:
+			logger.Logf("error running spout: %+v, retrying in: %+v", err, d)
+			return nil
+		}
+	})
+}
+
+// receiveFifo reads one tar stream from the named pipe at 'fifo', which
+// blocks until the spout's code opens it
+func (m *spoutManager) receiveFifo(fifo string) (retErr error) {
+	out, err := os.Open(fifo)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := out.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+	return m.receive(out)
+}
+
+// receive puts the files in the tar stream 'r' into the open commit, and
+// finishes the commit if that fills up the batch
+func (m *spoutManager) receive(r io.Reader) (retErr error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	defer func() {
+		// finish the commit even if there was an issue, so that the files
+		// that were received aren't held back until the next batch
+		if retErr != nil || m.batchFull() {
+			if err := m.finishCommit(); err != nil && retErr == nil {
+				retErr = err
+			}
+		}
+	}()
+	outTar := tar.NewReader(r)
+	for {
+		fileHeader, err := outTar.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if fileHeader.Typeflag == tar.TypeDir {
+			continue
+		}
+		if m.commit == nil {
+			if m.commit, err = m.committer.startCommit(); err != nil {
+				return err
+			}
+			m.started = time.Now()
+			m.size = 0
+		}
+		n, err := m.committer.putFile(m.commit, fileHeader.Name, outTar, m.overwrite || isSpoutMarker(fileHeader.Name))
+		m.size += int64(n)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// batchFull returns true if the open commit should be finished.
+// m.mu must be held.
+func (m *spoutManager) batchFull() bool {
+	switch {
+	case m.commit == nil:
+		return false
+	case m.batchBytes == 0 && m.batchInterval == 0:
+		return true
+	case m.batchBytes > 0 && m.size >= m.batchBytes:
+		return true
+	case m.batchInterval > 0 && time.Since(m.started) >= m.batchInterval:
+		return true
+	}
+	return false
+}
+
+// finishCommit finishes the open commit, if there is one. m.mu must be
+// held.
+func (m *spoutManager) finishCommit() error {
+	if m.commit == nil {
+		return nil
+	}
+	commit := m.commit
+	m.commit = nil
+	return m.committer.finishCommit(commit)
+}
+
+// finishFullBatch finishes the open commit if its batch is full, which
+// happens between tar streams when the batch's interval is up
+func (m *spoutManager) finishFullBatch() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.batchFull() {
+		return nil
+	}
+	return m.finishCommit()
+}
+
+// finishBatches finishes batches whose interval is up, until 'ctx' is
+// cancelled, when it finishes the open batch
+func (m *spoutManager) finishBatches(ctx context.Context, logger *taggedLogger) {
+	// check a few times per interval, so that batches aren't held open for
+	// much longer than it
+	ticker := time.NewTicker(m.batchInterval / 4)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := m.finishFullBatch(); err != nil {
+				logger.Logf("error finishing spout commit: %v", err)
+			}
+		case <-ctx.Done():
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			if err := m.finishCommit(); err != nil {
+				logger.Logf("error finishing spout commit: %v", err)
+			}
+			return
+		}
+	}
+}
+
+// prepareSpoutOutput creates the named pipe at 'outPath' that the spout's
+// code writes to, and downloads the spout's marker, if it has one, into
+// 'dir'
+func (a *APIServer) prepareSpoutOutput(pachClient *client.APIClient, puller *filesync.Puller, dir string, outPath string) error {
+	if err := os.MkdirAll(filepath.Dir(outPath), 0700); err != nil {
+		return fmt.Errorf("mkdirall :%v", err)
+	}
+	if err := createSpoutFifo(outPath); err != nil {
+		return fmt.Errorf("mkfifo :%v", err)
+	}
+	repo, branch := a.pipelineInfo.Pipeline.Name, a.pipelineInfo.OutputBranch
+	if _, err := pachClient.InspectFile(repo, branch, spoutMarkerFile); err != nil {
+		if pfs.IsBranchNoHeadErr(err) || pfs.IsFileNotFoundErr(err) || pfs.IsRepoNotFoundErr(err) || pfs.IsBranchNotFoundErr(err) {
+			return nil
+		}
+		return err
+	}
+	return puller.Pull(pachClient, filepath.Join(dir, spoutMarkerFile), repo, branch, "/"+spoutMarkerFile, false, false, a.config.transferConcurrency(), nil, "")
+}
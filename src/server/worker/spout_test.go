@@ -0,0 +1,119 @@
+package worker
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+// testSpoutCommitter records the commits that a spoutManager makes
+type testSpoutCommitter struct {
+	commits  []map[string]string
+	finished int
+	failPut  bool
+}
+
+func (c *testSpoutCommitter) startCommit() (*pfs.Commit, error) {
+	c.commits = append(c.commits, make(map[string]string))
+	return client.NewCommit("spout", fmt.Sprint(len(c.commits)-1)), nil
+}
+
+func (c *testSpoutCommitter) putFile(commit *pfs.Commit, path string, r io.Reader, overwrite bool) (int, error) {
+	if c.failPut {
+		return 0, fmt.Errorf("putFile failed")
+	}
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+	files := c.commits[len(c.commits)-1]
+	if overwrite {
+		files[path] = string(content)
+	} else {
+		files[path] += string(content)
+	}
+	return len(content), nil
+}
+
+func (c *testSpoutCommitter) finishCommit(commit *pfs.Commit) error {
+	c.finished++
+	return nil
+}
+
+// spoutTar returns a tar stream of 'files', in order
+func spoutTar(t *testing.T, files ...string) io.Reader {
+	var buf bytes.Buffer
+	w := tar.NewWriter(&buf)
+	for i := 0; i < len(files); i += 2 {
+		require.NoError(t, w.WriteHeader(&tar.Header{Name: files[i], Mode: 0600, Size: int64(len(files[i+1]))}))
+		_, err := w.Write([]byte(files[i+1]))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return &buf
+}
+
+func TestSpoutManager(t *testing.T) {
+	// By default, each tar stream is its own commit, and only the marker is
+	// overwritten
+	c := &testSpoutCommitter{}
+	m, err := newSpoutManager(c, &pps.Spout{})
+	require.NoError(t, err)
+	require.NoError(t, m.receive(spoutTar(t, "a", "foo", "a", "bar", "marker", "1", "marker", "2")))
+	require.NoError(t, m.receive(spoutTar(t)))
+	require.NoError(t, m.receive(spoutTar(t, "b", "baz")))
+	require.Equal(t, []map[string]string{{"a": "foobar", "marker": "2"}, {"b": "baz"}}, c.commits)
+	require.Equal(t, 2, c.finished)
+
+	c = &testSpoutCommitter{}
+	m, err = newSpoutManager(c, &pps.Spout{Overwrite: true})
+	require.NoError(t, err)
+	require.NoError(t, m.receive(spoutTar(t, "a", "foo", "a", "bar")))
+	require.Equal(t, []map[string]string{{"a": "bar"}}, c.commits)
+
+	// A commit is finished even if a stream fails partway through
+	c = &testSpoutCommitter{failPut: true}
+	m, err = newSpoutManager(c, &pps.Spout{})
+	require.NoError(t, err)
+	require.YesError(t, m.receive(spoutTar(t, "a", "foo")))
+	require.Equal(t, 1, c.finished)
+}
+
+func TestSpoutManagerBatches(t *testing.T) {
+	// Streams are put into the same commit until they add up to batch_bytes
+	c := &testSpoutCommitter{}
+	m, err := newSpoutManager(c, &pps.Spout{BatchBytes: 6})
+	require.NoError(t, err)
+	require.NoError(t, m.receive(spoutTar(t, "a", "foo")))
+	require.Equal(t, 0, c.finished)
+	require.NoError(t, m.receive(spoutTar(t, "b", "bar")))
+	require.Equal(t, 1, c.finished)
+	require.NoError(t, m.receive(spoutTar(t, "c", "baz")))
+	require.Equal(t, []map[string]string{{"a": "foo", "b": "bar"}, {"c": "baz"}}, c.commits)
+	require.Equal(t, 1, c.finished)
+
+	// ...or until the commit has been open for batch_interval, even if no
+	// more streams arrive
+	c = &testSpoutCommitter{}
+	m, err = newSpoutManager(c, &pps.Spout{BatchInterval: types.DurationProto(50 * time.Millisecond)})
+	require.NoError(t, err)
+	require.NoError(t, m.receive(spoutTar(t, "a", "foo")))
+	require.NoError(t, m.finishFullBatch())
+	require.Equal(t, 0, c.finished)
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, m.finishFullBatch())
+	require.Equal(t, 1, c.finished)
+	require.NoError(t, m.finishFullBatch())
+	require.Equal(t, 1, c.finished)
+}
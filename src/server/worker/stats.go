@@ -161,6 +161,51 @@ var (
 			"job",
 		},
 	)
+
+	treeCacheHits = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pachyderm",
+			Subsystem: "worker",
+			Name:      "hashtree_cache_hits",
+			Help:      "Number of hashtrees read from the worker's hashtree cache",
+		},
+		[]string{
+			"pipeline",
+		},
+	)
+	treeCacheMisses = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pachyderm",
+			Subsystem: "worker",
+			Name:      "hashtree_cache_misses",
+			Help:      "Number of hashtrees downloaded because they weren't in the worker's hashtree cache",
+		},
+		[]string{
+			"pipeline",
+		},
+	)
+	treeCacheEvictions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pachyderm",
+			Subsystem: "worker",
+			Name:      "hashtree_cache_evictions",
+			Help:      "Number of hashtrees evicted from the worker's hashtree cache to stay within its size",
+		},
+		[]string{
+			"pipeline",
+		},
+	)
+	treeCacheBytes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "pachyderm",
+			Subsystem: "worker",
+			Name:      "hashtree_cache_bytes",
+			Help:      "Size of the hashtrees in the worker's hashtree cache",
+		},
+		[]string{
+			"pipeline",
+		},
+	)
 )
 
 func initPrometheus() {
@@ -176,6 +221,10 @@ func initPrometheus() {
 		datumDownloadBytesCount,
 		datumUploadSize,
 		datumUploadBytesCount,
+		treeCacheHits,
+		treeCacheMisses,
+		treeCacheEvictions,
+		treeCacheBytes,
 	}
 	for _, metric := range metrics {
 		if err := prometheus.Register(metric); err != nil {
@@ -0,0 +1,49 @@
+package worker
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+)
+
+// maxHeldLogBytes is the most of a datum's logs that a worker holds in memory
+// while it waits to see if the datum's stats are written (see holdLogs).
+// Later lines are dropped.
+const maxHeldLogBytes = 16 * 1024 * 1024
+
+// statsSampled returns true if the detailed stats of the datum with hash
+// 'tag' are written, given the pipeline's stats sample rate (see
+// pps.PipelineInfo.StatsSampleRate). Datums are sampled by their hash, so
+// that every worker (and every retry) picks the same ones.
+func statsSampled(tag string, rate float64) bool {
+	if rate <= 0 || rate >= 1 {
+		return true
+	}
+	h := sha256.Sum256([]byte(tag))
+	return float64(binary.BigEndian.Uint64(h[:8])) < rate*math.MaxUint64
+}
+
+// heldLogs are the logs of a datum whose stats are only written if it fails
+type heldLogs struct {
+	buf bytes.Buffer
+	// closed is set once the logger is closed, after which no more lines are
+	// held
+	closed bool
+}
+
+// holdLogs makes 'logger' hold the datum's logs in memory, rather than
+// writing them to object storage as they're logged. writeStats writes them
+// if the datum's stats are written.
+func (logger *taggedLogger) holdLogs() {
+	held := &heldLogs{}
+	logger.held = held
+	logger.eg.Go(func() error {
+		for msg := range logger.msgCh {
+			if held.buf.Len()+len(msg) <= maxHeldLogBytes {
+				held.buf.WriteString(msg)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,44 @@
+package worker
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gogo/protobuf/jsonpb"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestStatsSampled(t *testing.T) {
+	// every datum is sampled by default
+	require.True(t, statsSampled("tag", 0))
+	require.True(t, statsSampled("tag", 1))
+
+	sampled := 0
+	for i := 0; i < 10000; i++ {
+		tag := fmt.Sprintf("datum-%d", i)
+		if statsSampled(tag, 0.1) {
+			sampled++
+			// a datum sampled at one rate is sampled at higher rates too
+			require.True(t, statsSampled(tag, 0.5))
+		}
+		require.Equal(t, statsSampled(tag, 0.1), statsSampled(tag, 0.1))
+	}
+	require.True(t, sampled > 800 && sampled < 1200, "sampled %d of 10000 datums", sampled)
+}
+
+func TestHoldLogs(t *testing.T) {
+	logger := &taggedLogger{marshaler: &jsonpb.Marshaler{}, msgCh: make(chan string, logBuffer)}
+	logger.holdLogs()
+	clone := logger.clone()
+	logger.Logf("one")
+	clone.Logf("two")
+	object, _, err := logger.Close()
+	require.NoError(t, err)
+	require.True(t, object == nil)
+	require.Matches(t, "one.*\n.*two.*\n$", logger.held.buf.String())
+	// lines logged after the logger is closed aren't held
+	clone.Logf("three")
+	require.False(t, strings.Contains(logger.held.buf.String(), "three"))
+}
@@ -0,0 +1,274 @@
+package worker
+
+import (
+	"bufio"
+	"bytes"
+	"container/list"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/server/pkg/obj"
+	"golang.org/x/net/context"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// defaultTreeCacheSize is the size of a worker's hashtree cache if
+// WORKER_HASHTREE_CACHE_SIZE isn't set
+const defaultTreeCacheSize = 1 << 30
+
+// treeCacheSize parses the size of a worker's hashtree cache, e.g. "512M", ""
+// is defaultTreeCacheSize
+func treeCacheSize(size string) (int64, error) {
+	if size == "" {
+		return defaultTreeCacheSize, nil
+	}
+	quantity, err := resource.ParseQuantity(size)
+	if err != nil {
+		return 0, fmt.Errorf("invalid hashtree cache size %q: %v", size, err)
+	}
+	return quantity.Value(), nil
+}
+
+// treeCache holds serialized hashtrees (output trees written by merges, and
+// the datum and parent trees that they read) in local files, keyed by the
+// hash of their object. The merge and datum-serving code read trees through
+// it, so that a tree that a worker needs again (e.g. the output tree of the
+// last job, which is the parent tree of the next one) isn't downloaded again,
+// and so that all of them read the same mmapped copy rather than each reading
+// their own copy into memory.
+//
+// Trees that aren't in use are evicted, least recently used first, once the
+// cache holds more than its budget. Trees that are in use are never evicted,
+// so the cache may briefly exceed its budget.
+type treeCache struct {
+	dir      string
+	budget   int64
+	pipeline string
+
+	mu   sync.Mutex
+	size int64
+	// lru holds the cache's *treeEntry, most recently used first
+	lru     *list.List
+	entries map[string]*list.Element
+}
+
+type treeEntry struct {
+	key  string
+	path string
+	data []byte
+	refs int
+}
+
+func newTreeCache(dir string, budget int64, pipeline string) *treeCache {
+	return &treeCache{
+		dir:      dir,
+		budget:   budget,
+		pipeline: pipeline,
+		lru:      list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// get returns the tree with hash 'key', calling 'fetch' to write it to the
+// cache if it isn't there. The tree stays in the cache until the returned
+// treeRef is closed.
+func (c *treeCache) get(key string, fetch func(w io.Writer) error) (*treeRef, error) {
+	if ref := c.lookup(key); ref != nil {
+		treeCacheHits.WithLabelValues(c.pipeline).Inc()
+		return ref, nil
+	}
+	treeCacheMisses.WithLabelValues(c.pipeline).Inc()
+	w, err := c.create()
+	if err != nil {
+		return nil, err
+	}
+	if err := fetch(w); err != nil {
+		w.abort()
+		return nil, err
+	}
+	return w.commit(key)
+}
+
+func (c *treeCache) lookup(key string) *treeRef {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	c.lru.MoveToFront(elem)
+	entry := elem.Value.(*treeEntry)
+	entry.refs++
+	return &treeRef{c: c, entry: entry}
+}
+
+// create returns a writer for a tree whose hash isn't known until it's
+// written, e.g. the output of a merge. The tree is added to the cache by
+// committing the writer.
+func (c *treeCache) create() (*treeWriter, error) {
+	f, err := ioutil.TempFile(c.dir, "tree")
+	if err != nil {
+		return nil, err
+	}
+	return &treeWriter{c: c, f: f, w: bufio.NewWriter(f)}, nil
+}
+
+// add adds a mapped tree to the cache, unless another copy was added while
+// it was being written, then evicts trees if the cache is over budget
+func (c *treeCache) add(entry *treeEntry) *treeRef {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[entry.key]; ok {
+		removeTree(entry)
+		c.lru.MoveToFront(elem)
+		entry = elem.Value.(*treeEntry)
+		entry.refs++
+		return &treeRef{c: c, entry: entry}
+	}
+	entry.refs = 1
+	c.entries[entry.key] = c.lru.PushFront(entry)
+	c.size += int64(len(entry.data))
+	c.evict()
+	return &treeRef{c: c, entry: entry}
+}
+
+func (c *treeCache) release(entry *treeEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry.refs--
+	c.evict()
+}
+
+// evict removes the least recently used trees that aren't in use until the
+// cache is within its budget. c.mu must be held.
+func (c *treeCache) evict() {
+	for elem := c.lru.Back(); elem != nil && c.size > c.budget; {
+		prev := elem.Prev()
+		if entry := elem.Value.(*treeEntry); entry.refs == 0 {
+			c.lru.Remove(elem)
+			delete(c.entries, entry.key)
+			c.size -= int64(len(entry.data))
+			removeTree(entry)
+			treeCacheEvictions.WithLabelValues(c.pipeline).Inc()
+		}
+		elem = prev
+	}
+	treeCacheBytes.WithLabelValues(c.pipeline).Set(float64(c.size))
+}
+
+// removeTree unmaps and deletes the file of a tree that isn't in use
+func removeTree(entry *treeEntry) {
+	unmapTree(entry.data)
+	os.Remove(entry.path)
+}
+
+// treeRef is a tree in a treeCache that's in use
+type treeRef struct {
+	c     *treeCache
+	entry *treeEntry
+	once  sync.Once
+}
+
+// Reader returns a reader of the serialized tree, which may only be used
+// until the treeRef is closed
+func (r *treeRef) Reader() io.Reader {
+	return bytes.NewReader(r.entry.data)
+}
+
+// Close releases the tree, after which it may be evicted
+func (r *treeRef) Close() error {
+	r.once.Do(func() { r.c.release(r.entry) })
+	return nil
+}
+
+// treeWriter writes a tree to a treeCache
+type treeWriter struct {
+	c *treeCache
+	f *os.File
+	w *bufio.Writer
+}
+
+func (w *treeWriter) Write(p []byte) (int, error) {
+	return w.w.Write(p)
+}
+
+// commit adds the written tree to the cache under 'key', and returns it in
+// use
+func (w *treeWriter) commit(key string) (_ *treeRef, retErr error) {
+	defer func() {
+		if retErr != nil {
+			w.abort()
+		}
+	}()
+	if err := w.w.Flush(); err != nil {
+		return nil, err
+	}
+	info, err := w.f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	data, err := mapTree(w.f, info.Size())
+	if err != nil {
+		return nil, err
+	}
+	if err := w.f.Close(); err != nil {
+		unmapTree(data)
+		return nil, err
+	}
+	return w.c.add(&treeEntry{key: key, path: w.f.Name(), data: data}), nil
+}
+
+// abort discards the written tree
+func (w *treeWriter) abort() {
+	w.f.Close()
+	os.Remove(w.f.Name())
+}
+
+// getCachedTree returns the tree described by 'info' from the tree cache,
+// downloading it from object storage if it isn't there. Compressed datum
+// trees are decompressed as they're downloaded, so the cache only holds
+// uncompressed trees.
+func (a *APIServer) getCachedTree(ctx context.Context, objClient obj.Client, info *pfs.ObjectInfo) (*treeRef, error) {
+	return a.treeCache.get(info.Object.Hash, func(w io.Writer) (retErr error) {
+		path, err := obj.BlockPathFromEnv(info.BlockRef.Block)
+		if err != nil {
+			return err
+		}
+		objR, err := objClient.Reader(ctx, path, 0, 0)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := objR.Close(); err != nil && retErr == nil {
+				retErr = err
+			}
+		}()
+		return copyDatumTree(w, objR)
+	})
+}
+
+// getTaggedTree returns the tree tagged with 'tag' from the tree cache,
+// downloading it through pachd if it isn't there
+func (a *APIServer) getTaggedTree(pachClient *client.APIClient, tag string) (*treeRef, error) {
+	info, err := pachClient.InspectTag(pachClient.Ctx(), client.NewTag(tag))
+	if err != nil {
+		return nil, err
+	}
+	return a.treeCache.get(info.Object.Hash, func(w io.Writer) (retErr error) {
+		r, err := pachClient.GetObjectReader(info.Object.Hash)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := r.Close(); err != nil && retErr == nil {
+				retErr = err
+			}
+		}()
+		return copyDatumTree(w, r)
+	})
+}
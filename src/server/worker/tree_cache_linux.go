@@ -0,0 +1,22 @@
+package worker
+
+import (
+	"os"
+	"syscall"
+)
+
+// mapTree maps the first 'size' bytes of 'f' into memory read-only, so that
+// the tree is paged in from the file as it's read and its pages can be
+// reclaimed by the kernel rather than counting against the worker's heap
+func mapTree(f *os.File, size int64) ([]byte, error) {
+	if size == 0 {
+		return nil, nil
+	}
+	return syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+}
+
+func unmapTree(data []byte) {
+	if data != nil {
+		syscall.Munmap(data)
+	}
+}
@@ -0,0 +1,19 @@
+// +build !linux
+
+package worker
+
+import (
+	"os"
+)
+
+// mapTree reads the first 'size' bytes of 'f' into memory. Workers run on
+// Linux, where trees are mmapped, this is only used in tests.
+func mapTree(f *os.File, size int64) ([]byte, error) {
+	data := make([]byte, size)
+	if _, err := f.ReadAt(data, 0); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func unmapTree(data []byte) {}
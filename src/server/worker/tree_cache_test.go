@@ -0,0 +1,129 @@
+package worker
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestTreeCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tree")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	c := newTreeCache(dir, 200, "pipeline")
+	fetches := 0
+	get := func(key string) *treeRef {
+		ref, err := c.get(key, func(w io.Writer) error {
+			fetches++
+			_, err := w.Write(bytes.Repeat([]byte(key), 100))
+			return err
+		})
+		require.NoError(t, err)
+		return ref
+	}
+	read := func(ref *treeRef) string {
+		data, err := ioutil.ReadAll(ref.Reader())
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	a := get("a")
+	require.Equal(t, string(bytes.Repeat([]byte("a"), 100)), read(a))
+	require.NoError(t, a.Close())
+	require.NoError(t, a.Close()) // closing twice only releases once
+	b := get("b")
+	require.NoError(t, b.Close())
+	// "a" is cached
+	require.NoError(t, get("a").Close())
+	require.Equal(t, 2, fetches)
+
+	// "b" is the least recently used, so it's evicted to make room for "c"
+	c1 := get("c")
+	require.Equal(t, 3, fetches)
+	require.NoError(t, get("b").Close())
+	require.Equal(t, 4, fetches)
+
+	// "c" is now the least recently used, but it's in use, so "b" is evicted
+	// instead
+	require.NoError(t, get("d").Close())
+	require.Equal(t, 5, fetches)
+	require.Equal(t, string(bytes.Repeat([]byte("c"), 100)), read(c1))
+	require.NoError(t, get("c").Close())
+	require.Equal(t, 5, fetches)
+	require.NoError(t, c1.Close())
+	require.Equal(t, int64(200), c.size)
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(files))
+
+	// failed fetches aren't cached
+	_, err = c.get("e", func(w io.Writer) error {
+		w.Write([]byte("partial"))
+		return errors.New("failed")
+	})
+	require.YesError(t, err)
+	files, err = ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(files))
+}
+
+func TestTreeCacheCommit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tree")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	c := newTreeCache(dir, 1000, "pipeline")
+
+	// a tree written before its hash is known is read back under that hash
+	w, err := c.create()
+	require.NoError(t, err)
+	_, err = w.Write([]byte("merged"))
+	require.NoError(t, err)
+	ref, err := w.commit("hash")
+	require.NoError(t, err)
+	require.NoError(t, ref.Close())
+	ref, err = c.get("hash", func(w io.Writer) error {
+		return errors.New("shouldn't be fetched")
+	})
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(ref.Reader())
+	require.NoError(t, err)
+	require.Equal(t, "merged", string(data))
+
+	// a second copy of a tree that's already cached is discarded
+	w, err = c.create()
+	require.NoError(t, err)
+	_, err = w.Write([]byte("merged"))
+	require.NoError(t, err)
+	ref2, err := w.commit("hash")
+	require.NoError(t, err)
+	require.Equal(t, ref.entry, ref2.entry)
+	require.NoError(t, ref.Close())
+	require.NoError(t, ref2.Close())
+	require.Equal(t, int64(len("merged")), c.size)
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(files))
+
+	// empty trees can be cached
+	w, err = c.create()
+	require.NoError(t, err)
+	ref, err = w.commit("empty")
+	require.NoError(t, err)
+	data, err = ioutil.ReadAll(ref.Reader())
+	require.NoError(t, err)
+	require.Equal(t, 0, len(data))
+	require.NoError(t, ref.Close())
+
+	_, err = treeCacheSize("512M")
+	require.NoError(t, err)
+	size, err := treeCacheSize("")
+	require.NoError(t, err)
+	require.Equal(t, int64(defaultTreeCacheSize), size)
+	_, err = treeCacheSize("lots")
+	require.YesError(t, err)
+}
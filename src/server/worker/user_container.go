@@ -0,0 +1,387 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	osexec "os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/backoff"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// In pipelines with separate_container set, the user code doesn't run in the
+// worker's container. The user code container runs ServeUserCode (from the
+// worker binary that the init container copies into /pach-bin), and the
+// worker sends it each command to run over client.PPSWorkerExecSocket.
+//
+// The protocol is a stream of JSON values: the worker sends one execRequest
+// per connection, and the user code container replies with execResponses
+// carrying the command's output, the last of which has Done set. The worker
+// cancels the command by closing the connection.
+
+// execRequest is a command that the worker asks the user code container to
+// run
+type execRequest struct {
+	Args  []string `json:"args"`
+	Stdin []string `json:"stdin,omitempty"`
+	Env   []string `json:"env"`
+	// InheritEnv is set if the command gets the environment of the user's
+	// image as well as Env, i.e. if the transform doesn't set scrub_env
+	InheritEnv bool   `json:"inherit_env"`
+	Dir        string `json:"dir,omitempty"`
+}
+
+// execResponse is a chunk of a command's output or, if Done is set, its
+// result
+type execResponse struct {
+	Stdout []byte `json:"stdout,omitempty"`
+	Stderr []byte `json:"stderr,omitempty"`
+	Done   bool   `json:"done,omitempty"`
+	// Error is set if the command couldn't be run
+	Error string `json:"error,omitempty"`
+	// ExitError is set if the command exited with a nonzero status or was
+	// killed, in which case ExitCode and Failure are set too
+	ExitError string          `json:"exit_error,omitempty"`
+	ExitCode  int             `json:"exit_code,omitempty"`
+	Failure   pps.FailureType `json:"failure,omitempty"`
+}
+
+// err returns the error that the command that 'r' is the result of failed
+// with, if any
+func (r *execResponse) err() error {
+	switch {
+	case r.Error != "":
+		return errors.New(r.Error)
+	case r.ExitError != "":
+		return fmt.Errorf("error cmd.WaitIO: %s", r.ExitError)
+	}
+	return nil
+}
+
+// ServeUserCode accepts commands from the worker on 'socketPath' and runs
+// them as 'userArg' (a Dockerfile USER argument, i.e. transform.user), or as
+// the container's user if it's empty. It's run by the user code container of
+// pipelines with separate_container set, and only returns if it fails.
+func ServeUserCode(socketPath string, userArg string) error {
+	s := &userCodeServer{}
+	if userArg != "" {
+		// The user is looked up here, rather than by the worker, as it's
+		// in the user's image
+		user, err := lookupDockerUser(userArg)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if user != nil {
+			uid, err := strconv.ParseUint(user.Uid, 10, 32)
+			if err != nil {
+				return err
+			}
+			gid, err := strconv.ParseUint(user.Gid, 10, 32)
+			if err != nil {
+				return err
+			}
+			uid32, gid32 := uint32(uid), uint32(gid)
+			s.uid, s.gid = &uid32, &gid32
+		}
+	}
+	// the socket may be left over from a previous run of the container
+	if err := os.Remove(socketPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+	// Only this container's user (and root) may connect, so that the user
+	// code, if it runs as a different user, can't run commands through the
+	// socket. The worker's container runs as root or, if the pod sets a
+	// user, as the same user as this container.
+	if err := os.Chmod(socketPath, 0700); err != nil {
+		return err
+	}
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		go s.serveExec(conn)
+	}
+}
+
+// userCodeServer runs the commands that the worker sends to the user code
+// container
+type userCodeServer struct {
+	// The user and group that commands run as, or nil to run them as the
+	// container's user
+	uid *uint32
+	gid *uint32
+}
+
+// serveExec runs the command that the worker sends on 'conn'
+func (s *userCodeServer) serveExec(conn net.Conn) {
+	defer conn.Close()
+	req := &execRequest{}
+	if err := json.NewDecoder(conn).Decode(req); err != nil {
+		log.Errorf("error reading command from the worker: %v", err)
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		// the worker doesn't send anything else, so this only returns once it
+		// closes the connection, which cancels the command
+		io.Copy(ioutil.Discard, conn)
+		cancel()
+	}()
+	w := &execWriter{encoder: json.NewEncoder(conn)}
+	resp := s.runExec(ctx, req, w.stream(false), w.stream(true))
+	resp.Done = true
+	if err := w.write(resp); err != nil {
+		log.Errorf("error sending the result of %v to the worker: %v", req.Args, err)
+	}
+}
+
+// runExec runs 'req', writing its stdout and stderr to 'stdout' and 'stderr',
+// and returns its result
+func (s *userCodeServer) runExec(ctx context.Context, req *execRequest, stdout, stderr io.Writer) *execResponse {
+	if len(req.Args) == 0 {
+		return &execResponse{Error: "no command to run"}
+	}
+	// The standard os/exec is used rather than pkg/exec, as the latter doesn't
+	// wait for a failed command's output to be copied, and the worker stops
+	// reading the output once it gets the result
+	cmd := osexec.CommandContext(ctx, req.Args[0], req.Args[1:]...)
+	if req.Stdin != nil {
+		cmd.Stdin = strings.NewReader(strings.Join(req.Stdin, "\n") + "\n")
+	}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.Env = execEnv(os.Environ(), req.Env, req.InheritEnv)
+	if s.uid != nil && s.gid != nil {
+		cmd.SysProcAttr = makeCmdCredentials(*s.uid, *s.gid)
+	}
+	cmd.Dir = req.Dir
+	// The OOM killer is counted here, as the user code's memory limit is this
+	// container's
+	oomKillsBefore, oomKnown := oomKills()
+	if err := cmd.Start(); err != nil {
+		return &execResponse{Error: fmt.Sprintf("error cmd.Start: %v", err)}
+	}
+	// See execUserCode for why broken pipe errors are ignored
+	err := cmd.Wait()
+	if err != nil && !strings.Contains(err.Error(), "broken pipe") {
+		if exiterr, ok := err.(*osexec.ExitError); ok {
+			if status, ok := exiterr.Sys().(syscall.WaitStatus); ok {
+				oomKillsAfter, ok := oomKills()
+				return &execResponse{
+					ExitError: err.Error(),
+					ExitCode:  status.ExitStatus(),
+					Failure:   exitFailureType(status, oomKillsAfter > oomKillsBefore, oomKnown && ok),
+				}
+			}
+		}
+		return &execResponse{Error: fmt.Sprintf("error cmd.Wait: %v", err)}
+	}
+	return &execResponse{}
+}
+
+// execEnv returns the environment of a command run in the user code
+// container: the worker's environment for the user code, 'requested', on top
+// of the container's own environment, 'own', which comes from the user's
+// image. PATH and HOME are only taken from 'requested' if the image doesn't
+// set them, as the worker's come from Pachyderm's worker image. If 'inherit'
+// is false, nothing else is taken from the image.
+func execEnv(own []string, requested []string, inherit bool) []string {
+	var result []string
+	index := make(map[string]int)
+	fromImage := make(map[string]bool)
+	set := func(kv string) {
+		name := strings.SplitN(kv, "=", 2)[0]
+		if i, ok := index[name]; ok {
+			result[i] = kv
+			return
+		}
+		index[name] = len(result)
+		result = append(result, kv)
+	}
+	for _, kv := range own {
+		name := strings.SplitN(kv, "=", 2)[0]
+		if inherit || name == "PATH" || name == "HOME" {
+			set(kv)
+			fromImage[name] = true
+		}
+	}
+	for _, kv := range requested {
+		name := strings.SplitN(kv, "=", 2)[0]
+		if (name == "PATH" || name == "HOME") && fromImage[name] {
+			continue
+		}
+		set(kv)
+	}
+	return result
+}
+
+// execWriter sends a command's output to the worker. stdout and stderr are
+// written concurrently, so writes are serialized.
+type execWriter struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+func (w *execWriter) write(resp *execResponse) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.encoder.Encode(resp)
+}
+
+func (w *execWriter) stream(stderr bool) io.Writer {
+	return writerFunc(func(p []byte) (int, error) {
+		// p may be reused by the caller once Write returns, but it's encoded
+		// before then
+		resp := &execResponse{Stdout: p}
+		if stderr {
+			resp = &execResponse{Stderr: p}
+		}
+		if err := w.write(resp); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	})
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
+
+// execRequest returns the request that runs 'args' as user code, with 'stdin'
+// and 'environ', in the user code container
+func (a *APIServer) execRequest(args []string, stdin []string, environ []string) *execRequest {
+	return &execRequest{
+		Args:       args,
+		Stdin:      stdin,
+		Env:        environ,
+		InheritEnv: !a.pipelineInfo.Transform.ScrubEnv,
+		Dir:        a.pipelineInfo.Transform.WorkingDir,
+	}
+}
+
+// execInUserContainer runs 'req' in the user code container, writing its
+// output to 'stdout' and 'stderr', and returns its result. Cancelling 'ctx'
+// kills the command.
+func execInUserContainer(ctx context.Context, req *execRequest, stdout, stderr io.Writer) (*execResponse, error) {
+	conn, err := dialUserContainer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return execOnConn(ctx, conn, req, stdout, stderr)
+}
+
+// execOnConn runs 'req' in the user code container that 'conn' is connected
+// to, and closes 'conn'
+func execOnConn(ctx context.Context, conn net.Conn, req *execRequest, stdout, stderr io.Writer) (*execResponse, error) {
+	defer conn.Close()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, fmt.Errorf("error sending command to the user code container: %v", err)
+	}
+	decoder := json.NewDecoder(conn)
+	for {
+		resp := &execResponse{}
+		if err := decoder.Decode(resp); err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, fmt.Errorf("error reading from the user code container: %v", err)
+		}
+		if resp.Done {
+			return resp, nil
+		}
+		if len(resp.Stdout) > 0 {
+			stdout.Write(resp.Stdout)
+		}
+		if len(resp.Stderr) > 0 {
+			stderr.Write(resp.Stderr)
+		}
+	}
+}
+
+// dialUserContainer connects to the user code container. The containers in a
+// pod start at the same time, so it retries until the user code container is
+// listening.
+func dialUserContainer(ctx context.Context) (net.Conn, error) {
+	var conn net.Conn
+	var dialer net.Dialer
+	b := backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = 5 * time.Minute
+	if err := backoff.RetryNotify(func() error {
+		var err error
+		conn, err = dialer.DialContext(ctx, "unix", client.PPSWorkerExecSocket)
+		return err
+	}, b, func(err error, d time.Duration) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("error connecting to the user code container: %v", err)
+	}
+	return conn, nil
+}
+
+// runInUserContainer runs user code in the user code container. It's the
+// separate_container counterpart of running the user code in execUserCode
+// (if 'userCode' is set) and runUserErrorHandlingCode.
+func (a *APIServer) runInUserContainer(ctx context.Context, stdout, stderr io.Writer, args []string, stdin []string, environ []string, userCode bool) error {
+	resp, err := execInUserContainer(ctx, a.execRequest(args, stdin, environ), stdout, stderr)
+	if isDone(ctx) {
+		if err = ctx.Err(); err != nil {
+			if userCode && err == context.DeadlineExceeded {
+				return classify(pps.FailureType_DATUM_TIMEOUT, err)
+			}
+			return err
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if resp.ExitError == "" {
+		return resp.err()
+	}
+	for _, returnCode := range a.pipelineInfo.Transform.AcceptReturnCode {
+		if int(returnCode) == resp.ExitCode {
+			return nil
+		}
+	}
+	if !userCode {
+		return resp.err()
+	}
+	if resp.Failure == pps.FailureType_OOM_KILLED {
+		return classify(resp.Failure, fmt.Errorf("user code was killed by the OOM killer: %v", resp.err()))
+	}
+	return classify(resp.Failure, resp.err())
+}
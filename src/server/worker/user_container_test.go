@@ -0,0 +1,72 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestExecEnv(t *testing.T) {
+	image := []string{"PATH=/opt/conda/bin:/usr/bin", "LANG=C.UTF-8", "A=image"}
+	requested := []string{"PATH=/usr/bin", "HOME=/root", "A=worker", "PPS_POD_NAME=pipeline-v1-abcde"}
+	require.Equal(t, []string{
+		"PATH=/opt/conda/bin:/usr/bin",
+		"LANG=C.UTF-8",
+		"A=worker",
+		"HOME=/root",
+		"PPS_POD_NAME=pipeline-v1-abcde",
+	}, execEnv(image, requested, true))
+	require.Equal(t, []string{
+		"PATH=/opt/conda/bin:/usr/bin",
+		"HOME=/root",
+		"A=worker",
+		"PPS_POD_NAME=pipeline-v1-abcde",
+	}, execEnv(image, requested, false))
+}
+
+func TestExecOnConn(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the user code container only runs on linux")
+	}
+	exec := func(ctx context.Context, req *execRequest) (*execResponse, string, string, error) {
+		worker, userCode := net.Pipe()
+		go (&userCodeServer{}).serveExec(userCode)
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		resp, err := execOnConn(ctx, worker, req, stdout, stderr)
+		return resp, stdout.String(), stderr.String(), err
+	}
+
+	resp, stdout, stderr, err := exec(context.Background(), &execRequest{
+		Args:  []string{"sh", "-c", `read line; echo "$line $FOO"; echo err >&2`},
+		Stdin: []string{"hello"},
+		Env:   []string{"FOO=bar"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, resp.err())
+	require.Equal(t, "hello bar\n", stdout)
+	require.Equal(t, "err\n", stderr)
+
+	// a failed command's output is sent before its result
+	resp, _, stderr, err = exec(context.Background(), &execRequest{Args: []string{"sh", "-c", "echo failed >&2; exit 3"}})
+	require.NoError(t, err)
+	require.YesError(t, resp.err())
+	require.Equal(t, 3, resp.ExitCode)
+	require.Equal(t, "failed\n", stderr)
+
+	resp, _, _, err = exec(context.Background(), &execRequest{Args: []string{"pachyderm-no-such-command"}})
+	require.NoError(t, err)
+	require.NotEqual(t, "", resp.Error)
+
+	// cancelling the command kills it
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, _, _, err = exec(ctx, &execRequest{Args: []string{"sleep", "30"}})
+	require.YesError(t, err)
+	require.True(t, time.Since(start) < 10*time.Second)
+}
@@ -0,0 +1,116 @@
+package worker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/sirupsen/logrus"
+
+	"github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pkg/backoff"
+	"github.com/pachyderm/pachyderm/src/server/pkg/errutil"
+	"github.com/pachyderm/pachyderm/src/server/pkg/watch"
+)
+
+// workerConfig holds the pipeline's worker config (see pps.WorkerConfig),
+// which SetWorkerConfig changes while the worker is running. A nil
+// workerConfig has the default value of every tunable.
+type workerConfig struct {
+	mu     sync.RWMutex
+	config pps.WorkerConfig
+}
+
+func (c *workerConfig) get() pps.WorkerConfig {
+	if c == nil {
+		return pps.WorkerConfig{}
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.config
+}
+
+// set replaces the config, and returns true if it changed
+func (c *workerConfig) set(config *pps.WorkerConfig) bool {
+	if config == nil {
+		config = &pps.WorkerConfig{}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.config.TransferConcurrency == config.TransferConcurrency &&
+		c.config.DatumTimeoutMultiplier == config.DatumTimeoutMultiplier &&
+		c.config.LogLevel == config.LogLevel {
+		return false
+	}
+	c.config = *config
+	return true
+}
+
+// transferConcurrency returns the number of files to download at once
+func (c *workerConfig) transferConcurrency() int {
+	if n := c.get().TransferConcurrency; n > 0 {
+		return int(n)
+	}
+	return concurrency
+}
+
+// scaleDatumTimeout returns 'timeout' scaled by the datum timeout multiplier
+func (c *workerConfig) scaleDatumTimeout(timeout *types.Duration) (*types.Duration, error) {
+	multiplier := c.get().DatumTimeoutMultiplier
+	if timeout == nil || multiplier == 0 || multiplier == 1 {
+		return timeout, nil
+	}
+	d, err := types.DurationFromProto(timeout)
+	if err != nil {
+		return nil, err
+	}
+	return types.DurationProto(time.Duration(float64(d) * multiplier)), nil
+}
+
+// logLevel returns the level of the worker's logs
+func (c *workerConfig) logLevel() logrus.Level {
+	level, err := logrus.ParseLevel(c.get().LogLevel)
+	if err != nil {
+		// unset (PPS validates the level)
+		return logrus.InfoLevel
+	}
+	return level
+}
+
+// debug returns true if the worker's debug logs are enabled
+func (c *workerConfig) debug() bool {
+	return c.logLevel() >= logrus.DebugLevel
+}
+
+// watchWorkerConfig applies the pipeline's worker config whenever
+// SetWorkerConfig changes it, for as long as the worker runs
+func (a *APIServer) watchWorkerConfig() {
+	logger := a.getWorkerLogger()
+	name := a.pipelineInfo.Pipeline.Name
+	backoff.RetryNotify(func() error {
+		return a.pipelines.ReadOnly(a.pachClient.Ctx()).WatchOneF(name, func(e *watch.Event) error {
+			if e.Type == watch.EventError {
+				return e.Err
+			}
+			if e.Type != watch.EventPut {
+				// the pipeline was deleted, and so will this worker be
+				return errutil.ErrBreak
+			}
+			var key string
+			ptr := &pps.EtcdPipelineInfo{}
+			if err := e.Unmarshal(&key, ptr); err != nil {
+				return err
+			}
+			if a.config.set(ptr.WorkerConfig) {
+				logrus.SetLevel(a.config.logLevel())
+				config := a.config.get()
+				logger.Logf("applied worker config: transfer concurrency %d, datum timeout multiplier %v, log level %s",
+					a.config.transferConcurrency(), config.DatumTimeoutMultiplier, a.config.logLevel())
+			}
+			return nil
+		})
+	}, backoff.NewInfiniteBackOff(), func(err error, d time.Duration) error {
+		logger.Logf("error watching the worker config of %q: %v; retrying in %v", name, err, d)
+		return nil
+	})
+}
@@ -0,0 +1,41 @@
+package worker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pps"
+)
+
+func TestWorkerConfig(t *testing.T) {
+	// A nil config (as in tests that don't watch one) has the defaults
+	var c *workerConfig
+	require.Equal(t, concurrency, c.transferConcurrency())
+	require.False(t, c.debug())
+	timeout := types.DurationProto(time.Minute)
+	scaled, err := c.scaleDatumTimeout(timeout)
+	require.NoError(t, err)
+	require.Equal(t, timeout, scaled)
+
+	c = &workerConfig{}
+	require.False(t, c.set(nil))
+	require.True(t, c.set(&pps.WorkerConfig{TransferConcurrency: 10, DatumTimeoutMultiplier: 1.5, LogLevel: "debug"}))
+	require.False(t, c.set(&pps.WorkerConfig{TransferConcurrency: 10, DatumTimeoutMultiplier: 1.5, LogLevel: "debug"}))
+	require.Equal(t, 10, c.transferConcurrency())
+	require.True(t, c.debug())
+	scaled, err = c.scaleDatumTimeout(timeout)
+	require.NoError(t, err)
+	require.Equal(t, types.DurationProto(90*time.Second), scaled)
+	// datums without a timeout still don't have one
+	scaled, err = c.scaleDatumTimeout(nil)
+	require.NoError(t, err)
+	require.True(t, scaled == nil)
+
+	// Setting an empty config restores the defaults
+	require.True(t, c.set(&pps.WorkerConfig{}))
+	require.Equal(t, concurrency, c.transferConcurrency())
+	require.False(t, c.debug())
+}
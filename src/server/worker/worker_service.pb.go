@@ -67,6 +67,8 @@ type Input struct {
 	Branch               string        `protobuf:"bytes,4,opt,name=branch,proto3" json:"branch,omitempty"`
 	GitURL               string        `protobuf:"bytes,6,opt,name=git_url,json=gitUrl,proto3" json:"git_url,omitempty"`
 	EmptyFiles           bool          `protobuf:"varint,7,opt,name=empty_files,json=emptyFiles,proto3" json:"empty_files,omitempty"`
+	ReadAhead            string        `protobuf:"bytes,9,opt,name=read_ahead,json=readAhead,proto3" json:"read_ahead,omitempty"`
+	Mount                bool          `protobuf:"varint,10,opt,name=mount,proto3" json:"mount,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
 	XXX_unrecognized     []byte        `json:"-"`
 	XXX_sizecache        int32         `json:"-"`
@@ -161,6 +163,20 @@ func (m *Input) GetEmptyFiles() bool {
 	return false
 }
 
+func (m *Input) GetReadAhead() string {
+	if m != nil {
+		return m.ReadAhead
+	}
+	return ""
+}
+
+func (m *Input) GetMount() bool {
+	if m != nil {
+		return m.Mount
+	}
+	return false
+}
+
 type CancelRequest struct {
 	JobID                string   `protobuf:"bytes,2,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
 	DataFilters          []string `protobuf:"bytes,1,rep,name=data_filters,json=dataFilters,proto3" json:"data_filters,omitempty"`
@@ -263,6 +279,174 @@ func (m *CancelResponse) GetSuccess() bool {
 	return false
 }
 
+// DriverState is a snapshot of a worker's internal state, which pachd adds
+// to debug dumps
+type DriverState struct {
+	Status        *pps.WorkerStatus `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	ClaimedChunks []*ClaimedChunk   `protobuf:"bytes,2,rep,name=claimed_chunks,json=claimedChunks,proto3" json:"claimed_chunks,omitempty"`
+	// scratch_bytes is the disk space used by the datums that the worker has
+	// downloaded
+	ScratchBytes int64 `protobuf:"varint,3,opt,name=scratch_bytes,json=scratchBytes,proto3" json:"scratch_bytes,omitempty"`
+	// log_tail has the worker's most recent log lines, oldest first
+	LogTail []string `protobuf:"bytes,4,rep,name=log_tail,json=logTail,proto3" json:"log_tail,omitempty"`
+	// goroutines is a dump of the worker's goroutines
+	Goroutines           []byte   `protobuf:"bytes,5,opt,name=goroutines,proto3" json:"goroutines,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DriverState) Reset()         { *m = DriverState{} }
+func (m *DriverState) String() string { return proto.CompactTextString(m) }
+func (*DriverState) ProtoMessage()    {}
+func (*DriverState) Descriptor() ([]byte, []int) {
+	return fileDescriptor_23ff4b5163b7daa7, []int{3}
+}
+func (m *DriverState) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DriverState) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DriverState.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *DriverState) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DriverState.Merge(m, src)
+}
+func (m *DriverState) XXX_Size() int {
+	return m.Size()
+}
+func (m *DriverState) XXX_DiscardUnknown() {
+	xxx_messageInfo_DriverState.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DriverState proto.InternalMessageInfo
+
+func (m *DriverState) GetStatus() *pps.WorkerStatus {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+func (m *DriverState) GetClaimedChunks() []*ClaimedChunk {
+	if m != nil {
+		return m.ClaimedChunks
+	}
+	return nil
+}
+
+func (m *DriverState) GetScratchBytes() int64 {
+	if m != nil {
+		return m.ScratchBytes
+	}
+	return 0
+}
+
+func (m *DriverState) GetLogTail() []string {
+	if m != nil {
+		return m.LogTail
+	}
+	return nil
+}
+
+func (m *DriverState) GetGoroutines() []byte {
+	if m != nil {
+		return m.Goroutines
+	}
+	return nil
+}
+
+// ClaimedChunk is a chunk of a job's datums that a worker has claimed
+type ClaimedChunk struct {
+	JobID string `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	// The chunk's datums are [low, high)
+	Low  int64 `protobuf:"varint,2,opt,name=low,proto3" json:"low,omitempty"`
+	High int64 `protobuf:"varint,3,opt,name=high,proto3" json:"high,omitempty"`
+	// speculative is true if the worker is running a duplicate attempt of a
+	// chunk that another worker is straggling on
+	Speculative          bool             `protobuf:"varint,4,opt,name=speculative,proto3" json:"speculative,omitempty"`
+	Claimed              *types.Timestamp `protobuf:"bytes,5,opt,name=claimed,proto3" json:"claimed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *ClaimedChunk) Reset()         { *m = ClaimedChunk{} }
+func (m *ClaimedChunk) String() string { return proto.CompactTextString(m) }
+func (*ClaimedChunk) ProtoMessage()    {}
+func (*ClaimedChunk) Descriptor() ([]byte, []int) {
+	return fileDescriptor_23ff4b5163b7daa7, []int{4}
+}
+func (m *ClaimedChunk) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ClaimedChunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ClaimedChunk.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ClaimedChunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClaimedChunk.Merge(m, src)
+}
+func (m *ClaimedChunk) XXX_Size() int {
+	return m.Size()
+}
+func (m *ClaimedChunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClaimedChunk.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClaimedChunk proto.InternalMessageInfo
+
+func (m *ClaimedChunk) GetJobID() string {
+	if m != nil {
+		return m.JobID
+	}
+	return ""
+}
+
+func (m *ClaimedChunk) GetLow() int64 {
+	if m != nil {
+		return m.Low
+	}
+	return 0
+}
+
+func (m *ClaimedChunk) GetHigh() int64 {
+	if m != nil {
+		return m.High
+	}
+	return 0
+}
+
+func (m *ClaimedChunk) GetSpeculative() bool {
+	if m != nil {
+		return m.Speculative
+	}
+	return false
+}
+
+func (m *ClaimedChunk) GetClaimed() *types.Timestamp {
+	if m != nil {
+		return m.Claimed
+	}
+	return nil
+}
+
 type GetChunkRequest struct {
 	Id                   int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	Shard                int64    `protobuf:"varint,2,opt,name=shard,proto3" json:"shard,omitempty"`
@@ -276,7 +460,7 @@ func (m *GetChunkRequest) Reset()         { *m = GetChunkRequest{} }
 func (m *GetChunkRequest) String() string { return proto.CompactTextString(m) }
 func (*GetChunkRequest) ProtoMessage()    {}
 func (*GetChunkRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_23ff4b5163b7daa7, []int{3}
+	return fileDescriptor_23ff4b5163b7daa7, []int{5}
 }
 func (m *GetChunkRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -330,18 +514,31 @@ type ChunkState struct {
 	State   State  `protobuf:"varint,1,opt,name=state,proto3,enum=worker.State" json:"state,omitempty"`
 	DatumID string `protobuf:"bytes,2,opt,name=datum_id,json=datumId,proto3" json:"datum_id,omitempty"`
 	// The IP address of the worker who processed this chunk
-	Address              string      `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
-	RecoveredDatums      *pfs.Object `protobuf:"bytes,4,opt,name=recovered_datums,json=recoveredDatums,proto3" json:"recovered_datums,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
-	XXX_unrecognized     []byte      `json:"-"`
-	XXX_sizecache        int32       `json:"-"`
+	Address         string      `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	RecoveredDatums *pfs.Object `protobuf:"bytes,4,opt,name=recovered_datums,json=recoveredDatums,proto3" json:"recovered_datums,omitempty"`
+	// When the worker that claimed this chunk started processing it
+	Started *types.Timestamp `protobuf:"bytes,5,opt,name=started,proto3" json:"started,omitempty"`
+	// How long processing the chunk took (set once it's COMPLETE)
+	ProcessTime *types.Duration `protobuf:"bytes,6,opt,name=process_time,json=processTime,proto3" json:"process_time,omitempty"`
+	// Why the datum in datum_id failed (set if the chunk is FAILED)
+	FailureType pps.FailureType `protobuf:"varint,7,opt,name=failure_type,json=failureType,proto3,enum=pps.FailureType" json:"failure_type,omitempty"`
+	// The tags of the datums at the start of the chunk that are done, in
+	// order. These are journaled (in a chunk's journal, rather than its claim)
+	// as the datums complete, so that a worker that restarts mid-chunk resumes
+	// it at the first unfinished datum.
+	CompletedTags []string `protobuf:"bytes,8,rep,name=completed_tags,json=completedTags,proto3" json:"completed_tags,omitempty"`
+	// How many of the datums in completed_tags were skipped
+	CompletedSkipped     int64    `protobuf:"varint,9,opt,name=completed_skipped,json=completedSkipped,proto3" json:"completed_skipped,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *ChunkState) Reset()         { *m = ChunkState{} }
 func (m *ChunkState) String() string { return proto.CompactTextString(m) }
 func (*ChunkState) ProtoMessage()    {}
 func (*ChunkState) Descriptor() ([]byte, []int) {
-	return fileDescriptor_23ff4b5163b7daa7, []int{4}
+	return fileDescriptor_23ff4b5163b7daa7, []int{6}
 }
 func (m *ChunkState) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -398,6 +595,41 @@ func (m *ChunkState) GetRecoveredDatums() *pfs.Object {
 	return nil
 }
 
+func (m *ChunkState) GetStarted() *types.Timestamp {
+	if m != nil {
+		return m.Started
+	}
+	return nil
+}
+
+func (m *ChunkState) GetProcessTime() *types.Duration {
+	if m != nil {
+		return m.ProcessTime
+	}
+	return nil
+}
+
+func (m *ChunkState) GetFailureType() pps.FailureType {
+	if m != nil {
+		return m.FailureType
+	}
+	return pps.FailureType_FAILURE_UNKNOWN
+}
+
+func (m *ChunkState) GetCompletedTags() []string {
+	if m != nil {
+		return m.CompletedTags
+	}
+	return nil
+}
+
+func (m *ChunkState) GetCompletedSkipped() int64 {
+	if m != nil {
+		return m.CompletedSkipped
+	}
+	return 0
+}
+
 type MergeState struct {
 	State                State       `protobuf:"varint,1,opt,name=state,proto3,enum=worker.State" json:"state,omitempty"`
 	Tree                 *pfs.Object `protobuf:"bytes,2,opt,name=tree,proto3" json:"tree,omitempty"`
@@ -413,7 +645,7 @@ func (m *MergeState) Reset()         { *m = MergeState{} }
 func (m *MergeState) String() string { return proto.CompactTextString(m) }
 func (*MergeState) ProtoMessage()    {}
 func (*MergeState) Descriptor() ([]byte, []int) {
-	return fileDescriptor_23ff4b5163b7daa7, []int{5}
+	return fileDescriptor_23ff4b5163b7daa7, []int{7}
 }
 func (m *MergeState) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -487,7 +719,7 @@ func (m *ShardInfo) Reset()         { *m = ShardInfo{} }
 func (m *ShardInfo) String() string { return proto.CompactTextString(m) }
 func (*ShardInfo) ProtoMessage()    {}
 func (*ShardInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_23ff4b5163b7daa7, []int{6}
+	return fileDescriptor_23ff4b5163b7daa7, []int{8}
 }
 func (m *ShardInfo) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -517,8 +749,14 @@ func (m *ShardInfo) XXX_DiscardUnknown() {
 var xxx_messageInfo_ShardInfo proto.InternalMessageInfo
 
 type Plan struct {
-	Chunks               []int64  `protobuf:"varint,1,rep,packed,name=chunks,proto3" json:"chunks,omitempty"`
-	Merges               int64    `protobuf:"varint,2,opt,name=merges,proto3" json:"merges,omitempty"`
+	Chunks []int64 `protobuf:"varint,1,rep,packed,name=chunks,proto3" json:"chunks,omitempty"`
+	Merges int64   `protobuf:"varint,2,opt,name=merges,proto3" json:"merges,omitempty"`
+	// order, if set, is the order (of indexes into chunks) in which workers
+	// claim the chunks (see pps.CHUNKS_LARGEST_FIRST)
+	Order []int64 `protobuf:"varint,3,rep,packed,name=order,proto3" json:"order,omitempty"`
+	// keys, if set, are the IDs of each chunk's first datum, by which each
+	// worker orders the chunks (see pps.CHUNKS_LOCALITY)
+	Keys                 []string `protobuf:"bytes,4,rep,name=keys,proto3" json:"keys,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -528,7 +766,7 @@ func (m *Plan) Reset()         { *m = Plan{} }
 func (m *Plan) String() string { return proto.CompactTextString(m) }
 func (*Plan) ProtoMessage()    {}
 func (*Plan) Descriptor() ([]byte, []int) {
-	return fileDescriptor_23ff4b5163b7daa7, []int{7}
+	return fileDescriptor_23ff4b5163b7daa7, []int{9}
 }
 func (m *Plan) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -571,11 +809,27 @@ func (m *Plan) GetMerges() int64 {
 	return 0
 }
 
+func (m *Plan) GetOrder() []int64 {
+	if m != nil {
+		return m.Order
+	}
+	return nil
+}
+
+func (m *Plan) GetKeys() []string {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterEnum("worker.State", State_name, State_value)
 	proto.RegisterType((*Input)(nil), "worker.Input")
 	proto.RegisterType((*CancelRequest)(nil), "worker.CancelRequest")
 	proto.RegisterType((*CancelResponse)(nil), "worker.CancelResponse")
+	proto.RegisterType((*DriverState)(nil), "worker.DriverState")
+	proto.RegisterType((*ClaimedChunk)(nil), "worker.ClaimedChunk")
 	proto.RegisterType((*GetChunkRequest)(nil), "worker.GetChunkRequest")
 	proto.RegisterType((*ChunkState)(nil), "worker.ChunkState")
 	proto.RegisterType((*MergeState)(nil), "worker.MergeState")
@@ -586,58 +840,80 @@ func init() {
 func init() { proto.RegisterFile("server/worker/worker_service.proto", fileDescriptor_23ff4b5163b7daa7) }
 
 var fileDescriptor_23ff4b5163b7daa7 = []byte{
-	// 806 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0xcd, 0x6e, 0xdb, 0x46,
-	0x10, 0x16, 0x2d, 0x89, 0x92, 0x46, 0xb6, 0xa3, 0x2e, 0x52, 0x87, 0x70, 0x50, 0x49, 0x65, 0x80,
-	0x42, 0xf0, 0x81, 0x32, 0x5c, 0x34, 0x40, 0x8f, 0x95, 0x65, 0x1b, 0x2a, 0xfc, 0x13, 0xac, 0xed,
-	0x16, 0xe8, 0x85, 0xe0, 0xcf, 0x48, 0xa2, 0x43, 0x71, 0xd9, 0xdd, 0x65, 0x02, 0xe7, 0xdc, 0x87,
-	0xe8, 0x3b, 0xf4, 0x25, 0x7a, 0x6b, 0x8f, 0x7d, 0x02, 0xa3, 0x50, 0x5f, 0xa4, 0xd8, 0x5d, 0x32,
-	0x89, 0xd5, 0x5e, 0x72, 0x20, 0x38, 0xf3, 0xcd, 0xc7, 0x0f, 0x33, 0xb3, 0xdf, 0x12, 0x5c, 0x81,
-	0xfc, 0x0d, 0xf2, 0xf1, 0x5b, 0xc6, 0x5f, 0xbf, 0x7f, 0xf9, 0x0a, 0x4c, 0x22, 0xf4, 0x72, 0xce,
-	0x24, 0x23, 0xb6, 0x41, 0xf7, 0x9f, 0x46, 0x69, 0x82, 0x99, 0x1c, 0xe7, 0x73, 0xa1, 0x1e, 0x53,
-	0xfd, 0x80, 0xe6, 0x42, 0x3d, 0x15, 0xba, 0x60, 0x0b, 0xa6, 0xc3, 0xb1, 0x8a, 0x4a, 0xf4, 0xf9,
-	0x82, 0xb1, 0x45, 0x8a, 0x63, 0x9d, 0x85, 0xc5, 0x7c, 0x8c, 0xab, 0x5c, 0xde, 0x97, 0xc5, 0xfe,
-	0x66, 0xf1, 0x2d, 0x0f, 0xf2, 0x1c, 0x79, 0x29, 0xe9, 0xfe, 0xb2, 0x05, 0xcd, 0x59, 0x96, 0x17,
-	0x92, 0x1c, 0x40, 0x67, 0x9e, 0xa4, 0xe8, 0x27, 0xd9, 0x9c, 0x39, 0xd6, 0xd0, 0x1a, 0x75, 0x8f,
-	0x76, 0x3c, 0xd5, 0xd1, 0x69, 0x92, 0xe2, 0x2c, 0x9b, 0x33, 0xda, 0x9e, 0x97, 0x11, 0x39, 0x84,
-	0x9d, 0x3c, 0xe0, 0x98, 0x49, 0x3f, 0x62, 0xab, 0x55, 0x22, 0x9d, 0xa6, 0xe6, 0x77, 0x35, 0xff,
-	0x58, 0x43, 0x74, 0xdb, 0x30, 0x4c, 0x46, 0x08, 0x34, 0xb2, 0x60, 0x85, 0xce, 0xd6, 0xd0, 0x1a,
-	0x75, 0xa8, 0x8e, 0xc9, 0x33, 0x68, 0xdd, 0xb1, 0x24, 0xf3, 0x59, 0xe6, 0xb4, 0x35, 0x6c, 0xab,
-	0xf4, 0x2a, 0x53, 0xe4, 0x34, 0x78, 0x77, 0xef, 0xd4, 0x87, 0xd6, 0xa8, 0x4d, 0x75, 0x4c, 0xf6,
-	0xc0, 0x0e, 0x79, 0x90, 0x45, 0x4b, 0xa7, 0x61, 0xb8, 0x26, 0x23, 0x2f, 0xa0, 0xb5, 0x48, 0xa4,
-	0x5f, 0xf0, 0xd4, 0xb1, 0x55, 0x61, 0x02, 0xeb, 0x87, 0x81, 0x7d, 0x96, 0xc8, 0x5b, 0x7a, 0x4e,
-	0xed, 0x45, 0x22, 0x6f, 0x79, 0x4a, 0x06, 0xd0, 0xd5, 0x4b, 0xf1, 0xd5, 0x04, 0xc2, 0x69, 0x69,
-	0x5d, 0xd0, 0x90, 0x9a, 0x4e, 0xb8, 0x37, 0xb0, 0x73, 0x1c, 0x64, 0x11, 0xa6, 0x14, 0x7f, 0x2e,
-	0x50, 0x48, 0x32, 0x04, 0xfb, 0x8e, 0x85, 0x7e, 0x12, 0x9b, 0x8e, 0x27, 0x9d, 0xf5, 0xc3, 0xa0,
-	0xf9, 0x3d, 0x0b, 0x67, 0x53, 0xda, 0xbc, 0x63, 0xe1, 0x2c, 0x26, 0x5f, 0xc2, 0x76, 0x1c, 0xc8,
-	0x40, 0x49, 0x4a, 0xe4, 0xc2, 0xb1, 0x86, 0xf5, 0x51, 0x87, 0x76, 0x15, 0x76, 0x6a, 0x20, 0xf7,
-	0x00, 0x76, 0x2b, 0x55, 0x91, 0xb3, 0x4c, 0x20, 0x71, 0xa0, 0x25, 0x8a, 0x28, 0x42, 0x21, 0xf4,
-	0x8a, 0xdb, 0xb4, 0x4a, 0xdd, 0x0b, 0x78, 0x72, 0x86, 0xf2, 0x78, 0x59, 0x64, 0xaf, 0xab, 0x1e,
-	0x76, 0x61, 0x2b, 0x89, 0x35, 0xaf, 0x4e, 0xb7, 0x92, 0x98, 0x3c, 0x85, 0xa6, 0x58, 0x06, 0xdc,
-	0xb4, 0x54, 0xa7, 0x26, 0xd1, 0xa8, 0x0c, 0xa4, 0x28, 0xb7, 0x65, 0x12, 0xf7, 0x37, 0x0b, 0x40,
-	0x8b, 0x5d, 0xcb, 0x40, 0x22, 0x79, 0x61, 0x48, 0xa8, 0xd5, 0x76, 0x8f, 0x76, 0x3c, 0xe3, 0x3e,
-	0x4f, 0x57, 0xcd, 0x37, 0x48, 0xbe, 0x82, 0x76, 0x1c, 0xc8, 0x62, 0xf5, 0x61, 0xea, 0xee, 0xfa,
-	0x61, 0xd0, 0x9a, 0x2a, 0x6c, 0x36, 0xa5, 0x2d, 0x5d, 0x9c, 0xc5, 0x6a, 0x88, 0x20, 0x8e, 0xb9,
-	0x1a, 0xa2, 0xae, 0xcf, 0xa2, 0x4a, 0xc9, 0x4b, 0xe8, 0x71, 0x8c, 0xd8, 0x1b, 0xe4, 0x18, 0xfb,
-	0x9a, 0x2e, 0xf4, 0x71, 0x55, 0xd6, 0xb8, 0x0a, 0xef, 0x30, 0x92, 0xf4, 0xc9, 0x7b, 0x92, 0xd6,
-	0x16, 0xee, 0x1f, 0x16, 0xc0, 0x05, 0xf2, 0x05, 0x7e, 0x42, 0xb7, 0x03, 0x68, 0x48, 0x8e, 0xc6,
-	0x51, 0x1b, 0xfa, 0xba, 0x40, 0xbe, 0x00, 0x10, 0xc9, 0x3b, 0xf4, 0xc3, 0x7b, 0x89, 0xa6, 0xd3,
-	0x06, 0xed, 0x28, 0x64, 0xa2, 0x00, 0x72, 0x00, 0xa0, 0x57, 0xe5, 0x6b, 0x95, 0xff, 0xe9, 0xb2,
-	0xa3, 0xcb, 0x37, 0x4a, 0x6a, 0x04, 0x3d, 0xc3, 0xfd, 0x48, 0xb0, 0xa9, 0x05, 0x77, 0x35, 0x7e,
-	0x5d, 0xa9, 0xba, 0x5d, 0xe8, 0x5c, 0xab, 0x63, 0x51, 0xd7, 0xc4, 0x7d, 0x09, 0x8d, 0x57, 0x69,
-	0x90, 0x29, 0xef, 0x46, 0xea, 0x2c, 0x8c, 0x49, 0xea, 0xb4, 0xcc, 0x14, 0xbe, 0x52, 0x53, 0x8b,
-	0xf2, 0x44, 0xcb, 0xec, 0xc0, 0x83, 0xa6, 0x59, 0x44, 0x17, 0x5a, 0xf4, 0xf6, 0xf2, 0x72, 0x76,
-	0x79, 0xd6, 0xab, 0x91, 0x6d, 0x68, 0x1f, 0x5f, 0x5d, 0xbc, 0x3a, 0x3f, 0xb9, 0x39, 0xe9, 0x59,
-	0x04, 0xc0, 0x3e, 0xfd, 0x6e, 0x76, 0x7e, 0x32, 0xed, 0xd5, 0x8f, 0x7e, 0xb7, 0xc0, 0xfe, 0x51,
-	0xaf, 0x88, 0x7c, 0x03, 0xb6, 0xfa, 0xb4, 0x10, 0x64, 0xcf, 0x33, 0x57, 0xdf, 0xab, 0xae, 0xbe,
-	0x77, 0xa2, 0xfc, 0xbe, 0xff, 0x99, 0xa7, 0x7e, 0x28, 0x86, 0x6e, 0xa8, 0x6e, 0x8d, 0x7c, 0x0b,
-	0xb6, 0x71, 0x2a, 0xf9, 0xbc, 0x5a, 0xf6, 0xa3, 0xfb, 0xb0, 0xbf, 0xb7, 0x09, 0x1b, 0x43, 0xbb,
-	0x35, 0x32, 0x85, 0x76, 0x65, 0x5c, 0xf2, 0xac, 0x62, 0x6d, 0x58, 0x79, 0xff, 0xf9, 0x7f, 0x9a,
-	0xd1, 0xeb, 0xfa, 0x21, 0x48, 0x0b, 0x74, 0x6b, 0x87, 0xd6, 0x64, 0xf2, 0xe7, 0xba, 0x6f, 0xfd,
-	0xb5, 0xee, 0x5b, 0x7f, 0xaf, 0xfb, 0xd6, 0xaf, 0xff, 0xf4, 0x6b, 0x3f, 0x1d, 0x2e, 0x12, 0xb9,
-	0x2c, 0x42, 0x2f, 0x62, 0xab, 0x71, 0x1e, 0x44, 0xcb, 0xfb, 0x18, 0xf9, 0xc7, 0x91, 0xe0, 0xd1,
-	0xf8, 0xd1, 0x7f, 0x36, 0xb4, 0xb5, 0xf8, 0xd7, 0xff, 0x06, 0x00, 0x00, 0xff, 0xff, 0xaf, 0x47,
-	0x0d, 0x52, 0x7f, 0x05, 0x00, 0x00,
+	// 1166 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x56, 0xdd, 0x6e, 0x1b, 0x45,
+	0x14, 0xce, 0xc6, 0xf6, 0xda, 0x3e, 0x6b, 0xbb, 0xee, 0x50, 0xda, 0x6d, 0x2a, 0x12, 0xb3, 0x15,
+	0x28, 0x04, 0xc9, 0xae, 0x52, 0x40, 0x42, 0xf4, 0xa6, 0x89, 0x93, 0xca, 0xa8, 0x7f, 0x9a, 0xb8,
+	0x20, 0x71, 0xb3, 0x8c, 0x77, 0xc7, 0xeb, 0x49, 0xd6, 0x3b, 0xcb, 0xcc, 0x6c, 0x2a, 0xf7, 0x49,
+	0x78, 0x04, 0xae, 0xb8, 0xe7, 0x09, 0xe0, 0x92, 0x1b, 0x6e, 0x2b, 0x14, 0xde, 0x03, 0xa1, 0x99,
+	0x59, 0x27, 0x6e, 0x42, 0x24, 0xb8, 0xb0, 0x32, 0xe7, 0x3b, 0xdf, 0x9e, 0x99, 0x39, 0xf3, 0x9d,
+	0x4f, 0x81, 0x40, 0x52, 0x71, 0x4a, 0xc5, 0xe0, 0x35, 0x17, 0x27, 0xe7, 0x7f, 0x42, 0x0d, 0xb2,
+	0x88, 0xf6, 0x73, 0xc1, 0x15, 0x47, 0xae, 0x45, 0x37, 0x6e, 0x45, 0x29, 0xa3, 0x99, 0x1a, 0xe4,
+	0x53, 0xa9, 0x7f, 0x36, 0x7b, 0x81, 0xe6, 0x52, 0xff, 0x96, 0x68, 0xc2, 0x13, 0x6e, 0x96, 0x03,
+	0xbd, 0x2a, 0xd1, 0xcd, 0x84, 0xf3, 0x24, 0xa5, 0x03, 0x13, 0x4d, 0x8a, 0xe9, 0x20, 0x2e, 0x04,
+	0x51, 0x8c, 0x67, 0x65, 0xfe, 0xde, 0xe5, 0x3c, 0x9d, 0xe7, 0x6a, 0x51, 0x26, 0xb7, 0x2e, 0x27,
+	0x15, 0x9b, 0x53, 0xa9, 0xc8, 0x3c, 0xbf, 0xae, 0xfa, 0x6b, 0x41, 0xf2, 0x9c, 0x8a, 0xf2, 0x4c,
+	0xc1, 0x2f, 0xeb, 0x50, 0x1b, 0x65, 0x79, 0xa1, 0xd0, 0x0e, 0x34, 0xa7, 0x2c, 0xa5, 0x21, 0xcb,
+	0xa6, 0xdc, 0x77, 0x7a, 0xce, 0xb6, 0xb7, 0xdb, 0xee, 0xeb, 0x2b, 0x1d, 0xb2, 0x94, 0x8e, 0xb2,
+	0x29, 0xc7, 0x8d, 0x69, 0xb9, 0x42, 0x0f, 0xa0, 0x9d, 0x13, 0x41, 0x33, 0x15, 0x46, 0x7c, 0x3e,
+	0x67, 0xca, 0xaf, 0x19, 0xbe, 0x67, 0xf8, 0xfb, 0x06, 0xc2, 0x2d, 0xcb, 0xb0, 0x11, 0x42, 0x50,
+	0xcd, 0xc8, 0x9c, 0xfa, 0xeb, 0x3d, 0x67, 0xbb, 0x89, 0xcd, 0x1a, 0xdd, 0x81, 0xfa, 0x31, 0x67,
+	0x59, 0xc8, 0x33, 0xbf, 0x61, 0x60, 0x57, 0x87, 0x2f, 0x32, 0x4d, 0x4e, 0xc9, 0x9b, 0x85, 0x5f,
+	0xe9, 0x39, 0xdb, 0x0d, 0x6c, 0xd6, 0xe8, 0x36, 0xb8, 0x13, 0x41, 0xb2, 0x68, 0xe6, 0x57, 0x2d,
+	0xd7, 0x46, 0xe8, 0x3e, 0xd4, 0x13, 0xa6, 0xc2, 0x42, 0xa4, 0xbe, 0xab, 0x13, 0x7b, 0x70, 0xf6,
+	0x76, 0xcb, 0x7d, 0xc2, 0xd4, 0x2b, 0xfc, 0x14, 0xbb, 0x09, 0x53, 0xaf, 0x44, 0x8a, 0xb6, 0xc0,
+	0x33, 0x5d, 0x0b, 0xf5, 0x0d, 0xa4, 0x5f, 0x37, 0x75, 0xc1, 0x40, 0xfa, 0x76, 0x12, 0x7d, 0x00,
+	0x20, 0x28, 0x89, 0x43, 0x32, 0xa3, 0x24, 0xf6, 0x9b, 0x66, 0x87, 0xa6, 0x46, 0x1e, 0x6b, 0x00,
+	0xdd, 0x82, 0xda, 0x9c, 0x17, 0x99, 0xf2, 0xc1, 0x7c, 0x69, 0x83, 0x60, 0x0c, 0xed, 0x7d, 0x92,
+	0x45, 0x34, 0xc5, 0xf4, 0x87, 0x82, 0x4a, 0x85, 0x7a, 0xe0, 0x1e, 0xf3, 0x49, 0xc8, 0x62, 0x7b,
+	0xcd, 0xbd, 0xe6, 0xd9, 0xdb, 0xad, 0xda, 0xd7, 0x7c, 0x32, 0x1a, 0xe2, 0xda, 0x31, 0x9f, 0x8c,
+	0x62, 0xf4, 0x21, 0xb4, 0x62, 0xa2, 0x88, 0x3e, 0x87, 0xa2, 0x42, 0xfa, 0x4e, 0xaf, 0xb2, 0xdd,
+	0xc4, 0x9e, 0xc6, 0x0e, 0x2d, 0x14, 0xec, 0x40, 0x67, 0x59, 0x55, 0xe6, 0x3c, 0x93, 0x14, 0xf9,
+	0x50, 0x97, 0x45, 0x14, 0x51, 0x29, 0xcd, 0xbb, 0x34, 0xf0, 0x32, 0x0c, 0xfe, 0x70, 0xc0, 0x1b,
+	0x0a, 0x76, 0x4a, 0xc5, 0x91, 0x22, 0x8a, 0xa2, 0x4f, 0xc0, 0x95, 0x8a, 0xa8, 0x42, 0x96, 0x0f,
+	0x78, 0xb3, 0xaf, 0xd5, 0xf7, 0xad, 0x91, 0xea, 0x91, 0x49, 0xe0, 0x92, 0x80, 0xbe, 0x82, 0x4e,
+	0x94, 0x12, 0x36, 0xa7, 0x71, 0x18, 0xcd, 0x8a, 0xec, 0x44, 0xfa, 0xeb, 0xbd, 0xca, 0xb6, 0xb7,
+	0x7b, 0xab, 0x6f, 0x95, 0xdd, 0xdf, 0xb7, 0xd9, 0x7d, 0x9d, 0xc4, 0xed, 0x68, 0x25, 0x92, 0xe8,
+	0x3e, 0xb4, 0x65, 0x24, 0x88, 0x8a, 0x66, 0xe1, 0x64, 0xa1, 0xa8, 0x34, 0x2f, 0x55, 0xc1, 0xad,
+	0x12, 0xdc, 0xd3, 0x18, 0xba, 0x0b, 0x8d, 0x94, 0x27, 0xa1, 0x22, 0x2c, 0xf5, 0xab, 0xe6, 0x9e,
+	0xf5, 0x94, 0x27, 0x63, 0xc2, 0x52, 0xb4, 0x09, 0x90, 0x70, 0xc1, 0x0b, 0xc5, 0x32, 0x2a, 0x8d,
+	0x78, 0x5a, 0x78, 0x05, 0x09, 0x7e, 0x76, 0xa0, 0xb5, 0xba, 0xff, 0x4a, 0x67, 0x9d, 0x6b, 0x3a,
+	0xdb, 0x85, 0x4a, 0xca, 0x5f, 0x9b, 0xc6, 0x57, 0xb0, 0x5e, 0x6a, 0x15, 0xcd, 0x58, 0x32, 0x2b,
+	0xcf, 0x66, 0xd6, 0xa8, 0x07, 0x9e, 0xcc, 0x69, 0x54, 0xa4, 0x44, 0xb1, 0x53, 0x6a, 0xa4, 0xd4,
+	0xc0, 0xab, 0x10, 0xfa, 0x0c, 0xea, 0xe5, 0x5d, 0x4b, 0x51, 0x6f, 0xf4, 0xed, 0x08, 0xf5, 0x97,
+	0x23, 0xd4, 0x1f, 0x2f, 0x67, 0x0c, 0x2f, 0xa9, 0xc1, 0x33, 0xb8, 0xf1, 0x84, 0x2a, 0xdb, 0xab,
+	0x52, 0x0c, 0x1d, 0x58, 0x2f, 0x8f, 0x5b, 0xc1, 0xeb, 0xcc, 0x68, 0x48, 0xce, 0x88, 0x88, 0xcb,
+	0x23, 0xda, 0xc0, 0xa0, 0x8a, 0x28, 0x59, 0x6a, 0xdd, 0x06, 0xc1, 0x4f, 0x15, 0x00, 0x53, 0xcc,
+	0x3e, 0xeb, 0x7d, 0x4b, 0xa2, 0xa6, 0x5a, 0x67, 0xb7, 0xbd, 0x7c, 0x22, 0x93, 0xb5, 0xdf, 0x50,
+	0xf4, 0x31, 0x34, 0x62, 0xa2, 0x8a, 0xf9, 0x85, 0xfc, 0xbc, 0xb3, 0xb7, 0x5b, 0xf5, 0xa1, 0xc6,
+	0x46, 0x43, 0x5c, 0x37, 0xc9, 0x51, 0xac, 0xd5, 0x44, 0xe2, 0x58, 0x50, 0x69, 0xf7, 0x6c, 0xe2,
+	0x65, 0x88, 0xbe, 0x80, 0xae, 0xa0, 0x11, 0x3f, 0xa5, 0x82, 0xc6, 0xa1, 0xa1, 0x4b, 0xbf, 0xba,
+	0x32, 0xd8, 0x2f, 0x26, 0xc7, 0x34, 0x52, 0xf8, 0xc6, 0x39, 0xc9, 0xd4, 0x96, 0xba, 0x65, 0x52,
+	0x11, 0xa1, 0xfe, 0x5b, 0xcb, 0x4a, 0x2a, 0x7a, 0x04, 0xad, 0x5c, 0x70, 0x2d, 0xe3, 0x50, 0x9b,
+	0x96, 0x99, 0x5e, 0x6f, 0xf7, 0xee, 0x95, 0x4f, 0x87, 0xa5, 0x1d, 0x62, 0xaf, 0xa4, 0xeb, 0x5a,
+	0xe8, 0x21, 0xb4, 0xa6, 0x84, 0xa5, 0x85, 0xa0, 0xa1, 0x5a, 0xe4, 0xd4, 0x8c, 0x74, 0x67, 0xb7,
+	0x6b, 0xf4, 0x7e, 0x68, 0x13, 0xe3, 0x45, 0x4e, 0xb1, 0x37, 0xbd, 0x08, 0xd0, 0x47, 0xd0, 0x89,
+	0xf8, 0x3c, 0x4f, 0xa9, 0xa2, 0x71, 0xa8, 0x48, 0x22, 0xfd, 0x86, 0xd1, 0x65, 0xfb, 0x1c, 0x1d,
+	0x93, 0x44, 0xa2, 0x4f, 0xe1, 0xe6, 0x05, 0x4d, 0x9e, 0xb0, 0x3c, 0xa7, 0xd6, 0x13, 0x2a, 0xb8,
+	0x7b, 0x9e, 0x38, 0xb2, 0x78, 0xf0, 0xab, 0x03, 0xf0, 0x8c, 0x8a, 0x84, 0xfe, 0x8f, 0xa7, 0xda,
+	0x82, 0xaa, 0x12, 0xd4, 0x9a, 0xe1, 0xa5, 0xe6, 0x9a, 0x84, 0xb6, 0x23, 0xc9, 0xde, 0xd0, 0x95,
+	0xe1, 0xaa, 0xe2, 0xa6, 0x46, 0xec, 0x64, 0xed, 0x00, 0x18, 0x9d, 0x84, 0xa6, 0xca, 0xbf, 0x3c,
+	0x51, 0xd3, 0xa4, 0xc7, 0xba, 0xd4, 0x36, 0x74, 0x2d, 0x77, 0xa5, 0x60, 0xcd, 0x14, 0xec, 0x18,
+	0xfc, 0x68, 0x59, 0x35, 0xf0, 0xa0, 0x79, 0xa4, 0x35, 0xa9, 0x1d, 0x3e, 0xf8, 0x1e, 0xaa, 0x2f,
+	0x53, 0x92, 0x69, 0xdb, 0x2d, 0xed, 0x41, 0x5b, 0x55, 0x05, 0x97, 0x91, 0xc6, 0xe7, 0xfa, 0xd6,
+	0xb2, 0x94, 0x73, 0x19, 0x69, 0x3d, 0x73, 0x11, 0x53, 0xe1, 0x57, 0x0c, 0xdd, 0x06, 0x7a, 0x14,
+	0x4f, 0xe8, 0x42, 0x96, 0x36, 0x60, 0xd6, 0x3b, 0x7d, 0xa8, 0xd9, 0x96, 0x79, 0x50, 0xc7, 0xaf,
+	0x9e, 0x3f, 0x1f, 0x3d, 0x7f, 0xd2, 0x5d, 0x43, 0x2d, 0x68, 0xec, 0xbf, 0x78, 0xf6, 0xf2, 0xe9,
+	0xc1, 0xf8, 0xa0, 0xeb, 0x20, 0x00, 0xf7, 0xf0, 0xf1, 0xe8, 0xe9, 0xc1, 0xb0, 0x5b, 0xd9, 0xfd,
+	0xdb, 0x01, 0xd7, 0x3a, 0x19, 0xfa, 0x1c, 0x5c, 0xeb, 0x66, 0xe8, 0xf6, 0x15, 0xb9, 0x1c, 0x68,
+	0x53, 0xdf, 0xb8, 0x6a, 0x7c, 0xc1, 0x1a, 0xfa, 0x12, 0x5c, 0xeb, 0xac, 0xe8, 0xfd, 0x73, 0x93,
+	0x5b, 0xf5, 0xef, 0x8d, 0xdb, 0x97, 0x61, 0x6b, 0xc0, 0xc1, 0x1a, 0x1a, 0x42, 0x63, 0x39, 0xdf,
+	0xe8, 0xce, 0x92, 0x75, 0x69, 0xe2, 0x37, 0xee, 0x5d, 0x39, 0x8c, 0x69, 0xec, 0x37, 0x24, 0x2d,
+	0x68, 0xb0, 0xf6, 0xc0, 0x41, 0x8f, 0xde, 0x75, 0xeb, 0xeb, 0x0e, 0xff, 0xde, 0x72, 0x83, 0x15,
+	0x72, 0xb0, 0xb6, 0xb7, 0xf7, 0xdb, 0xd9, 0xa6, 0xf3, 0xfb, 0xd9, 0xa6, 0xf3, 0xe7, 0xd9, 0xa6,
+	0xf3, 0xe3, 0x5f, 0x9b, 0x6b, 0xdf, 0x3d, 0x48, 0x98, 0x9a, 0x15, 0x93, 0x7e, 0xc4, 0xe7, 0x83,
+	0x9c, 0x44, 0xb3, 0x45, 0x4c, 0xc5, 0xea, 0x4a, 0x8a, 0x68, 0xf0, 0xce, 0xff, 0x32, 0x13, 0xd7,
+	0x6c, 0xf5, 0xf0, 0x9f, 0x01, 0x00, 0x6e, 0x9b, 0x77, 0x57, 0xe3, 0x08, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -655,6 +931,7 @@ type WorkerClient interface {
 	Status(ctx context.Context, in *types.Empty, opts ...grpc.CallOption) (*pps.WorkerStatus, error)
 	Cancel(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*CancelResponse, error)
 	GetChunk(ctx context.Context, in *GetChunkRequest, opts ...grpc.CallOption) (Worker_GetChunkClient, error)
+	DriverState(ctx context.Context, in *types.Empty, opts ...grpc.CallOption) (*DriverState, error)
 }
 
 type workerClient struct {
@@ -715,11 +992,21 @@ func (x *workerGetChunkClient) Recv() (*types.BytesValue, error) {
 	return m, nil
 }
 
+func (c *workerClient) DriverState(ctx context.Context, in *types.Empty, opts ...grpc.CallOption) (*DriverState, error) {
+	out := new(DriverState)
+	err := c.cc.Invoke(ctx, "/worker.Worker/DriverState", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // WorkerServer is the server API for Worker service.
 type WorkerServer interface {
 	Status(context.Context, *types.Empty) (*pps.WorkerStatus, error)
 	Cancel(context.Context, *CancelRequest) (*CancelResponse, error)
 	GetChunk(*GetChunkRequest, Worker_GetChunkServer) error
+	DriverState(context.Context, *types.Empty) (*DriverState, error)
 }
 
 // UnimplementedWorkerServer can be embedded to have forward compatible implementations.
@@ -735,6 +1022,9 @@ func (*UnimplementedWorkerServer) Cancel(ctx context.Context, req *CancelRequest
 func (*UnimplementedWorkerServer) GetChunk(req *GetChunkRequest, srv Worker_GetChunkServer) error {
 	return status.Errorf(codes.Unimplemented, "method GetChunk not implemented")
 }
+func (*UnimplementedWorkerServer) DriverState(ctx context.Context, req *types.Empty) (*DriverState, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DriverState not implemented")
+}
 
 func RegisterWorkerServer(s *grpc.Server, srv WorkerServer) {
 	s.RegisterService(&_Worker_serviceDesc, srv)
@@ -797,6 +1087,24 @@ func (x *workerGetChunkServer) Send(m *types.BytesValue) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Worker_DriverState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(types.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServer).DriverState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/worker.Worker/DriverState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).DriverState(ctx, req.(*types.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Worker_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "worker.Worker",
 	HandlerType: (*WorkerServer)(nil),
@@ -809,6 +1117,10 @@ var _Worker_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Cancel",
 			Handler:    _Worker_Cancel_Handler,
 		},
+		{
+			MethodName: "DriverState",
+			Handler:    _Worker_DriverState_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -844,6 +1156,23 @@ func (m *Input) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.Mount {
+		i--
+		if m.Mount {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x50
+	}
+	if len(m.ReadAhead) > 0 {
+		i -= len(m.ReadAhead)
+		copy(dAtA[i:], m.ReadAhead)
+		i = encodeVarintWorkerService(dAtA, i, uint64(len(m.ReadAhead)))
+		i--
+		dAtA[i] = 0x4a
+	}
 	if len(m.JoinOn) > 0 {
 		i -= len(m.JoinOn)
 		copy(dAtA[i:], m.JoinOn)
@@ -999,7 +1328,7 @@ func (m *CancelResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *GetChunkRequest) Marshal() (dAtA []byte, err error) {
+func (m *DriverState) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1009,12 +1338,12 @@ func (m *GetChunkRequest) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *GetChunkRequest) MarshalTo(dAtA []byte) (int, error) {
+func (m *DriverState) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *GetChunkRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *DriverState) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
@@ -1023,30 +1352,57 @@ func (m *GetChunkRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	if m.Stats {
+	if len(m.Goroutines) > 0 {
+		i -= len(m.Goroutines)
+		copy(dAtA[i:], m.Goroutines)
+		i = encodeVarintWorkerService(dAtA, i, uint64(len(m.Goroutines)))
 		i--
-		if m.Stats {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
+		dAtA[i] = 0x2a
+	}
+	if len(m.LogTail) > 0 {
+		for iNdEx := len(m.LogTail) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.LogTail[iNdEx])
+			copy(dAtA[i:], m.LogTail[iNdEx])
+			i = encodeVarintWorkerService(dAtA, i, uint64(len(m.LogTail[iNdEx])))
+			i--
+			dAtA[i] = 0x22
 		}
+	}
+	if m.ScratchBytes != 0 {
+		i = encodeVarintWorkerService(dAtA, i, uint64(m.ScratchBytes))
 		i--
 		dAtA[i] = 0x18
 	}
-	if m.Shard != 0 {
-		i = encodeVarintWorkerService(dAtA, i, uint64(m.Shard))
-		i--
-		dAtA[i] = 0x10
+	if len(m.ClaimedChunks) > 0 {
+		for iNdEx := len(m.ClaimedChunks) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.ClaimedChunks[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintWorkerService(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
 	}
-	if m.Id != 0 {
-		i = encodeVarintWorkerService(dAtA, i, uint64(m.Id))
+	if m.Status != nil {
+		{
+			size, err := m.Status.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintWorkerService(dAtA, i, uint64(size))
+		}
 		i--
-		dAtA[i] = 0x8
+		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *ChunkState) Marshal() (dAtA []byte, err error) {
+func (m *ClaimedChunk) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1056,12 +1412,12 @@ func (m *ChunkState) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *ChunkState) MarshalTo(dAtA []byte) (int, error) {
+func (m *ClaimedChunk) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *ChunkState) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *ClaimedChunk) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
@@ -1070,9 +1426,9 @@ func (m *ChunkState) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	if m.RecoveredDatums != nil {
+	if m.Claimed != nil {
 		{
-			size, err := m.RecoveredDatums.MarshalToSizedBuffer(dAtA[:i])
+			size, err := m.Claimed.MarshalToSizedBuffer(dAtA[:i])
 			if err != nil {
 				return 0, err
 			}
@@ -1080,31 +1436,39 @@ func (m *ChunkState) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 			i = encodeVarintWorkerService(dAtA, i, uint64(size))
 		}
 		i--
-		dAtA[i] = 0x22
+		dAtA[i] = 0x2a
 	}
-	if len(m.Address) > 0 {
-		i -= len(m.Address)
-		copy(dAtA[i:], m.Address)
-		i = encodeVarintWorkerService(dAtA, i, uint64(len(m.Address)))
+	if m.Speculative {
 		i--
-		dAtA[i] = 0x1a
+		if m.Speculative {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x20
 	}
-	if len(m.DatumID) > 0 {
-		i -= len(m.DatumID)
-		copy(dAtA[i:], m.DatumID)
-		i = encodeVarintWorkerService(dAtA, i, uint64(len(m.DatumID)))
+	if m.High != 0 {
+		i = encodeVarintWorkerService(dAtA, i, uint64(m.High))
 		i--
-		dAtA[i] = 0x12
+		dAtA[i] = 0x18
 	}
-	if m.State != 0 {
-		i = encodeVarintWorkerService(dAtA, i, uint64(m.State))
+	if m.Low != 0 {
+		i = encodeVarintWorkerService(dAtA, i, uint64(m.Low))
 		i--
-		dAtA[i] = 0x8
+		dAtA[i] = 0x10
+	}
+	if len(m.JobID) > 0 {
+		i -= len(m.JobID)
+		copy(dAtA[i:], m.JobID)
+		i = encodeVarintWorkerService(dAtA, i, uint64(len(m.JobID)))
+		i--
+		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *MergeState) Marshal() (dAtA []byte, err error) {
+func (m *GetChunkRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1114,12 +1478,160 @@ func (m *MergeState) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *MergeState) MarshalTo(dAtA []byte) (int, error) {
+func (m *GetChunkRequest) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *MergeState) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *GetChunkRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.Stats {
+		i--
+		if m.Stats {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Shard != 0 {
+		i = encodeVarintWorkerService(dAtA, i, uint64(m.Shard))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Id != 0 {
+		i = encodeVarintWorkerService(dAtA, i, uint64(m.Id))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ChunkState) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ChunkState) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ChunkState) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.CompletedSkipped != 0 {
+		i = encodeVarintWorkerService(dAtA, i, uint64(m.CompletedSkipped))
+		i--
+		dAtA[i] = 0x48
+	}
+	if len(m.CompletedTags) > 0 {
+		for iNdEx := len(m.CompletedTags) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.CompletedTags[iNdEx])
+			copy(dAtA[i:], m.CompletedTags[iNdEx])
+			i = encodeVarintWorkerService(dAtA, i, uint64(len(m.CompletedTags[iNdEx])))
+			i--
+			dAtA[i] = 0x42
+		}
+	}
+	if m.FailureType != 0 {
+		i = encodeVarintWorkerService(dAtA, i, uint64(m.FailureType))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.ProcessTime != nil {
+		{
+			size, err := m.ProcessTime.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintWorkerService(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x32
+	}
+	if m.Started != nil {
+		{
+			size, err := m.Started.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintWorkerService(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.RecoveredDatums != nil {
+		{
+			size, err := m.RecoveredDatums.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintWorkerService(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Address) > 0 {
+		i -= len(m.Address)
+		copy(dAtA[i:], m.Address)
+		i = encodeVarintWorkerService(dAtA, i, uint64(len(m.Address)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.DatumID) > 0 {
+		i -= len(m.DatumID)
+		copy(dAtA[i:], m.DatumID)
+		i = encodeVarintWorkerService(dAtA, i, uint64(len(m.DatumID)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.State != 0 {
+		i = encodeVarintWorkerService(dAtA, i, uint64(m.State))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *MergeState) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *MergeState) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *MergeState) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
@@ -1221,27 +1733,55 @@ func (m *Plan) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.Keys) > 0 {
+		for iNdEx := len(m.Keys) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Keys[iNdEx])
+			copy(dAtA[i:], m.Keys[iNdEx])
+			i = encodeVarintWorkerService(dAtA, i, uint64(len(m.Keys[iNdEx])))
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if len(m.Order) > 0 {
+		dAtA11 := make([]byte, len(m.Order)*10)
+		var j10 int
+		for _, num1 := range m.Order {
+			num := uint64(num1)
+			for num >= 1<<7 {
+				dAtA11[j10] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j10++
+			}
+			dAtA11[j10] = uint8(num)
+			j10++
+		}
+		i -= j10
+		copy(dAtA[i:], dAtA11[:j10])
+		i = encodeVarintWorkerService(dAtA, i, uint64(j10))
+		i--
+		dAtA[i] = 0x1a
+	}
 	if m.Merges != 0 {
 		i = encodeVarintWorkerService(dAtA, i, uint64(m.Merges))
 		i--
 		dAtA[i] = 0x10
 	}
 	if len(m.Chunks) > 0 {
-		dAtA7 := make([]byte, len(m.Chunks)*10)
-		var j6 int
+		dAtA13 := make([]byte, len(m.Chunks)*10)
+		var j12 int
 		for _, num1 := range m.Chunks {
 			num := uint64(num1)
 			for num >= 1<<7 {
-				dAtA7[j6] = uint8(uint64(num)&0x7f | 0x80)
+				dAtA13[j12] = uint8(uint64(num)&0x7f | 0x80)
 				num >>= 7
-				j6++
+				j12++
 			}
-			dAtA7[j6] = uint8(num)
-			j6++
+			dAtA13[j12] = uint8(num)
+			j12++
 		}
-		i -= j6
-		copy(dAtA[i:], dAtA7[:j6])
-		i = encodeVarintWorkerService(dAtA, i, uint64(j6))
+		i -= j12
+		copy(dAtA[i:], dAtA13[:j12])
+		i = encodeVarintWorkerService(dAtA, i, uint64(j12))
 		i--
 		dAtA[i] = 0xa
 	}
@@ -1295,6 +1835,13 @@ func (m *Input) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovWorkerService(uint64(l))
 	}
+	l = len(m.ReadAhead)
+	if l > 0 {
+		n += 1 + l + sovWorkerService(uint64(l))
+	}
+	if m.Mount {
+		n += 2
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1338,6 +1885,70 @@ func (m *CancelResponse) Size() (n int) {
 	return n
 }
 
+func (m *DriverState) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Status != nil {
+		l = m.Status.Size()
+		n += 1 + l + sovWorkerService(uint64(l))
+	}
+	if len(m.ClaimedChunks) > 0 {
+		for _, e := range m.ClaimedChunks {
+			l = e.Size()
+			n += 1 + l + sovWorkerService(uint64(l))
+		}
+	}
+	if m.ScratchBytes != 0 {
+		n += 1 + sovWorkerService(uint64(m.ScratchBytes))
+	}
+	if len(m.LogTail) > 0 {
+		for _, s := range m.LogTail {
+			l = len(s)
+			n += 1 + l + sovWorkerService(uint64(l))
+		}
+	}
+	l = len(m.Goroutines)
+	if l > 0 {
+		n += 1 + l + sovWorkerService(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ClaimedChunk) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.JobID)
+	if l > 0 {
+		n += 1 + l + sovWorkerService(uint64(l))
+	}
+	if m.Low != 0 {
+		n += 1 + sovWorkerService(uint64(m.Low))
+	}
+	if m.High != 0 {
+		n += 1 + sovWorkerService(uint64(m.High))
+	}
+	if m.Speculative {
+		n += 2
+	}
+	if m.Claimed != nil {
+		l = m.Claimed.Size()
+		n += 1 + l + sovWorkerService(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
 func (m *GetChunkRequest) Size() (n int) {
 	if m == nil {
 		return 0
@@ -1380,6 +1991,26 @@ func (m *ChunkState) Size() (n int) {
 		l = m.RecoveredDatums.Size()
 		n += 1 + l + sovWorkerService(uint64(l))
 	}
+	if m.Started != nil {
+		l = m.Started.Size()
+		n += 1 + l + sovWorkerService(uint64(l))
+	}
+	if m.ProcessTime != nil {
+		l = m.ProcessTime.Size()
+		n += 1 + l + sovWorkerService(uint64(l))
+	}
+	if m.FailureType != 0 {
+		n += 1 + sovWorkerService(uint64(m.FailureType))
+	}
+	if len(m.CompletedTags) > 0 {
+		for _, s := range m.CompletedTags {
+			l = len(s)
+			n += 1 + l + sovWorkerService(uint64(l))
+		}
+	}
+	if m.CompletedSkipped != 0 {
+		n += 1 + sovWorkerService(uint64(m.CompletedSkipped))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1443,6 +2074,19 @@ func (m *Plan) Size() (n int) {
 	if m.Merges != 0 {
 		n += 1 + sovWorkerService(uint64(m.Merges))
 	}
+	if len(m.Order) > 0 {
+		l = 0
+		for _, e := range m.Order {
+			l += sovWorkerService(uint64(e))
+		}
+		n += 1 + sovWorkerService(uint64(l)) + l
+	}
+	if len(m.Keys) > 0 {
+		for _, s := range m.Keys {
+			l = len(s)
+			n += 1 + l + sovWorkerService(uint64(l))
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1724,34 +2368,86 @@ func (m *Input) Unmarshal(dAtA []byte) error {
 			}
 			m.JoinOn = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipWorkerService(dAtA[iNdEx:])
-			if err != nil {
-				return err
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReadAhead", wireType)
 			}
-			if skippy < 0 {
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWorkerService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthWorkerService
 			}
-			if (iNdEx + skippy) < 0 {
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
 				return ErrInvalidLengthWorkerService
 			}
-			if (iNdEx + skippy) > l {
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *CancelRequest) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
+			m.ReadAhead = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Mount", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWorkerService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Mount = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipWorkerService(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthWorkerService
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthWorkerService
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CancelRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
 	for iNdEx < l {
 		preIndex := iNdEx
 		var wire uint64
@@ -1900,7 +2596,380 @@ func (m *CancelResponse) Unmarshal(dAtA []byte) error {
 			if wireType != 0 {
 				return fmt.Errorf("proto: wrong wireType = %d for field Success", wireType)
 			}
-			var v int
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWorkerService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Success = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipWorkerService(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthWorkerService
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthWorkerService
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DriverState) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowWorkerService
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DriverState: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DriverState: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWorkerService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthWorkerService
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthWorkerService
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Status == nil {
+				m.Status = &pps.WorkerStatus{}
+			}
+			if err := m.Status.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClaimedChunks", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWorkerService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthWorkerService
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthWorkerService
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ClaimedChunks = append(m.ClaimedChunks, &ClaimedChunk{})
+			if err := m.ClaimedChunks[len(m.ClaimedChunks)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ScratchBytes", wireType)
+			}
+			m.ScratchBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWorkerService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ScratchBytes |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LogTail", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWorkerService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthWorkerService
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthWorkerService
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.LogTail = append(m.LogTail, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Goroutines", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWorkerService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthWorkerService
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthWorkerService
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Goroutines = append(m.Goroutines[:0], dAtA[iNdEx:postIndex]...)
+			if m.Goroutines == nil {
+				m.Goroutines = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipWorkerService(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthWorkerService
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthWorkerService
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ClaimedChunk) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowWorkerService
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ClaimedChunk: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ClaimedChunk: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field JobID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWorkerService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthWorkerService
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthWorkerService
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.JobID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Low", wireType)
+			}
+			m.Low = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWorkerService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Low |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field High", wireType)
+			}
+			m.High = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWorkerService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.High |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Speculative", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWorkerService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Speculative = bool(v != 0)
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Claimed", wireType)
+			}
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowWorkerService
@@ -1910,12 +2979,28 @@ func (m *CancelResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.Success = bool(v != 0)
+			if msglen < 0 {
+				return ErrInvalidLengthWorkerService
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthWorkerService
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Claimed == nil {
+				m.Claimed = &types.Timestamp{}
+			}
+			if err := m.Claimed.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipWorkerService(dAtA[iNdEx:])
@@ -2201,6 +3286,148 @@ func (m *ChunkState) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Started", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWorkerService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthWorkerService
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthWorkerService
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Started == nil {
+				m.Started = &types.Timestamp{}
+			}
+			if err := m.Started.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ProcessTime", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWorkerService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthWorkerService
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthWorkerService
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ProcessTime == nil {
+				m.ProcessTime = &types.Duration{}
+			}
+			if err := m.ProcessTime.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FailureType", wireType)
+			}
+			m.FailureType = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWorkerService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FailureType |= pps.FailureType(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CompletedTags", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWorkerService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthWorkerService
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthWorkerService
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CompletedTags = append(m.CompletedTags, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CompletedSkipped", wireType)
+			}
+			m.CompletedSkipped = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWorkerService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CompletedSkipped |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipWorkerService(dAtA[iNdEx:])
@@ -2587,6 +3814,114 @@ func (m *Plan) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 3:
+			if wireType == 0 {
+				var v int64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowWorkerService
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= int64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.Order = append(m.Order, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowWorkerService
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthWorkerService
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthWorkerService
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				var count int
+				for _, integer := range dAtA[iNdEx:postIndex] {
+					if integer < 128 {
+						count++
+					}
+				}
+				elementCount = count
+				if elementCount != 0 && len(m.Order) == 0 {
+					m.Order = make([]int64, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v int64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowWorkerService
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= int64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.Order = append(m.Order, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field Order", wireType)
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Keys", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWorkerService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthWorkerService
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthWorkerService
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Keys = append(m.Keys, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipWorkerService(dAtA[iNdEx:])
@@ -48,7 +48,7 @@ func TestAcquireDatums(t *testing.T) {
 				server := newTestAPIServer(c, etcdClient, "", t)
 				logger := server.getMasterLogger()
 				eg.Go(func() error {
-					return server.acquireDatums(context.Background(), jobInfo.Job.ID, plan, logger, func(low, high int64) (*processResult, error) {
+					return server.acquireDatums(context.Background(), jobInfo.Job.ID, plan, logger, nil, func(ctx context.Context, low, high int64) (*processResult, error) {
 						chunksMu.Lock()
 						defer chunksMu.Unlock()
 						seenChunks = append(seenChunks, high)
@@ -114,5 +114,7 @@ func newTestAPIServer(pachClient *client.APIClient, etcdClient *etcd.Client, etc
 		jobs:      ppsdb.Jobs(etcdClient, etcdPrefix),
 		pipelines: ppsdb.Pipelines(etcdClient, etcdPrefix),
 		plans:     col.NewCollection(etcdClient, path.Join(etcdPrefix, planPrefix), nil, &Plan{}, nil, nil),
+		logTail:   newLogTail(),
+		claims:    newChunkClaims(),
 	}
 }